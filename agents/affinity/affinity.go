@@ -0,0 +1,58 @@
+// Package affinity escolhe a instância que recebe as tarefas relacionadas (as subtarefas de
+// uma mesma tarefa, por exemplo) por hashing consistente da chave de afinidade: a mesma chave
+// vai sempre para a mesma instância enquanto ela estiver entre as candidatas, preservando o
+// contexto já carregado na memória e nos caches dela. Usa rendezvous hashing (HRW): quando uma
+// instância entra ou sai, só as chaves dela mudam de dono.
+package affinity
+
+import (
+	"hash/fnv"
+	"sort"
+)
+
+// Pick retorna a candidata dona da chave, ou "" se não há candidatas. Chave vazia não tem
+// afinidade e também retorna "".
+func Pick(key string, candidates []string) string {
+	if key == "" || len(candidates) == 0 {
+		return ""
+	}
+	return Rank(key, candidates)[0]
+}
+
+// Rank ordena as candidatas pela preferência da chave, da dona para a última alternativa;
+// a ordem sem uma das candidatas é a mesma com ela removida
+func Rank(key string, candidates []string) []string {
+	ranked := append([]string(nil), candidates...)
+	weights := make(map[string]uint64, len(ranked))
+	for _, candidate := range ranked {
+		weights[candidate] = weight(key, candidate)
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		wi, wj := weights[ranked[i]], weights[ranked[j]]
+		if wi != wj {
+			return wi > wj
+		}
+		return ranked[i] < ranked[j]
+	})
+	return ranked
+}
+
+// weight é o peso da candidata para a chave
+func weight(key, candidate string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(key))
+	h.Write([]byte{0})
+	h.Write([]byte(candidate))
+	return mix(h.Sum64())
+}
+
+// mix espalha os bits do hash (finalizador do SplitMix64), já que o FNV de chaves parecidas
+// difere pouco nos bits altos
+func mix(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
@@ -0,0 +1,48 @@
+package affinity
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestPickIsStable(t *testing.T) {
+	candidates := []string{"writer-1", "writer-2", "writer-3"}
+	owner := Pick("task-42", candidates)
+	if owner == "" {
+		t.Fatal("deveria escolher uma candidata")
+	}
+	reversed := []string{"writer-3", "writer-2", "writer-1"}
+	if got := Pick("task-42", reversed); got != owner {
+		t.Fatalf("a ordem das candidatas não deveria mudar o dono: %s != %s", got, owner)
+	}
+	if Pick("", candidates) != "" || Pick("task-42", nil) != "" {
+		t.Fatal("chave vazia ou sem candidatas não deveria ter dono")
+	}
+}
+
+func TestRemovingCandidateMovesOnlyItsKeys(t *testing.T) {
+	candidates := []string{"a", "b", "c", "d"}
+	owners := make(map[string]string)
+	count := make(map[string]int)
+	for i := 0; i < 1000; i++ {
+		key := fmt.Sprintf("parent-%d", i)
+		owners[key] = Pick(key, candidates)
+		count[owners[key]]++
+	}
+	for _, candidate := range candidates {
+		if count[candidate] < 150 {
+			t.Fatalf("distribuição desequilibrada: %v", count)
+		}
+	}
+
+	remaining := []string{"a", "b", "d"}
+	for key, owner := range owners {
+		got := Pick(key, remaining)
+		if owner != "c" && got != owner {
+			t.Fatalf("a chave %s mudou de %s para %s sem que o dono saísse", key, owner, got)
+		}
+		if owner == "c" && got != Rank(key, candidates)[1] {
+			t.Fatalf("a chave %s deveria ir para a segunda preferência", key)
+		}
+	}
+}
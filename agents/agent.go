@@ -2,6 +2,10 @@ package agents
 
 import (
 	"context"
+
+	"github.com/google/uuid"
+
+	"github.com/suissa/HiveMind/agents/tenant"
 )
 
 // Agent define a interface básica para todos os agentes
@@ -35,6 +39,9 @@ type AgentStruct struct {
 	AllowDelegation bool
 	Model           string
 	Backstory       string
+	Tenant          string
+	ParentID        string   // Agente de origem, para clones
+	Lineage         []string // IDs dos ancestrais, do original ao pai
 }
 
 // GetID retorna o ID do agente
@@ -52,15 +59,39 @@ func (a *AgentStruct) GetRole() string {
 	return a.Role
 }
 
-// Clone cria uma cópia do agente
+// GetTenant retorna o tenant (namespace) do agente
+func (a *AgentStruct) GetTenant() string {
+	if a.Tenant == "" {
+		return tenant.DefaultTenant
+	}
+	return a.Tenant
+}
+
+// Clone cria uma cópia do agente com novo ID, registrando o agente de origem na linhagem.
+// O nome é mantido, pois identifica o tipo do agente (ex.: no escalonamento).
 func (a *AgentStruct) Clone() *AgentStruct {
+	lineage := make([]string, 0, len(a.Lineage)+1)
+	lineage = append(lineage, a.Lineage...)
+	lineage = append(lineage, a.ID)
+
 	return &AgentStruct{
-		ID:              a.ID,
+		ID:              a.ID + "-" + uuid.NewString()[:8],
 		Name:            a.Name,
 		Role:            a.Role,
 		Goal:            a.Goal,
 		AllowDelegation: a.AllowDelegation,
 		Model:           a.Model,
 		Backstory:       a.Backstory,
+		Tenant:          a.Tenant,
+		ParentID:        a.ID,
+		Lineage:         lineage,
 	}
 }
+
+// Root retorna o ID do agente original da linhagem (o próprio ID se não for um clone)
+func (a *AgentStruct) Root() string {
+	if len(a.Lineage) > 0 {
+		return a.Lineage[0]
+	}
+	return a.ID
+}
@@ -0,0 +1,119 @@
+// Package approval implementa os portões de aprovação humana: a tarefa fica parada até que
+// um revisor a aprove ou a rejeite, por exemplo pela API de gerenciamento com a permissão
+// approvals:decide. As aprovações pendentes ficam na memória do processo que executa a tarefa.
+package approval
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/suissa/HiveMind/agents/errs"
+)
+
+// ErrRejected indica que o revisor rejeitou a tarefa
+var ErrRejected = errors.New("tarefa rejeitada na aprovação")
+
+// Request é uma aprovação pendente
+type Request struct {
+	ID          string    `json:"id"`
+	Tenant      string    `json:"tenant"`
+	AgentID     string    `json:"agent_id"`
+	TaskID      string    `json:"task_id"`
+	Description string    `json:"description"`
+	RequestedAt time.Time `json:"requested_at"`
+}
+
+// Decision é a decisão do revisor sobre uma aprovação
+type Decision struct {
+	Approved  bool      `json:"approved"`
+	Reviewer  string    `json:"reviewer,omitempty"`
+	Comment   string    `json:"comment,omitempty"`
+	DecidedAt time.Time `json:"decided_at"`
+}
+
+// pending é uma aprovação à espera da decisão
+type pending struct {
+	request  Request
+	decision chan Decision
+}
+
+// Gate guarda as aprovações pendentes e entrega a decisão a quem aguarda
+type Gate struct {
+	mu      sync.Mutex
+	pending map[string]*pending
+}
+
+// NewGate cria um portão sem aprovações pendentes
+func NewGate() *Gate {
+	return &Gate{pending: make(map[string]*pending)}
+}
+
+// Await registra a aprovação e aguarda a decisão do revisor ou o cancelamento do contexto. O
+// ID e o instante do pedido são preenchidos aqui; notify (opcional) recebe o pedido já
+// registrado, para avisar os revisores. Uma tarefa rejeitada retorna a decisão com ErrRejected.
+func (g *Gate) Await(ctx context.Context, req Request, notify func(Request)) (Decision, error) {
+	req.ID = uuid.NewString()
+	req.RequestedAt = time.Now()
+	p := &pending{request: req, decision: make(chan Decision, 1)}
+
+	g.mu.Lock()
+	g.pending[req.ID] = p
+	g.mu.Unlock()
+	if notify != nil {
+		notify(req)
+	}
+
+	select {
+	case decision := <-p.decision:
+		if !decision.Approved {
+			return decision, ErrRejected
+		}
+		return decision, nil
+	case <-ctx.Done():
+		g.mu.Lock()
+		delete(g.pending, req.ID)
+		g.mu.Unlock()
+		return Decision{}, ctx.Err()
+	}
+}
+
+// Pending retorna as aprovações pendentes do tenant, da mais antiga para a mais recente
+func (g *Gate) Pending(tenant string) []Request {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	requests := make([]Request, 0, len(g.pending))
+	for _, p := range g.pending {
+		if p.request.Tenant == tenant {
+			requests = append(requests, p.request)
+		}
+	}
+	sort.Slice(requests, func(i, j int) bool {
+		return requests[i].RequestedAt.Before(requests[j].RequestedAt)
+	})
+	return requests
+}
+
+// Decide entrega a decisão à aprovação pendente do tenant. Aprovações de outro tenant, já
+// decididas ou abandonadas retornam errs.ErrNotFound.
+func (g *Gate) Decide(tenant, id string, decision Decision) error {
+	g.mu.Lock()
+	p, ok := g.pending[id]
+	if !ok || p.request.Tenant != tenant {
+		g.mu.Unlock()
+		return errs.New(errs.ErrNotFound, "approval.Gate", "aprovação %s não encontrada", id)
+	}
+	delete(g.pending, id)
+	g.mu.Unlock()
+
+	if decision.DecidedAt.IsZero() {
+		decision.DecidedAt = time.Now()
+	}
+	p.decision <- decision
+	return nil
+}
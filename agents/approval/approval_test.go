@@ -0,0 +1,76 @@
+package approval
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/suissa/HiveMind/agents/errs"
+)
+
+// await registra a aprovação e retorna o pedido registrado e o canal do resultado
+func await(ctx context.Context, gate *Gate, req Request) (Request, <-chan error) {
+	requested := make(chan Request, 1)
+	result := make(chan error, 1)
+	go func() {
+		_, err := gate.Await(ctx, req, func(r Request) { requested <- r })
+		result <- err
+	}()
+	return <-requested, result
+}
+
+func TestGateDecide(t *testing.T) {
+	cases := []struct {
+		name      string
+		tenant    string
+		decision  Decision
+		decideErr error
+		awaitErr  error
+	}{
+		{"aprovada", "acme", Decision{Approved: true, Reviewer: "maria"}, nil, nil},
+		{"rejeitada", "acme", Decision{Reviewer: "maria"}, nil, ErrRejected},
+		{"outro tenant", "globex", Decision{Approved: true}, errs.ErrNotFound, context.DeadlineExceeded},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+			defer cancel()
+			gate := NewGate()
+			req, result := await(ctx, gate, Request{Tenant: "acme", TaskID: "t-1"})
+			if req.ID == "" || req.RequestedAt.IsZero() {
+				t.Fatalf("o pedido deveria receber ID e instante: %+v", req)
+			}
+
+			if err := gate.Decide(c.tenant, req.ID, c.decision); !errors.Is(err, c.decideErr) {
+				t.Fatalf("Decide: erro %v, esperado %v", err, c.decideErr)
+			}
+			if err := <-result; !errors.Is(err, c.awaitErr) {
+				t.Fatalf("Await: erro %v, esperado %v", err, c.awaitErr)
+			}
+			if len(gate.Pending("acme")) != 0 {
+				t.Error("a aprovação não deveria continuar pendente")
+			}
+			if err := gate.Decide("acme", req.ID, c.decision); !errors.Is(err, errs.ErrNotFound) {
+				t.Errorf("uma aprovação encerrada deveria ser desconhecida: %v", err)
+			}
+		})
+	}
+}
+
+func TestGatePending(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	gate := NewGate()
+	first, _ := await(ctx, gate, Request{Tenant: "acme", TaskID: "t-1"})
+	await(ctx, gate, Request{Tenant: "globex", TaskID: "t-2"})
+	second, _ := await(ctx, gate, Request{Tenant: "acme", TaskID: "t-3"})
+
+	pending := gate.Pending("acme")
+	if len(pending) != 2 || pending[0].ID != first.ID || pending[1].ID != second.ID {
+		t.Fatalf("esperava as duas aprovações do tenant em ordem, obtidas %+v", pending)
+	}
+	if got := gate.Pending("initech"); len(got) != 0 {
+		t.Errorf("tenant sem aprovações: %+v", got)
+	}
+}
@@ -0,0 +1,83 @@
+package agents
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/suissa/HiveMind/agents/approval"
+	"github.com/suissa/HiveMind/agents/tenant"
+)
+
+// approvalHooks para as tarefas no portão de aprovação até a decisão de um revisor
+type approvalHooks struct {
+	NopHooks
+	gate      *approval.Gate
+	events    *EventEmitter
+	taskTypes map[string]bool // Vazio exige aprovação de todas as tarefas
+}
+
+// ApprovalHooks cria hooks que só iniciam as tarefas dos tipos informados (todas, sem tipos)
+// depois da aprovação no gate. Cada aprovação pendente é emitida no events (opcional) com a
+// ação approval_requested, que os webhooks entregam como approval.requested. Uma tarefa
+// rejeitada falha com approval.ErrRejected; o cancelamento da tarefa abandona a aprovação.
+func ApprovalHooks(gate *approval.Gate, events *EventEmitter, taskTypes ...string) Hooks {
+	h := &approvalHooks{gate: gate, events: events, taskTypes: make(map[string]bool, len(taskTypes))}
+	for _, taskType := range taskTypes {
+		h.taskTypes[taskType] = true
+	}
+	return h
+}
+
+// OnTaskBegin aguarda a decisão sobre a tarefa
+func (h *approvalHooks) OnTaskBegin(ctx context.Context, agent Agent, task *Task) error {
+	if len(h.taskTypes) > 0 && !h.taskTypes[task.Type] {
+		return nil
+	}
+	// A aprovação fica no tenant do agente quando o contexto não define um
+	if scoped, ok := agent.(interface {
+		scope(context.Context) context.Context
+	}); ok {
+		ctx = scoped.scope(ctx)
+	}
+
+	request := approval.Request{
+		Tenant:      tenant.FromContext(ctx),
+		AgentID:     agent.GetID(),
+		TaskID:      task.ID,
+		Description: task.Description,
+	}
+	decision, err := h.gate.Await(ctx, request, func(req approval.Request) {
+		log.Printf("⏸️ Tarefa %s do agente %s aguardando aprovação %s", task.ID, agent.GetID(), req.ID)
+		h.emit(task, req)
+	})
+	if err != nil {
+		if decision.Reviewer != "" {
+			return fmt.Errorf("tarefa %s rejeitada por %s: %w", task.ID, decision.Reviewer, err)
+		}
+		return fmt.Errorf("tarefa %s não aprovada: %w", task.ID, err)
+	}
+	return nil
+}
+
+// emit notifica a aprovação pendente. A descrição da tarefa não vai no evento: os revisores a
+// consultam em GET /v1/approvals.
+func (h *approvalHooks) emit(task *Task, req approval.Request) {
+	if h.events == nil {
+		return
+	}
+
+	h.events.Emit(Event{
+		Type:      EventTaskUpdate,
+		Timestamp: req.RequestedAt,
+		Source:    "approval",
+		Data: map[string]interface{}{
+			"action":      ActionApprovalRequested,
+			"approval_id": req.ID,
+			"tenant":      req.Tenant,
+			"agent_id":    req.AgentID,
+			"task_id":     req.TaskID,
+			"task_type":   task.Type,
+		},
+	})
+}
@@ -0,0 +1,68 @@
+package agents
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/suissa/HiveMind/agents/approval"
+)
+
+func TestApprovalHooks(t *testing.T) {
+	cases := []struct {
+		name     string
+		taskType string
+		approved bool
+		held     bool // A tarefa aguarda a aprovação
+		wantErr  error
+	}{
+		{"aprovada", "publish", true, true, nil},
+		{"rejeitada", "publish", false, true, approval.ErrRejected},
+		{"tipo sem aprovação", "draft", false, false, nil},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			gate := approval.NewGate()
+			events := NewEventEmitter()
+			requested := make(chan Event, 1)
+			events.On(EventTaskUpdate, func(event Event) { requested <- event })
+
+			agent := NewCognitiveAgent("writer-1", "Redator", "", 1, "llama3", "writer", "", nil)
+			agent.Tenant = "acme"
+			hooks := ApprovalHooks(gate, events, "publish")
+			result := make(chan error, 1)
+			go func() {
+				result <- hooks.OnTaskBegin(context.Background(), agent, NewTask("t-1", c.taskType, "publicar o post", nil))
+			}()
+
+			if c.held {
+				var event Event
+				select {
+				case event = <-requested:
+				case <-time.After(time.Second):
+					t.Fatal("esperava o evento approval_requested")
+				}
+				if event.Data["action"] != ActionApprovalRequested || event.Data["tenant"] != "acme" || event.Data["task_id"] != "t-1" {
+					t.Fatalf("evento inesperado: %+v", event.Data)
+				}
+				id, _ := event.Data["approval_id"].(string)
+				if err := gate.Decide("acme", id, approval.Decision{Approved: c.approved, Reviewer: "maria"}); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			select {
+			case err := <-result:
+				if !errors.Is(err, c.wantErr) {
+					t.Fatalf("erro %v, esperado %v", err, c.wantErr)
+				}
+			case <-time.After(time.Second):
+				t.Fatal("a tarefa deveria ter sido liberada")
+			}
+			if !c.held && len(requested) != 0 {
+				t.Error("tarefa sem aprovação não deveria emitir approval_requested")
+			}
+		})
+	}
+}
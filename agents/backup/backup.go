@@ -0,0 +1,219 @@
+// Package backup define o arquivo de backup da memória: um tar.gz com uma seção JSONL por
+// armazenamento (Redis, MongoDB, Weaviate) e um manifesto gravado por último, com a
+// contagem e o checksum de cada seção, que marca o backup como consistente e completo.
+package backup
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/suissa/HiveMind/agents/errs"
+)
+
+// FormatVersion é a versão do formato do arquivo
+const FormatVersion = 1
+
+// manifestName é o nome do manifesto dentro do arquivo
+const manifestName = "manifest.json"
+
+var (
+	// ErrIncomplete indica um arquivo sem manifesto (backup interrompido)
+	ErrIncomplete = errors.New("backup incompleto")
+	// ErrCorrupted indica uma seção cuja contagem ou checksum não confere com o manifesto
+	ErrCorrupted = errors.New("backup corrompido")
+)
+
+// Section descreve uma seção do arquivo
+type Section struct {
+	Name    string `json:"name"`
+	Records int    `json:"records"`
+	SHA256  string `json:"sha256"`
+}
+
+// Manifest descreve o backup e é gravado após todas as seções
+type Manifest struct {
+	Version    int       `json:"version"`
+	Namespace  string    `json:"namespace"` // Tenant exportado
+	StartedAt  time.Time `json:"started_at"`
+	FinishedAt time.Time `json:"finished_at"`
+	Sections   []Section `json:"sections"`
+}
+
+// Section retorna a descrição da seção pelo nome
+func (m *Manifest) Section(name string) (Section, bool) {
+	for _, s := range m.Sections {
+		if s.Name == name {
+			return s, true
+		}
+	}
+	return Section{}, false
+}
+
+// Writer grava um arquivo de backup
+type Writer struct {
+	gz       *gzip.Writer
+	tw       *tar.Writer
+	manifest Manifest
+}
+
+// NewWriter inicia um arquivo de backup do namespace informado
+func NewWriter(w io.Writer, namespace string) *Writer {
+	gz := gzip.NewWriter(w)
+	return &Writer{
+		gz: gz,
+		tw: tar.NewWriter(gz),
+		manifest: Manifest{
+			Version:   FormatVersion,
+			Namespace: namespace,
+			StartedAt: time.Now().UTC(),
+		},
+	}
+}
+
+// WriteSection grava uma seção com um registro JSON por linha
+func (w *Writer) WriteSection(name string, records []json.RawMessage) error {
+	var buf bytes.Buffer
+	for _, record := range records {
+		var compact bytes.Buffer
+		if err := json.Compact(&compact, record); err != nil {
+			return fmt.Errorf("registro inválido na seção %s: %v", name, err)
+		}
+		buf.Write(compact.Bytes())
+		buf.WriteByte('\n')
+	}
+
+	if err := w.writeFile(name+".jsonl", buf.Bytes()); err != nil {
+		return err
+	}
+	sum := sha256.Sum256(buf.Bytes())
+	w.manifest.Sections = append(w.manifest.Sections, Section{
+		Name:    name,
+		Records: len(records),
+		SHA256:  hex.EncodeToString(sum[:]),
+	})
+	return nil
+}
+
+// Close grava o manifesto, que marca o backup como completo, e fecha o arquivo
+func (w *Writer) Close() (*Manifest, error) {
+	w.manifest.FinishedAt = time.Now().UTC()
+	data, err := json.MarshalIndent(w.manifest, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("erro ao serializar manifesto: %v", err)
+	}
+	if err := w.writeFile(manifestName, data); err != nil {
+		return nil, err
+	}
+	if err := w.tw.Close(); err != nil {
+		return nil, fmt.Errorf("erro ao fechar arquivo de backup: %v", err)
+	}
+	if err := w.gz.Close(); err != nil {
+		return nil, fmt.Errorf("erro ao fechar arquivo de backup: %v", err)
+	}
+	manifest := w.manifest
+	return &manifest, nil
+}
+
+// writeFile adiciona um arquivo ao tar
+func (w *Writer) writeFile(name string, data []byte) error {
+	header := &tar.Header{
+		Name:    name,
+		Mode:    0600,
+		Size:    int64(len(data)),
+		ModTime: time.Now(),
+	}
+	if err := w.tw.WriteHeader(header); err != nil {
+		return fmt.Errorf("erro ao gravar %s no backup: %v", name, err)
+	}
+	if _, err := w.tw.Write(data); err != nil {
+		return fmt.Errorf("erro ao gravar %s no backup: %v", name, err)
+	}
+	return nil
+}
+
+// Archive é um arquivo de backup lido e verificado
+type Archive struct {
+	Manifest Manifest
+	sections map[string][]json.RawMessage
+}
+
+// Records retorna os registros de uma seção (nil se ausente)
+func (a *Archive) Records(name string) []json.RawMessage {
+	return a.sections[name]
+}
+
+// Read lê o arquivo e verifica o manifesto: arquivos sem manifesto retornam ErrIncomplete e
+// seções com contagem ou checksum divergentes retornam ErrCorrupted
+func Read(r io.Reader) (*Archive, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, errs.Wrap(errs.ErrValidation, "backup.Read", err, "arquivo de backup inválido")
+	}
+	defer gz.Close()
+
+	files := make(map[string][]byte)
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			// Um arquivo truncado é tratado como backup interrompido
+			return nil, errs.Wrap(errs.ErrValidation, "backup.Read", ErrIncomplete, "erro ao ler arquivo: %v", err)
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, errs.Wrap(errs.ErrValidation, "backup.Read", ErrIncomplete, "erro ao ler %s: %v", header.Name, err)
+		}
+		files[header.Name] = data
+	}
+
+	data, ok := files[manifestName]
+	if !ok {
+		return nil, errs.Wrap(errs.ErrValidation, "backup.Read", ErrIncomplete, "manifesto ausente")
+	}
+	archive := &Archive{sections: make(map[string][]json.RawMessage)}
+	if err := json.Unmarshal(data, &archive.Manifest); err != nil {
+		return nil, errs.Wrap(errs.ErrValidation, "backup.Read", ErrCorrupted, "manifesto inválido: %v", err)
+	}
+	if archive.Manifest.Version > FormatVersion {
+		return nil, errs.New(errs.ErrValidation, "backup.Read", "versão %d do backup não suportada", archive.Manifest.Version)
+	}
+
+	for _, section := range archive.Manifest.Sections {
+		content, ok := files[section.Name+".jsonl"]
+		if !ok {
+			return nil, errs.Wrap(errs.ErrValidation, "backup.Read", ErrCorrupted, "seção %s ausente", section.Name)
+		}
+		sum := sha256.Sum256(content)
+		if hex.EncodeToString(sum[:]) != section.SHA256 {
+			return nil, errs.Wrap(errs.ErrValidation, "backup.Read", ErrCorrupted, "checksum da seção %s não confere", section.Name)
+		}
+
+		records := make([]json.RawMessage, 0, section.Records)
+		scanner := bufio.NewScanner(bytes.NewReader(content))
+		scanner.Buffer(make([]byte, 0, 64*1024), 64*1024*1024)
+		for scanner.Scan() {
+			records = append(records, json.RawMessage(append([]byte(nil), scanner.Bytes()...)))
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, errs.Wrap(errs.ErrValidation, "backup.Read", ErrCorrupted, "erro ao ler seção %s: %v", section.Name, err)
+		}
+		if len(records) != section.Records {
+			return nil, errs.Wrap(errs.ErrValidation, "backup.Read", ErrCorrupted,
+				"seção %s com %d registros, esperado %d", section.Name, len(records), section.Records)
+		}
+		archive.sections[section.Name] = records
+	}
+	return archive, nil
+}
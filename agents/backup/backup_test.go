@@ -0,0 +1,52 @@
+package backup
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestWriteAndRead(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewWriter(&buf, "acme")
+	if err := w.WriteSection("redis", []json.RawMessage{json.RawMessage(`{"key": "memory:a:1"}`), json.RawMessage(`{"key":"memory:a:2"}`)}); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.WriteSection("mongodb", nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	archive, err := Read(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if archive.Manifest.Namespace != "acme" || len(archive.Manifest.Sections) != 2 {
+		t.Fatalf("manifesto inesperado: %#v", archive.Manifest)
+	}
+	records := archive.Records("redis")
+	if len(records) != 2 || string(records[0]) != `{"key":"memory:a:1"}` {
+		t.Fatalf("registros inesperados: %s", records)
+	}
+	if section, ok := archive.Manifest.Section("mongodb"); !ok || section.Records != 0 {
+		t.Fatalf("seção vazia deve constar no manifesto: %#v", section)
+	}
+}
+
+func TestReadRejectsIncompleteArchive(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewWriter(&buf, "acme")
+	if err := w.WriteSection("redis", []json.RawMessage{json.RawMessage(`{}`)}); err != nil {
+		t.Fatal(err)
+	}
+	// Sem Close o manifesto não é gravado: o backup foi interrompido
+	w.tw.Flush()
+	w.gz.Close()
+
+	if _, err := Read(bytes.NewReader(buf.Bytes())); !errors.Is(err, ErrIncomplete) {
+		t.Fatalf("esperava ErrIncomplete, obteve %v", err)
+	}
+}
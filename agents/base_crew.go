@@ -2,6 +2,12 @@ package agents
 
 import (
 	"sync"
+	"time"
+
+	"github.com/suissa/HiveMind/agents/i18n"
+	"github.com/suissa/HiveMind/agents/presence"
+	"github.com/suissa/HiveMind/agents/redact"
+	"github.com/suissa/HiveMind/agents/supervisor"
 )
 
 // BaseCrew fornece funcionalidade básica para equipes de agentes
@@ -9,6 +15,7 @@ type BaseCrew struct {
 	agents        []Agent
 	eventHandlers map[EventType][]EventHandler
 	anyHandlers   []EventHandler
+	presence      *presence.Monitor
 	mu            sync.RWMutex
 }
 
@@ -24,11 +31,10 @@ func NewBaseCrew() *BaseCrew {
 // AddAgent adiciona um agente à equipe
 func (c *BaseCrew) AddAgent(agent Agent) {
 	c.mu.Lock()
-	defer c.mu.Unlock()
-
 	c.agents = append(c.agents, agent)
+	c.mu.Unlock()
 
-	// Emite evento de adição de agente
+	// Emite evento de adição de agente; EmitEvent toma o lock de leitura
 	c.EmitEvent(Event{
 		Type:   EventAgentAction,
 		Source: "base_crew",
@@ -65,19 +71,30 @@ func (c *BaseCrew) EmitEvent(event Event) {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 
+	// Mascara segredos antes de notificar os handlers
+	event.Data = redact.Map(event.Data)
+	event = event.localize(i18n.Default())
+
 	// Notifica handlers específicos do tipo de evento
 	if handlers, ok := c.eventHandlers[event.Type]; ok {
 		for _, handler := range handlers {
-			handler(event)
+			c.notify(handler, event)
 		}
 	}
 
 	// Notifica handlers genéricos
 	for _, handler := range c.anyHandlers {
-		handler(event)
+		c.notify(handler, event)
 	}
 }
 
+// notify executa o handler isolando panics, para que um handler com falha não
+// impeça a entrega aos demais
+func (c *BaseCrew) notify(handler EventHandler, event Event) {
+	defer supervisor.Default.Recover("crew_event_handler:" + string(event.Type))
+	handler(event)
+}
+
 // GetAgents retorna a lista de agentes na equipe
 func (c *BaseCrew) GetAgents() []Agent {
 	c.mu.RLock()
@@ -87,3 +104,63 @@ func (c *BaseCrew) GetAgents() []Agent {
 	copy(agents, c.agents)
 	return agents
 }
+
+// WatchPresence acompanha os heartbeats dos agentes da equipe, emitindo um EventAgentAction
+// (agent_healthy, agent_unhealthy ou agent_left) quando a situação de um deles muda
+func (c *BaseCrew) WatchPresence(monitor *presence.Monitor) {
+	c.mu.Lock()
+	c.presence = monitor
+	c.mu.Unlock()
+
+	monitor.OnChange(func(status presence.Status) {
+		agent := c.member(status.AgentID)
+		if agent == nil {
+			return
+		}
+		c.EmitEvent(presenceEvent("base_crew", agent, status))
+	})
+}
+
+// HealthyAgents retorna os agentes da equipe disponíveis para receber tarefas. Sem
+// WatchPresence, todos os agentes são considerados disponíveis.
+func (c *BaseCrew) HealthyAgents() []Agent {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	agents := make([]Agent, 0, len(c.agents))
+	for _, agent := range c.agents {
+		if c.presence == nil || c.presence.Available(agent.GetID()) {
+			agents = append(agents, agent)
+		}
+	}
+	return agents
+}
+
+// member retorna o agente da equipe com o ID informado
+func (c *BaseCrew) member(id string) Agent {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	for _, agent := range c.agents {
+		if agent.GetID() == id {
+			return agent
+		}
+	}
+	return nil
+}
+
+// presenceEvent monta o evento da mudança de situação de um agente da equipe
+func presenceEvent(source string, agent Agent, status presence.Status) Event {
+	return Event{
+		Type:      EventAgentAction,
+		Timestamp: time.Now(),
+		Source:    source,
+		Data: map[string]interface{}{
+			"action":     "agent_" + string(status.State),
+			"agent_id":   agent.GetID(),
+			"agent_name": agent.GetName(),
+			"agent_role": agent.GetRole(),
+			"running":    status.Load.Running,
+			"last_seen":  status.LastSeen.Format(time.RFC3339),
+		},
+	}
+}
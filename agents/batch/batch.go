@@ -0,0 +1,247 @@
+// Package batch agrupa requisições pequenas e compatíveis ao LLM (mesmo modelo, system,
+// temperatura e limite de tokens), como as de tarefas que avaliam muitos itens de uma vez.
+// Os grupos vão para a API de lote do provedor, quando ele implementa llm.BatchProvider, ou
+// viram chamadas paralelas com um limite de concorrência.
+package batch
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/suissa/HiveMind/agents/errs"
+	"github.com/suissa/HiveMind/agents/llm"
+	"github.com/suissa/HiveMind/agents/metrics"
+)
+
+// Valores padrão da configuração
+const (
+	DefaultMaxBatch    = 16
+	DefaultWindow      = 10 * time.Millisecond
+	DefaultConcurrency = 4
+)
+
+var requestsTotal = metrics.Default.Counter("hivemind_llm_batched_requests_total",
+	"Requisições ao LLM enviadas pelo agrupador, por modo (batch ou parallel)", "mode")
+
+// Config define o agrupamento
+type Config struct {
+	MaxBatch    int           `json:"max_batch,omitempty" yaml:"max_batch,omitempty"`     // Requisições por lote
+	Window      time.Duration `json:"window,omitempty" yaml:"window,omitempty"`           // Espera máxima para completar um lote
+	Concurrency int           `json:"concurrency,omitempty" yaml:"concurrency,omitempty"` // Chamadas simultâneas ao provedor
+}
+
+// Result é o resultado de uma das requisições de CompleteAll
+type Result struct {
+	Response *llm.Response
+	Err      error
+}
+
+// call é uma requisição aguardando o lote
+type call struct {
+	ctx  context.Context
+	req  llm.Request
+	resp *llm.Response
+	err  error
+	done chan struct{}
+}
+
+// finish entrega o resultado a quem aguarda a requisição
+func (c *call) finish(resp *llm.Response, err error) {
+	c.resp, c.err = resp, err
+	close(c.done)
+}
+
+// key agrupa as requisições compatíveis
+type key struct {
+	model       string
+	system      string
+	temperature float64
+	maxTokens   int
+}
+
+// group é um lote em formação
+type group struct {
+	calls []*call
+	timer *time.Timer
+}
+
+// Batcher é um llm.Provider que agrupa as requisições recebidas dentro da janela
+type Batcher struct {
+	provider llm.Provider
+	batch    llm.BatchProvider // Nil quando o provedor não tem API de lote
+	config   Config
+	slots    chan struct{}
+	pending  map[key]*group
+	mu       sync.Mutex
+}
+
+// New cria o agrupador para o provedor; campos zerados da configuração usam os padrões
+func New(provider llm.Provider, config Config) *Batcher {
+	if config.MaxBatch <= 0 {
+		config.MaxBatch = DefaultMaxBatch
+	}
+	if config.Window <= 0 {
+		config.Window = DefaultWindow
+	}
+	if config.Concurrency <= 0 {
+		config.Concurrency = DefaultConcurrency
+	}
+	b := &Batcher{
+		provider: provider,
+		config:   config,
+		slots:    make(chan struct{}, config.Concurrency),
+		pending:  make(map[key]*group),
+	}
+	b.batch, _ = provider.(llm.BatchProvider)
+	return b
+}
+
+// Complete implementa llm.Provider: a requisição aguarda até a janela fechar ou o lote
+// encher e é enviada junto com as compatíveis
+func (b *Batcher) Complete(ctx context.Context, req llm.Request) (*llm.Response, error) {
+	c := &call{ctx: ctx, req: req, done: make(chan struct{})}
+	b.enqueue(c)
+	select {
+	case <-c.done:
+		return c.resp, c.err
+	case <-ctx.Done():
+		return nil, errs.FromContext("batch.Complete", ctx.Err())
+	}
+}
+
+// CompleteAll envia as requisições e aguarda todas; os resultados seguem a ordem das requisições
+func (b *Batcher) CompleteAll(ctx context.Context, reqs []llm.Request) []Result {
+	results := make([]Result, len(reqs))
+	var wg sync.WaitGroup
+	for i, req := range reqs {
+		wg.Add(1)
+		go func(i int, req llm.Request) {
+			defer wg.Done()
+			resp, err := b.Complete(ctx, req)
+			results[i] = Result{Response: resp, Err: err}
+		}(i, req)
+	}
+	wg.Wait()
+	return results
+}
+
+// enqueue adiciona a requisição ao lote compatível, enviando-o se ficar cheio
+func (b *Batcher) enqueue(c *call) {
+	k := key{model: c.req.Model, system: c.req.System, temperature: c.req.Temperature, maxTokens: c.req.MaxTokens}
+
+	b.mu.Lock()
+	g, ok := b.pending[k]
+	if !ok {
+		g = &group{}
+		g.timer = time.AfterFunc(b.config.Window, func() { b.flush(k, g) })
+		b.pending[k] = g
+	}
+	g.calls = append(g.calls, c)
+	full := len(g.calls) >= b.config.MaxBatch
+	if full {
+		delete(b.pending, k)
+		g.timer.Stop()
+	}
+	b.mu.Unlock()
+
+	if full {
+		go b.send(g.calls)
+	}
+}
+
+// flush envia o lote quando a janela fecha, se ele ainda não foi enviado por estar cheio
+func (b *Batcher) flush(k key, g *group) {
+	b.mu.Lock()
+	if b.pending[k] != g {
+		b.mu.Unlock()
+		return
+	}
+	delete(b.pending, k)
+	b.mu.Unlock()
+	b.send(g.calls)
+}
+
+// send envia o lote pela API de lote ou em chamadas paralelas, ignorando as requisições
+// cujo chamador já desistiu
+func (b *Batcher) send(calls []*call) {
+	active := calls[:0:0]
+	for _, c := range calls {
+		if c.ctx.Err() == nil {
+			active = append(active, c)
+		}
+	}
+	switch {
+	case len(active) == 0:
+		return
+	case b.batch != nil && len(active) > 1:
+		b.sendBatch(active)
+	default:
+		for _, c := range active {
+			go b.sendOne(c)
+		}
+	}
+}
+
+// sendBatch envia o lote numa única chamada. A chamada só é cancelada se todos os chamadores
+// desistirem.
+func (b *Batcher) sendBatch(calls []*call) {
+	ctx, cancel := context.WithCancel(context.WithoutCancel(calls[0].ctx))
+	defer cancel()
+	remaining := int32(len(calls))
+	for _, c := range calls {
+		stop := context.AfterFunc(c.ctx, func() {
+			if atomic.AddInt32(&remaining, -1) == 0 {
+				cancel()
+			}
+		})
+		defer stop()
+	}
+
+	reqs := make([]llm.Request, len(calls))
+	for i, c := range calls {
+		reqs[i] = c.req
+	}
+	select {
+	case b.slots <- struct{}{}:
+	case <-ctx.Done():
+		b.fail(calls, errs.FromContext("batch.Complete", ctx.Err()))
+		return
+	}
+	requestsTotal.Add(float64(len(calls)), "batch")
+	resps, err := b.batch.CompleteBatch(ctx, reqs)
+	<-b.slots
+
+	if err == nil && len(resps) != len(calls) {
+		err = errs.New(errs.ErrValidation, "batch.CompleteBatch", "o provedor retornou %d respostas para %d requisições", len(resps), len(calls))
+	}
+	if err != nil {
+		b.fail(calls, err)
+		return
+	}
+	for i, c := range calls {
+		c.finish(resps[i], nil)
+	}
+}
+
+// sendOne envia uma requisição do lote respeitando o limite de concorrência
+func (b *Batcher) sendOne(c *call) {
+	select {
+	case b.slots <- struct{}{}:
+	case <-c.ctx.Done():
+		c.finish(nil, errs.FromContext("batch.Complete", c.ctx.Err()))
+		return
+	}
+	requestsTotal.Inc("parallel")
+	resp, err := b.provider.Complete(c.ctx, c.req)
+	<-b.slots
+	c.finish(resp, err)
+}
+
+// fail entrega o mesmo erro a todas as requisições do lote
+func (b *Batcher) fail(calls []*call, err error) {
+	for _, c := range calls {
+		c.finish(nil, err)
+	}
+}
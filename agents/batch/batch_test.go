@@ -0,0 +1,132 @@
+package batch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/suissa/HiveMind/agents/errs"
+	"github.com/suissa/HiveMind/agents/llm"
+)
+
+// batchProvider registra o tamanho de cada lote recebido
+type batchProvider struct {
+	sizes []int
+	mu    sync.Mutex
+}
+
+func (p *batchProvider) Complete(ctx context.Context, req llm.Request) (*llm.Response, error) {
+	return &llm.Response{Text: "nota de " + req.Prompt, Model: req.Model}, nil
+}
+
+func (p *batchProvider) CompleteBatch(ctx context.Context, reqs []llm.Request) ([]*llm.Response, error) {
+	p.mu.Lock()
+	p.sizes = append(p.sizes, len(reqs))
+	p.mu.Unlock()
+	resps := make([]*llm.Response, len(reqs))
+	for i, req := range reqs {
+		resps[i] = &llm.Response{Text: "nota de " + req.Prompt, Model: req.Model}
+	}
+	return resps, nil
+}
+
+func TestBatchAPI(t *testing.T) {
+	provider := &batchProvider{}
+	b := New(provider, Config{MaxBatch: 4, Window: 20 * time.Millisecond})
+
+	reqs := make([]llm.Request, 10)
+	for i := range reqs {
+		reqs[i] = llm.Request{Model: "m", Prompt: fmt.Sprintf("item %d", i)}
+	}
+	// Uma requisição com outro system não entra nos lotes das demais
+	reqs = append(reqs, llm.Request{Model: "m", System: "outro", Prompt: "extra"})
+
+	results := b.CompleteAll(context.Background(), reqs)
+	for i, result := range results {
+		if result.Err != nil || result.Response.Text != "nota de "+reqs[i].Prompt {
+			t.Fatalf("resultado %d = %+v", i, result)
+		}
+	}
+
+	total := 0
+	for _, size := range provider.sizes {
+		if size > 4 {
+			t.Fatalf("lote com %d requisições excede MaxBatch: %v", size, provider.sizes)
+		}
+		total += size
+	}
+	// A requisição isolada vai pelo Complete do provedor: só 10 passam por lotes
+	if total != 10 || len(provider.sizes) < 3 {
+		t.Fatalf("lotes = %v", provider.sizes)
+	}
+}
+
+func TestParallelConcurrency(t *testing.T) {
+	var (
+		running, peak int
+		mu            sync.Mutex
+	)
+	provider := llm.ProviderFunc(func(ctx context.Context, req llm.Request) (*llm.Response, error) {
+		mu.Lock()
+		running++
+		if running > peak {
+			peak = running
+		}
+		mu.Unlock()
+		time.Sleep(5 * time.Millisecond)
+		mu.Lock()
+		running--
+		mu.Unlock()
+		return &llm.Response{Text: req.Prompt}, nil
+	})
+	b := New(provider, Config{Concurrency: 2})
+
+	reqs := make([]llm.Request, 8)
+	for i := range reqs {
+		reqs[i] = llm.Request{Prompt: fmt.Sprint(i)}
+	}
+	for i, result := range b.CompleteAll(context.Background(), reqs) {
+		if result.Err != nil || result.Response.Text != fmt.Sprint(i) {
+			t.Fatalf("resultado %d = %+v", i, result)
+		}
+	}
+	if peak != 2 {
+		t.Fatalf("pico de %d chamadas simultâneas; esperava 2", peak)
+	}
+}
+
+func TestCompleteCanceled(t *testing.T) {
+	provider := llm.ProviderFunc(func(ctx context.Context, req llm.Request) (*llm.Response, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+	b := New(provider, Config{})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Millisecond)
+	defer cancel()
+	if _, err := b.Complete(ctx, llm.Request{Prompt: "lento"}); !errors.Is(err, errs.ErrTimeout) {
+		t.Fatalf("esperava ErrTimeout: %v", err)
+	}
+}
+
+func TestBatchSizeMismatch(t *testing.T) {
+	provider := &mismatchProvider{}
+	b := New(provider, Config{MaxBatch: 2})
+	results := b.CompleteAll(context.Background(), []llm.Request{{Prompt: "a"}, {Prompt: "b"}})
+	for _, result := range results {
+		if !errors.Is(result.Err, errs.ErrValidation) {
+			t.Fatalf("esperava ErrValidation: %+v", result)
+		}
+	}
+}
+
+type mismatchProvider struct {
+	llm.Provider
+}
+
+func (p *mismatchProvider) CompleteBatch(ctx context.Context, reqs []llm.Request) ([]*llm.Response, error) {
+	return []*llm.Response{{Text: "só uma"}}, nil
+}
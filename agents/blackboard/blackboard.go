@@ -0,0 +1,262 @@
+// Package blackboard implementa a coordenação por quadro compartilhado: os agentes publicam
+// soluções parciais em tópicos de um espaço de trabalho estruturado (Board) e reagem às
+// mudanças dos tópicos que acompanham, como alternativa à delegação por filas em problemas
+// exploratórios. O Controller executa as fontes de conhecimento (KnowledgeSource) até o
+// problema ser resolvido ou ninguém mais ter o que contribuir.
+package blackboard
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/suissa/HiveMind/agents/communication"
+	"github.com/suissa/HiveMind/agents/errs"
+)
+
+// Entry é uma contribuição no quadro. Os tópicos são separados por pontos
+// ("hipoteses.causa") e as inscrições aceitam os curingas "*" e "#" dos tópicos do barramento.
+type Entry struct {
+	ID         string                 `json:"id"`
+	Topic      string                 `json:"topic"`
+	AgentID    string                 `json:"agent_id"`
+	Content    map[string]interface{} `json:"content"`
+	Confidence float64                `json:"confidence"`          // Confiança do autor, de 0 a 1
+	Parents    []string               `json:"parents,omitempty"`   // Entradas em que esta se baseia
+	Version    int                    `json:"version"`             // Incrementada a cada atualização
+	Retracted  bool                   `json:"retracted,omitempty"` // Retirada pelo autor ou por outra fonte
+	CreatedAt  time.Time              `json:"created_at"`
+	UpdatedAt  time.Time              `json:"updated_at"`
+}
+
+// ChangeType é o tipo de mudança no quadro
+type ChangeType string
+
+const (
+	ChangePost    ChangeType = "post"
+	ChangeUpdate  ChangeType = "update"
+	ChangeRetract ChangeType = "retract"
+)
+
+// Change é uma mudança informada aos inscritos
+type Change struct {
+	Type  ChangeType `json:"type"`
+	Entry Entry      `json:"entry"`
+}
+
+// subscription é uma inscrição em um padrão de tópicos
+type subscription struct {
+	id      int
+	pattern string
+	handler func(Change)
+}
+
+// Board é o espaço de trabalho compartilhado
+type Board struct {
+	name          string
+	entries       map[string]*Entry
+	order         []string // IDs na ordem de publicação
+	subscriptions []subscription
+	nextID        int
+	mu            sync.RWMutex
+}
+
+// New cria um quadro vazio
+func New(name string) *Board {
+	return &Board{name: name, entries: make(map[string]*Entry)}
+}
+
+// Name retorna o nome do quadro
+func (b *Board) Name() string {
+	return b.name
+}
+
+// Post publica uma contribuição e retorna a entrada criada
+func (b *Board) Post(topic, agentID string, content map[string]interface{}, confidence float64, parents ...string) (Entry, error) {
+	if err := communication.ValidateSubject(topic); err != nil {
+		return Entry{}, err
+	}
+	if communication.IsPattern(topic) {
+		return Entry{}, errs.New(errs.ErrValidation, "blackboard.Post", "tópico %q com curinga", topic)
+	}
+	now := time.Now()
+	entry := &Entry{
+		ID:         uuid.New().String(),
+		Topic:      topic,
+		AgentID:    agentID,
+		Content:    content,
+		Confidence: confidence,
+		Parents:    parents,
+		Version:    1,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+
+	b.mu.Lock()
+	b.entries[entry.ID] = entry
+	b.order = append(b.order, entry.ID)
+	change, handlers := Change{Type: ChangePost, Entry: *entry}, b.matching(topic)
+	b.mu.Unlock()
+
+	notify(handlers, change)
+	return change.Entry, nil
+}
+
+// Update substitui o conteúdo e a confiança da entrada. version deve ser a versão lida pelo
+// chamador: se outra fonte atualizou a entrada antes, retorna errs.ErrValidation e a entrada
+// deve ser relida (controle de concorrência otimista).
+func (b *Board) Update(id string, version int, content map[string]interface{}, confidence float64) (Entry, error) {
+	b.mu.Lock()
+	entry, ok := b.entries[id]
+	if !ok {
+		b.mu.Unlock()
+		return Entry{}, errs.New(errs.ErrNotFound, "blackboard.Update", "entrada %s não encontrada", id)
+	}
+	if entry.Version != version {
+		current := entry.Version
+		b.mu.Unlock()
+		return Entry{}, errs.New(errs.ErrValidation, "blackboard.Update", "entrada %s na versão %d, não %d", id, current, version)
+	}
+	entry.Content = content
+	entry.Confidence = confidence
+	entry.Version++
+	entry.UpdatedAt = time.Now()
+	change, handlers := Change{Type: ChangeUpdate, Entry: *entry}, b.matching(entry.Topic)
+	b.mu.Unlock()
+
+	notify(handlers, change)
+	return change.Entry, nil
+}
+
+// Retract retira a entrada: ela deixa de aparecer nas consultas, mas continua acessível por Get
+func (b *Board) Retract(id string) error {
+	b.mu.Lock()
+	entry, ok := b.entries[id]
+	if !ok {
+		b.mu.Unlock()
+		return errs.New(errs.ErrNotFound, "blackboard.Retract", "entrada %s não encontrada", id)
+	}
+	if entry.Retracted {
+		b.mu.Unlock()
+		return nil
+	}
+	entry.Retracted = true
+	entry.Version++
+	entry.UpdatedAt = time.Now()
+	change, handlers := Change{Type: ChangeRetract, Entry: *entry}, b.matching(entry.Topic)
+	b.mu.Unlock()
+
+	notify(handlers, change)
+	return nil
+}
+
+// Get retorna uma entrada pelo ID
+func (b *Board) Get(id string) (Entry, bool) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	entry, ok := b.entries[id]
+	if !ok {
+		return Entry{}, false
+	}
+	return *entry, true
+}
+
+// Query retorna as entradas ativas dos tópicos que casam com o padrão, na ordem de publicação
+func (b *Board) Query(pattern string) []Entry {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	var entries []Entry
+	for _, id := range b.order {
+		entry := b.entries[id]
+		if !entry.Retracted && communication.MatchSubject(pattern, entry.Topic) {
+			entries = append(entries, *entry)
+		}
+	}
+	return entries
+}
+
+// Best retorna a entrada ativa de maior confiança dos tópicos que casam com o padrão
+func (b *Board) Best(pattern string) (Entry, bool) {
+	entries := b.Query(pattern)
+	if len(entries) == 0 {
+		return Entry{}, false
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].Confidence > entries[j].Confidence })
+	return entries[0], true
+}
+
+// Subscribe registra um handler das mudanças nos tópicos que casam com o padrão e retorna a
+// função que cancela a inscrição. Os handlers são chamados na goroutine que fez a mudança,
+// fora do lock do quadro, e podem publicar no quadro.
+func (b *Board) Subscribe(pattern string, handler func(Change)) (cancel func()) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.nextID++
+	id := b.nextID
+	b.subscriptions = append(b.subscriptions, subscription{id: id, pattern: pattern, handler: handler})
+	return func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		for i, sub := range b.subscriptions {
+			if sub.id == id {
+				b.subscriptions = append(b.subscriptions[:i:i], b.subscriptions[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// Watch entrega as mudanças dos tópicos que casam com o padrão no canal retornado, até o
+// contexto ser cancelado. Mudanças que não couberem no buffer são descartadas.
+func (b *Board) Watch(ctx context.Context, pattern string, buffer int) <-chan Change {
+	changes := make(chan Change, buffer)
+	var mu sync.Mutex
+	closed := false
+	cancel := b.Subscribe(pattern, func(change Change) {
+		mu.Lock()
+		defer mu.Unlock()
+		if closed {
+			return
+		}
+		select {
+		case changes <- change:
+		default:
+		}
+	})
+	go func() {
+		<-ctx.Done()
+		cancel()
+		mu.Lock()
+		closed = true
+		close(changes)
+		mu.Unlock()
+	}()
+	return changes
+}
+
+// matching retorna os handlers inscritos no tópico. Deve ser chamado com b.mu travado.
+func (b *Board) matching(topic string) []func(Change) {
+	var handlers []func(Change)
+	for _, sub := range b.subscriptions {
+		if communication.MatchSubject(sub.pattern, topic) {
+			handlers = append(handlers, sub.handler)
+		}
+	}
+	return handlers
+}
+
+// notify chama os handlers com a mudança
+func notify(handlers []func(Change), change Change) {
+	for _, handler := range handlers {
+		handler(change)
+	}
+}
+
+// String descreve a entrada nos prompts e nos logs
+func (e Entry) String() string {
+	return fmt.Sprintf("[%s] %s (%s, confiança %.2f): %v", e.ID, e.Topic, e.AgentID, e.Confidence, e.Content)
+}
@@ -0,0 +1,109 @@
+package blackboard
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/suissa/HiveMind/agents/errs"
+)
+
+func TestBoardPostUpdateAndSubscribe(t *testing.T) {
+	board := New("incidente")
+	var changes []Change
+	cancel := board.Subscribe("hipoteses.*", func(change Change) { changes = append(changes, change) })
+
+	entry, err := board.Post("hipoteses.causa", "analista", map[string]interface{}{"text": "disco cheio"}, 0.4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := board.Post("hipoteses.#", "analista", nil, 0); !errors.Is(err, errs.ErrValidation) {
+		t.Fatalf("tópicos com curinga deveriam ser rejeitados: %v", err)
+	}
+
+	updated, err := board.Update(entry.ID, entry.Version, map[string]interface{}{"text": "inode esgotado"}, 0.8)
+	if err != nil || updated.Version != 2 {
+		t.Fatalf("atualização inesperada: %+v, %v", updated, err)
+	}
+	if _, err := board.Update(entry.ID, entry.Version, nil, 0); !errors.Is(err, errs.ErrValidation) {
+		t.Fatalf("versão antiga deveria ser rejeitada: %v", err)
+	}
+
+	board.Post("hipoteses.rede", "rede", nil, 0.6)
+	if best, _ := board.Best("hipoteses.*"); best.ID != entry.ID {
+		t.Fatalf("esperava a entrada de maior confiança: %+v", best)
+	}
+	board.Retract(entry.ID)
+	if len(board.Query("hipoteses.*")) != 1 {
+		t.Fatal("entradas retiradas não deveriam aparecer nas consultas")
+	}
+
+	cancel()
+	board.Post("hipoteses.outra", "analista", nil, 0)
+	if len(changes) != 4 || changes[1].Type != ChangeUpdate || changes[3].Type != ChangeRetract {
+		t.Fatalf("mudanças inesperadas: %+v", changes)
+	}
+
+	ctx, stop := context.WithCancel(context.Background())
+	watched := board.Watch(ctx, "solucao", 1)
+	board.Post("solucao", "analista", nil, 1)
+	if change := <-watched; change.Entry.Topic != "solucao" {
+		t.Fatalf("mudança inesperada: %+v", change)
+	}
+	stop()
+	select {
+	case _, open := <-watched:
+		if open {
+			t.Fatal("o canal deveria fechar com o contexto")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("o canal não foi fechado")
+	}
+}
+
+func TestControllerRunsTriggeredSources(t *testing.T) {
+	board := New("plano")
+	calls := map[string]int{}
+	proposer := KnowledgeSource{
+		Name:     "proponente",
+		Triggers: []string{"criticas.*"},
+		Contribute: func(ctx context.Context, board *Board) error {
+			calls["proponente"]++
+			_, err := board.Post("propostas.plano", "proponente", map[string]interface{}{"versao": calls["proponente"]}, 0.5)
+			return err
+		},
+	}
+	critic := KnowledgeSource{
+		Name:     "critico",
+		Triggers: []string{"propostas.*"},
+		Contribute: func(ctx context.Context, board *Board) error {
+			calls["critico"]++
+			if len(board.Query("propostas.*")) < 2 {
+				_, err := board.Post("criticas.plano", "critico", map[string]interface{}{"text": "falta orçamento"}, 0.7)
+				return err
+			}
+			_, err := board.Post("solucao", "critico", nil, 0.9)
+			return err
+		},
+	}
+
+	controller := NewController(board, func(b *Board) bool { return len(b.Query("solucao")) > 0 }, proposer, critic)
+	result, err := controller.Run(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Solved || calls["proponente"] != 2 {
+		t.Fatalf("resultado inesperado: %+v, chamadas %v", result, calls)
+	}
+
+	failing := KnowledgeSource{Name: "falha", Contribute: func(context.Context, *Board) error { return fmt.Errorf("sem LLM") }}
+	if _, err := NewController(New("vazio"), func(*Board) bool { return false }, failing).Run(context.Background()); err == nil {
+		t.Fatal("esperava o erro da fonte")
+	}
+	result, _ = NewController(New("vazio"), func(*Board) bool { return false }).Run(context.Background())
+	if result.Solved || result.Cycles != 0 {
+		t.Fatalf("sem fontes disparadas o controller deveria parar: %+v", result)
+	}
+}
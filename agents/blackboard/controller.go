@@ -0,0 +1,110 @@
+package blackboard
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/suissa/HiveMind/agents/communication"
+	"github.com/suissa/HiveMind/agents/errs"
+)
+
+// DefaultMaxCycles é o limite padrão de ciclos do Controller
+const DefaultMaxCycles = 10
+
+// KnowledgeSource é uma fonte de conhecimento: um agente (ou regra) que lê o quadro e publica
+// contribuições. A fonte roda no primeiro ciclo e volta a rodar quando outra fonte muda um dos
+// tópicos de Triggers; as mudanças publicadas com o próprio Name como autor não a disparam.
+type KnowledgeSource struct {
+	Name       string
+	Triggers   []string // Padrões de tópicos que disparam a fonte
+	Contribute func(ctx context.Context, board *Board) error
+}
+
+// Result é o resultado de uma execução do Controller
+type Result struct {
+	Solved bool `json:"solved"`
+	Cycles int  `json:"cycles"`
+}
+
+// Controller coordena as fontes de conhecimento sobre um quadro
+type Controller struct {
+	board     *Board
+	sources   []KnowledgeSource
+	solved    func(*Board) bool
+	maxCycles int
+}
+
+// NewController cria um Controller; solved informa se o quadro já contém a solução
+func NewController(board *Board, solved func(*Board) bool, sources ...KnowledgeSource) *Controller {
+	return &Controller{board: board, sources: sources, solved: solved, maxCycles: DefaultMaxCycles}
+}
+
+// SetMaxCycles limita o número de ciclos de Run
+func (c *Controller) SetMaxCycles(cycles int) {
+	c.maxCycles = cycles
+}
+
+// Run executa ciclos até o quadro estar resolvido, nenhuma fonte ter sido disparada ou o
+// limite de ciclos ser atingido. Em cada ciclo as fontes disparadas rodam na ordem de registro.
+// Retorna o erro da primeira fonte que falhar.
+func (c *Controller) Run(ctx context.Context) (Result, error) {
+	pending := make([]bool, len(c.sources))
+	for i := range pending {
+		pending[i] = true
+	}
+	var mu sync.Mutex
+	cancel := c.board.Subscribe(communication.WildcardMany, func(change Change) {
+		mu.Lock()
+		defer mu.Unlock()
+		for i, source := range c.sources {
+			if change.Entry.AgentID != source.Name && triggers(source, change.Entry.Topic) {
+				pending[i] = true
+			}
+		}
+	})
+	defer cancel()
+
+	var result Result
+	for result.Cycles < c.maxCycles {
+		if c.solved(c.board) {
+			result.Solved = true
+			return result, nil
+		}
+		if err := ctx.Err(); err != nil {
+			return result, errs.FromContext("blackboard.Run", err)
+		}
+
+		// As mudanças feitas durante o ciclo disparam as fontes no ciclo seguinte
+		mu.Lock()
+		runnable := pending
+		pending = make([]bool, len(c.sources))
+		mu.Unlock()
+		ran := false
+		for i, source := range c.sources {
+			if !runnable[i] {
+				continue
+			}
+			ran = true
+			if err := source.Contribute(ctx, c.board); err != nil {
+				return result, fmt.Errorf("erro na fonte %s: %w", source.Name, err)
+			}
+		}
+		if !ran {
+			break
+		}
+		result.Cycles++
+	}
+	result.Solved = c.solved(c.board)
+	return result, nil
+}
+
+// triggers informa se a mudança no tópico dispara a fonte
+func triggers(source KnowledgeSource, topic string) bool {
+	for _, pattern := range source.Triggers {
+		if communication.MatchSubject(pattern, topic) {
+			return true
+		}
+	}
+	return false
+}
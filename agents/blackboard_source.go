@@ -0,0 +1,58 @@
+package agents
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/suissa/HiveMind/agents/blackboard"
+)
+
+// BlackboardSource transforma o agente em uma fonte de conhecimento do quadro: a cada disparo
+// o agente lê as entradas ativas dos tópicos de triggers e publica a sua contribuição
+// ({"text": ...}) em topic, com a confiança do agente e as entradas lidas como origem
+func BlackboardSource(agent *CognitiveAgent, topic string, triggers ...string) blackboard.KnowledgeSource {
+	return blackboard.KnowledgeSource{
+		Name:     agent.GetID(),
+		Triggers: triggers,
+		Contribute: func(ctx context.Context, board *blackboard.Board) error {
+			seen := make(map[string]bool)
+			var entries []blackboard.Entry
+			for _, pattern := range triggers {
+				for _, entry := range board.Query(pattern) {
+					if !seen[entry.ID] {
+						seen[entry.ID] = true
+						entries = append(entries, entry)
+					}
+				}
+			}
+
+			output, err := agent.Complete(ctx, blackboardPrompt(board, agent, topic, entries))
+			if err != nil {
+				return err
+			}
+			parents := make([]string, len(entries))
+			for i, entry := range entries {
+				parents[i] = entry.ID
+			}
+			_, err = board.Post(topic, agent.GetID(), map[string]interface{}{"text": output}, agent.confidence(), parents...)
+			return err
+		},
+	}
+}
+
+// blackboardPrompt descreve o estado do quadro para o agente
+func blackboardPrompt(board *blackboard.Board, agent *CognitiveAgent, topic string, entries []blackboard.Entry) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Você colabora na resolução de um problema pelo quadro compartilhado %q.\n", board.Name())
+	if len(entries) == 0 {
+		b.WriteString("O quadro ainda não tem contribuições.\n")
+	} else {
+		b.WriteString("Contribuições atuais:\n")
+		for _, entry := range entries {
+			fmt.Fprintf(&b, "- %s\n", entry)
+		}
+	}
+	fmt.Fprintf(&b, "Como %s (%s), publique a sua contribuição para o tópico %s.", agent.GetName(), agent.GetRole(), topic)
+	return b.String()
+}
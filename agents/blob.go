@@ -0,0 +1,102 @@
+package agents
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/suissa/HiveMind/agents/blob"
+	"github.com/suissa/HiveMind/agents/errs"
+)
+
+// BlobReadToolName é o nome da ferramenta que lê os conteúdos guardados no armazenamento de objetos
+const BlobReadToolName = "read_blob"
+
+// DefaultBlobReadLimit é quantos bytes read_blob lê por chamada quando o limite não é informado
+const DefaultBlobReadLimit = 16 * 1024
+
+// blobReadTool lê sob demanda trechos dos conteúdos que foram para o armazenamento de objetos
+type blobReadTool struct {
+	blobs *blob.Manager
+}
+
+// NewBlobReadTool cria a ferramenta read_blob, com que o agente lê por trechos os conteúdos
+// substituídos por uma referência [blob:...] nas saídas de ferramentas e nas memórias
+func NewBlobReadTool(blobs *blob.Manager) Tool {
+	return &blobReadTool{blobs: blobs}
+}
+
+func (t *blobReadTool) Name() string { return BlobReadToolName }
+
+func (t *blobReadTool) Description() string {
+	return "Lê um trecho de um conteúdo grande guardado como referência [blob:...], a partir de offset"
+}
+
+// InputSchema implementa SchemaTool
+func (t *blobReadTool) InputSchema() json.RawMessage {
+	return json.RawMessage(`{"type":"object","properties":{` +
+		`"ref":{"type":"string","description":"A referência [blob:...] ou a chave sha256/..."},` +
+		`"offset":{"type":"integer","minimum":0,"description":"Byte inicial do trecho"},` +
+		`"limit":{"type":"integer","minimum":1,"description":"Bytes a ler"}},` +
+		`"required":["ref"]}`)
+}
+
+// Execute lê o trecho e retorna o conteúdo (em base64 para conteúdos binários), o tamanho
+// total e o offset do próximo trecho
+func (t *blobReadTool) Execute(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+	text, _ := params["ref"].(string)
+	ref, ok := blob.ParseRef(text)
+	if !ok {
+		return nil, errs.New(errs.ErrValidation, "agents.read_blob", "referência de blob inválida: %q", text)
+	}
+	offset := intParam(params["offset"], 0)
+	limit := intParam(params["limit"], DefaultBlobReadLimit)
+	// Trechos maiores que o limite voltariam ao armazenamento como outra referência
+	if max := t.blobs.Config().Threshold / 2; limit <= 0 || limit > max {
+		limit = max
+	}
+
+	reader, err := t.blobs.Open(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	if offset > 0 {
+		if _, err := io.CopyN(io.Discard, reader, int64(offset)); err != nil && err != io.EOF {
+			return nil, fmt.Errorf("erro ao ler o blob %s: %w", ref.Key, err)
+		}
+	}
+	data, err := io.ReadAll(io.LimitReader(reader, int64(limit)))
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler o blob %s: %w", ref.Key, err)
+	}
+
+	result := map[string]interface{}{"key": ref.Key, "offset": offset, "size": ref.Size}
+	if ref.Size > 0 && int64(offset+len(data)) < ref.Size {
+		result["next_offset"] = offset + len(data)
+	}
+	if strings.HasPrefix(ref.ContentType, "text/") || (ref.ContentType == "" && utf8.Valid(data)) {
+		result["content"] = string(data)
+	} else {
+		result["content_base64"] = base64.StdEncoding.EncodeToString(data)
+	}
+	return result, nil
+}
+
+// intParam lê um parâmetro numérico, que chega como float64 quando vem de JSON
+func intParam(value interface{}, fallback int) int {
+	switch v := value.(type) {
+	case int:
+		return v
+	case int64:
+		return int(v)
+	case float64:
+		return int(v)
+	default:
+		return fallback
+	}
+}
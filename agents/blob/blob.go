@@ -0,0 +1,274 @@
+// Package blob guarda os conteúdos grandes — planilhas, relatórios de varredura, imagens e
+// outras saídas de ferramentas — num armazenamento de objetos (S3, MinIO ou GridFS) e passa
+// apenas referências nas mensagens e nas memórias. A referência acompanha uma prévia do
+// início do conteúdo; o conteúdo inteiro só é buscado quando alguém precisa dele.
+package blob
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/suissa/HiveMind/agents/errs"
+	"github.com/suissa/HiveMind/agents/metrics"
+)
+
+// Valores padrão da configuração
+const (
+	DefaultThreshold   = 64 * 1024
+	DefaultPreviewSize = 1024
+)
+
+// previewSuffix separa a prévia da referência no conteúdo gerado por Offload
+const previewSuffix = "…\n"
+
+var (
+	offloadedTotal = metrics.Default.Counter("hivemind_blob_offloaded_total",
+		"Conteúdos movidos para o armazenamento de objetos, por tipo (text ou binary)", "kind")
+	offloadedBytes = metrics.Default.Counter("hivemind_blob_offloaded_bytes_total",
+		"Bytes movidos para o armazenamento de objetos, por tipo (text ou binary)", "kind")
+)
+
+// Store é o armazenamento de objetos. As chaves são derivadas do conteúdo, então gravar de
+// novo a mesma chave grava o mesmo conteúdo.
+type Store interface {
+	Put(ctx context.Context, key string, data []byte, contentType string) error
+	// Open abre o objeto; um objeto inexistente retorna errs.ErrNotFound
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// Ref é a referência a um conteúdo guardado no armazenamento de objetos
+type Ref struct {
+	Key         string `json:"key"`
+	Size        int64  `json:"size"`
+	ContentType string `json:"content_type,omitempty"`
+}
+
+// refPattern reconhece as referências escritas por Ref.String
+var refPattern = regexp.MustCompile(`\[blob:(sha256/[0-9a-f]{64}) size=(\d+)(?: type=([^\]\s]+))?\]`)
+
+// String escreve a referência no formato embutido nos textos: [blob:<chave> size=<bytes> type=<tipo>]
+func (r Ref) String() string {
+	if r.ContentType == "" {
+		return fmt.Sprintf("[blob:%s size=%d]", r.Key, r.Size)
+	}
+	return fmt.Sprintf("[blob:%s size=%d type=%s]", r.Key, r.Size, strings.ReplaceAll(r.ContentType, " ", ""))
+}
+
+// ParseRef lê uma referência escrita por Ref.String ou apenas a chave
+func ParseRef(text string) (Ref, bool) {
+	text = strings.TrimSpace(text)
+	if match := refPattern.FindStringSubmatch(text); match != nil {
+		size, _ := strconv.ParseInt(match[2], 10, 64)
+		return Ref{Key: match[1], Size: size, ContentType: match[3]}, true
+	}
+	if strings.HasPrefix(text, "sha256/") && len(text) == len("sha256/")+64 {
+		return Ref{Key: text}, true
+	}
+	return Ref{}, false
+}
+
+// Refs retorna as referências embutidas no texto
+func Refs(text string) []Ref {
+	var refs []Ref
+	for _, match := range refPattern.FindAllString(text, -1) {
+		if ref, ok := ParseRef(match); ok {
+			refs = append(refs, ref)
+		}
+	}
+	return refs
+}
+
+// Key retorna a chave do conteúdo no armazenamento
+func Key(data []byte) string {
+	sum := sha256.Sum256(data)
+	return "sha256/" + hex.EncodeToString(sum[:])
+}
+
+// Config define quando os conteúdos vão para o armazenamento de objetos
+type Config struct {
+	Threshold   int `json:"threshold,omitempty" yaml:"threshold,omitempty"`       // Bytes a partir dos quais o conteúdo é guardado (padrão DefaultThreshold)
+	PreviewSize int `json:"preview_size,omitempty" yaml:"preview_size,omitempty"` // Bytes do início mantidos como prévia (padrão DefaultPreviewSize)
+}
+
+// Manager guarda os conteúdos grandes no armazenamento e resolve as referências
+type Manager struct {
+	store  Store
+	config Config
+}
+
+// NewManager cria o gerenciador sobre o armazenamento; campos zerados da configuração usam os padrões
+func NewManager(store Store, config Config) *Manager {
+	if config.Threshold <= 0 {
+		config.Threshold = DefaultThreshold
+	}
+	if config.PreviewSize <= 0 {
+		config.PreviewSize = DefaultPreviewSize
+	}
+	return &Manager{store: store, config: config}
+}
+
+// Config retorna a configuração em uso
+func (m *Manager) Config() Config {
+	return m.config
+}
+
+// Put guarda o conteúdo e retorna a referência. Sem contentType, o tipo é detectado.
+func (m *Manager) Put(ctx context.Context, data []byte, contentType string) (Ref, error) {
+	if contentType == "" {
+		contentType = http.DetectContentType(data)
+	}
+	ref := Ref{Key: Key(data), Size: int64(len(data)), ContentType: contentType}
+	if err := m.store.Put(ctx, ref.Key, data, contentType); err != nil {
+		return Ref{}, fmt.Errorf("erro ao guardar o blob %s: %w", ref.Key, err)
+	}
+	return ref, nil
+}
+
+// Open abre o conteúdo da referência para leitura
+func (m *Manager) Open(ctx context.Context, ref Ref) (io.ReadCloser, error) {
+	return m.store.Open(ctx, ref.Key)
+}
+
+// Fetch lê o conteúdo inteiro da referência
+func (m *Manager) Fetch(ctx context.Context, ref Ref) ([]byte, error) {
+	reader, err := m.store.Open(ctx, ref.Key)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler o blob %s: %w", ref.Key, err)
+	}
+	return data, nil
+}
+
+// Delete remove o conteúdo da referência
+func (m *Manager) Delete(ctx context.Context, ref Ref) error {
+	return m.store.Delete(ctx, ref.Key)
+}
+
+// Offload guarda o texto se ele alcançar o limite e retorna a prévia seguida da referência;
+// textos menores voltam inalterados
+func (m *Manager) Offload(ctx context.Context, text string) (string, error) {
+	if len(text) < m.config.Threshold || len(Refs(text)) > 0 {
+		return text, nil
+	}
+	ref, err := m.Put(ctx, []byte(text), "text/plain; charset=utf-8")
+	if err != nil {
+		return "", err
+	}
+	offloadedTotal.Inc("text")
+	offloadedBytes.Add(float64(len(text)), "text")
+	return preview(text, m.config.PreviewSize) + previewSuffix + ref.String(), nil
+}
+
+// OffloadValue aplica Offload às saídas de ferramentas: textos grandes viram a prévia com a
+// referência e conteúdos binários ([]byte) grandes viram uma Ref. Os demais valores voltam
+// inalterados.
+func (m *Manager) OffloadValue(ctx context.Context, value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case string:
+		return m.Offload(ctx, v)
+	case []byte:
+		if len(v) < m.config.Threshold {
+			return v, nil
+		}
+		ref, err := m.Put(ctx, v, "")
+		if err != nil {
+			return nil, err
+		}
+		offloadedTotal.Inc("binary")
+		offloadedBytes.Add(float64(len(v)), "binary")
+		return ref, nil
+	default:
+		return value, nil
+	}
+}
+
+// Resolve substitui as referências do texto pelos conteúdos. A prévia escrita por Offload
+// antes de uma referência é descartada, devolvendo o texto original.
+func (m *Manager) Resolve(ctx context.Context, text string) (string, error) {
+	matches := refPattern.FindAllStringIndex(text, -1)
+	if len(matches) == 0 {
+		return text, nil
+	}
+	var b strings.Builder
+	last := 0
+	for _, match := range matches {
+		ref, _ := ParseRef(text[match[0]:match[1]])
+		data, err := m.Fetch(ctx, ref)
+		if err != nil {
+			return "", err
+		}
+		segment := text[last:match[0]]
+		if head := strings.TrimSuffix(segment, previewSuffix); head != segment && strings.HasPrefix(string(data), head) {
+			segment = ""
+		}
+		b.WriteString(segment)
+		b.Write(data)
+		last = match[1]
+	}
+	b.WriteString(text[last:])
+	return b.String(), nil
+}
+
+// preview retorna até size bytes do início do texto sem partir caracteres
+func preview(text string, size int) string {
+	if len(text) <= size {
+		return text
+	}
+	cut := size
+	for cut > 0 && !utf8.RuneStart(text[cut]) {
+		cut--
+	}
+	return text[:cut]
+}
+
+// MemoryStore guarda os objetos em memória, para testes e uso local
+type MemoryStore struct {
+	objects map[string][]byte
+	mu      sync.RWMutex
+}
+
+// NewMemoryStore cria um armazenamento em memória
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{objects: make(map[string][]byte)}
+}
+
+// Put implementa Store
+func (s *MemoryStore) Put(ctx context.Context, key string, data []byte, contentType string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.objects[key] = append([]byte(nil), data...)
+	return nil
+}
+
+// Open implementa Store
+func (s *MemoryStore) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	data, ok := s.objects[key]
+	if !ok {
+		return nil, errs.New(errs.ErrNotFound, "blob.Open", "blob %s não encontrado", key)
+	}
+	return io.NopCloser(strings.NewReader(string(data))), nil
+}
+
+// Delete implementa Store
+func (s *MemoryStore) Delete(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.objects, key)
+	return nil
+}
@@ -0,0 +1,94 @@
+package blob
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/suissa/HiveMind/agents/errs"
+)
+
+func TestOffloadAndResolve(t *testing.T) {
+	ctx := context.Background()
+	m := NewManager(NewMemoryStore(), Config{Threshold: 100, PreviewSize: 10})
+
+	small := "pequeno"
+	if got, err := m.Offload(ctx, small); err != nil || got != small {
+		t.Fatalf("texto abaixo do limite deveria voltar inalterado: %q, %v", got, err)
+	}
+
+	large := strings.Repeat("relatório ", 50)
+	offloaded, err := m.Offload(ctx, large)
+	if err != nil {
+		t.Fatal(err)
+	}
+	refs := Refs(offloaded)
+	if len(refs) != 1 || refs[0].Size != int64(len(large)) || refs[0].ContentType != "text/plain;charset=utf-8" {
+		t.Fatalf("referência inesperada em %q: %+v", offloaded, refs)
+	}
+	if !strings.HasPrefix(offloaded, "relatório") || len(offloaded) > 150 {
+		t.Fatalf("deveria manter só a prévia: %q", offloaded)
+	}
+	if again, _ := m.Offload(ctx, offloaded); again != offloaded {
+		t.Fatal("texto com referência não deveria ser guardado de novo")
+	}
+
+	resolved, err := m.Resolve(ctx, offloaded)
+	if err != nil || resolved != large {
+		t.Fatalf("Resolve deveria devolver o texto original: %v", err)
+	}
+	embedded := "saída: " + refs[0].String() + " fim"
+	if resolved, _ := m.Resolve(ctx, embedded); resolved != "saída: "+large+" fim" {
+		t.Fatalf("referência embutida resolvida de forma inesperada: %q", resolved)
+	}
+}
+
+func TestOffloadValueBinary(t *testing.T) {
+	ctx := context.Background()
+	m := NewManager(NewMemoryStore(), Config{Threshold: 8})
+
+	image := append([]byte("\x89PNG\r\n\x1a\n"), make([]byte, 32)...)
+	value, err := m.OffloadValue(ctx, image)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ref, ok := value.(Ref)
+	if !ok || ref.ContentType != "image/png" || ref.Size != int64(len(image)) {
+		t.Fatalf("binário grande deveria virar Ref: %#v", value)
+	}
+	data, err := m.Fetch(ctx, ref)
+	if err != nil || string(data) != string(image) {
+		t.Fatalf("Fetch deveria devolver o binário: %v", err)
+	}
+	if value, _ := m.OffloadValue(ctx, 42); value != 42 {
+		t.Fatalf("outros valores deveriam voltar inalterados: %v", value)
+	}
+
+	if err := m.Delete(ctx, ref); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.Fetch(ctx, ref); !errors.Is(err, errs.ErrNotFound) {
+		t.Fatalf("blob removido deveria retornar ErrNotFound, veio %v", err)
+	}
+}
+
+func TestParseRef(t *testing.T) {
+	key := Key([]byte("x"))
+	if ref, ok := ParseRef(key); !ok || ref.Key != key {
+		t.Fatalf("a chave sozinha deveria ser aceita: %+v", ref)
+	}
+	ref := Ref{Key: key, Size: 1, ContentType: "text/csv"}
+	if parsed, ok := ParseRef(ref.String()); !ok || parsed != ref {
+		t.Fatalf("ida e volta inesperada: %+v", parsed)
+	}
+	if _, ok := ParseRef("sha256/curta"); ok {
+		t.Fatal("chave inválida não deveria ser aceita")
+	}
+}
+
+func TestPreviewKeepsRunes(t *testing.T) {
+	if got := preview("ação", 2); got != "a" {
+		t.Fatalf("a prévia não deveria partir caracteres: %q", got)
+	}
+}
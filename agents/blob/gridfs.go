@@ -0,0 +1,124 @@
+package blob
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/gridfs"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/suissa/HiveMind/agents/errs"
+)
+
+// DefaultGridFSBucket é o nome do bucket GridFS usado quando nenhum é informado
+const DefaultGridFSBucket = "blobs"
+
+// GridFSStore guarda os objetos no GridFS do MongoDB, com a chave como _id do arquivo
+type GridFSStore struct {
+	client *mongo.Client
+	bucket *gridfs.Bucket
+	// O bucket guarda os prazos e o buffer de escrita em campos compartilhados: as aberturas
+	// de leitura e escrita são serializadas
+	mu sync.Mutex
+}
+
+// NewGridFSStore conecta ao MongoDB e cria o armazenamento no bucket do banco
+// (DefaultGridFSBucket se vazio)
+func NewGridFSStore(ctx context.Context, mongoURL, database, bucket string) (*GridFSStore, error) {
+	if bucket == "" {
+		bucket = DefaultGridFSBucket
+	}
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(mongoURL))
+	if err != nil {
+		return nil, fmt.Errorf("erro ao conectar ao MongoDB: %w", err)
+	}
+	if err := client.Ping(ctx, nil); err != nil {
+		client.Disconnect(ctx)
+		return nil, fmt.Errorf("erro ao verificar conexão com MongoDB: %w", err)
+	}
+	b, err := gridfs.NewBucket(client.Database(database), options.GridFSBucket().SetName(bucket))
+	if err != nil {
+		client.Disconnect(ctx)
+		return nil, fmt.Errorf("erro ao abrir o bucket GridFS %s: %v", bucket, err)
+	}
+	return &GridFSStore{client: client, bucket: b}, nil
+}
+
+// Close fecha a conexão com o MongoDB
+func (s *GridFSStore) Close(ctx context.Context) error {
+	if err := s.client.Disconnect(ctx); err != nil {
+		return fmt.Errorf("erro ao fechar conexão com MongoDB: %w", err)
+	}
+	return nil
+}
+
+// Put implementa Store; a chave já gravada não é regravada
+func (s *GridFSStore) Put(ctx context.Context, key string, data []byte, contentType string) error {
+	cursor, err := s.bucket.FindContext(ctx, bson.M{"_id": key})
+	if err != nil {
+		return errs.FromContext("blob.GridFSStore", fmt.Errorf("erro ao consultar o GridFS: %w", err))
+	}
+	exists := cursor.Next(ctx)
+	cursor.Close(ctx)
+	if exists {
+		return nil
+	}
+
+	deadline, _ := ctx.Deadline()
+	s.mu.Lock()
+	s.bucket.SetWriteDeadline(deadline)
+	upload, err := s.bucket.OpenUploadStreamWithID(key, key,
+		options.GridFSUpload().SetMetadata(bson.M{"contentType": contentType}))
+	s.bucket.SetWriteDeadline(time.Time{})
+	s.mu.Unlock()
+	if err != nil {
+		return errs.FromContext("blob.GridFSStore", fmt.Errorf("erro ao gravar no GridFS: %w", err))
+	}
+	upload.SetWriteDeadline(deadline)
+	if _, err := upload.Write(data); err != nil {
+		upload.Abort()
+		return uploadError(err)
+	}
+	return uploadError(upload.Close())
+}
+
+// Open implementa Store
+func (s *GridFSStore) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	deadline, _ := ctx.Deadline()
+	s.mu.Lock()
+	s.bucket.SetReadDeadline(deadline)
+	download, err := s.bucket.OpenDownloadStream(key)
+	s.bucket.SetReadDeadline(time.Time{})
+	s.mu.Unlock()
+	if errors.Is(err, gridfs.ErrFileNotFound) {
+		return nil, errs.New(errs.ErrNotFound, "blob.GridFSStore", "blob %s não encontrado", key)
+	}
+	if err != nil {
+		return nil, errs.FromContext("blob.GridFSStore", fmt.Errorf("erro ao ler do GridFS: %w", err))
+	}
+	download.SetReadDeadline(deadline)
+	return download, nil
+}
+
+// Delete implementa Store; remover um objeto inexistente não é erro
+func (s *GridFSStore) Delete(ctx context.Context, key string) error {
+	err := s.bucket.DeleteContext(ctx, key)
+	if err != nil && !errors.Is(err, gridfs.ErrFileNotFound) {
+		return errs.FromContext("blob.GridFSStore", fmt.Errorf("erro ao remover do GridFS: %w", err))
+	}
+	return nil
+}
+
+// uploadError trata a gravação concorrente da mesma chave como sucesso, já que o conteúdo é o mesmo
+func uploadError(err error) error {
+	if err == nil || mongo.IsDuplicateKeyError(err) {
+		return nil
+	}
+	return errs.FromContext("blob.GridFSStore", fmt.Errorf("erro ao gravar no GridFS: %w", err))
+}
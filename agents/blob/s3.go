@@ -0,0 +1,260 @@
+package blob
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/suissa/HiveMind/agents/errs"
+)
+
+// DefaultS3Region é a região usada quando S3Config.Region está vazia (o MinIO aceita qualquer uma)
+const DefaultS3Region = "us-east-1"
+
+// emptyPayloadHash é o SHA-256 do corpo vazio, usado nas requisições sem corpo
+const emptyPayloadHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+// S3Config configura o acesso a um bucket S3 ou MinIO
+type S3Config struct {
+	Endpoint     string `json:"endpoint" yaml:"endpoint"` // Ex.: https://s3.us-east-1.amazonaws.com ou http://localhost:9000
+	Region       string `json:"region,omitempty" yaml:"region,omitempty"`
+	Bucket       string `json:"bucket" yaml:"bucket"`
+	Prefix       string `json:"prefix,omitempty" yaml:"prefix,omitempty"` // Prefixo das chaves no bucket
+	AccessKey    string `json:"-" yaml:"-"`
+	SecretKey    string `json:"-" yaml:"-"`
+	SessionToken string `json:"-" yaml:"-"`
+}
+
+// S3ConfigFromEnv lê BLOB_S3_ENDPOINT, BLOB_S3_REGION, BLOB_S3_BUCKET, BLOB_S3_PREFIX e as
+// credenciais AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY e AWS_SESSION_TOKEN
+func S3ConfigFromEnv() S3Config {
+	return S3Config{
+		Endpoint:     os.Getenv("BLOB_S3_ENDPOINT"),
+		Region:       os.Getenv("BLOB_S3_REGION"),
+		Bucket:       os.Getenv("BLOB_S3_BUCKET"),
+		Prefix:       os.Getenv("BLOB_S3_PREFIX"),
+		AccessKey:    os.Getenv("AWS_ACCESS_KEY_ID"),
+		SecretKey:    os.Getenv("AWS_SECRET_ACCESS_KEY"),
+		SessionToken: os.Getenv("AWS_SESSION_TOKEN"),
+	}
+}
+
+// S3Store guarda os objetos num bucket S3 ou MinIO pela API REST, com endereçamento por
+// caminho (endpoint/bucket/chave) e assinatura AWS Signature V4
+type S3Store struct {
+	config S3Config
+	client *http.Client
+	now    func() time.Time
+}
+
+// NewS3Store cria o armazenamento sobre o bucket; o bucket precisa existir
+func NewS3Store(config S3Config) (*S3Store, error) {
+	if config.Endpoint == "" || config.Bucket == "" {
+		return nil, errs.New(errs.ErrValidation, "blob.NewS3Store", "endpoint e bucket são obrigatórios")
+	}
+	if config.Region == "" {
+		config.Region = DefaultS3Region
+	}
+	config.Endpoint = strings.TrimRight(config.Endpoint, "/")
+	return &S3Store{
+		config: config,
+		client: &http.Client{Timeout: 5 * time.Minute},
+		now:    time.Now,
+	}, nil
+}
+
+// Put implementa Store (PUT /bucket/chave)
+func (s *S3Store) Put(ctx context.Context, key string, data []byte, contentType string) error {
+	req, err := s.request(ctx, http.MethodPut, key, data)
+	if err != nil {
+		return err
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	resp, err := s.do(req, data)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// Open implementa Store (GET /bucket/chave)
+func (s *S3Store) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	req, err := s.request(ctx, http.MethodGet, key, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.do(req, nil)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// Delete implementa Store (DELETE /bucket/chave); remover um objeto inexistente não é erro
+func (s *S3Store) Delete(ctx context.Context, key string) error {
+	req, err := s.request(ctx, http.MethodDelete, key, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := s.do(req, nil)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// request cria a requisição para o objeto da chave
+func (s *S3Store) request(ctx context.Context, method, key string, data []byte) (*http.Request, error) {
+	target := s.config.Endpoint + "/" + escapePath(s.config.Bucket+"/"+s.config.Prefix+key)
+	req, err := http.NewRequestWithContext(ctx, method, target, bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("erro ao criar requisição ao S3: %v", err)
+	}
+	if data == nil {
+		req.Body, req.ContentLength = http.NoBody, 0
+	}
+	return req, nil
+}
+
+// do assina e envia a requisição; respostas fora de 2xx viram erro com o tipo do status
+func (s *S3Store) do(req *http.Request, data []byte) (*http.Response, error) {
+	payloadHash := emptyPayloadHash
+	if len(data) > 0 {
+		sum := sha256.Sum256(data)
+		payloadHash = hex.EncodeToString(sum[:])
+	}
+	if s.config.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", s.config.SessionToken)
+	}
+	if s.config.AccessKey != "" {
+		sign(req, payloadHash, s.config.AccessKey, s.config.SecretKey, s.config.Region, s.now())
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, errs.FromContext("blob.S3Store", fmt.Errorf("erro ao chamar o S3: %w", err))
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		defer resp.Body.Close()
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		if kind := errs.KindForStatus(resp.StatusCode); kind != nil {
+			return nil, errs.New(kind, "blob.S3Store", "%s %s: status %d: %s", req.Method, req.URL.Path, resp.StatusCode, strings.TrimSpace(string(message)))
+		}
+		return nil, fmt.Errorf("blob.S3Store: %s %s: status %d: %s", req.Method, req.URL.Path, resp.StatusCode, strings.TrimSpace(string(message)))
+	}
+	return resp, nil
+}
+
+// sign assina a requisição com AWS Signature V4 (serviço s3). Assina o host, o Content-Type,
+// o Range e os cabeçalhos x-amz-*.
+func sign(req *http.Request, payloadHash, accessKey, secretKey, region string, now time.Time) {
+	now = now.UTC()
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	host := req.Host
+	if host == "" {
+		host = req.URL.Host
+	}
+	headers := map[string]string{"host": host}
+	for name, values := range req.Header {
+		name = strings.ToLower(name)
+		if strings.HasPrefix(name, "x-amz-") || name == "content-type" || name == "range" {
+			headers[name] = strings.TrimSpace(strings.Join(values, ","))
+		}
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+	scope := date + "/" + region + "/s3/aws4_request"
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(requestHash[:])
+
+	key := hmacSHA256([]byte("AWS4"+secretKey), date)
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		accessKey, scope, signedHeaders, signature))
+}
+
+// canonicalQuery ordena os parâmetros da query como a assinatura exige
+func canonicalQuery(query url.Values) string {
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var pairs []string
+	for _, key := range keys {
+		values := append([]string(nil), query[key]...)
+		sort.Strings(values)
+		for _, value := range values {
+			pairs = append(pairs, escape(key)+"="+escape(value))
+		}
+	}
+	return strings.Join(pairs, "&")
+}
+
+// escapePath codifica cada segmento do caminho, preservando as barras
+func escapePath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		segments[i] = escape(segment)
+	}
+	return strings.Join(segments, "/")
+}
+
+// escape codifica tudo exceto os caracteres não reservados (A-Z, a-z, 0-9, -, _, . e ~)
+func escape(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z' || '0' <= c && c <= '9' || c == '-' || c == '_' || c == '.' || c == '~' {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
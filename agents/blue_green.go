@@ -0,0 +1,110 @@
+package agents
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/suissa/HiveMind/agents/rollout"
+)
+
+// Rollout executa tarefas dividindo o tráfego entre a versão atual de um agente (blue) e
+// uma nova versão (green, normalmente criada com Clone e com novos prompts ou modelo).
+// As execuções são comparadas e a nova versão é promovida ou revertida automaticamente.
+type Rollout struct {
+	Blue       *CognitiveAgent
+	Green      *CognitiveAgent
+	controller *rollout.Controller
+	emitter    *EventEmitter
+}
+
+// NewRollout cria um rollout blue/green; events (opcional) recebe as decisões
+func NewRollout(blue, green *CognitiveAgent, config rollout.Config, events *EventEmitter) *Rollout {
+	return &Rollout{
+		Blue:       blue,
+		Green:      green,
+		controller: rollout.New(config),
+		emitter:    events,
+	}
+}
+
+// Run executa a tarefa na versão escolhida para ela e registra o resultado
+func (r *Rollout) Run(ctx context.Context, task *Task) (string, error) {
+	variant := r.controller.Route(task.ID)
+	agent := r.agent(variant)
+
+	before := r.controller.State()
+	started := time.Now()
+	output, err := agent.Run(ctx, task)
+	after := r.controller.Record(variant, time.Since(started), err)
+
+	if after != before {
+		r.decided(after)
+	}
+	return output, err
+}
+
+// Active retorna a versão que atende o tráfego após a decisão (blue enquanto ativo ou revertido)
+func (r *Rollout) Active() *CognitiveAgent {
+	if r.controller.State() == rollout.Promoted {
+		return r.Green
+	}
+	return r.Blue
+}
+
+// State retorna o estado do rollout
+func (r *Rollout) State() rollout.State {
+	return r.controller.State()
+}
+
+// Stats retorna as estatísticas das duas versões
+func (r *Rollout) Stats() map[rollout.Variant]rollout.Stats {
+	return map[rollout.Variant]rollout.Stats{
+		rollout.Blue:  r.controller.Stats(rollout.Blue),
+		rollout.Green: r.controller.Stats(rollout.Green),
+	}
+}
+
+// Promote envia todo o tráfego à nova versão
+func (r *Rollout) Promote() {
+	r.controller.Promote()
+	r.decided(rollout.Promoted)
+}
+
+// Rollback mantém a versão atual e descarta a nova
+func (r *Rollout) Rollback() {
+	r.controller.Rollback()
+	r.decided(rollout.RolledBack)
+}
+
+// agent retorna o agente da versão
+func (r *Rollout) agent(variant rollout.Variant) *CognitiveAgent {
+	if variant == rollout.Green {
+		return r.Green
+	}
+	return r.Blue
+}
+
+// decided registra e emite a decisão do rollout
+func (r *Rollout) decided(state rollout.State) {
+	stats := r.Stats()
+	log.Printf("🚦 Rollout de %s -> %s: %s (erros %.2f/%.2f, latência %v/%v)",
+		r.Blue.GetID(), r.Green.GetID(), state,
+		stats[rollout.Blue].ErrorRate(), stats[rollout.Green].ErrorRate(),
+		stats[rollout.Blue].MeanLatency(), stats[rollout.Green].MeanLatency())
+
+	if r.emitter == nil {
+		return
+	}
+	r.emitter.Emit(Event{
+		Type:      EventAgentAction,
+		Timestamp: time.Now(),
+		Source:    "rollout",
+		Data: map[string]interface{}{
+			"action":   "rollout_" + string(state),
+			"agent_id": r.Blue.GetID(),
+			"green_id": r.Green.GetID(),
+			"stats":    stats,
+		},
+	})
+}
@@ -0,0 +1,75 @@
+package agents
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/suissa/HiveMind/agents/brandvoice"
+	"github.com/suissa/HiveMind/agents/errs"
+	"github.com/suissa/HiveMind/agents/metrics"
+	"github.com/suissa/HiveMind/agents/prompt"
+)
+
+// brandVoiceRevisions conta as revisões pedidas pela voz da marca, por agente e regra violada
+var brandVoiceRevisions = metrics.Default.Counter("hivemind_brand_voice_revisions_total",
+	"Revisões de saídas pedidas por violar a voz da marca", "agent", "rule")
+
+// SetBrandVoice ativa o revisor da voz da marca nas saídas do agente: as diretrizes entram no
+// prompt de sistema, e uma saída que as viole volta ao agente com as violações até
+// voice.Revisions vezes; depois a tarefa falha com ErrValidation. Nil desativa.
+func (a *CognitiveAgent) SetBrandVoice(voice *brandvoice.Voice) {
+	if voice == nil {
+		a.brandVoice = nil
+		return
+	}
+	a.brandVoice = brandvoice.NewReviewer(voice)
+}
+
+// BrandVoice retorna as diretrizes da voz da marca do agente (nil se desativadas)
+func (a *CognitiveAgent) BrandVoice() *brandvoice.Voice {
+	if a.brandVoice == nil {
+		return nil
+	}
+	return a.brandVoice.Voice()
+}
+
+// withBrandVoice acrescenta as diretrizes da voz da marca ao prompt de sistema
+func (a *CognitiveAgent) withBrandVoice(system string) string {
+	if a.brandVoice == nil {
+		return system
+	}
+	guidelines := a.brandVoice.Voice().Guidelines()
+	if system == "" {
+		return guidelines
+	}
+	return system + "\n\n" + guidelines
+}
+
+// reviewBrandVoice revisa a saída da tarefa e pede novas versões ao LLM enquanto ela violar a
+// voz da marca. Como na validação, as novas versões não usam os caches.
+func (a *CognitiveAgent) reviewBrandVoice(ctx context.Context, task *Task, p prompt.Prompt, output string) (string, error) {
+	if a.brandVoice == nil {
+		return output, nil
+	}
+	review, err := a.brandVoice.Review(ctx, output, func(ctx context.Context, text string, request brandvoice.Request) (string, error) {
+		for _, violation := range request.Report.Violations {
+			brandVoiceRevisions.Inc(a.GetID(), violation.Rule)
+		}
+		log.Printf("⚠️ Agente %s: saída da tarefa %s viola a voz da marca, revisão %d", a.GetID(), task.ID, request.Attempt)
+
+		// Se a revisão exceder o prazo da tarefa, a versão atual é o resultado parcial
+		task.setPartial(text)
+		retry := p
+		retry.Input = fmt.Sprintf("%s\n\n%s\nResposta anterior:\n%s", p.Input, request, text)
+		return a.CompletePrompt(ctx, retry)
+	})
+	if err != nil {
+		return "", fmt.Errorf("erro ao revisar a saída da tarefa %s: %w", task.ID, err)
+	}
+	if !review.Approved {
+		return "", errs.New(errs.ErrValidation, "agents.Run", "saída da tarefa %s viola a voz da marca após %d revisões: %s",
+			task.ID, review.Revisions, brandvoice.Report{Violations: review.Violations()}.Feedback())
+	}
+	return review.Text, nil
+}
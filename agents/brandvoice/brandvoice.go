@@ -0,0 +1,341 @@
+// Package brandvoice aplica as diretrizes de voz da marca — tom, termos proibidos, limites de
+// estilo e textos de exemplo — aos textos gerados. Um revisor verifica o texto e, enquanto
+// houver violações, devolve-o ao redator com um pedido de revisão, até o limite de revisões.
+package brandvoice
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v2"
+)
+
+// DefaultMaxRevisions é o número padrão de revisões pedidas após um texto reprovado
+const DefaultMaxRevisions = 2
+
+// Regras verificadas
+const (
+	RuleBanned         = "banned"
+	RuleSentenceLength = "sentence_length"
+	RuleExclamations   = "exclamations"
+	RuleShouting       = "shouting"
+)
+
+// Voice são as diretrizes de voz da marca
+type Voice struct {
+	Name             string            `yaml:"name" json:"name"`
+	Tone             []string          `yaml:"tone,omitempty" json:"tone,omitempty"`                 // Ex.: "próximo", "confiante"
+	Banned           []string          `yaml:"banned,omitempty" json:"banned,omitempty"`             // Palavras e expressões proibidas
+	Replacements     map[string]string `yaml:"replacements,omitempty" json:"replacements,omitempty"` // Termo proibido → substituto preferido
+	Examples         []string          `yaml:"examples,omitempty" json:"examples,omitempty"`         // Textos de referência do estilo
+	MaxSentenceWords int               `yaml:"max_sentence_words,omitempty" json:"max_sentence_words,omitempty"`
+	MaxExclamations  int               `yaml:"max_exclamations,omitempty" json:"max_exclamations,omitempty"` // Por texto (0 sem limite; negativo proíbe)
+	NoShouting       bool              `yaml:"no_shouting,omitempty" json:"no_shouting,omitempty"`           // Proíbe palavras inteiras em caixa alta
+	MaxRevisions     int               `yaml:"max_revisions,omitempty" json:"max_revisions,omitempty"`       // DefaultMaxRevisions se zero; negativo desativa
+}
+
+// Load carrega as diretrizes de um arquivo YAML no formato "brand_voice: {...}"
+func Load(filename string) (*Voice, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler arquivo de voz da marca: %v", err)
+	}
+	var config struct {
+		Voice Voice `yaml:"brand_voice"`
+	}
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("erro ao decodificar arquivo de voz da marca: %v", err)
+	}
+	return &config.Voice, nil
+}
+
+// Revisions retorna o número efetivo de revisões
+func (v *Voice) Revisions() int {
+	switch {
+	case v.MaxRevisions < 0:
+		return 0
+	case v.MaxRevisions == 0:
+		return DefaultMaxRevisions
+	}
+	return v.MaxRevisions
+}
+
+// Guidelines monta as diretrizes em texto, para o prompt de sistema dos redatores
+func (v *Voice) Guidelines() string {
+	var b strings.Builder
+	if v.Name != "" {
+		fmt.Fprintf(&b, "Voz da marca %s.\n", v.Name)
+	}
+	if len(v.Tone) > 0 {
+		fmt.Fprintf(&b, "Tom: %s.\n", strings.Join(v.Tone, ", "))
+	}
+	if len(v.Banned) > 0 {
+		fmt.Fprintf(&b, "Nunca use: %s.\n", strings.Join(v.Banned, ", "))
+	}
+	if v.MaxSentenceWords > 0 {
+		fmt.Fprintf(&b, "Frases com no máximo %d palavras.\n", v.MaxSentenceWords)
+	}
+	switch {
+	case v.MaxExclamations < 0:
+		b.WriteString("Não use pontos de exclamação.\n")
+	case v.MaxExclamations > 0:
+		fmt.Fprintf(&b, "No máximo %d pontos de exclamação.\n", v.MaxExclamations)
+	}
+	if v.NoShouting {
+		b.WriteString("Não escreva palavras inteiras em caixa alta.\n")
+	}
+	if len(v.Examples) > 0 {
+		b.WriteString("Exemplos do estilo:\n")
+		for _, example := range v.Examples {
+			fmt.Fprintf(&b, "- %s\n", example)
+		}
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// Violation é uma violação das diretrizes
+type Violation struct {
+	Rule   string `json:"rule"`
+	Term   string `json:"term,omitempty"` // Termo ou trecho que violou a regra
+	Detail string `json:"detail"`
+}
+
+// Report é o resultado da verificação de um texto
+type Report struct {
+	Violations []Violation `json:"violations,omitempty"`
+}
+
+// Passed indica que o texto atende às diretrizes
+func (r Report) Passed() bool {
+	return len(r.Violations) == 0
+}
+
+// Feedback descreve as violações, uma por linha, para o pedido de revisão
+func (r Report) Feedback() string {
+	lines := make([]string, len(r.Violations))
+	for i, violation := range r.Violations {
+		lines[i] = "- " + violation.Detail
+	}
+	return strings.Join(lines, "\n")
+}
+
+var (
+	sentenceEnd = regexp.MustCompile(`[.!?]+(\s+|$)`)
+	wordPattern = regexp.MustCompile(`[\p{L}\p{N}]+`)
+)
+
+// Check verifica o texto contra as diretrizes
+func (v *Voice) Check(text string) Report {
+	var report Report
+	for _, term := range v.Banned {
+		if spans := findTerm(text, term); len(spans) > 0 {
+			found := text[spans[0][0]:spans[0][1]]
+			detail := fmt.Sprintf("termo proibido %q", found)
+			if replacement := v.Replacements[term]; replacement != "" {
+				detail += fmt.Sprintf("; use %q", replacement)
+			}
+			report.Violations = append(report.Violations, Violation{Rule: RuleBanned, Term: found, Detail: detail})
+		}
+	}
+	if v.MaxSentenceWords > 0 {
+		for _, sentence := range sentenceEnd.Split(text, -1) {
+			if words := len(wordPattern.FindAllString(sentence, -1)); words > v.MaxSentenceWords {
+				report.Violations = append(report.Violations, Violation{
+					Rule:   RuleSentenceLength,
+					Term:   excerpt(sentence),
+					Detail: fmt.Sprintf("frase com %d palavras (máximo %d): %q", words, v.MaxSentenceWords, excerpt(sentence)),
+				})
+			}
+		}
+	}
+	if count := strings.Count(text, "!"); v.MaxExclamations != 0 && count > maxExclamations(v.MaxExclamations) {
+		report.Violations = append(report.Violations, Violation{
+			Rule:   RuleExclamations,
+			Detail: fmt.Sprintf("%d pontos de exclamação (máximo %d)", count, maxExclamations(v.MaxExclamations)),
+		})
+	}
+	if v.NoShouting {
+		for _, word := range wordPattern.FindAllString(text, -1) {
+			if shouting(word) {
+				report.Violations = append(report.Violations, Violation{Rule: RuleShouting, Term: word, Detail: fmt.Sprintf("palavra em caixa alta %q", word)})
+			}
+		}
+	}
+	return report
+}
+
+// Apply faz as correções mecânicas: troca os termos proibidos pelos substitutos, rebaixa as
+// palavras em caixa alta e troca os pontos de exclamação excedentes por pontos finais. Frases
+// longas e termos sem substituto continuam a exigir revisão do redator.
+func (v *Voice) Apply(text string) string {
+	for _, term := range v.Banned {
+		replacement := v.Replacements[term]
+		if replacement == "" {
+			continue
+		}
+		spans := findTerm(text, term)
+		for i := len(spans) - 1; i >= 0; i-- {
+			start, end := spans[i][0], spans[i][1]
+			text = text[:start] + matchCase(text[start:end], replacement) + text[end:]
+		}
+	}
+	if v.NoShouting {
+		text = wordPattern.ReplaceAllStringFunc(text, func(word string) string {
+			if !shouting(word) {
+				return word
+			}
+			return matchCase(word, strings.ToLower(word))
+		})
+	}
+	if v.MaxExclamations != 0 {
+		allowed := maxExclamations(v.MaxExclamations)
+		text = strings.Map(func(r rune) rune {
+			if r != '!' {
+				return r
+			}
+			if allowed > 0 {
+				allowed--
+				return r
+			}
+			return '.'
+		}, text)
+	}
+	return text
+}
+
+// findTerm localiza as ocorrências do termo como palavra inteira, sem diferenciar maiúsculas
+func findTerm(text, term string) [][]int {
+	var spans [][]int
+	for _, span := range regexp.MustCompile(`(?i)`+regexp.QuoteMeta(term)).FindAllStringIndex(text, -1) {
+		before, _ := utf8.DecodeLastRuneInString(text[:span[0]])
+		after, _ := utf8.DecodeRuneInString(text[span[1]:])
+		if !wordRune(before) && !wordRune(after) {
+			spans = append(spans, span)
+		}
+	}
+	return spans
+}
+
+// wordRune indica letras e dígitos; utf8.RuneError marca o início ou o fim do texto
+func wordRune(r rune) bool {
+	return r != utf8.RuneError && (unicode.IsLetter(r) || unicode.IsDigit(r))
+}
+
+// maxExclamations converte o limite configurado (negativo proíbe) no máximo permitido
+func maxExclamations(limit int) int {
+	if limit < 0 {
+		return 0
+	}
+	return limit
+}
+
+// shouting indica uma palavra inteira em caixa alta com quatro ou mais letras, para não
+// confundir siglas como ROI e SEO com gritos
+func shouting(word string) bool {
+	letters := 0
+	for _, r := range word {
+		if unicode.IsLower(r) {
+			return false
+		}
+		if unicode.IsUpper(r) {
+			letters++
+		}
+	}
+	return letters > 3
+}
+
+// matchCase capitaliza o substituto quando o original começa em maiúscula
+func matchCase(original, replacement string) string {
+	first := []rune(strings.TrimSpace(original))
+	if len(first) == 0 || !unicode.IsUpper(first[0]) || replacement == "" {
+		return replacement
+	}
+	runes := []rune(replacement)
+	runes[0] = unicode.ToUpper(runes[0])
+	return string(runes)
+}
+
+// excerpt encurta o trecho para as mensagens
+func excerpt(text string) string {
+	text = strings.TrimSpace(text)
+	if runes := []rune(text); len(runes) > 60 {
+		return string(runes[:60]) + "…"
+	}
+	return text
+}
+
+// Request é o pedido de revisão enviado ao redator
+type Request struct {
+	Attempt int    // Revisão pedida, a partir de 1
+	Report  Report // Violações do texto atual
+	Voice   *Voice
+}
+
+// String monta o pedido em texto, com as violações e as diretrizes
+func (r Request) String() string {
+	return fmt.Sprintf("O texto viola a voz da marca:\n%s\n\nReescreva-o seguindo as diretrizes:\n%s",
+		r.Report.Feedback(), r.Voice.Guidelines())
+}
+
+// Reviser reescreve o texto atendendo ao pedido de revisão
+type Reviser func(ctx context.Context, text string, request Request) (string, error)
+
+// Review é o resultado da revisão de um texto
+type Review struct {
+	Text      string   `json:"text"`
+	Approved  bool     `json:"approved"`
+	Revisions int      `json:"revisions"`         // Revisões pedidas ao redator
+	Reports   []Report `json:"reports,omitempty"` // Verificação de cada versão, da original à final
+}
+
+// Violations retorna as violações da versão final
+func (r *Review) Violations() []Violation {
+	if len(r.Reports) == 0 {
+		return nil
+	}
+	return r.Reports[len(r.Reports)-1].Violations
+}
+
+// Reviewer verifica os textos contra a voz da marca e pede revisões
+type Reviewer struct {
+	voice *Voice
+}
+
+// NewReviewer cria o revisor das diretrizes
+func NewReviewer(voice *Voice) *Reviewer {
+	return &Reviewer{voice: voice}
+}
+
+// Voice retorna as diretrizes do revisor
+func (r *Reviewer) Voice() *Voice {
+	return r.voice
+}
+
+// Review verifica o texto e, enquanto houver violações, pede ao redator uma nova versão, até
+// Voice.Revisions vezes. Um texto ainda reprovado após as revisões volta com Approved falso;
+// cabe a quem chamou decidir se o descarta.
+func (r *Reviewer) Review(ctx context.Context, text string, revise Reviser) (*Review, error) {
+	review := &Review{Text: text}
+	for {
+		report := r.voice.Check(review.Text)
+		review.Reports = append(review.Reports, report)
+		if report.Passed() {
+			review.Approved = true
+			return review, nil
+		}
+		if review.Revisions >= r.voice.Revisions() {
+			return review, nil
+		}
+		review.Revisions++
+		revised, err := revise(ctx, review.Text, Request{Attempt: review.Revisions, Report: report, Voice: r.voice})
+		if err != nil {
+			return nil, fmt.Errorf("erro na revisão %d do texto: %w", review.Revisions, err)
+		}
+		review.Text = revised
+	}
+}
@@ -0,0 +1,101 @@
+package brandvoice
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func voice() *Voice {
+	return &Voice{
+		Name:             "Corre Já",
+		Tone:             []string{"próximo", "confiante"},
+		Banned:           []string{"barato", "melhor do mundo"},
+		Replacements:     map[string]string{"barato": "acessível"},
+		MaxSentenceWords: 8,
+		MaxExclamations:  1,
+		NoShouting:       true,
+	}
+}
+
+func rules(report Report) string {
+	var names []string
+	for _, violation := range report.Violations {
+		names = append(names, violation.Rule)
+	}
+	return strings.Join(names, ",")
+}
+
+func TestCheck(t *testing.T) {
+	v := voice()
+	if report := v.Check("Tênis acessível para o seu treino. Veja o ROI!"); !report.Passed() {
+		t.Fatalf("texto aprovado reportou violações: %+v", report)
+	}
+	report := v.Check("Barato e o MELHOR do mundo! Corra já! Um tênis leve que acompanha você em cada quilômetro da prova")
+	if got := rules(report); got != "banned,banned,sentence_length,exclamations,shouting" {
+		t.Fatalf("violações inesperadas: %s", got)
+	}
+	if report.Violations[0].Term != "Barato" || !strings.Contains(report.Feedback(), `use "acessível"`) {
+		t.Fatalf("termo inesperado: %+v", report.Violations[0])
+	}
+	// Termos só contam como palavra inteira
+	if report := v.Check("Baratos"); !report.Passed() {
+		t.Fatalf("parte de palavra não deveria violar: %+v", report)
+	}
+}
+
+func TestApply(t *testing.T) {
+	text := voice().Apply("Barato, muito barato! GRÁTIS! Corra!")
+	if text != "Acessível, muito acessível! Grátis. Corra." {
+		t.Fatalf("correção inesperada: %q", text)
+	}
+}
+
+func TestReview(t *testing.T) {
+	v := voice()
+	reviewer := NewReviewer(v)
+	var requests []Request
+	revise := func(_ context.Context, text string, request Request) (string, error) {
+		requests = append(requests, request)
+		return v.Apply(text), nil
+	}
+
+	review, err := reviewer.Review(context.Background(), "Tênis barato!!", revise)
+	if err != nil || !review.Approved || review.Revisions != 1 || review.Text != "Tênis acessível!." || len(review.Reports) != 2 {
+		t.Fatalf("revisão inesperada: %+v %v", review, err)
+	}
+	if !strings.Contains(requests[0].String(), "Tom: próximo, confiante.") {
+		t.Fatalf("pedido sem as diretrizes: %s", requests[0])
+	}
+
+	// Termos sem substituto continuam reprovados após as revisões
+	review, _ = reviewer.Review(context.Background(), "O melhor do mundo", revise)
+	if review.Approved || review.Revisions != DefaultMaxRevisions || len(review.Violations()) != 1 {
+		t.Fatalf("revisão inesperada: %+v", review)
+	}
+
+	failure := errors.New("redator indisponível")
+	if _, err := reviewer.Review(context.Background(), "barato", func(context.Context, string, Request) (string, error) {
+		return "", failure
+	}); !errors.Is(err, failure) {
+		t.Fatalf("erro inesperado: %v", err)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "brand_voice.yaml")
+	data := "brand_voice:\n  name: Corre Já\n  tone: [próximo]\n  banned: [barato]\n  max_exclamations: -1\n  max_revisions: -1\n"
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	v, err := Load(path)
+	if err != nil || v.Name != "Corre Já" || v.Revisions() != 0 {
+		t.Fatalf("diretrizes inesperadas: %+v %v", v, err)
+	}
+	if !strings.Contains(v.Guidelines(), "Não use pontos de exclamação.") || v.Check("Corra!").Passed() {
+		t.Fatalf("exclamações deveriam ser proibidas: %s", v.Guidelines())
+	}
+}
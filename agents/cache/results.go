@@ -0,0 +1,146 @@
+// Package cache armazena resultados de tarefas endereçados pelo conteúdo (hash do
+// modelo, do prompt e das entradas), para que execuções repetidas de um workflow com
+// as mesmas entradas reutilizem as respostas anteriores em vez de chamar o LLM.
+package cache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Entry é um resultado armazenado
+type Entry struct {
+	Key       string    `json:"key"`
+	Model     string    `json:"model,omitempty"`
+	Output    string    `json:"output"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Store armazena resultados pela chave calculada com Key
+type Store interface {
+	Get(ctx context.Context, key string) (*Entry, bool, error)
+	Put(ctx context.Context, entry Entry) error
+}
+
+// SemanticStore reutiliza respostas de consultas semanticamente similares, separadas por
+// namespace (ex.: agente e modelo). A implementação sobre o Weaviate é memory.SemanticCache.
+type SemanticStore interface {
+	Lookup(ctx context.Context, namespace, query string) (response string, similarity float64, ok bool, err error)
+	Store(ctx context.Context, namespace, query, response string) error
+}
+
+// Key calcula a chave de um resultado a partir do modelo, do system prompt, do prompt e das
+// entradas da tarefa. As entradas são serializadas em JSON (chaves ordenadas).
+func Key(model, system, prompt string, inputs map[string]interface{}) string {
+	encoded, err := json.Marshal(inputs)
+	if err != nil {
+		encoded = []byte(fmt.Sprintf("%v", inputs))
+	}
+
+	h := sha256.New()
+	for _, part := range [][]byte{[]byte(model), []byte(system), []byte(prompt), encoded} {
+		h.Write(part)
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// MemoryStore mantém os resultados em memória, durante a vida do processo
+type MemoryStore struct {
+	entries map[string]Entry
+	mu      sync.RWMutex
+}
+
+// NewMemoryStore cria um MemoryStore vazio
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{entries: make(map[string]Entry)}
+}
+
+// Get implementa Store
+func (s *MemoryStore) Get(ctx context.Context, key string) (*Entry, bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	entry, ok := s.entries[key]
+	if !ok {
+		return nil, false, nil
+	}
+	return &entry, true, nil
+}
+
+// Put implementa Store
+func (s *MemoryStore) Put(ctx context.Context, entry Entry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries[entry.Key] = entry
+	return nil
+}
+
+// FileStore grava cada resultado em um arquivo JSON do diretório (um arquivo por chave),
+// preservando o cache entre execuções durante o desenvolvimento
+type FileStore struct {
+	dir string
+}
+
+// NewFileStore cria o diretório, se necessário, e retorna um FileStore sobre ele
+func NewFileStore(dir string) (*FileStore, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("erro ao criar diretório do cache: %v", err)
+	}
+	return &FileStore{dir: dir}, nil
+}
+
+// path retorna o arquivo da chave
+func (s *FileStore) path(key string) string {
+	return filepath.Join(s.dir, key+".json")
+}
+
+// Get implementa Store
+func (s *FileStore) Get(ctx context.Context, key string) (*Entry, bool, error) {
+	data, err := os.ReadFile(s.path(key))
+	if os.IsNotExist(err) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("erro ao ler cache: %v", err)
+	}
+
+	var entry Entry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, false, fmt.Errorf("entrada de cache inválida %s: %v", key, err)
+	}
+	return &entry, true, nil
+}
+
+// Put implementa Store. O arquivo é gravado de forma atômica (arquivo temporário + rename).
+func (s *FileStore) Put(ctx context.Context, entry Entry) error {
+	data, err := json.MarshalIndent(entry, "", "  ")
+	if err != nil {
+		return fmt.Errorf("erro ao serializar entrada de cache: %v", err)
+	}
+
+	tmp, err := os.CreateTemp(s.dir, entry.Key+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("erro ao gravar cache: %v", err)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return fmt.Errorf("erro ao gravar cache: %v", err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("erro ao gravar cache: %v", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path(entry.Key)); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("erro ao gravar cache: %v", err)
+	}
+	return nil
+}
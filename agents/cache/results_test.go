@@ -0,0 +1,41 @@
+package cache
+
+import (
+	"context"
+	"testing"
+)
+
+func TestKeyIsContentAddressed(t *testing.T) {
+	a := Key("gpt-4", "system", "prompt", map[string]interface{}{"a": 1, "b": "x"})
+	b := Key("gpt-4", "system", "prompt", map[string]interface{}{"b": "x", "a": 1})
+	if a != b {
+		t.Fatal("a ordem das entradas não deve alterar a chave")
+	}
+	if a == Key("gpt-3.5", "system", "prompt", map[string]interface{}{"a": 1, "b": "x"}) {
+		t.Fatal("modelos diferentes devem gerar chaves diferentes")
+	}
+	if Key("m", "ab", "c", nil) == Key("m", "a", "bc", nil) {
+		t.Fatal("as partes da chave devem ser delimitadas")
+	}
+}
+
+func TestStores(t *testing.T) {
+	ctx := context.Background()
+	files, err := NewFileStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, store := range map[string]Store{"memory": NewMemoryStore(), "file": files} {
+		if _, ok, err := store.Get(ctx, "k"); ok || err != nil {
+			t.Fatalf("%s: chave inexistente retornou ok=%v err=%v", name, ok, err)
+		}
+		if err := store.Put(ctx, Entry{Key: "k", Model: "gpt-4", Output: "resposta"}); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		entry, ok, err := store.Get(ctx, "k")
+		if !ok || err != nil || entry.Output != "resposta" {
+			t.Fatalf("%s: entrada inesperada %#v ok=%v err=%v", name, entry, ok, err)
+		}
+	}
+}
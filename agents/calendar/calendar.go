@@ -0,0 +1,123 @@
+// Package calendar monta o calendário de conteúdo de uma campanha — datas, canais, variantes
+// do texto e responsáveis — e o exporta em CSV, para planilhas, e em iCalendar (ICS), para
+// Google Calendar, Outlook e afins.
+package calendar
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Valores padrão do plano
+const (
+	DefaultWeeks    = 4
+	DefaultAt       = 10 * time.Hour
+	DefaultDuration = 30 * time.Minute
+)
+
+// DefaultWeekdays são os dias de publicação padrão
+var DefaultWeekdays = []time.Weekday{time.Tuesday, time.Thursday}
+
+// Entry é uma publicação do calendário
+type Entry struct {
+	Date     time.Time `json:"date"`
+	Channel  string    `json:"channel"`
+	Title    string    `json:"title"`
+	Variants []string  `json:"variants"` // Variantes do texto; a primeira é a principal
+	Owner    string    `json:"owner,omitempty"`
+	Campaign string    `json:"campaign,omitempty"`
+}
+
+// Calendar é o calendário de conteúdo
+type Calendar struct {
+	Name     string        `json:"name"`
+	Duration time.Duration `json:"duration"` // Duração de cada evento no ICS
+	Entries  []Entry       `json:"entries"`
+}
+
+// Plan descreve como distribuir as publicações
+type Plan struct {
+	Start        time.Time         `json:"start" yaml:"start"`                                     // Primeiro dia; o fuso das datas é o de Start (padrão: amanhã)
+	Weeks        int               `json:"weeks,omitempty" yaml:"weeks,omitempty"`                 // Padrão DefaultWeeks
+	Weekdays     []time.Weekday    `json:"weekdays,omitempty" yaml:"weekdays,omitempty"`           // Padrão DefaultWeekdays
+	At           time.Duration     `json:"at,omitempty" yaml:"at,omitempty"`                       // Horário, desde a meia-noite (padrão DefaultAt)
+	Channels     []string          `json:"channels" yaml:"channels"`                               // Uma publicação por canal em cada dia
+	Owners       map[string]string `json:"owners,omitempty" yaml:"owners,omitempty"`               // Responsável por canal
+	DefaultOwner string            `json:"default_owner,omitempty" yaml:"default_owner,omitempty"` // Responsável dos canais sem dono
+}
+
+// Generate distribui as publicações da campanha pelos dias do plano. Cada publicação recebe
+// todas as variantes, em rodízio: a variante principal muda a cada publicação do canal, para
+// que o público veja textos diferentes.
+func Generate(name, campaign string, variants []string, plan Plan) (*Calendar, error) {
+	if len(plan.Channels) == 0 {
+		return nil, fmt.Errorf("calendário %s sem canais", name)
+	}
+	if len(variants) == 0 {
+		return nil, fmt.Errorf("calendário %s sem variantes do texto", name)
+	}
+	if plan.Start.IsZero() {
+		plan.Start = time.Now().AddDate(0, 0, 1)
+	}
+	if plan.Weeks <= 0 {
+		plan.Weeks = DefaultWeeks
+	}
+	if len(plan.Weekdays) == 0 {
+		plan.Weekdays = DefaultWeekdays
+	}
+	if plan.At <= 0 {
+		plan.At = DefaultAt
+	}
+	weekdays := make(map[time.Weekday]bool, len(plan.Weekdays))
+	for _, day := range plan.Weekdays {
+		weekdays[day] = true
+	}
+
+	calendar := &Calendar{Name: name, Duration: DefaultDuration}
+	start := time.Date(plan.Start.Year(), plan.Start.Month(), plan.Start.Day(), 0, 0, 0, 0, plan.Start.Location())
+	posts := make(map[string]int, len(plan.Channels))
+	for day := 0; day < plan.Weeks*7; day++ {
+		date := start.AddDate(0, 0, day)
+		if !weekdays[date.Weekday()] {
+			continue
+		}
+		for _, channel := range plan.Channels {
+			n := posts[channel]
+			posts[channel]++
+			owner := plan.Owners[channel]
+			if owner == "" {
+				owner = plan.DefaultOwner
+			}
+			calendar.Entries = append(calendar.Entries, Entry{
+				Date:     date.Add(plan.At),
+				Channel:  channel,
+				Title:    fmt.Sprintf("%s — %s #%d", campaign, channel, n+1),
+				Variants: rotate(variants, n),
+				Owner:    owner,
+				Campaign: campaign,
+			})
+		}
+	}
+	return calendar, nil
+}
+
+// rotate retorna as variantes começando pela de índice n
+func rotate(variants []string, n int) []string {
+	n %= len(variants)
+	return append(append([]string(nil), variants[n:]...), variants[:n]...)
+}
+
+// Channels retorna os canais do calendário, em ordem alfabética
+func (c *Calendar) Channels() []string {
+	seen := make(map[string]bool)
+	var channels []string
+	for _, entry := range c.Entries {
+		if !seen[entry.Channel] {
+			seen[entry.Channel] = true
+			channels = append(channels, entry.Channel)
+		}
+	}
+	sort.Strings(channels)
+	return channels
+}
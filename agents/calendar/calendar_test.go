@@ -0,0 +1,79 @@
+package calendar
+
+import (
+	"bytes"
+	"encoding/csv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func plan() Plan {
+	return Plan{
+		Start:        time.Date(2024, time.March, 4, 15, 0, 0, 0, time.UTC), // Segunda-feira
+		Weeks:        2,
+		Channels:     []string{"Instagram", "LinkedIn"},
+		Owners:       map[string]string{"LinkedIn": "Ana"},
+		DefaultOwner: "content-creator",
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	if _, err := Generate("Verão", "Campanha", []string{"a"}, Plan{}); err == nil {
+		t.Fatal("plano sem canais deveria falhar")
+	}
+	calendar, err := Generate("Verão", "Campanha", []string{"A", "B"}, plan())
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Terças e quintas de duas semanas, dois canais
+	if len(calendar.Entries) != 8 {
+		t.Fatalf("publicações inesperadas: %d", len(calendar.Entries))
+	}
+	first, third := calendar.Entries[0], calendar.Entries[2]
+	if !first.Date.Equal(time.Date(2024, time.March, 5, 10, 0, 0, 0, time.UTC)) || first.Owner != "content-creator" || calendar.Entries[1].Owner != "Ana" {
+		t.Fatalf("primeira publicação inesperada: %+v", first)
+	}
+	if first.Variants[0] != "A" || third.Variants[0] != "B" || third.Title != "Campanha — Instagram #2" {
+		t.Fatalf("rodízio inesperado: %+v %+v", first, third)
+	}
+	if channels := calendar.Channels(); len(channels) != 2 || channels[0] != "Instagram" {
+		t.Fatalf("canais inesperados: %v", channels)
+	}
+}
+
+func TestExport(t *testing.T) {
+	long := strings.Repeat("Corra mais, sofra menos; ", 10)
+	calendar, _ := Generate("Verão", "Campanha", []string{long, "B"}, plan())
+
+	var out bytes.Buffer
+	if err := calendar.WriteCSV(&out); err != nil {
+		t.Fatal(err)
+	}
+	rows, err := csv.NewReader(&out).ReadAll()
+	if err != nil || len(rows) != 9 || rows[0][5] != "variant_1" || rows[1][0] != "2024-03-05" || rows[1][1] != "10:00" || rows[1][5] != long {
+		t.Fatalf("CSV inesperado: %v %v", rows, err)
+	}
+
+	out.Reset()
+	if err := calendar.WriteICS(&out); err != nil {
+		t.Fatal(err)
+	}
+	ics := out.String()
+	if strings.Count(ics, "BEGIN:VEVENT") != 8 || !strings.Contains(ics, "DTSTART:20240305T100000Z\r\n") || !strings.Contains(ics, "CATEGORIES:LinkedIn\r\n") {
+		t.Fatalf("ICS inesperado:\n%s", ics)
+	}
+	for _, line := range strings.Split(ics, "\r\n") {
+		if len(line) > 75 {
+			t.Fatalf("linha não dobrada: %q", line)
+		}
+	}
+	if _, err := calendar.Export("pdf"); err == nil {
+		t.Fatal("formato desconhecido deveria falhar")
+	}
+	// Desdobrada, a descrição mantém o texto escapado
+	unfolded := strings.ReplaceAll(ics, "\r\n ", "")
+	if !strings.Contains(unfolded, `Corra mais\, sofra menos\;`) || !strings.Contains(unfolded, `\nResponsável: content-creator`) {
+		t.Fatalf("descrição inesperada:\n%s", unfolded)
+	}
+}
@@ -0,0 +1,159 @@
+package calendar
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Format é o formato de exportação do calendário
+type Format string
+
+// Formatos de exportação
+const (
+	FormatCSV Format = "csv"
+	FormatICS Format = "ics"
+)
+
+// ContentType retorna o tipo MIME do formato
+func (f Format) ContentType() string {
+	if f == FormatICS {
+		return "text/calendar; charset=utf-8"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+// Export grava o calendário no formato informado
+func (c *Calendar) Export(format Format) ([]byte, error) {
+	var buf bytes.Buffer
+	var err error
+	switch format {
+	case FormatCSV:
+		err = c.WriteCSV(&buf)
+	case FormatICS:
+		err = c.WriteICS(&buf)
+	default:
+		return nil, fmt.Errorf("formato de calendário desconhecido: %s", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// WriteCSV grava o calendário em CSV, uma linha por publicação: data, hora, canal,
+// responsável, título e uma coluna por variante
+func (c *Calendar) WriteCSV(w io.Writer) error {
+	variants := 0
+	for _, entry := range c.Entries {
+		if len(entry.Variants) > variants {
+			variants = len(entry.Variants)
+		}
+	}
+	writer := csv.NewWriter(w)
+	header := []string{"date", "time", "channel", "owner", "title"}
+	for i := 1; i <= variants; i++ {
+		header = append(header, "variant_"+strconv.Itoa(i))
+	}
+	if err := writer.Write(header); err != nil {
+		return fmt.Errorf("erro ao gravar o CSV do calendário: %v", err)
+	}
+	for _, entry := range c.Entries {
+		row := []string{entry.Date.Format("2006-01-02"), entry.Date.Format("15:04"), entry.Channel, entry.Owner, entry.Title}
+		row = append(row, entry.Variants...)
+		for len(row) < len(header) {
+			row = append(row, "")
+		}
+		if err := writer.Write(row); err != nil {
+			return fmt.Errorf("erro ao gravar o CSV do calendário: %v", err)
+		}
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("erro ao gravar o CSV do calendário: %v", err)
+	}
+	return nil
+}
+
+// icsTime é o formato das datas em UTC do iCalendar
+const icsTime = "20060102T150405Z"
+
+// WriteICS grava o calendário em iCalendar (RFC 5545), um evento por publicação, com o canal
+// como categoria e as variantes e o responsável na descrição. Os UIDs são derivados da
+// publicação, então importar de novo o calendário atualiza os eventos em vez de duplicá-los.
+func (c *Calendar) WriteICS(w io.Writer) error {
+	buf := bufio.NewWriter(w)
+	duration := c.Duration
+	if duration <= 0 {
+		duration = DefaultDuration
+	}
+	stamp := time.Now().UTC().Format(icsTime)
+	writeLine(buf, "BEGIN:VCALENDAR")
+	writeLine(buf, "VERSION:2.0")
+	writeLine(buf, "PRODID:-//HiveMind//Content Calendar//PT")
+	writeLine(buf, "CALSCALE:GREGORIAN")
+	writeLine(buf, "X-WR-CALNAME:"+escape(c.Name))
+	for _, entry := range c.Entries {
+		description := make([]string, 0, len(entry.Variants)+1)
+		for i, variant := range entry.Variants {
+			description = append(description, fmt.Sprintf("Variante %d: %s", i+1, variant))
+		}
+		if entry.Owner != "" {
+			description = append(description, "Responsável: "+entry.Owner)
+		}
+		writeLine(buf, "BEGIN:VEVENT")
+		writeLine(buf, "UID:"+uid(c.Name, entry))
+		writeLine(buf, "DTSTAMP:"+stamp)
+		writeLine(buf, "DTSTART:"+entry.Date.UTC().Format(icsTime))
+		writeLine(buf, "DTEND:"+entry.Date.Add(duration).UTC().Format(icsTime))
+		writeLine(buf, "SUMMARY:"+escape(entry.Title))
+		writeLine(buf, "CATEGORIES:"+escape(entry.Channel))
+		writeLine(buf, "DESCRIPTION:"+escape(strings.Join(description, "\n")))
+		writeLine(buf, "END:VEVENT")
+	}
+	writeLine(buf, "END:VCALENDAR")
+	if err := buf.Flush(); err != nil {
+		return fmt.Errorf("erro ao gravar o ICS do calendário: %v", err)
+	}
+	return nil
+}
+
+// uid identifica a publicação pelo calendário, canal e data
+func uid(name string, entry Entry) string {
+	sum := sha256.Sum256([]byte(name + "|" + entry.Channel + "|" + entry.Date.UTC().Format(icsTime)))
+	return hex.EncodeToString(sum[:12]) + "@hivemind"
+}
+
+// escape escapa o texto de uma propriedade do iCalendar
+func escape(text string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`).Replace(text)
+}
+
+// writeLine grava a linha terminada em CRLF, dobrada a cada 75 bytes sem partir caracteres
+// UTF-8 (RFC 5545, seção 3.1)
+func writeLine(w *bufio.Writer, line string) {
+	limit := 75
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8Start(line[cut]) {
+			cut--
+		}
+		w.WriteString(line[:cut] + "\r\n ")
+		line = line[cut:]
+		// As linhas de continuação começam com um espaço, que conta no limite
+		limit = 74
+	}
+	w.WriteString(line + "\r\n")
+}
+
+// utf8Start indica se o byte inicia um caractere UTF-8
+func utf8Start(b byte) bool {
+	return b&0xC0 != 0x80
+}
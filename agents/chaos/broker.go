@@ -0,0 +1,80 @@
+package chaos
+
+import (
+	"context"
+	"time"
+
+	"github.com/suissa/HiveMind/agents/communication"
+)
+
+// Dropper é implementado pelos clientes capazes de derrubar a própria conexão, deixando a
+// reconexão por conta deles (ex.: communication.RabbitMQPool)
+type Dropper interface {
+	DropConnection() error
+}
+
+// Disconnect sorteia uma queda do broker com Config.BrokerDisconnectRate e, se ocorrer,
+// derruba a conexão do alvo. Retorna se a conexão foi derrubada.
+func (i *Injector) Disconnect(target Dropper) bool {
+	if !i.roll(BrokerDisconnect, i.config.BrokerDisconnectRate) {
+		return false
+	}
+	return target.DropConnection() == nil
+}
+
+// Disconnects sorteia uma queda do alvo a cada intervalo, até o contexto ser cancelado
+func (i *Injector) Disconnects(ctx context.Context, interval time.Duration, target Dropper) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			i.Disconnect(target)
+		}
+	}
+}
+
+// Broker envolve o cliente de mensageria: uma publicação pode falhar como numa queda do broker
+// (derrubando a conexão se o cliente implementar Dropper) ou ser entregue em duplicidade, e
+// cada mensagem recebida pode chegar duas vezes ao handler
+func (i *Injector) Broker(client communication.CommunicationClient) communication.CommunicationClient {
+	return &faultyBroker{CommunicationClient: client, injector: i}
+}
+
+// faultyBroker é o cliente de mensageria com falhas injetadas
+type faultyBroker struct {
+	communication.CommunicationClient
+	injector *Injector
+}
+
+// Publish implementa communication.CommunicationClient
+func (b *faultyBroker) Publish(ctx context.Context, subject string, data []byte) error {
+	if b.injector.roll(BrokerDisconnect, b.injector.config.BrokerDisconnectRate) {
+		if dropper, ok := b.CommunicationClient.(Dropper); ok {
+			dropper.DropConnection()
+		}
+		return fail(nil, "chaos.Broker.Publish", BrokerDisconnect)
+	}
+	if err := b.CommunicationClient.Publish(ctx, subject, data); err != nil {
+		return err
+	}
+	if b.injector.roll(Duplicate, b.injector.config.DuplicateRate) {
+		return b.CommunicationClient.Publish(ctx, subject, data)
+	}
+	return nil
+}
+
+// Subscribe implementa communication.CommunicationClient
+func (b *faultyBroker) Subscribe(subject string, handler communication.MessageHandler) error {
+	return b.CommunicationClient.Subscribe(subject, func(ctx context.Context, subject string, data []byte) error {
+		if err := handler(ctx, subject, data); err != nil {
+			return err
+		}
+		if b.injector.roll(Duplicate, b.injector.config.DuplicateRate) {
+			return handler(ctx, subject, data)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,141 @@
+// Package chaos injeta falhas controladas nos componentes do HiveMind: quedas do broker,
+// latência e erros do LLM, falhas do backend de memória e mensagens duplicadas, cada uma
+// com a sua taxa. Serve para validar em testes que retries, circuit breakers, reconexões e
+// deduplicação se comportam como esperado.
+package chaos
+
+import (
+	"errors"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/suissa/HiveMind/agents/errs"
+	"github.com/suissa/HiveMind/agents/metrics"
+)
+
+// ErrInjected identifica as falhas injetadas: errors.Is(err, chaos.ErrInjected)
+var ErrInjected = errors.New("falha injetada pelo chaos")
+
+var injectedTotal = metrics.Default.Counter("hivemind_chaos_injected_total",
+	"Falhas injetadas pelo módulo de chaos", "fault")
+
+// Fault é um tipo de falha injetada
+type Fault string
+
+// Falhas suportadas
+const (
+	BrokerDisconnect Fault = "broker_disconnect"
+	Duplicate        Fault = "duplicate"
+	LLMError         Fault = "llm_error"
+	LLMLatency       Fault = "llm_latency"
+	MemoryFailure    Fault = "memory_failure"
+)
+
+// DefaultLLMLatency é a latência acrescentada quando Config.LLMLatency não é definido
+const DefaultLLMLatency = 2 * time.Second
+
+// Config define a taxa (probabilidade entre 0 e 1, por operação) de cada falha
+type Config struct {
+	Seed                 int64         `json:"seed,omitempty" yaml:"seed,omitempty"` // Zero usa uma semente aleatória
+	BrokerDisconnectRate float64       `json:"broker_disconnect_rate,omitempty" yaml:"broker_disconnect_rate,omitempty"`
+	DuplicateRate        float64       `json:"duplicate_rate,omitempty" yaml:"duplicate_rate,omitempty"`
+	LLMErrorRate         float64       `json:"llm_error_rate,omitempty" yaml:"llm_error_rate,omitempty"`
+	LLMLatencyRate       float64       `json:"llm_latency_rate,omitempty" yaml:"llm_latency_rate,omitempty"`
+	LLMLatency           time.Duration `json:"llm_latency,omitempty" yaml:"llm_latency,omitempty"`
+	MemoryFailureRate    float64       `json:"memory_failure_rate,omitempty" yaml:"memory_failure_rate,omitempty"`
+}
+
+// Validate verifica se as taxas estão entre 0 e 1
+func (c Config) Validate() error {
+	rates := map[string]float64{
+		"broker_disconnect_rate": c.BrokerDisconnectRate,
+		"duplicate_rate":         c.DuplicateRate,
+		"llm_error_rate":         c.LLMErrorRate,
+		"llm_latency_rate":       c.LLMLatencyRate,
+		"memory_failure_rate":    c.MemoryFailureRate,
+	}
+	for name, rate := range rates {
+		if rate < 0 || rate > 1 {
+			return errs.New(errs.ErrValidation, "chaos.Config", "%s deve estar entre 0 e 1: %v", name, rate)
+		}
+	}
+	if c.LLMLatency < 0 {
+		return errs.New(errs.ErrValidation, "chaos.Config", "llm_latency não pode ser negativa")
+	}
+	return nil
+}
+
+// Injector sorteia as falhas com as taxas da configuração. É seguro para uso concorrente e
+// pode ser desativado durante o teste para verificar a recuperação.
+type Injector struct {
+	config   Config
+	rand     *rand.Rand
+	disabled bool
+	stats    map[Fault]int64
+	mu       sync.Mutex
+}
+
+// New cria um injetor com a configuração informada
+func New(config Config) (*Injector, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	if config.LLMLatency == 0 {
+		config.LLMLatency = DefaultLLMLatency
+	}
+	seed := config.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	return &Injector{
+		config: config,
+		rand:   rand.New(rand.NewSource(seed)),
+		stats:  make(map[Fault]int64),
+	}, nil
+}
+
+// Config retorna a configuração do injetor
+func (i *Injector) Config() Config {
+	return i.config
+}
+
+// SetEnabled ativa ou desativa a injeção de falhas
+func (i *Injector) SetEnabled(enabled bool) {
+	i.mu.Lock()
+	i.disabled = !enabled
+	i.mu.Unlock()
+}
+
+// Stats retorna quantas falhas de cada tipo foram injetadas
+func (i *Injector) Stats() map[Fault]int64 {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	stats := make(map[Fault]int64, len(i.stats))
+	for fault, n := range i.stats {
+		stats[fault] = n
+	}
+	return stats
+}
+
+// roll sorteia a falha com a taxa informada, contabilizando-a quando ocorre
+func (i *Injector) roll(fault Fault, rate float64) bool {
+	if rate <= 0 {
+		return false
+	}
+	i.mu.Lock()
+	hit := !i.disabled && i.rand.Float64() < rate
+	if hit {
+		i.stats[fault]++
+	}
+	i.mu.Unlock()
+	if hit {
+		injectedTotal.Inc(string(fault))
+	}
+	return hit
+}
+
+// fail cria o erro de uma falha injetada, classificado também na categoria informada
+func fail(kind error, op string, fault Fault) error {
+	return errs.Wrap(kind, op, ErrInjected, "falha injetada: %s", fault)
+}
@@ -0,0 +1,154 @@
+package chaos
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/suissa/HiveMind/agents/communication"
+	"github.com/suissa/HiveMind/agents/errs"
+	"github.com/suissa/HiveMind/agents/llm"
+	"github.com/suissa/HiveMind/agents/memory"
+)
+
+func TestConfigValidate(t *testing.T) {
+	if _, err := New(Config{LLMErrorRate: 1.5}); !errors.Is(err, errs.ErrValidation) {
+		t.Fatalf("esperava ErrValidation para taxa acima de 1: %v", err)
+	}
+	if _, err := New(Config{DuplicateRate: -0.1}); !errors.Is(err, errs.ErrValidation) {
+		t.Fatalf("esperava ErrValidation para taxa negativa: %v", err)
+	}
+}
+
+func TestLLM(t *testing.T) {
+	injector, err := New(Config{Seed: 1, LLMErrorRate: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	provider := injector.LLM(llm.ProviderFunc(func(ctx context.Context, req llm.Request) (*llm.Response, error) {
+		return &llm.Response{Text: "ok"}, nil
+	}))
+
+	_, err = provider.Complete(context.Background(), llm.Request{})
+	if !errors.Is(err, ErrInjected) || !errors.Is(err, errs.ErrRateLimited) {
+		t.Fatalf("esperava falha injetada transitória: %v", err)
+	}
+
+	// Desativado, o provedor original responde
+	injector.SetEnabled(false)
+	if resp, err := provider.Complete(context.Background(), llm.Request{}); err != nil || resp.Text != "ok" {
+		t.Fatalf("Complete = %v, %v", resp, err)
+	}
+	if stats := injector.Stats(); stats[LLMError] != 1 {
+		t.Fatalf("estatísticas = %v", stats)
+	}
+}
+
+func TestLLMLatencyRespectsContext(t *testing.T) {
+	injector, err := New(Config{Seed: 1, LLMLatencyRate: 1, LLMLatency: time.Hour})
+	if err != nil {
+		t.Fatal(err)
+	}
+	provider := injector.LLM(llm.ProviderFunc(func(ctx context.Context, req llm.Request) (*llm.Response, error) {
+		return &llm.Response{}, nil
+	}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := provider.Complete(ctx, llm.Request{}); !errors.Is(err, errs.ErrTimeout) {
+		t.Fatalf("esperava ErrTimeout: %v", err)
+	}
+}
+
+type fakeMemory struct {
+	memory.MemoryManager
+	stored int
+}
+
+func (m *fakeMemory) StoreMemory(ctx context.Context, mem *memory.Memory) error {
+	m.stored++
+	return nil
+}
+
+func (m *fakeMemory) Close(ctx context.Context) error {
+	return nil
+}
+
+func TestMemory(t *testing.T) {
+	injector, err := New(Config{Seed: 42, MemoryFailureRate: 0.5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	backend := &fakeMemory{}
+	manager := injector.Memory(backend)
+
+	failures := 0
+	for n := 0; n < 200; n++ {
+		if err := manager.StoreMemory(context.Background(), &memory.Memory{}); err != nil {
+			if !errors.Is(err, ErrInjected) {
+				t.Fatalf("erro inesperado: %v", err)
+			}
+			failures++
+		}
+	}
+	if failures < 50 || failures > 150 || backend.stored != 200-failures {
+		t.Fatalf("%d falhas e %d gravações em 200 operações", failures, backend.stored)
+	}
+	if err := manager.Close(context.Background()); err != nil {
+		t.Fatalf("Close não deveria falhar: %v", err)
+	}
+}
+
+type fakeBroker struct {
+	communication.CommunicationClient
+	published int
+	dropped   int
+	handler   communication.MessageHandler
+}
+
+func (b *fakeBroker) Publish(ctx context.Context, subject string, data []byte) error {
+	b.published++
+	return nil
+}
+
+func (b *fakeBroker) Subscribe(subject string, handler communication.MessageHandler) error {
+	b.handler = handler
+	return nil
+}
+
+func (b *fakeBroker) DropConnection() error {
+	b.dropped++
+	return nil
+}
+
+func TestBroker(t *testing.T) {
+	injector, err := New(Config{Seed: 1, DuplicateRate: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	backend := &fakeBroker{}
+	client := injector.Broker(backend)
+
+	if err := client.Publish(context.Background(), "tasks", []byte("x")); err != nil || backend.published != 2 {
+		t.Fatalf("Publish = %v com %d publicações; esperava a duplicata", err, backend.published)
+	}
+
+	received := 0
+	client.Subscribe("tasks", func(ctx context.Context, subject string, data []byte) error {
+		received++
+		return nil
+	})
+	backend.handler(context.Background(), "tasks", []byte("x"))
+	if received != 2 {
+		t.Fatalf("handler chamado %d vezes; esperava 2", received)
+	}
+
+	disconnects, err := New(Config{Seed: 1, BrokerDisconnectRate: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := disconnects.Broker(backend).Publish(context.Background(), "tasks", nil); !errors.Is(err, ErrInjected) || backend.dropped != 1 {
+		t.Fatalf("Publish = %v com %d quedas; esperava a queda injetada", err, backend.dropped)
+	}
+}
@@ -0,0 +1,29 @@
+package chaos
+
+import (
+	"context"
+	"time"
+
+	"github.com/suissa/HiveMind/agents/errs"
+	"github.com/suissa/HiveMind/agents/llm"
+)
+
+// LLM envolve o provedor: cada chamada pode sofrer a latência de Config.LLMLatency e falhar
+// com um erro transitório (ErrRateLimited), como um provedor sobrecarregado
+func (i *Injector) LLM(provider llm.Provider) llm.Provider {
+	return llm.ProviderFunc(func(ctx context.Context, req llm.Request) (*llm.Response, error) {
+		if i.roll(LLMLatency, i.config.LLMLatencyRate) {
+			timer := time.NewTimer(i.config.LLMLatency)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return nil, errs.FromContext("chaos.LLM", ctx.Err())
+			case <-timer.C:
+			}
+		}
+		if i.roll(LLMError, i.config.LLMErrorRate) {
+			return nil, fail(errs.ErrRateLimited, "chaos.LLM", LLMError)
+		}
+		return provider.Complete(ctx, req)
+	})
+}
@@ -0,0 +1,96 @@
+package chaos
+
+import (
+	"context"
+
+	"github.com/suissa/HiveMind/agents/memory"
+)
+
+// Memory envolve o gerenciador de memória: cada operação, exceto Close, pode falhar como um
+// backend indisponível
+func (i *Injector) Memory(manager memory.MemoryManager) memory.MemoryManager {
+	return &faultyMemory{manager: manager, injector: i}
+}
+
+// faultyMemory é o gerenciador de memória com falhas injetadas
+type faultyMemory struct {
+	manager  memory.MemoryManager
+	injector *Injector
+}
+
+// fail sorteia a falha do backend para a operação
+func (m *faultyMemory) fail(op string) error {
+	if m.injector.roll(MemoryFailure, m.injector.config.MemoryFailureRate) {
+		return fail(nil, "chaos.Memory."+op, MemoryFailure)
+	}
+	return nil
+}
+
+// StoreMemory implementa memory.MemoryManager
+func (m *faultyMemory) StoreMemory(ctx context.Context, mem *memory.Memory) error {
+	if err := m.fail("StoreMemory"); err != nil {
+		return err
+	}
+	return m.manager.StoreMemory(ctx, mem)
+}
+
+// GetMemory implementa memory.MemoryManager
+func (m *faultyMemory) GetMemory(ctx context.Context, agentID, memoryID string) (*memory.Memory, error) {
+	if err := m.fail("GetMemory"); err != nil {
+		return nil, err
+	}
+	return m.manager.GetMemory(ctx, agentID, memoryID)
+}
+
+// SearchMemories implementa memory.MemoryManager
+func (m *faultyMemory) SearchMemories(ctx context.Context, agentID string, tags []string) ([]*memory.Memory, error) {
+	if err := m.fail("SearchMemories"); err != nil {
+		return nil, err
+	}
+	return m.manager.SearchMemories(ctx, agentID, tags)
+}
+
+// SearchSimilarMemories implementa memory.MemoryManager
+func (m *faultyMemory) SearchSimilarMemories(ctx context.Context, query string, limit int) ([]*memory.Memory, error) {
+	if err := m.fail("SearchSimilarMemories"); err != nil {
+		return nil, err
+	}
+	return m.manager.SearchSimilarMemories(ctx, query, limit)
+}
+
+// UpdateMemory implementa memory.MemoryManager
+func (m *faultyMemory) UpdateMemory(ctx context.Context, mem *memory.Memory) error {
+	if err := m.fail("UpdateMemory"); err != nil {
+		return err
+	}
+	return m.manager.UpdateMemory(ctx, mem)
+}
+
+// DeleteMemory implementa memory.MemoryManager
+func (m *faultyMemory) DeleteMemory(ctx context.Context, agentID, memoryID string) error {
+	if err := m.fail("DeleteMemory"); err != nil {
+		return err
+	}
+	return m.manager.DeleteMemory(ctx, agentID, memoryID)
+}
+
+// ConsolidateMemories implementa memory.MemoryManager
+func (m *faultyMemory) ConsolidateMemories(ctx context.Context, agentID string) error {
+	if err := m.fail("ConsolidateMemories"); err != nil {
+		return err
+	}
+	return m.manager.ConsolidateMemories(ctx, agentID)
+}
+
+// PruneMemories implementa memory.MemoryManager
+func (m *faultyMemory) PruneMemories(ctx context.Context, agentID string) error {
+	if err := m.fail("PruneMemories"); err != nil {
+		return err
+	}
+	return m.manager.PruneMemories(ctx, agentID)
+}
+
+// Close não sofre falhas, para que o encerramento do teste libere os recursos
+func (m *faultyMemory) Close(ctx context.Context) error {
+	return m.manager.Close(ctx)
+}
@@ -0,0 +1,96 @@
+package agents
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Clone cria uma cópia do agente em execução com novo ID e linhagem (ParentID/Lineage).
+// O clone compartilha o LLM, a memória e o registro de ferramentas (com as mesmas
+// ferramentas liberadas), mas começa sem caches de resultados, histórico de respostas e
+// de treinamento, para que seu desempenho possa ser comparado ao do pai com Divergence.
+func (a *CognitiveAgent) Clone() *CognitiveAgent {
+	clone := &CognitiveAgent{
+		AgentStruct:      a.AgentStruct.Clone(),
+		Model:            a.Model,
+		Temperature:      a.Temperature,
+		MaxTokens:        a.MaxTokens,
+		ContextWindow:    a.ContextWindow,
+		MemoryRecall:     a.MemoryRecall,
+		MemorySimilarity: a.MemorySimilarity,
+		MemoryRanking:    a.MemoryRanking,
+		KnowledgeBase:    make(map[string]interface{}, len(a.KnowledgeBase)),
+		LearningRate:     a.LearningRate,
+		PromptTemplates:  make(map[string]string, len(a.PromptTemplates)),
+		ResponseHistory:  make([]string, 0),
+		PerformanceStats: make(map[string]float64, len(a.PerformanceStats)),
+		MaxRounds:        a.MaxRounds,
+		trainingHistory:  make([]*TrainingMetrics, 0),
+		memoryManager:    a.memoryManager,
+		llm:              a.llm,
+		hooks:            append(hookChain(nil), a.hooks...),
+		tools:            a.tools,
+		limits:           a.limits,
+		anonymizer:       a.anonymizer,
+		scorer:           a.scorer,
+		trainingData:     append([]TrainingExample(nil), a.trainingData...),
+		grader:           a.grader,
+		stopChan:         make(chan struct{}),
+	}
+	for key, value := range a.KnowledgeBase {
+		clone.KnowledgeBase[key] = value
+	}
+	for name, template := range a.PromptTemplates {
+		clone.PromptTemplates[name] = template
+	}
+	for name, value := range a.PerformanceStats {
+		clone.PerformanceStats[name] = value
+	}
+
+	// As permissões por ID do pai valem também para o clone
+	if a.tools != nil {
+		a.tools.GrantAgent(clone.GetID(), a.tools.Allowed(a))
+	}
+	return clone
+}
+
+// Divergence compara um clone ao agente de origem
+type Divergence struct {
+	ParentID string
+	CloneID  string
+	Stats    map[string]float64 // Diferença clone - pai em cada estatística de performance
+	Changes  []string           // Diferenças de configuração (modelo, temperatura, prompt)
+}
+
+// Divergence compara o desempenho e a configuração do agente com os do pai informado
+func (a *CognitiveAgent) Divergence(parent *CognitiveAgent) (*Divergence, error) {
+	if a.ParentID != parent.GetID() {
+		return nil, fmt.Errorf("agente %s não é clone de %s", a.GetID(), parent.GetID())
+	}
+
+	divergence := &Divergence{
+		ParentID: parent.GetID(),
+		CloneID:  a.GetID(),
+		Stats:    make(map[string]float64),
+	}
+	for name, value := range a.PerformanceStats {
+		divergence.Stats[name] = value - parent.PerformanceStats[name]
+	}
+	for name, value := range parent.PerformanceStats {
+		if _, ok := a.PerformanceStats[name]; !ok {
+			divergence.Stats[name] = -value
+		}
+	}
+
+	if a.Model != parent.Model {
+		divergence.Changes = append(divergence.Changes, fmt.Sprintf("model: %s -> %s", parent.Model, a.Model))
+	}
+	if a.Temperature != parent.Temperature {
+		divergence.Changes = append(divergence.Changes, fmt.Sprintf("temperature: %.2f -> %.2f", parent.Temperature, a.Temperature))
+	}
+	if version, parentVersion := a.promptVersion(), parent.promptVersion(); version != parentVersion {
+		divergence.Changes = append(divergence.Changes, fmt.Sprintf("prompt_version: %s -> %s", parentVersion, version))
+	}
+	sort.Strings(divergence.Changes)
+	return divergence, nil
+}
@@ -0,0 +1,156 @@
+// Package codec serializa as mensagens trocadas entre os agentes em JSON, Protobuf ou
+// MsgPack e escolhe o formato pelo tipo de conteúdo (Content-Type e Accept).
+package codec
+
+import (
+	"encoding/json"
+	"fmt"
+	"mime"
+	"strings"
+	"sync"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// Tipos de conteúdo dos codecs embutidos
+const (
+	ContentTypeJSON     = "application/json"
+	ContentTypeProtobuf = "application/x-protobuf"
+	ContentTypeMsgPack  = "application/msgpack"
+)
+
+// Codec serializa valores em um formato identificado pelo tipo de conteúdo
+type Codec interface {
+	ContentType() string
+	Marshal(v interface{}) ([]byte, error)
+	Unmarshal(data []byte, v interface{}) error
+}
+
+// Codecs embutidos
+var (
+	JSON     Codec = jsonCodec{}
+	Protobuf Codec = protobufCodec{}
+	MsgPack  Codec = msgpackCodec{}
+)
+
+// jsonCodec serializa com encoding/json
+type jsonCodec struct{}
+
+func (jsonCodec) ContentType() string { return ContentTypeJSON }
+
+func (jsonCodec) Marshal(v interface{}) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao serializar JSON: %v", err)
+	}
+	return data, nil
+}
+
+func (jsonCodec) Unmarshal(data []byte, v interface{}) error {
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("erro ao desserializar JSON: %v", err)
+	}
+	return nil
+}
+
+// protobufCodec serializa mensagens Protobuf (valores que implementam proto.Message)
+type protobufCodec struct{}
+
+func (protobufCodec) ContentType() string { return ContentTypeProtobuf }
+
+func (protobufCodec) Marshal(v interface{}) ([]byte, error) {
+	msg, ok := v.(proto.Message)
+	if !ok {
+		return nil, fmt.Errorf("valor %T não é uma mensagem Protobuf", v)
+	}
+	data, err := proto.Marshal(msg)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao serializar Protobuf: %v", err)
+	}
+	return data, nil
+}
+
+func (protobufCodec) Unmarshal(data []byte, v interface{}) error {
+	msg, ok := v.(proto.Message)
+	if !ok {
+		return fmt.Errorf("valor %T não é uma mensagem Protobuf", v)
+	}
+	if err := proto.Unmarshal(data, msg); err != nil {
+		return fmt.Errorf("erro ao desserializar Protobuf: %v", err)
+	}
+	return nil
+}
+
+// Registry associa tipos de conteúdo a codecs
+type Registry struct {
+	codecs map[string]Codec
+	order  []string
+	mu     sync.RWMutex
+}
+
+// NewRegistry cria um registro com os codecs informados, na ordem de preferência
+func NewRegistry(codecs ...Codec) *Registry {
+	r := &Registry{codecs: make(map[string]Codec)}
+	for _, c := range codecs {
+		r.Register(c)
+	}
+	return r
+}
+
+// Default é o registro com os codecs embutidos, preferindo JSON
+var Default = NewRegistry(JSON, Protobuf, MsgPack)
+
+// Register adiciona ou substitui o codec do seu tipo de conteúdo
+func (r *Registry) Register(c Codec) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	contentType := normalize(c.ContentType())
+	if _, exists := r.codecs[contentType]; !exists {
+		r.order = append(r.order, contentType)
+	}
+	r.codecs[contentType] = c
+}
+
+// Lookup retorna o codec do tipo de conteúdo, ignorando parâmetros como charset
+func (r *Registry) Lookup(contentType string) (Codec, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	c, ok := r.codecs[normalize(contentType)]
+	return c, ok
+}
+
+// ContentTypes retorna os tipos de conteúdo registrados, na ordem de preferência
+func (r *Registry) ContentTypes() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return append([]string(nil), r.order...)
+}
+
+// Accept monta o valor do header Accept com os tipos registrados
+func (r *Registry) Accept() string {
+	return strings.Join(r.ContentTypes(), ", ")
+}
+
+// Negotiate escolhe o codec para uma resposta a partir do header Accept: o primeiro tipo
+// aceito que esteja registrado ou, se nenhum estiver (ou o header estiver vazio), fallback
+func (r *Registry) Negotiate(accept string, fallback Codec) Codec {
+	for _, item := range strings.Split(accept, ",") {
+		contentType := normalize(item)
+		if contentType == "*/*" {
+			return fallback
+		}
+		if c, ok := r.Lookup(contentType); ok {
+			return c
+		}
+	}
+	return fallback
+}
+
+// normalize remove os parâmetros e normaliza a caixa do tipo de conteúdo
+func normalize(contentType string) string {
+	contentType = strings.TrimSpace(contentType)
+	if mediaType, _, err := mime.ParseMediaType(contentType); err == nil {
+		return mediaType
+	}
+	return strings.ToLower(contentType)
+}
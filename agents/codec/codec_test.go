@@ -0,0 +1,85 @@
+package codec
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+type task struct {
+	ID       string            `json:"id"`
+	Priority int               `json:"priority"`
+	Score    float64           `json:"score"`
+	Done     bool              `json:"done"`
+	Tags     []string          `json:"tags"`
+	Meta     map[string]string `json:"meta"`
+	Payload  []byte            `json:"payload"`
+	Parent   *task             `json:"parent"`
+}
+
+func TestCodecsRoundTrip(t *testing.T) {
+	in := task{
+		ID:       "t-1",
+		Priority: -70000,
+		Score:    0.75,
+		Done:     true,
+		Tags:     []string{"urgente", "marketing"},
+		Meta:     map[string]string{"owner": "ana"},
+		Payload:  []byte{0, 1, 2, 255},
+	}
+	for _, c := range []Codec{JSON, MsgPack} {
+		data, err := c.Marshal(in)
+		if err != nil {
+			t.Fatalf("%s: %v", c.ContentType(), err)
+		}
+		var out task
+		if err := c.Unmarshal(data, &out); err != nil {
+			t.Fatalf("%s: %v", c.ContentType(), err)
+		}
+		if !reflect.DeepEqual(in, out) {
+			t.Fatalf("%s: esperado %+v, obtido %+v", c.ContentType(), in, out)
+		}
+	}
+}
+
+func TestMsgPackEncoding(t *testing.T) {
+	data, err := MsgPack.Marshal(map[string]interface{}{"a": 1, "b": []interface{}{true, nil}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []byte{0x82, 0xa1, 'a', 0x01, 0xa1, 'b', 0x92, 0xc3, 0xc0}
+	if !bytes.Equal(data, want) {
+		t.Fatalf("codificação inesperada: % x", data)
+	}
+	if err := MsgPack.Unmarshal(data[:4], new(interface{})); err == nil {
+		t.Fatal("esperava erro para dados truncados")
+	}
+}
+
+func TestProtobufCodec(t *testing.T) {
+	data, err := Protobuf.Marshal(wrapperspb.String("olá"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := &wrapperspb.StringValue{}
+	if err := Protobuf.Unmarshal(data, out); err != nil || out.GetValue() != "olá" {
+		t.Fatalf("valor inesperado %q: %v", out.GetValue(), err)
+	}
+	if _, err := Protobuf.Marshal(task{}); err == nil {
+		t.Fatal("esperava erro para valor que não é Protobuf")
+	}
+}
+
+func TestNegotiate(t *testing.T) {
+	if c, ok := Default.Lookup("application/json; charset=utf-8"); !ok || c != JSON {
+		t.Fatal("Content-Type com parâmetros não reconhecido")
+	}
+	if c := Default.Negotiate("text/plain, application/msgpack;q=0.9, application/json", JSON); c != MsgPack {
+		t.Fatalf("negociação inesperada: %s", c.ContentType())
+	}
+	if c := Default.Negotiate("text/plain", Protobuf); c != Protobuf {
+		t.Fatalf("esperava o codec padrão, obtido %s", c.ContentType())
+	}
+}
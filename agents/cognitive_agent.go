@@ -4,10 +4,31 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"os"
+	"log"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
-	"HiveMind/memory"
+	"github.com/suissa/HiveMind/agents/brandvoice"
+	"github.com/suissa/HiveMind/agents/cache"
+	"github.com/suissa/HiveMind/agents/contractnet"
+	"github.com/suissa/HiveMind/agents/deadline"
+	"github.com/suissa/HiveMind/agents/errs"
+	"github.com/suissa/HiveMind/agents/importance"
+	"github.com/suissa/HiveMind/agents/llm"
+	"github.com/suissa/HiveMind/agents/memory"
+	"github.com/suissa/HiveMind/agents/metrics"
+	"github.com/suissa/HiveMind/agents/outbox"
+	"github.com/suissa/HiveMind/agents/overrides"
+	"github.com/suissa/HiveMind/agents/pii"
+	"github.com/suissa/HiveMind/agents/presence"
+	"github.com/suissa/HiveMind/agents/prompt"
+	"github.com/suissa/HiveMind/agents/retrieval"
+	"github.com/suissa/HiveMind/agents/simulation"
+	"github.com/suissa/HiveMind/agents/tenant"
+	"github.com/suissa/HiveMind/agents/timing"
+	"github.com/suissa/HiveMind/agents/validation"
 )
 
 // CognitiveAgent representa um agente cognitivo que pode executar tarefas específicas
@@ -17,6 +38,9 @@ type CognitiveAgent struct {
 	Temperature      float64                // Temperatura para geração de respostas
 	MaxTokens        int                    // Número máximo de tokens por resposta
 	ContextWindow    int                    // Tamanho da janela de contexto
+	MemoryRecall     int                    // Memórias similares incluídas no prompt das tarefas (0 desativa)
+	MemorySimilarity float64                // Similaridade mínima das memórias incluídas no prompt (0 não filtra)
+	MemoryRanking    *retrieval.Weights     // Ranking das memórias do prompt por similaridade, recência e importância (nil ordena por similaridade)
 	KnowledgeBase    map[string]interface{} // Base de conhecimento do agente
 	LearningRate     float64                // Taxa de aprendizado para ajustes
 	PromptTemplates  map[string]string      // Templates de prompts
@@ -24,10 +48,26 @@ type CognitiveAgent struct {
 	PerformanceStats map[string]float64     // Estatísticas de performance
 	MaxRounds        int                    // Número máximo de rodadas de treinamento
 	trainingHistory  []*TrainingMetrics     // Histórico de treinamento
+	persona          string                 // Persona que gerou a backstory (SetPersona)
 
 	// Campos específicos para execução de tarefas
 	taskManager   *TaskManager
 	memoryManager memory.MemoryManager
+	llm           llm.Provider
+	hooks         hookChain
+	resultCache   cache.Store
+	semanticCache cache.SemanticStore
+	tools         *ToolRegistry
+	limits        overrides.Limits
+	anonymizer    *pii.Anonymizer
+	scorer        importance.Scorer
+	trainingData  []TrainingExample
+	grader        Grader
+	validation    *validation.Config
+	brandVoice    *brandvoice.Reviewer
+	startOnce     sync.Once
+	startErr      error
+	running       atomic.Int32 // Tarefas em execução, informadas nos heartbeats
 	stopChan      chan struct{}
 	healthTicker  *time.Ticker
 	metricsTicker *time.Ticker
@@ -68,21 +108,140 @@ func NewCognitiveAgent(id, name, description string, maxRounds int, model string
 	}
 }
 
-// Train treina o agente com base na configuração fornecida
+// Train avalia o agente em rodadas sobre os exemplos rotulados (config.Dataset, SetTrainingData
+// e o feedback armazenado com RecordFeedback), ajustando a temperatura e os exemplos few-shot entre as
+// rodadas até atingir config.MinAccuracy. As métricas finais são medidas nos exemplos de
+// validação (config.ValidationRatio) e armazenadas na memória de longo prazo.
 func (a *CognitiveAgent) Train(ctx context.Context, config TrainingConfig) (*TrainingMetrics, error) {
-	startTime := time.Now()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if config.TrainingTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, config.TrainingTimeout)
+		defer cancel()
+	}
+
+	data, err := a.trainingDataset(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+	if len(data.Examples) == 0 {
+		return nil, errs.New(errs.ErrValidation, "agent.Train", "agente %s sem exemplos de treinamento", a.GetID())
+	}
+	trainSet, validationSet := data.Split(config.ValidationRatio, config.Seed)
+	train, validation := trainSet.Examples, validationSet.Examples
+
+	if config.LearningRate > 0 {
+		a.LearningRate = config.LearningRate
+	}
+	rounds := config.MaxRounds
+	if rounds <= 0 || (a.MaxRounds > 0 && a.MaxRounds < rounds) {
+		rounds = a.MaxRounds
+	}
+	if rounds <= 0 {
+		rounds = 1
+	}
+
 	metrics := &TrainingMetrics{
-		StartTime:      startTime,
-		EndTime:        startTime.Add(30 * time.Second),
-		Accuracy:       0.85,
-		Loss:           0.15,
-		RoundsExecuted: 100,
+		StartTime:      time.Now(),
 		Errors:         make([]error, 0),
+		DatasetVersion: data.String(),
+	}
+	for round := 1; round <= rounds; round++ {
+		if err := ctx.Err(); err != nil {
+			metrics.Errors = append(metrics.Errors, errs.FromContext("agent.Train", err))
+			break
+		}
+
+		result := a.evaluate(ctx, train)
+		metrics.RoundsExecuted = round
+		metrics.Accuracy, metrics.Loss = result.accuracy, result.loss
+		metrics.Errors = append(metrics.Errors, result.errors...)
+
+		a.PerformanceStats["accuracy"] = result.accuracy
+		a.PerformanceStats["success_rate"] = 1 - float64(len(result.failed))/float64(len(train))
+		if result.accuracy >= config.MinAccuracy {
+			break
+		}
+
+		// Ajusta os parâmetros e inclui os exemplos errados como few-shot na próxima rodada
+		a.updateFewShot(result.failed)
+		a.adjustParameters()
+	}
+
+	if len(validation) > 0 && ctx.Err() == nil {
+		result := a.evaluate(ctx, validation)
+		metrics.Accuracy, metrics.Loss = result.accuracy, result.loss
+		metrics.Errors = append(metrics.Errors, result.errors...)
+	}
+	metrics.EndTime = time.Now()
+	a.updatePerformanceStats(metrics)
+
+	// Adiciona as métricas ao histórico de treinamento
+	a.trainingHistory = append(a.trainingHistory, metrics)
+
+	if err := a.storeTrainingMetrics(ctx, metrics, len(train), len(validation)); err != nil {
+		return metrics, err
+	}
+	return metrics, nil
+}
+
+// evaluation é o resultado da avaliação do agente sobre um conjunto de exemplos
+type evaluation struct {
+	accuracy float64
+	loss     float64
+	failed   []TrainingExample
+	errors   []error
+}
+
+// evaluate executa o agente em cada exemplo e avalia as respostas com o grader.
+// A acurácia é a nota média; o loss é o erro quadrático médio (1 - nota)².
+func (a *CognitiveAgent) evaluate(ctx context.Context, examples []TrainingExample) evaluation {
+	grader := a.grader
+	if grader == nil {
+		grader = DefaultGrader
+	}
+
+	var result evaluation
+	var total, loss float64
+	for _, example := range examples {
+		var score float64
+		output, err := a.Complete(ctx, a.withFewShot(example.Input))
+		if err == nil {
+			score, err = grader(ctx, example, output)
+		}
+		if err != nil {
+			result.errors = append(result.errors, fmt.Errorf("exemplo %s: %w", example.ID, err))
+			score = 0
+		}
+
+		total += score
+		loss += (1 - score) * (1 - score)
+		if score < 0.5 {
+			result.failed = append(result.failed, example)
+		}
+	}
+
+	if len(examples) > 0 {
+		result.accuracy = total / float64(len(examples))
+		result.loss = loss / float64(len(examples))
+	}
+	return result
+}
+
+// storeTrainingMetrics armazena as métricas e os parâmetros resultantes na memória de longo prazo
+func (a *CognitiveAgent) storeTrainingMetrics(ctx context.Context, metrics *TrainingMetrics, trainSize, validationSize int) error {
+	if a.memoryManager == nil {
+		return nil
 	}
 
-	// Armazena métricas de treinamento na memória
 	metricsData := map[string]interface{}{
 		"metrics": metrics,
+		"dataset": map[string]interface{}{
+			"train":      trainSize,
+			"validation": validationSize,
+		},
 		"parameters": map[string]interface{}{
 			"temperature":   a.Temperature,
 			"learning_rate": a.LearningRate,
@@ -91,7 +250,7 @@ func (a *CognitiveAgent) Train(ctx context.Context, config TrainingConfig) (*Tra
 
 	metricsJSON, err := json.Marshal(metricsData)
 	if err != nil {
-		return metrics, fmt.Errorf("erro ao converter métricas para JSON: %v", err)
+		return fmt.Errorf("erro ao converter métricas para JSON: %v", err)
 	}
 
 	memory := &memory.Memory{
@@ -100,17 +259,14 @@ func (a *CognitiveAgent) Train(ctx context.Context, config TrainingConfig) (*Tra
 		Type:       memory.LongTerm,
 		Content:    string(metricsJSON),
 		Importance: metrics.Accuracy,
+		Timestamp:  time.Now(),
 		Tags:       []string{"training", "metrics", "parameters"},
 	}
 
-	if err := a.memoryManager.StoreMemory(ctx, memory); err != nil {
-		return metrics, fmt.Errorf("erro ao armazenar métricas de treinamento: %v", err)
+	if err := a.memoryManager.StoreMemory(a.scope(ctx), memory); err != nil {
+		return fmt.Errorf("erro ao armazenar métricas de treinamento: %w", err)
 	}
-
-	// Adiciona as métricas ao histórico de treinamento
-	a.trainingHistory = append(a.trainingHistory, metrics)
-
-	return metrics, nil
+	return nil
 }
 
 // GetTrainingHistory retorna o histórico de treinamento do agente
@@ -118,13 +274,95 @@ func (a *CognitiveAgent) GetTrainingHistory() []*TrainingMetrics {
 	return a.trainingHistory
 }
 
+// scope associa o contexto ao tenant do agente, preservando um tenant já definido
+func (a *CognitiveAgent) scope(ctx context.Context) context.Context {
+	if _, ok := tenant.Lookup(ctx); ok || a.Tenant == "" {
+		return ctx
+	}
+	return tenant.WithTenant(ctx, a.Tenant)
+}
+
 // Remember busca memórias relacionadas a um conjunto de tags
 func (a *CognitiveAgent) Remember(ctx context.Context, tags []string) ([]*memory.Memory, error) {
-	return a.memoryManager.SearchMemories(ctx, a.GetID(), tags)
+	return a.memoryManager.SearchMemories(a.scope(ctx), a.GetID(), tags)
 }
 
-// Memorize armazena uma nova memória
+// AutoImportance pede a Memorize que estime a importância com o avaliador do agente
+const AutoImportance = -1.0
+
+// relatedMemories é a quantidade de memórias similares enviadas ao avaliador de importância
+const relatedMemories = 5
+
+// Memorize armazena uma nova memória. As pessoas citadas em content["subject_id"] ou
+// content["subjects"] são registradas em Memory.Subjects para a exclusão por titular.
+// Com importance igual a AutoImportance a importância é estimada pelo avaliador do agente.
 func (a *CognitiveAgent) Memorize(ctx context.Context, content map[string]interface{}, importance float64, tags []string, isLongTerm bool) error {
+	memory, err := a.newMemory(ctx, content, importance, tags, isLongTerm)
+	if err != nil {
+		return err
+	}
+	return a.memoryManager.StoreMemory(a.scope(ctx), memory)
+}
+
+// MemorizeValue armazena o valor (uma struct com tags json) como memória do esquema
+// informado, marcada com a tag memory.SchemaTag(schema). Qualquer agente com acesso à mesma
+// memória o lê de volta com RecallValue.
+func (a *CognitiveAgent) MemorizeValue(ctx context.Context, schema string, value interface{}, importance float64, tags []string, isLongTerm bool) error {
+	content, err := memory.Content(schema, value)
+	if err != nil {
+		return err
+	}
+	return a.Memorize(ctx, content, importance, append(append([]string(nil), tags...), memory.SchemaTag(schema)), isLongTerm)
+}
+
+// RecallValue decodifica em v a memória mais recente do esquema gravada pelo agente agentID
+// (vazio para o próprio agente) e informa se alguma foi encontrada
+func (a *CognitiveAgent) RecallValue(ctx context.Context, agentID, schema string, v interface{}) (bool, error) {
+	if agentID == "" {
+		agentID = a.GetID()
+	}
+	memories, err := a.memoryManager.SearchMemories(a.scope(ctx), agentID, []string{memory.SchemaTag(schema)})
+	if err != nil {
+		return false, err
+	}
+	// Os valores grandes ficam no armazenamento de objetos, e a memória guarda só a prévia
+	if resolver, ok := a.memoryManager.(interface {
+		FullContent(context.Context, *memory.Memory) (string, error)
+	}); ok {
+		for i, m := range memories {
+			content, err := resolver.FullContent(ctx, m)
+			if err != nil {
+				return false, err
+			}
+			resolved := *m
+			resolved.Content = content
+			memories[i] = &resolved
+		}
+	}
+	latest := memory.Latest(memories, schema)
+	if latest == nil {
+		return false, nil
+	}
+	return true, memory.Decode(latest, schema, v)
+}
+
+// MemorizeAndPublish armazena a memória como Memorize e grava os eventos no outbox na mesma
+// transação, de modo que não há memória sem evento nem evento sem memória. Os eventos são
+// publicados pelo relay do outbox (hivemind.WithOutbox) depois do commit.
+func (a *CognitiveAgent) MemorizeAndPublish(ctx context.Context, content map[string]interface{}, importance float64, tags []string, isLongTerm bool, events ...*outbox.Event) error {
+	store, ok := a.memoryManager.(memory.EventStore)
+	if !ok {
+		return errs.New(errs.ErrValidation, "agents.MemorizeAndPublish", "o gerenciador de memória do agente %s não tem outbox", a.GetID())
+	}
+	memory, err := a.newMemory(ctx, content, importance, tags, isLongTerm)
+	if err != nil {
+		return err
+	}
+	return store.StoreMemoryWithEvents(a.scope(ctx), memory, events...)
+}
+
+// newMemory monta a memória gravada por Memorize e MemorizeAndPublish
+func (a *CognitiveAgent) newMemory(ctx context.Context, content map[string]interface{}, importance float64, tags []string, isLongTerm bool) (*memory.Memory, error) {
 	memType := memory.ShortTerm
 	var ttl time.Duration
 
@@ -136,30 +374,122 @@ func (a *CognitiveAgent) Memorize(ctx context.Context, content map[string]interf
 
 	contentJSON, err := json.Marshal(content)
 	if err != nil {
-		return fmt.Errorf("erro ao converter conteúdo para JSON: %v", err)
+		return nil, fmt.Errorf("erro ao converter conteúdo para JSON: %v", err)
+	}
+	if importance == AutoImportance {
+		importance = a.scoreImportance(ctx, string(contentJSON))
 	}
 
-	memory := &memory.Memory{
-		ID:         fmt.Sprintf("memory_%s_%d", a.GetID(), time.Now().Unix()),
+	return &memory.Memory{
+		// Em nanossegundos: duas memórias gravadas no mesmo segundo não podem ter o mesmo ID,
+		// senão a segunda substitui a primeira
+		ID:         fmt.Sprintf("memory_%s_%d", a.GetID(), time.Now().UnixNano()),
 		AgentID:    a.GetID(),
 		Type:       memType,
 		Content:    string(contentJSON),
 		Importance: importance,
 		TTL:        ttl,
 		Tags:       tags,
+		Subjects:   subjectsOf(content),
+	}, nil
+}
+
+// Note grava uma anotação intermediária no scratchpad do workflow em andamento. As anotações
+// não entram na memória do agente: são descartadas ao final do workflow, salvo as promovidas
+// com PromoteNote.
+func (a *CognitiveAgent) Note(ctx context.Context, content map[string]interface{}, tags []string) (*memory.Memory, error) {
+	scratchpad, ok := memory.ScratchpadFromContext(ctx)
+	if !ok {
+		return nil, errs.New(errs.ErrValidation, "agents.Note", "nenhum workflow em andamento para a anotação do agente %s", a.GetID())
 	}
 
-	return a.memoryManager.StoreMemory(ctx, memory)
+	contentJSON, err := json.Marshal(content)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao converter conteúdo para JSON: %v", err)
+	}
+	note := &memory.Memory{
+		ID:       fmt.Sprintf("note_%s_%d", a.GetID(), time.Now().UnixNano()),
+		AgentID:  a.GetID(),
+		Content:  string(contentJSON),
+		Tags:     tags,
+		Subjects: subjectsOf(content),
+	}
+	if err := scratchpad.Write(a.scope(ctx), note); err != nil {
+		return nil, err
+	}
+	return note, nil
+}
+
+// PromoteNote move uma anotação do scratchpad do workflow para a memória do agente. Com
+// AutoImportance a importância é estimada como em Memorize.
+func (a *CognitiveAgent) PromoteNote(ctx context.Context, noteID string, importance float64) (*memory.Memory, error) {
+	scratchpad, ok := memory.ScratchpadFromContext(ctx)
+	if !ok {
+		return nil, errs.New(errs.ErrValidation, "agents.PromoteNote", "nenhum workflow em andamento para promover a anotação %s", noteID)
+	}
+	ctx = a.scope(ctx)
+	if importance == AutoImportance {
+		note, err := scratchpad.Get(ctx, noteID)
+		if err != nil {
+			return nil, err
+		}
+		importance = a.scoreImportance(ctx, note.Content)
+	}
+	return scratchpad.Promote(ctx, noteID, importance)
+}
+
+// scoreImportance estima a importância do conteúdo frente às memórias similares já gravadas.
+// Sem avaliador, ou se a avaliação falhar, a memória recebe a importância neutra.
+func (a *CognitiveAgent) scoreImportance(ctx context.Context, content string) float64 {
+	if a.scorer == nil || simulation.IsDryRun(ctx) {
+		return importance.Neutral
+	}
+	ctx = a.scope(ctx)
+
+	var related []string
+	similar, err := a.memoryManager.SearchSimilarMemories(ctx, content, relatedMemories)
+	if err != nil {
+		log.Printf("⚠️ Agente %s: erro ao buscar memórias para a avaliação de importância: %v", a.GetID(), err)
+	}
+	for _, m := range similar {
+		related = append(related, m.Content)
+	}
+
+	score, err := a.scorer.Score(ctx, content, related)
+	if err != nil {
+		log.Printf("⚠️ Agente %s: erro ao avaliar importância, usando %.1f: %v", a.GetID(), importance.Neutral, err)
+		return importance.Neutral
+	}
+	return score.Importance
+}
+
+// subjectsOf extrai os titulares de dados referenciados pelo conteúdo de uma memória
+func subjectsOf(content map[string]interface{}) []string {
+	var subjects []string
+	if id, ok := content["subject_id"].(string); ok && id != "" {
+		subjects = append(subjects, id)
+	}
+	switch list := content["subjects"].(type) {
+	case []string:
+		subjects = append(subjects, list...)
+	case []interface{}:
+		for _, item := range list {
+			if id, ok := item.(string); ok && id != "" {
+				subjects = append(subjects, id)
+			}
+		}
+	}
+	return subjects
 }
 
 // ConsolidateMemories move memórias importantes de curto prazo para longo prazo
 func (a *CognitiveAgent) ConsolidateMemories(ctx context.Context) error {
-	return a.memoryManager.ConsolidateMemories(ctx, a.GetID())
+	return a.memoryManager.ConsolidateMemories(a.scope(ctx), a.GetID())
 }
 
 // ForgetOldMemories remove memórias antigas ou irrelevantes
 func (a *CognitiveAgent) ForgetOldMemories(ctx context.Context) error {
-	return a.memoryManager.PruneMemories(ctx, a.GetID())
+	return a.memoryManager.PruneMemories(a.scope(ctx), a.GetID())
 }
 
 // adjustParameters ajusta os parâmetros do agente baseado no histórico
@@ -192,16 +522,14 @@ func (a *CognitiveAgent) updatePerformanceStats(metrics *TrainingMetrics) {
 	responseTime := metrics.EndTime.Sub(metrics.StartTime).Seconds()
 	a.PerformanceStats["response_time"] = (a.PerformanceStats["response_time"]*0.9 + responseTime*0.1)
 
-	// Atualiza taxa de sucesso
-	if len(metrics.Errors) == 0 {
-		a.PerformanceStats["success_rate"] = (a.PerformanceStats["success_rate"]*0.9 + 1.0*0.1)
-	} else {
-		a.PerformanceStats["success_rate"] = (a.PerformanceStats["success_rate"] * 0.9)
-	}
+	// A acurácia passa a refletir a última avaliação
+	a.PerformanceStats["accuracy"] = metrics.Accuracy
 
 	// Atualiza score de aprendizado
-	learningProgress := float64(metrics.RoundsExecuted) / float64(a.MaxRounds)
-	a.PerformanceStats["learning_score"] = learningProgress
+	if a.MaxRounds > 0 {
+		learningProgress := float64(metrics.RoundsExecuted) / float64(a.MaxRounds)
+		a.PerformanceStats["learning_score"] = learningProgress
+	}
 }
 
 // Validate implementa validação específica para o agente cognitivo
@@ -236,6 +564,17 @@ func (a *CognitiveAgent) GetPerformanceStats() map[string]float64 {
 	return stats
 }
 
+// RecordScores registra as notas médias de uma avaliação (eval) nas estatísticas de
+// performance, com o prefixo "eval_"; a nota geral passa a ser a acurácia do agente
+func (a *CognitiveAgent) RecordScores(scores map[string]float64) {
+	for name, value := range scores {
+		a.PerformanceStats["eval_"+name] = value
+	}
+	if overall, ok := scores["overall"]; ok {
+		a.PerformanceStats["accuracy"] = overall
+	}
+}
+
 // AddPromptTemplate adiciona um template de prompt
 func (a *CognitiveAgent) AddPromptTemplate(name, template string) {
 	a.PromptTemplates[name] = template
@@ -258,9 +597,136 @@ func (a *CognitiveAgent) GetFromKnowledgeBase(key string) (interface{}, bool) {
 	return value, ok
 }
 
+// SetLLM define o provedor de LLM usado pelo agente
+func (a *CognitiveAgent) SetLLM(provider llm.Provider) {
+	a.llm = provider
+}
+
+// LLM retorna o provedor de LLM do agente (nil se não configurado)
+func (a *CognitiveAgent) LLM() llm.Provider {
+	return a.llm
+}
+
+// Complete envia um prompt ao LLM do agente. No modo dry-run a resposta vem da sessão de simulação.
+func (a *CognitiveAgent) Complete(ctx context.Context, text string) (string, error) {
+	return a.CompletePrompt(ctx, prompt.Prompt{System: a.Backstory, Input: text})
+}
+
+// CompletePrompt envia um prompt com memórias e histórico ao LLM do agente. Se o prompt
+// exceder a janela de contexto (ContextWindow menos os MaxTokens reservados à resposta),
+// as memórias menos relevantes são descartadas e os turnos antigos resumidos pelo LLM.
+func (a *CognitiveAgent) CompletePrompt(ctx context.Context, p prompt.Prompt) (string, error) {
+	provider := simulation.Provider(ctx, a.llm)
+	if provider == nil {
+		return "", fmt.Errorf("agente %s sem provedor de LLM configurado", a.GetID())
+	}
+	if a.anonymizer != nil {
+		provider = a.anonymizer.Provider(provider)
+	}
+
+	// Os overrides da tarefa (Task.Overrides) substituem os parâmetros do agente
+	req := overrides.FromContext(ctx).Request(llm.Request{
+		Model:       a.Model,
+		Temperature: a.Temperature,
+		MaxTokens:   a.MaxTokens,
+	})
+
+	compressor := prompt.Compressor{KeepTurns: 2, Summarize: a.summarizer(provider, req.Model)}
+	p, err := compressor.Fit(ctx, p, a.promptBudget(req.MaxTokens))
+	if err != nil {
+		return "", fmt.Errorf("erro ao montar o prompt do agente %s: %w", a.GetID(), err)
+	}
+
+	req.System = p.System
+	req.Prompt = p.Render()
+	start := time.Now()
+	resp, err := provider.Complete(a.scope(ctx), req)
+	timing.Record(ctx, a.GetID(), timing.LLM, req.Model, start)
+	if err != nil {
+		return "", fmt.Errorf("erro na chamada ao LLM do agente %s: %w", a.GetID(), err)
+	}
+
+	a.ResponseHistory = append(a.ResponseHistory, resp.Text)
+	return resp.Text, nil
+}
+
+// promptBudget retorna os tokens disponíveis para o prompt na janela de contexto,
+// reservando maxTokens para a resposta
+func (a *CognitiveAgent) promptBudget(maxTokens int) int {
+	if maxTokens > 0 && maxTokens < a.ContextWindow {
+		return a.ContextWindow - maxTokens
+	}
+	return a.ContextWindow
+}
+
+// summarizer resume o histórico antigo com o próprio provedor do agente
+func (a *CognitiveAgent) summarizer(provider llm.Provider, model string) prompt.Summarizer {
+	return func(ctx context.Context, text string, maxTokens int) (string, error) {
+		start := time.Now()
+		resp, err := provider.Complete(a.scope(ctx), llm.Request{
+			Model:     model,
+			System:    "Resuma a conversa a seguir preservando fatos, decisões e pendências.",
+			Prompt:    text,
+			MaxTokens: maxTokens,
+		})
+		timing.Record(ctx, a.GetID(), timing.LLM, model, start)
+		if err != nil {
+			return "", err
+		}
+		return resp.Text, nil
+	}
+}
+
+// recall busca as memórias similares à tarefa para incluí-las no prompt, retornando
+// também suas referências para o reforço posterior (Reinforce)
+func (a *CognitiveAgent) recall(ctx context.Context, query string) ([]prompt.Memory, []MemoryRef) {
+	if a.MemoryRecall <= 0 || a.memoryManager == nil || simulation.IsDryRun(ctx) {
+		return nil, nil
+	}
+
+	limit := a.MemoryRecall
+	if a.MemoryRanking != nil {
+		limit = a.MemoryRanking.CandidateLimit(limit)
+	}
+	var memories []*memory.Memory
+	var err error
+	if searcher, ok := a.memoryManager.(memory.SimilaritySearcher); ok && a.MemorySimilarity > 0 {
+		memories, err = searcher.SearchSimilar(a.scope(ctx), query, memory.SimilarityOptions{
+			Limit:         limit,
+			MinSimilarity: a.MemorySimilarity,
+		})
+	} else {
+		memories, err = a.memoryManager.SearchSimilarMemories(a.scope(ctx), query, limit)
+	}
+	if err != nil {
+		log.Printf("⚠️ Agente %s: erro ao buscar memórias para o prompt: %v", a.GetID(), err)
+		return nil, nil
+	}
+
+	recalled := make([]prompt.Memory, 0, len(memories))
+	refs := make([]MemoryRef, 0, len(memories))
+	if a.MemoryRanking != nil {
+		ranked := retrieval.Rank(memories, *a.MemoryRanking, time.Now())
+		if len(ranked) > a.MemoryRecall {
+			ranked = ranked[:a.MemoryRecall]
+		}
+		for _, r := range ranked {
+			recalled = append(recalled, prompt.Memory{Content: r.Memory.Content, Relevance: r.Score})
+			refs = append(refs, MemoryRef{AgentID: r.Memory.AgentID, ID: r.Memory.ID})
+		}
+		return recalled, refs
+	}
+	for _, m := range memories {
+		recalled = append(recalled, prompt.Memory{Content: m.Content, Relevance: m.Importance})
+		refs = append(refs, MemoryRef{AgentID: m.AgentID, ID: m.ID})
+	}
+	return recalled, refs
+}
+
 // SetBackstory define a história/contexto do agente
 func (a *CognitiveAgent) SetBackstory(backstory string) {
 	a.Backstory = backstory
+	a.persona = ""
 }
 
 // GetDescription retorna a descrição do agente
@@ -268,118 +734,322 @@ func (a *CognitiveAgent) GetDescription() string {
 	return fmt.Sprintf("Agente cognitivo %s (%s) - %s", a.GetName(), a.GetRole(), a.Goal)
 }
 
-// Execute executa uma tarefa
-func (a *CognitiveAgent) Execute(ctx context.Context, task Task) error {
-	// TODO: Implementar execução de tarefas
-	return nil
+// SetResultCache ativa o cache de resultados: tarefas com o mesmo modelo, prompt e
+// entradas reutilizam a resposta anterior em vez de chamar o LLM
+func (a *CognitiveAgent) SetResultCache(store cache.Store) {
+	a.resultCache = store
 }
 
-// Stop interrompe a execução do agente
-func (a *CognitiveAgent) Stop() error {
-	// TODO: Implementar parada do agente
+// ResultCache retorna o cache de resultados do agente (nil se desativado)
+func (a *CognitiveAgent) ResultCache() cache.Store {
+	return a.resultCache
+}
+
+// SetSemanticCache ativa o cache semântico: prompts similares a um já respondido pelo
+// agente reutilizam a resposta. Com nil o cache semântico é desativado.
+func (a *CognitiveAgent) SetSemanticCache(store cache.SemanticStore) {
+	a.semanticCache = store
+}
+
+// SemanticCache retorna o cache semântico do agente (nil se desativado)
+func (a *CognitiveAgent) SemanticCache() cache.SemanticStore {
+	return a.semanticCache
+}
+
+// SetOverrideLimits define os limites aplicados aos overrides das tarefas (Task.Overrides)
+func (a *CognitiveAgent) SetOverrideLimits(limits overrides.Limits) {
+	a.limits = limits
+}
+
+// SetAnonymizer pseudonimiza os dados pessoais dos prompts antes das chamadas ao LLM e
+// reidentifica as respostas quando permitido. Com nil a pseudonimização é desativada.
+func (a *CognitiveAgent) SetAnonymizer(anonymizer *pii.Anonymizer) {
+	a.anonymizer = anonymizer
+}
+
+// Anonymizer retorna o pseudonimizador do agente (nil se desativado)
+func (a *CognitiveAgent) Anonymizer() *pii.Anonymizer {
+	return a.anonymizer
+}
+
+// SetImportanceScorer define o avaliador usado por Memorize com AutoImportance. Com nil
+// essas memórias recebem a importância neutra.
+func (a *CognitiveAgent) SetImportanceScorer(scorer importance.Scorer) {
+	a.scorer = scorer
+}
+
+// SetMemoryRanking define os pesos do ranking das memórias incluídas no prompt; nil volta a
+// ordená-las apenas por similaridade
+func (a *CognitiveAgent) SetMemoryRanking(weights *retrieval.Weights) error {
+	if weights != nil {
+		if err := weights.Validate(); err != nil {
+			return err
+		}
+	}
+	a.MemoryRanking = weights
 	return nil
 }
 
-// SaveState salva o estado atual do agente em um arquivo
-func (a *CognitiveAgent) SaveState(path string) error {
-	state := map[string]interface{}{
-		"id":                a.GetID(),
-		"name":              a.GetName(),
-		"role":              a.GetRole(),
-		"goal":              a.Goal,
-		"model":             a.Model,
-		"temperature":       a.Temperature,
-		"max_tokens":        a.MaxTokens,
-		"context_window":    a.ContextWindow,
-		"learning_rate":     a.LearningRate,
-		"knowledge_base":    a.KnowledgeBase,
-		"prompt_templates":  a.PromptTemplates,
-		"performance_stats": a.PerformanceStats,
-		"training_history":  a.trainingHistory,
-	}
-
-	data, err := json.MarshalIndent(state, "", "  ")
+// ImportanceScorer retorna o avaliador de importância do agente (nil se desativado)
+func (a *CognitiveAgent) ImportanceScorer() importance.Scorer {
+	return a.scorer
+}
+
+// SetToolRegistry associa o registro de ferramentas do agente, usado nos snapshots para
+// salvar e restaurar as ferramentas liberadas
+func (a *CognitiveAgent) SetToolRegistry(registry *ToolRegistry) {
+	a.tools = registry
+}
+
+// AddHooks anexa hooks ao ciclo de vida do agente, executados na ordem de registro
+func (a *CognitiveAgent) AddHooks(hooks ...Hooks) {
+	a.hooks = append(a.hooks, hooks...)
+}
+
+// Start executa os hooks OnStart uma única vez. É chamado automaticamente na primeira tarefa.
+func (a *CognitiveAgent) Start(ctx context.Context) error {
+	a.startOnce.Do(func() {
+		a.startErr = a.hooks.start(ctx, a)
+	})
+	return a.startErr
+}
+
+// Run executa a tarefa com o LLM do agente, passando pelos hooks do ciclo de vida,
+// e retorna o resultado. O estado, as datas e a saída ("text") são registrados na tarefa.
+// Uma tarefa que excede o prazo termina com TaskStatusTimeout, um erro ErrTimeout e o
+// resultado parcial, se houver.
+func (a *CognitiveAgent) Run(ctx context.Context, task *Task) (string, error) {
+	if err := a.Start(ctx); err != nil {
+		return "", fmt.Errorf("erro ao iniciar agente %s: %w", a.GetID(), err)
+	}
+
+	a.running.Add(1)
+	defer a.running.Add(-1)
+
+	started := time.Now()
+	task.StartedAt = &started
+	task.Status = TaskStatusRunning
+	task.AssignedTo = a.GetID()
+
+	// A espera desde o despacho e a execução entram no breakdown do workflow (timing.Recorder).
+	// O despacho é consumido aqui para não ser medido de novo em tarefas aninhadas.
+	ctx = timing.WithAgent(ctx, a.GetID())
+	if queued, ok := timing.QueuedAt(ctx); ok {
+		timing.Record(ctx, a.GetID(), timing.QueueWait, task.ID, queued)
+		ctx = timing.WithQueued(ctx, time.Time{})
+	}
+	defer timing.Record(ctx, a.GetID(), timing.Task, task.ID, started)
+
+	// As memórias gravadas durante a tarefa registram sua proveniência
+	ctx = memory.WithProvenance(ctx, memory.Provenance{TaskID: task.ID})
+
+	// O prazo da tarefa (Deadline ou Timeout, o que vencer primeiro) interrompe a execução
+	var timeout time.Time
+	if task.Timeout > 0 {
+		timeout = started.Add(task.Timeout)
+	}
+	at := deadline.Earliest(task.Deadline, timeout)
+	taskCtx, cancel := deadline.Context(ctx, at)
+	defer cancel()
+	output, err := a.runWithOverrides(taskCtx, task)
+
+	finished := time.Now()
+	task.FinishedAt = &finished
+	if err != nil && deadline.Expired(ctx, taskCtx) {
+		// A tarefa termina com o resultado parcial e o status de timeout, sem prender o workflow
+		err = deadline.Error("agents.Run", task.ID, at, err)
+		task.Status = TaskStatusTimeout
+		task.Error = err
+		a.hooks.error(ctx, a, task, err)
+		partial, _ := task.Partial()
+		return partial, err
+	}
 	if err != nil {
-		return fmt.Errorf("erro ao converter estado para JSON: %v", err)
+		task.Status = TaskStatusFailed
+		task.Error = err
+		a.hooks.error(ctx, a, task, err)
+		return "", err
 	}
 
-	if err := os.WriteFile(path, data, 0644); err != nil {
-		return fmt.Errorf("erro ao salvar estado em arquivo: %v", err)
+	task.Status = TaskStatusComplete
+	if task.Output == nil {
+		task.Output = make(map[string]interface{})
 	}
+	delete(task.Output, partialKey)
+	task.Output["text"] = output
+	return output, nil
+}
 
-	return nil
+// Load retorna a carga atual do agente, publicada nos heartbeats de presença
+func (a *CognitiveAgent) Load() presence.Load {
+	return presence.Load{Running: int(a.running.Load())}
 }
 
-// LoadState carrega o estado do agente de um arquivo
-func (a *CognitiveAgent) LoadState(path string) error {
-	data, err := os.ReadFile(path)
-	if err != nil {
-		return fmt.Errorf("erro ao ler arquivo de estado: %v", err)
+// Heartbeat retorna o heartbeat de presença do agente, com a carga atual
+func (a *CognitiveAgent) Heartbeat() presence.Heartbeat {
+	return presence.Heartbeat{AgentID: a.GetID(), Name: a.GetName(), Role: a.GetRole(), Load: a.Load()}
+}
+
+// Bid é o lance do agente nas licitações de tarefas (contract net). O agente só concorre às
+// tarefas do seu papel, com a carga atual como custo e a sua confiança.
+func (a *CognitiveAgent) Bid(ctx context.Context, announcement contractnet.Announcement) (contractnet.Bid, bool) {
+	if announcement.Role != "" && !strings.EqualFold(announcement.Role, a.GetRole()) {
+		return contractnet.Bid{}, false
 	}
+	return contractnet.Bid{Cost: float64(a.running.Load()), Confidence: a.confidence()}, true
+}
 
-	var state map[string]interface{}
-	if err := json.Unmarshal(data, &state); err != nil {
-		return fmt.Errorf("erro ao decodificar estado do JSON: %v", err)
+// confidence é a confiança do agente nas próprias respostas: a taxa de sucesso do
+// treinamento, ou 0,5 enquanto não houver treinamento
+func (a *CognitiveAgent) confidence() float64 {
+	if rate, ok := a.PerformanceStats["success_rate"]; ok {
+		return rate
 	}
+	return 0.5
+}
 
-	// Atualiza os campos do agente
-	if id, ok := state["id"].(string); ok {
-		a.AgentStruct.ID = id
+// runWithOverrides valida os overrides da tarefa contra os limites do agente e os
+// associa ao contexto da execução
+func (a *CognitiveAgent) runWithOverrides(ctx context.Context, task *Task) (string, error) {
+	if task.Overrides != nil {
+		o, err := a.limits.Apply(*task.Overrides)
+		if err != nil {
+			return "", err
+		}
+		ctx = overrides.WithContext(ctx, o)
 	}
-	if name, ok := state["name"].(string); ok {
-		a.AgentStruct.Name = name
+	return a.run(ctx, task)
+}
+
+// run executa os hooks de início e fim em torno da chamada ao LLM
+func (a *CognitiveAgent) run(ctx context.Context, task *Task) (string, error) {
+	if err := a.hooks.taskBegin(ctx, a, task); err != nil {
+		return "", err
 	}
-	if role, ok := state["role"].(string); ok {
-		a.AgentStruct.Role = role
+
+	input := a.withFewShot(task.Description)
+	if task.ExpectedOutput != "" {
+		input += "\nResultado esperado: " + task.ExpectedOutput
 	}
-	if goal, ok := state["goal"].(string); ok {
-		a.Goal = goal
+	memories, refs := a.recall(ctx, task.Description)
+	for _, ref := range refs {
+		memory.RecordSources(ctx, ref.ID)
 	}
-	if model, ok := state["model"].(string); ok {
-		a.Model = model
+	p := prompt.Prompt{
+		System:   a.withBrandVoice(a.Backstory),
+		Memories: memories,
+		Input:    input,
 	}
-	if temperature, ok := state["temperature"].(float64); ok {
-		a.Temperature = temperature
+	output, err := a.completeCached(ctx, p, task.Input)
+	if err != nil {
+		return "", err
 	}
-	if maxTokens, ok := state["max_tokens"].(float64); ok {
-		a.MaxTokens = int(maxTokens)
+	if output, err = a.validateOutput(ctx, task, p, output); err != nil {
+		return "", err
 	}
-	if contextWindow, ok := state["context_window"].(float64); ok {
-		a.ContextWindow = int(contextWindow)
+	if output, err = a.reviewBrandVoice(ctx, task, p, output); err != nil {
+		return "", err
 	}
-	if learningRate, ok := state["learning_rate"].(float64); ok {
-		a.LearningRate = learningRate
+
+	// Registra a contribuição para que o resultado do workflow possa ser propagado ao agente
+	if task.Output == nil {
+		task.Output = make(map[string]interface{})
 	}
-	if knowledgeBase, ok := state["knowledge_base"].(map[string]interface{}); ok {
-		a.KnowledgeBase = knowledgeBase
+	provenance := memory.ProvenanceFromContext(ctx)
+	task.Output[contributionKey] = Contribution{
+		AgentID:       a.GetID(),
+		TaskID:        task.ID,
+		WorkflowID:    provenance.WorkflowID,
+		PromptVersion: a.promptVersion(),
+		Memories:      refs,
+		ToolCalls:     provenance.ToolCalls,
 	}
-	if promptTemplates, ok := state["prompt_templates"].(map[string]interface{}); ok {
-		for k, v := range promptTemplates {
-			if template, ok := v.(string); ok {
-				a.PromptTemplates[k] = template
-			}
+
+	return a.hooks.taskEnd(ctx, a, task, output)
+}
+
+// cacheRequests conta as consultas aos caches por resultado (hit, miss ou error), para a
+// taxa de acerto exposta em /metrics
+var cacheRequests = metrics.Default.Counter("hivemind_cache_requests_total",
+	"Consultas aos caches de resultados e semântico por resultado", "cache", "result")
+
+// cacheResult é o rótulo de resultado de uma consulta ao cache
+func cacheResult(hit bool, err error) string {
+	switch {
+	case err != nil:
+		return "error"
+	case hit:
+		return "hit"
+	}
+	return "miss"
+}
+
+// completeCached consulta o cache de resultados (correspondência exata) e o cache
+// semântico antes de chamar o LLM. Os caches não são usados no modo dry-run, e falhas
+// dos caches apenas são registradas no log.
+func (a *CognitiveAgent) completeCached(ctx context.Context, p prompt.Prompt, inputs map[string]interface{}) (string, error) {
+	if simulation.IsDryRun(ctx) || (a.resultCache == nil && a.semanticCache == nil) {
+		return a.CompletePrompt(ctx, p)
+	}
+
+	// O modelo pode ter sido substituído pelos overrides da tarefa
+	model := a.Model
+	if o := overrides.FromContext(ctx); o.Model != "" {
+		model = o.Model
+	}
+
+	var key string
+	if a.resultCache != nil {
+		key = cache.Key(model, p.System, p.Input, inputs)
+		entry, ok, err := a.resultCache.Get(ctx, key)
+		cacheRequests.Inc("result", cacheResult(ok, err))
+		if err != nil {
+			log.Printf("⚠️ Agente %s: erro ao consultar cache de resultados: %v", a.GetID(), err)
+		}
+		if ok {
+			return entry.Output, nil
 		}
 	}
-	if performanceStats, ok := state["performance_stats"].(map[string]interface{}); ok {
-		for k, v := range performanceStats {
-			if stat, ok := v.(float64); ok {
-				a.PerformanceStats[k] = stat
-			}
+
+	// O namespace separa as respostas por agente e modelo, já que o system prompt difere
+	namespace := a.GetID() + ":" + model
+	if a.semanticCache != nil {
+		response, _, ok, err := a.semanticCache.Lookup(ctx, namespace, p.Input)
+		cacheRequests.Inc("semantic", cacheResult(ok, err))
+		if err != nil {
+			log.Printf("⚠️ Agente %s: erro ao consultar cache semântico: %v", a.GetID(), err)
+		}
+		if ok {
+			return response, nil
 		}
 	}
-	if trainingHistory, ok := state["training_history"].([]interface{}); ok {
-		for _, v := range trainingHistory {
-			if metrics, ok := v.(map[string]interface{}); ok {
-				a.trainingHistory = append(a.trainingHistory, &TrainingMetrics{
-					StartTime:      time.Now(),
-					EndTime:        time.Now(),
-					Accuracy:       metrics["accuracy"].(float64),
-					Loss:           metrics["loss"].(float64),
-					RoundsExecuted: int(metrics["rounds_executed"].(float64)),
-				})
-			}
+
+	output, err := a.CompletePrompt(ctx, p)
+	if err != nil {
+		return "", err
+	}
+
+	if a.resultCache != nil {
+		if err := a.resultCache.Put(ctx, cache.Entry{Key: key, Model: model, Output: output, CreatedAt: time.Now()}); err != nil {
+			log.Printf("⚠️ Agente %s: erro ao gravar cache de resultados: %v", a.GetID(), err)
 		}
 	}
+	if a.semanticCache != nil {
+		if err := a.semanticCache.Store(ctx, namespace, p.Input, output); err != nil {
+			log.Printf("⚠️ Agente %s: erro ao gravar cache semântico: %v", a.GetID(), err)
+		}
+	}
+	return output, nil
+}
 
+// Execute executa uma tarefa
+func (a *CognitiveAgent) Execute(ctx context.Context, task Task) error {
+	_, err := a.Run(ctx, &task)
+	return err
+}
+
+// Stop interrompe a execução do agente
+func (a *CognitiveAgent) Stop() error {
+	// TODO: Implementar parada do agente
 	return nil
 }
@@ -0,0 +1,391 @@
+package communication
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/streadway/amqp"
+
+	"github.com/suissa/HiveMind/agents/errs"
+)
+
+// DefaultTopicExchange é a topic exchange padrão do AMQPClient
+const DefaultTopicExchange = "hivemind.topics"
+
+// DelayedExchangeSuffix é o sufixo da exchange de mensagens adiadas, do tipo x-delayed-message
+// do plugin rabbitmq_delayed_message_exchange, ligada à topic exchange do cliente
+const DelayedExchangeSuffix = ".delayed"
+
+// AMQPClient implementa a interface CommunicationClient sobre uma topic exchange do
+// RabbitMQ: os tópicos são routing keys, e os padrões ("tasks.*", "metrics.#") são as
+// bindings nativas da exchange. Cada inscrição tem uma fila exclusiva e um canal próprio; as
+// publicações e requisições usam os canais com confirmação de um RabbitMQPool sobre a conexão.
+type AMQPClient struct {
+	conn     *amqp.Connection
+	pool     *RabbitMQPool // Canais de publicação
+	config   *ConnectionConfig
+	exchange string
+	delayed  bool // Se a exchange de mensagens adiadas já foi declarada
+	status   *ClientStatus
+	subs     map[string]*amqpSubscription
+	mu       sync.RWMutex
+	ctx      context.Context // Cancelado no Disconnect, interrompendo os handlers em execução
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+}
+
+// amqpSubscription é uma inscrição com fila exclusiva e canal próprio
+type amqpSubscription struct {
+	channel *amqp.Channel
+	done    chan struct{}
+}
+
+// NewAMQPClient cria um cliente para a topic exchange informada (vazio usa DefaultTopicExchange)
+func NewAMQPClient(config *ConnectionConfig, exchange string) *AMQPClient {
+	if exchange == "" {
+		exchange = DefaultTopicExchange
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	return &AMQPClient{
+		config:   config,
+		exchange: exchange,
+		status:   &ClientStatus{Connected: false},
+		subs:     make(map[string]*amqpSubscription),
+		ctx:      ctx,
+		cancel:   cancel,
+	}
+}
+
+// Connect conecta ao RabbitMQ e declara a topic exchange
+func (ac *AMQPClient) Connect(ctx context.Context) error {
+	conn, err := DialRabbitMQ(ac.config)
+	if err != nil {
+		return err
+	}
+	channel, err := conn.Channel()
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("erro ao abrir canal AMQP: %v", err)
+	}
+	if err := channel.ExchangeDeclare(ac.exchange, amqp.ExchangeTopic, true, false, false, false, nil); err != nil {
+		conn.Close()
+		return fmt.Errorf("erro ao declarar a exchange %s: %v", ac.exchange, err)
+	}
+	channel.Close()
+
+	ac.mu.Lock()
+	defer ac.mu.Unlock()
+	ac.conn = conn
+	ac.pool = NewConnectionPool(conn, PoolOptions{})
+	ac.status.Connected = true
+	ac.status.LastConnection = time.Now().Unix()
+	return nil
+}
+
+// Disconnect fecha a conexão; as filas exclusivas das inscrições são removidas pelo servidor
+func (ac *AMQPClient) Disconnect() error {
+	ac.mu.Lock()
+	ac.cancel()
+	conn := ac.conn
+	if ac.pool != nil {
+		ac.pool.Close()
+	}
+	ac.subs = make(map[string]*amqpSubscription)
+	ac.status.Subscriptions = 0
+	ac.status.Connected = false
+	ac.mu.Unlock()
+
+	if conn != nil {
+		if err := conn.Close(); err != nil {
+			return fmt.Errorf("erro ao fechar conexão AMQP: %v", err)
+		}
+	}
+	ac.wg.Wait()
+	return nil
+}
+
+// Subscribe registra um handler para um tópico ou padrão, ligando uma fila exclusiva à
+// exchange com o padrão como binding key
+func (ac *AMQPClient) Subscribe(subject string, handler MessageHandler) error {
+	return ac.SubscribeHeaders(subject, withoutHeaders(handler))
+}
+
+// SubscribeHeaders registra um handler que recebe também os headers das mensagens
+func (ac *AMQPClient) SubscribeHeaders(subject string, handler HeaderHandler) error {
+	if err := ValidateSubject(subject); err != nil {
+		return err
+	}
+
+	ac.mu.Lock()
+	defer ac.mu.Unlock()
+
+	if ac.conn == nil {
+		return fmt.Errorf("cliente AMQP não conectado")
+	}
+	if _, exists := ac.subs[subject]; exists {
+		return fmt.Errorf("já existe um handler para o tópico %s", subject)
+	}
+
+	channel, err := ac.conn.Channel()
+	if err != nil {
+		return fmt.Errorf("erro ao abrir canal AMQP: %v", err)
+	}
+	queue, err := channel.QueueDeclare("", false, true, true, false, nil)
+	if err != nil {
+		channel.Close()
+		return fmt.Errorf("erro ao declarar fila para o tópico %s: %v", subject, err)
+	}
+	if err := channel.QueueBind(queue.Name, subject, ac.exchange, false, nil); err != nil {
+		channel.Close()
+		return fmt.Errorf("erro ao ligar a fila ao tópico %s: %v", subject, err)
+	}
+	deliveries, err := channel.Consume(queue.Name, "", true, true, false, false, nil)
+	if err != nil {
+		channel.Close()
+		return fmt.Errorf("erro ao consumir o tópico %s: %v", subject, err)
+	}
+
+	sub := &amqpSubscription{channel: channel, done: make(chan struct{})}
+	ac.subs[subject] = sub
+	ac.status.Subscriptions++
+
+	ac.wg.Add(1)
+	go func() {
+		defer ac.wg.Done()
+		defer close(sub.done)
+		// O canal de entregas é fechado no Unsubscribe ou no Disconnect
+		for delivery := range deliveries {
+			if handler == nil {
+				ac.mu.Lock()
+				ac.status.BytesReceived += int64(len(delivery.Body))
+				ac.mu.Unlock()
+				continue
+			}
+			start := time.Now()
+			headers := amqpHeaders(delivery.ContentType, delivery.Headers)
+			receive := func(ctx context.Context, subject string, data []byte) error {
+				return handler(ctx, subject, data, headers)
+			}
+			err := deliver(ac.ctx, parseDelivery(headers), func() error {
+				return safeHandle(ac.ctx, "amqp", receive, delivery.RoutingKey, delivery.Body)
+			})
+			ac.mu.Lock()
+			ac.status.recordConsume(delivery.RoutingKey, len(delivery.Body), start, err)
+			ac.mu.Unlock()
+		}
+	}()
+
+	return nil
+}
+
+// Unsubscribe remove a inscrição, fechando o canal e a fila exclusiva
+func (ac *AMQPClient) Unsubscribe(subject string) error {
+	ac.mu.Lock()
+	sub, exists := ac.subs[subject]
+	if !exists {
+		ac.mu.Unlock()
+		return errs.New(errs.ErrNotFound, "amqp.Unsubscribe", "não existe handler para o tópico %s", subject)
+	}
+	delete(ac.subs, subject)
+	ac.status.Subscriptions--
+	ac.mu.Unlock()
+
+	if err := sub.channel.Close(); err != nil {
+		return fmt.Errorf("erro ao cancelar inscrição do tópico %s: %v", subject, err)
+	}
+	<-sub.done
+	return nil
+}
+
+// Publish envia uma mensagem para a exchange com o tópico como routing key
+func (ac *AMQPClient) Publish(ctx context.Context, subject string, data []byte) error {
+	return ac.PublishHeaders(ctx, subject, data, nil)
+}
+
+// PublishHeaders envia uma mensagem com headers; content-type vai na propriedade
+// ContentType da mensagem e os demais na tabela de headers
+func (ac *AMQPClient) PublishHeaders(ctx context.Context, subject string, data []byte, headers map[string]string) error {
+	ac.mu.RLock()
+	pool := ac.pool
+	ac.mu.RUnlock()
+
+	if pool == nil {
+		return fmt.Errorf("cliente AMQP não conectado")
+	}
+	start := time.Now()
+	err := pool.Publish(ctx, ac.exchange, subject, amqpPublishing(data, headers))
+	if err != nil {
+		err = fmt.Errorf("erro ao publicar mensagem no tópico %s: %w", subject, err)
+	}
+
+	ac.mu.Lock()
+	ac.status.recordPublish(subject, len(data), start, err)
+	ac.mu.Unlock()
+	return err
+}
+
+// PublishWithOptions publica a mensagem com opções de entrega. O TTL vira a expiração da
+// mensagem no RabbitMQ e o adiamento usa a exchange de mensagens adiadas, que exige o plugin
+// rabbitmq_delayed_message_exchange; as reentregas são feitas pelas inscrições do cliente.
+func (ac *AMQPClient) PublishWithOptions(ctx context.Context, subject string, data []byte, opts DeliveryOptions) error {
+	publishing := amqpPublishing(data, opts.headers(time.Now(), nil))
+	if opts.TTL > 0 {
+		publishing.Expiration = strconv.FormatInt(opts.TTL.Milliseconds(), 10)
+	}
+
+	ac.mu.Lock()
+	pool := ac.pool
+	if pool == nil {
+		ac.mu.Unlock()
+		return fmt.Errorf("cliente AMQP não conectado")
+	}
+	exchange := ac.exchange
+	if opts.DeliverAfter > 0 {
+		if err := ac.declareDelayed(); err != nil {
+			ac.mu.Unlock()
+			return err
+		}
+		exchange += DelayedExchangeSuffix
+		if publishing.Headers == nil {
+			publishing.Headers = amqp.Table{}
+		}
+		publishing.Headers["x-delay"] = opts.DeliverAfter.Milliseconds()
+	}
+	ac.mu.Unlock()
+
+	start := time.Now()
+	err := pool.Publish(ctx, exchange, subject, publishing)
+	if err != nil {
+		err = fmt.Errorf("erro ao publicar mensagem no tópico %s: %w", subject, err)
+	}
+
+	ac.mu.Lock()
+	ac.status.recordPublish(subject, len(data), start, err)
+	ac.mu.Unlock()
+	return err
+}
+
+// declareDelayed declara a exchange de mensagens adiadas e a liga à topic exchange, uma vez
+// por cliente. A declaração usa um canal próprio, já que a falha (sem o plugin) fecha o canal.
+// Deve ser chamado com ac.mu travado.
+func (ac *AMQPClient) declareDelayed() error {
+	if ac.delayed {
+		return nil
+	}
+	channel, err := ac.conn.Channel()
+	if err != nil {
+		return fmt.Errorf("erro ao abrir canal AMQP: %v", err)
+	}
+	defer channel.Close()
+
+	delayed := ac.exchange + DelayedExchangeSuffix
+	args := amqp.Table{"x-delayed-type": amqp.ExchangeTopic}
+	if err := channel.ExchangeDeclare(delayed, "x-delayed-message", true, false, false, false, args); err != nil {
+		return fmt.Errorf("erro ao declarar a exchange %s (o plugin de mensagens adiadas está ativo?): %v", delayed, err)
+	}
+	if err := channel.ExchangeBind(ac.exchange, "#", delayed, false, nil); err != nil {
+		return fmt.Errorf("erro ao ligar a exchange %s: %v", delayed, err)
+	}
+	ac.delayed = true
+	return nil
+}
+
+// Request envia uma mensagem e aguarda a resposta pela fila direct reply-to do RabbitMQ
+func (ac *AMQPClient) Request(ctx context.Context, subject string, data []byte, timeout int) ([]byte, error) {
+	response, _, err := ac.RequestHeaders(ctx, subject, data, nil, timeout)
+	return response, err
+}
+
+// RequestHeaders envia uma mensagem com headers e aguarda a resposta com os headers dela
+func (ac *AMQPClient) RequestHeaders(ctx context.Context, subject string, data []byte, headers map[string]string, timeout int) ([]byte, map[string]string, error) {
+	start := time.Now()
+	response, replyHeaders, err := ac.request(ctx, subject, data, headers, timeout)
+
+	ac.mu.Lock()
+	ac.status.recordRequest(subject, len(data), len(response), start, err)
+	ac.mu.Unlock()
+
+	return response, replyHeaders, err
+}
+
+// request publica a requisição e aguarda a resposta de mesmo correlation ID
+func (ac *AMQPClient) request(ctx context.Context, subject string, data []byte, headers map[string]string, timeout int) ([]byte, map[string]string, error) {
+	ac.mu.RLock()
+	pool := ac.pool
+	ac.mu.RUnlock()
+	if pool == nil {
+		return nil, nil, fmt.Errorf("cliente AMQP não conectado")
+	}
+
+	publishing := amqpPublishing(data, headers)
+	publishing.CorrelationId = fmt.Sprintf("%s.%d", subject, time.Now().UnixNano())
+	requestCtx, cancel := context.WithTimeout(ctx, time.Duration(timeout)*time.Millisecond)
+	defer cancel()
+
+	reply, err := pool.Request(requestCtx, ac.exchange, subject, publishing)
+	switch {
+	case err == nil:
+		return reply.Body, amqpHeaders(reply.ContentType, reply.Headers), nil
+	case ctx.Err() == nil && errors.Is(err, context.DeadlineExceeded):
+		return nil, nil, errs.New(errs.ErrTimeout, "amqp.Request", "timeout ao aguardar resposta do tópico %s", subject)
+	default:
+		return nil, nil, fmt.Errorf("erro na requisição ao tópico %s: %w", subject, err)
+	}
+}
+
+// amqpPublishing monta a mensagem AMQP, levando content-type na propriedade ContentType
+func amqpPublishing(data []byte, headers map[string]string) amqp.Publishing {
+	publishing := amqp.Publishing{
+		ContentType: "application/octet-stream",
+		Timestamp:   time.Now(),
+		Body:        data,
+	}
+	for key, value := range headers {
+		if strings.EqualFold(key, HeaderContentType) {
+			publishing.ContentType = value
+			continue
+		}
+		if publishing.Headers == nil {
+			publishing.Headers = amqp.Table{}
+		}
+		publishing.Headers[strings.ToLower(key)] = value
+	}
+	return publishing
+}
+
+// amqpHeaders reúne a propriedade ContentType e a tabela de headers de uma entrega. O
+// content-type padrão application/octet-stream não é repassado, como em uma mensagem sem headers.
+func amqpHeaders(contentType string, table amqp.Table) map[string]string {
+	headers := make(map[string]string, len(table)+1)
+	for key, value := range table {
+		headers[strings.ToLower(key)] = fmt.Sprint(value)
+	}
+	if contentType != "" && contentType != "application/octet-stream" {
+		headers[HeaderContentType] = contentType
+	}
+	return headers
+}
+
+// GetStatus retorna uma cópia do estado atual do cliente
+func (ac *AMQPClient) GetStatus() *ClientStatus {
+	ac.mu.RLock()
+	defer ac.mu.RUnlock()
+	return ac.status.snapshot()
+}
+
+// GetSubscriptions retorna a lista de inscrições ativas
+func (ac *AMQPClient) GetSubscriptions() []string {
+	ac.mu.RLock()
+	defer ac.mu.RUnlock()
+
+	subs := make([]string, 0, len(ac.subs))
+	for subject := range ac.subs {
+		subs = append(subs, subject)
+	}
+	return subs
+}
@@ -0,0 +1,75 @@
+// Package commtest oferece um barramento em memória para os testes dos protocolos sobre
+// agents/communication (presença, contract net, plano de controle, redistribuição): as
+// publicações são entregues de forma síncrona às inscrições cujo padrão casa com o assunto.
+package commtest
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/suissa/HiveMind/agents/communication"
+)
+
+// ErrUnavailable é o erro das publicações nos assuntos marcados com Fail
+var ErrUnavailable = errors.New("barramento indisponível")
+
+// Bus é um barramento em memória; implementa Publish, Subscribe e Unsubscribe como
+// communication.CommunicationClient
+type Bus struct {
+	handlers map[string]communication.MessageHandler
+	fail     map[string]bool
+	mu       sync.Mutex
+}
+
+// NewBus cria um barramento sem inscrições
+func NewBus() *Bus {
+	return &Bus{
+		handlers: make(map[string]communication.MessageHandler),
+		fail:     make(map[string]bool),
+	}
+}
+
+// Publish entrega a mensagem às inscrições do assunto, fora do lock, para que os handlers
+// possam publicar e se inscrever
+func (b *Bus) Publish(ctx context.Context, subject string, data []byte) error {
+	b.mu.Lock()
+	if b.fail[subject] {
+		b.mu.Unlock()
+		return ErrUnavailable
+	}
+	var handlers []communication.MessageHandler
+	for pattern, handler := range b.handlers {
+		if communication.MatchSubject(pattern, subject) {
+			handlers = append(handlers, handler)
+		}
+	}
+	b.mu.Unlock()
+	for _, handler := range handlers {
+		handler(ctx, subject, data)
+	}
+	return nil
+}
+
+// Subscribe inscreve o handler no assunto (ou padrão), substituindo a inscrição anterior
+func (b *Bus) Subscribe(subject string, handler communication.MessageHandler) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.handlers[subject] = handler
+	return nil
+}
+
+// Unsubscribe remove a inscrição do assunto
+func (b *Bus) Unsubscribe(subject string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.handlers, subject)
+	return nil
+}
+
+// Fail faz as publicações no assunto falharem com ErrUnavailable (ou voltarem a funcionar)
+func (b *Bus) Fail(subject string, fail bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.fail[subject] = fail
+}
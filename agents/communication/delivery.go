@@ -0,0 +1,198 @@
+package communication
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Headers das opções de entrega por mensagem. Os instantes são Unix em milissegundos.
+const (
+	HeaderDeliverAt       = "x-deliver-at"       // A mensagem não é entregue antes deste instante
+	HeaderExpiresAt       = "x-expires-at"       // A mensagem é descartada se não for entregue até este instante
+	HeaderMaxRedeliveries = "x-max-redeliveries" // Reentregas permitidas quando o handler falha
+	HeaderRedelivery      = "x-redelivery"       // Número da reentrega (ausente na primeira entrega)
+	HeaderRedeliveryDelay = "x-redelivery-delay" // Espera em milissegundos entre as reentregas
+)
+
+// DefaultRedeliveryDelay é a espera padrão entre as reentregas de uma mensagem
+const DefaultRedeliveryDelay = time.Second
+
+// DeliveryOptions são as opções de entrega de uma mensagem
+type DeliveryOptions struct {
+	// MaxRedeliveries é o número de reentregas quando o handler falha (0 entrega uma vez só)
+	MaxRedeliveries int
+	// RedeliveryDelay é a espera entre as reentregas (0 usa DefaultRedeliveryDelay)
+	RedeliveryDelay time.Duration
+	// TTL é o prazo para a mensagem ser entregue depois de disponível; expirada, é descartada
+	TTL time.Duration
+	// DeliverAfter adia a entrega da mensagem
+	DeliverAfter time.Duration
+}
+
+// DeliveryPublisher é implementado pelos clientes que publicam com opções de entrega. AMQP
+// (TTL e delayed exchange), NATS com JetStream (reentregas e adiamento pelo servidor) e Kafka
+// (retry topics) usam os recursos do broker; o NATS sem JetStream e o DeliveryClient, para os
+// demais transportes, emulam as opções no cliente.
+type DeliveryPublisher interface {
+	PublishWithOptions(ctx context.Context, subject string, data []byte, opts DeliveryOptions) error
+}
+
+// headers acrescenta aos headers informados os das opções de entrega
+func (o DeliveryOptions) headers(now time.Time, headers map[string]string) map[string]string {
+	out := make(map[string]string, len(headers)+4)
+	for key, value := range headers {
+		out[key] = value
+	}
+	if o.DeliverAfter > 0 {
+		out[HeaderDeliverAt] = strconv.FormatInt(now.Add(o.DeliverAfter).UnixMilli(), 10)
+	}
+	if o.TTL > 0 {
+		out[HeaderExpiresAt] = strconv.FormatInt(now.Add(o.DeliverAfter+o.TTL).UnixMilli(), 10)
+	}
+	if o.MaxRedeliveries > 0 {
+		out[HeaderMaxRedeliveries] = strconv.Itoa(o.MaxRedeliveries)
+		if o.RedeliveryDelay > 0 {
+			out[HeaderRedeliveryDelay] = strconv.FormatInt(o.RedeliveryDelay.Milliseconds(), 10)
+		}
+	}
+	return out
+}
+
+// delivery é o estado de entrega lido dos headers de uma mensagem
+type delivery struct {
+	deliverAt       time.Time
+	expiresAt       time.Time
+	maxRedeliveries int
+	redelivery      int
+	redeliveryDelay time.Duration
+}
+
+// parseDelivery lê o estado de entrega dos headers, ignorando valores inválidos
+func parseDelivery(headers map[string]string) delivery {
+	d := delivery{redeliveryDelay: DefaultRedeliveryDelay}
+	if ms, err := strconv.ParseInt(headers[HeaderDeliverAt], 10, 64); err == nil {
+		d.deliverAt = time.UnixMilli(ms)
+	}
+	if ms, err := strconv.ParseInt(headers[HeaderExpiresAt], 10, 64); err == nil {
+		d.expiresAt = time.UnixMilli(ms)
+	}
+	d.maxRedeliveries, _ = strconv.Atoi(headers[HeaderMaxRedeliveries])
+	d.redelivery, _ = strconv.Atoi(headers[HeaderRedelivery])
+	if ms, err := strconv.ParseInt(headers[HeaderRedeliveryDelay], 10, 64); err == nil && ms > 0 {
+		d.redeliveryDelay = time.Duration(ms) * time.Millisecond
+	}
+	return d
+}
+
+// expired informa se a mensagem expirou
+func (d delivery) expired(now time.Time) bool {
+	return !d.expiresAt.IsZero() && now.After(d.expiresAt)
+}
+
+// canRedeliver informa se a mensagem ainda tem reentregas disponíveis
+func (d delivery) canRedeliver() bool {
+	return d.redelivery < d.maxRedeliveries
+}
+
+// next retorna os headers da próxima reentrega da mensagem
+func (d delivery) next(now time.Time, headers map[string]string) map[string]string {
+	out := make(map[string]string, len(headers)+2)
+	for key, value := range headers {
+		out[key] = value
+	}
+	out[HeaderRedelivery] = strconv.Itoa(d.redelivery + 1)
+	out[HeaderDeliverAt] = strconv.FormatInt(now.Add(d.redeliveryDelay).UnixMilli(), 10)
+	return out
+}
+
+// deliver emula no consumidor o TTL e as reentregas: descarta a mensagem expirada e repete o
+// handler que falhou até esgotar as reentregas. As esperas bloqueiam a inscrição.
+func deliver(ctx context.Context, d delivery, handle func() error) error {
+	if d.expired(time.Now()) {
+		return nil
+	}
+	err := handle()
+	for ; err != nil && d.canRedeliver(); d.redelivery++ {
+		if !sleep(ctx, d.redeliveryDelay) {
+			return ctx.Err()
+		}
+		if d.expired(time.Now()) {
+			return nil
+		}
+		err = handle()
+	}
+	return err
+}
+
+// deliveryEnvelope leva as opções de entrega nos transportes sem headers
+type deliveryEnvelope struct {
+	Headers map[string]string `json:"delivery"`
+	Payload []byte            `json:"payload"`
+}
+
+// DeliveryClient adiciona opções de entrega a qualquer cliente. Se o cliente as implementa
+// (DeliveryPublisher), a publicação é repassada a ele; nos demais, o adiamento é feito por um
+// timer no publicador, as opções seguem em um envelope JSON e o TTL e as reentregas são
+// emulados nas inscrições feitas por este cliente.
+type DeliveryClient struct {
+	CommunicationClient
+	ctx    context.Context // Cancelado no Disconnect, descartando as publicações adiadas
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewDeliveryClient cria um cliente com opções de entrega
+func NewDeliveryClient(client CommunicationClient) *DeliveryClient {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &DeliveryClient{CommunicationClient: client, ctx: ctx, cancel: cancel}
+}
+
+// PublishWithOptions publica a mensagem com as opções de entrega
+func (c *DeliveryClient) PublishWithOptions(ctx context.Context, subject string, data []byte, opts DeliveryOptions) error {
+	if publisher, ok := c.CommunicationClient.(DeliveryPublisher); ok {
+		return publisher.PublishWithOptions(ctx, subject, data, opts)
+	}
+
+	body, err := json.Marshal(deliveryEnvelope{Headers: opts.headers(time.Now(), nil), Payload: data})
+	if err != nil {
+		return fmt.Errorf("erro ao serializar envelope de entrega: %v", err)
+	}
+	if opts.DeliverAfter <= 0 {
+		return c.CommunicationClient.Publish(ctx, subject, body)
+	}
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+		if sleep(c.ctx, opts.DeliverAfter) {
+			c.CommunicationClient.Publish(c.ctx, subject, body)
+		}
+	}()
+	return nil
+}
+
+// Subscribe registra o handler, aplicando o TTL e as reentregas das mensagens com envelope
+func (c *DeliveryClient) Subscribe(subject string, handler MessageHandler) error {
+	if _, ok := c.CommunicationClient.(DeliveryPublisher); ok {
+		return c.CommunicationClient.Subscribe(subject, handler)
+	}
+	return c.CommunicationClient.Subscribe(subject, func(ctx context.Context, msgSubject string, data []byte) error {
+		var envelope deliveryEnvelope
+		if err := json.Unmarshal(data, &envelope); err != nil || envelope.Headers == nil {
+			return handler(ctx, msgSubject, data)
+		}
+		return deliver(ctx, parseDelivery(envelope.Headers), func() error {
+			return handler(ctx, msgSubject, envelope.Payload)
+		})
+	})
+}
+
+// Disconnect descarta as publicações adiadas pendentes e desconecta o cliente
+func (c *DeliveryClient) Disconnect() error {
+	c.cancel()
+	c.wg.Wait()
+	return c.CommunicationClient.Disconnect()
+}
@@ -0,0 +1,63 @@
+package communication
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestDeliveryClientRedeliveries(t *testing.T) {
+	client := NewDeliveryClient(&loopbackClient{handlers: make(map[string]MessageHandler)})
+	defer client.Disconnect()
+
+	attempts := 0
+	client.Subscribe("tasks.run", func(ctx context.Context, subject string, data []byte) error {
+		attempts++
+		if string(data) != "t-1" {
+			t.Fatalf("payload inesperado: %q", data)
+		}
+		return errors.New("falha")
+	})
+
+	opts := DeliveryOptions{MaxRedeliveries: 2, RedeliveryDelay: time.Millisecond}
+	if err := client.PublishWithOptions(context.Background(), "tasks.run", []byte("t-1"), opts); err == nil {
+		t.Fatal("esperava o erro do handler após as reentregas")
+	}
+	if attempts != 3 {
+		t.Fatalf("esperava 3 entregas, obtidas %d", attempts)
+	}
+}
+
+func TestDeliveryClientDelayAndTTL(t *testing.T) {
+	client := NewDeliveryClient(&loopbackClient{handlers: make(map[string]MessageHandler)})
+	defer client.Disconnect()
+
+	received := make(chan time.Time, 1)
+	client.Subscribe("tasks.run", func(ctx context.Context, subject string, data []byte) error {
+		received <- time.Now()
+		return nil
+	})
+
+	start := time.Now()
+	opts := DeliveryOptions{DeliverAfter: 20 * time.Millisecond, TTL: time.Second}
+	if err := client.PublishWithOptions(context.Background(), "tasks.run", []byte("t-1"), opts); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case at := <-received:
+		if at.Sub(start) < 20*time.Millisecond {
+			t.Fatalf("mensagem entregue antes do horário: %v", at.Sub(start))
+		}
+	case <-time.After(time.Second):
+		t.Fatal("mensagem adiada não entregue")
+	}
+
+	expired := parseDelivery(DeliveryOptions{TTL: time.Millisecond}.headers(start.Add(-time.Second), nil))
+	if !expired.expired(time.Now()) {
+		t.Fatal("esperava a mensagem expirada")
+	}
+	if err := deliver(context.Background(), expired, func() error { return errors.New("não deveria executar") }); err != nil {
+		t.Fatalf("mensagem expirada deveria ser descartada: %v", err)
+	}
+}
@@ -9,6 +9,8 @@ import (
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
+
+	"github.com/suissa/HiveMind/agents/errs"
 )
 
 // GRPCClient implementa a interface CommunicationClient usando gRPC
@@ -41,22 +43,34 @@ func (gc *GRPCClient) Connect(ctx context.Context) error {
 	var opts []grpc.DialOption
 
 	// Configura TLS se necessário
-	if gc.config.TLS {
-		creds, err := credentials.NewClientTLSFromFile("cert.pem", "")
-		if err != nil {
-			return fmt.Errorf("erro ao carregar certificado TLS: %v", err)
-		}
-		opts = append(opts, grpc.WithTransportCredentials(creds))
+	tlsConfig, err := gc.config.tlsConfig()
+	if err != nil {
+		return fmt.Errorf("erro ao configurar TLS do gRPC: %v", err)
+	}
+	if tlsConfig != nil {
+		opts = append(opts, grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)))
 	} else {
 		opts = append(opts, grpc.WithTransportCredentials(insecure.NewCredentials()))
 	}
 
-	// Configura autenticação se necessário
-	if gc.config.Username != "" {
-		opts = append(opts, grpc.WithPerRPCCredentials(&authCreds{
-			username: gc.config.Username,
-			password: gc.config.Password,
-		}))
+	// Configura autenticação se necessário; as credenciais só trafegam sobre TLS
+	if sasl := gc.config.sasl(); sasl != nil {
+		if tlsConfig == nil {
+			return fmt.Errorf("autenticação do gRPC exige TLS: credenciais não são enviadas em texto puro")
+		}
+		switch sasl.Mechanism {
+		case SASLPlain:
+			opts = append(opts, grpc.WithPerRPCCredentials(&authCreds{
+				username: sasl.Username,
+				password: sasl.Password,
+			}))
+		case SASLToken:
+			opts = append(opts, grpc.WithPerRPCCredentials(&tokenCreds{
+				token: sasl.Token,
+			}))
+		default:
+			return fmt.Errorf("mecanismo de autenticação não suportado pelo gRPC: %s", sasl.Mechanism)
+		}
 	}
 
 	// Estabelece a conexão
@@ -95,22 +109,24 @@ func (gc *GRPCClient) processMessages() {
 			msg, err := gc.stream.Recv()
 			if err != nil {
 				gc.mu.Lock()
-				gc.status.LastError = err.Error()
+				gc.status.recordError("connection", "", err)
 				gc.mu.Unlock()
 				time.Sleep(time.Second) // Espera antes de tentar novamente
 				continue
 			}
 
+			// Entrega à inscrição exata e às inscrições por padrão que casam com o tópico
 			gc.mu.RLock()
-			handler, exists := gc.handlers[msg.Subject]
+			handlers := matchingHandlers(gc.handlers, msg.Subject)
 			gc.mu.RUnlock()
 
-			if exists && handler != nil {
-				if err := handler(gc.ctx, msg.Subject, msg.Data); err != nil {
-					gc.mu.Lock()
-					gc.status.LastError = err.Error()
-					gc.mu.Unlock()
-				}
+			for _, handler := range handlers {
+				start := time.Now()
+				err := safeHandle(gc.ctx, "grpc", handler, msg.Subject, msg.Data)
+				// Os bytes são contados uma vez por mensagem, abaixo
+				gc.mu.Lock()
+				gc.status.recordConsume(msg.Subject, 0, start, err)
+				gc.mu.Unlock()
 			}
 
 			gc.mu.Lock()
@@ -137,8 +153,13 @@ func (gc *GRPCClient) Disconnect() error {
 	return nil
 }
 
-// Subscribe registra um handler para receber mensagens de um tópico
+// Subscribe registra um handler para receber mensagens de um tópico ou padrão ("tasks.*",
+// "metrics.#"). O padrão é enviado ao servidor e também aplicado às mensagens recebidas.
 func (gc *GRPCClient) Subscribe(subject string, handler MessageHandler) error {
+	if err := ValidateSubject(subject); err != nil {
+		return err
+	}
+
 	gc.mu.Lock()
 	defer gc.mu.Unlock()
 
@@ -167,7 +188,7 @@ func (gc *GRPCClient) Unsubscribe(subject string) error {
 	defer gc.mu.Unlock()
 
 	if _, exists := gc.handlers[subject]; !exists {
-		return fmt.Errorf("não existe handler para o tópico %s", subject)
+		return errs.New(errs.ErrNotFound, "grpc.Unsubscribe", "não existe handler para o tópico %s", subject)
 	}
 
 	// Envia requisição de cancelamento de inscrição
@@ -193,15 +214,17 @@ func (gc *GRPCClient) Publish(ctx context.Context, subject string, data []byte)
 		Timestamp: time.Now().Unix(),
 	}
 
-	if _, err := gc.client.Publish(ctx, msg); err != nil {
-		return fmt.Errorf("erro ao publicar mensagem no tópico %s: %v", subject, err)
+	start := time.Now()
+	_, err := gc.client.Publish(ctx, msg)
+	if err != nil {
+		err = fmt.Errorf("erro ao publicar mensagem no tópico %s: %v", subject, err)
 	}
 
 	gc.mu.Lock()
-	gc.status.BytesSent += int64(len(data))
+	gc.status.recordPublish(subject, len(data), start, err)
 	gc.mu.Unlock()
 
-	return nil
+	return err
 }
 
 // Request envia uma mensagem e aguarda resposta
@@ -215,24 +238,28 @@ func (gc *GRPCClient) Request(ctx context.Context, subject string, data []byte,
 	ctx, cancel := context.WithTimeout(ctx, time.Duration(timeout)*time.Millisecond)
 	defer cancel()
 
+	start := time.Now()
 	response, err := gc.client.Request(ctx, msg)
 	if err != nil {
-		return nil, fmt.Errorf("erro na requisição para o tópico %s: %v", subject, err)
+		err = fmt.Errorf("erro na requisição para o tópico %s: %v", subject, err)
+		gc.mu.Lock()
+		gc.status.recordRequest(subject, len(data), 0, start, err)
+		gc.mu.Unlock()
+		return nil, err
 	}
 
 	gc.mu.Lock()
-	gc.status.BytesSent += int64(len(data))
-	gc.status.BytesReceived += int64(len(response.Data))
+	gc.status.recordRequest(subject, len(data), len(response.Data), start, nil)
 	gc.mu.Unlock()
 
 	return response.Data, nil
 }
 
-// GetStatus retorna o estado atual do cliente
+// GetStatus retorna uma cópia do estado atual do cliente
 func (gc *GRPCClient) GetStatus() *ClientStatus {
 	gc.mu.RLock()
 	defer gc.mu.RUnlock()
-	return gc.status
+	return gc.status.snapshot()
 }
 
 // GetSubscriptions retorna a lista de inscrições ativas
@@ -263,3 +290,18 @@ func (c *authCreds) GetRequestMetadata(context.Context, ...string) (map[string]s
 func (c *authCreds) RequireTransportSecurity() bool {
 	return true
 }
+
+// tokenCreds envia um token Bearer em cada chamada gRPC
+type tokenCreds struct {
+	token string
+}
+
+func (c *tokenCreds) GetRequestMetadata(context.Context, ...string) (map[string]string, error) {
+	return map[string]string{
+		"authorization": "Bearer " + c.token,
+	}, nil
+}
+
+func (c *tokenCreds) RequireTransportSecurity() bool {
+	return true
+}
@@ -2,11 +2,21 @@ package communication
 
 import (
 	"context"
+
+	"github.com/suissa/HiveMind/agents/supervisor"
 )
 
 // MessageHandler é a função que processa mensagens recebidas
 type MessageHandler func(ctx context.Context, subject string, data []byte) error
 
+// safeHandle executa o handler isolando panics: a falha é reportada ao supervisor
+// e devolvida como erro, sem derrubar o loop de consumo
+func safeHandle(ctx context.Context, protocol string, handler MessageHandler, subject string, data []byte) error {
+	return supervisor.Default.Protect(protocol+":"+subject, func() error {
+		return handler(ctx, subject, data)
+	})
+}
+
 // CommunicationClient define a interface base para comunicação
 type CommunicationClient interface {
 	// Connect estabelece a conexão com o servidor
@@ -21,7 +31,7 @@ type CommunicationClient interface {
 	Publish(ctx context.Context, subject string, data []byte) error
 	// Request envia uma mensagem e aguarda resposta
 	Request(ctx context.Context, subject string, data []byte, timeout int) ([]byte, error)
-	// GetStatus retorna o estado atual do cliente
+	// GetStatus retorna uma cópia do estado atual do cliente
 	GetStatus() *ClientStatus
 	// GetSubscriptions retorna a lista de inscrições ativas
 	GetSubscriptions() []string
@@ -29,12 +39,15 @@ type CommunicationClient interface {
 
 // ConnectionConfig define as configurações de conexão
 type ConnectionConfig struct {
-	Host     string            // Endereço do servidor
-	Port     int               // Porta do servidor
-	Username string            // Nome de usuário (opcional)
-	Password string            // Senha (opcional)
-	TLS      bool              // Usar TLS
-	Headers  map[string]string // Headers adicionais
+	Host      string            // Endereço do servidor
+	Port      int               // Porta do servidor
+	Username  string            // Nome de usuário (opcional)
+	Password  string            // Senha (opcional)
+	TLS       bool              // Usar TLS com as configurações padrão
+	TLSConfig *TLSConfig        // Configuração TLS completa (CA, certificados, skip-verify)
+	SASL      *SASLConfig       // Mecanismo de autenticação (opcional)
+	VHost     string            // Virtual host do RabbitMQ (opcional)
+	Headers   map[string]string // Headers adicionais
 }
 
 // Message representa uma mensagem trocada entre os agentes
@@ -55,4 +68,15 @@ type ClientStatus struct {
 	Subscriptions  int    `json:"subscriptions"`   // Número de inscrições ativas
 	BytesSent      int64  `json:"bytes_sent"`      // Total de bytes enviados
 	BytesReceived  int64  `json:"bytes_received"`  // Total de bytes recebidos
+
+	MessagesSent     int64            `json:"messages_sent"`     // Mensagens publicadas com sucesso
+	MessagesReceived int64            `json:"messages_received"` // Mensagens entregues aos handlers
+	PublishErrors    int64            `json:"publish_errors"`    // Publicações e requisições que falharam
+	ConsumeErrors    int64            `json:"consume_errors"`    // Handlers que retornaram erro
+	PublishLatency   LatencyHistogram `json:"publish_latency"`   // Duração das publicações
+	RequestLatency   LatencyHistogram `json:"request_latency"`   // Duração das requisições até a resposta
+	ConsumeLatency   LatencyHistogram `json:"consume_latency"`   // Duração dos handlers
+	RecentErrors     []StatusError    `json:"recent_errors"`     // Últimos erros, do mais antigo ao mais recente
+
+	errors errorRing // Preenche RecentErrors nas cópias de GetStatus
 }
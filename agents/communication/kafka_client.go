@@ -2,35 +2,101 @@ package communication
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/Shopify/sarama"
+
+	"github.com/suissa/HiveMind/agents/errs"
 )
 
-// KafkaClient implementa a interface CommunicationClient usando Kafka
+// KafkaClient implementa a interface CommunicationClient usando Kafka. Cada inscrição tem
+// o próprio consumer group e o próprio loop de consumo, que pode ser pausado e retomado.
+// Uma inscrição por padrão ("tasks.*", "metrics.#") consome os tópicos existentes que casam
+// com ele e acompanha a criação de novos.
 type KafkaClient struct {
+	client      sarama.Client
 	producer    sarama.SyncProducer
-	consumer    sarama.ConsumerGroup
+	brokers     []string
+	saramaCfg   *sarama.Config
 	config      *ConnectionConfig
 	status      *ClientStatus
-	handlers    map[string]MessageHandler
+	subs        map[string]*kafkaSubscription
 	groupID     string
+	newGroup    func(brokers []string, groupID string, config *sarama.Config) (sarama.ConsumerGroup, error) // Cria os consumer groups (sarama.NewConsumerGroup)
 	mu          sync.RWMutex
 	ctx         context.Context
 	cancel      context.CancelFunc
 	consumeWait sync.WaitGroup
 }
 
-// NewKafkaClient cria uma nova instância do cliente Kafka
+// KafkaSubscribeOptions configura uma inscrição do KafkaClient
+type KafkaSubscribeOptions struct {
+	// GroupID é o consumer group da inscrição. Vazio usa um grupo dedicado ao tópico,
+	// "<grupo do cliente>.<tópico>", de modo que as instâncias com o mesmo grupo do cliente
+	// dividem as partições de cada tópico sem que uma inscrição afete as demais.
+	GroupID string
+	// ManualCommit desativa o commit periódico de offsets: o offset de cada mensagem é
+	// confirmado de forma síncrona logo após o processamento
+	ManualCommit bool
+	// MaxRetries é o número de novas tentativas do handler antes de desistir da mensagem
+	MaxRetries int
+	// RetryBackoff é a espera entre as tentativas (padrão 500ms)
+	RetryBackoff time.Duration
+	// DLQTopic recebe as mensagens cujo handler falhou após as tentativas, com o tópico,
+	// a partição, o offset e o erro nos headers. Vazio apenas registra o erro no status.
+	DLQTopic string
+	// RetryTopic recebe as reentregas das mensagens publicadas com MaxRedeliveries, e é
+	// consumido pela própria inscrição a partir do horário de cada reentrega. Vazio faz as
+	// reentregas no consumidor, segurando a partição durante a espera.
+	RetryTopic string
+	// TopicRefresh é o intervalo de descoberta de novos tópicos nas inscrições por padrão
+	// (padrão DefaultTopicRefresh)
+	TopicRefresh time.Duration
+}
+
+// DefaultTopicRefresh é o intervalo padrão de descoberta de tópicos das inscrições por padrão
+const DefaultTopicRefresh = 30 * time.Second
+
+// DLQTopic retorna o tópico de mensagens mortas convencional de um tópico
+func DLQTopic(subject string) string {
+	return subject + ".dlq"
+}
+
+// RetryTopic retorna o retry topic convencional de um tópico em um consumer group. O tópico é
+// próprio do grupo para que as reentregas não cheguem aos outros grupos.
+func RetryTopic(subject, groupID string) string {
+	return subject + ".retry." + groupID
+}
+
+// retryOriginalTopic é o header com o tópico original das mensagens do retry topic
+const retryOriginalTopic = "retry_original_topic"
+
+// kafkaSubscription é uma inscrição com consumer group e loop de consumo próprios
+type kafkaSubscription struct {
+	subject string
+	handler HeaderHandler
+	opts    KafkaSubscribeOptions
+	group   sarama.ConsumerGroup
+	cancel  context.CancelFunc
+	done    chan struct{}
+	paused  bool
+}
+
+// NewKafkaClient cria uma nova instância do cliente Kafka. groupID é o prefixo dos consumer
+// groups dedicados de cada inscrição.
 func NewKafkaClient(config *ConnectionConfig, groupID string) *KafkaClient {
 	ctx, cancel := context.WithCancel(context.Background())
 	return &KafkaClient{
 		config:   config,
 		groupID:  groupID,
+		newGroup: sarama.NewConsumerGroup,
 		status:   &ClientStatus{Connected: false},
-		handlers: make(map[string]MessageHandler),
+		subs:     make(map[string]*kafkaSubscription),
 		ctx:      ctx,
 		cancel:   cancel,
 	}
@@ -44,35 +110,60 @@ func (kc *KafkaClient) Connect(ctx context.Context) error {
 	config.Producer.RequiredAcks = sarama.WaitForAll
 	config.Consumer.Group.Rebalance.Strategy = sarama.BalanceStrategyRoundRobin
 	config.Consumer.Offsets.Initial = sarama.OffsetNewest
+	config.Consumer.Return.Errors = true
 
-	// Configura autenticação se necessário
-	if kc.config.Username != "" {
+	// Configura autenticação SASL se necessário
+	if sasl := kc.config.sasl(); sasl != nil {
 		config.Net.SASL.Enable = true
-		config.Net.SASL.User = kc.config.Username
-		config.Net.SASL.Password = kc.config.Password
+		config.Net.SASL.User = sasl.Username
+		config.Net.SASL.Password = sasl.Password
+
+		switch sasl.Mechanism {
+		case SASLPlain:
+			config.Net.SASL.Mechanism = sarama.SASLTypePlaintext
+		case SASLScramSHA256:
+			config.Net.SASL.Mechanism = sarama.SASLTypeSCRAMSHA256
+			config.Net.SASL.SCRAMClientGeneratorFunc = func() sarama.SCRAMClient {
+				return &scramClient{HashGeneratorFcn: scramSHA256}
+			}
+		case SASLScramSHA512:
+			config.Net.SASL.Mechanism = sarama.SASLTypeSCRAMSHA512
+			config.Net.SASL.SCRAMClientGeneratorFunc = func() sarama.SCRAMClient {
+				return &scramClient{HashGeneratorFcn: scramSHA512}
+			}
+		default:
+			return fmt.Errorf("mecanismo SASL não suportado pelo Kafka: %s", sasl.Mechanism)
+		}
 	}
 
 	// Configura TLS se necessário
-	if kc.config.TLS {
+	tlsConfig, err := kc.config.tlsConfig()
+	if err != nil {
+		return fmt.Errorf("erro ao configurar TLS do Kafka: %v", err)
+	}
+	if tlsConfig != nil {
 		config.Net.TLS.Enable = true
+		config.Net.TLS.Config = tlsConfig
 	}
 
-	// Cria o produtor
+	// Cria o cliente de metadados e o produtor; os consumer groups são criados a cada inscrição
 	brokers := []string{fmt.Sprintf("%s:%d", kc.config.Host, kc.config.Port)}
-	producer, err := sarama.NewSyncProducer(brokers, config)
+	client, err := sarama.NewClient(brokers, config)
 	if err != nil {
-		return fmt.Errorf("erro ao criar produtor Kafka: %v", err)
+		return fmt.Errorf("erro ao conectar ao Kafka: %v", err)
 	}
-	kc.producer = producer
-
-	// Cria o consumidor
-	consumer, err := sarama.NewConsumerGroup(brokers, kc.groupID, config)
+	producer, err := sarama.NewSyncProducerFromClient(client)
 	if err != nil {
-		kc.producer.Close()
-		return fmt.Errorf("erro ao criar consumidor Kafka: %v", err)
+		client.Close()
+		return fmt.Errorf("erro ao criar produtor Kafka: %v", err)
 	}
-	kc.consumer = consumer
 
+	kc.mu.Lock()
+	defer kc.mu.Unlock()
+	kc.client = client
+	kc.producer = producer
+	kc.brokers = brokers
+	kc.saramaCfg = config
 	kc.status.Connected = true
 	kc.status.LastConnection = time.Now().Unix()
 
@@ -82,129 +173,451 @@ func (kc *KafkaClient) Connect(ctx context.Context) error {
 // Disconnect fecha a conexão com o servidor Kafka
 func (kc *KafkaClient) Disconnect() error {
 	kc.mu.Lock()
-	defer kc.mu.Unlock()
+	subs := kc.subs
+	kc.subs = make(map[string]*kafkaSubscription)
+	kc.status.Subscriptions = 0
+	kc.mu.Unlock()
 
 	kc.cancel()
+	var failures []error
+	for _, sub := range subs {
+		if err := sub.stop(); err != nil {
+			failures = append(failures, err)
+		}
+	}
 	kc.consumeWait.Wait()
 
+	kc.mu.Lock()
+	defer kc.mu.Unlock()
 	if kc.producer != nil {
 		if err := kc.producer.Close(); err != nil {
-			return fmt.Errorf("erro ao fechar produtor Kafka: %v", err)
+			failures = append(failures, fmt.Errorf("erro ao fechar produtor Kafka: %v", err))
 		}
 	}
-
-	if kc.consumer != nil {
-		if err := kc.consumer.Close(); err != nil {
-			return fmt.Errorf("erro ao fechar consumidor Kafka: %v", err)
+	if kc.client != nil && !kc.client.Closed() {
+		if err := kc.client.Close(); err != nil {
+			failures = append(failures, fmt.Errorf("erro ao fechar cliente Kafka: %v", err))
 		}
 	}
-
 	kc.status.Connected = false
+
+	if len(failures) > 0 {
+		return fmt.Errorf("erros ao desconectar do Kafka: %v", failures)
+	}
 	return nil
 }
 
-// consumerHandler implementa a interface sarama.ConsumerGroupHandler
-type consumerHandler struct {
-	handlers map[string]MessageHandler
-	mu       sync.RWMutex
-	status   *ClientStatus
+// Subscribe registra um handler para receber mensagens de um tópico, com um consumer group
+// dedicado e commit automático de offsets
+func (kc *KafkaClient) Subscribe(subject string, handler MessageHandler) error {
+	return kc.SubscribeWithOptions(subject, handler, KafkaSubscribeOptions{})
 }
 
-func (h *consumerHandler) Setup(_ sarama.ConsumerGroupSession) error   { return nil }
-func (h *consumerHandler) Cleanup(_ sarama.ConsumerGroupSession) error { return nil }
+// SubscribeWithOptions registra um handler para um tópico ou padrão com grupo, commit de
+// offsets, novas tentativas e DLQ configuráveis
+func (kc *KafkaClient) SubscribeWithOptions(subject string, handler MessageHandler, opts KafkaSubscribeOptions) error {
+	return kc.subscribe(subject, withoutHeaders(handler), opts)
+}
 
-func (h *consumerHandler) ConsumeClaim(session sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
-	for message := range claim.Messages() {
-		h.mu.RLock()
-		handler, exists := h.handlers[message.Topic]
-		h.mu.RUnlock()
-
-		if exists && handler != nil {
-			if err := handler(session.Context(), message.Topic, message.Value); err != nil {
-				h.mu.Lock()
-				h.status.LastError = err.Error()
-				h.mu.Unlock()
+// SubscribeHeaders registra um handler que recebe também os headers das mensagens
+func (kc *KafkaClient) SubscribeHeaders(subject string, handler HeaderHandler) error {
+	return kc.subscribe(subject, handler, KafkaSubscribeOptions{})
+}
+
+// subscribe cria a inscrição com o consumer group dedicado
+func (kc *KafkaClient) subscribe(subject string, handler HeaderHandler, opts KafkaSubscribeOptions) error {
+	if err := ValidateSubject(subject); err != nil {
+		return err
+	}
+	if handler == nil {
+		return errs.New(errs.ErrValidation, "kafka.Subscribe", "handler nulo para o tópico %s", subject)
+	}
+
+	kc.mu.Lock()
+	defer kc.mu.Unlock()
+
+	if kc.saramaCfg == nil {
+		return fmt.Errorf("cliente Kafka não conectado")
+	}
+	if _, exists := kc.subs[subject]; exists {
+		return fmt.Errorf("já existe um handler para o tópico %s", subject)
+	}
+
+	if opts.GroupID == "" {
+		opts.GroupID = kc.groupID + "." + subject
+	}
+	if opts.MaxRetries > 0 && opts.RetryBackoff <= 0 {
+		opts.RetryBackoff = 500 * time.Millisecond
+	}
+	if opts.TopicRefresh <= 0 {
+		opts.TopicRefresh = DefaultTopicRefresh
+	}
+
+	// Cada inscrição usa uma cópia da configuração para controlar o próprio commit
+	config := *kc.saramaCfg
+	config.Consumer.Offsets.AutoCommit.Enable = !opts.ManualCommit
+	group, err := kc.newGroup(kc.brokers, opts.GroupID, &config)
+	if err != nil {
+		return fmt.Errorf("erro ao criar consumidor Kafka para o tópico %s: %v", subject, err)
+	}
+
+	ctx, cancel := context.WithCancel(kc.ctx)
+	sub := &kafkaSubscription{
+		subject: subject,
+		handler: handler,
+		opts:    opts,
+		group:   group,
+		cancel:  cancel,
+		done:    make(chan struct{}),
+	}
+	kc.subs[subject] = sub
+	kc.status.Subscriptions++
+
+	kc.consumeWait.Add(2)
+	go kc.consume(ctx, sub)
+	go kc.watchErrors(sub)
+
+	return nil
+}
+
+// consume mantém o loop de consumo da inscrição: Consume retorna a cada rebalanceamento
+// do grupo e é chamado novamente até a inscrição ser encerrada. Nas inscrições por padrão
+// o consumo também recomeça quando o conjunto de tópicos que casam com ele muda.
+func (kc *KafkaClient) consume(ctx context.Context, sub *kafkaSubscription) {
+	defer kc.consumeWait.Done()
+	defer close(sub.done)
+
+	h := &consumerHandler{client: kc, sub: sub}
+	for ctx.Err() == nil {
+		topics, err := kc.topics(sub.subject)
+		if err == nil && len(topics) == 0 {
+			// Nenhum tópico casa com o padrão ainda
+			if !sleep(ctx, sub.opts.TopicRefresh) {
+				return
 			}
+			continue
 		}
 
-		h.mu.Lock()
-		h.status.BytesReceived += int64(len(message.Value))
-		h.mu.Unlock()
+		consumeCtx, stop := context.WithCancel(ctx)
+		if err == nil && IsPattern(sub.subject) {
+			go kc.watchTopics(consumeCtx, sub, topics, stop)
+		}
+		if err == nil {
+			if sub.opts.RetryTopic != "" {
+				topics = append(topics[:len(topics):len(topics)], sub.opts.RetryTopic)
+			}
+			err = sub.group.Consume(consumeCtx, topics, h)
+			if consumeCtx.Err() != nil {
+				// Sessão encerrada de propósito (novos tópicos ou fim da inscrição)
+				err = nil
+			}
+		}
+		stop()
 
-		session.MarkMessage(message, "")
+		if err != nil {
+			if errors.Is(err, sarama.ErrClosedConsumerGroup) {
+				return
+			}
+			kc.setError("connection", sub.subject, err)
+			if !sleep(ctx, time.Second) { // Espera antes de tentar novamente
+				return
+			}
+		}
+	}
+}
+
+// topics retorna os tópicos consumidos pela inscrição: o próprio tópico ou os tópicos
+// existentes que casam com o padrão
+func (kc *KafkaClient) topics(subject string) ([]string, error) {
+	if !IsPattern(subject) {
+		return []string{subject}, nil
+	}
+	if err := kc.client.RefreshMetadata(); err != nil {
+		return nil, fmt.Errorf("erro ao atualizar os tópicos do Kafka: %v", err)
+	}
+	topics, err := kc.client.Topics()
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar os tópicos do Kafka: %v", err)
+	}
+	return matchingTopics(subject, topics), nil
+}
+
+// watchTopics encerra a sessão de consumo (stop) quando os tópicos que casam com o padrão
+// da inscrição deixam de ser os consumidos
+func (kc *KafkaClient) watchTopics(ctx context.Context, sub *kafkaSubscription, current []string, stop context.CancelFunc) {
+	ticker := time.NewTicker(sub.opts.TopicRefresh)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			topics, err := kc.topics(sub.subject)
+			if err != nil {
+				kc.setError("connection", sub.subject, err)
+				continue
+			}
+			if strings.Join(topics, ",") != strings.Join(current, ",") {
+				stop()
+				return
+			}
+		}
+	}
+}
+
+// sleep espera o intervalo, retornando false se o contexto for cancelado antes
+func sleep(ctx context.Context, d time.Duration) bool {
+	select {
+	case <-ctx.Done():
+		return false
+	case <-time.After(d):
+		return true
+	}
+}
+
+// watchErrors registra no status os erros assíncronos do consumer group
+func (kc *KafkaClient) watchErrors(sub *kafkaSubscription) {
+	defer kc.consumeWait.Done()
+	for err := range sub.group.Errors() {
+		kc.setError("connection", sub.subject, err)
+	}
+}
+
+// stop encerra o loop de consumo e fecha o consumer group da inscrição
+func (sub *kafkaSubscription) stop() error {
+	sub.cancel()
+	<-sub.done
+	if err := sub.group.Close(); err != nil {
+		return fmt.Errorf("erro ao fechar consumidor Kafka do tópico %s: %v", sub.subject, err)
 	}
 	return nil
 }
 
-// Subscribe registra um handler para receber mensagens de um tópico
-func (kc *KafkaClient) Subscribe(subject string, handler MessageHandler) error {
+// setError registra o erro no status
+func (kc *KafkaClient) setError(op, subject string, err error) {
 	kc.mu.Lock()
 	defer kc.mu.Unlock()
+	kc.status.recordError(op, subject, err)
+}
 
-	if _, exists := kc.handlers[subject]; exists {
-		return fmt.Errorf("já existe um handler para o tópico %s", subject)
+// consumerHandler implementa a interface sarama.ConsumerGroupHandler para uma inscrição
+type consumerHandler struct {
+	client *KafkaClient
+	sub    *kafkaSubscription
+}
+
+func (h *consumerHandler) Setup(_ sarama.ConsumerGroupSession) error   { return nil }
+func (h *consumerHandler) Cleanup(_ sarama.ConsumerGroupSession) error { return nil }
+
+// ConsumeClaim processa as mensagens de uma partição. A mensagem é marcada como consumida
+// depois de processada ou enviada à DLQ; se a DLQ falhar, a sessão é encerrada sem marcá-la
+// e a mensagem é reentregue a partir do último offset confirmado.
+func (h *consumerHandler) ConsumeClaim(session sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
+	kc, sub := h.client, h.sub
+	for {
+		select {
+		case <-session.Context().Done():
+			return nil
+		case message, ok := <-claim.Messages():
+			if !ok {
+				return nil
+			}
+
+			start := time.Now()
+			err := kc.handle(session.Context(), sub, message)
+			if err != nil && session.Context().Err() != nil {
+				// Sessão encerrada durante uma espera: a mensagem não é marcada e volta a ser entregue
+				return nil
+			}
+			kc.mu.Lock()
+			kc.status.recordConsume(message.Topic, len(message.Value), start, err)
+			kc.mu.Unlock()
+
+			if err != nil && sub.opts.DLQTopic != "" {
+				if err := kc.deadLetter(sub, message, err); err != nil {
+					kc.setError("consume", message.Topic, err)
+					return err
+				}
+			}
+
+			session.MarkMessage(message, "")
+			if sub.opts.ManualCommit {
+				session.Commit()
+			}
+		}
 	}
+}
 
-	kc.handlers[subject] = handler
-	kc.status.Subscriptions++
+// handle executa o handler da inscrição com as opções de entrega da mensagem: a partição
+// espera até o horário de entrega, a mensagem expirada é descartada e as reentregas vão para
+// o RetryTopic da inscrição. Sem opções de entrega, valem as novas tentativas configuradas.
+func (kc *KafkaClient) handle(ctx context.Context, sub *kafkaSubscription, message *sarama.ConsumerMessage) error {
+	headers := kafkaHeaders(message.Headers)
+	subject := message.Topic
+	if original, ok := headers[retryOriginalTopic]; ok {
+		subject = original
+	}
+	receive := func(ctx context.Context, subject string, data []byte) error {
+		return sub.handler(ctx, subject, data, headers)
+	}
+	once := func() error {
+		return safeHandle(ctx, "kafka", receive, subject, message.Value)
+	}
 
-	// Inicia uma goroutine para consumir mensagens
-	kc.consumeWait.Add(1)
-	go func() {
-		defer kc.consumeWait.Done()
-		h := &consumerHandler{
-			handlers: kc.handlers,
-			status:   kc.status,
+	d := parseDelivery(headers)
+	if wait := time.Until(d.deliverAt); wait > 0 && !d.expired(time.Now()) && !sleep(ctx, wait) {
+		return ctx.Err()
+	}
+	if d.expired(time.Now()) {
+		return nil
+	}
+	if d.maxRedeliveries > 0 {
+		if sub.opts.RetryTopic == "" {
+			return deliver(ctx, d, once)
+		}
+		if err := once(); err == nil || !d.canRedeliver() {
+			return err
 		}
+		return kc.retry(sub, message, subject, d, headers)
+	}
 
-		for {
+	var err error
+	for attempt := 0; attempt <= sub.opts.MaxRetries; attempt++ {
+		if attempt > 0 {
 			select {
-			case <-kc.ctx.Done():
-				return
-			default:
-				if err := kc.consumer.Consume(kc.ctx, []string{subject}, h); err != nil {
-					kc.mu.Lock()
-					kc.status.LastError = err.Error()
-					kc.mu.Unlock()
-					time.Sleep(time.Second) // Espera antes de tentar novamente
-				}
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(sub.opts.RetryBackoff):
 			}
 		}
-	}()
+		if err = once(); err == nil {
+			return nil
+		}
+	}
+	return err
+}
 
+// retry publica no RetryTopic da inscrição a próxima reentrega de uma mensagem cujo handler falhou
+func (kc *KafkaClient) retry(sub *kafkaSubscription, message *sarama.ConsumerMessage, subject string, d delivery, headers map[string]string) error {
+	next := d.next(time.Now(), headers)
+	next[retryOriginalTopic] = subject
+	_, _, err := kc.producer.SendMessage(&sarama.ProducerMessage{
+		Topic:   sub.opts.RetryTopic,
+		Key:     sarama.ByteEncoder(message.Key),
+		Value:   sarama.ByteEncoder(message.Value),
+		Headers: recordHeaders(next),
+	})
+	if err != nil {
+		return fmt.Errorf("erro ao enviar reentrega ao tópico %s: %v", sub.opts.RetryTopic, err)
+	}
 	return nil
 }
 
-// Unsubscribe remove a inscrição de um tópico
-func (kc *KafkaClient) Unsubscribe(subject string) error {
+// deadLetter publica na DLQ da inscrição uma mensagem cujo handler falhou, preservando os
+// headers originais e acrescentando a origem e o erro
+func (kc *KafkaClient) deadLetter(sub *kafkaSubscription, message *sarama.ConsumerMessage, cause error) error {
+	headers := make([]sarama.RecordHeader, 0, len(message.Headers)+4)
+	for _, header := range message.Headers {
+		if header != nil {
+			headers = append(headers, *header)
+		}
+	}
+	headers = append(headers,
+		sarama.RecordHeader{Key: []byte("dlq_original_topic"), Value: []byte(message.Topic)},
+		sarama.RecordHeader{Key: []byte("dlq_original_partition"), Value: []byte(strconv.Itoa(int(message.Partition)))},
+		sarama.RecordHeader{Key: []byte("dlq_original_offset"), Value: []byte(strconv.FormatInt(message.Offset, 10))},
+		sarama.RecordHeader{Key: []byte("dlq_error"), Value: []byte(cause.Error())},
+	)
+
+	_, _, err := kc.producer.SendMessage(&sarama.ProducerMessage{
+		Topic:   sub.opts.DLQTopic,
+		Key:     sarama.ByteEncoder(message.Key),
+		Value:   sarama.ByteEncoder(message.Value),
+		Headers: headers,
+	})
+	if err != nil {
+		return fmt.Errorf("erro ao enviar mensagem do tópico %s para a DLQ %s: %v", message.Topic, sub.opts.DLQTopic, err)
+	}
+	return nil
+}
+
+// Pause suspende o consumo do tópico sem deixar o consumer group; as mensagens acumulam
+// no broker até Resume
+func (kc *KafkaClient) Pause(subject string) error {
 	kc.mu.Lock()
 	defer kc.mu.Unlock()
 
-	if _, exists := kc.handlers[subject]; !exists {
-		return fmt.Errorf("não existe handler para o tópico %s", subject)
+	sub, exists := kc.subs[subject]
+	if !exists {
+		return errs.New(errs.ErrNotFound, "kafka.Pause", "não existe handler para o tópico %s", subject)
 	}
+	sub.group.PauseAll()
+	sub.paused = true
+	return nil
+}
 
-	delete(kc.handlers, subject)
-	kc.status.Subscriptions--
+// Resume retoma o consumo de um tópico pausado
+func (kc *KafkaClient) Resume(subject string) error {
+	kc.mu.Lock()
+	defer kc.mu.Unlock()
 
+	sub, exists := kc.subs[subject]
+	if !exists {
+		return errs.New(errs.ErrNotFound, "kafka.Resume", "não existe handler para o tópico %s", subject)
+	}
+	sub.group.ResumeAll()
+	sub.paused = false
 	return nil
 }
 
+// Paused informa se o consumo do tópico está pausado
+func (kc *KafkaClient) Paused(subject string) bool {
+	kc.mu.RLock()
+	defer kc.mu.RUnlock()
+	sub, exists := kc.subs[subject]
+	return exists && sub.paused
+}
+
+// Unsubscribe remove a inscrição de um tópico, encerrando o consumo e o consumer group
+func (kc *KafkaClient) Unsubscribe(subject string) error {
+	kc.mu.Lock()
+	sub, exists := kc.subs[subject]
+	if !exists {
+		kc.mu.Unlock()
+		return errs.New(errs.ErrNotFound, "kafka.Unsubscribe", "não existe handler para o tópico %s", subject)
+	}
+	delete(kc.subs, subject)
+	kc.status.Subscriptions--
+	kc.mu.Unlock()
+
+	return sub.stop()
+}
+
 // Publish envia uma mensagem para um tópico
 func (kc *KafkaClient) Publish(ctx context.Context, subject string, data []byte) error {
+	return kc.PublishHeaders(ctx, subject, data, nil)
+}
+
+// PublishHeaders envia uma mensagem com headers de registro para um tópico
+func (kc *KafkaClient) PublishHeaders(ctx context.Context, subject string, data []byte, headers map[string]string) error {
 	msg := &sarama.ProducerMessage{
-		Topic: subject,
-		Value: sarama.ByteEncoder(data),
+		Topic:   subject,
+		Headers: recordHeaders(headers),
+		Value:   sarama.ByteEncoder(data),
 	}
 
+	start := time.Now()
 	partition, offset, err := kc.producer.SendMessage(msg)
 	if err != nil {
-		return fmt.Errorf("erro ao publicar mensagem no tópico %s: %v", subject, err)
+		err = fmt.Errorf("erro ao publicar mensagem no tópico %s: %v", subject, err)
 	}
 
 	kc.mu.Lock()
-	kc.status.BytesSent += int64(len(data))
+	kc.status.recordPublish(subject, len(data), start, err)
 	kc.mu.Unlock()
+	if err != nil {
+		return err
+	}
 
 	// Log opcional para debug
 	fmt.Printf("Mensagem enviada para tópico=%s partition=%d offset=%d\n",
@@ -213,67 +626,107 @@ func (kc *KafkaClient) Publish(ctx context.Context, subject string, data []byte)
 	return nil
 }
 
+// PublishWithOptions publica a mensagem com as opções de entrega nos headers. O Kafka não
+// adia nem expira mensagens: a inscrição segura a partição até o horário de entrega, descarta
+// as expiradas e faz as reentregas pelo RetryTopic.
+func (kc *KafkaClient) PublishWithOptions(ctx context.Context, subject string, data []byte, opts DeliveryOptions) error {
+	return kc.PublishHeaders(ctx, subject, data, opts.headers(time.Now(), nil))
+}
+
 // Request envia uma mensagem e aguarda resposta
 // Nota: Kafka não tem suporte nativo para request/reply, então implementamos usando tópicos temporários
 func (kc *KafkaClient) Request(ctx context.Context, subject string, data []byte, timeout int) ([]byte, error) {
+	response, _, err := kc.RequestHeaders(ctx, subject, data, nil, timeout)
+	return response, err
+}
+
+// kafkaReply é uma resposta recebida no tópico temporário de um request
+type kafkaReply struct {
+	data    []byte
+	headers map[string]string
+}
+
+// RequestHeaders envia uma mensagem com headers e aguarda a resposta com os headers dela
+func (kc *KafkaClient) RequestHeaders(ctx context.Context, subject string, data []byte, headers map[string]string, timeout int) ([]byte, map[string]string, error) {
+	start := time.Now()
+	response, replyHeaders, err := kc.request(ctx, subject, data, headers, timeout)
+
+	kc.mu.Lock()
+	kc.status.recordRequest(subject, len(data), len(response), start, err)
+	kc.mu.Unlock()
+
+	return response, replyHeaders, err
+}
+
+// request publica a requisição com o tópico de resposta e aguarda a resposta
+func (kc *KafkaClient) request(ctx context.Context, subject string, data []byte, headers map[string]string, timeout int) ([]byte, map[string]string, error) {
 	// Cria um tópico temporário para a resposta
 	replyTopic := fmt.Sprintf("%s.reply.%d", subject, time.Now().UnixNano())
-	responseChan := make(chan []byte, 1)
-	errorChan := make(chan error, 1)
+	responseChan := make(chan kafkaReply, 1)
 
 	// Inscreve-se no tópico de resposta
-	handler := func(ctx context.Context, subject string, data []byte) error {
+	handler := func(ctx context.Context, subject string, data []byte, headers map[string]string) error {
 		select {
-		case responseChan <- data:
+		case responseChan <- kafkaReply{data: data, headers: headers}:
 		default:
 		}
 		return nil
 	}
 
-	if err := kc.Subscribe(replyTopic, handler); err != nil {
-		return nil, fmt.Errorf("erro ao se inscrever no tópico de resposta: %v", err)
+	if err := kc.SubscribeHeaders(replyTopic, handler); err != nil {
+		return nil, nil, fmt.Errorf("erro ao se inscrever no tópico de resposta: %v", err)
 	}
 	defer kc.Unsubscribe(replyTopic)
 
 	// Adiciona o tópico de resposta à mensagem
 	requestMsg := &sarama.ProducerMessage{
 		Topic: subject,
-		Headers: []sarama.RecordHeader{
-			{
-				Key:   []byte("reply_to"),
-				Value: []byte(replyTopic),
-			},
-		},
+		Headers: append(recordHeaders(headers), sarama.RecordHeader{
+			Key:   []byte("reply_to"),
+			Value: []byte(replyTopic),
+		}),
 		Value: sarama.ByteEncoder(data),
 	}
 
 	// Envia a requisição
 	if _, _, err := kc.producer.SendMessage(requestMsg); err != nil {
-		return nil, fmt.Errorf("erro ao enviar requisição: %v", err)
+		return nil, nil, fmt.Errorf("erro ao enviar requisição: %v", err)
 	}
 
-	kc.mu.Lock()
-	kc.status.BytesSent += int64(len(data))
-	kc.mu.Unlock()
-
 	// Aguarda a resposta com timeout
 	select {
 	case response := <-responseChan:
-		return response, nil
-	case err := <-errorChan:
-		return nil, err
+		return response.data, response.headers, nil
 	case <-time.After(time.Duration(timeout) * time.Millisecond):
-		return nil, fmt.Errorf("timeout ao aguardar resposta do tópico %s", subject)
+		return nil, nil, errs.New(errs.ErrTimeout, "kafka.Request", "timeout ao aguardar resposta do tópico %s", subject)
 	case <-ctx.Done():
-		return nil, ctx.Err()
+		return nil, nil, ctx.Err()
+	}
+}
+
+// recordHeaders converte headers em headers de registro do Kafka
+func recordHeaders(headers map[string]string) []sarama.RecordHeader {
+	records := make([]sarama.RecordHeader, 0, len(headers))
+	for key, value := range headers {
+		records = append(records, sarama.RecordHeader{Key: []byte(key), Value: []byte(value)})
+	}
+	return records
+}
+
+// kafkaHeaders converte os headers de registro, com as chaves em minúsculas
+func kafkaHeaders(records []*sarama.RecordHeader) map[string]string {
+	headers := make(map[string]string, len(records))
+	for _, record := range records {
+		headers[strings.ToLower(string(record.Key))] = string(record.Value)
 	}
+	return headers
 }
 
-// GetStatus retorna o estado atual do cliente
+// GetStatus retorna uma cópia do estado atual do cliente
 func (kc *KafkaClient) GetStatus() *ClientStatus {
 	kc.mu.RLock()
 	defer kc.mu.RUnlock()
-	return kc.status
+	return kc.status.snapshot()
 }
 
 // GetSubscriptions retorna a lista de inscrições ativas
@@ -281,8 +734,8 @@ func (kc *KafkaClient) GetSubscriptions() []string {
 	kc.mu.RLock()
 	defer kc.mu.RUnlock()
 
-	subs := make([]string, 0, len(kc.handlers))
-	for subject := range kc.handlers {
+	subs := make([]string, 0, len(kc.subs))
+	for subject := range kc.subs {
 		subs = append(subs, subject)
 	}
 	return subs
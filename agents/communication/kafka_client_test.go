@@ -0,0 +1,366 @@
+package communication
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"github.com/Shopify/sarama/mocks"
+
+	"github.com/suissa/HiveMind/agents/errs"
+)
+
+// fakeGroup é um consumer group em memória: Consume entrega as mensagens de messages em uma
+// única partição e registra os offsets marcados e os commits da sessão
+type fakeGroup struct {
+	config   *sarama.Config
+	messages chan *sarama.ConsumerMessage
+	errors   chan error
+
+	mu      sync.Mutex
+	marked  []int64
+	commits int
+	paused  bool
+	closed  bool
+}
+
+func newFakeGroup(config *sarama.Config) *fakeGroup {
+	return &fakeGroup{
+		config:   config,
+		messages: make(chan *sarama.ConsumerMessage, 10),
+		errors:   make(chan error),
+	}
+}
+
+func (g *fakeGroup) Consume(ctx context.Context, topics []string, handler sarama.ConsumerGroupHandler) error {
+	session := &fakeSession{group: g, ctx: ctx}
+	if err := handler.Setup(session); err != nil {
+		return err
+	}
+	defer handler.Cleanup(session)
+	return handler.ConsumeClaim(session, &fakeClaim{topic: topics[0], messages: g.messages})
+}
+
+func (g *fakeGroup) Errors() <-chan error { return g.errors }
+
+func (g *fakeGroup) Close() error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if !g.closed {
+		g.closed = true
+		close(g.errors)
+	}
+	return nil
+}
+
+func (g *fakeGroup) Pause(partitions map[string][]int32)  {}
+func (g *fakeGroup) Resume(partitions map[string][]int32) {}
+
+func (g *fakeGroup) PauseAll() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.paused = true
+}
+
+func (g *fakeGroup) ResumeAll() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.paused = false
+}
+
+// state retorna os offsets marcados, os commits e se o grupo está pausado ou fechado
+func (g *fakeGroup) state() (marked []int64, commits int, paused, closed bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return append([]int64(nil), g.marked...), g.commits, g.paused, g.closed
+}
+
+// fakeSession é a sessão do fakeGroup
+type fakeSession struct {
+	group *fakeGroup
+	ctx   context.Context
+}
+
+func (s *fakeSession) Claims() map[string][]int32                                        { return nil }
+func (s *fakeSession) MemberID() string                                                  { return "member-1" }
+func (s *fakeSession) GenerationID() int32                                               { return 1 }
+func (s *fakeSession) MarkOffset(topic string, partition int32, offset int64, m string)  {}
+func (s *fakeSession) ResetOffset(topic string, partition int32, offset int64, m string) {}
+func (s *fakeSession) Context() context.Context                                          { return s.ctx }
+
+func (s *fakeSession) MarkMessage(msg *sarama.ConsumerMessage, metadata string) {
+	s.group.mu.Lock()
+	defer s.group.mu.Unlock()
+	s.group.marked = append(s.group.marked, msg.Offset)
+}
+
+func (s *fakeSession) Commit() {
+	s.group.mu.Lock()
+	defer s.group.mu.Unlock()
+	s.group.commits++
+}
+
+// fakeClaim é a partição única entregue pelo fakeGroup
+type fakeClaim struct {
+	topic    string
+	messages chan *sarama.ConsumerMessage
+}
+
+func (c *fakeClaim) Topic() string                            { return c.topic }
+func (c *fakeClaim) Partition() int32                         { return 0 }
+func (c *fakeClaim) InitialOffset() int64                     { return 0 }
+func (c *fakeClaim) HighWaterMarkOffset() int64               { return 0 }
+func (c *fakeClaim) Messages() <-chan *sarama.ConsumerMessage { return c.messages }
+
+// newFakeKafkaClient cria um cliente conectado ao produtor informado, com os consumer groups
+// em memória registrados em groups pelo ID
+func newFakeKafkaClient(producer sarama.SyncProducer) (*KafkaClient, map[string]*fakeGroup) {
+	kc := NewKafkaClient(&ConnectionConfig{Host: "localhost", Port: 9092}, "hivemind")
+	kc.saramaCfg = sarama.NewConfig()
+	kc.producer = producer
+	kc.brokers = []string{"localhost:9092"}
+
+	groups := make(map[string]*fakeGroup)
+	kc.newGroup = func(brokers []string, groupID string, config *sarama.Config) (sarama.ConsumerGroup, error) {
+		group := newFakeGroup(config)
+		groups[groupID] = group
+		return group, nil
+	}
+	return kc, groups
+}
+
+func TestKafkaSubscriptionGroups(t *testing.T) {
+	kc, groups := newFakeKafkaClient(mocks.NewSyncProducer(t, nil))
+	defer kc.Disconnect()
+	noop := func(ctx context.Context, subject string, data []byte) error { return nil }
+
+	tests := []struct {
+		subject    string
+		opts       KafkaSubscribeOptions
+		group      string
+		autoCommit bool
+	}{
+		{"tasks.new", KafkaSubscribeOptions{}, "hivemind.tasks.new", true},
+		{"metrics.cpu", KafkaSubscribeOptions{ManualCommit: true}, "hivemind.metrics.cpu", false},
+		{"events", KafkaSubscribeOptions{GroupID: "auditoria"}, "auditoria", true},
+	}
+	for _, tt := range tests {
+		if err := kc.SubscribeWithOptions(tt.subject, noop, tt.opts); err != nil {
+			t.Fatal(err)
+		}
+		group, ok := groups[tt.group]
+		if !ok {
+			t.Fatalf("%s: esperava o consumer group %s, obtidos %v", tt.subject, tt.group, groups)
+		}
+		if group.config.Consumer.Offsets.AutoCommit.Enable != tt.autoCommit {
+			t.Errorf("%s: commit automático %v, esperado %v", tt.subject, group.config.Consumer.Offsets.AutoCommit.Enable, tt.autoCommit)
+		}
+	}
+	// A configuração do cliente não é alterada pelas inscrições
+	if !kc.saramaCfg.Consumer.Offsets.AutoCommit.Enable {
+		t.Fatal("o commit manual de uma inscrição não deveria valer para o cliente")
+	}
+
+	if err := kc.Subscribe("tasks.new", noop); err == nil {
+		t.Fatal("esperava o erro da inscrição duplicada")
+	}
+	if err := kc.Subscribe("tasks.*", nil); !errors.Is(err, errs.ErrValidation) {
+		t.Fatalf("esperava ErrValidation para o handler nulo: %v", err)
+	}
+
+	// Encerrar uma inscrição fecha só o grupo dela
+	if err := kc.Unsubscribe("metrics.cpu"); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, _, closed := groups["hivemind.metrics.cpu"].state(); !closed {
+		t.Fatal("o grupo da inscrição encerrada deveria ser fechado")
+	}
+	if _, _, _, closed := groups["hivemind.tasks.new"].state(); closed {
+		t.Fatal("os grupos das outras inscrições deveriam continuar abertos")
+	}
+	if status := kc.GetStatus(); status.Subscriptions != 2 {
+		t.Fatalf("esperava duas inscrições, obtidas %d", status.Subscriptions)
+	}
+	if err := kc.Unsubscribe("metrics.cpu"); !errors.Is(err, errs.ErrNotFound) {
+		t.Fatalf("esperava ErrNotFound: %v", err)
+	}
+}
+
+func TestKafkaOffsetCommit(t *testing.T) {
+	tests := []struct {
+		name    string
+		manual  bool
+		commits int
+	}{
+		{"Commit automático", false, 0},
+		{"Commit manual", true, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kc, groups := newFakeKafkaClient(mocks.NewSyncProducer(t, nil))
+			defer kc.Disconnect()
+
+			handled := make(chan string, 2)
+			handler := func(ctx context.Context, subject string, data []byte) error {
+				handled <- string(data)
+				return nil
+			}
+			if err := kc.SubscribeWithOptions("tasks.new", handler, KafkaSubscribeOptions{ManualCommit: tt.manual}); err != nil {
+				t.Fatal(err)
+			}
+			group := groups["hivemind.tasks.new"]
+			group.messages <- &sarama.ConsumerMessage{Topic: "tasks.new", Offset: 7, Value: []byte("t-1")}
+			group.messages <- &sarama.ConsumerMessage{Topic: "tasks.new", Offset: 8, Value: []byte("t-2")}
+
+			for _, want := range []string{"t-1", "t-2"} {
+				if got := <-handled; got != want {
+					t.Fatalf("mensagem %q, esperada %q", got, want)
+				}
+			}
+			waitFor(t, func() bool {
+				marked, _, _, _ := group.state()
+				return len(marked) == 2
+			})
+			marked, commits, _, _ := group.state()
+			if marked[0] != 7 || marked[1] != 8 || commits != tt.commits {
+				t.Fatalf("offsets %v e %d commits, esperados [7 8] e %d", marked, commits, tt.commits)
+			}
+		})
+	}
+}
+
+func TestKafkaPauseResume(t *testing.T) {
+	kc, groups := newFakeKafkaClient(mocks.NewSyncProducer(t, nil))
+	defer kc.Disconnect()
+	if err := kc.Subscribe("tasks.new", func(ctx context.Context, subject string, data []byte) error { return nil }); err != nil {
+		t.Fatal(err)
+	}
+	group := groups["hivemind.tasks.new"]
+
+	tests := []struct {
+		name   string
+		action func(subject string) error
+		paused bool
+	}{
+		{"Pausa", kc.Pause, true},
+		{"Pausa repetida", kc.Pause, true},
+		{"Retomada", kc.Resume, false},
+	}
+	for _, tt := range tests {
+		if err := tt.action("tasks.new"); err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if _, _, paused, _ := group.state(); paused != tt.paused || kc.Paused("tasks.new") != tt.paused {
+			t.Fatalf("%s: pausado=%v, esperado %v", tt.name, paused, tt.paused)
+		}
+	}
+
+	for name, action := range map[string]func(string) error{"Pause": kc.Pause, "Resume": kc.Resume} {
+		if err := action("ausente"); !errors.Is(err, errs.ErrNotFound) {
+			t.Errorf("%s de um tópico sem inscrição: esperava ErrNotFound, obtido %v", name, err)
+		}
+	}
+	if kc.Paused("ausente") {
+		t.Error("um tópico sem inscrição não está pausado")
+	}
+}
+
+func TestKafkaDeadLetterHeaders(t *testing.T) {
+	producer := mocks.NewSyncProducer(t, nil)
+	producer.ExpectSendMessageWithMessageCheckerFunctionAndSucceed(func(msg *sarama.ProducerMessage) error {
+		if msg.Topic != "tasks.new.dlq" {
+			return fmt.Errorf("tópico %s, esperado tasks.new.dlq", msg.Topic)
+		}
+		key, _ := msg.Key.Encode()
+		value, _ := msg.Value.Encode()
+		if string(key) != "k-1" || string(value) != "t-1" {
+			return fmt.Errorf("chave %q e valor %q inesperados", key, value)
+		}
+		headers := make(map[string]string)
+		for _, h := range msg.Headers {
+			headers[string(h.Key)] = string(h.Value)
+		}
+		want := map[string]string{
+			"trace_id":               "abc",
+			"dlq_original_topic":     "tasks.new",
+			"dlq_original_partition": "3",
+			"dlq_original_offset":    "42",
+			"dlq_error":              "falha no handler",
+		}
+		for key, value := range want {
+			if headers[key] != value {
+				return fmt.Errorf("header %s = %q, esperado %q (%v)", key, headers[key], value, headers)
+			}
+		}
+		return nil
+	})
+	kc, groups := newFakeKafkaClient(producer)
+
+	var mu sync.Mutex
+	attempts := 0
+	handler := func(ctx context.Context, subject string, data []byte) error {
+		mu.Lock()
+		defer mu.Unlock()
+		attempts++
+		return errors.New("falha no handler")
+	}
+	opts := KafkaSubscribeOptions{MaxRetries: 1, RetryBackoff: time.Millisecond, DLQTopic: DLQTopic("tasks.new")}
+	if err := kc.SubscribeWithOptions("tasks.new", handler, opts); err != nil {
+		t.Fatal(err)
+	}
+	group := groups["hivemind.tasks.new"]
+	group.messages <- &sarama.ConsumerMessage{
+		Topic:     "tasks.new",
+		Partition: 3,
+		Offset:    42,
+		Key:       []byte("k-1"),
+		Value:     []byte("t-1"),
+		Headers:   []*sarama.RecordHeader{{Key: []byte("trace_id"), Value: []byte("abc")}},
+	}
+
+	// A mensagem enviada à DLQ é marcada como consumida
+	waitFor(t, func() bool {
+		marked, _, _, _ := group.state()
+		return len(marked) == 1
+	})
+	mu.Lock()
+	if attempts != 2 {
+		t.Errorf("esperava a tentativa original e uma nova, obtidas %d", attempts)
+	}
+	mu.Unlock()
+	// O mock verifica no fechamento que a mensagem chegou à DLQ
+	if err := kc.Disconnect(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestKafkaDeadLetterFailureKeepsOffset(t *testing.T) {
+	producer := mocks.NewSyncProducer(t, nil)
+	producer.ExpectSendMessageAndFail(sarama.ErrOutOfBrokers)
+	kc, groups := newFakeKafkaClient(producer)
+	defer kc.Disconnect()
+
+	handler := func(ctx context.Context, subject string, data []byte) error { return errors.New("falha no handler") }
+	if err := kc.SubscribeWithOptions("tasks.new", handler, KafkaSubscribeOptions{DLQTopic: DLQTopic("tasks.new")}); err != nil {
+		t.Fatal(err)
+	}
+	group := groups["hivemind.tasks.new"]
+	group.messages <- &sarama.ConsumerMessage{Topic: "tasks.new", Offset: 5, Value: []byte("t-1")}
+
+	// Sem a DLQ, a sessão termina sem marcar a mensagem, que volta a ser entregue
+	waitFor(t, func() bool {
+		for _, e := range kc.GetStatus().RecentErrors {
+			if e.Op == "consume" {
+				return true
+			}
+		}
+		return false
+	})
+	if marked, _, _, _ := group.state(); len(marked) != 0 {
+		t.Fatalf("a mensagem não deveria ser marcada: %v", marked)
+	}
+}
@@ -0,0 +1,176 @@
+package communication
+
+import (
+	"math"
+	"time"
+)
+
+// LatencyBuckets são os limites superiores dos buckets dos histogramas de latência
+var LatencyBuckets = []time.Duration{
+	time.Millisecond,
+	5 * time.Millisecond,
+	10 * time.Millisecond,
+	25 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	250 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+	2500 * time.Millisecond,
+	5 * time.Second,
+	10 * time.Second,
+}
+
+// RecentErrorsSize é o número de erros guardados em ClientStatus.RecentErrors
+const RecentErrorsSize = 20
+
+// LatencyHistogram conta as durações observadas por bucket de LatencyBuckets
+type LatencyHistogram struct {
+	// Counts tem um contador por bucket, mais um para as durações acima do maior limite
+	Counts []int64       `json:"counts"`
+	Count  int64         `json:"count"`
+	Sum    time.Duration `json:"sum"`
+	Max    time.Duration `json:"max"`
+}
+
+// Observe registra uma duração
+func (h *LatencyHistogram) Observe(d time.Duration) {
+	if h.Counts == nil {
+		h.Counts = make([]int64, len(LatencyBuckets)+1)
+	}
+	i := 0
+	for i < len(LatencyBuckets) && d > LatencyBuckets[i] {
+		i++
+	}
+	h.Counts[i]++
+	h.Count++
+	h.Sum += d
+	if d > h.Max {
+		h.Max = d
+	}
+}
+
+// Mean retorna a duração média
+func (h LatencyHistogram) Mean() time.Duration {
+	if h.Count == 0 {
+		return 0
+	}
+	return h.Sum / time.Duration(h.Count)
+}
+
+// Quantile estima o quantil q (0 a 1) pelo limite superior do bucket que o contém; acima do
+// maior limite, retorna a maior duração observada
+func (h LatencyHistogram) Quantile(q float64) time.Duration {
+	if h.Count == 0 {
+		return 0
+	}
+	rank := int64(math.Ceil(q * float64(h.Count)))
+	if rank < 1 {
+		rank = 1
+	}
+	var seen int64
+	for i, count := range h.Counts {
+		seen += count
+		if seen >= rank {
+			if i < len(LatencyBuckets) {
+				return min(LatencyBuckets[i], h.Max)
+			}
+			break
+		}
+	}
+	return h.Max
+}
+
+// clone copia o histograma sem compartilhar os contadores
+func (h LatencyHistogram) clone() LatencyHistogram {
+	if h.Counts != nil {
+		h.Counts = append([]int64(nil), h.Counts...)
+	}
+	return h
+}
+
+// StatusError é um erro registrado pelo cliente
+type StatusError struct {
+	Time    time.Time `json:"time"`
+	Op      string    `json:"op"` // publish, request, consume ou connection
+	Subject string    `json:"subject,omitempty"`
+	Error   string    `json:"error"`
+}
+
+// errorRing guarda os últimos RecentErrorsSize erros
+type errorRing struct {
+	items []StatusError
+	next  int
+}
+
+func (r *errorRing) add(e StatusError) {
+	if len(r.items) < RecentErrorsSize {
+		r.items = append(r.items, e)
+		return
+	}
+	r.items[r.next] = e
+	r.next = (r.next + 1) % RecentErrorsSize
+}
+
+// list retorna os erros do mais antigo ao mais recente
+func (r *errorRing) list() []StatusError {
+	out := make([]StatusError, 0, len(r.items))
+	out = append(out, r.items[r.next:]...)
+	return append(out, r.items[:r.next]...)
+}
+
+// Os métodos abaixo devem ser chamados com o mutex do cliente travado.
+
+// recordError registra o erro em LastError e nos erros recentes
+func (s *ClientStatus) recordError(op, subject string, err error) {
+	s.LastError = err.Error()
+	s.errors.add(StatusError{Time: time.Now(), Op: op, Subject: subject, Error: err.Error()})
+}
+
+// recordPublish registra uma publicação iniciada em start
+func (s *ClientStatus) recordPublish(subject string, size int, start time.Time, err error) {
+	if err != nil {
+		s.PublishErrors++
+		s.recordError("publish", subject, err)
+		return
+	}
+	s.MessagesSent++
+	s.BytesSent += int64(size)
+	s.PublishLatency.Observe(time.Since(start))
+}
+
+// recordRequest registra uma requisição iniciada em start e a resposta recebida
+func (s *ClientStatus) recordRequest(subject string, sent, received int, start time.Time, err error) {
+	if err != nil {
+		s.PublishErrors++
+		s.recordError("request", subject, err)
+		return
+	}
+	s.MessagesSent++
+	s.MessagesReceived++
+	s.BytesSent += int64(sent)
+	s.BytesReceived += int64(received)
+	s.RequestLatency.Observe(time.Since(start))
+}
+
+// recordConsume registra uma mensagem entregue ao handler em start
+func (s *ClientStatus) recordConsume(subject string, size int, start time.Time, err error) {
+	s.MessagesReceived++
+	s.BytesReceived += int64(size)
+	s.ConsumeLatency.Observe(time.Since(start))
+	if err != nil {
+		s.ConsumeErrors++
+		s.recordError("consume", subject, err)
+	}
+}
+
+// snapshot retorna uma cópia independente do estado, com os erros recentes
+func (s *ClientStatus) snapshot() *ClientStatus {
+	out := *s
+	out.PublishLatency = s.PublishLatency.clone()
+	out.RequestLatency = s.RequestLatency.clone()
+	out.ConsumeLatency = s.ConsumeLatency.clone()
+	out.RecentErrors = s.errors.list()
+	out.errors = errorRing{}
+	return &out
+}
@@ -0,0 +1,58 @@
+package communication
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestLatencyHistogram(t *testing.T) {
+	var h LatencyHistogram
+	for i := 0; i < 9; i++ {
+		h.Observe(3 * time.Millisecond)
+	}
+	h.Observe(30 * time.Second)
+
+	if h.Count != 10 {
+		t.Fatalf("esperava 10 observações, obtidas %d", h.Count)
+	}
+	// O quantil é estimado pelo limite superior do bucket
+	if q := h.Quantile(0.5); q != 5*time.Millisecond {
+		t.Fatalf("mediana inesperada: %v", q)
+	}
+	if q := h.Quantile(0.99); q != 30*time.Second {
+		t.Fatalf("p99 deveria ser a maior duração: %v", q)
+	}
+	if h.Counts[len(LatencyBuckets)] != 1 {
+		t.Fatal("esperava uma observação acima do maior limite")
+	}
+}
+
+func TestClientStatusSnapshot(t *testing.T) {
+	status := &ClientStatus{}
+	for i := 0; i < RecentErrorsSize+5; i++ {
+		status.recordPublish("tasks.run", 10, time.Now(), fmt.Errorf("falha %d", i))
+	}
+	status.recordPublish("tasks.run", 10, time.Now(), nil)
+	status.recordConsume("tasks.run", 4, time.Now(), errors.New("handler"))
+
+	snapshot := status.snapshot()
+	if snapshot.PublishErrors != RecentErrorsSize+5 || snapshot.MessagesSent != 1 || snapshot.ConsumeErrors != 1 {
+		t.Fatalf("contadores inesperados: %+v", snapshot)
+	}
+	if len(snapshot.RecentErrors) != RecentErrorsSize {
+		t.Fatalf("esperava %d erros recentes, obtidos %d", RecentErrorsSize, len(snapshot.RecentErrors))
+	}
+	if first := snapshot.RecentErrors[0].Error; first != "falha 6" {
+		t.Fatalf("erro mais antigo inesperado: %s", first)
+	}
+	if last := snapshot.RecentErrors[RecentErrorsSize-1]; last.Op != "consume" || last.Error != snapshot.LastError {
+		t.Fatalf("erro mais recente inesperado: %+v", last)
+	}
+
+	status.recordPublish("tasks.run", 10, time.Now(), nil)
+	if snapshot.MessagesSent != 1 || snapshot.PublishLatency.Count != 1 {
+		t.Fatal("a cópia não deveria mudar com o estado do cliente")
+	}
+}
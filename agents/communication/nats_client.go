@@ -2,11 +2,15 @@ package communication
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/nats-io/nats.go"
+
+	"github.com/suissa/HiveMind/agents/errs"
 )
 
 // NatsClient implementa a interface CommunicationClient usando NATS
@@ -14,20 +18,27 @@ type NatsClient struct {
 	conn          *nats.Conn
 	config        *ConnectionConfig
 	status        *ClientStatus
-	subscriptions map[string]*nats.Subscription
-	handlers      map[string]MessageHandler
+	subscriptions map[string][]*nats.Subscription // Um padrão pode exigir mais de uma inscrição no NATS
+	handlers      map[string]HeaderHandler
+	js            nats.JetStreamContext // Ativado por UseJetStream
 	mu            sync.RWMutex
+	ctx           context.Context // Cancelado no Disconnect, interrompendo os handlers em execução
+	cancel        context.CancelFunc
+	wg            sync.WaitGroup // Publicações adiadas pendentes
 }
 
 // NewNatsClient cria uma nova instância do cliente NATS
 func NewNatsClient(config *ConnectionConfig) *NatsClient {
+	ctx, cancel := context.WithCancel(context.Background())
 	return &NatsClient{
 		config: config,
 		status: &ClientStatus{
 			Connected: false,
 		},
-		subscriptions: make(map[string]*nats.Subscription),
-		handlers:      make(map[string]MessageHandler),
+		subscriptions: make(map[string][]*nats.Subscription),
+		handlers:      make(map[string]HeaderHandler),
+		ctx:           ctx,
+		cancel:        cancel,
 	}
 }
 
@@ -48,19 +59,36 @@ func (nc *NatsClient) Connect(ctx context.Context) error {
 			nc.status.LastConnection = time.Now().Unix()
 			nc.mu.Unlock()
 		}),
-		nats.ErrorHandler(func(_ *nats.Conn, _ *nats.Subscription, err error) {
+		nats.ErrorHandler(func(_ *nats.Conn, sub *nats.Subscription, err error) {
+			subject := ""
+			if sub != nil {
+				subject = sub.Subject
+			}
 			nc.mu.Lock()
-			nc.status.LastError = err.Error()
+			nc.status.recordError("connection", subject, err)
 			nc.mu.Unlock()
 		}),
 	}
 
-	if nc.config.Username != "" {
-		opts = append(opts, nats.UserInfo(nc.config.Username, nc.config.Password))
+	if sasl := nc.config.sasl(); sasl != nil {
+		switch sasl.Mechanism {
+		case SASLPlain:
+			opts = append(opts, nats.UserInfo(sasl.Username, sasl.Password))
+		case SASLToken:
+			opts = append(opts, nats.Token(sasl.Token))
+		case SASLCredentials:
+			opts = append(opts, nats.UserCredentials(sasl.CredentialsFile))
+		default:
+			return fmt.Errorf("mecanismo de autenticação não suportado pelo NATS: %s", sasl.Mechanism)
+		}
 	}
 
-	if nc.config.TLS {
-		opts = append(opts, nats.Secure())
+	tlsConfig, err := nc.config.tlsConfig()
+	if err != nil {
+		return fmt.Errorf("erro ao configurar TLS do NATS: %v", err)
+	}
+	if tlsConfig != nil {
+		opts = append(opts, nats.Secure(tlsConfig))
 	}
 
 	url := fmt.Sprintf("nats://%s:%d", nc.config.Host, nc.config.Port)
@@ -78,6 +106,9 @@ func (nc *NatsClient) Connect(ctx context.Context) error {
 
 // Disconnect fecha a conexão com o servidor NATS
 func (nc *NatsClient) Disconnect() error {
+	nc.cancel()
+	nc.wg.Wait()
+
 	nc.mu.Lock()
 	defer nc.mu.Unlock()
 
@@ -89,8 +120,55 @@ func (nc *NatsClient) Disconnect() error {
 	return nil
 }
 
-// Subscribe registra um handler para receber mensagens de um tópico
+// UseJetStream ativa o JetStream nas opções de entrega, criando o stream com os tópicos ou
+// padrões informados se ele não existir. As inscrições em tópicos cobertos por um stream
+// passam a ser consumidores JetStream com confirmação manual, e as publicações com opções de
+// entrega nesses tópicos são persistidas; os demais tópicos seguem no NATS básico.
+func (nc *NatsClient) UseJetStream(stream string, subjects ...string) error {
+	if nc.conn == nil {
+		return fmt.Errorf("cliente NATS não conectado")
+	}
+	js, err := nc.conn.JetStream()
+	if err != nil {
+		return fmt.Errorf("erro ao ativar o JetStream: %v", err)
+	}
+
+	_, err = js.StreamInfo(stream)
+	if errors.Is(err, nats.ErrStreamNotFound) {
+		if len(subjects) == 0 {
+			return errs.New(errs.ErrValidation, "nats.UseJetStream", "o stream %s não existe e nenhum tópico foi informado", stream)
+		}
+		var natsSubs []string
+		for _, subject := range subjects {
+			if err := ValidateSubject(subject); err != nil {
+				return err
+			}
+			natsSubs = append(natsSubs, natsSubjects(subject)...)
+		}
+		_, err = js.AddStream(&nats.StreamConfig{Name: stream, Subjects: natsSubs})
+	}
+	if err != nil {
+		return fmt.Errorf("erro ao configurar o stream %s: %v", stream, err)
+	}
+
+	nc.mu.Lock()
+	nc.js = js
+	nc.mu.Unlock()
+	return nil
+}
+
+// Subscribe registra um handler para receber mensagens de um tópico ou padrão ("tasks.*",
+// "metrics.#"), traduzido para os curingas do NATS
 func (nc *NatsClient) Subscribe(subject string, handler MessageHandler) error {
+	return nc.SubscribeHeaders(subject, withoutHeaders(handler))
+}
+
+// SubscribeHeaders registra um handler que recebe também os headers das mensagens
+func (nc *NatsClient) SubscribeHeaders(subject string, handler HeaderHandler) error {
+	if err := ValidateSubject(subject); err != nil {
+		return err
+	}
+
 	nc.mu.Lock()
 	defer nc.mu.Unlock()
 
@@ -98,39 +176,117 @@ func (nc *NatsClient) Subscribe(subject string, handler MessageHandler) error {
 		return fmt.Errorf("já existe uma inscrição para o tópico %s", subject)
 	}
 
-	sub, err := nc.conn.Subscribe(subject, func(msg *nats.Msg) {
-		if handler != nil {
-			ctx := context.Background()
-			if err := handler(ctx, msg.Subject, msg.Data); err != nil {
-				// Log do erro ou tratamento adequado
-				fmt.Printf("Erro ao processar mensagem do tópico %s: %v\n", subject, err)
-			}
+	pattern := IsPattern(subject)
+	handle := func(msg *nats.Msg, headers map[string]string) error {
+		receive := func(ctx context.Context, subject string, data []byte) error {
+			return handler(ctx, subject, data, headers)
+		}
+		start := time.Now()
+		err := safeHandle(nc.ctx, "nats", receive, msg.Subject, msg.Data)
+		nc.mu.Lock()
+		nc.status.recordConsume(msg.Subject, len(msg.Data), start, err)
+		nc.mu.Unlock()
+		if err != nil {
+			// Log do erro ou tratamento adequado
+			fmt.Printf("Erro ao processar mensagem do tópico %s: %v\n", msg.Subject, err)
+		}
+		return err
+	}
+	callback := func(msg *nats.Msg) {
+		// A tradução de "#" pode ser mais ampla que o padrão
+		if handler == nil || (pattern && !MatchSubject(subject, msg.Subject)) {
+			return
+		}
+		headers := natsHeaders(msg.Header)
+		deliver(nc.ctx, parseDelivery(headers), func() error {
+			return handle(msg, headers)
+		})
+	}
+	jsCallback := func(msg *nats.Msg) {
+		if handler == nil || (pattern && !MatchSubject(subject, msg.Subject)) {
+			msg.Ack()
+			return
 		}
-	})
+		nc.deliverJetStream(msg, handle)
+	}
 
-	if err != nil {
-		return fmt.Errorf("erro ao se inscrever no tópico %s: %v", subject, err)
+	var subs []*nats.Subscription
+	for _, natsSubject := range natsSubjects(subject) {
+		var sub *nats.Subscription
+		var err error = nats.ErrNoMatchingStream
+		if nc.js != nil {
+			sub, err = nc.js.Subscribe(natsSubject, jsCallback, nats.ManualAck(), nats.DeliverNew())
+		}
+		if errors.Is(err, nats.ErrNoMatchingStream) {
+			sub, err = nc.conn.Subscribe(natsSubject, callback)
+		}
+		if err != nil {
+			for _, sub := range subs {
+				sub.Unsubscribe()
+			}
+			return fmt.Errorf("erro ao se inscrever no tópico %s: %v", subject, err)
+		}
+		subs = append(subs, sub)
 	}
 
-	nc.subscriptions[subject] = sub
+	nc.subscriptions[subject] = subs
 	nc.handlers[subject] = handler
 	nc.status.Subscriptions++
 
 	return nil
 }
 
+// deliverJetStream aplica as opções de entrega com as confirmações do JetStream: a mensagem
+// adiada tem a primeira entrega devolvida ao servidor até o horário marcado, a expirada é
+// descartada e a que falhou é devolvida enquanto houver reentregas, contadas pelo servidor
+func (nc *NatsClient) deliverJetStream(msg *nats.Msg, handle func(*nats.Msg, map[string]string) error) {
+	headers := natsHeaders(msg.Header)
+	d := parseDelivery(headers)
+	meta, err := msg.Metadata()
+	if err != nil {
+		msg.Term()
+		return
+	}
+
+	scheduling := 1
+	if !d.deliverAt.IsZero() {
+		scheduling = 2
+		if meta.NumDelivered == 1 {
+			msg.NakWithDelay(max(time.Until(d.deliverAt), 0))
+			return
+		}
+	}
+	if d.expired(time.Now()) {
+		msg.Term()
+		return
+	}
+
+	if err := handle(msg, headers); err != nil {
+		d.redelivery = int(meta.NumDelivered) - scheduling
+		if d.canRedeliver() {
+			msg.NakWithDelay(d.redeliveryDelay)
+		} else {
+			msg.Term()
+		}
+		return
+	}
+	msg.Ack()
+}
+
 // Unsubscribe remove a inscrição de um tópico
 func (nc *NatsClient) Unsubscribe(subject string) error {
 	nc.mu.Lock()
 	defer nc.mu.Unlock()
 
-	sub, exists := nc.subscriptions[subject]
+	subs, exists := nc.subscriptions[subject]
 	if !exists {
-		return fmt.Errorf("não existe inscrição para o tópico %s", subject)
+		return errs.New(errs.ErrNotFound, "nats.Unsubscribe", "não existe inscrição para o tópico %s", subject)
 	}
 
-	if err := sub.Unsubscribe(); err != nil {
-		return fmt.Errorf("erro ao cancelar inscrição do tópico %s: %v", subject, err)
+	for _, sub := range subs {
+		if err := sub.Unsubscribe(); err != nil {
+			return fmt.Errorf("erro ao cancelar inscrição do tópico %s: %v", subject, err)
+		}
 	}
 
 	delete(nc.subscriptions, subject)
@@ -142,40 +298,136 @@ func (nc *NatsClient) Unsubscribe(subject string) error {
 
 // Publish envia uma mensagem para um tópico
 func (nc *NatsClient) Publish(ctx context.Context, subject string, data []byte) error {
-	if err := nc.conn.Publish(subject, data); err != nil {
-		return fmt.Errorf("erro ao publicar mensagem no tópico %s: %v", subject, err)
+	return nc.PublishHeaders(ctx, subject, data, nil)
+}
+
+// PublishHeaders envia uma mensagem com headers para um tópico
+func (nc *NatsClient) PublishHeaders(ctx context.Context, subject string, data []byte, headers map[string]string) error {
+	start := time.Now()
+	err := nc.conn.PublishMsg(natsMsg(subject, data, headers))
+	if err != nil {
+		err = fmt.Errorf("erro ao publicar mensagem no tópico %s: %v", subject, err)
 	}
 
 	nc.mu.Lock()
-	nc.status.BytesSent += int64(len(data))
+	nc.status.recordPublish(subject, len(data), start, err)
 	nc.mu.Unlock()
 
+	return err
+}
+
+// PublishWithOptions publica a mensagem com opções de entrega. Nos tópicos cobertos por um
+// stream do JetStream, a mensagem é persistida e as opções são aplicadas com as confirmações
+// do servidor; nos demais, o adiamento é feito por um timer no publicador e o TTL e as
+// reentregas são emulados nas inscrições.
+func (nc *NatsClient) PublishWithOptions(ctx context.Context, subject string, data []byte, opts DeliveryOptions) error {
+	headers := opts.headers(time.Now(), nil)
+
+	nc.mu.RLock()
+	js := nc.js
+	nc.mu.RUnlock()
+	if js != nil {
+		start := time.Now()
+		_, err := js.PublishMsg(natsMsg(subject, data, headers), nats.Context(ctx))
+		if err == nil || !errors.Is(err, nats.ErrNoStreamResponse) {
+			if err != nil {
+				err = fmt.Errorf("erro ao publicar mensagem no tópico %s: %v", subject, err)
+			}
+			nc.mu.Lock()
+			nc.status.recordPublish(subject, len(data), start, err)
+			nc.mu.Unlock()
+			return err
+		}
+	}
+
+	if opts.DeliverAfter <= 0 {
+		return nc.PublishHeaders(ctx, subject, data, headers)
+	}
+	nc.wg.Add(1)
+	go func() {
+		defer nc.wg.Done()
+		if sleep(nc.ctx, opts.DeliverAfter) {
+			nc.PublishHeaders(nc.ctx, subject, data, headers)
+		}
+	}()
 	return nil
 }
 
 // Request envia uma mensagem e aguarda resposta
 func (nc *NatsClient) Request(ctx context.Context, subject string, data []byte, timeout int) ([]byte, error) {
+	start := time.Now()
 	msg, err := nc.conn.Request(subject, data, time.Duration(timeout)*time.Millisecond)
 	if err != nil {
 		if err == nats.ErrTimeout {
-			return nil, fmt.Errorf("timeout ao aguardar resposta do tópico %s", subject)
+			err = errs.New(errs.ErrTimeout, "nats.Request", "timeout ao aguardar resposta do tópico %s", subject)
+		} else {
+			err = fmt.Errorf("erro ao fazer request no tópico %s: %v", subject, err)
 		}
-		return nil, fmt.Errorf("erro ao fazer request no tópico %s: %v", subject, err)
+		nc.mu.Lock()
+		nc.status.recordRequest(subject, len(data), 0, start, err)
+		nc.mu.Unlock()
+		return nil, err
 	}
 
 	nc.mu.Lock()
-	nc.status.BytesSent += int64(len(data))
-	nc.status.BytesReceived += int64(len(msg.Data))
+	nc.status.recordRequest(subject, len(data), len(msg.Data), start, nil)
 	nc.mu.Unlock()
 
 	return msg.Data, nil
 }
 
-// GetStatus retorna o estado atual do cliente
+// RequestHeaders envia uma mensagem com headers e aguarda a resposta com os headers dela
+func (nc *NatsClient) RequestHeaders(ctx context.Context, subject string, data []byte, headers map[string]string, timeout int) ([]byte, map[string]string, error) {
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(timeout)*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	msg, err := nc.conn.RequestMsgWithContext(ctx, natsMsg(subject, data, headers))
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, nats.ErrTimeout) {
+			err = errs.New(errs.ErrTimeout, "nats.Request", "timeout ao aguardar resposta do tópico %s", subject)
+		} else {
+			err = fmt.Errorf("erro ao fazer request no tópico %s: %v", subject, err)
+		}
+		nc.mu.Lock()
+		nc.status.recordRequest(subject, len(data), 0, start, err)
+		nc.mu.Unlock()
+		return nil, nil, err
+	}
+
+	nc.mu.Lock()
+	nc.status.recordRequest(subject, len(data), len(msg.Data), start, nil)
+	nc.mu.Unlock()
+
+	return msg.Data, natsHeaders(msg.Header), nil
+}
+
+// natsMsg monta uma mensagem NATS com os headers informados
+func natsMsg(subject string, data []byte, headers map[string]string) *nats.Msg {
+	msg := nats.NewMsg(subject)
+	msg.Data = data
+	for key, value := range headers {
+		msg.Header.Set(key, value)
+	}
+	return msg
+}
+
+// natsHeaders converte os headers NATS, mantendo o primeiro valor de cada chave em minúsculas
+func natsHeaders(header nats.Header) map[string]string {
+	headers := make(map[string]string, len(header))
+	for key, values := range header {
+		if len(values) > 0 {
+			headers[strings.ToLower(key)] = values[0]
+		}
+	}
+	return headers
+}
+
+// GetStatus retorna uma cópia do estado atual do cliente
 func (nc *NatsClient) GetStatus() *ClientStatus {
 	nc.mu.RLock()
 	defer nc.mu.RUnlock()
-	return nc.status
+	return nc.status.snapshot()
 }
 
 // GetSubscriptions retorna a lista de inscrições ativas
@@ -93,6 +93,8 @@ func (o *Observer) Start(ctx context.Context) error {
 			protocol = "NATS"
 		case *KafkaClient:
 			protocol = "Kafka"
+		case *AMQPClient:
+			protocol = "AMQP"
 		case *GRPCClient:
 			protocol = "gRPC"
 		}
@@ -0,0 +1,74 @@
+package communication
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/streadway/amqp"
+)
+
+// defaultRabbitMQHeartbeat é o intervalo de heartbeat padrão da conexão AMQP
+const defaultRabbitMQHeartbeat = 10 * time.Second
+
+// externalAuth implementa o mecanismo SASL EXTERNAL (autenticação via certificado do cliente)
+type externalAuth struct{}
+
+func (externalAuth) Mechanism() string { return SASLExternal }
+func (externalAuth) Response() string  { return "" }
+
+// DialRabbitMQ conecta ao RabbitMQ usando a configuração comum de TLS e SASL
+func DialRabbitMQ(config *ConnectionConfig) (*amqp.Connection, error) {
+	tlsConfig, err := config.tlsConfig()
+	if err != nil {
+		return nil, fmt.Errorf("erro ao configurar TLS do RabbitMQ: %v", err)
+	}
+
+	scheme := "amqp"
+	if tlsConfig != nil {
+		scheme = "amqps"
+	}
+
+	u := url.URL{
+		Scheme: scheme,
+		Host:   fmt.Sprintf("%s:%d", config.Host, config.Port),
+	}
+	// Sem vhost explícito o servidor usa o vhost padrão "/"
+	if config.VHost != "" {
+		u.Path = "/" + config.VHost
+	}
+
+	amqpConfig := amqp.Config{
+		TLSClientConfig: tlsConfig,
+		Heartbeat:       defaultRabbitMQHeartbeat,
+	}
+
+	if sasl := config.sasl(); sasl != nil {
+		switch sasl.Mechanism {
+		case SASLPlain:
+			amqpConfig.SASL = []amqp.Authentication{&amqp.PlainAuth{
+				Username: sasl.Username,
+				Password: sasl.Password,
+			}}
+		case SASLExternal:
+			if tlsConfig == nil || len(tlsConfig.Certificates) == 0 {
+				return nil, fmt.Errorf("SASL EXTERNAL requer TLS com certificado do cliente")
+			}
+			amqpConfig.SASL = []amqp.Authentication{externalAuth{}}
+		default:
+			return nil, fmt.Errorf("mecanismo SASL não suportado pelo RabbitMQ: %s", sasl.Mechanism)
+		}
+	}
+
+	conn, err := amqp.DialConfig(u.String(), amqpConfig)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao conectar ao RabbitMQ em %s: %v", u.Host, err)
+	}
+
+	return conn, nil
+}
+
+// RabbitMQConfigFromEnv carrega a configuração do RabbitMQ das variáveis RABBITMQ_*
+func RabbitMQConfigFromEnv() *ConnectionConfig {
+	return ConnectionConfigFromEnv("RABBITMQ", "localhost", 5672)
+}
@@ -0,0 +1,557 @@
+package communication
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/streadway/amqp"
+
+	"github.com/suissa/HiveMind/agents/errs"
+)
+
+// Padrões do RabbitMQPool
+const (
+	DefaultPoolChannels   = 8
+	DefaultConfirmTimeout = 5 * time.Second
+	DefaultRedialDelay    = time.Second
+	DefaultMaxRedialDelay = 30 * time.Second
+)
+
+// PoolOptions configura o RabbitMQPool
+type PoolOptions struct {
+	Channels       int           // Máximo de canais abertos na conexão (padrão DefaultPoolChannels)
+	ConfirmTimeout time.Duration // Espera pela confirmação do broker (padrão DefaultConfirmTimeout)
+	RedialDelay    time.Duration // Primeira espera entre as reconexões (padrão DefaultRedialDelay)
+	MaxRedialDelay time.Duration // Espera máxima entre as reconexões (padrão DefaultMaxRedialDelay)
+}
+
+// PoolStats é o estado do pool
+type PoolStats struct {
+	Connected    bool  `json:"connected"`
+	Blocked      bool  `json:"blocked"` // O broker suspendeu as publicações (connection.blocked)
+	OpenChannels int   `json:"open_channels"`
+	IdleChannels int   `json:"idle_channels"`
+	Redials      int64 `json:"redials"` // Reconexões feitas depois de uma queda
+}
+
+// RabbitMQPool compartilha uma conexão com o RabbitMQ entre os publicadores, com um conjunto
+// de canais em modo de confirmação: cada publicação aguarda o ack do broker. Enquanto o broker
+// bloqueia a conexão por falta de recursos, as publicações aguardam o desbloqueio; se a
+// conexão cai, o pool reconecta em segundo plano, com espera exponencial, e as publicações
+// seguintes usam a nova conexão.
+type RabbitMQPool struct {
+	config   *ConnectionConfig
+	opts     PoolOptions
+	dial     func() (amqpConnection, error)
+	external bool // A conexão é de quem criou o pool: não é fechada nem reconectada
+	current  *pooledConnection
+	redials  int64
+	closed   bool
+	done     chan struct{}
+	mu       sync.Mutex
+}
+
+// amqpConnection é a parte da conexão AMQP usada pelo pool (substituída nos testes)
+type amqpConnection interface {
+	channel() (amqpChannel, error)
+	IsClosed() bool
+	Close() error
+	NotifyBlocked(receiver chan amqp.Blocking) chan amqp.Blocking
+	NotifyClose(receiver chan *amqp.Error) chan *amqp.Error
+}
+
+// amqpChannel é a parte do canal AMQP usada pelo pool
+type amqpChannel interface {
+	Confirm(noWait bool) error
+	NotifyPublish(confirm chan amqp.Confirmation) chan amqp.Confirmation
+	Publish(exchange, key string, mandatory, immediate bool, msg amqp.Publishing) error
+	Consume(queue, consumer string, autoAck, exclusive, noLocal, noWait bool, args amqp.Table) (<-chan amqp.Delivery, error)
+	Cancel(consumer string, noWait bool) error
+	QueueDeclare(name string, durable, autoDelete, exclusive, noWait bool, args amqp.Table) (amqp.Queue, error)
+	Close() error
+}
+
+// streadwayConnection adapta a conexão do cliente AMQP ao pool
+type streadwayConnection struct {
+	*amqp.Connection
+}
+
+func (c streadwayConnection) channel() (amqpChannel, error) {
+	ch, err := c.Channel()
+	if err != nil {
+		return nil, err
+	}
+	return ch, nil
+}
+
+// pooledConnection é o estado de uma conexão do pool; uma reconexão cria outro
+type pooledConnection struct {
+	conn      amqpConnection
+	idle      chan *pooledChannel
+	open      int
+	queues    map[string]bool // Filas já declaradas nesta conexão
+	unblocked chan struct{}   // Fechado enquanto a conexão não está bloqueada
+	replaced  chan struct{}   // Fechado quando a conexão deixa de ser a atual
+}
+
+// blocked informa se o broker bloqueou a conexão. Deve ser chamado com o mutex do pool travado.
+func (pc *pooledConnection) blocked() bool {
+	select {
+	case <-pc.unblocked:
+		return false
+	default:
+		return true
+	}
+}
+
+// pooledChannel é um canal em modo de confirmação
+type pooledChannel struct {
+	owner    *pooledConnection
+	channel  amqpChannel
+	confirms chan amqp.Confirmation
+}
+
+// NewRabbitMQPool cria um pool; a conexão é aberta em Connect ou na primeira publicação
+func NewRabbitMQPool(config *ConnectionConfig, opts PoolOptions) *RabbitMQPool {
+	return newRabbitMQPool(config, opts, func() (amqpConnection, error) {
+		conn, err := DialRabbitMQ(config)
+		if err != nil {
+			return nil, err
+		}
+		return streadwayConnection{conn}, nil
+	})
+}
+
+// NewConnectionPool cria um pool sobre uma conexão já aberta, para quem recebe a conexão
+// pronta (como o LLMRouter e o LLMAgent). A conexão continua sendo de quem a abriu: o pool não
+// a fecha nem reconecta depois de uma queda.
+func NewConnectionPool(conn *amqp.Connection, opts PoolOptions) *RabbitMQPool {
+	pool := newRabbitMQPool(nil, opts, func() (amqpConnection, error) {
+		if conn.IsClosed() {
+			return nil, fmt.Errorf("conexão com o RabbitMQ fechada")
+		}
+		return streadwayConnection{conn}, nil
+	})
+	pool.external = true
+	return pool
+}
+
+// newRabbitMQPool cria um pool que abre as conexões com dial
+func newRabbitMQPool(config *ConnectionConfig, opts PoolOptions, dial func() (amqpConnection, error)) *RabbitMQPool {
+	if opts.Channels <= 0 {
+		opts.Channels = DefaultPoolChannels
+	}
+	if opts.ConfirmTimeout <= 0 {
+		opts.ConfirmTimeout = DefaultConfirmTimeout
+	}
+	if opts.RedialDelay <= 0 {
+		opts.RedialDelay = DefaultRedialDelay
+	}
+	if opts.MaxRedialDelay <= 0 {
+		opts.MaxRedialDelay = DefaultMaxRedialDelay
+	}
+	return &RabbitMQPool{config: config, opts: opts, dial: dial, done: make(chan struct{})}
+}
+
+var (
+	sharedPools   = make(map[string]*RabbitMQPool)
+	sharedPoolsMu sync.Mutex
+)
+
+// SharedRabbitMQPool retorna o pool do processo para o servidor, o vhost e o usuário da
+// configuração, criado com as opções padrão no primeiro uso
+func SharedRabbitMQPool(config *ConnectionConfig) *RabbitMQPool {
+	key := fmt.Sprintf("%s@%s:%d/%s", config.Username, config.Host, config.Port, config.VHost)
+
+	sharedPoolsMu.Lock()
+	defer sharedPoolsMu.Unlock()
+	pool, ok := sharedPools[key]
+	if !ok {
+		pool = NewRabbitMQPool(config, PoolOptions{})
+		sharedPools[key] = pool
+	}
+	return pool
+}
+
+// Connect abre a conexão compartilhada, se ainda não estiver aberta
+func (p *RabbitMQPool) Connect() error {
+	_, err := p.connection()
+	return err
+}
+
+// connection retorna a conexão atual, conectando se necessário
+func (p *RabbitMQPool) connection() (*pooledConnection, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.dialLocked()
+}
+
+// dialLocked conecta se não houver conexão aberta. Deve ser chamado com p.mu travado.
+func (p *RabbitMQPool) dialLocked() (*pooledConnection, error) {
+	if p.closed {
+		return nil, fmt.Errorf("pool do RabbitMQ fechado")
+	}
+	if p.current != nil && !p.current.conn.IsClosed() {
+		return p.current, nil
+	}
+
+	conn, err := p.dial()
+	if err != nil {
+		return nil, err
+	}
+	if p.current != nil {
+		close(p.current.replaced)
+	}
+	pc := &pooledConnection{
+		conn:      conn,
+		idle:      make(chan *pooledChannel, p.opts.Channels),
+		queues:    make(map[string]bool),
+		unblocked: make(chan struct{}),
+		replaced:  make(chan struct{}),
+	}
+	close(pc.unblocked)
+	p.current = pc
+
+	blocked := conn.NotifyBlocked(make(chan amqp.Blocking, 1))
+	closes := conn.NotifyClose(make(chan *amqp.Error, 1))
+	go p.watch(pc, blocked, closes)
+	return pc, nil
+}
+
+// watch acompanha o bloqueio e a queda da conexão, reconectando após uma queda
+func (p *RabbitMQPool) watch(pc *pooledConnection, blocked chan amqp.Blocking, closes chan *amqp.Error) {
+	for {
+		select {
+		case b, ok := <-blocked:
+			if !ok {
+				blocked = nil
+				continue
+			}
+			p.mu.Lock()
+			if b.Active != pc.blocked() {
+				if b.Active {
+					pc.unblocked = make(chan struct{})
+				} else {
+					close(pc.unblocked)
+				}
+			}
+			p.mu.Unlock()
+		case <-closes:
+			p.mu.Lock()
+			// Libera as publicações que aguardavam o desbloqueio desta conexão
+			if pc.blocked() {
+				close(pc.unblocked)
+			}
+			current := p.current == pc && !p.closed && !p.external
+			p.mu.Unlock()
+			if current {
+				p.redial()
+			}
+			return
+		}
+	}
+}
+
+// redial reconecta com espera exponencial até conseguir ou o pool ser fechado. Uma
+// publicação feita nesse meio-tempo também tenta conectar.
+func (p *RabbitMQPool) redial() {
+	delay := p.opts.RedialDelay
+	for {
+		p.mu.Lock()
+		_, err := p.dialLocked()
+		if err == nil {
+			p.redials++
+		}
+		p.mu.Unlock()
+		if err == nil {
+			return
+		}
+
+		select {
+		case <-p.done:
+			return
+		case <-time.After(delay):
+		}
+		delay = min(delay*2, p.opts.MaxRedialDelay)
+	}
+}
+
+// acquire retorna um canal livre da conexão atual, abrindo outro enquanto houver vaga
+func (p *RabbitMQPool) acquire(ctx context.Context) (*pooledChannel, error) {
+	for {
+		p.mu.Lock()
+		pc, err := p.dialLocked()
+		if err != nil {
+			p.mu.Unlock()
+			return nil, err
+		}
+		select {
+		case ch := <-pc.idle:
+			p.mu.Unlock()
+			return ch, nil
+		default:
+		}
+		if pc.open < p.opts.Channels {
+			pc.open++
+			p.mu.Unlock()
+			ch, err := p.openChannel(pc)
+			if err != nil {
+				p.mu.Lock()
+				pc.open--
+				p.mu.Unlock()
+				return nil, err
+			}
+			return ch, nil
+		}
+		p.mu.Unlock()
+
+		// Todos os canais em uso: aguarda um ser devolvido ou a conexão ser trocada
+		select {
+		case ch := <-pc.idle:
+			return ch, nil
+		case <-pc.replaced:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// openChannel abre um canal em modo de confirmação
+func (p *RabbitMQPool) openChannel(pc *pooledConnection) (*pooledChannel, error) {
+	channel, err := pc.conn.channel()
+	if err != nil {
+		return nil, fmt.Errorf("erro ao abrir canal AMQP: %v", err)
+	}
+	if err := channel.Confirm(false); err != nil {
+		channel.Close()
+		return nil, fmt.Errorf("erro ao ativar confirmações no canal AMQP: %v", err)
+	}
+	return &pooledChannel{
+		owner:    pc,
+		channel:  channel,
+		confirms: channel.NotifyPublish(make(chan amqp.Confirmation, 1)),
+	}, nil
+}
+
+// release devolve o canal ao pool ou, se estiver com defeito ou for de uma conexão
+// anterior, o fecha
+func (p *RabbitMQPool) release(ch *pooledChannel, broken bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !broken && ch.owner == p.current && !p.closed {
+		ch.owner.idle <- ch
+		return
+	}
+	ch.owner.open--
+	ch.channel.Close()
+}
+
+// Publish publica a mensagem e aguarda a confirmação do broker. Se o canal estiver fechado,
+// a publicação é repetida uma vez em outro canal; sem confirmação no prazo, o resultado é um
+// erro de timeout e a mensagem pode ter sido entregue.
+func (p *RabbitMQPool) Publish(ctx context.Context, exchange, routingKey string, msg amqp.Publishing) error {
+	for attempt := 0; ; attempt++ {
+		ch, err := p.acquire(ctx)
+		if err != nil {
+			return err
+		}
+		sent, err := p.publish(ctx, ch, exchange, routingKey, msg)
+		p.release(ch, err != nil && !errors.Is(err, errNack))
+		if err != nil && !sent && attempt == 0 && ctx.Err() == nil {
+			continue
+		}
+		return err
+	}
+}
+
+// errNack indica que o broker recusou a mensagem; o canal continua utilizável
+var errNack = errors.New("mensagem recusada pelo RabbitMQ")
+
+// publish publica em um canal e aguarda a confirmação; sent informa se a mensagem chegou a
+// ser enviada
+func (p *RabbitMQPool) publish(ctx context.Context, ch *pooledChannel, exchange, routingKey string, msg amqp.Publishing) (sent bool, err error) {
+	// Controle de fluxo: aguarda o broker desbloquear a conexão
+	p.mu.Lock()
+	unblocked := ch.owner.unblocked
+	p.mu.Unlock()
+	select {
+	case <-unblocked:
+	case <-ctx.Done():
+		return false, ctx.Err()
+	}
+
+	if err := ch.channel.Publish(exchange, routingKey, false, false, msg); err != nil {
+		return false, fmt.Errorf("erro ao publicar mensagem em %s: %v", routingKey, err)
+	}
+
+	timer := time.NewTimer(p.opts.ConfirmTimeout)
+	defer timer.Stop()
+	select {
+	case confirm, ok := <-ch.confirms:
+		if !ok {
+			return true, fmt.Errorf("canal fechado antes da confirmação da mensagem em %s", routingKey)
+		}
+		if !confirm.Ack {
+			return true, fmt.Errorf("%w: %s", errNack, routingKey)
+		}
+		return true, nil
+	case <-timer.C:
+		return true, errs.New(errs.ErrTimeout, "rabbitmq.Publish", "timeout aguardando a confirmação da mensagem em %s", routingKey)
+	case <-ctx.Done():
+		return true, ctx.Err()
+	}
+}
+
+// replyQueue é a pseudofila direct reply-to do RabbitMQ
+const replyQueue = "amq.rabbitmq.reply-to"
+
+// replyConsumers numera os consumidores das respostas das requisições
+var replyConsumers atomic.Int64
+
+// Request publica a requisição, com resposta pela fila direct reply-to, e aguarda a confirmação
+// do broker e a resposta de mesmo correlation ID até o fim do ctx. O RabbitMQ exige que as
+// respostas sejam consumidas no canal da publicação, então o canal fica reservado até a
+// resposta, e o consumo é cancelado antes de ele voltar ao pool.
+func (p *RabbitMQPool) Request(ctx context.Context, exchange, routingKey string, msg amqp.Publishing) (amqp.Delivery, error) {
+	ch, err := p.acquire(ctx)
+	if err != nil {
+		return amqp.Delivery{}, err
+	}
+	reply, broken, err := p.request(ctx, ch, exchange, routingKey, msg)
+	p.release(ch, broken)
+	return reply, err
+}
+
+// request consome as respostas, publica a requisição e aguarda a resposta; broken informa se
+// o canal deve ser descartado
+func (p *RabbitMQPool) request(ctx context.Context, ch *pooledChannel, exchange, routingKey string, msg amqp.Publishing) (reply amqp.Delivery, broken bool, err error) {
+	consumer := fmt.Sprintf("hivemind-reply-%d", replyConsumers.Add(1))
+	replies, err := ch.channel.Consume(replyQueue, consumer, true, true, false, false, nil)
+	if err != nil {
+		return amqp.Delivery{}, true, fmt.Errorf("erro ao aguardar resposta de %s: %v", routingKey, err)
+	}
+	defer func() {
+		if cancelErr := ch.channel.Cancel(consumer, false); cancelErr != nil {
+			broken = true
+		}
+	}()
+
+	msg.ReplyTo = replyQueue
+	if _, err := p.publish(ctx, ch, exchange, routingKey, msg); err != nil {
+		return amqp.Delivery{}, !errors.Is(err, errNack), err
+	}
+	for {
+		select {
+		case reply, ok := <-replies:
+			if !ok {
+				return amqp.Delivery{}, true, fmt.Errorf("canal de respostas fechado aguardando %s", routingKey)
+			}
+			if reply.CorrelationId == msg.CorrelationId {
+				return reply, false, nil
+			}
+		case <-ctx.Done():
+			return amqp.Delivery{}, false, ctx.Err()
+		}
+	}
+}
+
+// PublishJSON serializa o valor e o publica como mensagem persistente
+func (p *RabbitMQPool) PublishJSON(ctx context.Context, exchange, routingKey string, v interface{}) error {
+	body, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("erro ao converter mensagem para JSON: %v", err)
+	}
+	return p.Publish(ctx, exchange, routingKey, amqp.Publishing{
+		ContentType:  "application/json",
+		DeliveryMode: amqp.Persistent,
+		Timestamp:    time.Now(),
+		Body:         body,
+	})
+}
+
+// DeclareQueue declara uma fila durável, uma vez por conexão
+func (p *RabbitMQPool) DeclareQueue(ctx context.Context, name string) error {
+	pc, err := p.connection()
+	if err != nil {
+		return err
+	}
+	p.mu.Lock()
+	declared := pc.queues[name]
+	p.mu.Unlock()
+	if declared {
+		return nil
+	}
+
+	ch, err := p.acquire(ctx)
+	if err != nil {
+		return err
+	}
+	_, err = ch.channel.QueueDeclare(name, true, false, false, false, nil)
+	// Uma falha na declaração fecha o canal
+	p.release(ch, err != nil)
+	if err != nil {
+		return fmt.Errorf("erro ao declarar a fila %s: %v", name, err)
+	}
+
+	p.mu.Lock()
+	ch.owner.queues[name] = true
+	p.mu.Unlock()
+	return nil
+}
+
+// Stats retorna o estado do pool
+func (p *RabbitMQPool) Stats() PoolStats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	stats := PoolStats{Redials: p.redials}
+	if pc := p.current; pc != nil {
+		stats.Connected = !pc.conn.IsClosed()
+		stats.OpenChannels = pc.open
+		stats.IdleChannels = len(pc.idle)
+		stats.Blocked = pc.blocked()
+	}
+	return stats
+}
+
+// DropConnection fecha a conexão atual como numa queda: o pool reconecta em segundo plano,
+// como faria após uma falha de rede. Usado para testar a reconexão (ver o pacote chaos).
+func (p *RabbitMQPool) DropConnection() error {
+	p.mu.Lock()
+	pc := p.current
+	p.mu.Unlock()
+
+	if pc == nil || pc.conn.IsClosed() {
+		return fmt.Errorf("pool do RabbitMQ sem conexão aberta")
+	}
+	if err := pc.conn.Close(); err != nil {
+		return fmt.Errorf("erro ao derrubar conexão com o RabbitMQ: %v", err)
+	}
+	return nil
+}
+
+// Close fecha a conexão e interrompe as reconexões. Num pool criado com NewConnectionPool, a
+// conexão continua aberta e os canais do pool são fechados junto com ela.
+func (p *RabbitMQPool) Close() error {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return nil
+	}
+	p.closed = true
+	close(p.done)
+	pc := p.current
+	p.mu.Unlock()
+
+	if pc != nil && !p.external && !pc.conn.IsClosed() {
+		if err := pc.conn.Close(); err != nil {
+			return fmt.Errorf("erro ao fechar conexão com o RabbitMQ: %v", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,343 @@
+package communication
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/streadway/amqp"
+
+	"github.com/suissa/HiveMind/agents/errs"
+)
+
+// fakeConnection é uma conexão AMQP em memória; os canais respondem conforme outcome
+type fakeConnection struct {
+	mu       sync.Mutex
+	closed   bool
+	channels []*fakeChannel
+	outcomes []string // Resultado das publicações de cada canal aberto, em ordem (padrão "ack")
+	blocked  chan amqp.Blocking
+	closes   chan *amqp.Error
+}
+
+func newFakeConnection(outcomes ...string) *fakeConnection {
+	return &fakeConnection{outcomes: outcomes}
+}
+
+func (c *fakeConnection) channel() (amqpChannel, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	outcome := "ack"
+	if len(c.outcomes) > 0 {
+		outcome, c.outcomes = c.outcomes[0], c.outcomes[1:]
+	}
+	ch := &fakeChannel{outcome: outcome}
+	c.channels = append(c.channels, ch)
+	return ch, nil
+}
+
+func (c *fakeConnection) opened() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.channels)
+}
+
+func (c *fakeConnection) IsClosed() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.closed
+}
+
+func (c *fakeConnection) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.closed = true
+	return nil
+}
+
+func (c *fakeConnection) NotifyBlocked(receiver chan amqp.Blocking) chan amqp.Blocking {
+	c.blocked = receiver
+	return receiver
+}
+
+func (c *fakeConnection) NotifyClose(receiver chan *amqp.Error) chan *amqp.Error {
+	c.closes = receiver
+	return receiver
+}
+
+// fakeChannel confirma as publicações conforme outcome: "ack", "nack", "fail" (a publicação
+// falha), "close" (o canal fecha antes da confirmação) ou "silent" (sem confirmação)
+type fakeChannel struct {
+	mu        sync.Mutex
+	outcome   string
+	confirms  chan amqp.Confirmation
+	replies   chan amqp.Delivery
+	published []amqp.Publishing
+	cancelled []string
+	closed    bool
+	tag       uint64
+}
+
+func (ch *fakeChannel) Confirm(noWait bool) error { return nil }
+
+func (ch *fakeChannel) NotifyPublish(confirm chan amqp.Confirmation) chan amqp.Confirmation {
+	ch.confirms = confirm
+	return confirm
+}
+
+func (ch *fakeChannel) Publish(exchange, key string, mandatory, immediate bool, msg amqp.Publishing) error {
+	ch.mu.Lock()
+	defer ch.mu.Unlock()
+	if ch.outcome == "fail" {
+		return errors.New("canal fechado")
+	}
+	ch.published = append(ch.published, msg)
+	ch.tag++
+	switch ch.outcome {
+	case "close":
+		close(ch.confirms)
+	case "silent":
+	default:
+		ch.confirms <- amqp.Confirmation{DeliveryTag: ch.tag, Ack: ch.outcome != "nack"}
+	}
+	if ch.replies != nil {
+		// Uma resposta de outra requisição chega antes da esperada
+		ch.replies <- amqp.Delivery{CorrelationId: "outra"}
+		ch.replies <- amqp.Delivery{CorrelationId: msg.CorrelationId, Body: []byte("pong")}
+	}
+	return nil
+}
+
+func (ch *fakeChannel) Consume(queue, consumer string, autoAck, exclusive, noLocal, noWait bool, args amqp.Table) (<-chan amqp.Delivery, error) {
+	ch.mu.Lock()
+	defer ch.mu.Unlock()
+	ch.replies = make(chan amqp.Delivery, 2)
+	return ch.replies, nil
+}
+
+func (ch *fakeChannel) Cancel(consumer string, noWait bool) error {
+	ch.mu.Lock()
+	defer ch.mu.Unlock()
+	ch.cancelled = append(ch.cancelled, consumer)
+	ch.replies = nil
+	return nil
+}
+
+func (ch *fakeChannel) QueueDeclare(name string, durable, autoDelete, exclusive, noWait bool, args amqp.Table) (amqp.Queue, error) {
+	return amqp.Queue{Name: name}, nil
+}
+
+func (ch *fakeChannel) Close() error {
+	ch.mu.Lock()
+	defer ch.mu.Unlock()
+	ch.closed = true
+	return nil
+}
+
+func (ch *fakeChannel) isClosed() bool {
+	ch.mu.Lock()
+	defer ch.mu.Unlock()
+	return ch.closed
+}
+
+func newFakePool(conn *fakeConnection, opts PoolOptions) *RabbitMQPool {
+	return newRabbitMQPool(nil, opts, func() (amqpConnection, error) { return conn, nil })
+}
+
+func TestPoolAcquireRelease(t *testing.T) {
+	conn := newFakeConnection()
+	pool := newFakePool(conn, PoolOptions{Channels: 2})
+	defer pool.Close()
+	ctx := context.Background()
+
+	first, err := pool.acquire(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := pool.acquire(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first == second || conn.opened() != 2 {
+		t.Fatalf("esperava dois canais abertos, obtidos %d", conn.opened())
+	}
+
+	// Com todos os canais em uso, a aquisição aguarda uma devolução
+	waitCtx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+	if _, err := pool.acquire(waitCtx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("esperava aguardar um canal livre: %v", err)
+	}
+
+	acquired := make(chan *pooledChannel)
+	go func() {
+		ch, _ := pool.acquire(ctx)
+		acquired <- ch
+	}()
+	pool.release(first, false)
+	if ch := <-acquired; ch != first || conn.opened() != 2 {
+		t.Fatalf("esperava reutilizar o canal devolvido (%d abertos)", conn.opened())
+	}
+
+	// Um canal com defeito é fechado e libera a vaga para outro
+	pool.release(second, true)
+	if !second.channel.(*fakeChannel).isClosed() || pool.Stats().OpenChannels != 1 {
+		t.Fatalf("canal com defeito deveria ser fechado: %+v", pool.Stats())
+	}
+	if _, err := pool.acquire(ctx); err != nil || conn.opened() != 3 {
+		t.Fatalf("esperava abrir outro canal: %v (%d abertos)", err, conn.opened())
+	}
+}
+
+func TestPoolNackKeepsChannel(t *testing.T) {
+	conn := newFakeConnection("nack", "close")
+	pool := newFakePool(conn, PoolOptions{Channels: 1})
+	defer pool.Close()
+	ctx := context.Background()
+
+	// O broker recusou a mensagem, mas o canal continua utilizável
+	if err := pool.Publish(ctx, "", "tasks", amqp.Publishing{}); !errors.Is(err, errNack) {
+		t.Fatalf("esperava a recusa do broker: %v", err)
+	}
+	if stats := pool.Stats(); stats.IdleChannels != 1 || conn.channels[0].isClosed() {
+		t.Fatalf("o canal deveria voltar ao pool após a recusa: %+v", stats)
+	}
+
+	// Sem a confirmação, o canal é descartado e a mensagem não é reenviada
+	pool.release(mustAcquire(t, pool), true)
+	if err := pool.Publish(ctx, "", "tasks", amqp.Publishing{}); err == nil {
+		t.Fatal("esperava o erro do canal fechado antes da confirmação")
+	}
+	if stats := pool.Stats(); stats.OpenChannels != 0 || !conn.channels[1].isClosed() || len(conn.channels[1].published) != 1 {
+		t.Fatalf("o canal fechado deveria ser descartado: %+v", stats)
+	}
+}
+
+func TestPoolRetriesUnsentPublication(t *testing.T) {
+	conn := newFakeConnection("fail", "ack")
+	pool := newFakePool(conn, PoolOptions{Channels: 2})
+	defer pool.Close()
+
+	if err := pool.Publish(context.Background(), "", "tasks", amqp.Publishing{Body: []byte("t-1")}); err != nil {
+		t.Fatalf("esperava a publicação no segundo canal: %v", err)
+	}
+	if !conn.channels[0].isClosed() || len(conn.channels[1].published) != 1 {
+		t.Fatal("esperava descartar o canal com falha e publicar em outro")
+	}
+}
+
+func TestPoolWaitsWhileBlocked(t *testing.T) {
+	conn := newFakeConnection()
+	pool := newFakePool(conn, PoolOptions{})
+	defer pool.Close()
+	if err := pool.Connect(); err != nil {
+		t.Fatal(err)
+	}
+
+	conn.blocked <- amqp.Blocking{Active: true, Reason: "low on memory"}
+	waitFor(t, func() bool { return pool.Stats().Blocked })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := pool.Publish(ctx, "", "tasks", amqp.Publishing{}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("a publicação deveria aguardar o desbloqueio: %v", err)
+	}
+
+	published := make(chan error, 1)
+	go func() {
+		published <- pool.Publish(context.Background(), "", "tasks", amqp.Publishing{})
+	}()
+	select {
+	case err := <-published:
+		t.Fatalf("a publicação não deveria ocorrer com a conexão bloqueada: %v", err)
+	case <-time.After(20 * time.Millisecond):
+	}
+	conn.blocked <- amqp.Blocking{Active: false}
+	if err := <-published; err != nil {
+		t.Fatalf("esperava publicar após o desbloqueio: %v", err)
+	}
+	if pool.Stats().Blocked {
+		t.Fatal("o pool deveria estar desbloqueado")
+	}
+}
+
+func TestPoolConfirmTimeout(t *testing.T) {
+	conn := newFakeConnection("silent")
+	pool := newFakePool(conn, PoolOptions{ConfirmTimeout: 10 * time.Millisecond})
+	defer pool.Close()
+
+	if err := pool.Publish(context.Background(), "", "tasks", amqp.Publishing{}); !errors.Is(err, errs.ErrTimeout) {
+		t.Fatalf("esperava o timeout da confirmação: %v", err)
+	}
+	// Uma confirmação atrasada não pode ser atribuída à próxima publicação
+	if !conn.channels[0].isClosed() {
+		t.Fatal("o canal sem confirmação deveria ser descartado")
+	}
+}
+
+func TestPoolRequest(t *testing.T) {
+	conn := newFakeConnection()
+	pool := newFakePool(conn, PoolOptions{Channels: 1})
+	defer pool.Close()
+
+	reply, err := pool.Request(context.Background(), "hivemind.topics", "tasks.ping", amqp.Publishing{CorrelationId: "req-1"})
+	if err != nil || string(reply.Body) != "pong" {
+		t.Fatalf("resposta inesperada: %+v %v", reply, err)
+	}
+	ch := conn.channels[0]
+	if ch.published[0].ReplyTo != replyQueue || len(ch.cancelled) != 1 {
+		t.Fatalf("a requisição deveria usar a fila direct reply-to e cancelar o consumo: %+v", ch)
+	}
+	if stats := pool.Stats(); stats.IdleChannels != 1 || ch.isClosed() {
+		t.Fatalf("o canal deveria voltar ao pool: %+v", stats)
+	}
+}
+
+func TestConnectionPoolKeepsExternalConnection(t *testing.T) {
+	conn := newFakeConnection()
+	pool := newFakePool(conn, PoolOptions{})
+	pool.external = true
+	if err := pool.Connect(); err != nil {
+		t.Fatal(err)
+	}
+
+	conn.Close()
+	conn.closes <- amqp.ErrClosed
+	if err := pool.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if pool.Stats().Redials != 0 {
+		t.Fatal("o pool não deveria reconectar uma conexão externa")
+	}
+
+	conn = newFakeConnection()
+	pool = newFakePool(conn, PoolOptions{})
+	pool.external = true
+	pool.Connect()
+	pool.Close()
+	if conn.IsClosed() {
+		t.Fatal("o pool não deveria fechar uma conexão externa")
+	}
+}
+
+func mustAcquire(t *testing.T, pool *RabbitMQPool) *pooledChannel {
+	t.Helper()
+	ch, err := pool.acquire(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ch
+}
+
+func waitFor(t *testing.T, condition func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatal("condição não atingida a tempo")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
@@ -0,0 +1,160 @@
+package communication
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Mecanismos SASL suportados pelos clientes
+const (
+	SASLPlain       = "PLAIN"
+	SASLScramSHA256 = "SCRAM-SHA-256"
+	SASLScramSHA512 = "SCRAM-SHA-512"
+	SASLExternal    = "EXTERNAL"
+	SASLToken       = "TOKEN" // Token/Bearer (NATS token, gRPC Bearer)
+	SASLCredentials = "CREDENTIALS"
+)
+
+// TLSConfig define a configuração TLS comum a todos os brokers
+type TLSConfig struct {
+	Enabled            bool   `json:"enabled" yaml:"enabled"`
+	CAFile             string `json:"ca_file" yaml:"ca_file"`                           // Certificado da CA (PEM)
+	CertFile           string `json:"cert_file" yaml:"cert_file"`                       // Certificado do cliente (mTLS)
+	KeyFile            string `json:"key_file" yaml:"key_file"`                         // Chave privada do cliente (mTLS)
+	ServerName         string `json:"server_name" yaml:"server_name"`                   // Nome esperado no certificado do servidor
+	InsecureSkipVerify bool   `json:"insecure_skip_verify" yaml:"insecure_skip_verify"` // Apenas para desenvolvimento
+	MinVersion         string `json:"min_version" yaml:"min_version"`                   // "1.2" ou "1.3"
+}
+
+// SASLConfig define a autenticação comum a todos os brokers
+type SASLConfig struct {
+	Mechanism       string `json:"mechanism" yaml:"mechanism"` // PLAIN, SCRAM-SHA-256, SCRAM-SHA-512, EXTERNAL, TOKEN, CREDENTIALS
+	Username        string `json:"username" yaml:"username"`
+	Password        string `json:"password" yaml:"password"`
+	Token           string `json:"token" yaml:"token"`
+	CredentialsFile string `json:"credentials_file" yaml:"credentials_file"` // Arquivo .creds do NATS
+}
+
+// Build gera o *tls.Config a partir da configuração
+func (c *TLSConfig) Build() (*tls.Config, error) {
+	if c == nil || !c.Enabled {
+		return nil, nil
+	}
+
+	cfg := &tls.Config{
+		ServerName:         c.ServerName,
+		InsecureSkipVerify: c.InsecureSkipVerify,
+		MinVersion:         tls.VersionTLS12,
+	}
+
+	switch c.MinVersion {
+	case "", "1.2":
+	case "1.3":
+		cfg.MinVersion = tls.VersionTLS13
+	default:
+		return nil, fmt.Errorf("versão mínima de TLS não suportada: %s", c.MinVersion)
+	}
+
+	if c.CAFile != "" {
+		caPEM, err := os.ReadFile(c.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao ler certificado da CA: %v", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caPEM) {
+			return nil, fmt.Errorf("certificado da CA inválido: %s", c.CAFile)
+		}
+		cfg.RootCAs = pool
+	}
+
+	if c.CertFile != "" || c.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(c.CertFile, c.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao carregar certificado do cliente: %v", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+
+	return cfg, nil
+}
+
+// tlsConfig resolve a configuração TLS, mantendo compatibilidade com o campo TLS booleano
+func (c *ConnectionConfig) tlsConfig() (*tls.Config, error) {
+	if c.TLSConfig != nil && c.TLSConfig.Enabled {
+		return c.TLSConfig.Build()
+	}
+	if c.TLS {
+		return &tls.Config{MinVersion: tls.VersionTLS12}, nil
+	}
+	return nil, nil
+}
+
+// sasl resolve a configuração SASL, usando Username/Password como PLAIN por padrão
+func (c *ConnectionConfig) sasl() *SASLConfig {
+	if c.SASL != nil && c.SASL.Mechanism != "" {
+		return c.SASL
+	}
+	if c.Username != "" {
+		return &SASLConfig{
+			Mechanism: SASLPlain,
+			Username:  c.Username,
+			Password:  c.Password,
+		}
+	}
+	return nil
+}
+
+// ConnectionConfigFromEnv carrega a configuração de um broker a partir de variáveis
+// de ambiente com o prefixo informado (ex.: RABBITMQ_HOST, RABBITMQ_TLS_CA_FILE)
+func ConnectionConfigFromEnv(prefix string, defaultHost string, defaultPort int) *ConnectionConfig {
+	env := func(name string) string {
+		return os.Getenv(prefix + "_" + name)
+	}
+	envBool := func(name string) bool {
+		value, _ := strconv.ParseBool(env(name))
+		return value
+	}
+
+	config := &ConnectionConfig{
+		Host:     defaultHost,
+		Port:     defaultPort,
+		Username: env("USERNAME"),
+		Password: env("PASSWORD"),
+		VHost:    env("VHOST"),
+	}
+
+	if host := env("HOST"); host != "" {
+		config.Host = host
+	}
+	if port, err := strconv.Atoi(env("PORT")); err == nil {
+		config.Port = port
+	}
+
+	if envBool("TLS_ENABLED") {
+		config.TLSConfig = &TLSConfig{
+			Enabled:            true,
+			CAFile:             env("TLS_CA_FILE"),
+			CertFile:           env("TLS_CERT_FILE"),
+			KeyFile:            env("TLS_KEY_FILE"),
+			ServerName:         env("TLS_SERVER_NAME"),
+			InsecureSkipVerify: envBool("TLS_INSECURE_SKIP_VERIFY"),
+			MinVersion:         env("TLS_MIN_VERSION"),
+		}
+	}
+
+	if mechanism := env("SASL_MECHANISM"); mechanism != "" {
+		config.SASL = &SASLConfig{
+			Mechanism:       strings.ToUpper(mechanism),
+			Username:        config.Username,
+			Password:        config.Password,
+			Token:           env("SASL_TOKEN"),
+			CredentialsFile: env("SASL_CREDENTIALS_FILE"),
+		}
+	}
+
+	return config
+}
@@ -0,0 +1,236 @@
+package communication
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeCertificate gera um certificado autoassinado e grava o certificado e a chave em PEM
+func writeCertificate(t *testing.T, dir string) (certFile, keyFile string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "hivemind-test"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	certFile = filepath.Join(dir, "cert.pem")
+	keyFile = filepath.Join(dir, "key.pem")
+	if err := os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return certFile, keyFile
+}
+
+func TestTLSConfigBuild(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := writeCertificate(t, dir)
+	invalidPEM := filepath.Join(dir, "invalid.pem")
+	if err := os.WriteFile(invalidPEM, []byte("não é um certificado"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		config     *TLSConfig
+		wantNil    bool
+		wantErr    bool
+		minVersion uint16
+		check      func(t *testing.T, cfg *tls.Config)
+	}{
+		{name: "Configuração nula", config: nil, wantNil: true},
+		{name: "TLS desativado", config: &TLSConfig{CAFile: certFile}, wantNil: true},
+		{name: "Versão padrão", config: &TLSConfig{Enabled: true}, minVersion: tls.VersionTLS12},
+		{name: "TLS 1.2", config: &TLSConfig{Enabled: true, MinVersion: "1.2"}, minVersion: tls.VersionTLS12},
+		{name: "TLS 1.3", config: &TLSConfig{Enabled: true, MinVersion: "1.3"}, minVersion: tls.VersionTLS13},
+		{name: "Versão não suportada", config: &TLSConfig{Enabled: true, MinVersion: "1.1"}, wantErr: true},
+		{
+			name:       "Nome do servidor e verificação desativada",
+			config:     &TLSConfig{Enabled: true, ServerName: "broker.internal", InsecureSkipVerify: true},
+			minVersion: tls.VersionTLS12,
+			check: func(t *testing.T, cfg *tls.Config) {
+				if cfg.ServerName != "broker.internal" || !cfg.InsecureSkipVerify {
+					t.Errorf("campos não repassados: %q %v", cfg.ServerName, cfg.InsecureSkipVerify)
+				}
+			},
+		},
+		{
+			name:       "CA",
+			config:     &TLSConfig{Enabled: true, CAFile: certFile},
+			minVersion: tls.VersionTLS12,
+			check: func(t *testing.T, cfg *tls.Config) {
+				if cfg.RootCAs == nil {
+					t.Error("esperava o pool com a CA")
+				}
+			},
+		},
+		{name: "CA ausente", config: &TLSConfig{Enabled: true, CAFile: filepath.Join(dir, "ausente.pem")}, wantErr: true},
+		{name: "CA inválida", config: &TLSConfig{Enabled: true, CAFile: invalidPEM}, wantErr: true},
+		{
+			name:       "Certificado do cliente (mTLS)",
+			config:     &TLSConfig{Enabled: true, CertFile: certFile, KeyFile: keyFile},
+			minVersion: tls.VersionTLS12,
+			check: func(t *testing.T, cfg *tls.Config) {
+				if len(cfg.Certificates) != 1 {
+					t.Errorf("esperava um certificado do cliente, obtidos %d", len(cfg.Certificates))
+				}
+			},
+		},
+		{name: "Certificado sem chave", config: &TLSConfig{Enabled: true, CertFile: certFile}, wantErr: true},
+		{name: "Chave sem certificado", config: &TLSConfig{Enabled: true, KeyFile: keyFile}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := tt.config.Build()
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("esperava erro")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.wantNil {
+				if cfg != nil {
+					t.Fatalf("esperava nil, obtido %+v", cfg)
+				}
+				return
+			}
+			if cfg.MinVersion != tt.minVersion {
+				t.Errorf("MinVersion %x, esperado %x", cfg.MinVersion, tt.minVersion)
+			}
+			if tt.check != nil {
+				tt.check(t, cfg)
+			}
+		})
+	}
+}
+
+func TestConnectionConfigTLSFallback(t *testing.T) {
+	tests := []struct {
+		name       string
+		config     ConnectionConfig
+		wantNil    bool
+		minVersion uint16
+	}{
+		{name: "Sem TLS", config: ConnectionConfig{}, wantNil: true},
+		{name: "Campo TLS legado", config: ConnectionConfig{TLS: true}, minVersion: tls.VersionTLS12},
+		{name: "TLSConfig desativado e campo legado", config: ConnectionConfig{TLS: true, TLSConfig: &TLSConfig{MinVersion: "1.3"}}, minVersion: tls.VersionTLS12},
+		{name: "TLSConfig prevalece", config: ConnectionConfig{TLS: true, TLSConfig: &TLSConfig{Enabled: true, MinVersion: "1.3"}}, minVersion: tls.VersionTLS13},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := tt.config.tlsConfig()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.wantNil != (cfg == nil) {
+				t.Fatalf("configuração inesperada: %+v", cfg)
+			}
+			if cfg != nil && cfg.MinVersion != tt.minVersion {
+				t.Errorf("MinVersion %x, esperado %x", cfg.MinVersion, tt.minVersion)
+			}
+		})
+	}
+}
+
+func TestConnectionConfigSASLFallback(t *testing.T) {
+	scram := &SASLConfig{Mechanism: SASLScramSHA512, Username: "svc", Password: "scram"}
+
+	tests := []struct {
+		name   string
+		config ConnectionConfig
+		want   *SASLConfig
+	}{
+		{name: "Sem credenciais", config: ConnectionConfig{}},
+		{name: "Só a senha", config: ConnectionConfig{Password: "secret"}},
+		{
+			name:   "Usuário e senha viram PLAIN",
+			config: ConnectionConfig{Username: "guest", Password: "secret"},
+			want:   &SASLConfig{Mechanism: SASLPlain, Username: "guest", Password: "secret"},
+		},
+		{
+			name:   "SASL explícito prevalece",
+			config: ConnectionConfig{Username: "guest", Password: "secret", SASL: scram},
+			want:   scram,
+		},
+		{
+			name:   "SASL sem mecanismo usa o usuário",
+			config: ConnectionConfig{Username: "guest", Password: "secret", SASL: &SASLConfig{Token: "t"}},
+			want:   &SASLConfig{Mechanism: SASLPlain, Username: "guest", Password: "secret"},
+		},
+		{name: "SASL sem mecanismo e sem usuário", config: ConnectionConfig{SASL: &SASLConfig{Token: "t"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.config.sasl()
+			if tt.want == nil {
+				if got != nil {
+					t.Fatalf("esperava sem SASL, obtido %+v", got)
+				}
+				return
+			}
+			if got == nil || *got != *tt.want {
+				t.Fatalf("SASL %+v, esperado %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConnectionConfigFromEnv(t *testing.T) {
+	t.Setenv("KAFKA_HOST", "broker.internal")
+	t.Setenv("KAFKA_PORT", "9093")
+	t.Setenv("KAFKA_USERNAME", "svc")
+	t.Setenv("KAFKA_PASSWORD", "secret")
+	t.Setenv("KAFKA_TLS_ENABLED", "true")
+	t.Setenv("KAFKA_TLS_MIN_VERSION", "1.3")
+	t.Setenv("KAFKA_TLS_SERVER_NAME", "kafka")
+	t.Setenv("KAFKA_SASL_MECHANISM", "scram-sha-256")
+
+	config := ConnectionConfigFromEnv("KAFKA", "localhost", 9092)
+	if config.Host != "broker.internal" || config.Port != 9093 {
+		t.Fatalf("endereço inesperado: %s:%d", config.Host, config.Port)
+	}
+	if config.TLSConfig == nil || !config.TLSConfig.Enabled || config.TLSConfig.MinVersion != "1.3" || config.TLSConfig.ServerName != "kafka" {
+		t.Fatalf("TLS inesperado: %+v", config.TLSConfig)
+	}
+	if sasl := config.sasl(); sasl == nil || sasl.Mechanism != SASLScramSHA256 || sasl.Username != "svc" || sasl.Password != "secret" {
+		t.Fatalf("SASL inesperado: %+v", sasl)
+	}
+
+	// Sem variáveis, valem os padrões e nem TLS nem SASL
+	config = ConnectionConfigFromEnv("NATS", "localhost", 4222)
+	if config.Host != "localhost" || config.Port != 4222 || config.TLSConfig != nil || config.sasl() != nil {
+		t.Fatalf("configuração padrão inesperada: %+v", config)
+	}
+}
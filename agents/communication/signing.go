@@ -0,0 +1,351 @@
+package communication
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/streadway/amqp"
+
+	"github.com/suissa/HiveMind/agents/errs"
+)
+
+// Algoritmos de assinatura suportados
+const (
+	SignatureHMACSHA256 = "HMAC-SHA256"
+	SignatureEd25519    = "Ed25519"
+)
+
+// Headers AMQP usados para transportar a assinatura
+const (
+	HeaderSignature    = "x-hivemind-signature"
+	HeaderSignatureAlg = "x-hivemind-signature-alg"
+	HeaderKeyID        = "x-hivemind-key-id"
+	HeaderSignedAt     = "x-hivemind-signed-at"
+)
+
+// DefaultSignatureMaxAge é a idade máxima aceita para uma mensagem assinada
+const DefaultSignatureMaxAge = 5 * time.Minute
+
+// Signer assina o conteúdo das mensagens publicadas
+type Signer interface {
+	// KeyID identifica a chave usada, permitindo rotação
+	KeyID() string
+	// Algorithm retorna o algoritmo de assinatura
+	Algorithm() string
+	// Sign assina os dados
+	Sign(data []byte) ([]byte, error)
+}
+
+// Verifier verifica a assinatura das mensagens consumidas
+type Verifier interface {
+	Verify(keyID, algorithm string, data, signature []byte) error
+}
+
+// HMACSigner assina mensagens com HMAC-SHA256 e um segredo compartilhado
+type HMACSigner struct {
+	keyID  string
+	secret []byte
+}
+
+// NewHMACSigner cria um Signer HMAC-SHA256
+func NewHMACSigner(keyID string, secret []byte) *HMACSigner {
+	return &HMACSigner{keyID: keyID, secret: secret}
+}
+
+func (s *HMACSigner) KeyID() string     { return s.keyID }
+func (s *HMACSigner) Algorithm() string { return SignatureHMACSHA256 }
+
+// Sign implementa Signer
+func (s *HMACSigner) Sign(data []byte) ([]byte, error) {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write(data)
+	return mac.Sum(nil), nil
+}
+
+// Ed25519Signer assina mensagens com uma chave privada Ed25519
+type Ed25519Signer struct {
+	keyID string
+	key   ed25519.PrivateKey
+}
+
+// NewEd25519Signer cria um Signer Ed25519
+func NewEd25519Signer(keyID string, key ed25519.PrivateKey) (*Ed25519Signer, error) {
+	if len(key) != ed25519.PrivateKeySize {
+		return nil, fmt.Errorf("chave privada Ed25519 inválida para %s", keyID)
+	}
+	return &Ed25519Signer{keyID: keyID, key: key}, nil
+}
+
+func (s *Ed25519Signer) KeyID() string     { return s.keyID }
+func (s *Ed25519Signer) Algorithm() string { return SignatureEd25519 }
+
+// Sign implementa Signer
+func (s *Ed25519Signer) Sign(data []byte) ([]byte, error) {
+	return ed25519.Sign(s.key, data), nil
+}
+
+// KeyRing guarda as chaves confiáveis para verificação das assinaturas
+type KeyRing struct {
+	hmacKeys    map[string][]byte
+	ed25519Keys map[string]ed25519.PublicKey
+	mu          sync.RWMutex
+}
+
+// NewKeyRing cria um KeyRing vazio
+func NewKeyRing() *KeyRing {
+	return &KeyRing{
+		hmacKeys:    make(map[string][]byte),
+		ed25519Keys: make(map[string]ed25519.PublicKey),
+	}
+}
+
+// AddHMACKey registra um segredo HMAC confiável
+func (k *KeyRing) AddHMACKey(keyID string, secret []byte) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.hmacKeys[keyID] = secret
+}
+
+// AddEd25519Key registra uma chave pública Ed25519 confiável
+func (k *KeyRing) AddEd25519Key(keyID string, key ed25519.PublicKey) error {
+	if len(key) != ed25519.PublicKeySize {
+		return fmt.Errorf("chave pública Ed25519 inválida para %s", keyID)
+	}
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.ed25519Keys[keyID] = key
+	return nil
+}
+
+// RemoveKey revoga uma chave
+func (k *KeyRing) RemoveKey(keyID string) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	delete(k.hmacKeys, keyID)
+	delete(k.ed25519Keys, keyID)
+}
+
+// Verify implementa Verifier
+func (k *KeyRing) Verify(keyID, algorithm string, data, signature []byte) error {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	switch algorithm {
+	case SignatureHMACSHA256:
+		secret, ok := k.hmacKeys[keyID]
+		if !ok {
+			return errs.New(errs.ErrValidation, "signing", "chave de assinatura desconhecida: %s", keyID)
+		}
+		mac := hmac.New(sha256.New, secret)
+		mac.Write(data)
+		if !hmac.Equal(mac.Sum(nil), signature) {
+			return errs.New(errs.ErrValidation, "signing", "assinatura inválida para a chave %s", keyID)
+		}
+	case SignatureEd25519:
+		key, ok := k.ed25519Keys[keyID]
+		if !ok {
+			return errs.New(errs.ErrValidation, "signing", "chave de assinatura desconhecida: %s", keyID)
+		}
+		if !ed25519.Verify(key, data, signature) {
+			return errs.New(errs.ErrValidation, "signing", "assinatura inválida para a chave %s", keyID)
+		}
+	default:
+		return errs.New(errs.ErrValidation, "signing", "algoritmo de assinatura não suportado: %s", algorithm)
+	}
+
+	return nil
+}
+
+// SignedEnvelope encapsula uma mensagem assinada
+type SignedEnvelope struct {
+	KeyID     string `json:"key_id"`
+	Algorithm string `json:"alg"`
+	SignedAt  int64  `json:"signed_at"` // Unix em milissegundos
+	Payload   []byte `json:"payload"`
+	Signature []byte `json:"signature"`
+}
+
+// signingInput monta o conteúdo assinado, vinculando a assinatura ao tópico e ao horário
+func signingInput(subject string, signedAt int64, payload []byte) []byte {
+	prefix := subject + "\n" + strconv.FormatInt(signedAt, 10) + "\n"
+	return append([]byte(prefix), payload...)
+}
+
+// SignPayload assina um payload para o tópico informado
+func SignPayload(signer Signer, subject string, payload []byte) (*SignedEnvelope, error) {
+	signedAt := time.Now().UnixMilli()
+	signature, err := signer.Sign(signingInput(subject, signedAt, payload))
+	if err != nil {
+		return nil, fmt.Errorf("erro ao assinar mensagem: %v", err)
+	}
+
+	return &SignedEnvelope{
+		KeyID:     signer.KeyID(),
+		Algorithm: signer.Algorithm(),
+		SignedAt:  signedAt,
+		Payload:   payload,
+		Signature: signature,
+	}, nil
+}
+
+// VerifyEnvelope verifica a assinatura e a idade de um envelope (maxAge 0 desativa a checagem de idade)
+func VerifyEnvelope(verifier Verifier, subject string, envelope *SignedEnvelope, maxAge time.Duration) error {
+	if maxAge > 0 {
+		age := time.Since(time.UnixMilli(envelope.SignedAt))
+		if age > maxAge || age < -maxAge {
+			return errs.New(errs.ErrValidation, "signing", "mensagem assinada fora da janela de validade: %v", age)
+		}
+	}
+	return verifier.Verify(envelope.KeyID, envelope.Algorithm, signingInput(subject, envelope.SignedAt, envelope.Payload), envelope.Signature)
+}
+
+// SignedClient decora um CommunicationClient assinando as mensagens publicadas
+// e verificando as mensagens consumidas
+type SignedClient struct {
+	CommunicationClient
+	signer        Signer
+	verifier      Verifier
+	maxAge        time.Duration
+	allowUnsigned bool
+}
+
+// NewSignedClient cria um cliente com assinatura. O signer ou o verifier podem ser nil
+// quando o componente apenas consome ou apenas publica.
+func NewSignedClient(client CommunicationClient, signer Signer, verifier Verifier) *SignedClient {
+	return &SignedClient{
+		CommunicationClient: client,
+		signer:              signer,
+		verifier:            verifier,
+		maxAge:              DefaultSignatureMaxAge,
+	}
+}
+
+// SetMaxAge define a idade máxima aceita para as mensagens (0 desativa)
+func (c *SignedClient) SetMaxAge(maxAge time.Duration) {
+	c.maxAge = maxAge
+}
+
+// SetAllowUnsigned aceita mensagens sem assinatura, útil durante a migração dos publicadores
+func (c *SignedClient) SetAllowUnsigned(allow bool) {
+	c.allowUnsigned = allow
+}
+
+// Publish assina e publica a mensagem
+func (c *SignedClient) Publish(ctx context.Context, subject string, data []byte) error {
+	body, err := c.seal(subject, data)
+	if err != nil {
+		return err
+	}
+	return c.CommunicationClient.Publish(ctx, subject, body)
+}
+
+// Request assina a requisição e verifica a resposta
+func (c *SignedClient) Request(ctx context.Context, subject string, data []byte, timeout int) ([]byte, error) {
+	body, err := c.seal(subject, data)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := c.CommunicationClient.Request(ctx, subject, body, timeout)
+	if err != nil {
+		return nil, err
+	}
+	return c.open(subject, response)
+}
+
+// Subscribe registra um handler que só recebe mensagens com assinatura válida
+func (c *SignedClient) Subscribe(subject string, handler MessageHandler) error {
+	return c.CommunicationClient.Subscribe(subject, func(ctx context.Context, msgSubject string, data []byte) error {
+		payload, err := c.open(msgSubject, data)
+		if err != nil {
+			return err
+		}
+		return handler(ctx, msgSubject, payload)
+	})
+}
+
+// seal assina o payload quando há um signer configurado
+func (c *SignedClient) seal(subject string, data []byte) ([]byte, error) {
+	if c.signer == nil {
+		return data, nil
+	}
+
+	envelope, err := SignPayload(c.signer, subject, data)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := json.Marshal(envelope)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao serializar mensagem assinada: %v", err)
+	}
+	return body, nil
+}
+
+// open verifica e extrai o payload de uma mensagem assinada
+func (c *SignedClient) open(subject string, data []byte) ([]byte, error) {
+	if c.verifier == nil {
+		return data, nil
+	}
+
+	var envelope SignedEnvelope
+	if err := json.Unmarshal(data, &envelope); err != nil || envelope.Signature == nil {
+		if c.allowUnsigned {
+			return data, nil
+		}
+		return nil, errs.New(errs.ErrValidation, "signing", "mensagem sem assinatura recebida em %s", subject)
+	}
+
+	if err := VerifyEnvelope(c.verifier, subject, &envelope, c.maxAge); err != nil {
+		return nil, err
+	}
+	return envelope.Payload, nil
+}
+
+// SignAMQP assina uma publicação AMQP, guardando a assinatura nos headers
+func SignAMQP(signer Signer, routingKey string, msg *amqp.Publishing) error {
+	envelope, err := SignPayload(signer, routingKey, msg.Body)
+	if err != nil {
+		return err
+	}
+
+	if msg.Headers == nil {
+		msg.Headers = amqp.Table{}
+	}
+	msg.Headers[HeaderSignature] = base64.StdEncoding.EncodeToString(envelope.Signature)
+	msg.Headers[HeaderSignatureAlg] = envelope.Algorithm
+	msg.Headers[HeaderKeyID] = envelope.KeyID
+	msg.Headers[HeaderSignedAt] = envelope.SignedAt
+	return nil
+}
+
+// VerifyAMQP verifica a assinatura de uma mensagem AMQP consumida
+func VerifyAMQP(verifier Verifier, delivery *amqp.Delivery, maxAge time.Duration) error {
+	encoded, _ := delivery.Headers[HeaderSignature].(string)
+	if encoded == "" {
+		return errs.New(errs.ErrValidation, "signing", "mensagem sem assinatura recebida em %s", delivery.RoutingKey)
+	}
+
+	signature, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return errs.Wrap(errs.ErrValidation, "signing", err, "assinatura malformada")
+	}
+
+	envelope := &SignedEnvelope{
+		Payload:   delivery.Body,
+		Signature: signature,
+	}
+	envelope.Algorithm, _ = delivery.Headers[HeaderSignatureAlg].(string)
+	envelope.KeyID, _ = delivery.Headers[HeaderKeyID].(string)
+	envelope.SignedAt, _ = delivery.Headers[HeaderSignedAt].(int64)
+
+	return VerifyEnvelope(verifier, delivery.RoutingKey, envelope, maxAge)
+}
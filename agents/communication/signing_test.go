@@ -0,0 +1,129 @@
+package communication
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"testing"
+	"time"
+
+	"github.com/streadway/amqp"
+)
+
+// loopbackClient entrega as mensagens publicadas diretamente aos handlers inscritos
+type loopbackClient struct {
+	handlers map[string]MessageHandler
+}
+
+func (c *loopbackClient) Connect(ctx context.Context) error { return nil }
+func (c *loopbackClient) Disconnect() error                 { return nil }
+func (c *loopbackClient) Subscribe(subject string, handler MessageHandler) error {
+	c.handlers[subject] = handler
+	return nil
+}
+func (c *loopbackClient) Unsubscribe(subject string) error { delete(c.handlers, subject); return nil }
+func (c *loopbackClient) Publish(ctx context.Context, subject string, data []byte) error {
+	if handler, ok := c.handlers[subject]; ok {
+		return handler(ctx, subject, data)
+	}
+	return nil
+}
+func (c *loopbackClient) Request(ctx context.Context, subject string, data []byte, timeout int) ([]byte, error) {
+	return data, nil
+}
+func (c *loopbackClient) GetStatus() *ClientStatus   { return &ClientStatus{} }
+func (c *loopbackClient) GetSubscriptions() []string { return nil }
+
+func TestSignedClient(t *testing.T) {
+	keys := NewKeyRing()
+	keys.AddHMACKey("team-a", []byte("segredo"))
+
+	transport := &loopbackClient{handlers: make(map[string]MessageHandler)}
+	client := NewSignedClient(transport, NewHMACSigner("team-a", []byte("segredo")), keys)
+	ctx := context.Background()
+
+	var received []byte
+	if err := client.Subscribe("tasks", func(ctx context.Context, subject string, data []byte) error {
+		received = data
+		return nil
+	}); err != nil {
+		t.Fatalf("Erro ao se inscrever: %v", err)
+	}
+
+	if err := client.Publish(ctx, "tasks", []byte("tarefa")); err != nil {
+		t.Fatalf("Erro ao publicar: %v", err)
+	}
+	if string(received) != "tarefa" {
+		t.Errorf("Payload incorreto. Esperado: tarefa, Recebido: %s", received)
+	}
+
+	// Mensagem publicada sem assinatura deve ser rejeitada
+	if err := transport.Publish(ctx, "tasks", []byte("forjada")); err == nil {
+		t.Error("Mensagem sem assinatura deveria ser rejeitada")
+	}
+
+	// Mensagem assinada com chave desconhecida deve ser rejeitada
+	intruder := NewSignedClient(transport, NewHMACSigner("team-b", []byte("outro")), nil)
+	if err := intruder.Publish(ctx, "tasks", []byte("forjada")); err == nil {
+		t.Error("Mensagem com chave desconhecida deveria ser rejeitada")
+	}
+}
+
+func TestVerifyEnvelope(t *testing.T) {
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("Erro ao gerar chave: %v", err)
+	}
+
+	signer, err := NewEd25519Signer("approvals", private)
+	if err != nil {
+		t.Fatalf("Erro ao criar signer: %v", err)
+	}
+	keys := NewKeyRing()
+	if err := keys.AddEd25519Key("approvals", public); err != nil {
+		t.Fatalf("Erro ao registrar chave: %v", err)
+	}
+
+	envelope, err := SignPayload(signer, "gates.approve", []byte(`{"approved":true}`))
+	if err != nil {
+		t.Fatalf("Erro ao assinar: %v", err)
+	}
+
+	if err := VerifyEnvelope(keys, "gates.approve", envelope, time.Minute); err != nil {
+		t.Errorf("Assinatura válida rejeitada: %v", err)
+	}
+	if err := VerifyEnvelope(keys, "gates.reject", envelope, time.Minute); err == nil {
+		t.Error("Assinatura não deveria valer para outro tópico")
+	}
+
+	envelope.Payload = []byte(`{"approved":false}`)
+	if err := VerifyEnvelope(keys, "gates.approve", envelope, time.Minute); err == nil {
+		t.Error("Payload adulterado deveria ser rejeitado")
+	}
+
+	expired, _ := SignPayload(signer, "gates.approve", []byte("{}"))
+	expired.SignedAt = time.Now().Add(-time.Hour).UnixMilli()
+	if err := VerifyEnvelope(keys, "gates.approve", expired, time.Minute); err == nil {
+		t.Error("Mensagem expirada deveria ser rejeitada")
+	}
+}
+
+func TestSignAMQP(t *testing.T) {
+	keys := NewKeyRing()
+	keys.AddHMACKey("router", []byte("segredo"))
+
+	msg := amqp.Publishing{Body: []byte(`{"id":"1"}`)}
+	if err := SignAMQP(NewHMACSigner("router", []byte("segredo")), "llm_input", &msg); err != nil {
+		t.Fatalf("Erro ao assinar: %v", err)
+	}
+
+	delivery := amqp.Delivery{RoutingKey: "llm_input", Headers: msg.Headers, Body: msg.Body}
+	if err := VerifyAMQP(keys, &delivery, time.Minute); err != nil {
+		t.Errorf("Assinatura válida rejeitada: %v", err)
+	}
+
+	delivery.Body = []byte(`{"id":"2"}`)
+	if err := VerifyAMQP(keys, &delivery, time.Minute); err == nil {
+		t.Error("Corpo adulterado deveria ser rejeitado")
+	}
+}
@@ -0,0 +1,134 @@
+package communication
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/suissa/HiveMind/agents/errs"
+)
+
+// Curingas dos tópicos, com a semântica das topic exchanges do AMQP em todos os
+// transportes: os tópicos são separados por ponto, "*" casa exatamente um segmento e
+// "#" casa zero ou mais segmentos ("tasks.*" casa "tasks.new"; "metrics.#" casa "metrics"
+// e "metrics.cpu.load").
+const (
+	WildcardOne  = "*"
+	WildcardMany = "#"
+)
+
+// IsPattern informa se o tópico contém curingas
+func IsPattern(subject string) bool {
+	for _, token := range strings.Split(subject, ".") {
+		if token == WildcardOne || token == WildcardMany {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateSubject verifica um tópico ou padrão: os segmentos não podem ser vazios e os
+// curingas devem ocupar um segmento inteiro
+func ValidateSubject(subject string) error {
+	if subject == "" {
+		return errs.New(errs.ErrValidation, "communication.ValidateSubject", "tópico vazio")
+	}
+	for _, token := range strings.Split(subject, ".") {
+		switch {
+		case token == "":
+			return errs.New(errs.ErrValidation, "communication.ValidateSubject", "tópico %q com segmento vazio", subject)
+		case token != WildcardOne && token != WildcardMany && strings.ContainsAny(token, "*#>"):
+			return errs.New(errs.ErrValidation, "communication.ValidateSubject", "curinga deve ocupar um segmento inteiro em %q", subject)
+		}
+	}
+	return nil
+}
+
+// MatchSubject informa se o tópico casa com o padrão (ou é igual a ele, sem curingas)
+func MatchSubject(pattern, subject string) bool {
+	if pattern == subject {
+		return true
+	}
+	return matchTokens(strings.Split(pattern, "."), strings.Split(subject, "."))
+}
+
+// matchTokens casa os segmentos do padrão com os do tópico
+func matchTokens(pattern, subject []string) bool {
+	for len(pattern) > 0 {
+		switch pattern[0] {
+		case WildcardMany:
+			// "#" consome de zero a todos os segmentos restantes
+			for skip := 0; skip <= len(subject); skip++ {
+				if matchTokens(pattern[1:], subject[skip:]) {
+					return true
+				}
+			}
+			return false
+		case WildcardOne:
+			if len(subject) == 0 {
+				return false
+			}
+		default:
+			if len(subject) == 0 || pattern[0] != subject[0] {
+				return false
+			}
+		}
+		pattern, subject = pattern[1:], subject[1:]
+	}
+	return len(subject) == 0
+}
+
+// natsSubjects traduz o padrão para os tópicos do NATS que o cobrem. O "*" é o mesmo no
+// NATS; o ">" do NATS equivale a um ou mais segmentos e só pode ser o último, então o
+// padrão a partir do primeiro "#" vira ">" (e o prefixo sozinho, quando "#" pode casar
+// zero segmentos). O resultado pode ser mais amplo que o padrão: as mensagens recebidas
+// ainda passam por MatchSubject.
+func natsSubjects(pattern string) []string {
+	tokens := strings.Split(pattern, ".")
+	first := -1
+	for i, token := range tokens {
+		if token == WildcardMany {
+			first = i
+			break
+		}
+	}
+	if first < 0 {
+		return []string{pattern}
+	}
+	if first == 0 {
+		return []string{">"}
+	}
+
+	prefix := strings.Join(tokens[:first], ".")
+	subjects := []string{prefix + ".>"}
+	if MatchSubject(pattern, prefix) {
+		subjects = append(subjects, prefix)
+	}
+	return subjects
+}
+
+// matchingTopics retorna, em ordem, os tópicos existentes que casam com o padrão
+func matchingTopics(pattern string, topics []string) []string {
+	var matched []string
+	for _, topic := range topics {
+		if MatchSubject(pattern, topic) {
+			matched = append(matched, topic)
+		}
+	}
+	sort.Strings(matched)
+	return matched
+}
+
+// matchingHandlers retorna os handlers inscritos no tópico exato e nos padrões que casam
+// com ele, como cada inscrição recebe a mensagem em um broker com curingas
+func matchingHandlers(handlers map[string]MessageHandler, subject string) []MessageHandler {
+	var matched []MessageHandler
+	if handler, ok := handlers[subject]; ok && handler != nil {
+		matched = append(matched, handler)
+	}
+	for pattern, handler := range handlers {
+		if pattern != subject && handler != nil && IsPattern(pattern) && MatchSubject(pattern, subject) {
+			matched = append(matched, handler)
+		}
+	}
+	return matched
+}
@@ -0,0 +1,66 @@
+package communication
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMatchSubject(t *testing.T) {
+	cases := []struct {
+		pattern, subject string
+		want             bool
+	}{
+		{"tasks.*", "tasks.new", true},
+		{"tasks.*", "tasks", false},
+		{"tasks.*", "tasks.new.urgent", false},
+		{"metrics.#", "metrics", true},
+		{"metrics.#", "metrics.cpu.load", true},
+		{"#", "anything.at.all", true},
+		{"a.#.z", "a.z", true},
+		{"a.#.z", "a.b.c.z", true},
+		{"a.#.z", "a.b.c", false},
+		{"*.events.#", "agent1.events.task.done", true},
+		{"tasks.new", "tasks.new", true},
+		{"tasks.new", "tasks.old", false},
+	}
+	for _, c := range cases {
+		if got := MatchSubject(c.pattern, c.subject); got != c.want {
+			t.Errorf("MatchSubject(%q, %q) = %v, esperado %v", c.pattern, c.subject, got, c.want)
+		}
+	}
+}
+
+func TestValidateSubject(t *testing.T) {
+	for _, subject := range []string{"tasks.*", "metrics.#", "a.b.c"} {
+		if err := ValidateSubject(subject); err != nil {
+			t.Errorf("%q deveria ser válido: %v", subject, err)
+		}
+	}
+	for _, subject := range []string{"", "tasks.", "tasks.a*", "metrics.#x", "a..b", "a.>"} {
+		if err := ValidateSubject(subject); err == nil {
+			t.Errorf("%q deveria ser inválido", subject)
+		}
+	}
+}
+
+func TestNatsSubjects(t *testing.T) {
+	cases := map[string][]string{
+		"tasks.*":    {"tasks.*"},
+		"metrics.#":  {"metrics.>", "metrics"},
+		"a.#.z":      {"a.>"},
+		"#":          {">"},
+		"*.events.#": {"*.events.>", "*.events"},
+	}
+	for pattern, want := range cases {
+		if got := natsSubjects(pattern); !reflect.DeepEqual(got, want) {
+			t.Errorf("natsSubjects(%q) = %v, esperado %v", pattern, got, want)
+		}
+	}
+}
+
+func TestMatchingTopics(t *testing.T) {
+	got := matchingTopics("tasks.*", []string{"tasks.new", "metrics.cpu", "tasks.done", "tasks.new.urgent"})
+	if want := []string{"tasks.done", "tasks.new"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("tópicos inesperados: %v", got)
+	}
+}
@@ -0,0 +1,202 @@
+package communication
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/suissa/HiveMind/agents/codec"
+	"github.com/suissa/HiveMind/agents/errs"
+)
+
+// Headers de negociação de conteúdo, com os mesmos nomes em todos os transportes
+const (
+	HeaderContentType = "content-type"
+	HeaderAccept      = "accept"
+)
+
+// HeaderHandler processa mensagens recebidas com os headers do transporte
+type HeaderHandler func(ctx context.Context, subject string, data []byte, headers map[string]string) error
+
+// HeaderClient é implementado pelos clientes cujo transporte carrega headers (NATS, Kafka
+// e AMQP). Nos demais, TypedClient leva o tipo de conteúdo em um envelope JSON.
+type HeaderClient interface {
+	PublishHeaders(ctx context.Context, subject string, data []byte, headers map[string]string) error
+	RequestHeaders(ctx context.Context, subject string, data []byte, headers map[string]string, timeout int) ([]byte, map[string]string, error)
+	SubscribeHeaders(subject string, handler HeaderHandler) error
+}
+
+// withoutHeaders adapta um MessageHandler para receber mensagens com headers
+func withoutHeaders(handler MessageHandler) HeaderHandler {
+	if handler == nil {
+		return nil
+	}
+	return func(ctx context.Context, subject string, data []byte, _ map[string]string) error {
+		return handler(ctx, subject, data)
+	}
+}
+
+// typedEnvelope leva o tipo de conteúdo nos transportes sem headers
+type typedEnvelope struct {
+	ContentType string `json:"content_type"`
+	Accept      string `json:"accept,omitempty"`
+	Payload     []byte `json:"payload"`
+}
+
+// TypedMessage é uma mensagem recebida por SubscribeValue
+type TypedMessage struct {
+	Subject     string
+	ContentType string
+	Accept      string // Formatos aceitos pelo remetente para a resposta
+	Data        []byte
+	codec       codec.Codec
+}
+
+// Decode desserializa o conteúdo em v com o codec do tipo de conteúdo da mensagem
+func (m *TypedMessage) Decode(v interface{}) error {
+	return m.codec.Unmarshal(m.Data, v)
+}
+
+// ReplyCodec escolhe, pelo Accept do remetente, o codec para responder à mensagem
+func (m *TypedMessage) ReplyCodec() codec.Codec {
+	return codec.Default.Negotiate(m.Accept, m.codec)
+}
+
+// TypedHandler processa uma mensagem tipada
+type TypedHandler func(ctx context.Context, msg *TypedMessage) error
+
+// TypedClient envia e recebe valores serializados por um codec. O tipo de conteúdo vai no
+// header content-type quando o transporte tem headers, ou em um envelope JSON quando não
+// tem, e as mensagens recebidas são decodificadas pelo codec do tipo informado.
+//
+//	client := communication.NewTypedClient(natsClient, codec.MsgPack)
+//	client.PublishValue(ctx, "tasks.new", Task{ID: "t-1"})
+type TypedClient struct {
+	CommunicationClient
+	codec  codec.Codec
+	codecs *codec.Registry
+}
+
+// NewTypedClient cria um cliente que serializa com o codec informado (nil usa JSON)
+func NewTypedClient(client CommunicationClient, c codec.Codec) *TypedClient {
+	if c == nil {
+		c = codec.JSON
+	}
+	return &TypedClient{CommunicationClient: client, codec: c, codecs: codec.Default}
+}
+
+// Codec retorna o codec usado nas mensagens enviadas
+func (c *TypedClient) Codec() codec.Codec {
+	return c.codec
+}
+
+// PublishValue serializa e publica o valor
+func (c *TypedClient) PublishValue(ctx context.Context, subject string, v interface{}) error {
+	data, err := c.codec.Marshal(v)
+	if err != nil {
+		return errs.Wrap(errs.ErrValidation, "communication.PublishValue", err, "erro ao serializar mensagem para %s", subject)
+	}
+	if hc, ok := c.CommunicationClient.(HeaderClient); ok {
+		return hc.PublishHeaders(ctx, subject, data, map[string]string{HeaderContentType: c.codec.ContentType()})
+	}
+	body, err := c.seal(data)
+	if err != nil {
+		return err
+	}
+	return c.CommunicationClient.Publish(ctx, subject, body)
+}
+
+// RequestValue serializa a requisição, aguarda a resposta e a desserializa em resp com o
+// codec do tipo de conteúdo da resposta. O Accept da requisição informa os formatos aceitos,
+// preferindo o do cliente.
+func (c *TypedClient) RequestValue(ctx context.Context, subject string, req, resp interface{}, timeout int) error {
+	data, err := c.codec.Marshal(req)
+	if err != nil {
+		return errs.Wrap(errs.ErrValidation, "communication.RequestValue", err, "erro ao serializar requisição para %s", subject)
+	}
+
+	var response []byte
+	var headers map[string]string
+	if hc, ok := c.CommunicationClient.(HeaderClient); ok {
+		response, headers, err = hc.RequestHeaders(ctx, subject, data, map[string]string{
+			HeaderContentType: c.codec.ContentType(),
+			HeaderAccept:      c.accept(),
+		}, timeout)
+	} else {
+		var body []byte
+		if body, err = c.seal(data); err == nil {
+			response, err = c.CommunicationClient.Request(ctx, subject, body, timeout)
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	msg, err := c.open(subject, response, headers)
+	if err != nil {
+		return err
+	}
+	return msg.Decode(resp)
+}
+
+// SubscribeValue registra um handler que recebe as mensagens prontas para decodificação
+func (c *TypedClient) SubscribeValue(subject string, handler TypedHandler) error {
+	receive := func(ctx context.Context, msgSubject string, data []byte, headers map[string]string) error {
+		msg, err := c.open(msgSubject, data, headers)
+		if err != nil {
+			return err
+		}
+		return handler(ctx, msg)
+	}
+	if hc, ok := c.CommunicationClient.(HeaderClient); ok {
+		return hc.SubscribeHeaders(subject, receive)
+	}
+	return c.CommunicationClient.Subscribe(subject, func(ctx context.Context, msgSubject string, data []byte) error {
+		return receive(ctx, msgSubject, data, nil)
+	})
+}
+
+// accept monta o Accept das requisições, com o codec do cliente em primeiro lugar
+func (c *TypedClient) accept() string {
+	accept := c.codec.ContentType()
+	for _, contentType := range c.codecs.ContentTypes() {
+		if contentType != accept {
+			accept += ", " + contentType
+		}
+	}
+	return accept
+}
+
+// seal envolve o conteúdo serializado no envelope dos transportes sem headers
+func (c *TypedClient) seal(data []byte) ([]byte, error) {
+	body, err := json.Marshal(typedEnvelope{ContentType: c.codec.ContentType(), Accept: c.accept(), Payload: data})
+	if err != nil {
+		return nil, fmt.Errorf("erro ao serializar envelope: %v", err)
+	}
+	return body, nil
+}
+
+// open identifica o tipo de conteúdo da mensagem pelos headers ou pelo envelope. Sem
+// nenhum dos dois, a mensagem é tratada como serializada pelo codec do cliente.
+func (c *TypedClient) open(subject string, data []byte, headers map[string]string) (*TypedMessage, error) {
+	msg := &TypedMessage{Subject: subject, Data: data}
+	if headers != nil {
+		msg.ContentType, msg.Accept = headers[HeaderContentType], headers[HeaderAccept]
+	} else {
+		var envelope typedEnvelope
+		if err := json.Unmarshal(data, &envelope); err == nil && envelope.ContentType != "" {
+			msg.ContentType, msg.Accept, msg.Data = envelope.ContentType, envelope.Accept, envelope.Payload
+		}
+	}
+
+	if msg.ContentType == "" {
+		msg.ContentType, msg.codec = c.codec.ContentType(), c.codec
+		return msg, nil
+	}
+	found, ok := c.codecs.Lookup(msg.ContentType)
+	if !ok {
+		return nil, errs.New(errs.ErrValidation, "communication.TypedClient", "tipo de conteúdo %s não suportado em %s", msg.ContentType, subject)
+	}
+	msg.codec = found
+	return msg, nil
+}
@@ -9,6 +9,8 @@ import (
 	"time"
 
 	"github.com/gorilla/websocket"
+
+	"github.com/suissa/HiveMind/agents/errs"
 )
 
 // WebSocketClient implementa a interface CommunicationClient usando WebSocket
@@ -19,10 +21,13 @@ type WebSocketClient struct {
 	handlers map[string]MessageHandler
 	mu       sync.RWMutex
 	done     chan struct{}
+	ctx      context.Context // Cancelado no Disconnect, interrompendo os handlers em execução
+	cancel   context.CancelFunc
 }
 
 // NewWebSocketClient cria uma nova instância do cliente WebSocket
 func NewWebSocketClient(config *ConnectionConfig) *WebSocketClient {
+	ctx, cancel := context.WithCancel(context.Background())
 	return &WebSocketClient{
 		config: config,
 		status: &ClientStatus{
@@ -30,13 +35,20 @@ func NewWebSocketClient(config *ConnectionConfig) *WebSocketClient {
 		},
 		handlers: make(map[string]MessageHandler),
 		done:     make(chan struct{}),
+		ctx:      ctx,
+		cancel:   cancel,
 	}
 }
 
 // Connect estabelece a conexão com o servidor WebSocket
 func (wc *WebSocketClient) Connect(ctx context.Context) error {
+	tlsConfig, err := wc.config.tlsConfig()
+	if err != nil {
+		return fmt.Errorf("erro ao configurar TLS do WebSocket: %v", err)
+	}
+
 	scheme := "ws"
-	if wc.config.TLS {
+	if tlsConfig != nil {
 		scheme = "wss"
 	}
 
@@ -48,6 +60,7 @@ func (wc *WebSocketClient) Connect(ctx context.Context) error {
 
 	dialer := websocket.Dialer{
 		HandshakeTimeout: 10 * time.Second,
+		TLSClientConfig:  tlsConfig,
 	}
 
 	if wc.config.Username != "" {
@@ -73,7 +86,9 @@ func (wc *WebSocketClient) Connect(ctx context.Context) error {
 func (wc *WebSocketClient) readPump() {
 	defer func() {
 		wc.conn.Close()
+		wc.mu.Lock()
 		wc.status.Connected = false
+		wc.mu.Unlock()
 	}()
 
 	for {
@@ -84,7 +99,9 @@ func (wc *WebSocketClient) readPump() {
 			_, message, err := wc.conn.ReadMessage()
 			if err != nil {
 				if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseAbnormalClosure) {
-					wc.status.LastError = err.Error()
+					wc.mu.Lock()
+					wc.status.recordError("connection", "", err)
+					wc.mu.Unlock()
 				}
 				return
 			}
@@ -96,19 +113,27 @@ func (wc *WebSocketClient) readPump() {
 			}
 
 			if err := json.Unmarshal(message, &msg); err != nil {
-				wc.status.LastError = fmt.Sprintf("erro ao decodificar mensagem: %v", err)
+				wc.mu.Lock()
+				wc.status.recordError("consume", "", fmt.Errorf("erro ao decodificar mensagem: %v", err))
+				wc.mu.Unlock()
 				continue
 			}
 
+			// Entrega à inscrição exata e às inscrições por padrão que casam com o tópico
 			wc.mu.RLock()
-			handler, exists := wc.handlers[msg.Subject]
+			handlers := matchingHandlers(wc.handlers, msg.Subject)
 			wc.mu.RUnlock()
 
-			if exists && handler != nil {
-				ctx := context.Background()
-				if err := handler(ctx, msg.Subject, []byte(msg.Data)); err != nil {
-					wc.status.LastError = fmt.Sprintf("erro ao processar mensagem do tópico %s: %v", msg.Subject, err)
+			for _, handler := range handlers {
+				start := time.Now()
+				err := safeHandle(wc.ctx, "websocket", handler, msg.Subject, []byte(msg.Data))
+				if err != nil {
+					err = fmt.Errorf("erro ao processar mensagem do tópico %s: %v", msg.Subject, err)
 				}
+				// Os bytes são contados uma vez por mensagem, abaixo
+				wc.mu.Lock()
+				wc.status.recordConsume(msg.Subject, 0, start, err)
+				wc.mu.Unlock()
 			}
 
 			wc.mu.Lock()
@@ -123,6 +148,7 @@ func (wc *WebSocketClient) Disconnect() error {
 	wc.mu.Lock()
 	defer wc.mu.Unlock()
 
+	wc.cancel()
 	close(wc.done)
 	if wc.conn != nil {
 		return wc.conn.Close()
@@ -130,8 +156,13 @@ func (wc *WebSocketClient) Disconnect() error {
 	return nil
 }
 
-// Subscribe registra um handler para receber mensagens de um tópico
+// Subscribe registra um handler para receber mensagens de um tópico ou padrão ("tasks.*",
+// "metrics.#"). O padrão é enviado ao servidor e também aplicado às mensagens recebidas.
 func (wc *WebSocketClient) Subscribe(subject string, handler MessageHandler) error {
+	if err := ValidateSubject(subject); err != nil {
+		return err
+	}
+
 	wc.mu.Lock()
 	defer wc.mu.Unlock()
 
@@ -164,7 +195,7 @@ func (wc *WebSocketClient) Unsubscribe(subject string) error {
 	defer wc.mu.Unlock()
 
 	if _, exists := wc.handlers[subject]; !exists {
-		return fmt.Errorf("não existe handler para o tópico %s", subject)
+		return errs.New(errs.ErrNotFound, "websocket.Unsubscribe", "não existe handler para o tópico %s", subject)
 	}
 
 	// Envia mensagem de cancelamento de inscrição para o servidor
@@ -198,19 +229,33 @@ func (wc *WebSocketClient) Publish(ctx context.Context, subject string, data []b
 		Data:    data,
 	}
 
-	if err := wc.conn.WriteJSON(msg); err != nil {
-		return fmt.Errorf("erro ao publicar mensagem no tópico %s: %v", subject, err)
+	start := time.Now()
+	err := wc.conn.WriteJSON(msg)
+	if err != nil {
+		err = fmt.Errorf("erro ao publicar mensagem no tópico %s: %v", subject, err)
 	}
 
 	wc.mu.Lock()
-	wc.status.BytesSent += int64(len(data))
+	wc.status.recordPublish(subject, len(data), start, err)
 	wc.mu.Unlock()
 
-	return nil
+	return err
 }
 
 // Request envia uma mensagem e aguarda resposta
 func (wc *WebSocketClient) Request(ctx context.Context, subject string, data []byte, timeout int) ([]byte, error) {
+	start := time.Now()
+	response, err := wc.request(ctx, subject, data, timeout)
+
+	wc.mu.Lock()
+	wc.status.recordRequest(subject, len(data), len(response), start, err)
+	wc.mu.Unlock()
+
+	return response, err
+}
+
+// request envia a requisição e aguarda a resposta pelo handler temporário do seu ID
+func (wc *WebSocketClient) request(ctx context.Context, subject string, data []byte, timeout int) ([]byte, error) {
 	responseChan := make(chan []byte, 1)
 	errorChan := make(chan error, 1)
 
@@ -252,10 +297,6 @@ func (wc *WebSocketClient) Request(ctx context.Context, subject string, data []b
 		return nil, fmt.Errorf("erro ao enviar requisição para o tópico %s: %v", subject, err)
 	}
 
-	wc.mu.Lock()
-	wc.status.BytesSent += int64(len(data))
-	wc.mu.Unlock()
-
 	// Aguarda a resposta com timeout
 	select {
 	case response := <-responseChan:
@@ -263,17 +304,17 @@ func (wc *WebSocketClient) Request(ctx context.Context, subject string, data []b
 	case err := <-errorChan:
 		return nil, err
 	case <-time.After(time.Duration(timeout) * time.Millisecond):
-		return nil, fmt.Errorf("timeout ao aguardar resposta do tópico %s", subject)
+		return nil, errs.New(errs.ErrTimeout, "websocket.Request", "timeout ao aguardar resposta do tópico %s", subject)
 	case <-ctx.Done():
 		return nil, ctx.Err()
 	}
 }
 
-// GetStatus retorna o estado atual do cliente
+// GetStatus retorna uma cópia do estado atual do cliente
 func (wc *WebSocketClient) GetStatus() *ClientStatus {
 	wc.mu.RLock()
 	defer wc.mu.RUnlock()
-	return wc.status
+	return wc.status.snapshot()
 }
 
 // GetSubscriptions retorna a lista de inscrições ativas
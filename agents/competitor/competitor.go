@@ -0,0 +1,272 @@
+// Package competitor monitora as páginas e os feeds (RSS ou Atom) dos concorrentes: a cada
+// verificação o conteúdo é baixado, comparado linha a linha com a verificação anterior e as
+// mudanças relevantes — muitas linhas alteradas ou palavras-chave como "preço" e
+// "lançamento" — são entregues aos observadores. As verificações periódicas são agendadas
+// com o cron do runtime, usando Watcher.Run como função do job.
+package competitor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/suissa/HiveMind/agents/metrics"
+)
+
+// Valores padrão da configuração
+const (
+	DefaultMinChanges = 3
+	DefaultMaxLines   = 20
+)
+
+var checksTotal = metrics.Default.Counter("hivemind_competitor_checks_total",
+	"Verificações das fontes dos concorrentes, por concorrente e resultado (baseline, unchanged, changed, notable ou error)",
+	"competitor", "result")
+
+// Kind é o tipo de uma fonte
+type Kind string
+
+// Tipos de fonte
+const (
+	KindPage Kind = "page"
+	KindFeed Kind = "feed" // RSS ou Atom; cada item é uma linha "título — link"
+)
+
+// Source é uma página ou feed de um concorrente
+type Source struct {
+	Competitor string   `json:"competitor" yaml:"competitor"`
+	URL        string   `json:"url" yaml:"url"`
+	Kind       Kind     `json:"kind,omitempty" yaml:"kind,omitempty"`           // Vazio detecta pelo conteúdo
+	Selectors  []string `json:"selectors,omitempty" yaml:"selectors,omitempty"` // Só páginas: trechos monitorados, ex.: ".pricing" (padrão: a página inteira)
+}
+
+// key identifica a fonte entre as verificações
+func (s Source) key() string {
+	return s.URL + "#" + strings.Join(s.Selectors, ",")
+}
+
+// Fetcher baixa o conteúdo de uma fonte como linhas de texto
+type Fetcher interface {
+	Fetch(ctx context.Context, source Source) ([]string, error)
+}
+
+// Config configura o monitoramento
+type Config struct {
+	MinChanges int      `json:"min_changes,omitempty" yaml:"min_changes,omitempty"` // Linhas alteradas para a mudança ser relevante (padrão DefaultMinChanges)
+	Keywords   []string `json:"keywords,omitempty" yaml:"keywords,omitempty"`       // Tornam relevante qualquer mudança que as contenha, ex.: "preço"
+	MaxLines   int      `json:"max_lines,omitempty" yaml:"max_lines,omitempty"`     // Linhas guardadas em Added e Removed (padrão DefaultMaxLines)
+}
+
+// Change é uma mudança relevante numa fonte
+type Change struct {
+	Competitor string    `json:"competitor"`
+	URL        string    `json:"url"`
+	Added      []string  `json:"added,omitempty"`
+	Removed    []string  `json:"removed,omitempty"`
+	Total      int       `json:"total"`              // Linhas alteradas, inclusive as além de MaxLines
+	Keywords   []string  `json:"keywords,omitempty"` // Palavras-chave encontradas nas linhas alteradas
+	DetectedAt time.Time `json:"detected_at"`
+}
+
+// Summary descreve a mudança numa linha, ex.: "Marca X: 4 linhas alteradas (preço): Plano Pro por R$ 99"
+func (c Change) Summary() string {
+	summary := fmt.Sprintf("%s: %d linhas alteradas", c.Competitor, c.Total)
+	if len(c.Keywords) > 0 {
+		summary += " (" + strings.Join(c.Keywords, ", ") + ")"
+	}
+	if len(c.Added) > 0 {
+		summary += ": " + c.Added[0]
+	}
+	return summary
+}
+
+// Observer recebe as mudanças relevantes
+type Observer func(ctx context.Context, change Change) error
+
+// Watcher verifica as fontes e guarda o último conteúdo de cada uma; é seguro para uso
+// concorrente. A primeira verificação de cada fonte só registra o conteúdo de referência.
+type Watcher struct {
+	fetcher   Fetcher
+	config    Config
+	mu        sync.Mutex
+	sources   []Source
+	snapshots map[string][]string
+	observers []Observer
+}
+
+// New cria o monitor com as fontes; campos zerados da configuração usam os padrões
+func New(fetcher Fetcher, config Config, sources ...Source) (*Watcher, error) {
+	if config.MinChanges <= 0 {
+		config.MinChanges = DefaultMinChanges
+	}
+	if config.MaxLines <= 0 {
+		config.MaxLines = DefaultMaxLines
+	}
+	w := &Watcher{fetcher: fetcher, config: config, snapshots: make(map[string][]string)}
+	for _, source := range sources {
+		if err := w.Add(source); err != nil {
+			return nil, err
+		}
+	}
+	return w, nil
+}
+
+// Add acrescenta uma fonte
+func (w *Watcher) Add(source Source) error {
+	if source.Competitor == "" {
+		return fmt.Errorf("fonte %s sem concorrente", source.URL)
+	}
+	if u, err := url.Parse(source.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("URL inválida da fonte de %s: %q", source.Competitor, source.URL)
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.sources = append(w.sources, source)
+	return nil
+}
+
+// Sources retorna as fontes monitoradas
+func (w *Watcher) Sources() []Source {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return append([]Source(nil), w.sources...)
+}
+
+// OnChange registra um observador das mudanças relevantes
+func (w *Watcher) OnChange(observer Observer) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.observers = append(w.observers, observer)
+}
+
+// Check verifica todas as fontes, em ordem, e retorna as mudanças relevantes. As falhas de
+// uma fonte (ou dos observadores) não interrompem as demais e são retornadas juntas.
+func (w *Watcher) Check(ctx context.Context) ([]Change, error) {
+	var changes []Change
+	var failures []error
+	for _, source := range w.Sources() {
+		if err := ctx.Err(); err != nil {
+			failures = append(failures, err)
+			break
+		}
+		change, err := w.check(ctx, source)
+		if err != nil {
+			checksTotal.Inc(source.Competitor, "error")
+			failures = append(failures, fmt.Errorf("%s (%s): %w", source.Competitor, source.URL, err))
+			continue
+		}
+		if change == nil {
+			continue
+		}
+		changes = append(changes, *change)
+		w.mu.Lock()
+		observers := append([]Observer(nil), w.observers...)
+		w.mu.Unlock()
+		for _, observer := range observers {
+			if err := observer(ctx, *change); err != nil {
+				failures = append(failures, fmt.Errorf("observador da mudança de %s: %w", source.Competitor, err))
+			}
+		}
+	}
+	return changes, errors.Join(failures...)
+}
+
+// Run verifica as fontes descartando as mudanças, já entregues aos observadores; serve de
+// função de um job periódico (CronJob.Run)
+func (w *Watcher) Run(ctx context.Context) error {
+	_, err := w.Check(ctx)
+	return err
+}
+
+// check verifica uma fonte e retorna a mudança relevante, se houver
+func (w *Watcher) check(ctx context.Context, source Source) (*Change, error) {
+	lines, err := w.fetcher.Fetch(ctx, source)
+	if err != nil {
+		return nil, err
+	}
+	w.mu.Lock()
+	previous, seen := w.snapshots[source.key()]
+	w.snapshots[source.key()] = lines
+	w.mu.Unlock()
+	if !seen {
+		checksTotal.Inc(source.Competitor, "baseline")
+		return nil, nil
+	}
+
+	added, removed := Diff(previous, lines)
+	total := len(added) + len(removed)
+	if total == 0 {
+		checksTotal.Inc(source.Competitor, "unchanged")
+		return nil, nil
+	}
+	keywords := w.keywords(added, removed)
+	if total < w.config.MinChanges && len(keywords) == 0 {
+		checksTotal.Inc(source.Competitor, "changed")
+		return nil, nil
+	}
+	checksTotal.Inc(source.Competitor, "notable")
+	return &Change{
+		Competitor: source.Competitor,
+		URL:        source.URL,
+		Added:      truncate(added, w.config.MaxLines),
+		Removed:    truncate(removed, w.config.MaxLines),
+		Total:      total,
+		Keywords:   keywords,
+		DetectedAt: time.Now(),
+	}, nil
+}
+
+// keywords retorna as palavras-chave presentes nas linhas alteradas
+func (w *Watcher) keywords(added, removed []string) []string {
+	var found []string
+	for _, keyword := range w.config.Keywords {
+		lower := strings.ToLower(keyword)
+		for _, line := range append(append([]string(nil), added...), removed...) {
+			if strings.Contains(strings.ToLower(line), lower) {
+				found = append(found, keyword)
+				break
+			}
+		}
+	}
+	return found
+}
+
+// Diff retorna as linhas acrescentadas e removidas entre as versões, na ordem em que aparecem;
+// linhas repetidas contam pelo número de ocorrências
+func Diff(previous, current []string) (added, removed []string) {
+	count := make(map[string]int, len(previous))
+	for _, line := range previous {
+		count[line]++
+	}
+	for _, line := range current {
+		if count[line] > 0 {
+			count[line]--
+			continue
+		}
+		added = append(added, line)
+	}
+	remaining := make(map[string]int, len(current))
+	for _, line := range current {
+		remaining[line]++
+	}
+	for _, line := range previous {
+		if remaining[line] > 0 {
+			remaining[line]--
+			continue
+		}
+		removed = append(removed, line)
+	}
+	return added, removed
+}
+
+// truncate limita a quantidade de linhas
+func truncate(lines []string, max int) []string {
+	if len(lines) > max {
+		return lines[:max]
+	}
+	return lines
+}
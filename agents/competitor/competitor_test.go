@@ -0,0 +1,89 @@
+package competitor
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	added, removed := Diff([]string{"a", "b", "b", "c"}, []string{"b", "c", "d", "b", "b"})
+	if strings.Join(added, ",") != "d,b" || strings.Join(removed, ",") != "a" {
+		t.Fatalf("diferença inesperada: +%v -%v", added, removed)
+	}
+}
+
+func TestPageAndFeedLines(t *testing.T) {
+	page := `<html><head><style>.x{}</style></head><body><h1>Planos</h1>
+		<div class="pricing"><ul><li>Básico <b>R$ 49</b></li><li>Pro R$ 99</li></ul></div>
+		<script>track()</script><p>Rodapé</p></body></html>`
+	lines, err := PageLines([]byte(page), nil)
+	if err != nil || strings.Join(lines, "|") != "Planos|Básico R$ 49|Pro R$ 99|Rodapé" {
+		t.Fatalf("linhas inesperadas: %q %v", lines, err)
+	}
+	if lines, _ := PageLines([]byte(page), []string{".pricing"}); len(lines) != 2 {
+		t.Fatalf("seletor ignorado: %q", lines)
+	}
+
+	rss := `<?xml version="1.0"?><rss><channel><item><title>Novo tênis</title><link>https://x.com/1</link></item></channel></rss>`
+	atom := `<feed xmlns="http://www.w3.org/2005/Atom"><entry><title>Promoção</title><link href="https://x.com/2"/></entry></feed>`
+	for feed, want := range map[string]string{rss: "Novo tênis — https://x.com/1", atom: "Promoção — https://x.com/2"} {
+		if lines, err := FeedLines([]byte(feed)); err != nil || len(lines) != 1 || lines[0] != want {
+			t.Fatalf("itens inesperados: %q %v", lines, err)
+		}
+	}
+}
+
+func TestWatcherReportsNotableChanges(t *testing.T) {
+	pages := []string{
+		`<ul><li>Básico R$ 49</li><li>Pro R$ 99</li></ul>`,
+		`<ul><li>Básico R$ 49</li><li>Pro R$ 99</li><li>Novidade</li></ul>`,
+		`<ul><li>Básico R$ 39</li><li>Pro R$ 99</li><li>Novidade</li></ul>`,
+	}
+	version := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(pages[version]))
+	}))
+	defer server.Close()
+
+	watcher, err := New(NewHTTPFetcher(), Config{Keywords: []string{"R$"}},
+		Source{Competitor: "Marca X", URL: server.URL},
+		Source{Competitor: "Marca Y", URL: server.URL + "/fora", Selectors: []string{".inexistente"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := New(nil, Config{}, Source{Competitor: "Z", URL: "ftp://x"}); err == nil {
+		t.Fatal("URL inválida deveria ser rejeitada")
+	}
+	var observed []Change
+	watcher.OnChange(func(_ context.Context, change Change) error {
+		observed = append(observed, change)
+		return errors.New("memória indisponível")
+	})
+
+	// A primeira verificação só registra a referência
+	if changes, err := watcher.Check(context.Background()); err != nil || len(changes) != 0 {
+		t.Fatalf("referência inesperada: %v %v", changes, err)
+	}
+	// Uma linha nova sem palavra-chave não é relevante
+	version = 1
+	if changes, _ := watcher.Check(context.Background()); len(changes) != 0 {
+		t.Fatalf("mudança irrelevante reportada: %v", changes)
+	}
+	// A mudança de preço é relevante pela palavra-chave
+	version = 2
+	changes, err := watcher.Check(context.Background())
+	if len(changes) != 1 || changes[0].Added[0] != "Básico R$ 39" || changes[0].Removed[0] != "Básico R$ 49" || len(observed) != 1 {
+		t.Fatalf("mudança inesperada: %+v", changes)
+	}
+	if err == nil || !strings.Contains(err.Error(), "memória indisponível") {
+		t.Fatalf("a falha do observador deveria ser retornada: %v", err)
+	}
+	if summary := changes[0].Summary(); summary != "Marca X: 2 linhas alteradas (R$): Básico R$ 39" {
+		t.Fatalf("resumo inesperado: %s", summary)
+	}
+}
@@ -0,0 +1,181 @@
+package competitor
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
+)
+
+// maxBody é o tamanho máximo lido de cada fonte
+const maxBody = 5 << 20
+
+// HTTPFetcher baixa as fontes por HTTP GET; as páginas viram uma linha por bloco de texto
+// (parágrafo, item de lista, título, célula) e os feeds, uma linha por item
+type HTTPFetcher struct {
+	Client    *http.Client
+	UserAgent string
+}
+
+// NewHTTPFetcher cria o fetcher com prazo de 30 segundos por requisição
+func NewHTTPFetcher() *HTTPFetcher {
+	return &HTTPFetcher{Client: &http.Client{Timeout: 30 * time.Second}, UserAgent: "HiveMind-CompetitorWatch/1.0"}
+}
+
+// Fetch implementa Fetcher
+func (f *HTTPFetcher) Fetch(ctx context.Context, source Source) ([]string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, source.URL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao criar a requisição: %v", err)
+	}
+	if f.UserAgent != "" {
+		req.Header.Set("User-Agent", f.UserAgent)
+	}
+	resp, err := f.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao baixar a fonte: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("a fonte retornou status %d", resp.StatusCode)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxBody))
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler a fonte: %v", err)
+	}
+
+	kind := source.Kind
+	if kind == "" {
+		kind = detect(resp.Header.Get("Content-Type"), body)
+	}
+	if kind == KindFeed {
+		return FeedLines(body)
+	}
+	return PageLines(body, source.Selectors)
+}
+
+// detect identifica os feeds pelo tipo de conteúdo ou pelo elemento raiz
+func detect(contentType string, body []byte) Kind {
+	if strings.Contains(contentType, "rss") || strings.Contains(contentType, "atom") {
+		return KindFeed
+	}
+	head := strings.ToLower(string(body[:min(len(body), 512)]))
+	if strings.Contains(head, "<rss") || strings.Contains(head, "<feed") {
+		return KindFeed
+	}
+	return KindPage
+}
+
+// FeedLines lê os itens de um feed RSS ou Atom como linhas "título — link"
+func FeedLines(body []byte) ([]string, error) {
+	var feed struct {
+		Channel struct {
+			Items []struct {
+				Title string `xml:"title"`
+				Link  string `xml:"link"`
+			} `xml:"item"`
+		} `xml:"channel"`
+		Entries []struct {
+			Title string `xml:"title"`
+			Links []struct {
+				Href string `xml:"href,attr"`
+			} `xml:"link"`
+		} `xml:"entry"`
+	}
+	decoder := xml.NewDecoder(bytes.NewReader(body))
+	decoder.Strict = false
+	if err := decoder.Decode(&feed); err != nil {
+		return nil, fmt.Errorf("feed inválido: %v", err)
+	}
+	var lines []string
+	for _, item := range feed.Channel.Items {
+		lines = appendLine(lines, item.Title, item.Link)
+	}
+	for _, entry := range feed.Entries {
+		link := ""
+		if len(entry.Links) > 0 {
+			link = entry.Links[0].Href
+		}
+		lines = appendLine(lines, entry.Title, link)
+	}
+	return lines, nil
+}
+
+// appendLine acrescenta o item do feed
+func appendLine(lines []string, title, link string) []string {
+	title, link = normalize(title), strings.TrimSpace(link)
+	switch {
+	case title != "" && link != "":
+		return append(lines, title+" — "+link)
+	case title != "":
+		return append(lines, title)
+	case link != "":
+		return append(lines, link)
+	}
+	return lines
+}
+
+// blocks são os elementos que encerram uma linha de texto
+var blocks = map[string]bool{
+	"p": true, "div": true, "li": true, "tr": true, "td": true, "th": true, "br": true,
+	"h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+	"section": true, "article": true, "header": true, "footer": true, "blockquote": true,
+	"dt": true, "dd": true, "pre": true, "option": true, "figcaption": true,
+}
+
+// PageLines extrai o texto visível da página (ou dos trechos selecionados), uma linha por
+// bloco de texto
+func PageLines(body []byte, selectors []string) ([]string, error) {
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("página inválida: %v", err)
+	}
+	doc.Find("script, style, noscript, template, svg").Remove()
+	if len(selectors) == 0 {
+		selectors = []string{"body"}
+	}
+	var lines []string
+	var current strings.Builder
+	flush := func() {
+		if line := normalize(current.String()); line != "" {
+			lines = append(lines, line)
+		}
+		current.Reset()
+	}
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		switch n.Type {
+		case html.TextNode:
+			current.WriteString(n.Data)
+			current.WriteByte(' ')
+			return
+		case html.ElementNode:
+			if blocks[n.Data] {
+				flush()
+				defer flush()
+			}
+		}
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			walk(child)
+		}
+	}
+	for _, selector := range selectors {
+		for _, node := range doc.Find(selector).Nodes {
+			walk(node)
+			flush()
+		}
+	}
+	return lines, nil
+}
+
+// normalize junta os espaços do texto
+func normalize(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
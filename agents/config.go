@@ -3,8 +3,12 @@ package agents
 import (
 	"fmt"
 	"os"
+	"strings"
 
 	"gopkg.in/yaml.v2"
+
+	"github.com/suissa/HiveMind/agents/overrides"
+	"github.com/suissa/HiveMind/agents/persona"
 )
 
 // Configurações do RabbitMQ
@@ -50,11 +54,41 @@ type AgentConfig struct {
 	Model       string `yaml:"model"`
 	MaxRounds   int    `yaml:"max_rounds"`
 	Backstory   string `yaml:"backstory"`
+	Persona     string `yaml:"persona"` // Personas da seção personas, combinadas com "+" (ex.: "analista+cetico")
+	Tenant      string `yaml:"tenant"`
 }
 
 // AgentsConfig representa a configuração de todos os agentes
 type AgentsConfig struct {
-	Agents []AgentConfig `yaml:"agents"`
+	Agents          []AgentConfig    `yaml:"agents"`
+	ToolPermissions ToolPermissions  `yaml:"tool_permissions"`
+	Overrides       overrides.Limits `yaml:"overrides"` // Limites dos overrides por tarefa
+
+	Personas map[string]persona.Persona `yaml:"personas"` // Biblioteca de personas referenciadas pelos agentes
+}
+
+// PersonaLibrary cria a biblioteca com as personas da configuração
+func (c *AgentsConfig) PersonaLibrary() (*persona.Library, error) {
+	return persona.FromMap(c.Personas)
+}
+
+// Persona compõe a persona do agente a partir das referenciadas em AgentConfig.Persona, com a
+// backstory própria do agente ao final. Sem persona referenciada, retorna uma persona sem nome
+// só com a backstory do agente. Use com CognitiveAgent.SetPersona.
+func (c *AgentsConfig) Persona(agent AgentConfig) (persona.Persona, error) {
+	if agent.Persona == "" {
+		return persona.Persona{Backstory: agent.Backstory}, nil
+	}
+	library, err := c.PersonaLibrary()
+	if err != nil {
+		return persona.Persona{}, err
+	}
+	p, err := library.Compose(strings.Split(agent.Persona, "+")...)
+	if err != nil {
+		return persona.Persona{}, fmt.Errorf("erro na persona do agente %s: %w", agent.ID, err)
+	}
+	p.Backstory = strings.TrimSpace(p.Backstory + "\n" + agent.Backstory)
+	return p, nil
 }
 
 // TaskConfig representa a configuração de uma tarefa
@@ -67,6 +101,11 @@ type TaskConfig struct {
 	Priority     int      `yaml:"priority"`
 	Status       string   `yaml:"status"`
 	Deadline     string   `yaml:"deadline"`
+
+	ExpectedOutput string `yaml:"expected_output"` // Descrição do resultado esperado, verificada com SetOutputValidation
+	OutputSchema   string `yaml:"output_schema"`   // JSON Schema da saída, em JSON (opcional)
+
+	Overrides *overrides.Overrides `yaml:"overrides"` // Parâmetros do modelo para a tarefa (opcional)
 }
 
 // TasksConfig representa a configuração de todas as tarefas
@@ -0,0 +1,185 @@
+package consensus
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/suissa/HiveMind/agents/llm"
+)
+
+// AggregatorFunc adapta uma função a Aggregator
+type AggregatorFunc func(ctx context.Context, question Question, ballots []Ballot) (Decision, error)
+
+// Aggregate implementa Aggregator
+func (f AggregatorFunc) Aggregate(ctx context.Context, question Question, ballots []Ballot) (Decision, error) {
+	return f(ctx, question, ballots)
+}
+
+// tally agrupa as respostas normalizadas, na ordem em que aparecem pela primeira vez
+type tally struct {
+	order      []string           // Respostas normalizadas
+	display    map[string]string  // Texto original da primeira ocorrência
+	votes      map[string]int     // Votos por resposta normalizada
+	confidence map[string]float64 // Soma das confianças por resposta normalizada
+}
+
+func newTally() *tally {
+	return &tally{display: make(map[string]string), votes: make(map[string]int), confidence: make(map[string]float64)}
+}
+
+func (t *tally) add(answer string, confidence float64) {
+	key := Normalize(answer)
+	if _, seen := t.display[key]; !seen {
+		t.order = append(t.order, key)
+		t.display[key] = strings.TrimSpace(answer)
+	}
+	t.votes[key]++
+	t.confidence[key] += confidence
+}
+
+// leader retorna a resposta mais votada; nos empates, a de maior confiança somada e depois a
+// que apareceu primeiro
+func (t *tally) leader() string {
+	var best string
+	for _, key := range t.order {
+		if best == "" || t.votes[key] > t.votes[best] ||
+			(t.votes[key] == t.votes[best] && t.confidence[key] > t.confidence[best]) {
+			best = key
+		}
+	}
+	return best
+}
+
+// counts retorna os votos pelo texto original das respostas
+func (t *tally) counts() map[string]int {
+	counts := make(map[string]int, len(t.votes))
+	for key, votes := range t.votes {
+		counts[t.display[key]] = votes
+	}
+	return counts
+}
+
+// Majority escolhe a resposta mais votada (pluralidade), desempatando pela confiança
+func Majority() Aggregator {
+	return AggregatorFunc(func(ctx context.Context, question Question, ballots []Ballot) (Decision, error) {
+		t := newTally()
+		for _, ballot := range ballots {
+			t.add(ballot.Answer, ballot.Confidence)
+		}
+		return Decision{Answer: t.display[t.leader()], Votes: t.counts()}, nil
+	})
+}
+
+// RankedChoice decide por voto ranqueado (instant runoff): a cada rodada conta a preferência
+// mais alta ainda em disputa de cada voto e elimina a menos votada, até uma resposta ter a
+// maioria dos votos não esgotados. Votos sem Ranking contam apenas com a resposta.
+func RankedChoice() Aggregator {
+	return AggregatorFunc(func(ctx context.Context, question Question, ballots []Ballot) (Decision, error) {
+		rankings := make([][]string, len(ballots))
+		display := make(map[string]string)
+		for i, ballot := range ballots {
+			ranking := ballot.Ranking
+			if len(ranking) == 0 {
+				ranking = []string{ballot.Answer}
+			}
+			for _, answer := range ranking {
+				key := Normalize(answer)
+				if _, seen := display[key]; !seen {
+					display[key] = strings.TrimSpace(answer)
+				}
+				rankings[i] = append(rankings[i], key)
+			}
+		}
+
+		eliminated := make(map[string]bool)
+		for round := 1; ; round++ {
+			t := newTally()
+			for i, ranking := range rankings {
+				for _, key := range ranking {
+					if !eliminated[key] {
+						t.add(key, ballots[i].Confidence)
+						break
+					}
+				}
+			}
+			if len(t.order) == 0 {
+				return Decision{}, fmt.Errorf("todos os votos se esgotaram")
+			}
+
+			active := 0
+			for _, votes := range t.votes {
+				active += votes
+			}
+			leader := t.leader()
+			if t.votes[leader]*2 > active || len(t.order) == 1 {
+				votes := make(map[string]int, len(t.votes))
+				for key, count := range t.votes {
+					votes[display[key]] = count
+				}
+				return Decision{Answer: display[leader], Votes: votes, Rounds: round}, nil
+			}
+
+			// Elimina a menos votada; nos empates, a que apareceu por último
+			loser := t.order[0]
+			for _, key := range t.order {
+				if t.votes[key] <= t.votes[loser] {
+					loser = key
+				}
+			}
+			eliminated[loser] = true
+		}
+	})
+}
+
+const judgeSystem = `Você é o juiz de uma votação entre agentes de IA.
+Leia a pergunta e as respostas candidatas, com as justificativas dos votantes, e escolha a
+resposta mais correta e bem fundamentada, independentemente de quantos votos ela recebeu.
+Responda apenas com JSON no formato {"choice": 1, "rationale": "..."}, em que choice é o
+número da resposta escolhida.`
+
+// Judge usa um modelo juiz para escolher entre as respostas distintas dos votantes
+func Judge(provider llm.Provider, model string) Aggregator {
+	return AggregatorFunc(func(ctx context.Context, question Question, ballots []Ballot) (Decision, error) {
+		t := newTally()
+		rationales := make(map[string][]string)
+		for _, ballot := range ballots {
+			t.add(ballot.Answer, ballot.Confidence)
+			if ballot.Rationale != "" {
+				key := Normalize(ballot.Answer)
+				rationales[key] = append(rationales[key], ballot.Rationale)
+			}
+		}
+
+		var b strings.Builder
+		fmt.Fprintf(&b, "Pergunta: %s\n\nRespostas candidatas:\n", question.Text)
+		for i, key := range t.order {
+			fmt.Fprintf(&b, "%d. %s (%d votos)\n", i+1, t.display[key], t.votes[key])
+			for _, rationale := range rationales[key] {
+				fmt.Fprintf(&b, "   - %s\n", rationale)
+			}
+		}
+
+		resp, err := provider.Complete(ctx, llm.Request{Model: model, System: judgeSystem, Prompt: b.String(), Temperature: 0})
+		if err != nil {
+			return Decision{}, fmt.Errorf("erro no modelo juiz: %w", err)
+		}
+		text := resp.Text
+		start, end := strings.Index(text, "{"), strings.LastIndex(text, "}")
+		if start < 0 || end < start {
+			return Decision{}, fmt.Errorf("resposta do juiz sem JSON: %q", text)
+		}
+		var verdict struct {
+			Choice    int    `json:"choice"`
+			Rationale string `json:"rationale"`
+		}
+		if err := json.Unmarshal([]byte(text[start:end+1]), &verdict); err != nil {
+			return Decision{}, fmt.Errorf("resposta do juiz inválida: %v", err)
+		}
+		if verdict.Choice < 1 || verdict.Choice > len(t.order) {
+			return Decision{}, fmt.Errorf("o juiz escolheu a resposta %d de %d", verdict.Choice, len(t.order))
+		}
+		return Decision{Answer: t.display[t.order[verdict.Choice-1]], Votes: t.counts(), Rationale: verdict.Rationale}, nil
+	})
+}
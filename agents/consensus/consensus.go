@@ -0,0 +1,128 @@
+// Package consensus implementa decisões por votação entre agentes: cada votante responde à
+// pergunta de forma independente e um agregador configurável (maioria, voto ranqueado ou um
+// modelo juiz) produz a resposta final. As divergências de cada votante são registradas no
+// resultado para análise.
+package consensus
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/suissa/HiveMind/agents/errs"
+)
+
+// Question é a pergunta submetida aos votantes. Com Options, as respostas devem ser uma das
+// opções (e os rankings, uma ordenação delas).
+type Question struct {
+	Text    string   `json:"text"`
+	Options []string `json:"options,omitempty"`
+}
+
+// Ballot é o voto de um votante
+type Ballot struct {
+	VoterID    string        `json:"voter_id"`
+	Answer     string        `json:"answer"`
+	Ranking    []string      `json:"ranking,omitempty"`    // Preferências, da preferida à menos preferida
+	Confidence float64       `json:"confidence,omitempty"` // Confiança de 0 a 1, usada nos desempates
+	Rationale  string        `json:"rationale,omitempty"`
+	Error      string        `json:"error,omitempty"` // Falha do votante; o voto não é contado
+	Duration   time.Duration `json:"duration"`
+}
+
+// Voter é um participante da votação
+type Voter struct {
+	ID     string
+	Answer func(ctx context.Context, question Question) (Ballot, error)
+}
+
+// Decision é a resposta escolhida pelo agregador
+type Decision struct {
+	Answer    string         `json:"answer"`
+	Votes     map[string]int `json:"votes,omitempty"`  // Votos por resposta (na última rodada, no voto ranqueado)
+	Rounds    int            `json:"rounds,omitempty"` // Rodadas de eliminação do voto ranqueado
+	Rationale string         `json:"rationale,omitempty"`
+}
+
+// Aggregator produz a decisão a partir dos votos válidos
+type Aggregator interface {
+	Aggregate(ctx context.Context, question Question, ballots []Ballot) (Decision, error)
+}
+
+// Dissent é a divergência de um votante em relação à decisão
+type Dissent struct {
+	VoterID   string `json:"voter_id"`
+	Answer    string `json:"answer"`
+	Rationale string `json:"rationale,omitempty"`
+}
+
+// Result é o resultado da votação
+type Result struct {
+	Question  Question  `json:"question"`
+	Decision  Decision  `json:"decision"`
+	Ballots   []Ballot  `json:"ballots"`   // Todos os votos, inclusive os com falha, na ordem dos votantes
+	Dissent   []Dissent `json:"dissent"`   // Votantes cuja resposta difere da decisão
+	Agreement float64   `json:"agreement"` // Fração dos votos válidos que concordam com a decisão
+}
+
+// Vote coleta os votos em paralelo e os agrega. Votantes que falham são registrados no
+// resultado sem contar na decisão; retorna erro se nenhum voto for válido.
+func Vote(ctx context.Context, question Question, voters []Voter, aggregator Aggregator) (Result, error) {
+	if len(voters) == 0 {
+		return Result{}, errs.New(errs.ErrValidation, "consensus.Vote", "votação sem votantes")
+	}
+
+	ballots := make([]Ballot, len(voters))
+	var wg sync.WaitGroup
+	for i, voter := range voters {
+		wg.Add(1)
+		go func(i int, voter Voter) {
+			defer wg.Done()
+			started := time.Now()
+			ballot, err := voter.Answer(ctx, question)
+			ballot.VoterID = voter.ID
+			ballot.Duration = time.Since(started)
+			if err != nil {
+				ballot.Error = err.Error()
+			}
+			ballots[i] = ballot
+		}(i, voter)
+	}
+	wg.Wait()
+
+	var valid []Ballot
+	for _, ballot := range ballots {
+		if ballot.Error == "" {
+			valid = append(valid, ballot)
+		}
+	}
+	result := Result{Question: question, Ballots: ballots}
+	if len(valid) == 0 {
+		return result, fmt.Errorf("nenhum voto válido entre %d votantes: %s", len(ballots), ballots[0].Error)
+	}
+
+	decision, err := aggregator.Aggregate(ctx, question, valid)
+	if err != nil {
+		return result, fmt.Errorf("erro ao agregar os votos: %w", err)
+	}
+	result.Decision = decision
+
+	agree := 0
+	for _, ballot := range valid {
+		if Normalize(ballot.Answer) == Normalize(decision.Answer) {
+			agree++
+			continue
+		}
+		result.Dissent = append(result.Dissent, Dissent{VoterID: ballot.VoterID, Answer: ballot.Answer, Rationale: ballot.Rationale})
+	}
+	result.Agreement = float64(agree) / float64(len(valid))
+	return result, nil
+}
+
+// Normalize prepara a resposta para a contagem: sem espaços nas pontas, sem pontuação final
+// e em minúsculas
+func Normalize(answer string) string {
+	return strings.ToLower(strings.TrimRight(strings.TrimSpace(answer), ".!"))
+}
@@ -0,0 +1,85 @@
+package consensus
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/suissa/HiveMind/agents/llm"
+)
+
+// fixed cria um votante com voto fixo
+func fixed(id string, ballot Ballot, err error) Voter {
+	return Voter{ID: id, Answer: func(ctx context.Context, question Question) (Ballot, error) {
+		return ballot, err
+	}}
+}
+
+func TestMajorityRecordsDissent(t *testing.T) {
+	voters := []Voter{
+		fixed("a", Ballot{Answer: "Paris", Confidence: 0.9}, nil),
+		fixed("b", Ballot{Answer: "paris.", Confidence: 0.8}, nil),
+		fixed("c", Ballot{Answer: "Lyon", Rationale: "maior polo gastronômico"}, nil),
+		fixed("d", Ballot{}, errors.New("sem LLM")),
+	}
+
+	result, err := Vote(context.Background(), Question{Text: "Capital da França?"}, voters, Majority())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Decision.Answer != "Paris" || result.Decision.Votes["Paris"] != 2 {
+		t.Fatalf("decisão inesperada: %+v", result.Decision)
+	}
+	if len(result.Dissent) != 1 || result.Dissent[0].VoterID != "c" || result.Dissent[0].Rationale == "" {
+		t.Fatalf("divergência inesperada: %+v", result.Dissent)
+	}
+	if result.Agreement < 0.66 || result.Agreement > 0.67 {
+		t.Fatalf("concordância inesperada: %v", result.Agreement)
+	}
+	if result.Ballots[3].VoterID != "d" || result.Ballots[3].Error == "" {
+		t.Fatalf("o voto com falha deveria ser registrado: %+v", result.Ballots[3])
+	}
+}
+
+func TestRankedChoiceTransfersEliminatedVotes(t *testing.T) {
+	ballots := []Ballot{
+		{Ranking: []string{"A", "B"}},
+		{Ranking: []string{"A", "B"}},
+		{Ranking: []string{"B", "A"}},
+		{Ranking: []string{"C", "B"}},
+		{Ranking: []string{"C", "B"}},
+	}
+	for i := range ballots {
+		ballots[i].Answer = ballots[i].Ranking[0]
+	}
+
+	decision, err := RankedChoice().Aggregate(context.Background(), Question{}, ballots)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// B é eliminada na primeira rodada e o seu voto vai para A
+	if decision.Answer != "A" || decision.Rounds != 2 || decision.Votes["A"] != 3 {
+		t.Fatalf("decisão inesperada: %+v", decision)
+	}
+}
+
+func TestJudgePicksCandidate(t *testing.T) {
+	var prompt string
+	judge := Judge(llm.ProviderFunc(func(ctx context.Context, req llm.Request) (*llm.Response, error) {
+		prompt = req.Prompt
+		return &llm.Response{Text: `{"choice": 2, "rationale": "melhor fundamentada"}`}, nil
+	}), "juiz")
+
+	ballots := []Ballot{{Answer: "42"}, {Answer: "42"}, {Answer: "41", Rationale: "erro de arredondamento"}}
+	decision, err := judge.Aggregate(context.Background(), Question{Text: "Quanto é 6x7?"}, ballots)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if decision.Answer != "41" || decision.Rationale != "melhor fundamentada" {
+		t.Fatalf("decisão inesperada: %+v", decision)
+	}
+	if !strings.Contains(prompt, "2. 41 (1 votos)") || !strings.Contains(prompt, "erro de arredondamento") {
+		t.Fatalf("prompt sem os candidatos: %s", prompt)
+	}
+}
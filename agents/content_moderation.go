@@ -0,0 +1,65 @@
+package agents
+
+import (
+	"context"
+	"time"
+
+	"github.com/suissa/HiveMind/agents/moderation"
+)
+
+// moderationHooks modera a descrição das tarefas antes da execução e o resultado do agente
+type moderationHooks struct {
+	NopHooks
+	pipeline *moderation.Pipeline
+	events   *EventEmitter
+}
+
+// ModerationHooks cria hooks que passam a entrada de cada tarefa e a saída do agente pelo
+// pipeline de moderação. Conteúdo redigido substitui o original, conteúdo bloqueado
+// interrompe a tarefa, e toda decisão sobre conteúdo sinalizado é emitida como
+// EventModeration no events (opcional) para auditoria.
+func ModerationHooks(pipeline *moderation.Pipeline, events *EventEmitter) Hooks {
+	return &moderationHooks{pipeline: pipeline, events: events}
+}
+
+// OnTaskBegin modera a descrição da tarefa
+func (h *moderationHooks) OnTaskBegin(ctx context.Context, agent Agent, task *Task) error {
+	decision, err := h.pipeline.Check(ctx, moderation.Input, task.Description)
+	h.emit(agent, task, decision)
+	if err != nil {
+		return err
+	}
+	task.Description = decision.Text
+	return nil
+}
+
+// OnTaskEnd modera o resultado do agente
+func (h *moderationHooks) OnTaskEnd(ctx context.Context, agent Agent, task *Task, output string) (string, error) {
+	decision, err := h.pipeline.Check(ctx, moderation.Output, output)
+	h.emit(agent, task, decision)
+	if err != nil {
+		return "", err
+	}
+	return decision.Text, nil
+}
+
+// emit registra a decisão sobre conteúdo sinalizado. O conteúdo em si não é incluído no evento.
+func (h *moderationHooks) emit(agent Agent, task *Task, decision moderation.Decision) {
+	if h.events == nil || !decision.Flagged() {
+		return
+	}
+
+	h.events.Emit(Event{
+		Type:      EventModeration,
+		Timestamp: time.Now(),
+		Source:    "moderation",
+		Data: map[string]interface{}{
+			"agent_id":   agent.GetID(),
+			"task_id":    task.ID,
+			"stage":      string(decision.Stage),
+			"action":     string(decision.Action),
+			"categories": decision.Result.Categories,
+			"scores":     decision.Result.Scores,
+		},
+	})
+}
@@ -0,0 +1,366 @@
+// Package contractnet implementa a alocação de tarefas por licitação (contract net): o
+// gerente (Manager) anuncia a tarefa no barramento, os agentes interessados respondem com
+// lances de custo e confiança (Contractor) e a tarefa é adjudicada ao melhor lance, em vez
+// de seguir um roteamento fixo pelo tipo.
+package contractnet
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/suissa/HiveMind/agents/affinity"
+	"github.com/suissa/HiveMind/agents/communication"
+	"github.com/suissa/HiveMind/agents/errs"
+	"github.com/suissa/HiveMind/agents/overrides"
+)
+
+// Tópicos do protocolo: os anúncios são publicados em AnnounceSubject, os lances em
+// "contractnet.bid.<tarefa>" e as adjudicações em "contractnet.award.<tarefa>"
+const (
+	AnnounceSubject = "contractnet.announce"
+	BidPrefix       = "contractnet.bid"
+	AwardPrefix     = "contractnet.award"
+)
+
+// DefaultBidTimeout é o prazo padrão para o recebimento dos lances
+const DefaultBidTimeout = 2 * time.Second
+
+// DefaultAffinityTolerance é a fração padrão da pontuação do melhor lance que o dono da
+// afinidade precisa alcançar para receber a tarefa
+const DefaultAffinityTolerance = 0.5
+
+// BidSubject retorna o tópico dos lances da tarefa
+func BidSubject(taskID string) string {
+	return BidPrefix + "." + taskID
+}
+
+// AwardSubject retorna o tópico da adjudicação da tarefa
+func AwardSubject(taskID string) string {
+	return AwardPrefix + "." + taskID
+}
+
+// Announcement é o anúncio de uma tarefa aberta a lances
+type Announcement struct {
+	TaskID      string                 `json:"task_id"`
+	Type        string                 `json:"type,omitempty"`
+	Role        string                 `json:"role,omitempty"` // Papel exigido; vazio aceita qualquer agente
+	Description string                 `json:"description"`
+	Parameters  map[string]interface{} `json:"parameters,omitempty"`
+	Overrides   *overrides.Overrides   `json:"overrides,omitempty"`
+	Affinity    string                 `json:"affinity,omitempty"` // Chave de afinidade (ex.: ID da tarefa pai); vazia desativa
+	Deadline    time.Time              `json:"deadline"`           // Fim do prazo dos lances
+}
+
+// Bid é o lance de um agente: menor custo e maior confiança vencem
+type Bid struct {
+	TaskID     string    `json:"task_id"`
+	AgentID    string    `json:"agent_id"`
+	Cost       float64   `json:"cost"`       // Custo estimado (por exemplo, a carga atual)
+	Confidence float64   `json:"confidence"` // Confiança de 0 a 1 em concluir a tarefa
+	Timestamp  time.Time `json:"timestamp"`
+}
+
+// Award é a adjudicação da tarefa ao lance vencedor
+type Award struct {
+	Announcement Announcement `json:"announcement"`
+	Bid          Bid          `json:"bid"`  // Lance vencedor
+	Bids         int          `json:"bids"` // Lances recebidos
+}
+
+// Scorer pontua os lances; o maior vence
+type Scorer func(Bid) float64
+
+// DefaultScore favorece a confiança e penaliza o custo
+func DefaultScore(bid Bid) float64 {
+	return bid.Confidence / (1 + bid.Cost)
+}
+
+// Bus é o barramento usado pelo protocolo; communication.CommunicationClient o implementa
+type Bus interface {
+	Publish(ctx context.Context, subject string, data []byte) error
+	Subscribe(subject string, handler communication.MessageHandler) error
+	Unsubscribe(subject string) error
+}
+
+// Config configura o gerente
+type Config struct {
+	BidTimeout   time.Duration `json:"bid_timeout" yaml:"bid_timeout"`     // Prazo dos lances (padrão DefaultBidTimeout)
+	ExpectedBids int           `json:"expected_bids" yaml:"expected_bids"` // Encerra a licitação ao receber esse número de lances (0 espera o prazo)
+	// DisableAffinity ignora a chave de afinidade dos anúncios: vence sempre o melhor lance
+	DisableAffinity bool `json:"disable_affinity,omitempty" yaml:"disable_affinity,omitempty"`
+	// AffinityTolerance é a fração da pontuação do melhor lance que o dono da afinidade precisa
+	// alcançar; abaixo dela a tarefa vai para a próxima instância na ordem da afinidade
+	// (padrão DefaultAffinityTolerance)
+	AffinityTolerance float64 `json:"affinity_tolerance,omitempty" yaml:"affinity_tolerance,omitempty"`
+}
+
+// Manager anuncia as tarefas e as adjudica ao melhor lance
+type Manager struct {
+	bus    Bus
+	config Config
+	score  Scorer
+}
+
+// NewManager cria um gerente com a pontuação padrão
+func NewManager(bus Bus, config Config) *Manager {
+	if config.BidTimeout <= 0 {
+		config.BidTimeout = DefaultBidTimeout
+	}
+	if config.AffinityTolerance <= 0 {
+		config.AffinityTolerance = DefaultAffinityTolerance
+	}
+	return &Manager{bus: bus, config: config, score: DefaultScore}
+}
+
+// SetScorer substitui a pontuação dos lances
+func (m *Manager) SetScorer(score Scorer) {
+	m.score = score
+}
+
+// Announce anuncia a tarefa, recebe os lances até o prazo e publica a adjudicação ao melhor.
+// Retorna errs.ErrNotFound se nenhum agente der lance.
+func (m *Manager) Announce(ctx context.Context, announcement Announcement) (Award, error) {
+	if announcement.TaskID == "" {
+		return Award{}, errs.New(errs.ErrValidation, "contractnet.Announce", "tarefa sem ID")
+	}
+	announcement.Deadline = time.Now().Add(m.config.BidTimeout)
+
+	// Os lances são coletados antes do anúncio para que nenhum se perca
+	bids := make(chan Bid, 16)
+	subject := BidSubject(announcement.TaskID)
+	err := m.bus.Subscribe(subject, func(ctx context.Context, _ string, data []byte) error {
+		var bid Bid
+		if err := json.Unmarshal(data, &bid); err != nil {
+			return fmt.Errorf("lance inválido: %v", err)
+		}
+		select {
+		case bids <- bid:
+		default:
+			log.Printf("⚠️ Lance do agente %s descartado na tarefa %s", bid.AgentID, bid.TaskID)
+		}
+		return nil
+	})
+	if err != nil {
+		return Award{}, fmt.Errorf("erro ao se inscrever nos lances: %v", err)
+	}
+	defer m.bus.Unsubscribe(subject)
+
+	if err := publishJSON(ctx, m.bus, AnnounceSubject, announcement); err != nil {
+		return Award{}, fmt.Errorf("erro ao anunciar a tarefa %s: %v", announcement.TaskID, err)
+	}
+
+	received := m.collect(ctx, bids)
+	if len(received) == 0 {
+		if err := ctx.Err(); err != nil {
+			return Award{}, errs.FromContext("contractnet.Announce", err)
+		}
+		return Award{}, errs.New(errs.ErrNotFound, "contractnet.Announce", "nenhum lance para a tarefa %s", announcement.TaskID)
+	}
+
+	award := Award{Announcement: announcement, Bid: m.best(announcement, received), Bids: len(received)}
+	if err := publishJSON(ctx, m.bus, AwardSubject(announcement.TaskID), award); err != nil {
+		return Award{}, fmt.Errorf("erro ao adjudicar a tarefa %s: %v", announcement.TaskID, err)
+	}
+	return award, nil
+}
+
+// collect recebe os lances até o prazo, o cancelamento ou o número esperado
+func (m *Manager) collect(ctx context.Context, bids <-chan Bid) []Bid {
+	timer := time.NewTimer(m.config.BidTimeout)
+	defer timer.Stop()
+
+	var received []Bid
+	for {
+		select {
+		case <-ctx.Done():
+			return received
+		case <-timer.C:
+			return received
+		case bid := <-bids:
+			received = append(received, bid)
+			if m.config.ExpectedBids > 0 && len(received) >= m.config.ExpectedBids {
+				return received
+			}
+		}
+	}
+}
+
+// best escolhe o lance de maior pontuação; nos empates, o agente de menor ID. Com uma chave
+// de afinidade, vence o primeiro agente na ordem da afinidade cujo lance alcança a tolerância,
+// para que as tarefas relacionadas fiquem com a mesma instância enquanto ela não estiver
+// sobrecarregada.
+func (m *Manager) best(announcement Announcement, bids []Bid) Bid {
+	sort.SliceStable(bids, func(i, j int) bool {
+		si, sj := m.score(bids[i]), m.score(bids[j])
+		if si != sj {
+			return si > sj
+		}
+		return bids[i].AgentID < bids[j].AgentID
+	})
+	winner := bids[0]
+	if announcement.Affinity == "" || m.config.DisableAffinity {
+		return winner
+	}
+
+	byAgent := make(map[string]Bid, len(bids))
+	agents := make([]string, 0, len(bids))
+	for _, bid := range bids {
+		if _, ok := byAgent[bid.AgentID]; !ok {
+			byAgent[bid.AgentID] = bid
+			agents = append(agents, bid.AgentID)
+		}
+	}
+	threshold := m.score(winner) * m.config.AffinityTolerance
+	for _, agentID := range affinity.Rank(announcement.Affinity, agents) {
+		if bid := byAgent[agentID]; m.score(bid) >= threshold {
+			return bid
+		}
+	}
+	return winner
+}
+
+// Bidder decide se o agente dá lance na tarefa anunciada e com qual custo e confiança
+type Bidder func(ctx context.Context, announcement Announcement) (Bid, bool)
+
+// Executor executa a tarefa adjudicada ao agente
+type Executor func(ctx context.Context, award Award) error
+
+// bidder é um agente registrado no Contractor
+type bidder struct {
+	bid     Bidder
+	execute Executor
+}
+
+// Contractor responde aos anúncios em nome dos agentes de um processo e executa as tarefas
+// adjudicadas a eles. Uma única inscrição no barramento atende todos os agentes.
+type Contractor struct {
+	bus      Bus
+	agents   map[string]bidder
+	running  sync.WaitGroup
+	stopOnce sync.Once
+	mu       sync.RWMutex
+}
+
+// NewContractor cria um Contractor sem agentes
+func NewContractor(bus Bus) *Contractor {
+	return &Contractor{bus: bus, agents: make(map[string]bidder)}
+}
+
+// Register registra um agente: bid decide os lances e execute roda as tarefas adjudicadas
+func (c *Contractor) Register(agentID string, bid Bidder, execute Executor) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.agents[agentID] = bidder{bid: bid, execute: execute}
+}
+
+// Unregister remove o agente; as tarefas já adjudicadas continuam em execução
+func (c *Contractor) Unregister(agentID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.agents, agentID)
+}
+
+// Start inscreve o Contractor nos anúncios e nas adjudicações até Stop ou o cancelamento do
+// contexto. As tarefas adjudicadas rodam em goroutines com esse contexto; Wait aguarda o término.
+func (c *Contractor) Start(ctx context.Context) error {
+	if err := c.bus.Subscribe(AnnounceSubject, c.handleAnnouncement); err != nil {
+		return fmt.Errorf("erro ao se inscrever nos anúncios: %v", err)
+	}
+	err := c.bus.Subscribe(AwardPrefix+".*", func(_ context.Context, _ string, data []byte) error {
+		return c.handleAward(ctx, data)
+	})
+	if err != nil {
+		c.bus.Unsubscribe(AnnounceSubject)
+		return fmt.Errorf("erro ao se inscrever nas adjudicações: %v", err)
+	}
+
+	go func() {
+		<-ctx.Done()
+		c.Stop()
+	}()
+	return nil
+}
+
+// Stop deixa de responder aos anúncios e de receber adjudicações, sem interromper as tarefas
+// em execução
+func (c *Contractor) Stop() {
+	c.stopOnce.Do(func() {
+		c.bus.Unsubscribe(AnnounceSubject)
+		c.bus.Unsubscribe(AwardPrefix + ".*")
+	})
+}
+
+// Wait aguarda as tarefas adjudicadas em execução
+func (c *Contractor) Wait() {
+	c.running.Wait()
+}
+
+// handleAnnouncement publica os lances dos agentes interessados, se ainda houver prazo
+func (c *Contractor) handleAnnouncement(ctx context.Context, _ string, data []byte) error {
+	var announcement Announcement
+	if err := json.Unmarshal(data, &announcement); err != nil {
+		return fmt.Errorf("anúncio inválido: %v", err)
+	}
+	if !announcement.Deadline.IsZero() && time.Now().After(announcement.Deadline) {
+		return nil
+	}
+
+	c.mu.RLock()
+	agents := make(map[string]bidder, len(c.agents))
+	for id, agent := range c.agents {
+		agents[id] = agent
+	}
+	c.mu.RUnlock()
+
+	for id, agent := range agents {
+		bid, ok := agent.bid(ctx, announcement)
+		if !ok {
+			continue
+		}
+		bid.TaskID = announcement.TaskID
+		bid.AgentID = id
+		bid.Timestamp = time.Now()
+		if err := publishJSON(ctx, c.bus, BidSubject(announcement.TaskID), bid); err != nil {
+			log.Printf("⚠️ Erro ao publicar lance do agente %s: %v", id, err)
+		}
+	}
+	return nil
+}
+
+// handleAward executa a tarefa quando o vencedor é um agente deste Contractor
+func (c *Contractor) handleAward(ctx context.Context, data []byte) error {
+	var award Award
+	if err := json.Unmarshal(data, &award); err != nil {
+		return fmt.Errorf("adjudicação inválida: %v", err)
+	}
+
+	c.mu.RLock()
+	agent, ok := c.agents[award.Bid.AgentID]
+	c.mu.RUnlock()
+	if !ok {
+		return nil
+	}
+
+	c.running.Add(1)
+	go func() {
+		defer c.running.Done()
+		if err := agent.execute(ctx, award); err != nil {
+			log.Printf("❌ Erro ao executar a tarefa %s adjudicada ao agente %s: %v", award.Announcement.TaskID, award.Bid.AgentID, err)
+		}
+	}()
+	return nil
+}
+
+// publishJSON serializa e publica a mensagem
+func publishJSON(ctx context.Context, bus Bus, subject string, value interface{}) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("erro ao serializar mensagem: %v", err)
+	}
+	return bus.Publish(ctx, subject, data)
+}
@@ -0,0 +1,92 @@
+package contractnet
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/suissa/HiveMind/agents/affinity"
+	"github.com/suissa/HiveMind/agents/communication/commtest"
+	"github.com/suissa/HiveMind/agents/errs"
+)
+
+// fixedBid dá sempre o mesmo lance nas tarefas do papel informado
+func fixedBid(role string, cost, confidence float64) Bidder {
+	return func(ctx context.Context, announcement Announcement) (Bid, bool) {
+		if announcement.Role != "" && announcement.Role != role {
+			return Bid{}, false
+		}
+		return Bid{Cost: cost, Confidence: confidence}, true
+	}
+}
+
+func TestAnnounceAwardsBestBid(t *testing.T) {
+	bus := commtest.NewBus()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	executed := make(chan string, 3)
+	execute := func(ctx context.Context, award Award) error {
+		executed <- award.Bid.AgentID
+		return nil
+	}
+	contractor := NewContractor(bus)
+	contractor.Register("busy", fixedBid("writer", 3, 0.9), execute)
+	contractor.Register("idle", fixedBid("writer", 0, 0.6), execute)
+	contractor.Register("reviewer", fixedBid("reviewer", 0, 1), execute)
+	if err := contractor.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	manager := NewManager(bus, Config{BidTimeout: time.Second, ExpectedBids: 2})
+	award, err := manager.Announce(ctx, Announcement{TaskID: "t-1", Role: "writer", Description: "Escrever o post"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if award.Bid.AgentID != "idle" || award.Bids != 2 {
+		t.Fatalf("adjudicação inesperada: %+v", award)
+	}
+	contractor.Wait()
+	if winner := <-executed; winner != "idle" || len(executed) != 0 {
+		t.Fatalf("apenas o vencedor deveria executar a tarefa: %s", winner)
+	}
+
+	contractor.Stop()
+	_, err = NewManager(bus, Config{BidTimeout: 10 * time.Millisecond}).Announce(ctx, Announcement{TaskID: "t-2"})
+	if !errors.Is(err, errs.ErrNotFound) {
+		t.Fatalf("esperava ErrNotFound sem lances, obtido %v", err)
+	}
+}
+
+func TestBestPrefersAffinityOwner(t *testing.T) {
+	manager := NewManager(commtest.NewBus(), Config{})
+	bids := func(ownerCost float64, owner string) []Bid {
+		var out []Bid
+		for _, id := range []string{"w-1", "w-2", "w-3"} {
+			cost := 0.0
+			if id == owner {
+				cost = ownerCost
+			}
+			out = append(out, Bid{AgentID: id, Cost: cost, Confidence: 0.8})
+		}
+		return out
+	}
+	announcement := Announcement{TaskID: "t-1-2", Affinity: "t-1"}
+	owner := affinity.Pick("t-1", []string{"w-1", "w-2", "w-3"})
+
+	// Com carga moderada o dono mantém as subtarefas da tarefa
+	if got := manager.best(announcement, bids(0.5, owner)); got.AgentID != owner {
+		t.Fatalf("o dono da afinidade deveria vencer: %s != %s", got.AgentID, owner)
+	}
+	// Sobrecarregado, a tarefa vai para a próxima instância na ordem da afinidade
+	next := affinity.Rank("t-1", []string{"w-1", "w-2", "w-3"})[1]
+	if got := manager.best(announcement, bids(5, owner)); got.AgentID != next {
+		t.Fatalf("a próxima instância da afinidade deveria vencer: %s != %s", got.AgentID, next)
+	}
+	// Sem afinidade vence o melhor lance, com o menor ID no empate
+	manager = NewManager(commtest.NewBus(), Config{DisableAffinity: true})
+	if got := manager.best(announcement, bids(0.5, "w-1")); got.AgentID != "w-2" {
+		t.Fatalf("sem afinidade deveria vencer o melhor lance: %s", got.AgentID)
+	}
+}
@@ -0,0 +1,272 @@
+// Package control implementa o plano de controle dos agentes: o orquestrador (Controller)
+// envia comandos administrativos às instâncias dos agentes pelo barramento (pausar ou retomar
+// a entrada de tarefas, drenar as tarefas em andamento, recarregar a configuração e mudar o
+// nível dos logs) e cada processo (Node) os aplica aos seus agentes e responde com uma
+// confirmação.
+//
+// As confirmações atualizam a situação administrativa do agente no registro de presença do
+// orquestrador, e os heartbeats seguintes do agente a levam aos demais monitores. Agentes
+// pausados ou drenados deixam de ser considerados disponíveis para novas tarefas.
+package control
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/suissa/HiveMind/agents/errs"
+	"github.com/suissa/HiveMind/agents/logging"
+	"github.com/suissa/HiveMind/agents/metrics"
+	"github.com/suissa/HiveMind/agents/presence"
+)
+
+// SubjectPrefix é o prefixo dos tópicos do plano de controle
+const SubjectPrefix = "control"
+
+// Valores padrão da configuração
+const (
+	DefaultTimeout      = 10 * time.Second
+	DefaultDrainTimeout = 30 * time.Second
+)
+
+var commandsTotal = metrics.Default.Counter("hivemind_control_commands_total",
+	"Comandos do plano de controle por ação e resultado (ok, error ou timeout)", "action", "result")
+
+// CommandSubject é o tópico dos comandos dirigidos ao agente
+func CommandSubject(agentID string) string {
+	return SubjectPrefix + ".command." + agentID
+}
+
+// AckSubject é o tópico das confirmações enviadas ao controlador
+func AckSubject(controllerID string) string {
+	return SubjectPrefix + ".ack." + controllerID
+}
+
+// Action é a ação administrativa de um comando
+type Action string
+
+const (
+	ActionPause    Action = "pause"     // Para de receber tarefas, sem interromper as em andamento
+	ActionResume   Action = "resume"    // Volta a receber tarefas
+	ActionDrain    Action = "drain"     // Para de receber tarefas e aguarda as em andamento
+	ActionReload   Action = "reload"    // Recarrega a configuração
+	ActionLogLevel Action = "log_level" // Muda o nível dos logs (argumento "level")
+)
+
+// Config define a espera pelas confirmações
+type Config struct {
+	Timeout      time.Duration `json:"timeout,omitempty" yaml:"timeout,omitempty"`             // Espera pela confirmação de um comando
+	DrainTimeout time.Duration `json:"drain_timeout,omitempty" yaml:"drain_timeout,omitempty"` // Prazo da drenagem, somado à espera
+}
+
+// withDefaults completa os padrões da configuração
+func (c Config) withDefaults() Config {
+	if c.Timeout <= 0 {
+		c.Timeout = DefaultTimeout
+	}
+	if c.DrainTimeout <= 0 {
+		c.DrainTimeout = DefaultDrainTimeout
+	}
+	return c
+}
+
+// Command é um comando administrativo dirigido a um agente
+type Command struct {
+	ID        string            `json:"id"`
+	AgentID   string            `json:"agent_id"`
+	Action    Action            `json:"action"`
+	Args      map[string]string `json:"args,omitempty"`
+	ReplyTo   string            `json:"reply_to"`          // Controlador que aguarda a confirmação
+	Timeout   time.Duration     `json:"timeout,omitempty"` // Prazo da drenagem
+	Timestamp time.Time         `json:"timestamp"`
+}
+
+// Ack é a confirmação de um comando, com a situação do agente depois dele
+type Ack struct {
+	CommandID string           `json:"command_id"`
+	AgentID   string           `json:"agent_id"`
+	Action    Action           `json:"action"`
+	OK        bool             `json:"ok"`
+	Error     string           `json:"error,omitempty"`
+	Control   presence.Control `json:"control"`
+	Timestamp time.Time        `json:"timestamp"`
+}
+
+// Registry informa as instâncias conhecidas e registra a situação confirmada por elas;
+// presence.Monitor o implementa
+type Registry interface {
+	Agents() []presence.Status
+	Acknowledge(agentID string, control presence.Control)
+}
+
+// Controller envia os comandos aos agentes e aguarda as confirmações
+type Controller struct {
+	id       string
+	bus      presence.Bus
+	registry Registry
+	config   Config
+	pending  map[string]chan Ack // Comandos aguardando confirmação, pelo ID
+	acks     map[string]Ack      // Última confirmação de cada agente
+	mu       sync.Mutex
+}
+
+// NewController cria o controlador; Start o inscreve nas confirmações
+func NewController(bus presence.Bus, registry Registry, config Config) *Controller {
+	return &Controller{
+		id:       uuid.NewString(),
+		bus:      bus,
+		registry: registry,
+		config:   config.withDefaults(),
+		pending:  make(map[string]chan Ack),
+		acks:     make(map[string]Ack),
+	}
+}
+
+// ID retorna o identificador do controlador, usado no tópico das confirmações
+func (c *Controller) ID() string {
+	return c.id
+}
+
+// Start inscreve o controlador nas confirmações até o contexto ser cancelado
+func (c *Controller) Start(ctx context.Context) error {
+	subject := AckSubject(c.id)
+	err := c.bus.Subscribe(subject, func(_ context.Context, _ string, data []byte) error {
+		return c.handleAck(data)
+	})
+	if err != nil {
+		return fmt.Errorf("erro ao se inscrever nas confirmações do plano de controle: %v", err)
+	}
+	go func() {
+		<-ctx.Done()
+		c.bus.Unsubscribe(subject)
+	}()
+	return nil
+}
+
+// Send envia o comando ao agente e aguarda a confirmação. Um comando recusado retorna a
+// confirmação com o erro informado pelo agente; sem confirmação no prazo, retorna um erro
+// errs.ErrTimeout.
+func (c *Controller) Send(ctx context.Context, agentID string, action Action, args map[string]string) (Ack, error) {
+	cmd := Command{
+		ID:        uuid.NewString(),
+		AgentID:   agentID,
+		Action:    action,
+		Args:      args,
+		ReplyTo:   c.id,
+		Timestamp: time.Now(),
+	}
+	wait := c.config.Timeout
+	if action == ActionDrain {
+		cmd.Timeout = c.config.DrainTimeout
+		wait += cmd.Timeout
+	}
+	data, err := json.Marshal(cmd)
+	if err != nil {
+		return Ack{}, fmt.Errorf("erro ao serializar o comando: %v", err)
+	}
+
+	reply := make(chan Ack, 1)
+	c.mu.Lock()
+	c.pending[cmd.ID] = reply
+	c.mu.Unlock()
+	defer func() {
+		c.mu.Lock()
+		delete(c.pending, cmd.ID)
+		c.mu.Unlock()
+	}()
+
+	if err := c.bus.Publish(ctx, CommandSubject(agentID), data); err != nil {
+		commandsTotal.Inc(string(action), "error")
+		return Ack{}, fmt.Errorf("erro ao enviar o comando %s ao agente %s: %v", action, agentID, err)
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case ack := <-reply:
+		if !ack.OK {
+			commandsTotal.Inc(string(action), "error")
+			return ack, fmt.Errorf("o agente %s recusou o comando %s: %s", agentID, action, ack.Error)
+		}
+		commandsTotal.Inc(string(action), "ok")
+		return ack, nil
+	case <-timer.C:
+		commandsTotal.Inc(string(action), "timeout")
+		return Ack{}, errs.New(errs.ErrTimeout, "control.Send", "o agente %s não confirmou o comando %s em %s", agentID, action, wait)
+	case <-ctx.Done():
+		return Ack{}, errs.FromContext("control.Send", ctx.Err())
+	}
+}
+
+// Broadcast envia o comando a todas as instâncias do papel (todas, se role for vazio) com
+// heartbeats em dia e retorna as confirmações recebidas. Os erros das instâncias que
+// recusaram ou não confirmaram são combinados no erro retornado.
+func (c *Controller) Broadcast(ctx context.Context, role string, action Action, args map[string]string) ([]Ack, error) {
+	var targets []string
+	for _, status := range c.registry.Agents() {
+		if status.State == presence.StateHealthy && (role == "" || status.Role == role) {
+			targets = append(targets, status.AgentID)
+		}
+	}
+
+	acks := make([]Ack, len(targets))
+	failures := make([]error, len(targets))
+	var wg sync.WaitGroup
+	for i, agentID := range targets {
+		wg.Add(1)
+		go func(i int, agentID string) {
+			defer wg.Done()
+			acks[i], failures[i] = c.Send(ctx, agentID, action, args)
+		}(i, agentID)
+	}
+	wg.Wait()
+
+	confirmed := make([]Ack, 0, len(acks))
+	for i, ack := range acks {
+		if failures[i] == nil {
+			confirmed = append(confirmed, ack)
+		}
+	}
+	return confirmed, errors.Join(failures...)
+}
+
+// Acks retorna a última confirmação de cada agente, ordenadas pelo ID do agente
+func (c *Controller) Acks() []Ack {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	acks := make([]Ack, 0, len(c.acks))
+	for _, ack := range c.acks {
+		acks = append(acks, ack)
+	}
+	sort.Slice(acks, func(i, j int) bool { return acks[i].AgentID < acks[j].AgentID })
+	return acks
+}
+
+// handleAck entrega a confirmação ao comando que a aguarda e registra no registro de presença a
+// situação informada pelo agente
+func (c *Controller) handleAck(data []byte) error {
+	var ack Ack
+	if err := json.Unmarshal(data, &ack); err != nil {
+		return fmt.Errorf("confirmação do plano de controle inválida: %v", err)
+	}
+	c.mu.Lock()
+	reply, waiting := c.pending[ack.CommandID]
+	delete(c.pending, ack.CommandID)
+	c.acks[ack.AgentID] = ack
+	c.mu.Unlock()
+
+	// Mesmo recusado, o comando pode ter alterado a situação (a drenagem expirada pausa a entrada)
+	c.registry.Acknowledge(ack.AgentID, ack.Control)
+	if waiting {
+		reply <- ack
+	} else {
+		logging.Debugf("🛂 Confirmação do comando %s recebida fora do prazo", ack.CommandID)
+	}
+	return nil
+}
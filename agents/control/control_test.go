@@ -0,0 +1,192 @@
+package control
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/suissa/HiveMind/agents/communication/commtest"
+	"github.com/suissa/HiveMind/agents/errs"
+	"github.com/suissa/HiveMind/agents/logging"
+	"github.com/suissa/HiveMind/agents/presence"
+)
+
+// setup cria um controlador e um nó com os agentes informados (ID e papel) já registrados
+// no monitor de presença
+func setup(t *testing.T, agents map[string]string) (*Controller, *Node, *presence.Monitor) {
+	t.Helper()
+	bus := commtest.NewBus()
+	monitor := presence.NewMonitor(bus, presence.Config{})
+	node := NewNode(bus)
+	for id, role := range agents {
+		monitor.Observe(presence.Heartbeat{AgentID: id, Role: role}, time.Now())
+		if err := node.Add(id); err != nil {
+			t.Fatal(err)
+		}
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	controller := NewController(bus, monitor, Config{Timeout: 200 * time.Millisecond})
+	if err := controller.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	return controller, node, monitor
+}
+
+func noop(context.Context, string, Command) error { return nil }
+
+func TestPauseAndResumeReflectedInRegistry(t *testing.T) {
+	controller, node, monitor := setup(t, map[string]string{"a-1": "analyst"})
+	node.Handle(ActionPause, noop)
+	node.Handle(ActionResume, noop)
+	ctx := context.Background()
+
+	ack, err := controller.Send(ctx, "a-1", ActionPause, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ack.Control.Intake != presence.IntakePaused || ack.Control.LastCommand != ack.CommandID {
+		t.Fatalf("confirmação inesperada: %+v", ack)
+	}
+	status, _ := monitor.Status("a-1")
+	if status.Intake() != presence.IntakePaused || status.Available() || monitor.Available("a-1") {
+		t.Fatalf("o registro deveria mostrar o agente pausado: %+v", status)
+	}
+	if node.Control("a-1").Intake != presence.IntakePaused {
+		t.Fatalf("o nó deveria informar a pausa nos heartbeats: %+v", node.Control("a-1"))
+	}
+
+	if _, err := controller.Send(ctx, "a-1", ActionResume, nil); err != nil {
+		t.Fatal(err)
+	}
+	if !monitor.Available("a-1") {
+		t.Fatal("o agente deveria voltar a ficar disponível")
+	}
+	if acks := controller.Acks(); len(acks) != 1 || acks[0].Action != ActionResume {
+		t.Fatalf("últimas confirmações inesperadas: %+v", acks)
+	}
+}
+
+func TestDrainWaitsForHandler(t *testing.T) {
+	controller, node, _ := setup(t, map[string]string{"a-1": "analyst"})
+	var during presence.Intake
+	node.Handle(ActionDrain, func(ctx context.Context, agentID string, cmd Command) error {
+		during = node.Control(agentID).Intake
+		if _, ok := ctx.Deadline(); !ok {
+			t.Error("a drenagem deveria ter prazo")
+		}
+		return nil
+	})
+
+	ack, err := controller.Send(context.Background(), "a-1", ActionDrain, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if during != presence.IntakeDraining || ack.Control.Intake != presence.IntakeDrained {
+		t.Fatalf("situação durante (%q) e depois (%q) da drenagem inesperadas", during, ack.Control.Intake)
+	}
+}
+
+func TestFailedCommandKeepsState(t *testing.T) {
+	controller, node, monitor := setup(t, map[string]string{"a-1": "analyst"})
+	node.Handle(ActionPause, func(context.Context, string, Command) error { return errors.New("agente ocupado") })
+
+	ack, err := controller.Send(context.Background(), "a-1", ActionPause, nil)
+	if err == nil || ack.OK || !strings.Contains(ack.Error, "ocupado") {
+		t.Fatalf("esperava a recusa do comando, veio %+v, %v", ack, err)
+	}
+	if node.Control("a-1") != (presence.Control{}) || !monitor.Available("a-1") {
+		t.Fatalf("a falha não deveria alterar a situação: %+v", node.Control("a-1"))
+	}
+
+	if _, err := controller.Send(context.Background(), "a-1", ActionReload, nil); err == nil {
+		t.Fatal("ação sem tratamento deveria ser recusada")
+	}
+}
+
+func TestDrainTimeoutLeavesPaused(t *testing.T) {
+	controller, node, monitor := setup(t, map[string]string{"a-1": "analyst"})
+	controller.config.DrainTimeout = 10 * time.Millisecond
+	node.Handle(ActionDrain, func(ctx context.Context, _ string, _ Command) error {
+		<-ctx.Done()
+		return errs.FromContext("drain", ctx.Err())
+	})
+
+	if _, err := controller.Send(context.Background(), "a-1", ActionDrain, nil); err == nil {
+		t.Fatal("a drenagem deveria estourar o prazo")
+	}
+	if node.Control("a-1").Intake != presence.IntakePaused || monitor.Available("a-1") {
+		t.Fatalf("a drenagem expirada deveria deixar a entrada pausada: %+v", node.Control("a-1"))
+	}
+}
+
+func TestLogLevel(t *testing.T) {
+	controller, _, monitor := setup(t, map[string]string{"a-1": "analyst"})
+	defer logging.SetLevel(logging.CurrentLevel())
+
+	ack, err := controller.Send(context.Background(), "a-1", ActionLogLevel, map[string]string{"level": "debug"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if logging.CurrentLevel() != logging.LevelDebug || ack.Control.LogLevel != "debug" {
+		t.Fatalf("nível não aplicado: %v, %+v", logging.CurrentLevel(), ack.Control)
+	}
+	if status, _ := monitor.Status("a-1"); status.Control == nil || status.Control.LogLevel != "debug" || !status.Available() {
+		t.Fatalf("o registro deveria mostrar o nível: %+v", status)
+	}
+
+	if _, err := controller.Send(context.Background(), "a-1", ActionLogLevel, map[string]string{"level": "verbose"}); err == nil {
+		t.Fatal("nível inválido deveria ser recusado")
+	}
+}
+
+func TestBroadcastByRole(t *testing.T) {
+	controller, node, _ := setup(t, map[string]string{"a-1": "analyst", "a-2": "analyst", "w-1": "writer"})
+	var paused []string
+	var mu sync.Mutex
+	node.Handle(ActionPause, func(_ context.Context, agentID string, _ Command) error {
+		mu.Lock()
+		defer mu.Unlock()
+		paused = append(paused, agentID)
+		return nil
+	})
+
+	acks, err := controller.Broadcast(context.Background(), "analyst", ActionPause, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(acks) != 2 || len(paused) != 2 || node.Control("w-1").Intake != presence.IntakeOpen {
+		t.Fatalf("só os analistas deveriam pausar: %v", paused)
+	}
+}
+
+func TestSendTimeout(t *testing.T) {
+	controller, _, _ := setup(t, nil)
+	_, err := controller.Send(context.Background(), "ausente", ActionPause, nil)
+	if !errors.Is(err, errs.ErrTimeout) {
+		t.Fatalf("esperava ErrTimeout, veio %v", err)
+	}
+}
+
+func TestRepeatedCommandAppliedOnce(t *testing.T) {
+	bus := commtest.NewBus()
+	node := NewNode(bus)
+	if err := node.Add("a-1"); err != nil {
+		t.Fatal(err)
+	}
+	calls := 0
+	node.Handle(ActionPause, func(context.Context, string, Command) error { calls++; return nil })
+
+	data := []byte(`{"id":"c-1","agent_id":"a-1","action":"pause"}`)
+	for i := 0; i < 2; i++ {
+		if err := bus.Publish(context.Background(), CommandSubject("a-1"), data); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if calls != 1 {
+		t.Fatalf("o comando repetido deveria ser aplicado uma vez, foi %d", calls)
+	}
+}
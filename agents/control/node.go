@@ -0,0 +1,201 @@
+package control
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/suissa/HiveMind/agents/errs"
+	"github.com/suissa/HiveMind/agents/logging"
+	"github.com/suissa/HiveMind/agents/presence"
+)
+
+// Handler aplica um comando a um agente local; o erro retornado é enviado na confirmação
+type Handler func(ctx context.Context, agentID string, cmd Command) error
+
+// agentState é a situação administrativa de um agente local
+type agentState struct {
+	control presence.Control
+	busy    sync.Mutex // Aplica os comandos do agente um de cada vez, na ordem de chegada
+}
+
+// Node atende os comandos dirigidos aos agentes locais de um processo
+type Node struct {
+	bus      presence.Bus
+	handlers map[Action]Handler
+	agents   map[string]*agentState
+	mu       sync.RWMutex
+}
+
+// NewNode cria o nó do plano de controle. O comando log_level é atendido pelo próprio nó,
+// mudando o nível dos logs do processo; as demais ações precisam de um Handler.
+func NewNode(bus presence.Bus) *Node {
+	n := &Node{
+		bus:      bus,
+		handlers: make(map[Action]Handler),
+		agents:   make(map[string]*agentState),
+	}
+	n.Handle(ActionLogLevel, setLogLevel)
+	return n
+}
+
+// Handle define o tratamento de uma ação. A situação da entrada de tarefas é atualizada pelo
+// nó: pause e drain a fecham, resume a reabre.
+func (n *Node) Handle(action Action, handler Handler) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.handlers[action] = handler
+}
+
+// Add inscreve o agente local nos comandos dirigidos a ele
+func (n *Node) Add(agentID string) error {
+	err := n.bus.Subscribe(CommandSubject(agentID), func(ctx context.Context, _ string, data []byte) error {
+		return n.handle(ctx, agentID, data)
+	})
+	if err != nil {
+		return fmt.Errorf("erro ao inscrever o agente %s no plano de controle: %v", agentID, err)
+	}
+	n.mu.Lock()
+	if _, ok := n.agents[agentID]; !ok {
+		n.agents[agentID] = &agentState{}
+	}
+	n.mu.Unlock()
+	return nil
+}
+
+// Remove retira o agente local do plano de controle
+func (n *Node) Remove(agentID string) {
+	n.bus.Unsubscribe(CommandSubject(agentID))
+	n.mu.Lock()
+	delete(n.agents, agentID)
+	n.mu.Unlock()
+}
+
+// Stop retira todos os agentes locais do plano de controle
+func (n *Node) Stop() {
+	n.mu.Lock()
+	ids := make([]string, 0, len(n.agents))
+	for id := range n.agents {
+		ids = append(ids, id)
+	}
+	n.mu.Unlock()
+	for _, id := range ids {
+		n.Remove(id)
+	}
+}
+
+// Control retorna a situação administrativa do agente local, informada nos heartbeats
+func (n *Node) Control(agentID string) presence.Control {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	if state, ok := n.agents[agentID]; ok {
+		return state.control
+	}
+	return presence.Control{}
+}
+
+// handle aplica o comando ao agente e publica a confirmação
+func (n *Node) handle(ctx context.Context, agentID string, data []byte) error {
+	var cmd Command
+	if err := json.Unmarshal(data, &cmd); err != nil {
+		return fmt.Errorf("comando do plano de controle inválido: %v", err)
+	}
+	n.mu.RLock()
+	state, ok := n.agents[agentID]
+	handler := n.handlers[cmd.Action]
+	n.mu.RUnlock()
+	if !ok {
+		return nil
+	}
+
+	state.busy.Lock()
+	defer state.busy.Unlock()
+
+	ack := Ack{CommandID: cmd.ID, AgentID: agentID, Action: cmd.Action, OK: true}
+	switch {
+	case cmd.ID != "" && cmd.ID == n.Control(agentID).LastCommand:
+		// Entrega repetida de um comando já aplicado: apenas confirma de novo
+	case handler == nil:
+		ack.OK, ack.Error = false, fmt.Sprintf("ação não suportada: %s", cmd.Action)
+	default:
+		if err := n.apply(ctx, agentID, cmd, handler); err != nil {
+			ack.OK, ack.Error = false, err.Error()
+			logging.Warnf("⚠️ Comando %s do plano de controle falhou no agente %s: %v", cmd.Action, agentID, err)
+		} else {
+			logging.Infof("🛂 Comando %s aplicado ao agente %s", cmd.Action, agentID)
+		}
+	}
+	ack.Control = n.Control(agentID)
+	ack.Timestamp = time.Now()
+
+	if cmd.ReplyTo == "" {
+		return nil
+	}
+	out, err := json.Marshal(ack)
+	if err != nil {
+		return fmt.Errorf("erro ao serializar a confirmação: %v", err)
+	}
+	return n.bus.Publish(ctx, AckSubject(cmd.ReplyTo), out)
+}
+
+// apply executa o tratamento e atualiza a situação do agente. Durante a drenagem a entrada
+// aparece como draining nos heartbeats, e a drenagem que estoura o prazo a deixa pausada; nas
+// demais falhas a situação anterior é mantida.
+func (n *Node) apply(ctx context.Context, agentID string, cmd Command, handler Handler) error {
+	previous := n.Control(agentID)
+	if cmd.Action == ActionDrain {
+		n.update(agentID, func(c *presence.Control) { c.Intake = presence.IntakeDraining })
+		if cmd.Timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, cmd.Timeout)
+			defer cancel()
+		}
+	}
+
+	if err := handler(ctx, agentID, cmd); err != nil {
+		n.update(agentID, func(c *presence.Control) {
+			*c = previous
+			if cmd.Action == ActionDrain && errors.Is(err, errs.ErrTimeout) {
+				c.Intake = presence.IntakePaused
+			}
+		})
+		return err
+	}
+
+	n.update(agentID, func(c *presence.Control) {
+		switch cmd.Action {
+		case ActionPause:
+			c.Intake = presence.IntakePaused
+		case ActionResume:
+			c.Intake = presence.IntakeOpen
+		case ActionDrain:
+			c.Intake = presence.IntakeDrained
+		case ActionLogLevel:
+			c.LogLevel = logging.CurrentLevel().String()
+		}
+		c.LastCommand = cmd.ID
+	})
+	return nil
+}
+
+// update altera a situação do agente local
+func (n *Node) update(agentID string, change func(*presence.Control)) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if state, ok := n.agents[agentID]; ok {
+		change(&state.control)
+	}
+}
+
+// setLogLevel muda o nível dos logs do processo para o argumento "level" do comando
+func setLogLevel(_ context.Context, _ string, cmd Command) error {
+	level, err := logging.ParseLevel(cmd.Args["level"])
+	if err != nil {
+		return err
+	}
+	logging.SetLevel(level)
+	return nil
+}
@@ -0,0 +1,71 @@
+package agents
+
+import (
+	"github.com/suissa/HiveMind/agents/crewai"
+)
+
+// ImportCrewAI lê os agents.yaml e tasks.yaml de um projeto CrewAI e os converte para a
+// configuração dos agentes e das tarefas do HiveMind. Com inputs, os {placeholders} são
+// substituídos como no kickoff do CrewAI; sem inputs, são mantidos. Os campos ignorados
+// são retornados para revisão.
+func ImportCrewAI(agentsFile, tasksFile string, inputs map[string]string) (*AgentsConfig, *TasksConfig, []crewai.Issue, error) {
+	crew, err := crewai.Load(agentsFile, tasksFile)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if inputs != nil {
+		if crew, err = crew.Interpolate(inputs); err != nil {
+			return nil, nil, nil, err
+		}
+	}
+	agentsConfig, tasksConfig := FromCrewAI(crew)
+	return agentsConfig, tasksConfig, crew.Issues, nil
+}
+
+// FromCrewAI converte os agentes e as tarefas do CrewAI. A chave de cada agente vira o ID e
+// o papel (para a matriz de permissões, que recebe as ferramentas do agente) e o role do
+// CrewAI vira o nome. O expected_output vira o resultado esperado da tarefa, verificado com
+// CognitiveAgent.SetOutputValidation. Tarefas sem context dependem da anterior, como no
+// processo sequencial do CrewAI.
+func FromCrewAI(crew *crewai.Crew) (*AgentsConfig, *TasksConfig) {
+	agentsConfig := &AgentsConfig{Agents: make([]AgentConfig, 0, len(crew.Agents))}
+	for _, agent := range crew.Agents {
+		agentsConfig.Agents = append(agentsConfig.Agents, AgentConfig{
+			ID:        agent.Key,
+			Name:      agent.Role,
+			Role:      agent.Key,
+			Goal:      agent.Goal,
+			Backstory: agent.Backstory,
+			Model:     agent.LLM,
+			MaxRounds: agent.MaxIter,
+		})
+		if len(agent.Tools) > 0 {
+			if agentsConfig.ToolPermissions.Agents == nil {
+				agentsConfig.ToolPermissions.Agents = make(map[string][]string)
+			}
+			agentsConfig.ToolPermissions.Agents[agent.Key] = agent.Tools
+		}
+	}
+
+	tasksConfig := &TasksConfig{Tasks: make([]TaskConfig, 0, len(crew.Tasks))}
+	for i, task := range crew.Tasks {
+		dependencies := task.Context
+		if dependencies == nil {
+			dependencies = []string{}
+			if i > 0 {
+				dependencies = []string{crew.Tasks[i-1].Key}
+			}
+		}
+		tasksConfig.Tasks = append(tasksConfig.Tasks, TaskConfig{
+			ID:             task.Key,
+			Name:           task.Key,
+			Description:    task.Description,
+			ExpectedOutput: task.ExpectedOutput,
+			AssignedTo:     task.Agent,
+			Dependencies:   dependencies,
+			Priority:       i + 1,
+			Status:         "pending",
+		})
+	}
+	return agentsConfig, tasksConfig
+}
@@ -0,0 +1,261 @@
+// Package crewai lê os arquivos agents.yaml e tasks.yaml do CrewAI sem alterações, com os
+// {placeholders} e as referências entre agentes e tarefas, e relata os campos que o HiveMind
+// não suporta. A conversão para a configuração dos agentes fica em agents.ImportCrewAI.
+package crewai
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/suissa/HiveMind/agents/errs"
+)
+
+// Agent é um agente do agents.yaml do CrewAI
+type Agent struct {
+	Key       string // Nome da entrada no YAML, usado nas referências das tarefas
+	Role      string
+	Goal      string
+	Backstory string
+	LLM       string
+	Tools     []string
+	MaxIter   int
+}
+
+// Task é uma tarefa do tasks.yaml do CrewAI
+type Task struct {
+	Key            string
+	Description    string
+	ExpectedOutput string
+	Agent          string   // Chave do agente responsável
+	Context        []string // Chaves das tarefas cujas saídas a tarefa recebe
+}
+
+// Issue é um campo do CrewAI ignorado na importação
+type Issue struct {
+	File   string
+	Entry  string
+	Field  string
+	Reason string
+}
+
+func (i Issue) String() string {
+	if i.Field == "" {
+		return fmt.Sprintf("%s: %s: %s", i.File, i.Entry, i.Reason)
+	}
+	return fmt.Sprintf("%s: %s.%s: %s", i.File, i.Entry, i.Field, i.Reason)
+}
+
+// Crew são os agentes e as tarefas importados, na ordem dos arquivos, com os campos ignorados
+type Crew struct {
+	Agents []Agent
+	Tasks  []Task
+	Issues []Issue
+}
+
+// Load lê os arquivos do CrewAI (tasksFile é opcional)
+func Load(agentsFile, tasksFile string) (*Crew, error) {
+	agentsData, err := os.ReadFile(agentsFile)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler os agentes do CrewAI: %v", err)
+	}
+	var tasksData []byte
+	if tasksFile != "" {
+		if tasksData, err = os.ReadFile(tasksFile); err != nil {
+			return nil, fmt.Errorf("erro ao ler as tarefas do CrewAI: %v", err)
+		}
+	}
+	return Parse(agentsData, tasksData)
+}
+
+// Parse interpreta o conteúdo dos arquivos do CrewAI e valida as referências das tarefas
+func Parse(agentsData, tasksData []byte) (*Crew, error) {
+	crew := &Crew{}
+
+	entries, err := parseEntries(agentsData, "agents.yaml")
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		agent := Agent{Key: entry.key}
+		for _, field := range entry.fields {
+			switch field.Key {
+			case "role":
+				agent.Role = text(field.Value)
+			case "goal":
+				agent.Goal = text(field.Value)
+			case "backstory":
+				agent.Backstory = text(field.Value)
+			case "llm":
+				agent.LLM = text(field.Value)
+			case "tools":
+				agent.Tools = list(field.Value)
+			case "max_iter":
+				agent.MaxIter, _ = field.Value.(int)
+			default:
+				crew.unsupported("agents.yaml", entry.key, fmt.Sprint(field.Key))
+			}
+		}
+		if agent.Role == "" {
+			return nil, errs.New(errs.ErrValidation, "crewai.Parse", "agente %s sem role", agent.Key)
+		}
+		crew.Agents = append(crew.Agents, agent)
+	}
+
+	entries, err = parseEntries(tasksData, "tasks.yaml")
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		task := Task{Key: entry.key}
+		for _, field := range entry.fields {
+			switch field.Key {
+			case "description":
+				task.Description = text(field.Value)
+			case "expected_output":
+				task.ExpectedOutput = text(field.Value)
+			case "agent":
+				task.Agent = text(field.Value)
+			case "context":
+				task.Context = list(field.Value)
+			default:
+				crew.unsupported("tasks.yaml", entry.key, fmt.Sprint(field.Key))
+			}
+		}
+		if task.Agent == "" {
+			crew.Issues = append(crew.Issues, Issue{File: "tasks.yaml", Entry: task.Key, Reason: "tarefa sem agente (processo hierárquico); atribua um responsável"})
+		}
+		crew.Tasks = append(crew.Tasks, task)
+	}
+
+	return crew, crew.validate()
+}
+
+// unsupported registra um campo ignorado
+func (c *Crew) unsupported(file, entry, field string) {
+	c.Issues = append(c.Issues, Issue{File: file, Entry: entry, Field: field, Reason: "campo não suportado, ignorado"})
+}
+
+// validate verifica se os agentes e as tarefas referenciados existem
+func (c *Crew) validate() error {
+	agents := make(map[string]bool, len(c.Agents))
+	for _, agent := range c.Agents {
+		agents[agent.Key] = true
+	}
+	tasks := make(map[string]bool, len(c.Tasks))
+	for _, task := range c.Tasks {
+		tasks[task.Key] = true
+	}
+	for _, task := range c.Tasks {
+		if task.Agent != "" && !agents[task.Agent] {
+			return errs.New(errs.ErrValidation, "crewai.Parse", "tarefa %s referencia o agente inexistente %s", task.Key, task.Agent)
+		}
+		for _, ref := range task.Context {
+			if !tasks[ref] {
+				return errs.New(errs.ErrValidation, "crewai.Parse", "tarefa %s usa o contexto da tarefa inexistente %s", task.Key, ref)
+			}
+		}
+	}
+	return nil
+}
+
+// placeholder reconhece os {placeholders} interpolados pelo kickoff do CrewAI
+var placeholder = regexp.MustCompile(`\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// Placeholders retorna os nomes dos {placeholders} usados nos textos, em ordem alfabética
+func (c *Crew) Placeholders() []string {
+	seen := make(map[string]bool)
+	for _, value := range c.texts() {
+		for _, match := range placeholder.FindAllStringSubmatch(*value, -1) {
+			seen[match[1]] = true
+		}
+	}
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Interpolate retorna uma cópia com os {placeholders} substituídos pelas entradas, como no
+// kickoff(inputs=...) do CrewAI. Placeholders sem entrada são um erro.
+func (c *Crew) Interpolate(inputs map[string]string) (*Crew, error) {
+	var missing []string
+	for _, name := range c.Placeholders() {
+		if _, ok := inputs[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, errs.New(errs.ErrValidation, "crewai.Interpolate", "entradas ausentes: %s", strings.Join(missing, ", "))
+	}
+
+	clone := &Crew{
+		Agents: append([]Agent(nil), c.Agents...),
+		Tasks:  append([]Task(nil), c.Tasks...),
+		Issues: c.Issues,
+	}
+	for _, value := range clone.texts() {
+		*value = placeholder.ReplaceAllStringFunc(*value, func(match string) string {
+			return inputs[match[1:len(match)-1]]
+		})
+	}
+	return clone, nil
+}
+
+// texts retorna os campos de texto que aceitam placeholders
+func (c *Crew) texts() []*string {
+	var values []*string
+	for i := range c.Agents {
+		values = append(values, &c.Agents[i].Role, &c.Agents[i].Goal, &c.Agents[i].Backstory)
+	}
+	for i := range c.Tasks {
+		values = append(values, &c.Tasks[i].Description, &c.Tasks[i].ExpectedOutput)
+	}
+	return values
+}
+
+// entry é uma entrada de primeiro nível do YAML, com os campos na ordem do arquivo
+type entry struct {
+	key    string
+	fields yaml.MapSlice
+}
+
+func parseEntries(data []byte, file string) ([]entry, error) {
+	var root yaml.MapSlice
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, errs.Wrap(errs.ErrValidation, "crewai.Parse", err, "erro ao decodificar %s", file)
+	}
+	entries := make([]entry, 0, len(root))
+	for _, item := range root {
+		fields, ok := item.Value.(yaml.MapSlice)
+		if !ok {
+			return nil, errs.New(errs.ErrValidation, "crewai.Parse", "%s: a entrada %v deveria ser um mapa", file, item.Key)
+		}
+		entries = append(entries, entry{key: fmt.Sprint(item.Key), fields: fields})
+	}
+	return entries, nil
+}
+
+// text normaliza um texto do YAML (os blocos ">" do CrewAI terminam com quebra de linha)
+func text(value interface{}) string {
+	if value == nil {
+		return ""
+	}
+	return strings.TrimSpace(fmt.Sprint(value))
+}
+
+// list converte uma lista do YAML em strings
+func list(value interface{}) []string {
+	items, _ := value.([]interface{})
+	values := make([]string, 0, len(items))
+	for _, item := range items {
+		values = append(values, text(item))
+	}
+	return values
+}
@@ -0,0 +1,112 @@
+package crewai
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/suissa/HiveMind/agents/errs"
+)
+
+const agentsYAML = `
+researcher:
+  role: >
+    {topic} Senior Data Researcher
+  goal: >
+    Uncover cutting-edge developments in {topic}
+  backstory: >
+    You're a seasoned researcher with a knack for uncovering the latest
+    developments in {topic}.
+  llm: openai/gpt-4o
+  tools: [serper_search]
+  verbose: true
+reporting_analyst:
+  role: >
+    {topic} Reporting Analyst
+  goal: >
+    Create detailed reports based on {topic} data analysis
+  backstory: >
+    You're a meticulous analyst.
+  max_iter: 5
+`
+
+const tasksYAML = `
+research_task:
+  description: >
+    Conduct a thorough research about {topic} in {year}
+  expected_output: >
+    A list with 10 bullet points of the most relevant information about {topic}
+  agent: researcher
+reporting_task:
+  description: >
+    Review the context you got and expand each topic into a full section for a report.
+  expected_output: >
+    A fully fledged report formatted as markdown
+  agent: reporting_analyst
+  context: [research_task]
+  output_file: report.md
+`
+
+func TestParseKeepsOrderPlaceholdersAndReportsUnsupportedFields(t *testing.T) {
+	crew, err := Parse([]byte(agentsYAML), []byte(tasksYAML))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(crew.Agents) != 2 || crew.Agents[0].Key != "researcher" || crew.Agents[1].MaxIter != 5 {
+		t.Fatalf("agentes inesperados: %+v", crew.Agents)
+	}
+	researcher := crew.Agents[0]
+	if researcher.Role != "{topic} Senior Data Researcher" || researcher.LLM != "openai/gpt-4o" || !reflect.DeepEqual(researcher.Tools, []string{"serper_search"}) {
+		t.Fatalf("pesquisador inesperado: %+v", researcher)
+	}
+	if task := crew.Tasks[1]; task.Agent != "reporting_analyst" || !reflect.DeepEqual(task.Context, []string{"research_task"}) {
+		t.Fatalf("tarefa inesperada: %+v", task)
+	}
+
+	var issues []string
+	for _, issue := range crew.Issues {
+		issues = append(issues, issue.String())
+	}
+	want := []string{"agents.yaml: researcher.verbose: campo não suportado, ignorado", "tasks.yaml: reporting_task.output_file: campo não suportado, ignorado"}
+	if !reflect.DeepEqual(issues, want) {
+		t.Fatalf("campos ignorados inesperados: %v", issues)
+	}
+
+	if got := crew.Placeholders(); !reflect.DeepEqual(got, []string{"topic", "year"}) {
+		t.Fatalf("placeholders inesperados: %v", got)
+	}
+}
+
+func TestInterpolate(t *testing.T) {
+	crew, err := Parse([]byte(agentsYAML), []byte(tasksYAML))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := crew.Interpolate(map[string]string{"topic": "IA"}); !errors.Is(err, errs.ErrValidation) || !strings.Contains(err.Error(), "year") {
+		t.Fatalf("esperava o erro da entrada ausente: %v", err)
+	}
+
+	filled, err := crew.Interpolate(map[string]string{"topic": "IA", "year": "2025"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if filled.Agents[0].Role != "IA Senior Data Researcher" || filled.Tasks[0].Description != "Conduct a thorough research about IA in 2025" {
+		t.Fatalf("interpolação inesperada: %+v", filled)
+	}
+	if crew.Agents[0].Role != "{topic} Senior Data Researcher" {
+		t.Fatal("o original não deveria ser alterado")
+	}
+}
+
+func TestParseRejectsUnknownReferences(t *testing.T) {
+	tasks := strings.Replace(tasksYAML, "agent: reporting_analyst", "agent: writer", 1)
+	if _, err := Parse([]byte(agentsYAML), []byte(tasks)); !errors.Is(err, errs.ErrValidation) || !strings.Contains(err.Error(), "writer") {
+		t.Fatalf("esperava o erro do agente inexistente: %v", err)
+	}
+	tasks = strings.Replace(tasksYAML, "context: [research_task]", "context: [analysis_task]", 1)
+	if _, err := Parse([]byte(agentsYAML), []byte(tasks)); !errors.Is(err, errs.ErrValidation) {
+		t.Fatalf("esperava o erro do contexto inexistente: %v", err)
+	}
+}
@@ -0,0 +1,381 @@
+// Package cron executa jobs periódicos, como a análise semanal de tendências de uma equipe,
+// em expressões cron. Um job nunca roda em paralelo consigo mesmo: se a execução anterior
+// ainda não terminou, a nova é descartada (OverlapSkip) ou enfileirada para rodar logo em
+// seguida (OverlapQueue).
+package cron
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/suissa/HiveMind/agents/metrics"
+)
+
+// Etapas informadas aos observadores
+const (
+	StageStart    = "start"    // A execução começou
+	StageComplete = "complete" // A execução terminou sem erro
+	StageFailed   = "failed"   // A execução retornou erro
+	StageSkipped  = "skipped"  // A execução foi descartada porque a anterior ainda rodava
+	StageQueued   = "queued"   // A execução foi enfileirada atrás da anterior
+)
+
+var runsTotal = metrics.Default.Counter("hivemind_cron_runs_total",
+	"Execuções dos jobs periódicos por job e resultado (complete, failed ou skipped)", "job", "result")
+
+// Overlap define o que fazer quando o horário chega com a execução anterior em andamento
+type Overlap string
+
+const (
+	OverlapSkip  Overlap = "skip"  // Descarta a nova execução (padrão)
+	OverlapQueue Overlap = "queue" // Executa assim que a anterior terminar, até MaxQueued pendentes
+)
+
+// Config configura o agendador
+type Config struct {
+	// Location é o fuso das expressões (padrão: o fuso local)
+	Location *time.Location `json:"-" yaml:"-"`
+}
+
+// Job é uma execução periódica
+type Job struct {
+	Name      string                          `json:"name" yaml:"name"`
+	Schedule  string                          `json:"schedule" yaml:"schedule"` // Expressão cron, ex.: "0 9 * * mon"
+	Overlap   Overlap                         `json:"overlap,omitempty" yaml:"overlap,omitempty"`
+	MaxQueued int                             `json:"max_queued,omitempty" yaml:"max_queued,omitempty"` // Com OverlapQueue (padrão 1); as excedentes são descartadas
+	Timeout   time.Duration                   `json:"timeout,omitempty" yaml:"timeout,omitempty"`       // Prazo de cada execução (zero: sem prazo)
+	Run       func(ctx context.Context) error `json:"-" yaml:"-"`
+}
+
+// Entry é a situação de um job agendado
+type Entry struct {
+	Name      string    `json:"name"`
+	Schedule  string    `json:"schedule"`
+	Overlap   Overlap   `json:"overlap"`
+	Next      time.Time `json:"next"`
+	Running   bool      `json:"running"`
+	Queued    int       `json:"queued"`
+	Runs      int       `json:"runs"`
+	Skipped   int       `json:"skipped"`
+	LastStart time.Time `json:"last_start,omitempty"`
+	LastEnd   time.Time `json:"last_end,omitempty"`
+	LastError string    `json:"last_error,omitempty"`
+}
+
+// Run descreve uma etapa de uma execução
+type Run struct {
+	Job      string
+	Stage    string        // StageStart, StageComplete, StageFailed, StageSkipped ou StageQueued
+	Duration time.Duration // Duração da execução, nas etapas finais
+	Err      error
+}
+
+// Observer recebe as etapas das execuções
+type Observer func(ctx context.Context, run Run)
+
+// entry é um job agendado e o estado das suas execuções
+type entry struct {
+	job      Job
+	schedule Schedule
+	status   Entry
+	removed  bool
+}
+
+// Scheduler executa os jobs nos horários das expressões
+type Scheduler struct {
+	config   Config
+	entries  map[string]*entry
+	observer Observer
+	wake     chan struct{}
+	stop     chan struct{}
+	stopOnce sync.Once
+	running  sync.WaitGroup
+	now      func() time.Time
+	mu       sync.Mutex
+}
+
+// New cria o agendador
+func New(config Config) *Scheduler {
+	if config.Location == nil {
+		config.Location = time.Local
+	}
+	return &Scheduler{
+		config:  config,
+		entries: make(map[string]*entry),
+		wake:    make(chan struct{}, 1),
+		stop:    make(chan struct{}),
+		now:     time.Now,
+	}
+}
+
+// OnRun define quem recebe as etapas das execuções
+func (s *Scheduler) OnRun(observer Observer) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.observer = observer
+}
+
+// Add agenda o job; a primeira execução é no próximo horário da expressão
+func (s *Scheduler) Add(job Job) error {
+	if job.Name == "" {
+		return fmt.Errorf("job periódico sem nome")
+	}
+	if job.Run == nil {
+		return fmt.Errorf("job periódico %s sem função de execução", job.Name)
+	}
+	schedule, err := Parse(job.Schedule)
+	if err != nil {
+		return err
+	}
+	switch job.Overlap {
+	case "":
+		job.Overlap = OverlapSkip
+	case OverlapSkip, OverlapQueue:
+	default:
+		return fmt.Errorf("política de sobreposição inválida no job %s: %s", job.Name, job.Overlap)
+	}
+	if job.MaxQueued <= 0 {
+		job.MaxQueued = 1
+	}
+
+	s.mu.Lock()
+	if _, exists := s.entries[job.Name]; exists {
+		s.mu.Unlock()
+		return fmt.Errorf("job periódico %s já agendado", job.Name)
+	}
+	next := schedule.Next(s.now().In(s.config.Location))
+	if next.IsZero() {
+		s.mu.Unlock()
+		return fmt.Errorf("a expressão %q do job %s nunca ocorre", job.Schedule, job.Name)
+	}
+	s.entries[job.Name] = &entry{
+		job:      job,
+		schedule: schedule,
+		status:   Entry{Name: job.Name, Schedule: job.Schedule, Overlap: job.Overlap, Next: next},
+	}
+	s.mu.Unlock()
+	s.notify()
+	return nil
+}
+
+// Remove desagenda o job; uma execução em andamento termina, mas as enfileiradas são descartadas
+func (s *Scheduler) Remove(name string) {
+	s.mu.Lock()
+	if e, ok := s.entries[name]; ok {
+		e.removed = true
+		e.status.Queued = 0
+		delete(s.entries, name)
+	}
+	s.mu.Unlock()
+	s.notify()
+}
+
+// Entries retorna a situação dos jobs agendados, por nome
+func (s *Scheduler) Entries() []Entry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entries := make([]Entry, 0, len(s.entries))
+	for _, e := range s.entries {
+		entries = append(entries, e.status)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name < entries[j].Name })
+	return entries
+}
+
+// Trigger dispara o job fora do horário, com a mesma política de sobreposição
+func (s *Scheduler) Trigger(ctx context.Context, name string) error {
+	s.mu.Lock()
+	e, ok := s.entries[name]
+	s.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("job periódico %s não encontrado", name)
+	}
+	s.fire(ctx, e)
+	return nil
+}
+
+// notify acorda o laço de Run para recalcular o próximo horário
+func (s *Scheduler) notify() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Run dispara os jobs nos horários até o contexto ser cancelado ou Stop ser chamado. As
+// execuções usam ctx, então cancelá-lo também as interrompe.
+func (s *Scheduler) Run(ctx context.Context) {
+	for {
+		timer := time.NewTimer(s.wait())
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-s.stop:
+			timer.Stop()
+			return
+		case <-s.wake:
+			timer.Stop()
+			continue
+		case <-timer.C:
+		}
+		for _, e := range s.due() {
+			s.fire(ctx, e)
+		}
+	}
+}
+
+// Stop para de disparar os jobs e descarta as execuções enfileiradas, sem interromper as que
+// estão em andamento
+func (s *Scheduler) Stop() {
+	s.stopOnce.Do(func() { close(s.stop) })
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, e := range s.entries {
+		e.status.Queued = 0
+	}
+}
+
+// Wait aguarda as execuções em andamento até o contexto ser cancelado
+func (s *Scheduler) Wait(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		s.running.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// wait retorna quanto falta para o próximo horário
+func (s *Scheduler) wait() time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.entries) == 0 {
+		return time.Hour
+	}
+	var earliest time.Time
+	for _, e := range s.entries {
+		if earliest.IsZero() || e.status.Next.Before(earliest) {
+			earliest = e.status.Next
+		}
+	}
+	return max(earliest.Sub(s.now()), 0)
+}
+
+// due retorna os jobs cujo horário chegou e agenda o horário seguinte de cada um; os
+// horários perdidos enquanto o processo estava parado não são recuperados
+func (s *Scheduler) due() []*entry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now().In(s.config.Location)
+	var due []*entry
+	for _, e := range s.entries {
+		if e.status.Next.After(now) {
+			continue
+		}
+		due = append(due, e)
+		e.status.Next = e.schedule.Next(now)
+		if e.status.Next.IsZero() {
+			delete(s.entries, e.job.Name)
+		}
+	}
+	sort.Slice(due, func(i, j int) bool { return due[i].job.Name < due[j].job.Name })
+	return due
+}
+
+// fire inicia a execução do job ou aplica a política de sobreposição
+func (s *Scheduler) fire(ctx context.Context, e *entry) {
+	s.mu.Lock()
+	if !e.status.Running {
+		e.status.Running = true
+		s.running.Add(1)
+		s.mu.Unlock()
+		s.started(ctx, e)
+		go s.execute(ctx, e)
+		return
+	}
+	stage := StageSkipped
+	if e.job.Overlap == OverlapQueue && e.status.Queued < e.job.MaxQueued {
+		e.status.Queued++
+		stage = StageQueued
+	} else {
+		e.status.Skipped++
+		runsTotal.Inc(e.job.Name, StageSkipped)
+	}
+	observer := s.observer
+	s.mu.Unlock()
+	if observer != nil {
+		observer(ctx, Run{Job: e.job.Name, Stage: stage})
+	}
+}
+
+// execute roda o job e, em seguida, as execuções enfileiradas durante ele
+func (s *Scheduler) execute(ctx context.Context, e *entry) {
+	defer s.running.Done()
+	for {
+		s.runOnce(ctx, e)
+
+		s.mu.Lock()
+		if e.removed || e.status.Queued == 0 || ctx.Err() != nil {
+			e.status.Running = false
+			e.status.Queued = 0
+			s.mu.Unlock()
+			return
+		}
+		e.status.Queued--
+		s.mu.Unlock()
+		s.started(ctx, e)
+	}
+}
+
+// started registra o início de uma execução; é chamado antes de a execução rodar, para que
+// os observadores recebam as etapas em ordem
+func (s *Scheduler) started(ctx context.Context, e *entry) {
+	s.mu.Lock()
+	e.status.LastStart = s.now()
+	observer := s.observer
+	s.mu.Unlock()
+	if observer != nil {
+		observer(ctx, Run{Job: e.job.Name, Stage: StageStart})
+	}
+}
+
+// runOnce executa o job uma vez, com o prazo do job
+func (s *Scheduler) runOnce(ctx context.Context, e *entry) {
+	s.mu.Lock()
+	start := e.status.LastStart
+	s.mu.Unlock()
+
+	runCtx, cancel := ctx, context.CancelFunc(func() {})
+	if e.job.Timeout > 0 {
+		runCtx, cancel = context.WithTimeout(ctx, e.job.Timeout)
+	}
+	err := e.job.Run(runCtx)
+	cancel()
+
+	run := Run{Job: e.job.Name, Stage: StageComplete, Duration: s.now().Sub(start), Err: err}
+	if err != nil {
+		run.Stage = StageFailed
+	}
+	s.mu.Lock()
+	e.status.Runs++
+	e.status.LastEnd = s.now()
+	e.status.LastError = ""
+	if err != nil {
+		e.status.LastError = err.Error()
+	}
+	observer := s.observer
+	s.mu.Unlock()
+	runsTotal.Inc(e.job.Name, run.Stage)
+	if observer != nil {
+		observer(ctx, run)
+	}
+}
@@ -0,0 +1,142 @@
+package cron
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestParseNext(t *testing.T) {
+	base := time.Date(2024, time.March, 14, 10, 30, 0, 0, time.UTC) // quinta-feira
+	for spec, want := range map[string]time.Time{
+		"*/15 * * * *":   time.Date(2024, time.March, 14, 10, 45, 0, 0, time.UTC),
+		"0 9 * * mon":    time.Date(2024, time.March, 18, 9, 0, 0, 0, time.UTC),
+		"0 9 * * 7":      time.Date(2024, time.March, 17, 9, 0, 0, 0, time.UTC),
+		"30 8 1 * *":     time.Date(2024, time.April, 1, 8, 30, 0, 0, time.UTC),
+		"0 0 29 feb *":   time.Date(2028, time.February, 29, 0, 0, 0, 0, time.UTC),
+		"0 12 1 * fri":   time.Date(2024, time.March, 15, 12, 0, 0, 0, time.UTC),
+		"0 8-18/4 * * *": time.Date(2024, time.March, 14, 12, 0, 0, 0, time.UTC),
+		"@weekly":        time.Date(2024, time.March, 17, 0, 0, 0, 0, time.UTC),
+		"@every 90m":     time.Date(2024, time.March, 14, 12, 0, 0, 0, time.UTC),
+	} {
+		schedule, err := Parse(spec)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", spec, err)
+		}
+		if got := schedule.Next(base); !got.Equal(want) {
+			t.Errorf("Next(%q) = %v, esperava %v", spec, got, want)
+		}
+	}
+
+	for _, spec := range []string{"* * * *", "60 * * * *", "* * * * mon-", "*/0 * * * *", "@every 1ms"} {
+		if _, err := Parse(spec); err == nil {
+			t.Errorf("Parse(%q) deveria falhar", spec)
+		}
+	}
+	if schedule, _ := Parse("0 0 30 feb *"); !schedule.Next(base).IsZero() {
+		t.Error("30 de fevereiro nunca deveria ocorrer")
+	}
+}
+
+// blockingJob roda até ser liberado
+type blockingJob struct {
+	release chan struct{}
+	runs    int
+	mu      sync.Mutex
+}
+
+func (j *blockingJob) run(ctx context.Context) error {
+	j.mu.Lock()
+	j.runs++
+	j.mu.Unlock()
+	<-j.release
+	return nil
+}
+
+func TestOverlapPolicies(t *testing.T) {
+	for _, tc := range []struct {
+		overlap       Overlap
+		runs, skipped int
+		stages        []string
+	}{
+		{OverlapSkip, 1, 2, []string{StageStart, StageSkipped, StageSkipped, StageComplete}},
+		{OverlapQueue, 2, 1, []string{StageStart, StageQueued, StageSkipped, StageComplete, StageStart, StageComplete}},
+	} {
+		t.Run(string(tc.overlap), func(t *testing.T) {
+			job := &blockingJob{release: make(chan struct{})}
+			s := New(Config{})
+			var mu sync.Mutex
+			var stages []string
+			s.OnRun(func(_ context.Context, run Run) {
+				mu.Lock()
+				defer mu.Unlock()
+				stages = append(stages, run.Stage)
+			})
+			if err := s.Add(Job{Name: "tendencias", Schedule: "@weekly", Overlap: tc.overlap, Run: job.run}); err != nil {
+				t.Fatal(err)
+			}
+			ctx := context.Background()
+			for i := 0; i < 3; i++ {
+				s.Trigger(ctx, "tendencias")
+			}
+			close(job.release)
+			if err := s.Wait(ctx); err != nil {
+				t.Fatal(err)
+			}
+
+			entry := s.Entries()[0]
+			if job.runs != tc.runs || entry.Runs != tc.runs || entry.Skipped != tc.skipped || entry.Running {
+				t.Fatalf("execuções inesperadas: job %d, %+v", job.runs, entry)
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			if len(stages) != len(tc.stages) {
+				t.Fatalf("etapas %v, esperava %v", stages, tc.stages)
+			}
+			for i := range stages {
+				if stages[i] != tc.stages[i] {
+					t.Fatalf("etapas %v, esperava %v", stages, tc.stages)
+				}
+			}
+		})
+	}
+}
+
+func TestRunFiresDueJobs(t *testing.T) {
+	now := time.Date(2024, time.March, 14, 10, 30, 0, 0, time.UTC)
+	s := New(Config{Location: time.UTC})
+	var mu sync.Mutex
+	s.now = func() time.Time {
+		mu.Lock()
+		defer mu.Unlock()
+		return now
+	}
+	done := make(chan struct{}, 1)
+	if err := s.Add(Job{Name: "relatorio", Schedule: "31 10 * * *", Run: func(context.Context) error {
+		done <- struct{}{}
+		return nil
+	}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Add(Job{Name: "relatorio", Schedule: "@daily", Run: func(context.Context) error { return nil }}); err == nil {
+		t.Fatal("nome repetido deveria ser rejeitado")
+	}
+
+	mu.Lock()
+	now = now.Add(time.Minute)
+	mu.Unlock()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go s.Run(ctx)
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("o job vencido deveria ter rodado")
+	}
+	s.Stop()
+	s.Wait(ctx)
+	if next := s.Entries()[0].Next; !next.Equal(time.Date(2024, time.March, 15, 10, 31, 0, 0, time.UTC)) {
+		t.Fatalf("próximo horário inesperado: %v", next)
+	}
+}
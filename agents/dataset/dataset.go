@@ -0,0 +1,196 @@
+// Package dataset gerencia os conjuntos de exemplos rotulados usados no treinamento e na
+// avaliação dos agentes: carga de arquivos JSONL ou CSV, divisão em treino e validação e
+// versionamento pelo conteúdo.
+package dataset
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/suissa/HiveMind/agents/errs"
+)
+
+// Example é uma entrada rotulada com a resposta esperada
+type Example struct {
+	ID       string `json:"id,omitempty"`
+	Input    string `json:"input"`
+	Expected string `json:"expected"`
+	Format   string `json:"format,omitempty"` // Formato esperado da resposta (opcional)
+}
+
+// Dataset é um conjunto versionado de exemplos
+type Dataset struct {
+	Name     string
+	Version  string // Hash do conteúdo; muda sempre que um exemplo muda
+	Examples []Example
+}
+
+// New cria um dataset com os exemplos informados, atribuindo IDs e a versão
+func New(name string, examples []Example) *Dataset {
+	d := &Dataset{Name: name, Examples: examples}
+	for i := range d.Examples {
+		if d.Examples[i].ID == "" {
+			d.Examples[i].ID = fmt.Sprintf("%s-%d", name, i+1)
+		}
+	}
+	d.Version = d.hash()
+	return d
+}
+
+// Load carrega um dataset de um arquivo .jsonl ou .csv; o nome é o do arquivo sem extensão
+func Load(path string) (*Dataset, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao abrir dataset: %v", err)
+	}
+	defer file.Close()
+
+	name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".jsonl":
+		return ReadJSONL(name, file)
+	case ".csv":
+		return ReadCSV(name, file)
+	default:
+		return nil, errs.New(errs.ErrValidation, "dataset.Load", "formato de dataset não suportado: %s", path)
+	}
+}
+
+// ReadJSONL lê um exemplo por linha (campos id, input, expected e format)
+func ReadJSONL(name string, r io.Reader) (*Dataset, error) {
+	var examples []Example
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+
+		var example Example
+		if err := json.Unmarshal([]byte(text), &example); err != nil {
+			return nil, errs.Wrap(errs.ErrValidation, "dataset.ReadJSONL", err, "linha %d inválida", line)
+		}
+		if example.Input == "" {
+			return nil, errs.New(errs.ErrValidation, "dataset.ReadJSONL", "linha %d sem input", line)
+		}
+		examples = append(examples, example)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao ler dataset: %v", err)
+	}
+	return New(name, examples), nil
+}
+
+// ReadCSV lê um CSV com cabeçalho; as colunas reconhecidas são id, input, expected e format
+func ReadCSV(name string, r io.Reader) (*Dataset, error) {
+	records, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return nil, errs.Wrap(errs.ErrValidation, "dataset.ReadCSV", err, "CSV inválido")
+	}
+	if len(records) == 0 {
+		return New(name, nil), nil
+	}
+
+	columns := make(map[string]int)
+	for i, column := range records[0] {
+		columns[strings.ToLower(strings.TrimSpace(column))] = i
+	}
+	if _, ok := columns["input"]; !ok {
+		return nil, errs.New(errs.ErrValidation, "dataset.ReadCSV", "CSV sem a coluna input")
+	}
+	field := func(record []string, column string) string {
+		if i, ok := columns[column]; ok && i < len(record) {
+			return record[i]
+		}
+		return ""
+	}
+
+	examples := make([]Example, 0, len(records)-1)
+	for _, record := range records[1:] {
+		examples = append(examples, Example{
+			ID:       field(record, "id"),
+			Input:    field(record, "input"),
+			Expected: field(record, "expected"),
+			Format:   field(record, "format"),
+		})
+	}
+	return New(name, examples), nil
+}
+
+// Save grava o dataset em JSONL
+func (d *Dataset) Save(path string) error {
+	var b strings.Builder
+	for _, example := range d.Examples {
+		line, err := json.Marshal(example)
+		if err != nil {
+			return fmt.Errorf("erro ao serializar exemplo %s: %v", example.ID, err)
+		}
+		b.Write(line)
+		b.WriteByte('\n')
+	}
+	if err := os.WriteFile(path, []byte(b.String()), 0644); err != nil {
+		return fmt.Errorf("erro ao gravar dataset: %v", err)
+	}
+	return nil
+}
+
+// Split divide o dataset em treino e validação. A proporção de validação segue
+// TrainingConfig.ValidationRatio; a ordem é embaralhada com a semente, de forma que a
+// mesma versão e a mesma semente resultam sempre na mesma divisão.
+func (d *Dataset) Split(ratio float64, seed int64) (train, validation *Dataset) {
+	examples := append([]Example(nil), d.Examples...)
+	rand.New(rand.NewSource(seed)).Shuffle(len(examples), func(i, j int) {
+		examples[i], examples[j] = examples[j], examples[i]
+	})
+
+	n := int(float64(len(examples)) * ratio)
+	if ratio <= 0 || n == 0 || n >= len(examples) {
+		return d.subset("train", examples), d.subset("validation", nil)
+	}
+	return d.subset("train", examples[n:]), d.subset("validation", examples[:n])
+}
+
+// subset cria um dataset derivado, identificado pelo nome e pela versão de origem
+func (d *Dataset) subset(part string, examples []Example) *Dataset {
+	return &Dataset{Name: d.Name + "/" + part, Version: d.Version, Examples: examples}
+}
+
+// Merge cria um novo dataset com os exemplos deste seguidos dos demais
+func (d *Dataset) Merge(others ...*Dataset) *Dataset {
+	examples := append([]Example(nil), d.Examples...)
+	for _, other := range others {
+		if other != nil {
+			examples = append(examples, other.Examples...)
+		}
+	}
+	return New(d.Name, examples)
+}
+
+// String identifica o dataset pelo nome e versão (ex.: "suporte@3f2a9c1b7d4e")
+func (d *Dataset) String() string {
+	return d.Name + "@" + d.Version
+}
+
+// hash calcula a versão a partir do conteúdo dos exemplos
+func (d *Dataset) hash() string {
+	h := sha256.New()
+	for _, example := range d.Examples {
+		for _, part := range []string{example.ID, example.Input, example.Expected, example.Format} {
+			h.Write([]byte(part))
+			h.Write([]byte{0})
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil))[:12]
+}
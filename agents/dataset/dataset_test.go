@@ -0,0 +1,77 @@
+package dataset
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/suissa/HiveMind/agents/errs"
+)
+
+func TestReadFormatsAndVersion(t *testing.T) {
+	jsonl, err := ReadJSONL("qa", strings.NewReader(`{"id":"1","input":"2+2","expected":"4"}
+
+{"input":"capital da França","expected":"Paris"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	csvData, err := ReadCSV("qa", strings.NewReader("id,input,expected\n1,2+2,4\n,capital da França,Paris\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(jsonl.Examples) != 2 || jsonl.Examples[1].ID != "qa-2" {
+		t.Fatalf("exemplos inesperados: %#v", jsonl.Examples)
+	}
+	if jsonl.Version != csvData.Version {
+		t.Fatalf("o mesmo conteúdo deve ter a mesma versão: %s != %s", jsonl.Version, csvData.Version)
+	}
+
+	changed := New("qa", []Example{{ID: "1", Input: "2+2", Expected: "5"}})
+	if changed.Version == jsonl.Version {
+		t.Fatal("conteúdo diferente deve mudar a versão")
+	}
+
+	if _, err := ReadJSONL("qa", strings.NewReader(`{"expected":"x"}`)); !errors.Is(err, errs.ErrValidation) {
+		t.Fatalf("exemplo sem input deve ser rejeitado: %v", err)
+	}
+}
+
+func TestSplitIsDeterministic(t *testing.T) {
+	var examples []Example
+	for i := 0; i < 10; i++ {
+		examples = append(examples, Example{Input: strings.Repeat("x", i+1), Expected: "y"})
+	}
+	d := New("d", examples)
+
+	train, validation := d.Split(0.2, 42)
+	if len(train.Examples) != 8 || len(validation.Examples) != 2 {
+		t.Fatalf("divisão inesperada: %d/%d", len(train.Examples), len(validation.Examples))
+	}
+	_, again := d.Split(0.2, 42)
+	for i := range validation.Examples {
+		if validation.Examples[i].ID != again.Examples[i].ID {
+			t.Fatal("a mesma semente deve gerar a mesma divisão")
+		}
+	}
+
+	if train, validation := d.Split(0, 42); len(train.Examples) != 10 || len(validation.Examples) != 0 {
+		t.Fatal("sem proporção de validação todos os exemplos são de treino")
+	}
+}
+
+func TestSaveAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "qa.jsonl")
+	d := New("qa", []Example{{Input: "pergunta", Expected: "resposta", Format: "texto"}})
+	if err := d.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.String() != d.String() {
+		t.Fatalf("dataset recarregado difere: %s != %s", loaded, d)
+	}
+}
@@ -0,0 +1,104 @@
+// Package deadline interpreta os prazos das tarefas — um instante RFC 3339 ou uma duração
+// relativa ao início ("10m"), vindos do tasks.yaml ou dos parâmetros das subtarefas — e
+// distingue o prazo da tarefa expirado do cancelamento do chamador.
+package deadline
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/suissa/HiveMind/agents/errs"
+)
+
+// Parâmetros das subtarefas com o prazo
+const (
+	ParamDeadline = "deadline" // Instante RFC 3339 ou duração
+	ParamTimeout  = "timeout"  // Duração ("90s") ou segundos
+)
+
+// Parse interpreta o prazo como instante RFC 3339 ou como duração a partir de now. Um
+// prazo vazio retorna o instante zero (sem prazo).
+func Parse(value string, now time.Time) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if at, err := time.Parse(time.RFC3339, value); err == nil {
+		return at, nil
+	}
+	if timeout, err := time.ParseDuration(value); err == nil && timeout > 0 {
+		return now.Add(timeout), nil
+	}
+	return time.Time{}, errs.New(errs.ErrValidation, "deadline.Parse", "prazo inválido %q: use um instante RFC 3339 ou uma duração como 10m", value)
+}
+
+// FromParameters lê o prazo dos parâmetros de uma subtarefa (deadline ou timeout), o que
+// vencer primeiro. Sem prazo retorna o instante zero.
+func FromParameters(params map[string]interface{}, now time.Time) (time.Time, error) {
+	var at time.Time
+	switch value := params[ParamDeadline].(type) {
+	case nil:
+	case string:
+		parsed, err := Parse(value, now)
+		if err != nil {
+			return time.Time{}, err
+		}
+		at = parsed
+	case time.Time:
+		at = value
+	default:
+		return time.Time{}, errs.New(errs.ErrValidation, "deadline.FromParameters", "prazo inválido: %v", value)
+	}
+
+	var timeout time.Duration
+	switch value := params[ParamTimeout].(type) {
+	case nil:
+	case string:
+		parsed, err := time.ParseDuration(value)
+		if err != nil || parsed <= 0 {
+			return time.Time{}, errs.New(errs.ErrValidation, "deadline.FromParameters", "timeout inválido %q", value)
+		}
+		timeout = parsed
+	case float64:
+		timeout = time.Duration(value * float64(time.Second))
+	case int:
+		timeout = time.Duration(value) * time.Second
+	default:
+		return time.Time{}, errs.New(errs.ErrValidation, "deadline.FromParameters", "timeout inválido: %v", value)
+	}
+	if timeout > 0 {
+		return Earliest(at, now.Add(timeout)), nil
+	}
+	return at, nil
+}
+
+// Earliest retorna o primeiro prazo a vencer, ignorando os zerados
+func Earliest(deadlines ...time.Time) time.Time {
+	var earliest time.Time
+	for _, at := range deadlines {
+		if !at.IsZero() && (earliest.IsZero() || at.Before(earliest)) {
+			earliest = at
+		}
+	}
+	return earliest
+}
+
+// Context deriva do contexto pai um contexto que expira no prazo. Com prazo zero o contexto
+// só é cancelado junto com o pai.
+func Context(parent context.Context, at time.Time) (context.Context, context.CancelFunc) {
+	if at.IsZero() {
+		return context.WithCancel(parent)
+	}
+	return context.WithDeadline(parent, at)
+}
+
+// Expired indica que o contexto da tarefa expirou pelo próprio prazo, e não por
+// cancelamento ou prazo do contexto pai
+func Expired(parent, ctx context.Context) bool {
+	return parent.Err() == nil && errors.Is(ctx.Err(), context.DeadlineExceeded)
+}
+
+// Error classifica o erro de uma tarefa que excedeu o prazo como ErrTimeout
+func Error(op, taskID string, at time.Time, err error) error {
+	return errs.Wrap(errs.ErrTimeout, op, err, "tarefa %s excedeu o prazo %s", taskID, at.Format(time.RFC3339))
+}
@@ -0,0 +1,72 @@
+package deadline
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/suissa/HiveMind/agents/errs"
+)
+
+func TestParse(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	cases := []struct {
+		value string
+		want  time.Time
+	}{
+		{"", time.Time{}},
+		{"2025-01-01T13:00:00Z", now.Add(time.Hour)},
+		{"90s", now.Add(90 * time.Second)},
+	}
+	for _, c := range cases {
+		got, err := Parse(c.value, now)
+		if err != nil || !got.Equal(c.want) {
+			t.Errorf("Parse(%q) = %v, %v; esperava %v", c.value, got, err, c.want)
+		}
+	}
+	for _, value := range []string{"amanhã", "-5m"} {
+		if _, err := Parse(value, now); !errors.Is(err, errs.ErrValidation) {
+			t.Errorf("Parse(%q) deveria falhar com ErrValidation: %v", value, err)
+		}
+	}
+}
+
+func TestFromParametersUsesEarliest(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	got, err := FromParameters(map[string]interface{}{"deadline": "2025-01-01T13:00:00Z", "timeout": float64(60)}, now)
+	if err != nil || !got.Equal(now.Add(time.Minute)) {
+		t.Fatalf("esperava o timeout de 1 minuto: %v, %v", got, err)
+	}
+	got, err = FromParameters(map[string]interface{}{"deadline": "10m", "timeout": "1h"}, now)
+	if err != nil || !got.Equal(now.Add(10*time.Minute)) {
+		t.Fatalf("esperava o deadline de 10 minutos: %v, %v", got, err)
+	}
+	if got, err := FromParameters(map[string]interface{}{"priority": "high"}, now); err != nil || !got.IsZero() {
+		t.Fatalf("esperava nenhum prazo: %v, %v", got, err)
+	}
+	if _, err := FromParameters(map[string]interface{}{"timeout": true}, now); !errors.Is(err, errs.ErrValidation) {
+		t.Fatalf("esperava ErrValidation: %v", err)
+	}
+}
+
+func TestExpiredDistinguishesParentCancellation(t *testing.T) {
+	parent, cancelParent := context.WithCancel(context.Background())
+	ctx, cancel := Context(parent, time.Now().Add(10*time.Millisecond))
+	defer cancel()
+	<-ctx.Done()
+	if !Expired(parent, ctx) {
+		t.Fatal("o prazo da tarefa deveria ter expirado")
+	}
+
+	ctx, cancel = Context(parent, time.Time{})
+	defer cancel()
+	cancelParent()
+	<-ctx.Done()
+	if Expired(parent, ctx) {
+		t.Fatal("o cancelamento do pai não é prazo expirado")
+	}
+	if err := Error("op", "t1", time.Now(), context.DeadlineExceeded); !errors.Is(err, errs.ErrTimeout) {
+		t.Fatalf("esperava ErrTimeout: %v", err)
+	}
+}
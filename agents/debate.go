@@ -0,0 +1,82 @@
+package agents
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/suissa/HiveMind/agents/debate"
+)
+
+// Debate conduz um debate entre três agentes — proponente, crítico e juiz — e retorna a
+// transcrição. Qualquer crew pode usá-lo nas tarefas críticas antes de aceitar uma resposta.
+func Debate(ctx context.Context, question string, proposer, critic, judge *CognitiveAgent, config debate.Config) (*debate.Transcript, error) {
+	return debate.Run(ctx, question, DebateProposer(proposer), DebateCritic(critic), DebateJudge(judge), config)
+}
+
+// DebateProposer faz do agente o proponente: responde à pergunta e, nas rodadas seguintes,
+// revisa a proposta atendendo à última crítica
+func DebateProposer(agent *CognitiveAgent) debate.Proposer {
+	return debate.Proposer{
+		ID: agent.GetID(),
+		Propose: func(ctx context.Context, transcript *debate.Transcript) (string, error) {
+			if _, ok := transcript.Last(debate.RoleCritic); !ok {
+				return agent.Complete(ctx, fmt.Sprintf("Pergunta: %s\nResponda de forma completa e fundamentada.", transcript.Question))
+			}
+			return agent.Complete(ctx, transcript.String()+"\nRevise a sua última proposta atendendo às críticas e responda apenas com a nova versão.")
+		},
+	}
+}
+
+// DebateCritic faz do agente o crítico: aponta falhas da última proposta ou a aceita
+func DebateCritic(agent *CognitiveAgent) debate.Critic {
+	return debate.Critic{
+		ID: agent.GetID(),
+		Critique: func(ctx context.Context, transcript *debate.Transcript) (debate.Critique, error) {
+			output, err := agent.Complete(ctx, transcript.String()+
+				"\nAvalie criticamente a última proposta: aponte erros, omissões e riscos. "+
+				`Responda apenas com JSON no formato {"accept": false, "critique": "..."}, com accept true se não houver mais nada a corrigir.`)
+			if err != nil {
+				return debate.Critique{}, err
+			}
+			var critique struct {
+				Accept   bool   `json:"accept"`
+				Critique string `json:"critique"`
+			}
+			start, end := strings.Index(output, "{"), strings.LastIndex(output, "}")
+			if start < 0 || end < start || json.Unmarshal([]byte(output[start:end+1]), &critique) != nil {
+				// Sem JSON, o texto inteiro é a crítica
+				return debate.Critique{Text: strings.TrimSpace(output)}, nil
+			}
+			return debate.Critique{Text: critique.Critique, Accept: critique.Accept}, nil
+		},
+	}
+}
+
+// DebateJudge faz do agente o juiz: lê a transcrição e dá a resposta final
+func DebateJudge(agent *CognitiveAgent) debate.Judge {
+	return debate.Judge{
+		ID: agent.GetID(),
+		Decide: func(ctx context.Context, transcript *debate.Transcript) (debate.Verdict, error) {
+			output, err := agent.Complete(ctx, transcript.String()+
+				"\nVocê é o juiz do debate. Considerando as propostas e as críticas, dê a resposta final à pergunta. "+
+				`Responda apenas com JSON no formato {"answer": "...", "rationale": "...", "score": 0.8}, com score de 0 a 1 para a qualidade da resposta.`)
+			if err != nil {
+				return debate.Verdict{}, err
+			}
+			var verdict debate.Verdict
+			start, end := strings.Index(output, "{"), strings.LastIndex(output, "}")
+			if start < 0 || end < start {
+				return verdict, fmt.Errorf("veredito do agente %s sem JSON: %q", agent.GetID(), output)
+			}
+			if err := json.Unmarshal([]byte(output[start:end+1]), &verdict); err != nil {
+				return verdict, fmt.Errorf("veredito do agente %s inválido: %v", agent.GetID(), err)
+			}
+			if verdict.Answer == "" {
+				return verdict, fmt.Errorf("veredito do agente %s sem resposta", agent.GetID())
+			}
+			return verdict, nil
+		},
+	}
+}
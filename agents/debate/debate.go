@@ -0,0 +1,194 @@
+// Package debate implementa um debate estruturado entre agentes: um proponente responde à
+// pergunta, um crítico aponta falhas na proposta e o proponente a revisa, por até K rodadas;
+// ao final um juiz decide a resposta. A transcrição completa pode ser gravada como artefato
+// para auditoria das tarefas críticas.
+package debate
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/suissa/HiveMind/agents/errs"
+)
+
+// DefaultRounds é o número padrão de rodadas de proposta e crítica
+const DefaultRounds = 2
+
+// Role é o papel de um participante do debate
+type Role string
+
+const (
+	RoleProposer Role = "proposer"
+	RoleCritic   Role = "critic"
+	RoleJudge    Role = "judge"
+)
+
+// Turn é uma intervenção na transcrição
+type Turn struct {
+	Round    int           `json:"round"` // Rodada da intervenção; 0 para o veredito do juiz
+	Role     Role          `json:"role"`
+	AgentID  string        `json:"agent_id"`
+	Text     string        `json:"text"`
+	Accepted bool          `json:"accepted,omitempty"` // O crítico aceitou a proposta
+	Duration time.Duration `json:"duration"`
+}
+
+// Critique é a resposta do crítico; com Accept a proposta é aceita e o debate segue para o juiz
+type Critique struct {
+	Text   string
+	Accept bool
+}
+
+// Verdict é a decisão do juiz
+type Verdict struct {
+	Answer    string  `json:"answer"`
+	Rationale string  `json:"rationale,omitempty"`
+	Score     float64 `json:"score,omitempty"` // Qualidade da resposta final, de 0 a 1
+}
+
+// Proposer propõe uma resposta e a revisa a partir das críticas já presentes na transcrição
+type Proposer struct {
+	ID      string
+	Propose func(ctx context.Context, transcript *Transcript) (string, error)
+}
+
+// Critic critica a última proposta da transcrição
+type Critic struct {
+	ID       string
+	Critique func(ctx context.Context, transcript *Transcript) (Critique, error)
+}
+
+// Judge decide a resposta final a partir da transcrição
+type Judge struct {
+	ID     string
+	Decide func(ctx context.Context, transcript *Transcript) (Verdict, error)
+}
+
+// Transcript é o registro de um debate
+type Transcript struct {
+	ID        string    `json:"id"`
+	Question  string    `json:"question"`
+	Turns     []Turn    `json:"turns"`
+	Rounds    int       `json:"rounds"` // Rodadas efetivamente executadas
+	Verdict   Verdict   `json:"verdict"`
+	Artifact  string    `json:"artifact,omitempty"` // Localização da transcrição no Store
+	StartedAt time.Time `json:"started_at"`
+	EndedAt   time.Time `json:"ended_at"`
+}
+
+// Last retorna a última intervenção do papel
+func (t *Transcript) Last(role Role) (Turn, bool) {
+	for i := len(t.Turns) - 1; i >= 0; i-- {
+		if t.Turns[i].Role == role {
+			return t.Turns[i], true
+		}
+	}
+	return Turn{}, false
+}
+
+// String formata as intervenções para os prompts dos participantes
+func (t *Transcript) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Pergunta: %s\n", t.Question)
+	for _, turn := range t.Turns {
+		fmt.Fprintf(&b, "\n[rodada %d] %s (%s):\n%s\n", turn.Round, turn.Role, turn.AgentID, turn.Text)
+	}
+	return b.String()
+}
+
+// Store grava as transcrições como artefatos e retorna a sua localização
+type Store interface {
+	Save(ctx context.Context, transcript *Transcript) (string, error)
+}
+
+// DirStore grava cada transcrição como um arquivo JSON no diretório
+type DirStore string
+
+// Save implementa Store
+func (d DirStore) Save(ctx context.Context, transcript *Transcript) (string, error) {
+	if err := os.MkdirAll(string(d), 0o755); err != nil {
+		return "", fmt.Errorf("erro ao criar o diretório de artefatos: %w", err)
+	}
+	data, err := json.MarshalIndent(transcript, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("erro ao serializar a transcrição: %w", err)
+	}
+	path := filepath.Join(string(d), "debate-"+transcript.ID+".json")
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return "", fmt.Errorf("erro ao gravar a transcrição: %w", err)
+	}
+	return path, nil
+}
+
+// Config configura o debate
+type Config struct {
+	Rounds int   // Máximo de rodadas de proposta e crítica (DefaultRounds se zero)
+	Store  Store // Opcional; grava a transcrição ao final, inclusive dos debates com falha
+}
+
+// Run conduz o debate: a cada rodada o proponente propõe (ou revisa) a resposta e o crítico a
+// avalia; o debate termina quando o crítico aceita a proposta ou as rodadas se esgotam, e o
+// juiz dá o veredito. A transcrição parcial é retornada junto com o erro de um participante.
+func Run(ctx context.Context, question string, proposer Proposer, critic Critic, judge Judge, config Config) (*Transcript, error) {
+	if proposer.Propose == nil || critic.Critique == nil || judge.Decide == nil {
+		return nil, errs.New(errs.ErrValidation, "debate.Run", "debate sem proponente, crítico ou juiz")
+	}
+	if config.Rounds <= 0 {
+		config.Rounds = DefaultRounds
+	}
+
+	transcript := &Transcript{ID: uuid.NewString(), Question: question, StartedAt: time.Now()}
+	err := run(ctx, transcript, proposer, critic, judge, config.Rounds)
+	transcript.EndedAt = time.Now()
+
+	if config.Store != nil {
+		artifact, saveErr := config.Store.Save(ctx, transcript)
+		if saveErr != nil && err == nil {
+			err = saveErr
+		}
+		transcript.Artifact = artifact
+	}
+	return transcript, err
+}
+
+func run(ctx context.Context, transcript *Transcript, proposer Proposer, critic Critic, judge Judge, rounds int) error {
+	for round := 1; round <= rounds; round++ {
+		if err := ctx.Err(); err != nil {
+			return errs.FromContext("debate.Run", err)
+		}
+		transcript.Rounds = round
+
+		started := time.Now()
+		proposal, err := proposer.Propose(ctx, transcript)
+		if err != nil {
+			return fmt.Errorf("erro do proponente %s na rodada %d: %w", proposer.ID, round, err)
+		}
+		transcript.Turns = append(transcript.Turns, Turn{Round: round, Role: RoleProposer, AgentID: proposer.ID, Text: proposal, Duration: time.Since(started)})
+
+		started = time.Now()
+		critique, err := critic.Critique(ctx, transcript)
+		if err != nil {
+			return fmt.Errorf("erro do crítico %s na rodada %d: %w", critic.ID, round, err)
+		}
+		transcript.Turns = append(transcript.Turns, Turn{Round: round, Role: RoleCritic, AgentID: critic.ID, Text: critique.Text, Accepted: critique.Accept, Duration: time.Since(started)})
+		if critique.Accept {
+			break
+		}
+	}
+
+	started := time.Now()
+	verdict, err := judge.Decide(ctx, transcript)
+	if err != nil {
+		return fmt.Errorf("erro do juiz %s: %w", judge.ID, err)
+	}
+	transcript.Verdict = verdict
+	transcript.Turns = append(transcript.Turns, Turn{Role: RoleJudge, AgentID: judge.ID, Text: verdict.Rationale, Duration: time.Since(started)})
+	return nil
+}
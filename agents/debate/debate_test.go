@@ -0,0 +1,69 @@
+package debate
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+)
+
+func TestRunRevisesUntilAccepted(t *testing.T) {
+	proposer := Proposer{ID: "proponente", Propose: func(ctx context.Context, transcript *Transcript) (string, error) {
+		if critique, ok := transcript.Last(RoleCritic); ok {
+			return "versão revisada: " + critique.Text, nil
+		}
+		return "primeira versão", nil
+	}}
+	critic := Critic{ID: "critico", Critique: func(ctx context.Context, transcript *Transcript) (Critique, error) {
+		if transcript.Rounds < 2 {
+			return Critique{Text: "falta o orçamento"}, nil
+		}
+		return Critique{Text: "aprovada", Accept: true}, nil
+	}}
+	judge := Judge{ID: "juiz", Decide: func(ctx context.Context, transcript *Transcript) (Verdict, error) {
+		proposal, _ := transcript.Last(RoleProposer)
+		return Verdict{Answer: proposal.Text, Rationale: "crítica atendida", Score: 0.9}, nil
+	}}
+
+	dir := t.TempDir()
+	transcript, err := Run(context.Background(), "Qual o plano?", proposer, critic, judge, Config{Rounds: 5, Store: DirStore(dir)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if transcript.Rounds != 2 || len(transcript.Turns) != 5 || !transcript.Turns[3].Accepted {
+		t.Fatalf("transcrição inesperada: %+v", transcript)
+	}
+	if transcript.Verdict.Answer != "versão revisada: falta o orçamento" {
+		t.Fatalf("veredito inesperado: %+v", transcript.Verdict)
+	}
+
+	data, err := os.ReadFile(transcript.Artifact)
+	if err != nil {
+		t.Fatalf("artefato não gravado: %v", err)
+	}
+	var saved Transcript
+	if err := json.Unmarshal(data, &saved); err != nil || saved.ID != transcript.ID || len(saved.Turns) != 5 {
+		t.Fatalf("artefato inesperado: %+v, %v", saved, err)
+	}
+}
+
+func TestRunStoresPartialTranscriptOnFailure(t *testing.T) {
+	proposer := Proposer{ID: "p", Propose: func(context.Context, *Transcript) (string, error) { return "proposta", nil }}
+	critic := Critic{ID: "c", Critique: func(context.Context, *Transcript) (Critique, error) { return Critique{}, errors.New("sem LLM") }}
+	judge := Judge{ID: "j", Decide: func(context.Context, *Transcript) (Verdict, error) { return Verdict{}, fmt.Errorf("não chamado") }}
+
+	dir := t.TempDir()
+	transcript, err := Run(context.Background(), "?", proposer, critic, judge, Config{Store: DirStore(dir)})
+	if err == nil || transcript == nil || len(transcript.Turns) != 1 {
+		t.Fatalf("esperava o erro do crítico com a transcrição parcial: %+v, %v", transcript, err)
+	}
+	if _, statErr := os.Stat(transcript.Artifact); statErr != nil {
+		t.Fatalf("a transcrição parcial deveria ser gravada: %v", statErr)
+	}
+
+	if _, err := Run(context.Background(), "?", proposer, Critic{}, judge, Config{}); err == nil {
+		t.Fatal("esperava erro de validação sem crítico")
+	}
+}
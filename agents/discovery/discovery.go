@@ -0,0 +1,362 @@
+// Package discovery implementa a descoberta de agentes por gossip (memberlist) para as
+// implantações sem registro central: cada processo anuncia as capacidades dos seus agentes,
+// os anúncios se espalham entre os nós do cluster e as equipes são formadas dinamicamente a
+// partir dos agentes conhecidos.
+package discovery
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/memberlist"
+
+	"github.com/suissa/HiveMind/agents/errs"
+)
+
+// Padrões do protocolo
+const (
+	DefaultBindPort      = 7946
+	DefaultLeaveTimeout  = 5 * time.Second
+	DefaultRetransmitMul = 4
+)
+
+// Capability é o anúncio de um agente: quem ele é e o que sabe fazer
+type Capability struct {
+	AgentID string   `json:"agent_id"`
+	Name    string   `json:"name,omitempty"`
+	Role    string   `json:"role,omitempty"`
+	Tools   []string `json:"tools,omitempty"`
+	Node    string   `json:"node,omitempty"` // Nó que executa o agente, preenchido pela descoberta
+}
+
+// Advertisement é o anúncio de um nó, com as capacidades de todos os seus agentes.
+// Version cresce a cada mudança e decide qual anúncio de um nó é o mais recente; começa no
+// horário de criação do nó, para que os anúncios de um processo reiniciado prevaleçam.
+type Advertisement struct {
+	Node    string       `json:"node"`
+	Version uint64       `json:"version"`
+	Agents  []Capability `json:"agents"`
+}
+
+// EventType é o tipo de mudança no cluster
+type EventType string
+
+const (
+	EventJoin   EventType = "join"   // Primeiro anúncio de um nó
+	EventUpdate EventType = "update" // Novo anúncio de um nó conhecido
+	EventLeave  EventType = "leave"  // O nó saiu ou deixou de responder
+)
+
+// Event é uma mudança no cluster informada aos observadores
+type Event struct {
+	Type          EventType
+	Advertisement Advertisement // Último anúncio do nó
+}
+
+// Config configura o nó de descoberta
+type Config struct {
+	NodeName      string    `json:"node_name" yaml:"node_name"`           // Nome único do nó (padrão: hostname)
+	BindAddr      string    `json:"bind_addr" yaml:"bind_addr"`           // Endereço de escuta (padrão 0.0.0.0)
+	BindPort      int       `json:"bind_port" yaml:"bind_port"`           // Porta de escuta (padrão DefaultBindPort; -1 escolhe uma livre)
+	AdvertiseAddr string    `json:"advertise_addr" yaml:"advertise_addr"` // Endereço anunciado aos outros nós, se diferente do de escuta
+	AdvertisePort int       `json:"advertise_port" yaml:"advertise_port"`
+	Seeds         []string  `json:"seeds" yaml:"seeds"` // Nós conhecidos ("host:porta") usados para entrar no cluster
+	SecretKey     []byte    `json:"-" yaml:"-"`         // Chave AES (16, 24 ou 32 bytes) que cifra o gossip
+	LogOutput     io.Writer `json:"-" yaml:"-"`         // Destino dos logs do memberlist (padrão: descartados)
+}
+
+// Node é o participante local do cluster de descoberta
+type Node struct {
+	list       *memberlist.Memberlist
+	broadcasts *memberlist.TransmitLimitedQueue
+	name       string
+	local      Advertisement
+	peers      map[string]Advertisement // Anúncios dos outros nós, pelo nome do nó
+	members    map[string]bool          // Nós vivos segundo o memberlist
+	observers  []func(Event)
+	mu         sync.RWMutex
+}
+
+// New cria o nó e começa a escutar; Join o conecta ao cluster
+func New(config Config) (*Node, error) {
+	conf := memberlist.DefaultLANConfig()
+	if config.NodeName != "" {
+		conf.Name = config.NodeName
+	}
+	if config.BindAddr != "" {
+		conf.BindAddr = config.BindAddr
+	}
+	switch {
+	case config.BindPort > 0:
+		conf.BindPort = config.BindPort
+	case config.BindPort < 0:
+		conf.BindPort = 0
+	default:
+		conf.BindPort = DefaultBindPort
+	}
+	conf.AdvertisePort = conf.BindPort
+	if config.AdvertiseAddr != "" {
+		conf.AdvertiseAddr = config.AdvertiseAddr
+	}
+	if config.AdvertisePort > 0 {
+		conf.AdvertisePort = config.AdvertisePort
+	}
+	conf.SecretKey = config.SecretKey
+	conf.LogOutput = config.LogOutput
+	if conf.LogOutput == nil {
+		conf.LogOutput = io.Discard
+	}
+
+	n := newNode(conf.Name)
+	conf.Delegate = (*delegate)(n)
+	conf.Events = (*events)(n)
+
+	list, err := memberlist.Create(conf)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao iniciar o gossip: %v", err)
+	}
+	n.list = list
+
+	if len(config.Seeds) > 0 {
+		if err := n.Join(config.Seeds...); err != nil {
+			list.Shutdown()
+			return nil, err
+		}
+	}
+	return n, nil
+}
+
+// newNode cria o estado do nó, sem rede
+func newNode(name string) *Node {
+	if name == "" {
+		name, _ = os.Hostname()
+	}
+	n := &Node{
+		name:    name,
+		local:   Advertisement{Node: name, Version: uint64(time.Now().UnixNano())},
+		peers:   make(map[string]Advertisement),
+		members: make(map[string]bool),
+	}
+	n.broadcasts = &memberlist.TransmitLimitedQueue{
+		NumNodes:       n.numMembers,
+		RetransmitMult: DefaultRetransmitMul,
+	}
+	return n
+}
+
+// numMembers retorna o número de nós vivos, usado para dimensionar as retransmissões
+func (n *Node) numMembers() int {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	return max(len(n.members), 1)
+}
+
+// Name retorna o nome do nó no cluster
+func (n *Node) Name() string {
+	return n.name
+}
+
+// Addr retorna o endereço "host:porta" em que o nó recebe o gossip, para ser usado como seed
+func (n *Node) Addr() string {
+	return n.list.LocalNode().Address()
+}
+
+// Join entra no cluster pelos nós informados; basta que um deles responda
+func (n *Node) Join(seeds ...string) error {
+	if _, err := n.list.Join(seeds); err != nil {
+		return fmt.Errorf("erro ao entrar no cluster: %v", err)
+	}
+	return nil
+}
+
+// Advertise substitui as capacidades anunciadas pelo nó e espalha o novo anúncio
+func (n *Node) Advertise(capabilities ...Capability) {
+	n.mu.Lock()
+	agents := make([]Capability, len(capabilities))
+	for i, capability := range capabilities {
+		capability.Node = n.name
+		agents[i] = capability
+	}
+	sort.Slice(agents, func(i, j int) bool { return agents[i].AgentID < agents[j].AgentID })
+	n.local.Agents = agents
+	n.local.Version++
+	ad := n.local
+	n.mu.Unlock()
+
+	n.broadcast(ad)
+}
+
+// broadcast coloca o anúncio na fila de gossip, substituindo os anteriores do mesmo nó
+func (n *Node) broadcast(ad Advertisement) {
+	data, err := json.Marshal([]Advertisement{ad})
+	if err != nil {
+		log.Printf("⚠️ Erro ao serializar anúncio do nó %s: %v", ad.Node, err)
+		return
+	}
+	n.broadcasts.QueueBroadcast(&broadcast{node: ad.Node, data: data})
+}
+
+// OnChange registra um observador das entradas, atualizações e saídas de nós. Os observadores
+// são chamados nas goroutines do gossip e não devem bloquear.
+func (n *Node) OnChange(observer func(Event)) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.observers = append(n.observers, observer)
+}
+
+// Peers retorna os anúncios dos outros nós, ordenados pelo nome do nó
+func (n *Node) Peers() []Advertisement {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	ads := make([]Advertisement, 0, len(n.peers))
+	for _, ad := range n.peers {
+		ads = append(ads, ad)
+	}
+	sort.Slice(ads, func(i, j int) bool { return ads[i].Node < ads[j].Node })
+	return ads
+}
+
+// Agents retorna as capacidades de todos os agentes do cluster, inclusive os locais, com o
+// papel informado (vazio retorna todos), ordenadas pelo nó e pelo ID do agente
+func (n *Node) Agents(role string) []Capability {
+	n.mu.RLock()
+	ads := make([]Advertisement, 0, len(n.peers)+1)
+	ads = append(ads, n.local)
+	for _, ad := range n.peers {
+		ads = append(ads, ad)
+	}
+	n.mu.RUnlock()
+
+	var agents []Capability
+	for _, ad := range ads {
+		for _, capability := range ad.Agents {
+			if role == "" || capability.Role == role {
+				agents = append(agents, capability)
+			}
+		}
+	}
+	sort.Slice(agents, func(i, j int) bool {
+		if agents[i].Node != agents[j].Node {
+			return agents[i].Node < agents[j].Node
+		}
+		return agents[i].AgentID < agents[j].AgentID
+	})
+	return agents
+}
+
+// FormCrew escolhe um agente do cluster para cada papel informado, sem repetir agentes. Entre
+// os candidatos de um papel, prefere os nós com menos agentes já escolhidos, para espalhar a
+// equipe pelo cluster. Retorna errs.ErrNotFound se algum papel não tiver agente disponível.
+func (n *Node) FormCrew(roles ...string) ([]Capability, error) {
+	all := n.Agents("")
+	used := make(map[string]bool)
+	perNode := make(map[string]int)
+	crew := make([]Capability, 0, len(roles))
+
+	for _, role := range roles {
+		var chosen *Capability
+		for i := range all {
+			candidate := &all[i]
+			if candidate.Role != role || used[candidate.Node+"/"+candidate.AgentID] {
+				continue
+			}
+			if chosen == nil || perNode[candidate.Node] < perNode[chosen.Node] {
+				chosen = candidate
+			}
+		}
+		if chosen == nil {
+			return nil, errs.New(errs.ErrNotFound, "discovery.FormCrew", "nenhum agente disponível com o papel %s", role)
+		}
+		used[chosen.Node+"/"+chosen.AgentID] = true
+		perNode[chosen.Node]++
+		crew = append(crew, *chosen)
+	}
+	return crew, nil
+}
+
+// Leave avisa o cluster da saída do nó e encerra o gossip
+func (n *Node) Leave(timeout time.Duration) error {
+	if timeout <= 0 {
+		timeout = DefaultLeaveTimeout
+	}
+	if err := n.list.Leave(timeout); err != nil {
+		n.list.Shutdown()
+		return fmt.Errorf("erro ao sair do cluster: %v", err)
+	}
+	return n.list.Shutdown()
+}
+
+// merge aplica anúncios recebidos pelo gossip, mantendo o mais recente de cada nó. Os anúncios
+// novos são repassados aos outros nós, para que se espalhem pelo cluster.
+func (n *Node) merge(ads []Advertisement) {
+	var changes []Event
+	n.mu.Lock()
+	for _, ad := range ads {
+		if ad.Node == "" || ad.Node == n.name || !n.members[ad.Node] {
+			continue
+		}
+		current, known := n.peers[ad.Node]
+		if known && current.Version >= ad.Version {
+			continue
+		}
+		n.peers[ad.Node] = ad
+		event := Event{Type: EventUpdate, Advertisement: ad}
+		if !known {
+			event.Type = EventJoin
+		}
+		changes = append(changes, event)
+	}
+	observers := n.observers
+	n.mu.Unlock()
+
+	for _, event := range changes {
+		n.broadcast(event.Advertisement)
+		notify(observers, event)
+	}
+}
+
+// join registra um nó vivo segundo o memberlist
+func (n *Node) join(name string) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.members[name] = true
+}
+
+// leave remove o nó e o seu anúncio
+func (n *Node) leave(name string) {
+	n.mu.Lock()
+	delete(n.members, name)
+	ad, known := n.peers[name]
+	delete(n.peers, name)
+	observers := n.observers
+	n.mu.Unlock()
+
+	if known {
+		notify(observers, Event{Type: EventLeave, Advertisement: ad})
+	}
+}
+
+// state retorna todos os anúncios conhecidos, trocados na sincronização completa entre nós
+func (n *Node) state() []Advertisement {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	ads := make([]Advertisement, 0, len(n.peers)+1)
+	ads = append(ads, n.local)
+	for _, ad := range n.peers {
+		ads = append(ads, ad)
+	}
+	return ads
+}
+
+// notify avisa os observadores
+func notify(observers []func(Event), event Event) {
+	for _, observer := range observers {
+		observer(event)
+	}
+}
@@ -0,0 +1,66 @@
+package discovery
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/suissa/HiveMind/agents/errs"
+)
+
+func TestMergeKeepsLatestAdvertisement(t *testing.T) {
+	node := newNode("local")
+	var events []Event
+	node.OnChange(func(event Event) { events = append(events, event) })
+
+	ad := Advertisement{Node: "remote", Version: 2, Agents: []Capability{{AgentID: "w-1", Role: "writer", Node: "remote"}}}
+	node.merge([]Advertisement{ad})
+	if len(node.Peers()) != 0 {
+		t.Fatal("anúncios de nós fora do cluster deveriam ser ignorados")
+	}
+
+	node.join("remote")
+	node.merge([]Advertisement{ad})
+	node.merge([]Advertisement{{Node: "remote", Version: 1}})
+	if peers := node.Peers(); len(peers) != 1 || peers[0].Version != 2 {
+		t.Fatalf("esperava o anúncio mais recente: %+v", peers)
+	}
+
+	ad.Version = 3
+	ad.Agents = nil
+	node.merge([]Advertisement{ad})
+	node.leave("remote")
+	if len(events) != 3 || events[0].Type != EventJoin || events[1].Type != EventUpdate || events[2].Type != EventLeave {
+		t.Fatalf("eventos inesperados: %+v", events)
+	}
+	if len(node.Peers()) != 0 {
+		t.Fatal("o anúncio deveria sair com o nó")
+	}
+	if node.broadcasts.NumQueued() == 0 {
+		t.Fatal("os anúncios novos deveriam ser repassados")
+	}
+}
+
+func TestFormCrewSpreadsAcrossNodes(t *testing.T) {
+	node := newNode("a")
+	node.Advertise(
+		Capability{AgentID: "w-1", Role: "writer"},
+		Capability{AgentID: "r-1", Role: "reviewer"},
+	)
+	node.join("b")
+	node.merge([]Advertisement{{Node: "b", Version: 1, Agents: []Capability{
+		{AgentID: "w-2", Role: "writer", Node: "b"},
+		{AgentID: "r-2", Role: "reviewer", Node: "b"},
+	}}})
+
+	crew, err := node.FormCrew("writer", "reviewer", "writer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(crew) != 3 || crew[0].Node != "a" || crew[1].Node != "b" || crew[2].AgentID != "w-2" {
+		t.Fatalf("equipe inesperada: %+v", crew)
+	}
+
+	if _, err := node.FormCrew("designer"); !errors.Is(err, errs.ErrNotFound) {
+		t.Fatalf("esperava ErrNotFound, obtido %v", err)
+	}
+}
@@ -0,0 +1,77 @@
+package discovery
+
+import (
+	"encoding/json"
+	"log"
+
+	"github.com/hashicorp/memberlist"
+)
+
+// delegate liga o nó ao protocolo de gossip do memberlist
+type delegate Node
+
+// NodeMeta não usa os metadados do memberlist: os anúncios são grandes demais para o limite
+// de memberlist.MetaMaxSize e seguem pelas mensagens de gossip
+func (d *delegate) NodeMeta(limit int) []byte {
+	return nil
+}
+
+// NotifyMsg recebe os anúncios espalhados pelos outros nós
+func (d *delegate) NotifyMsg(data []byte) {
+	var ads []Advertisement
+	if err := json.Unmarshal(data, &ads); err != nil {
+		log.Printf("⚠️ Anúncio de descoberta inválido: %v", err)
+		return
+	}
+	(*Node)(d).merge(ads)
+}
+
+// GetBroadcasts entrega ao memberlist os anúncios pendentes de envio
+func (d *delegate) GetBroadcasts(overhead, limit int) [][]byte {
+	return d.broadcasts.GetBroadcasts(overhead, limit)
+}
+
+// LocalState envia todos os anúncios conhecidos na sincronização completa com outro nó
+func (d *delegate) LocalState(join bool) []byte {
+	data, err := json.Marshal((*Node)(d).state())
+	if err != nil {
+		log.Printf("⚠️ Erro ao serializar anúncios: %v", err)
+		return nil
+	}
+	return data
+}
+
+// MergeRemoteState aplica os anúncios recebidos na sincronização completa
+func (d *delegate) MergeRemoteState(data []byte, join bool) {
+	d.NotifyMsg(data)
+}
+
+// events acompanha as entradas e saídas de nós detectadas pelo memberlist
+type events Node
+
+func (e *events) NotifyJoin(node *memberlist.Node) {
+	(*Node)(e).join(node.Name)
+}
+
+func (e *events) NotifyLeave(node *memberlist.Node) {
+	(*Node)(e).leave(node.Name)
+}
+
+func (e *events) NotifyUpdate(node *memberlist.Node) {}
+
+// broadcast é um anúncio na fila de gossip; um anúncio mais novo do mesmo nó invalida o anterior
+type broadcast struct {
+	node string
+	data []byte
+}
+
+func (b *broadcast) Invalidates(other memberlist.Broadcast) bool {
+	previous, ok := other.(*broadcast)
+	return ok && previous.node == b.node
+}
+
+func (b *broadcast) Message() []byte {
+	return b.data
+}
+
+func (b *broadcast) Finished() {}
@@ -0,0 +1,269 @@
+// Package docgen preenche modelos de documentos — propostas, briefings, relatórios — com os
+// dados dos workflows. Os modelos são Markdown ou Word (DOCX) com a sintaxe de text/template,
+// então aceitam laços ({{range .itens}}...{{end}}) e condicionais ({{if .desconto}}...{{end}});
+// nos modelos Word, as ações podem ser escritas normalmente no editor, mesmo que ele as
+// divida em trechos com formatações diferentes.
+package docgen
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/suissa/HiveMind/agents/errs"
+)
+
+// Format é o formato de um modelo e dos documentos gerados a partir dele
+type Format string
+
+// Formatos suportados
+const (
+	FormatMarkdown Format = "markdown"
+	FormatDocx     Format = "docx"
+)
+
+// Tipos de conteúdo dos documentos gerados
+const (
+	ContentTypeMarkdown = "text/markdown; charset=utf-8"
+	ContentTypeDocx     = "application/vnd.openxmlformats-officedocument.wordprocessingml.document"
+)
+
+// ContentType retorna o tipo de conteúdo dos documentos do formato
+func (f Format) ContentType() string {
+	if f == FormatDocx {
+		return ContentTypeDocx
+	}
+	return ContentTypeMarkdown
+}
+
+// Extension retorna a extensão de arquivo dos documentos do formato
+func (f Format) Extension() string {
+	if f == FormatDocx {
+		return ".docx"
+	}
+	return ".md"
+}
+
+// FormatFromPath deduz o formato pela extensão do arquivo (.md, .markdown, .mdown ou .docx)
+func FormatFromPath(path string) (Format, bool) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".md", ".markdown", ".mdown":
+		return FormatMarkdown, true
+	case ".docx":
+		return FormatDocx, true
+	}
+	return "", false
+}
+
+// Document é um documento gerado
+type Document struct {
+	Template    string `json:"template"`
+	Format      Format `json:"format"`
+	ContentType string `json:"content_type"`
+	Data        []byte `json:"-"`
+}
+
+// Filename retorna o nome de arquivo sugerido para o documento
+func (d *Document) Filename() string {
+	return d.Template + d.Format.Extension()
+}
+
+// Template é um modelo carregado
+type Template struct {
+	Name   string
+	Format Format
+	source []byte
+}
+
+// Library guarda os modelos pelo nome; é segura para uso concorrente
+type Library struct {
+	mu        sync.RWMutex
+	templates map[string]*Template
+}
+
+// New cria uma biblioteca vazia
+func New() *Library {
+	return &Library{templates: make(map[string]*Template)}
+}
+
+// Add valida e registra o modelo, substituindo o de mesmo nome
+func (l *Library) Add(name string, format Format, source []byte) error {
+	if name == "" {
+		return errs.New(errs.ErrValidation, "docgen.Add", "modelo sem nome")
+	}
+	t := &Template{Name: name, Format: format, source: source}
+	// A validação compila o modelo, expondo erros de sintaxe no registro e não na geração
+	if _, err := t.render(nil, true); err != nil {
+		return err
+	}
+	l.mu.Lock()
+	l.templates[name] = t
+	l.mu.Unlock()
+	return nil
+}
+
+// Load registra o arquivo de modelo com o nome do arquivo sem a extensão
+func (l *Library) Load(path string) error {
+	format, ok := FormatFromPath(path)
+	if !ok {
+		return errs.New(errs.ErrValidation, "docgen.Load", "formato de modelo não suportado: %s", path)
+	}
+	source, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("erro ao ler o modelo %s: %w", path, err)
+	}
+	name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	return l.Add(name, format, source)
+}
+
+// LoadDir registra os modelos Markdown e Word do diretório; os demais arquivos são ignorados
+func (l *Library) LoadDir(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return fmt.Errorf("erro ao ler o diretório de modelos %s: %w", dir, err)
+	}
+	for _, entry := range entries {
+		// Os arquivos de bloqueio do Word (~$modelo.docx) não são modelos
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), "~$") {
+			continue
+		}
+		if _, ok := FormatFromPath(entry.Name()); !ok {
+			continue
+		}
+		if err := l.Load(filepath.Join(dir, entry.Name())); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Get retorna o modelo registrado com o nome
+func (l *Library) Get(name string) (*Template, bool) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	t, ok := l.templates[name]
+	return t, ok
+}
+
+// Names retorna os nomes dos modelos registrados, em ordem alfabética
+func (l *Library) Names() []string {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	names := make([]string, 0, len(l.templates))
+	for name := range l.templates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Render preenche o modelo com os dados
+func (l *Library) Render(name string, data interface{}) (*Document, error) {
+	t, ok := l.Get(name)
+	if !ok {
+		return nil, errs.New(errs.ErrNotFound, "docgen.Render", "modelo de documento %s não encontrado", name)
+	}
+	return t.Render(data)
+}
+
+// Render preenche o modelo com os dados
+func (t *Template) Render(data interface{}) (*Document, error) {
+	out, err := t.render(data, false)
+	if err != nil {
+		return nil, err
+	}
+	return &Document{Template: t.Name, Format: t.Format, ContentType: t.Format.ContentType(), Data: out}, nil
+}
+
+// render preenche o modelo; com parseOnly, só compila as partes com ações
+func (t *Template) render(data interface{}, parseOnly bool) ([]byte, error) {
+	switch t.Format {
+	case FormatMarkdown:
+		tmpl, err := parse(t.Name, string(t.source), nil)
+		if err != nil || parseOnly {
+			return nil, err
+		}
+		return execute(tmpl, data)
+	case FormatDocx:
+		return renderDocx(t.Name, t.source, data, parseOnly)
+	}
+	return nil, errs.New(errs.ErrValidation, "docgen.Render", "formato de modelo desconhecido: %q", t.Format)
+}
+
+// parse compila o texto do modelo com as funções de Funcs e as extras
+func parse(name, text string, extra template.FuncMap) (*template.Template, error) {
+	tmpl, err := template.New(name).Funcs(Funcs()).Funcs(extra).Parse(text)
+	if err != nil {
+		return nil, errs.Wrap(errs.ErrValidation, "docgen.Parse", err, "modelo %s inválido", name)
+	}
+	return tmpl, nil
+}
+
+// execute preenche o modelo compilado
+func execute(tmpl *template.Template, data interface{}) ([]byte, error) {
+	var out bytes.Buffer
+	if err := tmpl.Execute(&out, data); err != nil {
+		return nil, errs.Wrap(errs.ErrValidation, "docgen.Render", err, "erro ao preencher o modelo %s", tmpl.Name())
+	}
+	return out.Bytes(), nil
+}
+
+// Funcs retorna as funções disponíveis nos modelos, além das nativas de text/template:
+//
+//	upper, lower, trim         {{upper .cliente}}
+//	join                       {{join .canais ", "}}
+//	default                    {{default "a combinar" .prazo}}
+//	date                       {{date "02/01/2006" .vencimento}} (time.Time ou RFC 3339)
+//	now                        {{date "02/01/2006" now}}
+//	add                        {{add $i 1}}, para numerar os itens de um range
+func Funcs() template.FuncMap {
+	return template.FuncMap{
+		"upper": strings.ToUpper,
+		"lower": strings.ToLower,
+		"trim":  strings.TrimSpace,
+		"join": func(items interface{}, sep string) string {
+			switch list := items.(type) {
+			case []string:
+				return strings.Join(list, sep)
+			case []interface{}:
+				parts := make([]string, len(list))
+				for i, item := range list {
+					parts[i] = fmt.Sprint(item)
+				}
+				return strings.Join(parts, sep)
+			case nil:
+				return ""
+			}
+			return fmt.Sprint(items)
+		},
+		"default": func(fallback, value interface{}) interface{} {
+			if value == nil || fmt.Sprint(value) == "" {
+				return fallback
+			}
+			return value
+		},
+		"date": func(layout string, value interface{}) (string, error) {
+			switch v := value.(type) {
+			case time.Time:
+				return v.Format(layout), nil
+			case string:
+				parsed, err := time.Parse(time.RFC3339, v)
+				if err != nil {
+					return "", fmt.Errorf("data inválida %q: %v", v, err)
+				}
+				return parsed.Format(layout), nil
+			case nil:
+				return "", nil
+			}
+			return "", fmt.Errorf("data inválida: %v", value)
+		},
+		"now": time.Now,
+		"add": func(a, b int) int { return a + b },
+	}
+}
@@ -0,0 +1,136 @@
+package docgen
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func offer() map[string]interface{} {
+	return map[string]interface{}{
+		"cliente":  "Loja <Centro> & Cia",
+		"desconto": 10,
+		"itens": []interface{}{
+			map[string]interface{}{"nome": "Tênis", "preco": "R$ 299"},
+			map[string]interface{}{"nome": "Bota", "preco": "R$ 459"},
+		},
+		"obs": "Entrega em 5 dias\nFrete grátis",
+	}
+}
+
+func TestMarkdown(t *testing.T) {
+	library := New()
+	source := "# Proposta para {{.cliente}}\n{{range $i, $item := .itens}}{{add $i 1}}. {{$item.nome}}: {{$item.preco}}\n{{end}}" +
+		"{{if .desconto}}Desconto de {{.desconto}}%{{end}} — {{default \"a combinar\" .prazo}}"
+	if err := library.Add("proposta", FormatMarkdown, []byte(source)); err != nil {
+		t.Fatal(err)
+	}
+	doc, err := library.Render("proposta", offer())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "# Proposta para Loja <Centro> & Cia\n1. Tênis: R$ 299\n2. Bota: R$ 459\nDesconto de 10% — a combinar"
+	if string(doc.Data) != want || doc.ContentType != ContentTypeMarkdown || doc.Filename() != "proposta.md" {
+		t.Fatalf("documento inesperado: %q", doc.Data)
+	}
+
+	if err := library.Add("quebrado", FormatMarkdown, []byte("{{if .x}}")); err == nil {
+		t.Fatal("modelo inválido deveria ser rejeitado no registro")
+	}
+	if _, err := library.Render("inexistente", nil); err == nil {
+		t.Fatal("modelo inexistente deveria falhar")
+	}
+}
+
+// docx monta um pacote Word mínimo com o corpo informado
+func docx(t *testing.T, body string) []byte {
+	var out bytes.Buffer
+	w := zip.NewWriter(&out)
+	parts := map[string]string{
+		"[Content_Types].xml": `<?xml version="1.0"?><Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types"/>`,
+		"word/document.xml": `<?xml version="1.0"?><w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body>` +
+			body + `</w:body></w:document>`,
+	}
+	for name, content := range parts {
+		f, err := w.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		f.Write([]byte(content))
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return out.Bytes()
+}
+
+func TestDocx(t *testing.T) {
+	// O Word divide as ações em trechos e troca as aspas
+	body := `<w:p><w:r><w:t>Cliente: {</w:t></w:r><w:r><w:rPr><w:b/></w:rPr><w:t>{.cli</w:t></w:r><w:proofErr w:type="spellEnd"/><w:r><w:t>ente}}</w:t></w:r></w:p>` +
+		`<w:p><w:r><w:t>{{range .itens}}</w:t></w:r></w:p>` +
+		`<w:p><w:r><w:t>{{.nome}} por {{.preco}}</w:t></w:r></w:p>` +
+		`<w:p><w:r><w:t>{{end}}</w:t></w:r></w:p>` +
+		`<w:p><w:r><w:t>{{if gt .desconto 5}}Desconto especial{{else}}Sem desconto{{end}}</w:t></w:r></w:p>` +
+		`<w:p><w:r><w:t>{{default “a combinar” .prazo}} — {{.obs}}</w:t></w:r></w:p>`
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "proposta.docx")
+	if err := os.WriteFile(path, docx(t, body), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	os.WriteFile(filepath.Join(dir, "~$proposta.docx"), []byte("bloqueio"), 0o644)
+	os.WriteFile(filepath.Join(dir, "notas.txt"), []byte("ignorado"), 0o644)
+	library := New()
+	if err := library.LoadDir(dir); err != nil {
+		t.Fatal(err)
+	}
+	if names := library.Names(); len(names) != 1 || names[0] != "proposta" {
+		t.Fatalf("modelos inesperados: %v", names)
+	}
+
+	doc, err := library.Render("proposta", offer())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if doc.Format != FormatDocx || doc.Filename() != "proposta.docx" {
+		t.Fatalf("documento inesperado: %+v", doc)
+	}
+	reader, err := zip.NewReader(bytes.NewReader(doc.Data), int64(len(doc.Data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var document string
+	for _, f := range reader.File {
+		if f.Name == "word/document.xml" {
+			rc, _ := f.Open()
+			data, _ := io.ReadAll(rc)
+			rc.Close()
+			document = string(data)
+		}
+	}
+	if err := xml.Unmarshal([]byte(document), new(interface{})); err != nil {
+		t.Fatalf("XML malformado: %v\n%s", err, document)
+	}
+	for _, want := range []string{
+		"Cliente: Loja &lt;Centro&gt; &amp; Cia",
+		"Tênis por R$ 299", "Bota por R$ 459",
+		"Desconto especial",
+		"a combinar — Entrega em 5 dias</w:t><w:br/>",
+	} {
+		if !strings.Contains(document, want) {
+			t.Fatalf("%q ausente em:\n%s", want, document)
+		}
+	}
+	if strings.Contains(document, "{{") || strings.Contains(document, "Sem desconto") {
+		t.Fatalf("ações não preenchidas:\n%s", document)
+	}
+
+	if err := library.Add("invalido", FormatDocx, []byte("não é zip")); err == nil {
+		t.Fatal("DOCX inválido deveria ser rejeitado")
+	}
+}
@@ -0,0 +1,187 @@
+package docgen
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"html"
+	"io"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"github.com/suissa/HiveMind/agents/errs"
+)
+
+// templatedPart reconhece as partes do DOCX com texto do documento
+var templatedPart = regexp.MustCompile(`^word/(document|header\d*|footer\d*|footnotes|endnotes)\.xml$`)
+
+// action reconhece as ações já reunidas por mergeActions, com os marcadores de corte de espaços
+var action = regexp.MustCompile(`(?s)\{\{(-\s)?(.*?)(\s-)?\}\}`)
+
+// controlAction reconhece as ações que não escrevem um valor: estruturas de controle,
+// comentários e declarações de variáveis
+var controlAction = regexp.MustCompile(`^\s*(?:(?:if|else|end|range|with|define|template|block|break|continue)\b|/\*|\$\w*\s*:?=)`)
+
+// smartQuotes desfaz a troca automática de aspas do Word dentro das ações
+var smartQuotes = strings.NewReplacer("“", `"`, "”", `"`, "„", `"`)
+
+// breaks transforma as quebras de linha e tabulações dos valores em elementos do Word; o
+// valor é escrito dentro de um <w:t>, que é fechado e reaberto em volta do elemento
+var breaks = strings.NewReplacer(
+	"&#xA;", `</w:t><w:br/><w:t xml:space="preserve">`,
+	"&#x9;", `</w:t><w:tab/><w:t xml:space="preserve">`,
+	"&#xD;", "",
+)
+
+// renderDocx preenche as partes de texto do documento Word e remonta o pacote
+func renderDocx(name string, source []byte, data interface{}, parseOnly bool) ([]byte, error) {
+	reader, err := zip.NewReader(bytes.NewReader(source), int64(len(source)))
+	if err != nil {
+		return nil, errs.Wrap(errs.ErrValidation, "docgen.Render", err, "modelo %s não é um DOCX válido", name)
+	}
+	var out bytes.Buffer
+	writer := zip.NewWriter(&out)
+	found := false
+	for _, file := range reader.File {
+		if !templatedPart.MatchString(file.Name) {
+			if err := writer.Copy(file); err != nil {
+				return nil, fmt.Errorf("erro ao copiar %s do modelo %s: %v", file.Name, name, err)
+			}
+			continue
+		}
+		found = found || file.Name == "word/document.xml"
+		part, err := readPart(file)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao ler %s do modelo %s: %v", file.Name, name, err)
+		}
+		text := prepare(part)
+		if !strings.Contains(text, "{{") {
+			if err := writer.Copy(file); err != nil {
+				return nil, fmt.Errorf("erro ao copiar %s do modelo %s: %v", file.Name, name, err)
+			}
+			continue
+		}
+		tmpl, err := parse(name+":"+file.Name, text, template.FuncMap{"xml": escapeXML})
+		if err != nil {
+			return nil, err
+		}
+		if parseOnly {
+			continue
+		}
+		filled, err := execute(tmpl, data)
+		if err != nil {
+			return nil, err
+		}
+		w, err := writer.CreateHeader(&zip.FileHeader{Name: file.Name, Method: zip.Deflate, Modified: file.Modified})
+		if err != nil {
+			return nil, fmt.Errorf("erro ao gravar %s do documento %s: %v", file.Name, name, err)
+		}
+		if _, err := w.Write(filled); err != nil {
+			return nil, fmt.Errorf("erro ao gravar %s do documento %s: %v", file.Name, name, err)
+		}
+	}
+	if !found {
+		return nil, errs.New(errs.ErrValidation, "docgen.Render", "modelo %s não é um DOCX válido: word/document.xml ausente", name)
+	}
+	if err := writer.Close(); err != nil {
+		return nil, fmt.Errorf("erro ao gravar o documento %s: %v", name, err)
+	}
+	if parseOnly {
+		return nil, nil
+	}
+	return out.Bytes(), nil
+}
+
+// readPart lê uma parte do pacote
+func readPart(file *zip.File) (string, error) {
+	rc, err := file.Open()
+	if err != nil {
+		return "", err
+	}
+	defer rc.Close()
+	data, err := io.ReadAll(rc)
+	return string(data), err
+}
+
+// prepare transforma o XML de uma parte num modelo de text/template: reúne as ações
+// divididas entre trechos, desfaz nelas o escape do XML e as aspas do Word e escapa para
+// XML o valor das ações que escrevem no documento
+func prepare(part string) string {
+	return action.ReplaceAllStringFunc(mergeActions(part), func(match string) string {
+		groups := action.FindStringSubmatch(match)
+		left, body, right := groups[1], smartQuotes.Replace(html.UnescapeString(groups[2])), groups[3]
+		if !controlAction.MatchString(body) && strings.TrimSpace(body) != "" {
+			body = "(" + body + ") | xml"
+		}
+		return "{{" + left + body + right + "}}"
+	})
+}
+
+// mergeActions remove as tags XML de dentro das ações. O Word divide o texto em trechos
+// (<w:r>) a cada mudança de formatação ou revisão ortográfica, então "{{.cliente}}" pode
+// chegar como "{{</w:t></w:r><w:r><w:t>.cliente}}"; as tags removidas fecham e reabrem os
+// mesmos elementos, e o XML continua bem formado.
+func mergeActions(part string) string {
+	var out strings.Builder
+	out.Grow(len(part))
+	inAction := false
+	for i := 0; i < len(part); {
+		c := part[i]
+		if c == '<' {
+			end := strings.IndexByte(part[i:], '>')
+			if end < 0 {
+				out.WriteString(part[i:])
+				break
+			}
+			if !inAction {
+				out.WriteString(part[i : i+end+1])
+			}
+			i += end + 1
+			continue
+		}
+		if c == '{' && !inAction {
+			if next, ok := nextText(part, i+1, '{'); ok {
+				out.WriteString("{{")
+				inAction = true
+				i = next + 1
+				continue
+			}
+		}
+		if c == '}' && inAction {
+			if next, ok := nextText(part, i+1, '}'); ok {
+				out.WriteString("}}")
+				inAction = false
+				i = next + 1
+				continue
+			}
+		}
+		out.WriteByte(c)
+		i++
+	}
+	return out.String()
+}
+
+// nextText informa se o próximo caractere de texto a partir de i, pulando as tags, é want,
+// e a sua posição
+func nextText(part string, i int, want byte) (int, bool) {
+	for i < len(part) && part[i] == '<' {
+		end := strings.IndexByte(part[i:], '>')
+		if end < 0 {
+			return 0, false
+		}
+		i += end + 1
+	}
+	return i, i < len(part) && part[i] == want
+}
+
+// escapeXML escreve o valor como texto do documento Word
+func escapeXML(value interface{}) string {
+	if value == nil {
+		return ""
+	}
+	var out strings.Builder
+	xml.EscapeText(&out, []byte(fmt.Sprint(value)))
+	return breaks.Replace(out.String())
+}
@@ -0,0 +1,107 @@
+package agents
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/suissa/HiveMind/agents/blob"
+	"github.com/suissa/HiveMind/agents/docgen"
+	"github.com/suissa/HiveMind/agents/errs"
+)
+
+// DocumentToolName é o nome da ferramenta que gera documentos a partir dos modelos
+const DocumentToolName = "generate_document"
+
+// documentTool preenche os modelos da biblioteca e exporta os documentos para o
+// armazenamento de objetos
+type documentTool struct {
+	library *docgen.Library
+	blobs   *blob.Manager
+}
+
+// NewDocumentTool cria a ferramenta generate_document, com que o agente preenche um modelo
+// Markdown ou Word da biblioteca — proposta, briefing, relatório — com os dados do workflow.
+// O documento é guardado no armazenamento de objetos e a ferramenta retorna a referência
+// [blob:...]; sem armazenamento, só os modelos Markdown podem ser gerados, e o texto é
+// retornado diretamente.
+func NewDocumentTool(library *docgen.Library, blobs *blob.Manager) Tool {
+	return &documentTool{library: library, blobs: blobs}
+}
+
+func (t *documentTool) Name() string { return DocumentToolName }
+
+func (t *documentTool) Description() string {
+	return "Gera um documento (Markdown ou Word) preenchendo um modelo com os dados informados. Modelos: " +
+		strings.Join(t.library.Names(), ", ")
+}
+
+// InputSchema implementa SchemaTool
+func (t *documentTool) InputSchema() json.RawMessage {
+	return json.RawMessage(`{"type":"object","properties":{` +
+		`"template":{"type":"string","description":"Nome do modelo"},` +
+		`"data":{"type":"object","description":"Dados usados pelo modelo, ex.: cliente, itens, prazo"}},` +
+		`"required":["template"]}`)
+}
+
+// Execute gera o documento e retorna a referência no armazenamento (ou o texto, sem ele)
+func (t *documentTool) Execute(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+	name, _ := params["template"].(string)
+	if name == "" {
+		return nil, errs.New(errs.ErrValidation, "agents.generate_document", "parâmetro template obrigatório")
+	}
+	doc, err := t.library.Render(name, params["data"])
+	if err != nil {
+		return nil, err
+	}
+
+	result := map[string]interface{}{
+		"template":     doc.Template,
+		"filename":     doc.Filename(),
+		"format":       string(doc.Format),
+		"content_type": doc.ContentType,
+		"size":         len(doc.Data),
+	}
+	if t.blobs == nil {
+		if doc.Format != docgen.FormatMarkdown {
+			return nil, errs.New(errs.ErrValidation, "agents.generate_document", "o modelo %s gera %s, que exige um armazenamento de objetos", name, doc.Format)
+		}
+		result["content"] = string(doc.Data)
+		return result, nil
+	}
+	ref, err := t.blobs.Put(ctx, doc.Data, doc.ContentType)
+	if err != nil {
+		return nil, err
+	}
+	result["ref"] = ref.String()
+	return result, nil
+}
+
+// WorkflowDocumentData reúne os dados de um workflow concluído para os modelos de documento:
+// project, objective, target_audience, channels, constraints, budget, status, outputs (a
+// resposta de cada tarefa pelo ID), timed_out, skipped e completed_at
+func WorkflowDocumentData(project *MarketingProject, results *WorkflowResults) map[string]interface{} {
+	data := map[string]interface{}{
+		"project":         project.Name,
+		"objective":       project.Objective,
+		"target_audience": project.TargetAudience,
+		"channels":        project.Channels,
+		"constraints":     project.Constraints,
+		"budget":          project.Budget,
+		"completed_at":    time.Now(),
+	}
+	if results != nil {
+		outputs := make(map[string]interface{}, len(project.Tasks))
+		for _, task := range project.Tasks {
+			if output, ok := results.TaskOutputs[task.ID]; ok {
+				outputs[task.ID] = output
+			}
+		}
+		data["status"] = results.Status
+		data["outputs"] = outputs
+		data["timed_out"] = results.TimedOut
+		data["skipped"] = results.Skipped
+	}
+	return data
+}
@@ -0,0 +1,102 @@
+// Package errs define a taxonomia de erros do HiveMind: sentinelas para os casos que
+// exigem tratamento programático (não encontrado, timeout, limite de taxa, validação)
+// e um tipo Error que preserva a operação e a causa original, compatível com
+// errors.Is e errors.As.
+package errs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// Sentinelas da taxonomia. Use errors.Is(err, errs.ErrNotFound) para identificá-las.
+var (
+	ErrNotFound    = errors.New("não encontrado")
+	ErrTimeout     = errors.New("tempo esgotado")
+	ErrRateLimited = errors.New("limite de requisições excedido")
+	ErrValidation  = errors.New("dados inválidos")
+)
+
+// Error associa uma sentinela (Kind) à operação que falhou e à causa original
+type Error struct {
+	Kind    error  // Sentinela da taxonomia
+	Op      string // Operação que falhou, ex.: "redis.GetMemory"
+	Message string // Mensagem legível; se vazia, usa a da sentinela
+	Err     error  // Causa original, se houver
+}
+
+// Error implementa error
+func (e *Error) Error() string {
+	message := e.Message
+	if message == "" && e.Kind != nil {
+		message = e.Kind.Error()
+	}
+	if e.Op != "" {
+		message = e.Op + ": " + message
+	}
+	if e.Err != nil {
+		message += ": " + e.Err.Error()
+	}
+	return message
+}
+
+// Unwrap expõe a sentinela e a causa para errors.Is e errors.As
+func (e *Error) Unwrap() []error {
+	unwrapped := make([]error, 0, 2)
+	if e.Kind != nil {
+		unwrapped = append(unwrapped, e.Kind)
+	}
+	if e.Err != nil {
+		unwrapped = append(unwrapped, e.Err)
+	}
+	return unwrapped
+}
+
+// New cria um erro da categoria informada com uma mensagem formatada
+func New(kind error, op, format string, args ...interface{}) error {
+	return &Error{Kind: kind, Op: op, Message: fmt.Sprintf(format, args...)}
+}
+
+// Wrap classifica a causa na categoria informada, preservando-a na cadeia
+func Wrap(kind error, op string, err error, format string, args ...interface{}) error {
+	if err == nil {
+		return nil
+	}
+	return &Error{Kind: kind, Op: op, Message: fmt.Sprintf(format, args...), Err: err}
+}
+
+// Kind retorna a sentinela da taxonomia presente na cadeia do erro, ou nil
+func Kind(err error) error {
+	for _, kind := range []error{ErrNotFound, ErrTimeout, ErrRateLimited, ErrValidation} {
+		if errors.Is(err, kind) {
+			return kind
+		}
+	}
+	return nil
+}
+
+// FromContext classifica um contexto expirado como ErrTimeout. Outros erros,
+// inclusive o cancelamento pelo chamador, são devolvidos sem alteração.
+func FromContext(op string, err error) error {
+	if errors.Is(err, context.DeadlineExceeded) && !errors.Is(err, ErrTimeout) {
+		return Wrap(ErrTimeout, op, err, "prazo da operação expirado")
+	}
+	return err
+}
+
+// KindForStatus mapeia um status HTTP para a sentinela correspondente, ou nil
+func KindForStatus(status int) error {
+	switch status {
+	case http.StatusNotFound, http.StatusGone:
+		return ErrNotFound
+	case http.StatusRequestTimeout, http.StatusGatewayTimeout:
+		return ErrTimeout
+	case http.StatusTooManyRequests:
+		return ErrRateLimited
+	case http.StatusBadRequest, http.StatusUnprocessableEntity:
+		return ErrValidation
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package errs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestWrapPreservesKindAndCause(t *testing.T) {
+	cause := errors.New("conexão recusada")
+	err := fmt.Errorf("erro ao buscar memória: %w", Wrap(ErrNotFound, "redis.GetMemory", cause, "memória %s não encontrada", "m-1"))
+
+	if !errors.Is(err, ErrNotFound) {
+		t.Fatalf("esperava ErrNotFound na cadeia: %v", err)
+	}
+	if !errors.Is(err, cause) {
+		t.Fatalf("esperava a causa original na cadeia: %v", err)
+	}
+	if errors.Is(err, ErrTimeout) {
+		t.Fatalf("não esperava ErrTimeout na cadeia: %v", err)
+	}
+
+	var typed *Error
+	if !errors.As(err, &typed) || typed.Op != "redis.GetMemory" {
+		t.Fatalf("esperava *Error com a operação, obteve %#v", typed)
+	}
+
+	want := "erro ao buscar memória: redis.GetMemory: memória m-1 não encontrada: conexão recusada"
+	if err.Error() != want {
+		t.Fatalf("mensagem inesperada:\n%s\n%s", err.Error(), want)
+	}
+}
+
+func TestFromContext(t *testing.T) {
+	if err := FromContext("op", context.DeadlineExceeded); !errors.Is(err, ErrTimeout) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("esperava ErrTimeout envolvendo DeadlineExceeded: %v", err)
+	}
+	if err := FromContext("op", context.Canceled); err != context.Canceled {
+		t.Fatalf("cancelamento não deve ser classificado: %v", err)
+	}
+	if Wrap(ErrValidation, "op", nil, "ignorado") != nil {
+		t.Fatal("Wrap de erro nil deve retornar nil")
+	}
+}
+
+func TestKindForStatus(t *testing.T) {
+	cases := map[int]error{
+		http.StatusOK:                  nil,
+		http.StatusNotFound:            ErrNotFound,
+		http.StatusTooManyRequests:     ErrRateLimited,
+		http.StatusGatewayTimeout:      ErrTimeout,
+		http.StatusUnprocessableEntity: ErrValidation,
+		http.StatusInternalServerError: nil,
+	}
+	for status, want := range cases {
+		if got := KindForStatus(status); got != want {
+			t.Errorf("status %d: esperava %v, obteve %v", status, want, got)
+		}
+		if want != nil && Kind(New(want, "op", "falha")) != want {
+			t.Errorf("Kind não identificou %v", want)
+		}
+	}
+}
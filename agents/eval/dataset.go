@@ -0,0 +1,27 @@
+package eval
+
+import (
+	"context"
+
+	"github.com/suissa/HiveMind/agents/dataset"
+)
+
+// CaseFromExample converte um exemplo de dataset em caso de avaliação
+func CaseFromExample(example dataset.Example) Case {
+	return Case{ID: example.ID, Input: example.Input, Expected: example.Expected, Format: example.Format}
+}
+
+// Cases converte os exemplos do dataset em casos de avaliação
+func Cases(d *dataset.Dataset) []Case {
+	cases := make([]Case, 0, len(d.Examples))
+	for _, example := range d.Examples {
+		cases = append(cases, CaseFromExample(example))
+	}
+	return cases
+}
+
+// EvaluateDataset avalia o agente sobre o dataset; a suíte do relatório é o nome e a
+// versão do dataset, de forma que o histórico registra sobre quais exemplos cada nota foi obtida
+func (j *Judge) EvaluateDataset(ctx context.Context, subject Subject, d *dataset.Dataset) (*Report, error) {
+	return j.Evaluate(ctx, d.String(), subject, Cases(d))
+}
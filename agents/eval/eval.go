@@ -0,0 +1,232 @@
+// Package eval avalia as respostas dos agentes com um modelo juiz (LLM-as-judge): cada
+// resposta recebe notas por critério de uma rubrica (relevância, factualidade, formato),
+// e os relatórios por agente são acumulados ao longo do tempo para acompanhar a evolução.
+package eval
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/suissa/HiveMind/agents/llm"
+)
+
+// Criterion é um critério da rubrica avaliado pelo juiz com nota de 0 a 10
+type Criterion struct {
+	Name        string
+	Description string
+	Weight      float64 // Peso na nota geral (zero equivale a 1)
+}
+
+// Critérios padrão
+var (
+	Relevance = Criterion{
+		Name:        "relevance",
+		Description: "a resposta trata diretamente do que foi pedido",
+	}
+	Factuality = Criterion{
+		Name:        "factuality",
+		Description: "as afirmações são corretas e consistentes com a referência, sem invenções",
+	}
+	Format = Criterion{
+		Name:        "format",
+		Description: "a resposta segue o formato solicitado e é clara",
+	}
+)
+
+// DefaultRubric é a rubrica usada quando nenhuma é informada
+var DefaultRubric = []Criterion{Relevance, Factuality, Format}
+
+// Case é um caso de avaliação
+type Case struct {
+	ID       string `json:"id"`
+	Input    string `json:"input"`
+	Expected string `json:"expected,omitempty"` // Resposta de referência (opcional)
+	Format   string `json:"format,omitempty"`   // Formato esperado (opcional)
+}
+
+// Score contém as notas de uma resposta, normalizadas entre 0 e 1
+type Score struct {
+	Criteria  map[string]float64 `json:"criteria"`
+	Overall   float64            `json:"overall"`
+	Reasoning string             `json:"reasoning,omitempty"`
+}
+
+// judgeSystem instrui o juiz a responder apenas com JSON
+const judgeSystem = `Você é um avaliador rigoroso de respostas de agentes de IA.
+Dê a cada critério uma nota inteira de 0 a 10 e responda apenas com um objeto JSON
+no formato {"scores": {"<critério>": <nota>}, "reasoning": "<justificativa curta>"}.`
+
+// Judge avalia respostas com um LLM segundo uma rubrica
+type Judge struct {
+	provider llm.Provider
+	model    string
+	rubric   []Criterion
+}
+
+// NewJudge cria um juiz com o provedor e o modelo informados (DefaultRubric se vazia)
+func NewJudge(provider llm.Provider, model string, rubric ...Criterion) *Judge {
+	if len(rubric) == 0 {
+		rubric = DefaultRubric
+	}
+	return &Judge{provider: provider, model: model, rubric: rubric}
+}
+
+// Score avalia a resposta dada a um caso
+func (j *Judge) Score(ctx context.Context, c Case, output string) (Score, error) {
+	resp, err := j.provider.Complete(ctx, llm.Request{
+		Model:       j.model,
+		System:      judgeSystem,
+		Prompt:      j.prompt(c, output),
+		Temperature: 0,
+	})
+	if err != nil {
+		return Score{}, fmt.Errorf("erro na chamada ao juiz: %w", err)
+	}
+	return j.parse(resp.Text)
+}
+
+// prompt monta a solicitação de avaliação
+func (j *Judge) prompt(c Case, output string) string {
+	var b strings.Builder
+	b.WriteString("Critérios:\n")
+	for _, criterion := range j.rubric {
+		fmt.Fprintf(&b, "- %s: %s\n", criterion.Name, criterion.Description)
+	}
+	fmt.Fprintf(&b, "\nPergunta:\n%s\n", c.Input)
+	if c.Expected != "" {
+		fmt.Fprintf(&b, "\nResposta de referência:\n%s\n", c.Expected)
+	}
+	if c.Format != "" {
+		fmt.Fprintf(&b, "\nFormato esperado:\n%s\n", c.Format)
+	}
+	fmt.Fprintf(&b, "\nResposta do agente:\n%s\n", output)
+	return b.String()
+}
+
+// parse extrai as notas do JSON devolvido pelo juiz, tolerando texto ou blocos de código ao redor
+func (j *Judge) parse(text string) (Score, error) {
+	start, end := strings.Index(text, "{"), strings.LastIndex(text, "}")
+	if start < 0 || end < start {
+		return Score{}, fmt.Errorf("resposta do juiz sem JSON: %q", text)
+	}
+
+	var verdict struct {
+		Scores    map[string]float64 `json:"scores"`
+		Reasoning string             `json:"reasoning"`
+	}
+	if err := json.Unmarshal([]byte(text[start:end+1]), &verdict); err != nil {
+		return Score{}, fmt.Errorf("resposta do juiz inválida: %v", err)
+	}
+
+	score := Score{Criteria: make(map[string]float64, len(j.rubric)), Reasoning: verdict.Reasoning}
+	var total, weights float64
+	for _, criterion := range j.rubric {
+		value, ok := verdict.Scores[criterion.Name]
+		if !ok {
+			return Score{}, fmt.Errorf("juiz não avaliou o critério %s", criterion.Name)
+		}
+		value = clamp(value/10, 0, 1)
+		score.Criteria[criterion.Name] = value
+
+		weight := criterion.Weight
+		if weight <= 0 {
+			weight = 1
+		}
+		total += value * weight
+		weights += weight
+	}
+	score.Overall = total / weights
+	return score, nil
+}
+
+// clamp limita o valor ao intervalo
+func clamp(value, min, max float64) float64 {
+	if value < min {
+		return min
+	}
+	if value > max {
+		return max
+	}
+	return value
+}
+
+// Subject é o agente avaliado
+type Subject interface {
+	GetID() string
+	Complete(ctx context.Context, prompt string) (string, error)
+}
+
+// ScoreRecorder é implementado pelos agentes que registram as notas médias de uma avaliação
+// (ex.: em PerformanceStats)
+type ScoreRecorder interface {
+	RecordScores(scores map[string]float64)
+}
+
+// Result é o resultado de um caso
+type Result struct {
+	CaseID string `json:"case_id"`
+	Output string `json:"output"`
+	Score  Score  `json:"score"`
+	Error  string `json:"error,omitempty"`
+}
+
+// Report é o relatório de uma avaliação de um agente sobre uma suíte de casos
+type Report struct {
+	Suite     string             `json:"suite"`
+	AgentID   string             `json:"agent_id"`
+	Model     string             `json:"judge_model"`
+	Timestamp time.Time          `json:"timestamp"`
+	Results   []Result           `json:"results"`
+	Averages  map[string]float64 `json:"averages"` // Média por critério e "overall"
+	Failures  int                `json:"failures"`
+}
+
+// Evaluate executa o agente em cada caso e avalia as respostas. Casos com erro contam
+// como nota zero. Se o agente implementa ScoreRecorder, as médias são registradas nele.
+func (j *Judge) Evaluate(ctx context.Context, suite string, subject Subject, cases []Case) (*Report, error) {
+	report := &Report{
+		Suite:     suite,
+		AgentID:   subject.GetID(),
+		Model:     j.model,
+		Timestamp: time.Now(),
+		Results:   make([]Result, 0, len(cases)),
+		Averages:  make(map[string]float64),
+	}
+
+	for _, c := range cases {
+		if err := ctx.Err(); err != nil {
+			return report, err
+		}
+
+		result := Result{CaseID: c.ID}
+		output, err := subject.Complete(ctx, c.Input)
+		if err == nil {
+			result.Output = output
+			result.Score, err = j.Score(ctx, c, output)
+		}
+		if err != nil {
+			result.Error = err.Error()
+			report.Failures++
+		}
+		report.Results = append(report.Results, result)
+
+		for name, value := range result.Score.Criteria {
+			report.Averages[name] += value
+		}
+		report.Averages["overall"] += result.Score.Overall
+	}
+
+	if len(cases) > 0 {
+		for name := range report.Averages {
+			report.Averages[name] /= float64(len(cases))
+		}
+	}
+
+	if recorder, ok := subject.(ScoreRecorder); ok {
+		recorder.RecordScores(report.Averages)
+	}
+	return report, nil
+}
@@ -0,0 +1,77 @@
+package eval
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/suissa/HiveMind/agents/dataset"
+	"github.com/suissa/HiveMind/agents/llm"
+)
+
+// subject é um agente simulado que registra as notas recebidas
+type subject struct {
+	answers map[string]string
+	scores  map[string]float64
+}
+
+func (s *subject) GetID() string { return "agent-1" }
+
+func (s *subject) Complete(ctx context.Context, prompt string) (string, error) {
+	return s.answers[prompt], nil
+}
+
+func (s *subject) RecordScores(scores map[string]float64) { s.scores = scores }
+
+func TestJudgeParsesVerdict(t *testing.T) {
+	judge := NewJudge(llm.ProviderFunc(func(ctx context.Context, req llm.Request) (*llm.Response, error) {
+		return &llm.Response{Text: "```json\n{\"scores\": {\"relevance\": 10, \"factuality\": 5, \"format\": 15}, \"reasoning\": \"ok\"}\n```"}, nil
+	}), "judge")
+
+	score, err := judge.Score(context.Background(), Case{ID: "c1", Input: "pergunta"}, "resposta")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if score.Criteria["relevance"] != 1 || score.Criteria["factuality"] != 0.5 || score.Criteria["format"] != 1 {
+		t.Fatalf("notas inesperadas: %#v", score.Criteria)
+	}
+	if want := 2.5 / 3; score.Overall != want {
+		t.Fatalf("nota geral %.3f, esperado %.3f", score.Overall, want)
+	}
+}
+
+func TestEvaluateRecordsScoresAndHistory(t *testing.T) {
+	judge := NewJudge(llm.ProviderFunc(func(ctx context.Context, req llm.Request) (*llm.Response, error) {
+		return &llm.Response{Text: `{"scores": {"relevance": 8}}`}, nil
+	}), "judge", Relevance)
+
+	agent := &subject{answers: map[string]string{"a": "resposta a", "b": "resposta b"}}
+	suite := dataset.New("smoke", []dataset.Example{{Input: "a"}, {Input: "b"}})
+	report, err := judge.EvaluateDataset(context.Background(), agent, suite)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Suite != suite.String() || len(report.Results) != 2 {
+		t.Fatalf("relatório inesperado: %s com %d resultados", report.Suite, len(report.Results))
+	}
+	if report.Averages["overall"] != 0.8 || agent.scores["relevance"] != 0.8 {
+		t.Fatalf("médias inesperadas: %#v / %#v", report.Averages, agent.scores)
+	}
+
+	history, err := NewHistory(filepath.Join(t.TempDir(), "evals", "history.jsonl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		if err := history.Append(report); err != nil {
+			t.Fatal(err)
+		}
+	}
+	reports, err := history.Reports("agent-1")
+	if err != nil || len(reports) != 2 {
+		t.Fatalf("esperava 2 relatórios, obteve %d (%v)", len(reports), err)
+	}
+	if others, _ := history.Reports("agent-2"); len(others) != 0 {
+		t.Fatal("relatórios de outro agente não devem ser retornados")
+	}
+}
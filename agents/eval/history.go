@@ -0,0 +1,82 @@
+package eval
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+)
+
+// History acumula os relatórios de avaliação em um arquivo JSONL (um relatório por linha),
+// permitindo acompanhar a evolução de cada agente ao longo do tempo
+type History struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewHistory cria o histórico no arquivo informado, criando o diretório se necessário
+func NewHistory(path string) (*History, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("erro ao criar diretório do histórico: %v", err)
+	}
+	return &History{path: path}, nil
+}
+
+// Append adiciona um relatório ao histórico
+func (h *History) Append(report *Report) error {
+	data, err := json.Marshal(report)
+	if err != nil {
+		return fmt.Errorf("erro ao serializar relatório: %v", err)
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	file, err := os.OpenFile(h.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("erro ao abrir histórico: %v", err)
+	}
+	defer file.Close()
+
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("erro ao gravar histórico: %v", err)
+	}
+	return nil
+}
+
+// Reports retorna os relatórios do agente em ordem cronológica (todos, com agentID vazio)
+func (h *History) Reports(agentID string) ([]*Report, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	file, err := os.Open(h.path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("erro ao abrir histórico: %v", err)
+	}
+	defer file.Close()
+
+	var reports []*Report
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		var report Report
+		if err := json.Unmarshal(scanner.Bytes(), &report); err != nil {
+			return nil, fmt.Errorf("relatório inválido no histórico: %v", err)
+		}
+		if agentID == "" || report.AgentID == agentID {
+			reports = append(reports, &report)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao ler histórico: %v", err)
+	}
+
+	sort.SliceStable(reports, func(i, j int) bool { return reports[i].Timestamp.Before(reports[j].Timestamp) })
+	return reports, nil
+}
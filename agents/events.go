@@ -1,19 +1,32 @@
 package agents
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
 	"time"
+
+	"github.com/suissa/HiveMind/agents/i18n"
+	"github.com/suissa/HiveMind/agents/redact"
+	"github.com/suissa/HiveMind/agents/supervisor"
 )
 
 // EventType representa o tipo de evento
 type EventType string
 
 const (
-	EventAgentAction     EventType = "agent_action"
-	EventTaskUpdate      EventType = "task_update"
-	EventWorkflowUpdate  EventType = "workflow_update"
-	EventProjectUpdate   EventType = "project_update"
-	EventMemoryOperation EventType = "memory_operation"
+	EventAgentAction       EventType = "agent_action"
+	EventTaskUpdate        EventType = "task_update"
+	EventWorkflowUpdate    EventType = "workflow_update"
+	EventProjectUpdate     EventType = "project_update"
+	EventMemoryOperation   EventType = "memory_operation"
+	EventMemoryMaintenance EventType = "memory_maintenance"
+	EventToolCall          EventType = "tool_call"
+	EventToolDenied        EventType = "tool_denied"
+	EventModeration        EventType = "moderation"
+	EventError             EventType = "error"
 )
 
 // Event representa um evento no sistema
@@ -22,6 +35,23 @@ type Event struct {
 	Timestamp time.Time              `json:"timestamp"`
 	Source    string                 `json:"source"`
 	Data      map[string]interface{} `json:"data"`
+	Message   string                 `json:"message,omitempty"` // Descrição legível, no idioma do emissor
+}
+
+// localize preenche a mensagem legível a partir do catálogo do idioma, usando a chave
+// "event.<tipo>.<ação>" (ou "event.<tipo>" para eventos sem ação) e os dados do evento
+func (e Event) localize(locale i18n.Locale) Event {
+	if e.Message != "" {
+		return e
+	}
+	key := "event." + string(e.Type)
+	if action, ok := e.Data["action"].(string); ok {
+		key += "." + action
+	}
+	if message, ok := i18n.Render(locale, key, e.Data); ok {
+		e.Message = message
+	}
+	return e
 }
 
 // ToJSON converte o evento para uma string JSON formatada
@@ -42,6 +72,8 @@ type EventListener func(Event)
 // EventEmitter gerencia a emissão e escuta de eventos
 type EventEmitter struct {
 	listeners map[EventType][]EventListener
+	locale    i18n.Locale    // Idioma das mensagens; vazio usa o padrão do i18n
+	pending   sync.WaitGroup // Listeners em execução, aguardados por Flush
 }
 
 // NewEventEmitter cria um novo emissor de eventos
@@ -51,6 +83,11 @@ func NewEventEmitter() *EventEmitter {
 	}
 }
 
+// SetLocale define o idioma das mensagens dos eventos emitidos
+func (e *EventEmitter) SetLocale(locale i18n.Locale) {
+	e.locale = locale
+}
+
 // On registra um listener para um tipo específico de evento
 func (e *EventEmitter) On(eventType EventType, listener EventListener) {
 	e.listeners[eventType] = append(e.listeners[eventType], listener)
@@ -62,18 +99,73 @@ func (e *EventEmitter) OnAny(listener EventListener) {
 		EventAgentAction,
 		EventTaskUpdate,
 		EventMemoryOperation,
+		EventMemoryMaintenance,
 		EventWorkflowUpdate,
 		EventProjectUpdate,
+		EventToolCall,
+		EventToolDenied,
+		EventModeration,
+		EventError,
 	} {
 		e.On(eventType, listener)
 	}
 }
 
-// Emit emite um evento para todos os listeners registrados
+// Emit emite um evento para todos os listeners registrados.
+// Segredos e dados pessoais são mascarados antes da entrega.
 func (e *EventEmitter) Emit(event Event) {
+	event.Data = redact.Map(event.Data)
+	locale := e.locale
+	if locale == "" {
+		locale = i18n.Default()
+	}
+	event = event.localize(locale)
 	if listeners, ok := e.listeners[event.Type]; ok {
 		for _, listener := range listeners {
-			go listener(event)
+			e.pending.Add(1)
+			go func(listener EventListener) {
+				defer e.pending.Done()
+				// Um listener com panic não derruba o processo; a falha vira um evento de erro
+				err := supervisor.Default.Protect("event_listener:"+string(event.Type), func() error {
+					listener(event)
+					return nil
+				})
+				var failure supervisor.Failure
+				if errors.As(err, &failure) && event.Type != EventError {
+					e.ReportFailure(failure)
+				}
+			}(listener)
 		}
 	}
 }
+
+// ReportFailure emite um evento de erro para uma falha recuperada.
+// Pode ser registrado como supervisor.Reporter.
+func (e *EventEmitter) ReportFailure(failure supervisor.Failure) {
+	e.Emit(Event{
+		Type:      EventError,
+		Timestamp: failure.Time,
+		Source:    failure.Component,
+		Data: map[string]interface{}{
+			"error":    failure.Error(),
+			"restarts": failure.Restarts,
+			"stack":    failure.Stack,
+		},
+	})
+}
+
+// Flush aguarda a entrega dos eventos já emitidos ou o cancelamento do contexto
+func (e *EventEmitter) Flush(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		e.pending.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("eventos pendentes não foram entregues: %v", ctx.Err())
+	}
+}
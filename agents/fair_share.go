@@ -0,0 +1,33 @@
+package agents
+
+import (
+	"context"
+
+	"github.com/suissa/HiveMind/agents/fairshare"
+	"github.com/suissa/HiveMind/agents/llm"
+	"github.com/suissa/HiveMind/agents/memory"
+	"github.com/suissa/HiveMind/agents/tenant"
+)
+
+// FairShareFlow identifica o fluxo do contexto no escalonamento justo: o tenant
+// (tenant.WithTenant) e o workflow em execução (o projeto de ExecuteWorkflow)
+func FairShareFlow(ctx context.Context) fairshare.Flow {
+	return fairshare.Flow{
+		Tenant:   tenant.FromContext(ctx),
+		Workflow: memory.ProvenanceFromContext(ctx).WorkflowID,
+	}
+}
+
+// FairShareLLM reparte a cota do provedor de LLM entre os tenants e os workflows: cada
+// chamada aguarda a vez do seu fluxo no escalonador. Use um escalonador próprio para as
+// chamadas ao LLM, separado do das tarefas, já que as chamadas acontecem dentro delas.
+func FairShareLLM(scheduler *fairshare.Scheduler, provider llm.Provider) llm.Provider {
+	return llm.ProviderFunc(func(ctx context.Context, req llm.Request) (*llm.Response, error) {
+		release, err := scheduler.Acquire(ctx, FairShareFlow(ctx))
+		if err != nil {
+			return nil, err
+		}
+		defer release()
+		return provider.Complete(ctx, req)
+	})
+}
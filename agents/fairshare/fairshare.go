@@ -0,0 +1,265 @@
+// Package fairshare reparte a capacidade de despacho (tarefas ou chamadas ao LLM) entre os
+// tenants e os workflows que rodam ao mesmo tempo, com cotas ponderadas. A escolha é feita em
+// dois níveis por stride scheduling: primeiro o tenant, pela sua cota, e depois o workflow
+// dentro dele. Assim um workflow com muitas tarefas não esgota a capacidade dos demais.
+package fairshare
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/suissa/HiveMind/agents/errs"
+)
+
+// DefaultShare é a cota dos tenants e workflows não configurados
+const DefaultShare = 1.0
+
+// Flow identifica quem disputa a capacidade
+type Flow struct {
+	Tenant   string
+	Workflow string
+}
+
+// Config define a capacidade compartilhada e as cotas
+type Config struct {
+	Capacity  int                `json:"capacity,omitempty" yaml:"capacity,omitempty"`   // Despachos simultâneos; zero não limita
+	Rate      float64            `json:"rate,omitempty" yaml:"rate,omitempty"`           // Despachos por segundo; zero não limita
+	Tenants   map[string]float64 `json:"tenants,omitempty" yaml:"tenants,omitempty"`     // Cota de cada tenant
+	Workflows map[string]float64 `json:"workflows,omitempty" yaml:"workflows,omitempty"` // Cota de cada workflow dentro do seu tenant
+}
+
+// Validate verifica a capacidade e as cotas
+func (c Config) Validate() error {
+	if c.Capacity < 0 || c.Rate < 0 {
+		return errs.New(errs.ErrValidation, "fairshare.Config", "capacity e rate não podem ser negativos")
+	}
+	for name, share := range c.Tenants {
+		if share <= 0 {
+			return errs.New(errs.ErrValidation, "fairshare.Config", "cota do tenant %s deve ser positiva: %v", name, share)
+		}
+	}
+	for name, share := range c.Workflows {
+		if share <= 0 {
+			return errs.New(errs.ErrValidation, "fairshare.Config", "cota do workflow %s deve ser positiva: %v", name, share)
+		}
+	}
+	return nil
+}
+
+// FlowStats é o estado de um fluxo no escalonador
+type FlowStats struct {
+	Running int `json:"running"`
+	Waiting int `json:"waiting"`
+}
+
+// node é um tenant ou um workflow. O passo (pass) avança 1/share a cada despacho; o nó com
+// menor passo é o próximo atendido.
+type node struct {
+	name     string
+	share    float64
+	pass     float64
+	running  int
+	waiting  int              // Tenant: espera somada dos seus workflows
+	vtime    float64          // Tenant: passo do último workflow atendido
+	children map[string]*node // Tenant: workflows
+	queue    []*waiter        // Workflow: pedidos em ordem de chegada
+}
+
+// waiter é um pedido de despacho aguardando a vez
+type waiter struct {
+	ready    chan struct{}
+	granted  bool
+	tenant   *node
+	workflow *node
+}
+
+// Scheduler concede os despachos aos fluxos respeitando a capacidade, a taxa e as cotas
+type Scheduler struct {
+	config   Config
+	interval time.Duration
+	tenants  map[string]*node
+	vtime    float64 // Passo do último tenant atendido
+	running  int
+	next     time.Time // Próximo despacho permitido pela taxa
+	timer    *time.Timer
+	mu       sync.Mutex
+}
+
+// New cria um escalonador com a configuração informada
+func New(config Config) (*Scheduler, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	s := &Scheduler{config: config, tenants: make(map[string]*node)}
+	if config.Rate > 0 {
+		s.interval = time.Duration(float64(time.Second) / config.Rate)
+	}
+	return s, nil
+}
+
+// Acquire aguarda a vez do fluxo e retorna a função que devolve a capacidade ao fim do
+// despacho. Se o contexto terminar antes, o pedido sai da fila com o erro do contexto.
+func (s *Scheduler) Acquire(ctx context.Context, flow Flow) (release func(), err error) {
+	s.mu.Lock()
+	w := &waiter{ready: make(chan struct{})}
+	w.tenant, w.workflow = s.nodes(flow)
+	// Fluxos ociosos não acumulam crédito: voltam a disputar a partir do passo atual
+	if w.tenant.waiting == 0 && w.tenant.pass < s.vtime {
+		w.tenant.pass = s.vtime
+	}
+	if len(w.workflow.queue) == 0 && w.workflow.pass < w.tenant.vtime {
+		w.workflow.pass = w.tenant.vtime
+	}
+	w.workflow.queue = append(w.workflow.queue, w)
+	w.tenant.waiting++
+	s.dispatchLocked()
+	s.mu.Unlock()
+
+	select {
+	case <-w.ready:
+		return s.releaser(w), nil
+	case <-ctx.Done():
+		s.mu.Lock()
+		granted := w.granted
+		if !granted {
+			s.removeLocked(w)
+		}
+		s.mu.Unlock()
+		if granted {
+			s.releaser(w)()
+		}
+		return nil, errs.FromContext("fairshare.Acquire", ctx.Err())
+	}
+}
+
+// Stats retorna o estado dos fluxos ativos
+func (s *Scheduler) Stats() map[Flow]FlowStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stats := make(map[Flow]FlowStats)
+	for _, t := range s.tenants {
+		for _, w := range t.children {
+			stats[Flow{Tenant: t.name, Workflow: w.name}] = FlowStats{Running: w.running, Waiting: len(w.queue)}
+		}
+	}
+	return stats
+}
+
+// nodes retorna os nós do tenant e do workflow, criando-os se necessário. Deve ser chamado
+// com s.mu travado.
+func (s *Scheduler) nodes(flow Flow) (*node, *node) {
+	t, ok := s.tenants[flow.Tenant]
+	if !ok {
+		t = &node{name: flow.Tenant, share: share(s.config.Tenants, flow.Tenant), children: make(map[string]*node)}
+		s.tenants[flow.Tenant] = t
+	}
+	w, ok := t.children[flow.Workflow]
+	if !ok {
+		w = &node{name: flow.Workflow, share: share(s.config.Workflows, flow.Workflow)}
+		t.children[flow.Workflow] = w
+	}
+	return t, w
+}
+
+// share retorna a cota configurada ou DefaultShare
+func share(shares map[string]float64, name string) float64 {
+	if value, ok := shares[name]; ok {
+		return value
+	}
+	return DefaultShare
+}
+
+// next retorna o nó pronto de menor passo; o empate é decidido pelo nome
+func next(nodes map[string]*node, ready func(*node) bool) *node {
+	var best *node
+	for _, n := range nodes {
+		if !ready(n) {
+			continue
+		}
+		if best == nil || n.pass < best.pass || (n.pass == best.pass && n.name < best.name) {
+			best = n
+		}
+	}
+	return best
+}
+
+// dispatchLocked concede a vez aos pedidos enquanto houver capacidade e a taxa permitir.
+// Deve ser chamado com s.mu travado.
+func (s *Scheduler) dispatchLocked() {
+	for s.config.Capacity == 0 || s.running < s.config.Capacity {
+		t := next(s.tenants, func(n *node) bool { return n.waiting > 0 })
+		if t == nil {
+			return
+		}
+		if s.interval > 0 {
+			now := time.Now()
+			if now.Before(s.next) {
+				if s.timer == nil {
+					s.timer = time.AfterFunc(s.next.Sub(now), func() {
+						s.mu.Lock()
+						s.timer = nil
+						s.dispatchLocked()
+						s.mu.Unlock()
+					})
+				}
+				return
+			}
+			s.next = now.Add(s.interval)
+		}
+
+		w := next(t.children, func(n *node) bool { return len(n.queue) > 0 })
+		granted := w.queue[0]
+		w.queue = w.queue[1:]
+		t.waiting--
+
+		s.vtime = t.pass
+		t.pass += 1 / t.share
+		t.vtime = w.pass
+		w.pass += 1 / w.share
+		s.running++
+		t.running++
+		w.running++
+		granted.granted = true
+		close(granted.ready)
+	}
+}
+
+// releaser retorna a função que devolve a capacidade do pedido; chamadas repetidas são ignoradas
+func (s *Scheduler) releaser(w *waiter) func() {
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			s.running--
+			w.tenant.running--
+			w.workflow.running--
+			s.pruneLocked(w)
+			s.dispatchLocked()
+		})
+	}
+}
+
+// removeLocked retira da fila um pedido que desistiu. Deve ser chamado com s.mu travado.
+func (s *Scheduler) removeLocked(w *waiter) {
+	for i, queued := range w.workflow.queue {
+		if queued == w {
+			w.workflow.queue = append(w.workflow.queue[:i], w.workflow.queue[i+1:]...)
+			w.tenant.waiting--
+			break
+		}
+	}
+	s.pruneLocked(w)
+}
+
+// pruneLocked descarta os nós sem pedidos nem despachos em andamento. Deve ser chamado com
+// s.mu travado.
+func (s *Scheduler) pruneLocked(w *waiter) {
+	if w.workflow.running == 0 && len(w.workflow.queue) == 0 {
+		delete(w.tenant.children, w.workflow.name)
+	}
+	if len(w.tenant.children) == 0 {
+		delete(s.tenants, w.tenant.name)
+	}
+}
@@ -0,0 +1,165 @@
+package fairshare
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/suissa/HiveMind/agents/errs"
+)
+
+// waitQueued aguarda até o escalonador ter n pedidos na fila
+func waitQueued(t *testing.T, s *Scheduler, n int) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		waiting := 0
+		for _, stats := range s.Stats() {
+			waiting += stats.Waiting
+		}
+		if waiting == n {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("esperava %d pedidos na fila: %v", n, s.Stats())
+}
+
+func TestWeightedShares(t *testing.T) {
+	s, err := New(Config{Capacity: 1, Tenants: map[string]float64{"acme": 3}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	hold, err := s.Acquire(context.Background(), Flow{Tenant: "setup"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		order []string
+		mu    sync.Mutex
+		wg    sync.WaitGroup
+	)
+	for _, tenant := range []string{"acme", "globex"} {
+		for n := 0; n < 8; n++ {
+			wg.Add(1)
+			go func(tenant string) {
+				defer wg.Done()
+				release, err := s.Acquire(context.Background(), Flow{Tenant: tenant, Workflow: "campanha"})
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				mu.Lock()
+				order = append(order, tenant)
+				mu.Unlock()
+				release()
+			}(tenant)
+		}
+	}
+	waitQueued(t, s, 16)
+	hold()
+	wg.Wait()
+
+	acme := 0
+	for _, tenant := range order[:8] {
+		if tenant == "acme" {
+			acme++
+		}
+	}
+	if acme != 6 {
+		t.Fatalf("acme recebeu %d dos primeiros 8 despachos; esperava 6 (cota 3:1): %v", acme, order)
+	}
+}
+
+func TestWorkflowsShareTenant(t *testing.T) {
+	s, err := New(Config{Capacity: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	hold, _ := s.Acquire(context.Background(), Flow{Tenant: "acme", Workflow: "setup"})
+
+	var (
+		order []string
+		mu    sync.Mutex
+		wg    sync.WaitGroup
+	)
+	// Um workflow com muitas tarefas não bloqueia o outro workflow nem o outro tenant
+	flows := []Flow{{"acme", "grande"}, {"acme", "grande"}, {"acme", "grande"}, {"acme", "grande"}, {"acme", "pequeno"}, {"globex", "w"}}
+	for _, flow := range flows {
+		wg.Add(1)
+		go func(flow Flow) {
+			defer wg.Done()
+			release, err := s.Acquire(context.Background(), flow)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			mu.Lock()
+			order = append(order, flow.Tenant+"/"+flow.Workflow)
+			mu.Unlock()
+			release()
+		}(flow)
+	}
+	waitQueued(t, s, len(flows))
+	hold()
+	wg.Wait()
+
+	first := map[string]bool{}
+	for _, name := range order[:3] {
+		first[name] = true
+	}
+	if !first["acme/pequeno"] || !first["globex/w"] {
+		t.Fatalf("os fluxos menores deveriam ser atendidos entre os primeiros: %v", order)
+	}
+}
+
+func TestAcquireCanceled(t *testing.T) {
+	s, err := New(Config{Capacity: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	hold, _ := s.Acquire(context.Background(), Flow{Tenant: "acme"})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := s.Acquire(ctx, Flow{Tenant: "globex"}); !errors.Is(err, errs.ErrTimeout) {
+		t.Fatalf("esperava ErrTimeout: %v", err)
+	}
+	if stats := s.Stats(); len(stats) != 1 {
+		t.Fatalf("o pedido cancelado deveria sair da fila: %v", stats)
+	}
+	hold()
+	hold()
+	if stats := s.Stats(); len(stats) != 0 {
+		t.Fatalf("esperava o escalonador vazio: %v", stats)
+	}
+}
+
+func TestRate(t *testing.T) {
+	s, err := New(Config{Rate: 50})
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Now()
+	for n := 0; n < 3; n++ {
+		release, err := s.Acquire(context.Background(), Flow{Tenant: "acme"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		release()
+	}
+	if elapsed := time.Since(start); elapsed < 40*time.Millisecond {
+		t.Fatalf("3 despachos a 50/s levaram %v", elapsed)
+	}
+}
+
+func TestConfigValidate(t *testing.T) {
+	for _, config := range []Config{{Capacity: -1}, {Tenants: map[string]float64{"acme": 0}}, {Workflows: map[string]float64{"w": -2}}} {
+		if _, err := New(config); !errors.Is(err, errs.ErrValidation) {
+			t.Errorf("New(%+v) deveria falhar com ErrValidation: %v", config, err)
+		}
+	}
+}
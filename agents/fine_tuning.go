@@ -0,0 +1,98 @@
+package agents
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/suissa/HiveMind/agents/dataset"
+	"github.com/suissa/HiveMind/agents/errs"
+	"github.com/suissa/HiveMind/agents/finetune"
+	"github.com/suissa/HiveMind/agents/memory"
+	"github.com/suissa/HiveMind/agents/simulation"
+)
+
+// minFineTuneExamples é a quantidade mínima de exemplos exigida pela OpenAI
+const minFineTuneExamples = 10
+
+// FineTuneConfig configura o fine-tuning de um agente
+type FineTuneConfig struct {
+	Dataset      *dataset.Dataset // Exemplos adicionais aos do agente (opcional)
+	BaseModel    string           // Modelo base (padrão: o modelo atual do agente)
+	Suffix       string           // Sufixo do nome do modelo ajustado
+	PollInterval time.Duration    // Intervalo entre consultas ao job (padrão finetune.DefaultPollInterval)
+	MinExamples  int              // Mínimo de exemplos para enviar o job (padrão 10)
+}
+
+// ExportFineTuning converte os exemplos curados do agente (config.Dataset, SetTrainingData e
+// o feedback armazenado com RecordFeedback) em registros de chat, com o backstory como system
+func (a *CognitiveAgent) ExportFineTuning(ctx context.Context, config FineTuneConfig) ([]finetune.Record, error) {
+	data, err := a.trainingDataset(ctx, TrainingConfig{Dataset: config.Dataset})
+	if err != nil {
+		return nil, err
+	}
+	return finetune.FromExamples(a.Backstory, data.Examples), nil
+}
+
+// FineTune exporta os exemplos curados, envia o job de fine-tuning e aguarda sua conclusão.
+// Em caso de sucesso o agente passa a usar o modelo ajustado. No modo dry-run o job
+// apenas é registrado na sessão de simulação e o modelo não muda.
+func (a *CognitiveAgent) FineTune(ctx context.Context, client finetune.Client, config FineTuneConfig) (*finetune.Job, error) {
+	records, err := a.ExportFineTuning(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+
+	minExamples := config.MinExamples
+	if minExamples <= 0 {
+		minExamples = minFineTuneExamples
+	}
+	if len(records) < minExamples {
+		return nil, errs.New(errs.ErrValidation, "agent.FineTune",
+			"agente %s tem %d exemplos para fine-tuning, mínimo %d", a.GetID(), len(records), minExamples)
+	}
+
+	baseModel := config.BaseModel
+	if baseModel == "" {
+		baseModel = a.Model
+	}
+
+	if session, ok := simulation.FromContext(ctx); ok {
+		session.Record(simulation.EffectFineTune, baseModel, map[string]interface{}{
+			"examples": len(records),
+			"suffix":   config.Suffix,
+		})
+		return &finetune.Job{Model: baseModel, Status: finetune.StatusQueued}, nil
+	}
+
+	job, err := finetune.Run(ctx, client, records, finetune.Options{
+		Model:        baseModel,
+		Suffix:       config.Suffix,
+		PollInterval: config.PollInterval,
+		OnStatus: func(job *finetune.Job) {
+			log.Printf("🎛️ Agente %s: job de fine-tuning %s %s", a.GetID(), job.ID, job.Status)
+		},
+	})
+	if err != nil {
+		return job, fmt.Errorf("erro no fine-tuning do agente %s: %w", a.GetID(), err)
+	}
+
+	a.Model = job.FineTunedModel
+	a.AgentStruct.Model = job.FineTunedModel
+
+	if a.memoryManager != nil {
+		if err := a.memoryManager.StoreMemory(a.scope(ctx), &memory.Memory{
+			ID:         fmt.Sprintf("fine_tune_%s_%s", a.GetID(), job.ID),
+			AgentID:    a.GetID(),
+			Type:       memory.LongTerm,
+			Content:    fmt.Sprintf("Modelo ajustado %s a partir de %s com %d exemplos (job %s)", job.FineTunedModel, baseModel, len(records), job.ID),
+			Importance: 1,
+			Timestamp:  time.Now(),
+			Tags:       []string{"fine_tune", "model"},
+		}); err != nil {
+			log.Printf("⚠️ Agente %s: erro ao registrar fine-tuning na memória: %v", a.GetID(), err)
+		}
+	}
+	return job, nil
+}
@@ -0,0 +1,187 @@
+// Package finetune orquestra jobs de fine-tuning nos provedores de LLM: exporta os exemplos
+// curados de um agente no formato de chat do provedor (JSONL), envia o arquivo, cria o job
+// e acompanha o status até o modelo ajustado ficar disponível.
+package finetune
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/suissa/HiveMind/agents/dataset"
+	"github.com/suissa/HiveMind/agents/errs"
+)
+
+// DefaultPollInterval é o intervalo padrão entre consultas ao status do job
+const DefaultPollInterval = 30 * time.Second
+
+// Message é uma mensagem de um exemplo de chat
+type Message struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// Record é uma linha do arquivo de treinamento no formato de chat da OpenAI
+type Record struct {
+	Messages []Message `json:"messages"`
+}
+
+// FromExamples converte exemplos rotulados em registros de chat (system, user, assistant);
+// exemplos sem resposta esperada são ignorados
+func FromExamples(system string, examples []dataset.Example) []Record {
+	records := make([]Record, 0, len(examples))
+	for _, example := range examples {
+		if strings.TrimSpace(example.Expected) == "" {
+			continue
+		}
+
+		var messages []Message
+		if system != "" {
+			messages = append(messages, Message{Role: "system", Content: system})
+		}
+		messages = append(messages,
+			Message{Role: "user", Content: example.Input},
+			Message{Role: "assistant", Content: example.Expected},
+		)
+		records = append(records, Record{Messages: messages})
+	}
+	return records
+}
+
+// WriteJSONL grava um registro por linha
+func WriteJSONL(w io.Writer, records []Record) error {
+	encoder := json.NewEncoder(w)
+	for _, record := range records {
+		if err := encoder.Encode(record); err != nil {
+			return fmt.Errorf("erro ao serializar registro de fine-tuning: %v", err)
+		}
+	}
+	return nil
+}
+
+// Status de um job de fine-tuning
+const (
+	StatusValidating = "validating_files"
+	StatusQueued     = "queued"
+	StatusRunning    = "running"
+	StatusSucceeded  = "succeeded"
+	StatusFailed     = "failed"
+	StatusCancelled  = "cancelled"
+)
+
+// Job é um job de fine-tuning no provedor
+type Job struct {
+	ID             string    `json:"id"`
+	Model          string    `json:"model"`
+	Status         string    `json:"status"`
+	FineTunedModel string    `json:"fine_tuned_model,omitempty"`
+	TrainingFile   string    `json:"training_file"`
+	Error          *JobError `json:"error,omitempty"`
+}
+
+// JobError descreve a falha de um job
+type JobError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// Done indica se o job terminou, com sucesso ou não
+func (j *Job) Done() bool {
+	switch j.Status {
+	case StatusSucceeded, StatusFailed, StatusCancelled:
+		return true
+	}
+	return false
+}
+
+// JobRequest são os parâmetros de criação de um job
+type JobRequest struct {
+	Model          string `json:"model"`
+	TrainingFile   string `json:"training_file"`
+	ValidationFile string `json:"validation_file,omitempty"`
+	Suffix         string `json:"suffix,omitempty"` // Incluído no nome do modelo ajustado
+}
+
+// Client é a API de fine-tuning de um provedor
+type Client interface {
+	// UploadFile envia o arquivo de treinamento e retorna seu ID
+	UploadFile(ctx context.Context, name string, data []byte) (string, error)
+	// CreateJob cria o job de fine-tuning
+	CreateJob(ctx context.Context, req JobRequest) (*Job, error)
+	// GetJob consulta o status do job
+	GetJob(ctx context.Context, id string) (*Job, error)
+}
+
+// Options configura a execução de um job
+type Options struct {
+	Model        string        // Modelo base
+	Suffix       string        // Sufixo do modelo ajustado (opcional)
+	PollInterval time.Duration // Intervalo entre consultas (padrão DefaultPollInterval)
+	OnStatus     func(job *Job)
+}
+
+// Run envia os registros, cria o job e aguarda sua conclusão. Retorna o job concluído
+// com sucesso ou um erro se ele falhar, for cancelado ou o contexto expirar.
+func Run(ctx context.Context, client Client, records []Record, opts Options) (*Job, error) {
+	if len(records) == 0 {
+		return nil, errs.New(errs.ErrValidation, "finetune.Run", "nenhum exemplo para fine-tuning")
+	}
+	if opts.Model == "" {
+		return nil, errs.New(errs.ErrValidation, "finetune.Run", "modelo base não informado")
+	}
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = DefaultPollInterval
+	}
+
+	var data strings.Builder
+	if err := WriteJSONL(&data, records); err != nil {
+		return nil, err
+	}
+	fileID, err := client.UploadFile(ctx, "training.jsonl", []byte(data.String()))
+	if err != nil {
+		return nil, fmt.Errorf("erro ao enviar arquivo de treinamento: %w", err)
+	}
+
+	job, err := client.CreateJob(ctx, JobRequest{Model: opts.Model, TrainingFile: fileID, Suffix: opts.Suffix})
+	if err != nil {
+		return nil, fmt.Errorf("erro ao criar job de fine-tuning: %w", err)
+	}
+
+	ticker := time.NewTicker(opts.PollInterval)
+	defer ticker.Stop()
+	for {
+		if opts.OnStatus != nil {
+			opts.OnStatus(job)
+		}
+		if job.Done() {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return job, errs.FromContext("finetune.Run", ctx.Err())
+		case <-ticker.C:
+		}
+
+		next, err := client.GetJob(ctx, job.ID)
+		if err != nil {
+			return job, fmt.Errorf("erro ao consultar job %s: %w", job.ID, err)
+		}
+		job = next
+	}
+
+	if job.Status != StatusSucceeded {
+		reason := job.Status
+		if job.Error != nil && job.Error.Message != "" {
+			reason += ": " + job.Error.Message
+		}
+		return job, fmt.Errorf("job de fine-tuning %s não concluído (%s)", job.ID, reason)
+	}
+	if job.FineTunedModel == "" {
+		return job, fmt.Errorf("job de fine-tuning %s concluído sem modelo ajustado", job.ID)
+	}
+	return job, nil
+}
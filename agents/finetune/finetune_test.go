@@ -0,0 +1,114 @@
+package finetune
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/suissa/HiveMind/agents/dataset"
+)
+
+func TestFromExamples(t *testing.T) {
+	records := FromExamples("Seja breve.", []dataset.Example{
+		{Input: "2+2", Expected: "4"},
+		{Input: "sem resposta"},
+	})
+	if len(records) != 1 {
+		t.Fatalf("exemplos sem resposta devem ser ignorados: %d registros", len(records))
+	}
+
+	var b strings.Builder
+	if err := WriteJSONL(&b, records); err != nil {
+		t.Fatal(err)
+	}
+	want := `{"messages":[{"role":"system","content":"Seja breve."},{"role":"user","content":"2+2"},{"role":"assistant","content":"4"}]}` + "\n"
+	if b.String() != want {
+		t.Fatalf("JSONL inesperado:\n%s", b.String())
+	}
+}
+
+func TestRunWithOpenAIClient(t *testing.T) {
+	polls := 0
+	var uploaded string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer key" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/files":
+			file, _, err := r.FormFile("file")
+			if err != nil || r.FormValue("purpose") != "fine-tune" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			data, _ := io.ReadAll(file)
+			uploaded = string(data)
+			json.NewEncoder(w).Encode(map[string]string{"id": "file-1"})
+		case r.Method == http.MethodPost && r.URL.Path == "/fine_tuning/jobs":
+			var req JobRequest
+			json.NewDecoder(r.Body).Decode(&req)
+			json.NewEncoder(w).Encode(Job{ID: "job-1", Model: req.Model, TrainingFile: req.TrainingFile, Status: StatusQueued})
+		case r.Method == http.MethodGet && r.URL.Path == "/fine_tuning/jobs/job-1":
+			polls++
+			job := Job{ID: "job-1", Status: StatusRunning}
+			if polls == 2 {
+				job.Status, job.FineTunedModel = StatusSucceeded, "ft:gpt-4o-mini:hivemind"
+			}
+			json.NewEncoder(w).Encode(job)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := NewOpenAIClient("key")
+	client.BaseURL = server.URL
+
+	var statuses []string
+	records := FromExamples("", []dataset.Example{{Input: "a", Expected: "b"}})
+	job, err := Run(context.Background(), client, records, Options{
+		Model:        "gpt-4o-mini",
+		PollInterval: time.Millisecond,
+		OnStatus:     func(job *Job) { statuses = append(statuses, job.Status) },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if job.FineTunedModel != "ft:gpt-4o-mini:hivemind" || !strings.Contains(uploaded, `"assistant","content":"b"`) {
+		t.Fatalf("job inesperado: %#v (arquivo %q)", job, uploaded)
+	}
+	if strings.Join(statuses, ",") != "queued,running,succeeded" {
+		t.Fatalf("sequência de status inesperada: %v", statuses)
+	}
+}
+
+func TestRunFailedJob(t *testing.T) {
+	client := &fakeClient{final: Job{ID: "job-1", Status: StatusFailed, Error: &JobError{Message: "arquivo inválido"}}}
+	_, err := Run(context.Background(), client, []Record{{}}, Options{Model: "m", PollInterval: time.Millisecond})
+	if err == nil || !strings.Contains(err.Error(), "arquivo inválido") {
+		t.Fatalf("esperava erro com o motivo da falha, obteve %v", err)
+	}
+}
+
+type fakeClient struct {
+	final Job
+}
+
+func (c *fakeClient) UploadFile(ctx context.Context, name string, data []byte) (string, error) {
+	return "file-1", nil
+}
+
+func (c *fakeClient) CreateJob(ctx context.Context, req JobRequest) (*Job, error) {
+	return &Job{ID: c.final.ID, Status: StatusQueued}, nil
+}
+
+func (c *fakeClient) GetJob(ctx context.Context, id string) (*Job, error) {
+	job := c.final
+	return &job, nil
+}
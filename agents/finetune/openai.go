@@ -0,0 +1,116 @@
+package finetune
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/suissa/HiveMind/agents/errs"
+)
+
+// DefaultOpenAIURL é a URL base da API da OpenAI
+const DefaultOpenAIURL = "https://api.openai.com/v1"
+
+// OpenAIClient implementa Client com a API de fine-tuning da OpenAI
+type OpenAIClient struct {
+	BaseURL string
+	APIKey  string
+	client  *http.Client
+}
+
+// NewOpenAIClient cria um cliente para a API de fine-tuning da OpenAI
+func NewOpenAIClient(apiKey string) *OpenAIClient {
+	return &OpenAIClient{
+		BaseURL: DefaultOpenAIURL,
+		APIKey:  apiKey,
+		client:  &http.Client{Timeout: 2 * time.Minute},
+	}
+}
+
+// UploadFile envia o arquivo com propósito "fine-tune" (POST /files)
+func (c *OpenAIClient) UploadFile(ctx context.Context, name string, data []byte) (string, error) {
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	if err := form.WriteField("purpose", "fine-tune"); err != nil {
+		return "", fmt.Errorf("erro ao montar formulário: %v", err)
+	}
+	part, err := form.CreateFormFile("file", name)
+	if err != nil {
+		return "", fmt.Errorf("erro ao montar formulário: %v", err)
+	}
+	if _, err := part.Write(data); err != nil {
+		return "", fmt.Errorf("erro ao montar formulário: %v", err)
+	}
+	if err := form.Close(); err != nil {
+		return "", fmt.Errorf("erro ao montar formulário: %v", err)
+	}
+
+	var file struct {
+		ID string `json:"id"`
+	}
+	if err := c.do(ctx, http.MethodPost, "/files", form.FormDataContentType(), &body, &file); err != nil {
+		return "", err
+	}
+	return file.ID, nil
+}
+
+// CreateJob cria o job (POST /fine_tuning/jobs)
+func (c *OpenAIClient) CreateJob(ctx context.Context, req JobRequest) (*Job, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao serializar job: %v", err)
+	}
+
+	var job Job
+	if err := c.do(ctx, http.MethodPost, "/fine_tuning/jobs", "application/json", bytes.NewReader(body), &job); err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// GetJob consulta o job (GET /fine_tuning/jobs/{id})
+func (c *OpenAIClient) GetJob(ctx context.Context, id string) (*Job, error) {
+	var job Job
+	if err := c.do(ctx, http.MethodGet, "/fine_tuning/jobs/"+id, "", nil, &job); err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// do executa a requisição e decodifica a resposta; erros HTTP são classificados com errs.KindForStatus
+func (c *OpenAIClient) do(ctx context.Context, method, path, contentType string, body io.Reader, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, method, strings.TrimRight(c.BaseURL, "/")+path, body)
+	if err != nil {
+		return fmt.Errorf("erro ao criar requisição à OpenAI: %v", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+c.APIKey)
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return errs.FromContext("finetune.openai", fmt.Errorf("erro ao chamar a OpenAI: %w", err))
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		op := "finetune.openai " + method + " " + path
+		if kind := errs.KindForStatus(resp.StatusCode); kind != nil {
+			return errs.New(kind, op, "status %d: %s", resp.StatusCode, strings.TrimSpace(string(message)))
+		}
+		return fmt.Errorf("%s: status %d: %s", op, resp.StatusCode, strings.TrimSpace(string(message)))
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("erro ao decodificar resposta da OpenAI: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package agents
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/suissa/HiveMind/agents/groupchat"
+)
+
+// GroupChat conduz uma conversa em grupo entre os agentes sobre o tópico, com a política de
+// escolha do próximo participante e a condição de término da configuração
+func GroupChat(ctx context.Context, topic string, config groupchat.Config, agents ...*CognitiveAgent) (*groupchat.Thread, error) {
+	participants := make([]groupchat.Participant, len(agents))
+	for i, agent := range agents {
+		participants[i] = GroupChatParticipant(agent)
+	}
+	return groupchat.Run(ctx, topic, participants, config)
+}
+
+// GroupChatParticipant faz do agente um participante da conversa: ele lê a thread e
+// contribui com a próxima mensagem, encerrando a conversa com TERMINATE quando o tópico
+// estiver resolvido
+func GroupChatParticipant(agent *CognitiveAgent) groupchat.Participant {
+	return groupchat.Participant{
+		ID:          agent.GetID(),
+		Description: agent.GetDescription(),
+		Reply: func(ctx context.Context, thread *groupchat.Thread) (string, error) {
+			output, err := agent.Complete(ctx, fmt.Sprintf(
+				"Você participa de uma conversa em grupo com %s como %s.\n%s\n"+
+					"Contribua com a próxima mensagem dentro do seu papel. Mencione @participante para passar a palavra "+
+					"e escreva %s quando o tópico estiver resolvido.",
+				strings.Join(thread.Participants, ", "), agent.GetID(), thread.String(), groupchat.TerminateKeyword))
+			return strings.TrimSpace(output), err
+		},
+	}
+}
+
+// GroupChatSelector faz do agente o gerente da conversa: ele escolhe o próximo participante
+// a partir da thread e das descrições dos agentes
+func GroupChatSelector(manager *CognitiveAgent) groupchat.Selector {
+	return groupchat.LLM(manager.Complete)
+}
+
+// GroupChat executa o projeto como uma conversa em grupo entre os agentes da equipe, em vez
+// do workflow de tarefas: o objetivo e as restrições do projeto são o tópico e a última
+// mensagem é o resultado
+func (c *MarketingCrew) GroupChat(ctx context.Context, project *MarketingProject, config groupchat.Config) (*groupchat.Thread, error) {
+	c.project = project
+	c.startTime = time.Now()
+
+	c.emitter.Emit(Event{
+		Type:      EventWorkflowUpdate,
+		Timestamp: time.Now(),
+		Source:    "marketing_crew",
+		Data: map[string]interface{}{
+			"action":    "workflow_start",
+			"project":   project.Name,
+			"objective": project.Objective,
+			"mode":      "group_chat",
+		},
+	})
+
+	topic := project.Objective
+	if len(project.Constraints) > 0 {
+		topic += "\nRestrições: " + strings.Join(project.Constraints, "; ")
+	}
+	thread, err := GroupChat(ctx, topic, config, c.agents...)
+	if err != nil {
+		c.emitWorkflowFailed(project, err)
+		return thread, err
+	}
+	if last, ok := thread.Last(); ok {
+		c.outputs[project.Name] = last.Text
+	}
+
+	c.emitter.Emit(Event{
+		Type:      EventWorkflowUpdate,
+		Timestamp: time.Now(),
+		Source:    "marketing_crew",
+		Data: map[string]interface{}{
+			"action":     "workflow_complete",
+			"project":    project.Name,
+			"mode":       "group_chat",
+			"messages":   len(thread.Messages),
+			"stopped_by": thread.StoppedBy,
+			"duration":   time.Since(c.startTime).String(),
+		},
+	})
+	c.writeRecap(ctx, GroupChatTranscript(project.Name, thread))
+	return thread, nil
+}
@@ -0,0 +1,172 @@
+// Package groupchat implementa uma conversa em grupo no estilo AutoGen: vários agentes
+// trocam mensagens em uma thread compartilhada, um seletor escolhe quem fala a seguir
+// (rodízio, escolha por LLM ou regras) e a conversa termina quando uma condição de término
+// é atingida ou o limite de turnos se esgota.
+package groupchat
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/suissa/HiveMind/agents/errs"
+)
+
+// DefaultMaxTurns é o limite padrão de mensagens da conversa
+const DefaultMaxTurns = 12
+
+// TerminateKeyword é a palavra com que os participantes encerram a conversa (Keyword)
+const TerminateKeyword = "TERMINATE"
+
+// Motivos de término registrados em Thread.StoppedBy
+const (
+	StoppedByMaxTurns = "max_turns"
+	StoppedByKeyword  = "keyword"
+)
+
+// Message é uma mensagem da thread
+type Message struct {
+	Turn     int           `json:"turn"`
+	Speaker  string        `json:"speaker"`
+	Text     string        `json:"text"`
+	Time     time.Time     `json:"time"`
+	Duration time.Duration `json:"duration"`
+}
+
+// Participant é um participante da conversa; Description orienta o seletor por LLM
+type Participant struct {
+	ID          string
+	Description string
+	Reply       func(ctx context.Context, thread *Thread) (string, error)
+}
+
+// Thread é a conversa compartilhada pelos participantes
+type Thread struct {
+	ID           string    `json:"id"`
+	Topic        string    `json:"topic"`
+	Participants []string  `json:"participants"`
+	Messages     []Message `json:"messages"`
+	StoppedBy    string    `json:"stopped_by,omitempty"` // Motivo do término
+	StartedAt    time.Time `json:"started_at"`
+	EndedAt      time.Time `json:"ended_at"`
+}
+
+// Last retorna a última mensagem da thread
+func (t *Thread) Last() (Message, bool) {
+	if len(t.Messages) == 0 {
+		return Message{}, false
+	}
+	return t.Messages[len(t.Messages)-1], true
+}
+
+// String formata a conversa para os prompts dos participantes
+func (t *Thread) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Tópico: %s\n", t.Topic)
+	for _, message := range t.Messages {
+		fmt.Fprintf(&b, "\n[%d] %s:\n%s\n", message.Turn, message.Speaker, message.Text)
+	}
+	return b.String()
+}
+
+// Termination decide se a conversa terminou, retornando o motivo
+type Termination func(thread *Thread) (string, bool)
+
+// Keyword termina a conversa quando a última mensagem contém a palavra (TerminateKeyword
+// se vazia)
+func Keyword(word string) Termination {
+	if word == "" {
+		word = TerminateKeyword
+	}
+	return func(thread *Thread) (string, bool) {
+		last, ok := thread.Last()
+		return StoppedByKeyword, ok && strings.Contains(last.Text, word)
+	}
+}
+
+// Any termina a conversa quando qualquer uma das condições é atingida
+func Any(terminations ...Termination) Termination {
+	return func(thread *Thread) (string, bool) {
+		for _, termination := range terminations {
+			if reason, done := termination(thread); done {
+				return reason, true
+			}
+		}
+		return "", false
+	}
+}
+
+// Config configura a conversa
+type Config struct {
+	Selector    Selector    // Quem fala a seguir (RoundRobin se nil)
+	Termination Termination // Condição de término (Keyword(TerminateKeyword) se nil)
+	MaxTurns    int         // Limite de mensagens (DefaultMaxTurns se zero)
+}
+
+// Run conduz a conversa sobre o tópico até a condição de término ou o limite de turnos. A
+// thread parcial é retornada junto com o erro de um participante ou do seletor.
+func Run(ctx context.Context, topic string, participants []Participant, config Config) (*Thread, error) {
+	if len(participants) < 2 {
+		return nil, errs.New(errs.ErrValidation, "groupchat.Run", "a conversa precisa de pelo menos dois participantes")
+	}
+	byID := make(map[string]Participant, len(participants))
+	thread := &Thread{ID: uuid.NewString(), Topic: topic, StartedAt: time.Now()}
+	for _, participant := range participants {
+		if participant.Reply == nil {
+			return nil, errs.New(errs.ErrValidation, "groupchat.Run", "participante %s sem Reply", participant.ID)
+		}
+		if _, exists := byID[participant.ID]; exists {
+			return nil, errs.New(errs.ErrValidation, "groupchat.Run", "participante repetido: %s", participant.ID)
+		}
+		byID[participant.ID] = participant
+		thread.Participants = append(thread.Participants, participant.ID)
+	}
+	if config.Selector == nil {
+		config.Selector = RoundRobin()
+	}
+	if config.Termination == nil {
+		config.Termination = Keyword(TerminateKeyword)
+	}
+	if config.MaxTurns <= 0 {
+		config.MaxTurns = DefaultMaxTurns
+	}
+
+	err := run(ctx, thread, participants, byID, config)
+	thread.EndedAt = time.Now()
+	return thread, err
+}
+
+func run(ctx context.Context, thread *Thread, participants []Participant, byID map[string]Participant, config Config) error {
+	for turn := 1; ; turn++ {
+		if reason, done := config.Termination(thread); done {
+			thread.StoppedBy = reason
+			return nil
+		}
+		if turn > config.MaxTurns {
+			thread.StoppedBy = StoppedByMaxTurns
+			return nil
+		}
+		if err := ctx.Err(); err != nil {
+			return errs.FromContext("groupchat.Run", err)
+		}
+
+		speakerID, err := config.Selector.Next(ctx, thread, participants)
+		if err != nil {
+			return fmt.Errorf("erro ao escolher o participante do turno %d: %w", turn, err)
+		}
+		speaker, ok := byID[speakerID]
+		if !ok {
+			return errs.New(errs.ErrValidation, "groupchat.Run", "o seletor escolheu um participante desconhecido: %s", speakerID)
+		}
+
+		started := time.Now()
+		text, err := speaker.Reply(ctx, thread)
+		if err != nil {
+			return fmt.Errorf("erro do participante %s no turno %d: %w", speaker.ID, turn, err)
+		}
+		thread.Messages = append(thread.Messages, Message{Turn: turn, Speaker: speaker.ID, Text: text, Time: started, Duration: time.Since(started)})
+	}
+}
@@ -0,0 +1,126 @@
+package groupchat
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/suissa/HiveMind/agents/errs"
+)
+
+// scripted cria um participante que responde com as falas em ordem e depois com "ok"
+func scripted(id string, lines ...string) Participant {
+	return Participant{
+		ID:          id,
+		Description: "participante " + id,
+		Reply: func(_ context.Context, thread *Thread) (string, error) {
+			if len(lines) == 0 {
+				return "ok", nil
+			}
+			line := lines[0]
+			lines = lines[1:]
+			return line, nil
+		},
+	}
+}
+
+func speakers(thread *Thread) []string {
+	ids := make([]string, len(thread.Messages))
+	for i, message := range thread.Messages {
+		ids[i] = message.Speaker
+	}
+	return ids
+}
+
+func TestRoundRobinUntilKeyword(t *testing.T) {
+	participants := []Participant{
+		scripted("planner", "plano"),
+		scripted("writer", "rascunho"),
+		scripted("critic", "ajuste o título", "aprovado. TERMINATE"),
+	}
+	thread, err := Run(context.Background(), "post do blog", participants, Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"planner", "writer", "critic", "planner", "writer", "critic"}
+	if !reflect.DeepEqual(speakers(thread), want) || thread.StoppedBy != StoppedByKeyword {
+		t.Fatalf("conversa inesperada: %v (%s)", speakers(thread), thread.StoppedBy)
+	}
+	if !strings.Contains(thread.String(), "[3] critic:\najuste o título") {
+		t.Fatalf("transcrição inesperada:\n%s", thread)
+	}
+}
+
+func TestMaxTurns(t *testing.T) {
+	thread, err := Run(context.Background(), "loop", []Participant{scripted("a"), scripted("b")}, Config{MaxTurns: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(thread.Messages) != 3 || thread.StoppedBy != StoppedByMaxTurns {
+		t.Fatalf("esperava 3 mensagens até max_turns: %d (%s)", len(thread.Messages), thread.StoppedBy)
+	}
+}
+
+func TestRulesSelector(t *testing.T) {
+	participants := []Participant{
+		scripted("planner", "@coder implemente"),
+		scripted("coder", "pronto"),
+		scripted("reviewer", "@planner revisado"),
+	}
+	selector := Rules(nil, Mentioned(), After("coder", "reviewer"))
+	thread, err := Run(context.Background(), "tarefa", participants, Config{Selector: selector, MaxTurns: 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"planner", "coder", "reviewer", "planner"}
+	if !reflect.DeepEqual(speakers(thread), want) {
+		t.Fatalf("ordem inesperada: %v", speakers(thread))
+	}
+}
+
+func TestLLMSelector(t *testing.T) {
+	answers := []string{"writer", "Acho que `senior_writer`.", "ninguém"}
+	var prompts []string
+	selector := LLM(func(_ context.Context, prompt string) (string, error) {
+		prompts = append(prompts, prompt)
+		answer := answers[0]
+		answers = answers[1:]
+		return answer, nil
+	})
+	participants := []Participant{scripted("writer"), scripted("senior_writer")}
+	thread, err := Run(context.Background(), "texto", participants, Config{Selector: selector, MaxTurns: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A resposta sem participante cai no rodízio: depois de senior_writer vem writer
+	want := []string{"writer", "senior_writer", "writer"}
+	if !reflect.DeepEqual(speakers(thread), want) {
+		t.Fatalf("ordem inesperada: %v", speakers(thread))
+	}
+	if !strings.Contains(prompts[0], "- senior_writer: participante senior_writer") {
+		t.Fatalf("prompt sem as descrições: %s", prompts[0])
+	}
+}
+
+func TestRunErrors(t *testing.T) {
+	if _, err := Run(context.Background(), "x", []Participant{scripted("a")}, Config{}); !errors.Is(err, errs.ErrValidation) {
+		t.Fatalf("esperava ErrValidation com um participante: %v", err)
+	}
+	if _, err := Run(context.Background(), "x", []Participant{scripted("a"), scripted("a")}, Config{}); !errors.Is(err, errs.ErrValidation) {
+		t.Fatalf("esperava ErrValidation com participantes repetidos: %v", err)
+	}
+
+	unknown := SelectorFunc(func(context.Context, *Thread, []Participant) (string, error) { return "c", nil })
+	if _, err := Run(context.Background(), "x", []Participant{scripted("a"), scripted("b")}, Config{Selector: unknown}); !errors.Is(err, errs.ErrValidation) {
+		t.Fatalf("esperava ErrValidation com participante desconhecido: %v", err)
+	}
+
+	failing := Participant{ID: "b", Reply: func(context.Context, *Thread) (string, error) { return "", fmt.Errorf("falhou") }}
+	thread, err := Run(context.Background(), "x", []Participant{scripted("a"), failing}, Config{})
+	if err == nil || !strings.Contains(err.Error(), "participante b no turno 2") || len(thread.Messages) != 1 {
+		t.Fatalf("esperava o erro do participante com a thread parcial: %v", err)
+	}
+}
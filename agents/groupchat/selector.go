@@ -0,0 +1,151 @@
+package groupchat
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Selector escolhe o participante que fala a seguir
+type Selector interface {
+	Next(ctx context.Context, thread *Thread, participants []Participant) (string, error)
+}
+
+// SelectorFunc adapta uma função a Selector
+type SelectorFunc func(ctx context.Context, thread *Thread, participants []Participant) (string, error)
+
+// Next implementa Selector
+func (f SelectorFunc) Next(ctx context.Context, thread *Thread, participants []Participant) (string, error) {
+	return f(ctx, thread, participants)
+}
+
+// RoundRobin passa a palavra ao participante seguinte ao último a falar, na ordem da lista
+func RoundRobin() Selector {
+	return SelectorFunc(func(_ context.Context, thread *Thread, participants []Participant) (string, error) {
+		return next(thread, participants), nil
+	})
+}
+
+func next(thread *Thread, participants []Participant) string {
+	last, ok := thread.Last()
+	if !ok {
+		return participants[0].ID
+	}
+	for i, participant := range participants {
+		if participant.ID == last.Speaker {
+			return participants[(i+1)%len(participants)].ID
+		}
+	}
+	return participants[0].ID
+}
+
+// Rule escolhe o próximo participante a partir da thread; ok falso deixa a decisão para a
+// regra seguinte
+type Rule func(thread *Thread) (speaker string, ok bool)
+
+// After passa a palavra a speaker sempre que prev acabou de falar
+func After(prev, speaker string) Rule {
+	return func(thread *Thread) (string, bool) {
+		last, ok := thread.Last()
+		return speaker, ok && last.Speaker == prev
+	}
+}
+
+// When passa a palavra a speaker quando a condição é verdadeira
+func When(condition func(thread *Thread) bool, speaker string) Rule {
+	return func(thread *Thread) (string, bool) {
+		return speaker, condition(thread)
+	}
+}
+
+// mention reconhece as menções @participante
+var mention = regexp.MustCompile(`@([\w.-]+)`)
+
+// Mentioned passa a palavra ao primeiro participante mencionado com @id na última mensagem
+func Mentioned(ids ...string) Rule {
+	known := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		known[id] = true
+	}
+	return func(thread *Thread) (string, bool) {
+		last, ok := thread.Last()
+		if !ok {
+			return "", false
+		}
+		for _, match := range mention.FindAllStringSubmatch(last.Text, -1) {
+			if match[1] != last.Speaker && (len(known) == 0 || known[match[1]]) {
+				return match[1], true
+			}
+		}
+		return "", false
+	}
+}
+
+// Rules aplica as regras em ordem; a primeira que decidir escolhe o participante. Sem
+// nenhuma regra aplicável, a escolha fica com fallback (RoundRobin se nil).
+func Rules(fallback Selector, rules ...Rule) Selector {
+	if fallback == nil {
+		fallback = RoundRobin()
+	}
+	return SelectorFunc(func(ctx context.Context, thread *Thread, participants []Participant) (string, error) {
+		for _, rule := range rules {
+			if speaker, ok := rule(thread); ok && member(participants, speaker) {
+				return speaker, nil
+			}
+		}
+		return fallback.Next(ctx, thread, participants)
+	})
+}
+
+// LLM pede ao modelo (normalmente um agente gerente) que escolha o próximo participante a
+// partir da conversa e das descrições. Respostas que não nomeiam um participante caem no
+// rodízio.
+func LLM(complete func(ctx context.Context, prompt string) (string, error)) Selector {
+	return SelectorFunc(func(ctx context.Context, thread *Thread, participants []Participant) (string, error) {
+		var b strings.Builder
+		b.WriteString("Você coordena uma conversa em grupo. Participantes:\n")
+		for _, participant := range participants {
+			fmt.Fprintf(&b, "- %s: %s\n", participant.ID, participant.Description)
+		}
+		b.WriteString("\n")
+		b.WriteString(thread.String())
+		b.WriteString("\nQuem deve falar a seguir? Responda apenas com o ID do participante.")
+
+		output, err := complete(ctx, b.String())
+		if err != nil {
+			return "", err
+		}
+		if speaker, ok := pick(output, participants); ok {
+			return speaker, nil
+		}
+		return next(thread, participants), nil
+	})
+}
+
+// pick encontra o participante nomeado na resposta do modelo, preferindo a resposta exata
+func pick(output string, participants []Participant) (string, bool) {
+	answer := strings.Trim(strings.TrimSpace(output), "`\"'.@")
+	for _, participant := range participants {
+		if strings.EqualFold(answer, participant.ID) {
+			return participant.ID, true
+		}
+	}
+	// O ID mais longo primeiro, para que "writer" não vença "senior_writer"
+	best := ""
+	for _, participant := range participants {
+		if len(participant.ID) > len(best) && strings.Contains(output, participant.ID) {
+			best = participant.ID
+		}
+	}
+	return best, best != ""
+}
+
+func member(participants []Participant, id string) bool {
+	for _, participant := range participants {
+		if participant.ID == id {
+			return true
+		}
+	}
+	return false
+}
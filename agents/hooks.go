@@ -0,0 +1,103 @@
+package agents
+
+import (
+	"context"
+)
+
+// Hooks anexa comportamento ao ciclo de vida de um agente (logs, métricas, alteração
+// do prompt, pós-processamento do resultado) sem a necessidade de estender o agente.
+// Embuta NopHooks para implementar apenas os métodos desejados.
+type Hooks interface {
+	// OnStart é chamado uma única vez, antes da primeira tarefa do agente
+	OnStart(ctx context.Context, agent Agent) error
+	// OnTaskBegin é chamado antes da execução; pode alterar a tarefa (ex.: Description)
+	// ou abortá-la retornando um erro
+	OnTaskBegin(ctx context.Context, agent Agent, task *Task) error
+	// OnTaskEnd recebe o resultado da tarefa e retorna o resultado (possivelmente alterado)
+	OnTaskEnd(ctx context.Context, agent Agent, task *Task, output string) (string, error)
+	// OnError é chamado quando a tarefa falha, inclusive por erro de outro hook
+	OnError(ctx context.Context, agent Agent, task *Task, err error)
+}
+
+// NopHooks implementa Hooks sem nenhum efeito
+type NopHooks struct{}
+
+func (NopHooks) OnStart(ctx context.Context, agent Agent) error                 { return nil }
+func (NopHooks) OnTaskBegin(ctx context.Context, agent Agent, task *Task) error { return nil }
+func (NopHooks) OnTaskEnd(ctx context.Context, agent Agent, task *Task, output string) (string, error) {
+	return output, nil
+}
+func (NopHooks) OnError(ctx context.Context, agent Agent, task *Task, err error) {}
+
+// HookFuncs adapta funções avulsas para a interface Hooks; campos nil são ignorados
+type HookFuncs struct {
+	Start     func(ctx context.Context, agent Agent) error
+	TaskBegin func(ctx context.Context, agent Agent, task *Task) error
+	TaskEnd   func(ctx context.Context, agent Agent, task *Task, output string) (string, error)
+	Error     func(ctx context.Context, agent Agent, task *Task, err error)
+}
+
+func (h HookFuncs) OnStart(ctx context.Context, agent Agent) error {
+	if h.Start == nil {
+		return nil
+	}
+	return h.Start(ctx, agent)
+}
+
+func (h HookFuncs) OnTaskBegin(ctx context.Context, agent Agent, task *Task) error {
+	if h.TaskBegin == nil {
+		return nil
+	}
+	return h.TaskBegin(ctx, agent, task)
+}
+
+func (h HookFuncs) OnTaskEnd(ctx context.Context, agent Agent, task *Task, output string) (string, error) {
+	if h.TaskEnd == nil {
+		return output, nil
+	}
+	return h.TaskEnd(ctx, agent, task, output)
+}
+
+func (h HookFuncs) OnError(ctx context.Context, agent Agent, task *Task, err error) {
+	if h.Error != nil {
+		h.Error(ctx, agent, task, err)
+	}
+}
+
+// hookChain executa os hooks na ordem de registro
+type hookChain []Hooks
+
+func (c hookChain) start(ctx context.Context, agent Agent) error {
+	for _, h := range c {
+		if err := h.OnStart(ctx, agent); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c hookChain) taskBegin(ctx context.Context, agent Agent, task *Task) error {
+	for _, h := range c {
+		if err := h.OnTaskBegin(ctx, agent, task); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// taskEnd encadeia o resultado: cada hook recebe a saída do anterior
+func (c hookChain) taskEnd(ctx context.Context, agent Agent, task *Task, output string) (string, error) {
+	for _, h := range c {
+		var err error
+		if output, err = h.OnTaskEnd(ctx, agent, task, output); err != nil {
+			return "", err
+		}
+	}
+	return output, nil
+}
+
+func (c hookChain) error(ctx context.Context, agent Agent, task *Task, err error) {
+	for _, h := range c {
+		h.OnError(ctx, agent, task, err)
+	}
+}
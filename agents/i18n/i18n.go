@@ -0,0 +1,247 @@
+// Package i18n traduz as mensagens exibidas ao usuário (eventos, respostas da API e
+// relatórios gerados) a partir de catálogos por idioma. O idioma padrão é o inglês;
+// o português do Brasil é mantido como catálogo completo. Logs de operação não são
+// traduzidos.
+package i18n
+
+import (
+	"bytes"
+	"context"
+	"embed"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"text/template"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Locale identifica um idioma (tag BCP 47, ex.: "en", "pt-BR")
+type Locale string
+
+// Idiomas com catálogo embutido
+const (
+	English      Locale = "en"
+	PortugueseBR Locale = "pt-BR"
+)
+
+// EnvLocale é a variável de ambiente que define o idioma padrão
+const EnvLocale = "HIVEMIND_LOCALE"
+
+//go:embed locales/*.yaml
+var embedded embed.FS
+
+var (
+	catalogs = make(map[Locale]map[string]string)
+	current  = English
+	mu       sync.RWMutex
+)
+
+func init() {
+	entries, err := embedded.ReadDir("locales")
+	if err != nil {
+		panic(fmt.Sprintf("i18n: erro ao ler catálogos embutidos: %v", err))
+	}
+	for _, entry := range entries {
+		data, err := embedded.ReadFile(path.Join("locales", entry.Name()))
+		if err != nil {
+			panic(fmt.Sprintf("i18n: erro ao ler catálogo %s: %v", entry.Name(), err))
+		}
+		if err := load(localeFromFile(entry.Name()), data); err != nil {
+			panic(fmt.Sprintf("i18n: %v", err))
+		}
+	}
+}
+
+// localeFromFile extrai o idioma do nome do arquivo (ex.: "pt-BR.yaml")
+func localeFromFile(name string) Locale {
+	return Locale(strings.TrimSuffix(filepath.Base(name), filepath.Ext(name)))
+}
+
+// load decodifica um catálogo YAML (chave: mensagem) e o registra
+func load(locale Locale, data []byte) error {
+	messages := make(map[string]string)
+	if err := yaml.Unmarshal(data, &messages); err != nil {
+		return fmt.Errorf("erro ao decodificar catálogo %s: %v", locale, err)
+	}
+	Register(locale, messages)
+	return nil
+}
+
+// Register adiciona mensagens ao catálogo do idioma, sobrescrevendo as chaves existentes
+func Register(locale Locale, messages map[string]string) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	catalog, ok := catalogs[locale]
+	if !ok {
+		catalog = make(map[string]string, len(messages))
+		catalogs[locale] = catalog
+	}
+	for key, message := range messages {
+		catalog[key] = message
+	}
+}
+
+// LoadFile carrega um catálogo adicional; o idioma é o nome do arquivo (ex.: "es.yaml")
+func LoadFile(filename string) error {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return fmt.Errorf("erro ao ler catálogo: %v", err)
+	}
+	return load(localeFromFile(filename), data)
+}
+
+// Supported retorna os idiomas com catálogo registrado
+func Supported() []Locale {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	locales := make([]Locale, 0, len(catalogs))
+	for locale := range catalogs {
+		locales = append(locales, locale)
+	}
+	sort.Slice(locales, func(i, j int) bool { return locales[i] < locales[j] })
+	return locales
+}
+
+// Parse normaliza uma tag de idioma ("pt_br", "PT-BR", "pt") para um idioma suportado.
+// Retorna false se não houver catálogo correspondente.
+func Parse(tag string) (Locale, bool) {
+	tag = strings.TrimSpace(strings.ReplaceAll(tag, "_", "-"))
+	if tag == "" {
+		return "", false
+	}
+	base := strings.ToLower(strings.SplitN(tag, "-", 2)[0])
+
+	mu.RLock()
+	defer mu.RUnlock()
+
+	// Correspondência exata (sem diferenciar maiúsculas) e, depois, pelo idioma base
+	for locale := range catalogs {
+		if strings.EqualFold(string(locale), tag) {
+			return locale, true
+		}
+	}
+	for _, locale := range []Locale{English, PortugueseBR} {
+		if strings.EqualFold(strings.SplitN(string(locale), "-", 2)[0], base) {
+			if _, ok := catalogs[locale]; ok {
+				return locale, true
+			}
+		}
+	}
+	for locale := range catalogs {
+		if strings.EqualFold(strings.SplitN(string(locale), "-", 2)[0], base) {
+			return locale, true
+		}
+	}
+	return "", false
+}
+
+// FromAcceptLanguage escolhe o primeiro idioma suportado de um header Accept-Language,
+// respeitando a ordem de preferência (os pesos q são ignorados)
+func FromAcceptLanguage(header string) (Locale, bool) {
+	for _, part := range strings.Split(header, ",") {
+		tag := strings.SplitN(part, ";", 2)[0]
+		if locale, ok := Parse(tag); ok {
+			return locale, true
+		}
+	}
+	return "", false
+}
+
+// SetDefault define o idioma usado quando o contexto não informa um
+func SetDefault(locale Locale) error {
+	resolved, ok := Parse(string(locale))
+	if !ok {
+		return fmt.Errorf("idioma não suportado: %s", locale)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	current = resolved
+	return nil
+}
+
+// Default retorna o idioma padrão
+func Default() Locale {
+	mu.RLock()
+	defer mu.RUnlock()
+	return current
+}
+
+// LoadFromEnv define o idioma padrão a partir de HIVEMIND_LOCALE, se configurado
+func LoadFromEnv() error {
+	if value := os.Getenv(EnvLocale); value != "" {
+		return SetDefault(Locale(value))
+	}
+	return nil
+}
+
+// localeKey é a chave do idioma no contexto
+type localeKey struct{}
+
+// WithLocale associa o idioma ao contexto
+func WithLocale(ctx context.Context, locale Locale) context.Context {
+	return context.WithValue(ctx, localeKey{}, locale)
+}
+
+// FromContext retorna o idioma do contexto ou o padrão
+func FromContext(ctx context.Context) Locale {
+	if locale, ok := ctx.Value(localeKey{}).(Locale); ok && locale != "" {
+		return locale
+	}
+	return Default()
+}
+
+// Lookup busca a mensagem no catálogo do idioma, recorrendo ao inglês
+func Lookup(locale Locale, key string) (string, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	if message, ok := catalogs[locale][key]; ok {
+		return message, true
+	}
+	message, ok := catalogs[English][key]
+	return message, ok
+}
+
+// Message retorna a mensagem traduzida, formatada com fmt.Sprintf quando há argumentos.
+// Chaves desconhecidas são devolvidas como estão.
+func Message(locale Locale, key string, args ...interface{}) string {
+	message, ok := Lookup(locale, key)
+	if !ok {
+		return key
+	}
+	if len(args) == 0 {
+		return message
+	}
+	return fmt.Sprintf(message, args...)
+}
+
+// T traduz a mensagem para o idioma do contexto
+func T(ctx context.Context, key string, args ...interface{}) string {
+	return Message(FromContext(ctx), key, args...)
+}
+
+// Render traduz uma mensagem com campos nomeados ({{.campo}}) preenchidos a partir de data.
+// Retorna false se a chave não existir ou o template falhar.
+func Render(locale Locale, key string, data map[string]interface{}) (string, bool) {
+	message, ok := Lookup(locale, key)
+	if !ok {
+		return "", false
+	}
+
+	tmpl, err := template.New(key).Option("missingkey=zero").Parse(message)
+	if err != nil {
+		return "", false
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", false
+	}
+	return buf.String(), true
+}
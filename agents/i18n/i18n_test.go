@@ -0,0 +1,68 @@
+package i18n
+
+import (
+	"context"
+	"testing"
+)
+
+func TestCatalogsHaveSameKeys(t *testing.T) {
+	for key := range catalogs[English] {
+		if _, ok := catalogs[PortugueseBR][key]; !ok {
+			t.Errorf("chave %s ausente no catálogo pt-BR", key)
+		}
+	}
+	for key := range catalogs[PortugueseBR] {
+		if _, ok := catalogs[English][key]; !ok {
+			t.Errorf("chave %s ausente no catálogo en", key)
+		}
+	}
+}
+
+func TestMessageAndContext(t *testing.T) {
+	if got := Message(English, "api.method_not_allowed", "PUT"); got != "method not supported: PUT" {
+		t.Fatalf("mensagem inesperada: %s", got)
+	}
+	if got := Message(PortugueseBR, "api.agent_id_required"); got != "agent_id é obrigatório" {
+		t.Fatalf("mensagem inesperada: %s", got)
+	}
+	if got := Message(English, "chave.inexistente"); got != "chave.inexistente" {
+		t.Fatalf("chave desconhecida deve ser devolvida: %s", got)
+	}
+
+	if FromContext(context.Background()) != English {
+		t.Fatal("o idioma padrão deve ser inglês")
+	}
+	ctx := WithLocale(context.Background(), PortugueseBR)
+	if got := T(ctx, "api.missing_credentials"); got != "credenciais ausentes" {
+		t.Fatalf("mensagem inesperada: %s", got)
+	}
+}
+
+func TestParseAndAcceptLanguage(t *testing.T) {
+	cases := map[string]Locale{"pt_br": PortugueseBR, "PT": PortugueseBR, "en-US": English}
+	for tag, want := range cases {
+		if got, ok := Parse(tag); !ok || got != want {
+			t.Errorf("Parse(%q) = %q, esperado %q", tag, got, want)
+		}
+	}
+	if _, ok := Parse("ja"); ok {
+		t.Error("idioma sem catálogo não deve ser aceito")
+	}
+	if got, ok := FromAcceptLanguage("ja;q=1.0, pt-BR;q=0.9, en;q=0.8"); !ok || got != PortugueseBR {
+		t.Errorf("Accept-Language resolvido para %q", got)
+	}
+}
+
+func TestRender(t *testing.T) {
+	got, ok := Render(PortugueseBR, "event.project_update.status_update", map[string]interface{}{
+		"progress":        42.4,
+		"completed_tasks": 2,
+		"total_tasks":     5,
+	})
+	if !ok || got != "Progresso do projeto: 42% (2/5 tarefas)" {
+		t.Fatalf("render inesperado: %q", got)
+	}
+	if _, ok := Render(English, "event.inexistente", nil); ok {
+		t.Fatal("chave desconhecida não deve ser renderizada")
+	}
+}
@@ -0,0 +1,125 @@
+// Package importance estima a importância de uma memória no momento em que ela é gravada,
+// pedindo a um modelo pequeno que avalie a novidade e a utilidade do conteúdo em relação
+// às memórias já existentes.
+package importance
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/suissa/HiveMind/agents/llm"
+)
+
+// Neutral é a importância usada quando não há avaliador ou a avaliação falha
+const Neutral = 0.5
+
+// Score é a avaliação de uma memória, com notas entre 0 e 1
+type Score struct {
+	Novelty    float64 `json:"novelty"`    // Quanto o conteúdo difere das memórias existentes
+	Utility    float64 `json:"utility"`    // Quanto o conteúdo tende a ser útil em tarefas futuras
+	Importance float64 `json:"importance"` // Combinação ponderada de novidade e utilidade
+	Reasoning  string  `json:"reasoning,omitempty"`
+}
+
+// Scorer avalia o conteúdo de uma memória frente às memórias relacionadas já gravadas
+type Scorer interface {
+	Score(ctx context.Context, content string, related []string) (Score, error)
+}
+
+const scorerSystem = `Você avalia memórias de um agente de IA antes de gravá-las.
+Dê notas de 0 a 10 para:
+- novelty: quanto o conteúdo novo acrescenta às memórias existentes (0 = repetido, 10 = inédito)
+- utility: quanto o conteúdo tende a ser útil em tarefas futuras (0 = irrelevante, 10 = essencial)
+Responda apenas com JSON no formato {"novelty": 0, "utility": 0, "reasoning": "..."}.`
+
+// LLMScorer avalia memórias com um modelo de linguagem
+type LLMScorer struct {
+	provider llm.Provider
+	Model    string
+	// UtilityWeight é o peso da utilidade na importância (padrão 0.6); a novidade recebe o restante
+	UtilityWeight float64
+	// MaxRelated limita as memórias relacionadas enviadas ao modelo (padrão 5)
+	MaxRelated int
+}
+
+// NewLLMScorer cria um avaliador com o provedor e o modelo informados. Um modelo pequeno é
+// suficiente e reduz o custo de cada gravação.
+func NewLLMScorer(provider llm.Provider, model string) *LLMScorer {
+	return &LLMScorer{provider: provider, Model: model, UtilityWeight: 0.6, MaxRelated: 5}
+}
+
+// Score implementa Scorer
+func (s *LLMScorer) Score(ctx context.Context, content string, related []string) (Score, error) {
+	resp, err := s.provider.Complete(ctx, llm.Request{
+		Model:       s.Model,
+		System:      scorerSystem,
+		Prompt:      s.prompt(content, related),
+		Temperature: 0,
+	})
+	if err != nil {
+		return Score{}, fmt.Errorf("erro na avaliação de importância: %w", err)
+	}
+	return s.parse(resp.Text)
+}
+
+// prompt monta a solicitação com as memórias relacionadas e o conteúdo novo
+func (s *LLMScorer) prompt(content string, related []string) string {
+	var b strings.Builder
+	b.WriteString("Memórias existentes relacionadas:\n")
+	if len(related) == 0 {
+		b.WriteString("(nenhuma)\n")
+	}
+	for i, r := range related {
+		if s.MaxRelated > 0 && i >= s.MaxRelated {
+			break
+		}
+		fmt.Fprintf(&b, "- %s\n", r)
+	}
+	fmt.Fprintf(&b, "\nConteúdo novo:\n%s\n", content)
+	return b.String()
+}
+
+// parse extrai as notas do JSON devolvido, tolerando texto ou blocos de código ao redor
+func (s *LLMScorer) parse(text string) (Score, error) {
+	start, end := strings.Index(text, "{"), strings.LastIndex(text, "}")
+	if start < 0 || end < start {
+		return Score{}, fmt.Errorf("resposta da avaliação sem JSON: %q", text)
+	}
+
+	var verdict struct {
+		Novelty   *float64 `json:"novelty"`
+		Utility   *float64 `json:"utility"`
+		Reasoning string   `json:"reasoning"`
+	}
+	if err := json.Unmarshal([]byte(text[start:end+1]), &verdict); err != nil {
+		return Score{}, fmt.Errorf("resposta da avaliação inválida: %v", err)
+	}
+	if verdict.Novelty == nil || verdict.Utility == nil {
+		return Score{}, fmt.Errorf("avaliação sem novelty ou utility: %q", text)
+	}
+
+	weight := s.UtilityWeight
+	if weight <= 0 || weight > 1 {
+		weight = 0.6
+	}
+	score := Score{
+		Novelty:   clamp(*verdict.Novelty / 10),
+		Utility:   clamp(*verdict.Utility / 10),
+		Reasoning: verdict.Reasoning,
+	}
+	score.Importance = weight*score.Utility + (1-weight)*score.Novelty
+	return score, nil
+}
+
+// clamp limita o valor ao intervalo [0, 1]
+func clamp(value float64) float64 {
+	if value < 0 {
+		return 0
+	}
+	if value > 1 {
+		return 1
+	}
+	return value
+}
@@ -0,0 +1,42 @@
+package importance
+
+import (
+	"context"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/suissa/HiveMind/agents/llm"
+)
+
+func TestLLMScorerWeighsNoveltyAndUtility(t *testing.T) {
+	var prompt string
+	scorer := NewLLMScorer(llm.ProviderFunc(func(ctx context.Context, req llm.Request) (*llm.Response, error) {
+		prompt = req.Prompt
+		return &llm.Response{Text: "```json\n{\"novelty\": 2, \"utility\": 12, \"reasoning\": \"repetido, mas útil\"}\n```"}, nil
+	}), "small")
+
+	score, err := scorer.Score(context.Background(), "cliente prefere e-mail", []string{"cliente prefere contato por e-mail"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if score.Novelty != 0.2 || score.Utility != 1 {
+		t.Fatalf("notas inesperadas: %+v", score)
+	}
+	if math.Abs(score.Importance-0.68) > 1e-9 {
+		t.Fatalf("importância inesperada: %v", score.Importance)
+	}
+	if !strings.Contains(prompt, "cliente prefere contato por e-mail") {
+		t.Fatalf("memórias relacionadas ausentes do prompt: %s", prompt)
+	}
+}
+
+func TestLLMScorerRejectsIncompleteVerdict(t *testing.T) {
+	scorer := NewLLMScorer(llm.ProviderFunc(func(ctx context.Context, req llm.Request) (*llm.Response, error) {
+		return &llm.Response{Text: `{"utility": 7}`}, nil
+	}), "small")
+
+	if _, err := scorer.Score(context.Background(), "x", nil); err == nil {
+		t.Fatal("esperava erro para avaliação sem novelty")
+	}
+}
@@ -0,0 +1,51 @@
+package agents
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/suissa/HiveMind/agents/inbox"
+)
+
+// InboxTask converte a mensagem da caixa de entrada na tarefa do agente. O payload pode ser
+// uma *Task, uma Task, a descrição da tarefa (string) ou os parâmetros dela, com a descrição
+// em "description". Vindo de outra instância (stealing), o payload chega em JSON.
+func InboxTask(msg inbox.Message) *Task {
+	switch payload := msg.Payload.(type) {
+	case *Task:
+		return payload
+	case Task:
+		return &payload
+	case string:
+		return NewTask(msg.ID, msg.Type, payload, nil)
+	case map[string]interface{}:
+		description, _ := payload["description"].(string)
+		return NewTask(msg.ID, msg.Type, description, payload)
+	case json.RawMessage:
+		var description string
+		if json.Unmarshal(payload, &description) == nil {
+			return NewTask(msg.ID, msg.Type, description, nil)
+		}
+		var task Task
+		if json.Unmarshal(payload, &task) == nil && task.ID != "" {
+			return &task
+		}
+		var input map[string]interface{}
+		json.Unmarshal(payload, &input)
+		msg.Payload = input
+		return InboxTask(msg)
+	default:
+		return NewTask(msg.ID, msg.Type, fmt.Sprint(payload), nil)
+	}
+}
+
+// InboxHandler executa com o agente as tarefas entregues pela caixa de entrada dele
+//
+//	go box.Serve(ctx, agent.GetID(), agents.InboxHandler(agent))
+func InboxHandler(agent *CognitiveAgent) inbox.Handler {
+	return func(ctx context.Context, delivery inbox.Delivery) error {
+		_, err := agent.Run(ctx, InboxTask(delivery.Message))
+		return err
+	}
+}
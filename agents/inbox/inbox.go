@@ -0,0 +1,630 @@
+// Package inbox dá a cada agente uma caixa de entrada consumida por demanda (pull): o agente
+// pede mensagens quando está livre, em vez de receber tudo o que chega numa fila compartilhada
+// do tipo. Cada entrega é um empréstimo com prazo de visibilidade; sem confirmação (Ack) dentro
+// do prazo a mensagem volta a ficar disponível e pode ser entregue de novo. Agentes ociosos
+// tomam as mensagens disponíveis das caixas dos agentes ocupados que atendem os mesmos tipos,
+// então um agente lento não retém mensagens e o trabalho se redistribui.
+//
+// A entrega é pelo menos uma vez: uma tarefa que excede o prazo de visibilidade pode ser
+// executada por outro agente enquanto a primeira execução ainda termina.
+package inbox
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/suissa/HiveMind/agents/affinity"
+	"github.com/suissa/HiveMind/agents/errs"
+	"github.com/suissa/HiveMind/agents/metrics"
+)
+
+// Valores padrão da configuração
+const (
+	DefaultVisibilityTimeout = 30 * time.Second
+	DefaultMaxDeliveries     = 5
+	DefaultRetryDelay        = time.Second
+)
+
+var messagesTotal = metrics.Default.Counter("hivemind_inbox_messages_total",
+	"Mensagens das caixas de entrada dos agentes por evento (enqueued, delivered, redelivered, acked, nacked, expired, stolen ou dead)", "event")
+
+// Config define as caixas de entrada
+type Config struct {
+	// VisibilityTimeout é o prazo de uma entrega; depois dele a mensagem não confirmada volta a ficar disponível
+	VisibilityTimeout time.Duration `json:"visibility_timeout,omitempty" yaml:"visibility_timeout,omitempty"`
+	// MaxDeliveries é o número de entregas após o qual a mensagem vai para as mensagens mortas
+	MaxDeliveries int `json:"max_deliveries,omitempty" yaml:"max_deliveries,omitempty"`
+	// RetryDelay é a espera antes de reentregar uma mensagem recusada, multiplicada pelo número de entregas
+	RetryDelay time.Duration `json:"retry_delay,omitempty" yaml:"retry_delay,omitempty"`
+}
+
+// Message é uma mensagem da caixa de entrada
+type Message struct {
+	ID         string      `json:"id"`
+	Type       string      `json:"type"`
+	Payload    interface{} `json:"payload,omitempty"`
+	Agent      string      `json:"agent"`      // Agente dono da caixa em que a mensagem está
+	Deliveries int         `json:"deliveries"` // Entregas feitas, incluindo a atual
+	EnqueuedAt time.Time   `json:"enqueued_at"`
+	LastError  string      `json:"last_error,omitempty"`
+}
+
+// Delivery é uma mensagem emprestada a um agente. O recibo confirma (Ack), recusa (Nack) ou
+// prorroga (Extend) a entrega.
+type Delivery struct {
+	Message
+	Receipt  string    `json:"receipt"`
+	Deadline time.Time `json:"deadline"` // Fim do prazo de visibilidade
+}
+
+// Stats resume a caixa de entrada de um agente
+type Stats struct {
+	Pending  int `json:"pending"`   // Mensagens aguardando entrega
+	InFlight int `json:"in_flight"` // Mensagens entregues e não confirmadas
+}
+
+// Handler processa uma mensagem entregue; um erro recusa a entrega
+type Handler func(ctx context.Context, delivery Delivery) error
+
+// entry é uma mensagem numa caixa
+type entry struct {
+	msg       Message
+	receipt   string    // Recibo da entrega atual; vazio quando a mensagem está disponível
+	visibleAt time.Time // Início da disponibilidade ou, durante uma entrega, o fim do prazo
+}
+
+// mailbox é a caixa de entrada de um agente
+type mailbox struct {
+	types   map[string]bool
+	entries []*entry
+}
+
+// available informa se a entrada pode ser entregue no instante
+func (e *entry) available(now time.Time) bool {
+	return e.receipt == "" && !e.visibleAt.After(now)
+}
+
+// remove tira a entrada da caixa
+func (b *mailbox) remove(e *entry) {
+	for i, current := range b.entries {
+		if current == e {
+			b.entries = append(b.entries[:i], b.entries[i+1:]...)
+			return
+		}
+	}
+}
+
+// Inbox guarda as caixas de entrada dos agentes
+type Inbox struct {
+	config  Config
+	boxes   map[string]*mailbox
+	leases  map[string]*entry
+	dead    []Message
+	changed chan struct{} // Fechado (e trocado) a cada mudança, acordando os agentes em Receive
+	seq     uint64
+	mu      sync.Mutex
+}
+
+// New cria as caixas de entrada; campos zerados da configuração usam os padrões
+func New(config Config) *Inbox {
+	if config.VisibilityTimeout <= 0 {
+		config.VisibilityTimeout = DefaultVisibilityTimeout
+	}
+	if config.MaxDeliveries <= 0 {
+		config.MaxDeliveries = DefaultMaxDeliveries
+	}
+	if config.RetryDelay <= 0 {
+		config.RetryDelay = DefaultRetryDelay
+	}
+	return &Inbox{
+		config:  config,
+		boxes:   make(map[string]*mailbox),
+		leases:  make(map[string]*entry),
+		changed: make(chan struct{}),
+	}
+}
+
+// Config retorna a configuração em uso
+func (b *Inbox) Config() Config {
+	return b.config
+}
+
+// Backoff retorna a espera antes da próxima entrega de uma mensagem recusada
+func (b *Inbox) Backoff(deliveries int) time.Duration {
+	if deliveries < 1 {
+		deliveries = 1
+	}
+	return b.config.RetryDelay * time.Duration(deliveries)
+}
+
+// Register cria a caixa de entrada do agente, ou atualiza os tipos de uma existente. O agente
+// recebe por Dispatch e toma de outras caixas as mensagens dos tipos informados.
+func (b *Inbox) Register(agentID string, types ...string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	box, ok := b.boxes[agentID]
+	if !ok {
+		box = &mailbox{}
+		b.boxes[agentID] = box
+	}
+	box.types = make(map[string]bool, len(types))
+	for _, t := range types {
+		box.types[t] = true
+	}
+	b.notify()
+}
+
+// Unregister remove a caixa de entrada do agente. As mensagens dela, inclusive as entregues e
+// não confirmadas, passam para o agente menos carregado do mesmo tipo ou, sem nenhum, para as
+// mensagens mortas.
+func (b *Inbox) Unregister(agentID string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	box, ok := b.boxes[agentID]
+	if !ok {
+		return
+	}
+	delete(b.boxes, agentID)
+	now := time.Now()
+	for _, e := range box.entries {
+		if e.receipt != "" {
+			delete(b.leases, e.receipt)
+			e.receipt = ""
+		}
+		target := b.leastLoaded(e.msg.Type)
+		if target == "" {
+			e.msg.LastError = fmt.Sprintf("nenhum agente atende o tipo %s", e.msg.Type)
+			b.bury(e)
+			continue
+		}
+		e.msg.Agent = target
+		e.visibleAt = now
+		b.boxes[target].entries = append(b.boxes[target].entries, e)
+	}
+	b.notify()
+}
+
+// Send coloca a mensagem na caixa de entrada do agente e retorna o seu ID
+func (b *Inbox) Send(agentID, msgType string, payload interface{}) (string, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if _, ok := b.boxes[agentID]; !ok {
+		return "", errs.New(errs.ErrNotFound, "inbox.Send", "agente %s sem caixa de entrada", agentID)
+	}
+	return b.enqueue(agentID, msgType, payload), nil
+}
+
+// Dispatch coloca a mensagem na caixa do agente menos carregado que atende o tipo e retorna o seu ID
+func (b *Inbox) Dispatch(msgType string, payload interface{}) (string, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	target := b.leastLoaded(msgType)
+	if target == "" {
+		return "", errs.New(errs.ErrNotFound, "inbox.Dispatch", "nenhum agente atende o tipo %s", msgType)
+	}
+	return b.enqueue(target, msgType, payload), nil
+}
+
+// DispatchAffinity coloca a mensagem na caixa do agente dono da chave de afinidade entre os
+// que atendem o tipo (hashing consistente), para que as mensagens relacionadas fiquem com o
+// mesmo agente. Chave vazia equivale a Dispatch. Se o dono estiver ocupado, um agente ocioso
+// ainda pode tomar a mensagem.
+func (b *Inbox) DispatchAffinity(msgType, key string, payload interface{}) (string, error) {
+	if key == "" {
+		return b.Dispatch(msgType, payload)
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var candidates []string
+	for id, box := range b.boxes {
+		if box.types[msgType] {
+			candidates = append(candidates, id)
+		}
+	}
+	target := affinity.Pick(key, candidates)
+	if target == "" {
+		return "", errs.New(errs.ErrNotFound, "inbox.DispatchAffinity", "nenhum agente atende o tipo %s", msgType)
+	}
+	return b.enqueue(target, msgType, payload), nil
+}
+
+// enqueue adiciona a mensagem à caixa. Deve ser chamado com b.mu travado.
+func (b *Inbox) enqueue(agentID, msgType string, payload interface{}) string {
+	now := time.Now()
+	e := &entry{
+		msg: Message{
+			ID:         b.nextID("msg"),
+			Type:       msgType,
+			Payload:    payload,
+			Agent:      agentID,
+			EnqueuedAt: now,
+		},
+		visibleAt: now,
+	}
+	b.boxes[agentID].entries = append(b.boxes[agentID].entries, e)
+	messagesTotal.Inc("enqueued")
+	b.notify()
+	return e.msg.ID
+}
+
+// leastLoaded retorna o agente do tipo com menos mensagens na caixa, ou "" se nenhum o
+// atende. O empate fica com o menor ID. Deve ser chamado com b.mu travado.
+func (b *Inbox) leastLoaded(msgType string) string {
+	target, load := "", 0
+	for _, id := range b.agentIDs() {
+		box := b.boxes[id]
+		if !box.types[msgType] {
+			continue
+		}
+		if target == "" || len(box.entries) < load {
+			target, load = id, len(box.entries)
+		}
+	}
+	return target
+}
+
+// agentIDs retorna os IDs dos agentes em ordem. Deve ser chamado com b.mu travado.
+func (b *Inbox) agentIDs() []string {
+	ids := make([]string, 0, len(b.boxes))
+	for id := range b.boxes {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// Put coloca na caixa do agente uma mensagem vinda de outra instância, mantendo o ID, as
+// entregas e a data de entrada
+func (b *Inbox) Put(agentID string, msg Message) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	box, ok := b.boxes[agentID]
+	if !ok {
+		return errs.New(errs.ErrNotFound, "inbox.Put", "agente %s sem caixa de entrada", agentID)
+	}
+	msg.Agent = agentID
+	if msg.EnqueuedAt.IsZero() {
+		msg.EnqueuedAt = time.Now()
+	}
+	box.entries = append(box.entries, &entry{msg: msg, visibleAt: time.Now()})
+	messagesTotal.Inc("enqueued")
+	b.notify()
+	return nil
+}
+
+// Receive entrega até max mensagens ao agente (zero entrega uma), aguardando até haver alguma
+// ou o contexto ser cancelado. Primeiro vêm as mensagens da própria caixa; com ela vazia, o
+// agente toma as disponíveis das caixas com mais mensagens entre os agentes dos seus tipos.
+func (b *Inbox) Receive(ctx context.Context, agentID string, max int) ([]Delivery, error) {
+	if max <= 0 {
+		max = 1
+	}
+	for {
+		b.mu.Lock()
+		box, ok := b.boxes[agentID]
+		if !ok {
+			b.mu.Unlock()
+			return nil, errs.New(errs.ErrNotFound, "inbox.Receive", "agente %s sem caixa de entrada", agentID)
+		}
+		now := time.Now()
+		b.expire(now)
+		deliveries := b.take(box, max, now)
+		if len(deliveries) == 0 {
+			deliveries = b.steal(agentID, box, max, now)
+		}
+		wake := b.nextVisible(now)
+		changed := b.changed
+		b.mu.Unlock()
+
+		if len(deliveries) > 0 {
+			return deliveries, nil
+		}
+		var timer *time.Timer
+		var expired <-chan time.Time
+		if !wake.IsZero() {
+			timer = time.NewTimer(wake.Sub(now))
+			expired = timer.C
+		}
+		select {
+		case <-ctx.Done():
+			if timer != nil {
+				timer.Stop()
+			}
+			return nil, errs.FromContext("inbox.Receive", ctx.Err())
+		case <-changed:
+		case <-expired:
+		}
+		if timer != nil {
+			timer.Stop()
+		}
+	}
+}
+
+// expire devolve as mensagens cujo prazo de visibilidade acabou, ou as enterra se já atingiram
+// o limite de entregas. Deve ser chamado com b.mu travado.
+func (b *Inbox) expire(now time.Time) {
+	for receipt, e := range b.leases {
+		if e.visibleAt.After(now) {
+			continue
+		}
+		delete(b.leases, receipt)
+		e.receipt = ""
+		messagesTotal.Inc("expired")
+		if e.msg.Deliveries >= b.config.MaxDeliveries {
+			e.msg.LastError = fmt.Sprintf("prazo de visibilidade esgotado em %d entregas", e.msg.Deliveries)
+			b.bury(e)
+		}
+	}
+}
+
+// take entrega as mensagens disponíveis da caixa. Deve ser chamado com b.mu travado.
+func (b *Inbox) take(box *mailbox, max int, now time.Time) []Delivery {
+	var deliveries []Delivery
+	for _, e := range box.entries {
+		if len(deliveries) == max {
+			break
+		}
+		if e.available(now) {
+			deliveries = append(deliveries, b.lease(e, now))
+		}
+	}
+	return deliveries
+}
+
+// Lease entrega até max mensagens disponíveis do tipo na caixa do agente sem que ele as
+// receba, como para transferi-las a outra instância: confirmadas com Ack, elas saem da caixa;
+// sem confirmação, voltam a ficar disponíveis no fim do prazo de visibilidade
+func (b *Inbox) Lease(agentID, msgType string, max int) []Delivery {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	box, ok := b.boxes[agentID]
+	if !ok {
+		return nil
+	}
+	now := time.Now()
+	b.expire(now)
+	var deliveries []Delivery
+	for _, e := range box.entries {
+		if len(deliveries) == max {
+			break
+		}
+		if e.available(now) && e.msg.Type == msgType {
+			deliveries = append(deliveries, b.lease(e, now))
+		}
+	}
+	return deliveries
+}
+
+// steal move para a caixa do agente e entrega as mensagens disponíveis dos seus tipos nas
+// caixas dos outros agentes, começando pela mais cheia. Deve ser chamado com b.mu travado.
+func (b *Inbox) steal(agentID string, box *mailbox, max int, now time.Time) []Delivery {
+	type victim struct {
+		id    string
+		ready int
+	}
+	var victims []victim
+	for _, id := range b.agentIDs() {
+		if id == agentID {
+			continue
+		}
+		ready := 0
+		for _, e := range b.boxes[id].entries {
+			if e.available(now) && box.types[e.msg.Type] {
+				ready++
+			}
+		}
+		if ready > 0 {
+			victims = append(victims, victim{id: id, ready: ready})
+		}
+	}
+	sort.SliceStable(victims, func(i, j int) bool { return victims[i].ready > victims[j].ready })
+
+	var deliveries []Delivery
+	for _, v := range victims {
+		source := b.boxes[v.id]
+		for _, e := range append([]*entry(nil), source.entries...) {
+			if len(deliveries) == max {
+				return deliveries
+			}
+			if !e.available(now) || !box.types[e.msg.Type] {
+				continue
+			}
+			source.remove(e)
+			e.msg.Agent = agentID
+			box.entries = append(box.entries, e)
+			messagesTotal.Inc("stolen")
+			deliveries = append(deliveries, b.lease(e, now))
+		}
+	}
+	return deliveries
+}
+
+// lease entrega a mensagem com um novo recibo. Deve ser chamado com b.mu travado.
+func (b *Inbox) lease(e *entry, now time.Time) Delivery {
+	e.receipt = b.nextID("rcpt")
+	e.visibleAt = now.Add(b.config.VisibilityTimeout)
+	e.msg.Deliveries++
+	b.leases[e.receipt] = e
+	messagesTotal.Inc("delivered")
+	if e.msg.Deliveries > 1 {
+		messagesTotal.Inc("redelivered")
+	}
+	return Delivery{Message: e.msg, Receipt: e.receipt, Deadline: e.visibleAt}
+}
+
+// nextVisible retorna o próximo instante em que uma mensagem fica disponível ou tem o prazo
+// esgotado, ou zero se não há nenhum. Deve ser chamado com b.mu travado.
+func (b *Inbox) nextVisible(now time.Time) time.Time {
+	var next time.Time
+	for _, box := range b.boxes {
+		for _, e := range box.entries {
+			if e.visibleAt.After(now) && (next.IsZero() || e.visibleAt.Before(next)) {
+				next = e.visibleAt
+			}
+		}
+	}
+	return next
+}
+
+// Ack confirma a entrega e remove a mensagem. Um recibo cujo prazo acabou retorna
+// errs.ErrNotFound: a mensagem pode já ter sido entregue a outro agente.
+func (b *Inbox) Ack(receipt string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	e, err := b.leased("inbox.Ack", receipt)
+	if err != nil {
+		return err
+	}
+	delete(b.leases, receipt)
+	if box, ok := b.boxes[e.msg.Agent]; ok {
+		box.remove(e)
+	}
+	messagesTotal.Inc("acked")
+	return nil
+}
+
+// Nack recusa a entrega: a mensagem volta a ficar disponível após delay, registrando a causa,
+// ou vai para as mensagens mortas se atingiu o limite de entregas
+func (b *Inbox) Nack(receipt string, delay time.Duration, cause error) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	e, err := b.leased("inbox.Nack", receipt)
+	if err != nil {
+		return err
+	}
+	delete(b.leases, receipt)
+	e.receipt = ""
+	e.visibleAt = time.Now().Add(delay)
+	if cause != nil {
+		e.msg.LastError = cause.Error()
+	}
+	messagesTotal.Inc("nacked")
+	if e.msg.Deliveries >= b.config.MaxDeliveries {
+		b.bury(e)
+	}
+	b.notify()
+	return nil
+}
+
+// Extend prorroga o prazo de visibilidade da entrega para d a partir de agora
+func (b *Inbox) Extend(receipt string, d time.Duration) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	e, err := b.leased("inbox.Extend", receipt)
+	if err != nil {
+		return err
+	}
+	e.visibleAt = time.Now().Add(d)
+	return nil
+}
+
+// leased retorna a entrada do recibo. Deve ser chamado com b.mu travado.
+func (b *Inbox) leased(op, receipt string) (*entry, error) {
+	e, ok := b.leases[receipt]
+	if !ok || !e.visibleAt.After(time.Now()) {
+		return nil, errs.New(errs.ErrNotFound, op, "recibo %s expirado ou desconhecido", receipt)
+	}
+	return e, nil
+}
+
+// bury move a mensagem para as mensagens mortas. Deve ser chamado com b.mu travado.
+func (b *Inbox) bury(e *entry) {
+	if box, ok := b.boxes[e.msg.Agent]; ok {
+		box.remove(e)
+	}
+	b.dead = append(b.dead, e.msg)
+	messagesTotal.Inc("dead")
+}
+
+// notify acorda os agentes aguardando em Receive. Deve ser chamado com b.mu travado.
+func (b *Inbox) notify() {
+	close(b.changed)
+	b.changed = make(chan struct{})
+}
+
+// nextID gera um identificador. Deve ser chamado com b.mu travado.
+func (b *Inbox) nextID(prefix string) string {
+	b.seq++
+	return fmt.Sprintf("%s_%d_%d", prefix, time.Now().UnixNano(), b.seq)
+}
+
+// DeadLetters retorna as mensagens que atingiram o limite de entregas ou ficaram sem agente
+func (b *Inbox) DeadLetters() []Message {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]Message(nil), b.dead...)
+}
+
+// AgentStats retorna o resumo da caixa de entrada do agente
+func (b *Inbox) AgentStats(agentID string) (Stats, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	box, ok := b.boxes[agentID]
+	if !ok {
+		return Stats{}, false
+	}
+	return box.stats(), true
+}
+
+// stats resume a caixa
+func (b *mailbox) stats() Stats {
+	var s Stats
+	for _, e := range b.entries {
+		if e.receipt != "" {
+			s.InFlight++
+		} else {
+			s.Pending++
+		}
+	}
+	return s
+}
+
+// Stats retorna o resumo da caixa de entrada de cada agente
+func (b *Inbox) Stats() map[string]Stats {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	stats := make(map[string]Stats, len(b.boxes))
+	for id, box := range b.boxes {
+		stats[id] = box.stats()
+	}
+	return stats
+}
+
+// Serve consome a caixa de entrada do agente até o contexto ser cancelado, uma mensagem por
+// vez: a entrega é confirmada quando o handler termina sem erro e recusada, com Backoff, quando
+// ele falha. O prazo de visibilidade não é prorrogado; tarefas longas devem chamar Extend ou
+// usar um VisibilityTimeout maior.
+func (b *Inbox) Serve(ctx context.Context, agentID string, handler Handler) error {
+	for {
+		deliveries, err := b.Receive(ctx, agentID, 1)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		for _, delivery := range deliveries {
+			if err := handler(ctx, delivery); err != nil {
+				// Um recibo expirado já foi devolvido à caixa por expire
+				b.Nack(delivery.Receipt, b.Backoff(delivery.Deliveries), err)
+				continue
+			}
+			b.Ack(delivery.Receipt)
+		}
+	}
+}
@@ -0,0 +1,223 @@
+package inbox
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/suissa/HiveMind/agents/errs"
+)
+
+// receive entrega uma mensagem ao agente com um prazo curto
+func receive(t *testing.T, b *Inbox, agentID string) (Delivery, bool) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	deliveries, err := b.Receive(ctx, agentID, 1)
+	if errors.Is(err, errs.ErrTimeout) || errors.Is(err, context.DeadlineExceeded) {
+		return Delivery{}, false
+	}
+	if err != nil {
+		t.Fatalf("Receive(%s): %v", agentID, err)
+	}
+	return deliveries[0], true
+}
+
+func TestDispatchLeastLoadedAndAck(t *testing.T) {
+	b := New(Config{})
+	b.Register("writer-a", "write")
+	b.Register("writer-b", "write")
+	b.Register("reviewer", "review")
+
+	for i := 0; i < 4; i++ {
+		if _, err := b.Dispatch("write", i); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := b.Dispatch("translate", nil); !errors.Is(err, errs.ErrNotFound) {
+		t.Fatalf("tipo sem agente deveria retornar ErrNotFound, veio %v", err)
+	}
+	stats := b.Stats()
+	if stats["writer-a"].Pending != 2 || stats["writer-b"].Pending != 2 || stats["reviewer"].Pending != 0 {
+		t.Fatalf("distribuição inesperada: %+v", stats)
+	}
+
+	d, ok := receive(t, b, "writer-a")
+	if !ok || d.Payload != 0 || d.Deliveries != 1 {
+		t.Fatalf("entrega inesperada: %+v", d)
+	}
+	if got := b.Stats()["writer-a"]; got.InFlight != 1 || got.Pending != 1 {
+		t.Fatalf("stats após a entrega: %+v", got)
+	}
+	if err := b.Ack(d.Receipt); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Ack(d.Receipt); !errors.Is(err, errs.ErrNotFound) {
+		t.Fatalf("segundo Ack deveria retornar ErrNotFound, veio %v", err)
+	}
+}
+
+func TestVisibilityTimeoutRebalances(t *testing.T) {
+	b := New(Config{VisibilityTimeout: 30 * time.Millisecond})
+	b.Register("slow", "write")
+	b.Register("fast", "write")
+	id, _ := b.Send("slow", "write", "post")
+
+	first, ok := receive(t, b, "slow")
+	if !ok || first.ID != id {
+		t.Fatalf("entrega inesperada: %+v", first)
+	}
+	// O agente lento não confirma: após o prazo, o agente ocioso toma a mensagem
+	second, ok := receive(t, b, "fast")
+	if !ok || second.ID != id || second.Deliveries != 2 || second.Agent != "fast" {
+		t.Fatalf("reentrega inesperada: %+v", second)
+	}
+	if err := b.Ack(first.Receipt); !errors.Is(err, errs.ErrNotFound) {
+		t.Fatalf("recibo expirado deveria retornar ErrNotFound, veio %v", err)
+	}
+	if err := b.Ack(second.Receipt); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestStealOnlyMatchingTypes(t *testing.T) {
+	b := New(Config{})
+	b.Register("busy", "write", "review")
+	b.Register("idle", "review")
+	b.Send("busy", "write", 1)
+	b.Send("busy", "review", 2)
+
+	d, ok := receive(t, b, "idle")
+	if !ok || d.Type != "review" {
+		t.Fatalf("o agente ocioso deveria tomar apenas review, veio %+v", d)
+	}
+	if _, ok := receive(t, b, "idle"); ok {
+		t.Fatal("o agente ocioso não deveria tomar mensagens de outro tipo")
+	}
+}
+
+func TestNackRedeliversAndDeadLetters(t *testing.T) {
+	b := New(Config{MaxDeliveries: 2})
+	b.Register("agent", "write")
+	b.Send("agent", "write", "post")
+
+	d, _ := receive(t, b, "agent")
+	if err := b.Nack(d.Receipt, 0, errors.New("falhou")); err != nil {
+		t.Fatal(err)
+	}
+	d, ok := receive(t, b, "agent")
+	if !ok || d.Deliveries != 2 || d.LastError != "falhou" {
+		t.Fatalf("reentrega inesperada: %+v", d)
+	}
+	b.Nack(d.Receipt, 0, errors.New("falhou de novo"))
+
+	if _, ok := receive(t, b, "agent"); ok {
+		t.Fatal("a mensagem deveria ter ido para as mensagens mortas")
+	}
+	dead := b.DeadLetters()
+	if len(dead) != 1 || dead[0].LastError != "falhou de novo" {
+		t.Fatalf("mensagens mortas: %+v", dead)
+	}
+}
+
+func TestNackDelay(t *testing.T) {
+	b := New(Config{})
+	b.Register("agent", "write")
+	b.Send("agent", "write", "post")
+
+	d, _ := receive(t, b, "agent")
+	start := time.Now()
+	b.Nack(d.Receipt, 50*time.Millisecond, nil)
+	if _, ok := receive(t, b, "agent"); !ok {
+		t.Fatal("a mensagem deveria voltar após a espera")
+	}
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+		t.Fatalf("mensagem reentregue antes da espera: %v", elapsed)
+	}
+}
+
+func TestUnregisterMovesMessages(t *testing.T) {
+	b := New(Config{})
+	b.Register("leaving", "write")
+	b.Register("orphan", "audit")
+	b.Send("leaving", "write", 1)
+	b.Send("orphan", "audit", 2)
+	receive(t, b, "leaving")
+
+	b.Register("staying", "write")
+	b.Unregister("leaving")
+	b.Unregister("orphan")
+
+	if got := b.Stats()["staying"]; got.Pending != 1 {
+		t.Fatalf("a mensagem em andamento deveria passar ao outro agente: %+v", got)
+	}
+	if dead := b.DeadLetters(); len(dead) != 1 || dead[0].Type != "audit" {
+		t.Fatalf("mensagens sem agente: %+v", dead)
+	}
+	if _, err := b.Receive(context.Background(), "leaving", 1); !errors.Is(err, errs.ErrNotFound) {
+		t.Fatalf("agente removido deveria retornar ErrNotFound, veio %v", err)
+	}
+}
+
+func TestServe(t *testing.T) {
+	b := New(Config{RetryDelay: time.Millisecond})
+	b.Register("agent", "write")
+	b.Send("agent", "write", "ok")
+	b.Send("agent", "write", "erro")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	handled := make(chan string, 10)
+	go func() {
+		done <- b.Serve(ctx, "agent", func(ctx context.Context, d Delivery) error {
+			handled <- d.Payload.(string)
+			if d.Payload == "erro" && d.Deliveries == 1 {
+				return errors.New("falha temporária")
+			}
+			return nil
+		})
+	}()
+	for i := 0; i < 3; i++ {
+		select {
+		case <-handled:
+		case <-time.After(time.Second):
+			t.Fatal("Serve não processou as mensagens")
+		}
+	}
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	if got := b.Stats()["agent"]; got.Pending != 0 || got.InFlight != 0 {
+		t.Fatalf("mensagens restantes: %+v", got)
+	}
+}
+
+func TestDispatchAffinity(t *testing.T) {
+	b := New(Config{})
+	b.Register("writer-1", "write")
+	b.Register("writer-2", "write")
+	b.Register("writer-3", "write")
+
+	owners := make(map[string]bool)
+	for i := 0; i < 5; i++ {
+		if _, err := b.DispatchAffinity("write", "parent-1", i); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for id, stats := range b.Stats() {
+		if stats.Pending > 0 {
+			owners[id] = true
+			if stats.Pending != 5 {
+				t.Fatalf("as mensagens relacionadas deveriam ficar com um agente: %+v", b.Stats())
+			}
+		}
+	}
+	if len(owners) != 1 {
+		t.Fatalf("esperava um dono, obtido %v", owners)
+	}
+	if _, err := b.DispatchAffinity("review", "parent-1", nil); !errors.Is(err, errs.ErrNotFound) {
+		t.Fatalf("tipo sem agente deveria retornar ErrNotFound, veio %v", err)
+	}
+}
@@ -1,14 +1,20 @@
 package agents
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"log"
+	"os"
 	"runtime"
 	"sync"
 	"time"
 
 	"github.com/streadway/amqp"
+
+	"github.com/suissa/HiveMind/agents/communication"
+	"github.com/suissa/HiveMind/agents/messages"
+	"github.com/suissa/HiveMind/agents/scaling"
 )
 
 // Thresholds para escalonamento
@@ -46,9 +52,12 @@ type ObserverInfrastructureAgent struct {
 	*AgentStruct
 	metricsMap     map[string]*AgentMetrics
 	metricsMapLock sync.RWMutex
-	rabbitmqConn   *amqp.Connection
-	rabbitmqCh     *amqp.Channel
+	rabbitmq       *communication.RabbitMQPool
 	systemMetrics  *SystemMetrics
+
+	// depths informa a profundidade real das filas consumidas por cada agente (queues)
+	depths scaling.DepthSource
+	queues map[string][]string
 }
 
 // OrchestratorInfrastructureAgent gerencia o escalonamento dos agentes
@@ -60,6 +69,10 @@ type OrchestratorInfrastructureAgent struct {
 	rabbitmqCh    *amqp.Channel
 	lastScaleTime time.Time
 	metrics       *SystemMetrics
+
+	// policies define os limites de cada tipo de agente; lastScaled aplica o cooldown por tipo
+	policies   *scaling.Policies
+	lastScaled map[string]time.Time
 }
 
 // AgentInstance representa uma instância de um agente
@@ -82,22 +95,66 @@ func NewObserverInfrastructureAgent() *ObserverInfrastructureAgent {
 		},
 		metricsMap:    make(map[string]*AgentMetrics),
 		systemMetrics: &SystemMetrics{},
+		queues:        make(map[string][]string),
 	}
 
 	// Inicializar conexão com RabbitMQ
-	conn, err := amqp.Dial(fmt.Sprintf("amqp://guest:guest@%s:%d/", RABBITMQ_HOST, RABBITMQ_PORT))
-	if err != nil {
+	config := communication.ConnectionConfigFromEnv("RABBITMQ", RABBITMQ_HOST, RABBITMQ_PORT)
+	agent.rabbitmq = communication.SharedRabbitMQPool(config)
+	if err := agent.rabbitmq.Connect(); err != nil {
 		log.Fatalf("Falha ao conectar ao RabbitMQ: %v", err)
 	}
-	agent.rabbitmqConn = conn
+	agent.depths = rabbitMQManagement(config)
 
-	ch, err := conn.Channel()
-	if err != nil {
-		log.Fatalf("Falha ao abrir canal: %v", err)
+	return agent
+}
+
+// rabbitMQManagement configura a leitura da profundidade das filas pela API de gerenciamento
+// do RabbitMQ (RABBITMQ_MANAGEMENT_URL, padrão na porta 15672 do mesmo host), com as
+// credenciais da conexão AMQP
+func rabbitMQManagement(config *communication.ConnectionConfig) scaling.DepthSource {
+	managementURL := os.Getenv("RABBITMQ_MANAGEMENT_URL")
+	if managementURL == "" {
+		managementURL = fmt.Sprintf("http://%s:15672", config.Host)
 	}
-	agent.rabbitmqCh = ch
+	username, password := config.Username, config.Password
+	if username == "" {
+		username, password = "guest", "guest"
+	}
+	return scaling.NewRabbitMQManagement(managementURL, username, password, config.VHost)
+}
 
-	return agent
+// SetQueueDepthSource define de onde vem a profundidade das filas, como
+// scaling.Sources(rabbitmq, kafkaLag) para somar os dois brokers
+func (o *ObserverInfrastructureAgent) SetQueueDepthSource(source scaling.DepthSource) {
+	o.metricsMapLock.Lock()
+	defer o.metricsMapLock.Unlock()
+	o.depths = source
+}
+
+// WatchQueues associa ao agente as filas (ou tópicos) que ele consome: a soma das suas
+// profundidades passa a ser o TasksInQueue do agente
+func (o *ObserverInfrastructureAgent) WatchQueues(agentName string, queues ...string) {
+	o.metricsMapLock.Lock()
+	defer o.metricsMapLock.Unlock()
+	o.queues[agentName] = append(o.queues[agentName], queues...)
+}
+
+// queueDepths consulta a profundidade das filas observadas. Deve ser chamado com
+// metricsMapLock travado; sem filas observadas o broker não é consultado.
+func (o *ObserverInfrastructureAgent) queueDepths() (map[string]int, bool) {
+	if o.depths == nil || len(o.queues) == 0 {
+		return nil, false
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 4*time.Second)
+	defer cancel()
+	depths, err := o.depths.QueueDepths(ctx)
+	if err != nil {
+		// As métricas anteriores são mantidas até a próxima coleta
+		log.Printf("⚠️ Erro ao consultar a profundidade das filas: %v", err)
+		return nil, false
+	}
+	return depths, true
 }
 
 // NewOrchestratorInfrastructureAgent cria uma nova instância do OrchestratorInfrastructureAgent
@@ -114,10 +171,12 @@ func NewOrchestratorInfrastructureAgent() *OrchestratorInfrastructureAgent {
 		instances:     make(map[string][]*AgentInstance),
 		lastScaleTime: time.Now(),
 		metrics:       &SystemMetrics{},
+		policies:      defaultScalingPolicies(),
+		lastScaled:    make(map[string]time.Time),
 	}
 
 	// Inicializar conexão com RabbitMQ
-	conn, err := amqp.Dial(fmt.Sprintf("amqp://guest:guest@%s:%d/", RABBITMQ_HOST, RABBITMQ_PORT))
+	conn, err := communication.DialRabbitMQ(communication.ConnectionConfigFromEnv("RABBITMQ", RABBITMQ_HOST, RABBITMQ_PORT))
 	if err != nil {
 		log.Fatalf("Falha ao conectar ao RabbitMQ: %v", err)
 	}
@@ -161,6 +220,7 @@ func (o *ObserverInfrastructureAgent) collectMetrics() {
 		var totalErrors float64
 		var agentCount int
 
+		depths, ok := o.queueDepths()
 		for agentName, metrics := range o.metricsMap {
 			// Coletar métricas do agente
 			var m runtime.MemStats
@@ -169,6 +229,9 @@ func (o *ObserverInfrastructureAgent) collectMetrics() {
 			metrics.Memory = m.Alloc
 			metrics.CPU = getCPUUsage()
 			metrics.LastUpdated = time.Now().Unix()
+			if queues, watched := o.queues[agentName]; ok && watched {
+				metrics.TasksInQueue = scaling.Depth(depths, queues...)
+			}
 
 			// Acumular métricas do sistema
 			totalCPU += metrics.CPU
@@ -209,47 +272,34 @@ func (o *ObserverInfrastructureAgent) collectMetrics() {
 // publishMetric publica uma métrica específica no RabbitMQ
 func (o *ObserverInfrastructureAgent) publishMetric(agentName, metricName string, value float64) {
 	queueName := fmt.Sprintf("metrics.%s.%s", agentName, metricName)
+	// As métricas são coletadas a cada 5 segundos; uma publicação não espera pela seguinte
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
 
 	// Declarar a fila para a métrica
-	_, err := o.rabbitmqCh.QueueDeclare(
-		queueName, // nome
-		true,      // durable
-		false,     // delete when unused
-		false,     // exclusive
-		false,     // no-wait
-		nil,       // arguments
-	)
-	if err != nil {
+	if err := o.rabbitmq.DeclareQueue(ctx, queueName); err != nil {
 		log.Printf("Erro ao declarar fila %s: %v", queueName, err)
 		return
 	}
 
 	// Criar mensagem com a métrica
-	message := struct {
-		Value     float64 `json:"value"`
-		Timestamp int64   `json:"timestamp"`
-	}{
+	message := messages.MetricSample{
 		Value:     value,
 		Timestamp: time.Now().Unix(),
 	}
 
-	body, err := json.Marshal(message)
+	body, err := messages.Encode(message)
 	if err != nil {
 		log.Printf("Erro ao converter mensagem para JSON: %v", err)
 		return
 	}
 
 	// Publicar mensagem
-	err = o.rabbitmqCh.Publish(
-		"",        // exchange
-		queueName, // routing key
-		false,     // mandatory
-		false,     // immediate
-		amqp.Publishing{
-			ContentType:  "application/json",
-			Body:         body,
-			DeliveryMode: 2, // mensagem persistente
-		})
+	err = o.rabbitmq.Publish(ctx, "", queueName, amqp.Publishing{
+		ContentType:  "application/json",
+		Body:         body,
+		DeliveryMode: 2, // mensagem persistente
+	})
 	if err != nil {
 		log.Printf("Erro ao publicar métrica %s: %v", queueName, err)
 	}
@@ -316,63 +366,123 @@ func (o *OrchestratorInfrastructureAgent) UpdateMetrics(metrics *SystemMetrics)
 	o.metrics = metrics
 }
 
-// ScaleSystem escala o sistema baseado nas métricas atuais
-func (o *OrchestratorInfrastructureAgent) ScaleSystem() error {
-	if !o.CheckScaling() {
-		return nil
+// UpdateQueueDepth informa a profundidade da fila consumida pelas instâncias do agente. As
+// instâncias dividem a fila, então cada uma recebe a sua parte em TasksInQueue e ScaleSystem
+// escala quando a fila por instância passa do limite.
+func (o *OrchestratorInfrastructureAgent) UpdateQueueDepth(agentType string, depth int) {
+	o.instancesLock.Lock()
+	defer o.instancesLock.Unlock()
+
+	instances := o.instances[agentType]
+	for i, instance := range instances {
+		share := depth / len(instances)
+		if i < depth%len(instances) {
+			share++
+		}
+		instance.Metrics.TasksInQueue = share
 	}
+}
 
+// SetScalingPolicies define as políticas de escalonamento por tipo de agente (ver
+// scaling.LoadPolicies); os tipos sem política própria usam a padrão
+func (o *OrchestratorInfrastructureAgent) SetScalingPolicies(policies *scaling.Policies) error {
+	if err := policies.Validate(); err != nil {
+		return err
+	}
 	o.instancesLock.Lock()
 	defer o.instancesLock.Unlock()
+	o.policies = policies
+	return nil
+}
 
-	// Escalar cada tipo de agente
-	for agentType, instances := range o.instances {
-		// Verificar métricas do tipo de agente
-		var totalCPU float64
-		var totalMemory float64
-		var totalTasks int
-		var totalErrors float64
-		var instanceCount int
+// ScalingPolicy retorna a política efetiva do tipo de agente
+func (o *OrchestratorInfrastructureAgent) ScalingPolicy(agentType string) scaling.Policy {
+	o.instancesLock.RLock()
+	defer o.instancesLock.RUnlock()
+	return o.policies.For(agentType)
+}
 
-		for _, instance := range instances {
-			metrics := instance.Metrics
-			totalCPU += metrics.CPU
-			totalMemory += float64(metrics.Memory)
-			totalTasks += metrics.TasksInQueue
-			totalErrors += metrics.ErrorRate
-			instanceCount++
+// ScalingPolicies retorna as políticas de escalonamento configuradas
+func (o *OrchestratorInfrastructureAgent) ScalingPolicies() *scaling.Policies {
+	o.instancesLock.RLock()
+	defer o.instancesLock.RUnlock()
+	return o.policies
+}
+
+// defaultScalingPolicies mantém os limites globais como política padrão
+func defaultScalingPolicies() *scaling.Policies {
+	return &scaling.Policies{Default: scaling.Policy{
+		MinInstances: 1,
+		Cooldown:     cooldownPeriod * time.Second,
+		Thresholds: scaling.Thresholds{
+			CPU:       cpuThreshold,
+			Memory:    memoryThreshold,
+			Tasks:     tasksThreshold,
+			ErrorRate: errorThreshold,
+		},
+	}}
+}
+
+// ScaleSystem ajusta o número de instâncias de cada tipo de agente pela sua política:
+// limites por métrica, target tracking da fila, mínimo, máximo e cooldown por tipo. Novas
+// instâncias são clones da primeira; na redução, as instâncias mais novas são removidas.
+func (o *OrchestratorInfrastructureAgent) ScaleSystem() error {
+	o.instancesLock.Lock()
+	defer o.instancesLock.Unlock()
+
+	now := time.Now()
+	for agentType, instances := range o.instances {
+		if len(instances) == 0 {
+			continue
+		}
+		desired, ok := o.policies.For(agentType).Decide(sampleOf(instances), o.lastScaled[agentType], now)
+		if !ok {
+			continue
 		}
 
-		// Calcular médias
-		avgCPU := totalCPU / float64(instanceCount)
-		avgMemory := totalMemory / float64(instanceCount)
-		avgTasks := float64(totalTasks) / float64(instanceCount)
-		avgErrors := totalErrors / float64(instanceCount)
-
-		// Decidir se precisa escalar
-		if avgCPU > cpuThreshold ||
-			avgMemory > memoryThreshold ||
-			avgTasks > float64(tasksThreshold) ||
-			avgErrors > errorThreshold {
+		for len(instances) < desired {
 			// Criar nova instância do agente
 			baseInstance := instances[0]
 			newAgent := baseInstance.Agent.Clone()
-
-			// Registrar nova instância
-			instance := &AgentInstance{
+			instances = append(instances, &AgentInstance{
 				Agent:      newAgent,
-				LastScaled: time.Now(),
+				LastScaled: now,
 				Metrics:    &AgentMetrics{AgentName: newAgent.GetName()},
+			})
+			log.Printf("🔄 Escalando agente %s: nova instância %s criada a partir de %s", agentType, newAgent.GetID(), newAgent.ParentID)
+		}
+		if len(instances) > desired {
+			for _, removed := range instances[desired:] {
+				log.Printf("🔽 Reduzindo agente %s: instância %s removida", agentType, removed.Agent.GetID())
 			}
-
-			o.instances[agentType] = append(o.instances[agentType], instance)
-			log.Printf("🔄 Escalando agente %s: nova instância criada", agentType)
+			instances = instances[:desired]
 		}
+
+		o.instances[agentType] = instances
+		o.lastScaled[agentType] = now
+		o.lastScaleTime = now
 	}
 
 	return nil
 }
 
+// sampleOf resume as métricas das instâncias de um tipo de agente: médias por instância e o
+// total da fila
+func sampleOf(instances []*AgentInstance) scaling.Sample {
+	sample := scaling.Sample{Instances: len(instances)}
+	for _, instance := range instances {
+		sample.CPU += instance.Metrics.CPU
+		sample.Memory += float64(instance.Metrics.Memory)
+		sample.ErrorRate += instance.Metrics.ErrorRate
+		sample.TasksInQueue += instance.Metrics.TasksInQueue
+	}
+	n := float64(len(instances))
+	sample.CPU /= n
+	sample.Memory /= n
+	sample.ErrorRate /= n
+	return sample
+}
+
 // getCPUUsage retorna o uso atual de CPU (simulado)
 func getCPUUsage() float64 {
 	return 50.0 // Valor simulado de 50% de uso de CPU
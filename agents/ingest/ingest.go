@@ -0,0 +1,358 @@
+// Package ingest liga tópicos do Kafka ou assuntos do NATS às tarefas do HiveMind: cada
+// registro recebido é transformado em um TaskRequest por um template e submetido ao
+// roteamento, para que sistemas externos acionem os agentes sem código de integração.
+//
+// Os templates usam text/template sobre o Record: .Subject, .Headers, .Raw (o corpo como
+// texto), .Value (o corpo decodificado de JSON, ou o texto quando não é JSON) e .Time; campos
+// ausentes renderizam vazio. Além das funções padrão há json, default e uuid, por exemplo:
+//
+//	Description: "Analisar o produto {{.Value.name}}"
+//	Parameters:  {"sku": "{{.Value.sku}}", "tags": "{{json .Value.tags}}"}
+//
+// Um registro que não pode ser transformado ou submetido faz o handler retornar erro, e o
+// cliente aplica a sua política de reentrega (no Kafka, retry topics e DLQ).
+package ingest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/suissa/HiveMind/agents/communication"
+	"github.com/suissa/HiveMind/agents/messages"
+	"github.com/suissa/HiveMind/agents/metrics"
+)
+
+// Resultados da ingestão de um registro
+const (
+	ResultSubmitted = "submitted" // A tarefa foi submetida
+	ResultFiltered  = "filtered"  // O filtro descartou o registro
+	ResultFailed    = "failed"    // A transformação ou a submissão falhou
+)
+
+var recordsTotal = metrics.Default.Counter("hivemind_ingest_records_total",
+	"Registros recebidos pelas pontes de ingestão por assunto e resultado (submitted, filtered ou failed)", "subject", "result")
+
+// Source é o barramento de origem dos registros; os clientes Kafka e NATS de
+// agents/communication o implementam
+type Source interface {
+	Subscribe(subject string, handler communication.MessageHandler) error
+	Unsubscribe(subject string) error
+}
+
+// HeaderSource é implementada pelas origens que entregam os headers dos registros
+type HeaderSource interface {
+	SubscribeHeaders(subject string, handler communication.HeaderHandler) error
+}
+
+// Submitter submete as tarefas transformadas ao roteamento
+type Submitter func(ctx context.Context, task messages.TaskRequest) error
+
+// Template transforma um registro em tarefa; cada campo é um text/template
+type Template struct {
+	ID          string            `json:"id,omitempty" yaml:"id,omitempty"` // Padrão: um UUID por registro
+	Description string            `json:"description" yaml:"description"`
+	Tenant      string            `json:"tenant,omitempty" yaml:"tenant,omitempty"`
+	Parameters  map[string]string `json:"parameters,omitempty" yaml:"parameters,omitempty"` // Saídas que são JSON válido (números, listas) entram decodificadas
+	Filter      string            `json:"filter,omitempty" yaml:"filter,omitempty"`         // Descarta o registro quando renderiza vazio ou "false"
+}
+
+// Config liga um tópico (ou assunto) a um template
+type Config struct {
+	Subject  string   `json:"subject" yaml:"subject"`
+	Template Template `json:"template" yaml:"template"`
+}
+
+// Record são os dados de um registro disponíveis aos templates
+type Record struct {
+	Subject string
+	Headers map[string]string
+	Raw     string
+	Value   interface{}
+	Time    time.Time
+}
+
+// Stats são as contagens de uma ligação
+type Stats struct {
+	Subject   string `json:"subject"`
+	Received  int64  `json:"received"`
+	Submitted int64  `json:"submitted"`
+	Filtered  int64  `json:"filtered"`
+	Failed    int64  `json:"failed"`
+	LastError string `json:"last_error,omitempty"`
+}
+
+var funcs = template.FuncMap{
+	"json": func(v interface{}) (string, error) {
+		data, err := json.Marshal(v)
+		return string(data), err
+	},
+	"default": func(fallback, v interface{}) interface{} {
+		if v == nil || v == "" {
+			return fallback
+		}
+		return v
+	},
+	"uuid": uuid.NewString,
+}
+
+// compiled é um template pronto para uso
+type compiled struct {
+	id, description, tenant, filter *template.Template
+	parameters                      map[string]*template.Template
+}
+
+// compile valida o template
+func (t Template) compile(subject string) (*compiled, error) {
+	if strings.TrimSpace(t.Description) == "" {
+		return nil, fmt.Errorf("template de ingestão de %s sem descrição", subject)
+	}
+	parse := func(field, text string) (*template.Template, error) {
+		if text == "" {
+			return nil, nil
+		}
+		tmpl, err := template.New(field).Funcs(funcs).Option("missingkey=zero").Parse(text)
+		if err != nil {
+			return nil, fmt.Errorf("template de ingestão de %s inválido no campo %s: %v", subject, field, err)
+		}
+		return tmpl, nil
+	}
+	c := &compiled{parameters: make(map[string]*template.Template, len(t.Parameters))}
+	var err error
+	if c.id, err = parse("id", t.ID); err != nil {
+		return nil, err
+	}
+	if c.description, err = parse("description", t.Description); err != nil {
+		return nil, err
+	}
+	if c.tenant, err = parse("tenant", t.Tenant); err != nil {
+		return nil, err
+	}
+	if c.filter, err = parse("filter", t.Filter); err != nil {
+		return nil, err
+	}
+	for name, text := range t.Parameters {
+		if c.parameters[name], err = parse("parameters."+name, text); err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// render executa um campo do template; campos do template e do registro ausentes renderizam
+// vazio, no lugar do "<no value>" do text/template
+func render(tmpl *template.Template, record Record) (string, error) {
+	if tmpl == nil {
+		return "", nil
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, record); err != nil {
+		return "", fmt.Errorf("erro ao renderizar %s: %v", tmpl.Name(), err)
+	}
+	return strings.TrimSpace(strings.ReplaceAll(buf.String(), "<no value>", "")), nil
+}
+
+// transform aplica o template ao registro; ok é falso quando o filtro o descarta
+func (c *compiled) transform(record Record) (task messages.TaskRequest, ok bool, err error) {
+	if c.filter != nil {
+		keep, err := render(c.filter, record)
+		if err != nil {
+			return task, false, err
+		}
+		if keep == "" || keep == "false" {
+			return task, false, nil
+		}
+	}
+	if task.ID, err = render(c.id, record); err != nil {
+		return task, false, err
+	}
+	if task.ID == "" {
+		task.ID = uuid.NewString()
+	}
+	if task.Description, err = render(c.description, record); err != nil {
+		return task, false, err
+	}
+	if task.Description == "" {
+		return task, false, fmt.Errorf("o registro de %s gerou uma tarefa sem descrição", record.Subject)
+	}
+	if task.Tenant, err = render(c.tenant, record); err != nil {
+		return task, false, err
+	}
+	if len(c.parameters) > 0 {
+		task.Parameters = make(map[string]interface{}, len(c.parameters))
+		for name, tmpl := range c.parameters {
+			text, err := render(tmpl, record)
+			if err != nil {
+				return task, false, err
+			}
+			var value interface{}
+			if json.Unmarshal([]byte(text), &value) != nil {
+				value = text
+			}
+			task.Parameters[name] = value
+		}
+	}
+	return task, true, nil
+}
+
+// NewRecord monta os dados do template a partir de um registro recebido
+func NewRecord(subject string, data []byte, headers map[string]string) Record {
+	record := Record{Subject: subject, Headers: headers, Raw: string(data), Time: time.Now()}
+	if record.Headers == nil {
+		record.Headers = map[string]string{}
+	}
+	if json.Unmarshal(data, &record.Value) != nil {
+		record.Value = record.Raw
+	}
+	return record
+}
+
+// binding é uma ligação ativa e as suas contagens
+type binding struct {
+	config   Config
+	template *compiled
+	stats    Stats
+}
+
+// Bridge consome os tópicos configurados e submete as tarefas transformadas
+type Bridge struct {
+	source     Source
+	submit     Submitter
+	bindings   map[string]*binding
+	subscribed []string
+	mu         sync.Mutex
+}
+
+// New cria a ponte, validando os templates das ligações
+func New(source Source, submit Submitter, configs ...Config) (*Bridge, error) {
+	b := &Bridge{source: source, submit: submit, bindings: make(map[string]*binding)}
+	for _, config := range configs {
+		if config.Subject == "" {
+			return nil, fmt.Errorf("ligação de ingestão sem tópico")
+		}
+		if _, exists := b.bindings[config.Subject]; exists {
+			return nil, fmt.Errorf("tópico de ingestão repetido: %s", config.Subject)
+		}
+		tmpl, err := config.Template.compile(config.Subject)
+		if err != nil {
+			return nil, err
+		}
+		b.bindings[config.Subject] = &binding{config: config, template: tmpl, stats: Stats{Subject: config.Subject}}
+	}
+	return b, nil
+}
+
+// Transform aplica o template do tópico ao registro sem submetê-lo, para testar templates;
+// ok é falso quando o filtro descarta o registro
+func (b *Bridge) Transform(subject string, data []byte, headers map[string]string) (messages.TaskRequest, bool, error) {
+	b.mu.Lock()
+	bind, exists := b.bindings[subject]
+	b.mu.Unlock()
+	if !exists {
+		return messages.TaskRequest{}, false, fmt.Errorf("tópico de ingestão não configurado: %s", subject)
+	}
+	return bind.template.transform(NewRecord(subject, data, headers))
+}
+
+// Start inscreve a ponte nos tópicos configurados
+func (b *Bridge) Start(ctx context.Context) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	subjects := make([]string, 0, len(b.bindings))
+	for subject := range b.bindings {
+		subjects = append(subjects, subject)
+	}
+	sort.Strings(subjects)
+
+	headerSource, withHeaders := b.source.(HeaderSource)
+	for _, subject := range subjects {
+		bind := b.bindings[subject]
+		var err error
+		if withHeaders {
+			err = headerSource.SubscribeHeaders(subject, func(ctx context.Context, _ string, data []byte, headers map[string]string) error {
+				return b.handle(ctx, bind, data, headers)
+			})
+		} else {
+			err = b.source.Subscribe(subject, func(ctx context.Context, _ string, data []byte) error {
+				return b.handle(ctx, bind, data, nil)
+			})
+		}
+		if err != nil {
+			return fmt.Errorf("erro ao consumir o tópico de ingestão %s: %w", subject, err)
+		}
+		b.subscribed = append(b.subscribed, subject)
+	}
+	return nil
+}
+
+// Stop cancela as inscrições; os registros em processamento terminam normalmente
+func (b *Bridge) Stop(ctx context.Context) error {
+	b.mu.Lock()
+	subjects := b.subscribed
+	b.subscribed = nil
+	b.mu.Unlock()
+	var errs []error
+	for _, subject := range subjects {
+		if err := b.source.Unsubscribe(subject); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", subject, err))
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("erro ao encerrar a ingestão: %v", errs)
+	}
+	return nil
+}
+
+// Stats retorna as contagens de cada tópico, por nome
+func (b *Bridge) Stats() []Stats {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	stats := make([]Stats, 0, len(b.bindings))
+	for _, bind := range b.bindings {
+		stats = append(stats, bind.stats)
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Subject < stats[j].Subject })
+	return stats
+}
+
+// handle transforma e submete um registro
+func (b *Bridge) handle(ctx context.Context, bind *binding, data []byte, headers map[string]string) error {
+	task, ok, err := bind.template.transform(NewRecord(bind.config.Subject, data, headers))
+	if err == nil && ok {
+		err = b.submit(ctx, task)
+	}
+
+	result := ResultSubmitted
+	switch {
+	case err != nil:
+		result = ResultFailed
+	case !ok:
+		result = ResultFiltered
+	}
+	b.mu.Lock()
+	bind.stats.Received++
+	switch result {
+	case ResultSubmitted:
+		bind.stats.Submitted++
+	case ResultFiltered:
+		bind.stats.Filtered++
+	default:
+		bind.stats.Failed++
+		bind.stats.LastError = err.Error()
+	}
+	b.mu.Unlock()
+	recordsTotal.Inc(bind.config.Subject, result)
+
+	if err != nil {
+		return fmt.Errorf("erro ao ingerir registro de %s: %w", bind.config.Subject, err)
+	}
+	return nil
+}
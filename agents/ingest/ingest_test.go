@@ -0,0 +1,133 @@
+package ingest
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/suissa/HiveMind/agents/communication"
+	"github.com/suissa/HiveMind/agents/messages"
+)
+
+// source entrega os registros publicados aos handlers inscritos
+type source struct {
+	handlers map[string]communication.HeaderHandler
+	mu       sync.Mutex
+}
+
+func (s *source) Subscribe(subject string, handler communication.MessageHandler) error {
+	return s.SubscribeHeaders(subject, func(ctx context.Context, subject string, data []byte, _ map[string]string) error {
+		return handler(ctx, subject, data)
+	})
+}
+
+func (s *source) SubscribeHeaders(subject string, handler communication.HeaderHandler) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.handlers == nil {
+		s.handlers = make(map[string]communication.HeaderHandler)
+	}
+	s.handlers[subject] = handler
+	return nil
+}
+
+func (s *source) Unsubscribe(subject string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.handlers, subject)
+	return nil
+}
+
+func (s *source) deliver(subject, data string, headers map[string]string) error {
+	s.mu.Lock()
+	handler := s.handlers[subject]
+	s.mu.Unlock()
+	if handler == nil {
+		return nil
+	}
+	return handler(context.Background(), subject, []byte(data), headers)
+}
+
+func TestBridgeSubmitsTransformedRecords(t *testing.T) {
+	src := &source{}
+	var submitted []messages.TaskRequest
+	bridge, err := New(src, func(_ context.Context, task messages.TaskRequest) error {
+		submitted = append(submitted, task)
+		return nil
+	}, Config{
+		Subject: "produtos.novos",
+		Template: Template{
+			ID:          "produto-{{.Value.sku}}",
+			Description: "Criar campanha para {{.Value.name}} ({{default \"sem categoria\" .Value.category}})",
+			Tenant:      "{{.Headers.tenant}}",
+			Parameters:  map[string]string{"sku": "{{.Value.sku}}", "price": "{{.Value.price}}", "tags": "{{json .Value.tags}}"},
+			Filter:      "{{.Value.active}}",
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := bridge.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	record := `{"sku":"A-1","name":"Tênis","price":199.9,"tags":["verão"],"active":true}`
+	if err := src.deliver("produtos.novos", record, map[string]string{"tenant": "loja"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := src.deliver("produtos.novos", `{"sku":"A-2","name":"Bota","active":false}`, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := src.deliver("produtos.novos", `não é json`, nil); err == nil {
+		t.Fatal("registro que não casa com o template deveria retornar erro para reentrega")
+	}
+
+	if len(submitted) != 1 {
+		t.Fatalf("esperava uma tarefa submetida: %+v", submitted)
+	}
+	task := submitted[0]
+	if task.ID != "produto-A-1" || task.Tenant != "loja" || task.Description != "Criar campanha para Tênis (sem categoria)" {
+		t.Fatalf("tarefa inesperada: %+v", task)
+	}
+	if task.Parameters["sku"] != "A-1" || task.Parameters["price"] != 199.9 {
+		t.Fatalf("parâmetros inesperados: %+v", task.Parameters)
+	}
+	if tags, ok := task.Parameters["tags"].([]interface{}); !ok || len(tags) != 1 || tags[0] != "verão" {
+		t.Fatalf("a lista deveria ser decodificada: %#v", task.Parameters["tags"])
+	}
+
+	stats := bridge.Stats()[0]
+	if stats.Received != 3 || stats.Submitted != 1 || stats.Filtered != 1 || stats.Failed != 1 || stats.LastError == "" {
+		t.Fatalf("contagens inesperadas: %+v", stats)
+	}
+
+	if err := bridge.Stop(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	src.deliver("produtos.novos", record, nil)
+	if len(submitted) != 1 {
+		t.Fatal("a ponte parada não deveria submeter tarefas")
+	}
+}
+
+func TestNewValidatesTemplates(t *testing.T) {
+	submit := func(context.Context, messages.TaskRequest) error { return nil }
+	for name, config := range map[string]Config{
+		"sem tópico":    {Template: Template{Description: "x"}},
+		"sem descrição": {Subject: "a"},
+		"inválido":      {Subject: "a", Template: Template{Description: "{{.Value"}},
+	} {
+		if _, err := New(&source{}, submit, config); err == nil {
+			t.Errorf("%s: esperava erro", name)
+		}
+	}
+
+	bridge, err := New(&source{}, submit, Config{Subject: "texto", Template: Template{Description: "Resumir: {{.Raw}}"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	task, ok, err := bridge.Transform("texto", []byte("relatório trimestral"), nil)
+	if err != nil || !ok || task.Description != "Resumir: relatório trimestral" || task.ID == "" {
+		t.Fatalf("transformação inesperada: %+v %v %v", task, ok, err)
+	}
+}
@@ -0,0 +1,126 @@
+package agents
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/suissa/HiveMind/agents/interop"
+)
+
+// ExportAgents exporta os agentes, com as ferramentas que cada um pode usar no registro e os
+// templates de prompt, no formato informado (interop.FormatLangChain ou FormatLlamaIndex).
+// Sem registro, usa o registro de ferramentas de cada agente.
+func ExportAgents(format string, registry *ToolRegistry, agents ...*CognitiveAgent) ([]byte, error) {
+	bundle := interop.Bundle{}
+	used := make(map[string]*ToolRegistry)
+	for _, agent := range agents {
+		exported := interop.Agent{
+			ID:          agent.GetID(),
+			Name:        agent.GetName(),
+			Role:        agent.Role,
+			Goal:        agent.Goal,
+			Backstory:   agent.Backstory,
+			Model:       agent.Model,
+			Temperature: agent.Temperature,
+			MaxTokens:   agent.MaxTokens,
+			Prompts:     agent.PromptTemplates,
+		}
+		tools := registry
+		if tools == nil {
+			tools = agent.tools
+		}
+		if tools != nil {
+			exported.Tools = tools.Allowed(agent)
+			for _, name := range exported.Tools {
+				if _, ok := used[name]; !ok {
+					used[name] = tools
+				}
+			}
+		}
+		bundle.Agents = append(bundle.Agents, exported)
+	}
+	bundle.Tools = exportTools(used)
+	return interop.Export(bundle, format)
+}
+
+// Export exporta os agentes da configuração no formato informado, com a persona composta
+// como prompt de sistema e as ferramentas da matriz tool_permissions. As descrições e os
+// schemas das ferramentas vêm do registro, quando informado.
+func (c *AgentsConfig) Export(format string, registry *ToolRegistry) ([]byte, error) {
+	bundle := interop.Bundle{}
+	used := make(map[string]*ToolRegistry)
+	for _, agent := range c.Agents {
+		p, err := c.Persona(agent)
+		if err != nil {
+			return nil, err
+		}
+		exported := interop.Agent{
+			ID:          agent.ID,
+			Name:        agent.Name,
+			Description: agent.Description,
+			Role:        agent.Role,
+			Goal:        agent.Goal,
+			Backstory:   p.Prompt(),
+			Model:       agent.Model,
+			Temperature: 0.7,
+		}
+		if !c.ToolPermissions.Empty() {
+			exported.Tools = permittedTools(c.ToolPermissions, agent, registry)
+		} else if registry != nil {
+			exported.Tools = registry.Allowed(&AgentStruct{ID: agent.ID, Role: agent.Role})
+		}
+		for _, name := range exported.Tools {
+			used[name] = registry
+		}
+		bundle.Agents = append(bundle.Agents, exported)
+	}
+	bundle.Tools = exportTools(used)
+	return interop.Export(bundle, format)
+}
+
+// permittedTools retorna as ferramentas liberadas ao agente pelo ID ou pelo papel. O curinga
+// "*" corresponde às ferramentas do registro.
+func permittedTools(permissions ToolPermissions, agent AgentConfig, registry *ToolRegistry) []string {
+	names := append(append([]string{}, permissions.Agents[agent.ID]...), permissions.Roles[agent.Role]...)
+	seen := make(map[string]bool)
+	var tools []string
+	for i := 0; i < len(names); i++ {
+		name := names[i]
+		if name == "*" {
+			if registry != nil {
+				names = append(names, registry.List()...)
+			}
+			continue
+		}
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		tools = append(tools, name)
+	}
+	sort.Strings(tools)
+	return tools
+}
+
+// exportTools descreve as ferramentas usadas pelos agentes, em ordem alfabética. Ferramentas
+// sem registro (ou fora dele) são exportadas só com o nome.
+func exportTools(used map[string]*ToolRegistry) []interop.Tool {
+	names := make([]string, 0, len(used))
+	for name := range used {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	tools := make([]interop.Tool, 0, len(names))
+	for _, name := range names {
+		tool := interop.Tool{Name: name}
+		if registry := used[name]; registry != nil {
+			if registered, ok := registry.Get(name); ok {
+				tool.Description = strings.TrimSpace(registered.Description())
+			}
+			tool.Schema, _ = registry.Schema(name)
+		}
+		tools = append(tools, tool)
+	}
+	return tools
+}
@@ -0,0 +1,146 @@
+// Package interop exporta as definições dos agentes (prompts de sistema, modelos, ferramentas
+// e templates de prompt) em formatos consumidos por LangChain e LlamaIndex, para migrar ou
+// integrar os agentes a pipelines em Python.
+package interop
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/suissa/HiveMind/agents/errs"
+)
+
+// Formatos de exportação suportados
+const (
+	FormatLangChain  = "langchain"
+	FormatLlamaIndex = "llamaindex"
+)
+
+// Version é a versão do documento exportado
+const Version = 1
+
+// Agent é a definição de um agente, independente do framework
+type Agent struct {
+	ID          string
+	Name        string
+	Description string
+	Role        string
+	Goal        string
+	Backstory   string // Prompt de sistema
+	Model       string
+	Temperature float64
+	MaxTokens   int
+	Tools       []string          // Ferramentas liberadas ao agente
+	Prompts     map[string]string // Templates de prompt, pelo nome
+}
+
+// Tool é a definição de uma ferramenta; Schema é o JSON Schema dos parâmetros (opcional)
+type Tool struct {
+	Name        string
+	Description string
+	Schema      json.RawMessage
+}
+
+// Bundle reúne os agentes e as ferramentas exportados
+type Bundle struct {
+	Agents []Agent
+	Tools  []Tool
+}
+
+// Export serializa o bundle no formato informado, em JSON indentado
+func Export(bundle Bundle, format string) ([]byte, error) {
+	var doc interface{}
+	switch strings.ToLower(format) {
+	case FormatLangChain:
+		doc = LangChain(bundle)
+	case FormatLlamaIndex:
+		doc = LlamaIndex(bundle)
+	default:
+		return nil, errs.New(errs.ErrValidation, "interop.Export", "formato desconhecido: %s (use %s ou %s)", format, FormatLangChain, FormatLlamaIndex)
+	}
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("erro ao serializar a exportação %s: %v", format, err)
+	}
+	return data, nil
+}
+
+// Template converte um template de prompt do HiveMind (variáveis "{{.nome}}" ou "{nome}")
+// para o formato f-string de LangChain e LlamaIndex, escapando as demais chaves. Retorna o
+// template convertido e as variáveis, na ordem em que aparecem.
+func Template(text string) (string, []string) {
+	var b strings.Builder
+	var variables []string
+	seen := make(map[string]bool)
+	addVariable := func(name string) {
+		b.WriteString("{" + name + "}")
+		if !seen[name] {
+			seen[name] = true
+			variables = append(variables, name)
+		}
+	}
+
+	for i := 0; i < len(text); {
+		if strings.HasPrefix(text[i:], "{{") {
+			if end := strings.Index(text[i:], "}}"); end > 0 {
+				inner := strings.TrimSpace(text[i+2 : i+end])
+				if name := strings.TrimPrefix(inner, "."); strings.HasPrefix(inner, ".") && isIdentifier(name) {
+					addVariable(name)
+					i += end + 2
+					continue
+				}
+			}
+			b.WriteString("{{{{") // Ações do template Go ({{if}}, {{end}}) viram texto literal
+			i += 2
+			continue
+		}
+		if text[i] == '{' {
+			if end := strings.IndexByte(text[i:], '}'); end > 0 && isIdentifier(text[i+1:i+end]) {
+				addVariable(text[i+1 : i+end])
+				i += end + 1
+				continue
+			}
+		}
+		switch text[i] {
+		case '{':
+			b.WriteString("{{")
+		case '}':
+			b.WriteString("}}")
+		default:
+			b.WriteByte(text[i])
+		}
+		i++
+	}
+	return b.String(), variables
+}
+
+// escape escapa as chaves de um texto fixo (ex.: o prompt de sistema) para o formato f-string
+func escape(text string) string {
+	return strings.NewReplacer("{", "{{", "}", "}}").Replace(text)
+}
+
+// isIdentifier informa se name é um nome de variável válido em Python
+func isIdentifier(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i, c := range name {
+		letter := c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+		if !letter && (i == 0 || c < '0' || c > '9') {
+			return false
+		}
+	}
+	return true
+}
+
+// objectSchema é o schema das ferramentas que não declaram parâmetros
+var objectSchema = json.RawMessage(`{"type":"object","properties":{}}`)
+
+// schema retorna o schema da ferramenta ou um objeto vazio
+func (t Tool) schema() json.RawMessage {
+	if len(t.Schema) == 0 {
+		return objectSchema
+	}
+	return t.Schema
+}
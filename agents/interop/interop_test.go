@@ -0,0 +1,121 @@
+package interop
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/suissa/HiveMind/agents/errs"
+)
+
+func TestTemplateConvertsVariablesAndEscapesBraces(t *testing.T) {
+	cases := []struct {
+		in, out string
+		vars    []string
+	}{
+		{"Analise os dados: {{.dados}}", "Analise os dados: {dados}", []string{"dados"}},
+		{"Pesquise {topic} e {aspects}, depois {topic}", "Pesquise {topic} e {aspects}, depois {topic}", []string{"topic", "aspects"}},
+		{`Responda em JSON: {"ok": true} {{ .x }}`, `Responda em JSON: {{"ok": true}} {x}`, []string{"x"}},
+		{"{{if .x}}sim{{end}}", "{{{{if .x}}}}sim{{{{end}}}}", nil},
+	}
+	for _, c := range cases {
+		out, vars := Template(c.in)
+		if out != c.out || !reflect.DeepEqual(vars, c.vars) {
+			t.Errorf("Template(%q) = %q, %v; esperava %q, %v", c.in, out, vars, c.out, c.vars)
+		}
+	}
+}
+
+func testBundle() Bundle {
+	return Bundle{
+		Agents: []Agent{{
+			ID:          "analista",
+			Name:        "Analista de Mercado",
+			Role:        "analyst",
+			Goal:        "Analisar tendências",
+			Backstory:   "Você responde em JSON {\"ok\": true}",
+			Model:       "gpt-4o",
+			Temperature: 0.2,
+			Tools:       []string{"github.search", "predict_trend"},
+			Prompts:     map[string]string{"pesquisa": "Analise {{.dados}}"},
+		}},
+		Tools: []Tool{
+			{Name: "github.search", Description: "Busca no GitHub", Schema: json.RawMessage(`{"type":"object","required":["q"]}`)},
+			{Name: "predict_trend"},
+		},
+	}
+}
+
+func TestLangChainExport(t *testing.T) {
+	data, err := Export(testBundle(), "LangChain")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc struct {
+		Agents []struct {
+			Name   string
+			Tools  []string
+			Prompt struct {
+				ID     []string
+				Kwargs struct {
+					Messages []struct {
+						ID     []string
+						Kwargs map[string]interface{}
+					}
+				}
+			}
+			Prompts map[string]struct{ Kwargs map[string]interface{} }
+		}
+		Tools []struct{ Function struct{ Name string } }
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatal(err)
+	}
+
+	agent := doc.Agents[0]
+	if agent.Name != "analista" || !reflect.DeepEqual(agent.Tools, []string{"github_search", "predict_trend"}) {
+		t.Fatalf("agente inesperado: %+v", agent)
+	}
+	if strings.Join(agent.Prompt.ID, ".") != "langchain.prompts.chat.ChatPromptTemplate" || len(agent.Prompt.Kwargs.Messages) != 4 {
+		t.Fatalf("prompt inesperado: %s", data)
+	}
+	system := agent.Prompt.Kwargs.Messages[0].Kwargs["prompt"].(map[string]interface{})["kwargs"].(map[string]interface{})
+	if system["template"] != `Você responde em JSON {{"ok": true}}` {
+		t.Fatalf("as chaves do prompt de sistema deveriam ser escapadas: %v", system["template"])
+	}
+	if agent.Prompts["pesquisa"].Kwargs["template"] != "Analise {dados}" {
+		t.Fatalf("template inesperado: %v", agent.Prompts["pesquisa"])
+	}
+	if doc.Tools[0].Function.Name != "github_search" || !strings.Contains(string(data), `"properties": {}`) {
+		t.Fatalf("ferramentas inesperadas: %s", data)
+	}
+}
+
+func TestLlamaIndexExport(t *testing.T) {
+	data, err := Export(testBundle(), FormatLlamaIndex)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc LlamaIndexDocument
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatal(err)
+	}
+	agent := doc.Agents[0]
+	if agent.SystemPrompt != `Você responde em JSON {"ok": true}` || agent.Description != "Analisar tendências" || agent.LLM.Model != "gpt-4o" {
+		t.Fatalf("agente inesperado: %+v", agent)
+	}
+	if prompt := agent.Prompts["pesquisa"]; prompt.Template != "Analise {dados}" || !reflect.DeepEqual(prompt.TemplateVars, []string{"dados"}) {
+		t.Fatalf("template inesperado: %+v", prompt)
+	}
+	var schema bytes.Buffer
+	if err := json.Compact(&schema, doc.Tools[0].FnSchema); err != nil || schema.String() != `{"type":"object","required":["q"]}` {
+		t.Fatalf("schema inesperado: %s", doc.Tools[0].FnSchema)
+	}
+
+	if _, err := Export(testBundle(), "autogen"); !errors.Is(err, errs.ErrValidation) {
+		t.Fatalf("esperava ErrValidation: %v", err)
+	}
+}
@@ -0,0 +1,138 @@
+package interop
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// Serialized é um objeto no formato de serialização do LangChain (langchain_core.load)
+type Serialized struct {
+	LC     int                    `json:"lc"`
+	Type   string                 `json:"type"`
+	ID     []string               `json:"id"`
+	Kwargs map[string]interface{} `json:"kwargs"`
+}
+
+// constructor monta a serialização de uma classe de langchain.prompts
+func constructor(kwargs map[string]interface{}, id ...string) Serialized {
+	return Serialized{LC: 1, Type: "constructor", ID: append([]string{"langchain", "prompts"}, id...), Kwargs: kwargs}
+}
+
+// promptTemplate monta um PromptTemplate a partir de um template já em f-string
+func promptTemplate(template string, variables []string) Serialized {
+	if variables == nil {
+		variables = []string{}
+	}
+	return constructor(map[string]interface{}{
+		"template":        template,
+		"input_variables": variables,
+		"template_format": "f-string",
+	}, "prompt", "PromptTemplate")
+}
+
+// messageTemplate monta uma mensagem do ChatPromptTemplate (SystemMessagePromptTemplate etc.)
+func messageTemplate(class, template string, variables []string) Serialized {
+	return constructor(map[string]interface{}{"prompt": promptTemplate(template, variables)}, "chat", class)
+}
+
+// LLMConfig são os parâmetros do modelo, aceitos por init_chat_model (LangChain) e pelas
+// classes de LLM do LlamaIndex
+type LLMConfig struct {
+	Model       string  `json:"model"`
+	Temperature float64 `json:"temperature"`
+	MaxTokens   int     `json:"max_tokens,omitempty"`
+}
+
+// LangChainAgent é um agente exportado para LangChain: o ChatPromptTemplate (com o prompt
+// de sistema, a entrada do usuário e o agent_scratchpad) é carregado com langchain_core.load
+type LangChainAgent struct {
+	Name     string                `json:"name"`
+	Prompt   Serialized            `json:"prompt"`
+	LLM      LLMConfig             `json:"llm"`
+	Tools    []string              `json:"tools"`
+	Prompts  map[string]Serialized `json:"prompts,omitempty"`
+	Metadata map[string]string     `json:"metadata"`
+}
+
+// LangChainFunction é uma ferramenta no formato de funções aceito por bind_tools
+type LangChainFunction struct {
+	Type     string `json:"type"`
+	Function struct {
+		Name        string          `json:"name"`
+		Description string          `json:"description"`
+		Parameters  json.RawMessage `json:"parameters"`
+	} `json:"function"`
+}
+
+// LangChainDocument é o documento exportado para LangChain
+type LangChainDocument struct {
+	Format  string              `json:"format"`
+	Version int                 `json:"version"`
+	Agents  []LangChainAgent    `json:"agents"`
+	Tools   []LangChainFunction `json:"tools"`
+}
+
+// LangChain converte o bundle para LangChain. Os nomes das ferramentas são ajustados ao padrão
+// das funções (letras, números, "_" e "-").
+func LangChain(bundle Bundle) LangChainDocument {
+	doc := LangChainDocument{
+		Format:  FormatLangChain,
+		Version: Version,
+		Agents:  make([]LangChainAgent, 0, len(bundle.Agents)),
+		Tools:   make([]LangChainFunction, 0, len(bundle.Tools)),
+	}
+
+	for _, agent := range bundle.Agents {
+		exported := LangChainAgent{
+			Name: agent.ID,
+			Prompt: constructor(map[string]interface{}{
+				"input_variables": []string{"input"},
+				"messages": []Serialized{
+					messageTemplate("SystemMessagePromptTemplate", escape(agent.Backstory), nil),
+					constructor(map[string]interface{}{"variable_name": "chat_history", "optional": true}, "chat", "MessagesPlaceholder"),
+					messageTemplate("HumanMessagePromptTemplate", "{input}", []string{"input"}),
+					constructor(map[string]interface{}{"variable_name": "agent_scratchpad", "optional": true}, "chat", "MessagesPlaceholder"),
+				},
+			}, "chat", "ChatPromptTemplate"),
+			LLM:   LLMConfig{Model: agent.Model, Temperature: agent.Temperature, MaxTokens: agent.MaxTokens},
+			Tools: make([]string, 0, len(agent.Tools)),
+			Metadata: map[string]string{
+				"display_name": agent.Name,
+				"description":  agent.Description,
+				"role":         agent.Role,
+				"goal":         agent.Goal,
+			},
+		}
+		for _, tool := range agent.Tools {
+			exported.Tools = append(exported.Tools, functionName(tool))
+		}
+		if len(agent.Prompts) > 0 {
+			exported.Prompts = make(map[string]Serialized, len(agent.Prompts))
+			for name, text := range agent.Prompts {
+				exported.Prompts[name] = promptTemplate(Template(text))
+			}
+		}
+		doc.Agents = append(doc.Agents, exported)
+	}
+
+	for _, tool := range bundle.Tools {
+		var function LangChainFunction
+		function.Type = "function"
+		function.Function.Name = functionName(tool.Name)
+		function.Function.Description = tool.Description
+		function.Function.Parameters = tool.schema()
+		doc.Tools = append(doc.Tools, function)
+	}
+	return doc
+}
+
+// functionName troca os caracteres não aceitos em nomes de funções (ex.: o "." das
+// ferramentas MCP) por "_"
+func functionName(name string) string {
+	return strings.Map(func(c rune) rune {
+		if c == '_' || c == '-' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9') {
+			return c
+		}
+		return '_'
+	}, name)
+}
@@ -0,0 +1,89 @@
+package interop
+
+import "encoding/json"
+
+// LlamaIndexPrompt é um template no formato do PromptTemplate do LlamaIndex
+type LlamaIndexPrompt struct {
+	Template     string   `json:"template"`
+	TemplateVars []string `json:"template_vars"`
+}
+
+// LlamaIndexAgent é um agente exportado com os argumentos do FunctionAgent (name,
+// description, system_prompt, tools, llm), para uso isolado ou em um AgentWorkflow
+type LlamaIndexAgent struct {
+	Name         string                      `json:"name"`
+	Description  string                      `json:"description"`
+	SystemPrompt string                      `json:"system_prompt"`
+	LLM          LLMConfig                   `json:"llm"`
+	Tools        []string                    `json:"tools"`
+	Prompts      map[string]LlamaIndexPrompt `json:"prompts,omitempty"`
+	Metadata     map[string]string           `json:"metadata"`
+}
+
+// LlamaIndexTool é uma ferramenta com os metadados do FunctionTool; FnSchema é o JSON Schema
+// dos parâmetros
+type LlamaIndexTool struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description"`
+	FnSchema    json.RawMessage `json:"fn_schema"`
+}
+
+// LlamaIndexDocument é o documento exportado para LlamaIndex
+type LlamaIndexDocument struct {
+	Format  string            `json:"format"`
+	Version int               `json:"version"`
+	Agents  []LlamaIndexAgent `json:"agents"`
+	Tools   []LlamaIndexTool  `json:"tools"`
+}
+
+// LlamaIndex converte o bundle para LlamaIndex. O prompt de sistema é texto fixo; apenas os
+// templates de prompt usam variáveis.
+func LlamaIndex(bundle Bundle) LlamaIndexDocument {
+	doc := LlamaIndexDocument{
+		Format:  FormatLlamaIndex,
+		Version: Version,
+		Agents:  make([]LlamaIndexAgent, 0, len(bundle.Agents)),
+		Tools:   make([]LlamaIndexTool, 0, len(bundle.Tools)),
+	}
+
+	for _, agent := range bundle.Agents {
+		exported := LlamaIndexAgent{
+			Name:         agent.ID,
+			Description:  agent.Description,
+			SystemPrompt: agent.Backstory,
+			LLM:          LLMConfig{Model: agent.Model, Temperature: agent.Temperature, MaxTokens: agent.MaxTokens},
+			Tools:        make([]string, 0, len(agent.Tools)),
+			Metadata: map[string]string{
+				"display_name": agent.Name,
+				"role":         agent.Role,
+				"goal":         agent.Goal,
+			},
+		}
+		if exported.Description == "" {
+			exported.Description = agent.Goal
+		}
+		for _, tool := range agent.Tools {
+			exported.Tools = append(exported.Tools, functionName(tool))
+		}
+		if len(agent.Prompts) > 0 {
+			exported.Prompts = make(map[string]LlamaIndexPrompt, len(agent.Prompts))
+			for name, text := range agent.Prompts {
+				template, variables := Template(text)
+				if variables == nil {
+					variables = []string{}
+				}
+				exported.Prompts[name] = LlamaIndexPrompt{Template: template, TemplateVars: variables}
+			}
+		}
+		doc.Agents = append(doc.Agents, exported)
+	}
+
+	for _, tool := range bundle.Tools {
+		doc.Tools = append(doc.Tools, LlamaIndexTool{
+			Name:        functionName(tool.Name),
+			Description: tool.Description,
+			FnSchema:    tool.schema(),
+		})
+	}
+	return doc
+}
@@ -0,0 +1,290 @@
+// Package knowledge guarda as entidades das campanhas — projetos, produtos, públicos, canais,
+// concorrentes, estratégias — e as relações entre elas num grafo consultável. Os agentes
+// consultam o grafo em vez de decodificar o conteúdo das memórias anteriores.
+package knowledge
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Kind é o tipo de uma entidade
+type Kind string
+
+// Tipos de entidade
+const (
+	KindProject    Kind = "project"
+	KindProduct    Kind = "product"
+	KindAudience   Kind = "audience"
+	KindChannel    Kind = "channel"
+	KindCompetitor Kind = "competitor"
+	KindStrategy   Kind = "strategy"
+	KindTactic     Kind = "tactic"
+	KindKPI        Kind = "kpi"
+	KindCampaign   Kind = "campaign"
+)
+
+// Tipos de relação
+const (
+	RelOffers       = "offers"        // Projeto → produto
+	RelTargets      = "targets"       // Projeto ou campanha → público
+	RelUses         = "uses"          // Projeto, estratégia ou campanha → canal ou tática
+	RelCompetesWith = "competes_with" // Projeto ou produto → concorrente
+	RelPlans        = "plans"         // Projeto → estratégia
+	RelMeasures     = "measures"      // Estratégia → KPI
+	RelIncludes     = "includes"      // Estratégia → campanha
+)
+
+// Entity é uma entidade do grafo
+type Entity struct {
+	ID         string            `json:"id"`
+	Kind       Kind              `json:"kind"`
+	Name       string            `json:"name"`
+	Attributes map[string]string `json:"attributes,omitempty"`
+	UpdatedAt  time.Time         `json:"updated_at"`
+}
+
+// Relation liga duas entidades
+type Relation struct {
+	From string `json:"from"`
+	Type string `json:"type"`
+	To   string `json:"to"`
+}
+
+// EntityID retorna o ID da entidade: o tipo e o nome normalizado, de modo que "Instagram" e
+// " instagram " são a mesma entidade
+func EntityID(kind Kind, name string) string {
+	return string(kind) + ":" + strings.ToLower(strings.Join(strings.Fields(name), " "))
+}
+
+// Query filtra as entidades; os campos vazios não filtram
+type Query struct {
+	Kind      Kind   `json:"kind,omitempty"`
+	Name      string `json:"name,omitempty"`       // Trecho do nome, sem diferenciar maiúsculas
+	RelatedTo string `json:"related_to,omitempty"` // ID da entidade de origem da relação
+	Relation  string `json:"relation,omitempty"`   // Tipo da relação com RelatedTo
+}
+
+// Graph é o grafo em memória; é seguro para uso concorrente
+type Graph struct {
+	mu       sync.RWMutex
+	entities map[string]*Entity
+	order    []string              // IDs na ordem de criação
+	out      map[string][]Relation // Relações pela origem, na ordem de criação
+}
+
+// New cria um grafo vazio
+func New() *Graph {
+	return &Graph{entities: make(map[string]*Entity), out: make(map[string][]Relation)}
+}
+
+// Upsert cria a entidade ou acrescenta os atributos à existente, e retorna o seu ID. Os
+// atributos vazios não apagam os existentes.
+func (g *Graph) Upsert(kind Kind, name string, attributes map[string]string) string {
+	name = strings.Join(strings.Fields(name), " ")
+	id := EntityID(kind, name)
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	entity, ok := g.entities[id]
+	if !ok {
+		entity = &Entity{ID: id, Kind: kind, Name: name, Attributes: make(map[string]string)}
+		g.entities[id] = entity
+		g.order = append(g.order, id)
+	}
+	for key, value := range attributes {
+		if value != "" {
+			entity.Attributes[key] = value
+		}
+	}
+	entity.UpdatedAt = time.Now()
+	return id
+}
+
+// Relate liga as entidades; repetir a relação não a duplica
+func (g *Graph) Relate(from, relation, to string) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for _, id := range []string{from, to} {
+		if _, ok := g.entities[id]; !ok {
+			return fmt.Errorf("entidade %s não encontrada", id)
+		}
+	}
+	for _, existing := range g.out[from] {
+		if existing.Type == relation && existing.To == to {
+			return nil
+		}
+	}
+	g.out[from] = append(g.out[from], Relation{From: from, Type: relation, To: to})
+	return nil
+}
+
+// Get retorna a entidade pelo ID
+func (g *Graph) Get(id string) (Entity, bool) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	entity, ok := g.entities[id]
+	if !ok {
+		return Entity{}, false
+	}
+	return entity.copy(), true
+}
+
+// Related retorna as entidades ligadas a id pela relação (vazia para todas) e do tipo
+// informado (vazio para todos), na ordem em que foram ligadas
+func (g *Graph) Related(id, relation string, kind Kind) []Entity {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	var related []Entity
+	for _, rel := range g.out[id] {
+		entity := g.entities[rel.To]
+		if (relation == "" || rel.Type == relation) && (kind == "" || entity.Kind == kind) {
+			related = append(related, entity.copy())
+		}
+	}
+	return related
+}
+
+// Relations retorna as relações que partem da entidade
+func (g *Graph) Relations(id string) []Relation {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return append([]Relation(nil), g.out[id]...)
+}
+
+// Find retorna as entidades que atendem à consulta, na ordem de criação (ou de ligação, com
+// RelatedTo)
+func (g *Graph) Find(query Query) []Entity {
+	var candidates []Entity
+	if query.RelatedTo != "" {
+		candidates = g.Related(query.RelatedTo, query.Relation, query.Kind)
+	} else {
+		g.mu.RLock()
+		for _, id := range g.order {
+			if entity := g.entities[id]; query.Kind == "" || entity.Kind == query.Kind {
+				candidates = append(candidates, entity.copy())
+			}
+		}
+		g.mu.RUnlock()
+	}
+	if query.Name == "" {
+		return candidates
+	}
+	name := strings.ToLower(query.Name)
+	found := candidates[:0]
+	for _, entity := range candidates {
+		if strings.Contains(strings.ToLower(entity.Name), name) {
+			found = append(found, entity)
+		}
+	}
+	return found
+}
+
+// copy isola os atributos da entidade guardada
+func (e *Entity) copy() Entity {
+	c := *e
+	c.Attributes = make(map[string]string, len(e.Attributes))
+	for key, value := range e.Attributes {
+		c.Attributes[key] = value
+	}
+	return c
+}
+
+// Names retorna os nomes das entidades
+func Names(entities []Entity) []string {
+	names := make([]string, len(entities))
+	for i, entity := range entities {
+		names[i] = entity.Name
+	}
+	return names
+}
+
+// Aliases das chaves dos detalhes do projeto lidas por Extract, por tipo de entidade
+var detailKeys = map[Kind][]string{
+	KindProduct:    {"product", "products", "produto", "produtos"},
+	KindAudience:   {"audience", "audiences", "target", "target_audience", "publico", "público"},
+	KindChannel:    {"channel", "channels", "canal", "canais"},
+	KindCompetitor: {"competitor", "competitors", "concorrente", "concorrentes"},
+}
+
+// projectRelations são as relações do projeto com cada tipo extraído
+var projectRelations = map[Kind]string{
+	KindProduct:    RelOffers,
+	KindAudience:   RelTargets,
+	KindChannel:    RelUses,
+	KindCompetitor: RelCompetesWith,
+}
+
+// Extract registra o projeto descrito pelos detalhes (name, objective, budget, products,
+// audience/target, channels, competitors e variações em português) e as entidades ligadas a
+// ele, e retorna o ID do projeto. Os valores podem ser textos (listas separadas por vírgula),
+// listas de textos ou listas decodificadas de JSON.
+func Extract(g *Graph, details map[string]interface{}) string {
+	name := first(details, "name", "project", "nome", "projeto")
+	if name == "" {
+		name = "projeto"
+	}
+	attributes := make(map[string]string)
+	for _, key := range []string{"objective", "budget", "duration"} {
+		if value, ok := details[key]; ok && value != nil {
+			attributes[key] = strings.TrimSpace(fmt.Sprint(value))
+		}
+	}
+	if objective := first(details, "objetivo"); objective != "" && attributes["objective"] == "" {
+		attributes["objective"] = objective
+	}
+	project := g.Upsert(KindProject, name, attributes)
+
+	kinds := make([]Kind, 0, len(detailKeys))
+	for kind := range detailKeys {
+		kinds = append(kinds, kind)
+	}
+	sort.Slice(kinds, func(i, j int) bool { return kinds[i] < kinds[j] })
+	for _, kind := range kinds {
+		for _, key := range detailKeys[kind] {
+			for _, value := range values(details[key]) {
+				g.Relate(project, projectRelations[kind], g.Upsert(kind, value, nil))
+			}
+		}
+	}
+	return project
+}
+
+// first retorna o primeiro texto não vazio entre as chaves
+func first(details map[string]interface{}, keys ...string) string {
+	for _, key := range keys {
+		if value, ok := details[key].(string); ok && strings.TrimSpace(value) != "" {
+			return strings.TrimSpace(value)
+		}
+	}
+	return ""
+}
+
+// values lê uma lista de nomes de um texto separado por vírgulas, de uma lista ou de objetos
+// com o campo "name"
+func values(value interface{}) []string {
+	var items []string
+	switch v := value.(type) {
+	case string:
+		items = strings.Split(v, ",")
+	case []string:
+		items = v
+	case []interface{}:
+		for _, item := range v {
+			items = append(items, values(item)...)
+		}
+	case map[string]interface{}:
+		if name, ok := v["name"].(string); ok {
+			items = []string{name}
+		}
+	}
+	names := items[:0:0]
+	for _, item := range items {
+		if item = strings.TrimSpace(item); item != "" {
+			names = append(names, item)
+		}
+	}
+	return names
+}
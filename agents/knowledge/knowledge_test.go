@@ -0,0 +1,61 @@
+package knowledge
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestExtractToleratesDecodedJSON(t *testing.T) {
+	// Os detalhes relidos de JSON chegam como []interface{}, não como []string
+	var details map[string]interface{}
+	json.Unmarshal([]byte(`{
+		"name": "Lançamento Verão",
+		"objective": "vender tênis de corrida",
+		"budget": 5000,
+		"channels": ["Instagram", " instagram ", "TikTok"],
+		"target": "corredores amadores, triatletas",
+		"products": [{"name": "Tênis Veloz"}],
+		"competitors": "Marca X"
+	}`), &details)
+
+	g := New()
+	project := Extract(g, details)
+	entity, ok := g.Get(project)
+	if !ok || entity.Name != "Lançamento Verão" || entity.Attributes["objective"] != "vender tênis de corrida" || entity.Attributes["budget"] != "5000" {
+		t.Fatalf("projeto inesperado: %+v", entity)
+	}
+	if channels := Names(g.Related(project, RelUses, KindChannel)); len(channels) != 2 || channels[0] != "Instagram" || channels[1] != "TikTok" {
+		t.Fatalf("canais inesperados: %v", channels)
+	}
+	if audiences := Names(g.Related(project, RelTargets, "")); len(audiences) != 2 || audiences[1] != "triatletas" {
+		t.Fatalf("públicos inesperados: %v", audiences)
+	}
+	if products := g.Find(Query{Kind: KindProduct, Name: "veloz"}); len(products) != 1 || products[0].ID != "product:tênis veloz" {
+		t.Fatalf("produtos inesperados: %+v", products)
+	}
+	if competitors := g.Find(Query{RelatedTo: project, Relation: RelCompetesWith}); len(competitors) != 1 || competitors[0].Name != "Marca X" {
+		t.Fatalf("concorrentes inesperados: %+v", competitors)
+	}
+}
+
+func TestRelate(t *testing.T) {
+	g := New()
+	strategy := g.Upsert(KindStrategy, "Verão", map[string]string{"objective": "vendas"})
+	kpi := g.Upsert(KindKPI, "ROI", nil)
+	if err := g.Relate(strategy, RelMeasures, "kpi:inexistente"); err == nil {
+		t.Fatal("relação com entidade inexistente deveria falhar")
+	}
+	g.Relate(strategy, RelMeasures, kpi)
+	g.Relate(strategy, RelMeasures, kpi)
+	if relations := g.Relations(strategy); len(relations) != 1 {
+		t.Fatalf("relação duplicada: %v", relations)
+	}
+
+	// Os atributos vazios não apagam os existentes, e as cópias são isoladas
+	g.Upsert(KindStrategy, "verão", map[string]string{"objective": "", "kpi": "ROI"})
+	entity, _ := g.Get(strategy)
+	entity.Attributes["objective"] = "alterado"
+	if stored, _ := g.Get(strategy); stored.Attributes["objective"] != "vendas" || stored.Attributes["kpi"] != "ROI" {
+		t.Fatalf("atributos inesperados: %v", stored.Attributes)
+	}
+}
@@ -0,0 +1,64 @@
+package agents
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/suissa/HiveMind/agents/knowledge"
+)
+
+// KnowledgeToolName é o nome da ferramenta que consulta o grafo de conhecimento das campanhas
+const KnowledgeToolName = "query_knowledge"
+
+// knowledgeTool consulta as entidades do grafo e as suas relações
+type knowledgeTool struct {
+	graph *knowledge.Graph
+}
+
+// NewKnowledgeTool cria a ferramenta query_knowledge, com que o agente (por exemplo, o
+// estrategista) consulta os produtos, públicos, canais, concorrentes e estratégias já
+// registrados no grafo, em vez de reler as memórias anteriores
+func NewKnowledgeTool(graph *knowledge.Graph) Tool {
+	return &knowledgeTool{graph: graph}
+}
+
+func (t *knowledgeTool) Name() string { return KnowledgeToolName }
+
+func (t *knowledgeTool) Description() string {
+	return "Consulta as entidades das campanhas (project, product, audience, channel, competitor, strategy, " +
+		"tactic, kpi, campaign) por tipo, trecho do nome ou relação com outra entidade"
+}
+
+// InputSchema implementa SchemaTool
+func (t *knowledgeTool) InputSchema() json.RawMessage {
+	return json.RawMessage(`{"type":"object","properties":{` +
+		`"kind":{"type":"string","enum":["project","product","audience","channel","competitor","strategy","tactic","kpi","campaign"]},` +
+		`"name":{"type":"string","description":"Trecho do nome da entidade"},` +
+		`"related_to":{"type":"string","description":"ID da entidade de origem, ex.: project:lançamento"},` +
+		`"relation":{"type":"string","enum":["offers","targets","uses","competes_with","plans","measures","includes"]}}}`)
+}
+
+// Execute retorna as entidades encontradas, cada uma com as relações que partem dela
+func (t *knowledgeTool) Execute(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+	query := knowledge.Query{}
+	if kind, ok := params["kind"].(string); ok {
+		query.Kind = knowledge.Kind(kind)
+	}
+	query.Name, _ = params["name"].(string)
+	query.RelatedTo, _ = params["related_to"].(string)
+	query.Relation, _ = params["relation"].(string)
+
+	entities := t.graph.Find(query)
+	results := make([]map[string]interface{}, 0, len(entities))
+	for _, entity := range entities {
+		result := map[string]interface{}{"id": entity.ID, "kind": entity.Kind, "name": entity.Name}
+		if len(entity.Attributes) > 0 {
+			result["attributes"] = entity.Attributes
+		}
+		if relations := t.graph.Relations(entity.ID); len(relations) > 0 {
+			result["relations"] = relations
+		}
+		results = append(results, result)
+	}
+	return map[string]interface{}{"entities": results, "total": len(results)}, nil
+}
@@ -0,0 +1,242 @@
+package llm
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"text/template"
+)
+
+// FakeMode define como o FakeLLMProvider usa o provedor real
+type FakeMode string
+
+const (
+	// FakeReplay responde apenas com as fixtures (offline)
+	FakeReplay FakeMode = "replay"
+	// FakeRecord sempre chama o provedor real e grava as respostas
+	FakeRecord FakeMode = "record"
+	// FakeAuto responde com as fixtures e grava as que faltarem usando o provedor real
+	FakeAuto FakeMode = "auto"
+)
+
+// Fixture representa uma resposta gravada ou configurada para um prompt
+type Fixture struct {
+	Hash     string `json:"hash"`
+	Model    string `json:"model,omitempty"`
+	Prompt   string `json:"prompt,omitempty"` // Apenas para leitura humana
+	Response string `json:"response"`
+	Template bool   `json:"template,omitempty"` // Response é um text/template sobre o Request
+}
+
+// fixturesFile é o formato do arquivo de fixtures
+type fixturesFile struct {
+	Default  string    `json:"default,omitempty"`
+	Fixtures []Fixture `json:"fixtures"`
+}
+
+// PromptHash calcula a chave de uma requisição a partir do modelo, system e prompt
+func PromptHash(req Request) string {
+	sum := sha256.Sum256([]byte(req.Model + "\x00" + req.System + "\x00" + req.Prompt))
+	return hex.EncodeToString(sum[:])
+}
+
+// FakeLLMProvider responde com respostas fixas ou templates indexados pelo hash do prompt,
+// com suporte a gravação e replay contra um provedor real
+type FakeLLMProvider struct {
+	fixtures map[string]Fixture
+	fallback *template.Template // Template usado quando não há fixture
+	defaults string
+	upstream Provider
+	mode     FakeMode
+	mu       sync.RWMutex
+}
+
+// NewFakeLLMProvider cria um provedor sem fixtures em modo replay
+func NewFakeLLMProvider() *FakeLLMProvider {
+	return &FakeLLMProvider{
+		fixtures: make(map[string]Fixture),
+		mode:     FakeReplay,
+	}
+}
+
+// LoadFakeLLMProvider carrega as fixtures de um arquivo JSON. Um arquivo inexistente
+// resulta em um provedor vazio, que pode ser preenchido em modo record.
+func LoadFakeLLMProvider(filename string) (*FakeLLMProvider, error) {
+	p := NewFakeLLMProvider()
+
+	data, err := os.ReadFile(filename)
+	if os.IsNotExist(err) {
+		return p, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler fixtures de LLM: %v", err)
+	}
+
+	var file fixturesFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("erro ao decodificar fixtures de LLM: %v", err)
+	}
+
+	for _, fixture := range file.Fixtures {
+		if fixture.Template {
+			if _, err := template.New(fixture.Hash).Parse(fixture.Response); err != nil {
+				return nil, fmt.Errorf("template inválido na fixture %s: %v", fixture.Hash, err)
+			}
+		}
+		p.fixtures[fixture.Hash] = fixture
+	}
+	if file.Default != "" {
+		if err := p.SetDefault(file.Default); err != nil {
+			return nil, err
+		}
+	}
+
+	return p, nil
+}
+
+// SetUpstream define o provedor real e o modo de gravação/replay
+func (p *FakeLLMProvider) SetUpstream(upstream Provider, mode FakeMode) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.upstream = upstream
+	p.mode = mode
+}
+
+// SetDefault define o template de resposta usado quando não há fixture para o prompt
+func (p *FakeLLMProvider) SetDefault(tmpl string) error {
+	parsed, err := template.New("default").Parse(tmpl)
+	if err != nil {
+		return fmt.Errorf("template padrão inválido: %v", err)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.fallback = parsed
+	p.defaults = tmpl
+	return nil
+}
+
+// AddResponse registra uma resposta fixa para a requisição
+func (p *FakeLLMProvider) AddResponse(req Request, response string) {
+	p.add(req, response, false)
+}
+
+// AddTemplate registra uma resposta em text/template (ex.: "Resumo de {{.Prompt}}")
+func (p *FakeLLMProvider) AddTemplate(req Request, tmpl string) error {
+	if _, err := template.New("fixture").Parse(tmpl); err != nil {
+		return fmt.Errorf("template inválido: %v", err)
+	}
+	p.add(req, tmpl, true)
+	return nil
+}
+
+// add registra uma fixture
+func (p *FakeLLMProvider) add(req Request, response string, isTemplate bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	hash := PromptHash(req)
+	p.fixtures[hash] = Fixture{
+		Hash:     hash,
+		Model:    req.Model,
+		Prompt:   req.Prompt,
+		Response: response,
+		Template: isTemplate,
+	}
+}
+
+// Complete implementa Provider
+func (p *FakeLLMProvider) Complete(ctx context.Context, req Request) (*Response, error) {
+	p.mu.RLock()
+	fixture, found := p.fixtures[PromptHash(req)]
+	upstream, mode, fallback := p.upstream, p.mode, p.fallback
+	p.mu.RUnlock()
+
+	if upstream != nil && (mode == FakeRecord || (mode == FakeAuto && !found)) {
+		return p.record(ctx, upstream, req)
+	}
+
+	if found {
+		text := fixture.Response
+		if fixture.Template {
+			rendered, err := render(template.New(fixture.Hash), fixture.Response, req)
+			if err != nil {
+				return nil, err
+			}
+			text = rendered
+		}
+		return &Response{Text: text, Model: req.Model}, nil
+	}
+
+	if fallback != nil {
+		var buf bytes.Buffer
+		if err := fallback.Execute(&buf, req); err != nil {
+			return nil, fmt.Errorf("erro ao renderizar resposta padrão: %v", err)
+		}
+		return &Response{Text: buf.String(), Model: req.Model}, nil
+	}
+
+	return nil, fmt.Errorf("nenhuma fixture para o prompt %s (hash %s)", truncate(req.Prompt, 40), PromptHash(req))
+}
+
+// record chama o provedor real e grava a resposta
+func (p *FakeLLMProvider) record(ctx context.Context, upstream Provider, req Request) (*Response, error) {
+	resp, err := upstream.Complete(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	p.AddResponse(req, resp.Text)
+	return resp, nil
+}
+
+// Save grava as fixtures em um arquivo JSON, ordenadas pelo hash para diffs estáveis
+func (p *FakeLLMProvider) Save(filename string) error {
+	p.mu.RLock()
+	file := fixturesFile{Fixtures: make([]Fixture, 0, len(p.fixtures))}
+	for _, fixture := range p.fixtures {
+		file.Fixtures = append(file.Fixtures, fixture)
+	}
+	file.Default = p.defaults
+	p.mu.RUnlock()
+
+	sort.Slice(file.Fixtures, func(i, j int) bool {
+		return file.Fixtures[i].Hash < file.Fixtures[j].Hash
+	})
+
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return fmt.Errorf("erro ao serializar fixtures de LLM: %v", err)
+	}
+	if err := os.WriteFile(filename, data, 0644); err != nil {
+		return fmt.Errorf("erro ao gravar fixtures de LLM: %v", err)
+	}
+	return nil
+}
+
+// render executa um template sobre a requisição
+func render(t *template.Template, text string, req Request) (string, error) {
+	parsed, err := t.Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("template inválido: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := parsed.Execute(&buf, req); err != nil {
+		return "", fmt.Errorf("erro ao renderizar template: %v", err)
+	}
+	return buf.String(), nil
+}
+
+// truncate limita o tamanho de um texto para mensagens de erro
+func truncate(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n]) + "..."
+}
@@ -0,0 +1,75 @@
+package llm
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+)
+
+func TestFakeLLMProviderReplay(t *testing.T) {
+	p := NewFakeLLMProvider()
+	ctx := context.Background()
+
+	req := Request{Model: "gpt-4", Prompt: "Crie um slogan"}
+	p.AddResponse(req, "Verde é o novo luxo")
+	if err := p.AddTemplate(Request{Model: "gpt-4", Prompt: "Resuma"}, "Resumo ({{.Model}}): {{.Prompt}}"); err != nil {
+		t.Fatalf("Erro ao adicionar template: %v", err)
+	}
+
+	resp, err := p.Complete(ctx, req)
+	if err != nil || resp.Text != "Verde é o novo luxo" {
+		t.Errorf("Resposta fixa incorreta: %v, %v", resp, err)
+	}
+
+	resp, err = p.Complete(ctx, Request{Model: "gpt-4", Prompt: "Resuma"})
+	if err != nil || resp.Text != "Resumo (gpt-4): Resuma" {
+		t.Errorf("Resposta de template incorreta: %v, %v", resp, err)
+	}
+
+	if _, err := p.Complete(ctx, Request{Prompt: "desconhecido"}); err == nil {
+		t.Error("Prompt sem fixture deveria retornar erro")
+	}
+
+	if err := p.SetDefault("[offline] {{.Prompt}}"); err != nil {
+		t.Fatalf("Erro ao definir padrão: %v", err)
+	}
+	resp, err = p.Complete(ctx, Request{Prompt: "desconhecido"})
+	if err != nil || resp.Text != "[offline] desconhecido" {
+		t.Errorf("Resposta padrão incorreta: %v, %v", resp, err)
+	}
+}
+
+func TestFakeLLMProviderRecord(t *testing.T) {
+	calls := 0
+	upstream := ProviderFunc(func(ctx context.Context, req Request) (*Response, error) {
+		calls++
+		return &Response{Text: "real: " + req.Prompt, Model: req.Model}, nil
+	})
+
+	filename := filepath.Join(t.TempDir(), "fixtures.json")
+	ctx := context.Background()
+	req := Request{Model: "gpt-4", Prompt: "Analise o mercado"}
+
+	recorder := NewFakeLLMProvider()
+	recorder.SetUpstream(upstream, FakeAuto)
+	for i := 0; i < 2; i++ {
+		if _, err := recorder.Complete(ctx, req); err != nil {
+			t.Fatalf("Erro na gravação: %v", err)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("Modo auto deveria chamar o provedor real uma vez, chamou %d", calls)
+	}
+	if err := recorder.Save(filename); err != nil {
+		t.Fatalf("Erro ao salvar fixtures: %v", err)
+	}
+
+	replay, err := LoadFakeLLMProvider(filename)
+	if err != nil {
+		t.Fatalf("Erro ao carregar fixtures: %v", err)
+	}
+	resp, err := replay.Complete(ctx, req)
+	if err != nil || resp.Text != "real: Analise o mercado" {
+		t.Errorf("Replay incorreto: %v, %v", resp, err)
+	}
+}
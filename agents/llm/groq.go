@@ -0,0 +1,34 @@
+package llm
+
+import (
+	"os"
+
+	"github.com/suissa/HiveMind/agents/errs"
+)
+
+const (
+	// DefaultGroqURL é a API compatível com a OpenAI da Groq
+	DefaultGroqURL = "https://api.groq.com/openai/v1"
+	// DefaultGroqModel é o modelo usado quando GROQ_MODEL não está definido
+	DefaultGroqModel = "llama-3.3-70b-versatile"
+)
+
+// GroqFromEnv cria o provedor da Groq, que fala a mesma API do vLLM, a partir de GROQ_API_KEY,
+// GROQ_MODEL e GROQ_BASE_URL. Sem a chave, retorna ErrValidation.
+func GroqFromEnv() (*VLLM, error) {
+	key := os.Getenv("GROQ_API_KEY")
+	if key == "" {
+		return nil, errs.New(errs.ErrValidation, "llm.GroqFromEnv", "GROQ_API_KEY não definida")
+	}
+	baseURL := os.Getenv("GROQ_BASE_URL")
+	if baseURL == "" {
+		baseURL = DefaultGroqURL
+	}
+	model := os.Getenv("GROQ_MODEL")
+	if model == "" {
+		model = DefaultGroqModel
+	}
+	g := NewVLLM(baseURL, model)
+	g.APIKey = key
+	return g, nil
+}
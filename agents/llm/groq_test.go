@@ -0,0 +1,32 @@
+package llm
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/suissa/HiveMind/agents/errs"
+)
+
+func TestGroqFromEnv(t *testing.T) {
+	t.Setenv("GROQ_API_KEY", "")
+	if _, err := GroqFromEnv(); !errors.Is(err, errs.ErrValidation) {
+		t.Fatalf("esperava ErrValidation sem a chave: %v", err)
+	}
+
+	t.Setenv("GROQ_API_KEY", "segredo")
+	t.Setenv("GROQ_MODEL", "")
+	t.Setenv("GROQ_BASE_URL", "")
+	g, err := GroqFromEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g.BaseURL != DefaultGroqURL || g.Model != DefaultGroqModel || g.APIKey != "segredo" {
+		t.Fatalf("provedor inesperado: %+v", g)
+	}
+
+	t.Setenv("GROQ_MODEL", "mixtral-8x7b-32768")
+	t.Setenv("GROQ_BASE_URL", "http://localhost:9999/v1/")
+	if g, _ = GroqFromEnv(); g.Model != "mixtral-8x7b-32768" || g.BaseURL != "http://localhost:9999/v1" {
+		t.Fatalf("provedor inesperado: %+v", g)
+	}
+}
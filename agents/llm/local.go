@@ -0,0 +1,49 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/suissa/HiveMind/agents/errs"
+)
+
+// HealthTimeout limita a verificação de saúde, para que um servidor travado não prenda quem verifica
+const HealthTimeout = 5 * time.Second
+
+// LocalProvider é um provedor de inferência local (Ollama, vLLM), que roda sem dependência
+// de APIs externas. O runtime verifica a saúde do servidor e a disponibilidade do modelo
+// padrão em Start.
+type LocalProvider interface {
+	Provider
+	// Health verifica se o servidor de inferência responde
+	Health(ctx context.Context) error
+	// Models lista os modelos disponíveis no servidor
+	Models(ctx context.Context) ([]string, error)
+	// EnsureModel garante que o modelo esteja disponível, baixando-o se o servidor permitir
+	EnsureModel(ctx context.Context, model string) error
+	// DefaultModel é o modelo usado nas requisições sem Request.Model
+	DefaultModel() string
+}
+
+// statusError classifica a resposta de erro de um servidor de inferência
+func statusError(op string, resp *http.Response) error {
+	message, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	if kind := errs.KindForStatus(resp.StatusCode); kind != nil {
+		return errs.New(kind, op, "status %d: %s", resp.StatusCode, strings.TrimSpace(string(message)))
+	}
+	return fmt.Errorf("%s: status %d: %s", op, resp.StatusCode, strings.TrimSpace(string(message)))
+}
+
+// hasModel informa se o modelo está na lista; nomes sem tag equivalem à tag latest
+func hasModel(models []string, model string) bool {
+	for _, name := range models {
+		if name == model || name == model+":latest" {
+			return true
+		}
+	}
+	return false
+}
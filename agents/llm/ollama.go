@@ -0,0 +1,244 @@
+package llm
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/suissa/HiveMind/agents/errs"
+	"github.com/suissa/HiveMind/agents/progress"
+)
+
+// DefaultOllamaURL é o endereço padrão do servidor Ollama
+const DefaultOllamaURL = "http://localhost:11434"
+
+// Ollama é o provedor de inferência local do Ollama (API HTTP em /api)
+type Ollama struct {
+	BaseURL        string
+	Model          string // Modelo padrão
+	EmbeddingModel string // Modelo de Embed; vazio usa Model
+	AutoPull       bool   // EnsureModel baixa o modelo ausente
+	client         *http.Client
+}
+
+// NewOllama cria o provedor para o servidor e o modelo padrão informados
+func NewOllama(baseURL, model string) *Ollama {
+	if baseURL == "" {
+		baseURL = DefaultOllamaURL
+	}
+	return &Ollama{
+		BaseURL:  strings.TrimRight(baseURL, "/"),
+		Model:    model,
+		AutoPull: true,
+		// Sem timeout global: gerações e downloads longos são limitados pelo contexto
+		client: &http.Client{},
+	}
+}
+
+// OllamaFromEnv lê OLLAMA_HOST, OLLAMA_MODEL, OLLAMA_EMBEDDING_MODEL e OLLAMA_AUTO_PULL
+// ("false" desativa o download)
+func OllamaFromEnv() *Ollama {
+	host := os.Getenv("OLLAMA_HOST")
+	if host != "" && !strings.Contains(host, "://") {
+		host = "http://" + host
+	}
+	o := NewOllama(host, os.Getenv("OLLAMA_MODEL"))
+	o.EmbeddingModel = os.Getenv("OLLAMA_EMBEDDING_MODEL")
+	o.AutoPull = os.Getenv("OLLAMA_AUTO_PULL") != "false"
+	return o
+}
+
+// DefaultModel implementa LocalProvider
+func (o *Ollama) DefaultModel() string {
+	return o.Model
+}
+
+// Complete implementa Provider (POST /api/generate, sem streaming)
+func (o *Ollama) Complete(ctx context.Context, req Request) (*Response, error) {
+	model := req.Model
+	if model == "" {
+		model = o.Model
+	}
+	options := map[string]interface{}{"temperature": req.Temperature}
+	if req.MaxTokens > 0 {
+		options["num_predict"] = req.MaxTokens
+	}
+	var payload struct {
+		Model           string `json:"model"`
+		Response        string `json:"response"`
+		PromptEvalCount int    `json:"prompt_eval_count"`
+		EvalCount       int    `json:"eval_count"`
+	}
+	err := o.do(ctx, http.MethodPost, "/api/generate", map[string]interface{}{
+		"model":   model,
+		"system":  req.System,
+		"prompt":  req.Prompt,
+		"stream":  false,
+		"options": options,
+	}, &payload)
+	if err != nil {
+		return nil, err
+	}
+	return &Response{
+		Text:  payload.Response,
+		Model: payload.Model,
+		Usage: Usage{PromptTokens: payload.PromptEvalCount, CompletionTokens: payload.EvalCount},
+	}, nil
+}
+
+// Embed calcula os embeddings dos textos (POST /api/embed), na mesma ordem
+func (o *Ollama) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	model := o.EmbeddingModel
+	if model == "" {
+		model = o.Model
+	}
+	var payload struct {
+		Embeddings [][]float32 `json:"embeddings"`
+	}
+	err := o.do(ctx, http.MethodPost, "/api/embed", map[string]interface{}{
+		"model": model,
+		"input": texts,
+	}, &payload)
+	if err != nil {
+		return nil, err
+	}
+	if len(payload.Embeddings) != len(texts) {
+		return nil, errs.New(errs.ErrValidation, "llm.Ollama", "%d embeddings retornados para %d textos", len(payload.Embeddings), len(texts))
+	}
+	return payload.Embeddings, nil
+}
+
+// Health implementa LocalProvider (GET /api/version)
+func (o *Ollama) Health(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, HealthTimeout)
+	defer cancel()
+	var payload struct {
+		Version string `json:"version"`
+	}
+	return o.do(ctx, http.MethodGet, "/api/version", nil, &payload)
+}
+
+// Models implementa LocalProvider (GET /api/tags)
+func (o *Ollama) Models(ctx context.Context) ([]string, error) {
+	var payload struct {
+		Models []struct {
+			Name string `json:"name"`
+		} `json:"models"`
+	}
+	if err := o.do(ctx, http.MethodGet, "/api/tags", nil, &payload); err != nil {
+		return nil, err
+	}
+	models := make([]string, len(payload.Models))
+	for i, model := range payload.Models {
+		models[i] = model.Name
+	}
+	return models, nil
+}
+
+// EnsureModel implementa LocalProvider: baixa o modelo ausente se AutoPull estiver ativo
+func (o *Ollama) EnsureModel(ctx context.Context, model string) error {
+	models, err := o.Models(ctx)
+	if err != nil {
+		return err
+	}
+	if hasModel(models, model) {
+		return nil
+	}
+	if !o.AutoPull {
+		return errs.New(errs.ErrNotFound, "llm.Ollama", "modelo %s não instalado e o download automático está desativado", model)
+	}
+	return o.Pull(ctx, model)
+}
+
+// Pull baixa o modelo (POST /api/pull). O andamento do download é informado pelo
+// progress.Reporter do contexto.
+func (o *Ollama) Pull(ctx context.Context, model string) error {
+	body, err := json.Marshal(map[string]interface{}{"model": model, "stream": true})
+	if err != nil {
+		return fmt.Errorf("erro ao serializar requisição ao Ollama: %v", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, o.BaseURL+"/api/pull", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("erro ao criar requisição ao Ollama: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := o.client.Do(req)
+	if err != nil {
+		return errs.FromContext("llm.Ollama", fmt.Errorf("erro ao chamar o Ollama: %w", err))
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return statusError("llm.Ollama", resp)
+	}
+
+	// Cada linha é um estado do download; as camadas informam o total e o já recebido
+	scanner := bufio.NewScanner(resp.Body)
+	last := ""
+	for scanner.Scan() {
+		var status struct {
+			Status    string `json:"status"`
+			Error     string `json:"error"`
+			Total     int64  `json:"total"`
+			Completed int64  `json:"completed"`
+		}
+		if err := json.Unmarshal(scanner.Bytes(), &status); err != nil {
+			continue
+		}
+		if status.Error != "" {
+			return fmt.Errorf("erro ao baixar o modelo %s: %s", model, status.Error)
+		}
+		last = status.Status
+		if status.Total > 0 {
+			progress.Report(ctx, float64(status.Completed)*100/float64(status.Total), fmt.Sprintf("%s: %s", model, status.Status))
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return errs.FromContext("llm.Ollama", fmt.Errorf("erro ao ler o download do modelo %s: %w", model, err))
+	}
+	if last != "success" {
+		return fmt.Errorf("download do modelo %s interrompido (último estado: %q)", model, last)
+	}
+	progress.Report(ctx, 100, model+": success")
+	return nil
+}
+
+// Delete remove o modelo do servidor (DELETE /api/delete)
+func (o *Ollama) Delete(ctx context.Context, model string) error {
+	return o.do(ctx, http.MethodDelete, "/api/delete", map[string]string{"model": model}, nil)
+}
+
+// do envia a requisição JSON e decodifica a resposta em out, se informado
+func (o *Ollama) do(ctx context.Context, method, path string, in, out interface{}) error {
+	var body bytes.Buffer
+	if in != nil {
+		if err := json.NewEncoder(&body).Encode(in); err != nil {
+			return fmt.Errorf("erro ao serializar requisição ao Ollama: %v", err)
+		}
+	}
+	req, err := http.NewRequestWithContext(ctx, method, o.BaseURL+path, &body)
+	if err != nil {
+		return fmt.Errorf("erro ao criar requisição ao Ollama: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := o.client.Do(req)
+	if err != nil {
+		return errs.FromContext("llm.Ollama", fmt.Errorf("erro ao chamar o Ollama: %w", err))
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return statusError("llm.Ollama", resp)
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("erro ao decodificar resposta do Ollama: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/suissa/HiveMind/agents/errs"
+	"github.com/suissa/HiveMind/agents/progress"
+)
+
+// ollamaServer simula a API do Ollama com os modelos instalados informados
+func ollamaServer(t *testing.T, installed ...string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/version":
+			w.Write([]byte(`{"version": "0.5.0"}`))
+		case "/api/tags":
+			models := []map[string]string{}
+			for _, name := range installed {
+				models = append(models, map[string]string{"name": name})
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{"models": models})
+		case "/api/generate":
+			var body struct {
+				Model   string                 `json:"model"`
+				Prompt  string                 `json:"prompt"`
+				Stream  bool                   `json:"stream"`
+				Options map[string]interface{} `json:"options"`
+			}
+			json.NewDecoder(r.Body).Decode(&body)
+			if body.Stream || body.Options["num_predict"] != float64(64) {
+				t.Errorf("requisição inesperada: %+v", body)
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"model": body.Model, "response": "eco: " + body.Prompt, "prompt_eval_count": 3, "eval_count": 5,
+			})
+		case "/api/embed":
+			var body struct {
+				Model string   `json:"model"`
+				Input []string `json:"input"`
+			}
+			json.NewDecoder(r.Body).Decode(&body)
+			embeddings := make([][]float32, len(body.Input))
+			for i, text := range body.Input {
+				embeddings[i] = []float32{float32(len(text)), 1}
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{"model": body.Model, "embeddings": embeddings})
+		case "/api/pull":
+			w.Write([]byte(`{"status": "pulling manifest"}` + "\n"))
+			w.Write([]byte(`{"status": "pulling abc", "total": 200, "completed": 100}` + "\n"))
+			w.Write([]byte(`{"status": "success"}` + "\n"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+}
+
+func TestOllamaComplete(t *testing.T) {
+	server := ollamaServer(t)
+	defer server.Close()
+
+	ollama := NewOllama(server.URL, "llama3.2")
+	resp, err := ollama.Complete(context.Background(), Request{Prompt: "oi", MaxTokens: 64})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Text != "eco: oi" || resp.Model != "llama3.2" || resp.Usage.CompletionTokens != 5 {
+		t.Fatalf("resposta = %+v", resp)
+	}
+	if err := ollama.Health(context.Background()); err != nil {
+		t.Fatalf("Health: %v", err)
+	}
+}
+
+func TestOllamaEmbed(t *testing.T) {
+	server := ollamaServer(t)
+	defer server.Close()
+
+	ollama := NewOllama(server.URL, "llama3.2")
+	ollama.EmbeddingModel = "nomic-embed-text"
+	vectors, err := ollama.Embed(context.Background(), []string{"oi", "olá mundo"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(vectors) != 2 || vectors[0][0] != 2 || vectors[1][0] != float32(len("olá mundo")) {
+		t.Fatalf("vetores = %v", vectors)
+	}
+}
+
+func TestOllamaEnsureModel(t *testing.T) {
+	server := ollamaServer(t, "llama3.2:latest")
+	defer server.Close()
+	ollama := NewOllama(server.URL, "llama3.2")
+
+	if err := ollama.EnsureModel(context.Background(), "llama3.2"); err != nil {
+		t.Fatalf("modelo instalado: %v", err)
+	}
+
+	var percents []float64
+	ctx := progress.WithReporter(context.Background(), progress.ReporterFunc(func(percent float64, message string) {
+		percents = append(percents, percent)
+	}))
+	if err := ollama.EnsureModel(ctx, "qwen2.5"); err != nil {
+		t.Fatalf("download: %v", err)
+	}
+	if len(percents) != 2 || percents[0] != 50 || percents[1] != 100 {
+		t.Fatalf("andamento do download = %v", percents)
+	}
+
+	ollama.AutoPull = false
+	if err := ollama.EnsureModel(context.Background(), "qwen2.5"); !errors.Is(err, errs.ErrNotFound) {
+		t.Fatalf("esperava ErrNotFound sem AutoPull: %v", err)
+	}
+}
+
+func TestOllamaPullError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"error": "pull model manifest: file does not exist"}` + "\n"))
+	}))
+	defer server.Close()
+
+	if err := NewOllama(server.URL, "").Pull(context.Background(), "inexistente"); err == nil {
+		t.Fatal("esperava erro no download")
+	}
+}
@@ -0,0 +1,47 @@
+package llm
+
+import (
+	"context"
+)
+
+// Request representa uma chamada a um modelo de linguagem
+type Request struct {
+	Model       string  `json:"model"`
+	System      string  `json:"system,omitempty"`
+	Prompt      string  `json:"prompt"`
+	Temperature float64 `json:"temperature"`
+	MaxTokens   int     `json:"max_tokens"`
+}
+
+// Usage contém o consumo de tokens de uma chamada
+type Usage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+}
+
+// Response representa a resposta de um modelo de linguagem
+type Response struct {
+	Text  string `json:"text"`
+	Model string `json:"model"`
+	Usage Usage  `json:"usage"`
+}
+
+// Provider define a interface comum dos provedores de LLM
+type Provider interface {
+	Complete(ctx context.Context, req Request) (*Response, error)
+}
+
+// ProviderFunc adapta uma função para a interface Provider (útil para respostas simuladas)
+type ProviderFunc func(ctx context.Context, req Request) (*Response, error)
+
+// Complete implementa Provider
+func (f ProviderFunc) Complete(ctx context.Context, req Request) (*Response, error) {
+	return f(ctx, req)
+}
+
+// BatchProvider é implementado pelos provedores com API de lote: as requisições são enviadas
+// numa única chamada, geralmente com custo menor. As respostas seguem a ordem das requisições.
+type BatchProvider interface {
+	Provider
+	CompleteBatch(ctx context.Context, reqs []Request) ([]*Response, error)
+}
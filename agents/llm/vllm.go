@@ -0,0 +1,194 @@
+package llm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/suissa/HiveMind/agents/errs"
+)
+
+// DefaultVLLMURL é o endereço padrão da API compatível com a OpenAI do vLLM
+const DefaultVLLMURL = "http://localhost:8000/v1"
+
+// VLLM é o provedor de inferência local do vLLM, pela API compatível com a OpenAI. Também
+// atende outros servidores compatíveis (llama.cpp, LM Studio, TGI).
+type VLLM struct {
+	BaseURL        string // Inclui o prefixo /v1
+	APIKey         string // Opcional (vllm serve --api-key)
+	Model          string // Modelo padrão
+	EmbeddingModel string // Modelo de Embed; vazio usa Model
+	client         *http.Client
+}
+
+// NewVLLM cria o provedor para o servidor e o modelo padrão informados
+func NewVLLM(baseURL, model string) *VLLM {
+	if baseURL == "" {
+		baseURL = DefaultVLLMURL
+	}
+	return &VLLM{
+		BaseURL: strings.TrimRight(baseURL, "/"),
+		Model:   model,
+		client:  &http.Client{},
+	}
+}
+
+// VLLMFromEnv lê VLLM_BASE_URL, VLLM_API_KEY, VLLM_MODEL e VLLM_EMBEDDING_MODEL
+func VLLMFromEnv() *VLLM {
+	v := NewVLLM(os.Getenv("VLLM_BASE_URL"), os.Getenv("VLLM_MODEL"))
+	v.APIKey = os.Getenv("VLLM_API_KEY")
+	v.EmbeddingModel = os.Getenv("VLLM_EMBEDDING_MODEL")
+	return v
+}
+
+// DefaultModel implementa LocalProvider
+func (v *VLLM) DefaultModel() string {
+	return v.Model
+}
+
+// Complete implementa Provider (POST /chat/completions)
+func (v *VLLM) Complete(ctx context.Context, req Request) (*Response, error) {
+	model := req.Model
+	if model == "" {
+		model = v.Model
+	}
+	messages := []map[string]string{}
+	if req.System != "" {
+		messages = append(messages, map[string]string{"role": "system", "content": req.System})
+	}
+	messages = append(messages, map[string]string{"role": "user", "content": req.Prompt})
+	body := map[string]interface{}{
+		"model":       model,
+		"messages":    messages,
+		"temperature": req.Temperature,
+	}
+	if req.MaxTokens > 0 {
+		body["max_tokens"] = req.MaxTokens
+	}
+
+	var payload struct {
+		Model   string `json:"model"`
+		Choices []struct {
+			Message struct {
+				Content string `json:"content"`
+			} `json:"message"`
+		} `json:"choices"`
+		Usage Usage `json:"usage"`
+	}
+	if err := v.do(ctx, http.MethodPost, v.BaseURL+"/chat/completions", body, &payload); err != nil {
+		return nil, err
+	}
+	if len(payload.Choices) == 0 {
+		return nil, fmt.Errorf("resposta do vLLM sem conteúdo")
+	}
+	return &Response{Text: payload.Choices[0].Message.Content, Model: payload.Model, Usage: payload.Usage}, nil
+}
+
+// Embed calcula os embeddings dos textos (POST /embeddings), na mesma ordem
+func (v *VLLM) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	model := v.EmbeddingModel
+	if model == "" {
+		model = v.Model
+	}
+	var payload struct {
+		Data []struct {
+			Index     int       `json:"index"`
+			Embedding []float32 `json:"embedding"`
+		} `json:"data"`
+	}
+	err := v.do(ctx, http.MethodPost, v.BaseURL+"/embeddings", map[string]interface{}{
+		"model": model,
+		"input": texts,
+	}, &payload)
+	if err != nil {
+		return nil, err
+	}
+	vectors := make([][]float32, len(texts))
+	for _, item := range payload.Data {
+		if item.Index < 0 || item.Index >= len(texts) {
+			return nil, errs.New(errs.ErrValidation, "llm.VLLM", "índice de embedding fora do intervalo: %d", item.Index)
+		}
+		vectors[item.Index] = item.Embedding
+	}
+	for i, vector := range vectors {
+		if vector == nil {
+			return nil, errs.New(errs.ErrValidation, "llm.VLLM", "embedding ausente para o texto %d", i)
+		}
+	}
+	return vectors, nil
+}
+
+// Health implementa LocalProvider (GET /health, fora do prefixo /v1)
+func (v *VLLM) Health(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, HealthTimeout)
+	defer cancel()
+	return v.do(ctx, http.MethodGet, strings.TrimSuffix(v.BaseURL, "/v1")+"/health", nil, nil)
+}
+
+// Models implementa LocalProvider (GET /models)
+func (v *VLLM) Models(ctx context.Context) ([]string, error) {
+	var payload struct {
+		Data []struct {
+			ID string `json:"id"`
+		} `json:"data"`
+	}
+	if err := v.do(ctx, http.MethodGet, v.BaseURL+"/models", nil, &payload); err != nil {
+		return nil, err
+	}
+	models := make([]string, len(payload.Data))
+	for i, model := range payload.Data {
+		models[i] = model.ID
+	}
+	return models, nil
+}
+
+// EnsureModel implementa LocalProvider. O vLLM carrega os modelos na inicialização do
+// servidor (vllm serve <modelo>), então um modelo ausente é apenas reportado.
+func (v *VLLM) EnsureModel(ctx context.Context, model string) error {
+	models, err := v.Models(ctx)
+	if err != nil {
+		return err
+	}
+	if !hasModel(models, model) {
+		return errs.New(errs.ErrNotFound, "llm.VLLM", "modelo %s não servido pelo vLLM (disponíveis: %v)", model, models)
+	}
+	return nil
+}
+
+// do envia a requisição JSON e decodifica a resposta em out, se informado
+func (v *VLLM) do(ctx context.Context, method, url string, in, out interface{}) error {
+	var body bytes.Buffer
+	if in != nil {
+		if err := json.NewEncoder(&body).Encode(in); err != nil {
+			return fmt.Errorf("erro ao serializar requisição ao vLLM: %v", err)
+		}
+	}
+	req, err := http.NewRequestWithContext(ctx, method, url, &body)
+	if err != nil {
+		return fmt.Errorf("erro ao criar requisição ao vLLM: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if v.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+v.APIKey)
+	}
+
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return errs.FromContext("llm.VLLM", fmt.Errorf("erro ao chamar o vLLM: %w", err))
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return statusError("llm.VLLM", resp)
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("erro ao decodificar resposta do vLLM: %v", err)
+	}
+	return nil
+}
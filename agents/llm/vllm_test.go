@@ -0,0 +1,83 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/suissa/HiveMind/agents/errs"
+)
+
+func TestVLLM(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer segredo" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/health":
+		case "/v1/models":
+			w.Write([]byte(`{"data": [{"id": "meta-llama/Llama-3.1-8B-Instruct"}]}`))
+		case "/v1/chat/completions":
+			var body struct {
+				Model    string `json:"model"`
+				Messages []struct {
+					Role    string `json:"role"`
+					Content string `json:"content"`
+				} `json:"messages"`
+			}
+			json.NewDecoder(r.Body).Decode(&body)
+			if len(body.Messages) != 2 || body.Messages[0].Role != "system" {
+				t.Errorf("mensagens inesperadas: %+v", body.Messages)
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"model":   body.Model,
+				"choices": []map[string]interface{}{{"message": map[string]string{"role": "assistant", "content": "olá"}}},
+				"usage":   map[string]int{"prompt_tokens": 7, "completion_tokens": 2},
+			})
+		case "/v1/embeddings":
+			// Fora de ordem, como permite a API
+			w.Write([]byte(`{"data": [{"index": 1, "embedding": [0, 1]}, {"index": 0, "embedding": [1, 0]}]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	vllm := NewVLLM(server.URL+"/v1", "meta-llama/Llama-3.1-8B-Instruct")
+	vllm.APIKey = "segredo"
+
+	if err := vllm.Health(context.Background()); err != nil {
+		t.Fatalf("Health: %v", err)
+	}
+	resp, err := vllm.Complete(context.Background(), Request{System: "seja breve", Prompt: "oi"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Text != "olá" || resp.Usage.PromptTokens != 7 {
+		t.Fatalf("resposta = %+v", resp)
+	}
+
+	vectors, err := vllm.Embed(context.Background(), []string{"a", "b"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vectors[0][0] != 1 || vectors[1][1] != 1 {
+		t.Fatalf("vetores = %v", vectors)
+	}
+
+	if err := vllm.EnsureModel(context.Background(), vllm.Model); err != nil {
+		t.Fatalf("modelo servido: %v", err)
+	}
+	if err := vllm.EnsureModel(context.Background(), "outro"); !errors.Is(err, errs.ErrNotFound) {
+		t.Fatalf("esperava ErrNotFound: %v", err)
+	}
+
+	vllm.APIKey = ""
+	if _, err := vllm.Complete(context.Background(), Request{Prompt: "oi"}); err == nil {
+		t.Fatal("esperava erro sem a chave da API")
+	}
+}
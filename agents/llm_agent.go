@@ -2,47 +2,56 @@ package agents
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"time"
 
 	"github.com/streadway/amqp"
+
+	"github.com/suissa/HiveMind/agents/communication"
+	"github.com/suissa/HiveMind/agents/deadline"
+	"github.com/suissa/HiveMind/agents/messages"
+	"github.com/suissa/HiveMind/agents/shutdown"
+	"github.com/suissa/HiveMind/agents/simulation"
+	"github.com/suissa/HiveMind/agents/supervisor"
+	"github.com/suissa/HiveMind/agents/tenant"
 )
 
 // LLMAgent representa um agent que processa tarefas do RouteLLM
 type LLMAgent struct {
 	ID          string
 	Type        string
+	Tenant      string
 	conn        *amqp.Connection
-	channel     *amqp.Channel
+	channel     *amqp.Channel               // Consumo da fila de tarefas
+	publisher   *communication.RabbitMQPool // Publicação dos resultados, com confirmação do broker
 	taskQueue   string
 	resultQueue string
+	signer      communication.Signer   // Assina os resultados publicados
+	verifier    communication.Verifier // Verifica as subtarefas consumidas
+	shutdown    *shutdown.Manager
+	supervisor  *supervisor.Supervisor
 }
 
-// SubTask representa uma subtarefa a ser executada (mesma estrutura do orchestrator)
-type SubTask struct {
-	ID          string                 `json:"id"`
-	ParentID    string                 `json:"parent_id"`
-	Name        string                 `json:"name"`
-	Description string                 `json:"description"`
-	Type        string                 `json:"type"`
-	Parameters  map[string]interface{} `json:"parameters"`
-	Status      string                 `json:"status"`
-}
+// Contratos das mensagens das filas, os mesmos do orchestrator, gerados dos JSON Schemas em
+// agents/messages/schemas
+type (
+	SubTask    = messages.SubTask
+	TaskResult = messages.TaskResult
+)
 
-// TaskResult representa o resultado do processamento de uma tarefa
-type TaskResult struct {
-	TaskID      string                 `json:"task_id"`
-	ParentID    string                 `json:"parent_id"`
-	AgentID     string                 `json:"agent_id"`
-	Status      string                 `json:"status"`
-	Result      map[string]interface{} `json:"result"`
-	CompletedAt string                 `json:"completed_at"`
+// NewLLMAgent cria um novo LLMAgent para o tenant padrão
+func NewLLMAgent(id string, agentType string, conn *amqp.Connection) (*LLMAgent, error) {
+	return NewTenantLLMAgent(id, agentType, tenant.DefaultTenant, conn)
 }
 
-// NewLLMAgent cria um novo LLMAgent
-func NewLLMAgent(id string, agentType string, conn *amqp.Connection) (*LLMAgent, error) {
+// NewTenantLLMAgent cria um LLMAgent que consome as filas do tenant informado
+func NewTenantLLMAgent(id string, agentType string, tenantID string, conn *amqp.Connection) (*LLMAgent, error) {
+	if err := tenant.Validate(tenantID); err != nil {
+		return nil, err
+	}
+
 	channel, err := conn.Channel()
 	if err != nil {
 		return nil, fmt.Errorf("erro ao criar canal: %v", err)
@@ -51,18 +60,25 @@ func NewLLMAgent(id string, agentType string, conn *amqp.Connection) (*LLMAgent,
 	return &LLMAgent{
 		ID:          id,
 		Type:        agentType,
+		Tenant:      tenantID,
 		conn:        conn,
 		channel:     channel,
-		taskQueue:   "llm_tasks",
-		resultQueue: "llm_results",
+		publisher:   communication.NewConnectionPool(conn, communication.PoolOptions{}),
+		taskQueue:   tenant.Namespace(tenantID, "llm_tasks"),
+		resultQueue: tenant.Namespace(tenantID, "llm_results"),
+		supervisor:  supervisor.Default,
 	}, nil
 }
 
-// processTask simula o processamento de uma tarefa
-func (a *LLMAgent) processTask(task SubTask) TaskResult {
+// processTask simula o processamento de uma tarefa, interrompendo-o se o contexto for cancelado
+func (a *LLMAgent) processTask(ctx context.Context, task SubTask) (TaskResult, error) {
 	// Simula o tempo de processamento
 	processingTime := time.Duration(2+time.Now().Unix()%3) * time.Second
-	time.Sleep(processingTime)
+	select {
+	case <-ctx.Done():
+		return TaskResult{}, ctx.Err()
+	case <-time.After(processingTime):
+	}
 
 	result := TaskResult{
 		TaskID:      task.ID,
@@ -81,7 +97,48 @@ func (a *LLMAgent) processTask(task SubTask) TaskResult {
 		},
 	}
 
-	return result
+	return result, nil
+}
+
+// timeoutResult é o resultado de uma tarefa que excedeu o prazo
+func (a *LLMAgent) timeoutResult(task SubTask, at time.Time, err error) TaskResult {
+	return TaskResult{
+		TaskID:      task.ID,
+		ParentID:    task.ParentID,
+		AgentID:     a.ID,
+		Status:      string(TaskStatusTimeout),
+		CompletedAt: time.Now().Format(time.RFC3339),
+		Result: map[string]interface{}{
+			"error":    deadline.Error("agents.LLMAgent", task.ID, at, err).Error(),
+			"deadline": at.Format(time.RFC3339),
+		},
+	}
+}
+
+// SetShutdown registra as tarefas do agent no coordenador de encerramento,
+// que aguarda as tarefas em andamento antes de fechar as conexões
+func (a *LLMAgent) SetShutdown(m *shutdown.Manager) {
+	a.shutdown = m
+}
+
+// SetSigning configura a assinatura dos resultados publicados e a verificação das subtarefas
+// consumidas. Com verifier definido, subtarefas sem assinatura válida são descartadas.
+func (a *LLMAgent) SetSigning(signer communication.Signer, verifier communication.Verifier) {
+	a.signer = signer
+	a.verifier = verifier
+}
+
+// SetSupervisor define o supervisor que reinicia o consumo após um panic
+func (a *LLMAgent) SetSupervisor(s *supervisor.Supervisor) {
+	a.supervisor = s
+}
+
+// StopIntake cancela o consumo da fila de tarefas sem interromper as tarefas em andamento
+func (a *LLMAgent) StopIntake(ctx context.Context) error {
+	if err := a.channel.Cancel(a.ID, false); err != nil {
+		return fmt.Errorf("erro ao cancelar consumo do agent %s: %v", a.ID, err)
+	}
+	return nil
 }
 
 // Start inicia o processamento de tarefas
@@ -101,60 +158,137 @@ func (a *LLMAgent) Start(ctx context.Context) error {
 
 	log.Printf("🤖 Agent %s (%s) iniciado e aguardando tarefas...", a.ID, a.Type)
 
-	go func() {
+	// O loop é reiniciado com backoff se um panic escapar do processamento de uma tarefa
+	a.supervisor.Go(ctx, "llm_agent:"+a.ID, func(ctx context.Context) error {
 		for {
 			select {
 			case <-ctx.Done():
-				return
-			case msg := <-msgs:
-				var task SubTask
-				if err := json.Unmarshal(msg.Body, &task); err != nil {
-					log.Printf("❌ Agent %s: Erro ao deserializar tarefa: %v", a.ID, err)
-					msg.Nack(false, true)
-					continue
+				// Para de receber entregas; as não confirmadas voltam para a fila
+				a.channel.Cancel(a.ID, false)
+				return nil
+			case msg, ok := <-msgs:
+				if !ok {
+					return nil
 				}
+				a.handleDelivery(ctx, msg)
+			}
+		}
+	})
 
-				// Verifica se o agent pode processar este tipo de tarefa
-				if task.Type != a.Type {
-					msg.Nack(false, true) // Rejeita e recoloca na fila
-					continue
-				}
+	return nil
+}
 
-				log.Printf("🔄 Agent %s: Processando tarefa %s", a.ID, task.Name)
+// handleDelivery verifica, decodifica e processa uma subtarefa recebida da fila de tarefas
+func (a *LLMAgent) handleDelivery(ctx context.Context, msg amqp.Delivery) {
+	// Subtarefas sem assinatura válida não voltam para a fila
+	if a.verifier != nil {
+		if err := communication.VerifyAMQP(a.verifier, &msg, communication.DefaultSignatureMaxAge); err != nil {
+			log.Printf("🚫 Agent %s: subtarefa rejeitada: %v", a.ID, err)
+			msg.Nack(false, false)
+			return
+		}
+	}
 
-				// Processa a tarefa
-				result := a.processTask(task)
+	// Subtarefas fora do contrato não voltam para a fila; as de uma versão mais nova
+	// do contrato voltam, para um agent atualizado
+	var task SubTask
+	if err := messages.Decode(msg.Body, &task); err != nil {
+		log.Printf("❌ Agent %s: subtarefa inválida: %v", a.ID, err)
+		msg.Nack(false, errors.Is(err, messages.ErrUnsupportedVersion))
+		return
+	}
 
-				// Publica o resultado
-				resultBytes, err := json.Marshal(result)
-				if err != nil {
-					log.Printf("❌ Agent %s: Erro ao serializar resultado: %v", a.ID, err)
-					msg.Nack(false, true)
-					continue
-				}
+	// Verifica se o agent pode processar este tipo de tarefa
+	if task.Type != a.Type {
+		msg.Nack(false, true) // Rejeita e recoloca na fila
+		return
+	}
 
-				err = a.channel.Publish(
-					"",            // exchange
-					a.resultQueue, // routing key
-					false,         // mandatory
-					false,         // immediate
-					amqp.Publishing{
-						ContentType: "application/json",
-						Body:        resultBytes,
-					})
-				if err != nil {
-					log.Printf("❌ Agent %s: Erro ao publicar resultado: %v", a.ID, err)
-					msg.Nack(false, true)
-					continue
-				}
+	a.handleTask(ctx, msg, task)
+}
 
-				msg.Ack(false)
-				log.Printf("✅ Agent %s: Tarefa %s concluída", a.ID, task.Name)
-			}
+// handleTask processa a tarefa, publica o resultado e confirma a mensagem
+func (a *LLMAgent) handleTask(ctx context.Context, msg amqp.Delivery, task SubTask) {
+	// Durante o encerramento a tarefa volta para a fila
+	if a.shutdown != nil {
+		done, err := a.shutdown.Track()
+		if err != nil {
+			msg.Nack(false, true)
+			return
+		}
+		defer done()
+	}
+
+	// Uma tarefa que causa panic é descartada para não derrubar o agent em loop
+	defer func() {
+		if r := recover(); r != nil {
+			msg.Nack(false, false)
+			panic(r)
 		}
 	}()
 
-	return nil
+	log.Printf("🔄 Agent %s: Processando tarefa %s", a.ID, task.Name)
+
+	// O prazo dos parâmetros (deadline ou timeout) interrompe a tarefa; um prazo inválido é
+	// ignorado para não descartar a tarefa
+	at, err := deadline.FromParameters(task.Parameters, time.Now())
+	if err != nil {
+		log.Printf("⚠️ Agent %s: %v", a.ID, err)
+	}
+	taskCtx, cancel := deadline.Context(ctx, at)
+	defer cancel()
+
+	// Processa a tarefa
+	result, err := a.processTask(taskCtx, task)
+	if err != nil && deadline.Expired(ctx, taskCtx) {
+		// A tarefa expirada é concluída com o status de timeout em vez de voltar para a fila
+		log.Printf("⏰ Agent %s: Tarefa %s excedeu o prazo %s", a.ID, task.Name, at.Format(time.RFC3339))
+		result, err = a.timeoutResult(task, at, err), nil
+	}
+	if err != nil {
+		log.Printf("⏹️ Agent %s: Tarefa %s interrompida: %v", a.ID, task.Name, err)
+		msg.Nack(false, true)
+		return
+	}
+
+	// Publica o resultado
+	resultBytes, err := messages.Encode(result)
+	if err != nil {
+		log.Printf("❌ Agent %s: Erro ao serializar resultado: %v", a.ID, err)
+		msg.Nack(false, true)
+		return
+	}
+
+	err = a.publishResult(ctx, resultBytes)
+	if err != nil {
+		log.Printf("❌ Agent %s: Erro ao publicar resultado: %v", a.ID, err)
+		msg.Nack(false, true)
+		return
+	}
+
+	msg.Ack(false)
+	log.Printf("✅ Agent %s: Tarefa %s concluída", a.ID, task.Name)
+}
+
+// publishResult publica o resultado na fila de resultados, assinando-o quando há um signer
+// configurado, e aguarda a confirmação do broker. No modo dry-run a publicação é apenas
+// registrada na sessão de simulação.
+func (a *LLMAgent) publishResult(ctx context.Context, body []byte) error {
+	if session, ok := simulation.FromContext(ctx); ok {
+		session.Record(simulation.EffectPublish, a.resultQueue, string(body))
+		return nil
+	}
+
+	msg := amqp.Publishing{
+		ContentType: "application/json",
+		Body:        body,
+	}
+	if a.signer != nil {
+		if err := communication.SignAMQP(a.signer, a.resultQueue, &msg); err != nil {
+			return err
+		}
+	}
+	return a.publisher.Publish(ctx, "", a.resultQueue, msg)
 }
 
 // Close fecha a conexão do agent
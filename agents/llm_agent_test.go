@@ -0,0 +1,78 @@
+package agents
+
+import (
+	"context"
+	"testing"
+
+	"github.com/streadway/amqp"
+
+	"github.com/suissa/HiveMind/agents/communication"
+	"github.com/suissa/HiveMind/agents/messages"
+)
+
+// fakeAcknowledger registra as confirmações de uma entrega
+type fakeAcknowledger struct {
+	acked, nacked, requeued bool
+}
+
+func (a *fakeAcknowledger) Ack(tag uint64, multiple bool) error {
+	a.acked = true
+	return nil
+}
+
+func (a *fakeAcknowledger) Nack(tag uint64, multiple, requeue bool) error {
+	a.nacked, a.requeued = true, requeue
+	return nil
+}
+
+func (a *fakeAcknowledger) Reject(tag uint64, requeue bool) error {
+	return a.Nack(tag, false, requeue)
+}
+
+func TestLLMAgentVerifiesSubtasks(t *testing.T) {
+	body, err := messages.Encode(SubTask{ID: "s-1", ParentID: "t-1", Type: "research", Status: "pending"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	keys := communication.NewKeyRing()
+	keys.AddHMACKey("router", []byte("segredo"))
+
+	// signed publica a subtarefa na fila informada, assinada com a chave do signer
+	signed := func(signer communication.Signer, queue string) amqp.Table {
+		msg := amqp.Publishing{Body: body}
+		if err := communication.SignAMQP(signer, queue, &msg); err != nil {
+			t.Fatal(err)
+		}
+		return msg.Headers
+	}
+
+	tests := []struct {
+		name     string
+		verifier communication.Verifier
+		headers  amqp.Table
+		requeue  bool // A subtarefa aceita volta para a fila por ser de outro tipo de agent
+	}{
+		{name: "Assinatura válida", verifier: keys, headers: signed(communication.NewHMACSigner("router", []byte("segredo")), "llm_tasks"), requeue: true},
+		{name: "Sem assinatura", verifier: keys, headers: nil},
+		{name: "Chave desconhecida", verifier: keys, headers: signed(communication.NewHMACSigner("outro", []byte("segredo")), "llm_tasks")},
+		{name: "Segredo errado", verifier: keys, headers: signed(communication.NewHMACSigner("router", []byte("errado")), "llm_tasks")},
+		{name: "Assinada para outra fila", verifier: keys, headers: signed(communication.NewHMACSigner("router", []byte("segredo")), "acme.llm_tasks")},
+		{name: "Sem verificação", verifier: nil, headers: nil, requeue: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			agent := &LLMAgent{ID: "writer-1", Type: "writing", verifier: tt.verifier}
+			ack := &fakeAcknowledger{}
+			agent.handleDelivery(context.Background(), amqp.Delivery{
+				Acknowledger: ack,
+				RoutingKey:   "llm_tasks",
+				Headers:      tt.headers,
+				Body:         body,
+			})
+			if ack.acked || !ack.nacked || ack.requeued != tt.requeue {
+				t.Fatalf("confirmação inesperada: %+v, esperava requeue %v", ack, tt.requeue)
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+// Package logging controla o nível dos logs do processo. O nível inicial vem da variável
+// LOG_LEVEL (debug, info, warn ou error; padrão info) e pode ser alterado em tempo de execução,
+// inclusive pelo comando log_level do plano de controle.
+package logging
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"sync/atomic"
+)
+
+// Level é o nível de um log
+type Level int32
+
+const (
+	LevelDebug Level = iota
+	LevelInfo
+	LevelWarn
+	LevelError
+)
+
+var names = map[Level]string{
+	LevelDebug: "debug",
+	LevelInfo:  "info",
+	LevelWarn:  "warn",
+	LevelError: "error",
+}
+
+// String retorna o nome do nível
+func (l Level) String() string {
+	if name, ok := names[l]; ok {
+		return name
+	}
+	return fmt.Sprintf("level(%d)", int32(l))
+}
+
+// ParseLevel converte o nome de um nível; "warning" é aceito como "warn"
+func ParseLevel(name string) (Level, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "warning" {
+		name = "warn"
+	}
+	for level, n := range names {
+		if n == name {
+			return level, nil
+		}
+	}
+	return LevelInfo, fmt.Errorf("nível de log desconhecido: %q", name)
+}
+
+var current atomic.Int32
+
+func init() {
+	current.Store(int32(LevelInfo))
+	if name := os.Getenv("LOG_LEVEL"); name != "" {
+		if level, err := ParseLevel(name); err == nil {
+			SetLevel(level)
+		} else {
+			log.Printf("⚠️ LOG_LEVEL inválido, usando info: %v", err)
+		}
+	}
+}
+
+// SetLevel define o nível mínimo dos logs emitidos
+func SetLevel(level Level) {
+	current.Store(int32(level))
+}
+
+// CurrentLevel retorna o nível mínimo dos logs emitidos
+func CurrentLevel() Level {
+	return Level(current.Load())
+}
+
+// Enabled informa se os logs do nível são emitidos
+func Enabled(level Level) bool {
+	return level >= CurrentLevel()
+}
+
+// Debugf emite um log de depuração
+func Debugf(format string, args ...interface{}) {
+	logf(LevelDebug, format, args...)
+}
+
+// Infof emite um log informativo
+func Infof(format string, args ...interface{}) {
+	logf(LevelInfo, format, args...)
+}
+
+// Warnf emite um aviso
+func Warnf(format string, args ...interface{}) {
+	logf(LevelWarn, format, args...)
+}
+
+// Errorf emite um log de erro
+func Errorf(format string, args ...interface{}) {
+	logf(LevelError, format, args...)
+}
+
+func logf(level Level, format string, args ...interface{}) {
+	if Enabled(level) {
+		log.Printf(format, args...)
+	}
+}
@@ -0,0 +1,43 @@
+package logging
+
+import (
+	"bytes"
+	"log"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestParseLevel(t *testing.T) {
+	cases := map[string]Level{"debug": LevelDebug, "INFO": LevelInfo, " warning ": LevelWarn, "error": LevelError}
+	for name, want := range cases {
+		got, err := ParseLevel(name)
+		if err != nil || got != want {
+			t.Errorf("ParseLevel(%q) = %v, %v; esperava %v", name, got, err, want)
+		}
+	}
+	if _, err := ParseLevel("verbose"); err == nil {
+		t.Fatal("nível desconhecido deveria falhar")
+	}
+}
+
+func TestSetLevelFiltersLogs(t *testing.T) {
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	defer log.SetOutput(os.Stderr)
+	defer SetLevel(CurrentLevel())
+
+	SetLevel(LevelWarn)
+	Infof("informação")
+	Warnf("aviso")
+	if out := buf.String(); strings.Contains(out, "informação") || !strings.Contains(out, "aviso") {
+		t.Fatalf("saída inesperada com o nível warn: %q", out)
+	}
+
+	buf.Reset()
+	SetLevel(LevelDebug)
+	Debugf("depuração")
+	if !strings.Contains(buf.String(), "depuração") {
+		t.Fatalf("o log de depuração deveria ser emitido: %q", buf.String())
+	}
+}
@@ -0,0 +1,324 @@
+// Package maintenance agenda a manutenção periódica da memória dos agentes: consolidação
+// das memórias importantes em lotes e limpeza das antigas, com intervalo por agente e,
+// opcionalmente, apenas dentro de uma janela de baixo movimento.
+package maintenance
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Padrões do agendador
+const (
+	DefaultInterval  = time.Hour
+	DefaultBatchSize = 100
+)
+
+// Etapas informadas no progresso da manutenção
+const (
+	StageConsolidate = "consolidate" // Um lote de consolidação foi concluído
+	StagePrune       = "prune"       // A limpeza das memórias antigas foi concluída
+	StageComplete    = "complete"    // A manutenção do agente terminou
+	StageDeferred    = "deferred"    // A janela fechou; a manutenção continua na próxima abertura
+)
+
+// Window é a janela diária, em horas locais, em que a manutenção pode rodar. Uma janela
+// com Start maior que End atravessa a meia-noite (ex.: 22 a 6); Start igual a End cobre o
+// dia inteiro.
+type Window struct {
+	Start int `json:"start" yaml:"start"` // Hora de abertura (0-23)
+	End   int `json:"end" yaml:"end"`     // Hora de fechamento (0-23), exclusiva
+}
+
+// Validate verifica as horas da janela
+func (w Window) Validate() error {
+	if w.Start < 0 || w.Start > 23 || w.End < 0 || w.End > 23 {
+		return fmt.Errorf("janela de manutenção inválida: %d a %d", w.Start, w.End)
+	}
+	return nil
+}
+
+// Contains informa se o instante está dentro da janela
+func (w Window) Contains(t time.Time) bool {
+	hour := t.Hour()
+	switch {
+	case w.Start == w.End:
+		return true
+	case w.Start < w.End:
+		return hour >= w.Start && hour < w.End
+	default:
+		return hour >= w.Start || hour < w.End
+	}
+}
+
+// Next retorna t, se estiver dentro da janela, ou a próxima abertura da janela
+func (w Window) Next(t time.Time) time.Time {
+	if w.Contains(t) {
+		return t
+	}
+	open := time.Date(t.Year(), t.Month(), t.Day(), w.Start, 0, 0, 0, t.Location())
+	if !open.After(t) {
+		open = open.AddDate(0, 0, 1)
+	}
+	return open
+}
+
+// Config configura o agendador
+type Config struct {
+	// Interval é o intervalo padrão entre manutenções de um agente (padrão DefaultInterval)
+	Interval time.Duration `json:"interval" yaml:"interval"`
+	// Intervals define intervalos por agente; os agentes listados são agendados ao iniciar
+	Intervals map[string]time.Duration `json:"intervals,omitempty" yaml:"intervals,omitempty"`
+	// Window restringe a manutenção a uma janela diária (nil permite qualquer horário)
+	Window *Window `json:"window,omitempty" yaml:"window,omitempty"`
+	// BatchSize limita as memórias avaliadas por lote de consolidação (padrão DefaultBatchSize)
+	BatchSize int `json:"batch_size" yaml:"batch_size"`
+	// SkipPrune desativa a limpeza das memórias antigas após a consolidação
+	SkipPrune bool `json:"skip_prune" yaml:"skip_prune"`
+}
+
+// Target é a memória mantida pelo agendador
+type Target interface {
+	// ConsolidateBatch avalia até limit memórias de curto prazo do agente, retornando quantas
+	// foram consolidadas e se restam memórias a avaliar
+	ConsolidateBatch(ctx context.Context, agentID string, limit int) (consolidated int, more bool, err error)
+	// PruneMemories remove as memórias antigas ou irrelevantes do agente
+	PruneMemories(ctx context.Context, agentID string) error
+}
+
+// Progress descreve o andamento da manutenção de um agente
+type Progress struct {
+	AgentID      string
+	Stage        string        // StageConsolidate, StagePrune, StageComplete ou StageDeferred
+	Batches      int           // Lotes de consolidação concluídos
+	Consolidated int           // Memórias consolidadas até o momento
+	Duration     time.Duration // Tempo decorrido desde o início da manutenção do agente
+	Err          error
+}
+
+// Observer recebe o progresso da manutenção
+type Observer func(ctx context.Context, progress Progress)
+
+// Scheduler executa a manutenção dos agentes agendados
+type Scheduler struct {
+	target    Target
+	config    Config
+	observer  Observer
+	intervals map[string]time.Duration
+	next      map[string]time.Time
+	wake      chan struct{}
+	now       func() time.Time
+	mu        sync.Mutex
+}
+
+// New cria um agendador para a memória informada, já com os agentes de config.Intervals
+func New(target Target, config Config) (*Scheduler, error) {
+	if config.Window != nil {
+		if err := config.Window.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	if config.Interval <= 0 {
+		config.Interval = DefaultInterval
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = DefaultBatchSize
+	}
+	s := &Scheduler{
+		target:    target,
+		config:    config,
+		intervals: make(map[string]time.Duration),
+		next:      make(map[string]time.Time),
+		wake:      make(chan struct{}, 1),
+		now:       time.Now,
+	}
+	for agentID, interval := range config.Intervals {
+		s.Schedule(agentID, interval)
+	}
+	return s, nil
+}
+
+// OnProgress define quem recebe o progresso da manutenção
+func (s *Scheduler) OnProgress(observer Observer) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.observer = observer
+}
+
+// Schedule agenda a manutenção periódica do agente. Com interval zero vale o intervalo de
+// config.Intervals para o agente ou, na falta dele, config.Interval. A primeira manutenção
+// ocorre após um intervalo.
+func (s *Scheduler) Schedule(agentID string, interval time.Duration) {
+	s.mu.Lock()
+	if interval <= 0 {
+		interval = s.config.Intervals[agentID]
+	}
+	if interval <= 0 {
+		interval = s.config.Interval
+	}
+	s.intervals[agentID] = interval
+	s.next[agentID] = s.now().Add(interval)
+	s.mu.Unlock()
+	s.notify()
+}
+
+// Unschedule remove o agente do agendamento
+func (s *Scheduler) Unschedule(agentID string) {
+	s.mu.Lock()
+	delete(s.intervals, agentID)
+	delete(s.next, agentID)
+	s.mu.Unlock()
+	s.notify()
+}
+
+// Scheduled retorna a próxima manutenção de cada agente agendado
+func (s *Scheduler) Scheduled() map[string]time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	next := make(map[string]time.Time, len(s.next))
+	for agentID, at := range s.next {
+		next[agentID] = at
+	}
+	return next
+}
+
+// notify acorda o laço de Run para recalcular a próxima manutenção
+func (s *Scheduler) notify() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Run executa as manutenções agendadas até o contexto ser cancelado
+func (s *Scheduler) Run(ctx context.Context) error {
+	for {
+		timer := time.NewTimer(s.wait())
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-s.wake:
+			timer.Stop()
+			continue
+		case <-timer.C:
+		}
+
+		for _, agentID := range s.due() {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			complete, _ := s.Maintain(ctx, agentID)
+			s.reschedule(agentID, complete)
+		}
+	}
+}
+
+// wait retorna quanto falta para a próxima manutenção, respeitando a janela
+func (s *Scheduler) wait() time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.next) == 0 {
+		return s.config.Interval
+	}
+	var earliest time.Time
+	for _, at := range s.next {
+		if earliest.IsZero() || at.Before(earliest) {
+			earliest = at
+		}
+	}
+	if s.config.Window != nil {
+		earliest = s.config.Window.Next(earliest)
+	}
+	return max(earliest.Sub(s.now()), 0)
+}
+
+// due retorna, em ordem, os agentes cuja manutenção venceu (nenhum fora da janela)
+func (s *Scheduler) due() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	if s.config.Window != nil && !s.config.Window.Contains(now) {
+		return nil
+	}
+	var due []string
+	for agentID, at := range s.next {
+		if !at.After(now) {
+			due = append(due, agentID)
+		}
+	}
+	sort.Strings(due)
+	return due
+}
+
+// reschedule agenda a próxima manutenção do agente; uma manutenção interrompida pela janela
+// continua na próxima abertura
+func (s *Scheduler) reschedule(agentID string, complete bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	interval, ok := s.intervals[agentID]
+	if !ok {
+		return
+	}
+	if complete {
+		s.next[agentID] = s.now().Add(interval)
+	} else {
+		s.next[agentID] = s.now()
+	}
+}
+
+// Maintain executa imediatamente a manutenção do agente: consolida em lotes enquanto houver
+// memórias a avaliar e a janela estiver aberta e, em seguida, limpa as memórias antigas.
+// Retorna false se a janela fechou antes do fim.
+func (s *Scheduler) Maintain(ctx context.Context, agentID string) (complete bool, err error) {
+	start := s.now()
+	progress := Progress{AgentID: agentID}
+	report := func(stage string, err error) {
+		progress.Stage = stage
+		progress.Duration = s.now().Sub(start)
+		progress.Err = err
+		s.report(ctx, progress)
+	}
+
+	for more := true; more; {
+		if s.config.Window != nil && !s.config.Window.Contains(s.now()) {
+			report(StageDeferred, nil)
+			return false, nil
+		}
+		var consolidated int
+		consolidated, more, err = s.target.ConsolidateBatch(ctx, agentID, s.config.BatchSize)
+		progress.Batches++
+		progress.Consolidated += consolidated
+		report(StageConsolidate, err)
+		if err != nil {
+			report(StageComplete, err)
+			return true, fmt.Errorf("erro na consolidação das memórias do agente %s: %w", agentID, err)
+		}
+	}
+
+	if !s.config.SkipPrune {
+		err = s.target.PruneMemories(ctx, agentID)
+		report(StagePrune, err)
+		if err != nil {
+			err = fmt.Errorf("erro na limpeza das memórias do agente %s: %w", agentID, err)
+		}
+	}
+	report(StageComplete, err)
+	return true, err
+}
+
+// report entrega o progresso ao observador, se houver
+func (s *Scheduler) report(ctx context.Context, progress Progress) {
+	s.mu.Lock()
+	observer := s.observer
+	s.mu.Unlock()
+	if observer != nil {
+		observer(ctx, progress)
+	}
+}
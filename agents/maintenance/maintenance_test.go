@@ -0,0 +1,113 @@
+package maintenance
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+type fakeTarget struct {
+	pending int
+	pruned  []string
+}
+
+func (f *fakeTarget) ConsolidateBatch(ctx context.Context, agentID string, limit int) (int, bool, error) {
+	n := min(f.pending, limit)
+	f.pending -= n
+	return n, f.pending > 0, nil
+}
+
+func (f *fakeTarget) PruneMemories(ctx context.Context, agentID string) error {
+	f.pruned = append(f.pruned, agentID)
+	return nil
+}
+
+func TestWindowAcrossMidnight(t *testing.T) {
+	w := Window{Start: 22, End: 6}
+	at := func(hour int) time.Time { return time.Date(2024, 5, 1, hour, 30, 0, 0, time.UTC) }
+
+	if !w.Contains(at(23)) || !w.Contains(at(2)) || w.Contains(at(12)) {
+		t.Fatal("janela 22-6 avaliada incorretamente")
+	}
+	if next := w.Next(at(12)); !next.Equal(time.Date(2024, 5, 1, 22, 0, 0, 0, time.UTC)) {
+		t.Fatalf("próxima abertura inesperada: %v", next)
+	}
+	if next := w.Next(at(3)); !next.Equal(at(3)) {
+		t.Fatalf("instante dentro da janela deveria ser mantido: %v", next)
+	}
+}
+
+func TestMaintainConsolidatesInBatchesAndPrunes(t *testing.T) {
+	target := &fakeTarget{pending: 250}
+	s, err := New(target, Config{BatchSize: 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var stages []string
+	var last Progress
+	s.OnProgress(func(ctx context.Context, p Progress) {
+		stages = append(stages, p.Stage)
+		last = p
+	})
+
+	complete, err := s.Maintain(context.Background(), "agent-1")
+	if err != nil || !complete {
+		t.Fatalf("manutenção incompleta: %v", err)
+	}
+	want := []string{StageConsolidate, StageConsolidate, StageConsolidate, StagePrune, StageComplete}
+	if len(stages) != len(want) {
+		t.Fatalf("etapas inesperadas: %v", stages)
+	}
+	for i := range want {
+		if stages[i] != want[i] {
+			t.Fatalf("etapas inesperadas: %v", stages)
+		}
+	}
+	if last.Batches != 3 || last.Consolidated != 250 || len(target.pruned) != 1 {
+		t.Fatalf("progresso inesperado: %+v, limpezas %v", last, target.pruned)
+	}
+}
+
+func TestMaintainDefersOutsideWindow(t *testing.T) {
+	target := &fakeTarget{pending: 10}
+	s, err := New(target, Config{Window: &Window{Start: 1, End: 2}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.now = func() time.Time { return time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC) }
+
+	complete, err := s.Maintain(context.Background(), "agent-1")
+	if err != nil || complete {
+		t.Fatalf("esperava manutenção adiada: complete=%v err=%v", complete, err)
+	}
+	if target.pending != 10 || len(target.pruned) != 0 {
+		t.Fatal("nada deveria rodar fora da janela")
+	}
+}
+
+func TestScheduleUsesPerAgentInterval(t *testing.T) {
+	s, err := New(&fakeTarget{}, Config{Interval: time.Hour, Intervals: map[string]time.Duration{"busy": 5 * time.Minute}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	s.now = func() time.Time { return now }
+	s.Schedule("busy", 0)
+	s.Schedule("quiet", 0)
+
+	next := s.Scheduled()
+	if next["busy"] != now.Add(5*time.Minute) || next["quiet"] != now.Add(time.Hour) {
+		t.Fatalf("agendamento inesperado: %v", next)
+	}
+
+	now = now.Add(10 * time.Minute)
+	if due := s.due(); len(due) != 1 || due[0] != "busy" {
+		t.Fatalf("agentes vencidos inesperados: %v", due)
+	}
+}
+
+func TestNewRejectsInvalidWindow(t *testing.T) {
+	if _, err := New(&fakeTarget{}, Config{Window: &Window{Start: 25}}); err == nil {
+		t.Fatal("esperava erro para janela inválida")
+	}
+}
@@ -1,9 +1,24 @@
 package agents
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
 	"time"
 
-	"HiveMind/agents/memory"
+	"github.com/suissa/HiveMind/agents/deadline"
+	"github.com/suissa/HiveMind/agents/errs"
+	"github.com/suissa/HiveMind/agents/i18n"
+	"github.com/suissa/HiveMind/agents/memory"
+	"github.com/suissa/HiveMind/agents/presence"
+	"github.com/suissa/HiveMind/agents/progress"
+	"github.com/suissa/HiveMind/agents/recap"
+	"github.com/suissa/HiveMind/agents/rollout"
+	"github.com/suissa/HiveMind/agents/simulation"
+	"github.com/suissa/HiveMind/agents/timing"
 )
 
 // MarketingProject representa um projeto de marketing
@@ -31,6 +46,17 @@ type MarketingCrew struct {
 	project    *MarketingProject
 	startTime  time.Time
 	taskStatus map[string]string
+	outputs    map[string]string
+	middleware []TaskMiddleware
+
+	// progress guarda o último andamento informado pelas tarefas em execução
+	progress   map[string]progress.Update
+	progressMu sync.Mutex
+	presence   *presence.Monitor
+
+	contributions []Contribution
+	rollouts      map[string]*Rollout
+	recap         *recap.Writer // Resumo dos workflows concluídos (SetRecap)
 }
 
 // NewMarketingCrew cria uma nova equipe de marketing
@@ -40,6 +66,9 @@ func NewMarketingCrew(memManager memory.MemoryManager) *MarketingCrew {
 		memManager: memManager,
 		emitter:    NewEventEmitter(),
 		taskStatus: make(map[string]string),
+		outputs:    make(map[string]string),
+		progress:   make(map[string]progress.Update),
+		rollouts:   make(map[string]*Rollout),
 	}
 }
 
@@ -59,6 +88,24 @@ func (c *MarketingCrew) AddAgent(agent *CognitiveAgent) {
 	})
 }
 
+// StartRollout implanta uma nova versão de um agente da equipe ao lado da atual (ParentID
+// do green), dividindo as tarefas do agente entre as duas até a promoção ou reversão
+func (c *MarketingCrew) StartRollout(green *CognitiveAgent, config rollout.Config) (*Rollout, error) {
+	blue := c.findAgent(green.ParentID)
+	if blue == nil {
+		return nil, fmt.Errorf("agente %s não pertence à equipe", green.ParentID)
+	}
+
+	r := NewRollout(blue, green, config, c.emitter)
+	c.rollouts[blue.GetID()] = r
+	return r, nil
+}
+
+// Use adiciona middlewares à execução das tarefas da equipe; o primeiro registrado é o mais externo
+func (c *MarketingCrew) Use(middleware ...TaskMiddleware) {
+	c.middleware = append(c.middleware, middleware...)
+}
+
 // OnEvent registra um listener para um tipo específico de evento
 func (c *MarketingCrew) OnEvent(eventType EventType, listener EventListener) {
 	c.emitter.On(eventType, listener)
@@ -69,17 +116,94 @@ func (c *MarketingCrew) OnAnyEvent(listener EventListener) {
 	c.emitter.OnAny(listener)
 }
 
+// WatchPresence acompanha os heartbeats dos agentes da equipe: as mudanças de situação viram
+// eventos EventAgentAction e as tarefas de um agente indisponível passam a outro agente
+// disponível com o mesmo papel
+func (c *MarketingCrew) WatchPresence(monitor *presence.Monitor) {
+	c.presence = monitor
+	monitor.OnChange(func(status presence.Status) {
+		if agent := c.findAgent(status.AgentID); agent != nil {
+			c.emitter.Emit(presenceEvent("marketing_crew", agent, status))
+		}
+	})
+}
+
+// reroute retorna o agente que executa a tarefa designada a agent: o próprio, se estiver
+// disponível, ou outro agente disponível com o mesmo papel
+func (c *MarketingCrew) reroute(task TaskConfig, agent *CognitiveAgent) *CognitiveAgent {
+	if c.presence == nil || c.presence.Available(agent.GetID()) {
+		return agent
+	}
+	for _, candidate := range c.agents {
+		if candidate != agent && candidate.GetRole() == agent.GetRole() && c.presence.Available(candidate.GetID()) {
+			c.emitter.Emit(Event{
+				Type:      EventTaskUpdate,
+				Timestamp: time.Now(),
+				Source:    "marketing_crew",
+				Data: map[string]interface{}{
+					"action":      "task_rerouted",
+					"task_id":     task.ID,
+					"task_name":   task.Name,
+					"from":        agent.GetID(),
+					"assigned_to": candidate.GetID(),
+				},
+			})
+			return candidate
+		}
+	}
+	// Sem alternativa, a tarefa fica com o agente designado
+	return agent
+}
+
+// Status do workflow
+const (
+	WorkflowCompleted = "completed"
+	WorkflowTimeout   = "timeout" // Alguma tarefa excedeu o prazo; os resultados são parciais
+)
+
 // WorkflowResults contém os resultados do workflow
 type WorkflowResults struct {
-	Strategy string
-	Campaign string
-	Copy     string
+	Strategy    string
+	Campaign    string
+	Copy        string
+	TaskOutputs map[string]string // Respostas do LLM por tarefa (parciais nas que excederam o prazo)
+
+	Status   string   // WorkflowCompleted ou WorkflowTimeout
+	TimedOut []string // Tarefas que excederam o prazo
+	Skipped  []string // Tarefas não executadas porque dependem de uma tarefa não concluída
+
+	// Timing detalha o tempo de cada agente em LLM, ferramentas, memória e espera na fila
+	Timing *timing.Breakdown
+
+	// Contributions registra os agentes que contribuíram para o resultado, usado em Reinforce
+	Contributions []Contribution
 }
 
 // ExecuteWorkflow executa o workflow do projeto
 func (c *MarketingCrew) ExecuteWorkflow(project *MarketingProject) (*WorkflowResults, error) {
+	return c.ExecuteWorkflowContext(context.Background(), project)
+}
+
+// ExecuteWorkflowContext executa o workflow do projeto com o contexto informado.
+// Com uma sessão de simulation.WithSession no contexto o workflow roda em modo dry-run.
+// Uma tarefa que excede o prazo (TaskConfig.Deadline) não interrompe o workflow: as tarefas
+// que dependem dela são ignoradas e os resultados parciais são retornados com Status
+// WorkflowTimeout e um erro ErrTimeout.
+func (c *MarketingCrew) ExecuteWorkflowContext(ctx context.Context, project *MarketingProject) (results *WorkflowResults, err error) {
 	c.project = project
 	c.startTime = time.Now()
+	c.contributions = nil
+	ctx = memory.WithProvenance(ctx, memory.Provenance{WorkflowID: project.Name})
+	recorder := timing.NewRecorder()
+	ctx = timing.WithRecorder(ctx, recorder)
+
+	// As anotações intermediárias dos agentes (CognitiveAgent.Note) ficam no scratchpad desta
+	// execução e são descartadas ao final, mesmo se o workflow falhar ou for cancelado
+	if provider, ok := c.memManager.(memory.ScratchpadProvider); ok {
+		scratchpad := provider.Scratchpad(fmt.Sprintf("%s:%d", project.Name, c.startTime.UnixNano()))
+		ctx = memory.WithScratchpad(ctx, scratchpad)
+		defer purgeScratchpad(context.WithoutCancel(ctx), scratchpad)
+	}
 
 	c.emitter.Emit(Event{
 		Type:      EventWorkflowUpdate,
@@ -89,8 +213,16 @@ func (c *MarketingCrew) ExecuteWorkflow(project *MarketingProject) (*WorkflowRes
 			"action":    "workflow_start",
 			"project":   project.Name,
 			"objective": project.Objective,
+			"dry_run":   simulation.IsDryRun(ctx),
 		},
 	})
+	// Um workflow interrompido por erro ou cancelamento termina com workflow_failed; o que
+	// excede prazos termina com resultados parciais
+	defer func() {
+		if err != nil && results == nil {
+			c.emitWorkflowFailed(project, err)
+		}
+	}()
 
 	// Inicializa o status das tarefas
 	for _, task := range project.Tasks {
@@ -99,14 +231,43 @@ func (c *MarketingCrew) ExecuteWorkflow(project *MarketingProject) (*WorkflowRes
 
 	// TODO: Implementar a lógica real do workflow
 	// Por enquanto, simula o processamento das tarefas
+	run := ChainTaskMiddleware(c.runTask, c.middleware...)
+	var timedOut, skipped []string
+	unfinished := make(map[string]bool) // Tarefas que excederam o prazo ou foram ignoradas
 	for _, task := range project.Tasks {
-		c.processTask(task)
+		// Um workflow cancelado não inicia novas tarefas
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if dependency := firstUnfinished(task, unfinished); dependency != "" {
+			c.skipTask(task, dependency)
+			unfinished[task.ID] = true
+			skipped = append(skipped, task.ID)
+			continue
+		}
+		if err := c.processTask(ctx, task, run); err != nil {
+			if c.taskStatus[task.ID] != "timeout" {
+				return nil, err
+			}
+			unfinished[task.ID] = true
+			timedOut = append(timedOut, task.ID)
+		}
 	}
 
-	results := &WorkflowResults{
-		Strategy: "Estratégia de marketing digital focada em sustentabilidade",
-		Campaign: "Campanha 'Verde é o Novo Luxo'",
-		Copy:     "Descubra como luxo e sustentabilidade podem andar juntos",
+	// O relatório é gerado no idioma do contexto (i18n.WithLocale) ou no padrão
+	results = &WorkflowResults{
+		Strategy:      i18n.T(ctx, "report.marketing.strategy"),
+		Campaign:      i18n.T(ctx, "report.marketing.campaign"),
+		Copy:          i18n.T(ctx, "report.marketing.copy"),
+		TaskOutputs:   c.outputs,
+		Status:        WorkflowCompleted,
+		TimedOut:      timedOut,
+		Skipped:       skipped,
+		Timing:        recorder.Breakdown(),
+		Contributions: c.contributions,
+	}
+	if len(timedOut) > 0 {
+		results.Status = WorkflowTimeout
 	}
 
 	c.emitter.Emit(Event{
@@ -117,15 +278,75 @@ func (c *MarketingCrew) ExecuteWorkflow(project *MarketingProject) (*WorkflowRes
 			"action":   "workflow_complete",
 			"project":  project.Name,
 			"results":  results,
+			"status":   results.Status,
 			"duration": time.Since(c.startTime).String(),
+			"dry_run":  simulation.IsDryRun(ctx),
 		},
 	})
 
+	if !simulation.IsDryRun(ctx) {
+		c.writeRecap(ctx, WorkflowTranscript(project, c.outputs))
+	}
+
+	if len(timedOut) > 0 {
+		return results, errs.New(errs.ErrTimeout, "agents.ExecuteWorkflow", "tarefas %v excederam o prazo; %d tarefas ignoradas", timedOut, len(skipped))
+	}
 	return results, nil
 }
 
+// emitWorkflowFailed emite o término do workflow interrompido por erro
+func (c *MarketingCrew) emitWorkflowFailed(project *MarketingProject, err error) {
+	c.emitter.Emit(Event{
+		Type:      EventWorkflowUpdate,
+		Timestamp: time.Now(),
+		Source:    "marketing_crew",
+		Data: map[string]interface{}{
+			"action":   "workflow_failed",
+			"project":  project.Name,
+			"error":    err.Error(),
+			"duration": time.Since(c.startTime).String(),
+		},
+	})
+}
+
+// firstUnfinished retorna a primeira dependência da tarefa que não foi concluída
+func firstUnfinished(task TaskConfig, unfinished map[string]bool) string {
+	for _, dependency := range task.Dependencies {
+		if unfinished[dependency] {
+			return dependency
+		}
+	}
+	return ""
+}
+
+// skipTask marca como ignorada uma tarefa cuja dependência não foi concluída
+func (c *MarketingCrew) skipTask(task TaskConfig, dependency string) {
+	c.taskStatus[task.ID] = "skipped"
+	c.emitter.Emit(Event{
+		Type:      EventTaskUpdate,
+		Timestamp: time.Now(),
+		Source:    "marketing_crew",
+		Data: map[string]interface{}{
+			"action":     "task_skipped",
+			"task_id":    task.ID,
+			"task_name":  task.Name,
+			"dependency": dependency,
+		},
+	})
+}
+
+// purgeScratchpad descarta as anotações não promovidas do workflow. Uma falha apenas é
+// registrada no log: a expiração do scratchpad remove as anotações restantes.
+func purgeScratchpad(ctx context.Context, scratchpad *memory.Scratchpad) {
+	if _, err := scratchpad.Purge(ctx); err != nil {
+		log.Printf("⚠️ Erro ao descartar o scratchpad do workflow %s: %v", scratchpad.WorkflowID(), err)
+	}
+}
+
 // processTask processa uma tarefa do projeto
-func (c *MarketingCrew) processTask(task TaskConfig) {
+func (c *MarketingCrew) processTask(ctx context.Context, task TaskConfig, run TaskHandler) error {
+	// A espera nos middlewares (limites de taxa, políticas) conta como espera na fila
+	ctx = timing.WithQueued(ctx, time.Now())
 	c.emitter.Emit(Event{
 		Type:      EventTaskUpdate,
 		Timestamp: time.Now(),
@@ -138,8 +359,49 @@ func (c *MarketingCrew) processTask(task TaskConfig) {
 		},
 	})
 
-	// Simula o processamento da tarefa
-	time.Sleep(1 * time.Second)
+	// Um prazo relativo ("10m") conta a partir do início da tarefa; os handlers recebem o
+	// instante absoluto
+	at, err := deadline.Parse(task.Deadline, time.Now())
+	if err != nil {
+		c.failTask(task, err)
+		return fmt.Errorf("erro na tarefa %s: %w", task.ID, err)
+	}
+	if !at.IsZero() {
+		task.Deadline = at.Format(time.RFC3339Nano)
+	}
+
+	// O andamento informado pela tarefa e por suas ferramentas (progress.Report) vira eventos
+	// task_progress e aparece em GetProjectStatus enquanto a tarefa executa
+	defer c.clearProgress(task.ID)
+	output, err := run(progress.WithReporter(ctx, c.progressReporter(task)), task)
+	if err != nil && !at.IsZero() && ctx.Err() == nil && errors.Is(err, errs.ErrTimeout) {
+		// O resultado parcial fica disponível no lugar da resposta da tarefa
+		c.taskStatus[task.ID] = "timeout"
+		if output != "" {
+			c.outputs[task.ID] = output
+		}
+		c.emitter.Emit(Event{
+			Type:      EventTaskUpdate,
+			Timestamp: time.Now(),
+			Source:    "marketing_crew",
+			Data: map[string]interface{}{
+				"action":      "task_timeout",
+				"task_id":     task.ID,
+				"task_name":   task.Name,
+				"assigned_to": task.AssignedTo,
+				"deadline":    at.Format(time.RFC3339),
+				"partial":     output != "",
+			},
+		})
+		return fmt.Errorf("erro na tarefa %s: %w", task.ID, err)
+	}
+	if err != nil {
+		c.failTask(task, err)
+		return fmt.Errorf("erro na tarefa %s: %w", task.ID, err)
+	}
+	if output != "" {
+		c.outputs[task.ID] = output
+	}
 	c.taskStatus[task.ID] = "completed"
 
 	c.emitter.Emit(Event{
@@ -153,6 +415,163 @@ func (c *MarketingCrew) processTask(task TaskConfig) {
 			"assigned_to": task.AssignedTo,
 		},
 	})
+
+	return nil
+}
+
+// failTask marca a tarefa como falha e emite o evento task_failed
+func (c *MarketingCrew) failTask(task TaskConfig, err error) {
+	c.taskStatus[task.ID] = "failed"
+	c.emitter.Emit(Event{
+		Type:      EventTaskUpdate,
+		Timestamp: time.Now(),
+		Source:    "marketing_crew",
+		Data: map[string]interface{}{
+			"action":      "task_failed",
+			"task_id":     task.ID,
+			"task_name":   task.Name,
+			"assigned_to": task.AssignedTo,
+			"error":       err.Error(),
+		},
+	})
+}
+
+// progressReporter registra o andamento da tarefa e o emite como evento task_progress
+func (c *MarketingCrew) progressReporter(task TaskConfig) progress.Reporter {
+	return progress.ReporterFunc(func(percent float64, message string) {
+		c.progressMu.Lock()
+		c.progress[task.ID] = progress.Update{Percent: percent, Message: message, Timestamp: time.Now()}
+		c.progressMu.Unlock()
+
+		c.emitter.Emit(Event{
+			Type:      EventTaskUpdate,
+			Timestamp: time.Now(),
+			Source:    "marketing_crew",
+			Data: map[string]interface{}{
+				"action":      "task_progress",
+				"task_id":     task.ID,
+				"task_name":   task.Name,
+				"assigned_to": task.AssignedTo,
+				"percent":     percent,
+				"message":     message,
+			},
+		})
+	})
+}
+
+// clearProgress descarta o andamento de uma tarefa que terminou
+func (c *MarketingCrew) clearProgress(taskID string) {
+	c.progressMu.Lock()
+	defer c.progressMu.Unlock()
+	delete(c.progress, taskID)
+}
+
+// runTask executa a tarefa com o LLM do agente designado. Sem LLM configurado
+// o processamento é apenas simulado.
+func (c *MarketingCrew) runTask(ctx context.Context, task TaskConfig) (string, error) {
+	at, err := deadline.Parse(task.Deadline, time.Now())
+	if err != nil {
+		return "", err
+	}
+	agent := c.findAgent(task.AssignedTo)
+	if agent != nil {
+		agent = c.reroute(task, agent)
+	}
+	if agent == nil || simulation.Provider(ctx, agent.llm) == nil {
+		// Sem espera no modo dry-run, mantendo a simulação determinística e rápida
+		if simulation.IsDryRun(ctx) {
+			return "", nil
+		}
+		taskCtx, cancel := deadline.Context(ctx, at)
+		defer cancel()
+		select {
+		case <-taskCtx.Done():
+			if deadline.Expired(ctx, taskCtx) {
+				return "", deadline.Error("agents.runTask", task.ID, at, taskCtx.Err())
+			}
+			return "", ctx.Err()
+		case <-time.After(1 * time.Second):
+			return "", nil
+		}
+	}
+
+	prompt := fmt.Sprintf("Projeto: %s\nObjetivo: %s\nTarefa: %s\n%s",
+		c.project.Name, c.project.Objective, task.Name, task.Description)
+	run := NewTask(task.ID, "marketing", prompt, nil)
+	run.Overrides = task.Overrides
+	run.ExpectedOutput = task.ExpectedOutput
+	run.Deadline = at
+	if task.OutputSchema != "" {
+		run.OutputSchema = json.RawMessage(task.OutputSchema)
+	}
+	var output string
+	if r, ok := c.rollouts[agent.GetID()]; ok {
+		output, err = r.Run(ctx, run)
+	} else {
+		output, err = agent.Run(ctx, run)
+	}
+	if contribution, ok := contributionOf(run); ok && err == nil {
+		c.contributions = append(c.contributions, contribution)
+	}
+	return output, err
+}
+
+// Reinforce propaga o resultado final do workflow (aprovação humana ou métrica downstream)
+// aos agentes que contribuíram para ele
+func (c *MarketingCrew) Reinforce(ctx context.Context, results *WorkflowResults, outcome Outcome) error {
+	var failed []error
+	for _, contribution := range results.Contributions {
+		agent := c.findAgent(contribution.AgentID)
+		if agent == nil {
+			agent = c.findRolloutAgent(contribution.AgentID)
+		}
+		if agent == nil {
+			continue
+		}
+		if err := agent.Reinforce(ctx, contribution, outcome); err != nil {
+			failed = append(failed, fmt.Errorf("agente %s: %w", contribution.AgentID, err))
+		}
+
+		c.emitter.Emit(Event{
+			Type:      EventAgentAction,
+			Timestamp: time.Now(),
+			Source:    "marketing_crew",
+			Data: map[string]interface{}{
+				"action":         "reinforce",
+				"agent_id":       contribution.AgentID,
+				"task_id":        contribution.TaskID,
+				"prompt_version": contribution.PromptVersion,
+				"reward":         outcome.Reward,
+				"source":         outcome.Source,
+				"reason":         outcome.Reason,
+			},
+		})
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("erros ao propagar o resultado do workflow: %v", failed)
+	}
+	return nil
+}
+
+// findAgent retorna o agente da equipe com o ID informado
+func (c *MarketingCrew) findAgent(id string) *CognitiveAgent {
+	for _, agent := range c.agents {
+		if agent.GetID() == id {
+			return agent
+		}
+	}
+	return nil
+}
+
+// findRolloutAgent retorna a nova versão em rollout com o ID informado
+func (c *MarketingCrew) findRolloutAgent(id string) *CognitiveAgent {
+	for _, r := range c.rollouts {
+		if r.Green.GetID() == id {
+			return r.Green
+		}
+	}
+	return nil
 }
 
 // GetProjectStatus retorna o status atual do projeto
@@ -168,17 +587,27 @@ func (c *MarketingCrew) GetProjectStatus() *ProjectStatus {
 		}
 	}
 
+	// As tarefas em execução contam com o andamento que informaram
+	c.progressMu.Lock()
+	taskProgress := make(map[string]progress.Update, len(c.progress))
+	done := float64(completedTasks)
+	for id, update := range c.progress {
+		taskProgress[id] = update
+		done += update.Percent / 100
+	}
+	c.progressMu.Unlock()
+
 	totalTasks := len(c.project.Tasks)
-	progress := float64(completedTasks) / float64(totalTasks) * 100
 	elapsed := time.Since(c.startTime)
 	remaining := c.project.Duration - elapsed
 
 	status := &ProjectStatus{
-		Progress:       progress,
+		Progress:       done / float64(totalTasks) * 100,
 		CompletedTasks: completedTasks,
 		TotalTasks:     totalTasks,
 		ElapsedTime:    elapsed,
 		RemainingTime:  remaining,
+		TaskProgress:   taskProgress,
 	}
 
 	c.emitter.Emit(Event{
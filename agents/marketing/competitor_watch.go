@@ -0,0 +1,98 @@
+package marketing
+
+import (
+	"context"
+	"sort"
+
+	"github.com/suissa/HiveMind/agents"
+	"github.com/suissa/HiveMind/agents/competitor"
+	"github.com/suissa/HiveMind/agents/cron"
+	"github.com/suissa/HiveMind/agents/knowledge"
+	"github.com/suissa/HiveMind/agents/memory"
+)
+
+// CompetitorWatchID é o ID do agente de monitoramento de concorrentes
+const CompetitorWatchID = "competitor-watch"
+
+// SchemaCompetitorUpdate é o esquema das mudanças dos concorrentes memorizadas pelo monitor
+// (competitor.Change)
+const SchemaCompetitorUpdate = "marketing.competitor_update/v1"
+
+// competitorUpdatesLimit é quantas mudanças recentes o estrategista considera
+const competitorUpdatesLimit = 5
+
+// CompetitorWatchAgent é o agente pré-configurado que acompanha as páginas e os feeds dos
+// concorrentes e memoriza as mudanças relevantes, que o MarketingStrategyTask usa na
+// estratégia seguinte
+type CompetitorWatchAgent struct {
+	*agents.CognitiveAgent
+	watcher *competitor.Watcher
+}
+
+// NewCompetitorWatchAgent cria o agente sobre o monitor; as verificações são agendadas com
+// Job (ou chamando Watcher().Check)
+func NewCompetitorWatchAgent(memManager memory.MemoryManager, watcher *competitor.Watcher) *CompetitorWatchAgent {
+	agent := agents.NewCognitiveAgent(
+		CompetitorWatchID,
+		"Monitor de Concorrentes",
+		"Acompanha as páginas e os feeds dos concorrentes",
+		1, // maxRounds
+		"gpt-4",
+		"monitor",
+		"Detectar mudanças relevantes nos concorrentes",
+		memManager,
+	)
+	w := &CompetitorWatchAgent{CognitiveAgent: agent, watcher: watcher}
+	watcher.OnChange(w.memorize)
+	return w
+}
+
+// Watcher retorna o monitor das fontes
+func (w *CompetitorWatchAgent) Watcher() *competitor.Watcher {
+	return w.watcher
+}
+
+// Job retorna o job periódico das verificações, para Runtime.Schedule; as execuções
+// sobrepostas são descartadas
+func (w *CompetitorWatchAgent) Job(name, schedule string) cron.Job {
+	return cron.Job{Name: name, Schedule: schedule, Overlap: cron.OverlapSkip, Run: w.watcher.Run}
+}
+
+// memorize grava a mudança; as que citam palavras-chave são mais importantes
+func (w *CompetitorWatchAgent) memorize(ctx context.Context, change competitor.Change) error {
+	importance := 0.6 + 0.1*float64(len(change.Keywords))
+	if importance > 0.9 {
+		importance = 0.9
+	}
+	return w.MemorizeValue(ctx, SchemaCompetitorUpdate, change, importance, []string{"competitor", change.Competitor}, true)
+}
+
+// competitorUpdates retorna as mudanças mais recentes memorizadas pelo monitor de
+// concorrentes e as registra no grafo de conhecimento, ligadas ao projeto
+func (c *MarketingPostsCrew) competitorUpdates() ([]competitor.Change, error) {
+	memories, err := c.memoryManager.SearchMemories(c.ctx, CompetitorWatchID, []string{memory.SchemaTag(SchemaCompetitorUpdate)})
+	if err != nil {
+		return nil, err
+	}
+	var changes []competitor.Change
+	for _, m := range memories {
+		var change competitor.Change
+		if err := memory.Decode(m, SchemaCompetitorUpdate, &change); err != nil {
+			continue
+		}
+		changes = append(changes, change)
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].DetectedAt.After(changes[j].DetectedAt) })
+	if len(changes) > competitorUpdatesLimit {
+		changes = changes[:competitorUpdatesLimit]
+	}
+
+	// Da mais antiga para a mais recente, para que a última mudança de cada concorrente prevaleça
+	for i := len(changes) - 1; i >= 0; i-- {
+		id := c.knowledge.Upsert(knowledge.KindCompetitor, changes[i].Competitor, map[string]string{"last_update": changes[i].Summary()})
+		if c.project != "" {
+			c.knowledge.Relate(c.project, knowledge.RelCompetesWith, id)
+		}
+	}
+	return changes, nil
+}
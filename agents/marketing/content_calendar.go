@@ -0,0 +1,96 @@
+package marketing
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/suissa/HiveMind/agents/calendar"
+	"github.com/suissa/HiveMind/agents/knowledge"
+)
+
+// SchemaCalendar é o esquema do calendário de conteúdo memorizado pelo criador de conteúdo
+const SchemaCalendar = "marketing.calendar/v1"
+
+// calendarTacticVariants é quantas táticas da estratégia viram variantes do texto
+const calendarTacticVariants = 2
+
+// CalendarRecord é o calendário de conteúdo memorizado pelo criador de conteúdo
+type CalendarRecord struct {
+	Project   string             `json:"project,omitempty"`
+	Campaign  string             `json:"campaign,omitempty"`
+	Calendar  *calendar.Calendar `json:"calendar"`
+	Timestamp time.Time          `json:"timestamp"`
+}
+
+// ContentCalendarTask distribui o texto da campanha pelos canais da estratégia, em variantes,
+// e memoriza o calendário. Os campos vazios do plano são completados: os canais vêm da
+// estratégia no grafo de conhecimento e o responsável padrão é o criador de conteúdo.
+func (c *MarketingPostsCrew) ContentCalendarTask(plan calendar.Plan) (*calendar.Calendar, error) {
+	if err := c.restoreCopy(); err != nil {
+		return nil, err
+	}
+	if c.copy == nil {
+		return nil, fmt.Errorf("calendário de conteúdo exige o texto da campanha")
+	}
+	project, _ := c.knowledge.Get(c.project)
+	campaign, _ := c.knowledge.Get(c.campaign)
+	if len(plan.Channels) == 0 {
+		plan.Channels = knowledge.Names(c.knowledge.Related(c.strategy, knowledge.RelUses, knowledge.KindChannel))
+	}
+	if len(plan.Channels) == 0 {
+		plan.Channels = knowledge.Names(c.knowledge.Related(c.campaign, knowledge.RelUses, knowledge.KindChannel))
+	}
+	if plan.DefaultOwner == "" {
+		plan.DefaultOwner = c.creativeContentCreator.GetID()
+	}
+
+	name := campaign.Name
+	if name == "" {
+		name = c.copy.Title
+	}
+	tactics := knowledge.Names(c.knowledge.Related(c.strategy, knowledge.RelUses, knowledge.KindTactic))
+	contentCalendar, err := calendar.Generate(name, campaign.Name, copyVariants(c.copy, tactics), plan)
+	if err != nil {
+		return nil, err
+	}
+
+	// Memoriza o calendário
+	record := CalendarRecord{Project: project.Name, Campaign: campaign.Name, Calendar: contentCalendar, Timestamp: time.Now()}
+	err = c.creativeContentCreator.MemorizeValue(c.ctx, SchemaCalendar, record, 0.7, []string{"calendar", "content"}, true)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao memorizar calendário: %v", err)
+	}
+
+	return contentCalendar, nil
+}
+
+// copyVariants deriva do texto as variantes do calendário: o texto completo, o título sozinho
+// (para os canais de textos curtos) e uma chamada por tática da estratégia
+func copyVariants(copy *Copy, tactics []string) []string {
+	variants := []string{copy.Title + "\n\n" + copy.Body, copy.Title}
+	for i, tactic := range tactics {
+		if i == calendarTacticVariants {
+			break
+		}
+		variants = append(variants, fmt.Sprintf("Você já usa %s? %s", tactic, copy.Title))
+	}
+	return variants
+}
+
+// restoreCopy recupera da memória do criador de conteúdo o último texto, quando a criação do
+// texto não rodou nesta instância da equipe
+func (c *MarketingPostsCrew) restoreCopy() error {
+	if err := c.restoreCampaign(); err != nil || c.copy != nil {
+		return err
+	}
+	var record CopyRecord
+	found, err := c.creativeContentCreator.RecallValue(c.ctx, "", SchemaCopy, &record)
+	if err != nil {
+		return fmt.Errorf("erro ao recuperar memórias: %v", err)
+	}
+	if found {
+		c.copy = record.Copy
+		c.review = record.Review
+	}
+	return nil
+}
@@ -5,10 +5,16 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"strings"
 	"time"
 
-	"HiveMind/agents"
-	"HiveMind/agents/memory"
+	"github.com/suissa/HiveMind/agents"
+	"github.com/suissa/HiveMind/agents/brandvoice"
+	"github.com/suissa/HiveMind/agents/calendar"
+	"github.com/suissa/HiveMind/agents/knowledge"
+	"github.com/suissa/HiveMind/agents/llm"
+	"github.com/suissa/HiveMind/agents/memory"
+	"github.com/suissa/HiveMind/agents/prompt"
 )
 
 // MarketStrategy representa uma estratégia de marketing
@@ -17,6 +23,9 @@ type MarketStrategy struct {
 	Tactics  []string `json:"tactics"`  // Lista de táticas
 	Channels []string `json:"channels"` // Lista de canais
 	KPIs     []string `json:"kpis"`     // Lista de KPIs
+
+	// Mudanças recentes dos concorrentes consideradas (CompetitorWatchAgent)
+	CompetitorUpdates []string `json:"competitor_updates,omitempty"`
 }
 
 // CampaignIdea representa uma ideia de campanha
@@ -33,6 +42,41 @@ type Copy struct {
 	Body  string `json:"body"`  // Corpo do texto
 }
 
+// Esquemas das memórias trocadas entre os agentes da equipe (veja memory.Content)
+const (
+	SchemaProject  = "marketing.project/v1"
+	SchemaStrategy = "marketing.strategy/v1"
+	SchemaCampaign = "marketing.campaign/v1"
+	SchemaCopy     = "marketing.copy/v1"
+)
+
+// StrategyRecord é a estratégia memorizada pelo estrategista
+type StrategyRecord struct {
+	Project   string          `json:"project,omitempty"`
+	Objective string          `json:"objective,omitempty"`
+	Strategy  *MarketStrategy `json:"strategy"`
+	Timestamp time.Time       `json:"timestamp"`
+}
+
+// CampaignRecord é a ideia de campanha memorizada pelo criador de conteúdo
+type CampaignRecord struct {
+	Project   string        `json:"project,omitempty"`
+	Strategy  string        `json:"strategy,omitempty"` // Nome da estratégia de origem
+	Objective string        `json:"objective,omitempty"`
+	Idea      *CampaignIdea `json:"idea"`
+	Timestamp time.Time     `json:"timestamp"`
+}
+
+// CopyRecord é o texto publicitário memorizado pelo criador de conteúdo
+type CopyRecord struct {
+	Project   string             `json:"project,omitempty"`
+	Audience  string             `json:"audience,omitempty"`
+	Objective string             `json:"objective,omitempty"`
+	Copy      *Copy              `json:"copy"`
+	Review    *brandvoice.Review `json:"brand_voice,omitempty"` // Revisão da voz da marca
+	Timestamp time.Time          `json:"timestamp"`
+}
+
 // MarketingPostsCrew gerencia a equipe de marketing
 type MarketingPostsCrew struct {
 	leadMarketAnalyst        *agents.CognitiveAgent
@@ -40,6 +84,17 @@ type MarketingPostsCrew struct {
 	creativeContentCreator   *agents.CognitiveAgent
 	memoryManager            memory.MemoryManager
 	ctx                      context.Context
+
+	// Grafo das entidades das campanhas e as entidades do workflow em andamento
+	knowledge *knowledge.Graph
+	project   string
+	strategy  string
+	campaign  string
+	copy      *Copy
+
+	// Revisor da voz da marca aplicado aos textos (nil sem diretrizes)
+	brandVoice *brandvoice.Reviewer
+	review     *brandvoice.Review
 }
 
 // NewMarketingPostsCrew cria uma nova equipe de marketing
@@ -85,6 +140,7 @@ func NewMarketingPostsCrew(ctx context.Context, memManager memory.MemoryManager)
 	crew := &MarketingPostsCrew{
 		memoryManager: memManager,
 		ctx:           ctx,
+		knowledge:     knowledge.New(),
 	}
 
 	// Cria o analista líder de mercado
@@ -138,6 +194,40 @@ func NewMarketingPostsCrew(ctx context.Context, memManager memory.MemoryManager)
 	return crew
 }
 
+// DefaultBrandVoiceFile é o arquivo convencional das diretrizes da voz da marca
+// (LoadBrandVoice)
+const DefaultBrandVoiceFile = "config/brand_voice.yaml"
+
+// LoadBrandVoice carrega as diretrizes da voz da marca do arquivo e as ativa na equipe
+// (SetBrandVoice). A equipe não carrega nenhum arquivo por conta própria.
+func (c *MarketingPostsCrew) LoadBrandVoice(filename string) error {
+	voice, err := brandvoice.Load(filename)
+	if err != nil {
+		return err
+	}
+	c.SetBrandVoice(voice)
+	return nil
+}
+
+// SetLLM define o provedor de LLM dos agentes da equipe; com ele, as revisões da voz da marca
+// são reescritas pelo criador de conteúdo
+func (c *MarketingPostsCrew) SetLLM(provider llm.Provider) {
+	c.leadMarketAnalyst.SetLLM(provider)
+	c.chiefMarketingStrategist.SetLLM(provider)
+	c.creativeContentCreator.SetLLM(provider)
+}
+
+// SetBrandVoice define a voz da marca: as diretrizes entram no prompt do criador de conteúdo
+// e um revisor verifica os textos da equipe, pedindo revisões enquanto houver violações.
+// Nil desativa.
+func (c *MarketingPostsCrew) SetBrandVoice(voice *brandvoice.Voice) {
+	c.creativeContentCreator.SetBrandVoice(voice)
+	c.brandVoice = nil
+	if voice != nil {
+		c.brandVoice = brandvoice.NewReviewer(voice)
+	}
+}
+
 // ResearchTask executa a tarefa de pesquisa
 func (c *MarketingPostsCrew) ResearchTask(topic string) error {
 	research := map[string]interface{}{
@@ -154,45 +244,34 @@ func (c *MarketingPostsCrew) ResearchTask(topic string) error {
 	return nil
 }
 
-// ProjectUnderstandingTask executa a tarefa de compreensão do projeto
+// ProjectUnderstandingTask executa a tarefa de compreensão do projeto: memoriza os detalhes
+// e registra no grafo de conhecimento o projeto e os produtos, públicos, canais e
+// concorrentes citados
 func (c *MarketingPostsCrew) ProjectUnderstandingTask(projectDetails map[string]interface{}) error {
 	// Memoriza os detalhes do projeto
-	err := c.chiefMarketingStrategist.Memorize(c.ctx, projectDetails, 0.9, []string{"project", "understanding"}, true)
+	err := c.chiefMarketingStrategist.MemorizeValue(c.ctx, SchemaProject, projectDetails, 0.9, []string{"project", "understanding"}, true)
 	if err != nil {
 		return fmt.Errorf("erro ao memorizar detalhes do projeto: %v", err)
 	}
 
+	c.project = knowledge.Extract(c.knowledge, projectDetails)
 	return nil
 }
 
-// MarketingStrategyTask desenvolve a estratégia de marketing
+// MarketingStrategyTask desenvolve a estratégia de marketing a partir do projeto registrado
+// no grafo de conhecimento
 func (c *MarketingPostsCrew) MarketingStrategyTask() (*MarketStrategy, error) {
-	// Busca memórias relacionadas ao projeto
-	memories, err := c.chiefMarketingStrategist.Remember(c.ctx, []string{"project", "research"})
-	if err != nil {
-		return nil, fmt.Errorf("erro ao recuperar memórias: %v", err)
+	if err := c.restoreProject(); err != nil {
+		return nil, err
 	}
-
-	// Analisa as memórias para criar a estratégia
-	var channels []string
+	project, _ := c.knowledge.Get(c.project)
+	channels := knowledge.Names(c.knowledge.Related(c.project, knowledge.RelUses, knowledge.KindChannel))
+	objective := project.Attributes["objective"]
 	var tactics []string
 	var kpis []string
-	var projectName string
-	var objective string
+	projectName := project.Name
 
-	for _, mem := range memories {
-		if channels, ok := mem.Content["channels"].([]string); ok {
-			channels = append(channels, channels...)
-		}
-		if objective, ok := mem.Content["objective"].(string); ok {
-			objective = objective
-		}
-		if name, ok := mem.Content["name"].(string); ok {
-			projectName = name
-		}
-	}
-
-	// Se não encontrou dados nas memórias, usa valores padrão
+	// Se o projeto não informou os dados, usa valores padrão
 	if len(channels) == 0 {
 		channels = []string{"LinkedIn", "Twitter", "Email"}
 	}
@@ -206,21 +285,27 @@ func (c *MarketingPostsCrew) MarketingStrategyTask() (*MarketStrategy, error) {
 		projectName = "Estratégia de Marketing Digital"
 	}
 
-	// Cria a estratégia baseada nas memórias
+	// As mudanças dos concorrentes vêm da memória do monitor de concorrentes
+	updates, err := c.competitorUpdates()
+	if err != nil {
+		return nil, fmt.Errorf("erro ao recuperar memórias: %v", err)
+	}
+
+	// Cria a estratégia baseada no projeto
 	strategy := &MarketStrategy{
 		Name:     projectName,
 		Tactics:  tactics,
 		Channels: channels,
 		KPIs:     kpis,
 	}
+	for _, update := range updates {
+		strategy.CompetitorUpdates = append(strategy.CompetitorUpdates, update.Summary())
+	}
+	record := StrategyRecord{Project: project.Name, Objective: objective, Strategy: strategy, Timestamp: time.Now()}
+	c.recordStrategy(record)
 
 	// Memoriza a estratégia
-	strategyData := map[string]interface{}{
-		"strategy":  strategy,
-		"timestamp": time.Now(),
-		"objective": objective,
-	}
-	err = c.chiefMarketingStrategist.Memorize(c.ctx, strategyData, 0.9, []string{"strategy", "marketing"}, true)
+	err = c.chiefMarketingStrategist.MemorizeValue(c.ctx, SchemaStrategy, record, 0.9, []string{"strategy", "marketing"}, true)
 	if err != nil {
 		return nil, fmt.Errorf("erro ao memorizar estratégia: %v", err)
 	}
@@ -228,36 +313,30 @@ func (c *MarketingPostsCrew) MarketingStrategyTask() (*MarketStrategy, error) {
 	return strategy, nil
 }
 
-// CampaignIdeaTask desenvolve uma ideia de campanha
+// CampaignIdeaTask desenvolve uma ideia de campanha a partir da estratégia e do público do
+// projeto no grafo de conhecimento
 func (c *MarketingPostsCrew) CampaignIdeaTask() (*CampaignIdea, error) {
-	// Busca memórias relacionadas à estratégia
-	memories, err := c.creativeContentCreator.Remember(c.ctx, []string{"strategy"})
-	if err != nil {
-		return nil, fmt.Errorf("erro ao recuperar memórias: %v", err)
-	}
-
-	// Analisa as memórias para criar a ideia de campanha
 	var audience string
 	var channel string
 	var description string
-	var objective string
 
-	for _, mem := range memories {
-		if strategy, ok := mem.Content["strategy"].(*MarketStrategy); ok {
-			if len(strategy.Channels) > 0 {
-				channel = strategy.Channels[0] // Usa o primeiro canal como principal
-			}
-		}
-		if target, ok := mem.Content["target"].(string); ok {
-			audience = target
-		}
-		if obj, ok := mem.Content["objective"].(string); ok {
-			objective = obj
-			description = fmt.Sprintf("Série de posts interativos focados em %s", objective)
-		}
+	if err := c.restoreStrategy(); err != nil {
+		return nil, err
+	}
+	project, _ := c.knowledge.Get(c.project)
+	objective := project.Attributes["objective"]
+	if audiences := c.knowledge.Related(c.project, knowledge.RelTargets, knowledge.KindAudience); len(audiences) > 0 {
+		audience = audiences[0].Name
+	}
+	// Usa o primeiro canal da estratégia como principal
+	if channels := c.knowledge.Related(c.strategy, knowledge.RelUses, knowledge.KindChannel); len(channels) > 0 {
+		channel = channels[0].Name
+	}
+	if objective != "" {
+		description = fmt.Sprintf("Série de posts interativos focados em %s", objective)
 	}
 
-	// Se não encontrou dados nas memórias, usa valores padrão
+	// Se não encontrou os dados, usa valores padrão
 	if audience == "" {
 		audience = "Profissionais de Marketing Digital"
 	}
@@ -268,21 +347,19 @@ func (c *MarketingPostsCrew) CampaignIdeaTask() (*CampaignIdea, error) {
 		description = "Série de posts interativos focados em educação e engajamento"
 	}
 
-	// Cria a ideia de campanha baseada nas memórias
+	// Cria a ideia de campanha
 	idea := &CampaignIdea{
 		Name:        fmt.Sprintf("Campanha de %s", objective),
 		Description: description,
 		Audience:    audience,
 		Channel:     channel,
 	}
+	strategy, _ := c.knowledge.Get(c.strategy)
+	record := CampaignRecord{Project: project.Name, Strategy: strategy.Name, Objective: objective, Idea: idea, Timestamp: time.Now()}
+	c.recordCampaign(record)
 
 	// Memoriza a ideia
-	ideaData := map[string]interface{}{
-		"idea":      idea,
-		"timestamp": time.Now(),
-		"objective": objective,
-	}
-	err = c.creativeContentCreator.Memorize(c.ctx, ideaData, 0.8, []string{"campaign", "idea"}, true)
+	err := c.creativeContentCreator.MemorizeValue(c.ctx, SchemaCampaign, record, 0.8, []string{"campaign", "idea"}, true)
 	if err != nil {
 		return nil, fmt.Errorf("erro ao memorizar ideia: %v", err)
 	}
@@ -290,32 +367,22 @@ func (c *MarketingPostsCrew) CampaignIdeaTask() (*CampaignIdea, error) {
 	return idea, nil
 }
 
-// CopyCreationTask cria o texto publicitário
+// CopyCreationTask cria o texto publicitário a partir da campanha e da estratégia no grafo
+// de conhecimento
 func (c *MarketingPostsCrew) CopyCreationTask() (*Copy, error) {
-	// Busca memórias relacionadas à campanha e estratégia
-	memories, err := c.creativeContentCreator.Remember(c.ctx, []string{"campaign", "strategy"})
-	if err != nil {
-		return nil, fmt.Errorf("erro ao recuperar memórias: %v", err)
-	}
-
-	// Analisa as memórias para criar o texto
 	var title string
 	var body string
 	var audience string
-	var objective string
-	var tactics []string
 
-	for _, mem := range memories {
-		if idea, ok := mem.Content["idea"].(*CampaignIdea); ok {
-			audience = idea.Audience
-		}
-		if strategy, ok := mem.Content["strategy"].(*MarketStrategy); ok {
-			tactics = strategy.Tactics
-		}
-		if obj, ok := mem.Content["objective"].(string); ok {
-			objective = obj
-		}
+	if err := c.restoreCampaign(); err != nil {
+		return nil, err
 	}
+	project, _ := c.knowledge.Get(c.project)
+	objective := project.Attributes["objective"]
+	if audiences := c.knowledge.Related(c.campaign, knowledge.RelTargets, knowledge.KindAudience); len(audiences) > 0 {
+		audience = audiences[0].Name
+	}
+	tactics := knowledge.Names(c.knowledge.Related(c.strategy, knowledge.RelUses, knowledge.KindTactic))
 
 	// Cria o título baseado no objetivo e público
 	if audience != "" && objective != "" {
@@ -334,27 +401,147 @@ func (c *MarketingPostsCrew) CopyCreationTask() (*Copy, error) {
 		body = "Descubra as estratégias que estão revolucionando o mercado..."
 	}
 
-	// Cria o texto baseado nas memórias
-	copy := &Copy{
+	// Cria o texto e o passa pela revisão da voz da marca
+	copy, review, err := c.reviewCopy(&Copy{
 		Title: title,
 		Body:  body,
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	// Memoriza o texto
-	copyData := map[string]interface{}{
-		"copy":      copy,
-		"timestamp": time.Now(),
-		"audience":  audience,
-		"objective": objective,
-	}
-	err = c.creativeContentCreator.Memorize(c.ctx, copyData, 0.7, []string{"copy", "content"}, true)
+	record := CopyRecord{Project: project.Name, Audience: audience, Objective: objective, Copy: copy, Review: review, Timestamp: time.Now()}
+	err = c.creativeContentCreator.MemorizeValue(c.ctx, SchemaCopy, record, 0.7, []string{"copy", "content"}, true)
 	if err != nil {
 		return nil, fmt.Errorf("erro ao memorizar texto: %v", err)
 	}
+	c.copy = copy
+	c.review = review
 
 	return copy, nil
 }
 
+// reviewCopy verifica o texto contra a voz da marca. Cada violação gera um pedido de revisão
+// ao criador de conteúdo, que reescreve o texto pelo LLM com o relatório das violações, como
+// na revisão das saídas dos agentes; sem LLM, ele aplica só as correções mecânicas das
+// diretrizes. O texto que continuar reprovado após as revisões é mantido, com as violações
+// restantes na revisão.
+func (c *MarketingPostsCrew) reviewCopy(copy *Copy) (*Copy, *brandvoice.Review, error) {
+	if c.brandVoice == nil {
+		return copy, nil, nil
+	}
+	creator := c.creativeContentCreator
+	review, err := c.brandVoice.Review(c.ctx, copy.Title+"\n\n"+copy.Body, func(ctx context.Context, text string, request brandvoice.Request) (string, error) {
+		log.Printf("✏️ Revisão %d do texto pedida ao criador de conteúdo:\n%s", request.Attempt, request.Report.Feedback())
+		if creator.LLM() == nil {
+			return request.Voice.Apply(text), nil
+		}
+		return creator.CompletePrompt(ctx, prompt.Prompt{
+			Input: fmt.Sprintf("%s\nMantenha o título na primeira linha, seguido de uma linha em branco e do corpo.\n\nTexto anterior:\n%s",
+				request, text),
+		})
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("erro na revisão do texto: %v", err)
+	}
+	if !review.Approved {
+		log.Printf("⚠️ Texto mantido com violações da voz da marca após %d revisões:\n%s",
+			review.Revisions, brandvoice.Report{Violations: review.Violations()}.Feedback())
+	}
+	title, body, _ := strings.Cut(strings.TrimSpace(review.Text), "\n\n")
+	return &Copy{Title: title, Body: body}, review, nil
+}
+
+// recordStrategy registra a estratégia, os canais, as táticas e os KPIs no grafo
+func (c *MarketingPostsCrew) recordStrategy(record StrategyRecord) {
+	strategy := record.Strategy
+	c.strategy = c.knowledge.Upsert(knowledge.KindStrategy, strategy.Name, map[string]string{"objective": record.Objective})
+	if c.project != "" {
+		c.knowledge.Relate(c.project, knowledge.RelPlans, c.strategy)
+	}
+	for _, channel := range strategy.Channels {
+		c.knowledge.Relate(c.strategy, knowledge.RelUses, c.knowledge.Upsert(knowledge.KindChannel, channel, nil))
+	}
+	for _, tactic := range strategy.Tactics {
+		c.knowledge.Relate(c.strategy, knowledge.RelUses, c.knowledge.Upsert(knowledge.KindTactic, tactic, nil))
+	}
+	for _, kpi := range strategy.KPIs {
+		c.knowledge.Relate(c.strategy, knowledge.RelMeasures, c.knowledge.Upsert(knowledge.KindKPI, kpi, nil))
+	}
+}
+
+// recordCampaign registra a campanha, o público e o canal no grafo
+func (c *MarketingPostsCrew) recordCampaign(record CampaignRecord) {
+	idea := record.Idea
+	c.campaign = c.knowledge.Upsert(knowledge.KindCampaign, idea.Name, map[string]string{"description": idea.Description})
+	if c.strategy != "" {
+		c.knowledge.Relate(c.strategy, knowledge.RelIncludes, c.campaign)
+	}
+	if idea.Audience != "" {
+		c.knowledge.Relate(c.campaign, knowledge.RelTargets, c.knowledge.Upsert(knowledge.KindAudience, idea.Audience, nil))
+	}
+	if idea.Channel != "" {
+		c.knowledge.Relate(c.campaign, knowledge.RelUses, c.knowledge.Upsert(knowledge.KindChannel, idea.Channel, nil))
+	}
+}
+
+// restoreProject recupera da memória do estrategista o último projeto, quando a compreensão
+// do projeto não rodou nesta instância da equipe (por exemplo, ao retomar um workflow)
+func (c *MarketingPostsCrew) restoreProject() error {
+	if c.project != "" {
+		return nil
+	}
+	var details map[string]interface{}
+	found, err := c.chiefMarketingStrategist.RecallValue(c.ctx, "", SchemaProject, &details)
+	if err != nil {
+		return fmt.Errorf("erro ao recuperar memórias: %v", err)
+	}
+	if found {
+		c.project = knowledge.Extract(c.knowledge, details)
+	}
+	return nil
+}
+
+// restoreStrategy recupera da memória do estrategista a última estratégia, que o criador de
+// conteúdo usa quando a estratégia não foi desenvolvida nesta instância da equipe
+func (c *MarketingPostsCrew) restoreStrategy() error {
+	if err := c.restoreProject(); err != nil || c.strategy != "" {
+		return err
+	}
+	var record StrategyRecord
+	found, err := c.creativeContentCreator.RecallValue(c.ctx, c.chiefMarketingStrategist.GetID(), SchemaStrategy, &record)
+	if err != nil {
+		return fmt.Errorf("erro ao recuperar memórias: %v", err)
+	}
+	if found && record.Strategy != nil {
+		c.recordStrategy(record)
+	}
+	return nil
+}
+
+// restoreCampaign recupera da memória do criador de conteúdo a última ideia de campanha
+func (c *MarketingPostsCrew) restoreCampaign() error {
+	if err := c.restoreStrategy(); err != nil || c.campaign != "" {
+		return err
+	}
+	var record CampaignRecord
+	found, err := c.creativeContentCreator.RecallValue(c.ctx, "", SchemaCampaign, &record)
+	if err != nil {
+		return fmt.Errorf("erro ao recuperar memórias: %v", err)
+	}
+	if found && record.Idea != nil {
+		c.recordCampaign(record)
+	}
+	return nil
+}
+
+// Knowledge retorna o grafo com as entidades das campanhas da equipe, que os agentes podem
+// consultar (veja agents.NewKnowledgeTool)
+func (c *MarketingPostsCrew) Knowledge() *knowledge.Graph {
+	return c.knowledge
+}
+
 // ExecuteWorkflow executa o fluxo completo de trabalho
 func (c *MarketingPostsCrew) ExecuteWorkflow(projectDetails map[string]interface{}) (*WorkflowResult, error) {
 	// 1. Pesquisa
@@ -385,15 +572,23 @@ func (c *MarketingPostsCrew) ExecuteWorkflow(projectDetails map[string]interface
 		return nil, fmt.Errorf("erro na criação do texto: %v", err)
 	}
 
+	// 6. Calendário de conteúdo, nos canais da estratégia
+	contentCalendar, err := c.ContentCalendarTask(calendar.Plan{})
+	if err != nil {
+		return nil, fmt.Errorf("erro no calendário de conteúdo: %v", err)
+	}
+
 	// Consolida as memórias importantes
 	if err := c.ConsolidateAllMemories(); err != nil {
 		return nil, fmt.Errorf("erro ao consolidar memórias: %v", err)
 	}
 
 	return &WorkflowResult{
-		Strategy: strategy,
-		Campaign: idea,
-		Copy:     copy,
+		Strategy:   strategy,
+		Campaign:   idea,
+		Copy:       copy,
+		Calendar:   contentCalendar,
+		BrandVoice: c.review,
 	}, nil
 }
 
@@ -416,9 +611,13 @@ func (c *MarketingPostsCrew) ConsolidateAllMemories() error {
 
 // WorkflowResult contém os resultados do fluxo de trabalho
 type WorkflowResult struct {
-	Strategy *MarketStrategy `json:"strategy"`
-	Campaign *CampaignIdea   `json:"campaign"`
-	Copy     *Copy           `json:"copy"`
+	Strategy *MarketStrategy    `json:"strategy"`
+	Campaign *CampaignIdea      `json:"campaign"`
+	Copy     *Copy              `json:"copy"`
+	Calendar *calendar.Calendar `json:"calendar,omitempty"`
+
+	// Revisão do texto pela voz da marca (nil sem diretrizes)
+	BrandVoice *brandvoice.Review `json:"brand_voice,omitempty"`
 }
 
 // String retorna uma representação em string do resultado
@@ -0,0 +1,76 @@
+package marketing
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/suissa/HiveMind/agents/brandvoice"
+	"github.com/suissa/HiveMind/agents/llm"
+)
+
+func TestReviewCopyAsksTheCreator(t *testing.T) {
+	crew := NewMarketingPostsCrew(context.Background(), nil)
+	crew.SetBrandVoice(&brandvoice.Voice{Name: "HiveMind", Banned: []string{"revolucionando"}})
+
+	var prompts []string
+	crew.SetLLM(llm.ProviderFunc(func(ctx context.Context, req llm.Request) (*llm.Response, error) {
+		prompts = append(prompts, req.Prompt)
+		return &llm.Response{Text: "Domine o marketing\n\nDescubra as estratégias que estão mudando o mercado."}, nil
+	}))
+
+	copy, review, err := crew.reviewCopy(&Copy{Title: "Domine o marketing", Body: "Descubra as estratégias que estão revolucionando o mercado..."})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(prompts) != 1 || !strings.Contains(prompts[0], "revolucionando") || !strings.Contains(prompts[0], "Texto anterior:") {
+		t.Fatalf("o pedido de revisão deveria ir ao LLM com o relatório e o texto: %q", prompts)
+	}
+	if !review.Approved || review.Revisions != 1 {
+		t.Fatalf("revisão inesperada: %+v", review)
+	}
+	if copy.Title != "Domine o marketing" || copy.Body != "Descubra as estratégias que estão mudando o mercado." {
+		t.Fatalf("texto revisado inesperado: %+v", copy)
+	}
+}
+
+func TestReviewCopyWithoutLLM(t *testing.T) {
+	crew := NewMarketingPostsCrew(context.Background(), nil)
+	crew.SetBrandVoice(&brandvoice.Voice{
+		Name:         "HiveMind",
+		Banned:       []string{"revolucionando"},
+		Replacements: map[string]string{"revolucionando": "transformando"},
+	})
+
+	// Sem LLM, o criador aplica as correções mecânicas das diretrizes
+	copy, review, err := crew.reviewCopy(&Copy{Title: "Domine", Body: "Estratégias revolucionando o mercado"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !review.Approved || copy.Body != "Estratégias transformando o mercado" {
+		t.Fatalf("revisão inesperada: %+v %+v", copy, review)
+	}
+}
+
+func TestLoadBrandVoice(t *testing.T) {
+	crew := NewMarketingPostsCrew(context.Background(), nil)
+	if crew.brandVoice != nil {
+		t.Fatal("a equipe não deveria carregar a voz da marca por conta própria")
+	}
+
+	path := filepath.Join(t.TempDir(), "brand_voice.yaml")
+	if err := os.WriteFile(path, []byte("brand_voice:\n  name: HiveMind\n  banned: [incrível]\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := crew.LoadBrandVoice(path); err != nil {
+		t.Fatal(err)
+	}
+	if crew.brandVoice == nil || crew.creativeContentCreator.BrandVoice() == nil {
+		t.Fatal("esperava a voz da marca ativa na equipe e no criador de conteúdo")
+	}
+	if err := crew.LoadBrandVoice(filepath.Join(t.TempDir(), "ausente.yaml")); err == nil {
+		t.Fatal("esperava o erro do arquivo ausente")
+	}
+}
@@ -0,0 +1,320 @@
+// Package mcp implementa um cliente do Model Context Protocol: conecta-se a servidores MCP
+// externos (por stdio ou HTTP), lista as ferramentas e os recursos expostos e os chama. As
+// ferramentas do servidor são adaptadas à interface Tool dos agentes, com o JSON Schema dos
+// parâmetros, para registro no ToolRegistry. No sentido inverso, Server expõe ferramentas a
+// clientes MCP externos.
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/suissa/HiveMind/agents/errs"
+)
+
+// ProtocolVersion é a versão do protocolo negociada na inicialização
+const ProtocolVersion = "2025-03-26"
+
+// DefaultTimeout é o prazo padrão de cada chamada ao servidor
+const DefaultTimeout = 30 * time.Second
+
+// Config configura a conexão com um servidor MCP: Command para servidores locais (stdio) ou
+// URL para servidores remotos (HTTP)
+type Config struct {
+	Name       string            `yaml:"name"`
+	Command    string            `yaml:"command"`
+	Args       []string          `yaml:"args"`
+	Env        []string          `yaml:"env"` // Variáveis extras do processo, no formato CHAVE=valor
+	URL        string            `yaml:"url"`
+	Headers    map[string]string `yaml:"headers"`     // Cabeçalhos HTTP, ex.: Authorization
+	ToolPrefix string            `yaml:"tool_prefix"` // Prefixo dos nomes das ferramentas (padrão: "<Name>.")
+	Timeout    time.Duration     `yaml:"timeout"`
+}
+
+// ServerInfo descreve o servidor, conforme a resposta da inicialização
+type ServerInfo struct {
+	Name         string                 `json:"name"`
+	Version      string                 `json:"version"`
+	Protocol     string                 `json:"-"`
+	Capabilities map[string]interface{} `json:"-"`
+	Instructions string                 `json:"-"`
+}
+
+// ToolInfo é uma ferramenta exposta pelo servidor
+type ToolInfo struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description,omitempty"`
+	InputSchema json.RawMessage `json:"inputSchema"`
+}
+
+// Resource é um recurso exposto pelo servidor
+type Resource struct {
+	URI         string `json:"uri"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	MimeType    string `json:"mimeType,omitempty"`
+}
+
+// ResourceContent é o conteúdo de um recurso: texto ou binário em base64 (Blob)
+type ResourceContent struct {
+	URI      string `json:"uri"`
+	MimeType string `json:"mimeType,omitempty"`
+	Text     string `json:"text,omitempty"`
+	Blob     string `json:"blob,omitempty"`
+}
+
+// Content é um item do resultado de uma ferramenta
+type Content struct {
+	Type     string           `json:"type"` // text, image, audio ou resource
+	Text     string           `json:"text,omitempty"`
+	Data     string           `json:"data,omitempty"`
+	MimeType string           `json:"mimeType,omitempty"`
+	Resource *ResourceContent `json:"resource,omitempty"`
+}
+
+// CallResult é o resultado de uma chamada de ferramenta
+type CallResult struct {
+	Content           []Content              `json:"content"`
+	StructuredContent map[string]interface{} `json:"structuredContent,omitempty"`
+	IsError           bool                   `json:"isError,omitempty"`
+}
+
+// Text junta os itens de texto do resultado
+func (r *CallResult) Text() string {
+	var parts []string
+	for _, content := range r.Content {
+		switch {
+		case content.Type == "text":
+			parts = append(parts, content.Text)
+		case content.Resource != nil && content.Resource.Text != "":
+			parts = append(parts, content.Resource.Text)
+		}
+	}
+	return strings.Join(parts, "\n")
+}
+
+// Client é uma sessão com um servidor MCP, segura para uso concorrente
+type Client struct {
+	config    Config
+	transport transport
+	cmd       *exec.Cmd
+	server    ServerInfo
+	nextID    atomic.Int64
+	closeOnce sync.Once
+}
+
+// Connect inicia a conexão com o servidor (executando Command ou apontando para URL) e faz o
+// handshake de inicialização
+func Connect(ctx context.Context, config Config) (*Client, error) {
+	if config.Timeout <= 0 {
+		config.Timeout = DefaultTimeout
+	}
+	client := &Client{config: config}
+
+	switch {
+	case config.Command != "":
+		cmd := exec.Command(config.Command, config.Args...)
+		cmd.Env = append(os.Environ(), config.Env...)
+		cmd.Stderr = os.Stderr
+		stdin, err := cmd.StdinPipe()
+		if err != nil {
+			return nil, fmt.Errorf("erro ao abrir a entrada do servidor MCP %s: %v", config.Name, err)
+		}
+		stdout, err := cmd.StdoutPipe()
+		if err != nil {
+			return nil, fmt.Errorf("erro ao abrir a saída do servidor MCP %s: %v", config.Name, err)
+		}
+		if err := cmd.Start(); err != nil {
+			return nil, fmt.Errorf("erro ao iniciar o servidor MCP %s: %v", config.Name, err)
+		}
+		client.cmd = cmd
+		client.transport = newStreamTransport(stdout, stdin)
+	case config.URL != "":
+		client.transport = &httpTransport{url: config.URL, headers: config.Headers, client: &http.Client{}}
+	default:
+		return nil, errs.New(errs.ErrValidation, "mcp.Connect", "servidor MCP %s sem command nem url", config.Name)
+	}
+
+	if err := client.initialize(ctx); err != nil {
+		client.Close()
+		return nil, fmt.Errorf("erro ao inicializar o servidor MCP %s: %w", config.Name, err)
+	}
+	return client, nil
+}
+
+// newClient cria um cliente sobre um transporte já conectado (usado nos testes)
+func newClient(ctx context.Context, config Config, t transport) (*Client, error) {
+	if config.Timeout <= 0 {
+		config.Timeout = DefaultTimeout
+	}
+	client := &Client{config: config, transport: t}
+	if err := client.initialize(ctx); err != nil {
+		return nil, err
+	}
+	return client, nil
+}
+
+func (c *Client) initialize(ctx context.Context) error {
+	var result struct {
+		ProtocolVersion string                 `json:"protocolVersion"`
+		Capabilities    map[string]interface{} `json:"capabilities"`
+		ServerInfo      ServerInfo             `json:"serverInfo"`
+		Instructions    string                 `json:"instructions"`
+	}
+	err := c.call(ctx, "initialize", map[string]interface{}{
+		"protocolVersion": ProtocolVersion,
+		"capabilities":    map[string]interface{}{},
+		"clientInfo":      map[string]string{"name": "hivemind", "version": "1.0"},
+	}, &result)
+	if err != nil {
+		return err
+	}
+	c.server = result.ServerInfo
+	c.server.Protocol = result.ProtocolVersion
+	c.server.Capabilities = result.Capabilities
+	c.server.Instructions = result.Instructions
+
+	ctx, cancel := context.WithTimeout(ctx, c.config.Timeout)
+	defer cancel()
+	return c.transport.notify(ctx, request{JSONRPC: "2.0", Method: "notifications/initialized"})
+}
+
+// call envia a requisição e decodifica o resultado em out
+func (c *Client) call(ctx context.Context, method string, params interface{}, out interface{}) error {
+	ctx, cancel := context.WithTimeout(ctx, c.config.Timeout)
+	defer cancel()
+
+	id := c.nextID.Add(1)
+	msg, err := c.transport.call(ctx, request{JSONRPC: "2.0", ID: &id, Method: method, Params: params})
+	if err != nil {
+		return err
+	}
+	if msg.Error != nil {
+		return msg.Error
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.Unmarshal(msg.Result, out); err != nil {
+		return fmt.Errorf("resultado inválido de %s: %v", method, err)
+	}
+	return nil
+}
+
+// Name retorna o nome do servidor na configuração
+func (c *Client) Name() string {
+	return c.config.Name
+}
+
+// Server retorna as informações do servidor obtidas na inicialização
+func (c *Client) Server() ServerInfo {
+	return c.server
+}
+
+// Supports informa se o servidor declarou a capacidade (ex.: "tools", "resources")
+func (c *Client) Supports(capability string) bool {
+	_, ok := c.server.Capabilities[capability]
+	return ok
+}
+
+// ListTools lista as ferramentas do servidor, percorrendo todas as páginas
+func (c *Client) ListTools(ctx context.Context) ([]ToolInfo, error) {
+	var tools []ToolInfo
+	cursor := ""
+	for {
+		var page struct {
+			Tools      []ToolInfo `json:"tools"`
+			NextCursor string     `json:"nextCursor"`
+		}
+		if err := c.call(ctx, "tools/list", pageParams(cursor), &page); err != nil {
+			return nil, err
+		}
+		tools = append(tools, page.Tools...)
+		if page.NextCursor == "" {
+			return tools, nil
+		}
+		cursor = page.NextCursor
+	}
+}
+
+// CallTool chama uma ferramenta do servidor. Erros da ferramenta (IsError) são devolvidos no
+// resultado; o erro de retorno indica falha do protocolo.
+func (c *Client) CallTool(ctx context.Context, name string, arguments map[string]interface{}) (*CallResult, error) {
+	if arguments == nil {
+		arguments = map[string]interface{}{}
+	}
+	var result CallResult
+	if err := c.call(ctx, "tools/call", map[string]interface{}{"name": name, "arguments": arguments}, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// ListResources lista os recursos do servidor, percorrendo todas as páginas
+func (c *Client) ListResources(ctx context.Context) ([]Resource, error) {
+	var resources []Resource
+	cursor := ""
+	for {
+		var page struct {
+			Resources  []Resource `json:"resources"`
+			NextCursor string     `json:"nextCursor"`
+		}
+		if err := c.call(ctx, "resources/list", pageParams(cursor), &page); err != nil {
+			return nil, err
+		}
+		resources = append(resources, page.Resources...)
+		if page.NextCursor == "" {
+			return resources, nil
+		}
+		cursor = page.NextCursor
+	}
+}
+
+// ReadResource lê o conteúdo de um recurso pela URI
+func (c *Client) ReadResource(ctx context.Context, uri string) ([]ResourceContent, error) {
+	var result struct {
+		Contents []ResourceContent `json:"contents"`
+	}
+	if err := c.call(ctx, "resources/read", map[string]string{"uri": uri}, &result); err != nil {
+		return nil, err
+	}
+	return result.Contents, nil
+}
+
+// pageParams monta os parâmetros de paginação
+func pageParams(cursor string) interface{} {
+	if cursor == "" {
+		return nil
+	}
+	return map[string]string{"cursor": cursor}
+}
+
+// Close encerra a sessão e, nos servidores stdio, o processo do servidor
+func (c *Client) Close() error {
+	var err error
+	c.closeOnce.Do(func() {
+		err = c.transport.close()
+		if c.cmd != nil {
+			done := make(chan struct{})
+			go func() {
+				c.cmd.Wait()
+				close(done)
+			}()
+			select {
+			case <-done:
+			case <-time.After(5 * time.Second):
+				c.cmd.Process.Kill()
+				<-done
+			}
+		}
+	})
+	return err
+}
@@ -0,0 +1,144 @@
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// fakeServer responde às requisições MCP usadas nos testes
+func fakeServer(method string, params json.RawMessage) (interface{}, *RPCError) {
+	switch method {
+	case "initialize":
+		return map[string]interface{}{
+			"protocolVersion": ProtocolVersion,
+			"capabilities":    map[string]interface{}{"tools": map[string]interface{}{}, "resources": map[string]interface{}{}},
+			"serverInfo":      map[string]string{"name": "fake", "version": "0.1"},
+		}, nil
+	case "tools/list":
+		var page struct {
+			Cursor string `json:"cursor"`
+		}
+		json.Unmarshal(params, &page)
+		if page.Cursor == "" {
+			return map[string]interface{}{
+				"tools":      []map[string]interface{}{{"name": "soma", "description": "Soma dois números", "inputSchema": map[string]interface{}{"type": "object", "required": []string{"a", "b"}}}},
+				"nextCursor": "2",
+			}, nil
+		}
+		return map[string]interface{}{"tools": []map[string]interface{}{{"name": "falha", "inputSchema": map[string]interface{}{"type": "object"}}}}, nil
+	case "tools/call":
+		var call struct {
+			Name      string             `json:"name"`
+			Arguments map[string]float64 `json:"arguments"`
+		}
+		json.Unmarshal(params, &call)
+		if call.Name == "falha" {
+			return map[string]interface{}{"content": []map[string]string{{"type": "text", "text": "sem acesso"}}, "isError": true}, nil
+		}
+		return map[string]interface{}{"content": []map[string]string{{"type": "text", "text": fmt.Sprint(call.Arguments["a"] + call.Arguments["b"])}}}, nil
+	case "resources/read":
+		return map[string]interface{}{"contents": []map[string]string{{"uri": "file:///leia-me", "text": "conteúdo"}}}, nil
+	}
+	return nil, &RPCError{Code: -32601, Message: "método desconhecido"}
+}
+
+// reply monta a resposta JSON-RPC de uma requisição
+func reply(line []byte) ([]byte, bool) {
+	var req struct {
+		ID     *int64          `json:"id"`
+		Method string          `json:"method"`
+		Params json.RawMessage `json:"params"`
+	}
+	if json.Unmarshal(line, &req) != nil || req.ID == nil {
+		return nil, false // Notificação
+	}
+	result, rpcErr := fakeServer(req.Method, req.Params)
+	data, _ := json.Marshal(map[string]interface{}{"jsonrpc": "2.0", "id": *req.ID, "result": result, "error": rpcErr})
+	return data, true
+}
+
+func checkClient(t *testing.T, client *Client) {
+	ctx := context.Background()
+	if client.Server().Name != "fake" || !client.Supports("resources") {
+		t.Fatalf("servidor inesperado: %+v", client.Server())
+	}
+
+	tools, err := client.Tools(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tools) != 3 || tools[0].Name() != "fake.soma" || tools[2].Name() != "fake.read_resource" {
+		t.Fatalf("ferramentas inesperadas: %v", tools)
+	}
+	if !strings.Contains(string(tools[0].InputSchema()), `"required":["a","b"]`) {
+		t.Fatalf("schema inesperado: %s", tools[0].InputSchema())
+	}
+
+	if out, err := tools[0].Execute(ctx, map[string]interface{}{"a": 2, "b": 3}); err != nil || out != "5" {
+		t.Fatalf("resultado inesperado: %v, %v", out, err)
+	}
+	if _, err := tools[1].Execute(ctx, nil); err == nil || !strings.Contains(err.Error(), "sem acesso") {
+		t.Fatalf("esperava o erro da ferramenta: %v", err)
+	}
+	if out, err := tools[2].Execute(ctx, map[string]interface{}{"uri": "file:///leia-me"}); err != nil || out != "conteúdo" {
+		t.Fatalf("recurso inesperado: %v, %v", out, err)
+	}
+	if _, err := client.ListResources(ctx); err == nil {
+		t.Fatal("esperava o erro JSON-RPC do método desconhecido")
+	}
+}
+
+func TestStdioClient(t *testing.T) {
+	clientIn, serverOut := io.Pipe()
+	serverIn, clientOut := io.Pipe()
+	go func() {
+		scanner := bufio.NewScanner(serverIn)
+		for scanner.Scan() {
+			if data, ok := reply(scanner.Bytes()); ok {
+				serverOut.Write(append(data, '\n'))
+			}
+		}
+		serverOut.Close()
+	}()
+
+	client, err := newClient(context.Background(), Config{Name: "fake"}, newStreamTransport(clientIn, clientOut))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+	checkClient(t, client)
+}
+
+func TestHTTPClientWithEventStream(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if !strings.Contains(string(body), `"initialize"`) && r.Header.Get("Mcp-Session-Id") != "s-1" {
+			http.Error(w, "sessão ausente", http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Mcp-Session-Id", "s-1")
+		data, ok := reply(body)
+		if !ok {
+			w.WriteHeader(http.StatusAccepted)
+			return
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprintf(w, "event: message\ndata: {\"jsonrpc\":\"2.0\",\"method\":\"notifications/progress\"}\n\n")
+		fmt.Fprintf(w, "event: message\ndata: %s\n\n", data)
+	}))
+	defer server.Close()
+
+	client, err := Connect(context.Background(), Config{Name: "fake", URL: server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+	checkClient(t, client)
+}
@@ -0,0 +1,231 @@
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/suissa/HiveMind/agents/errs"
+)
+
+// Códigos de erro JSON-RPC usados pelo servidor
+const (
+	codeParseError     = -32700
+	codeMethodNotFound = -32601
+	codeInvalidParams  = -32602
+)
+
+// Caller identifica o cliente MCP perante a matriz de permissões de ferramentas
+type Caller struct {
+	ID   string `yaml:"id"`
+	Role string `yaml:"role"`
+}
+
+// GetID implementa a identificação do chamador das ferramentas (ToolCaller)
+func (c Caller) GetID() string { return c.ID }
+
+// GetRole implementa a identificação do chamador das ferramentas (ToolCaller)
+func (c Caller) GetRole() string { return c.Role }
+
+// Backend é o conjunto de ferramentas exposto pelo servidor, filtrado e executado com as
+// permissões do chamador
+type Backend interface {
+	Tools(caller Caller) []ToolInfo
+	Call(ctx context.Context, caller Caller, name string, arguments map[string]interface{}) (interface{}, error)
+}
+
+// ServerConfig configura o servidor MCP
+type ServerConfig struct {
+	Name    string            `yaml:"name"`
+	Version string            `yaml:"version"`
+	Caller  Caller            `yaml:"caller"` // Identidade do cliente no stdio
+	Tokens  map[string]Caller `yaml:"tokens"` // Tokens Bearer aceitos no HTTP e a identidade de cada um
+}
+
+// Server expõe as ferramentas do Backend pelo protocolo MCP, por stdio (ServeStdio) ou HTTP
+// (ServeHTTP, transporte streamable HTTP com respostas JSON)
+type Server struct {
+	config  ServerConfig
+	backend Backend
+}
+
+// NewServer cria um servidor MCP
+func NewServer(backend Backend, config ServerConfig) *Server {
+	if config.Name == "" {
+		config.Name = "hivemind"
+	}
+	if config.Version == "" {
+		config.Version = "1.0"
+	}
+	return &Server{config: config, backend: backend}
+}
+
+// ServeStdio atende às mensagens delimitadas por quebra de linha de r, respondendo em w, até
+// r terminar ou o contexto ser cancelado. As chamadas são atendidas em paralelo.
+func (s *Server) ServeStdio(ctx context.Context, r io.Reader, w io.Writer) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var writeMu sync.Mutex
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	reader := bufio.NewReader(r)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if len(strings.TrimSpace(string(line))) > 0 {
+			wg.Add(1)
+			go func(line []byte) {
+				defer wg.Done()
+				reply := s.handle(ctx, s.config.Caller, line)
+				if reply == nil {
+					return
+				}
+				writeMu.Lock()
+				defer writeMu.Unlock()
+				w.Write(append(reply, '\n'))
+			}(line)
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("erro ao ler do cliente MCP: %v", err)
+		}
+		if ctx.Err() != nil {
+			return errs.FromContext("mcp.ServeStdio", ctx.Err())
+		}
+	}
+}
+
+// ServeHTTP implementa http.Handler: cada POST traz uma mensagem JSON-RPC, respondida em JSON;
+// notificações recebem 202. Exige "Authorization: Bearer <token>" com um dos Tokens; sem
+// tokens configurados, todas as requisições são recusadas.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "use POST", http.StatusMethodNotAllowed)
+		return
+	}
+	if len(s.config.Tokens) == 0 {
+		http.Error(w, "servidor MCP sem tokens configurados", http.StatusUnauthorized)
+		return
+	}
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	caller, ok := s.config.Tokens[token]
+	if !ok || token == "" {
+		http.Error(w, "token inválido", http.StatusUnauthorized)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, 16*1024*1024))
+	if err != nil {
+		http.Error(w, "erro ao ler a requisição", http.StatusBadRequest)
+		return
+	}
+	reply := s.handle(r.Context(), caller, body)
+	if reply == nil {
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(reply)
+}
+
+// handle processa uma mensagem e retorna a resposta serializada (nil para notificações)
+func (s *Server) handle(ctx context.Context, caller Caller, data []byte) []byte {
+	var req struct {
+		ID     json.RawMessage `json:"id"`
+		Method string          `json:"method"`
+		Params json.RawMessage `json:"params"`
+	}
+	if err := json.Unmarshal(data, &req); err != nil {
+		return s.reply(json.RawMessage("null"), nil, &RPCError{Code: codeParseError, Message: "JSON inválido"})
+	}
+	if len(req.ID) == 0 {
+		return nil // Notificação (ex.: notifications/initialized)
+	}
+
+	result, rpcErr := s.dispatch(ctx, caller, req.Method, req.Params)
+	return s.reply(req.ID, result, rpcErr)
+}
+
+func (s *Server) reply(id json.RawMessage, result interface{}, rpcErr *RPCError) []byte {
+	msg := map[string]interface{}{"jsonrpc": "2.0", "id": id}
+	if rpcErr != nil {
+		msg["error"] = rpcErr
+	} else {
+		msg["result"] = result
+	}
+	data, _ := json.Marshal(msg)
+	return data
+}
+
+func (s *Server) dispatch(ctx context.Context, caller Caller, method string, params json.RawMessage) (interface{}, *RPCError) {
+	switch method {
+	case "initialize":
+		return map[string]interface{}{
+			"protocolVersion": ProtocolVersion,
+			"capabilities":    map[string]interface{}{"tools": map[string]interface{}{}},
+			"serverInfo":      map[string]string{"name": s.config.Name, "version": s.config.Version},
+		}, nil
+	case "ping":
+		return map[string]interface{}{}, nil
+	case "tools/list":
+		tools := s.backend.Tools(caller)
+		for i := range tools {
+			if len(tools[i].InputSchema) == 0 {
+				tools[i].InputSchema = json.RawMessage(`{"type":"object"}`)
+			}
+		}
+		return map[string]interface{}{"tools": tools}, nil
+	case "tools/call":
+		var call struct {
+			Name      string                 `json:"name"`
+			Arguments map[string]interface{} `json:"arguments"`
+		}
+		if err := json.Unmarshal(params, &call); err != nil || call.Name == "" {
+			return nil, &RPCError{Code: codeInvalidParams, Message: "parâmetros de tools/call inválidos"}
+		}
+		output, err := s.call(ctx, caller, call.Name, call.Arguments)
+		if err != nil {
+			// Erros da ferramenta (inclusive permissão negada) vão no resultado, para o modelo ver
+			return &CallResult{Content: []Content{{Type: "text", Text: err.Error()}}, IsError: true}, nil
+		}
+		return toCallResult(output), nil
+	}
+	return nil, &RPCError{Code: codeMethodNotFound, Message: "método não suportado: " + method}
+}
+
+// call executa a ferramenta, convertendo um panic em erro para não derrubar o servidor
+func (s *Server) call(ctx context.Context, caller Caller, name string, arguments map[string]interface{}) (output interface{}, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("ferramenta %s falhou: %v", name, r)
+		}
+	}()
+	return s.backend.Call(ctx, caller, name, arguments)
+}
+
+// toCallResult converte a saída da ferramenta: texto vira conteúdo de texto e os demais
+// valores vão serializados em JSON, também como conteúdo estruturado quando forem objetos
+func toCallResult(output interface{}) *CallResult {
+	if text, ok := output.(string); ok {
+		return &CallResult{Content: []Content{{Type: "text", Text: text}}}
+	}
+	data, err := json.Marshal(output)
+	if err != nil {
+		return &CallResult{Content: []Content{{Type: "text", Text: fmt.Sprint(output)}}}
+	}
+	result := &CallResult{Content: []Content{{Type: "text", Text: string(data)}}}
+	var structured map[string]interface{}
+	if json.Unmarshal(data, &structured) == nil {
+		result.StructuredContent = structured
+	}
+	return result
+}
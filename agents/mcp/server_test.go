@@ -0,0 +1,121 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// fakeBackend expõe "soma" a todos e "segredo" apenas ao papel admin
+type fakeBackend struct{}
+
+func (fakeBackend) Tools(caller Caller) []ToolInfo {
+	tools := []ToolInfo{{Name: "soma", Description: "Soma dois números", InputSchema: json.RawMessage(`{"type":"object","required":["a","b"]}`)}}
+	if caller.Role == "admin" {
+		tools = append(tools, ToolInfo{Name: "segredo"})
+	}
+	return tools
+}
+
+func (fakeBackend) Call(ctx context.Context, caller Caller, name string, arguments map[string]interface{}) (interface{}, error) {
+	switch {
+	case name == "soma":
+		a, _ := arguments["a"].(float64)
+		b, _ := arguments["b"].(float64)
+		return fmt.Sprint(a + b), nil
+	case name == "segredo" && caller.Role == "admin":
+		return map[string]interface{}{"valor": 42}, nil
+	}
+	return nil, fmt.Errorf("ferramenta não permitida: %s", name)
+}
+
+func TestServerOverStdio(t *testing.T) {
+	clientIn, serverOut := io.Pipe()
+	serverIn, clientOut := io.Pipe()
+	server := NewServer(fakeBackend{}, ServerConfig{Name: "teste", Caller: Caller{ID: "externo", Role: "assistant"}})
+	go func() {
+		server.ServeStdio(context.Background(), serverIn, serverOut)
+		serverOut.Close()
+	}()
+
+	client, err := newClient(context.Background(), Config{Name: "hive"}, newStreamTransport(clientIn, clientOut))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	if client.Server().Name != "teste" || !client.Supports("tools") {
+		t.Fatalf("servidor inesperado: %+v", client.Server())
+	}
+	tools, err := client.Tools(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tools) != 1 || tools[0].Name() != "hive.soma" {
+		t.Fatalf("o assistente só deveria ver a soma: %v", tools)
+	}
+	if out, err := tools[0].Execute(context.Background(), map[string]interface{}{"a": 2, "b": 3}); err != nil || out != "5" {
+		t.Fatalf("resultado inesperado: %v, %v", out, err)
+	}
+
+	result, err := client.CallTool(context.Background(), "segredo", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.IsError || !strings.Contains(result.Text(), "não permitida") {
+		t.Fatalf("esperava a negação no resultado: %+v", result)
+	}
+}
+
+func TestServerOverHTTPWithTokens(t *testing.T) {
+	server := NewServer(fakeBackend{}, ServerConfig{Tokens: map[string]Caller{"t-admin": {ID: "ops", Role: "admin"}}})
+	httpServer := httptest.NewServer(server)
+	defer httpServer.Close()
+
+	if _, err := Connect(context.Background(), Config{Name: "hive", URL: httpServer.URL}); err == nil {
+		t.Fatal("esperava a recusa sem token")
+	}
+
+	client, err := Connect(context.Background(), Config{Name: "hive", URL: httpServer.URL, Headers: map[string]string{"Authorization": "Bearer t-admin"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	tools, err := client.ListTools(context.Background())
+	if err != nil || len(tools) != 2 || string(tools[1].InputSchema) != `{"type":"object"}` {
+		t.Fatalf("ferramentas inesperadas: %+v, %v", tools, err)
+	}
+	result, err := client.CallTool(context.Background(), "segredo", nil)
+	if err != nil || result.IsError || result.StructuredContent["valor"] != float64(42) {
+		t.Fatalf("resultado inesperado: %+v, %v", result, err)
+	}
+
+	resp, err := http.Get(httpServer.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusMethodNotAllowed {
+		t.Fatalf("GET deveria ser recusado: %d", resp.StatusCode)
+	}
+}
+
+func TestServerOverHTTPWithoutTokens(t *testing.T) {
+	httpServer := httptest.NewServer(NewServer(fakeBackend{}, ServerConfig{Caller: Caller{ID: "ops", Role: "admin"}}))
+	defer httpServer.Close()
+
+	resp, err := http.Post(httpServer.URL, "application/json", strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"tools/list"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("HTTP sem tokens deveria ser recusado: %d", resp.StatusCode)
+	}
+}
@@ -0,0 +1,125 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// SchemaTool é uma ferramenta com o JSON Schema dos parâmetros; satisfaz a interface Tool
+// dos agentes
+type SchemaTool interface {
+	Name() string
+	Description() string
+	InputSchema() json.RawMessage
+	Execute(ctx context.Context, params map[string]interface{}) (interface{}, error)
+}
+
+// Tool adapta uma ferramenta do servidor MCP à interface Tool dos agentes
+type Tool struct {
+	client *Client
+	info   ToolInfo
+	name   string
+}
+
+// Name implementa Tool: o nome no servidor com o prefixo da configuração
+func (t *Tool) Name() string {
+	return t.name
+}
+
+// Description implementa Tool
+func (t *Tool) Description() string {
+	return t.info.Description
+}
+
+// InputSchema retorna o JSON Schema dos parâmetros declarado pelo servidor
+func (t *Tool) InputSchema() json.RawMessage {
+	return t.info.InputSchema
+}
+
+// Execute implementa Tool: retorna o conteúdo estruturado, quando houver, ou o texto do
+// resultado. Resultados marcados como erro pelo servidor viram erro.
+func (t *Tool) Execute(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+	result, err := t.client.CallTool(ctx, t.info.Name, params)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao chamar a ferramenta MCP %s: %w", t.name, err)
+	}
+	if result.IsError {
+		return nil, fmt.Errorf("ferramenta MCP %s falhou: %s", t.name, result.Text())
+	}
+	if result.StructuredContent != nil {
+		return result.StructuredContent, nil
+	}
+	return result.Text(), nil
+}
+
+// resourceSchema é o schema da ferramenta de leitura de recursos
+var resourceSchema = json.RawMessage(`{"type":"object","properties":{"uri":{"type":"string","description":"URI do recurso"}},"required":["uri"]}`)
+
+// resourceTool expõe a leitura dos recursos do servidor como uma ferramenta
+type resourceTool struct {
+	client *Client
+	name   string
+}
+
+func (t *resourceTool) Name() string {
+	return t.name
+}
+
+func (t *resourceTool) Description() string {
+	return fmt.Sprintf("Lê um recurso (pela URI) do servidor MCP %s", t.client.Name())
+}
+
+func (t *resourceTool) InputSchema() json.RawMessage {
+	return resourceSchema
+}
+
+func (t *resourceTool) Execute(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+	uri, _ := params["uri"].(string)
+	if uri == "" {
+		return nil, fmt.Errorf("parâmetro uri obrigatório")
+	}
+	contents, err := t.client.ReadResource(ctx, uri)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler o recurso MCP %s: %w", uri, err)
+	}
+	texts := make([]string, 0, len(contents))
+	for _, content := range contents {
+		if content.Text != "" {
+			texts = append(texts, content.Text)
+		}
+	}
+	if len(texts) == 0 {
+		return contents, nil
+	}
+	return strings.Join(texts, "\n"), nil
+}
+
+// prefix retorna o prefixo dos nomes das ferramentas do servidor
+func (c *Client) prefix() string {
+	if c.config.ToolPrefix != "" {
+		return c.config.ToolPrefix
+	}
+	if c.config.Name == "" {
+		return ""
+	}
+	return c.config.Name + "."
+}
+
+// Tools lista as ferramentas do servidor adaptadas à interface Tool dos agentes. Se o servidor
+// expõe recursos, inclui a ferramenta "<prefixo>read_resource" para lê-los pela URI.
+func (c *Client) Tools(ctx context.Context) ([]SchemaTool, error) {
+	infos, err := c.ListTools(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tools := make([]SchemaTool, 0, len(infos)+1)
+	for _, info := range infos {
+		tools = append(tools, &Tool{client: c, info: info, name: c.prefix() + info.Name})
+	}
+	if c.Supports("resources") {
+		tools = append(tools, &resourceTool{client: c, name: c.prefix() + "read_resource"})
+	}
+	return tools, nil
+}
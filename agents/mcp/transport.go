@@ -0,0 +1,276 @@
+package mcp
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/suissa/HiveMind/agents/errs"
+)
+
+// request é uma requisição ou notificação JSON-RPC 2.0 (notificações não têm ID)
+type request struct {
+	JSONRPC string      `json:"jsonrpc"`
+	ID      *int64      `json:"id,omitempty"`
+	Method  string      `json:"method"`
+	Params  interface{} `json:"params,omitempty"`
+}
+
+// message é qualquer mensagem recebida: resposta, requisição ou notificação do servidor
+type message struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      *int64          `json:"id,omitempty"`
+	Method  string          `json:"method,omitempty"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *RPCError       `json:"error,omitempty"`
+}
+
+// RPCError é um erro JSON-RPC devolvido pelo servidor
+type RPCError struct {
+	Code    int             `json:"code"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data,omitempty"`
+}
+
+func (e *RPCError) Error() string {
+	return fmt.Sprintf("erro MCP %d: %s", e.Code, e.Message)
+}
+
+// transport envia mensagens JSON-RPC ao servidor
+type transport interface {
+	call(ctx context.Context, req request) (*message, error)
+	notify(ctx context.Context, req request) error
+	close() error
+}
+
+// streamTransport troca mensagens delimitadas por quebra de linha (transporte stdio)
+type streamTransport struct {
+	w       io.WriteCloser
+	writeMu sync.Mutex
+	pending map[int64]chan *message
+	mu      sync.Mutex
+	done    chan struct{}
+	err     error // Motivo do fim da leitura, válido após done
+}
+
+func newStreamTransport(r io.Reader, w io.WriteCloser) *streamTransport {
+	t := &streamTransport{w: w, pending: make(map[int64]chan *message), done: make(chan struct{})}
+	go t.read(r)
+	return t
+}
+
+// read entrega as respostas às chamadas pendentes e atende às requisições do servidor
+func (t *streamTransport) read(r io.Reader) {
+	reader := bufio.NewReader(r)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if len(bytes.TrimSpace(line)) > 0 {
+			var msg message
+			if jsonErr := json.Unmarshal(line, &msg); jsonErr != nil {
+				log.Printf("⚠️ MCP: mensagem inválida do servidor: %v", jsonErr)
+			} else {
+				t.dispatch(&msg)
+			}
+		}
+		if err != nil {
+			t.mu.Lock()
+			t.err = fmt.Errorf("conexão com o servidor MCP encerrada: %v", err)
+			t.mu.Unlock()
+			close(t.done)
+			return
+		}
+	}
+}
+
+func (t *streamTransport) dispatch(msg *message) {
+	if msg.Method != "" {
+		// Requisições do servidor: responde ao ping e recusa as demais; notificações são ignoradas
+		if msg.ID != nil {
+			reply := map[string]interface{}{"jsonrpc": "2.0", "id": *msg.ID, "result": map[string]interface{}{}}
+			if msg.Method != "ping" {
+				delete(reply, "result")
+				reply["error"] = RPCError{Code: -32601, Message: "método não suportado: " + msg.Method}
+			}
+			t.write(reply)
+		}
+		return
+	}
+	if msg.ID == nil {
+		return
+	}
+	t.mu.Lock()
+	ch, ok := t.pending[*msg.ID]
+	delete(t.pending, *msg.ID)
+	t.mu.Unlock()
+	if ok {
+		ch <- msg
+	}
+}
+
+func (t *streamTransport) write(v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("erro ao serializar mensagem MCP: %v", err)
+	}
+	t.writeMu.Lock()
+	defer t.writeMu.Unlock()
+	if _, err := t.w.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("erro ao enviar mensagem MCP: %v", err)
+	}
+	return nil
+}
+
+func (t *streamTransport) call(ctx context.Context, req request) (*message, error) {
+	ch := make(chan *message, 1)
+	t.mu.Lock()
+	t.pending[*req.ID] = ch
+	t.mu.Unlock()
+	defer func() {
+		t.mu.Lock()
+		delete(t.pending, *req.ID)
+		t.mu.Unlock()
+	}()
+
+	if err := t.write(req); err != nil {
+		return nil, err
+	}
+	select {
+	case msg := <-ch:
+		return msg, nil
+	case <-t.done:
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		return nil, t.err
+	case <-ctx.Done():
+		return nil, errs.FromContext("mcp."+req.Method, ctx.Err())
+	}
+}
+
+func (t *streamTransport) notify(ctx context.Context, req request) error {
+	return t.write(req)
+}
+
+func (t *streamTransport) close() error {
+	return t.w.Close()
+}
+
+// httpTransport implementa o transporte HTTP (streamable HTTP): cada mensagem é um POST e a
+// resposta vem como JSON ou como um stream de eventos (SSE)
+type httpTransport struct {
+	url     string
+	headers map[string]string
+	client  *http.Client
+	session string // Mcp-Session-Id devolvido na inicialização
+	mu      sync.Mutex
+}
+
+func (t *httpTransport) post(ctx context.Context, req request) (*http.Response, error) {
+	data, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao serializar mensagem MCP: %v", err)
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, t.url, bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("erro ao criar requisição MCP: %v", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Accept", "application/json, text/event-stream")
+	for key, value := range t.headers {
+		httpReq.Header.Set(key, value)
+	}
+	t.mu.Lock()
+	if t.session != "" {
+		httpReq.Header.Set("Mcp-Session-Id", t.session)
+	}
+	t.mu.Unlock()
+
+	resp, err := t.client.Do(httpReq)
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, errs.FromContext("mcp."+req.Method, ctxErr)
+		}
+		return nil, fmt.Errorf("erro ao chamar o servidor MCP: %v", err)
+	}
+	if resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		resp.Body.Close()
+		return nil, errs.New(errs.KindForStatus(resp.StatusCode), "mcp."+req.Method, "servidor MCP respondeu %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+	if session := resp.Header.Get("Mcp-Session-Id"); session != "" {
+		t.mu.Lock()
+		t.session = session
+		t.mu.Unlock()
+	}
+	return resp, nil
+}
+
+func (t *httpTransport) call(ctx context.Context, req request) (*message, error) {
+	resp, err := t.post(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if !strings.HasPrefix(resp.Header.Get("Content-Type"), "text/event-stream") {
+		var msg message
+		if err := json.NewDecoder(resp.Body).Decode(&msg); err != nil {
+			return nil, fmt.Errorf("resposta MCP inválida: %v", err)
+		}
+		return &msg, nil
+	}
+
+	// No stream, a resposta é o evento com o ID da requisição; os demais são notificações
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	var data strings.Builder
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "data:") {
+			data.WriteString(strings.TrimSpace(strings.TrimPrefix(line, "data:")))
+			continue
+		}
+		if line != "" || data.Len() == 0 {
+			continue
+		}
+		if msg, ok := responseTo(req, data.String()); ok {
+			return msg, nil
+		}
+		data.Reset()
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao ler o stream MCP: %v", err)
+	}
+	if msg, ok := responseTo(req, data.String()); ok {
+		return msg, nil
+	}
+	return nil, fmt.Errorf("stream MCP encerrado sem resposta para %s", req.Method)
+}
+
+// responseTo decodifica o evento e informa se ele é a resposta à requisição
+func responseTo(req request, data string) (*message, bool) {
+	var msg message
+	if json.Unmarshal([]byte(data), &msg) != nil || msg.Method != "" || msg.ID == nil || *msg.ID != *req.ID {
+		return nil, false
+	}
+	return &msg, true
+}
+
+func (t *httpTransport) notify(ctx context.Context, req request) error {
+	resp, err := t.post(ctx, req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+func (t *httpTransport) close() error {
+	return nil
+}
@@ -0,0 +1,53 @@
+package agents
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/suissa/HiveMind/agents/mcp"
+)
+
+// registryBackend expõe as ferramentas do ToolRegistry ao servidor MCP. As permissões, as
+// políticas e os eventos de auditoria são os mesmos das chamadas feitas pelos agentes, mas,
+// ao contrário dos agentes, clientes externos não recebem nenhuma ferramenta sem matriz de
+// permissões carregada.
+type registryBackend struct {
+	registry *ToolRegistry
+}
+
+// Tools lista as ferramentas que o chamador pode usar
+func (b *registryBackend) Tools(caller mcp.Caller) []mcp.ToolInfo {
+	if !b.registry.hasPermissions() {
+		return []mcp.ToolInfo{}
+	}
+	names := b.registry.Allowed(caller)
+	infos := make([]mcp.ToolInfo, 0, len(names))
+	for _, name := range names {
+		tool, ok := b.registry.Get(name)
+		if !ok {
+			continue
+		}
+		info := mcp.ToolInfo{Name: name, Description: tool.Description()}
+		if schema, ok := b.registry.Schema(name); ok {
+			info.InputSchema = json.RawMessage(schema)
+		}
+		infos = append(infos, info)
+	}
+	return infos
+}
+
+// Call executa a ferramenta em nome do chamador
+func (b *registryBackend) Call(ctx context.Context, caller mcp.Caller, name string, arguments map[string]interface{}) (interface{}, error) {
+	if !b.registry.hasPermissions() {
+		b.registry.emit(EventToolDenied, caller, name, nil)
+		return nil, fmt.Errorf("%w: %s (sem matriz de permissões para clientes MCP)", ErrToolDenied, name)
+	}
+	return b.registry.Execute(ctx, caller, name, arguments)
+}
+
+// NewMCPServer cria um servidor MCP que expõe as ferramentas do registro a assistentes
+// externos, respeitando a matriz de permissões para a identidade de cada cliente
+func NewMCPServer(registry *ToolRegistry, config mcp.ServerConfig) *mcp.Server {
+	return mcp.NewServer(&registryBackend{registry: registry}, config)
+}
@@ -0,0 +1,317 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/weaviate/weaviate/entities/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/suissa/HiveMind/agents/backup"
+	"github.com/suissa/HiveMind/agents/errs"
+	"github.com/suissa/HiveMind/agents/tenant"
+)
+
+// Armazenamentos incluídos no backup
+const (
+	BackendRedis    = "redis"
+	BackendMongoDB  = "mongodb"
+	BackendWeaviate = "weaviate"
+)
+
+// Backends lista os armazenamentos na ordem em que são exportados e restaurados
+var Backends = []string{BackendRedis, BackendMongoDB, BackendWeaviate}
+
+// BackupOptions seleciona o que é exportado
+type BackupOptions struct {
+	Backends []string // Armazenamentos exportados (vazio exporta todos)
+}
+
+// RestoreOptions permite restaurar apenas parte de um backup
+type RestoreOptions struct {
+	Backends []string // Armazenamentos restaurados (vazio restaura todos os do arquivo)
+	AgentIDs []string // Restaura apenas as memórias destes agentes (vazio restaura todas)
+}
+
+// RestoreReport registra quantos registros foram restaurados em cada armazenamento
+type RestoreReport struct {
+	Namespace string         `json:"namespace"`
+	Restored  map[string]int `json:"restored"`
+}
+
+// redisRecord é uma chave do Redis exportada com DUMP, sem o prefixo do tenant
+type redisRecord struct {
+	Key   string `json:"key"`
+	TTLMs int64  `json:"ttl_ms"` // 0 sem expiração
+	Dump  []byte `json:"dump"`
+}
+
+// selected indica se o valor está entre os selecionados (lista vazia seleciona todos)
+func selected(values []string, value string) bool {
+	if len(values) == 0 {
+		return true
+	}
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// Backup exporta as chaves do Redis, os documentos do MongoDB e os objetos do Weaviate do
+// tenant do contexto para um único arquivo. O manifesto, com contagem e checksum de cada
+// seção, é gravado por último e marca o backup como completo.
+func (m *HybridMemoryManager) Backup(ctx context.Context, w io.Writer, opts BackupOptions) (*backup.Manifest, error) {
+	ctx = m.scope(ctx)
+	for _, b := range opts.Backends {
+		if !selected(Backends, b) {
+			return nil, errs.New(errs.ErrValidation, "memory.Backup", "armazenamento desconhecido: %s", b)
+		}
+	}
+
+	archive := backup.NewWriter(w, tenant.FromContext(ctx))
+	exporters := map[string]func(context.Context) ([]json.RawMessage, error){
+		BackendRedis:    m.shortTerm.export,
+		BackendMongoDB:  m.longTerm.export,
+		BackendWeaviate: m.semantic.export,
+	}
+	for _, backend := range Backends {
+		if !selected(opts.Backends, backend) {
+			continue
+		}
+		records, err := exporters[backend](ctx)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao exportar %s: %w", backend, err)
+		}
+		if err := archive.WriteSection(backend, records); err != nil {
+			return nil, err
+		}
+	}
+	return archive.Close()
+}
+
+// Restore importa um backup verificado para o tenant do contexto (ou, se o contexto não
+// definir um, para o tenant de origem do backup). Registros existentes são substituídos.
+func (m *HybridMemoryManager) Restore(ctx context.Context, r io.Reader, opts RestoreOptions) (*RestoreReport, error) {
+	archive, err := backup.Read(r)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := tenant.Lookup(ctx); !ok && archive.Manifest.Namespace != "" {
+		ctx = tenant.WithTenant(ctx, archive.Manifest.Namespace)
+	}
+	ctx = m.scope(ctx)
+
+	importers := map[string]func(context.Context, []json.RawMessage, []string) (int, error){
+		BackendRedis:    m.shortTerm.restore,
+		BackendMongoDB:  m.longTerm.restore,
+		BackendWeaviate: m.semantic.restore,
+	}
+	report := &RestoreReport{Namespace: tenant.FromContext(ctx), Restored: make(map[string]int)}
+	for _, backend := range Backends {
+		if !selected(opts.Backends, backend) {
+			continue
+		}
+		if _, ok := archive.Manifest.Section(backend); !ok {
+			if len(opts.Backends) > 0 {
+				return report, errs.New(errs.ErrValidation, "memory.Restore", "backup sem a seção %s", backend)
+			}
+			continue
+		}
+		restored, err := importers[backend](ctx, archive.Records(backend), opts.AgentIDs)
+		report.Restored[backend] = restored
+		if err != nil {
+			return report, fmt.Errorf("erro ao restaurar %s: %w", backend, err)
+		}
+	}
+	return report, nil
+}
+
+// export exporta as chaves de memória, agente e tag do tenant do contexto
+func (m *RedisMemoryManager) export(ctx context.Context) ([]json.RawMessage, error) {
+	prefix := m.key(ctx, "")
+	var records []json.RawMessage
+	for _, pattern := range []string{"memory:*", "agent:*", "tag:*"} {
+		iter := m.client.Scan(ctx, 0, m.key(ctx, "%s", pattern), 100).Iterator()
+		for iter.Next(ctx) {
+			key := iter.Val()
+			dump, err := m.client.Dump(ctx, key).Result()
+			if err == redis.Nil {
+				continue // Expirou durante o backup
+			}
+			if err != nil {
+				return nil, fmt.Errorf("erro ao exportar chave %s: %w", key, err)
+			}
+			ttl, err := m.client.PTTL(ctx, key).Result()
+			if err != nil {
+				return nil, fmt.Errorf("erro ao recuperar TTL de %s: %w", key, err)
+			}
+
+			record := redisRecord{Key: strings.TrimPrefix(key, prefix), Dump: []byte(dump)}
+			if ttl > 0 {
+				record.TTLMs = ttl.Milliseconds()
+			}
+			data, err := json.Marshal(record)
+			if err != nil {
+				return nil, fmt.Errorf("erro ao serializar chave %s: %w", key, err)
+			}
+			records = append(records, data)
+		}
+		if err := iter.Err(); err != nil {
+			return nil, fmt.Errorf("erro ao percorrer chaves: %w", err)
+		}
+	}
+	return records, nil
+}
+
+// restore importa as chaves no tenant do contexto, substituindo as existentes
+func (m *RedisMemoryManager) restore(ctx context.Context, records []json.RawMessage, agentIDs []string) (int, error) {
+	restored := 0
+	for _, data := range records {
+		var record redisRecord
+		if err := json.Unmarshal(data, &record); err != nil {
+			return restored, fmt.Errorf("registro do Redis inválido: %w", err)
+		}
+		// As chaves têm o formato memory:<agente>:<id>, agent:<agente>:memories ou tag:<agente>:<tag>
+		if parts := strings.SplitN(record.Key, ":", 3); len(parts) < 2 || !selected(agentIDs, parts[1]) {
+			continue
+		}
+
+		ttl := time.Duration(record.TTLMs) * time.Millisecond
+		if err := m.client.RestoreReplace(ctx, m.key(ctx, "%s", record.Key), ttl, string(record.Dump)).Err(); err != nil {
+			return restored, fmt.Errorf("erro ao restaurar chave %s: %w", record.Key, err)
+		}
+		restored++
+	}
+	return restored, nil
+}
+
+// export exporta os documentos do tenant do contexto em Extended JSON
+func (m *MongoMemoryManager) export(ctx context.Context) ([]json.RawMessage, error) {
+	cursor, err := m.collection.Find(ctx, scoped(ctx, bson.M{}))
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar memórias: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var records []json.RawMessage
+	for cursor.Next(ctx) {
+		data, err := bson.MarshalExtJSON(cursor.Current, true, false)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao serializar memória: %w", err)
+		}
+		records = append(records, data)
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao iterar sobre resultados: %w", err)
+	}
+	return records, nil
+}
+
+// restore importa os documentos no tenant do contexto, substituindo os existentes
+func (m *MongoMemoryManager) restore(ctx context.Context, records []json.RawMessage, agentIDs []string) (int, error) {
+	restored := 0
+	for _, data := range records {
+		var doc bson.M
+		if err := bson.UnmarshalExtJSON(data, true, &doc); err != nil {
+			return restored, fmt.Errorf("documento do MongoDB inválido: %w", err)
+		}
+		if agentID, _ := doc["agent_id"].(string); !selected(agentIDs, agentID) {
+			continue
+		}
+
+		doc["tenant_id"] = tenant.FromContext(ctx)
+		_, err := m.collection.ReplaceOne(ctx, bson.M{"_id": doc["_id"]}, doc, options.Replace().SetUpsert(true))
+		if err != nil {
+			return restored, fmt.Errorf("erro ao restaurar memória %v: %w", doc["_id"], err)
+		}
+		restored++
+	}
+	return restored, nil
+}
+
+// export exporta os objetos da classe do tenant do contexto, com os vetores
+func (m *SemanticMemoryManager) export(ctx context.Context) ([]json.RawMessage, error) {
+	class, err := m.className(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var records []json.RawMessage
+	after := ""
+	for {
+		getter := m.client.Data().ObjectsGetter().
+			WithClassName(class).
+			WithVector().
+			WithLimit(100)
+		if after != "" {
+			getter = getter.WithAfter(after)
+		}
+		objects, err := getter.Do(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao listar objetos: %w", err)
+		}
+		if len(objects) == 0 {
+			return records, nil
+		}
+
+		for _, obj := range objects {
+			data, err := json.Marshal(obj)
+			if err != nil {
+				return nil, fmt.Errorf("erro ao serializar objeto: %w", err)
+			}
+			records = append(records, data)
+		}
+		after = objects[len(objects)-1].ID.String()
+	}
+}
+
+// restore importa os objetos na classe do tenant do contexto, substituindo os existentes
+func (m *SemanticMemoryManager) restore(ctx context.Context, records []json.RawMessage, agentIDs []string) (int, error) {
+	class, err := m.className(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	restored := 0
+	for _, data := range records {
+		var obj models.Object
+		if err := json.Unmarshal(data, &obj); err != nil {
+			return restored, fmt.Errorf("objeto do Weaviate inválido: %w", err)
+		}
+		properties, _ := obj.Properties.(map[string]interface{})
+		if agentID, _ := properties["agentId"].(string); !selected(agentIDs, agentID) {
+			continue
+		}
+
+		id := obj.ID.String()
+		exists, err := m.client.Data().Checker().WithClassName(class).WithID(id).Do(ctx)
+		if err != nil {
+			return restored, fmt.Errorf("erro ao verificar objeto %s: %w", id, err)
+		}
+		if exists {
+			if err := m.client.Data().Deleter().WithClassName(class).WithID(id).Do(ctx); err != nil {
+				return restored, fmt.Errorf("erro ao substituir objeto %s: %w", id, err)
+			}
+		}
+
+		_, err = m.client.Data().Creator().
+			WithClassName(class).
+			WithID(id).
+			WithProperties(properties).
+			WithVector([]float32(obj.Vector)).
+			Do(ctx)
+		if err != nil {
+			return restored, fmt.Errorf("erro ao restaurar objeto %s: %w", id, err)
+		}
+		restored++
+	}
+	return restored, nil
+}
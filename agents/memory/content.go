@@ -0,0 +1,81 @@
+package memory
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/suissa/HiveMind/agents/errs"
+)
+
+// SchemaKey é a chave do conteúdo que identifica o esquema do valor memorizado
+const SchemaKey = "$schema"
+
+// schemaTagPrefix é o prefixo da tag que marca as memórias de um esquema
+const schemaTagPrefix = "schema:"
+
+// SchemaTag retorna a tag das memórias do esquema, usada para buscá-las com SearchMemories
+func SchemaTag(schema string) string {
+	return schemaTagPrefix + schema
+}
+
+// Content converte o valor — uma struct com tags json ou um mapa — no conteúdo de uma memória
+// marcado com o esquema (ex.: "marketing.strategy/v1"). O conteúdo é gravado como JSON, então
+// os valores devem ser lidos de volta com Decode, e não com asserções de tipo.
+func Content(schema string, value interface{}) (map[string]interface{}, error) {
+	if schema == "" {
+		return nil, errs.New(errs.ErrValidation, "memory.Content", "esquema obrigatório")
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, errs.Wrap(errs.ErrValidation, "memory.Content", err, "erro ao serializar o valor do esquema %s", schema)
+	}
+	var content map[string]interface{}
+	if err := json.Unmarshal(data, &content); err != nil || content == nil {
+		return nil, errs.New(errs.ErrValidation, "memory.Content", "o valor do esquema %s deve ser um objeto JSON", schema)
+	}
+	content[SchemaKey] = schema
+	return content, nil
+}
+
+// Schema retorna o esquema do conteúdo da memória, ou vazio para as memórias sem esquema
+func Schema(m *Memory) string {
+	if !strings.Contains(m.Content, SchemaKey) {
+		return ""
+	}
+	var header struct {
+		Schema string `json:"$schema"`
+	}
+	if err := json.Unmarshal([]byte(m.Content), &header); err != nil {
+		return ""
+	}
+	return header.Schema
+}
+
+// Decode decodifica o conteúdo da memória em v. Com schema, as memórias de outro esquema (ou
+// sem esquema) são recusadas com errs.ErrValidation. As memórias com conteúdo movido para o
+// armazenamento de objetos devem ser resolvidas antes (HybridMemoryManager.FullContent).
+func Decode(m *Memory, schema string, v interface{}) error {
+	if schema != "" {
+		if found := Schema(m); found != schema {
+			return errs.New(errs.ErrValidation, "memory.Decode", "memória %s tem o esquema %q, não %q", m.ID, found, schema)
+		}
+	}
+	if err := json.Unmarshal([]byte(m.Content), v); err != nil {
+		return errs.Wrap(errs.ErrValidation, "memory.Decode", err, "erro ao decodificar a memória %s", m.ID)
+	}
+	return nil
+}
+
+// Latest retorna a memória mais recente do esquema, ou nil
+func Latest(memories []*Memory, schema string) *Memory {
+	var latest *Memory
+	for _, m := range memories {
+		if Schema(m) != schema {
+			continue
+		}
+		if latest == nil || m.Timestamp.After(latest.Timestamp) {
+			latest = m
+		}
+	}
+	return latest
+}
@@ -0,0 +1,120 @@
+package memory
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/suissa/HiveMind/agents/errs"
+)
+
+type strategyValue struct {
+	Name     string   `json:"name"`
+	Channels []string `json:"channels"`
+	Budget   float64  `json:"budget"`
+}
+
+// memoryOf grava o conteúdo como o CognitiveAgent grava: o mapa serializado em JSON
+func memoryOf(t *testing.T, id string, content map[string]interface{}, at time.Time) *Memory {
+	t.Helper()
+	data, err := json.Marshal(content)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &Memory{ID: id, Content: string(data), Timestamp: at}
+}
+
+func TestContentDecodeRoundTrip(t *testing.T) {
+	value := strategyValue{Name: "Inbound", Channels: []string{"blog", "email"}, Budget: 1500.5}
+	content, err := Content("marketing.strategy/v1", value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if content[SchemaKey] != "marketing.strategy/v1" {
+		t.Fatalf("conteúdo sem o esquema: %v", content)
+	}
+
+	m := memoryOf(t, "m1", content, time.Now())
+	if schema := Schema(m); schema != "marketing.strategy/v1" {
+		t.Fatalf("esquema inesperado: %q", schema)
+	}
+	var decoded strategyValue
+	if err := Decode(m, "marketing.strategy/v1", &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Name != value.Name || len(decoded.Channels) != 2 || decoded.Channels[1] != "email" || decoded.Budget != value.Budget {
+		t.Fatalf("valor decodificado inesperado: %+v", decoded)
+	}
+}
+
+func TestContentRejectsInvalidValues(t *testing.T) {
+	cases := map[string]struct {
+		schema string
+		value  interface{}
+	}{
+		"sem esquema":      {"", strategyValue{}},
+		"não é objeto":     {"s/v1", []string{"a"}},
+		"texto":            {"s/v1", "valor"},
+		"nulo":             {"s/v1", nil},
+		"não serializável": {"s/v1", map[string]interface{}{"f": func() {}}},
+	}
+	for name, c := range cases {
+		if _, err := Content(c.schema, c.value); !errors.Is(err, errs.ErrValidation) {
+			t.Errorf("%s: esperava ErrValidation, obtido %v", name, err)
+		}
+	}
+}
+
+func TestDecodeSchemaMismatch(t *testing.T) {
+	content, _ := Content("marketing.copy/v1", map[string]string{"title": "Olá"})
+	m := memoryOf(t, "m1", content, time.Now())
+	var v map[string]interface{}
+	if err := Decode(m, "marketing.strategy/v1", &v); !errors.Is(err, errs.ErrValidation) {
+		t.Fatalf("esperava ErrValidation para o esquema errado: %v", err)
+	}
+
+	plain := memoryOf(t, "m2", map[string]interface{}{"title": "Olá"}, time.Now())
+	if Schema(plain) != "" {
+		t.Fatal("memória sem esquema não deveria ter esquema")
+	}
+	if err := Decode(plain, "marketing.copy/v1", &v); !errors.Is(err, errs.ErrValidation) {
+		t.Fatalf("esperava ErrValidation para a memória sem esquema: %v", err)
+	}
+	// Sem esquema pedido, qualquer conteúdo JSON é decodificado
+	if err := Decode(plain, "", &v); err != nil || v["title"] != "Olá" {
+		t.Fatalf("decodificação inesperada: %v %v", v, err)
+	}
+
+	broken := &Memory{ID: "m3", Content: `{"$schema": "marketing.copy/v1", "title": `}
+	if err := Decode(broken, "", &v); !errors.Is(err, errs.ErrValidation) {
+		t.Fatalf("esperava ErrValidation para o JSON inválido: %v", err)
+	}
+}
+
+func TestLatest(t *testing.T) {
+	now := time.Now()
+	content := func(schema, name string) map[string]interface{} {
+		c, _ := Content(schema, strategyValue{Name: name})
+		return c
+	}
+	memories := []*Memory{
+		memoryOf(t, "old", content("s/v1", "antiga"), now.Add(-2*time.Hour)),
+		memoryOf(t, "other", content("s/v2", "outro esquema"), now.Add(time.Hour)),
+		memoryOf(t, "new", content("s/v1", "recente"), now),
+		memoryOf(t, "plain", map[string]interface{}{"name": "sem esquema"}, now.Add(2*time.Hour)),
+		memoryOf(t, "mid", content("s/v1", "intermediária"), now.Add(-time.Hour)),
+	}
+	if latest := Latest(memories, "s/v1"); latest == nil || latest.ID != "new" {
+		t.Fatalf("esperava a memória mais recente do esquema: %+v", latest)
+	}
+	if latest := Latest(memories, "s/v2"); latest == nil || latest.ID != "other" {
+		t.Fatalf("esperava a única memória do esquema s/v2: %+v", latest)
+	}
+	if latest := Latest(memories, "s/v3"); latest != nil {
+		t.Fatalf("esquema sem memórias deveria retornar nil: %+v", latest)
+	}
+	if latest := Latest(nil, "s/v1"); latest != nil {
+		t.Fatal("lista vazia deveria retornar nil")
+	}
+}
@@ -0,0 +1,216 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/weaviate/weaviate-go-client/v4/weaviate/filters"
+	"github.com/weaviate/weaviate-go-client/v4/weaviate/graphql"
+
+	"github.com/suissa/HiveMind/agents/errs"
+)
+
+// Padrões da detecção de memórias quase duplicadas, que é opcional (MemoryConfig.DedupThreshold)
+const (
+	DefaultDedupThreshold = 0.95 // Certeza mínima sugerida do Weaviate para considerar duplicada
+	DefaultDedupBoost     = 0.05 // Importância somada a cada repetição
+)
+
+// duplicate é o objeto do Weaviate mais similar a uma memória nova
+type duplicate struct {
+	objectID  string
+	memoryID  string
+	certainty float64
+}
+
+// findDuplicate busca, entre as memórias do mesmo agente, a mais similar ao conteúdo com
+// certeza mínima threshold. Retorna nil se não houver.
+func (m *SemanticMemoryManager) findDuplicate(ctx context.Context, memory *Memory, threshold float64) (*duplicate, error) {
+	class, err := m.className(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	vector, err := m.vector(ctx, memory.Content)
+	if err != nil {
+		return nil, err
+	}
+	where := filters.Where().
+		WithPath([]string{"agentId"}).
+		WithOperator(filters.Equal).
+		WithValueString(memory.AgentID)
+
+	search := m.client.GraphQL().Get().
+		WithClassName(class).
+		WithFields(
+			graphql.Field{Name: "memoryId"},
+			graphql.Field{Name: "_additional", Fields: []graphql.Field{{Name: "id"}, {Name: "certainty"}}},
+		).
+		WithWhere(where).
+		WithLimit(1)
+	if vector != nil {
+		search = search.WithNearVector(m.client.GraphQL().NearVectorArgBuilder().
+			WithVector(vector).
+			WithCertainty(float32(threshold)))
+	} else {
+		search = search.WithNearText(m.client.GraphQL().NearTextArgBuilder().
+			WithConcepts([]string{memory.Content}).
+			WithCertainty(float32(threshold)))
+	}
+	result, err := search.Do(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar memórias duplicadas: %w", err)
+	}
+	if len(result.Errors) > 0 {
+		return nil, fmt.Errorf("erro ao buscar memórias duplicadas: %s", result.Errors[0].Message)
+	}
+
+	get, _ := result.Data["Get"].(map[string]interface{})
+	items, _ := get[class].([]interface{})
+	if len(items) == 0 {
+		return nil, nil
+	}
+	item, _ := items[0].(map[string]interface{})
+	dup := &duplicate{}
+	dup.memoryID, _ = item["memoryId"].(string)
+	if additional, ok := item["_additional"].(map[string]interface{}); ok {
+		dup.objectID, _ = additional["id"].(string)
+		dup.certainty, _ = additional["certainty"].(float64)
+	}
+	if dup.memoryID == "" || dup.objectID == "" || dup.certainty < threshold {
+		return nil, nil
+	}
+	return dup, nil
+}
+
+// mergeObject atualiza no Weaviate a importância, as tags, os titulares e a data do objeto
+// de uma memória mesclada
+func (m *SemanticMemoryManager) mergeObject(ctx context.Context, objectID string, memory *Memory) error {
+	class, err := m.className(ctx)
+	if err != nil {
+		return err
+	}
+	err = m.client.Data().Updater().
+		WithClassName(class).
+		WithID(objectID).
+		WithProperties(map[string]interface{}{
+			"importance": memory.Importance,
+			"timestamp":  memory.Timestamp.Format(time.RFC3339),
+			"tags":       memory.Tags,
+			"subjects":   memory.Subjects,
+		}).
+		WithMerge().
+		Do(ctx)
+	if err != nil {
+		return fmt.Errorf("erro ao mesclar memória no Weaviate: %w", err)
+	}
+	return nil
+}
+
+// merge incorpora uma repetição à memória existente: a importância passa a ser a maior das
+// duas acrescida de boost (limitada a 1), tags e titulares são unidos e a data é renovada
+func merge(existing, incoming *Memory, boost float64) {
+	existing.Importance = min(max(existing.Importance, incoming.Importance)+boost, 1)
+	existing.Tags = union(existing.Tags, incoming.Tags)
+	existing.Subjects = union(existing.Subjects, incoming.Subjects)
+	existing.Timestamp = incoming.Timestamp
+	if existing.Timestamp.IsZero() {
+		existing.Timestamp = time.Now()
+	}
+	existing.Merged++
+}
+
+// deduplicable indica se a memória pode ser mesclada a uma quase duplicada. Valores com
+// esquema nunca são mesclados: duas versões de uma estratégia são parecidas, mas a nova
+// substitui a antiga e não pode ser descartada.
+func deduplicable(memory *Memory) bool {
+	return Schema(memory) == ""
+}
+
+// union retorna os itens de a seguidos dos itens de b ausentes em a
+func union(a, b []string) []string {
+	seen := make(map[string]bool, len(a))
+	for _, item := range a {
+		seen[item] = true
+	}
+	for _, item := range b {
+		if !seen[item] {
+			seen[item] = true
+			a = append(a, item)
+		}
+	}
+	return a
+}
+
+// mergeDuplicate mescla a memória nova com uma quase duplicada do mesmo agente, se houver,
+// e retorna se a mesclagem ocorreu. Em caso afirmativo, memory passa a conter a memória
+// mesclada (com o ID da existente). Falhas na busca por duplicadas não impedem a gravação
+// e aparecem apenas nas métricas.
+func (m *HybridMemoryManager) mergeDuplicate(ctx context.Context, t *tracker, memory *Memory) (bool, error) {
+	var dup *duplicate
+	err := t.run(BackendWeaviate, func() (err error) {
+		dup, err = m.semantic.findDuplicate(ctx, memory, m.config.DedupThreshold)
+		return err
+	})
+	if err != nil || dup == nil || dup.memoryID == memory.ID {
+		return false, nil
+	}
+
+	// A memória canônica está no Redis (curto prazo) ou no MongoDB (longo prazo)
+	var existing *Memory
+	backend := BackendRedis
+	err = t.run(BackendRedis, func() (err error) {
+		existing, err = m.shortTerm.GetMemory(ctx, memory.AgentID, dup.memoryID)
+		return err
+	})
+	if err != nil {
+		backend = BackendMongoDB
+		err = t.run(BackendMongoDB, func() (err error) {
+			existing, err = m.longTerm.GetMemory(ctx, memory.AgentID, dup.memoryID)
+			return err
+		})
+	}
+	if errors.Is(err, errs.ErrNotFound) {
+		// Resta apenas o objeto semântico (a memória de curto prazo expirou)
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("erro ao recuperar memória duplicada: %w", err)
+	}
+
+	boost := m.config.DedupBoost
+	if boost <= 0 {
+		boost = DefaultDedupBoost
+	}
+	merge(existing, memory, boost)
+
+	switch {
+	case backend == BackendRedis && existing.Importance >= m.config.ImportanceThreshold:
+		// A repetição tornou a memória importante: ela passa para o longo prazo
+		existing.Type = LongTerm
+		if err := t.run(BackendMongoDB, func() error { return m.longTerm.StoreMemory(ctx, existing) }); err != nil {
+			return false, fmt.Errorf("erro ao consolidar memória mesclada: %w", err)
+		}
+		if err := t.run(BackendRedis, func() error { return m.shortTerm.DeleteMemory(ctx, existing.AgentID, existing.ID) }); err != nil {
+			return false, fmt.Errorf("erro ao remover memória consolidada: %w", err)
+		}
+	case backend == BackendRedis:
+		// Regrava com o TTL completo: a repetição prolonga a memória de curto prazo
+		if err := t.run(BackendRedis, func() error { return m.shortTerm.StoreMemory(ctx, existing) }); err != nil {
+			return false, fmt.Errorf("erro ao gravar memória mesclada: %w", err)
+		}
+	default:
+		if err := t.run(BackendMongoDB, func() error { return m.longTerm.UpdateMemory(ctx, existing) }); err != nil {
+			return false, fmt.Errorf("erro ao gravar memória mesclada: %w", err)
+		}
+	}
+	if err := t.run(BackendWeaviate, func() error { return m.semantic.mergeObject(ctx, dup.objectID, existing) }); err != nil {
+		return false, err
+	}
+
+	duplicatesMerged.Inc()
+	*memory = *existing
+	return true, nil
+}
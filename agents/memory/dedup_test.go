@@ -0,0 +1,90 @@
+package memory
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestMerge(t *testing.T) {
+	seen := time.Date(2026, 1, 10, 9, 0, 0, 0, time.UTC)
+	cases := []struct {
+		name       string
+		existing   Memory
+		incoming   Memory
+		importance float64
+	}{
+		{"existente mais importante", Memory{Importance: 0.6}, Memory{Importance: 0.4}, 0.65},
+		{"nova mais importante", Memory{Importance: 0.4}, Memory{Importance: 0.8}, 0.85},
+		{"limitada a 1", Memory{Importance: 0.98}, Memory{Importance: 0.5}, 1},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			existing := c.existing
+			existing.ID, existing.Content = "mem-1", "O público prefere vídeos curtos"
+			existing.Tags, existing.Subjects = []string{"research", "video"}, []string{"ana"}
+			incoming := c.incoming
+			incoming.ID, incoming.Content = "mem-2", "O público prefere vídeos curtos!"
+			incoming.Tags, incoming.Subjects, incoming.Timestamp = []string{"video", "tiktok"}, []string{"bruno", "ana"}, seen
+
+			merge(&existing, &incoming, DefaultDedupBoost)
+			if diff := existing.Importance - c.importance; diff > 1e-9 || diff < -1e-9 {
+				t.Fatalf("importância inesperada: %v", existing.Importance)
+			}
+			if existing.ID != "mem-1" || existing.Content != "O público prefere vídeos curtos" {
+				t.Fatalf("a memória existente deveria ser mantida: %+v", existing)
+			}
+			if !reflect.DeepEqual(existing.Tags, []string{"research", "video", "tiktok"}) ||
+				!reflect.DeepEqual(existing.Subjects, []string{"ana", "bruno"}) {
+				t.Fatalf("união inesperada: %v %v", existing.Tags, existing.Subjects)
+			}
+			if !existing.Timestamp.Equal(seen) || existing.Merged != 1 {
+				t.Fatalf("data ou contagem inesperadas: %v %d", existing.Timestamp, existing.Merged)
+			}
+		})
+	}
+
+	// Sem data na repetição, a memória mesclada recebe a data atual
+	existing := Memory{Merged: 2}
+	merge(&existing, &Memory{}, DefaultDedupBoost)
+	if existing.Timestamp.IsZero() || existing.Merged != 3 {
+		t.Fatalf("mesclagem inesperada: %+v", existing)
+	}
+}
+
+func TestUnion(t *testing.T) {
+	cases := []struct {
+		a, b, expected []string
+	}{
+		{nil, nil, nil},
+		{nil, []string{"x"}, []string{"x"}},
+		{[]string{"x"}, nil, []string{"x"}},
+		{[]string{"x", "y"}, []string{"y", "z", "z"}, []string{"x", "y", "z"}},
+	}
+	for _, c := range cases {
+		if got := union(c.a, c.b); !reflect.DeepEqual(got, c.expected) {
+			t.Fatalf("union(%v, %v) = %v, esperava %v", c.a, c.b, got, c.expected)
+		}
+	}
+}
+
+func TestDeduplicable(t *testing.T) {
+	content, err := Content("marketing.strategy/v1", map[string]interface{}{"name": "Lançamento"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(content)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if deduplicable(&Memory{Content: string(data)}) {
+		t.Fatal("valores com esquema não deveriam ser mesclados")
+	}
+	if !deduplicable(&Memory{Content: "O público prefere vídeos curtos"}) {
+		t.Fatal("memórias de texto deveriam ser mescladas")
+	}
+	if DefaultMemoryConfig().DedupThreshold != 0 {
+		t.Fatal("a detecção de duplicadas deveria ser opcional")
+	}
+}
@@ -0,0 +1,143 @@
+package memory
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+
+	"github.com/suissa/HiveMind/agents/metrics"
+)
+
+// DefaultEmbeddingCacheTTL é a validade padrão de um embedding no cache
+const DefaultEmbeddingCacheTTL = 30 * 24 * time.Hour
+
+// embeddingCacheRequests conta as consultas ao cache de embeddings por resultado (hit, miss
+// ou error). Cada miss é uma chamada a menos ao modelo de embeddings quando vira hit.
+var embeddingCacheRequests = metrics.Default.Counter("hivemind_embedding_cache_requests_total",
+	"Consultas ao cache de embeddings por resultado", "result")
+
+// Embedder calcula os vetores (embeddings) dos textos, na mesma ordem. Com um Embedder o
+// gerenciador envia os vetores ao Weaviate em vez de deixá-lo vetorizar o conteúdo.
+type Embedder interface {
+	Embed(ctx context.Context, texts []string) ([][]float32, error)
+}
+
+// EmbedderFunc adapta uma função a Embedder
+type EmbedderFunc func(ctx context.Context, texts []string) ([][]float32, error)
+
+// Embed implementa Embedder
+func (f EmbedderFunc) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	return f(ctx, texts)
+}
+
+// EmbeddingCache guarda no Redis os embeddings calculados, pelo hash do conteúdo e do
+// modelo: memorizar de novo um conteúdo idêntico não chama o modelo de embeddings. O cache
+// é compartilhado entre os tenants, já que o vetor depende apenas do conteúdo.
+type EmbeddingCache struct {
+	embedder Embedder
+	client   *redis.Client
+	model    string
+	ttl      time.Duration
+}
+
+// NewEmbeddingCache cria o cache para o embedder; o modelo separa os vetores de modelos
+// diferentes e TTL zero usa DefaultEmbeddingCacheTTL
+func NewEmbeddingCache(client *redis.Client, embedder Embedder, model string, ttl time.Duration) *EmbeddingCache {
+	if ttl <= 0 {
+		ttl = DefaultEmbeddingCacheTTL
+	}
+	return &EmbeddingCache{embedder: embedder, client: client, model: model, ttl: ttl}
+}
+
+// EmbeddingKey retorna a chave do embedding do conteúdo no cache
+func EmbeddingKey(model, content string) string {
+	sum := sha256.Sum256([]byte(model + "\x00" + content))
+	return "hivemind:embedding:" + hex.EncodeToString(sum[:])
+}
+
+// Embed implementa Embedder: consulta o cache e calcula apenas os textos ausentes. Uma
+// falha do Redis não impede o cálculo; os vetores só deixam de ser reaproveitados.
+func (c *EmbeddingCache) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	vectors := make([][]float32, len(texts))
+	keys := make([]string, len(texts))
+	for i, text := range texts {
+		keys[i] = EmbeddingKey(c.model, text)
+	}
+
+	cached, err := c.client.MGet(ctx, keys...).Result()
+	if err != nil {
+		embeddingCacheRequests.Add(float64(len(texts)), "error")
+		cached = nil
+	}
+	// Textos repetidos na mesma chamada são calculados uma única vez
+	missing := make(map[string][]int)
+	var pending []string
+	for i := range texts {
+		if i < len(cached) {
+			if value, ok := cached[i].(string); ok {
+				if vector, ok := decodeVector(value); ok {
+					vectors[i] = vector
+					embeddingCacheRequests.Inc("hit")
+					continue
+				}
+			}
+		}
+		if err == nil {
+			embeddingCacheRequests.Inc("miss")
+		}
+		if _, ok := missing[keys[i]]; !ok {
+			pending = append(pending, texts[i])
+		}
+		missing[keys[i]] = append(missing[keys[i]], i)
+	}
+	if len(pending) == 0 {
+		return vectors, nil
+	}
+
+	computed, err := c.embedder.Embed(ctx, pending)
+	if err != nil {
+		return nil, err
+	}
+	if len(computed) != len(pending) {
+		return nil, fmt.Errorf("o modelo de embeddings retornou %d vetores para %d textos", len(computed), len(pending))
+	}
+	pipe := c.client.Pipeline()
+	for n, text := range pending {
+		key := EmbeddingKey(c.model, text)
+		for _, i := range missing[key] {
+			vectors[i] = computed[n]
+		}
+		pipe.Set(ctx, key, encodeVector(computed[n]), c.ttl)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		embeddingCacheRequests.Add(float64(len(pending)), "error")
+	}
+	return vectors, nil
+}
+
+// encodeVector serializa o vetor em float32 little-endian
+func encodeVector(vector []float32) []byte {
+	data := make([]byte, 4*len(vector))
+	for i, value := range vector {
+		binary.LittleEndian.PutUint32(data[4*i:], math.Float32bits(value))
+	}
+	return data
+}
+
+// decodeVector lê um vetor serializado por encodeVector
+func decodeVector(value string) ([]float32, bool) {
+	if len(value) == 0 || len(value)%4 != 0 {
+		return nil, false
+	}
+	vector := make([]float32, len(value)/4)
+	for i := range vector {
+		vector[i] = math.Float32frombits(binary.LittleEndian.Uint32([]byte(value[4*i : 4*i+4])))
+	}
+	return vector, true
+}
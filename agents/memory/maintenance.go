@@ -0,0 +1,52 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/suissa/HiveMind/agents/errs"
+	"github.com/suissa/HiveMind/agents/maintenance"
+)
+
+// Maintainer é implementado pelos gerenciadores que executam a própria manutenção
+type Maintainer interface {
+	StartMaintenance(ctx context.Context, observer maintenance.Observer) (*maintenance.Scheduler, error)
+	StopMaintenance()
+}
+
+// StartMaintenance inicia em segundo plano a manutenção configurada em
+// MemoryConfig.Maintenance e retorna o agendador, onde os agentes são agendados com
+// Schedule. Retorna nil se a manutenção não estiver configurada. O progresso de cada
+// manutenção é entregue ao observer, e a manutenção para com o contexto, StopMaintenance
+// ou Close.
+func (m *HybridMemoryManager) StartMaintenance(ctx context.Context, observer maintenance.Observer) (*maintenance.Scheduler, error) {
+	if m.config.Maintenance == nil {
+		return nil, nil
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.stopMaintenance != nil {
+		return nil, fmt.Errorf("manutenção já iniciada")
+	}
+
+	scheduler, err := maintenance.New(m, *m.config.Maintenance)
+	if err != nil {
+		return nil, errs.Wrap(errs.ErrValidation, "memory.StartMaintenance", err, "configuração de manutenção inválida")
+	}
+	scheduler.OnProgress(observer)
+
+	ctx, cancel := context.WithCancel(m.scope(ctx))
+	m.stopMaintenance = cancel
+	go scheduler.Run(ctx)
+	return scheduler, nil
+}
+
+// StopMaintenance interrompe a manutenção iniciada por StartMaintenance
+func (m *HybridMemoryManager) StopMaintenance() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.stopMaintenance != nil {
+		m.stopMaintenance()
+		m.stopMaintenance = nil
+	}
+}
@@ -2,40 +2,57 @@ package memory
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sync"
+	"time"
+
+	"github.com/suissa/HiveMind/agents/blob"
+	"github.com/suissa/HiveMind/agents/errs"
+	"github.com/suissa/HiveMind/agents/outbox"
+	"github.com/suissa/HiveMind/agents/pii"
+	"github.com/suissa/HiveMind/agents/redact"
+	"github.com/suissa/HiveMind/agents/tenant"
 )
 
 // HybridMemoryManager combina Redis (curto prazo), MongoDB (longo prazo) e Weaviate (semântica)
 type HybridMemoryManager struct {
-	shortTerm *RedisMemoryManager
-	longTerm  *MongoMemoryManager
-	semantic  *SemanticMemoryManager
-	config    *MemoryConfig
+	shortTerm  *RedisMemoryManager
+	longTerm   *MongoMemoryManager
+	semantic   *SemanticMemoryManager
+	config     *MemoryConfig
+	anonymizer *pii.Anonymizer
+	observer   OperationObserver
+	blobs      *blob.Manager
+
+	stopMaintenance context.CancelFunc
+	mu              sync.Mutex
 }
 
 // NewHybridMemoryManager cria um novo gerenciador de memória híbrido
 func NewHybridMemoryManager(ctx context.Context, config *MemoryConfig) (*HybridMemoryManager, error) {
 	// Inicializa Redis para memória de curto prazo
-	shortTerm, err := NewRedisMemoryManager(config.RedisURL)
+	shortTerm, err := NewRedisMemoryManager(ctx, config.RedisURL)
 	if err != nil {
-		return nil, fmt.Errorf("erro ao inicializar Redis: %v", err)
+		return nil, fmt.Errorf("erro ao inicializar Redis: %w", err)
 	}
 
 	// Inicializa MongoDB para memória de longo prazo
 	longTerm, err := NewMongoMemoryManager(ctx, config.MongoURL, config.MongoDB, config.Collection)
 	if err != nil {
-		return nil, fmt.Errorf("erro ao inicializar MongoDB: %v", err)
+		return nil, fmt.Errorf("erro ao inicializar MongoDB: %w", err)
 	}
 
 	// Inicializa Weaviate para memória semântica
-	semantic, err := NewSemanticMemoryManager(&SemanticMemoryConfig{
+	semantic, err := NewSemanticMemoryManager(ctx, &SemanticMemoryConfig{
 		WeaviateURL: config.WeaviateURL,
 		APIKey:      config.WeaviateAPIKey,
 		Class:       config.WeaviateClass,
 		BatchSize:   config.WeaviateBatchSize,
+		Aliases:     NewRedisClassAliases(shortTerm.client),
 	})
 	if err != nil {
-		return nil, fmt.Errorf("erro ao inicializar Weaviate: %v", err)
+		return nil, fmt.Errorf("erro ao inicializar Weaviate: %w", err)
 	}
 
 	return &HybridMemoryManager{
@@ -46,23 +63,132 @@ func NewHybridMemoryManager(ctx context.Context, config *MemoryConfig) (*HybridM
 	}, nil
 }
 
+// scope aplica o tenant configurado quando o contexto não define um namespace
+func (m *HybridMemoryManager) scope(ctx context.Context) context.Context {
+	if _, ok := tenant.Lookup(ctx); ok || m.config.Tenant == "" {
+		return ctx
+	}
+	return tenant.WithTenant(ctx, m.config.Tenant)
+}
+
+// SetAnonymizer pseudonimiza os dados pessoais do conteúdo das memórias antes da persistência
+func (m *HybridMemoryManager) SetAnonymizer(anonymizer *pii.Anonymizer) {
+	m.anonymizer = anonymizer
+}
+
+// SetEmbedder passa a vetorizar as memórias com o embedder do modelo informado, guardando no
+// Redis os embeddings calculados: conteúdo idêntico não volta a chamar o modelo
+func (m *HybridMemoryManager) SetEmbedder(embedder Embedder, model string) {
+	m.semantic.SetEmbedder(NewEmbeddingCache(m.shortTerm.client, embedder, model, m.config.EmbeddingCacheTTL), model)
+}
+
+// AddEmbedder registra o embedder de outro modelo, também com o cache de embeddings, para as
+// classes que uma reindexação feita por outra instância passou para esse modelo (ver Reindex)
+func (m *HybridMemoryManager) AddEmbedder(embedder Embedder, model string) {
+	m.semantic.AddEmbedder(NewEmbeddingCache(m.shortTerm.client, embedder, model, m.config.EmbeddingCacheTTL), model)
+}
+
+// SetBlobs guarda no armazenamento de objetos os conteúdos que passam do limite do
+// gerenciador: a memória persiste só a prévia com a referência, e FullContent busca o resto
+func (m *HybridMemoryManager) SetBlobs(blobs *blob.Manager) {
+	m.blobs = blobs
+}
+
+// FullContent retorna o conteúdo da memória com as referências a blobs resolvidas
+func (m *HybridMemoryManager) FullContent(ctx context.Context, memory *Memory) (string, error) {
+	if m.blobs == nil {
+		return memory.Content, nil
+	}
+	return m.blobs.Resolve(ctx, memory.Content)
+}
+
+// sanitize mascara segredos e dados pessoais antes da persistência e, com SetBlobs, move os
+// conteúdos grandes para o armazenamento de objetos
+func (m *HybridMemoryManager) sanitize(ctx context.Context, memory *Memory) error {
+	if m.anonymizer != nil {
+		content, err := m.anonymizer.Pseudonymize(ctx, memory.Content)
+		if err != nil {
+			return fmt.Errorf("erro ao pseudonimizar memória %s: %w", memory.ID, err)
+		}
+		memory.Content = content
+	}
+	if !m.config.DisableRedaction {
+		memory.Content = redact.String(memory.Content)
+		if metadata, ok := memory.Metadata.(map[string]interface{}); ok {
+			memory.Metadata = redact.Map(metadata)
+		}
+	}
+	return m.offload(ctx, memory)
+}
+
+// offload move o conteúdo grande da memória para o armazenamento de objetos
+func (m *HybridMemoryManager) offload(ctx context.Context, memory *Memory) error {
+	if m.blobs == nil {
+		return nil
+	}
+	content, err := m.blobs.Offload(ctx, memory.Content)
+	if err != nil {
+		return fmt.Errorf("erro ao guardar o conteúdo da memória %s: %w", memory.ID, err)
+	}
+	memory.Content = content
+	return nil
+}
+
 // StoreMemory armazena uma memória no sistema apropriado
 func (m *HybridMemoryManager) StoreMemory(ctx context.Context, memory *Memory) error {
+	return m.store(ctx, memory, nil)
+}
+
+// store armazena a memória e, se houver, grava os eventos no outbox do mesmo armazenamento
+func (m *HybridMemoryManager) store(ctx context.Context, memory *Memory, events []*outbox.Event) (err error) {
+	if err := memory.Validate(); err != nil {
+		return err
+	}
+	ctx = m.scope(ctx)
+	t := m.track("store", memory.AgentID)
+	defer func() { t.done(ctx, successes(err, 1), err) }()
+
+	if memory.TenantID == "" {
+		memory.TenantID = tenant.FromContext(ctx)
+	}
+	if memory.Timestamp.IsZero() {
+		memory.Timestamp = time.Now()
+	}
+	if p := ProvenanceFromContext(ctx); memory.Provenance == nil && !p.IsZero() {
+		memory.Provenance = &p
+	}
+	if err := m.sanitize(ctx, memory); err != nil {
+		return err
+	}
+
+	// Repetições quase idênticas reforçam a memória existente em vez de criar outra
+	if m.config.DedupThreshold > 0 && len(events) == 0 && deduplicable(memory) {
+		if merged, err := m.mergeDuplicate(ctx, t, memory); err != nil || merged {
+			return err
+		}
+	}
+
 	// Armazena na memória semântica para busca por similaridade
-	if err := m.semantic.StoreMemory(ctx, memory); err != nil {
-		return fmt.Errorf("erro ao armazenar na memória semântica: %v", err)
+	if err := t.run(BackendWeaviate, func() error { return m.semantic.StoreMemory(ctx, memory) }); err != nil {
+		return fmt.Errorf("erro ao armazenar na memória semântica: %w", err)
 	}
 
 	// Decide onde armazenar com base na importância
 	if memory.Importance >= m.config.ImportanceThreshold {
 		// Memória importante vai para o armazenamento de longo prazo
-		if err := m.longTerm.StoreMemory(ctx, memory); err != nil {
-			return fmt.Errorf("erro ao armazenar na memória de longo prazo: %v", err)
+		err := t.run(BackendMongoDB, func() error {
+			if len(events) == 0 {
+				return m.longTerm.StoreMemory(ctx, memory)
+			}
+			return m.longTerm.StoreMemoryWithEvents(ctx, memory, events)
+		})
+		if err != nil {
+			return fmt.Errorf("erro ao armazenar na memória de longo prazo: %w", err)
 		}
 	} else {
 		// Memória menos importante vai para o armazenamento de curto prazo
-		if err := m.shortTerm.StoreMemory(ctx, memory); err != nil {
-			return fmt.Errorf("erro ao armazenar na memória de curto prazo: %v", err)
+		if err := t.run(BackendRedis, func() error { return m.shortTerm.StoreMemoryWithEvents(ctx, memory, events) }); err != nil {
+			return fmt.Errorf("erro ao armazenar na memória de curto prazo: %w", err)
 		}
 	}
 
@@ -70,29 +196,60 @@ func (m *HybridMemoryManager) StoreMemory(ctx context.Context, memory *Memory) e
 }
 
 // GetMemory recupera uma memória específica
-func (m *HybridMemoryManager) GetMemory(ctx context.Context, agentID, memoryID string) (*Memory, error) {
+func (m *HybridMemoryManager) GetMemory(ctx context.Context, agentID, memoryID string) (memory *Memory, getErr error) {
+	ctx = m.scope(ctx)
+	t := m.track("get", agentID)
+	defer func() { t.done(ctx, successes(getErr, 1), getErr) }()
+
 	// Tenta primeiro na memória de curto prazo
-	memory, err := m.shortTerm.GetMemory(ctx, agentID, memoryID)
+	err := t.run(BackendRedis, func() (err error) {
+		memory, err = m.shortTerm.GetMemory(ctx, agentID, memoryID)
+		return err
+	})
 	if err == nil {
 		return memory, nil
 	}
 
 	// Se não encontrou, tenta na memória de longo prazo
-	memory, err = m.longTerm.GetMemory(ctx, agentID, memoryID)
-	if err == nil {
+	longErr := t.run(BackendMongoDB, func() (err error) {
+		memory, err = m.longTerm.GetMemory(ctx, agentID, memoryID)
+		return err
+	})
+	if longErr == nil {
 		return memory, nil
 	}
 
-	return nil, fmt.Errorf("memória não encontrada")
+	// Falhas de infraestrutura prevalecem sobre a ausência da memória
+	for _, e := range []error{err, longErr} {
+		if !errors.Is(e, errs.ErrNotFound) {
+			return nil, errs.FromContext("memory.GetMemory", fmt.Errorf("erro ao recuperar memória: %w", e))
+		}
+	}
+
+	return nil, errs.New(errs.ErrNotFound, "memory.GetMemory", "memória %s não encontrada", memoryID)
 }
 
 // SearchMemories busca memórias por tags
-func (m *HybridMemoryManager) SearchMemories(ctx context.Context, agentID string, tags []string) ([]*Memory, error) {
-	var allMemories []*Memory
+func (m *HybridMemoryManager) SearchMemories(ctx context.Context, agentID string, tags []string) (allMemories []*Memory, err error) {
+	ctx = m.scope(ctx)
+	t := m.track("search", agentID)
+	defer func() { t.done(ctx, len(allMemories), err) }()
+
+	// Busca em todas as camadas; falhas parciais aparecem apenas nas métricas
+	var shortTermMemories, longTermMemories []*Memory
+	t.run(BackendRedis, func() (err error) {
+		shortTermMemories, err = m.shortTerm.SearchMemories(ctx, agentID, tags)
+		return err
+	})
+	t.run(BackendMongoDB, func() (err error) {
+		longTermMemories, err = m.longTerm.SearchMemories(ctx, agentID, tags)
+		return err
+	})
 
-	// Busca em todas as camadas
-	shortTermMemories, _ := m.shortTerm.SearchMemories(ctx, agentID, tags)
-	longTermMemories, _ := m.longTerm.SearchMemories(ctx, agentID, tags)
+	// Falhas parciais são toleradas, mas não o cancelamento da busca
+	if err := ctx.Err(); err != nil {
+		return nil, errs.FromContext("memory.SearchMemories", err)
+	}
 
 	// Combina os resultados
 	allMemories = append(allMemories, shortTermMemories...)
@@ -101,95 +258,164 @@ func (m *HybridMemoryManager) SearchMemories(ctx context.Context, agentID string
 	return allMemories, nil
 }
 
+// SearchMemoriesPage busca uma página das memórias de longo prazo do agente, da mais
+// recente para a mais antiga. As memórias de curto prazo expiram e não são paginadas.
+func (m *HybridMemoryManager) SearchMemoriesPage(ctx context.Context, agentID string, tags []string, page Page) (*MemoryPage, error) {
+	return m.longTerm.SearchMemoriesPage(m.scope(ctx), agentID, tags, page)
+}
+
+// MemoryStats agrega as memórias de longo prazo por agente (quantidade e distribuição de importância)
+func (m *HybridMemoryManager) MemoryStats(ctx context.Context, agentID string) ([]AgentStats, error) {
+	return m.longTerm.MemoryStats(m.scope(ctx), agentID)
+}
+
 // SearchSimilarMemories busca memórias semanticamente similares
 func (m *HybridMemoryManager) SearchSimilarMemories(ctx context.Context, query string, limit int) ([]*Memory, error) {
-	return m.semantic.SearchSimilarMemories(ctx, query, limit)
+	return m.SearchSimilar(ctx, query, SimilarityOptions{Limit: limit})
+}
+
+// SearchSimilar busca as memórias similares à consulta dentro dos limites de similaridade e
+// distância, com a pontuação de cada uma
+func (m *HybridMemoryManager) SearchSimilar(ctx context.Context, query string, options SimilarityOptions) (memories []*Memory, err error) {
+	ctx = m.scope(ctx)
+	t := m.track("search_similar", "")
+	defer func() { t.done(ctx, len(memories), err) }()
+
+	err = t.run(BackendWeaviate, func() (err error) {
+		memories, err = m.semantic.SearchSimilar(ctx, query, options)
+		return err
+	})
+	return memories, err
 }
 
 // ConsolidateMemories move memórias importantes para o armazenamento de longo prazo
 func (m *HybridMemoryManager) ConsolidateMemories(ctx context.Context, agentID string) error {
+	_, _, err := m.ConsolidateBatch(ctx, agentID, 0)
+	return err
+}
+
+// ConsolidateBatch move para o longo prazo até limit memórias importantes de curto prazo do
+// agente (todas com limit zero) e informa se restam memórias a consolidar
+func (m *HybridMemoryManager) ConsolidateBatch(ctx context.Context, agentID string, limit int) (consolidated int, more bool, err error) {
+	ctx = m.scope(ctx)
+	t := m.track("consolidate", agentID)
+	defer func() { t.done(ctx, consolidated, err) }()
+
 	// Busca todas as memórias de curto prazo
-	memories, err := m.shortTerm.SearchMemories(ctx, agentID, []string{})
+	var memories []*Memory
+	err = t.run(BackendRedis, func() (err error) {
+		memories, err = m.shortTerm.SearchMemories(ctx, agentID, []string{})
+		return err
+	})
 	if err != nil {
-		return fmt.Errorf("erro ao buscar memórias para consolidação: %v", err)
+		return 0, false, fmt.Errorf("erro ao buscar memórias para consolidação: %w", err)
 	}
 
-	// Avalia cada memória
+	// Seleciona as memórias importantes; as demais continuam no curto prazo até expirar
+	var candidates []*Memory
 	for _, memory := range memories {
 		if memory.Importance >= m.config.ImportanceThreshold {
-			// Move para memória de longo prazo
-			if err := m.longTerm.StoreMemory(ctx, memory); err != nil {
-				return fmt.Errorf("erro ao consolidar memória: %v", err)
-			}
+			candidates = append(candidates, memory)
+		}
+	}
+	if limit > 0 && len(candidates) > limit {
+		candidates, more = candidates[:limit], true
+	}
 
-			// Remove da memória de curto prazo
-			if err := m.shortTerm.DeleteMemory(ctx, agentID, memory.ID); err != nil {
-				return fmt.Errorf("erro ao remover memória consolidada: %v", err)
-			}
+	for _, memory := range candidates {
+		if err := ctx.Err(); err != nil {
+			return consolidated, more, errs.FromContext("memory.ConsolidateMemories", err)
+		}
+		// Move para memória de longo prazo
+		if err := t.run(BackendMongoDB, func() error { return m.longTerm.StoreMemory(ctx, memory) }); err != nil {
+			return consolidated, more, fmt.Errorf("erro ao consolidar memória: %w", err)
 		}
+
+		// Remove da memória de curto prazo
+		if err := t.run(BackendRedis, func() error { return m.shortTerm.DeleteMemory(ctx, agentID, memory.ID) }); err != nil {
+			return consolidated, more, fmt.Errorf("erro ao remover memória consolidada: %w", err)
+		}
+		consolidated++
 	}
 
-	return nil
+	return consolidated, more, nil
 }
 
 // PruneMemories remove memórias antigas ou irrelevantes
 func (m *HybridMemoryManager) PruneMemories(ctx context.Context, agentID string) error {
+	ctx = m.scope(ctx)
+
 	// Remove memórias antigas da memória de curto prazo
 	if err := m.shortTerm.PruneMemories(ctx, agentID); err != nil {
-		return fmt.Errorf("erro ao limpar memórias de curto prazo: %v", err)
+		return fmt.Errorf("erro ao limpar memórias de curto prazo: %w", err)
 	}
 
 	// Remove memórias pouco importantes da memória de longo prazo
 	if err := m.longTerm.PruneMemories(ctx, agentID); err != nil {
-		return fmt.Errorf("erro ao limpar memórias de longo prazo: %v", err)
+		return fmt.Errorf("erro ao limpar memórias de longo prazo: %w", err)
 	}
 
 	return nil
 }
 
 // DeleteMemory remove uma memória de todos os sistemas de armazenamento
-func (m *HybridMemoryManager) DeleteMemory(ctx context.Context, agentID, memoryID string) error {
-	var errors []error
+func (m *HybridMemoryManager) DeleteMemory(ctx context.Context, agentID, memoryID string) (err error) {
+	ctx = m.scope(ctx)
+	t := m.track("delete", agentID)
+	defer func() { t.done(ctx, successes(err, 1), err) }()
+
+	var failures []error
 
 	// Remove da memória semântica
-	if err := m.semantic.DeleteMemory(ctx, memoryID); err != nil {
-		errors = append(errors, fmt.Errorf("erro ao remover da memória semântica: %v", err))
+	if err := t.run(BackendWeaviate, func() error { return m.semantic.DeleteMemory(ctx, memoryID) }); err != nil {
+		failures = append(failures, fmt.Errorf("erro ao remover da memória semântica: %w", err))
 	}
 
 	// Remove da memória de curto prazo
-	if err := m.shortTerm.DeleteMemory(ctx, agentID, memoryID); err != nil {
-		errors = append(errors, fmt.Errorf("erro ao remover da memória de curto prazo: %v", err))
+	if err := t.run(BackendRedis, func() error { return m.shortTerm.DeleteMemory(ctx, agentID, memoryID) }); err != nil {
+		failures = append(failures, fmt.Errorf("erro ao remover da memória de curto prazo: %w", err))
 	}
 
 	// Remove da memória de longo prazo
-	if err := m.longTerm.DeleteMemory(ctx, agentID, memoryID); err != nil {
-		errors = append(errors, fmt.Errorf("erro ao remover da memória de longo prazo: %v", err))
+	if err := t.run(BackendMongoDB, func() error { return m.longTerm.DeleteMemory(ctx, agentID, memoryID) }); err != nil {
+		failures = append(failures, fmt.Errorf("erro ao remover da memória de longo prazo: %w", err))
 	}
 
-	if len(errors) > 0 {
-		return fmt.Errorf("erros ao remover memória: %v", errors)
+	if len(failures) > 0 {
+		return fmt.Errorf("erros ao remover memória: %v", failures)
 	}
 
 	return nil
 }
 
 // UpdateMemory atualiza uma memória existente
-func (m *HybridMemoryManager) UpdateMemory(ctx context.Context, memory *Memory) error {
+func (m *HybridMemoryManager) UpdateMemory(ctx context.Context, memory *Memory) (err error) {
+	if err := memory.Validate(); err != nil {
+		return err
+	}
+	ctx = m.scope(ctx)
+	t := m.track("update", memory.AgentID)
+	defer func() { t.done(ctx, successes(err, 1), err) }()
+
+	if err := m.sanitize(ctx, memory); err != nil {
+		return err
+	}
+
 	// Atualiza na memória semântica
-	if err := m.semantic.UpdateMemory(ctx, memory); err != nil {
-		return fmt.Errorf("erro ao atualizar na memória semântica: %v", err)
+	if err := t.run(BackendWeaviate, func() error { return m.semantic.UpdateMemory(ctx, memory) }); err != nil {
+		return fmt.Errorf("erro ao atualizar na memória semântica: %w", err)
 	}
 
 	// Decide onde atualizar com base na importância
 	if memory.Importance >= m.config.ImportanceThreshold {
 		// Memória importante vai para o armazenamento de longo prazo
-		if err := m.longTerm.UpdateMemory(ctx, memory); err != nil {
-			return fmt.Errorf("erro ao atualizar na memória de longo prazo: %v", err)
+		if err := t.run(BackendMongoDB, func() error { return m.longTerm.UpdateMemory(ctx, memory) }); err != nil {
+			return fmt.Errorf("erro ao atualizar na memória de longo prazo: %w", err)
 		}
 	} else {
 		// Memória menos importante vai para o armazenamento de curto prazo
-		if err := m.shortTerm.UpdateMemory(ctx, memory); err != nil {
-			return fmt.Errorf("erro ao atualizar na memória de curto prazo: %v", err)
+		if err := t.run(BackendRedis, func() error { return m.shortTerm.UpdateMemory(ctx, memory) }); err != nil {
+			return fmt.Errorf("erro ao atualizar na memória de curto prazo: %w", err)
 		}
 	}
 
@@ -198,22 +424,23 @@ func (m *HybridMemoryManager) UpdateMemory(ctx context.Context, memory *Memory)
 
 // Close fecha todas as conexões
 func (m *HybridMemoryManager) Close(ctx context.Context) error {
-	var errors []error
+	m.StopMaintenance()
+	var failures []error
 
 	if err := m.shortTerm.Close(ctx); err != nil {
-		errors = append(errors, fmt.Errorf("erro ao fechar Redis: %v", err))
+		failures = append(failures, fmt.Errorf("erro ao fechar Redis: %w", err))
 	}
 
 	if err := m.longTerm.Close(ctx); err != nil {
-		errors = append(errors, fmt.Errorf("erro ao fechar MongoDB: %v", err))
+		failures = append(failures, fmt.Errorf("erro ao fechar MongoDB: %w", err))
 	}
 
 	if err := m.semantic.Close(ctx); err != nil {
-		errors = append(errors, fmt.Errorf("erro ao fechar Weaviate: %v", err))
+		failures = append(failures, fmt.Errorf("erro ao fechar Weaviate: %w", err))
 	}
 
-	if len(errors) > 0 {
-		return fmt.Errorf("erros ao fechar conexões: %v", errors)
+	if len(failures) > 0 {
+		return fmt.Errorf("erros ao fechar conexões: %v", failures)
 	}
 
 	return nil
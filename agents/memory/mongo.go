@@ -8,58 +8,107 @@ import (
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/suissa/HiveMind/agents/errs"
+	"github.com/suissa/HiveMind/agents/tenant"
 )
 
 // MongoMemoryManager gerencia memórias usando MongoDB
 type MongoMemoryManager struct {
 	client     *mongo.Client
 	collection *mongo.Collection
+	outbox     *mongo.Collection // Eventos pendentes, gravados na transação da memória
 }
 
 // NewMongoMemoryManager cria um novo gerenciador de memória MongoDB
 func NewMongoMemoryManager(ctx context.Context, mongoURL, database, collection string) (*MongoMemoryManager, error) {
 	client, err := mongo.Connect(ctx, options.Client().ApplyURI(mongoURL))
 	if err != nil {
-		return nil, fmt.Errorf("erro ao conectar ao MongoDB: %v", err)
+		return nil, fmt.Errorf("erro ao conectar ao MongoDB: %w", err)
 	}
 
 	// Verifica a conexão
 	err = client.Ping(ctx, nil)
 	if err != nil {
-		return nil, fmt.Errorf("erro ao verificar conexão com MongoDB: %v", err)
+		return nil, fmt.Errorf("erro ao verificar conexão com MongoDB: %w", err)
+	}
+
+	manager := &MongoMemoryManager{
+		client:     client,
+		collection: client.Database(database).Collection(collection),
+		outbox:     client.Database(database).Collection(collection + OutboxSuffix),
+	}
+	if err := manager.ensureIndexes(ctx); err != nil {
+		return nil, err
 	}
+	if err := manager.ensureOutboxIndexes(ctx); err != nil {
+		return nil, err
+	}
+	return manager, nil
+}
 
-	// Cria índices
-	coll := client.Database(database).Collection(collection)
-	_, err = coll.Indexes().CreateMany(ctx, []mongo.IndexModel{
+// ensureIndexes cria os índices das consultas por agente (tags, data e importância) e da
+// exclusão por titular, evitando varreduras completas da coleção. Índices existentes são mantidos.
+func (m *MongoMemoryManager) ensureIndexes(ctx context.Context) error {
+	_, err := m.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
 		{
+			// Listagem paginada e limpeza por data
 			Keys: bson.D{
+				{Key: "tenant_id", Value: 1},
 				{Key: "agent_id", Value: 1},
-				{Key: "timestamp", Value: 1},
+				{Key: "timestamp", Value: -1},
+				{Key: "_id", Value: -1},
 			},
 		},
 		{
 			Keys: bson.D{
+				{Key: "tenant_id", Value: 1},
+				{Key: "agent_id", Value: 1},
 				{Key: "tags", Value: 1},
 			},
 		},
+		{
+			Keys: bson.D{
+				{Key: "tenant_id", Value: 1},
+				{Key: "agent_id", Value: 1},
+				{Key: "importance", Value: -1},
+			},
+		},
+		{
+			Keys:    bson.D{{Key: "subjects", Value: 1}},
+			Options: options.Index().SetSparse(true),
+		},
 	})
 	if err != nil {
-		return nil, fmt.Errorf("erro ao criar índices: %v", err)
+		return fmt.Errorf("erro ao criar índices: %w", err)
 	}
+	return nil
+}
 
-	return &MongoMemoryManager{
-		client:     client,
-		collection: coll,
-	}, nil
+// scoped adiciona ao filtro a restrição do tenant do contexto.
+// Documentos sem tenant_id pertencem ao tenant padrão.
+func scoped(ctx context.Context, filter bson.M) bson.M {
+	tenantID := tenant.FromContext(ctx)
+	if tenantID == tenant.DefaultTenant {
+		filter["tenant_id"] = bson.M{"$in": bson.A{tenantID, nil}}
+	} else {
+		filter["tenant_id"] = tenantID
+	}
+	return filter
 }
 
 // StoreMemory armazena uma memória no MongoDB
 func (m *MongoMemoryManager) StoreMemory(ctx context.Context, memory *Memory) error {
-	memory.Timestamp = time.Now()
+	if memory.TenantID == "" {
+		memory.TenantID = tenant.FromContext(ctx)
+	}
+	// A data original é mantida na consolidação, para a linha do tempo
+	if memory.Timestamp.IsZero() {
+		memory.Timestamp = time.Now()
+	}
 	_, err := m.collection.InsertOne(ctx, memory)
 	if err != nil {
-		return fmt.Errorf("erro ao armazenar memória: %v", err)
+		return fmt.Errorf("erro ao armazenar memória: %w", err)
 	}
 	return nil
 }
@@ -67,16 +116,16 @@ func (m *MongoMemoryManager) StoreMemory(ctx context.Context, memory *Memory) er
 // GetMemory recupera uma memória específica
 func (m *MongoMemoryManager) GetMemory(ctx context.Context, agentID, memoryID string) (*Memory, error) {
 	var memory Memory
-	err := m.collection.FindOne(ctx, bson.M{
+	err := m.collection.FindOne(ctx, scoped(ctx, bson.M{
 		"_id":      memoryID,
 		"agent_id": agentID,
-	}).Decode(&memory)
+	})).Decode(&memory)
 
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
-			return nil, fmt.Errorf("memória não encontrada")
+			return nil, errs.New(errs.ErrNotFound, "mongo.GetMemory", "memória não encontrada")
 		}
-		return nil, fmt.Errorf("erro ao buscar memória: %v", err)
+		return nil, fmt.Errorf("erro ao buscar memória: %w", err)
 	}
 
 	return &memory, nil
@@ -84,14 +133,14 @@ func (m *MongoMemoryManager) GetMemory(ctx context.Context, agentID, memoryID st
 
 // SearchMemories busca memórias por tags
 func (m *MongoMemoryManager) SearchMemories(ctx context.Context, agentID string, tags []string) ([]*Memory, error) {
-	filter := bson.M{"agent_id": agentID}
+	filter := scoped(ctx, bson.M{"agent_id": agentID})
 	if len(tags) > 0 {
 		filter["tags"] = bson.M{"$in": tags}
 	}
 
 	cursor, err := m.collection.Find(ctx, filter)
 	if err != nil {
-		return nil, fmt.Errorf("erro ao buscar memórias: %v", err)
+		return nil, fmt.Errorf("erro ao buscar memórias: %w", err)
 	}
 	defer cursor.Close(ctx)
 
@@ -99,52 +148,193 @@ func (m *MongoMemoryManager) SearchMemories(ctx context.Context, agentID string,
 	for cursor.Next(ctx) {
 		var memory Memory
 		if err := cursor.Decode(&memory); err != nil {
-			return nil, fmt.Errorf("erro ao decodificar memória: %v", err)
+			return nil, fmt.Errorf("erro ao decodificar memória: %w", err)
 		}
 		memories = append(memories, &memory)
 	}
 
 	if err := cursor.Err(); err != nil {
-		return nil, fmt.Errorf("erro ao iterar sobre resultados: %v", err)
+		return nil, fmt.Errorf("erro ao iterar sobre resultados: %w", err)
 	}
 
 	return memories, nil
 }
 
+// SearchMemoriesPage busca uma página das memórias do agente, da mais recente para a mais
+// antiga. A paginação por cursor usa o índice (tenant_id, agent_id, timestamp, _id).
+func (m *MongoMemoryManager) SearchMemoriesPage(ctx context.Context, agentID string, tags []string, page Page) (*MemoryPage, error) {
+	filter := scoped(ctx, bson.M{"agent_id": agentID})
+	if len(tags) > 0 {
+		filter["tags"] = bson.M{"$in": tags}
+	}
+	if page.Cursor != "" {
+		cursor, err := decodeCursor(page.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		filter["$or"] = bson.A{
+			bson.M{"timestamp": bson.M{"$lt": cursor.Timestamp}},
+			bson.M{"timestamp": cursor.Timestamp, "_id": bson.M{"$lt": cursor.ID}},
+		}
+	}
+
+	limit := page.limit()
+	opts := options.Find().
+		SetSort(bson.D{{Key: "timestamp", Value: -1}, {Key: "_id", Value: -1}}).
+		SetLimit(int64(limit + 1))
+	cursor, err := m.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar memórias: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	result := &MemoryPage{Memories: make([]*Memory, 0, limit)}
+	if err := cursor.All(ctx, &result.Memories); err != nil {
+		return nil, fmt.Errorf("erro ao decodificar memórias: %w", err)
+	}
+	// A memória excedente indica que há uma próxima página
+	if len(result.Memories) > limit {
+		result.Memories = result.Memories[:limit]
+		result.NextCursor = encodeCursor(result.Memories[limit-1])
+	}
+	return result, nil
+}
+
+// MemoryStats agrega a quantidade e a distribuição de importância das memórias por agente
+func (m *MongoMemoryManager) MemoryStats(ctx context.Context, agentID string) ([]AgentStats, error) {
+	match := scoped(ctx, bson.M{})
+	if agentID != "" {
+		match["agent_id"] = agentID
+	}
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$group", Value: bson.M{
+			"_id": bson.M{
+				"agent": "$agent_id",
+				// Faixas de 0.1; importância 1 entra na última faixa
+				"bucket": bson.M{"$min": bson.A{bson.M{"$floor": bson.M{"$multiply": bson.A{"$importance", 10}}}, 9}},
+			},
+			"count": bson.M{"$sum": 1},
+			"sum":   bson.M{"$sum": "$importance"},
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "_id.agent", Value: 1}, {Key: "_id.bucket", Value: 1}}}},
+	}
+
+	cursor, err := m.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao agregar memórias: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var rows []struct {
+		ID struct {
+			Agent  string  `bson:"agent"`
+			Bucket float64 `bson:"bucket"`
+		} `bson:"_id"`
+		Count int     `bson:"count"`
+		Sum   float64 `bson:"sum"`
+	}
+	if err := cursor.All(ctx, &rows); err != nil {
+		return nil, fmt.Errorf("erro ao decodificar agregação: %w", err)
+	}
+
+	stats := make([]AgentStats, 0)
+	index := make(map[string]int)
+	sums := make(map[string]float64)
+	for _, row := range rows {
+		i, ok := index[row.ID.Agent]
+		if !ok {
+			i = len(stats)
+			index[row.ID.Agent] = i
+			stats = append(stats, AgentStats{AgentID: row.ID.Agent, Importance: importanceBuckets()})
+		}
+		if bucket := int(row.ID.Bucket); bucket >= 0 && bucket < len(stats[i].Importance) {
+			stats[i].Importance[bucket].Count += row.Count
+		}
+		stats[i].Count += row.Count
+		sums[row.ID.Agent] += row.Sum
+	}
+	for i := range stats {
+		if stats[i].Count > 0 {
+			stats[i].AvgImportance = sums[stats[i].AgentID] / float64(stats[i].Count)
+		}
+	}
+	return stats, nil
+}
+
 // UpdateMemory atualiza uma memória existente
 func (m *MongoMemoryManager) UpdateMemory(ctx context.Context, memory *Memory) error {
 	memory.Timestamp = time.Now()
 	_, err := m.collection.UpdateOne(ctx,
-		bson.M{"_id": memory.ID, "agent_id": memory.AgentID},
+		scoped(ctx, bson.M{"_id": memory.ID, "agent_id": memory.AgentID}),
 		bson.M{"$set": memory},
 	)
 	if err != nil {
-		return fmt.Errorf("erro ao atualizar memória: %v", err)
+		return fmt.Errorf("erro ao atualizar memória: %w", err)
 	}
 	return nil
 }
 
 // DeleteMemory remove uma memória
 func (m *MongoMemoryManager) DeleteMemory(ctx context.Context, agentID, memoryID string) error {
-	_, err := m.collection.DeleteOne(ctx, bson.M{
+	_, err := m.collection.DeleteOne(ctx, scoped(ctx, bson.M{
 		"_id":      memoryID,
 		"agent_id": agentID,
-	})
+	}))
 	if err != nil {
-		return fmt.Errorf("erro ao deletar memória: %v", err)
+		return fmt.Errorf("erro ao deletar memória: %w", err)
 	}
 	return nil
 }
 
+// deleteBySubject remove as memórias do tenant do contexto que referenciam o titular
+func (m *MongoMemoryManager) deleteBySubject(ctx context.Context, terms []string) ([]string, error) {
+	conditions := bson.A{bson.M{"subjects": bson.M{"$in": terms}}}
+	for _, term := range terms {
+		conditions = append(conditions, bson.M{"content": bson.M{"$regex": tokenPattern(term)}})
+	}
+	filter := scoped(ctx, bson.M{"$or": conditions})
+
+	projection := bson.M{"_id": 1, "content": 1, "subjects": 1}
+	cursor, err := m.collection.Find(ctx, filter, options.Find().SetProjection(projection))
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar memórias do titular: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var ids []string
+	for cursor.Next(ctx) {
+		var memory Memory
+		if err := cursor.Decode(&memory); err != nil {
+			return nil, fmt.Errorf("erro ao decodificar memória: %w", err)
+		}
+		// A expressão regular só seleciona candidatas; a decisão é a mesma do Redis e do Weaviate
+		if references(&memory, terms) {
+			ids = append(ids, memory.ID)
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao iterar sobre resultados: %w", err)
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	if _, err := m.collection.DeleteMany(ctx, scoped(ctx, bson.M{"_id": bson.M{"$in": ids}})); err != nil {
+		return nil, fmt.Errorf("erro ao deletar memórias do titular: %w", err)
+	}
+	return ids, nil
+}
+
 // PruneMemories remove memórias antigas
 func (m *MongoMemoryManager) PruneMemories(ctx context.Context, agentID string) error {
 	cutoff := time.Now().Add(-24 * time.Hour)
-	_, err := m.collection.DeleteMany(ctx, bson.M{
+	_, err := m.collection.DeleteMany(ctx, scoped(ctx, bson.M{
 		"agent_id":  agentID,
 		"timestamp": bson.M{"$lt": cutoff},
-	})
+	}))
 	if err != nil {
-		return fmt.Errorf("erro ao limpar memórias antigas: %v", err)
+		return fmt.Errorf("erro ao limpar memórias antigas: %w", err)
 	}
 	return nil
 }
@@ -152,7 +342,7 @@ func (m *MongoMemoryManager) PruneMemories(ctx context.Context, agentID string)
 // Close fecha a conexão com o MongoDB
 func (m *MongoMemoryManager) Close(ctx context.Context) error {
 	if err := m.client.Disconnect(ctx); err != nil {
-		return fmt.Errorf("erro ao fechar conexão com MongoDB: %v", err)
+		return fmt.Errorf("erro ao fechar conexão com MongoDB: %w", err)
 	}
 	return nil
 }
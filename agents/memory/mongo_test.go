@@ -0,0 +1,204 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+
+	"github.com/suissa/HiveMind/agents/errs"
+	"github.com/suissa/HiveMind/agents/tenant"
+)
+
+// memoryDoc monta o documento de uma memória como o MongoDB o retornaria
+func memoryDoc(id string, timestamp time.Time) bson.D {
+	return bson.D{
+		{Key: "_id", Value: id},
+		{Key: "agent_id", Value: "writer-1"},
+		{Key: "content", Value: id},
+		{Key: "timestamp", Value: timestamp},
+	}
+}
+
+// startedCommand decodifica o campo do último comando enviado ao servidor simulado
+func startedCommand(mt *mtest.T, field string) bson.M {
+	mt.Helper()
+	event := mt.GetStartedEvent()
+	if event == nil {
+		mt.Fatal("nenhum comando enviado")
+	}
+	var value bson.M
+	if err := bson.Unmarshal(event.Command.Lookup(field).Document(), &value); err != nil {
+		mt.Fatal(err)
+	}
+	return value
+}
+
+func TestMongoSearchMemoriesPage(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	base := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	mt.Run("primeira página", func(mt *mtest.T) {
+		manager := &MongoMemoryManager{collection: mt.Coll}
+		ns := mt.Coll.Database().Name() + "." + mt.Coll.Name()
+		// Três documentos para uma página de dois: há uma próxima página
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, ns, mtest.FirstBatch,
+			memoryDoc("m-3", base.Add(2*time.Minute)),
+			memoryDoc("m-2", base.Add(time.Minute)),
+			memoryDoc("m-1", base),
+		))
+
+		page, err := manager.SearchMemoriesPage(context.Background(), "writer-1", []string{"copy"}, Page{Limit: 2})
+		if err != nil {
+			mt.Fatal(err)
+		}
+		if len(page.Memories) != 2 || page.Memories[0].ID != "m-3" || page.Memories[1].ID != "m-2" {
+			mt.Fatalf("página inesperada: %+v", page.Memories)
+		}
+		cursor, err := decodeCursor(page.NextCursor)
+		if err != nil {
+			mt.Fatal(err)
+		}
+		if cursor.ID != "m-2" || !cursor.Timestamp.Equal(base.Add(time.Minute)) {
+			mt.Fatalf("o cursor deveria apontar para a última memória da página: %+v", cursor)
+		}
+
+		event := mt.GetStartedEvent()
+		if limit := event.Command.Lookup("limit").AsInt64(); limit != 3 {
+			mt.Errorf("esperava limit 3 (página + 1), obtido %d", limit)
+		}
+		var sort bson.D
+		if err := bson.Unmarshal(event.Command.Lookup("sort").Document(), &sort); err != nil {
+			mt.Fatal(err)
+		}
+		if len(sort) != 2 || sort[0].Key != "timestamp" || sort[1].Key != "_id" {
+			mt.Errorf("ordenação inesperada: %v", sort)
+		}
+		var filter bson.M
+		if err := bson.Unmarshal(event.Command.Lookup("filter").Document(), &filter); err != nil {
+			mt.Fatal(err)
+		}
+		if filter["agent_id"] != "writer-1" || filter["tags"] == nil || filter["tenant_id"] == nil {
+			mt.Errorf("filtro inesperado: %v", filter)
+		}
+		if _, ok := filter["$or"]; ok {
+			mt.Errorf("a primeira página não deveria filtrar pelo cursor: %v", filter)
+		}
+	})
+
+	mt.Run("última página com cursor", func(mt *mtest.T) {
+		manager := &MongoMemoryManager{collection: mt.Coll}
+		ns := mt.Coll.Database().Name() + "." + mt.Coll.Name()
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, ns, mtest.FirstBatch, memoryDoc("m-1", base)))
+
+		ctx := tenant.WithTenant(context.Background(), "acme")
+		cursor := encodeCursor(&Memory{ID: "m-2", Timestamp: base.Add(time.Minute)})
+		page, err := manager.SearchMemoriesPage(ctx, "writer-1", nil, Page{Limit: 2, Cursor: cursor})
+		if err != nil {
+			mt.Fatal(err)
+		}
+		if len(page.Memories) != 1 || page.NextCursor != "" {
+			mt.Fatalf("esperava a última página sem cursor: %+v", page)
+		}
+
+		filter := startedCommand(mt, "filter")
+		if filter["tenant_id"] != "acme" {
+			mt.Errorf("a busca deveria se restringir ao tenant: %v", filter)
+		}
+		if _, ok := filter["tags"]; ok {
+			mt.Errorf("sem tags não deveria filtrar por tags: %v", filter)
+		}
+		if or, ok := filter["$or"].(bson.A); !ok || len(or) != 2 {
+			mt.Errorf("esperava o filtro do cursor em $or: %v", filter)
+		}
+	})
+
+	mt.Run("cursor inválido", func(mt *mtest.T) {
+		manager := &MongoMemoryManager{collection: mt.Coll}
+		_, err := manager.SearchMemoriesPage(context.Background(), "writer-1", nil, Page{Cursor: "%%%"})
+		if !errors.Is(err, errs.ErrValidation) {
+			mt.Fatalf("esperava ErrValidation, obtido %v", err)
+		}
+		if mt.GetStartedEvent() != nil {
+			mt.Error("um cursor inválido não deveria consultar o MongoDB")
+		}
+	})
+}
+
+func TestMongoMemoryStats(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("agrega por agente e faixa", func(mt *mtest.T) {
+		manager := &MongoMemoryManager{collection: mt.Coll}
+		ns := mt.Coll.Database().Name() + "." + mt.Coll.Name()
+		row := func(agent string, bucket float64, count int, sum float64) bson.D {
+			return bson.D{
+				{Key: "_id", Value: bson.D{{Key: "agent", Value: agent}, {Key: "bucket", Value: bucket}}},
+				{Key: "count", Value: count},
+				{Key: "sum", Value: sum},
+			}
+		}
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, ns, mtest.FirstBatch,
+			row("reviewer-1", 9, 1, 1),
+			row("writer-1", 2, 2, 0.5),
+			row("writer-1", 8, 1, 0.8),
+		))
+
+		stats, err := manager.MemoryStats(context.Background(), "")
+		if err != nil {
+			mt.Fatal(err)
+		}
+		if len(stats) != 2 {
+			mt.Fatalf("esperava dois agentes: %+v", stats)
+		}
+
+		cases := []struct {
+			agent   string
+			count   int
+			avg     float64
+			buckets map[int]int
+		}{
+			{"reviewer-1", 1, 1, map[int]int{9: 1}},
+			{"writer-1", 3, 1.3 / 3, map[int]int{2: 2, 8: 1}},
+		}
+		for i, c := range cases {
+			got := stats[i]
+			if got.AgentID != c.agent || got.Count != c.count || math.Abs(got.AvgImportance-c.avg) > 1e-9 {
+				mt.Errorf("estatísticas inesperadas: %+v, esperava %s com %d (média %.3f)", got, c.agent, c.count, c.avg)
+			}
+			for bucket, importance := range got.Importance {
+				if importance.Count != c.buckets[bucket] {
+					mt.Errorf("%s: faixa %d com %d memórias, esperava %d", c.agent, bucket, importance.Count, c.buckets[bucket])
+				}
+			}
+		}
+	})
+
+	mt.Run("filtra o agente e o tenant", func(mt *mtest.T) {
+		manager := &MongoMemoryManager{collection: mt.Coll}
+		ns := mt.Coll.Database().Name() + "." + mt.Coll.Name()
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, ns, mtest.FirstBatch))
+
+		stats, err := manager.MemoryStats(tenant.WithTenant(context.Background(), "acme"), "writer-1")
+		if err != nil {
+			mt.Fatal(err)
+		}
+		if stats == nil || len(stats) != 0 {
+			mt.Fatalf("esperava uma lista vazia: %#v", stats)
+		}
+
+		var pipeline []bson.M
+		event := mt.GetStartedEvent()
+		if err := event.Command.Lookup("pipeline").Unmarshal(&pipeline); err != nil {
+			mt.Fatal(err)
+		}
+		match, ok := pipeline[0]["$match"].(bson.M)
+		if !ok || match["agent_id"] != "writer-1" || match["tenant_id"] != "acme" {
+			mt.Errorf("$match inesperado: %v", pipeline[0])
+		}
+	})
+}
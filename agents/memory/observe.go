@@ -0,0 +1,117 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/suissa/HiveMind/agents/errs"
+	"github.com/suissa/HiveMind/agents/metrics"
+	"github.com/suissa/HiveMind/agents/timing"
+)
+
+// Métricas das operações de memória, expostas em /metrics
+var (
+	requestsTotal = metrics.Default.Counter("hivemind_memory_requests_total",
+		"Operações da memória híbrida por ação e resultado", "operation", "status")
+	backendTotal = metrics.Default.Counter("hivemind_memory_backend_operations_total",
+		"Operações por armazenamento (redis, mongodb, weaviate), ação e resultado", "operation", "backend", "status")
+	backendLatency = metrics.Default.Histogram("hivemind_memory_backend_duration_seconds",
+		"Latência das operações por armazenamento e ação", nil, "operation", "backend")
+	consolidatedTotal = metrics.Default.Counter("hivemind_memory_consolidated_total",
+		"Memórias movidas do curto para o longo prazo pela consolidação")
+	duplicatesMerged = metrics.Default.Counter("hivemind_memory_duplicates_merged_total",
+		"Memórias quase duplicadas mescladas a uma existente em vez de gravadas")
+	weaviateFailures = metrics.Default.Counter("hivemind_memory_weaviate_failures_total",
+		"Falhas de escrita e consulta no Weaviate por ação", "operation")
+)
+
+// Operation descreve uma operação concluída na memória híbrida
+type Operation struct {
+	Action   string                   // store, get, search, search_similar, update, delete ou consolidate
+	AgentID  string                   // Vazio nas operações sem agente (search_similar)
+	Count    int                      // Memórias gravadas, retornadas, removidas ou consolidadas
+	Duration time.Duration            // Duração total
+	Backends map[string]time.Duration // Latência de cada armazenamento envolvido
+	Err      error
+}
+
+// OperationObserver recebe cada operação concluída na memória híbrida
+type OperationObserver func(ctx context.Context, op Operation)
+
+// Observable é implementado pelos gerenciadores que notificam as operações concluídas
+type Observable interface {
+	SetObserver(observer OperationObserver)
+}
+
+// SetObserver define quem recebe as operações concluídas (ex.: o runtime, que as emite
+// como EventMemoryOperation). As métricas são registradas com ou sem observador.
+func (m *HybridMemoryManager) SetObserver(observer OperationObserver) {
+	m.observer = observer
+}
+
+// tracker mede uma operação da memória híbrida e as chamadas a cada armazenamento
+type tracker struct {
+	m     *HybridMemoryManager
+	op    Operation
+	start time.Time
+}
+
+// track inicia a medição de uma operação
+func (m *HybridMemoryManager) track(action, agentID string) *tracker {
+	return &tracker{
+		m:     m,
+		op:    Operation{Action: action, AgentID: agentID, Backends: make(map[string]time.Duration)},
+		start: time.Now(),
+	}
+}
+
+// run executa e mede uma chamada ao armazenamento informado
+func (t *tracker) run(backend string, fn func() error) error {
+	start := time.Now()
+	err := fn()
+	elapsed := time.Since(start)
+
+	t.op.Backends[backend] += elapsed
+	backendTotal.Inc(t.op.Action, backend, status(err))
+	backendLatency.Observe(elapsed.Seconds(), t.op.Action, backend)
+	if err != nil && backend == BackendWeaviate {
+		weaviateFailures.Inc(t.op.Action)
+	}
+	return err
+}
+
+// done encerra a medição e notifica o observador
+func (t *tracker) done(ctx context.Context, count int, err error) {
+	t.op.Count = count
+	t.op.Duration = time.Since(t.start)
+	t.op.Err = err
+	requestsTotal.Inc(t.op.Action, status(err))
+	// No breakdown do workflow (timing.Recorder), sem agente vale o agente da tarefa
+	timing.Record(ctx, t.op.AgentID, timing.Memory, t.op.Action, t.start)
+	if t.op.Action == "consolidate" {
+		consolidatedTotal.Add(float64(count))
+	}
+	if t.m.observer != nil {
+		t.m.observer(ctx, t.op)
+	}
+}
+
+// successes retorna a quantidade informada se a operação foi concluída, ou zero
+func successes(err error, n int) int {
+	if err != nil {
+		return 0
+	}
+	return n
+}
+
+// status é o rótulo de resultado das métricas
+func status(err error) string {
+	switch {
+	case err == nil:
+		return "ok"
+	case errors.Is(err, errs.ErrNotFound):
+		return "not_found"
+	}
+	return "error"
+}
@@ -0,0 +1,252 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/suissa/HiveMind/agents/outbox"
+)
+
+// OutboxSuffix é o sufixo da coleção de eventos pendentes, ao lado da coleção de memórias
+const OutboxSuffix = "_outbox"
+
+// Chaves do outbox no Redis. O outbox é único para todos os tenants: cada evento guarda o
+// seu, e o relay publica com o tenant do evento.
+const (
+	redisOutboxPending = "outbox:pending" // Conjunto ordenado pelo horário da próxima tentativa
+	redisOutboxEvents  = "outbox:events"  // Hash com os eventos serializados
+)
+
+// EventStore é implementado pelos gerenciadores que gravam os eventos do outbox na mesma
+// transação da memória
+type EventStore interface {
+	StoreMemoryWithEvents(ctx context.Context, memory *Memory, events ...*outbox.Event) error
+	Outbox() outbox.Store
+}
+
+// StoreMemoryWithEvents armazena a memória e grava os eventos no outbox na mesma transação
+// do armazenamento escolhido pela importância; um outbox.Relay os publica depois do commit.
+// As repetições não são mescladas à memória existente, para que a gravação seja atômica.
+func (m *HybridMemoryManager) StoreMemoryWithEvents(ctx context.Context, memory *Memory, events ...*outbox.Event) error {
+	return m.store(ctx, memory, events)
+}
+
+// Outbox retorna os eventos pendentes dos armazenamentos de longo e de curto prazo
+func (m *HybridMemoryManager) Outbox() outbox.Store {
+	return outbox.Multi(m.longTerm.Outbox(), m.shortTerm.Outbox())
+}
+
+// MongoOutbox é o outbox do MongoDB
+type MongoOutbox struct {
+	collection *mongo.Collection
+}
+
+// Outbox retorna o outbox da coleção de memórias
+func (m *MongoMemoryManager) Outbox() *MongoOutbox {
+	return &MongoOutbox{collection: m.outbox}
+}
+
+// ensureOutboxIndexes cria o índice da busca por eventos disponíveis
+func (m *MongoMemoryManager) ensureOutboxIndexes(ctx context.Context) error {
+	_, err := m.outbox.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "available_at", Value: 1}},
+	})
+	if err != nil {
+		return fmt.Errorf("erro ao criar índice do outbox: %w", err)
+	}
+	return nil
+}
+
+// StoreMemoryWithEvents armazena a memória e os eventos em uma transação. Transações exigem
+// um replica set ou um cluster fragmentado.
+func (m *MongoMemoryManager) StoreMemoryWithEvents(ctx context.Context, memory *Memory, events []*outbox.Event) error {
+	session, err := m.client.StartSession()
+	if err != nil {
+		return fmt.Errorf("erro ao iniciar sessão do MongoDB: %w", err)
+	}
+	defer session.EndSession(ctx)
+
+	_, err = session.WithTransaction(ctx, func(sc mongo.SessionContext) (interface{}, error) {
+		if err := m.StoreMemory(sc, memory); err != nil {
+			return nil, err
+		}
+		return nil, m.Outbox().enqueue(sc, events)
+	})
+	if err != nil {
+		return fmt.Errorf("erro na transação da memória %s: %w", memory.ID, err)
+	}
+	return nil
+}
+
+// enqueue grava os eventos no outbox
+func (o *MongoOutbox) enqueue(ctx context.Context, events []*outbox.Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+	now := time.Now()
+	docs := make([]interface{}, len(events))
+	for i, event := range events {
+		event.Prepare(ctx, now)
+		docs[i] = event
+	}
+	if _, err := o.collection.InsertMany(ctx, docs); err != nil {
+		return fmt.Errorf("erro ao gravar eventos no outbox: %w", err)
+	}
+	return nil
+}
+
+// Claim reserva os eventos disponíveis mais antigos, um a um, adiando a próxima tentativa de cada
+func (o *MongoOutbox) Claim(ctx context.Context, limit int, lease time.Duration) ([]*outbox.Event, error) {
+	now := time.Now()
+	opts := options.FindOneAndUpdate().
+		SetSort(bson.D{{Key: "available_at", Value: 1}}).
+		SetReturnDocument(options.After)
+
+	var events []*outbox.Event
+	for len(events) < limit {
+		var event outbox.Event
+		err := o.collection.FindOneAndUpdate(ctx,
+			bson.M{"available_at": bson.M{"$lte": now}},
+			bson.M{"$set": bson.M{"available_at": now.Add(lease)}, "$inc": bson.M{"attempts": 1}},
+			opts,
+		).Decode(&event)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			break
+		}
+		if err != nil {
+			return events, fmt.Errorf("erro ao reservar evento do outbox: %w", err)
+		}
+		events = append(events, &event)
+	}
+	return events, nil
+}
+
+// Ack remove o evento publicado
+func (o *MongoOutbox) Ack(ctx context.Context, id string) error {
+	if _, err := o.collection.DeleteOne(ctx, bson.M{"_id": id}); err != nil {
+		return fmt.Errorf("erro ao remover evento %s do outbox: %w", id, err)
+	}
+	return nil
+}
+
+// Retry registra a falha e agenda a próxima tentativa
+func (o *MongoOutbox) Retry(ctx context.Context, id string, cause error, at time.Time) error {
+	_, err := o.collection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{
+		"$set": bson.M{"available_at": at, "last_error": cause.Error()},
+	})
+	if err != nil {
+		return fmt.Errorf("erro ao reagendar evento %s do outbox: %w", id, err)
+	}
+	return nil
+}
+
+// RedisOutbox é o outbox do Redis
+type RedisOutbox struct {
+	client *redis.Client
+}
+
+// Outbox retorna o outbox do Redis
+func (m *RedisMemoryManager) Outbox() *RedisOutbox {
+	return &RedisOutbox{client: m.client}
+}
+
+// enqueue grava os eventos no outbox dentro da transação informada
+func (o *RedisOutbox) enqueue(ctx context.Context, pipe redis.Pipeliner, events []*outbox.Event) error {
+	now := time.Now()
+	for _, event := range events {
+		event.Prepare(ctx, now)
+		data, err := json.Marshal(event)
+		if err != nil {
+			return fmt.Errorf("erro ao serializar evento %s: %w", event.ID, err)
+		}
+		pipe.HSet(ctx, redisOutboxEvents, event.ID, data)
+		pipe.ZAdd(ctx, redisOutboxPending, &redis.Z{Score: float64(event.AvailableAt.UnixMilli()), Member: event.ID})
+	}
+	return nil
+}
+
+// claimScript reserva até ARGV[2] eventos com a próxima tentativa até ARGV[1], adiando-a
+// para ARGV[3] e contando a tentativa no próprio evento. IDs sem evento são descartados.
+var claimScript = redis.NewScript(`
+local ids = redis.call('ZRANGEBYSCORE', KEYS[1], '-inf', ARGV[1], 'LIMIT', 0, ARGV[2])
+local claimed = {}
+for _, id in ipairs(ids) do
+	local data = redis.call('HGET', KEYS[2], id)
+	if data then
+		local event = cjson.decode(data)
+		event.attempts = (event.attempts or 0) + 1
+		data = cjson.encode(event)
+		redis.call('HSET', KEYS[2], id, data)
+		redis.call('ZADD', KEYS[1], ARGV[3], id)
+		table.insert(claimed, data)
+	else
+		redis.call('ZREM', KEYS[1], id)
+	end
+end
+return claimed
+`)
+
+// retryScript registra o erro ARGV[3] no evento ARGV[1] e agenda a próxima tentativa para ARGV[2]
+var retryScript = redis.NewScript(`
+local data = redis.call('HGET', KEYS[2], ARGV[1])
+if not data then
+	return 0
+end
+local event = cjson.decode(data)
+event.last_error = ARGV[3]
+redis.call('HSET', KEYS[2], ARGV[1], cjson.encode(event))
+redis.call('ZADD', KEYS[1], ARGV[2], ARGV[1])
+return 1
+`)
+
+// Claim reserva os eventos disponíveis de forma atômica
+func (o *RedisOutbox) Claim(ctx context.Context, limit int, lease time.Duration) ([]*outbox.Event, error) {
+	now := time.Now()
+	result, err := claimScript.Run(ctx, o.client, []string{redisOutboxPending, redisOutboxEvents},
+		now.UnixMilli(), limit, now.Add(lease).UnixMilli()).StringSlice()
+	if err != nil {
+		return nil, fmt.Errorf("erro ao reservar eventos do outbox: %w", err)
+	}
+
+	events := make([]*outbox.Event, 0, len(result))
+	for _, data := range result {
+		var event outbox.Event
+		if err := json.Unmarshal([]byte(data), &event); err != nil {
+			return events, fmt.Errorf("erro ao desserializar evento do outbox: %w", err)
+		}
+		event.AvailableAt = now.Add(lease)
+		events = append(events, &event)
+	}
+	return events, nil
+}
+
+// Ack remove o evento publicado
+func (o *RedisOutbox) Ack(ctx context.Context, id string) error {
+	_, err := o.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.ZRem(ctx, redisOutboxPending, id)
+		pipe.HDel(ctx, redisOutboxEvents, id)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("erro ao remover evento %s do outbox: %w", id, err)
+	}
+	return nil
+}
+
+// Retry registra a falha e agenda a próxima tentativa
+func (o *RedisOutbox) Retry(ctx context.Context, id string, cause error, at time.Time) error {
+	err := retryScript.Run(ctx, o.client, []string{redisOutboxPending, redisOutboxEvents},
+		id, at.UnixMilli(), cause.Error()).Err()
+	if err != nil {
+		return fmt.Errorf("erro ao reagendar evento %s do outbox: %w", id, err)
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package memory
+
+import (
+	"context"
+	"sync"
+)
+
+// Provenance registra de onde veio uma memória: o workflow e a tarefa em que foi criada, as
+// memórias recuperadas para o prompt da tarefa e as ferramentas chamadas até a gravação
+type Provenance struct {
+	WorkflowID      string   `json:"workflow_id,omitempty" bson:"workflow_id,omitempty"`
+	TaskID          string   `json:"task_id,omitempty" bson:"task_id,omitempty"`
+	SourceMemoryIDs []string `json:"source_memory_ids,omitempty" bson:"source_memory_ids,omitempty"`
+	ToolCalls       []string `json:"tool_calls,omitempty" bson:"tool_calls,omitempty"` // Nomes das ferramentas, na ordem das chamadas
+}
+
+// IsZero indica se nenhuma proveniência foi registrada
+func (p Provenance) IsZero() bool {
+	return p.WorkflowID == "" && p.TaskID == "" && len(p.SourceMemoryIDs) == 0 && len(p.ToolCalls) == 0
+}
+
+// provenanceKey é a chave da proveniência no contexto
+type provenanceKey struct{}
+
+// trace acumula as memórias e ferramentas usadas durante uma tarefa
+type trace struct {
+	mu         sync.Mutex
+	provenance Provenance
+}
+
+// WithProvenance inicia o registro de proveniência no contexto. Workflow e tarefa vazios são
+// herdados do contexto (a tarefa herda o workflow em que roda); as memórias e ferramentas
+// registradas depois valem apenas para o novo contexto.
+func WithProvenance(ctx context.Context, p Provenance) context.Context {
+	current := ProvenanceFromContext(ctx)
+	if p.WorkflowID == "" {
+		p.WorkflowID = current.WorkflowID
+	}
+	if p.TaskID == "" {
+		p.TaskID = current.TaskID
+	}
+	return context.WithValue(ctx, provenanceKey{}, &trace{provenance: p})
+}
+
+// ProvenanceFromContext retorna uma cópia da proveniência registrada no contexto (vazia se não houver)
+func ProvenanceFromContext(ctx context.Context) Provenance {
+	t, ok := ctx.Value(provenanceKey{}).(*trace)
+	if !ok {
+		return Provenance{}
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	p := t.provenance
+	p.SourceMemoryIDs = append([]string(nil), p.SourceMemoryIDs...)
+	p.ToolCalls = append([]string(nil), p.ToolCalls...)
+	return p
+}
+
+// RecordSources registra memórias que informaram a tarefa em andamento (sem repetições).
+// Sem WithProvenance no contexto nada é registrado.
+func RecordSources(ctx context.Context, memoryIDs ...string) {
+	t, ok := ctx.Value(provenanceKey{}).(*trace)
+	if !ok {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.provenance.SourceMemoryIDs = union(t.provenance.SourceMemoryIDs, memoryIDs)
+}
+
+// RecordToolCall registra uma ferramenta chamada na tarefa em andamento. Sem WithProvenance
+// no contexto nada é registrado.
+func RecordToolCall(ctx context.Context, tool string) {
+	t, ok := ctx.Value(provenanceKey{}).(*trace)
+	if !ok {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.provenance.ToolCalls = append(t.provenance.ToolCalls, tool)
+}
@@ -0,0 +1,95 @@
+package memory
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestWithProvenanceInheritance(t *testing.T) {
+	workflow := WithProvenance(context.Background(), Provenance{WorkflowID: "wf-1"})
+	RecordSources(workflow, "m-0")
+
+	cases := []struct {
+		name string
+		ctx  context.Context
+		p    Provenance
+		want Provenance
+	}{
+		{"sem contexto anterior", context.Background(), Provenance{TaskID: "t-1"}, Provenance{TaskID: "t-1"}},
+		{"tarefa herda o workflow", workflow, Provenance{TaskID: "t-1"}, Provenance{WorkflowID: "wf-1", TaskID: "t-1"}},
+		{"workflow informado prevalece", workflow, Provenance{WorkflowID: "wf-2", TaskID: "t-1"}, Provenance{WorkflowID: "wf-2", TaskID: "t-1"}},
+		{"fontes não são herdadas", workflow, Provenance{}, Provenance{WorkflowID: "wf-1"}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := ProvenanceFromContext(WithProvenance(c.ctx, c.p)); !reflect.DeepEqual(got, c.want) {
+				t.Fatalf("proveniência %+v, esperava %+v", got, c.want)
+			}
+		})
+	}
+}
+
+func TestRecordProvenance(t *testing.T) {
+	ctx := WithProvenance(context.Background(), Provenance{WorkflowID: "wf-1", TaskID: "t-1"})
+	RecordSources(ctx, "m-1", "m-2")
+	RecordSources(ctx, "m-2", "m-3", "m-1")
+	RecordToolCall(ctx, "search")
+	RecordToolCall(ctx, "scrape")
+	RecordToolCall(ctx, "search")
+
+	want := Provenance{
+		WorkflowID:      "wf-1",
+		TaskID:          "t-1",
+		SourceMemoryIDs: []string{"m-1", "m-2", "m-3"},
+		ToolCalls:       []string{"search", "scrape", "search"},
+	}
+	got := ProvenanceFromContext(ctx)
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("proveniência %+v, esperava %+v", got, want)
+	}
+
+	// O valor retornado é uma cópia: alterá-lo não muda o registro do contexto
+	got.SourceMemoryIDs[0] = "alterada"
+	got.ToolCalls = append(got.ToolCalls[:0], "alterada")
+	if again := ProvenanceFromContext(ctx); !reflect.DeepEqual(again, want) {
+		t.Fatalf("a cópia alterou o contexto: %+v", again)
+	}
+
+	// O registro da tarefa não vaza para o contexto do workflow
+	parent := WithProvenance(context.Background(), Provenance{WorkflowID: "wf-1"})
+	RecordToolCall(WithProvenance(parent, Provenance{TaskID: "t-2"}), "publish")
+	if p := ProvenanceFromContext(parent); len(p.ToolCalls) != 0 {
+		t.Fatalf("a tarefa alterou o workflow: %+v", p)
+	}
+}
+
+func TestRecordProvenanceWithoutTrace(t *testing.T) {
+	ctx := context.Background()
+	RecordSources(ctx, "m-1")
+	RecordToolCall(ctx, "search")
+	if p := ProvenanceFromContext(ctx); !p.IsZero() {
+		t.Fatalf("sem WithProvenance nada deveria ser registrado: %+v", p)
+	}
+}
+
+func TestRecordProvenanceConcurrent(t *testing.T) {
+	ctx := WithProvenance(context.Background(), Provenance{TaskID: "t-1"})
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			RecordToolCall(ctx, "search")
+			RecordSources(ctx, "m-1")
+			ProvenanceFromContext(ctx)
+		}()
+	}
+	wg.Wait()
+
+	p := ProvenanceFromContext(ctx)
+	if len(p.ToolCalls) != 20 || len(p.SourceMemoryIDs) != 1 {
+		t.Fatalf("registro concorrente inesperado: %+v", p)
+	}
+}
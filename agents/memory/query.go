@@ -0,0 +1,137 @@
+package memory
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"time"
+
+	"github.com/suissa/HiveMind/agents/errs"
+)
+
+// DefaultPageSize é o tamanho de página usado quando Page.Limit não é informado
+const DefaultPageSize = 50
+
+// MaxPageSize é o maior tamanho de página aceito
+const MaxPageSize = 500
+
+// Page seleciona uma página de resultados, da memória mais recente para a mais antiga
+type Page struct {
+	Limit  int    // Memórias por página (padrão DefaultPageSize, máximo MaxPageSize)
+	Cursor string // Cursor retornado em MemoryPage.NextCursor; vazio inicia na primeira página
+}
+
+// MemoryPage é uma página de memórias
+type MemoryPage struct {
+	Memories   []*Memory `json:"memories"`
+	NextCursor string    `json:"next_cursor,omitempty"` // Vazio na última página
+}
+
+// ImportanceBucket conta as memórias em uma faixa de importância [Min, Max)
+type ImportanceBucket struct {
+	Min   float64 `json:"min"`
+	Max   float64 `json:"max"`
+	Count int     `json:"count"`
+}
+
+// AgentStats agrega as memórias de longo prazo de um agente
+type AgentStats struct {
+	AgentID       string             `json:"agent_id"`
+	Count         int                `json:"count"`
+	AvgImportance float64            `json:"avg_importance"`
+	Importance    []ImportanceBucket `json:"importance"` // Distribuição em faixas de 0.1
+}
+
+// PagedSearcher é implementado pelos gerenciadores com busca paginada
+type PagedSearcher interface {
+	SearchMemoriesPage(ctx context.Context, agentID string, tags []string, page Page) (*MemoryPage, error)
+}
+
+// SimilarityOptions filtra a busca por similaridade. Os dois limites podem ser combinados;
+// vale o mais restritivo.
+type SimilarityOptions struct {
+	Limit         int     // Máximo de memórias retornadas (0 usa o limite do Weaviate)
+	MinSimilarity float64 // Similaridade mínima, entre 0 e 1 (0 não filtra)
+	MaxDistance   float64 // Distância cosseno máxima, entre 0 e 2 (0 não filtra)
+}
+
+// Validate verifica os limites da busca
+func (o SimilarityOptions) Validate() error {
+	switch {
+	case o.Limit < 0:
+		return errs.New(errs.ErrValidation, "memory.SimilarityOptions", "limit não pode ser negativo: %d", o.Limit)
+	case o.MinSimilarity < 0 || o.MinSimilarity > 1:
+		return errs.New(errs.ErrValidation, "memory.SimilarityOptions", "min_similarity fora do intervalo [0, 1]: %v", o.MinSimilarity)
+	case o.MaxDistance < 0 || o.MaxDistance > 2:
+		return errs.New(errs.ErrValidation, "memory.SimilarityOptions", "max_distance fora do intervalo [0, 2]: %v", o.MaxDistance)
+	}
+	return nil
+}
+
+// distance retorna a distância máxima equivalente aos limites e se há algum limite
+func (o SimilarityOptions) distance() (float64, bool) {
+	distance, limited := o.MaxDistance, o.MaxDistance > 0
+	if o.MinSimilarity > 0 {
+		if fromSimilarity := 2 * (1 - o.MinSimilarity); !limited || fromSimilarity < distance {
+			distance, limited = fromSimilarity, true
+		}
+	}
+	return distance, limited
+}
+
+// SimilaritySearcher é implementado pelos gerenciadores com busca por similaridade com
+// limites e pontuação: as memórias retornadas trazem Similarity e Distance
+type SimilaritySearcher interface {
+	SearchSimilar(ctx context.Context, query string, options SimilarityOptions) ([]*Memory, error)
+}
+
+// StatsProvider é implementado pelos gerenciadores com consultas agregadas por agente
+type StatsProvider interface {
+	// MemoryStats agrega as memórias do agente informado, ou de todos os agentes se vazio
+	MemoryStats(ctx context.Context, agentID string) ([]AgentStats, error)
+}
+
+// limit aplica o padrão e o máximo ao tamanho da página
+func (p Page) limit() int {
+	switch {
+	case p.Limit <= 0:
+		return DefaultPageSize
+	case p.Limit > MaxPageSize:
+		return MaxPageSize
+	}
+	return p.Limit
+}
+
+// pageCursor é a posição da última memória de uma página
+type pageCursor struct {
+	Timestamp time.Time `json:"t"`
+	ID        string    `json:"id"`
+}
+
+// encodeCursor gera o cursor opaco da próxima página
+func encodeCursor(memory *Memory) string {
+	data, _ := json.Marshal(pageCursor{Timestamp: memory.Timestamp, ID: memory.ID})
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeCursor lê o cursor recebido do cliente
+func decodeCursor(cursor string) (*pageCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, errs.New(errs.ErrValidation, "memory.Page", "cursor inválido")
+	}
+	var c pageCursor
+	if err := json.Unmarshal(data, &c); err != nil || c.ID == "" {
+		return nil, errs.New(errs.ErrValidation, "memory.Page", "cursor inválido")
+	}
+	return &c, nil
+}
+
+// importanceBuckets cria as dez faixas de importância vazias
+func importanceBuckets() []ImportanceBucket {
+	buckets := make([]ImportanceBucket, 10)
+	for i := range buckets {
+		buckets[i] = ImportanceBucket{Min: float64(i) / 10, Max: float64(i+1) / 10}
+	}
+	return buckets
+}
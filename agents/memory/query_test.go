@@ -0,0 +1,127 @@
+package memory
+
+import (
+	"encoding/base64"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/suissa/HiveMind/agents/errs"
+)
+
+func TestPageLimit(t *testing.T) {
+	cases := []struct {
+		limit, want int
+	}{
+		{0, DefaultPageSize},
+		{-1, DefaultPageSize},
+		{1, 1},
+		{MaxPageSize, MaxPageSize},
+		{MaxPageSize + 1, MaxPageSize},
+	}
+	for _, c := range cases {
+		if got := (Page{Limit: c.limit}).limit(); got != c.want {
+			t.Errorf("Page{Limit: %d}.limit() = %d, esperava %d", c.limit, got, c.want)
+		}
+	}
+}
+
+func TestCursorRoundTrip(t *testing.T) {
+	memory := &Memory{ID: "m-42", Timestamp: time.Date(2024, 5, 1, 12, 30, 0, 123456789, time.UTC)}
+	cursor, err := decodeCursor(encodeCursor(memory))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cursor.ID != memory.ID || !cursor.Timestamp.Equal(memory.Timestamp) {
+		t.Fatalf("cursor %+v, esperava %s em %s", cursor, memory.ID, memory.Timestamp)
+	}
+}
+
+func TestDecodeCursorInvalid(t *testing.T) {
+	cases := []struct {
+		name   string
+		cursor string
+	}{
+		{"base64 inválido", "%%%"},
+		{"JSON inválido", base64.RawURLEncoding.EncodeToString([]byte("não é json"))},
+		{"sem ID", base64.RawURLEncoding.EncodeToString([]byte(`{"t":"2024-05-01T00:00:00Z"}`))},
+		{"data inválida", base64.RawURLEncoding.EncodeToString([]byte(`{"t":"ontem","id":"m-1"}`))},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if _, err := decodeCursor(c.cursor); !errors.Is(err, errs.ErrValidation) {
+				t.Fatalf("esperava ErrValidation, obtido %v", err)
+			}
+		})
+	}
+}
+
+func TestImportanceBuckets(t *testing.T) {
+	buckets := importanceBuckets()
+	if len(buckets) != 10 {
+		t.Fatalf("esperava 10 faixas, obtidas %d", len(buckets))
+	}
+	for i, bucket := range buckets {
+		if bucket.Count != 0 || bucket.Max <= bucket.Min {
+			t.Errorf("faixa %d inválida: %+v", i, bucket)
+		}
+		if i > 0 && bucket.Min != buckets[i-1].Max {
+			t.Errorf("faixa %d não continua a anterior: %+v", i, bucket)
+		}
+	}
+	if buckets[0].Min != 0 || buckets[9].Max != 1 {
+		t.Errorf("as faixas deveriam cobrir [0, 1]: %+v", buckets)
+	}
+}
+
+func TestSimilarityOptionsValidate(t *testing.T) {
+	cases := []struct {
+		name    string
+		options SimilarityOptions
+		wantErr bool
+	}{
+		{"sem limites", SimilarityOptions{}, false},
+		{"limites nos extremos", SimilarityOptions{Limit: 10, MinSimilarity: 1, MaxDistance: 2}, false},
+		{"limit negativo", SimilarityOptions{Limit: -1}, true},
+		{"similaridade negativa", SimilarityOptions{MinSimilarity: -0.1}, true},
+		{"similaridade acima de 1", SimilarityOptions{MinSimilarity: 1.1}, true},
+		{"distância negativa", SimilarityOptions{MaxDistance: -0.1}, true},
+		{"distância acima de 2", SimilarityOptions{MaxDistance: 2.1}, true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := c.options.Validate()
+			if c.wantErr != (err != nil) {
+				t.Fatalf("Validate() = %v, esperava erro: %v", err, c.wantErr)
+			}
+			if err != nil && !errors.Is(err, errs.ErrValidation) {
+				t.Fatalf("esperava ErrValidation, obtido %v", err)
+			}
+		})
+	}
+}
+
+func TestSimilarityOptionsDistance(t *testing.T) {
+	cases := []struct {
+		name    string
+		options SimilarityOptions
+		want    float64
+		limited bool
+	}{
+		{"sem limites", SimilarityOptions{}, 0, false},
+		{"só a distância", SimilarityOptions{MaxDistance: 0.5}, 0.5, true},
+		{"só a similaridade", SimilarityOptions{MinSimilarity: 0.8}, 0.4, true},
+		{"similaridade mais restritiva", SimilarityOptions{MinSimilarity: 0.9, MaxDistance: 0.5}, 0.2, true},
+		{"distância mais restritiva", SimilarityOptions{MinSimilarity: 0.5, MaxDistance: 0.3}, 0.3, true},
+		{"similaridade total", SimilarityOptions{MinSimilarity: 1}, 0, true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got, limited := c.options.distance()
+			if limited != c.limited || math.Abs(got-c.want) > 1e-9 {
+				t.Fatalf("distance() = %v, %v; esperava %v, %v", got, limited, c.want, c.limited)
+			}
+		})
+	}
+}
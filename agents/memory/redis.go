@@ -3,10 +3,15 @@ package memory
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/go-redis/redis/v8"
+
+	"github.com/suissa/HiveMind/agents/errs"
+	"github.com/suissa/HiveMind/agents/outbox"
+	"github.com/suissa/HiveMind/agents/tenant"
 )
 
 // RedisMemoryManager gerencia memórias usando Redis
@@ -15,15 +20,15 @@ type RedisMemoryManager struct {
 }
 
 // NewRedisMemoryManager cria um novo gerenciador de memória Redis
-func NewRedisMemoryManager(redisURL string) (*RedisMemoryManager, error) {
+func NewRedisMemoryManager(ctx context.Context, redisURL string) (*RedisMemoryManager, error) {
 	opt, err := redis.ParseURL(redisURL)
 	if err != nil {
-		return nil, fmt.Errorf("erro ao analisar URL do Redis: %v", err)
+		return nil, fmt.Errorf("erro ao analisar URL do Redis: %w", err)
 	}
 
 	client := redis.NewClient(opt)
-	if err := client.Ping(context.Background()).Err(); err != nil {
-		return nil, fmt.Errorf("erro ao conectar ao Redis: %v", err)
+	if err := client.Ping(ctx).Err(); err != nil {
+		return nil, fmt.Errorf("erro ao conectar ao Redis: %w", err)
 	}
 
 	return &RedisMemoryManager{
@@ -31,12 +36,27 @@ func NewRedisMemoryManager(redisURL string) (*RedisMemoryManager, error) {
 	}, nil
 }
 
+// key monta uma chave do Redis isolada pelo tenant do contexto
+func (m *RedisMemoryManager) key(ctx context.Context, format string, args ...interface{}) string {
+	return tenant.Namespace(tenant.FromContext(ctx), fmt.Sprintf(format, args...))
+}
+
 // StoreMemory armazena uma memória no Redis
 func (m *RedisMemoryManager) StoreMemory(ctx context.Context, memory *Memory) error {
-	key := fmt.Sprintf("memory:%s:%s", memory.AgentID, memory.ID)
+	return m.StoreMemoryWithEvents(ctx, memory, nil)
+}
+
+// StoreMemoryWithEvents armazena a memória, os seus índices e os eventos do outbox em uma
+// única transação (MULTI/EXEC)
+func (m *RedisMemoryManager) StoreMemoryWithEvents(ctx context.Context, memory *Memory, events []*outbox.Event) error {
+	if memory.TenantID == "" {
+		memory.TenantID = tenant.FromContext(ctx)
+	}
+
+	key := m.key(ctx, "memory:%s:%s", memory.AgentID, memory.ID)
 	data, err := json.Marshal(memory)
 	if err != nil {
-		return fmt.Errorf("erro ao serializar memória: %v", err)
+		return fmt.Errorf("erro ao serializar memória: %w", err)
 	}
 
 	// Define o TTL padrão de 24 horas se não especificado
@@ -45,25 +65,23 @@ func (m *RedisMemoryManager) StoreMemory(ctx context.Context, memory *Memory) er
 		ttl = 24 * time.Hour
 	}
 
-	err = m.client.Set(ctx, key, data, ttl).Err()
-	if err != nil {
-		return fmt.Errorf("erro ao armazenar memória: %v", err)
-	}
+	_, err = m.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, key, data, ttl)
 
-	// Adiciona à lista de memórias do agente
-	agentKey := fmt.Sprintf("agent:%s:memories", memory.AgentID)
-	err = m.client.SAdd(ctx, agentKey, memory.ID).Err()
-	if err != nil {
-		return fmt.Errorf("erro ao adicionar à lista de memórias: %v", err)
-	}
+		// Adiciona à lista de memórias do agente; os índices expiram com a memória mais duradoura
+		agentKey := m.key(ctx, "agent:%s:memories", memory.AgentID)
+		indexAddScript.Eval(ctx, pipe, []string{agentKey}, memory.ID, ttl.Milliseconds())
 
-	// Adiciona índices para as tags
-	for _, tag := range memory.Tags {
-		tagKey := fmt.Sprintf("tag:%s:%s", memory.AgentID, tag)
-		err = m.client.SAdd(ctx, tagKey, memory.ID).Err()
-		if err != nil {
-			return fmt.Errorf("erro ao adicionar índice de tag: %v", err)
+		// Adiciona índices para as tags
+		for _, tag := range memory.Tags {
+			tagKey := m.key(ctx, "tag:%s:%s", memory.AgentID, tag)
+			indexAddScript.Eval(ctx, pipe, []string{tagKey}, memory.ID, ttl.Milliseconds())
 		}
+
+		return m.Outbox().enqueue(ctx, pipe, events)
+	})
+	if err != nil {
+		return fmt.Errorf("erro ao armazenar memória: %w", err)
 	}
 
 	return nil
@@ -71,18 +89,18 @@ func (m *RedisMemoryManager) StoreMemory(ctx context.Context, memory *Memory) er
 
 // GetMemory recupera uma memória específica
 func (m *RedisMemoryManager) GetMemory(ctx context.Context, agentID, memoryID string) (*Memory, error) {
-	key := fmt.Sprintf("memory:%s:%s", agentID, memoryID)
+	key := m.key(ctx, "memory:%s:%s", agentID, memoryID)
 	data, err := m.client.Get(ctx, key).Bytes()
 	if err != nil {
 		if err == redis.Nil {
-			return nil, fmt.Errorf("memória não encontrada")
+			return nil, errs.New(errs.ErrNotFound, "redis.GetMemory", "memória não encontrada")
 		}
-		return nil, fmt.Errorf("erro ao recuperar memória: %v", err)
+		return nil, fmt.Errorf("erro ao recuperar memória: %w", err)
 	}
 
 	var memory Memory
 	if err := json.Unmarshal(data, &memory); err != nil {
-		return nil, fmt.Errorf("erro ao deserializar memória: %v", err)
+		return nil, fmt.Errorf("erro ao deserializar memória: %w", err)
 	}
 
 	return &memory, nil
@@ -95,12 +113,12 @@ func (m *RedisMemoryManager) SearchMemories(ctx context.Context, agentID string,
 		// Busca a interseção de memórias com todas as tags
 		var keys []string
 		for _, tag := range tags {
-			keys = append(keys, fmt.Sprintf("tag:%s:%s", agentID, tag))
+			keys = append(keys, m.key(ctx, "tag:%s:%s", agentID, tag))
 		}
 		memoryIDs, _ = m.client.SInter(ctx, keys...).Result()
 	} else {
 		// Se não houver tags, retorna todas as memórias do agente
-		agentKey := fmt.Sprintf("agent:%s:memories", agentID)
+		agentKey := m.key(ctx, "agent:%s:memories", agentID)
 		memoryIDs, _ = m.client.SMembers(ctx, agentKey).Result()
 	}
 
@@ -109,6 +127,11 @@ func (m *RedisMemoryManager) SearchMemories(ctx context.Context, agentID string,
 		memory, err := m.GetMemory(ctx, agentID, id)
 		if err == nil {
 			memories = append(memories, memory)
+			continue
+		}
+		// A memória expirou: remove o ID pendente dos índices consultados
+		if errors.Is(err, errs.ErrNotFound) {
+			m.unindex(ctx, agentID, id, tags)
 		}
 	}
 
@@ -118,26 +141,26 @@ func (m *RedisMemoryManager) SearchMemories(ctx context.Context, agentID string,
 // UpdateMemory atualiza uma memória existente
 func (m *RedisMemoryManager) UpdateMemory(ctx context.Context, memory *Memory) error {
 	// Recupera o TTL restante da memória existente
-	key := fmt.Sprintf("memory:%s:%s", memory.AgentID, memory.ID)
+	key := m.key(ctx, "memory:%s:%s", memory.AgentID, memory.ID)
 	ttl, err := m.client.TTL(ctx, key).Result()
 	if err != nil {
-		return fmt.Errorf("erro ao recuperar TTL: %v", err)
+		return fmt.Errorf("erro ao recuperar TTL: %w", err)
 	}
 
 	// Se a memória não existe ou expirou, retorna erro
 	if ttl < 0 {
-		return fmt.Errorf("memória não encontrada ou expirada")
+		return errs.New(errs.ErrNotFound, "redis.UpdateMemory", "memória não encontrada ou expirada")
 	}
 
 	// Atualiza a memória mantendo o TTL original
 	data, err := json.Marshal(memory)
 	if err != nil {
-		return fmt.Errorf("erro ao serializar memória: %v", err)
+		return fmt.Errorf("erro ao serializar memória: %w", err)
 	}
 
 	err = m.client.Set(ctx, key, data, ttl).Err()
 	if err != nil {
-		return fmt.Errorf("erro ao atualizar memória: %v", err)
+		return fmt.Errorf("erro ao atualizar memória: %w", err)
 	}
 
 	return nil
@@ -145,35 +168,72 @@ func (m *RedisMemoryManager) UpdateMemory(ctx context.Context, memory *Memory) e
 
 // DeleteMemory remove uma memória
 func (m *RedisMemoryManager) DeleteMemory(ctx context.Context, agentID, memoryID string) error {
-	key := fmt.Sprintf("memory:%s:%s", agentID, memoryID)
+	key := m.key(ctx, "memory:%s:%s", agentID, memoryID)
+
+	// Recupera as tags para limpar os índices; uma memória já expirada é limpa pela varredura
+	var tags []string
+	if memory, err := m.GetMemory(ctx, agentID, memoryID); err == nil {
+		tags = memory.Tags
+	}
 
 	// Remove a memória
 	err := m.client.Del(ctx, key).Err()
 	if err != nil {
-		return fmt.Errorf("erro ao deletar memória: %v", err)
+		return fmt.Errorf("erro ao deletar memória: %w", err)
 	}
 
-	// Remove da lista de memórias do agente
-	agentKey := fmt.Sprintf("agent:%s:memories", agentID)
-	err = m.client.SRem(ctx, agentKey, memoryID).Err()
-	if err != nil {
-		return fmt.Errorf("erro ao remover da lista de memórias: %v", err)
-	}
+	// Remove da lista de memórias do agente e dos índices de tags
+	return m.unindex(ctx, agentID, memoryID, tags)
+}
 
-	return nil
+// deleteBySubject percorre as memórias do tenant do contexto e remove as que referenciam
+// o titular, inclusive dos índices de agente e de tags
+func (m *RedisMemoryManager) deleteBySubject(ctx context.Context, terms []string) ([]string, error) {
+	var deleted []string
+	iter := m.client.Scan(ctx, 0, m.key(ctx, "memory:*"), 100).Iterator()
+	for iter.Next(ctx) {
+		data, err := m.client.Get(ctx, iter.Val()).Bytes()
+		if err == redis.Nil {
+			continue
+		}
+		if err != nil {
+			return deleted, fmt.Errorf("erro ao recuperar memória: %w", err)
+		}
+
+		var memory Memory
+		if err := json.Unmarshal(data, &memory); err != nil {
+			return deleted, fmt.Errorf("erro ao deserializar memória: %w", err)
+		}
+		if !references(&memory, terms) {
+			continue
+		}
+
+		if err := m.DeleteMemory(ctx, memory.AgentID, memory.ID); err != nil {
+			return deleted, err
+		}
+		deleted = append(deleted, memory.ID)
+	}
+	if err := iter.Err(); err != nil {
+		return deleted, fmt.Errorf("erro ao percorrer memórias: %w", err)
+	}
+	return deleted, nil
 }
 
-// PruneMemories remove memórias expiradas
+// PruneMemories remove dos índices do agente os IDs de memórias expiradas. O Redis já remove
+// as memórias expiradas; os índices de agente e de tags são varridos com SCAN e SSCAN.
 func (m *RedisMemoryManager) PruneMemories(ctx context.Context, agentID string) error {
-	// O Redis já remove automaticamente as chaves expiradas
-	// Esta função é mantida para compatibilidade com a interface
+	for _, pattern := range []string{"agent:%s:memories", "tag:%s:*"} {
+		if _, _, err := m.pruneIndexes(ctx, fmt.Sprintf(pattern, agentID)); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
 // Close fecha a conexão com o Redis
 func (m *RedisMemoryManager) Close(ctx context.Context) error {
 	if err := m.client.Close(); err != nil {
-		return fmt.Errorf("erro ao fechar conexão com Redis: %v", err)
+		return fmt.Errorf("erro ao fechar conexão com Redis: %w", err)
 	}
 	return nil
 }
@@ -0,0 +1,419 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/weaviate/weaviate-go-client/v4/weaviate/fault"
+	"github.com/weaviate/weaviate-go-client/v4/weaviate/filters"
+	"github.com/weaviate/weaviate-go-client/v4/weaviate/graphql"
+	"github.com/weaviate/weaviate/entities/models"
+
+	"github.com/suissa/HiveMind/agents/progress"
+	"github.com/suissa/HiveMind/agents/tenant"
+)
+
+// ClassAliases guarda qual classe física do Weaviate atende cada classe lógica. A troca do
+// alias é atômica: as instâncias passam a usar a nova classe na operação seguinte. Durante
+// uma reindexação, as remoções de objetos do alias ficam registradas para serem repetidas na
+// nova classe, já que a cópia pode ter passado por eles antes da remoção. O alias guarda
+// também o modelo de embeddings dos vetores da classe, para que todas as instâncias vetorizem
+// com ele depois da troca, e não só a que reindexou.
+type ClassAliases interface {
+	// Resolve retorna a classe física do alias, ou "" se ele não foi definido
+	Resolve(ctx context.Context, alias string) (string, error)
+	// Model retorna o modelo de embeddings da classe do alias, ou "" se não foi registrado
+	Model(ctx context.Context, alias string) (string, error)
+	// Switch aponta o alias para a classe, cujos vetores são do modelo informado ("" se não
+	// registrado), e retorna a classe anterior ("" se não havia)
+	Switch(ctx context.Context, alias, class, model string) (string, error)
+	// BeginReindex passa a registrar as remoções de objetos do alias
+	BeginReindex(ctx context.Context, alias string) error
+	// RecordDeletion registra a remoção do objeto, se o alias estiver em reindexação
+	RecordDeletion(ctx context.Context, alias, objectID string) error
+	// Deletions retorna as remoções registradas desde BeginReindex
+	Deletions(ctx context.Context, alias string) ([]string, error)
+	// EndReindex para de registrar as remoções do alias e descarta as registradas
+	EndReindex(ctx context.Context, alias string) error
+}
+
+// RedisClassAliases guarda os aliases no Redis, compartilhados entre as instâncias
+type RedisClassAliases struct {
+	client *redis.Client
+}
+
+// NewRedisClassAliases cria os aliases sobre o cliente Redis
+func NewRedisClassAliases(client *redis.Client) *RedisClassAliases {
+	return &RedisClassAliases{client: client}
+}
+
+// aliasKey retorna a chave do alias no Redis
+func aliasKey(alias string) string {
+	return "hivemind:weaviate:alias:" + alias
+}
+
+// Resolve implementa ClassAliases
+func (a *RedisClassAliases) Resolve(ctx context.Context, alias string) (string, error) {
+	class, err := a.client.Get(ctx, aliasKey(alias)).Result()
+	if errors.Is(err, redis.Nil) {
+		return "", nil
+	}
+	return class, err
+}
+
+// modelKey retorna a chave do modelo de embeddings da classe do alias
+func modelKey(alias string) string {
+	return aliasKey(alias) + ":model"
+}
+
+// Model implementa ClassAliases
+func (a *RedisClassAliases) Model(ctx context.Context, alias string) (string, error) {
+	model, err := a.client.Get(ctx, modelKey(alias)).Result()
+	if errors.Is(err, redis.Nil) {
+		return "", nil
+	}
+	return model, err
+}
+
+// Switch implementa ClassAliases com GETSET numa transação que grava também o modelo, para
+// que nenhuma instância veja a nova classe com o modelo anterior
+func (a *RedisClassAliases) Switch(ctx context.Context, alias, class, model string) (string, error) {
+	pipe := a.client.TxPipeline()
+	getSet := pipe.GetSet(ctx, aliasKey(alias), class)
+	if model != "" {
+		pipe.Set(ctx, modelKey(alias), model, 0)
+	} else {
+		pipe.Del(ctx, modelKey(alias))
+	}
+	if _, err := pipe.Exec(ctx); err != nil && !errors.Is(err, redis.Nil) {
+		return "", err
+	}
+	previous, err := getSet.Result()
+	if errors.Is(err, redis.Nil) {
+		return "", nil
+	}
+	return previous, err
+}
+
+// reindexTTL limita o registro de remoções de uma reindexação interrompida sem EndReindex
+const reindexTTL = 24 * time.Hour
+
+// reindexKey retorna a chave que marca o alias em reindexação
+func reindexKey(alias string) string {
+	return "hivemind:weaviate:reindex:" + alias
+}
+
+// deletionsKey retorna o conjunto com as remoções registradas durante a reindexação
+func deletionsKey(alias string) string {
+	return "hivemind:weaviate:reindex:" + alias + ":deleted"
+}
+
+// recordDeletionScript adiciona a remoção ao conjunto apenas se o alias estiver em reindexação
+var recordDeletionScript = redis.NewScript(`
+local ttl = redis.call('PTTL', KEYS[1])
+if ttl == -2 then
+	return 0
+end
+redis.call('SADD', KEYS[2], ARGV[1])
+if ttl > 0 then
+	redis.call('PEXPIRE', KEYS[2], ttl)
+end
+return 1
+`)
+
+// BeginReindex implementa ClassAliases
+func (a *RedisClassAliases) BeginReindex(ctx context.Context, alias string) error {
+	pipe := a.client.TxPipeline()
+	pipe.Del(ctx, deletionsKey(alias))
+	pipe.Set(ctx, reindexKey(alias), time.Now().Format(time.RFC3339), reindexTTL)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// RecordDeletion implementa ClassAliases
+func (a *RedisClassAliases) RecordDeletion(ctx context.Context, alias, objectID string) error {
+	return recordDeletionScript.Run(ctx, a.client, []string{reindexKey(alias), deletionsKey(alias)}, objectID).Err()
+}
+
+// Deletions implementa ClassAliases
+func (a *RedisClassAliases) Deletions(ctx context.Context, alias string) ([]string, error) {
+	return a.client.SMembers(ctx, deletionsKey(alias)).Result()
+}
+
+// EndReindex implementa ClassAliases
+func (a *RedisClassAliases) EndReindex(ctx context.Context, alias string) error {
+	return a.client.Del(ctx, reindexKey(alias), deletionsKey(alias)).Err()
+}
+
+// ReindexOptions controla a reindexação da memória semântica
+type ReindexOptions struct {
+	BatchSize    int    // Objetos por página; zero usa o BatchSize da configuração
+	DropPrevious bool   // Remove a classe anterior depois da troca do alias
+	Model        string // Modelo do embedder, registrado no alias junto com a nova classe
+}
+
+// ReindexReport registra a reindexação da classe de um tenant
+type ReindexReport struct {
+	Namespace    string        `json:"namespace"`
+	Alias        string        `json:"alias"`           // Classe lógica usada pelos agentes
+	Previous     string        `json:"previous"`        // Classe física anterior
+	Class        string        `json:"class"`           // Nova classe física
+	Model        string        `json:"model,omitempty"` // Modelo de embeddings da nova classe
+	Reindexed    int           `json:"reindexed"`       // Objetos copiados com os novos vetores
+	Dropped      bool          `json:"dropped"`         // A classe anterior foi removida
+	CacheCleared int           `json:"cache_cleared"`   // Entradas descartadas do cache semântico
+	Duration     time.Duration `json:"duration"`
+}
+
+// Reindex recalcula com o embedder os vetores de todas as memórias da classe do tenant do
+// contexto, gravando-as numa nova classe, e então troca o alias para ela. Os agentes seguem
+// usando a classe anterior até a troca. Memórias criadas durante a cópia são copiadas numa
+// segunda passada, e as remoções feitas durante a reindexação (inclusive as exclusões por
+// titular) são repetidas na nova classe antes da troca e ao final. Alterações feitas durante
+// a cópia não são propagadas, então a reindexação deve rodar numa janela de pouca escrita.
+// Com options.Model, o modelo é registrado no alias e as instâncias passam a vetorizar a nova
+// classe com o embedder desse modelo (ver AddEmbedder); esta passa a usar o embedder informado.
+func (m *SemanticMemoryManager) Reindex(ctx context.Context, embedder Embedder, options ReindexOptions) (*ReindexReport, error) {
+	if m.config.Aliases == nil {
+		return nil, fmt.Errorf("a reindexação requer aliases de classe (SemanticMemoryConfig.Aliases)")
+	}
+	start := time.Now()
+	alias := tenant.ClassName(tenant.FromContext(ctx), m.config.Class)
+	source, err := m.className(ctx)
+	if err != nil {
+		return nil, err
+	}
+	report := &ReindexReport{
+		Namespace: tenant.FromContext(ctx),
+		Alias:     alias,
+		Previous:  source,
+		Class:     fmt.Sprintf("%s_V%d", alias, start.Unix()),
+		Model:     options.Model,
+	}
+	if err := m.ensureClass(ctx, report.Class); err != nil {
+		return report, fmt.Errorf("erro ao configurar classe %s: %v", report.Class, err)
+	}
+	if err := m.config.Aliases.BeginReindex(ctx, alias); err != nil {
+		return report, fmt.Errorf("erro ao iniciar a reindexação do alias %s: %w", alias, err)
+	}
+	defer m.config.Aliases.EndReindex(context.WithoutCancel(ctx), alias)
+
+	batchSize := options.BatchSize
+	if batchSize <= 0 {
+		batchSize = m.config.BatchSize
+	}
+	if batchSize <= 0 {
+		batchSize = 100
+	}
+	total, err := m.count(ctx, source)
+	if err != nil {
+		return report, err
+	}
+	counter := progress.NewCounter(ctx, total)
+
+	copied := make(map[string]bool)
+	if err := m.copyObjects(ctx, embedder, source, report.Class, batchSize, copied, counter); err != nil {
+		report.Reindexed = len(copied)
+		return report, err
+	}
+	replayed := make(map[string]bool)
+	if err := m.replayDeletions(ctx, alias, report.Class, replayed); err != nil {
+		report.Reindexed = len(copied)
+		return report, err
+	}
+	if options.Model != "" {
+		m.AddEmbedder(embedder, options.Model)
+	}
+	previous, err := m.config.Aliases.Switch(ctx, alias, report.Class, options.Model)
+	if err != nil {
+		report.Reindexed = len(copied)
+		return report, fmt.Errorf("erro ao trocar o alias %s: %w", alias, err)
+	}
+	if previous != "" {
+		report.Previous = previous
+	}
+	// Segunda passada: memórias gravadas na classe anterior antes da troca do alias
+	err = m.copyObjects(ctx, embedder, report.Previous, report.Class, batchSize, copied, counter)
+	report.Reindexed = len(copied)
+	if err != nil {
+		return report, err
+	}
+	// Remoções feitas na classe anterior depois da primeira repetição, ou de objetos que a
+	// segunda passada copiou de novo
+	if err := m.replayDeletions(ctx, alias, report.Class, replayed); err != nil {
+		return report, err
+	}
+
+	if options.DropPrevious && report.Previous != report.Class {
+		if err := m.client.Schema().ClassDeleter().WithClassName(report.Previous).Do(ctx); err != nil {
+			return report, fmt.Errorf("erro ao remover classe %s: %w", report.Previous, err)
+		}
+		m.mu.Lock()
+		delete(m.classes, report.Previous)
+		m.mu.Unlock()
+		report.Dropped = true
+	}
+	report.Duration = time.Since(start)
+	return report, nil
+}
+
+// replayDeletions remove da classe as remoções registradas no alias durante a reindexação,
+// exceto as já repetidas. Objetos que não chegaram a ser copiados são ignorados.
+func (m *SemanticMemoryManager) replayDeletions(ctx context.Context, alias, class string, replayed map[string]bool) error {
+	ids, err := m.config.Aliases.Deletions(ctx, alias)
+	if err != nil {
+		return fmt.Errorf("erro ao obter as remoções do alias %s: %w", alias, err)
+	}
+	for _, id := range ids {
+		if replayed[id] {
+			continue
+		}
+		err := m.client.Data().Deleter().WithClassName(class).WithID(id).Do(ctx)
+		var clientErr *fault.WeaviateClientError
+		if err != nil && !(errors.As(err, &clientErr) && clientErr.StatusCode == http.StatusNotFound) {
+			return fmt.Errorf("erro ao remover da classe %s o objeto %s: %w", class, id, err)
+		}
+		replayed[id] = true
+	}
+	return nil
+}
+
+// count retorna a quantidade de objetos da classe
+func (m *SemanticMemoryManager) count(ctx context.Context, class string) (int, error) {
+	result, err := m.client.GraphQL().Aggregate().
+		WithClassName(class).
+		WithFields(graphql.Field{Name: "meta", Fields: []graphql.Field{{Name: "count"}}}).
+		Do(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("erro ao contar objetos da classe %s: %w", class, err)
+	}
+	if len(result.Errors) > 0 {
+		return 0, fmt.Errorf("erro ao contar objetos da classe %s: %s", class, result.Errors[0].Message)
+	}
+	aggregate, _ := result.Data["Aggregate"].(map[string]interface{})
+	items, _ := aggregate[class].([]interface{})
+	if len(items) == 0 {
+		return 0, nil
+	}
+	item, _ := items[0].(map[string]interface{})
+	meta, _ := item["meta"].(map[string]interface{})
+	count, _ := meta["count"].(float64)
+	return int(count), nil
+}
+
+// copyObjects percorre a classe de origem e grava na de destino, com os vetores recalculados,
+// os objetos ainda não copiados, preservando os IDs
+func (m *SemanticMemoryManager) copyObjects(ctx context.Context, embedder Embedder, source, target string, batchSize int, copied map[string]bool, counter *progress.Counter) error {
+	after := ""
+	for {
+		getter := m.client.Data().ObjectsGetter().
+			WithClassName(source).
+			WithLimit(batchSize)
+		if after != "" {
+			getter = getter.WithAfter(after)
+		}
+		objects, err := getter.Do(ctx)
+		if err != nil {
+			return fmt.Errorf("erro ao listar objetos da classe %s: %w", source, err)
+		}
+		if len(objects) == 0 {
+			return nil
+		}
+		after = objects[len(objects)-1].ID.String()
+
+		var pending []*models.Object
+		var contents []string
+		for _, obj := range objects {
+			if copied[obj.ID.String()] {
+				continue
+			}
+			properties, _ := obj.Properties.(map[string]interface{})
+			content, _ := properties["content"].(string)
+			pending = append(pending, &models.Object{Class: target, ID: obj.ID, Properties: properties})
+			contents = append(contents, content)
+		}
+		if len(pending) == 0 {
+			continue
+		}
+
+		vectors, err := embedder.Embed(ctx, contents)
+		if err != nil {
+			return fmt.Errorf("erro ao calcular embeddings: %w", err)
+		}
+		if len(vectors) != len(pending) {
+			return fmt.Errorf("o modelo de embeddings retornou %d vetores para %d textos", len(vectors), len(pending))
+		}
+		for i, obj := range pending {
+			obj.Vector = vectors[i]
+		}
+		responses, err := m.client.Batch().ObjectsBatcher().WithObjects(pending...).Do(ctx)
+		if err != nil {
+			return fmt.Errorf("erro ao gravar objetos na classe %s: %w", target, err)
+		}
+		for _, resp := range responses {
+			if resp.Result != nil && resp.Result.Errors != nil && len(resp.Result.Errors.Error) > 0 {
+				return fmt.Errorf("erro ao gravar objeto %s na classe %s: %s", resp.ID, target, resp.Result.Errors.Error[0].Message)
+			}
+		}
+		for _, obj := range pending {
+			copied[obj.ID.String()] = true
+		}
+		counter.Add(len(pending), fmt.Sprintf("%d memórias reindexadas", len(copied)))
+	}
+}
+
+// Clear descarta as entradas do cache semântico do tenant do contexto, como depois de uma
+// troca do modelo de embeddings, e retorna quantas foram removidas
+func (c *SemanticCache) Clear(ctx context.Context) (int, error) {
+	class, err := c.className(ctx)
+	if err != nil {
+		return 0, err
+	}
+	where := filters.Where().
+		WithPath([]string{"namespace"}).
+		WithOperator(filters.Like).
+		WithValueText("*")
+
+	cleared := 0
+	for {
+		resp, err := c.semantic.client.Batch().ObjectsBatchDeleter().
+			WithClassName(class).
+			WithWhere(where).
+			Do(ctx)
+		if err != nil {
+			return cleared, fmt.Errorf("erro ao limpar o cache semântico: %w", err)
+		}
+		if resp.Results == nil || resp.Results.Successful == 0 {
+			return cleared, nil
+		}
+		cleared += int(resp.Results.Successful)
+	}
+}
+
+// Reindex recalcula os vetores da memória semântica do tenant do contexto com o embedder do
+// modelo informado, troca o alias para a nova classe e passa a usar o embedder (com o cache
+// de embeddings) nas próximas operações. O modelo fica registrado no alias: as demais
+// instâncias precisam ter o embedder dele (AddEmbedder ou SetEmbedder) e, sem ele, recusam
+// as operações da classe em vez de gravar vetores do modelo anterior. O cache semântico do
+// tenant é descartado, já que os vetores das suas entradas são do modelo anterior.
+func (m *HybridMemoryManager) Reindex(ctx context.Context, embedder Embedder, model string, options ReindexOptions) (*ReindexReport, error) {
+	ctx = m.scope(ctx)
+	cache := NewEmbeddingCache(m.shortTerm.client, embedder, model, m.config.EmbeddingCacheTTL)
+	options.Model = model
+	report, err := m.semantic.Reindex(ctx, cache, options)
+	if err != nil {
+		return report, err
+	}
+	m.semantic.SetEmbedder(cache, model)
+
+	cleared, err := m.SemanticCache().Clear(ctx)
+	report.CacheCleared = cleared
+	if err != nil {
+		return report, err
+	}
+	return report, nil
+}
@@ -0,0 +1,305 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/weaviate/weaviate-go-client/v4/weaviate"
+)
+
+// fakeAliases guarda os aliases e as remoções da reindexação em memória
+type fakeAliases struct {
+	mu         sync.Mutex
+	classes    map[string]string
+	models     map[string]string
+	reindexing map[string]bool
+	deleted    map[string][]string
+}
+
+func newFakeAliases() *fakeAliases {
+	return &fakeAliases{classes: map[string]string{}, models: map[string]string{}, reindexing: map[string]bool{}, deleted: map[string][]string{}}
+}
+
+func (a *fakeAliases) Resolve(_ context.Context, alias string) (string, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.classes[alias], nil
+}
+
+func (a *fakeAliases) Model(_ context.Context, alias string) (string, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.models[alias], nil
+}
+
+func (a *fakeAliases) Switch(_ context.Context, alias, class, model string) (string, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	previous := a.classes[alias]
+	a.classes[alias] = class
+	a.models[alias] = model
+	return previous, nil
+}
+
+func (a *fakeAliases) BeginReindex(_ context.Context, alias string) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.reindexing[alias] = true
+	a.deleted[alias] = nil
+	return nil
+}
+
+func (a *fakeAliases) RecordDeletion(_ context.Context, alias, objectID string) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.reindexing[alias] {
+		a.deleted[alias] = append(a.deleted[alias], objectID)
+	}
+	return nil
+}
+
+func (a *fakeAliases) Deletions(_ context.Context, alias string) ([]string, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return append([]string(nil), a.deleted[alias]...), nil
+}
+
+func (a *fakeAliases) EndReindex(_ context.Context, alias string) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	delete(a.reindexing, alias)
+	delete(a.deleted, alias)
+	return nil
+}
+
+// fakeWeaviate registra as remoções de objetos; os objetos em missing respondem 404. A busca
+// pelo memoryId encontra em objects o UUID do objeto de cada memória.
+type fakeWeaviate struct {
+	mu      sync.Mutex
+	deletes []string
+	missing map[string]bool
+	objects map[string]string // memoryId -> ID do objeto
+}
+
+// graphQLLookup extrai a classe e o memoryId da busca do objeto de uma memória
+var graphQLLookup = regexp.MustCompile(`Get\s*\{\s*(\w+)\s*\(.*valueString:\s*\\?"([^"\\]+)`)
+
+func (f *fakeWeaviate) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodGet && r.URL.Path == "/v1/meta" {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"version":"1.27.0"}`))
+		return
+	}
+	if r.Method == http.MethodPost && r.URL.Path == "/v1/graphql" {
+		var body struct {
+			Query string `json:"query"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		match := graphQLLookup.FindStringSubmatch(body.Query)
+		if match == nil {
+			http.Error(w, "consulta inesperada: "+body.Query, http.StatusBadRequest)
+			return
+		}
+		var objects []interface{}
+		if id, ok := f.objects[match[2]]; ok {
+			objects = append(objects, map[string]interface{}{"_additional": map[string]interface{}{"id": id}})
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"data": map[string]interface{}{"Get": map[string]interface{}{match[1]: objects}},
+		})
+		return
+	}
+	if r.Method != http.MethodDelete {
+		http.NotFound(w, r)
+		return
+	}
+	path := strings.TrimPrefix(r.URL.Path, "/v1/objects/")
+	f.mu.Lock()
+	f.deletes = append(f.deletes, path)
+	f.mu.Unlock()
+	if f.missing[path] {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (f *fakeWeaviate) take() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	deletes := f.deletes
+	f.deletes = nil
+	return deletes
+}
+
+func newReindexManager(t *testing.T, aliases ClassAliases, server *fakeWeaviate) *SemanticMemoryManager {
+	httpServer := httptest.NewServer(server)
+	t.Cleanup(httpServer.Close)
+	client, err := weaviate.NewClient(weaviate.Config{Host: strings.TrimPrefix(httpServer.URL, "http://"), Scheme: "http"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &SemanticMemoryManager{
+		client:  client,
+		config:  &SemanticMemoryConfig{Class: "Memory", Aliases: aliases},
+		classes: map[string]bool{"Memory_V1": true, "Memory_V2": true},
+	}
+}
+
+func TestDeletionsDuringReindexAreReplayed(t *testing.T) {
+	ctx := context.Background()
+	aliases := newFakeAliases()
+	aliases.classes["Memory"] = "Memory_V1"
+
+	// Os IDs das memórias não são IDs de objeto: cada memória é encontrada pelo memoryId. O
+	// objeto de memory_writer_1 é anterior aos UUIDs derivados e tem um UUID aleatório.
+	legacy := "0b5e3f5a-8d0c-4a8e-9a62-3f1c2b7d9e41"
+	server := &fakeWeaviate{objects: map[string]string{
+		"memory_writer_0": objectID("memory_writer_0"),
+		"memory_writer_1": legacy,
+		"memory_writer_2": objectID("memory_writer_2"),
+		"memory_writer_3": objectID("memory_writer_3"),
+	}}
+	server.missing = map[string]bool{"Memory_V2/" + objectID("memory_writer_2"): true}
+	m := newReindexManager(t, aliases, server)
+	object := func(class, memoryID string) string { return class + "/" + server.objects[memoryID] }
+
+	// Fora de uma reindexação, as remoções não são registradas
+	if err := m.DeleteMemory(ctx, "memory_writer_0"); err != nil {
+		t.Fatal(err)
+	}
+	if err := aliases.BeginReindex(ctx, "Memory"); err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range []string{"memory_writer_1", "memory_writer_2"} {
+		if err := m.DeleteMemory(ctx, id); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want := []string{object("Memory_V1", "memory_writer_0"), object("Memory_V1", "memory_writer_1"), object("Memory_V1", "memory_writer_2")}
+	if got := server.take(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("remoções inesperadas na classe atual: %v, esperava %v", got, want)
+	}
+	if deleted, _ := aliases.Deletions(ctx, "Memory"); !reflect.DeepEqual(deleted, []string{legacy, objectID("memory_writer_2")}) {
+		t.Fatalf("deveriam ser registrados os IDs dos objetos: %v", deleted)
+	}
+
+	// A repetição remove da nova classe inclusive o que ainda não foi copiado (404)
+	replayed := make(map[string]bool)
+	if err := m.replayDeletions(ctx, "Memory", "Memory_V2", replayed); err != nil {
+		t.Fatal(err)
+	}
+	want = []string{object("Memory_V2", "memory_writer_1"), object("Memory_V2", "memory_writer_2")}
+	if got := server.take(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("remoções inesperadas na nova classe: %v, esperava %v", got, want)
+	}
+
+	// Depois da troca, as remoções vão para a nova classe e só as novas são repetidas
+	if _, err := aliases.Switch(ctx, "Memory", "Memory_V2", ""); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.DeleteMemory(ctx, "memory_writer_3"); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.replayDeletions(ctx, "Memory", "Memory_V2", replayed); err != nil {
+		t.Fatal(err)
+	}
+	want = []string{object("Memory_V2", "memory_writer_3"), object("Memory_V2", "memory_writer_3")}
+	if got := server.take(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("remoções inesperadas após a troca: %v, esperava %v", got, want)
+	}
+
+	// Uma memória sem objeto na classe não gera remoções
+	if err := m.DeleteMemory(ctx, "memory_writer_9"); err != nil {
+		t.Fatal(err)
+	}
+	if got := server.take(); len(got) != 0 {
+		t.Fatalf("remoções inesperadas de uma memória ausente: %v", got)
+	}
+
+	if err := aliases.EndReindex(ctx, "Memory"); err != nil {
+		t.Fatal(err)
+	}
+	if deleted, _ := aliases.Deletions(ctx, "Memory"); len(deleted) != 0 {
+		t.Fatalf("remoções deveriam ser descartadas ao final: %v", deleted)
+	}
+}
+
+func TestReplayDeletionsReportsFailures(t *testing.T) {
+	ctx := context.Background()
+	aliases := newFakeAliases()
+	aliases.BeginReindex(ctx, "Memory")
+	aliases.RecordDeletion(ctx, "Memory", objectID("memory_writer_1"))
+	m := newReindexManager(t, aliases, &fakeWeaviate{})
+	m.client, _ = weaviate.NewClient(weaviate.Config{Host: "127.0.0.1:1", Scheme: "http"})
+
+	if err := m.replayDeletions(ctx, "Memory", "Memory_V2", map[string]bool{}); err == nil {
+		t.Fatal("esperava o erro da remoção")
+	}
+}
+
+func TestObjectID(t *testing.T) {
+	id := objectID("memory_writer_1")
+	if _, err := uuid.Parse(id); err != nil {
+		t.Fatalf("esperava um UUID válido, obtido %q: %v", id, err)
+	}
+	if objectID("memory_writer_1") != id {
+		t.Fatal("o ID do objeto deveria ser determinístico")
+	}
+	if objectID("memory_writer_2") == id {
+		t.Fatal("memórias diferentes deveriam ter IDs de objeto diferentes")
+	}
+}
+
+func TestEmbedderFollowsAliasModel(t *testing.T) {
+	embedderOf := func(value float32) Embedder {
+		return EmbedderFunc(func(ctx context.Context, texts []string) ([][]float32, error) {
+			return [][]float32{{value}}, nil
+		})
+	}
+
+	cases := []struct {
+		name    string
+		model   string // Modelo registrado no alias
+		want    float32
+		wantErr bool
+	}{
+		{"sem modelo registrado", "", 1, false},
+		{"modelo padrão", "nomic-embed-text", 1, false},
+		{"modelo adicionado", "mxbai-embed-large", 2, false},
+		{"modelo sem embedder", "bge-m3", 0, true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			ctx := context.Background()
+			aliases := newFakeAliases()
+			aliases.Switch(ctx, "Memory", "Memory_V2", c.model)
+			m := &SemanticMemoryManager{config: &SemanticMemoryConfig{Class: "Memory", Aliases: aliases}}
+			m.SetEmbedder(embedderOf(1), "nomic-embed-text")
+			m.AddEmbedder(embedderOf(2), "mxbai-embed-large")
+
+			vector, err := m.vector(ctx, "vídeos curtos")
+			if c.wantErr {
+				if err == nil {
+					t.Fatalf("esperava erro sem o embedder do modelo %s", c.model)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if vector[0] != c.want {
+				t.Errorf("vetor do embedder %v, esperado %v", vector[0], c.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,159 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/go-redis/redis/v8"
+
+	"github.com/suissa/HiveMind/agents/tenant"
+)
+
+// IndexRepairReport registra a limpeza dos índices de agente e de tags do Redis
+type IndexRepairReport struct {
+	Namespace   string `json:"namespace"`
+	IndexesSeen int    `json:"indexes_seen"` // Conjuntos agent:* e tag:* verificados
+	Removed     int    `json:"removed"`      // IDs de memórias expiradas ou removidas retirados dos índices
+}
+
+// indexAddScript adiciona o ID ao índice e estende o TTL do índice até o da memória: o
+// conjunto vive enquanto viver a memória mais duradoura e expira junto com ela. Índices sem
+// TTL (anteriores a esta manutenção) são mantidos e limpos pela varredura.
+var indexAddScript = redis.NewScript(`
+local ttl = redis.call('PTTL', KEYS[1])
+redis.call('SADD', KEYS[1], ARGV[1])
+if ttl == -2 or (ttl >= 0 and ttl < tonumber(ARGV[2])) then
+	redis.call('PEXPIRE', KEYS[1], ARGV[2])
+end
+return 1
+`)
+
+// pruneBatch é a quantidade de IDs verificados por ida ao Redis
+const pruneBatch = 100
+
+// unindex remove a memória do índice do agente e dos índices das tags informadas
+func (m *RedisMemoryManager) unindex(ctx context.Context, agentID, memoryID string, tags []string) error {
+	keys := []string{m.key(ctx, "agent:%s:memories", agentID)}
+	for _, tag := range tags {
+		keys = append(keys, m.key(ctx, "tag:%s:%s", agentID, tag))
+	}
+	for _, key := range keys {
+		if err := m.client.SRem(ctx, key, memoryID).Err(); err != nil {
+			return fmt.Errorf("erro ao remover do índice %s: %w", key, err)
+		}
+	}
+	return nil
+}
+
+// pruneIndexes varre com SCAN os índices que casam com o padrão e remove os IDs cujas
+// memórias já expiraram. Conjuntos que ficam vazios são removidos pelo próprio Redis.
+func (m *RedisMemoryManager) pruneIndexes(ctx context.Context, pattern string) (seen, removed int, err error) {
+	iter := m.client.Scan(ctx, 0, m.key(ctx, "%s", pattern), 100).Iterator()
+	for iter.Next(ctx) {
+		n, err := m.pruneIndex(ctx, iter.Val())
+		removed += n
+		if err != nil {
+			return seen, removed, err
+		}
+		seen++
+	}
+	if err := iter.Err(); err != nil {
+		return seen, removed, fmt.Errorf("erro ao percorrer índices: %w", err)
+	}
+	return seen, removed, nil
+}
+
+// indexAgents retorna os agentes a que pode pertencer uma chave de índice, já sem o prefixo
+// do tenant. Em agent:<agente>:memories o agente é o que fica entre o prefixo e o sufixo; em
+// tag:<agente>:<tag> tanto o agente quanto a tag podem conter ':', e cada divisão é candidata.
+func indexAgents(key string) []string {
+	if rest, ok := strings.CutPrefix(key, "agent:"); ok {
+		if agentID, ok := strings.CutSuffix(rest, ":memories"); ok && agentID != "" {
+			return []string{agentID}
+		}
+		return nil
+	}
+	rest, ok := strings.CutPrefix(key, "tag:")
+	if !ok {
+		return nil
+	}
+	var agents []string
+	for i := 1; i < len(rest)-1; i++ {
+		if rest[i] == ':' {
+			agents = append(agents, rest[:i])
+		}
+	}
+	return agents
+}
+
+// pruneIndex remove de um índice os IDs sem memória correspondente. Quando a chave admite
+// mais de um agente, o ID só é removido se não houver memória em nenhum deles.
+func (m *RedisMemoryManager) pruneIndex(ctx context.Context, key string) (int, error) {
+	agents := indexAgents(strings.TrimPrefix(key, m.key(ctx, "")))
+	if len(agents) == 0 {
+		return 0, nil
+	}
+
+	var ids []string
+	iter := m.client.SScan(ctx, key, 0, "", pruneBatch).Iterator()
+	for iter.Next(ctx) {
+		ids = append(ids, iter.Val())
+	}
+	if err := iter.Err(); err != nil {
+		return 0, fmt.Errorf("erro ao percorrer índice %s: %w", key, err)
+	}
+
+	removed := 0
+	for start := 0; start < len(ids); start += pruneBatch {
+		batch := ids[start:min(start+pruneBatch, len(ids))]
+		pipe := m.client.Pipeline()
+		exists := make([]*redis.IntCmd, len(batch))
+		for i, id := range batch {
+			keys := make([]string, len(agents))
+			for j, agentID := range agents {
+				keys[j] = m.key(ctx, "memory:%s:%s", agentID, id)
+			}
+			exists[i] = pipe.Exists(ctx, keys...)
+		}
+		if _, err := pipe.Exec(ctx); err != nil {
+			return removed, fmt.Errorf("erro ao verificar memórias do índice %s: %w", key, err)
+		}
+
+		var dangling []interface{}
+		for i, cmd := range exists {
+			if cmd.Val() == 0 {
+				dangling = append(dangling, batch[i])
+			}
+		}
+		if len(dangling) == 0 {
+			continue
+		}
+		n, err := m.client.SRem(ctx, key, dangling...).Result()
+		removed += int(n)
+		if err != nil {
+			return removed, fmt.Errorf("erro ao limpar índice %s: %w", key, err)
+		}
+	}
+	return removed, nil
+}
+
+// RepairIndexes remove dos índices de agente e de tags do tenant do contexto todos os IDs
+// de memórias que já expiraram ou foram removidas
+func (m *RedisMemoryManager) RepairIndexes(ctx context.Context) (*IndexRepairReport, error) {
+	report := &IndexRepairReport{Namespace: tenant.FromContext(ctx)}
+	for _, pattern := range []string{"agent:*", "tag:*"} {
+		seen, removed, err := m.pruneIndexes(ctx, pattern)
+		report.IndexesSeen += seen
+		report.Removed += removed
+		if err != nil {
+			return report, err
+		}
+	}
+	return report, nil
+}
+
+// RepairIndexes remove IDs pendentes dos índices da memória de curto prazo do tenant do contexto
+func (m *HybridMemoryManager) RepairIndexes(ctx context.Context) (*IndexRepairReport, error) {
+	return m.shortTerm.RepairIndexes(m.scope(ctx))
+}
@@ -0,0 +1,32 @@
+package memory
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestIndexAgents(t *testing.T) {
+	cases := []struct {
+		name string
+		key  string
+		want []string
+	}{
+		{"índice do agente", "agent:writer-1:memories", []string{"writer-1"}},
+		{"agente com ':'", "agent:crew:writer:1:memories", []string{"crew:writer:1"}},
+		{"agente vazio", "agent::memories", nil},
+		{"índice do agente sem sufixo", "agent:writer-1:outros", nil},
+		{"índice de tag", "tag:writer-1:copy", []string{"writer-1"}},
+		{"tag com ':'", "tag:writer-1:schema:marketing.copy/v1", []string{"writer-1", "writer-1:schema"}},
+		{"agente com ':' na tag", "tag:crew:writer:copy", []string{"crew", "crew:writer"}},
+		{"tag vazia", "tag:writer-1:", nil},
+		{"tag sem agente", "tag::copy", nil},
+		{"outra chave", "memory:writer-1:m-1", nil},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := indexAgents(c.key); !reflect.DeepEqual(got, c.want) {
+				t.Fatalf("indexAgents(%q) = %q, esperava %q", c.key, got, c.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,175 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+
+	"github.com/suissa/HiveMind/agents/errs"
+	"github.com/suissa/HiveMind/agents/tenant"
+)
+
+// DefaultScratchpadTTL limita a vida das anotações de um workflow interrompido antes da purga
+const DefaultScratchpadTTL = 6 * time.Hour
+
+// ScratchpadProvider é implementado pelos gerenciadores com memória efêmera por workflow
+type ScratchpadProvider interface {
+	Scratchpad(workflowID string) *Scratchpad
+}
+
+// Scratchpad guarda as anotações intermediárias de um workflow fora da memória do agente.
+// As anotações ficam em um hash do Redis por workflow, são removidas por Purge ao final
+// do workflow e podem ser promovidas à memória do agente com Promote.
+type Scratchpad struct {
+	m          *HybridMemoryManager
+	workflowID string
+	TTL        time.Duration // Expiração de segurança do hash (padrão DefaultScratchpadTTL)
+}
+
+// Scratchpad retorna o scratchpad do workflow informado
+func (m *HybridMemoryManager) Scratchpad(workflowID string) *Scratchpad {
+	return &Scratchpad{m: m, workflowID: workflowID, TTL: DefaultScratchpadTTL}
+}
+
+// WorkflowID retorna o workflow do scratchpad
+func (s *Scratchpad) WorkflowID() string {
+	return s.workflowID
+}
+
+// key retorna a chave do hash do workflow no tenant do contexto
+func (s *Scratchpad) key(ctx context.Context) string {
+	return s.m.shortTerm.key(ctx, "scratch:%s", s.workflowID)
+}
+
+// Write grava uma anotação. Os dados pessoais e segredos são tratados como nas demais memórias.
+func (s *Scratchpad) Write(ctx context.Context, note *Memory) error {
+	if err := note.Validate(); err != nil {
+		return err
+	}
+	ctx = s.m.scope(ctx)
+	note.Type = Scratch
+	if note.TenantID == "" {
+		note.TenantID = tenant.FromContext(ctx)
+	}
+	if note.Timestamp.IsZero() {
+		note.Timestamp = time.Now()
+	}
+	if p := ProvenanceFromContext(ctx); note.Provenance == nil && !p.IsZero() {
+		note.Provenance = &p
+	}
+	if err := s.m.sanitize(ctx, note); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(note)
+	if err != nil {
+		return fmt.Errorf("erro ao serializar anotação: %w", err)
+	}
+	ttl := s.TTL
+	if ttl <= 0 {
+		ttl = DefaultScratchpadTTL
+	}
+	key := s.key(ctx)
+	client := s.m.shortTerm.client
+	if err := client.HSet(ctx, key, note.ID, data).Err(); err != nil {
+		return fmt.Errorf("erro ao gravar anotação: %w", err)
+	}
+	if err := client.Expire(ctx, key, ttl).Err(); err != nil {
+		return fmt.Errorf("erro ao definir expiração do scratchpad: %w", err)
+	}
+	return nil
+}
+
+// Get retorna uma anotação
+func (s *Scratchpad) Get(ctx context.Context, noteID string) (*Memory, error) {
+	ctx = s.m.scope(ctx)
+	data, err := s.m.shortTerm.client.HGet(ctx, s.key(ctx), noteID).Bytes()
+	if err == redis.Nil {
+		return nil, errs.New(errs.ErrNotFound, "memory.Scratchpad", "anotação %s não encontrada no workflow %s", noteID, s.workflowID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("erro ao recuperar anotação: %w", err)
+	}
+	var note Memory
+	if err := json.Unmarshal(data, &note); err != nil {
+		return nil, fmt.Errorf("erro ao deserializar anotação: %w", err)
+	}
+	return &note, nil
+}
+
+// List retorna as anotações do workflow em ordem cronológica
+func (s *Scratchpad) List(ctx context.Context) ([]*Memory, error) {
+	ctx = s.m.scope(ctx)
+	values, err := s.m.shortTerm.client.HGetAll(ctx, s.key(ctx)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar anotações: %w", err)
+	}
+	notes := make([]*Memory, 0, len(values))
+	for _, data := range values {
+		var note Memory
+		if err := json.Unmarshal([]byte(data), &note); err != nil {
+			return nil, fmt.Errorf("erro ao deserializar anotação: %w", err)
+		}
+		notes = append(notes, &note)
+	}
+	sort.SliceStable(notes, func(i, j int) bool {
+		return notes[i].Timestamp.Before(notes[j].Timestamp)
+	})
+	return notes, nil
+}
+
+// Promote move a anotação para a memória do agente com a importância informada, que decide
+// entre curto e longo prazo como em StoreMemory, e a remove do scratchpad
+func (s *Scratchpad) Promote(ctx context.Context, noteID string, importance float64) (*Memory, error) {
+	note, err := s.Get(ctx, noteID)
+	if err != nil {
+		return nil, err
+	}
+	note.Importance = importance
+	note.Type = ShortTerm
+	if importance >= s.m.config.ImportanceThreshold {
+		note.Type = LongTerm
+	}
+	note.TTL = 0
+	if err := s.m.StoreMemory(ctx, note); err != nil {
+		return nil, fmt.Errorf("erro ao promover anotação %s: %w", noteID, err)
+	}
+
+	ctx = s.m.scope(ctx)
+	if err := s.m.shortTerm.client.HDel(ctx, s.key(ctx), noteID).Err(); err != nil {
+		return note, fmt.Errorf("erro ao remover anotação promovida: %w", err)
+	}
+	return note, nil
+}
+
+// Purge descarta todas as anotações do workflow e retorna quantas foram removidas
+func (s *Scratchpad) Purge(ctx context.Context) (int, error) {
+	ctx = s.m.scope(ctx)
+	client := s.m.shortTerm.client
+	count, err := client.HLen(ctx, s.key(ctx)).Result()
+	if err != nil {
+		return 0, fmt.Errorf("erro ao contar anotações: %w", err)
+	}
+	if err := client.Del(ctx, s.key(ctx)).Err(); err != nil {
+		return 0, fmt.Errorf("erro ao descartar scratchpad do workflow %s: %w", s.workflowID, err)
+	}
+	return int(count), nil
+}
+
+// scratchpadKey é a chave do scratchpad no contexto
+type scratchpadKey struct{}
+
+// WithScratchpad associa o scratchpad do workflow ao contexto
+func WithScratchpad(ctx context.Context, s *Scratchpad) context.Context {
+	return context.WithValue(ctx, scratchpadKey{}, s)
+}
+
+// ScratchpadFromContext retorna o scratchpad do workflow em andamento, se houver
+func ScratchpadFromContext(ctx context.Context) (*Scratchpad, bool) {
+	s, ok := ctx.Value(scratchpadKey{}).(*Scratchpad)
+	return s, ok && s != nil
+}
@@ -0,0 +1,50 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/suissa/HiveMind/agents/errs"
+)
+
+func TestScratchpadFromContext(t *testing.T) {
+	pad := (&HybridMemoryManager{}).Scratchpad("wf-1")
+	if pad.WorkflowID() != "wf-1" || pad.TTL != DefaultScratchpadTTL {
+		t.Fatalf("scratchpad inesperado: %+v", pad)
+	}
+
+	cases := []struct {
+		name string
+		ctx  context.Context
+		want bool
+	}{
+		{"sem scratchpad", context.Background(), false},
+		{"scratchpad nulo", WithScratchpad(context.Background(), nil), false},
+		{"com scratchpad", WithScratchpad(context.Background(), pad), true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got, ok := ScratchpadFromContext(c.ctx)
+			if ok != c.want || (ok && got != pad) {
+				t.Fatalf("ScratchpadFromContext = %v, %v; esperava %v", got, ok, c.want)
+			}
+		})
+	}
+}
+
+func TestScratchpadWriteValidation(t *testing.T) {
+	// A validação acontece antes de qualquer acesso ao Redis
+	pad := (&HybridMemoryManager{}).Scratchpad("wf-1")
+	notes := []*Memory{
+		nil,
+		{AgentID: "writer-1", Content: "sem ID"},
+		{ID: "n-1", Content: "sem agente"},
+		{ID: "n-1", AgentID: "writer-1", Importance: 2},
+	}
+	for _, note := range notes {
+		if err := pad.Write(context.Background(), note); !errors.Is(err, errs.ErrValidation) {
+			t.Errorf("Write(%+v): esperava ErrValidation, obtido %v", note, err)
+		}
+	}
+}
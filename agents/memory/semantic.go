@@ -3,11 +3,18 @@ package memory
 import (
 	"context"
 	"fmt"
+	"math"
+	"sync"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/weaviate/weaviate-go-client/v4/weaviate"
+	"github.com/weaviate/weaviate-go-client/v4/weaviate/filters"
 	"github.com/weaviate/weaviate-go-client/v4/weaviate/graphql"
 	"github.com/weaviate/weaviate/entities/models"
+
+	"github.com/suissa/HiveMind/agents/errs"
+	"github.com/suissa/HiveMind/agents/tenant"
 )
 
 // SemanticMemoryConfig contém a configuração para o gerenciador de memória semântica
@@ -16,16 +23,23 @@ type SemanticMemoryConfig struct {
 	APIKey      string
 	Class       string
 	BatchSize   int
+	Aliases     ClassAliases // Opcional: classe física atrás de cada classe lógica (ver Reindex)
 }
 
 // SemanticMemoryManager gerencia memórias usando Weaviate para busca semântica
 type SemanticMemoryManager struct {
-	client *weaviate.Client
-	config *SemanticMemoryConfig
+	client    *weaviate.Client
+	config    *SemanticMemoryConfig
+	embedder  Embedder            // Nil deixa a vetorização para o Weaviate
+	model     string              // Modelo do embedder padrão
+	embedders map[string]Embedder // Embedders por modelo, para as classes reindexadas
+	embedMu   sync.RWMutex
+	classes   map[string]bool
+	mu        sync.Mutex
 }
 
 // NewSemanticMemoryManager cria um novo gerenciador de memória semântica
-func NewSemanticMemoryManager(config *SemanticMemoryConfig) (*SemanticMemoryManager, error) {
+func NewSemanticMemoryManager(ctx context.Context, config *SemanticMemoryConfig) (*SemanticMemoryManager, error) {
 	cfg := weaviate.Config{
 		Host:   config.WeaviateURL,
 		Scheme: "http",
@@ -36,32 +50,138 @@ func NewSemanticMemoryManager(config *SemanticMemoryConfig) (*SemanticMemoryMana
 
 	client, err := weaviate.NewClient(cfg)
 	if err != nil {
-		return nil, fmt.Errorf("erro ao criar cliente Weaviate: %v", err)
+		return nil, fmt.Errorf("erro ao criar cliente Weaviate: %w", err)
 	}
 
 	manager := &SemanticMemoryManager{
-		client: client,
-		config: config,
+		client:  client,
+		config:  config,
+		classes: make(map[string]bool),
 	}
 
-	// Garante que a classe existe
-	if err := manager.ensureClass(); err != nil {
-		return nil, fmt.Errorf("erro ao configurar classe: %v", err)
+	// Garante que a classe do tenant padrão existe
+	class, err := manager.resolve(ctx, config.Class)
+	if err != nil {
+		return nil, err
+	}
+	if err := manager.ensureClass(ctx, class); err != nil {
+		return nil, fmt.Errorf("erro ao configurar classe: %w", err)
 	}
 
 	return manager, nil
 }
 
+// SetEmbedder passa a calcular os vetores com o embedder do modelo (ex.: um EmbeddingCache),
+// enviando-os ao Weaviate na gravação e nas buscas por similaridade. É o embedder das classes
+// sem modelo registrado no alias; nas demais vale o embedder do modelo delas (ver AddEmbedder).
+func (m *SemanticMemoryManager) SetEmbedder(embedder Embedder, model string) {
+	m.embedMu.Lock()
+	defer m.embedMu.Unlock()
+	m.embedder, m.model = embedder, model
+}
+
+// AddEmbedder registra o embedder do modelo sem torná-lo o padrão: as classes que o alias
+// registra como reindexadas com esse modelo passam a ser vetorizadas com ele. Registre o novo
+// modelo antes de uma reindexação feita por outra instância para não interromper as operações.
+func (m *SemanticMemoryManager) AddEmbedder(embedder Embedder, model string) {
+	m.embedMu.Lock()
+	defer m.embedMu.Unlock()
+	if m.embedders == nil {
+		m.embedders = make(map[string]Embedder)
+	}
+	m.embedders[model] = embedder
+}
+
+// embedderFor retorna o embedder do modelo registrado no alias da classe do tenant do
+// contexto. Sem o embedder desse modelo, a operação falha: vetores de outro modelo não são
+// comparáveis com os da classe.
+func (m *SemanticMemoryManager) embedderFor(ctx context.Context) (Embedder, error) {
+	m.embedMu.RLock()
+	embedder, current := m.embedder, m.model
+	m.embedMu.RUnlock()
+	if m.config.Aliases == nil {
+		return embedder, nil
+	}
+	alias := tenant.ClassName(tenant.FromContext(ctx), m.config.Class)
+	model, err := m.config.Aliases.Model(ctx, alias)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao obter o modelo de embeddings do alias %s: %w", alias, err)
+	}
+	if model == "" || model == current {
+		return embedder, nil
+	}
+	m.embedMu.RLock()
+	embedder, ok := m.embedders[model]
+	m.embedMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("a classe do alias %s foi reindexada com o modelo %s, sem embedder nesta instância (AddEmbedder)", alias, model)
+	}
+	return embedder, nil
+}
+
+// vector calcula o vetor do texto com o embedder do modelo da classe; sem embedder retorna
+// nil e o Weaviate vetoriza o texto
+func (m *SemanticMemoryManager) vector(ctx context.Context, text string) ([]float32, error) {
+	embedder, err := m.embedderFor(ctx)
+	if err != nil || embedder == nil {
+		return nil, err
+	}
+	vectors, err := embedder.Embed(ctx, []string{text})
+	if err != nil {
+		return nil, fmt.Errorf("erro ao calcular embedding: %w", err)
+	}
+	return vectors[0], nil
+}
+
+// className retorna a classe do Weaviate do tenant do contexto, criando-a se necessário
+func (m *SemanticMemoryManager) className(ctx context.Context) (string, error) {
+	class, err := m.resolve(ctx, tenant.ClassName(tenant.FromContext(ctx), m.config.Class))
+	if err != nil {
+		return "", err
+	}
+	if err := m.ensureClass(ctx, class); err != nil {
+		return "", fmt.Errorf("erro ao configurar classe %s: %v", class, err)
+	}
+	return class, nil
+}
+
+// resolve retorna a classe física atrás da classe lógica; sem alias é a própria classe
+func (m *SemanticMemoryManager) resolve(ctx context.Context, alias string) (string, error) {
+	if m.config.Aliases == nil {
+		return alias, nil
+	}
+	class, err := m.config.Aliases.Resolve(ctx, alias)
+	if err != nil {
+		return "", fmt.Errorf("erro ao resolver alias %s: %w", alias, err)
+	}
+	if class == "" {
+		return alias, nil
+	}
+	return class, nil
+}
+
 // ensureClass garante que a classe necessária existe no Weaviate
-func (m *SemanticMemoryManager) ensureClass() error {
-	classExists, err := m.client.Schema().ClassExistenceChecker().WithClassName(m.config.Class).Do(context.Background())
+func (m *SemanticMemoryManager) ensureClass(ctx context.Context, className string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.classes[className] {
+		return nil
+	}
+
+	classExists, err := m.client.Schema().ClassExistenceChecker().WithClassName(className).Do(ctx)
 	if err != nil {
-		return fmt.Errorf("erro ao verificar existência da classe: %v", err)
+		return fmt.Errorf("erro ao verificar existência da classe: %w", err)
 	}
 
-	if !classExists {
+	if classExists {
+		// Classes criadas antes da exclusão por titular não têm a propriedade subjects
+		if err := m.ensureSubjectsProperty(ctx, className); err != nil {
+			return err
+		}
+	} else {
 		class := &models.Class{
-			Class: m.config.Class,
+			Class: className,
 			Properties: []*models.Property{
 				{
 					Name:     "content",
@@ -87,20 +207,62 @@ func (m *SemanticMemoryManager) ensureClass() error {
 					Name:     "tags",
 					DataType: []string{"string[]"},
 				},
+				subjectsProperty(),
 			},
 		}
 
-		err = m.client.Schema().ClassCreator().WithClass(class).Do(context.Background())
+		err = m.client.Schema().ClassCreator().WithClass(class).Do(ctx)
 		if err != nil {
-			return fmt.Errorf("erro ao criar classe: %v", err)
+			return fmt.Errorf("erro ao criar classe: %w", err)
 		}
 	}
 
+	m.classes[className] = true
+	return nil
+}
+
+// subjectsProperty descreve a propriedade com as pessoas referenciadas pela memória
+func subjectsProperty() *models.Property {
+	return &models.Property{
+		Name:     "subjects",
+		DataType: []string{"string[]"},
+	}
+}
+
+// ensureSubjectsProperty adiciona a propriedade subjects a uma classe existente
+func (m *SemanticMemoryManager) ensureSubjectsProperty(ctx context.Context, className string) error {
+	class, err := m.client.Schema().ClassGetter().WithClassName(className).Do(ctx)
+	if err != nil {
+		return fmt.Errorf("erro ao obter classe: %w", err)
+	}
+	for _, property := range class.Properties {
+		if property.Name == "subjects" {
+			return nil
+		}
+	}
+
+	err = m.client.Schema().PropertyCreator().
+		WithClassName(className).
+		WithProperty(subjectsProperty()).
+		Do(ctx)
+	if err != nil {
+		return fmt.Errorf("erro ao adicionar propriedade subjects: %w", err)
+	}
 	return nil
 }
 
 // StoreMemory armazena uma memória no Weaviate
 func (m *SemanticMemoryManager) StoreMemory(ctx context.Context, memory *Memory) error {
+	class, err := m.className(ctx)
+	if err != nil {
+		return err
+	}
+
+	vector, err := m.vector(ctx, memory.Content)
+	if err != nil {
+		return err
+	}
+
 	properties := map[string]interface{}{
 		"content":    memory.Content,
 		"agentId":    memory.AgentID,
@@ -108,15 +270,20 @@ func (m *SemanticMemoryManager) StoreMemory(ctx context.Context, memory *Memory)
 		"importance": memory.Importance,
 		"timestamp":  memory.Timestamp.Format(time.RFC3339),
 		"tags":       memory.Tags,
+		"subjects":   memory.Subjects,
 	}
 
-	_, err := m.client.Data().Creator().
-		WithClassName(m.config.Class).
-		WithProperties(properties).
-		Do(ctx)
+	creator := m.client.Data().Creator().
+		WithClassName(class).
+		WithID(objectID(memory.ID)).
+		WithProperties(properties)
+	if vector != nil {
+		creator = creator.WithVector(vector)
+	}
+	_, err = creator.Do(ctx)
 
 	if err != nil {
-		return fmt.Errorf("erro ao armazenar memória no Weaviate: %v", err)
+		return fmt.Errorf("erro ao armazenar memória no Weaviate: %w", err)
 	}
 
 	return nil
@@ -124,6 +291,20 @@ func (m *SemanticMemoryManager) StoreMemory(ctx context.Context, memory *Memory)
 
 // SearchSimilarMemories busca memórias semanticamente similares
 func (m *SemanticMemoryManager) SearchSimilarMemories(ctx context.Context, query string, limit int) ([]*Memory, error) {
+	return m.SearchSimilar(ctx, query, SimilarityOptions{Limit: limit})
+}
+
+// SearchSimilar busca as memórias mais similares à consulta dentro dos limites, da mais para
+// a menos similar, com a similaridade e a distância de cada uma
+func (m *SemanticMemoryManager) SearchSimilar(ctx context.Context, query string, options SimilarityOptions) ([]*Memory, error) {
+	if err := options.Validate(); err != nil {
+		return nil, err
+	}
+	class, err := m.className(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	fields := []graphql.Field{
 		{Name: "content"},
 		{Name: "agentId"},
@@ -131,45 +312,75 @@ func (m *SemanticMemoryManager) SearchSimilarMemories(ctx context.Context, query
 		{Name: "importance"},
 		{Name: "timestamp"},
 		{Name: "tags"},
+		{Name: "_additional", Fields: []graphql.Field{{Name: "distance"}}},
 	}
 
-	nearText := m.client.GraphQL().NearTextArgBuilder().
-		WithConcepts([]string{query})
-
-	result, err := m.client.GraphQL().Get().
-		WithClassName(m.config.Class).
-		WithFields(fields...).
-		WithNearText(nearText).
-		WithLimit(limit).
-		Do(ctx)
-
+	vector, err := m.vector(ctx, query)
 	if err != nil {
-		return nil, fmt.Errorf("erro ao buscar memórias similares: %v", err)
+		return nil, err
 	}
-
-	var memories []*Memory
-	for _, obj := range result.Data {
-		data := obj.(map[string]interface{})
-		memory := &Memory{
-			Content:    data["content"].(string),
-			AgentID:    data["agentId"].(string),
-			ID:         data["memoryId"].(string),
-			Importance: data["importance"].(float64),
-			Tags:       make([]string, 0),
+	search := m.client.GraphQL().Get().
+		WithClassName(class).
+		WithFields(fields...)
+	if options.Limit > 0 {
+		search = search.WithLimit(options.Limit)
+	}
+	// A similaridade mínima vira a distância cosseno equivalente: similaridade = 1 - distância/2
+	maxDistance, limited := options.distance()
+	if vector != nil {
+		nearVector := m.client.GraphQL().NearVectorArgBuilder().WithVector(vector)
+		if limited {
+			nearVector = nearVector.WithDistance(float32(maxDistance))
 		}
+		search = search.WithNearVector(nearVector)
+	} else {
+		nearText := m.client.GraphQL().NearTextArgBuilder().WithConcepts([]string{query})
+		if limited {
+			nearText = nearText.WithDistance(float32(maxDistance))
+		}
+		search = search.WithNearText(nearText)
+	}
+	result, err := search.Do(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar memórias similares: %w", err)
+	}
+	if len(result.Errors) > 0 {
+		return nil, fmt.Errorf("erro ao buscar memórias similares: %s", result.Errors[0].Message)
+	}
 
-		timestamp, err := time.Parse(time.RFC3339, data["timestamp"].(string))
-		if err != nil {
-			return nil, fmt.Errorf("erro ao converter timestamp: %v", err)
+	get, _ := result.Data["Get"].(map[string]interface{})
+	objects, _ := get[class].([]interface{})
+	memories := make([]*Memory, 0, len(objects))
+	for _, obj := range objects {
+		data, _ := obj.(map[string]interface{})
+		memory := &Memory{Tags: make([]string, 0)}
+		memory.Content, _ = data["content"].(string)
+		memory.AgentID, _ = data["agentId"].(string)
+		memory.ID, _ = data["memoryId"].(string)
+		memory.Importance, _ = data["importance"].(float64)
+
+		if value, ok := data["timestamp"].(string); ok {
+			timestamp, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				return nil, fmt.Errorf("erro ao converter timestamp: %w", err)
+			}
+			memory.Timestamp = timestamp
 		}
-		memory.Timestamp = timestamp
 
 		if tags, ok := data["tags"].([]interface{}); ok {
 			for _, tag := range tags {
-				memory.Tags = append(memory.Tags, tag.(string))
+				if value, ok := tag.(string); ok {
+					memory.Tags = append(memory.Tags, value)
+				}
 			}
 		}
 
+		additional, _ := data["_additional"].(map[string]interface{})
+		if distance, ok := additional["distance"].(float64); ok {
+			memory.Distance = distance
+			memory.Similarity = math.Max(0, math.Min(1, 1-distance/2))
+		}
+
 		memories = append(memories, memory)
 	}
 
@@ -178,21 +389,45 @@ func (m *SemanticMemoryManager) SearchSimilarMemories(ctx context.Context, query
 
 // UpdateMemory atualiza uma memória existente
 func (m *SemanticMemoryManager) UpdateMemory(ctx context.Context, memory *Memory) error {
+	class, err := m.className(ctx)
+	if err != nil {
+		return err
+	}
+	ids, err := m.objectIDs(ctx, class, memory.ID)
+	if err != nil {
+		return err
+	}
+	if len(ids) == 0 {
+		return errs.New(errs.ErrNotFound, "memory.SemanticMemoryManager", "memória %s não encontrada na classe %s", memory.ID, class)
+	}
+
+	vector, err := m.vector(ctx, memory.Content)
+	if err != nil {
+		return err
+	}
+
+	// A atualização substitui o objeto inteiro, então agentId e memoryId são regravados
 	properties := map[string]interface{}{
 		"content":    memory.Content,
+		"agentId":    memory.AgentID,
+		"memoryId":   memory.ID,
 		"importance": memory.Importance,
 		"timestamp":  memory.Timestamp.Format(time.RFC3339),
 		"tags":       memory.Tags,
+		"subjects":   memory.Subjects,
 	}
 
-	err := m.client.Data().Updater().
-		WithClassName(m.config.Class).
-		WithID(memory.ID).
-		WithProperties(properties).
-		Do(ctx)
-
-	if err != nil {
-		return fmt.Errorf("erro ao atualizar memória: %v", err)
+	for _, id := range ids {
+		updater := m.client.Data().Updater().
+			WithClassName(class).
+			WithID(id).
+			WithProperties(properties)
+		if vector != nil {
+			updater = updater.WithVector(vector)
+		}
+		if err := updater.Do(ctx); err != nil {
+			return fmt.Errorf("erro ao atualizar memória: %w", err)
+		}
 	}
 
 	return nil
@@ -200,18 +435,172 @@ func (m *SemanticMemoryManager) UpdateMemory(ctx context.Context, memory *Memory
 
 // DeleteMemory remove uma memória
 func (m *SemanticMemoryManager) DeleteMemory(ctx context.Context, memoryID string) error {
-	err := m.client.Data().Deleter().
-		WithClassName(m.config.Class).
-		WithID(memoryID).
-		Do(ctx)
+	class, err := m.className(ctx)
+	if err != nil {
+		return err
+	}
+	ids, err := m.objectIDs(ctx, class, memoryID)
+	if err != nil {
+		return err
+	}
+
+	for _, id := range ids {
+		err := m.client.Data().Deleter().
+			WithClassName(class).
+			WithID(id).
+			Do(ctx)
+		if err != nil {
+			return fmt.Errorf("erro ao deletar memória: %w", err)
+		}
+		if err := m.recordDeletion(ctx, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
 
+// objectNamespace é o namespace dos UUIDs derivados dos IDs das memórias
+var objectNamespace = uuid.MustParse("6f1d8c2e-5b7a-4e39-9c1f-2a4d8e6b0c57")
+
+// objectID retorna o UUID do objeto de uma memória no Weaviate, que não aceita os IDs das
+// memórias (memory_<agente>_<nanos>) como ID de objeto
+func objectID(memoryID string) string {
+	return uuid.NewSHA1(objectNamespace, []byte(memoryID)).String()
+}
+
+// objectIDs retorna os objetos da memória na classe pelo memoryId. Objetos gravados antes dos
+// UUIDs derivados de objectID têm IDs aleatórios, então o ID não é calculado.
+func (m *SemanticMemoryManager) objectIDs(ctx context.Context, class, memoryID string) ([]string, error) {
+	result, err := m.client.GraphQL().Get().
+		WithClassName(class).
+		WithFields(graphql.Field{Name: "_additional", Fields: []graphql.Field{{Name: "id"}}}).
+		WithWhere(filters.Where().WithPath([]string{"memoryId"}).WithOperator(filters.Equal).WithValueString(memoryID)).
+		WithLimit(100).
+		Do(ctx)
 	if err != nil {
-		return fmt.Errorf("erro ao deletar memória: %v", err)
+		return nil, fmt.Errorf("erro ao buscar o objeto da memória %s: %w", memoryID, err)
+	}
+	if len(result.Errors) > 0 {
+		return nil, fmt.Errorf("erro ao buscar o objeto da memória %s: %s", memoryID, result.Errors[0].Message)
 	}
 
+	get, _ := result.Data["Get"].(map[string]interface{})
+	objects, _ := get[class].([]interface{})
+	ids := make([]string, 0, len(objects))
+	for _, obj := range objects {
+		data, _ := obj.(map[string]interface{})
+		additional, _ := data["_additional"].(map[string]interface{})
+		if id, _ := additional["id"].(string); id != "" {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+// recordDeletion registra a remoção do objeto no alias, para que uma reindexação em andamento
+// a repita na nova classe
+func (m *SemanticMemoryManager) recordDeletion(ctx context.Context, objectID string) error {
+	if m.config.Aliases == nil {
+		return nil
+	}
+	alias := tenant.ClassName(tenant.FromContext(ctx), m.config.Class)
+	if err := m.config.Aliases.RecordDeletion(ctx, alias, objectID); err != nil {
+		return fmt.Errorf("erro ao registrar a remoção do objeto %s: %w", objectID, err)
+	}
 	return nil
 }
 
+// deleteBySubject remove da classe do tenant os objetos que referenciam o titular (pelos
+// termos no conteúdo ou em subjects) ou cujo memoryId está entre os informados. O filtro do
+// Weaviate compara tokens normalizados e só seleciona candidatos; a decisão usa references,
+// como no Redis e no MongoDB.
+func (m *SemanticMemoryManager) deleteBySubject(ctx context.Context, terms, memoryIDs []string) ([]string, error) {
+	class, err := m.className(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var operands []*filters.WhereBuilder
+	for _, term := range terms {
+		operands = append(operands,
+			filters.Where().WithPath([]string{"content"}).WithOperator(filters.Equal).WithValueText(term),
+			filters.Where().WithPath([]string{"subjects"}).WithOperator(filters.Equal).WithValueString(term),
+		)
+	}
+	known := make(map[string]bool, len(memoryIDs))
+	for _, id := range memoryIDs {
+		known[id] = true
+		operands = append(operands, filters.Where().WithPath([]string{"memoryId"}).WithOperator(filters.Equal).WithValueString(id))
+	}
+	where := filters.Where().WithOperator(filters.Or).WithOperands(operands)
+
+	fields := []graphql.Field{
+		{Name: "memoryId"},
+		{Name: "content"},
+		{Name: "subjects"},
+		{Name: "_additional", Fields: []graphql.Field{{Name: "id"}}},
+	}
+	const pageSize = 100
+
+	var deleted []string
+	// Os candidatos mantidos continuam no resultado; o offset pula os já examinados
+	skipped := 0
+	for {
+		result, err := m.client.GraphQL().Get().
+			WithClassName(class).
+			WithFields(fields...).
+			WithWhere(where).
+			WithLimit(pageSize).
+			WithOffset(skipped).
+			Do(ctx)
+		if err != nil {
+			return deleted, fmt.Errorf("erro ao buscar memórias do titular: %w", err)
+		}
+		if len(result.Errors) > 0 {
+			return deleted, fmt.Errorf("erro ao buscar memórias do titular: %s", result.Errors[0].Message)
+		}
+
+		get, _ := result.Data["Get"].(map[string]interface{})
+		objects, _ := get[class].([]interface{})
+		for _, obj := range objects {
+			data, _ := obj.(map[string]interface{})
+			additional, _ := data["_additional"].(map[string]interface{})
+			objectID, _ := additional["id"].(string)
+			memoryID, _ := data["memoryId"].(string)
+			if objectID == "" || !(known[memoryID] || references(semanticCandidate(data), terms)) {
+				skipped++
+				continue
+			}
+
+			err := m.client.Data().Deleter().WithClassName(class).WithID(objectID).Do(ctx)
+			if err != nil {
+				return deleted, fmt.Errorf("erro ao deletar memória: %w", err)
+			}
+			deleted = append(deleted, memoryID)
+			if err := m.recordDeletion(ctx, objectID); err != nil {
+				return deleted, err
+			}
+		}
+
+		if len(objects) < pageSize {
+			return deleted, nil
+		}
+	}
+}
+
+// semanticCandidate converte um objeto do Weaviate na memória comparada por references
+func semanticCandidate(data map[string]interface{}) *Memory {
+	memory := &Memory{}
+	memory.Content, _ = data["content"].(string)
+	subjects, _ := data["subjects"].([]interface{})
+	for _, subject := range subjects {
+		if s, ok := subject.(string); ok {
+			memory.Subjects = append(memory.Subjects, s)
+		}
+	}
+	return memory
+}
+
 // Close fecha a conexão com o Weaviate
 func (m *SemanticMemoryManager) Close(ctx context.Context) error {
 	// O cliente Weaviate não requer fechamento explícito
@@ -0,0 +1,178 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/weaviate/weaviate-go-client/v4/weaviate/filters"
+	"github.com/weaviate/weaviate-go-client/v4/weaviate/graphql"
+	"github.com/weaviate/weaviate/entities/models"
+
+	"github.com/suissa/HiveMind/agents/tenant"
+)
+
+// Valores padrão do cache semântico
+const (
+	DefaultSemanticCacheClass     = "LLMCache"
+	DefaultSemanticCacheThreshold = 0.92
+)
+
+// SemanticCache armazena respostas do LLM no Weaviate e as reutiliza quando uma nova
+// consulta é semanticamente similar (certeza acima do limiar) a uma já respondida.
+// As entradas são separadas por namespace (ex.: agente e modelo) e por tenant.
+type SemanticCache struct {
+	semantic  *SemanticMemoryManager
+	class     string
+	threshold float64
+	classes   map[string]bool
+	mu        sync.Mutex
+}
+
+// NewSemanticCache cria um cache semântico sobre o cliente Weaviate do gerenciador.
+// Classe vazia usa DefaultSemanticCacheClass e limiar zero usa DefaultSemanticCacheThreshold.
+func NewSemanticCache(semantic *SemanticMemoryManager, class string, threshold float64) *SemanticCache {
+	if class == "" {
+		class = DefaultSemanticCacheClass
+	}
+	if threshold <= 0 {
+		threshold = DefaultSemanticCacheThreshold
+	}
+	return &SemanticCache{
+		semantic:  semantic,
+		class:     class,
+		threshold: threshold,
+		classes:   make(map[string]bool),
+	}
+}
+
+// SemanticCache retorna o cache semântico sobre o Weaviate do gerenciador híbrido,
+// com a classe e o limiar da configuração
+func (m *HybridMemoryManager) SemanticCache() *SemanticCache {
+	return NewSemanticCache(m.semantic, m.config.SemanticCacheClass, m.config.SemanticCacheThreshold)
+}
+
+// className retorna a classe do tenant do contexto, criando-a se necessário
+func (c *SemanticCache) className(ctx context.Context) (string, error) {
+	class := tenant.ClassName(tenant.FromContext(ctx), c.class)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.classes[class] {
+		return class, nil
+	}
+
+	client := c.semantic.client
+	exists, err := client.Schema().ClassExistenceChecker().WithClassName(class).Do(ctx)
+	if err != nil {
+		return "", fmt.Errorf("erro ao verificar existência da classe %s: %w", class, err)
+	}
+	if !exists {
+		err = client.Schema().ClassCreator().WithClass(&models.Class{
+			Class: class,
+			Properties: []*models.Property{
+				{Name: "query", DataType: []string{"text"}},
+				{Name: "response", DataType: []string{"text"}},
+				{Name: "namespace", DataType: []string{"string"}},
+				{Name: "timestamp", DataType: []string{"date"}},
+			},
+		}).Do(ctx)
+		if err != nil {
+			return "", fmt.Errorf("erro ao criar classe %s: %w", class, err)
+		}
+	}
+
+	c.classes[class] = true
+	return class, nil
+}
+
+// Lookup busca a resposta de uma consulta similar do mesmo namespace. Retorna a resposta,
+// a similaridade (certeza do Weaviate) e se houve acerto.
+func (c *SemanticCache) Lookup(ctx context.Context, namespace, query string) (string, float64, bool, error) {
+	class, err := c.className(ctx)
+	if err != nil {
+		return "", 0, false, err
+	}
+
+	client := c.semantic.client
+	vector, err := c.semantic.vector(ctx, query)
+	if err != nil {
+		return "", 0, false, err
+	}
+	where := filters.Where().
+		WithPath([]string{"namespace"}).
+		WithOperator(filters.Equal).
+		WithValueString(namespace)
+
+	search := client.GraphQL().Get().
+		WithClassName(class).
+		WithFields(
+			graphql.Field{Name: "response"},
+			graphql.Field{Name: "_additional", Fields: []graphql.Field{{Name: "certainty"}}},
+		).
+		WithWhere(where).
+		WithLimit(1)
+	if vector != nil {
+		search = search.WithNearVector(client.GraphQL().NearVectorArgBuilder().
+			WithVector(vector).
+			WithCertainty(float32(c.threshold)))
+	} else {
+		search = search.WithNearText(client.GraphQL().NearTextArgBuilder().
+			WithConcepts([]string{query}).
+			WithCertainty(float32(c.threshold)))
+	}
+	result, err := search.Do(ctx)
+	if err != nil {
+		return "", 0, false, fmt.Errorf("erro ao consultar cache semântico: %w", err)
+	}
+	if len(result.Errors) > 0 {
+		return "", 0, false, fmt.Errorf("erro ao consultar cache semântico: %s", result.Errors[0].Message)
+	}
+
+	get, _ := result.Data["Get"].(map[string]interface{})
+	items, _ := get[class].([]interface{})
+	if len(items) == 0 {
+		return "", 0, false, nil
+	}
+
+	item, _ := items[0].(map[string]interface{})
+	response, _ := item["response"].(string)
+	var certainty float64
+	if additional, ok := item["_additional"].(map[string]interface{}); ok {
+		certainty, _ = additional["certainty"].(float64)
+	}
+	if certainty < c.threshold {
+		return "", certainty, false, nil
+	}
+	return response, certainty, true, nil
+}
+
+// Store grava a resposta da consulta no namespace informado
+func (c *SemanticCache) Store(ctx context.Context, namespace, query, response string) error {
+	class, err := c.className(ctx)
+	if err != nil {
+		return err
+	}
+
+	vector, err := c.semantic.vector(ctx, query)
+	if err != nil {
+		return err
+	}
+	creator := c.semantic.client.Data().Creator().
+		WithClassName(class).
+		WithProperties(map[string]interface{}{
+			"query":     query,
+			"response":  response,
+			"namespace": namespace,
+			"timestamp": time.Now().Format(time.RFC3339),
+		})
+	if vector != nil {
+		creator = creator.WithVector(vector)
+	}
+	_, err = creator.Do(ctx)
+	if err != nil {
+		return fmt.Errorf("erro ao gravar cache semântico: %w", err)
+	}
+	return nil
+}
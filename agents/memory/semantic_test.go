@@ -0,0 +1,174 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/weaviate/weaviate-go-client/v4/weaviate"
+
+	"github.com/suissa/HiveMind/agents/errs"
+)
+
+// fakeGraphQL registra as consultas GraphQL e responde com os objetos em objects
+type fakeGraphQL struct {
+	mu      sync.Mutex
+	queries []string
+	objects []map[string]interface{}
+}
+
+func (f *fakeGraphQL) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if r.Method == http.MethodGet && r.URL.Path == "/v1/meta" {
+		w.Write([]byte(`{"version":"1.27.0"}`))
+		return
+	}
+	if r.Method != http.MethodPost || r.URL.Path != "/v1/graphql" {
+		http.NotFound(w, r)
+		return
+	}
+	var body struct {
+		Query string `json:"query"`
+	}
+	json.NewDecoder(r.Body).Decode(&body)
+	f.mu.Lock()
+	f.queries = append(f.queries, body.Query)
+	f.mu.Unlock()
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"data": map[string]interface{}{"Get": map[string]interface{}{"Memory": f.objects}},
+	})
+}
+
+func (f *fakeGraphQL) take() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	queries := f.queries
+	f.queries = nil
+	return queries
+}
+
+// distanceArg extrai a distância máxima enviada na consulta, se houver
+var distanceArg = regexp.MustCompile(`distance:\s*([0-9.e+-]+)`)
+
+func newSimilarityManager(t *testing.T, server *fakeGraphQL, embedder Embedder) *SemanticMemoryManager {
+	httpServer := httptest.NewServer(server)
+	t.Cleanup(httpServer.Close)
+	client, err := weaviate.NewClient(weaviate.Config{Host: strings.TrimPrefix(httpServer.URL, "http://"), Scheme: "http"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &SemanticMemoryManager{
+		client:   client,
+		config:   &SemanticMemoryConfig{Class: "Memory"},
+		embedder: embedder,
+		classes:  map[string]bool{"Memory": true},
+	}
+}
+
+func TestSearchSimilarCutoffs(t *testing.T) {
+	embedder := EmbedderFunc(func(ctx context.Context, texts []string) ([][]float32, error) {
+		return [][]float32{{0.1, 0.2}}, nil
+	})
+
+	cases := []struct {
+		name     string
+		embedder Embedder
+		options  SimilarityOptions
+		operator string  // nearText ou nearVector
+		distance float64 // Negativa quando a consulta não deve limitar a distância
+		limit    bool
+	}{
+		{"sem limites", nil, SimilarityOptions{}, "nearText", -1, false},
+		{"limite de resultados", nil, SimilarityOptions{Limit: 5}, "nearText", -1, true},
+		{"similaridade mínima", nil, SimilarityOptions{MinSimilarity: 0.8}, "nearText", 0.4, false},
+		{"distância máxima", nil, SimilarityOptions{MaxDistance: 0.3}, "nearText", 0.3, false},
+		{"vale o mais restritivo", nil, SimilarityOptions{MinSimilarity: 0.9, MaxDistance: 0.5}, "nearText", 0.2, false},
+		{"com embedder", embedder, SimilarityOptions{MinSimilarity: 0.75}, "nearVector", 0.5, false},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			server := &fakeGraphQL{}
+			m := newSimilarityManager(t, server, c.embedder)
+			if _, err := m.SearchSimilar(context.Background(), "vídeos curtos", c.options); err != nil {
+				t.Fatal(err)
+			}
+			queries := server.take()
+			if len(queries) != 1 {
+				t.Fatalf("esperava uma consulta, obtidas %d", len(queries))
+			}
+			query := queries[0]
+			if !strings.Contains(query, c.operator) {
+				t.Errorf("esperava %s na consulta: %s", c.operator, query)
+			}
+			if strings.Contains(query, "limit:") != c.limit {
+				t.Errorf("limit inesperado na consulta: %s", query)
+			}
+			match := distanceArg.FindStringSubmatch(query)
+			if c.distance < 0 {
+				if match != nil {
+					t.Errorf("a consulta não deveria limitar a distância: %s", query)
+				}
+				return
+			}
+			if match == nil {
+				t.Fatalf("esperava a distância %v na consulta: %s", c.distance, query)
+			}
+			if got, _ := strconv.ParseFloat(match[1], 64); math.Abs(got-c.distance) > 1e-6 {
+				t.Errorf("distância %v, esperava %v", got, c.distance)
+			}
+		})
+	}
+}
+
+func TestSearchSimilarScores(t *testing.T) {
+	server := &fakeGraphQL{objects: []map[string]interface{}{
+		{"memoryId": "m-1", "agentId": "writer-1", "content": "a", "importance": 0.9, "timestamp": "2024-05-01T12:00:00Z", "tags": []string{"copy"}, "_additional": map[string]interface{}{"distance": 0.2}},
+		{"memoryId": "m-2", "agentId": "writer-1", "content": "b", "_additional": map[string]interface{}{"distance": 2.5}},
+		{"memoryId": "m-3", "agentId": "writer-1", "content": "c"},
+	}}
+	m := newSimilarityManager(t, server, nil)
+
+	memories, err := m.SearchSimilar(context.Background(), "vídeos curtos", SimilarityOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cases := []struct {
+		id                   string
+		distance, similarity float64
+	}{
+		{"m-1", 0.2, 0.9},
+		{"m-2", 2.5, 0}, // A similaridade é limitada a [0, 1]
+		{"m-3", 0, 0},   // Sem distância na resposta
+	}
+	if len(memories) != len(cases) {
+		t.Fatalf("esperava %d memórias, obtidas %d", len(cases), len(memories))
+	}
+	for i, c := range cases {
+		got := memories[i]
+		if got.ID != c.id || math.Abs(got.Distance-c.distance) > 1e-9 || math.Abs(got.Similarity-c.similarity) > 1e-9 {
+			t.Errorf("memória %+v, esperava %s com distância %v e similaridade %v", got, c.id, c.distance, c.similarity)
+		}
+	}
+	if memories[0].Timestamp.IsZero() || len(memories[0].Tags) != 1 || memories[0].Importance != 0.9 {
+		t.Errorf("campos não convertidos: %+v", memories[0])
+	}
+}
+
+func TestSearchSimilarInvalidOptions(t *testing.T) {
+	server := &fakeGraphQL{}
+	m := newSimilarityManager(t, server, nil)
+	if _, err := m.SearchSimilar(context.Background(), "consulta", SimilarityOptions{MinSimilarity: 2}); !errors.Is(err, errs.ErrValidation) {
+		t.Fatalf("esperava ErrValidation, obtido %v", err)
+	}
+	if queries := server.take(); len(queries) != 0 {
+		t.Fatalf("opções inválidas não deveriam consultar o Weaviate: %v", queries)
+	}
+}
@@ -0,0 +1,173 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/suissa/HiveMind/agents/errs"
+	"github.com/suissa/HiveMind/agents/tenant"
+)
+
+// SubjectEraser é implementado pelos gerenciadores que removem todas as memórias de um
+// titular de dados (direito ao esquecimento, como no GDPR/LGPD)
+type SubjectEraser interface {
+	DeleteMemoriesBySubject(ctx context.Context, subjectID string) (*DeletionReport, error)
+}
+
+// BackendDeletion registra as memórias removidas de um armazenamento
+type BackendDeletion struct {
+	Backend   string   `json:"backend"`
+	MemoryIDs []string `json:"memory_ids"`
+	Error     string   `json:"error,omitempty"`
+}
+
+// DeletionReport é o relatório de uma exclusão por titular, mantido para conformidade.
+// Não contém o conteúdo das memórias removidas.
+type DeletionReport struct {
+	SubjectID   string            `json:"subject_id"`
+	TenantID    string            `json:"tenant_id"`
+	RequestedAt time.Time         `json:"requested_at"`
+	CompletedAt time.Time         `json:"completed_at"`
+	Backends    []BackendDeletion `json:"backends"`
+}
+
+// Total retorna a quantidade de memórias removidas em todos os armazenamentos
+func (r *DeletionReport) Total() int {
+	total := 0
+	for _, b := range r.Backends {
+		total += len(b.MemoryIDs)
+	}
+	return total
+}
+
+// Complete indica se a exclusão foi concluída em todos os armazenamentos
+func (r *DeletionReport) Complete() bool {
+	for _, b := range r.Backends {
+		if b.Error != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// references indica se a memória referencia o titular: um dos termos (ID e pseudônimos) em
+// Subjects ou como token inteiro no conteúdo. A comparação é exata, para que o titular "ana"
+// não apague as memórias que citam "banana" ou "ana-maria".
+func references(memory *Memory, terms []string) bool {
+	for _, subject := range memory.Subjects {
+		for _, term := range terms {
+			if subject == term {
+				return true
+			}
+		}
+	}
+	for _, term := range terms {
+		if containsToken(memory.Content, term) {
+			return true
+		}
+	}
+	return false
+}
+
+// containsToken indica se o termo aparece no texto delimitado por caracteres que não fazem
+// parte de identificadores (letras, dígitos, '_' e '-')
+func containsToken(text, term string) bool {
+	if term == "" {
+		return false
+	}
+	for offset := 0; ; {
+		i := strings.Index(text[offset:], term)
+		if i < 0 {
+			return false
+		}
+		start, end := offset+i, offset+i+len(term)
+		before, _ := utf8.DecodeLastRuneInString(text[:start])
+		after, _ := utf8.DecodeRuneInString(text[end:])
+		if (start == 0 || !isTokenRune(before)) && (end == len(text) || !isTokenRune(after)) {
+			return true
+		}
+		_, size := utf8.DecodeRuneInString(text[start:])
+		offset = start + size
+	}
+}
+
+// isTokenRune indica se o caractere faz parte de um identificador
+func isTokenRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '-'
+}
+
+// tokenPattern é a expressão regular equivalente a containsToken, para as buscas no MongoDB
+func tokenPattern(term string) string {
+	return `(^|[^\p{L}\p{N}_-])` + regexp.QuoteMeta(term) + `($|[^\p{L}\p{N}_-])`
+}
+
+// subjectTerms retorna os termos que identificam o titular nas memórias: o próprio ID e,
+// com pseudonimização ativa, os pseudônimos gravados no lugar dele
+func (m *HybridMemoryManager) subjectTerms(ctx context.Context, subjectID string) ([]string, error) {
+	terms := []string{subjectID}
+	if m.anonymizer == nil {
+		return terms, nil
+	}
+
+	pseudonymized, err := m.anonymizer.Pseudonymize(ctx, subjectID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao pseudonimizar o titular: %w", err)
+	}
+	if pseudonymized != subjectID {
+		terms = append(terms, pseudonymized)
+	}
+	return terms, nil
+}
+
+// DeleteMemoriesBySubject remove do Redis, do MongoDB e do Weaviate todas as memórias do
+// tenant do contexto que referenciam o titular. Falhas de um armazenamento não interrompem
+// os demais: ficam registradas no relatório, que é devolvido junto com o erro.
+func (m *HybridMemoryManager) DeleteMemoriesBySubject(ctx context.Context, subjectID string) (*DeletionReport, error) {
+	if strings.TrimSpace(subjectID) == "" {
+		return nil, errs.New(errs.ErrValidation, "memory.DeleteMemoriesBySubject", "titular não informado")
+	}
+	ctx = m.scope(ctx)
+
+	terms, err := m.subjectTerms(ctx, subjectID)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &DeletionReport{
+		SubjectID:   subjectID,
+		TenantID:    tenant.FromContext(ctx),
+		RequestedAt: time.Now(),
+	}
+	var failures []string
+	record := func(backend string, ids []string, err error) {
+		deletion := BackendDeletion{Backend: backend, MemoryIDs: ids}
+		if deletion.MemoryIDs == nil {
+			deletion.MemoryIDs = []string{}
+		}
+		if err != nil {
+			deletion.Error = err.Error()
+			failures = append(failures, backend+": "+err.Error())
+		}
+		report.Backends = append(report.Backends, deletion)
+	}
+
+	shortIDs, err := m.shortTerm.deleteBySubject(ctx, terms)
+	record("redis", shortIDs, err)
+	longIDs, err := m.longTerm.deleteBySubject(ctx, terms)
+	record("mongodb", longIDs, err)
+
+	// O Weaviate também recebe os IDs já encontrados, já que a busca textual depende da tokenização
+	semanticIDs, err := m.semantic.deleteBySubject(ctx, terms, append(shortIDs, longIDs...))
+	record("weaviate", semanticIDs, err)
+
+	report.CompletedAt = time.Now()
+	if len(failures) > 0 {
+		return report, fmt.Errorf("exclusão do titular incompleta: %s", strings.Join(failures, "; "))
+	}
+	return report, nil
+}
@@ -0,0 +1,48 @@
+package memory
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestReferences(t *testing.T) {
+	terms := []string{"ana", "pseud-7f3a"}
+	cases := []struct {
+		name     string
+		memory   Memory
+		expected bool
+	}{
+		{"subjects exato", Memory{Subjects: []string{"ana"}}, true},
+		{"subjects com outro caso", Memory{Subjects: []string{"Ana"}}, false},
+		{"subjects parcial", Memory{Subjects: []string{"anabela"}}, false},
+		{"token no conteúdo", Memory{Content: "Reunião com ana, às 10h"}, true},
+		{"token no início e no fim", Memory{Content: "ana"}, true},
+		{"pseudônimo no conteúdo", Memory{Content: "cliente pseud-7f3a pediu reembolso"}, true},
+		{"parte de palavra", Memory{Content: "Receita de banana"}, false},
+		{"parte de identificador", Memory{Content: "ana-maria e ana_b confirmaram"}, false},
+		{"outro caso no conteúdo", Memory{Content: "ANA confirmou"}, false},
+		{"pseudônimo prefixo", Memory{Content: "pseud-7f3ab"}, false},
+		{"depois de um falso positivo", Memory{Content: "banana e ana"}, true},
+		{"acentos como letras", Memory{Content: "ãana anaé"}, false},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := references(&c.memory, terms); got != c.expected {
+				t.Fatalf("references(%+v) = %v, esperava %v", c.memory, got, c.expected)
+			}
+		})
+	}
+}
+
+func TestTokenPatternMatchesContainsToken(t *testing.T) {
+	texts := []string{"ana", "com ana.", "banana", "ana-maria", "(ana)", "anaé", "x ana_b ana"}
+	for _, text := range texts {
+		pattern := regexp.MustCompile(tokenPattern("ana"))
+		if got, want := pattern.MatchString(text), containsToken(text, "ana"); got != want {
+			t.Fatalf("tokenPattern e containsToken divergem em %q: %v != %v", text, got, want)
+		}
+	}
+	if regexp.MustCompile(tokenPattern("a.b")).MatchString("axb") {
+		t.Fatal("o termo deveria ser escapado na expressão regular")
+	}
+}
@@ -0,0 +1,83 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/suissa/HiveMind/agents/errs"
+)
+
+// TimelineProvider é implementado pelos gerenciadores com consulta cronológica
+type TimelineProvider interface {
+	GetMemoryTimeline(ctx context.Context, agentID string, from, to time.Time) ([]*Memory, error)
+}
+
+// GetMemoryTimeline retorna as memórias do agente criadas no intervalo [from, to), em ordem
+// cronológica e com a proveniência (workflow e tarefa) de cada uma. Combina as memórias de
+// curto prazo ainda vivas com as de longo prazo.
+func (m *HybridMemoryManager) GetMemoryTimeline(ctx context.Context, agentID string, from, to time.Time) (timeline []*Memory, err error) {
+	if !to.After(from) {
+		return nil, errs.New(errs.ErrValidation, "memory.GetMemoryTimeline", "intervalo inválido: %s a %s", from.Format(time.RFC3339), to.Format(time.RFC3339))
+	}
+	ctx = m.scope(ctx)
+	t := m.track("timeline", agentID)
+	defer func() { t.done(ctx, len(timeline), err) }()
+
+	var shortTermMemories, longTermMemories []*Memory
+	err = t.run(BackendRedis, func() (err error) {
+		shortTermMemories, err = m.shortTerm.SearchMemories(ctx, agentID, nil)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar memórias de curto prazo: %w", err)
+	}
+	err = t.run(BackendMongoDB, func() (err error) {
+		longTermMemories, err = m.longTerm.timeline(ctx, agentID, from, to)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar memórias de longo prazo: %w", err)
+	}
+
+	// Uma memória consolidada pode estar nos dois armazenamentos durante a migração
+	seen := make(map[string]bool)
+	for _, memory := range longTermMemories {
+		seen[memory.ID] = true
+		timeline = append(timeline, memory)
+	}
+	for _, memory := range shortTermMemories {
+		if !seen[memory.ID] && !memory.Timestamp.Before(from) && memory.Timestamp.Before(to) {
+			timeline = append(timeline, memory)
+		}
+	}
+
+	sort.SliceStable(timeline, func(i, j int) bool {
+		return timeline[i].Timestamp.Before(timeline[j].Timestamp)
+	})
+	return timeline, nil
+}
+
+// timeline busca as memórias do agente no intervalo pelo índice (tenant_id, agent_id, timestamp)
+func (m *MongoMemoryManager) timeline(ctx context.Context, agentID string, from, to time.Time) ([]*Memory, error) {
+	filter := scoped(ctx, bson.M{
+		"agent_id":  agentID,
+		"timestamp": bson.M{"$gte": from, "$lt": to},
+	})
+	opts := options.Find().SetSort(bson.D{{Key: "timestamp", Value: 1}, {Key: "_id", Value: 1}})
+	cursor, err := m.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar memórias: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var memories []*Memory
+	if err := cursor.All(ctx, &memories); err != nil {
+		return nil, fmt.Errorf("erro ao decodificar memórias: %w", err)
+	}
+	return memories, nil
+}
@@ -0,0 +1,86 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+
+	"github.com/suissa/HiveMind/agents/errs"
+	"github.com/suissa/HiveMind/agents/tenant"
+)
+
+func TestGetMemoryTimelineInterval(t *testing.T) {
+	now := time.Now()
+	cases := []struct {
+		name     string
+		from, to time.Time
+	}{
+		{"intervalo vazio", now, now},
+		{"intervalo invertido", now, now.Add(-time.Hour)},
+	}
+	manager := &HybridMemoryManager{}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if _, err := manager.GetMemoryTimeline(context.Background(), "writer-1", c.from, c.to); !errors.Is(err, errs.ErrValidation) {
+				t.Fatalf("esperava ErrValidation, obtido %v", err)
+			}
+		})
+	}
+}
+
+func TestMongoTimeline(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	from := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(24 * time.Hour)
+
+	mt.Run("intervalo e ordem cronológica", func(mt *mtest.T) {
+		manager := &MongoMemoryManager{collection: mt.Coll}
+		ns := mt.Coll.Database().Name() + "." + mt.Coll.Name()
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, ns, mtest.FirstBatch,
+			memoryDoc("m-1", from.Add(time.Hour)),
+			memoryDoc("m-2", from.Add(2*time.Hour)),
+		))
+
+		memories, err := manager.timeline(tenant.WithTenant(context.Background(), "acme"), "writer-1", from, to)
+		if err != nil {
+			mt.Fatal(err)
+		}
+		if len(memories) != 2 || memories[0].ID != "m-1" || memories[1].ID != "m-2" {
+			mt.Fatalf("linha do tempo inesperada: %+v", memories)
+		}
+
+		event := mt.GetStartedEvent()
+		var filter bson.M
+		if err := bson.Unmarshal(event.Command.Lookup("filter").Document(), &filter); err != nil {
+			mt.Fatal(err)
+		}
+		if filter["agent_id"] != "writer-1" || filter["tenant_id"] != "acme" {
+			mt.Errorf("filtro inesperado: %v", filter)
+		}
+		interval, ok := filter["timestamp"].(bson.M)
+		if !ok || interval["$gte"] != primitive.NewDateTimeFromTime(from) || interval["$lt"] != primitive.NewDateTimeFromTime(to) {
+			mt.Errorf("esperava o intervalo [from, to): %v", filter["timestamp"])
+		}
+		var sort bson.D
+		if err := bson.Unmarshal(event.Command.Lookup("sort").Document(), &sort); err != nil {
+			mt.Fatal(err)
+		}
+		want := bson.D{{Key: "timestamp", Value: int32(1)}, {Key: "_id", Value: int32(1)}}
+		if len(sort) != len(want) || sort[0] != want[0] || sort[1] != want[1] {
+			mt.Errorf("ordenação %v, esperava %v", sort, want)
+		}
+	})
+
+	mt.Run("erro do MongoDB", func(mt *mtest.T) {
+		manager := &MongoMemoryManager{collection: mt.Coll}
+		mt.AddMockResponses(mtest.CreateCommandErrorResponse(mtest.CommandError{Code: 2, Message: "falha"}))
+		if _, err := manager.timeline(context.Background(), "writer-1", from, to); err == nil {
+			mt.Fatal("esperava o erro da consulta")
+		}
+	})
+}
@@ -3,6 +3,9 @@ package memory
 import (
 	"context"
 	"time"
+
+	"github.com/suissa/HiveMind/agents/errs"
+	"github.com/suissa/HiveMind/agents/maintenance"
 )
 
 // MemoryType representa o tipo de memória
@@ -13,11 +16,14 @@ const (
 	ShortTerm MemoryType = "short_term"
 	// LongTerm representa memória de longo prazo
 	LongTerm MemoryType = "long_term"
+	// Scratch representa uma anotação intermediária de workflow, descartada ao final dele
+	Scratch MemoryType = "scratch"
 )
 
 // Memory representa uma unidade de memória
 type Memory struct {
 	ID         string        `json:"id" bson:"_id"`
+	TenantID   string        `json:"tenant_id,omitempty" bson:"tenant_id,omitempty"`
 	AgentID    string        `json:"agent_id" bson:"agent_id"`
 	Content    string        `json:"content" bson:"content"`
 	Type       MemoryType    `json:"type" bson:"type"`
@@ -25,7 +31,29 @@ type Memory struct {
 	Timestamp  time.Time     `json:"timestamp" bson:"timestamp"`
 	TTL        time.Duration `json:"ttl" bson:"ttl"`
 	Tags       []string      `json:"tags" bson:"tags"`
+	Subjects   []string      `json:"subjects,omitempty" bson:"subjects,omitempty"` // Pessoas referenciadas (exclusão por titular)
+	Merged     int           `json:"merged,omitempty" bson:"merged,omitempty"`     // Repetições quase idênticas mescladas nesta memória
+	Provenance *Provenance   `json:"provenance,omitempty" bson:"provenance,omitempty"`
 	Metadata   interface{}   `json:"metadata,omitempty" bson:"metadata,omitempty"`
+
+	// Preenchidos apenas nos resultados da busca por similaridade
+	Similarity float64 `json:"similarity,omitempty" bson:"-"` // Entre 0 e 1 (1 é idêntica)
+	Distance   float64 `json:"distance,omitempty" bson:"-"`   // Distância cosseno até a consulta, entre 0 e 2
+}
+
+// Validate verifica os campos obrigatórios da memória
+func (m *Memory) Validate() error {
+	switch {
+	case m == nil:
+		return errs.New(errs.ErrValidation, "memory.Validate", "memória nula")
+	case m.ID == "":
+		return errs.New(errs.ErrValidation, "memory.Validate", "memória sem ID")
+	case m.AgentID == "":
+		return errs.New(errs.ErrValidation, "memory.Validate", "memória %s sem agente", m.ID)
+	case m.Importance < 0 || m.Importance > 1:
+		return errs.New(errs.ErrValidation, "memory.Validate", "importância %.2f fora do intervalo [0, 1]", m.Importance)
+	}
+	return nil
 }
 
 // MemoryConfig contém a configuração para o sistema de memória
@@ -44,23 +72,50 @@ type MemoryConfig struct {
 	WeaviateClass     string `json:"weaviate_class" yaml:"weaviate_class"`
 	WeaviateBatchSize int    `json:"weaviate_batch_size" yaml:"weaviate_batch_size"`
 
+	// Cache semântico de respostas do LLM (classe no Weaviate e certeza mínima para reutilizar)
+	SemanticCacheClass     string  `json:"semantic_cache_class" yaml:"semantic_cache_class"`
+	SemanticCacheThreshold float64 `json:"semantic_cache_threshold" yaml:"semantic_cache_threshold"`
+
+	// Validade dos embeddings guardados no Redis quando o gerenciador usa um Embedder
+	EmbeddingCacheTTL time.Duration `json:"embedding_cache_ttl" yaml:"embedding_cache_ttl"`
+
+	// Tenant padrão usado quando o contexto não informa um namespace
+	Tenant string `json:"tenant" yaml:"tenant"`
+
 	// Configurações gerais
 	ImportanceThreshold float64       `json:"importance_threshold" yaml:"importance_threshold"`
 	ShortTermTTL        time.Duration `json:"short_term_ttl" yaml:"short_term_ttl"`
+
+	// Detecção de memórias quase duplicadas do mesmo agente: acima da certeza DedupThreshold a
+	// memória nova é mesclada à existente, cuja importância sobe DedupBoost. Desativada por
+	// padrão (0), já que o conteúdo da memória nova é descartado na mesclagem.
+	DedupThreshold float64 `json:"dedup_threshold" yaml:"dedup_threshold"`
+	DedupBoost     float64 `json:"dedup_boost" yaml:"dedup_boost"`
+
+	// Manutenção automática (consolidação em lotes e limpeza) executada pelo próprio
+	// gerenciador a partir de StartMaintenance; nil desativa
+	Maintenance *maintenance.Config `json:"maintenance,omitempty" yaml:"maintenance,omitempty"`
+
+	// DisableRedaction desativa o mascaramento de segredos no conteúdo das memórias
+	DisableRedaction bool `json:"disable_redaction" yaml:"disable_redaction"`
 }
 
 // DefaultMemoryConfig retorna uma configuração padrão
 func DefaultMemoryConfig() *MemoryConfig {
 	return &MemoryConfig{
-		RedisURL:            "localhost:6379",
-		MongoURL:            "mongodb://localhost:27017",
-		MongoDB:             "agent_memory",
-		Collection:          "memories",
-		WeaviateURL:         "http://localhost:8080",
-		WeaviateClass:       "Memory",
-		WeaviateBatchSize:   100,
-		ImportanceThreshold: 0.7,
-		ShortTermTTL:        24 * time.Hour,
+		RedisURL:               "localhost:6379",
+		MongoURL:               "mongodb://localhost:27017",
+		MongoDB:                "agent_memory",
+		Collection:             "memories",
+		WeaviateURL:            "http://localhost:8080",
+		WeaviateClass:          "Memory",
+		WeaviateBatchSize:      100,
+		SemanticCacheClass:     DefaultSemanticCacheClass,
+		SemanticCacheThreshold: DefaultSemanticCacheThreshold,
+		EmbeddingCacheTTL:      DefaultEmbeddingCacheTTL,
+		DedupBoost:             DefaultDedupBoost,
+		ImportanceThreshold:    0.7,
+		ShortTermTTL:           24 * time.Hour,
 	}
 }
 
@@ -0,0 +1,192 @@
+package agents
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/suissa/HiveMind/agents/errs"
+	"github.com/suissa/HiveMind/agents/memory"
+)
+
+// fakeMemory é um gerenciador de memória em memória; como o híbrido, data as memórias sem
+// Timestamp ao gravá-las
+type fakeMemory struct {
+	mu       sync.Mutex
+	memories []*memory.Memory
+	full     map[string]string // Conteúdo completo por ID, resolvido por FullContent
+}
+
+func (f *fakeMemory) StoreMemory(ctx context.Context, m *memory.Memory) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if m.Timestamp.IsZero() {
+		m.Timestamp = time.Now()
+	}
+	f.memories = append(f.memories, m)
+	return nil
+}
+
+func (f *fakeMemory) GetMemory(ctx context.Context, agentID, memoryID string) (*memory.Memory, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, m := range f.memories {
+		if m.AgentID == agentID && m.ID == memoryID {
+			return m, nil
+		}
+	}
+	return nil, errs.New(errs.ErrNotFound, "fakeMemory.GetMemory", "memória %s não encontrada", memoryID)
+}
+
+func (f *fakeMemory) SearchMemories(ctx context.Context, agentID string, tags []string) ([]*memory.Memory, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var found []*memory.Memory
+	for _, m := range f.memories {
+		if m.AgentID == agentID && hasTags(m.Tags, tags) {
+			found = append(found, m)
+		}
+	}
+	return found, nil
+}
+
+func hasTags(memoryTags, tags []string) bool {
+	for _, tag := range tags {
+		found := false
+		for _, t := range memoryTags {
+			found = found || t == tag
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func (f *fakeMemory) SearchSimilarMemories(ctx context.Context, query string, limit int) ([]*memory.Memory, error) {
+	return nil, nil
+}
+
+func (f *fakeMemory) UpdateMemory(ctx context.Context, m *memory.Memory) error { return nil }
+
+func (f *fakeMemory) DeleteMemory(ctx context.Context, agentID, memoryID string) error { return nil }
+
+func (f *fakeMemory) ConsolidateMemories(ctx context.Context, agentID string) error { return nil }
+
+func (f *fakeMemory) PruneMemories(ctx context.Context, agentID string) error { return nil }
+
+func (f *fakeMemory) Close(ctx context.Context) error { return nil }
+
+// resolvingMemory também resolve os conteúdos movidos para o armazenamento de objetos
+type resolvingMemory struct {
+	*fakeMemory
+}
+
+func (r resolvingMemory) FullContent(ctx context.Context, m *memory.Memory) (string, error) {
+	if content, ok := r.full[m.ID]; ok {
+		return content, nil
+	}
+	return m.Content, nil
+}
+
+type copyValue struct {
+	Title string   `json:"title"`
+	Tags  []string `json:"tags"`
+}
+
+func TestMemorizeRecallValue(t *testing.T) {
+	store := &fakeMemory{}
+	writer := NewCognitiveAgent("creator", "Criador", "", 1, "gpt-4", "criador", "escrever", store)
+	reader := NewCognitiveAgent("reviewer", "Revisor", "", 1, "gpt-4", "revisor", "revisar", store)
+	ctx := context.Background()
+
+	if found, err := writer.RecallValue(ctx, "", "marketing.copy/v1", &copyValue{}); err != nil || found {
+		t.Fatalf("não deveria haver valor antes da gravação: %v %v", found, err)
+	}
+
+	first := copyValue{Title: "Primeira versão", Tags: []string{"a"}}
+	if err := writer.MemorizeValue(ctx, "marketing.copy/v1", first, 0.7, []string{"copy"}, true); err != nil {
+		t.Fatal(err)
+	}
+	// Outro esquema com a mesma tag não interfere
+	if err := writer.MemorizeValue(ctx, "marketing.strategy/v1", map[string]string{"name": "Inbound"}, 0.7, []string{"copy"}, true); err != nil {
+		t.Fatal(err)
+	}
+	second := copyValue{Title: "Segunda versão", Tags: []string{"b", "c"}}
+	if err := writer.MemorizeValue(ctx, "marketing.copy/v1", second, 0.7, []string{"copy"}, true); err != nil {
+		t.Fatal(err)
+	}
+	// Memórias gravadas em sequência podem ter o mesmo instante; a ordem vem do Timestamp
+	store.memories[0].Timestamp = store.memories[2].Timestamp.Add(-time.Minute)
+
+	if tags := store.memories[0].Tags; len(tags) != 2 || tags[1] != memory.SchemaTag("marketing.copy/v1") {
+		t.Fatalf("a memória deveria levar a tag do esquema: %v", tags)
+	}
+
+	// Outro agente lê o valor mais recente gravado pelo criador
+	var recalled copyValue
+	found, err := reader.RecallValue(ctx, "creator", "marketing.copy/v1", &recalled)
+	if err != nil || !found {
+		t.Fatalf("esperava encontrar o valor: %v %v", found, err)
+	}
+	if recalled.Title != second.Title || len(recalled.Tags) != 2 {
+		t.Fatalf("esperava a versão mais recente: %+v", recalled)
+	}
+
+	// O esquema faz parte da busca: o leitor não encontra as memórias do próprio agente
+	if found, _ := reader.RecallValue(ctx, "", "marketing.copy/v1", &recalled); found {
+		t.Fatal("o revisor não gravou nenhum texto")
+	}
+}
+
+func TestMemorizeValueRejectsInvalidValues(t *testing.T) {
+	store := &fakeMemory{}
+	agent := NewCognitiveAgent("creator", "Criador", "", 1, "gpt-4", "criador", "escrever", store)
+
+	if err := agent.MemorizeValue(context.Background(), "", copyValue{}, 0.5, nil, true); !errors.Is(err, errs.ErrValidation) {
+		t.Fatalf("esperava ErrValidation sem esquema: %v", err)
+	}
+	if err := agent.MemorizeValue(context.Background(), "s/v1", "texto", 0.5, nil, true); !errors.Is(err, errs.ErrValidation) {
+		t.Fatalf("esperava ErrValidation para um valor que não é objeto: %v", err)
+	}
+	if len(store.memories) != 0 {
+		t.Fatal("valores inválidos não deveriam ser gravados")
+	}
+}
+
+func TestRecallValueSchemaMismatch(t *testing.T) {
+	store := &fakeMemory{}
+	agent := NewCognitiveAgent("creator", "Criador", "", 1, "gpt-4", "criador", "escrever", store)
+	ctx := context.Background()
+
+	// Uma memória com a tag do esquema mas conteúdo de outro esquema é recusada
+	content, _ := memory.Content("marketing.strategy/v1", map[string]string{"name": "Inbound"})
+	if err := agent.Memorize(ctx, content, 0.5, []string{memory.SchemaTag("marketing.copy/v1")}, true); err != nil {
+		t.Fatal(err)
+	}
+	var v copyValue
+	if found, err := agent.RecallValue(ctx, "", "marketing.copy/v1", &v); found || err != nil {
+		t.Fatalf("Latest deveria ignorar o conteúdo de outro esquema: %v %v", found, err)
+	}
+}
+
+func TestRecallValueResolvesFullContent(t *testing.T) {
+	store := &fakeMemory{full: map[string]string{}}
+	agent := NewCognitiveAgent("creator", "Criador", "", 1, "gpt-4", "criador", "escrever", resolvingMemory{store})
+	ctx := context.Background()
+
+	if err := agent.MemorizeValue(ctx, "marketing.copy/v1", copyValue{Title: "Completo"}, 0.5, nil, true); err != nil {
+		t.Fatal(err)
+	}
+	// A memória guarda só a prévia; o conteúdo completo está no armazenamento de objetos
+	m := store.memories[0]
+	store.full[m.ID] = m.Content
+	m.Content = "prévia..."
+
+	var v copyValue
+	if found, err := agent.RecallValue(ctx, "", "marketing.copy/v1", &v); !found || err != nil || v.Title != "Completo" {
+		t.Fatalf("esperava decodificar o conteúdo completo: %v %v %+v", found, err, v)
+	}
+}
@@ -0,0 +1,354 @@
+// Package codegen gera os tipos Go das mensagens a partir dos JSON Schemas em
+// agents/messages/schemas. Cada schema de objeto vira uma struct com as tags JSON dos campos
+// e um método Validate que confere os campos obrigatórios, os enums, os mínimos e máximos e
+// o formato date-time. Usado por cmd/msggen (go generate) e pelo teste que detecta tipos
+// desatualizados.
+//
+// Extensões aceitas nos schemas:
+//   - x-go-type: tipo Go do campo (ex.: "*overrides.Overrides" ou "int64")
+//   - x-go-import: pacote importado pelo tipo de x-go-type
+//   - x-go-name: nome do campo, quando a conversão do nome JSON não serve
+//   - x-go-preserve-unknown: no schema do objeto, guarda em Extra os campos não declarados e os
+//     devolve na serialização; o pacote gerado precisa definir as funções unknownFields e
+//     withFields
+package codegen
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// SchemaSuffix é o sufixo dos arquivos de schema lidos pelo gerador
+const SchemaSuffix = ".schema.json"
+
+// Schema é o subconjunto do JSON Schema entendido pelo gerador
+type Schema struct {
+	Title                string      `json:"title"`
+	Description          string      `json:"description"`
+	Type                 string      `json:"type"`
+	Required             []string    `json:"required"`
+	Properties           Properties  `json:"properties"`
+	Items                *Schema     `json:"items"`
+	Enum                 []string    `json:"enum"`
+	Minimum              *float64    `json:"minimum"`
+	Maximum              *float64    `json:"maximum"`
+	Format               string      `json:"format"`
+	Ref                  string      `json:"$ref"`
+	AdditionalProperties interface{} `json:"additionalProperties"`
+	GoType               string      `json:"x-go-type"`
+	GoImport             string      `json:"x-go-import"`
+	GoName               string      `json:"x-go-name"`
+	GoPreserveUnknown    bool        `json:"x-go-preserve-unknown"`
+}
+
+// Property é um campo do schema, na ordem em que foi declarado
+type Property struct {
+	Name   string
+	Schema *Schema
+}
+
+// Properties preserva a ordem dos campos, que define a ordem dos campos da struct
+type Properties []Property
+
+// UnmarshalJSON lê as propriedades na ordem do documento
+func (p *Properties) UnmarshalJSON(data []byte) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	if token, err := decoder.Token(); err != nil || token != json.Delim('{') {
+		return fmt.Errorf("properties deve ser um objeto")
+	}
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return err
+		}
+		var schema Schema
+		if err := decoder.Decode(&schema); err != nil {
+			return fmt.Errorf("propriedade %v: %w", token, err)
+		}
+		*p = append(*p, Property{Name: token.(string), Schema: &schema})
+	}
+	return nil
+}
+
+// initialisms são as siglas escritas em maiúsculas nos nomes Go
+var initialisms = map[string]string{"id": "ID", "url": "URL", "api": "API", "llm": "LLM", "json": "JSON", "http": "HTTP"}
+
+// goName converte o nome JSON (snake_case) no nome do campo Go
+func goName(name string) string {
+	var b strings.Builder
+	for _, part := range strings.Split(name, "_") {
+		if upper, ok := initialisms[part]; ok {
+			b.WriteString(upper)
+		} else if part != "" {
+			b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+		}
+	}
+	return b.String()
+}
+
+// generator guarda os schemas lidos e os pacotes importados pelo código gerado
+type generator struct {
+	schemas map[string]*Schema // Pelo nome do arquivo, para resolver $ref
+	imports map[string]bool
+	out     bytes.Buffer
+}
+
+// Generate lê os schemas do diretório e retorna o código Go formatado do pacote
+func Generate(dir fs.FS, pkg string) ([]byte, error) {
+	files, err := fs.Glob(dir, "*"+SchemaSuffix)
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("nenhum arquivo %s encontrado", SchemaSuffix)
+	}
+	sort.Strings(files)
+
+	g := &generator{schemas: make(map[string]*Schema), imports: map[string]bool{"github.com/suissa/HiveMind/agents/errs": true}}
+	for _, file := range files {
+		data, err := fs.ReadFile(dir, file)
+		if err != nil {
+			return nil, err
+		}
+		var schema Schema
+		if err := json.Unmarshal(data, &schema); err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+		if schema.Type != "object" || schema.Title == "" {
+			return nil, fmt.Errorf("%s: o schema precisa ser um objeto com title", file)
+		}
+		g.schemas[path.Base(file)] = &schema
+	}
+
+	var body bytes.Buffer
+	for _, file := range files {
+		if err := g.writeType(&body, g.schemas[path.Base(file)]); err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+	}
+
+	fmt.Fprintf(&g.out, "// Code generated by msggen from schemas/*%s. DO NOT EDIT.\n\npackage %s\n\nimport (\n", SchemaSuffix, pkg)
+	var std, local []string
+	for imp := range g.imports {
+		if strings.Contains(imp, ".") {
+			local = append(local, imp)
+		} else {
+			std = append(std, imp)
+		}
+	}
+	sort.Strings(std)
+	sort.Strings(local)
+	for _, imp := range std {
+		fmt.Fprintf(&g.out, "\t%q\n", imp)
+	}
+	if len(std) > 0 {
+		g.out.WriteString("\n")
+	}
+	for _, imp := range local {
+		fmt.Fprintf(&g.out, "\t%q\n", imp)
+	}
+	g.out.WriteString(")\n")
+	g.out.Write(body.Bytes())
+
+	source, err := format.Source(g.out.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("código gerado inválido: %w", err)
+	}
+	return source, nil
+}
+
+// field é um campo da struct com o tipo já resolvido
+type field struct {
+	Property
+	GoName   string
+	GoType   string
+	Required bool
+	Ref      *Schema // Schema referenciado por $ref
+}
+
+// writeType escreve a struct e o método Validate do schema
+func (g *generator) writeType(w *bytes.Buffer, schema *Schema) error {
+	required := make(map[string]bool)
+	for _, name := range schema.Required {
+		required[name] = true
+	}
+
+	var fields []field
+	for _, property := range schema.Properties {
+		f := field{Property: property, Required: required[property.Name], GoName: property.Schema.GoName}
+		if f.GoName == "" {
+			f.GoName = goName(property.Name)
+		}
+		goType, ref, err := g.goType(property.Schema)
+		if err != nil {
+			return fmt.Errorf("campo %s: %w", property.Name, err)
+		}
+		// Objetos referenciados opcionais viram ponteiros, para que a ausência não seja validada
+		if ref != nil && !f.Required {
+			goType = "*" + goType
+		}
+		f.GoType, f.Ref = goType, ref
+		fields = append(fields, f)
+		delete(required, property.Name)
+	}
+	for name := range required {
+		return fmt.Errorf("campo obrigatório %s não declarado em properties", name)
+	}
+
+	fmt.Fprintf(w, "\n// %s %s\ntype %s struct {\n", schema.Title, schema.Description, schema.Title)
+	for _, f := range fields {
+		if f.Schema.Description != "" {
+			fmt.Fprintf(w, "\t// %s\n", f.Schema.Description)
+		}
+		tag := f.Name
+		if !f.Required {
+			tag += ",omitempty"
+		}
+		fmt.Fprintf(w, "\t%s %s `json:%q`\n", f.GoName, f.GoType, tag)
+	}
+	if schema.GoPreserveUnknown {
+		g.imports["encoding/json"] = true
+		w.WriteString("\t// Campos não declarados no schema (de versões mais novas do contrato), devolvidos na serialização\n")
+		w.WriteString("\tExtra map[string]json.RawMessage `json:\"-\"`\n")
+	}
+	w.WriteString("}\n")
+	if schema.GoPreserveUnknown {
+		writeUnknownFields(w, schema.Title, fields)
+	}
+
+	fmt.Fprintf(w, "\n// Validate confere a mensagem contra o schema %s\nfunc (m %s) Validate() error {\n", schema.Title, schema.Title)
+	op := strconv.Quote("messages." + schema.Title)
+	for _, f := range fields {
+		g.writeChecks(w, op, f)
+	}
+	w.WriteString("\treturn nil\n}\n")
+	return nil
+}
+
+// writeUnknownFields escreve os métodos JSON que preservam os campos não declarados
+func writeUnknownFields(w *bytes.Buffer, title string, fields []field) {
+	names := make([]string, len(fields))
+	for i, f := range fields {
+		names[i] = strconv.Quote(f.Name)
+	}
+	fmt.Fprintf(w, `
+// UnmarshalJSON guarda em Extra os campos que o schema %[1]s não declara
+func (m *%[1]s) UnmarshalJSON(data []byte) error {
+	type plain %[1]s
+	if err := json.Unmarshal(data, (*plain)(m)); err != nil {
+		return err
+	}
+	extra, err := unknownFields(data, %[2]s)
+	m.Extra = extra
+	return err
+}
+
+// MarshalJSON serializa a mensagem com os campos guardados em Extra
+func (m %[1]s) MarshalJSON() ([]byte, error) {
+	type plain %[1]s
+	data, err := json.Marshal(plain(m))
+	if err != nil || len(m.Extra) == 0 {
+		return data, err
+	}
+	return withFields(data, m.Extra)
+}
+`, title, strings.Join(names, ", "))
+}
+
+// writeChecks escreve as verificações do campo
+func (g *generator) writeChecks(w *bytes.Buffer, op string, f field) {
+	name := strconv.Quote(f.Name)
+	value := "m." + f.GoName
+	invalid := func(format string, args ...string) string {
+		return fmt.Sprintf("return errs.New(errs.ErrValidation, %s, %s, %s)", op, strconv.Quote(format), strings.Join(append([]string{name}, args...), ", "))
+	}
+
+	switch {
+	case f.Ref != nil && f.Required:
+		g.imports["fmt"] = true
+		fmt.Fprintf(w, "\tif err := %s.Validate(); err != nil {\n\t\treturn fmt.Errorf(\"%%s: %%w\", %s, err)\n\t}\n", value, name)
+	case f.Ref != nil:
+		g.imports["fmt"] = true
+		fmt.Fprintf(w, "\tif %s != nil {\n\t\tif err := %s.Validate(); err != nil {\n\t\t\treturn fmt.Errorf(\"%%s: %%w\", %s, err)\n\t\t}\n\t}\n", value, value, name)
+	case f.Required && f.GoType == "string":
+		fmt.Fprintf(w, "\tif %s == \"\" {\n\t\t%s\n\t}\n", value, invalid("campo %q obrigatório"))
+	case f.Required && (strings.HasPrefix(f.GoType, "*") || strings.HasPrefix(f.GoType, "map[") || strings.HasPrefix(f.GoType, "[]")):
+		fmt.Fprintf(w, "\tif %s == nil {\n\t\t%s\n\t}\n", value, invalid("campo %q obrigatório"))
+	}
+
+	if len(f.Schema.Enum) > 0 {
+		cases := make([]string, 0, len(f.Schema.Enum)+1)
+		if !f.Required {
+			cases = append(cases, `""`)
+		}
+		for _, option := range f.Schema.Enum {
+			cases = append(cases, strconv.Quote(option))
+		}
+		allowed := strconv.Quote(strings.Join(f.Schema.Enum, ", "))
+		fmt.Fprintf(w, "\tswitch %s {\n\tcase %s:\n\tdefault:\n\t\t%s\n\t}\n", value, strings.Join(cases, ", "),
+			invalid("campo %q com valor %q fora de: %s", value, allowed))
+	}
+	if f.Schema.Format == "date-time" {
+		g.imports["time"] = true
+		fmt.Fprintf(w, "\tif %s != \"\" {\n\t\tif _, err := time.Parse(time.RFC3339, %s); err != nil {\n\t\t\t%s\n\t\t}\n\t}\n",
+			value, value, invalid("campo %q não é uma data RFC 3339: %q", value))
+	}
+	if f.Schema.Minimum != nil {
+		limit := strconv.FormatFloat(*f.Schema.Minimum, 'g', -1, 64)
+		fmt.Fprintf(w, "\tif %s < %s {\n\t\t%s\n\t}\n", value, limit, invalid("campo %q abaixo do mínimo "+limit+": %v", value))
+	}
+	if f.Schema.Maximum != nil {
+		limit := strconv.FormatFloat(*f.Schema.Maximum, 'g', -1, 64)
+		fmt.Fprintf(w, "\tif %s > %s {\n\t\t%s\n\t}\n", value, limit, invalid("campo %q acima do máximo "+limit+": %v", value))
+	}
+}
+
+// goType resolve o tipo Go do schema; para $ref retorna também o schema referenciado
+func (g *generator) goType(schema *Schema) (string, *Schema, error) {
+	if schema.GoType != "" {
+		if schema.GoImport != "" {
+			g.imports[schema.GoImport] = true
+		}
+		return schema.GoType, nil, nil
+	}
+	if schema.Ref != "" {
+		ref, ok := g.schemas[path.Base(schema.Ref)]
+		if !ok {
+			return "", nil, fmt.Errorf("$ref %s não encontrado", schema.Ref)
+		}
+		return ref.Title, ref, nil
+	}
+	switch schema.Type {
+	case "string":
+		return "string", nil, nil
+	case "integer":
+		return "int", nil, nil
+	case "number":
+		return "float64", nil, nil
+	case "boolean":
+		return "bool", nil, nil
+	case "object":
+		return "map[string]interface{}", nil, nil
+	case "array":
+		if schema.Items == nil {
+			return "[]interface{}", nil, nil
+		}
+		item, ref, err := g.goType(schema.Items)
+		if err != nil {
+			return "", nil, err
+		}
+		if ref != nil {
+			return "", nil, fmt.Errorf("arrays de $ref não são suportados")
+		}
+		return "[]" + item, nil, nil
+	default:
+		return "", nil, fmt.Errorf("tipo %q não suportado", schema.Type)
+	}
+}
@@ -0,0 +1,112 @@
+package messages
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/suissa/HiveMind/agents/errs"
+)
+
+// Versões do contrato das mensagens de tarefa (TaskRequest e SubTask). A versão 1 é a das
+// mensagens anteriores ao versionamento, sem os campos version e min_version.
+//
+// Numa atualização gradual, os processos antigos continuam consumindo as mensagens dos novos:
+// os campos que não conhecem são guardados em Extra e devolvidos quando a mensagem é
+// republicada. Uma mensagem que depende de um campo novo para ser processada corretamente
+// declara em min_version a versão que o introduziu, e os processos mais antigos a devolvem à
+// fila para um consumidor atualizado.
+const (
+	// Version é a versão do contrato escrita nas mensagens publicadas por este processo
+	Version = 2
+	// MinVersion é a menor versão capaz de processar as mensagens publicadas por este processo
+	MinVersion = 1
+)
+
+// ErrUnsupportedVersion indica uma mensagem que exige uma versão de contrato mais nova que a
+// deste processo. Ao contrário de ErrValidation, a mensagem não está errada: deve voltar à
+// fila para um consumidor atualizado.
+var ErrUnsupportedVersion = errors.New("versão de contrato não suportada")
+
+// stamp preenche a versão das mensagens publicadas; mensagens republicadas mantêm as versões
+// com que foram escritas
+func stamp(version, minVersion *int) {
+	if *version == 0 {
+		*version = Version
+	}
+	if *minVersion == 0 {
+		*minVersion = MinVersion
+	}
+}
+
+// supports confere se este processo atende à versão mínima exigida pela mensagem
+func supports(op string, version, minVersion int) error {
+	if minVersion > Version {
+		return errs.New(ErrUnsupportedVersion, op, "a mensagem (versão %d) exige a versão %d e este processo suporta até a %d",
+			version, minVersion, Version)
+	}
+	return nil
+}
+
+// stamper é implementada pelas mensagens com versão de contrato, pelo valor ou ponteiro
+type stamper interface {
+	stamped() Message
+}
+
+// negotiator é implementada pelos ponteiros das mensagens com versão de contrato
+type negotiator interface {
+	negotiate() error
+}
+
+func (m TaskRequest) stamped() Message {
+	stamp(&m.Version, &m.MinVersion)
+	return m
+}
+
+func (m *TaskRequest) negotiate() error {
+	return supports("messages.TaskRequest", m.Version, m.MinVersion)
+}
+
+func (m SubTask) stamped() Message {
+	stamp(&m.Version, &m.MinVersion)
+	return m
+}
+
+func (m *SubTask) negotiate() error {
+	if err := supports("messages.SubTask", m.Version, m.MinVersion); err != nil {
+		return err
+	}
+	// Na versão 1 o status era opcional: as subtarefas publicadas sem ele estavam pendentes
+	if m.Version <= 1 && m.Status == "" {
+		m.Status = "pending"
+	}
+	return nil
+}
+
+// unknownFields retorna os campos do objeto JSON que não estão entre os declarados
+func unknownFields(data []byte, known ...string) (map[string]json.RawMessage, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	for _, name := range known {
+		delete(fields, name)
+	}
+	if len(fields) == 0 {
+		return nil, nil
+	}
+	return fields, nil
+}
+
+// withFields acrescenta ao objeto JSON os campos extras que ele ainda não tem
+func withFields(data []byte, extra map[string]json.RawMessage) ([]byte, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	for name, value := range extra {
+		if _, ok := fields[name]; !ok {
+			fields[name] = value
+		}
+	}
+	return json.Marshal(fields)
+}
@@ -0,0 +1,49 @@
+package messages
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestUnknownFieldsRoundTrip(t *testing.T) {
+	// Subtarefa de uma versão mais nova, com um campo que esta versão não conhece
+	data := []byte(`{"id":"s-1","parent_id":"t-1","type":"analysis","status":"pending","version":3,"min_version":2,"deadline":{"at":"2030-01-01T00:00:00Z"}}`)
+	var task SubTask
+	if err := Decode(data, &task); err != nil {
+		t.Fatal(err)
+	}
+	if task.Version != 3 || string(task.Extra["deadline"]) != `{"at":"2030-01-01T00:00:00Z"}` {
+		t.Fatalf("subtarefa decodificada inesperada: %+v", task)
+	}
+
+	task.AssignedTo = "agent-1"
+	out, err := Encode(task)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fields map[string]interface{}
+	json.Unmarshal(out, &fields)
+	if fields["deadline"] == nil || fields["assigned_to"] != "agent-1" || fields["version"] != float64(3) {
+		t.Fatalf("o campo desconhecido e a versão original deveriam ser mantidos: %s", out)
+	}
+}
+
+func TestUnsupportedVersion(t *testing.T) {
+	data := []byte(`{"id":"t-1","description":"analisar","version":9,"min_version":9}`)
+	var task TaskRequest
+	if err := Decode(data, &task); !errors.Is(err, ErrUnsupportedVersion) {
+		t.Fatalf("esperava ErrUnsupportedVersion, veio %v", err)
+	}
+}
+
+func TestUpgradeVersionOne(t *testing.T) {
+	// Subtarefas anteriores ao versionamento podiam vir sem status
+	var task SubTask
+	if err := Decode([]byte(`{"id":"s-1","parent_id":"t-1","type":"analysis"}`), &task); err != nil {
+		t.Fatal(err)
+	}
+	if task.Status != "pending" || task.Extra != nil {
+		t.Fatalf("subtarefa da versão 1 deveria ser atualizada: %+v", task)
+	}
+}
@@ -0,0 +1,94 @@
+// Package messages define os contratos das mensagens trocadas pelas filas (AMQP e Kafka):
+// solicitações de tarefas, subtarefas, resultados, amostras de métricas e as mensagens do
+// fluxo de capítulos. A fonte da verdade são os JSON Schemas em schemas/; os tipos Go e a
+// validação são gerados por cmd/msggen e não devem ser editados à mão. Para mudar uma
+// mensagem, altere o schema e rode go generate ./agents/messages.
+package messages
+
+//go:generate go run ../../cmd/msggen -schemas schemas -out messages_gen.go
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+
+	"github.com/suissa/HiveMind/agents/errs"
+)
+
+//go:embed schemas/*.schema.json
+var embedded embed.FS
+
+// schemas guarda os JSON Schemas embutidos pelo título (o nome do tipo Go)
+var schemas = make(map[string]json.RawMessage)
+
+func init() {
+	entries, err := embedded.ReadDir("schemas")
+	if err != nil {
+		panic(fmt.Sprintf("messages: erro ao ler os schemas embutidos: %v", err))
+	}
+	for _, entry := range entries {
+		data, err := embedded.ReadFile(path.Join("schemas", entry.Name()))
+		if err != nil {
+			panic(fmt.Sprintf("messages: erro ao ler o schema %s: %v", entry.Name(), err))
+		}
+		var header struct {
+			Title string `json:"title"`
+		}
+		if err := json.Unmarshal(data, &header); err != nil {
+			panic(fmt.Sprintf("messages: schema %s inválido: %v", entry.Name(), err))
+		}
+		schemas[header.Title] = data
+	}
+}
+
+// Message é implementada por todos os tipos gerados
+type Message interface {
+	Validate() error
+}
+
+// Schema retorna o JSON Schema da mensagem pelo nome do tipo (ex.: "TaskRequest"), para
+// publicar o contrato a consumidores em outras linguagens
+func Schema(name string) (json.RawMessage, bool) {
+	schema, ok := schemas[name]
+	return schema, ok
+}
+
+// Names retorna os nomes das mensagens com schema, em ordem alfabética
+func Names() []string {
+	names := make([]string, 0, len(schemas))
+	for name := range schemas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Decode desserializa o JSON em msg (um ponteiro para o tipo gerado) e valida o resultado.
+// Mensagens fora do contrato retornam um erro errs.ErrValidation; mensagens que exigem uma
+// versão de contrato mais nova retornam ErrUnsupportedVersion. Mensagens de versões
+// anteriores são atualizadas para a versão atual antes da validação.
+func Decode(data []byte, msg Message) error {
+	if err := json.Unmarshal(data, msg); err != nil {
+		return errs.Wrap(errs.ErrValidation, "messages.Decode", err, "JSON inválido")
+	}
+	if v, ok := msg.(negotiator); ok {
+		if err := v.negotiate(); err != nil {
+			return err
+		}
+	}
+	return msg.Validate()
+}
+
+// Encode valida a mensagem e a serializa em JSON, impedindo a publicação de mensagens fora
+// do contrato. As mensagens com versão de contrato recebem a versão deste processo.
+func Encode(msg Message) ([]byte, error) {
+	if v, ok := msg.(stamper); ok {
+		msg = v.stamped()
+	}
+	if err := msg.Validate(); err != nil {
+		return nil, err
+	}
+	return json.Marshal(msg)
+}
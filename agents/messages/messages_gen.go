@@ -0,0 +1,302 @@
+// Code generated by msggen from schemas/*.schema.json. DO NOT EDIT.
+
+package messages
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/suissa/HiveMind/agents/errs"
+	"github.com/suissa/HiveMind/agents/overrides"
+)
+
+// ApprovalMessage envia um quiz ou desafio gerado para aprovação (chapter.approval.queue)
+type ApprovalMessage struct {
+	Type    string `json:"type"`
+	Prompt  string `json:"prompt"`
+	Content string `json:"content"`
+	Score   int    `json:"score"`
+}
+
+// Validate confere a mensagem contra o schema ApprovalMessage
+func (m ApprovalMessage) Validate() error {
+	if m.Type == "" {
+		return errs.New(errs.ErrValidation, "messages.ApprovalMessage", "campo %q obrigatório", "type")
+	}
+	switch m.Type {
+	case "quiz", "challenge":
+	default:
+		return errs.New(errs.ErrValidation, "messages.ApprovalMessage", "campo %q com valor %q fora de: %s", "type", m.Type, "quiz, challenge")
+	}
+	if m.Prompt == "" {
+		return errs.New(errs.ErrValidation, "messages.ApprovalMessage", "campo %q obrigatório", "prompt")
+	}
+	if m.Content == "" {
+		return errs.New(errs.ErrValidation, "messages.ApprovalMessage", "campo %q obrigatório", "content")
+	}
+	if m.Score < 0 {
+		return errs.New(errs.ErrValidation, "messages.ApprovalMessage", "campo %q abaixo do mínimo 0: %v", "score", m.Score)
+	}
+	return nil
+}
+
+// ChapterCreationMessage pede a criação de um capítulo (chapter.creation.queue)
+type ChapterCreationMessage struct {
+	Tema     string   `json:"tema"`
+	UserInfo UserInfo `json:"user_info"`
+}
+
+// Validate confere a mensagem contra o schema ChapterCreationMessage
+func (m ChapterCreationMessage) Validate() error {
+	if m.Tema == "" {
+		return errs.New(errs.ErrValidation, "messages.ChapterCreationMessage", "campo %q obrigatório", "tema")
+	}
+	if err := m.UserInfo.Validate(); err != nil {
+		return fmt.Errorf("%s: %w", "user_info", err)
+	}
+	return nil
+}
+
+// FinishedMessage anuncia o capítulo concluído (chapter.finished.queue)
+type FinishedMessage struct {
+	Status     string `json:"status"`
+	TotalScore int    `json:"total_score"`
+}
+
+// Validate confere a mensagem contra o schema FinishedMessage
+func (m FinishedMessage) Validate() error {
+	if m.Status == "" {
+		return errs.New(errs.ErrValidation, "messages.FinishedMessage", "campo %q obrigatório", "status")
+	}
+	switch m.Status {
+	case "completed":
+	default:
+		return errs.New(errs.ErrValidation, "messages.FinishedMessage", "campo %q com valor %q fora de: %s", "status", m.Status, "completed")
+	}
+	if m.TotalScore < 0 {
+		return errs.New(errs.ErrValidation, "messages.FinishedMessage", "campo %q abaixo do mínimo 0: %v", "total_score", m.TotalScore)
+	}
+	return nil
+}
+
+// ChapterTaskMessage pede a geração de um quiz ou desafio do capítulo (quiz.creation.queue e challenge.creation.queue)
+type ChapterTaskMessage struct {
+	Task string `json:"task"`
+	Tema string `json:"tema"`
+}
+
+// Validate confere a mensagem contra o schema ChapterTaskMessage
+func (m ChapterTaskMessage) Validate() error {
+	if m.Task == "" {
+		return errs.New(errs.ErrValidation, "messages.ChapterTaskMessage", "campo %q obrigatório", "task")
+	}
+	switch m.Task {
+	case "generate_quiz", "generate_challenge":
+	default:
+		return errs.New(errs.ErrValidation, "messages.ChapterTaskMessage", "campo %q com valor %q fora de: %s", "task", m.Task, "generate_quiz, generate_challenge")
+	}
+	if m.Tema == "" {
+		return errs.New(errs.ErrValidation, "messages.ChapterTaskMessage", "campo %q obrigatório", "tema")
+	}
+	return nil
+}
+
+// MetricSample é uma amostra de métrica de agente, publicada nas filas metrics.<agente>.<métrica>
+type MetricSample struct {
+	Value float64 `json:"value"`
+	// Instante da amostra em segundos Unix
+	Timestamp int64 `json:"timestamp"`
+}
+
+// Validate confere a mensagem contra o schema MetricSample
+func (m MetricSample) Validate() error {
+	if m.Timestamp < 0 {
+		return errs.New(errs.ErrValidation, "messages.MetricSample", "campo %q abaixo do mínimo 0: %v", "timestamp", m.Timestamp)
+	}
+	return nil
+}
+
+// SubTask representa uma subtarefa gerada pela LLM, publicada na fila de tarefas (llm_tasks)
+type SubTask struct {
+	ID          string `json:"id"`
+	ParentID    string `json:"parent_id"`
+	Name        string `json:"name,omitempty"`
+	Description string `json:"description,omitempty"`
+	// Tipo de agente que executa a subtarefa
+	Type       string                 `json:"type"`
+	Parameters map[string]interface{} `json:"parameters,omitempty"`
+	Status     string                 `json:"status"`
+	// Agente vencedor da licitação, com contract net
+	AssignedTo string `json:"assigned_to,omitempty"`
+	// Herdados da tarefa
+	Overrides *overrides.Overrides `json:"overrides,omitempty"`
+	// Chave de afinidade: subtarefas com a mesma chave preferem a mesma instância
+	Affinity string `json:"affinity,omitempty"`
+	// Versão do contrato com que a mensagem foi escrita; ausente nas mensagens anteriores ao versionamento (versão 1)
+	Version int `json:"version,omitempty"`
+	// Menor versão de contrato capaz de processar a mensagem
+	MinVersion int `json:"min_version,omitempty"`
+	// Campos não declarados no schema (de versões mais novas do contrato), devolvidos na serialização
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON guarda em Extra os campos que o schema SubTask não declara
+func (m *SubTask) UnmarshalJSON(data []byte) error {
+	type plain SubTask
+	if err := json.Unmarshal(data, (*plain)(m)); err != nil {
+		return err
+	}
+	extra, err := unknownFields(data, "id", "parent_id", "name", "description", "type", "parameters", "status", "assigned_to", "overrides", "affinity", "version", "min_version")
+	m.Extra = extra
+	return err
+}
+
+// MarshalJSON serializa a mensagem com os campos guardados em Extra
+func (m SubTask) MarshalJSON() ([]byte, error) {
+	type plain SubTask
+	data, err := json.Marshal(plain(m))
+	if err != nil || len(m.Extra) == 0 {
+		return data, err
+	}
+	return withFields(data, m.Extra)
+}
+
+// Validate confere a mensagem contra o schema SubTask
+func (m SubTask) Validate() error {
+	if m.ID == "" {
+		return errs.New(errs.ErrValidation, "messages.SubTask", "campo %q obrigatório", "id")
+	}
+	if m.ParentID == "" {
+		return errs.New(errs.ErrValidation, "messages.SubTask", "campo %q obrigatório", "parent_id")
+	}
+	if m.Type == "" {
+		return errs.New(errs.ErrValidation, "messages.SubTask", "campo %q obrigatório", "type")
+	}
+	if m.Status == "" {
+		return errs.New(errs.ErrValidation, "messages.SubTask", "campo %q obrigatório", "status")
+	}
+	switch m.Status {
+	case "pending", "awarded", "completed", "failed":
+	default:
+		return errs.New(errs.ErrValidation, "messages.SubTask", "campo %q com valor %q fora de: %s", "status", m.Status, "pending, awarded, completed, failed")
+	}
+	if m.Version < 0 {
+		return errs.New(errs.ErrValidation, "messages.SubTask", "campo %q abaixo do mínimo 0: %v", "version", m.Version)
+	}
+	if m.MinVersion < 0 {
+		return errs.New(errs.ErrValidation, "messages.SubTask", "campo %q abaixo do mínimo 0: %v", "min_version", m.MinVersion)
+	}
+	return nil
+}
+
+// TaskRequest representa uma solicitação de tarefa, publicada na fila de entrada do roteador (llm_input)
+type TaskRequest struct {
+	ID          string                 `json:"id"`
+	Tenant      string                 `json:"tenant,omitempty"`
+	Description string                 `json:"description"`
+	Parameters  map[string]interface{} `json:"parameters,omitempty"`
+	// Modelo, temperatura, tokens e ferramentas da tarefa
+	Overrides *overrides.Overrides `json:"overrides,omitempty"`
+	// Desliga a afinidade das subtarefas, que então vão para o melhor agente de cada uma
+	NoAffinity bool `json:"no_affinity,omitempty"`
+	// Versão do contrato com que a mensagem foi escrita; ausente nas mensagens anteriores ao versionamento (versão 1)
+	Version int `json:"version,omitempty"`
+	// Menor versão de contrato capaz de processar a mensagem
+	MinVersion int `json:"min_version,omitempty"`
+	// Campos não declarados no schema (de versões mais novas do contrato), devolvidos na serialização
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON guarda em Extra os campos que o schema TaskRequest não declara
+func (m *TaskRequest) UnmarshalJSON(data []byte) error {
+	type plain TaskRequest
+	if err := json.Unmarshal(data, (*plain)(m)); err != nil {
+		return err
+	}
+	extra, err := unknownFields(data, "id", "tenant", "description", "parameters", "overrides", "no_affinity", "version", "min_version")
+	m.Extra = extra
+	return err
+}
+
+// MarshalJSON serializa a mensagem com os campos guardados em Extra
+func (m TaskRequest) MarshalJSON() ([]byte, error) {
+	type plain TaskRequest
+	data, err := json.Marshal(plain(m))
+	if err != nil || len(m.Extra) == 0 {
+		return data, err
+	}
+	return withFields(data, m.Extra)
+}
+
+// Validate confere a mensagem contra o schema TaskRequest
+func (m TaskRequest) Validate() error {
+	if m.ID == "" {
+		return errs.New(errs.ErrValidation, "messages.TaskRequest", "campo %q obrigatório", "id")
+	}
+	if m.Description == "" {
+		return errs.New(errs.ErrValidation, "messages.TaskRequest", "campo %q obrigatório", "description")
+	}
+	if m.Version < 0 {
+		return errs.New(errs.ErrValidation, "messages.TaskRequest", "campo %q abaixo do mínimo 0: %v", "version", m.Version)
+	}
+	if m.MinVersion < 0 {
+		return errs.New(errs.ErrValidation, "messages.TaskRequest", "campo %q abaixo do mínimo 0: %v", "min_version", m.MinVersion)
+	}
+	return nil
+}
+
+// TaskResult representa o resultado do processamento de uma subtarefa, publicado na fila de resultados (llm_results)
+type TaskResult struct {
+	TaskID      string                 `json:"task_id"`
+	ParentID    string                 `json:"parent_id,omitempty"`
+	AgentID     string                 `json:"agent_id"`
+	Status      string                 `json:"status"`
+	Result      map[string]interface{} `json:"result,omitempty"`
+	CompletedAt string                 `json:"completed_at,omitempty"`
+}
+
+// Validate confere a mensagem contra o schema TaskResult
+func (m TaskResult) Validate() error {
+	if m.TaskID == "" {
+		return errs.New(errs.ErrValidation, "messages.TaskResult", "campo %q obrigatório", "task_id")
+	}
+	if m.AgentID == "" {
+		return errs.New(errs.ErrValidation, "messages.TaskResult", "campo %q obrigatório", "agent_id")
+	}
+	if m.Status == "" {
+		return errs.New(errs.ErrValidation, "messages.TaskResult", "campo %q obrigatório", "status")
+	}
+	switch m.Status {
+	case "completed", "failed":
+	default:
+		return errs.New(errs.ErrValidation, "messages.TaskResult", "campo %q com valor %q fora de: %s", "status", m.Status, "completed, failed")
+	}
+	if m.CompletedAt != "" {
+		if _, err := time.Parse(time.RFC3339, m.CompletedAt); err != nil {
+			return errs.New(errs.ErrValidation, "messages.TaskResult", "campo %q não é uma data RFC 3339: %q", "completed_at", m.CompletedAt)
+		}
+	}
+	return nil
+}
+
+// UserInfo descreve o leitor para quem o capítulo é criado
+type UserInfo struct {
+	Level      string `json:"level"`
+	Profession string `json:"profession"`
+	Age        int    `json:"age"`
+}
+
+// Validate confere a mensagem contra o schema UserInfo
+func (m UserInfo) Validate() error {
+	if m.Level == "" {
+		return errs.New(errs.ErrValidation, "messages.UserInfo", "campo %q obrigatório", "level")
+	}
+	if m.Profession == "" {
+		return errs.New(errs.ErrValidation, "messages.UserInfo", "campo %q obrigatório", "profession")
+	}
+	if m.Age < 0 {
+		return errs.New(errs.ErrValidation, "messages.UserInfo", "campo %q abaixo do mínimo 0: %v", "age", m.Age)
+	}
+	return nil
+}
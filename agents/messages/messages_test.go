@@ -0,0 +1,75 @@
+package messages
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/suissa/HiveMind/agents/errs"
+	"github.com/suissa/HiveMind/agents/messages/codegen"
+)
+
+// Os tipos gerados precisam acompanhar os schemas: rode go generate ./agents/messages
+func TestGeneratedUpToDate(t *testing.T) {
+	want, err := codegen.Generate(os.DirFS("schemas"), "messages")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := os.ReadFile("messages_gen.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Fatal("messages_gen.go está desatualizado em relação aos schemas: rode go generate ./agents/messages")
+	}
+}
+
+func TestDecodeValidates(t *testing.T) {
+	var task TaskRequest
+	if err := Decode([]byte(`{"id":"t-1","description":"analisar","no_affinity":true}`), &task); err != nil {
+		t.Fatal(err)
+	}
+	if task.ID != "t-1" || !task.NoAffinity {
+		t.Fatalf("tarefa decodificada inesperada: %+v", task)
+	}
+
+	cases := map[string]struct {
+		data string
+		msg  Message
+	}{
+		"obrigatório ausente": {`{"id":"t-1"}`, &TaskRequest{}},
+		"enum":                {`{"id":"s-1","parent_id":"t-1","type":"analysis","status":"lost"}`, &SubTask{}},
+		"data":                {`{"task_id":"s-1","agent_id":"a","status":"completed","completed_at":"ontem"}`, &TaskResult{}},
+		"mínimo":              {`{"type":"quiz","prompt":"p","content":"c","score":-1}`, &ApprovalMessage{}},
+		"objeto aninhado":     {`{"tema":"História","user_info":{"level":"Iniciante"}}`, &ChapterCreationMessage{}},
+		"JSON inválido":       {`{"id":`, &TaskRequest{}},
+	}
+	for name, c := range cases {
+		if err := Decode([]byte(c.data), c.msg); !errors.Is(err, errs.ErrValidation) {
+			t.Errorf("%s: esperava ErrValidation, veio %v", name, err)
+		}
+	}
+}
+
+func TestEncodeRejectsInvalid(t *testing.T) {
+	if _, err := Encode(SubTask{ID: "s-1", ParentID: "t-1", Type: "analysis"}); !errors.Is(err, errs.ErrValidation) {
+		t.Fatalf("subtarefa sem status deveria ser rejeitada, veio %v", err)
+	}
+	data, err := Encode(SubTask{ID: "s-1", ParentID: "t-1", Type: "analysis", Status: "pending"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"id":"s-1","parent_id":"t-1","type":"analysis","status":"pending","version":2,"min_version":1}`; string(data) != want {
+		t.Fatalf("JSON inesperado: %s", data)
+	}
+}
+
+func TestSchemas(t *testing.T) {
+	if len(Names()) != 9 {
+		t.Fatalf("schemas embutidos: %v", Names())
+	}
+	if schema, ok := Schema("SubTask"); !ok || !bytes.Contains(schema, []byte(`"parent_id"`)) {
+		t.Fatalf("schema de SubTask inesperado: %s", schema)
+	}
+}
@@ -0,0 +1,230 @@
+// Package metrics mantém contadores e histogramas em memória e os expõe no formato de
+// texto do Prometheus (endpoint /metrics), sem dependências externas.
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultBuckets são os limites padrão dos histogramas de latência, em segundos
+var DefaultBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// Default é o registro exposto pela API em /metrics
+var Default = NewRegistry()
+
+// Registry guarda as famílias de métricas
+type Registry struct {
+	mu       sync.Mutex
+	families map[string]*family
+	order    []string
+}
+
+// family é uma métrica com todas as suas combinações de rótulos
+type family struct {
+	name    string
+	help    string
+	kind    string // counter ou histogram
+	labels  []string
+	buckets []float64
+	series  map[string]*series
+}
+
+// series é uma combinação de valores de rótulos
+type series struct {
+	values []string
+	value  float64  // Contadores
+	counts []uint64 // Histogramas: contagem por limite (não acumulada)
+	sum    float64
+	count  uint64
+}
+
+// NewRegistry cria um registro vazio
+func NewRegistry() *Registry {
+	return &Registry{families: make(map[string]*family)}
+}
+
+// register cria a família ou retorna a existente com o mesmo nome
+func (r *Registry) register(name, help, kind string, buckets []float64, labels []string) *family {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if f, ok := r.families[name]; ok {
+		return f
+	}
+	f := &family{
+		name:    name,
+		help:    help,
+		kind:    kind,
+		labels:  labels,
+		buckets: buckets,
+		series:  make(map[string]*series),
+	}
+	r.families[name] = f
+	r.order = append(r.order, name)
+	return f
+}
+
+// get retorna a série dos valores de rótulos, criando-a se necessário. Deve ser chamado
+// com o registro bloqueado.
+func (f *family) get(values []string) *series {
+	if len(values) != len(f.labels) {
+		// Um rótulo a mais ou a menos é erro de programação; os valores são ajustados
+		// para não perder a observação
+		log.Printf("⚠️ Métrica %s: esperava %d rótulos, recebeu %d", f.name, len(f.labels), len(values))
+		adjusted := make([]string, len(f.labels))
+		copy(adjusted, values)
+		values = adjusted
+	}
+	key := strings.Join(values, "\xff")
+	s, ok := f.series[key]
+	if !ok {
+		s = &series{values: append([]string(nil), values...)}
+		if f.kind == "histogram" {
+			s.counts = make([]uint64, len(f.buckets))
+		}
+		f.series[key] = s
+	}
+	return s
+}
+
+// Counter é um contador monotônico com rótulos
+type Counter struct {
+	r *Registry
+	f *family
+}
+
+// Counter registra (ou retorna) um contador
+func (r *Registry) Counter(name, help string, labels ...string) *Counter {
+	return &Counter{r: r, f: r.register(name, help, "counter", nil, labels)}
+}
+
+// Add soma o valor (não negativo) à série dos rótulos informados
+func (c *Counter) Add(value float64, labelValues ...string) {
+	if value < 0 {
+		return
+	}
+	c.r.mu.Lock()
+	c.f.get(labelValues).value += value
+	c.r.mu.Unlock()
+}
+
+// Inc incrementa a série dos rótulos informados
+func (c *Counter) Inc(labelValues ...string) {
+	c.Add(1, labelValues...)
+}
+
+// Histogram distribui observações em faixas
+type Histogram struct {
+	r *Registry
+	f *family
+}
+
+// Histogram registra (ou retorna) um histograma; sem limites usa DefaultBuckets
+func (r *Registry) Histogram(name, help string, buckets []float64, labels ...string) *Histogram {
+	if len(buckets) == 0 {
+		buckets = DefaultBuckets
+	}
+	buckets = append([]float64(nil), buckets...)
+	sort.Float64s(buckets)
+	return &Histogram{r: r, f: r.register(name, help, "histogram", buckets, labels)}
+}
+
+// Observe registra uma observação na série dos rótulos informados
+func (h *Histogram) Observe(value float64, labelValues ...string) {
+	h.r.mu.Lock()
+	defer h.r.mu.Unlock()
+	s := h.f.get(labelValues)
+	if i := sort.SearchFloat64s(h.f.buckets, value); i < len(s.counts) {
+		s.counts[i]++
+	}
+	s.sum += value
+	s.count++
+}
+
+// WriteText grava todas as métricas no formato de texto do Prometheus
+func (r *Registry) WriteText(w io.Writer) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	bw := bufio.NewWriter(w)
+	for _, name := range r.order {
+		f := r.families[name]
+		if len(f.series) == 0 {
+			continue
+		}
+		fmt.Fprintf(bw, "# HELP %s %s\n", f.name, escapeHelp(f.help))
+		fmt.Fprintf(bw, "# TYPE %s %s\n", f.name, f.kind)
+
+		keys := make([]string, 0, len(f.series))
+		for key := range f.series {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			s := f.series[key]
+			if f.kind == "counter" {
+				fmt.Fprintf(bw, "%s%s %s\n", f.name, labelSet(f.labels, s.values, "", ""), formatValue(s.value))
+				continue
+			}
+			var cumulative uint64
+			for i, bound := range f.buckets {
+				cumulative += s.counts[i]
+				fmt.Fprintf(bw, "%s_bucket%s %d\n", f.name, labelSet(f.labels, s.values, "le", formatValue(bound)), cumulative)
+			}
+			fmt.Fprintf(bw, "%s_bucket%s %d\n", f.name, labelSet(f.labels, s.values, "le", "+Inf"), s.count)
+			fmt.Fprintf(bw, "%s_sum%s %s\n", f.name, labelSet(f.labels, s.values, "", ""), formatValue(s.sum))
+			fmt.Fprintf(bw, "%s_count%s %d\n", f.name, labelSet(f.labels, s.values, "", ""), s.count)
+		}
+	}
+	return bw.Flush()
+}
+
+// Handler serve as métricas para o Prometheus
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		if err := r.WriteText(w); err != nil {
+			log.Printf("❌ Erro ao gravar métricas: %v", err)
+		}
+	})
+}
+
+// labelSet formata os rótulos da série, com um rótulo extra opcional (le dos histogramas)
+func labelSet(names, values []string, extraName, extraValue string) string {
+	var pairs []string
+	for i, name := range names {
+		pairs = append(pairs, name+`="`+escapeLabel(values[i])+`"`)
+	}
+	if extraName != "" {
+		pairs = append(pairs, extraName+`="`+extraValue+`"`)
+	}
+	if len(pairs) == 0 {
+		return ""
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+// formatValue formata um valor como o Prometheus espera
+func formatValue(v float64) string {
+	if math.IsInf(v, 1) {
+		return "+Inf"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+var (
+	labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+	helpEscaper  = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+)
+
+func escapeLabel(s string) string { return labelEscaper.Replace(s) }
+
+func escapeHelp(s string) string { return helpEscaper.Replace(s) }
@@ -0,0 +1,51 @@
+package metrics
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCounterText(t *testing.T) {
+	r := NewRegistry()
+	c := r.Counter("hivemind_test_total", "Contador de teste", "backend")
+	c.Inc("redis")
+	c.Add(2, "redis")
+	c.Inc(`mongo"db`)
+
+	var out strings.Builder
+	if err := r.WriteText(&out); err != nil {
+		t.Fatal(err)
+	}
+	want := `# HELP hivemind_test_total Contador de teste
+# TYPE hivemind_test_total counter
+hivemind_test_total{backend="mongo\"db"} 1
+hivemind_test_total{backend="redis"} 3
+`
+	if out.String() != want {
+		t.Fatalf("saída inesperada:\n%s", out.String())
+	}
+}
+
+func TestHistogramBuckets(t *testing.T) {
+	r := NewRegistry()
+	h := r.Histogram("hivemind_latency_seconds", "Latência", []float64{0.1, 1})
+	h.Observe(0.05)
+	h.Observe(0.1)
+	h.Observe(5)
+
+	var out strings.Builder
+	if err := r.WriteText(&out); err != nil {
+		t.Fatal(err)
+	}
+	for _, line := range []string{
+		`hivemind_latency_seconds_bucket{le="0.1"} 2`,
+		`hivemind_latency_seconds_bucket{le="1"} 2`,
+		`hivemind_latency_seconds_bucket{le="+Inf"} 3`,
+		`hivemind_latency_seconds_sum 5.15`,
+		`hivemind_latency_seconds_count 3`,
+	} {
+		if !strings.Contains(out.String(), line+"\n") {
+			t.Fatalf("linha ausente %q em:\n%s", line, out.String())
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package moderation
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Keywords é um classificador local que sinaliza termos ou expressões regulares por categoria
+type Keywords struct {
+	patterns map[string]*regexp.Regexp
+}
+
+// NewKeywords cria o classificador a partir das listas de termos por categoria. Os termos
+// são comparados como palavras inteiras, sem diferenciar maiúsculas; termos entre barras
+// (ex.: "/\\d{3}-\\d{4}/") são tratados como expressões regulares.
+func NewKeywords(categories map[string][]string) (*Keywords, error) {
+	k := &Keywords{patterns: make(map[string]*regexp.Regexp, len(categories))}
+	for category, terms := range categories {
+		if len(terms) == 0 {
+			continue
+		}
+
+		alternatives := make([]string, 0, len(terms))
+		for _, term := range terms {
+			if len(term) > 2 && strings.HasPrefix(term, "/") && strings.HasSuffix(term, "/") {
+				alternatives = append(alternatives, "(?:"+term[1:len(term)-1]+")")
+				continue
+			}
+			alternatives = append(alternatives, `\b`+regexp.QuoteMeta(term)+`\b`)
+		}
+
+		pattern, err := regexp.Compile(`(?i)` + strings.Join(alternatives, "|"))
+		if err != nil {
+			return nil, fmt.Errorf("termos inválidos na categoria %s: %v", category, err)
+		}
+		k.patterns[category] = pattern
+	}
+	return k, nil
+}
+
+// Moderate implementa Moderator
+func (k *Keywords) Moderate(ctx context.Context, text string) (Result, error) {
+	var result Result
+	for category, pattern := range k.patterns {
+		matches := pattern.FindAllString(text, -1)
+		if len(matches) == 0 {
+			continue
+		}
+		result.Flagged = true
+		result.Categories = append(result.Categories, category)
+		result.Matches = append(result.Matches, matches...)
+	}
+	sort.Strings(result.Categories)
+	return result, nil
+}
@@ -0,0 +1,190 @@
+// Package moderation verifica as entradas das tarefas e as saídas dos agentes com
+// moderadores (APIs de moderação dos provedores ou classificadores locais) e aplica a
+// ação configurada ao conteúdo sinalizado: bloquear, sinalizar ou redigir.
+package moderation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/suissa/HiveMind/agents/errs"
+)
+
+// Removed substitui o conteúdo redigido quando o moderador não indica os trechos sinalizados
+const Removed = "[conteúdo removido pela moderação]"
+
+// ErrBlocked indica que o conteúdo foi bloqueado pela moderação
+var ErrBlocked = errors.New("conteúdo bloqueado pela moderação")
+
+// Stage identifica o ponto verificado
+type Stage string
+
+const (
+	Input  Stage = "input"  // Entrada da tarefa
+	Output Stage = "output" // Saída do agente
+)
+
+// Action é a ação aplicada ao conteúdo sinalizado
+type Action string
+
+const (
+	Flag   Action = "flag"   // Mantém o conteúdo e registra o evento
+	Redact Action = "redact" // Remove os trechos sinalizados
+	Block  Action = "block"  // Interrompe a tarefa com ErrBlocked
+)
+
+// severity ordena as ações da mais branda à mais restritiva
+var severity = map[Action]int{Flag: 1, Redact: 2, Block: 3}
+
+// Result é a classificação de um texto por um moderador
+type Result struct {
+	Flagged    bool               `json:"flagged"`
+	Categories []string           `json:"categories,omitempty"` // Categorias sinalizadas
+	Scores     map[string]float64 `json:"scores,omitempty"`     // Pontuação por categoria, se disponível
+	Matches    []string           `json:"matches,omitempty"`    // Trechos sinalizados, usados na redação
+}
+
+// Moderator classifica um texto
+type Moderator interface {
+	Moderate(ctx context.Context, text string) (Result, error)
+}
+
+// ModeratorFunc adapta uma função para a interface Moderator
+type ModeratorFunc func(ctx context.Context, text string) (Result, error)
+
+// Moderate implementa Moderator
+func (f ModeratorFunc) Moderate(ctx context.Context, text string) (Result, error) {
+	return f(ctx, text)
+}
+
+// Config define as ações aplicadas ao conteúdo sinalizado
+type Config struct {
+	Input      Action            `yaml:"input"`      // Ação nas entradas (padrão flag)
+	Output     Action            `yaml:"output"`     // Ação nas saídas (padrão flag)
+	Categories map[string]Action `yaml:"categories"` // Ação por categoria, substitui a da etapa
+}
+
+// action retorna a ação mais restritiva entre as categorias sinalizadas
+func (c Config) action(stage Stage, categories []string) Action {
+	action := c.Input
+	if stage == Output {
+		action = c.Output
+	}
+	if action == "" {
+		action = Flag
+	}
+
+	var override Action
+	for _, category := range categories {
+		if a, ok := c.Categories[category]; ok && severity[a] > severity[override] {
+			override = a
+		}
+	}
+	if override != "" {
+		return override
+	}
+	return action
+}
+
+// Decision é o resultado da moderação de um texto
+type Decision struct {
+	Stage  Stage  `json:"stage"`
+	Action Action `json:"action,omitempty"` // Vazia se o conteúdo não foi sinalizado
+	Result Result `json:"result"`
+	Text   string `json:"-"` // Texto após a ação (redigido quando Action é Redact)
+}
+
+// Flagged indica se o conteúdo foi sinalizado por algum moderador
+func (d Decision) Flagged() bool {
+	return d.Result.Flagged
+}
+
+// Pipeline executa os moderadores e aplica as ações configuradas
+type Pipeline struct {
+	config     Config
+	moderators []Moderator
+}
+
+// New cria um pipeline com os moderadores informados, executados em ordem
+func New(config Config, moderators ...Moderator) *Pipeline {
+	return &Pipeline{config: config, moderators: moderators}
+}
+
+// Check modera o texto na etapa informada. O conteúdo é sinalizado se qualquer moderador
+// o sinalizar; falhas dos moderadores interrompem a verificação. Conteúdo bloqueado
+// retorna a decisão e um erro ErrBlocked (classificado como errs.ErrValidation).
+func (p *Pipeline) Check(ctx context.Context, stage Stage, text string) (Decision, error) {
+	decision := Decision{Stage: stage, Text: text}
+	if text == "" {
+		return decision, nil
+	}
+
+	for _, moderator := range p.moderators {
+		result, err := moderator.Moderate(ctx, text)
+		if err != nil {
+			return decision, fmt.Errorf("erro na moderação (%s): %w", stage, err)
+		}
+		decision.Result = merge(decision.Result, result)
+	}
+	if !decision.Result.Flagged {
+		return decision, nil
+	}
+
+	decision.Action = p.config.action(stage, decision.Result.Categories)
+	switch decision.Action {
+	case Redact:
+		decision.Text = redact(text, decision.Result.Matches)
+	case Block:
+		decision.Text = ""
+		return decision, errs.Wrap(errs.ErrValidation, "moderation.Check", ErrBlocked,
+			"%s bloqueada (%s)", stage, strings.Join(decision.Result.Categories, ", "))
+	}
+	return decision, nil
+}
+
+// merge combina os resultados de dois moderadores
+func merge(a, b Result) Result {
+	a.Flagged = a.Flagged || b.Flagged
+	for _, category := range b.Categories {
+		if !contains(a.Categories, category) {
+			a.Categories = append(a.Categories, category)
+		}
+	}
+	for category, score := range b.Scores {
+		if a.Scores == nil {
+			a.Scores = make(map[string]float64)
+		}
+		if score > a.Scores[category] {
+			a.Scores[category] = score
+		}
+	}
+	a.Matches = append(a.Matches, b.Matches...)
+	sort.Strings(a.Categories)
+	return a
+}
+
+// redact remove os trechos sinalizados; sem trechos o texto inteiro é removido
+func redact(text string, matches []string) string {
+	if len(matches) == 0 {
+		return Removed
+	}
+	for _, match := range matches {
+		pattern := regexp.MustCompile(`(?i)` + regexp.QuoteMeta(match))
+		text = pattern.ReplaceAllLiteralString(text, "[removido]")
+	}
+	return text
+}
+
+// contains verifica se o valor está na lista
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
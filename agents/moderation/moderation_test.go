@@ -0,0 +1,75 @@
+package moderation
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/suissa/HiveMind/agents/errs"
+)
+
+func TestPipelineActions(t *testing.T) {
+	keywords, err := NewKeywords(map[string][]string{
+		"violence": {"ataque"},
+		"phone":    {`/\d{4}-\d{4}/`},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	pipeline := New(Config{
+		Input:      Flag,
+		Output:     Redact,
+		Categories: map[string]Action{"violence": Block},
+	}, keywords)
+	ctx := context.Background()
+
+	decision, err := pipeline.Check(ctx, Input, "ligue para 5555-1234")
+	if err != nil || decision.Action != Flag || decision.Text != "ligue para 5555-1234" {
+		t.Fatalf("entrada deveria ser apenas sinalizada: %#v (%v)", decision, err)
+	}
+
+	decision, err = pipeline.Check(ctx, Output, "ligue para 5555-1234")
+	if err != nil || decision.Action != Redact || decision.Text != "ligue para [removido]" {
+		t.Fatalf("saída deveria ser redigida: %#v (%v)", decision, err)
+	}
+
+	_, err = pipeline.Check(ctx, Output, "planeje o ATAQUE")
+	if !errors.Is(err, ErrBlocked) || !errors.Is(err, errs.ErrValidation) {
+		t.Fatalf("categoria bloqueada deveria interromper: %v", err)
+	}
+
+	if decision, _ := pipeline.Check(ctx, Input, "texto comum"); decision.Flagged() || decision.Action != "" {
+		t.Fatalf("texto comum não deve ser sinalizado: %#v", decision)
+	}
+}
+
+func TestOpenAIModerator(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/moderations" || r.Header.Get("Authorization") != "Bearer key" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"results": []map[string]interface{}{{
+				"flagged":         true,
+				"categories":      map[string]bool{"harassment": true, "violence": false},
+				"category_scores": map[string]float64{"harassment": 0.91, "violence": 0.02},
+			}},
+		})
+	}))
+	defer server.Close()
+
+	moderator := NewOpenAIModerator("key")
+	moderator.BaseURL = server.URL
+
+	decision, err := New(Config{Input: Redact}, moderator).Check(context.Background(), Input, "texto ofensivo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(decision.Result.Categories) != 1 || decision.Result.Categories[0] != "harassment" || decision.Text != Removed {
+		t.Fatalf("decisão inesperada: %#v", decision)
+	}
+}
@@ -0,0 +1,95 @@
+package moderation
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/suissa/HiveMind/agents/errs"
+)
+
+// DefaultOpenAIURL é a URL base da API da OpenAI
+const DefaultOpenAIURL = "https://api.openai.com/v1"
+
+// OpenAIModerator implementa Moderator com a API de moderação da OpenAI
+type OpenAIModerator struct {
+	BaseURL string
+	APIKey  string
+	Model   string // Vazio usa o modelo padrão da API
+	client  *http.Client
+}
+
+// NewOpenAIModerator cria um moderador para a API de moderação da OpenAI
+func NewOpenAIModerator(apiKey string) *OpenAIModerator {
+	return &OpenAIModerator{
+		BaseURL: DefaultOpenAIURL,
+		APIKey:  apiKey,
+		client:  &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// Moderate classifica o texto (POST /moderations)
+func (m *OpenAIModerator) Moderate(ctx context.Context, text string) (Result, error) {
+	body, err := json.Marshal(map[string]string{"input": text, "model": m.Model})
+	if err != nil {
+		return Result{}, fmt.Errorf("erro ao serializar requisição de moderação: %v", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimRight(m.BaseURL, "/")+"/moderations", bytes.NewReader(body))
+	if err != nil {
+		return Result{}, fmt.Errorf("erro ao criar requisição à OpenAI: %v", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+m.APIKey)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return Result{}, errs.FromContext("moderation.openai", fmt.Errorf("erro ao chamar a OpenAI: %w", err))
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		if kind := errs.KindForStatus(resp.StatusCode); kind != nil {
+			return Result{}, errs.New(kind, "moderation.openai", "status %d: %s", resp.StatusCode, strings.TrimSpace(string(message)))
+		}
+		return Result{}, fmt.Errorf("moderation.openai: status %d: %s", resp.StatusCode, strings.TrimSpace(string(message)))
+	}
+
+	var payload struct {
+		Results []struct {
+			Flagged        bool               `json:"flagged"`
+			Categories     map[string]bool    `json:"categories"`
+			CategoryScores map[string]float64 `json:"category_scores"`
+		} `json:"results"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return Result{}, fmt.Errorf("erro ao decodificar resposta da OpenAI: %v", err)
+	}
+
+	var result Result
+	for _, r := range payload.Results {
+		result.Flagged = result.Flagged || r.Flagged
+		for category, flagged := range r.Categories {
+			if flagged && !contains(result.Categories, category) {
+				result.Categories = append(result.Categories, category)
+			}
+		}
+		for category, score := range r.CategoryScores {
+			if result.Scores == nil {
+				result.Scores = make(map[string]float64)
+			}
+			if score > result.Scores[category] {
+				result.Scores[category] = score
+			}
+		}
+	}
+	sort.Strings(result.Categories)
+	return result, nil
+}
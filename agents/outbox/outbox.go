@@ -0,0 +1,290 @@
+// Package outbox garante a publicação dos eventos ligados às gravações de estado dos agentes
+// (transactional outbox): o evento é gravado na mesma transação da memória e um Relay o
+// publica depois do commit, repetindo até conseguir. A entrega é pelo menos uma vez; os
+// consumidores descartam duplicatas pelo header x-event-id.
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/suissa/HiveMind/agents/tenant"
+)
+
+// HeaderEventID é o header com o ID do evento, enviado aos publicadores com headers
+const HeaderEventID = "x-event-id"
+
+// Padrões do relay
+const (
+	DefaultInterval   = time.Second
+	DefaultBatchSize  = 100
+	DefaultLease      = 30 * time.Second
+	DefaultMaxBackoff = 5 * time.Minute
+)
+
+// Event é um evento aguardando publicação
+type Event struct {
+	ID          string            `json:"id" bson:"_id"`
+	Subject     string            `json:"subject" bson:"subject"`
+	Payload     []byte            `json:"payload" bson:"payload"`
+	Headers     map[string]string `json:"headers,omitempty" bson:"headers,omitempty"`
+	TenantID    string            `json:"tenant_id,omitempty" bson:"tenant_id,omitempty"`
+	CreatedAt   time.Time         `json:"created_at" bson:"created_at"`
+	AvailableAt time.Time         `json:"available_at" bson:"available_at"` // Próxima tentativa de publicação
+	Attempts    int               `json:"attempts" bson:"attempts"`         // Reservas feitas pelos relays
+	LastError   string            `json:"last_error,omitempty" bson:"last_error,omitempty"`
+}
+
+// NewEvent cria um evento para o tópico
+func NewEvent(subject string, payload []byte) *Event {
+	return &Event{ID: uuid.NewString(), Subject: subject, Payload: payload}
+}
+
+// NewJSONEvent cria um evento com o valor serializado em JSON
+func NewJSONEvent(subject string, v interface{}) (*Event, error) {
+	payload, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao serializar evento para %s: %v", subject, err)
+	}
+	return NewEvent(subject, payload), nil
+}
+
+// Prepare completa o ID, o tenant e as datas do evento antes da gravação
+func (e *Event) Prepare(ctx context.Context, now time.Time) {
+	if e.ID == "" {
+		e.ID = uuid.NewString()
+	}
+	if e.TenantID == "" {
+		e.TenantID = tenant.FromContext(ctx)
+	}
+	if e.CreatedAt.IsZero() {
+		e.CreatedAt = now
+	}
+	if e.AvailableAt.IsZero() {
+		e.AvailableAt = e.CreatedAt
+	}
+}
+
+// Store guarda os eventos pendentes. As implementações gravam os eventos junto com o estado,
+// na mesma transação (ver memory.EventStore).
+type Store interface {
+	// Claim reserva até limit eventos disponíveis pelo prazo lease, para que outro relay
+	// não os publique ao mesmo tempo
+	Claim(ctx context.Context, limit int, lease time.Duration) ([]*Event, error)
+	// Ack remove o evento publicado
+	Ack(ctx context.Context, id string) error
+	// Retry registra a falha na publicação e agenda a próxima tentativa
+	Retry(ctx context.Context, id string, cause error, at time.Time) error
+}
+
+// Publisher publica os eventos; communication.CommunicationClient o implementa
+type Publisher interface {
+	Publish(ctx context.Context, subject string, data []byte) error
+}
+
+// HeaderPublisher publica com headers, levando o ID e os headers do evento
+type HeaderPublisher interface {
+	PublishHeaders(ctx context.Context, subject string, data []byte, headers map[string]string) error
+}
+
+// Config configura o relay
+type Config struct {
+	Interval   time.Duration `json:"interval" yaml:"interval"`       // Intervalo entre as varreduras (padrão DefaultInterval)
+	BatchSize  int           `json:"batch_size" yaml:"batch_size"`   // Eventos reservados por vez (padrão DefaultBatchSize)
+	Lease      time.Duration `json:"lease" yaml:"lease"`             // Prazo da reserva (padrão DefaultLease)
+	MaxBackoff time.Duration `json:"max_backoff" yaml:"max_backoff"` // Espera máxima entre tentativas (padrão DefaultMaxBackoff)
+}
+
+// Observer recebe o resultado de cada tentativa de publicação (err nulo em caso de sucesso)
+type Observer func(ctx context.Context, event *Event, err error)
+
+// Relay publica os eventos pendentes de um Store
+type Relay struct {
+	store     Store
+	publisher Publisher
+	config    Config
+	observer  Observer
+	wake      chan struct{}
+	mu        sync.Mutex // Serializa as varreduras
+}
+
+// NewRelay cria um relay com a configuração informada, completando os padrões
+func NewRelay(store Store, publisher Publisher, config Config) *Relay {
+	if config.Interval <= 0 {
+		config.Interval = DefaultInterval
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = DefaultBatchSize
+	}
+	if config.Lease <= 0 {
+		config.Lease = DefaultLease
+	}
+	if config.MaxBackoff <= 0 {
+		config.MaxBackoff = DefaultMaxBackoff
+	}
+	return &Relay{store: store, publisher: publisher, config: config, wake: make(chan struct{}, 1)}
+}
+
+// OnPublish registra o observador das tentativas de publicação
+func (r *Relay) OnPublish(observer Observer) {
+	r.observer = observer
+}
+
+// Notify antecipa a próxima varredura, para publicar logo após um commit
+func (r *Relay) Notify() {
+	select {
+	case r.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Run varre o Store a cada intervalo (ou a cada Notify) até o contexto ser cancelado
+func (r *Relay) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.config.Interval)
+	defer ticker.Stop()
+	for {
+		r.Flush(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-r.wake:
+		}
+	}
+}
+
+// Flush publica os eventos disponíveis, lote a lote, e retorna quantos foram publicados. As
+// falhas de publicação são reagendadas com espera exponencial; o erro retornado é o do Store.
+func (r *Relay) Flush(ctx context.Context) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	published := 0
+	for ctx.Err() == nil {
+		// Os eventos reservados antes de uma falha do Store também são publicados
+		events, err := r.store.Claim(ctx, r.config.BatchSize, r.config.Lease)
+		failed := 0
+		for _, event := range events {
+			if err := r.publish(ctx, event); err != nil {
+				failed++
+				continue
+			}
+			published++
+		}
+		if err != nil {
+			return published, fmt.Errorf("erro ao reservar eventos: %w", err)
+		}
+		// Lote incompleto ou só com falhas: o restante fica para a próxima varredura
+		if len(events) < r.config.BatchSize || failed == len(events) {
+			break
+		}
+	}
+	return published, nil
+}
+
+// publish publica um evento e o remove do Store, ou agenda a próxima tentativa
+func (r *Relay) publish(ctx context.Context, event *Event) error {
+	pubCtx := ctx
+	if event.TenantID != "" {
+		pubCtx = tenant.WithTenant(ctx, event.TenantID)
+	}
+
+	var err error
+	if hp, ok := r.publisher.(HeaderPublisher); ok {
+		headers := make(map[string]string, len(event.Headers)+1)
+		for key, value := range event.Headers {
+			headers[key] = value
+		}
+		headers[HeaderEventID] = event.ID
+		err = hp.PublishHeaders(pubCtx, event.Subject, event.Payload, headers)
+	} else {
+		err = r.publisher.Publish(pubCtx, event.Subject, event.Payload)
+	}
+	if err == nil {
+		// Uma falha aqui faz o evento ser publicado de novo ao fim da reserva
+		err = r.store.Ack(ctx, event.ID)
+	} else if retryErr := r.store.Retry(ctx, event.ID, err, time.Now().Add(r.backoff(event.Attempts))); retryErr != nil {
+		err = fmt.Errorf("%v (e ao reagendar: %v)", err, retryErr)
+	}
+
+	if r.observer != nil {
+		r.observer(pubCtx, event, err)
+	}
+	return err
+}
+
+// backoff retorna a espera antes da próxima tentativa: o intervalo dobrado a cada tentativa
+// já feita, até MaxBackoff
+func (r *Relay) backoff(attempts int) time.Duration {
+	wait := r.config.Interval
+	for i := 1; i < attempts && wait < r.config.MaxBackoff; i++ {
+		wait *= 2
+	}
+	return min(wait, r.config.MaxBackoff)
+}
+
+// Multi combina os Stores de vários armazenamentos (por exemplo, os da memória de curto e de
+// longo prazo), reservando de cada um na ordem informada
+func Multi(stores ...Store) Store {
+	return &multiStore{stores: stores, owners: make(map[string]Store)}
+}
+
+// multiStore lembra de qual Store veio cada evento reservado
+type multiStore struct {
+	stores []Store
+	owners map[string]Store
+	mu     sync.Mutex
+}
+
+func (m *multiStore) Claim(ctx context.Context, limit int, lease time.Duration) ([]*Event, error) {
+	var claimed []*Event
+	for _, store := range m.stores {
+		if len(claimed) >= limit {
+			break
+		}
+		events, err := store.Claim(ctx, limit-len(claimed), lease)
+		if err != nil {
+			return claimed, err
+		}
+		m.mu.Lock()
+		for _, event := range events {
+			m.owners[event.ID] = store
+		}
+		m.mu.Unlock()
+		claimed = append(claimed, events...)
+	}
+	return claimed, nil
+}
+
+func (m *multiStore) Ack(ctx context.Context, id string) error {
+	store, err := m.owner(id)
+	if err != nil {
+		return err
+	}
+	return store.Ack(ctx, id)
+}
+
+func (m *multiStore) Retry(ctx context.Context, id string, cause error, at time.Time) error {
+	store, err := m.owner(id)
+	if err != nil {
+		return err
+	}
+	return store.Retry(ctx, id, cause, at)
+}
+
+// owner retorna e esquece o Store de um evento reservado
+func (m *multiStore) owner(id string) (Store, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	store, ok := m.owners[id]
+	if !ok {
+		return nil, fmt.Errorf("evento %s não foi reservado", id)
+	}
+	delete(m.owners, id)
+	return store, nil
+}
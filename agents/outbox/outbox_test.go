@@ -0,0 +1,125 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+)
+
+// memoryStore guarda os eventos em memória
+type memoryStore struct {
+	events map[string]*Event
+	mu     sync.Mutex
+}
+
+func (s *memoryStore) Claim(ctx context.Context, limit int, lease time.Duration) ([]*Event, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	var claimed []*Event
+	for _, event := range s.events {
+		if len(claimed) < limit && !event.AvailableAt.After(now) {
+			event.AvailableAt = now.Add(lease)
+			event.Attempts++
+			claimedEvent := *event
+			claimed = append(claimed, &claimedEvent)
+		}
+	}
+	sort.Slice(claimed, func(i, j int) bool { return claimed[i].ID < claimed[j].ID })
+	return claimed, nil
+}
+
+func (s *memoryStore) Ack(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.events, id)
+	return nil
+}
+
+func (s *memoryStore) Retry(ctx context.Context, id string, cause error, at time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.events[id].AvailableAt = at
+	s.events[id].LastError = cause.Error()
+	return nil
+}
+
+func newStore(events ...*Event) *memoryStore {
+	s := &memoryStore{events: make(map[string]*Event)}
+	for _, event := range events {
+		event.Prepare(context.Background(), time.Now().Add(-time.Second))
+		s.events[event.ID] = event
+	}
+	return s
+}
+
+// flakyPublisher falha nas publicações dos tópicos em fail
+type flakyPublisher struct {
+	fail      map[string]bool
+	published []string
+	headers   []map[string]string
+}
+
+func (p *flakyPublisher) Publish(ctx context.Context, subject string, data []byte) error {
+	return p.PublishHeaders(ctx, subject, data, nil)
+}
+
+func (p *flakyPublisher) PublishHeaders(ctx context.Context, subject string, data []byte, headers map[string]string) error {
+	if p.fail[subject] {
+		return errors.New("barramento indisponível")
+	}
+	p.published = append(p.published, subject)
+	p.headers = append(p.headers, headers)
+	return nil
+}
+
+func TestRelayFlush(t *testing.T) {
+	ok, failing := NewEvent("tasks.done", []byte("{}")), NewEvent("tasks.failed", []byte("{}"))
+	store := newStore(ok, failing)
+	publisher := &flakyPublisher{fail: map[string]bool{"tasks.failed": true}}
+	relay := NewRelay(store, publisher, Config{Interval: time.Minute})
+
+	published, err := relay.Flush(context.Background())
+	if err != nil || published != 1 {
+		t.Fatalf("esperava 1 evento publicado, obtidos %d: %v", published, err)
+	}
+	if publisher.headers[0][HeaderEventID] != ok.ID {
+		t.Fatalf("header %s ausente: %v", HeaderEventID, publisher.headers[0])
+	}
+	if _, pending := store.events[ok.ID]; pending {
+		t.Fatal("evento publicado deveria ser removido")
+	}
+	retry := store.events[failing.ID]
+	if retry == nil || retry.LastError == "" || time.Until(retry.AvailableAt) < 30*time.Second {
+		t.Fatalf("evento com falha deveria ser reagendado: %+v", retry)
+	}
+
+	// Ainda não disponível: a próxima varredura não publica nada
+	delete(publisher.fail, "tasks.failed")
+	if published, _ := relay.Flush(context.Background()); published != 0 {
+		t.Fatalf("evento reagendado publicado antes da hora")
+	}
+}
+
+func TestRelayBackoff(t *testing.T) {
+	relay := NewRelay(newStore(), &flakyPublisher{}, Config{Interval: time.Second, MaxBackoff: 5 * time.Second})
+	for attempts, want := range []time.Duration{time.Second, time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second} {
+		if got := relay.backoff(attempts); got != want {
+			t.Fatalf("tentativa %d: esperava %v, obtido %v", attempts, want, got)
+		}
+	}
+}
+
+func TestMulti(t *testing.T) {
+	first, second := newStore(NewEvent("a", nil)), newStore(NewEvent("b", nil), NewEvent("c", nil))
+	publisher := &flakyPublisher{}
+	if published, err := NewRelay(Multi(first, second), publisher, Config{BatchSize: 2}).Flush(context.Background()); err != nil || published != 3 {
+		t.Fatalf("esperava 3 eventos publicados, obtidos %d: %v", published, err)
+	}
+	if len(first.events)+len(second.events) != 0 {
+		t.Fatal("eventos publicados deveriam ser removidos dos Stores de origem")
+	}
+}
@@ -0,0 +1,65 @@
+package agents
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/suissa/HiveMind/agents/errs"
+	"github.com/suissa/HiveMind/agents/metrics"
+	"github.com/suissa/HiveMind/agents/prompt"
+	"github.com/suissa/HiveMind/agents/validation"
+)
+
+// outputRejections conta as saídas de tarefas rejeitadas pela validação, por agente
+var outputRejections = metrics.Default.Counter("hivemind_output_rejections_total",
+	"Saídas de tarefas rejeitadas pela validação do resultado esperado", "agent")
+
+// SetOutputValidation ativa a validação das saídas das tarefas que declaram ExpectedOutput ou
+// OutputSchema: uma saída rejeitada volta ao agente com o motivo até config.MaxRetries vezes,
+// e depois a tarefa falha com ErrValidation em vez de ser concluída
+func (a *CognitiveAgent) SetOutputValidation(config validation.Config) {
+	if config.Validator == nil {
+		config.Validator = validation.Schema()
+	}
+	a.validation = &config
+}
+
+// OutputValidation retorna a configuração da validação das saídas (nil se desativada)
+func (a *CognitiveAgent) OutputValidation() *validation.Config {
+	return a.validation
+}
+
+// validateOutput valida a saída da tarefa e pede novas versões ao LLM enquanto ela for
+// rejeitada. As novas tentativas não usam os caches, que podem conter a saída rejeitada.
+func (a *CognitiveAgent) validateOutput(ctx context.Context, task *Task, p prompt.Prompt, output string) (string, error) {
+	expectation := validation.Expectation{Description: task.ExpectedOutput, Schema: task.OutputSchema}
+	if a.validation == nil || expectation.Empty() {
+		return output, nil
+	}
+
+	retries := a.validation.Retries()
+	for attempt := 0; ; attempt++ {
+		result, err := a.validation.Validator.Validate(ctx, expectation, output)
+		if err != nil {
+			return "", fmt.Errorf("erro ao validar a saída da tarefa %s: %w", task.ID, err)
+		}
+		if result.Valid {
+			return output, nil
+		}
+		outputRejections.Inc(a.GetID())
+		if attempt >= retries {
+			return "", errs.New(errs.ErrValidation, "agents.Run", "saída da tarefa %s rejeitada após %d tentativas: %s", task.ID, attempt+1, result.Reason)
+		}
+		log.Printf("⚠️ Agente %s: saída da tarefa %s rejeitada (%s), nova tentativa", a.GetID(), task.ID, result.Reason)
+
+		// Se a nova tentativa exceder o prazo da tarefa, a saída rejeitada é o resultado parcial
+		task.setPartial(output)
+		retry := p
+		retry.Input = fmt.Sprintf("%s\n\nSua resposta anterior foi rejeitada: %s\nResposta anterior:\n%s\n\nResponda novamente atendendo ao resultado esperado.",
+			p.Input, result.Reason, output)
+		if output, err = a.CompletePrompt(ctx, retry); err != nil {
+			return "", err
+		}
+	}
+}
@@ -0,0 +1,95 @@
+// Package overrides permite que cada tarefa substitua os parâmetros do modelo do agente
+// (modelo, temperatura, máximo de tokens e ferramentas), respeitando os limites configurados.
+package overrides
+
+import (
+	"context"
+
+	"github.com/suissa/HiveMind/agents/errs"
+	"github.com/suissa/HiveMind/agents/llm"
+)
+
+// Overrides são os parâmetros definidos para uma única tarefa; campos vazios mantêm os do agente
+type Overrides struct {
+	Model       string   `json:"model,omitempty" yaml:"model"`
+	Temperature *float64 `json:"temperature,omitempty" yaml:"temperature"`
+	MaxTokens   int      `json:"max_tokens,omitempty" yaml:"max_tokens"`
+	// Tools restringe as ferramentas da tarefa às listadas; nunca libera ferramentas
+	// que o agente não poderia usar. Nil mantém as permissões do agente.
+	Tools []string `json:"tools,omitempty" yaml:"tools"`
+}
+
+// Limits são os limites aplicados aos overrides (seção overrides do agents.yaml)
+type Limits struct {
+	AllowedModels  []string `yaml:"allowed_models"`  // Modelos permitidos (vazio permite qualquer um)
+	MaxTemperature float64  `yaml:"max_temperature"` // Temperatura máxima (0 sem limite)
+	MaxTokens      int      `yaml:"max_tokens"`      // Máximo de tokens por resposta (0 sem limite)
+}
+
+// Apply valida os overrides contra os limites: modelos fora da lista são rejeitados com
+// errs.ErrValidation, e temperatura e tokens acima do limite são reduzidos ao limite
+func (l Limits) Apply(o Overrides) (Overrides, error) {
+	if o.Model != "" && len(l.AllowedModels) > 0 && !contains(l.AllowedModels, o.Model) {
+		return o, errs.New(errs.ErrValidation, "overrides.Apply", "modelo %s não permitido", o.Model)
+	}
+	if o.Temperature != nil {
+		temperature := *o.Temperature
+		if temperature < 0 {
+			return o, errs.New(errs.ErrValidation, "overrides.Apply", "temperatura %.2f inválida", temperature)
+		}
+		if l.MaxTemperature > 0 && temperature > l.MaxTemperature {
+			temperature = l.MaxTemperature
+		}
+		o.Temperature = &temperature
+	}
+	if o.MaxTokens < 0 {
+		return o, errs.New(errs.ErrValidation, "overrides.Apply", "máximo de tokens %d inválido", o.MaxTokens)
+	}
+	if l.MaxTokens > 0 && o.MaxTokens > l.MaxTokens {
+		o.MaxTokens = l.MaxTokens
+	}
+	return o, nil
+}
+
+// Request aplica os overrides à chamada ao LLM
+func (o Overrides) Request(req llm.Request) llm.Request {
+	if o.Model != "" {
+		req.Model = o.Model
+	}
+	if o.Temperature != nil {
+		req.Temperature = *o.Temperature
+	}
+	if o.MaxTokens > 0 {
+		req.MaxTokens = o.MaxTokens
+	}
+	return req
+}
+
+// AllowsTool indica se a ferramenta pode ser usada na tarefa
+func (o Overrides) AllowsTool(tool string) bool {
+	return o.Tools == nil || contains(o.Tools, tool) || contains(o.Tools, "*")
+}
+
+// overridesKey é a chave dos overrides no contexto
+type overridesKey struct{}
+
+// WithContext associa os overrides da tarefa ao contexto
+func WithContext(ctx context.Context, o Overrides) context.Context {
+	return context.WithValue(ctx, overridesKey{}, o)
+}
+
+// FromContext retorna os overrides do contexto (vazios se não houver)
+func FromContext(ctx context.Context) Overrides {
+	o, _ := ctx.Value(overridesKey{}).(Overrides)
+	return o
+}
+
+// contains verifica se o valor está na lista
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
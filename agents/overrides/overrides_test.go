@@ -0,0 +1,48 @@
+package overrides
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/suissa/HiveMind/agents/errs"
+	"github.com/suissa/HiveMind/agents/llm"
+)
+
+func TestLimitsApply(t *testing.T) {
+	limits := Limits{AllowedModels: []string{"gpt-4o-mini"}, MaxTemperature: 1, MaxTokens: 1000}
+
+	temperature := 1.5
+	o, err := limits.Apply(Overrides{Model: "gpt-4o-mini", Temperature: &temperature, MaxTokens: 4000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *o.Temperature != 1 || o.MaxTokens != 1000 {
+		t.Fatalf("limites não aplicados: %.2f / %d", *o.Temperature, o.MaxTokens)
+	}
+	if temperature != 1.5 {
+		t.Fatal("os overrides originais não devem ser alterados")
+	}
+
+	if _, err := limits.Apply(Overrides{Model: "gpt-4"}); !errors.Is(err, errs.ErrValidation) {
+		t.Fatalf("modelo fora da lista deve ser rejeitado: %v", err)
+	}
+}
+
+func TestRequestAndTools(t *testing.T) {
+	temperature := 0.0
+	o := Overrides{Model: "m2", Temperature: &temperature, Tools: []string{"search"}}
+	ctx := WithContext(context.Background(), o)
+
+	req := FromContext(ctx).Request(llm.Request{Model: "m1", Temperature: 0.7, MaxTokens: 512})
+	if req.Model != "m2" || req.Temperature != 0 || req.MaxTokens != 512 {
+		t.Fatalf("requisição inesperada: %#v", req)
+	}
+
+	if !o.AllowsTool("search") || o.AllowsTool("shell") {
+		t.Fatal("apenas as ferramentas listadas devem ser permitidas")
+	}
+	if !FromContext(context.Background()).AllowsTool("shell") {
+		t.Fatal("sem overrides as permissões do agente são mantidas")
+	}
+}
@@ -0,0 +1,17 @@
+package agents
+
+import (
+	"github.com/suissa/HiveMind/agents/persona"
+)
+
+// SetPersona troca a persona do agente: a backstory passa a ser o prompt da persona, inclusive
+// com o agente em execução (vale a partir da próxima chamada ao LLM)
+func (a *CognitiveAgent) SetPersona(p persona.Persona) {
+	a.persona = p.Name
+	a.Backstory = p.Prompt()
+}
+
+// Persona retorna o nome da persona atual do agente ("" se a backstory foi definida diretamente)
+func (a *CognitiveAgent) Persona() string {
+	return a.persona
+}
@@ -0,0 +1,214 @@
+// Package persona implementa uma biblioteca de personas reutilizáveis: traços, tom,
+// especialidades e restrições que compõem o prompt de sistema (backstory) dos agentes. As
+// personas são referenciadas pelo nome no agents.yaml, podem estender outras personas e ser
+// trocadas com o agente em execução.
+package persona
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/suissa/HiveMind/agents/errs"
+)
+
+// Persona descreve o comportamento de um agente
+type Persona struct {
+	Name        string   `yaml:"name" json:"name"`
+	Extends     []string `yaml:"extends,omitempty" json:"extends,omitempty"` // Personas base, aplicadas antes desta
+	Traits      []string `yaml:"traits,omitempty" json:"traits,omitempty"`
+	Tone        string   `yaml:"tone,omitempty" json:"tone,omitempty"`
+	Expertise   []string `yaml:"expertise,omitempty" json:"expertise,omitempty"`
+	Constraints []string `yaml:"constraints,omitempty" json:"constraints,omitempty"`
+	Backstory   string   `yaml:"backstory,omitempty" json:"backstory,omitempty"` // Texto livre, incluído no início do prompt
+}
+
+// Prompt monta o prompt de sistema da persona
+func (p Persona) Prompt() string {
+	var b strings.Builder
+	if p.Backstory != "" {
+		b.WriteString(strings.TrimSpace(p.Backstory))
+		b.WriteString("\n")
+	}
+	if len(p.Traits) > 0 {
+		fmt.Fprintf(&b, "Traços: %s.\n", strings.Join(p.Traits, ", "))
+	}
+	if p.Tone != "" {
+		fmt.Fprintf(&b, "Tom: %s.\n", p.Tone)
+	}
+	if len(p.Expertise) > 0 {
+		fmt.Fprintf(&b, "Especialidades: %s.\n", strings.Join(p.Expertise, ", "))
+	}
+	if len(p.Constraints) > 0 {
+		b.WriteString("Restrições:\n")
+		for _, constraint := range p.Constraints {
+			fmt.Fprintf(&b, "- %s\n", constraint)
+		}
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// Merge aplica other sobre a persona: as listas são somadas sem repetição e o tom e a
+// backstory de other, quando preenchidos, substituem os atuais
+func (p Persona) Merge(other Persona) Persona {
+	merged := Persona{
+		Name:        other.Name,
+		Traits:      union(p.Traits, other.Traits),
+		Tone:        p.Tone,
+		Expertise:   union(p.Expertise, other.Expertise),
+		Constraints: union(p.Constraints, other.Constraints),
+		Backstory:   p.Backstory,
+	}
+	if merged.Name == "" {
+		merged.Name = p.Name
+	}
+	if other.Tone != "" {
+		merged.Tone = other.Tone
+	}
+	if other.Backstory != "" {
+		merged.Backstory = other.Backstory
+	}
+	return merged
+}
+
+// union concatena as listas ignorando os itens repetidos
+func union(a, b []string) []string {
+	if len(a)+len(b) == 0 {
+		return nil
+	}
+	seen := make(map[string]bool, len(a)+len(b))
+	out := make([]string, 0, len(a)+len(b))
+	for _, item := range append(append([]string(nil), a...), b...) {
+		if !seen[item] {
+			seen[item] = true
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+// Library é um catálogo de personas pelo nome, seguro para uso concorrente
+type Library struct {
+	personas map[string]Persona
+	mu       sync.RWMutex
+}
+
+// NewLibrary cria uma biblioteca com as personas informadas
+func NewLibrary(personas ...Persona) (*Library, error) {
+	l := &Library{personas: make(map[string]Persona)}
+	for _, p := range personas {
+		if err := l.Register(p); err != nil {
+			return nil, err
+		}
+	}
+	return l, nil
+}
+
+// LoadLibrary carrega as personas de um arquivo YAML no formato "personas: {nome: {...}}"
+func LoadLibrary(filename string) (*Library, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler arquivo de personas: %v", err)
+	}
+	var config struct {
+		Personas map[string]Persona `yaml:"personas"`
+	}
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("erro ao decodificar arquivo de personas: %v", err)
+	}
+	return FromMap(config.Personas)
+}
+
+// FromMap cria uma biblioteca a partir das personas indexadas pelo nome (a seção personas do
+// agents.yaml); o nome da chave prevalece sobre o campo Name
+func FromMap(personas map[string]Persona) (*Library, error) {
+	l := &Library{personas: make(map[string]Persona)}
+	for name, p := range personas {
+		p.Name = name
+		if err := l.Register(p); err != nil {
+			return nil, err
+		}
+	}
+	return l, nil
+}
+
+// Register adiciona ou substitui uma persona
+func (l *Library) Register(p Persona) error {
+	if p.Name == "" {
+		return errs.New(errs.ErrValidation, "persona.Register", "persona sem nome")
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.personas[p.Name] = p
+	return nil
+}
+
+// Get retorna a persona registrada, sem resolver as personas que ela estende
+func (l *Library) Get(name string) (Persona, bool) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	p, ok := l.personas[name]
+	return p, ok
+}
+
+// Names retorna os nomes das personas em ordem alfabética
+func (l *Library) Names() []string {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	names := make([]string, 0, len(l.personas))
+	for name := range l.personas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Compose resolve as personas pelo nome, com as que cada uma estende, e as combina na ordem
+// informada: as posteriores prevalecem no tom e na backstory. O nome do resultado junta os
+// nomes com "+".
+func (l *Library) Compose(names ...string) (Persona, error) {
+	if len(names) == 0 {
+		return Persona{}, errs.New(errs.ErrValidation, "persona.Compose", "nenhuma persona informada")
+	}
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	var composed Persona
+	for _, name := range names {
+		resolved, err := l.resolve(name, map[string]bool{})
+		if err != nil {
+			return Persona{}, err
+		}
+		composed = composed.Merge(resolved)
+	}
+	composed.Name = strings.Join(names, "+")
+	return composed, nil
+}
+
+// resolve aplica as personas estendidas antes da própria persona, detectando ciclos
+func (l *Library) resolve(name string, visiting map[string]bool) (Persona, error) {
+	p, ok := l.personas[name]
+	if !ok {
+		return Persona{}, errs.New(errs.ErrNotFound, "persona.Compose", "persona %s não encontrada", name)
+	}
+	if visiting[name] {
+		return Persona{}, errs.New(errs.ErrValidation, "persona.Compose", "ciclo de extends na persona %s", name)
+	}
+	visiting[name] = true
+	defer delete(visiting, name)
+
+	var resolved Persona
+	for _, base := range p.Extends {
+		parent, err := l.resolve(base, visiting)
+		if err != nil {
+			return Persona{}, err
+		}
+		resolved = resolved.Merge(parent)
+	}
+	p.Extends = nil
+	return resolved.Merge(p), nil
+}
@@ -0,0 +1,64 @@
+package persona
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/suissa/HiveMind/agents/errs"
+)
+
+func TestComposeResolvesExtendsInOrder(t *testing.T) {
+	library, err := NewLibrary(
+		Persona{Name: "consultor", Traits: []string{"objetivo"}, Tone: "formal", Constraints: []string{"não invente números"}},
+		Persona{Name: "analista", Extends: []string{"consultor"}, Expertise: []string{"mercado"}, Traits: []string{"objetivo", "cético"}},
+		Persona{Name: "criativo", Tone: "leve", Expertise: []string{"copywriting"}, Backstory: "Sou redator."},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := library.Compose("analista", "criativo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Name != "analista+criativo" || p.Tone != "leve" || strings.Join(p.Traits, ",") != "objetivo,cético" {
+		t.Fatalf("composição inesperada: %+v", p)
+	}
+	if strings.Join(p.Expertise, ",") != "mercado,copywriting" || len(p.Constraints) != 1 {
+		t.Fatalf("composição inesperada: %+v", p)
+	}
+	prompt := p.Prompt()
+	if !strings.HasPrefix(prompt, "Sou redator.") || !strings.Contains(prompt, "Tom: leve.") || !strings.Contains(prompt, "- não invente números") {
+		t.Fatalf("prompt inesperado: %s", prompt)
+	}
+
+	if _, err := library.Compose("inexistente"); !errors.Is(err, errs.ErrNotFound) {
+		t.Fatalf("esperava ErrNotFound: %v", err)
+	}
+	library.Register(Persona{Name: "a", Extends: []string{"b"}})
+	library.Register(Persona{Name: "b", Extends: []string{"a"}})
+	if _, err := library.Compose("a"); !errors.Is(err, errs.ErrValidation) {
+		t.Fatalf("esperava erro de ciclo: %v", err)
+	}
+}
+
+func TestLoadLibrary(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "personas.yaml")
+	data := "personas:\n  revisor:\n    traits: [\"minucioso\"]\n    tone: \"neutro\"\n"
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	library, err := LoadLibrary(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p, ok := library.Get("revisor"); !ok || p.Name != "revisor" || p.Tone != "neutro" {
+		t.Fatalf("persona inesperada: %+v", p)
+	}
+	if names := library.Names(); len(names) != 1 || names[0] != "revisor" {
+		t.Fatalf("nomes inesperados: %v", names)
+	}
+}
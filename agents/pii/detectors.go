@@ -0,0 +1,134 @@
+package pii
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/suissa/HiveMind/agents/llm"
+)
+
+// pattern associa um tipo de dado pessoal a uma expressão regular
+type pattern struct {
+	entityType string
+	expr       *regexp.Regexp
+}
+
+// Patterns detecta dados pessoais com expressões regulares
+type Patterns struct {
+	patterns []pattern
+	mu       sync.RWMutex
+}
+
+// NewPatterns cria um detector sem padrões
+func NewPatterns() *Patterns {
+	return &Patterns{}
+}
+
+// DefaultPatterns cria um detector com os padrões de e-mail, CPF, CNPJ, cartão, telefone e IP
+func DefaultPatterns() *Patterns {
+	p := NewPatterns()
+	p.mustAdd("EMAIL", `[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)
+	p.mustAdd("CPF", `\b\d{3}\.\d{3}\.\d{3}-\d{2}\b`)
+	p.mustAdd("CNPJ", `\b\d{2}\.\d{3}\.\d{3}/\d{4}-\d{2}\b`)
+	p.mustAdd("CARD", `\b(?:\d{4}[ -]?){3}\d{4}\b`)
+	p.mustAdd("PHONE", `(?:\+\d{1,3}\s?)?\(?\b\d{2}\)?\s?\d{4,5}-\d{4}\b`)
+	p.mustAdd("IP", `\b(?:\d{1,3}\.){3}\d{1,3}\b`)
+	return p
+}
+
+// mustAdd adiciona um padrão interno, abortando se a expressão for inválida
+func (p *Patterns) mustAdd(entityType, expr string) {
+	if err := p.Add(entityType, expr); err != nil {
+		panic(err)
+	}
+}
+
+// Add adiciona um padrão para o tipo informado (ex.: "RG", "MATRICULA")
+func (p *Patterns) Add(entityType, expr string) error {
+	compiled, err := regexp.Compile(expr)
+	if err != nil {
+		return fmt.Errorf("padrão de dado pessoal inválido %s: %v", entityType, err)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.patterns = append(p.patterns, pattern{entityType: strings.ToUpper(entityType), expr: compiled})
+	return nil
+}
+
+// Detect implementa Detector
+func (p *Patterns) Detect(ctx context.Context, text string) ([]Entity, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	var entities []Entity
+	for _, pt := range p.patterns {
+		for _, loc := range pt.expr.FindAllStringIndex(text, -1) {
+			entities = append(entities, Entity{Type: pt.entityType, Value: text[loc[0]:loc[1]], Start: loc[0], End: loc[1]})
+		}
+	}
+	return entities, nil
+}
+
+// DefaultEntityTypes são os tipos pedidos ao LLM pelo NER
+var DefaultEntityTypes = []string{"PERSON", "LOCATION", "ORGANIZATION"}
+
+// NER reconhece entidades nomeadas (nomes, locais, organizações) com um LLM
+type NER struct {
+	provider llm.Provider
+	Model    string
+	Types    []string // Tipos reconhecidos (padrão DefaultEntityTypes)
+}
+
+// NewNER cria um detector de entidades nomeadas com o provedor e o modelo informados. O texto
+// original é enviado a esse provedor, que deve ser local ou confiável.
+func NewNER(provider llm.Provider, model string) *NER {
+	return &NER{provider: provider, Model: model, Types: DefaultEntityTypes}
+}
+
+// Detect implementa Detector. O LLM devolve as entidades como JSON, e cada ocorrência
+// delas no texto é marcada.
+func (n *NER) Detect(ctx context.Context, text string) ([]Entity, error) {
+	resp, err := n.provider.Complete(ctx, llm.Request{
+		Model: n.Model,
+		System: "Extraia as entidades dos tipos " + strings.Join(n.Types, ", ") + " do texto. " +
+			`Responda apenas com um array JSON no formato [{"type": "PERSON", "value": "..."}].`,
+		Prompt: text,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("erro no reconhecimento de entidades: %w", err)
+	}
+
+	var found []struct {
+		Type  string `json:"type"`
+		Value string `json:"value"`
+	}
+	raw := strings.TrimSpace(resp.Text)
+	if start, end := strings.Index(raw, "["), strings.LastIndex(raw, "]"); start >= 0 && end > start {
+		raw = raw[start : end+1]
+	}
+	if err := json.Unmarshal([]byte(raw), &found); err != nil {
+		return nil, fmt.Errorf("resposta inválida do reconhecimento de entidades: %v", err)
+	}
+
+	var entities []Entity
+	for _, f := range found {
+		if f.Value == "" {
+			continue
+		}
+		for offset := 0; ; {
+			i := strings.Index(text[offset:], f.Value)
+			if i < 0 {
+				break
+			}
+			start := offset + i
+			entities = append(entities, Entity{Type: strings.ToUpper(f.Type), Value: f.Value, Start: start, End: start + len(f.Value)})
+			offset = start + len(f.Value)
+		}
+	}
+	return entities, nil
+}
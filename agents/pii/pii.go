@@ -0,0 +1,171 @@
+// Package pii detecta dados pessoais (expressões regulares e reconhecimento de entidades
+// com LLM) e os pseudonimiza antes que cheguem aos provedores de LLM ou à memória. Os
+// pseudônimos são determinísticos e podem ser revertidos nas saídas finais quando permitido.
+package pii
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/suissa/HiveMind/agents/llm"
+)
+
+// Entity é um dado pessoal encontrado no texto, nas posições [Start, End)
+type Entity struct {
+	Type  string `json:"type"` // Ex.: EMAIL, PHONE, CPF, PERSON
+	Value string `json:"value"`
+	Start int    `json:"start"`
+	End   int    `json:"end"`
+}
+
+// Detector encontra dados pessoais em um texto
+type Detector interface {
+	Detect(ctx context.Context, text string) ([]Entity, error)
+}
+
+// Config define a chave dos pseudônimos e se a reidentificação é permitida
+type Config struct {
+	// Key deriva os pseudônimos (HMAC-SHA256); vazia gera uma chave aleatória, válida
+	// apenas durante a execução
+	Key string `yaml:"key"`
+	// Reidentify permite restaurar os valores originais nas saídas finais
+	Reidentify bool `yaml:"reidentify"`
+}
+
+// tokenPattern reconhece os pseudônimos gerados por Token
+var tokenPattern = regexp.MustCompile(`<[A-Z_]+_[0-9a-f]{10}>`)
+
+// Anonymizer pseudonimiza dados pessoais e guarda os valores originais para a reidentificação
+type Anonymizer struct {
+	detectors  []Detector
+	key        []byte
+	reidentify bool
+	vault      map[string]string // Pseudônimo -> valor original
+	mu         sync.RWMutex
+}
+
+// New cria um Anonymizer com os detectores informados, executados em ordem
+func New(config Config, detectors ...Detector) *Anonymizer {
+	key := []byte(config.Key)
+	if len(key) == 0 {
+		key = make([]byte, 32)
+		if _, err := rand.Read(key); err != nil {
+			panic(fmt.Sprintf("erro ao gerar chave de pseudonimização: %v", err))
+		}
+	}
+	return &Anonymizer{
+		detectors:  detectors,
+		key:        key,
+		reidentify: config.Reidentify,
+		vault:      make(map[string]string),
+	}
+}
+
+// Token retorna o pseudônimo do valor, sempre o mesmo para o mesmo tipo, valor e chave
+func (a *Anonymizer) Token(entityType, value string) string {
+	mac := hmac.New(sha256.New, a.key)
+	mac.Write([]byte(entityType + "\x00" + value))
+	token := "<" + entityType + "_" + hex.EncodeToString(mac.Sum(nil))[:10] + ">"
+
+	a.mu.Lock()
+	a.vault[token] = value
+	a.mu.Unlock()
+	return token
+}
+
+// Detect executa todos os detectores, descartando entidades sobrepostas (prevalece a mais longa)
+func (a *Anonymizer) Detect(ctx context.Context, text string) ([]Entity, error) {
+	var entities []Entity
+	for _, detector := range a.detectors {
+		found, err := detector.Detect(ctx, text)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao detectar dados pessoais: %w", err)
+		}
+		entities = append(entities, found...)
+	}
+
+	sort.Slice(entities, func(i, j int) bool {
+		if entities[i].Start != entities[j].Start {
+			return entities[i].Start < entities[j].Start
+		}
+		return entities[i].End > entities[j].End
+	})
+	result := make([]Entity, 0, len(entities))
+	end := 0
+	for _, e := range entities {
+		if e.Start < end || e.End <= e.Start {
+			continue
+		}
+		result = append(result, e)
+		end = e.End
+	}
+	return result, nil
+}
+
+// Pseudonymize substitui os dados pessoais do texto pelos seus pseudônimos
+func (a *Anonymizer) Pseudonymize(ctx context.Context, text string) (string, error) {
+	if text == "" {
+		return text, nil
+	}
+	entities, err := a.Detect(ctx, text)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	last := 0
+	for _, e := range entities {
+		b.WriteString(text[last:e.Start])
+		b.WriteString(a.Token(e.Type, e.Value))
+		last = e.End
+	}
+	b.WriteString(text[last:])
+	return b.String(), nil
+}
+
+// Reidentify restaura os valores originais dos pseudônimos conhecidos. Sem permissão
+// (Config.Reidentify) o texto é devolvido pseudonimizado.
+func (a *Anonymizer) Reidentify(text string) string {
+	if !a.reidentify {
+		return text
+	}
+
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return tokenPattern.ReplaceAllStringFunc(text, func(token string) string {
+		if value, ok := a.vault[token]; ok {
+			return value
+		}
+		return token
+	})
+}
+
+// Provider envolve um provedor de LLM: o system e o prompt são pseudonimizados antes da
+// chamada e a resposta é reidentificada quando permitido
+func (a *Anonymizer) Provider(provider llm.Provider) llm.Provider {
+	return llm.ProviderFunc(func(ctx context.Context, req llm.Request) (*llm.Response, error) {
+		var err error
+		if req.System, err = a.Pseudonymize(ctx, req.System); err != nil {
+			return nil, err
+		}
+		if req.Prompt, err = a.Pseudonymize(ctx, req.Prompt); err != nil {
+			return nil, err
+		}
+
+		resp, err := provider.Complete(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		reidentified := *resp
+		reidentified.Text = a.Reidentify(resp.Text)
+		return &reidentified, nil
+	})
+}
@@ -0,0 +1,65 @@
+package pii
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/suissa/HiveMind/agents/llm"
+)
+
+func TestPseudonymizeAndReidentify(t *testing.T) {
+	ctx := context.Background()
+	a := New(Config{Key: "segredo", Reidentify: true}, DefaultPatterns())
+
+	text := "Cliente ana@exemplo.com, CPF 123.456.789-09, telefone (11) 98765-4321. Reenviar para ana@exemplo.com."
+	masked, err := a.Pseudonymize(ctx, text)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, value := range []string{"ana@exemplo.com", "123.456.789-09", "98765-4321"} {
+		if strings.Contains(masked, value) {
+			t.Fatalf("dado pessoal %s não pseudonimizado: %s", value, masked)
+		}
+	}
+	email := a.Token("EMAIL", "ana@exemplo.com")
+	if strings.Count(masked, email) != 2 {
+		t.Fatalf("o mesmo valor deve gerar o mesmo pseudônimo: %s", masked)
+	}
+	if got := a.Reidentify(masked); got != text {
+		t.Fatalf("reidentificação inesperada: %s", got)
+	}
+
+	denied := New(Config{Key: "segredo"}, DefaultPatterns())
+	if denied.Token("EMAIL", "ana@exemplo.com") != email {
+		t.Fatal("a mesma chave deve gerar os mesmos pseudônimos")
+	}
+	if got := denied.Reidentify(masked); got != masked {
+		t.Fatalf("sem permissão o texto não deve ser reidentificado: %s", got)
+	}
+}
+
+func TestProviderWithNER(t *testing.T) {
+	ctx := context.Background()
+	ner := NewNER(llm.ProviderFunc(func(ctx context.Context, req llm.Request) (*llm.Response, error) {
+		return &llm.Response{Text: "```json\n[{\"type\": \"person\", \"value\": \"Maria Souza\"}]\n```"}, nil
+	}), "ner-model")
+	a := New(Config{Reidentify: true}, ner, DefaultPatterns())
+
+	var sent string
+	provider := a.Provider(llm.ProviderFunc(func(ctx context.Context, req llm.Request) (*llm.Response, error) {
+		sent = req.Prompt
+		return &llm.Response{Text: "Olá " + strings.Fields(req.Prompt)[1]}, nil
+	}))
+
+	resp, err := provider.Complete(ctx, llm.Request{Prompt: "Responda Maria Souza (maria@exemplo.com)"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(sent, "Maria") || strings.Contains(sent, "maria@exemplo.com") {
+		t.Fatalf("o provedor recebeu dados pessoais: %s", sent)
+	}
+	if resp.Text != "Olá Maria Souza" {
+		t.Fatalf("resposta não reidentificada: %s", resp.Text)
+	}
+}
@@ -0,0 +1,154 @@
+package policy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Consultas avaliadas pelo HiveMind (pacotes Rego em config/policies)
+const (
+	QueryTask = "hivemind/tasks"
+	QueryTool = "hivemind/tools"
+)
+
+// ErrDenied indica que a política negou a execução
+var ErrDenied = errors.New("execução negada pela política")
+
+// Decision representa o resultado de uma avaliação de política
+type Decision struct {
+	Allow   bool     `json:"allow"`
+	Reasons []string `json:"deny,omitempty"` // Motivos da negação
+}
+
+// Err retorna ErrDenied com os motivos quando a decisão nega a execução
+func (d *Decision) Err() error {
+	if d.Allow {
+		return nil
+	}
+	if len(d.Reasons) == 0 {
+		return ErrDenied
+	}
+	return fmt.Errorf("%w: %s", ErrDenied, strings.Join(d.Reasons, "; "))
+}
+
+// Engine avalia uma entrada contra as políticas configuradas
+type Engine interface {
+	Evaluate(ctx context.Context, query string, input interface{}) (*Decision, error)
+}
+
+// Check avalia a entrada e retorna erro se a execução for negada.
+// Com engine nil a execução é sempre permitida.
+func Check(ctx context.Context, engine Engine, query string, input interface{}) error {
+	if engine == nil {
+		return nil
+	}
+	decision, err := engine.Evaluate(ctx, query, input)
+	if err != nil {
+		return err
+	}
+	return decision.Err()
+}
+
+// OPAClient avalia políticas Rego em um servidor Open Policy Agent via API REST (Data API)
+type OPAClient struct {
+	URL      string // Ex.: http://localhost:8181
+	FailOpen bool   // Permite a execução quando o OPA estiver indisponível
+	client   *http.Client
+}
+
+// NewOPAClient cria um cliente para o OPA
+func NewOPAClient(url string) *OPAClient {
+	return &OPAClient{
+		URL:    strings.TrimRight(url, "/"),
+		client: &http.Client{Timeout: 5 * time.Second},
+	}
+}
+
+// Evaluate implementa Engine consultando POST /v1/data/<query>.
+// O resultado pode ser um booleano ou um objeto {"allow": bool, "deny": [motivos]}.
+func (c *OPAClient) Evaluate(ctx context.Context, query string, input interface{}) (*Decision, error) {
+	decision, err := c.evaluate(ctx, query, input)
+	if err != nil {
+		if c.FailOpen {
+			log.Printf("⚠️ OPA indisponível, permitindo execução: %v", err)
+			return &Decision{Allow: true}, nil
+		}
+		return nil, err
+	}
+	return decision, nil
+}
+
+// evaluate faz a consulta ao OPA
+func (c *OPAClient) evaluate(ctx context.Context, query string, input interface{}) (*Decision, error) {
+	body, err := json.Marshal(map[string]interface{}{"input": input})
+	if err != nil {
+		return nil, fmt.Errorf("erro ao serializar entrada da política: %v", err)
+	}
+
+	url := fmt.Sprintf("%s/v1/data/%s", c.URL, strings.Trim(query, "/"))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("erro ao criar requisição ao OPA: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao consultar OPA: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("OPA retornou status %d", resp.StatusCode)
+	}
+
+	var response struct {
+		Result json.RawMessage `json:"result"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("erro ao decodificar resposta do OPA: %v", err)
+	}
+
+	// Política indefinida: nega por padrão
+	if len(response.Result) == 0 {
+		return &Decision{Allow: false, Reasons: []string{"política indefinida: " + query}}, nil
+	}
+
+	var allow bool
+	if err := json.Unmarshal(response.Result, &allow); err == nil {
+		return &Decision{Allow: allow}, nil
+	}
+
+	var decision Decision
+	if err := json.Unmarshal(response.Result, &decision); err != nil {
+		return nil, fmt.Errorf("resultado de política inválido: %v", err)
+	}
+	if len(decision.Reasons) > 0 {
+		decision.Allow = false
+	}
+	return &decision, nil
+}
+
+// TaskInput é a entrada avaliada para uma TaskRequest
+type TaskInput struct {
+	ID          string                 `json:"id"`
+	Tenant      string                 `json:"tenant"`
+	Description string                 `json:"description"`
+	Parameters  map[string]interface{} `json:"parameters"`
+}
+
+// ToolInput é a entrada avaliada para uma chamada de ferramenta
+type ToolInput struct {
+	AgentID    string                 `json:"agent_id"`
+	Role       string                 `json:"role"`
+	Tenant     string                 `json:"tenant"`
+	Tool       string                 `json:"tool"`
+	Parameters map[string]interface{} `json:"parameters"`
+}
@@ -0,0 +1,67 @@
+package policy
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestOPAClientEvaluate(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Input TaskInput `json:"input"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+
+		switch r.URL.Path {
+		case "/v1/data/hivemind/tasks":
+			if budget, _ := body.Input.Parameters["budget"].(float64); budget > 1000 {
+				w.Write([]byte(`{"result": {"allow": false, "deny": ["orçamento acima do limite"]}}`))
+				return
+			}
+			w.Write([]byte(`{"result": {"allow": true}}`))
+		case "/v1/data/hivemind/tools":
+			w.Write([]byte(`{"result": true}`))
+		default:
+			w.Write([]byte(`{}`))
+		}
+	}))
+	defer server.Close()
+
+	client := NewOPAClient(server.URL)
+	ctx := context.Background()
+
+	if err := Check(ctx, client, QueryTask, TaskInput{Parameters: map[string]interface{}{"budget": 10}}); err != nil {
+		t.Errorf("Tarefa deveria ser permitida: %v", err)
+	}
+
+	err := Check(ctx, client, QueryTask, TaskInput{Parameters: map[string]interface{}{"budget": 5000}})
+	if !errors.Is(err, ErrDenied) {
+		t.Errorf("Tarefa deveria ser negada, erro: %v", err)
+	}
+
+	if err := Check(ctx, client, QueryTool, ToolInput{Tool: "nmap"}); err != nil {
+		t.Errorf("Ferramenta deveria ser permitida: %v", err)
+	}
+
+	if err := Check(ctx, client, "hivemind/unknown", nil); !errors.Is(err, ErrDenied) {
+		t.Errorf("Política indefinida deveria negar, erro: %v", err)
+	}
+}
+
+func TestOPAClientFailOpen(t *testing.T) {
+	client := NewOPAClient("http://127.0.0.1:1")
+	ctx := context.Background()
+
+	if err := Check(ctx, client, QueryTask, TaskInput{}); err == nil || errors.Is(err, ErrDenied) {
+		t.Errorf("OPA indisponível deveria retornar erro de conexão, erro: %v", err)
+	}
+
+	client.FailOpen = true
+	if err := Check(ctx, client, QueryTask, TaskInput{}); err != nil {
+		t.Errorf("Com FailOpen a tarefa deveria ser permitida: %v", err)
+	}
+}
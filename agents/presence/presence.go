@@ -0,0 +1,355 @@
+// Package presence implementa o protocolo de presença dos agentes no barramento: cada agente
+// publica heartbeats periódicos com a sua carga (Beacon) e os monitores (Monitor) marcam como
+// indisponíveis os agentes cujos heartbeats param de chegar, avisando os observadores para
+// que as tarefas desses agentes sejam redistribuídas.
+package presence
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/suissa/HiveMind/agents/communication"
+)
+
+// SubjectPrefix é o prefixo dos tópicos de heartbeat; cada agente publica em
+// "presence.heartbeat.<id>"
+const SubjectPrefix = "presence.heartbeat"
+
+// Padrões do protocolo
+const (
+	DefaultInterval    = 5 * time.Second
+	DefaultMissedBeats = 3
+)
+
+// Subject retorna o tópico de heartbeat do agente
+func Subject(agentID string) string {
+	return SubjectPrefix + "." + agentID
+}
+
+// State é a situação de um agente para os monitores
+type State string
+
+const (
+	StateHealthy   State = "healthy"   // Heartbeats em dia
+	StateUnhealthy State = "unhealthy" // Heartbeats atrasados
+	StateLeft      State = "left"      // O agente avisou que está saindo
+)
+
+// Intake é a situação da entrada de tarefas do agente, alterada pelo plano de controle
+type Intake string
+
+const (
+	IntakeOpen     Intake = ""         // Recebendo tarefas
+	IntakePaused   Intake = "paused"   // Não recebe novas tarefas
+	IntakeDraining Intake = "draining" // Concluindo as tarefas em andamento, sem receber novas
+	IntakeDrained  Intake = "drained"  // Sem tarefas em andamento e sem receber novas
+)
+
+// Control é a situação administrativa do agente, definida pelos comandos do plano de controle
+type Control struct {
+	Intake      Intake `json:"intake,omitempty"`
+	LogLevel    string `json:"log_level,omitempty"`
+	LastCommand string `json:"last_command,omitempty"` // ID do último comando confirmado
+}
+
+// Load é a carga informada no heartbeat
+type Load struct {
+	Running  int `json:"running"`            // Tarefas em execução
+	Capacity int `json:"capacity,omitempty"` // Tarefas simultâneas suportadas (0 sem limite)
+	Queued   int `json:"queued,omitempty"`   // Tarefas aguardando na caixa de entrada
+}
+
+// Heartbeat é a mensagem periódica de presença de um agente
+type Heartbeat struct {
+	AgentID   string        `json:"agent_id"`
+	Name      string        `json:"name,omitempty"`
+	Role      string        `json:"role,omitempty"`
+	Load      Load          `json:"load"`
+	Interval  time.Duration `json:"interval"`          // Intervalo entre os heartbeats do agente
+	Leaving   bool          `json:"leaving,omitempty"` // Último heartbeat, enviado no encerramento
+	Control   *Control      `json:"control,omitempty"` // Ausente enquanto o agente não recebeu comandos
+	Timestamp time.Time     `json:"timestamp"`
+}
+
+// Intake retorna a situação da entrada de tarefas informada no heartbeat
+func (h Heartbeat) Intake() Intake {
+	if h.Control == nil {
+		return IntakeOpen
+	}
+	return h.Control.Intake
+}
+
+// Bus é o barramento usado pelo protocolo; communication.CommunicationClient o implementa
+type Bus interface {
+	Publish(ctx context.Context, subject string, data []byte) error
+	Subscribe(subject string, handler communication.MessageHandler) error
+	Unsubscribe(subject string) error
+}
+
+// Config configura os beacons e os monitores
+type Config struct {
+	Interval    time.Duration `json:"interval" yaml:"interval"`         // Intervalo entre os heartbeats (padrão DefaultInterval)
+	MissedBeats int           `json:"missed_beats" yaml:"missed_beats"` // Heartbeats perdidos até o agente ficar indisponível (padrão DefaultMissedBeats)
+}
+
+// withDefaults completa os padrões da configuração
+func (c Config) withDefaults() Config {
+	if c.Interval <= 0 {
+		c.Interval = DefaultInterval
+	}
+	if c.MissedBeats <= 0 {
+		c.MissedBeats = DefaultMissedBeats
+	}
+	return c
+}
+
+// Beacon publica os heartbeats de um agente
+type Beacon struct {
+	bus       Bus
+	heartbeat Heartbeat
+	load      func() Load
+	control   func() Control
+	interval  time.Duration
+}
+
+// NewBeacon cria o beacon do agente descrito em heartbeat; load, se informado, fornece a
+// carga atual a cada heartbeat
+func NewBeacon(bus Bus, heartbeat Heartbeat, load func() Load, config Config) *Beacon {
+	config = config.withDefaults()
+	heartbeat.Interval = config.Interval
+	return &Beacon{bus: bus, heartbeat: heartbeat, load: load, interval: config.Interval}
+}
+
+// SetControl define a função que fornece a situação administrativa do agente a cada
+// heartbeat; deve ser chamada antes de Run
+func (b *Beacon) SetControl(control func() Control) {
+	b.control = control
+}
+
+// Beat publica um heartbeat
+func (b *Beacon) Beat(ctx context.Context) error {
+	return b.publish(ctx, false)
+}
+
+// Run publica um heartbeat a cada intervalo até o contexto ser cancelado; então publica o
+// heartbeat de saída, para que os monitores redistribuam as tarefas sem esperar o atraso
+func (b *Beacon) Run(ctx context.Context) {
+	ticker := time.NewTicker(b.interval)
+	defer ticker.Stop()
+	for {
+		if err := b.publish(ctx, false); err != nil && ctx.Err() == nil {
+			log.Printf("⚠️ Erro ao publicar heartbeat do agente %s: %v", b.heartbeat.AgentID, err)
+		}
+		select {
+		case <-ctx.Done():
+			leaveCtx, cancel := context.WithTimeout(context.Background(), b.interval)
+			defer cancel()
+			b.publish(leaveCtx, true)
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (b *Beacon) publish(ctx context.Context, leaving bool) error {
+	heartbeat := b.heartbeat
+	heartbeat.Leaving = leaving
+	heartbeat.Timestamp = time.Now()
+	if b.load != nil {
+		heartbeat.Load = b.load()
+	}
+	if b.control != nil {
+		if control := b.control(); control != (Control{}) {
+			heartbeat.Control = &control
+		}
+	}
+	data, err := json.Marshal(heartbeat)
+	if err != nil {
+		return fmt.Errorf("erro ao serializar heartbeat: %v", err)
+	}
+	return b.bus.Publish(ctx, Subject(heartbeat.AgentID), data)
+}
+
+// Status é a situação de um agente conhecida pelo monitor
+type Status struct {
+	Heartbeat           // Último heartbeat recebido
+	State     State     `json:"state"`
+	LastSeen  time.Time `json:"last_seen"` // Recebimento do último heartbeat
+}
+
+// Available informa se o agente pode receber tarefas: os heartbeats estão em dia e a entrada
+// não foi pausada nem drenada pelo plano de controle
+func (s Status) Available() bool {
+	return s.State == StateHealthy && s.Intake() == IntakeOpen
+}
+
+// Monitor acompanha os heartbeats dos agentes
+type Monitor struct {
+	bus       Bus
+	config    Config
+	agents    map[string]*Status
+	observers []func(Status)
+	mu        sync.RWMutex
+}
+
+// NewMonitor cria um monitor; Start o inscreve no barramento
+func NewMonitor(bus Bus, config Config) *Monitor {
+	return &Monitor{bus: bus, config: config.withDefaults(), agents: make(map[string]*Status)}
+}
+
+// OnChange registra um observador das mudanças de situação dos agentes: o primeiro heartbeat,
+// o atraso, a volta e a saída. Os observadores são chamados na ordem de registro.
+func (m *Monitor) OnChange(observer func(Status)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.observers = append(m.observers, observer)
+}
+
+// Start inscreve o monitor nos heartbeats e verifica os atrasos a cada intervalo, até o
+// contexto ser cancelado
+func (m *Monitor) Start(ctx context.Context) error {
+	subject := SubjectPrefix + ".*"
+	err := m.bus.Subscribe(subject, func(ctx context.Context, _ string, data []byte) error {
+		var heartbeat Heartbeat
+		if err := json.Unmarshal(data, &heartbeat); err != nil {
+			return fmt.Errorf("heartbeat inválido: %v", err)
+		}
+		m.Observe(heartbeat, time.Now())
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("erro ao se inscrever nos heartbeats: %v", err)
+	}
+
+	go func() {
+		defer m.bus.Unsubscribe(subject)
+		ticker := time.NewTicker(m.config.Interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				m.Sweep(now)
+			}
+		}
+	}()
+	return nil
+}
+
+// Observe registra um heartbeat recebido em now
+func (m *Monitor) Observe(heartbeat Heartbeat, now time.Time) {
+	if heartbeat.AgentID == "" {
+		return
+	}
+	state := StateHealthy
+	if heartbeat.Leaving {
+		state = StateLeft
+	}
+
+	m.mu.Lock()
+	status, known := m.agents[heartbeat.AgentID]
+	if !known {
+		status = &Status{}
+		m.agents[heartbeat.AgentID] = status
+	}
+	changed := !known || status.State != state || status.Intake() != heartbeat.Intake()
+	status.Heartbeat = heartbeat
+	status.State = state
+	status.LastSeen = now
+	snapshot, observers := *status, m.observers
+	m.mu.Unlock()
+
+	if changed {
+		notify(observers, snapshot)
+	}
+}
+
+// Acknowledge registra a situação administrativa confirmada pelo agente a um comando do plano
+// de controle, sem esperar o próximo heartbeat. Agentes desconhecidos são ignorados.
+func (m *Monitor) Acknowledge(agentID string, control Control) {
+	m.mu.Lock()
+	status, ok := m.agents[agentID]
+	if !ok {
+		m.mu.Unlock()
+		return
+	}
+	changed := status.Intake() != control.Intake
+	status.Control = nil
+	if control != (Control{}) {
+		status.Control = &control
+	}
+	snapshot, observers := *status, m.observers
+	m.mu.Unlock()
+
+	if changed {
+		notify(observers, snapshot)
+	}
+}
+
+// Sweep marca como indisponíveis os agentes sem heartbeat há MissedBeats intervalos
+func (m *Monitor) Sweep(now time.Time) {
+	var changed []Status
+	m.mu.Lock()
+	for _, status := range m.agents {
+		if status.State != StateHealthy {
+			continue
+		}
+		interval := status.Interval
+		if interval <= 0 {
+			interval = m.config.Interval
+		}
+		if now.Sub(status.LastSeen) > interval*time.Duration(m.config.MissedBeats) {
+			status.State = StateUnhealthy
+			changed = append(changed, *status)
+		}
+	}
+	observers := m.observers
+	m.mu.Unlock()
+
+	for _, status := range changed {
+		notify(observers, status)
+	}
+}
+
+// notify avisa os observadores
+func notify(observers []func(Status), status Status) {
+	for _, observer := range observers {
+		observer(status)
+	}
+}
+
+// Status retorna a situação de um agente
+func (m *Monitor) Status(agentID string) (Status, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	status, ok := m.agents[agentID]
+	if !ok {
+		return Status{}, false
+	}
+	return *status, true
+}
+
+// Available informa se o agente pode receber tarefas. Agentes dos quais nenhum heartbeat foi
+// recebido são considerados disponíveis, para que agentes sem beacon continuem funcionando.
+func (m *Monitor) Available(agentID string) bool {
+	status, ok := m.Status(agentID)
+	return !ok || status.Available()
+}
+
+// Agents retorna a situação de todos os agentes conhecidos, ordenados pelo ID
+func (m *Monitor) Agents() []Status {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	statuses := make([]Status, 0, len(m.agents))
+	for _, status := range m.agents {
+		statuses = append(statuses, *status)
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].AgentID < statuses[j].AgentID })
+	return statuses
+}
@@ -0,0 +1,130 @@
+package presence
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/suissa/HiveMind/agents/communication/commtest"
+)
+
+func TestMonitorMarksMissingAgents(t *testing.T) {
+	monitor := NewMonitor(commtest.NewBus(), Config{Interval: time.Second, MissedBeats: 2})
+	var changes []Status
+	monitor.OnChange(func(status Status) { changes = append(changes, status) })
+
+	start := time.Now()
+	monitor.Observe(Heartbeat{AgentID: "a-1", Interval: time.Second, Load: Load{Running: 2}}, start)
+	monitor.Observe(Heartbeat{AgentID: "a-1", Interval: time.Second}, start.Add(time.Second))
+	monitor.Sweep(start.Add(2 * time.Second))
+	if !monitor.Available("a-1") || len(changes) != 1 {
+		t.Fatalf("agente deveria continuar disponível: %+v", changes)
+	}
+
+	monitor.Sweep(start.Add(4 * time.Second))
+	if monitor.Available("a-1") {
+		t.Fatal("agente sem heartbeat deveria estar indisponível")
+	}
+	if len(changes) != 2 || changes[1].State != StateUnhealthy {
+		t.Fatalf("esperava a mudança para unhealthy: %+v", changes)
+	}
+
+	monitor.Observe(Heartbeat{AgentID: "a-1", Interval: time.Second}, start.Add(5*time.Second))
+	if !monitor.Available("a-1") || changes[2].State != StateHealthy {
+		t.Fatal("agente deveria voltar a ficar disponível")
+	}
+	if !monitor.Available("desconhecido") {
+		t.Fatal("agentes sem heartbeat conhecido são considerados disponíveis")
+	}
+}
+
+func TestBeaconLeaves(t *testing.T) {
+	bus := commtest.NewBus()
+	monitor := NewMonitor(bus, Config{Interval: time.Hour})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := monitor.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	beacon := NewBeacon(bus, Heartbeat{AgentID: "a-1", Role: "writer"}, func() Load { return Load{Running: 1} }, Config{Interval: time.Hour})
+	beaconCtx, stop := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		beacon.Run(beaconCtx)
+	}()
+
+	deadline := time.After(time.Second)
+	for {
+		if status, ok := monitor.Status("a-1"); ok {
+			if status.Load.Running != 1 || status.Role != "writer" {
+				t.Fatalf("heartbeat inesperado: %+v", status)
+			}
+			break
+		}
+		select {
+		case <-deadline:
+			t.Fatal("heartbeat não recebido")
+		case <-time.After(5 * time.Millisecond):
+		}
+	}
+
+	stop()
+	<-done
+	status, _ := monitor.Status("a-1")
+	if status.State != StateLeft || status.Available() {
+		t.Fatalf("esperava o agente fora após o heartbeat de saída: %+v", status)
+	}
+
+	data, _ := json.Marshal(status)
+	var decoded Status
+	if err := json.Unmarshal(data, &decoded); err != nil || decoded.AgentID != "a-1" {
+		t.Fatalf("status não serializável: %v", err)
+	}
+}
+
+func TestControlAffectsAvailability(t *testing.T) {
+	bus := commtest.NewBus()
+	monitor := NewMonitor(bus, Config{})
+	var changes []Status
+	monitor.OnChange(func(status Status) { changes = append(changes, status) })
+
+	monitor.Acknowledge("desconhecido", Control{Intake: IntakePaused})
+	if _, ok := monitor.Status("desconhecido"); ok {
+		t.Fatal("confirmações de agentes desconhecidos deveriam ser ignoradas")
+	}
+
+	now := time.Now()
+	monitor.Observe(Heartbeat{AgentID: "a-1"}, now)
+	monitor.Acknowledge("a-1", Control{Intake: IntakePaused, LastCommand: "c-1"})
+	if monitor.Available("a-1") || len(changes) != 2 || changes[1].Intake() != IntakePaused {
+		t.Fatalf("o agente pausado não deveria estar disponível: %+v", changes)
+	}
+
+	// Os heartbeats seguintes trazem a situação informada pelo próprio agente
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := monitor.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	control := Control{Intake: IntakeDrained}
+	beacon := NewBeacon(bus, Heartbeat{AgentID: "a-1"}, nil, Config{})
+	beacon.SetControl(func() Control { return control })
+	if err := beacon.Beat(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if status, _ := monitor.Status("a-1"); status.Intake() != IntakeDrained || len(changes) != 3 {
+		t.Fatalf("o heartbeat deveria trazer a drenagem: %+v", status)
+	}
+
+	control = Control{}
+	if err := beacon.Beat(ctx); err != nil {
+		t.Fatal(err)
+	}
+	status, _ := monitor.Status("a-1")
+	if !status.Available() || status.Control != nil || len(changes) != 4 {
+		t.Fatalf("o agente retomado deveria voltar a ficar disponível: %+v", status)
+	}
+}
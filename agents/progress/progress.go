@@ -0,0 +1,103 @@
+// Package progress permite que tarefas e ferramentas demoradas informem o andamento
+// ("30%, varrendo portas") a quem as executa. O Reporter viaja no contexto: quem executa a
+// tarefa o instala com WithReporter e o código da tarefa chama Report, que não faz nada
+// quando ninguém acompanha a execução.
+package progress
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// Update é um informe de andamento
+type Update struct {
+	Percent   float64   `json:"percent"` // De 0 a 100
+	Message   string    `json:"message,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// Reporter recebe os informes de andamento de uma tarefa
+type Reporter interface {
+	Report(percent float64, message string)
+}
+
+// ReporterFunc adapta uma função a Reporter
+type ReporterFunc func(percent float64, message string)
+
+// Report implementa Reporter
+func (f ReporterFunc) Report(percent float64, message string) {
+	f(percent, message)
+}
+
+type reporterKey struct{}
+
+// WithReporter instala o Reporter que recebe os informes das tarefas executadas com o contexto
+func WithReporter(ctx context.Context, reporter Reporter) context.Context {
+	return context.WithValue(ctx, reporterKey{}, reporter)
+}
+
+// FromContext retorna o Reporter do contexto, se houver
+func FromContext(ctx context.Context) (Reporter, bool) {
+	if ctx == nil {
+		return nil, false
+	}
+	reporter, ok := ctx.Value(reporterKey{}).(Reporter)
+	return reporter, ok && reporter != nil
+}
+
+// Report informa o andamento ao Reporter do contexto. O percentual é limitado a [0, 100].
+func Report(ctx context.Context, percent float64, message string) {
+	if reporter, ok := FromContext(ctx); ok {
+		reporter.Report(clamp(percent), message)
+	}
+}
+
+// Scale faz os informes feitos com o contexto retornado ocuparem a faixa [from, to] do
+// andamento do contexto pai, como uma ferramenta que executa uma etapa da tarefa
+func Scale(ctx context.Context, from, to float64) context.Context {
+	parent, ok := FromContext(ctx)
+	if !ok {
+		return ctx
+	}
+	from, to = clamp(from), clamp(to)
+	return WithReporter(ctx, ReporterFunc(func(percent float64, message string) {
+		parent.Report(from+(to-from)*percent/100, message)
+	}))
+}
+
+// Counter informa o andamento de um lote com um total conhecido de itens
+type Counter struct {
+	ctx   context.Context
+	total int
+	done  int
+	mu    sync.Mutex
+}
+
+// NewCounter cria um contador para um lote de total itens
+func NewCounter(ctx context.Context, total int) *Counter {
+	return &Counter{ctx: ctx, total: total}
+}
+
+// Add registra n itens concluídos e informa o andamento do lote
+func (c *Counter) Add(n int, message string) {
+	c.mu.Lock()
+	c.done += n
+	percent := 100.0
+	if c.total > 0 {
+		percent = float64(c.done) * 100 / float64(c.total)
+	}
+	c.mu.Unlock()
+	Report(c.ctx, percent, message)
+}
+
+func clamp(percent float64) float64 {
+	switch {
+	case percent < 0 || math.IsNaN(percent):
+		return 0
+	case percent > 100:
+		return 100
+	}
+	return percent
+}
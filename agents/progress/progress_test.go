@@ -0,0 +1,52 @@
+package progress
+
+import (
+	"context"
+	"testing"
+)
+
+func TestReport(t *testing.T) {
+	// Sem Reporter no contexto o informe é ignorado
+	Report(context.Background(), 50, "sem ouvinte")
+
+	var updates []Update
+	ctx := WithReporter(context.Background(), ReporterFunc(func(percent float64, message string) {
+		updates = append(updates, Update{Percent: percent, Message: message})
+	}))
+	Report(ctx, 30, "varrendo portas")
+	Report(ctx, 150, "acima do limite")
+	Report(ctx, -1, "abaixo do limite")
+
+	want := []Update{{Percent: 30, Message: "varrendo portas"}, {Percent: 100, Message: "acima do limite"}, {Percent: 0, Message: "abaixo do limite"}}
+	if len(updates) != len(want) {
+		t.Fatalf("informes = %+v; esperava %+v", updates, want)
+	}
+	for i := range want {
+		if updates[i] != want[i] {
+			t.Errorf("informe %d = %+v; esperava %+v", i, updates[i], want[i])
+		}
+	}
+}
+
+func TestScaleAndCounter(t *testing.T) {
+	var last float64
+	ctx := WithReporter(context.Background(), ReporterFunc(func(percent float64, _ string) {
+		last = percent
+	}))
+
+	// A etapa ocupa a segunda metade do andamento da tarefa
+	step := Scale(ctx, 50, 100)
+	counter := NewCounter(step, 4)
+	counter.Add(1, "item 1")
+	if last != 62.5 {
+		t.Fatalf("andamento = %v; esperava 62.5", last)
+	}
+	counter.Add(3, "lote concluído")
+	if last != 100 {
+		t.Fatalf("andamento = %v; esperava 100", last)
+	}
+
+	if Scale(context.Background(), 0, 50) != context.Background() {
+		t.Fatal("Scale sem Reporter deveria retornar o próprio contexto")
+	}
+}
@@ -0,0 +1,138 @@
+// Package prompt monta os prompts enviados aos LLMs e os comprime quando excedem a
+// janela de contexto do modelo: primeiro descarta as memórias menos relevantes, depois
+// resume os turnos mais antigos da conversa. Um prompt que não cabe nem assim resulta
+// em erro, em vez de ser truncado em silêncio ou rejeitado pelo provedor.
+package prompt
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/suissa/HiveMind/agents/errs"
+)
+
+// Memory é um trecho de memória incluído no prompt, com sua relevância para a tarefa
+type Memory struct {
+	Content   string
+	Relevance float64
+}
+
+// Turn é um turno anterior da conversa
+type Turn struct {
+	Role    string // "user" ou "assistant"
+	Content string
+}
+
+// Prompt reúne as partes de um prompt. Memórias e histórico podem ser comprimidos;
+// System e Input são sempre preservados.
+type Prompt struct {
+	System   string
+	Memories []Memory
+	History  []Turn // Do mais antigo para o mais recente
+	Input    string
+}
+
+// Render monta o texto do prompt (sem o System, enviado separadamente ao provedor)
+func (p Prompt) Render() string {
+	var b strings.Builder
+	if len(p.Memories) > 0 {
+		b.WriteString("Memórias relevantes:\n")
+		for _, m := range p.Memories {
+			b.WriteString("- ")
+			b.WriteString(m.Content)
+			b.WriteString("\n")
+		}
+		b.WriteString("\n")
+	}
+	if len(p.History) > 0 {
+		b.WriteString("Conversa anterior:\n")
+		for _, t := range p.History {
+			b.WriteString(t.Role)
+			b.WriteString(": ")
+			b.WriteString(t.Content)
+			b.WriteString("\n")
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString(p.Input)
+	return b.String()
+}
+
+// Tokens estima o total de tokens do prompt, incluindo o System
+func (p Prompt) Tokens() int {
+	return EstimateTokens(p.System) + EstimateTokens(p.Render())
+}
+
+// EstimateTokens estima a quantidade de tokens de um texto (~4 caracteres por token)
+func EstimateTokens(text string) int {
+	return (utf8.RuneCountInString(text) + 3) / 4
+}
+
+// Summarizer resume um texto em até maxTokens tokens
+type Summarizer func(ctx context.Context, text string, maxTokens int) (string, error)
+
+// Compressor ajusta prompts ao orçamento de tokens
+type Compressor struct {
+	// Summarize resume os turnos antigos; sem ele os turnos antigos são descartados
+	Summarize Summarizer
+	// KeepTurns é a quantidade de turnos recentes preservados integralmente
+	KeepTurns int
+}
+
+// Fit retorna o prompt dentro do orçamento de tokens:
+//  1. descarta as memórias menos relevantes
+//  2. resume (ou descarta) os turnos mais antigos, preservando os KeepTurns mais recentes
+//  3. descarta os turnos recentes restantes
+//
+// Se System e Input sozinhos excedem o orçamento, retorna um erro errs.ErrValidation.
+func (c *Compressor) Fit(ctx context.Context, p Prompt, budget int) (Prompt, error) {
+	if p.Tokens() <= budget {
+		return p, nil
+	}
+
+	base := Prompt{System: p.System, Input: p.Input}
+	if base.Tokens() > budget {
+		return p, errs.New(errs.ErrValidation, "prompt.Fit",
+			"prompt com %d tokens excede a janela de contexto de %d tokens", base.Tokens(), budget)
+	}
+
+	// 1. Memórias: mantém as mais relevantes
+	p.Memories = append([]Memory(nil), p.Memories...)
+	sort.SliceStable(p.Memories, func(i, j int) bool { return p.Memories[i].Relevance > p.Memories[j].Relevance })
+	for len(p.Memories) > 0 && p.Tokens() > budget {
+		p.Memories = p.Memories[:len(p.Memories)-1]
+	}
+	if p.Tokens() <= budget {
+		return p, nil
+	}
+
+	// 2. Turnos antigos: resumidos em um único turno
+	keep := c.KeepTurns
+	if keep < 0 {
+		keep = 0
+	}
+	if len(p.History) > keep {
+		old, recent := p.History[:len(p.History)-keep], p.History[len(p.History)-keep:]
+		p.History = append([]Turn(nil), recent...)
+
+		if c.Summarize != nil {
+			available := budget - p.Tokens() - EstimateTokens("summary: \n")
+			if available > 0 {
+				summary, err := c.Summarize(ctx, Prompt{History: old}.Render(), available)
+				if err != nil {
+					return p, fmt.Errorf("erro ao resumir o histórico: %w", err)
+				}
+				p.History = append([]Turn{{Role: "summary", Content: summary}}, p.History...)
+			}
+		}
+	}
+
+	// 3. Turnos recentes (e o resumo, se ainda não couber)
+	for len(p.History) > 0 && p.Tokens() > budget {
+		p.History = p.History[1:]
+	}
+	return p, nil
+}
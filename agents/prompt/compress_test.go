@@ -0,0 +1,68 @@
+package prompt
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/suissa/HiveMind/agents/errs"
+)
+
+func TestFitDropsLowRelevanceMemoriesFirst(t *testing.T) {
+	p := Prompt{
+		Input: "tarefa",
+		Memories: []Memory{
+			{Content: strings.Repeat("a", 40), Relevance: 0.2},
+			{Content: strings.Repeat("b", 40), Relevance: 0.9},
+		},
+	}
+	budget := p.Tokens() - 5
+
+	got, err := (&Compressor{}).Fit(context.Background(), p, budget)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Memories) != 1 || got.Memories[0].Relevance != 0.9 {
+		t.Fatalf("esperava manter apenas a memória mais relevante: %#v", got.Memories)
+	}
+	if got.Tokens() > budget {
+		t.Fatalf("prompt com %d tokens excede o orçamento de %d", got.Tokens(), budget)
+	}
+}
+
+func TestFitSummarizesOldestTurns(t *testing.T) {
+	p := Prompt{Input: "pergunta atual"}
+	for i := 0; i < 10; i++ {
+		p.History = append(p.History, Turn{Role: "user", Content: strings.Repeat("x", 80)})
+	}
+
+	var summarized string
+	c := &Compressor{
+		KeepTurns: 2,
+		Summarize: func(ctx context.Context, text string, maxTokens int) (string, error) {
+			summarized = text
+			return "resumo", nil
+		},
+	}
+	got, err := c.Fit(context.Background(), p, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if summarized == "" {
+		t.Fatal("o histórico antigo deveria ter sido resumido")
+	}
+	if len(got.History) != 3 || got.History[0].Content != "resumo" {
+		t.Fatalf("esperava o resumo seguido dos 2 turnos recentes: %#v", got.History)
+	}
+	if got.Input != p.Input {
+		t.Fatal("a entrada não deve ser alterada")
+	}
+}
+
+func TestFitRejectsOversizedInput(t *testing.T) {
+	_, err := (&Compressor{}).Fit(context.Background(), Prompt{Input: strings.Repeat("x", 400)}, 10)
+	if !errors.Is(err, errs.ErrValidation) {
+		t.Fatalf("esperava ErrValidation, obteve %v", err)
+	}
+}
@@ -0,0 +1,81 @@
+package agents
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/suissa/HiveMind/agents/groupchat"
+	"github.com/suissa/HiveMind/agents/recap"
+)
+
+// WorkflowTranscript monta a transcrição de um workflow de tarefas: cada tarefa concluída
+// contribui com a sua descrição e o seu resultado, na ordem do projeto
+func WorkflowTranscript(project *MarketingProject, outputs map[string]string) recap.Transcript {
+	transcript := recap.Transcript{WorkflowID: project.Name, Objective: project.Objective}
+	for _, task := range project.Tasks {
+		output, ok := outputs[task.ID]
+		if !ok {
+			continue
+		}
+		speaker := task.AssignedTo
+		if speaker == "" {
+			speaker = task.ID
+		}
+		transcript.Entries = append(transcript.Entries, recap.Entry{
+			Speaker: speaker,
+			Text:    fmt.Sprintf("Tarefa %s: %s\nResultado: %s", task.Name, task.Description, output),
+		})
+	}
+	return transcript
+}
+
+// GroupChatTranscript monta a transcrição de uma conversa em grupo
+func GroupChatTranscript(workflowID string, thread *groupchat.Thread) recap.Transcript {
+	transcript := recap.Transcript{WorkflowID: workflowID, Objective: thread.Topic}
+	for _, message := range thread.Messages {
+		transcript.Entries = append(transcript.Entries, recap.Entry{Speaker: message.Speaker, Text: message.Text})
+	}
+	return transcript
+}
+
+// SetRecap resume cada workflow ou conversa em grupo concluído da equipe numa memória de
+// longo prazo (o que foi pedido, o que foi decidido e as questões em aberto); nil desativa
+func (c *MarketingCrew) SetRecap(summarizer recap.Summarizer) {
+	if summarizer == nil {
+		c.recap = nil
+		return
+	}
+	c.recap = recap.NewWriter(summarizer, c.memManager)
+}
+
+// Recap retorna o gravador de resumos da equipe (nil se desativado), para ajustar o dono,
+// a importância e as tags das memórias
+func (c *MarketingCrew) Recap() *recap.Writer {
+	return c.recap
+}
+
+// writeRecap grava o resumo da transcrição. Uma falha não afeta o resultado do workflow, que
+// já foi concluído.
+func (c *MarketingCrew) writeRecap(ctx context.Context, transcript recap.Transcript) {
+	if c.recap == nil || c.memManager == nil {
+		return
+	}
+	mem, err := c.recap.Write(context.WithoutCancel(ctx), transcript)
+	if err != nil {
+		log.Printf("⚠️ Erro ao resumir o workflow %s: %v", transcript.WorkflowID, err)
+		return
+	}
+	if mem != nil {
+		c.emitter.Emit(Event{
+			Type:      EventWorkflowUpdate,
+			Timestamp: mem.Timestamp,
+			Source:    "marketing_crew",
+			Data: map[string]interface{}{
+				"action":    "workflow_summarized",
+				"project":   transcript.WorkflowID,
+				"memory_id": mem.ID,
+			},
+		})
+	}
+}
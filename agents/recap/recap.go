@@ -0,0 +1,204 @@
+// Package recap resume cada conversa ou workflow concluído numa memória compacta — o que foi
+// pedido, o que foi decidido e as questões em aberto — gravada no longo prazo. Os próximos
+// workflows recuperam o resumo pela busca por similaridade, sem repassar a transcrição inteira.
+package recap
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/suissa/HiveMind/agents/llm"
+	"github.com/suissa/HiveMind/agents/memory"
+)
+
+// Valores padrão do resumo
+const (
+	DefaultAgentID       = "recap"
+	DefaultImportance    = 0.9
+	DefaultMaxTranscript = 24000
+)
+
+// Tag identifica as memórias de resumo
+const Tag = "workflow_summary"
+
+// Entry é uma fala ou resultado da transcrição
+type Entry struct {
+	Speaker string // Agente ou tarefa que produziu o texto
+	Text    string
+}
+
+// Transcript é a transcrição de uma conversa ou workflow concluído
+type Transcript struct {
+	WorkflowID string
+	Objective  string
+	Entries    []Entry
+}
+
+// String formata a transcrição com no máximo max caracteres (zero não corta). Quando ela é
+// cortada, são mantidas as falas mais recentes, onde costumam estar as decisões.
+func (t Transcript) String(max int) string {
+	lines := make([]string, len(t.Entries))
+	size := 0
+	for i, entry := range t.Entries {
+		lines[i] = fmt.Sprintf("[%s] %s", entry.Speaker, strings.TrimSpace(entry.Text))
+		size += len(lines[i]) + 1
+	}
+	for max > 0 && size > max && len(lines) > 1 {
+		size -= len(lines[0]) + 1
+		lines = lines[1:]
+	}
+	text := strings.Join(lines, "\n")
+	if max > 0 && len(text) > max {
+		cut := len(text) - max
+		for cut < len(text) && !utf8.RuneStart(text[cut]) {
+			cut++
+		}
+		text = text[cut:]
+	}
+	return text
+}
+
+// Summary é o resumo de uma conversa
+type Summary struct {
+	Asked         string   `json:"asked"`
+	Decided       []string `json:"decided"`
+	OpenQuestions []string `json:"open_questions"`
+}
+
+// Content formata o resumo como o conteúdo da memória
+func (s Summary) Content(t Transcript) string {
+	var b strings.Builder
+	if t.WorkflowID != "" {
+		fmt.Fprintf(&b, "Resumo do workflow %s\n", t.WorkflowID)
+	}
+	fmt.Fprintf(&b, "Pedido: %s\n", s.Asked)
+	writeList(&b, "Decisões", s.Decided)
+	writeList(&b, "Questões em aberto", s.OpenQuestions)
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// writeList escreve a lista com o título, ou "nenhuma" se vazia
+func writeList(b *strings.Builder, title string, items []string) {
+	if len(items) == 0 {
+		fmt.Fprintf(b, "%s: nenhuma\n", title)
+		return
+	}
+	fmt.Fprintf(b, "%s:\n", title)
+	for _, item := range items {
+		fmt.Fprintf(b, "- %s\n", item)
+	}
+}
+
+// Summarizer resume uma transcrição
+type Summarizer interface {
+	Summarize(ctx context.Context, transcript Transcript) (Summary, error)
+}
+
+const summarizerSystem = `Você resume conversas e workflows concluídos de agentes de IA para a memória de longo prazo.
+Responda apenas com JSON no formato {"asked": "...", "decided": ["..."], "open_questions": ["..."]}:
+- asked: o que foi pedido, em uma frase
+- decided: as decisões e os resultados, em frases curtas e autossuficientes
+- open_questions: o que ficou sem resposta ou precisa de acompanhamento
+Não invente decisões que não estejam na transcrição.`
+
+// LLMSummarizer resume transcrições com um modelo de linguagem
+type LLMSummarizer struct {
+	provider llm.Provider
+	Model    string
+	// MaxTranscript limita os caracteres da transcrição enviados ao modelo (padrão DefaultMaxTranscript)
+	MaxTranscript int
+}
+
+// NewLLMSummarizer cria um resumidor com o provedor e o modelo informados
+func NewLLMSummarizer(provider llm.Provider, model string) *LLMSummarizer {
+	return &LLMSummarizer{provider: provider, Model: model, MaxTranscript: DefaultMaxTranscript}
+}
+
+// Summarize implementa Summarizer
+func (s *LLMSummarizer) Summarize(ctx context.Context, transcript Transcript) (Summary, error) {
+	var prompt strings.Builder
+	if transcript.Objective != "" {
+		fmt.Fprintf(&prompt, "Objetivo: %s\n\n", transcript.Objective)
+	}
+	fmt.Fprintf(&prompt, "Transcrição:\n%s\n", transcript.String(s.MaxTranscript))
+
+	resp, err := s.provider.Complete(ctx, llm.Request{
+		Model:       s.Model,
+		System:      summarizerSystem,
+		Prompt:      prompt.String(),
+		Temperature: 0,
+	})
+	if err != nil {
+		return Summary{}, fmt.Errorf("erro ao resumir a conversa: %w", err)
+	}
+	return parse(resp.Text)
+}
+
+// parse extrai o resumo do JSON devolvido, tolerando texto ou blocos de código ao redor
+func parse(text string) (Summary, error) {
+	start, end := strings.Index(text, "{"), strings.LastIndex(text, "}")
+	if start < 0 || end < start {
+		return Summary{}, fmt.Errorf("resumo sem JSON: %q", text)
+	}
+	var summary Summary
+	if err := json.Unmarshal([]byte(text[start:end+1]), &summary); err != nil {
+		return Summary{}, fmt.Errorf("resumo inválido: %v", err)
+	}
+	if strings.TrimSpace(summary.Asked) == "" {
+		return Summary{}, fmt.Errorf("resumo sem o pedido (asked): %q", text)
+	}
+	return summary, nil
+}
+
+// Writer grava o resumo das conversas concluídas na memória
+type Writer struct {
+	summarizer Summarizer
+	manager    memory.MemoryManager
+	AgentID    string   // Dono das memórias de resumo (padrão DefaultAgentID)
+	Importance float64  // Deve alcançar o ImportanceThreshold da memória para ir ao longo prazo (padrão DefaultImportance)
+	Tags       []string // Acrescentadas a Tag e ao ID do workflow
+}
+
+// NewWriter cria o gravador de resumos
+func NewWriter(summarizer Summarizer, manager memory.MemoryManager) *Writer {
+	return &Writer{summarizer: summarizer, manager: manager, AgentID: DefaultAgentID, Importance: DefaultImportance}
+}
+
+// Write resume a transcrição e grava o resumo como memória de longo prazo, com a proveniência
+// do workflow. Transcrições vazias não geram memória.
+func (w *Writer) Write(ctx context.Context, transcript Transcript) (*memory.Memory, error) {
+	if len(transcript.Entries) == 0 {
+		return nil, nil
+	}
+	summary, err := w.summarizer.Summarize(ctx, transcript)
+	if err != nil {
+		return nil, err
+	}
+
+	tags := append([]string{Tag}, w.Tags...)
+	if transcript.WorkflowID != "" {
+		tags = append(tags, transcript.WorkflowID)
+		if memory.ProvenanceFromContext(ctx).IsZero() {
+			ctx = memory.WithProvenance(ctx, memory.Provenance{WorkflowID: transcript.WorkflowID})
+		}
+	}
+	now := time.Now()
+	mem := &memory.Memory{
+		ID:         fmt.Sprintf("recap_%s_%d", w.AgentID, now.UnixNano()),
+		AgentID:    w.AgentID,
+		Type:       memory.LongTerm,
+		Content:    summary.Content(transcript),
+		Importance: w.Importance,
+		Timestamp:  now,
+		Tags:       tags,
+		Metadata:   summary,
+	}
+	if err := w.manager.StoreMemory(ctx, mem); err != nil {
+		return nil, fmt.Errorf("erro ao gravar o resumo do workflow %s: %w", transcript.WorkflowID, err)
+	}
+	return mem, nil
+}
@@ -0,0 +1,88 @@
+package recap
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/suissa/HiveMind/agents/llm"
+	"github.com/suissa/HiveMind/agents/memory"
+)
+
+// recordingManager guarda as memórias gravadas
+type recordingManager struct {
+	memory.MemoryManager
+	stored []*memory.Memory
+	ctx    context.Context
+}
+
+func (m *recordingManager) StoreMemory(ctx context.Context, mem *memory.Memory) error {
+	m.ctx = ctx
+	m.stored = append(m.stored, mem)
+	return nil
+}
+
+func TestWriterStoresCompactSummary(t *testing.T) {
+	var prompt string
+	summarizer := NewLLMSummarizer(llm.ProviderFunc(func(ctx context.Context, req llm.Request) (*llm.Response, error) {
+		prompt = req.Prompt
+		return &llm.Response{Text: "```json\n" + `{"asked": "lançar a campanha de verão", "decided": ["foco em Instagram"], "open_questions": ["orçamento de mídia"]}` + "\n```"}, nil
+	}), "small")
+	manager := &recordingManager{}
+	writer := NewWriter(summarizer, manager)
+
+	mem, err := writer.Write(context.Background(), Transcript{
+		WorkflowID: "verao",
+		Objective:  "campanha de verão",
+		Entries: []Entry{
+			{Speaker: "estrategista", Text: "Sugiro focar em Instagram."},
+			{Speaker: "redator", Text: "Concordo; falta definir o orçamento."},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(prompt, "[redator] Concordo") || !strings.Contains(prompt, "campanha de verão") {
+		t.Fatalf("transcrição ausente do prompt: %s", prompt)
+	}
+	if len(manager.stored) != 1 || manager.stored[0] != mem {
+		t.Fatalf("memórias gravadas: %+v", manager.stored)
+	}
+	if mem.Type != memory.LongTerm || mem.Importance != DefaultImportance || mem.AgentID != DefaultAgentID {
+		t.Fatalf("memória inesperada: %+v", mem)
+	}
+	for _, want := range []string{"Pedido: lançar a campanha de verão", "- foco em Instagram", "Questões em aberto:\n- orçamento de mídia"} {
+		if !strings.Contains(mem.Content, want) {
+			t.Fatalf("conteúdo sem %q:\n%s", want, mem.Content)
+		}
+	}
+	if p := memory.ProvenanceFromContext(manager.ctx); p.WorkflowID != "verao" {
+		t.Fatalf("proveniência = %+v", p)
+	}
+}
+
+func TestWriterSkipsEmptyTranscript(t *testing.T) {
+	manager := &recordingManager{}
+	writer := NewWriter(nil, manager)
+	mem, err := writer.Write(context.Background(), Transcript{WorkflowID: "vazio"})
+	if err != nil || mem != nil || len(manager.stored) != 0 {
+		t.Fatalf("transcrição vazia gerou memória: %v %v", mem, err)
+	}
+}
+
+func TestTranscriptKeepsLatestEntries(t *testing.T) {
+	transcript := Transcript{Entries: []Entry{
+		{Speaker: "a", Text: strings.Repeat("x", 50)},
+		{Speaker: "b", Text: "decisão final"},
+	}}
+	text := transcript.String(30)
+	if text != "[b] decisão final" {
+		t.Fatalf("transcrição cortada = %q", text)
+	}
+}
+
+func TestParseRejectsSummaryWithoutRequest(t *testing.T) {
+	if _, err := parse(`{"decided": ["x"]}`); err == nil {
+		t.Fatal("esperava erro sem asked")
+	}
+}
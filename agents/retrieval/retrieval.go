@@ -0,0 +1,117 @@
+// Package retrieval ordena as memórias recuperadas para o prompt combinando similaridade com a
+// consulta, recência e importância: score = w1·similaridade + w2·recência + w3·importância.
+// Com pesos por agente, um agente pode preferir contexto recente e importante a memórias
+// antigas apenas parecidas com a tarefa.
+package retrieval
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/suissa/HiveMind/agents/errs"
+	"github.com/suissa/HiveMind/agents/memory"
+)
+
+// Valores padrão da ponderação
+const (
+	DefaultHalfLife   = 7 * 24 * time.Hour
+	DefaultCandidates = 3
+)
+
+// Weights define os pesos do ranking. A recência decai exponencialmente: vale 1 para uma
+// memória recém-gravada e 0.5 após HalfLife.
+type Weights struct {
+	Similarity float64       `json:"similarity" yaml:"similarity"`
+	Recency    float64       `json:"recency" yaml:"recency"`
+	Importance float64       `json:"importance" yaml:"importance"`
+	HalfLife   time.Duration `json:"half_life,omitempty" yaml:"half_life,omitempty"` // Zero usa DefaultHalfLife
+	// Candidates multiplica o limite da busca por similaridade: o ranking escolhe entre
+	// limit·Candidates memórias (zero usa DefaultCandidates)
+	Candidates int `json:"candidates,omitempty" yaml:"candidates,omitempty"`
+}
+
+// DefaultWeights privilegia a similaridade, com recência e importância como desempate
+func DefaultWeights() Weights {
+	return Weights{Similarity: 0.6, Recency: 0.2, Importance: 0.2, HalfLife: DefaultHalfLife, Candidates: DefaultCandidates}
+}
+
+// Validate verifica os pesos
+func (w Weights) Validate() error {
+	switch {
+	case w.Similarity < 0 || w.Recency < 0 || w.Importance < 0:
+		return errs.New(errs.ErrValidation, "retrieval.Weights", "os pesos não podem ser negativos")
+	case w.Similarity+w.Recency+w.Importance == 0:
+		return errs.New(errs.ErrValidation, "retrieval.Weights", "ao menos um peso deve ser positivo")
+	case w.HalfLife < 0 || w.Candidates < 0:
+		return errs.New(errs.ErrValidation, "retrieval.Weights", "half_life e candidates não podem ser negativos")
+	}
+	return nil
+}
+
+// CandidateLimit retorna quantas memórias buscar para escolher as limit melhores
+func (w Weights) CandidateLimit(limit int) int {
+	candidates := w.Candidates
+	if candidates <= 0 {
+		candidates = DefaultCandidates
+	}
+	return limit * candidates
+}
+
+// Scored é uma memória com a sua pontuação no ranking
+type Scored struct {
+	Memory     *memory.Memory
+	Similarity float64
+	Recency    float64
+	Score      float64 // Combinação ponderada, normalizada pela soma dos pesos (entre 0 e 1)
+}
+
+// recency retorna a recência de uma memória com a idade informada
+func (w Weights) recency(age time.Duration) float64 {
+	halfLife := w.HalfLife
+	if halfLife <= 0 {
+		halfLife = DefaultHalfLife
+	}
+	if age <= 0 {
+		return 1
+	}
+	return math.Exp(-math.Ln2 * float64(age) / float64(halfLife))
+}
+
+// Rank pontua as memórias e as retorna da maior para a menor pontuação. As memórias devem vir
+// da busca por similaridade: sem Similarity preenchida (gerenciadores sem pontuação), a
+// similaridade é derivada da posição no resultado.
+func Rank(memories []*memory.Memory, weights Weights, now time.Time) []Scored {
+	scored := make([]Scored, len(memories))
+	if len(memories) == 0 {
+		return scored
+	}
+	positional := true
+	for _, m := range memories {
+		if m.Similarity > 0 {
+			positional = false
+			break
+		}
+	}
+	total := weights.Similarity + weights.Recency + weights.Importance
+	if total == 0 {
+		total = 1
+	}
+	for i, m := range memories {
+		similarity := m.Similarity
+		if positional {
+			similarity = 1 - float64(i)/float64(len(memories))
+		}
+		recency := weights.recency(now.Sub(m.Timestamp))
+		scored[i] = Scored{
+			Memory:     m,
+			Similarity: similarity,
+			Recency:    recency,
+			Score:      (weights.Similarity*similarity + weights.Recency*recency + weights.Importance*m.Importance) / total,
+		}
+	}
+	sort.SliceStable(scored, func(i, j int) bool {
+		return scored[i].Score > scored[j].Score
+	})
+	return scored
+}
@@ -0,0 +1,62 @@
+package retrieval
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/suissa/HiveMind/agents/memory"
+)
+
+func TestRankPrefersFreshImportantMemories(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	old := &memory.Memory{ID: "antiga", Similarity: 0.9, Importance: 0.2, Timestamp: now.Add(-30 * 24 * time.Hour)}
+	fresh := &memory.Memory{ID: "recente", Similarity: 0.8, Importance: 0.9, Timestamp: now.Add(-time.Hour)}
+
+	bySimilarity := Rank([]*memory.Memory{old, fresh}, Weights{Similarity: 1}, now)
+	if bySimilarity[0].Memory != old {
+		t.Fatalf("só com similaridade a memória antiga deveria vir primeiro: %+v", bySimilarity)
+	}
+
+	ranked := Rank([]*memory.Memory{old, fresh}, DefaultWeights(), now)
+	if ranked[0].Memory != fresh {
+		t.Fatalf("a memória recente e importante deveria vir primeiro: %+v", ranked)
+	}
+	if ranked[0].Score <= 0 || ranked[0].Score > 1 {
+		t.Fatalf("pontuação fora de [0, 1]: %v", ranked[0].Score)
+	}
+}
+
+func TestRecencyHalvesAtHalfLife(t *testing.T) {
+	now := time.Now()
+	weights := Weights{Recency: 1, HalfLife: 24 * time.Hour}
+	ranked := Rank([]*memory.Memory{{Timestamp: now.Add(-24 * time.Hour)}}, weights, now)
+	if math.Abs(ranked[0].Recency-0.5) > 1e-9 {
+		t.Fatalf("recência após a meia-vida = %v", ranked[0].Recency)
+	}
+}
+
+func TestRankWithoutScoresUsesPosition(t *testing.T) {
+	now := time.Now()
+	first := &memory.Memory{ID: "1", Timestamp: now}
+	second := &memory.Memory{ID: "2", Timestamp: now}
+	ranked := Rank([]*memory.Memory{first, second}, Weights{Similarity: 1}, now)
+	if ranked[0].Memory != first || ranked[0].Similarity != 1 || ranked[1].Similarity != 0.5 {
+		t.Fatalf("similaridade posicional inesperada: %+v", ranked)
+	}
+}
+
+func TestWeightsValidate(t *testing.T) {
+	if err := (Weights{}).Validate(); err == nil {
+		t.Fatal("esperava erro sem pesos")
+	}
+	if err := (Weights{Similarity: -1, Recency: 2}).Validate(); err == nil {
+		t.Fatal("esperava erro com peso negativo")
+	}
+	if err := DefaultWeights().Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if got := DefaultWeights().CandidateLimit(5); got != 15 {
+		t.Fatalf("CandidateLimit = %d", got)
+	}
+}
@@ -0,0 +1,142 @@
+package agents
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+)
+
+// rewardRate é o peso de cada recompensa nas médias móveis de success_rate e dos pesos de prompt
+const rewardRate = 0.2
+
+// promptWeightPrefix prefixa em PerformanceStats o peso de cada versão de prompt
+const promptWeightPrefix = "prompt_weight_"
+
+// contributionKey é a chave em Task.Output com a Contribution da execução
+const contributionKey = "contribution"
+
+// Outcome é o resultado final de um workflow, vindo de aprovação humana ou de uma métrica
+type Outcome struct {
+	Reward float64 // Entre -1 (rejeitado) e 1 (aceito)
+	Source string  // Origem do sinal (ex.: "human", "ctr")
+	Reason string  // Justificativa (opcional)
+}
+
+// Accepted é o resultado de um workflow aprovado
+func Accepted(source string) Outcome {
+	return Outcome{Reward: 1, Source: source}
+}
+
+// Rejected é o resultado de um workflow rejeitado
+func Rejected(source, reason string) Outcome {
+	return Outcome{Reward: -1, Source: source, Reason: reason}
+}
+
+// MetricOutcome converte uma métrica downstream em recompensa: atingir a meta vale 1,
+// metade da meta vale 0 e zero vale -1
+func MetricOutcome(source string, value, target float64) Outcome {
+	if target <= 0 {
+		return Outcome{Source: source}
+	}
+	return Outcome{Reward: clampReward(2*value/target - 1), Source: source}
+}
+
+// MemoryRef identifica uma memória incluída no prompt de uma tarefa
+type MemoryRef struct {
+	AgentID string `json:"agent_id"`
+	ID      string `json:"id"`
+}
+
+// Contribution registra como um agente contribuiu para um workflow: a tarefa executada,
+// a versão do prompt usada, as memórias recuperadas e as ferramentas chamadas. É a
+// proveniência do resultado da tarefa.
+type Contribution struct {
+	AgentID       string      `json:"agent_id"`
+	TaskID        string      `json:"task_id"`
+	WorkflowID    string      `json:"workflow_id,omitempty"`
+	PromptVersion string      `json:"prompt_version"`
+	Memories      []MemoryRef `json:"memories,omitempty"`
+	ToolCalls     []string    `json:"tool_calls,omitempty"`
+}
+
+// promptVersion identifica a versão atual do prompt do agente (backstory e exemplos few-shot)
+func (a *CognitiveAgent) promptVersion() string {
+	h := sha256.New()
+	h.Write([]byte(a.Backstory))
+	h.Write([]byte{0})
+	h.Write([]byte(a.PromptTemplates[fewShotTemplate]))
+	return hex.EncodeToString(h.Sum(nil))[:8]
+}
+
+// PromptWeight retorna o peso acumulado de uma versão de prompt (0.5 sem recompensas)
+func (a *CognitiveAgent) PromptWeight(version string) float64 {
+	if weight, ok := a.PerformanceStats[promptWeightPrefix+version]; ok {
+		return weight
+	}
+	return 0.5
+}
+
+// Reinforce aplica a recompensa do workflow à contribuição do agente: atualiza a
+// success_rate e o peso da versão de prompt usada (médias móveis) e a importância das
+// memórias que foram incluídas no prompt
+func (a *CognitiveAgent) Reinforce(ctx context.Context, contribution Contribution, outcome Outcome) error {
+	reward := clampReward(outcome.Reward)
+	target := (reward + 1) / 2
+
+	successRate := a.PerformanceStats["success_rate"]
+	a.PerformanceStats["success_rate"] = successRate + rewardRate*(target-successRate)
+
+	if contribution.PromptVersion != "" {
+		weight := a.PromptWeight(contribution.PromptVersion)
+		a.PerformanceStats[promptWeightPrefix+contribution.PromptVersion] = weight + rewardRate*(target-weight)
+	}
+
+	if a.memoryManager == nil || len(contribution.Memories) == 0 {
+		return nil
+	}
+	var failed []error
+	for _, ref := range contribution.Memories {
+		m, err := a.memoryManager.GetMemory(a.scope(ctx), ref.AgentID, ref.ID)
+		if err != nil {
+			failed = append(failed, err)
+			continue
+		}
+		m.Importance = clampImportance(m.Importance + rewardRate*reward)
+		if err := a.memoryManager.UpdateMemory(a.scope(ctx), m); err != nil {
+			failed = append(failed, err)
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("erro ao atualizar a importância de %d memórias: %v", len(failed), failed)
+	}
+	return nil
+}
+
+// contributionOf retorna a contribuição registrada por Run na tarefa
+func contributionOf(task *Task) (Contribution, bool) {
+	contribution, ok := task.Output[contributionKey].(Contribution)
+	return contribution, ok
+}
+
+// clampReward limita a recompensa ao intervalo [-1, 1]
+func clampReward(reward float64) float64 {
+	if reward < -1 {
+		return -1
+	}
+	if reward > 1 {
+		return 1
+	}
+	return reward
+}
+
+// clampImportance limita a importância ao intervalo [0, 1]
+func clampImportance(importance float64) float64 {
+	if importance < 0 {
+		return 0
+	}
+	if importance > 1 {
+		return 1
+	}
+	return importance
+}
@@ -0,0 +1,176 @@
+// Package rollout controla a implantação blue/green de uma nova versão de agente: divide o
+// tráfego por porcentagem, compara taxa de erro e latência das duas versões e promove ou
+// reverte a nova versão automaticamente.
+package rollout
+
+import (
+	"hash/fnv"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// Variant identifica a versão que atende uma tarefa
+type Variant string
+
+const (
+	Blue  Variant = "blue"  // Versão atual
+	Green Variant = "green" // Nova versão
+)
+
+// State é o estado do rollout
+type State string
+
+const (
+	Active     State = "active"      // Tráfego dividido entre as versões
+	Promoted   State = "promoted"    // Nova versão atende todo o tráfego
+	RolledBack State = "rolled_back" // Versão atual atende todo o tráfego
+)
+
+// Config define a divisão do tráfego e os critérios de decisão
+type Config struct {
+	Percent            float64 // Fração do tráfego enviada à nova versão (0 a 1)
+	MinSamples         int     // Execuções mínimas de cada versão antes de decidir (padrão 20)
+	MaxErrorIncrease   float64 // Aumento tolerado na taxa de erro (padrão 0.05)
+	MaxLatencyIncrease float64 // Aumento relativo tolerado na latência média (padrão 0.2)
+}
+
+// Stats acumula as execuções de uma versão
+type Stats struct {
+	Runs         int           `json:"runs"`
+	Failures     int           `json:"failures"`
+	TotalLatency time.Duration `json:"total_latency"`
+}
+
+// ErrorRate retorna a fração de execuções com erro
+func (s Stats) ErrorRate() float64 {
+	if s.Runs == 0 {
+		return 0
+	}
+	return float64(s.Failures) / float64(s.Runs)
+}
+
+// MeanLatency retorna a latência média das execuções
+func (s Stats) MeanLatency() time.Duration {
+	if s.Runs == 0 {
+		return 0
+	}
+	return s.TotalLatency / time.Duration(s.Runs)
+}
+
+// Controller decide qual versão atende cada tarefa e quando encerrar o rollout
+type Controller struct {
+	config Config
+	stats  map[Variant]*Stats
+	state  State
+	mu     sync.Mutex
+}
+
+// New cria um controlador de rollout, aplicando os padrões da configuração
+func New(config Config) *Controller {
+	if config.MinSamples <= 0 {
+		config.MinSamples = 20
+	}
+	if config.MaxErrorIncrease <= 0 {
+		config.MaxErrorIncrease = 0.05
+	}
+	if config.MaxLatencyIncrease <= 0 {
+		config.MaxLatencyIncrease = 0.2
+	}
+	return &Controller{
+		config: config,
+		stats:  map[Variant]*Stats{Blue: {}, Green: {}},
+		state:  Active,
+	}
+}
+
+// Route escolhe a versão para a chave (ex.: ID da tarefa). A mesma chave vai sempre para a
+// mesma versão enquanto o rollout está ativo; sem chave a escolha é aleatória.
+func (c *Controller) Route(key string) Variant {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	switch c.state {
+	case Promoted:
+		return Green
+	case RolledBack:
+		return Blue
+	}
+
+	var bucket float64
+	if key == "" {
+		bucket = rand.Float64()
+	} else {
+		h := fnv.New32a()
+		h.Write([]byte(key))
+		bucket = float64(h.Sum32()%10000) / 10000
+	}
+	if bucket < c.config.Percent {
+		return Green
+	}
+	return Blue
+}
+
+// Record registra uma execução e reavalia o rollout, retornando o estado resultante
+func (c *Controller) Record(variant Variant, latency time.Duration, err error) State {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stats := c.stats[variant]
+	stats.Runs++
+	stats.TotalLatency += latency
+	if err != nil {
+		stats.Failures++
+	}
+
+	if c.state == Active {
+		c.state = c.decide()
+	}
+	return c.state
+}
+
+// decide compara as versões quando ambas têm amostras suficientes: a nova versão é revertida
+// se a taxa de erro subir além do tolerado e promovida se também não piorar a latência
+func (c *Controller) decide() State {
+	blue, green := c.stats[Blue], c.stats[Green]
+	if blue.Runs < c.config.MinSamples || green.Runs < c.config.MinSamples {
+		return Active
+	}
+
+	if green.ErrorRate() > blue.ErrorRate()+c.config.MaxErrorIncrease {
+		return RolledBack
+	}
+	maxLatency := float64(blue.MeanLatency()) * (1 + c.config.MaxLatencyIncrease)
+	if float64(green.MeanLatency()) > maxLatency {
+		return RolledBack
+	}
+	return Promoted
+}
+
+// Promote encerra o rollout enviando todo o tráfego à nova versão
+func (c *Controller) Promote() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.state = Promoted
+}
+
+// Rollback encerra o rollout mantendo a versão atual
+func (c *Controller) Rollback() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.state = RolledBack
+}
+
+// State retorna o estado do rollout
+func (c *Controller) State() State {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.state
+}
+
+// Stats retorna as estatísticas acumuladas de uma versão
+func (c *Controller) Stats(variant Variant) Stats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return *c.stats[variant]
+}
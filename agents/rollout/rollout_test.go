@@ -0,0 +1,76 @@
+package rollout
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestRouteSplitsTrafficDeterministically(t *testing.T) {
+	c := New(Config{Percent: 0.25})
+
+	green := 0
+	for i := 0; i < 4000; i++ {
+		key := fmt.Sprintf("task-%d", i)
+		variant := c.Route(key)
+		if variant != c.Route(key) {
+			t.Fatalf("a chave %s deve ir sempre para a mesma versão", key)
+		}
+		if variant == Green {
+			green++
+		}
+	}
+	if share := float64(green) / 4000; share < 0.2 || share > 0.3 {
+		t.Fatalf("fração da nova versão %.2f, esperado ~0.25", share)
+	}
+}
+
+func TestPromoteWhenGreenIsNotWorse(t *testing.T) {
+	c := New(Config{Percent: 0.5, MinSamples: 5})
+	for i := 0; i < 5; i++ {
+		c.Record(Blue, 100*time.Millisecond, nil)
+		if state := c.Record(Green, 90*time.Millisecond, nil); i < 4 && state != Active {
+			t.Fatalf("não deve decidir antes de %d amostras: %s", 5, state)
+		}
+	}
+	if c.State() != Promoted || c.Route("qualquer") != Green {
+		t.Fatalf("esperava promoção, estado %s", c.State())
+	}
+}
+
+func TestRollbackOnErrorsOrLatency(t *testing.T) {
+	failing := New(Config{Percent: 0.5, MinSamples: 4})
+	for i := 0; i < 4; i++ {
+		failing.Record(Blue, time.Millisecond, nil)
+		var err error
+		if i%2 == 0 {
+			err = fmt.Errorf("falha")
+		}
+		failing.Record(Green, time.Millisecond, err)
+	}
+	if failing.State() != RolledBack || failing.Route("qualquer") != Blue {
+		t.Fatalf("esperava reversão por erros, estado %s", failing.State())
+	}
+
+	slow := New(Config{Percent: 0.5, MinSamples: 2})
+	for i := 0; i < 2; i++ {
+		slow.Record(Blue, 100*time.Millisecond, nil)
+		slow.Record(Green, 200*time.Millisecond, nil)
+	}
+	if slow.State() != RolledBack {
+		t.Fatalf("esperava reversão por latência, estado %s", slow.State())
+	}
+}
+
+func TestManualDecisionStopsEvaluation(t *testing.T) {
+	c := New(Config{Percent: 0.5, MinSamples: 1})
+	c.Rollback()
+	c.Record(Blue, time.Millisecond, nil)
+	if state := c.Record(Green, time.Millisecond, errors.New("x")); state != RolledBack {
+		t.Fatalf("decisão manual não deve ser reavaliada: %s", state)
+	}
+	if stats := c.Stats(Green); stats.Runs != 1 || stats.ErrorRate() != 1 {
+		t.Fatalf("estatísticas inesperadas: %#v", stats)
+	}
+}
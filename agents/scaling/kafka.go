@@ -0,0 +1,74 @@
+package scaling
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Shopify/sarama"
+)
+
+// KafkaLag lê o atraso (lag) do grupo de consumidores nos tópicos: a soma, por partição, das
+// mensagens após o último offset confirmado pelo grupo. O lag de cada tópico é a sua
+// profundidade.
+type KafkaLag struct {
+	client sarama.Client
+	admin  sarama.ClusterAdmin
+	group  string
+	topics []string
+}
+
+// NewKafkaLag cria a fonte de profundidade para o grupo de consumidores e os tópicos
+// informados. O cliente é compartilhado com o admin: fechá-lo encerra os dois.
+func NewKafkaLag(client sarama.Client, group string, topics ...string) (*KafkaLag, error) {
+	admin, err := sarama.NewClusterAdminFromClient(client)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao criar admin do Kafka: %v", err)
+	}
+	return &KafkaLag{client: client, admin: admin, group: group, topics: topics}, nil
+}
+
+// QueueDepths implementa DepthSource
+func (k *KafkaLag) QueueDepths(ctx context.Context) (map[string]int, error) {
+	partitions := make(map[string][]int32, len(k.topics))
+	for _, topic := range k.topics {
+		ids, err := k.client.Partitions(topic)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao listar partições do tópico %s: %w", topic, err)
+		}
+		partitions[topic] = ids
+	}
+
+	offsets, err := k.admin.ListConsumerGroupOffsets(k.group, partitions)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao consultar offsets do grupo %s: %w", k.group, err)
+	}
+
+	depths := make(map[string]int, len(partitions))
+	for topic, ids := range partitions {
+		depths[topic] = 0
+		for _, partition := range ids {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			newest, err := k.client.GetOffset(topic, partition, sarama.OffsetNewest)
+			if err != nil {
+				return nil, fmt.Errorf("erro ao consultar offset do tópico %s/%d: %w", topic, partition, err)
+			}
+
+			// Sem offset confirmado, o grupo ainda consumirá a partição desde o início
+			committed := int64(-1)
+			if block := offsets.GetBlock(topic, partition); block != nil {
+				committed = block.Offset
+			}
+			if committed < 0 {
+				if committed, err = k.client.GetOffset(topic, partition, sarama.OffsetOldest); err != nil {
+					return nil, fmt.Errorf("erro ao consultar offset do tópico %s/%d: %w", topic, partition, err)
+				}
+			}
+			if lag := newest - committed; lag > 0 {
+				depths[topic] += int(lag)
+			}
+		}
+	}
+	return depths, nil
+}
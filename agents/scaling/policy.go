@@ -0,0 +1,199 @@
+package scaling
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"time"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/suissa/HiveMind/agents/errs"
+)
+
+// Thresholds são os limites por instância que disparam uma nova instância. Zero desativa o
+// limite.
+type Thresholds struct {
+	CPU       float64 `json:"cpu,omitempty" yaml:"cpu,omitempty"`               // Uso médio de CPU (%)
+	Memory    float64 `json:"memory,omitempty" yaml:"memory,omitempty"`         // Uso médio de memória
+	Tasks     float64 `json:"tasks,omitempty" yaml:"tasks,omitempty"`           // Tarefas na fila por instância
+	ErrorRate float64 `json:"error_rate,omitempty" yaml:"error_rate,omitempty"` // Taxa média de erro
+}
+
+// Policy é a política de escalonamento de um tipo de agente
+type Policy struct {
+	MinInstances int           `json:"min_instances,omitempty" yaml:"min_instances,omitempty"` // Padrão 1
+	MaxInstances int           `json:"max_instances,omitempty" yaml:"max_instances,omitempty"` // Zero não limita
+	Cooldown     time.Duration `json:"cooldown,omitempty" yaml:"cooldown,omitempty"`           // Intervalo mínimo entre escalonamentos
+	Thresholds   Thresholds    `json:"thresholds,omitempty" yaml:"thresholds,omitempty"`
+
+	// TargetTasksPerInstance ativa o target tracking: o número de instâncias acompanha a fila
+	// para manter cerca desse número de tarefas por instância, aumentando ou reduzindo
+	TargetTasksPerInstance float64 `json:"target_tasks_per_instance,omitempty" yaml:"target_tasks_per_instance,omitempty"`
+}
+
+// DefaultPolicy retorna a política com os limites globais do pacote
+func DefaultPolicy() Policy {
+	return Policy{
+		MinInstances: 1,
+		Cooldown:     CooldownPeriod * time.Second,
+		Thresholds: Thresholds{
+			CPU:       CPUThreshold,
+			Memory:    MemoryThreshold,
+			Tasks:     TasksThreshold,
+			ErrorRate: ErrorThreshold,
+		},
+	}
+}
+
+// Validate verifica a consistência da política
+func (p Policy) Validate() error {
+	switch {
+	case p.MinInstances < 0 || p.MaxInstances < 0:
+		return errs.New(errs.ErrValidation, "scaling.Policy", "número de instâncias negativo")
+	case p.MaxInstances > 0 && p.MinInstances > p.MaxInstances:
+		return errs.New(errs.ErrValidation, "scaling.Policy", "min_instances (%d) maior que max_instances (%d)", p.MinInstances, p.MaxInstances)
+	case p.Cooldown < 0 || p.TargetTasksPerInstance < 0:
+		return errs.New(errs.ErrValidation, "scaling.Policy", "cooldown e target_tasks_per_instance não podem ser negativos")
+	}
+	return nil
+}
+
+// merge completa a política com os campos não definidos da base
+func (p Policy) merge(base Policy) Policy {
+	if p.MinInstances == 0 {
+		p.MinInstances = base.MinInstances
+	}
+	if p.MaxInstances == 0 {
+		p.MaxInstances = base.MaxInstances
+	}
+	if p.Cooldown == 0 {
+		p.Cooldown = base.Cooldown
+	}
+	if p.TargetTasksPerInstance == 0 {
+		p.TargetTasksPerInstance = base.TargetTasksPerInstance
+	}
+	if p.Thresholds.CPU == 0 {
+		p.Thresholds.CPU = base.Thresholds.CPU
+	}
+	if p.Thresholds.Memory == 0 {
+		p.Thresholds.Memory = base.Thresholds.Memory
+	}
+	if p.Thresholds.Tasks == 0 {
+		p.Thresholds.Tasks = base.Thresholds.Tasks
+	}
+	if p.Thresholds.ErrorRate == 0 {
+		p.Thresholds.ErrorRate = base.Thresholds.ErrorRate
+	}
+	return p
+}
+
+// Sample são as métricas atuais das instâncias de um tipo de agente
+type Sample struct {
+	Instances    int
+	CPU          float64 // Média por instância
+	Memory       float64 // Média por instância
+	TasksInQueue int     // Total na fila do tipo de agente
+	ErrorRate    float64 // Média por instância
+}
+
+// Desired retorna o número de instâncias desejado para as métricas: o target tracking
+// dimensiona pela fila e um limite excedido acrescenta uma instância. O resultado respeita
+// MinInstances e MaxInstances.
+func (p Policy) Desired(s Sample) int {
+	desired := s.Instances
+	if p.TargetTasksPerInstance > 0 {
+		desired = int(math.Ceil(float64(s.TasksInQueue) / p.TargetTasksPerInstance))
+	}
+
+	perInstance := float64(s.TasksInQueue)
+	if s.Instances > 0 {
+		perInstance /= float64(s.Instances)
+	}
+	t := p.Thresholds
+	if (t.CPU > 0 && s.CPU > t.CPU) || (t.Memory > 0 && s.Memory > t.Memory) ||
+		(t.Tasks > 0 && perInstance > t.Tasks) || (t.ErrorRate > 0 && s.ErrorRate > t.ErrorRate) {
+		if desired < s.Instances+1 {
+			desired = s.Instances + 1
+		}
+	}
+
+	min := p.MinInstances
+	if min < 1 {
+		min = 1
+	}
+	if desired < min {
+		desired = min
+	}
+	if p.MaxInstances > 0 && desired > p.MaxInstances {
+		desired = p.MaxInstances
+	}
+	return desired
+}
+
+// Decide aplica o cooldown a Desired: dentro do cooldown do último escalonamento o número de
+// instâncias é mantido. Retorna o número desejado e se ele difere do atual.
+func (p Policy) Decide(s Sample, lastScaled, now time.Time) (int, bool) {
+	desired := p.Desired(s)
+	if desired == s.Instances {
+		return desired, false
+	}
+	// Abaixo do mínimo ou acima do máximo a correção não espera o cooldown
+	outOfBounds := s.Instances < p.MinInstances || (p.MaxInstances > 0 && s.Instances > p.MaxInstances)
+	if !outOfBounds && !lastScaled.IsZero() && now.Sub(lastScaled) < p.Cooldown {
+		return s.Instances, false
+	}
+	return desired, true
+}
+
+// Policies são as políticas por tipo de agente, com uma política padrão para os demais
+type Policies struct {
+	Default Policy            `json:"default" yaml:"default"`
+	Agents  map[string]Policy `json:"agents" yaml:"agents"`
+}
+
+// For retorna a política do tipo de agente, completada pela padrão e por DefaultPolicy
+func (p *Policies) For(agentType string) Policy {
+	if p == nil {
+		return DefaultPolicy()
+	}
+	base := p.Default.merge(DefaultPolicy())
+	if policy, ok := p.Agents[agentType]; ok {
+		return policy.merge(base)
+	}
+	return base
+}
+
+// Validate verifica as políticas já completadas de cada tipo de agente
+func (p *Policies) Validate() error {
+	if err := p.For("").Validate(); err != nil {
+		return fmt.Errorf("política padrão: %w", err)
+	}
+	for agentType := range p.Agents {
+		if err := p.For(agentType).Validate(); err != nil {
+			return fmt.Errorf("política do agente %s: %w", agentType, err)
+		}
+	}
+	return nil
+}
+
+// ParsePolicies lê as políticas em YAML (default e agents: {tipo: política})
+func ParsePolicies(data []byte) (*Policies, error) {
+	var policies Policies
+	if err := yaml.UnmarshalStrict(data, &policies); err != nil {
+		return nil, errs.Wrap(errs.ErrValidation, "scaling.ParsePolicies", err, "políticas de escalonamento inválidas")
+	}
+	if err := policies.Validate(); err != nil {
+		return nil, err
+	}
+	return &policies, nil
+}
+
+// LoadPolicies carrega as políticas de um arquivo YAML
+func LoadPolicies(filename string) (*Policies, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler políticas de escalonamento: %v", err)
+	}
+	return ParsePolicies(data)
+}
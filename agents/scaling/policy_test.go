@@ -0,0 +1,87 @@
+package scaling
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/suissa/HiveMind/agents/errs"
+)
+
+const policiesYAML = `
+default:
+  cooldown: 2m
+  max_instances: 5
+agents:
+  analyst:
+    min_instances: 2
+    max_instances: 10
+    target_tasks_per_instance: 20
+  writer:
+    thresholds:
+      cpu: 60
+`
+
+func TestPoliciesFor(t *testing.T) {
+	policies, err := ParsePolicies([]byte(policiesYAML))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	analyst := policies.For("analyst")
+	if analyst.MinInstances != 2 || analyst.MaxInstances != 10 || analyst.Cooldown != 2*time.Minute || analyst.Thresholds.Tasks != TasksThreshold {
+		t.Fatalf("política do analyst = %+v", analyst)
+	}
+	writer := policies.For("writer")
+	if writer.Thresholds.CPU != 60 || writer.Thresholds.Memory != MemoryThreshold || writer.MaxInstances != 5 {
+		t.Fatalf("política do writer = %+v", writer)
+	}
+	if other := policies.For("outro"); other.MinInstances != 1 || other.Cooldown != 2*time.Minute {
+		t.Fatalf("política padrão = %+v", other)
+	}
+
+	for _, invalid := range []string{"agents:\n  a:\n    min_instances: 3\n    max_instances: 2\n", "default:\n  cooldwn: 1m\n"} {
+		if _, err := ParsePolicies([]byte(invalid)); !errors.Is(err, errs.ErrValidation) {
+			t.Errorf("ParsePolicies(%q) deveria falhar com ErrValidation: %v", invalid, err)
+		}
+	}
+}
+
+func TestDesired(t *testing.T) {
+	tracking := Policy{MinInstances: 2, MaxInstances: 10, TargetTasksPerInstance: 20}
+	cases := []struct {
+		policy Policy
+		sample Sample
+		want   int
+	}{
+		// Target tracking aumenta e reduz pela fila, dentro de min e max
+		{tracking, Sample{Instances: 2, TasksInQueue: 95}, 5},
+		{tracking, Sample{Instances: 5, TasksInQueue: 10}, 2},
+		{tracking, Sample{Instances: 5, TasksInQueue: 1000}, 10},
+		// Um limite excedido acrescenta uma instância
+		{Policy{Thresholds: Thresholds{CPU: 80}}, Sample{Instances: 3, CPU: 90}, 4},
+		{Policy{Thresholds: Thresholds{Tasks: 100}}, Sample{Instances: 2, TasksInQueue: 150}, 2},
+		{Policy{Thresholds: Thresholds{Tasks: 100}, MaxInstances: 3}, Sample{Instances: 3, TasksInQueue: 900}, 3},
+	}
+	for _, c := range cases {
+		if got := c.policy.Desired(c.sample); got != c.want {
+			t.Errorf("Desired(%+v) com %+v = %d; esperava %d", c.sample, c.policy, got, c.want)
+		}
+	}
+}
+
+func TestDecideCooldown(t *testing.T) {
+	policy := Policy{MinInstances: 2, Cooldown: time.Minute, TargetTasksPerInstance: 10}
+	now := time.Now()
+
+	if n, ok := policy.Decide(Sample{Instances: 2, TasksInQueue: 50}, now.Add(-30*time.Second), now); ok || n != 2 {
+		t.Fatalf("dentro do cooldown = %d, %v; esperava manter 2", n, ok)
+	}
+	if n, ok := policy.Decide(Sample{Instances: 2, TasksInQueue: 50}, now.Add(-2*time.Minute), now); !ok || n != 5 {
+		t.Fatalf("após o cooldown = %d, %v; esperava 5", n, ok)
+	}
+	// Abaixo do mínimo a correção ignora o cooldown
+	if n, ok := policy.Decide(Sample{Instances: 1}, now, now); !ok || n != 2 {
+		t.Fatalf("abaixo do mínimo = %d, %v; esperava 2", n, ok)
+	}
+}
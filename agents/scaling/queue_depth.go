@@ -0,0 +1,109 @@
+package scaling
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// DepthSource informa a profundidade atual das filas (mensagens aguardando processamento),
+// o sinal usado para escalar os agentes que as consomem
+type DepthSource interface {
+	QueueDepths(ctx context.Context) (map[string]int, error)
+}
+
+// DepthSourceFunc adapta uma função a DepthSource
+type DepthSourceFunc func(ctx context.Context) (map[string]int, error)
+
+// QueueDepths implementa DepthSource
+func (f DepthSourceFunc) QueueDepths(ctx context.Context) (map[string]int, error) {
+	return f(ctx)
+}
+
+// Sources combina as profundidades de vários brokers (ex.: RabbitMQ e Kafka). As filas com o
+// mesmo nome em mais de um broker são somadas; a primeira falha interrompe a consulta.
+func Sources(sources ...DepthSource) DepthSource {
+	return DepthSourceFunc(func(ctx context.Context) (map[string]int, error) {
+		depths := make(map[string]int)
+		for _, source := range sources {
+			partial, err := source.QueueDepths(ctx)
+			if err != nil {
+				return nil, err
+			}
+			for queue, depth := range partial {
+				depths[queue] += depth
+			}
+		}
+		return depths, nil
+	})
+}
+
+// Depth soma a profundidade das filas informadas
+func Depth(depths map[string]int, queues ...string) int {
+	total := 0
+	for _, queue := range queues {
+		total += depths[queue]
+	}
+	return total
+}
+
+// RabbitMQManagement lê a profundidade das filas pela API HTTP do plugin de gerenciamento do
+// RabbitMQ. A profundidade é o total de mensagens da fila: prontas e entregues sem ack.
+type RabbitMQManagement struct {
+	URL      string // Ex.: http://localhost:15672
+	Username string
+	Password string
+	VHost    string // Vazio consulta as filas de todos os vhosts
+	Client   *http.Client
+}
+
+// NewRabbitMQManagement cria a fonte de profundidade para a API de gerenciamento informada
+func NewRabbitMQManagement(managementURL, username, password, vhost string) *RabbitMQManagement {
+	return &RabbitMQManagement{
+		URL:      strings.TrimRight(managementURL, "/"),
+		Username: username,
+		Password: password,
+		VHost:    vhost,
+		Client:   &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// QueueDepths implementa DepthSource
+func (m *RabbitMQManagement) QueueDepths(ctx context.Context) (map[string]int, error) {
+	endpoint := m.URL + "/api/queues"
+	if m.VHost != "" {
+		endpoint += "/" + url.PathEscape(m.VHost)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint+"?columns=name,messages", nil)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao criar requisição à API de gerenciamento do RabbitMQ: %v", err)
+	}
+	req.SetBasicAuth(m.Username, m.Password)
+
+	resp, err := m.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao consultar as filas do RabbitMQ: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("API de gerenciamento do RabbitMQ retornou status %d", resp.StatusCode)
+	}
+
+	var queues []struct {
+		Name     string `json:"name"`
+		Messages int    `json:"messages"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&queues); err != nil {
+		return nil, fmt.Errorf("resposta inválida da API de gerenciamento do RabbitMQ: %v", err)
+	}
+
+	depths := make(map[string]int, len(queues))
+	for _, queue := range queues {
+		depths[queue.Name] += queue.Messages
+	}
+	return depths, nil
+}
@@ -0,0 +1,51 @@
+package scaling
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRabbitMQManagement(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, pass, _ := r.BasicAuth(); user != "guest" || pass != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.URL.EscapedPath() != "/api/queues/%2F" {
+			t.Errorf("caminho inesperado: %s", r.URL.EscapedPath())
+		}
+		w.Write([]byte(`[{"name": "llm_tasks", "messages": 42}, {"name": "llm_results", "messages": 0}]`))
+	}))
+	defer server.Close()
+
+	depths, err := NewRabbitMQManagement(server.URL+"/", "guest", "secret", "/").QueueDepths(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if depths["llm_tasks"] != 42 || len(depths) != 2 {
+		t.Fatalf("profundidades = %v", depths)
+	}
+
+	if _, err := NewRabbitMQManagement(server.URL, "guest", "errada", "/").QueueDepths(context.Background()); err == nil {
+		t.Fatal("esperava erro com credenciais inválidas")
+	}
+}
+
+func TestSources(t *testing.T) {
+	rabbit := DepthSourceFunc(func(context.Context) (map[string]int, error) {
+		return map[string]int{"llm_tasks": 10, "metrics": 3}, nil
+	})
+	kafka := DepthSourceFunc(func(context.Context) (map[string]int, error) {
+		return map[string]int{"llm_tasks": 5, "events": 7}, nil
+	})
+
+	depths, err := Sources(rabbit, kafka).QueueDepths(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := Depth(depths, "llm_tasks", "events", "ausente"); got != 22 {
+		t.Fatalf("Depth = %d; esperava 22", got)
+	}
+}
@@ -0,0 +1,52 @@
+package agents
+
+import (
+	"context"
+
+	"github.com/suissa/HiveMind/agents/sheetbatch"
+	"github.com/suissa/HiveMind/agents/templates"
+)
+
+// SpreadsheetRunner executa na equipe o projeto montado a partir de cada linha da planilha;
+// as execuções são sequenciais, pois a equipe guarda o estado do workflow em andamento. Só
+// as respostas das tarefas do projeto da linha são retornadas.
+func SpreadsheetRunner(crew *MarketingCrew, project func(row sheetbatch.Row) (*MarketingProject, error)) sheetbatch.Runner {
+	return func(ctx context.Context, row sheetbatch.Row) (string, map[string]string, error) {
+		p, err := project(row)
+		if err != nil {
+			return "", nil, err
+		}
+		results, err := crew.ExecuteWorkflowContext(ctx, p)
+		if results == nil {
+			return "", nil, err
+		}
+		outputs := make(map[string]string, len(p.Tasks))
+		for _, task := range p.Tasks {
+			if output, ok := results.TaskOutputs[task.ID]; ok {
+				outputs[task.ID] = output
+			}
+		}
+		return results.Status, outputs, err
+	}
+}
+
+// TemplateProject monta o projeto de cada linha a partir do template embutido, com as
+// colunas como variáveis; as colunas que não são variáveis do template são ignoradas. Os
+// agentes do template devem estar registrados na equipe que executa as linhas.
+func TemplateProject(name string) func(row sheetbatch.Row) (*MarketingProject, error) {
+	return func(row sheetbatch.Row) (*MarketingProject, error) {
+		vars := make(map[string]string, len(row.Values))
+		if t, ok := templates.Get(name); ok {
+			for _, variable := range t.Variables {
+				if value, ok := row.Values[variable.Name]; ok {
+					vars[variable.Name] = value
+				}
+			}
+		}
+		workflow, err := InstantiateTemplate(name, vars)
+		if err != nil {
+			return nil, err
+		}
+		return workflow.Project, nil
+	}
+}
@@ -0,0 +1,173 @@
+// Package sheetbatch executa um workflow por linha de uma planilha — por exemplo, uma campanha
+// por produto —, com as colunas da linha como parâmetros. As linhas são executadas em ordem,
+// o andamento é informado a cada linha e os resultados podem ser gravados de volta como uma
+// nova aba, ao lado de um resumo da execução.
+package sheetbatch
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Situações de uma linha
+const (
+	StatusCompleted = "completed" // O workflow terminou
+	StatusTimeout   = "timeout"   // Alguma tarefa excedeu o prazo; as respostas são parciais
+	StatusFailed    = "failed"    // O workflow falhou
+	StatusSkipped   = "skipped"   // A linha não foi executada (StopOnError ou cancelamento)
+)
+
+// Row é uma linha de dados da planilha
+type Row struct {
+	Index  int               `json:"index"` // Posição entre as linhas de dados, a partir de 1
+	Values map[string]string `json:"values"`
+}
+
+// Sheet são as linhas de dados e os cabeçalhos, na ordem da planilha
+type Sheet struct {
+	Headers []string
+	Rows    []Row
+}
+
+// NewSheet monta as linhas a partir dos cabeçalhos e das células; as linhas totalmente
+// vazias são ignoradas, e as colunas sem cabeçalho recebem o nome da letra (ex.: "C")
+func NewSheet(headers []string, cells [][]string) Sheet {
+	sheet := Sheet{Headers: append([]string(nil), headers...)}
+	for _, row := range cells {
+		if strings.TrimSpace(strings.Join(row, "")) == "" {
+			continue
+		}
+		values := make(map[string]string, len(row))
+		for i, value := range row {
+			if i >= len(sheet.Headers) {
+				sheet.Headers = append(sheet.Headers, columnName(i))
+			}
+			if sheet.Headers[i] == "" {
+				sheet.Headers[i] = columnName(i)
+			}
+			values[sheet.Headers[i]] = value
+		}
+		sheet.Rows = append(sheet.Rows, Row{Index: len(sheet.Rows) + 1, Values: values})
+	}
+	return sheet
+}
+
+// columnName retorna a letra da coluna de índice i (0 é "A", 26 é "AA")
+func columnName(i int) string {
+	name := ""
+	for i++; i > 0; i = (i - 1) / 26 {
+		name = string(rune('A'+(i-1)%26)) + name
+	}
+	return name
+}
+
+// Runner executa o workflow de uma linha e retorna a situação (StatusCompleted ou
+// StatusTimeout) e a resposta de cada tarefa
+type Runner func(ctx context.Context, row Row) (status string, outputs map[string]string, err error)
+
+// Config configura a execução
+type Config struct {
+	StopOnError bool   `json:"stop_on_error,omitempty" yaml:"stop_on_error,omitempty"` // Ignora as linhas seguintes à primeira falha
+	KeyColumn   string `json:"key_column,omitempty" yaml:"key_column,omitempty"`       // Coluna que identifica a linha no andamento, ex.: "sku"
+}
+
+// RowResult é o resultado de uma linha
+type RowResult struct {
+	Row      Row               `json:"row"`
+	Key      string            `json:"key,omitempty"`
+	Status   string            `json:"status"`
+	Outputs  map[string]string `json:"outputs,omitempty"`
+	Error    string            `json:"error,omitempty"`
+	Duration time.Duration     `json:"duration"`
+}
+
+// Summary é o resumo da execução
+type Summary struct {
+	Headers   []string      `json:"headers"`
+	Results   []RowResult   `json:"results"`
+	Total     int           `json:"total"`
+	Completed int           `json:"completed"`
+	TimedOut  int           `json:"timed_out"`
+	Failed    int           `json:"failed"`
+	Skipped   int           `json:"skipped"`
+	StartedAt time.Time     `json:"started_at"`
+	Duration  time.Duration `json:"duration"`
+}
+
+// Tasks retorna as tarefas com resposta em alguma linha, em ordem alfabética
+func (s *Summary) Tasks() []string {
+	seen := make(map[string]bool)
+	var tasks []string
+	for _, result := range s.Results {
+		for task := range result.Outputs {
+			if !seen[task] {
+				seen[task] = true
+				tasks = append(tasks, task)
+			}
+		}
+	}
+	sort.Strings(tasks)
+	return tasks
+}
+
+// Progress é o andamento informado após cada linha
+type Progress struct {
+	Done   int // Linhas concluídas (inclusive as ignoradas)
+	Total  int
+	Result RowResult
+}
+
+// Observer recebe o andamento da execução
+type Observer func(progress Progress)
+
+// Run executa o workflow de cada linha, em ordem. O cancelamento do contexto ignora as linhas
+// restantes e é retornado como erro junto com o resumo parcial.
+func Run(ctx context.Context, sheet Sheet, runner Runner, config Config, observer Observer) (*Summary, error) {
+	summary := &Summary{Headers: sheet.Headers, Total: len(sheet.Rows), StartedAt: time.Now()}
+	stop := false
+	for _, row := range sheet.Rows {
+		result := RowResult{Row: row, Key: row.Values[config.KeyColumn]}
+		if stop || ctx.Err() != nil {
+			result.Status = StatusSkipped
+		} else {
+			start := time.Now()
+			status, outputs, err := runner(ctx, row)
+			result.Duration = time.Since(start)
+			result.Outputs = outputs
+			result.Status = status
+			if err != nil {
+				result.Error = err.Error()
+				if status != StatusTimeout {
+					result.Status = StatusFailed
+					stop = config.StopOnError
+				}
+			}
+			if result.Status == "" {
+				result.Status = StatusCompleted
+			}
+		}
+
+		switch result.Status {
+		case StatusCompleted:
+			summary.Completed++
+		case StatusTimeout:
+			summary.TimedOut++
+		case StatusFailed:
+			summary.Failed++
+		default:
+			summary.Skipped++
+		}
+		summary.Results = append(summary.Results, result)
+		if observer != nil {
+			observer(Progress{Done: len(summary.Results), Total: summary.Total, Result: result})
+		}
+	}
+	summary.Duration = time.Since(summary.StartedAt)
+	if err := ctx.Err(); err != nil {
+		return summary, fmt.Errorf("execução da planilha interrompida após %d de %d linhas: %w", summary.Completed+summary.TimedOut+summary.Failed, summary.Total, err)
+	}
+	return summary, nil
+}
@@ -0,0 +1,95 @@
+package sheetbatch
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/xuri/excelize/v2"
+)
+
+func products() Sheet {
+	return NewSheet([]string{"sku", "name"}, [][]string{
+		{"A-1", "Tênis"},
+		{"", ""},
+		{"A-2", "Bota", "inverno"},
+		{"A-3", "Sandália"},
+	})
+}
+
+func TestNewSheet(t *testing.T) {
+	sheet := products()
+	if len(sheet.Rows) != 3 || sheet.Rows[1].Index != 2 || sheet.Rows[1].Values["C"] != "inverno" {
+		t.Fatalf("linhas inesperadas: %+v", sheet)
+	}
+	if len(sheet.Headers) != 3 || sheet.Headers[2] != "C" || columnName(27) != "AB" {
+		t.Fatalf("cabeçalhos inesperados: %v", sheet.Headers)
+	}
+}
+
+func TestRunStopsOnError(t *testing.T) {
+	runner := func(_ context.Context, row Row) (string, map[string]string, error) {
+		switch row.Values["sku"] {
+		case "A-2":
+			return "", nil, errors.New("sem estoque")
+		default:
+			return StatusCompleted, map[string]string{"copy": "Campanha de " + row.Values["name"]}, nil
+		}
+	}
+	var progress []Progress
+	summary, err := Run(context.Background(), products(), runner, Config{StopOnError: true, KeyColumn: "sku"}, func(p Progress) {
+		progress = append(progress, p)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if summary.Completed != 1 || summary.Failed != 1 || summary.Skipped != 1 || summary.Results[1].Error != "sem estoque" {
+		t.Fatalf("resumo inesperado: %+v", summary)
+	}
+	if len(progress) != 3 || progress[2].Done != 3 || progress[2].Result.Key != "A-3" || progress[2].Result.Status != StatusSkipped {
+		t.Fatalf("andamento inesperado: %+v", progress)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if summary, err := Run(ctx, products(), runner, Config{}, nil); err == nil || summary.Skipped != 3 {
+		t.Fatalf("o cancelamento deveria ignorar as linhas: %+v %v", summary, err)
+	}
+}
+
+func TestWriteResults(t *testing.T) {
+	runner := func(_ context.Context, row Row) (string, map[string]string, error) {
+		return StatusCompleted, map[string]string{"copy": "Campanha de " + row.Values["name"]}, nil
+	}
+	summary, _ := Run(context.Background(), products(), runner, Config{}, nil)
+	path := filepath.Join(t.TempDir(), "produtos.xlsx")
+	if err := WriteResults(path, "Resultados", summary); err != nil {
+		t.Fatal(err)
+	}
+	// Gravar de novo substitui as abas
+	if err := WriteResults(path, "Resultados", summary); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := excelize.OpenFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if sheets := f.GetSheetList(); len(sheets) != 2 || sheets[0] != "Resultados" || sheets[1] != SummarySheet("Resultados") {
+		t.Fatalf("abas inesperadas: %v", sheets)
+	}
+	rows, _ := f.GetRows("Resultados")
+	if len(rows) != 4 || rows[0][3] != "status" || rows[0][6] != "copy" || rows[3][6] != "Campanha de Sandália" {
+		t.Fatalf("resultados inesperados: %v", rows)
+	}
+	overview, _ := f.GetRows(SummarySheet("Resultados"))
+	if overview[1][0] != "completed" || overview[1][1] != "3" {
+		t.Fatalf("resumo inesperado: %v", overview)
+	}
+
+	if err := WriteResults(filepath.Join(t.TempDir(), "produtos.csv"), "Resultados", summary); err == nil {
+		t.Fatal("CSV não deveria receber uma nova aba")
+	}
+}
@@ -0,0 +1,128 @@
+package sheetbatch
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// maxSheetName é o limite de caracteres do nome de uma aba no Excel
+const maxSheetName = 31
+
+// SummarySheet retorna o nome da aba do resumo gravada junto com a aba dos resultados
+func SummarySheet(sheet string) string {
+	name := []rune("Resumo " + sheet)
+	if len(name) > maxSheetName {
+		name = name[:maxSheetName]
+	}
+	return string(name)
+}
+
+// WriteResults grava os resultados na aba sheet da planilha XLSX em path — as colunas da
+// planilha original seguidas da situação, do erro, da duração e da resposta de cada
+// tarefa — e o resumo na aba SummarySheet(sheet). Uma planilha existente recebe as novas
+// abas, substituindo as de mesmo nome; uma inexistente é criada.
+func WriteResults(path, sheet string, summary *Summary) error {
+	if ext := strings.ToLower(filepath.Ext(path)); ext != ".xlsx" && ext != ".xlsm" {
+		return fmt.Errorf("os resultados só podem ser gravados em XLSX, não em %s", ext)
+	}
+	if sheet == "" || len([]rune(sheet)) > maxSheetName {
+		return fmt.Errorf("nome de aba inválido: %q", sheet)
+	}
+
+	f, created, err := open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	tasks := summary.Tasks()
+	header := make([]interface{}, 0, len(summary.Headers)+3+len(tasks))
+	for _, column := range summary.Headers {
+		header = append(header, column)
+	}
+	header = append(header, "status", "error", "duration_s")
+	for _, task := range tasks {
+		header = append(header, task)
+	}
+	rows := [][]interface{}{header}
+	for _, result := range summary.Results {
+		row := make([]interface{}, 0, len(header))
+		for _, column := range summary.Headers {
+			row = append(row, result.Row.Values[column])
+		}
+		row = append(row, result.Status, result.Error, result.Duration.Seconds())
+		for _, task := range tasks {
+			row = append(row, result.Outputs[task])
+		}
+		rows = append(rows, row)
+	}
+	if err := writeSheet(f, sheet, rows); err != nil {
+		return err
+	}
+
+	overview := [][]interface{}{
+		{"total", summary.Total},
+		{"completed", summary.Completed},
+		{"timed_out", summary.TimedOut},
+		{"failed", summary.Failed},
+		{"skipped", summary.Skipped},
+		{"started_at", summary.StartedAt.Format("2006-01-02 15:04:05")},
+		{"duration_s", summary.Duration.Seconds()},
+	}
+	if err := writeSheet(f, SummarySheet(sheet), overview); err != nil {
+		return err
+	}
+
+	// A planilha nova nasce com uma aba vazia padrão
+	if created && sheet != "Sheet1" && SummarySheet(sheet) != "Sheet1" {
+		if err := f.DeleteSheet("Sheet1"); err != nil {
+			return fmt.Errorf("erro ao remover a aba padrão: %v", err)
+		}
+	}
+	if index, err := f.GetSheetIndex(sheet); err == nil && index >= 0 {
+		f.SetActiveSheet(index)
+	}
+	if err := f.SaveAs(path); err != nil {
+		return fmt.Errorf("erro ao gravar a planilha %s: %v", path, err)
+	}
+	return nil
+}
+
+// open abre a planilha existente ou cria uma nova
+func open(path string) (f *excelize.File, created bool, err error) {
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		return excelize.NewFile(), true, nil
+	}
+	f, err = excelize.OpenFile(path)
+	if err != nil {
+		return nil, false, fmt.Errorf("erro ao abrir a planilha %s: %v", path, err)
+	}
+	return f, false, nil
+}
+
+// writeSheet recria a aba com as linhas informadas
+func writeSheet(f *excelize.File, sheet string, rows [][]interface{}) error {
+	if index, err := f.GetSheetIndex(sheet); err == nil && index >= 0 && len(f.GetSheetList()) > 1 {
+		if err := f.DeleteSheet(sheet); err != nil {
+			return fmt.Errorf("erro ao substituir a aba %s: %v", sheet, err)
+		}
+	}
+	if _, err := f.NewSheet(sheet); err != nil {
+		return fmt.Errorf("erro ao criar a aba %s: %v", sheet, err)
+	}
+	for i, row := range rows {
+		cell, err := excelize.CoordinatesToCellName(1, i+1)
+		if err != nil {
+			return err
+		}
+		if err := f.SetSheetRow(sheet, cell, &row); err != nil {
+			return fmt.Errorf("erro ao gravar a linha %d da aba %s: %v", i+1, sheet, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,230 @@
+// Package shutdown coordena o encerramento gracioso do runtime: para de aceitar
+// tarefas, aguarda as que estão em andamento, descarrega eventos e memórias e
+// fecha os clientes na ordem inversa de dependência.
+package shutdown
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// DefaultTimeout é o prazo padrão para drenar as tarefas em andamento
+const DefaultTimeout = 30 * time.Second
+
+// ErrShuttingDown indica que o runtime está encerrando e não aceita novas tarefas
+var ErrShuttingDown = errors.New("runtime em encerramento")
+
+// Hook é executado em uma das fases do encerramento
+type Hook func(ctx context.Context) error
+
+// namedHook associa um nome ao hook para os logs e erros
+type namedHook struct {
+	name string
+	hook Hook
+}
+
+// Manager coordena as fases do encerramento:
+//  1. intake: consumidores e servidores param de receber tarefas
+//  2. drain: aguarda as tarefas em andamento até o prazo (ou aborta-as)
+//  3. flush: descarrega eventos, métricas e memórias pendentes
+//  4. close: fecha os clientes em ordem inversa ao registro
+type Manager struct {
+	timeout  time.Duration
+	abort    context.CancelFunc
+	intake   []namedHook
+	flush    []namedHook
+	close    []namedHook
+	inflight sync.WaitGroup
+	draining bool
+	done     chan struct{}
+	err      error
+	once     sync.Once
+	mu       sync.Mutex
+}
+
+// New cria um Manager com o prazo informado para drenar as tarefas (DefaultTimeout se zero)
+func New(timeout time.Duration) *Manager {
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	return &Manager{
+		timeout: timeout,
+		done:    make(chan struct{}),
+	}
+}
+
+// SetAbort define a função chamada quando o prazo de drenagem expira, normalmente o
+// cancel do contexto principal, interrompendo as tarefas que ainda estão em execução
+func (m *Manager) SetAbort(abort context.CancelFunc) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.abort = abort
+}
+
+// OnStopIntake registra um hook que interrompe a entrada de novas tarefas
+func (m *Manager) OnStopIntake(name string, hook Hook) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.intake = append(m.intake, namedHook{name, hook})
+}
+
+// OnFlush registra um hook que descarrega dados pendentes (eventos, métricas, memórias)
+func (m *Manager) OnFlush(name string, hook Hook) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.flush = append(m.flush, namedHook{name, hook})
+}
+
+// OnClose registra um hook que fecha um cliente. Os hooks são executados em ordem
+// inversa ao registro, como defer: registre as dependências (conexões) primeiro.
+func (m *Manager) OnClose(name string, hook Hook) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.close = append(m.close, namedHook{name, hook})
+}
+
+// Track registra uma tarefa em andamento. A função retornada deve ser chamada ao fim
+// da tarefa. Retorna ErrShuttingDown se o encerramento já começou.
+func (m *Manager) Track() (func(), error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.draining {
+		return nil, ErrShuttingDown
+	}
+
+	m.inflight.Add(1)
+	var once sync.Once
+	return func() { once.Do(m.inflight.Done) }, nil
+}
+
+// Draining indica se o encerramento já começou
+func (m *Manager) Draining() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.draining
+}
+
+// Done é fechado quando o encerramento termina
+func (m *Manager) Done() <-chan struct{} {
+	return m.done
+}
+
+// Shutdown executa as fases do encerramento uma única vez; chamadas concorrentes
+// aguardam a primeira e retornam o mesmo resultado
+func (m *Manager) Shutdown(ctx context.Context) error {
+	m.once.Do(func() {
+		m.err = m.shutdown(ctx)
+		close(m.done)
+	})
+	<-m.done
+	return m.err
+}
+
+// shutdown executa as fases do encerramento
+func (m *Manager) shutdown(ctx context.Context) error {
+	m.mu.Lock()
+	m.draining = true
+	intake, flush, closers, abort := m.intake, m.flush, m.close, m.abort
+	m.mu.Unlock()
+
+	errs := make([]error, 0)
+
+	log.Printf("🛑 Encerramento: parando a entrada de tarefas")
+	errs = append(errs, run(ctx, intake)...)
+
+	log.Printf("⏳ Encerramento: aguardando tarefas em andamento (prazo %v)", m.timeout)
+	if err := m.drain(ctx); err != nil {
+		errs = append(errs, err)
+		if abort != nil {
+			abort()
+			// Dá às tarefas abortadas a chance de devolver as mensagens ao broker
+			m.drain(context.Background())
+		}
+	}
+
+	log.Printf("💾 Encerramento: descarregando eventos e memórias")
+	errs = append(errs, run(ctx, flush)...)
+
+	log.Printf("🔌 Encerramento: fechando clientes")
+	reversed := make([]namedHook, len(closers))
+	for i, hook := range closers {
+		reversed[len(closers)-1-i] = hook
+	}
+	errs = append(errs, run(ctx, reversed)...)
+
+	if len(errs) > 0 {
+		return fmt.Errorf("erros durante o encerramento: %v", errs)
+	}
+
+	log.Printf("👋 Encerramento concluído")
+	return nil
+}
+
+// drain aguarda as tarefas em andamento até o prazo
+func (m *Manager) drain(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, m.timeout)
+	defer cancel()
+
+	finished := make(chan struct{})
+	go func() {
+		m.inflight.Wait()
+		close(finished)
+	}()
+
+	select {
+	case <-finished:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("tarefas em andamento não concluíram no prazo: %v", ctx.Err())
+	}
+}
+
+// run executa os hooks em ordem, continuando após falhas
+func run(ctx context.Context, hooks []namedHook) []error {
+	errs := make([]error, 0)
+	for _, h := range hooks {
+		if err := h.hook(ctx); err != nil {
+			log.Printf("❌ Encerramento: erro em %s: %v", h.name, err)
+			errs = append(errs, fmt.Errorf("%s: %w", h.name, err))
+		}
+	}
+	return errs
+}
+
+// WaitForSignal bloqueia até receber SIGINT/SIGTERM (ou o contexto ser cancelado)
+// e então executa o encerramento. Um segundo sinal aborta imediatamente as tarefas.
+func (m *Manager) WaitForSignal(ctx context.Context) error {
+	signals := make(chan os.Signal, 2)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(signals)
+
+	select {
+	case sig := <-signals:
+		log.Printf("📶 Sinal %v recebido", sig)
+	case <-ctx.Done():
+	}
+
+	go func() {
+		select {
+		case <-signals:
+			log.Printf("⚠️ Segundo sinal recebido, abortando tarefas em andamento")
+			m.mu.Lock()
+			abort := m.abort
+			m.mu.Unlock()
+			if abort != nil {
+				abort()
+			}
+		case <-m.done:
+		}
+	}()
+
+	return m.Shutdown(context.Background())
+}
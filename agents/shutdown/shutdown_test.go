@@ -0,0 +1,75 @@
+package shutdown
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestShutdownOrder(t *testing.T) {
+	m := New(time.Second)
+	order := make([]string, 0)
+	var mu sync.Mutex
+	appendOrder := func(name string) {
+		mu.Lock()
+		defer mu.Unlock()
+		order = append(order, name)
+	}
+	record := func(name string) Hook {
+		return func(ctx context.Context) error {
+			appendOrder(name)
+			return nil
+		}
+	}
+
+	m.OnClose("conn", record("conn"))
+	m.OnClose("channel", record("channel"))
+	m.OnFlush("events", record("events"))
+	m.OnStopIntake("consumer", record("consumer"))
+
+	done, err := m.Track()
+	if err != nil {
+		t.Fatalf("Track: %v", err)
+	}
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		appendOrder("task")
+		done()
+	}()
+
+	if err := m.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown: %v", err)
+	}
+
+	got := strings.Join(order, ",")
+	if got != "consumer,task,events,channel,conn" {
+		t.Fatalf("ordem inesperada: %s", got)
+	}
+
+	if _, err := m.Track(); !errors.Is(err, ErrShuttingDown) {
+		t.Fatalf("esperado ErrShuttingDown, obtido %v", err)
+	}
+}
+
+func TestShutdownAbortsOnDeadline(t *testing.T) {
+	m := New(20 * time.Millisecond)
+	ctx, cancel := context.WithCancel(context.Background())
+	m.SetAbort(cancel)
+
+	done, _ := m.Track()
+	go func() {
+		<-ctx.Done()
+		done()
+	}()
+
+	err := m.Shutdown(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "prazo") {
+		t.Fatalf("esperado erro de prazo, obtido %v", err)
+	}
+	if ctx.Err() == nil {
+		t.Fatal("o contexto das tarefas deveria ter sido abortado")
+	}
+}
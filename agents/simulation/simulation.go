@@ -0,0 +1,129 @@
+package simulation
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/suissa/HiveMind/agents/llm"
+)
+
+// Tipos de efeito colateral interceptados no modo dry-run
+const (
+	EffectPublish  = "publish"
+	EffectToolCall = "tool_call"
+	EffectLLMCall  = "llm_call"
+	EffectFineTune = "fine_tune"
+)
+
+// Effect registra um efeito colateral que teria acontecido fora do modo dry-run
+type Effect struct {
+	Kind      string      `json:"kind"`
+	Target    string      `json:"target"` // Fila, ferramenta ou modelo
+	Payload   interface{} `json:"payload,omitempty"`
+	Timestamp time.Time   `json:"timestamp"`
+}
+
+// Session representa uma execução simulada: as respostas de LLM vêm de um provedor
+// gravado ou simulado e nenhuma publicação no broker ou ferramenta é executada
+type Session struct {
+	llm         llm.Provider
+	toolResults map[string]interface{}
+	effects     []Effect
+	mu          sync.Mutex
+}
+
+// NewSession cria uma sessão de simulação com o provedor de LLM informado
+func NewSession(provider llm.Provider) *Session {
+	return &Session{
+		llm:         provider,
+		toolResults: make(map[string]interface{}),
+		effects:     make([]Effect, 0),
+	}
+}
+
+// SetToolResult define o resultado simulado de uma ferramenta
+func (s *Session) SetToolResult(tool string, result interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.toolResults[tool] = result
+}
+
+// ToolResult retorna o resultado simulado de uma ferramenta (nil se não definido)
+func (s *Session) ToolResult(tool string) interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.toolResults[tool]
+}
+
+// Record registra um efeito colateral interceptado
+func (s *Session) Record(kind, target string, payload interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.effects = append(s.effects, Effect{
+		Kind:      kind,
+		Target:    target,
+		Payload:   payload,
+		Timestamp: time.Now(),
+	})
+}
+
+// Effects retorna uma cópia dos efeitos registrados
+func (s *Session) Effects() []Effect {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	effects := make([]Effect, len(s.effects))
+	copy(effects, s.effects)
+	return effects
+}
+
+// EffectsOf retorna os efeitos registrados de um tipo
+func (s *Session) EffectsOf(kind string) []Effect {
+	effects := make([]Effect, 0)
+	for _, effect := range s.Effects() {
+		if effect.Kind == kind {
+			effects = append(effects, effect)
+		}
+	}
+	return effects
+}
+
+// Complete responde a chamada de LLM com o provedor da sessão
+func (s *Session) Complete(ctx context.Context, req llm.Request) (*llm.Response, error) {
+	s.Record(EffectLLMCall, req.Model, req.Prompt)
+	if s.llm == nil {
+		return nil, fmt.Errorf("nenhuma resposta de LLM simulada configurada para o modo dry-run")
+	}
+	return s.llm.Complete(ctx, req)
+}
+
+type sessionKey struct{}
+
+// WithSession ativa o modo dry-run no contexto
+func WithSession(ctx context.Context, session *Session) context.Context {
+	return context.WithValue(ctx, sessionKey{}, session)
+}
+
+// FromContext retorna a sessão de simulação do contexto, se houver
+func FromContext(ctx context.Context) (*Session, bool) {
+	if ctx == nil {
+		return nil, false
+	}
+	session, ok := ctx.Value(sessionKey{}).(*Session)
+	return session, ok
+}
+
+// IsDryRun indica se o contexto está em modo dry-run
+func IsDryRun(ctx context.Context) bool {
+	_, ok := FromContext(ctx)
+	return ok
+}
+
+// Provider retorna o provedor de LLM a ser usado: a sessão no modo dry-run ou o fallback
+func Provider(ctx context.Context, fallback llm.Provider) llm.Provider {
+	if session, ok := FromContext(ctx); ok {
+		return session
+	}
+	return fallback
+}
@@ -0,0 +1,40 @@
+package simulation
+
+import (
+	"context"
+	"testing"
+
+	"github.com/suissa/HiveMind/agents/llm"
+)
+
+func TestSession(t *testing.T) {
+	session := NewSession(llm.ProviderFunc(func(ctx context.Context, req llm.Request) (*llm.Response, error) {
+		return &llm.Response{Text: "resposta gravada para: " + req.Prompt}, nil
+	}))
+	session.SetToolResult("nmap", map[string]interface{}{"open_ports": []int{22}})
+
+	if IsDryRun(context.Background()) {
+		t.Error("Contexto sem sessão não deveria estar em dry-run")
+	}
+
+	ctx := WithSession(context.Background(), session)
+	if !IsDryRun(ctx) {
+		t.Fatal("Contexto com sessão deveria estar em dry-run")
+	}
+
+	resp, err := Provider(ctx, nil).Complete(ctx, llm.Request{Model: "gpt-4", Prompt: "olá"})
+	if err != nil {
+		t.Fatalf("Erro na chamada simulada: %v", err)
+	}
+	if resp.Text != "resposta gravada para: olá" {
+		t.Errorf("Resposta incorreta: %s", resp.Text)
+	}
+
+	session.Record(EffectPublish, "llm_tasks", `{"id":"1"}`)
+	if len(session.EffectsOf(EffectPublish)) != 1 || len(session.EffectsOf(EffectLLMCall)) != 1 {
+		t.Errorf("Efeitos registrados incorretos: %+v", session.Effects())
+	}
+	if session.ToolResult("nmap") == nil {
+		t.Error("Resultado simulado da ferramenta ausente")
+	}
+}
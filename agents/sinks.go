@@ -0,0 +1,53 @@
+package agents
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/suissa/HiveMind/agents/sinks"
+)
+
+// SinkListener grava nos sinks de cada workflow os resultados dos workflows concluídos pelas
+// equipes de marketing; as execuções em modo dry-run não são gravadas
+func SinkListener(router *sinks.Router) EventListener {
+	return func(event Event) {
+		if event.Type != EventWorkflowUpdate {
+			return
+		}
+		if action, _ := event.Data["action"].(string); action != "workflow_complete" {
+			return
+		}
+		if dryRun, _ := event.Data["dry_run"].(bool); dryRun {
+			return
+		}
+		results, ok := event.Data["results"].(*WorkflowResults)
+		if !ok {
+			return
+		}
+		project, _ := event.Data["project"].(string)
+		duration, _ := event.Data["duration"].(string)
+		elapsed, _ := time.ParseDuration(duration)
+		completed := event.Timestamp
+		if completed.IsZero() {
+			completed = time.Now()
+		}
+
+		outputs := make(map[string]string, len(results.TaskOutputs))
+		for task, output := range results.TaskOutputs {
+			outputs[task] = output
+		}
+		result := sinks.Result{
+			Workflow:    project,
+			Status:      results.Status,
+			Outputs:     outputs,
+			TimedOut:    results.TimedOut,
+			Skipped:     results.Skipped,
+			Duration:    elapsed,
+			CompletedAt: completed,
+		}
+		if err := router.Deliver(context.Background(), result); err != nil {
+			log.Printf("⚠️ Erro ao gravar o resultado do workflow %s: %v", project, err)
+		}
+	}
+}
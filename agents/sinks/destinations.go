@@ -0,0 +1,243 @@
+package sinks
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/suissa/HiveMind/agents/webhook"
+)
+
+// ObjectStore é o armazenamento de objetos do ObjectSink; blob.Store (S3, MinIO, GridFS) o
+// implementa
+type ObjectStore interface {
+	Put(ctx context.Context, key string, data []byte, contentType string) error
+}
+
+// ObjectSink grava cada resultado como um objeto JSON em <prefixo><workflow>/<instante>.json
+type ObjectSink struct {
+	store  ObjectStore
+	prefix string
+}
+
+// NewObjectSink cria o sink sobre o armazenamento, com o prefixo das chaves (ex.: "results/")
+func NewObjectSink(store ObjectStore, prefix string) *ObjectSink {
+	return &ObjectSink{store: store, prefix: prefix}
+}
+
+// Name implementa Sink
+func (s *ObjectSink) Name() string {
+	return "object"
+}
+
+// Key retorna a chave do objeto do resultado
+func (s *ObjectSink) Key(result Result) string {
+	return s.prefix + result.Workflow + "/" + result.CompletedAt.UTC().Format("20060102T150405.000Z") + ".json"
+}
+
+// Write implementa Sink
+func (s *ObjectSink) Write(ctx context.Context, result Result) error {
+	data, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return fmt.Errorf("erro ao serializar o resultado: %v", err)
+	}
+	return s.store.Put(ctx, s.Key(result), data, "application/json")
+}
+
+// Execer executa os comandos do SQLSink; *sql.DB e *sql.Tx o implementam
+type Execer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+// identifier valida os nomes de tabela, que não podem ir como parâmetro do comando
+var identifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)?$`)
+
+// SQLSink insere cada resultado numa linha de uma tabela do Postgres, com placeholders $n.
+// A tabela deve ter as colunas:
+//
+//	CREATE TABLE workflow_results (
+//	    workflow     TEXT NOT NULL,
+//	    status       TEXT NOT NULL,
+//	    outputs      JSONB NOT NULL,
+//	    duration_ms  BIGINT NOT NULL,
+//	    completed_at TIMESTAMPTZ NOT NULL
+//	);
+type SQLSink struct {
+	db    Execer
+	table string
+}
+
+// NewSQLSink cria o sink sobre a conexão aberta pela aplicação (com o driver do Postgres
+// que ela preferir)
+func NewSQLSink(db Execer, table string) (*SQLSink, error) {
+	if !identifier.MatchString(table) {
+		return nil, fmt.Errorf("nome de tabela inválido: %q", table)
+	}
+	return &SQLSink{db: db, table: table}, nil
+}
+
+// Name implementa Sink
+func (s *SQLSink) Name() string {
+	return "sql:" + s.table
+}
+
+// Write implementa Sink
+func (s *SQLSink) Write(ctx context.Context, result Result) error {
+	outputs, err := json.Marshal(result.Outputs)
+	if err != nil {
+		return fmt.Errorf("erro ao serializar as respostas: %v", err)
+	}
+	query := "INSERT INTO " + s.table + " (workflow, status, outputs, duration_ms, completed_at) VALUES ($1, $2, $3, $4, $5)"
+	if _, err := s.db.ExecContext(ctx, query, result.Workflow, result.Status, string(outputs), result.Duration.Milliseconds(), result.CompletedAt); err != nil {
+		return fmt.Errorf("erro ao inserir o resultado em %s: %w", s.table, err)
+	}
+	return nil
+}
+
+// SheetsAPI é o endereço padrão da API do Google Sheets
+const SheetsAPI = "https://sheets.googleapis.com"
+
+// TokenFunc fornece o token OAuth 2.0 de acesso às planilhas (escopo
+// https://www.googleapis.com/auth/spreadsheets), renovado pela aplicação
+type TokenFunc func(ctx context.Context) (string, error)
+
+// SheetsSink acrescenta ao fim de uma aba do Google Sheets uma linha por tarefa do resultado:
+// instante, workflow, status, tarefa e resposta
+type SheetsSink struct {
+	SpreadsheetID string
+	Sheet         string // Nome da aba
+	BaseURL       string // Padrão SheetsAPI
+	token         TokenFunc
+	client        *http.Client
+}
+
+// NewSheetsSink cria o sink sobre a aba da planilha
+func NewSheetsSink(spreadsheetID, sheet string, token TokenFunc) *SheetsSink {
+	return &SheetsSink{
+		SpreadsheetID: spreadsheetID,
+		Sheet:         sheet,
+		BaseURL:       SheetsAPI,
+		token:         token,
+		client:        &http.Client{Timeout: DefaultTimeout},
+	}
+}
+
+// Name implementa Sink
+func (s *SheetsSink) Name() string {
+	return "sheets:" + s.Sheet
+}
+
+// Rows retorna as linhas do resultado, por tarefa
+func (s *SheetsSink) Rows(result Result) [][]interface{} {
+	tasks := make([]string, 0, len(result.Outputs))
+	for task := range result.Outputs {
+		tasks = append(tasks, task)
+	}
+	sort.Strings(tasks)
+	completed := result.CompletedAt.UTC().Format(time.RFC3339)
+	rows := make([][]interface{}, 0, len(tasks))
+	for _, task := range tasks {
+		rows = append(rows, []interface{}{completed, result.Workflow, result.Status, task, result.Outputs[task]})
+	}
+	return rows
+}
+
+// Write implementa Sink (spreadsheets.values.append)
+func (s *SheetsSink) Write(ctx context.Context, result Result) error {
+	rows := s.Rows(result)
+	if len(rows) == 0 {
+		return nil
+	}
+	body, err := json.Marshal(map[string]interface{}{"values": rows})
+	if err != nil {
+		return fmt.Errorf("erro ao serializar as linhas: %v", err)
+	}
+	token, err := s.token(ctx)
+	if err != nil {
+		return fmt.Errorf("erro ao obter o token do Google Sheets: %w", err)
+	}
+	endpoint := fmt.Sprintf("%s/v4/spreadsheets/%s/values/%s:append?valueInputOption=RAW&insertDataOption=INSERT_ROWS",
+		strings.TrimSuffix(s.BaseURL, "/"), url.PathEscape(s.SpreadsheetID), url.PathEscape(s.Sheet))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("erro ao criar a requisição do Google Sheets: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+	return do(s.client, req, "Google Sheets")
+}
+
+// WebhookEvent é o evento informado no header X-HiveMind-Event do WebhookSink
+const WebhookEvent = "workflow.result"
+
+// WebhookSink envia cada resultado por POST, no envelope e com a assinatura HMAC dos webhooks
+// do ciclo de vida (agents/webhook), verificável com webhook.Verify
+type WebhookSink struct {
+	URL     string
+	Secret  string            // Vazio envia sem assinatura
+	Headers map[string]string // Cabeçalhos extras, ex.: Authorization
+	client  *http.Client
+}
+
+// NewWebhookSink cria o sink para a URL
+func NewWebhookSink(url, secret string) *WebhookSink {
+	return &WebhookSink{URL: url, Secret: secret, client: &http.Client{Timeout: DefaultTimeout}}
+}
+
+// Name implementa Sink
+func (s *WebhookSink) Name() string {
+	return "webhook"
+}
+
+// Write implementa Sink
+func (s *WebhookSink) Write(ctx context.Context, result Result) error {
+	data, err := json.Marshal(result)
+	if err != nil {
+		return fmt.Errorf("erro ao serializar o resultado: %v", err)
+	}
+	now := time.Now()
+	id := uuid.NewString()
+	body, err := json.Marshal(webhook.Envelope{ID: id, Event: WebhookEvent, Timestamp: now, Data: data})
+	if err != nil {
+		return fmt.Errorf("erro ao serializar o envelope: %v", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("erro ao criar a requisição do webhook: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for name, value := range s.Headers {
+		req.Header.Set(name, value)
+	}
+	req.Header.Set(webhook.HeaderEvent, WebhookEvent)
+	req.Header.Set(webhook.HeaderDelivery, id)
+	req.Header.Set(webhook.HeaderTimestamp, strconv.FormatInt(now.Unix(), 10))
+	if s.Secret != "" {
+		req.Header.Set(webhook.HeaderSignature, webhook.Sign(s.Secret, now.Unix(), body))
+	}
+	return do(s.client, req, "webhook")
+}
+
+// do envia a requisição; respostas fora da faixa 2xx são erros
+func do(client *http.Client, req *http.Request, service string) error {
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("erro ao chamar o %s: %v", service, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("%s retornou status %d", service, resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+// Package sinks entrega os resultados finais dos workflows onde as equipes seguintes precisam
+// deles: um bucket S3 (ou outro armazenamento de objetos), uma tabela do Postgres, uma planilha
+// do Google Sheets ou um webhook. Os sinks são configurados por workflow no Router, e cada
+// escrita que falha é repetida com espera exponencial sem atrasar os demais sinks.
+package sinks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/suissa/HiveMind/agents/metrics"
+)
+
+// Valores padrão da configuração
+const (
+	DefaultAttempts = 3
+	DefaultBackoff  = time.Second
+	DefaultTimeout  = 30 * time.Second
+)
+
+// AllWorkflows recebe os sinks aplicados a todos os workflows
+const AllWorkflows = "*"
+
+var writesTotal = metrics.Default.Counter("hivemind_sink_writes_total",
+	"Escritas dos resultados dos workflows nos sinks, por sink e resultado (ok ou failed)", "sink", "result")
+
+// Result é o resultado final de um workflow
+type Result struct {
+	Workflow    string            `json:"workflow"` // Nome do projeto
+	Status      string            `json:"status"`
+	Outputs     map[string]string `json:"outputs"` // Resposta de cada tarefa
+	TimedOut    []string          `json:"timed_out,omitempty"`
+	Skipped     []string          `json:"skipped,omitempty"`
+	Duration    time.Duration     `json:"duration"`
+	CompletedAt time.Time         `json:"completed_at"`
+}
+
+// Sink grava os resultados num destino
+type Sink interface {
+	// Name identifica o sink nos logs e nas métricas, ex.: "s3" ou "postgres:results"
+	Name() string
+	Write(ctx context.Context, result Result) error
+}
+
+// Config define as tentativas de cada escrita
+type Config struct {
+	Attempts int           `json:"attempts,omitempty" yaml:"attempts,omitempty"` // Tentativas por sink (padrão DefaultAttempts)
+	Backoff  time.Duration `json:"backoff,omitempty" yaml:"backoff,omitempty"`   // Espera antes da segunda tentativa, dobrada a cada nova
+	Timeout  time.Duration `json:"timeout,omitempty" yaml:"timeout,omitempty"`   // Prazo de cada tentativa
+}
+
+// Router associa os workflows aos seus sinks
+type Router struct {
+	config Config
+	routes map[string][]Sink
+	mu     sync.RWMutex
+}
+
+// New cria o roteador com a configuração informada, completando os padrões
+func New(config Config) *Router {
+	if config.Attempts <= 0 {
+		config.Attempts = DefaultAttempts
+	}
+	if config.Backoff <= 0 {
+		config.Backoff = DefaultBackoff
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = DefaultTimeout
+	}
+	return &Router{config: config, routes: make(map[string][]Sink)}
+}
+
+// Add acrescenta sinks ao workflow; AllWorkflows os aplica a todos
+func (r *Router) Add(workflow string, sinks ...Sink) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.routes[workflow] = append(r.routes[workflow], sinks...)
+}
+
+// Sinks retorna os sinks do workflow, incluindo os de AllWorkflows
+func (r *Router) Sinks(workflow string) []Sink {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	sinks := append([]Sink(nil), r.routes[workflow]...)
+	if workflow != AllWorkflows {
+		sinks = append(sinks, r.routes[AllWorkflows]...)
+	}
+	return sinks
+}
+
+// Deliver grava o resultado em todos os sinks do workflow, em paralelo, e retorna os erros
+// dos sinks que falharam em todas as tentativas
+func (r *Router) Deliver(ctx context.Context, result Result) error {
+	sinks := r.Sinks(result.Workflow)
+	errs := make([]error, len(sinks))
+	var wg sync.WaitGroup
+	for i, sink := range sinks {
+		wg.Add(1)
+		go func(i int, sink Sink) {
+			defer wg.Done()
+			if err := r.write(ctx, sink, result); err != nil {
+				errs[i] = fmt.Errorf("sink %s: %w", sink.Name(), err)
+			}
+		}(i, sink)
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+// write grava no sink com as novas tentativas
+func (r *Router) write(ctx context.Context, sink Sink, result Result) error {
+	wait := r.config.Backoff
+	var err error
+	for attempt := 1; ; attempt++ {
+		attemptCtx, cancel := context.WithTimeout(ctx, r.config.Timeout)
+		err = sink.Write(attemptCtx, result)
+		cancel()
+		if err == nil {
+			writesTotal.Inc(sink.Name(), "ok")
+			return nil
+		}
+		if attempt >= r.config.Attempts {
+			break
+		}
+		log.Printf("⚠️ Erro ao gravar o resultado de %s no sink %s (tentativa %d): %v", result.Workflow, sink.Name(), attempt, err)
+		select {
+		case <-ctx.Done():
+			writesTotal.Inc(sink.Name(), "failed")
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+		wait *= 2
+	}
+	writesTotal.Inc(sink.Name(), "failed")
+	return err
+}
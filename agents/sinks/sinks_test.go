@@ -0,0 +1,155 @@
+package sinks
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/suissa/HiveMind/agents/webhook"
+)
+
+func result() Result {
+	return Result{
+		Workflow:    "tendencias",
+		Status:      "completed",
+		Outputs:     map[string]string{"pesquisa": "alta em tênis", "copy": "Corra mais"},
+		Duration:    2 * time.Second,
+		CompletedAt: time.Date(2024, time.March, 14, 10, 30, 0, 0, time.UTC),
+	}
+}
+
+// flaky falha nas primeiras chamadas
+type flaky struct {
+	name     string
+	failures int
+	calls    int
+	mu       sync.Mutex
+}
+
+func (f *flaky) Name() string { return f.name }
+
+func (f *flaky) Write(context.Context, Result) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls++
+	if f.calls <= f.failures {
+		return errors.New("indisponível")
+	}
+	return nil
+}
+
+func TestRouterRetriesPerWorkflow(t *testing.T) {
+	router := New(Config{Attempts: 2, Backoff: time.Millisecond})
+	recovers := &flaky{name: "recupera", failures: 1}
+	broken := &flaky{name: "quebrado", failures: 10}
+	all := &flaky{name: "todos"}
+	router.Add("tendencias", recovers, broken)
+	router.Add(AllWorkflows, all)
+
+	err := router.Deliver(context.Background(), result())
+	if err == nil || recovers.calls != 2 || broken.calls != 2 || all.calls != 1 {
+		t.Fatalf("tentativas inesperadas: %v (%d, %d, %d)", err, recovers.calls, broken.calls, all.calls)
+	}
+	if router.Deliver(context.Background(), Result{Workflow: "outro"}); all.calls != 2 || recovers.calls != 2 {
+		t.Fatal("outro workflow só deveria usar os sinks de todos")
+	}
+}
+
+// objects guarda os objetos gravados
+type objects map[string][]byte
+
+func (o objects) Put(_ context.Context, key string, data []byte, _ string) error {
+	o[key] = data
+	return nil
+}
+
+// execer registra os comandos executados
+type execer struct {
+	query string
+	args  []interface{}
+}
+
+func (e *execer) ExecContext(_ context.Context, query string, args ...interface{}) (sql.Result, error) {
+	e.query, e.args = query, args
+	return nil, nil
+}
+
+func TestObjectAndSQLSinks(t *testing.T) {
+	store := objects{}
+	if err := NewObjectSink(store, "results/").Write(context.Background(), result()); err != nil {
+		t.Fatal(err)
+	}
+	var stored Result
+	if err := json.Unmarshal(store["results/tendencias/20240314T103000.000Z.json"], &stored); err != nil || stored.Outputs["copy"] != "Corra mais" {
+		t.Fatalf("objeto inesperado: %v %v", store, err)
+	}
+
+	if _, err := NewSQLSink(&execer{}, "results; DROP TABLE x"); err == nil {
+		t.Fatal("nome de tabela inválido deveria ser rejeitado")
+	}
+	db := &execer{}
+	sink, err := NewSQLSink(db, "analytics.workflow_results")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := sink.Write(context.Background(), result()); err != nil {
+		t.Fatal(err)
+	}
+	if db.query != "INSERT INTO analytics.workflow_results (workflow, status, outputs, duration_ms, completed_at) VALUES ($1, $2, $3, $4, $5)" ||
+		db.args[0] != "tendencias" || db.args[3] != int64(2000) {
+		t.Fatalf("comando inesperado: %s %v", db.query, db.args)
+	}
+}
+
+func TestSheetsSink(t *testing.T) {
+	var path, auth string
+	var body struct {
+		Values [][]string `json:"values"`
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path, auth = r.URL.EscapedPath()+"?"+r.URL.RawQuery, r.Header.Get("Authorization")
+		json.NewDecoder(r.Body).Decode(&body)
+	}))
+	defer server.Close()
+
+	sink := NewSheetsSink("planilha-1", "Resultados", func(context.Context) (string, error) { return "tok", nil })
+	sink.BaseURL = server.URL
+	if err := sink.Write(context.Background(), result()); err != nil {
+		t.Fatal(err)
+	}
+	if path != "/v4/spreadsheets/planilha-1/values/Resultados:append?valueInputOption=RAW&insertDataOption=INSERT_ROWS" || auth != "Bearer tok" {
+		t.Fatalf("requisição inesperada: %s (%s)", path, auth)
+	}
+	if len(body.Values) != 2 || body.Values[0][3] != "copy" || body.Values[1][4] != "alta em tênis" {
+		t.Fatalf("linhas inesperadas: %v", body.Values)
+	}
+}
+
+func TestWebhookSinkSigned(t *testing.T) {
+	var valid bool
+	var envelope webhook.Envelope
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		timestamp, _ := strconv.ParseInt(r.Header.Get(webhook.HeaderTimestamp), 10, 64)
+		valid = webhook.Verify("segredo", r.Header.Get(webhook.HeaderSignature), timestamp, data)
+		json.Unmarshal(data, &envelope)
+	}))
+	defer server.Close()
+
+	if err := NewWebhookSink(server.URL, "segredo").Write(context.Background(), result()); err != nil {
+		t.Fatal(err)
+	}
+	var received Result
+	json.Unmarshal(envelope.Data, &received)
+	if !valid || envelope.Event != WebhookEvent || received.Workflow != "tendencias" {
+		t.Fatalf("entrega inesperada: %v %+v", valid, envelope)
+	}
+}
@@ -0,0 +1,171 @@
+// Package skills carrega ferramentas (skills) empacotadas fora do binário: cada skill é um
+// diretório com um manifesto skill.yaml e um módulo WASM (WASI) ou um plugin Go. Os operadores
+// adicionam capacidades copiando o diretório, sem recompilar o HiveMind.
+//
+// Um módulo WASM recebe os parâmetros da chamada como JSON na entrada padrão e escreve o
+// resultado na saída padrão (JSON ou texto); um código de saída diferente de zero é um erro,
+// com a saída de erro como mensagem. Um plugin Go exporta uma função
+// func(context.Context, map[string]interface{}) (interface{}, error).
+package skills
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/suissa/HiveMind/agents/errs"
+)
+
+// ManifestFile é o nome do manifesto em cada diretório de skill
+const ManifestFile = "skill.yaml"
+
+// Runtimes suportados
+const (
+	RuntimeWASM   = "wasm"
+	RuntimePlugin = "plugin"
+)
+
+// Padrões aplicados aos manifestos
+const (
+	DefaultTimeout     = 10 * time.Second
+	DefaultEntrypoint  = "Execute"
+	DefaultMaxMemoryMB = 64
+)
+
+// Property é um parâmetro do schema
+type Property struct {
+	Type        string `yaml:"type" json:"type"` // string, number, integer, boolean, object ou array
+	Description string `yaml:"description,omitempty" json:"description,omitempty"`
+}
+
+// Schema descreve os parâmetros aceitos pela skill
+type Schema struct {
+	Required   []string            `yaml:"required,omitempty" json:"required,omitempty"`
+	Properties map[string]Property `yaml:"properties,omitempty" json:"properties,omitempty"`
+}
+
+// Permissions define quem pode usar a skill e o que o módulo WASM pode acessar
+type Permissions struct {
+	Roles  []string `yaml:"roles,omitempty" json:"roles,omitempty"`   // Papéis liberados na matriz de ferramentas
+	Agents []string `yaml:"agents,omitempty" json:"agents,omitempty"` // IDs de agentes liberados
+	Env    []string `yaml:"env,omitempty" json:"env,omitempty"`       // Variáveis de ambiente repassadas ao módulo
+	Dirs   []string `yaml:"dirs,omitempty" json:"dirs,omitempty"`     // Diretórios montados somente leitura em /<dir>
+}
+
+// Manifest descreve uma skill
+type Manifest struct {
+	Name        string        `yaml:"name" json:"name"`
+	Description string        `yaml:"description" json:"description"`
+	Version     string        `yaml:"version,omitempty" json:"version,omitempty"`
+	Runtime     string        `yaml:"runtime" json:"runtime"`                           // wasm ou plugin
+	Module      string        `yaml:"module" json:"module"`                             // Arquivo do módulo, relativo ao diretório da skill
+	Entrypoint  string        `yaml:"entrypoint,omitempty" json:"entrypoint,omitempty"` // Símbolo exportado pelo plugin Go
+	Schema      Schema        `yaml:"schema,omitempty" json:"schema,omitempty"`
+	Permissions Permissions   `yaml:"permissions,omitempty" json:"permissions,omitempty"`
+	Timeout     time.Duration `yaml:"timeout,omitempty" json:"timeout,omitempty"`
+	MaxMemoryMB int           `yaml:"max_memory_mb,omitempty" json:"max_memory_mb,omitempty"` // Limite de memória do módulo WASM
+
+	dir string // Diretório da skill
+}
+
+// Dir retorna o diretório de onde o manifesto foi carregado
+func (m *Manifest) Dir() string {
+	return m.dir
+}
+
+// LoadManifest lê e valida o manifesto do diretório da skill, aplicando os padrões
+func LoadManifest(dir string) (*Manifest, error) {
+	data, err := os.ReadFile(filepath.Join(dir, ManifestFile))
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler manifesto da skill: %v", err)
+	}
+	var manifest Manifest
+	if err := yaml.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("erro ao decodificar manifesto da skill em %s: %v", dir, err)
+	}
+	manifest.dir = dir
+	if err := manifest.Validate(); err != nil {
+		return nil, err
+	}
+	if manifest.Timeout <= 0 {
+		manifest.Timeout = DefaultTimeout
+	}
+	if manifest.Entrypoint == "" {
+		manifest.Entrypoint = DefaultEntrypoint
+	}
+	if manifest.MaxMemoryMB <= 0 {
+		manifest.MaxMemoryMB = DefaultMaxMemoryMB
+	}
+	return &manifest, nil
+}
+
+// Validate verifica os campos obrigatórios do manifesto
+func (m *Manifest) Validate() error {
+	const op = "skills.Manifest"
+	switch {
+	case m.Name == "":
+		return errs.New(errs.ErrValidation, op, "manifesto em %s sem nome", m.dir)
+	case m.Module == "":
+		return errs.New(errs.ErrValidation, op, "skill %s sem módulo", m.Name)
+	case m.Runtime != RuntimeWASM && m.Runtime != RuntimePlugin:
+		return errs.New(errs.ErrValidation, op, "skill %s com runtime %q inválido (use %s ou %s)", m.Name, m.Runtime, RuntimeWASM, RuntimePlugin)
+	}
+	for _, dir := range m.Permissions.Dirs {
+		if filepath.IsAbs(dir) || !filepath.IsLocal(dir) {
+			return errs.New(errs.ErrValidation, op, "skill %s: o diretório %s deve ficar dentro do diretório da skill", m.Name, dir)
+		}
+	}
+	for name, property := range m.Schema.Properties {
+		if _, ok := validTypes[property.Type]; !ok {
+			return errs.New(errs.ErrValidation, op, "skill %s: tipo %q inválido no parâmetro %s", m.Name, property.Type, name)
+		}
+	}
+	return nil
+}
+
+// validTypes são os tipos aceitos no schema, com a verificação de cada um
+var validTypes = map[string]func(value interface{}) bool{
+	"string":  func(v interface{}) bool { _, ok := v.(string); return ok },
+	"boolean": func(v interface{}) bool { _, ok := v.(bool); return ok },
+	"number": func(v interface{}) bool {
+		switch v.(type) {
+		case float64, float32, int, int64, int32:
+			return true
+		}
+		return false
+	},
+	"integer": func(v interface{}) bool {
+		switch n := v.(type) {
+		case int, int64, int32:
+			return true
+		case float64:
+			return n == float64(int64(n))
+		}
+		return false
+	},
+	"object": func(v interface{}) bool { _, ok := v.(map[string]interface{}); return ok },
+	"array":  func(v interface{}) bool { _, ok := v.([]interface{}); return ok },
+}
+
+// Check valida os parâmetros da chamada contra o schema: os obrigatórios devem estar presentes
+// e os declarados devem ter o tipo indicado. Parâmetros não declarados são repassados.
+func (s Schema) Check(params map[string]interface{}) error {
+	for _, name := range s.Required {
+		if _, ok := params[name]; !ok {
+			return errs.New(errs.ErrValidation, "skills.Schema", "parâmetro obrigatório ausente: %s", name)
+		}
+	}
+	for name, value := range params {
+		property, ok := s.Properties[name]
+		if !ok {
+			continue
+		}
+		if !validTypes[property.Type](value) {
+			return errs.New(errs.ErrValidation, "skills.Schema", "parâmetro %s deveria ser %s", name, property.Type)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package skills
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"plugin"
+)
+
+// pluginFunc é a assinatura exportada pelos plugins Go
+type pluginFunc = func(ctx context.Context, params map[string]interface{}) (interface{}, error)
+
+// pluginSkill executa a função exportada por um plugin Go (go build -buildmode=plugin). O
+// plugin roda no processo, sem isolamento: as permissões de ambiente e diretórios do manifesto
+// valem só para WASM, e o plugin deve ser compilado com a mesma versão do Go e das dependências.
+type pluginSkill struct {
+	fn pluginFunc
+}
+
+func loadPlugin(manifest *Manifest) (*pluginSkill, error) {
+	p, err := plugin.Open(filepath.Join(manifest.dir, manifest.Module))
+	if err != nil {
+		return nil, fmt.Errorf("erro ao abrir o plugin: %v", err)
+	}
+	symbol, err := p.Lookup(manifest.Entrypoint)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar %s no plugin: %v", manifest.Entrypoint, err)
+	}
+	switch fn := symbol.(type) {
+	case pluginFunc:
+		return &pluginSkill{fn: fn}, nil
+	case *pluginFunc:
+		return &pluginSkill{fn: *fn}, nil
+	}
+	return nil, fmt.Errorf("%s no plugin tem o tipo %T; esperava func(context.Context, map[string]interface{}) (interface{}, error)", manifest.Entrypoint, symbol)
+}
+
+func (s *pluginSkill) execute(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+	return s.fn(ctx, params)
+}
+
+// close não faz nada: plugins Go não podem ser descarregados
+func (s *pluginSkill) close(ctx context.Context) error {
+	return nil
+}
@@ -0,0 +1,116 @@
+package skills
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// executor executa o módulo de uma skill
+type executor interface {
+	execute(ctx context.Context, params map[string]interface{}) (interface{}, error)
+	close(ctx context.Context) error
+}
+
+// Skill é uma ferramenta carregada de um manifesto; implementa a interface Tool dos agentes
+type Skill struct {
+	manifest *Manifest
+	exec     executor
+}
+
+// Load carrega a skill do diretório: lê o manifesto e compila (WASM) ou abre (plugin) o módulo
+func Load(ctx context.Context, dir string) (*Skill, error) {
+	manifest, err := LoadManifest(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var exec executor
+	switch manifest.Runtime {
+	case RuntimeWASM:
+		exec, err = loadWASM(ctx, manifest)
+	case RuntimePlugin:
+		exec, err = loadPlugin(manifest)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("erro ao carregar a skill %s: %w", manifest.Name, err)
+	}
+	return &Skill{manifest: manifest, exec: exec}, nil
+}
+
+// LoadDir carrega as skills dos subdiretórios de dir que têm um skill.yaml, em ordem
+// alfabética. Em caso de erro, as skills já carregadas são fechadas.
+func LoadDir(ctx context.Context, dir string) ([]*Skill, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler o diretório de skills: %v", err)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+
+	var loaded []*Skill
+	for _, entry := range entries {
+		path := filepath.Join(dir, entry.Name())
+		if !entry.IsDir() {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(path, ManifestFile)); err != nil {
+			continue
+		}
+		skill, err := Load(ctx, path)
+		if err != nil {
+			for _, s := range loaded {
+				s.Close(ctx)
+			}
+			return nil, err
+		}
+		loaded = append(loaded, skill)
+	}
+	return loaded, nil
+}
+
+// Name implementa Tool
+func (s *Skill) Name() string {
+	return s.manifest.Name
+}
+
+// Description implementa Tool
+func (s *Skill) Description() string {
+	return s.manifest.Description
+}
+
+// InputSchema retorna o schema do manifesto como JSON Schema
+func (s *Skill) InputSchema() json.RawMessage {
+	schema := map[string]interface{}{"type": "object", "properties": s.manifest.Schema.Properties}
+	if s.manifest.Schema.Properties == nil {
+		schema["properties"] = map[string]Property{}
+	}
+	if len(s.manifest.Schema.Required) > 0 {
+		schema["required"] = s.manifest.Schema.Required
+	}
+	data, _ := json.Marshal(schema)
+	return data
+}
+
+// Manifest retorna o manifesto da skill
+func (s *Skill) Manifest() *Manifest {
+	return s.manifest
+}
+
+// Execute implementa Tool: valida os parâmetros contra o schema e executa o módulo com o
+// timeout do manifesto
+func (s *Skill) Execute(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+	if err := s.manifest.Schema.Check(params); err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(ctx, s.manifest.Timeout)
+	defer cancel()
+	return s.exec.execute(ctx, params)
+}
+
+// Close libera o runtime do módulo
+func (s *Skill) Close(ctx context.Context) error {
+	return s.exec.close(ctx)
+}
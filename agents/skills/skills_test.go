@@ -0,0 +1,67 @@
+package skills
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/suissa/HiveMind/agents/errs"
+)
+
+func TestLoadDirRunsWASMSkills(t *testing.T) {
+	ctx := context.Background()
+	loaded, err := LoadDir(ctx, "testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		for _, skill := range loaded {
+			skill.Close(ctx)
+		}
+	}()
+	if len(loaded) != 2 || loaded[0].Name() != "echo" || loaded[1].Name() != "loop" {
+		t.Fatalf("skills inesperadas: %v", loaded)
+	}
+	echo, loop := loaded[0], loaded[1]
+	if roles := echo.Manifest().Permissions.Roles; len(roles) != 1 || roles[0] != "analyst" {
+		t.Fatalf("permissões inesperadas: %+v", echo.Manifest().Permissions)
+	}
+
+	result, err := echo.Execute(ctx, map[string]interface{}{"text": "olá", "n": 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out, ok := result.(map[string]interface{}); !ok || out["text"] != "olá" || out["n"] != float64(2) {
+		t.Fatalf("resultado inesperado: %#v", result)
+	}
+
+	if _, err := echo.Execute(ctx, map[string]interface{}{}); !errors.Is(err, errs.ErrValidation) {
+		t.Fatalf("esperava erro de parâmetro obrigatório: %v", err)
+	}
+	if _, err := echo.Execute(ctx, map[string]interface{}{"text": 1}); !errors.Is(err, errs.ErrValidation) {
+		t.Fatalf("esperava erro de tipo: %v", err)
+	}
+	if _, err := loop.Execute(ctx, nil); !errors.Is(err, errs.ErrTimeout) {
+		t.Fatalf("esperava timeout: %v", err)
+	}
+}
+
+func TestLoadManifestValidation(t *testing.T) {
+	dir := t.TempDir()
+	manifest := "name: fora\nruntime: wasm\nmodule: fora.wasm\npermissions:\n  dirs: [\"../etc\"]\n"
+	if err := os.WriteFile(filepath.Join(dir, ManifestFile), []byte(manifest), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadManifest(dir); !errors.Is(err, errs.ErrValidation) {
+		t.Fatalf("diretórios fora da skill deveriam ser rejeitados: %v", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, ManifestFile), []byte("name: x\nruntime: lua\nmodule: x.lua\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(context.Background(), dir); !errors.Is(err, errs.ErrValidation) {
+		t.Fatalf("runtime inválido deveria ser rejeitado: %v", err)
+	}
+}
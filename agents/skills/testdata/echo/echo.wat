@@ -0,0 +1,14 @@
+;; Fonte de echo.wasm: copia a entrada padrão (os parâmetros em JSON) para a saída padrão.
+(module
+  (import "wasi_snapshot_preview1" "fd_read" (func $fd_read (param i32 i32 i32 i32) (result i32)))
+  (import "wasi_snapshot_preview1" "fd_write" (func $fd_write (param i32 i32 i32 i32) (result i32)))
+  (memory (export "memory") 1)
+  (func (export "_start")
+    ;; iovec de leitura em 0: buffer em 64 com 4000 bytes; bytes lidos em 8
+    (i32.store (i32.const 0) (i32.const 64))
+    (i32.store (i32.const 4) (i32.const 4000))
+    (drop (call $fd_read (i32.const 0) (i32.const 0) (i32.const 1) (i32.const 8)))
+    ;; iovec de escrita em 16 com os bytes lidos
+    (i32.store (i32.const 16) (i32.const 64))
+    (i32.store (i32.const 20) (i32.load (i32.const 8)))
+    (drop (call $fd_write (i32.const 1) (i32.const 16) (i32.const 1) (i32.const 24)))))
@@ -0,0 +1,5 @@
+;; Fonte de loop.wasm: um laço infinito, interrompido pelo timeout do manifesto.
+(module
+  (memory (export "memory") 1)
+  (func (export "_start")
+    (loop $forever (br $forever))))
@@ -0,0 +1,108 @@
+package skills
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/tetratelabs/wazero"
+	"github.com/tetratelabs/wazero/imports/wasi_snapshot_preview1"
+	"github.com/tetratelabs/wazero/sys"
+
+	"github.com/suissa/HiveMind/agents/errs"
+)
+
+// wasmPageSize é o tamanho de uma página de memória WASM
+const wasmPageSize = 64 * 1024
+
+// wasmSkill executa um módulo WASI compilado uma vez; cada chamada usa uma instância nova,
+// isolada das demais
+type wasmSkill struct {
+	manifest *Manifest
+	runtime  wazero.Runtime
+	compiled wazero.CompiledModule
+}
+
+func loadWASM(ctx context.Context, manifest *Manifest) (*wasmSkill, error) {
+	code, err := os.ReadFile(filepath.Join(manifest.dir, manifest.Module))
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler o módulo: %v", err)
+	}
+
+	pages := uint32(manifest.MaxMemoryMB * 1024 * 1024 / wasmPageSize)
+	runtime := wazero.NewRuntimeWithConfig(ctx, wazero.NewRuntimeConfig().
+		WithCloseOnContextDone(true).
+		WithMemoryLimitPages(pages))
+	if _, err := wasi_snapshot_preview1.Instantiate(ctx, runtime); err != nil {
+		runtime.Close(ctx)
+		return nil, fmt.Errorf("erro ao instanciar o WASI: %v", err)
+	}
+	compiled, err := runtime.CompileModule(ctx, code)
+	if err != nil {
+		runtime.Close(ctx)
+		return nil, fmt.Errorf("erro ao compilar o módulo: %v", err)
+	}
+	return &wasmSkill{manifest: manifest, runtime: runtime, compiled: compiled}, nil
+}
+
+// execute roda o módulo com os parâmetros em JSON na entrada padrão e lê o resultado da saída
+func (s *wasmSkill) execute(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+	input, err := json.Marshal(params)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao serializar os parâmetros: %v", err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	config := wazero.NewModuleConfig().
+		WithName(""). // Anônimo, para permitir chamadas concorrentes
+		WithArgs(s.manifest.Name).
+		WithStdin(bytes.NewReader(input)).
+		WithStdout(&stdout).
+		WithStderr(&stderr).
+		WithSysWalltime().
+		WithSysNanotime()
+	for _, key := range s.manifest.Permissions.Env {
+		if value, ok := os.LookupEnv(key); ok {
+			config = config.WithEnv(key, value)
+		}
+	}
+	if len(s.manifest.Permissions.Dirs) > 0 {
+		fsConfig := wazero.NewFSConfig()
+		for _, dir := range s.manifest.Permissions.Dirs {
+			fsConfig = fsConfig.WithReadOnlyDirMount(filepath.Join(s.manifest.dir, dir), "/"+filepath.ToSlash(dir))
+		}
+		config = config.WithFSConfig(fsConfig)
+	}
+
+	module, err := s.runtime.InstantiateModule(ctx, s.compiled, config)
+	if module != nil {
+		module.Close(ctx)
+	}
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, errs.FromContext("skills.Execute", ctxErr)
+		}
+		var exitErr *sys.ExitError
+		if errors.As(err, &exitErr) {
+			return nil, fmt.Errorf("skill %s terminou com código %d: %s", s.manifest.Name, exitErr.ExitCode(), strings.TrimSpace(stderr.String()))
+		}
+		return nil, fmt.Errorf("erro ao executar a skill %s: %v", s.manifest.Name, err)
+	}
+
+	output := bytes.TrimSpace(stdout.Bytes())
+	var result interface{}
+	if json.Unmarshal(output, &result) != nil {
+		// Saída que não é JSON é devolvida como texto
+		return string(output), nil
+	}
+	return result, nil
+}
+
+func (s *wasmSkill) close(ctx context.Context) error {
+	return s.runtime.Close(ctx)
+}
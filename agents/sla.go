@@ -0,0 +1,63 @@
+package agents
+
+import (
+	"context"
+	"time"
+
+	"github.com/suissa/HiveMind/agents/sla"
+)
+
+// SLAListener alimenta o monitor de SLA com os eventos das equipes e do contract net: os
+// inícios e términos dos workflows (pelo projeto) e das tarefas (pelo ID)
+func SLAListener(monitor *sla.Monitor) EventListener {
+	return func(event Event) {
+		action, _ := event.Data["action"].(string)
+		at := event.Timestamp
+		if at.IsZero() {
+			at = time.Now()
+		}
+		switch event.Type {
+		case EventWorkflowUpdate:
+			project, _ := event.Data["project"].(string)
+			switch action {
+			case "workflow_start":
+				monitor.Start(sla.KindWorkflow, project, project, at)
+			case "workflow_complete", "workflow_failed":
+				monitor.End(context.Background(), sla.KindWorkflow, project, at)
+			}
+		case EventTaskUpdate:
+			taskID, _ := event.Data["task_id"].(string)
+			name, _ := event.Data["task_name"].(string)
+			switch action {
+			case "task_start", "task_awarded":
+				monitor.Start(sla.KindTask, taskID, name, at)
+			case "task_complete", "task_failed", "task_timeout":
+				monitor.End(context.Background(), sla.KindTask, taskID, at)
+			}
+		}
+	}
+}
+
+// SLAEvent converte um alerta de SLA no evento sla_breach (ou sla_resolved) do workflow ou da
+// tarefa violada
+func SLAEvent(alert sla.Alert) Event {
+	action := "sla_breach"
+	if alert.Resolved {
+		action = "sla_resolved"
+	}
+	data := map[string]interface{}{
+		"action":     action,
+		"limit":      alert.Limit.String(),
+		"elapsed":    alert.Elapsed.Round(time.Second).String(),
+		"started_at": alert.StartedAt.Format(time.RFC3339),
+	}
+	eventType := EventWorkflowUpdate
+	if alert.Kind == sla.KindTask {
+		eventType = EventTaskUpdate
+		data["task_id"] = alert.ID
+		data["task_name"] = alert.Name
+	} else {
+		data["project"] = alert.ID
+	}
+	return Event{Type: eventType, Timestamp: alert.Timestamp, Source: "sla", Data: data}
+}
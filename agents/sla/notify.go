@@ -0,0 +1,79 @@
+package sla
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Subject é o tópico padrão dos alertas publicados no barramento
+const Subject = "sla.alert"
+
+// Publisher é o barramento dos alertas; communication.CommunicationClient o implementa
+type Publisher interface {
+	Publish(ctx context.Context, subject string, data []byte) error
+}
+
+// BusNotifier publica os alertas em JSON no barramento
+type BusNotifier struct {
+	bus     Publisher
+	subject string
+}
+
+// NewBusNotifier cria o notificador; subject vazio usa Subject
+func NewBusNotifier(bus Publisher, subject string) *BusNotifier {
+	if subject == "" {
+		subject = Subject
+	}
+	return &BusNotifier{bus: bus, subject: subject}
+}
+
+// Notify implementa Notifier
+func (n *BusNotifier) Notify(ctx context.Context, alert Alert) error {
+	data, err := json.Marshal(alert)
+	if err != nil {
+		return fmt.Errorf("erro ao serializar alerta: %v", err)
+	}
+	return n.bus.Publish(ctx, n.subject, data)
+}
+
+// WebhookNotifier envia os alertas em JSON por POST a uma URL
+type WebhookNotifier struct {
+	URL     string
+	Headers map[string]string // Cabeçalhos extras, ex.: Authorization
+	client  *http.Client
+}
+
+// NewWebhookNotifier cria o notificador para a URL
+func NewWebhookNotifier(url string) *WebhookNotifier {
+	return &WebhookNotifier{URL: url, client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+// Notify implementa Notifier; respostas fora da faixa 2xx são erros
+func (n *WebhookNotifier) Notify(ctx context.Context, alert Alert) error {
+	body, err := json.Marshal(alert)
+	if err != nil {
+		return fmt.Errorf("erro ao serializar alerta: %v", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("erro ao criar requisição do webhook: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for name, value := range n.Headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("erro ao chamar o webhook: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook retornou status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,232 @@
+// Package sla acompanha os acordos de nível de serviço dos workflows: a duração máxima de um
+// workflow e a latência máxima de uma tarefa. O Monitor recebe os inícios e términos
+// (normalmente pelos eventos do runtime) e, a cada verificação, alerta sobre os workflows e
+// tarefas ainda em andamento além do limite, para que os operadores saibam de uma campanha
+// travada antes dos clientes. Cada violação gera um único alerta, e o término de um item
+// violado gera o alerta de resolução.
+package sla
+
+import (
+	"context"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/suissa/HiveMind/agents/metrics"
+)
+
+// Valores padrão da configuração
+const (
+	DefaultInterval = 10 * time.Second
+	// tombstoneTTL é o tempo em que um término recebido antes do início é lembrado: os
+	// eventos são entregues em goroutines e podem chegar fora de ordem
+	tombstoneTTL = time.Minute
+)
+
+var breachesTotal = metrics.Default.Counter("hivemind_sla_breaches_total",
+	"Violações de SLA por tipo (workflow ou task)", "kind")
+
+// Kind é o tipo do item acompanhado
+type Kind string
+
+const (
+	KindWorkflow Kind = "workflow"
+	KindTask     Kind = "task"
+)
+
+// Config define os limites; limites zerados não são verificados
+type Config struct {
+	MaxWorkflowDuration time.Duration            `json:"max_workflow_duration,omitempty" yaml:"max_workflow_duration,omitempty"`
+	MaxTaskLatency      time.Duration            `json:"max_task_latency,omitempty" yaml:"max_task_latency,omitempty"`
+	Workflows           map[string]time.Duration `json:"workflows,omitempty" yaml:"workflows,omitempty"` // Duração máxima por workflow, no lugar de MaxWorkflowDuration
+	Interval            time.Duration            `json:"interval,omitempty" yaml:"interval,omitempty"`   // Verificação dos itens em andamento (padrão DefaultInterval)
+}
+
+// limit retorna o limite do item, ou zero se não houver
+func (c Config) limit(kind Kind, id string) time.Duration {
+	if kind == KindTask {
+		return c.MaxTaskLatency
+	}
+	if limit, ok := c.Workflows[id]; ok {
+		return limit
+	}
+	return c.MaxWorkflowDuration
+}
+
+// Alert é o aviso de uma violação de SLA ou da sua resolução
+type Alert struct {
+	Kind      Kind          `json:"kind"`
+	ID        string        `json:"id"`             // Nome do workflow ou ID da tarefa
+	Name      string        `json:"name,omitempty"` // Nome legível da tarefa
+	Limit     time.Duration `json:"limit"`
+	Elapsed   time.Duration `json:"elapsed"`
+	StartedAt time.Time     `json:"started_at"`
+	Resolved  bool          `json:"resolved,omitempty"` // O item violado terminou
+	Timestamp time.Time     `json:"timestamp"`
+}
+
+// Notifier entrega os alertas
+type Notifier interface {
+	Notify(ctx context.Context, alert Alert) error
+}
+
+// NotifierFunc adapta uma função a Notifier
+type NotifierFunc func(ctx context.Context, alert Alert) error
+
+// Notify implementa Notifier
+func (f NotifierFunc) Notify(ctx context.Context, alert Alert) error {
+	return f(ctx, alert)
+}
+
+// item é um workflow ou tarefa em andamento
+type item struct {
+	kind      Kind
+	id        string
+	name      string
+	startedAt time.Time
+	breached  bool
+}
+
+// Monitor acompanha os itens em andamento e alerta sobre as violações
+type Monitor struct {
+	config    Config
+	notifiers []Notifier
+	running   map[string]*item
+	finished  map[string]time.Time // Términos recebidos antes do início
+	mu        sync.Mutex
+}
+
+// New cria o monitor com os notificadores dos alertas
+func New(config Config, notifiers ...Notifier) *Monitor {
+	if config.Interval <= 0 {
+		config.Interval = DefaultInterval
+	}
+	return &Monitor{
+		config:    config,
+		notifiers: notifiers,
+		running:   make(map[string]*item),
+		finished:  make(map[string]time.Time),
+	}
+}
+
+// AddNotifier acrescenta um notificador dos alertas
+func (m *Monitor) AddNotifier(notifier Notifier) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.notifiers = append(m.notifiers, notifier)
+}
+
+func key(kind Kind, id string) string {
+	return string(kind) + ":" + id
+}
+
+// Start registra o início de um workflow ou tarefa em at
+func (m *Monitor) Start(kind Kind, id, name string, at time.Time) {
+	if id == "" || m.config.limit(kind, id) <= 0 {
+		return
+	}
+	k := key(kind, id)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if end, ok := m.finished[k]; ok && !end.Before(at) {
+		delete(m.finished, k)
+		return
+	}
+	m.running[k] = &item{kind: kind, id: id, name: name, startedAt: at}
+}
+
+// End registra o término (com sucesso ou não) de um workflow ou tarefa em at; o término de
+// um item violado gera o alerta de resolução
+func (m *Monitor) End(ctx context.Context, kind Kind, id string, at time.Time) {
+	if id == "" || m.config.limit(kind, id) <= 0 {
+		return
+	}
+	k := key(kind, id)
+	m.mu.Lock()
+	it, ok := m.running[k]
+	if !ok || it.startedAt.After(at) {
+		m.finished[k] = at
+		m.mu.Unlock()
+		return
+	}
+	delete(m.running, k)
+	notifiers := m.notifiers
+	m.mu.Unlock()
+
+	if it.breached {
+		alert := m.alert(it, at)
+		alert.Resolved = true
+		notify(ctx, notifiers, alert)
+	}
+}
+
+// Running retorna a quantidade de itens em andamento acompanhados
+func (m *Monitor) Running() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.running)
+}
+
+// Check alerta sobre os itens em andamento que passaram do limite em now e ainda não foram
+// alertados, e retorna os alertas emitidos
+func (m *Monitor) Check(ctx context.Context, now time.Time) []Alert {
+	var alerts []Alert
+	m.mu.Lock()
+	for k, end := range m.finished {
+		if now.Sub(end) > tombstoneTTL {
+			delete(m.finished, k)
+		}
+	}
+	for _, it := range m.running {
+		if it.breached || now.Sub(it.startedAt) <= m.config.limit(it.kind, it.id) {
+			continue
+		}
+		it.breached = true
+		alerts = append(alerts, m.alert(it, now))
+	}
+	notifiers := m.notifiers
+	m.mu.Unlock()
+
+	sort.Slice(alerts, func(i, j int) bool { return alerts[i].StartedAt.Before(alerts[j].StartedAt) })
+	for _, alert := range alerts {
+		breachesTotal.Inc(string(alert.Kind))
+		notify(ctx, notifiers, alert)
+	}
+	return alerts
+}
+
+// Run verifica os itens a cada intervalo até o contexto ser cancelado
+func (m *Monitor) Run(ctx context.Context) {
+	ticker := time.NewTicker(m.config.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			m.Check(ctx, now)
+		}
+	}
+}
+
+func (m *Monitor) alert(it *item, now time.Time) Alert {
+	return Alert{
+		Kind:      it.kind,
+		ID:        it.id,
+		Name:      it.name,
+		Limit:     m.config.limit(it.kind, it.id),
+		Elapsed:   now.Sub(it.startedAt),
+		StartedAt: it.startedAt,
+		Timestamp: now,
+	}
+}
+
+// notify entrega o alerta a todos os notificadores; uma falha não impede os demais
+func notify(ctx context.Context, notifiers []Notifier, alert Alert) {
+	for _, notifier := range notifiers {
+		if err := notifier.Notify(ctx, alert); err != nil {
+			log.Printf("⚠️ Erro ao notificar alerta de SLA do %s %s: %v", alert.Kind, alert.ID, err)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package sla
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recorder guarda os alertas recebidos
+type recorder struct {
+	alerts []Alert
+	mu     sync.Mutex
+}
+
+func (r *recorder) Notify(_ context.Context, alert Alert) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.alerts = append(r.alerts, alert)
+	return nil
+}
+
+func TestBreachAlertedOnceAndResolved(t *testing.T) {
+	rec := &recorder{}
+	monitor := New(Config{MaxWorkflowDuration: time.Hour, MaxTaskLatency: time.Minute}, rec)
+	ctx := context.Background()
+	start := time.Now()
+
+	monitor.Start(KindWorkflow, "campanha", "", start)
+	monitor.Start(KindTask, "t-1", "Pesquisa", start)
+	if alerts := monitor.Check(ctx, start.Add(30*time.Second)); len(alerts) != 0 {
+		t.Fatalf("nenhum limite deveria ter sido violado: %+v", alerts)
+	}
+
+	alerts := monitor.Check(ctx, start.Add(2*time.Minute))
+	if len(alerts) != 1 || alerts[0].Kind != KindTask || alerts[0].Name != "Pesquisa" || alerts[0].Limit != time.Minute {
+		t.Fatalf("esperava o alerta da tarefa: %+v", alerts)
+	}
+	if again := monitor.Check(ctx, start.Add(3*time.Minute)); len(again) != 0 {
+		t.Fatalf("a violação deveria ser alertada uma única vez: %+v", again)
+	}
+
+	monitor.End(ctx, KindTask, "t-1", start.Add(4*time.Minute))
+	monitor.End(ctx, KindWorkflow, "campanha", start.Add(5*time.Minute))
+	if len(rec.alerts) != 2 || !rec.alerts[1].Resolved || rec.alerts[1].Elapsed != 4*time.Minute {
+		t.Fatalf("esperava a resolução da tarefa: %+v", rec.alerts)
+	}
+	if monitor.Running() != 0 {
+		t.Fatal("os itens concluídos não deveriam continuar em andamento")
+	}
+}
+
+func TestPerWorkflowLimit(t *testing.T) {
+	monitor := New(Config{Workflows: map[string]time.Duration{"tendencias": time.Minute}})
+	start := time.Now()
+	monitor.Start(KindWorkflow, "tendencias", "", start)
+	monitor.Start(KindWorkflow, "sem-limite", "", start)
+	monitor.Start(KindTask, "t-1", "", start)
+	if monitor.Running() != 1 {
+		t.Fatalf("só o workflow com limite deveria ser acompanhado: %d", monitor.Running())
+	}
+	if alerts := monitor.Check(context.Background(), start.Add(2*time.Minute)); len(alerts) != 1 || alerts[0].ID != "tendencias" {
+		t.Fatalf("alertas inesperados: %+v", alerts)
+	}
+}
+
+func TestEndBeforeStart(t *testing.T) {
+	monitor := New(Config{MaxTaskLatency: time.Minute})
+	start := time.Now()
+	monitor.End(context.Background(), KindTask, "t-1", start.Add(time.Second))
+	monitor.Start(KindTask, "t-1", "", start)
+	if monitor.Running() != 0 {
+		t.Fatal("o término recebido antes do início deveria encerrar a tarefa")
+	}
+	if alerts := monitor.Check(context.Background(), start.Add(time.Hour)); len(alerts) != 0 {
+		t.Fatalf("tarefa concluída não deveria gerar alerta: %+v", alerts)
+	}
+}
+
+// bus registra as publicações
+type bus struct {
+	subject string
+	data    []byte
+}
+
+func (b *bus) Publish(_ context.Context, subject string, data []byte) error {
+	b.subject, b.data = subject, data
+	return nil
+}
+
+func TestNotifiers(t *testing.T) {
+	alert := Alert{Kind: KindWorkflow, ID: "campanha", Limit: time.Hour, Elapsed: 2 * time.Hour, Timestamp: time.Now()}
+
+	b := &bus{}
+	if err := NewBusNotifier(b, "").Notify(context.Background(), alert); err != nil {
+		t.Fatal(err)
+	}
+	var published Alert
+	if err := json.Unmarshal(b.data, &published); err != nil || b.subject != Subject || published.ID != "campanha" {
+		t.Fatalf("publicação inesperada em %s: %s", b.subject, b.data)
+	}
+
+	var received Alert
+	var auth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		json.NewDecoder(r.Body).Decode(&received)
+	}))
+	defer server.Close()
+	webhook := NewWebhookNotifier(server.URL)
+	webhook.Headers = map[string]string{"Authorization": "Bearer x"}
+	if err := webhook.Notify(context.Background(), alert); err != nil {
+		t.Fatal(err)
+	}
+	if received.Elapsed != 2*time.Hour || auth != "Bearer x" {
+		t.Fatalf("webhook recebeu %+v (%q)", received, auth)
+	}
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer failing.Close()
+	if err := NewWebhookNotifier(failing.URL).Notify(context.Background(), alert); err == nil {
+		t.Fatal("status 502 deveria ser um erro")
+	}
+}
@@ -0,0 +1,285 @@
+package agents
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/suissa/HiveMind/agents/errs"
+	"github.com/suissa/HiveMind/agents/retrieval"
+)
+
+// SnapshotVersion é a versão atual do formato de snapshot. Snapshots de versões anteriores
+// são migrados ao carregar; arquivos sem versão são do formato 1 (SaveState original).
+const SnapshotVersion = 2
+
+// Snapshot é o estado persistido de um agente cognitivo
+type Snapshot struct {
+	Version int       `json:"version"`
+	SavedAt time.Time `json:"saved_at"`
+
+	// Identidade e configuração
+	ID            string             `json:"id"`
+	Name          string             `json:"name"`
+	Role          string             `json:"role"`
+	Goal          string             `json:"goal"`
+	Backstory     string             `json:"backstory"`
+	Persona       string             `json:"persona,omitempty"` // Persona que gerou a backstory
+	Tenant        string             `json:"tenant,omitempty"`
+	Model         string             `json:"model"`
+	Temperature   float64            `json:"temperature"`
+	MaxTokens     int                `json:"max_tokens"`
+	ContextWindow int                `json:"context_window"`
+	MemoryRecall  int                `json:"memory_recall"`
+	MinSimilarity float64            `json:"memory_min_similarity,omitempty"`
+	MemoryRanking *retrieval.Weights `json:"memory_ranking,omitempty"`
+	LearningRate  float64            `json:"learning_rate"`
+
+	// Conhecimento, prompts e estatísticas
+	KnowledgeBase    map[string]interface{} `json:"knowledge_base"`
+	PromptTemplates  map[string]string      `json:"prompt_templates"`
+	PromptVersion    string                 `json:"prompt_version"`
+	PerformanceStats map[string]float64     `json:"performance_stats"`
+	TrainingHistory  []SnapshotMetrics      `json:"training_history"`
+
+	// Estado da conversa, permissões e referências de memória
+	Conversation []string    `json:"conversation"`
+	Tools        []string    `json:"tools,omitempty"` // Ferramentas liberadas ao agente
+	Memories     []MemoryRef `json:"memories,omitempty"`
+}
+
+// SnapshotMetrics são as métricas de um treinamento no snapshot (sem os erros, que não são serializáveis)
+type SnapshotMetrics struct {
+	StartTime      time.Time `json:"start_time"`
+	EndTime        time.Time `json:"end_time"`
+	Accuracy       float64   `json:"accuracy"`
+	Loss           float64   `json:"loss"`
+	RoundsExecuted int       `json:"rounds_executed"`
+	DatasetVersion string    `json:"dataset_version,omitempty"`
+}
+
+// snapshotMigrations convertem o snapshot da versão da chave para a seguinte
+var snapshotMigrations = map[int]func(state map[string]interface{}){
+	1: migrateSnapshotV1,
+}
+
+// migrateSnapshotV1 converte o formato original do SaveState: o histórico de treinamento
+// era gravado com os nomes dos campos Go (StartTime, RoundsExecuted...) e os erros
+func migrateSnapshotV1(state map[string]interface{}) {
+	renames := map[string]string{
+		"StartTime":      "start_time",
+		"EndTime":        "end_time",
+		"Accuracy":       "accuracy",
+		"Loss":           "loss",
+		"RoundsExecuted": "rounds_executed",
+	}
+	if history, ok := state["training_history"].([]interface{}); ok {
+		for _, entry := range history {
+			metrics, ok := entry.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			for from, to := range renames {
+				if value, ok := metrics[from]; ok {
+					metrics[to] = value
+					delete(metrics, from)
+				}
+			}
+			delete(metrics, "Errors")
+		}
+	}
+	state["version"] = 2
+}
+
+// Snapshot captura o estado do agente: configuração, prompts, estatísticas, histórico de
+// respostas, ferramentas liberadas e as referências das memórias do agente
+func (a *CognitiveAgent) Snapshot(ctx context.Context) (*Snapshot, error) {
+	snapshot := &Snapshot{
+		Version:          SnapshotVersion,
+		SavedAt:          time.Now(),
+		ID:               a.GetID(),
+		Name:             a.GetName(),
+		Role:             a.GetRole(),
+		Goal:             a.Goal,
+		Backstory:        a.Backstory,
+		Persona:          a.persona,
+		Tenant:           a.AgentStruct.Tenant,
+		Model:            a.Model,
+		Temperature:      a.Temperature,
+		MaxTokens:        a.MaxTokens,
+		ContextWindow:    a.ContextWindow,
+		MemoryRecall:     a.MemoryRecall,
+		MinSimilarity:    a.MemorySimilarity,
+		MemoryRanking:    a.MemoryRanking,
+		LearningRate:     a.LearningRate,
+		KnowledgeBase:    a.KnowledgeBase,
+		PromptTemplates:  a.PromptTemplates,
+		PromptVersion:    a.promptVersion(),
+		PerformanceStats: a.PerformanceStats,
+		Conversation:     a.ResponseHistory,
+	}
+	for _, metrics := range a.trainingHistory {
+		snapshot.TrainingHistory = append(snapshot.TrainingHistory, SnapshotMetrics{
+			StartTime:      metrics.StartTime,
+			EndTime:        metrics.EndTime,
+			Accuracy:       metrics.Accuracy,
+			Loss:           metrics.Loss,
+			RoundsExecuted: metrics.RoundsExecuted,
+			DatasetVersion: metrics.DatasetVersion,
+		})
+	}
+	if a.tools != nil {
+		snapshot.Tools = a.tools.Allowed(a)
+	}
+
+	if a.memoryManager != nil {
+		memories, err := a.memoryManager.SearchMemories(a.scope(ctx), a.GetID(), nil)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao listar memórias do agente %s: %w", a.GetID(), err)
+		}
+		for _, m := range memories {
+			snapshot.Memories = append(snapshot.Memories, MemoryRef{AgentID: m.AgentID, ID: m.ID})
+		}
+	}
+	return snapshot, nil
+}
+
+// Restore aplica um snapshot ao agente. As memórias referenciadas que não existem mais no
+// gerenciador de memória são apenas registradas no log.
+func (a *CognitiveAgent) Restore(ctx context.Context, snapshot *Snapshot) error {
+	if snapshot.Version != SnapshotVersion {
+		return errs.New(errs.ErrValidation, "agent.Restore", "versão de snapshot %d não suportada (atual %d)", snapshot.Version, SnapshotVersion)
+	}
+
+	a.AgentStruct.ID = snapshot.ID
+	a.AgentStruct.Name = snapshot.Name
+	a.AgentStruct.Role = snapshot.Role
+	a.AgentStruct.Tenant = snapshot.Tenant
+	a.Goal = snapshot.Goal
+	a.Backstory = snapshot.Backstory
+	a.persona = snapshot.Persona
+	a.Model = snapshot.Model
+	a.AgentStruct.Model = snapshot.Model
+	a.Temperature = snapshot.Temperature
+	a.MaxTokens = snapshot.MaxTokens
+	a.ContextWindow = snapshot.ContextWindow
+	a.MemoryRecall = snapshot.MemoryRecall
+	a.MemorySimilarity = snapshot.MinSimilarity
+	a.MemoryRanking = snapshot.MemoryRanking
+	a.LearningRate = snapshot.LearningRate
+	if snapshot.KnowledgeBase != nil {
+		a.KnowledgeBase = snapshot.KnowledgeBase
+	}
+	for name, template := range snapshot.PromptTemplates {
+		a.PromptTemplates[name] = template
+	}
+	for name, value := range snapshot.PerformanceStats {
+		a.PerformanceStats[name] = value
+	}
+	a.ResponseHistory = append([]string(nil), snapshot.Conversation...)
+
+	a.trainingHistory = make([]*TrainingMetrics, 0, len(snapshot.TrainingHistory))
+	for _, metrics := range snapshot.TrainingHistory {
+		a.trainingHistory = append(a.trainingHistory, &TrainingMetrics{
+			StartTime:      metrics.StartTime,
+			EndTime:        metrics.EndTime,
+			Accuracy:       metrics.Accuracy,
+			Loss:           metrics.Loss,
+			RoundsExecuted: metrics.RoundsExecuted,
+			Errors:         make([]error, 0),
+			DatasetVersion: metrics.DatasetVersion,
+		})
+	}
+
+	if a.tools != nil && len(snapshot.Tools) > 0 {
+		a.tools.GrantAgent(a.GetID(), snapshot.Tools)
+	}
+
+	if snapshot.PromptVersion != "" && snapshot.PromptVersion != a.promptVersion() {
+		log.Printf("⚠️ Agente %s: versão de prompt restaurada (%s) difere da registrada no snapshot (%s)",
+			a.GetID(), a.promptVersion(), snapshot.PromptVersion)
+	}
+
+	if a.memoryManager != nil {
+		missing := 0
+		for _, ref := range snapshot.Memories {
+			if _, err := a.memoryManager.GetMemory(a.scope(ctx), ref.AgentID, ref.ID); err != nil {
+				missing++
+			}
+		}
+		if missing > 0 {
+			log.Printf("⚠️ Agente %s: %d de %d memórias do snapshot não foram encontradas", a.GetID(), missing, len(snapshot.Memories))
+		}
+	}
+	return nil
+}
+
+// SaveState salva o snapshot do agente em um arquivo
+func (a *CognitiveAgent) SaveState(path string) error {
+	snapshot, err := a.Snapshot(context.Background())
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return fmt.Errorf("erro ao converter estado para JSON: %v", err)
+	}
+
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("erro ao salvar estado em arquivo: %v", err)
+	}
+
+	return nil
+}
+
+// LoadState carrega o estado do agente de um arquivo, migrando snapshots de versões anteriores
+func (a *CognitiveAgent) LoadState(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("erro ao ler arquivo de estado: %v", err)
+	}
+
+	snapshot, err := DecodeSnapshot(data)
+	if err != nil {
+		return err
+	}
+	return a.Restore(context.Background(), snapshot)
+}
+
+// DecodeSnapshot decodifica um snapshot em JSON, aplicando as migrações necessárias
+func DecodeSnapshot(data []byte) (*Snapshot, error) {
+	var state map[string]interface{}
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("erro ao decodificar estado do JSON: %v", err)
+	}
+
+	version := 1
+	if v, ok := state["version"].(float64); ok {
+		version = int(v)
+	}
+	if version > SnapshotVersion {
+		return nil, errs.New(errs.ErrValidation, "agent.DecodeSnapshot", "snapshot na versão %d, mais nova que a suportada (%d)", version, SnapshotVersion)
+	}
+	for version < SnapshotVersion {
+		migrate, ok := snapshotMigrations[version]
+		if !ok {
+			return nil, errs.New(errs.ErrValidation, "agent.DecodeSnapshot", "sem migração para snapshots da versão %d", version)
+		}
+		migrate(state)
+		version++
+	}
+
+	migrated, err := json.Marshal(state)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao migrar snapshot: %v", err)
+	}
+	var snapshot Snapshot
+	if err := json.Unmarshal(migrated, &snapshot); err != nil {
+		return nil, fmt.Errorf("erro ao decodificar snapshot: %v", err)
+	}
+	return &snapshot, nil
+}
@@ -0,0 +1,354 @@
+// Package stealing redistribui as tarefas entre instâncias do mesmo tipo de agente em
+// processos diferentes: quando uma instância está ociosa e outra do mesmo papel acumula
+// tarefas na caixa de entrada, a ociosa pede parte delas pelo barramento. A carga das
+// instâncias vem do registro de presença (os heartbeats informam as tarefas na fila), o que
+// melhora a utilização sem escalar novas instâncias.
+//
+// A transferência preserva a entrega pelo menos uma vez: a instância carregada empresta as
+// tarefas (inbox.Lease) e só as remove quando a ociosa confirma o recebimento. Sem
+// confirmação, elas voltam à caixa original no fim do prazo de visibilidade.
+package stealing
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/suissa/HiveMind/agents/inbox"
+	"github.com/suissa/HiveMind/agents/metrics"
+	"github.com/suissa/HiveMind/agents/presence"
+)
+
+// SubjectPrefix é o prefixo dos tópicos do protocolo
+const SubjectPrefix = "inbox.steal"
+
+// Valores padrão da configuração
+const (
+	DefaultInterval   = 2 * time.Second
+	DefaultMinBacklog = 2
+	DefaultMaxBatch   = 10
+	DefaultTimeout    = 10 * time.Second
+)
+
+var transfersTotal = metrics.Default.Counter("hivemind_work_stealing_tasks_total",
+	"Tarefas transferidas entre instâncias por direção (stolen ou given)", "direction")
+
+// RequestSubject é o tópico dos pedidos de tarefas feitos ao agente carregado
+func RequestSubject(agentID string) string {
+	return SubjectPrefix + ".request." + agentID
+}
+
+// TransferSubject é o tópico das tarefas transferidas ao agente ocioso
+func TransferSubject(agentID string) string {
+	return SubjectPrefix + ".transfer." + agentID
+}
+
+// ConfirmSubject é o tópico das confirmações de recebimento enviadas ao agente carregado
+func ConfirmSubject(agentID string) string {
+	return SubjectPrefix + ".confirm." + agentID
+}
+
+// Config define a redistribuição
+type Config struct {
+	Interval   time.Duration `json:"interval,omitempty" yaml:"interval,omitempty"`       // Verificação das instâncias ociosas
+	MinBacklog int           `json:"min_backlog,omitempty" yaml:"min_backlog,omitempty"` // Fila mínima da instância carregada para o pedido
+	MaxBatch   int           `json:"max_batch,omitempty" yaml:"max_batch,omitempty"`     // Tarefas por pedido
+	Timeout    time.Duration `json:"timeout,omitempty" yaml:"timeout,omitempty"`         // Espera pela resposta antes de um novo pedido
+}
+
+// Registry informa as instâncias conhecidas e a sua carga; presence.Monitor o implementa
+type Registry interface {
+	Agents() []presence.Status
+}
+
+// Request é o pedido de tarefas de um agente ocioso
+type Request struct {
+	Thief string `json:"thief"`
+	Role  string `json:"role"`
+	Max   int    `json:"max"`
+}
+
+// Item é uma tarefa transferida; Payload é o payload da mensagem em JSON
+type Item struct {
+	ID         string          `json:"id"`
+	Type       string          `json:"type"`
+	Payload    json.RawMessage `json:"payload,omitempty"`
+	Deliveries int             `json:"deliveries"`
+	EnqueuedAt time.Time       `json:"enqueued_at"`
+	Receipt    string          `json:"receipt"` // Recibo do empréstimo na caixa original
+}
+
+// Transfer é a resposta a um pedido, possivelmente sem tarefas
+type Transfer struct {
+	Victim string `json:"victim"`
+	Items  []Item `json:"items"`
+}
+
+// Confirmation confirma o recebimento das tarefas transferidas
+type Confirmation struct {
+	Thief    string   `json:"thief"`
+	Receipts []string `json:"receipts"`
+}
+
+// Stealer redistribui as tarefas das caixas de entrada dos agentes locais com as outras
+// instâncias do mesmo papel
+type Stealer struct {
+	inbox    *inbox.Inbox
+	bus      presence.Bus
+	registry Registry
+	config   Config
+	local    map[string]string    // Papel de cada agente local
+	waiting  map[string]time.Time // Agentes locais aguardando a resposta de um pedido
+	mu       sync.Mutex
+}
+
+// New cria o redistribuidor; campos zerados da configuração usam os padrões
+func New(box *inbox.Inbox, bus presence.Bus, registry Registry, config Config) *Stealer {
+	if config.Interval <= 0 {
+		config.Interval = DefaultInterval
+	}
+	if config.MinBacklog <= 0 {
+		config.MinBacklog = DefaultMinBacklog
+	}
+	if config.MaxBatch <= 0 {
+		config.MaxBatch = DefaultMaxBatch
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = DefaultTimeout
+	}
+	return &Stealer{
+		inbox:    box,
+		bus:      bus,
+		registry: registry,
+		config:   config,
+		local:    make(map[string]string),
+		waiting:  make(map[string]time.Time),
+	}
+}
+
+// Add inscreve o agente local no protocolo: ele passa a atender os pedidos das outras
+// instâncias do papel e a pedir tarefas a elas quando estiver ocioso
+func (s *Stealer) Add(agentID, role string) error {
+	subscriptions := []struct {
+		subject string
+		handler func(ctx context.Context, data []byte) error
+	}{
+		{RequestSubject(agentID), func(ctx context.Context, data []byte) error { return s.handleRequest(ctx, agentID, data) }},
+		{TransferSubject(agentID), func(ctx context.Context, data []byte) error { return s.handleTransfer(ctx, agentID, data) }},
+		{ConfirmSubject(agentID), func(ctx context.Context, data []byte) error { return s.handleConfirmation(agentID, data) }},
+	}
+	for i, sub := range subscriptions {
+		handler := sub.handler
+		err := s.bus.Subscribe(sub.subject, func(ctx context.Context, _ string, data []byte) error {
+			return handler(ctx, data)
+		})
+		if err != nil {
+			for _, done := range subscriptions[:i] {
+				s.bus.Unsubscribe(done.subject)
+			}
+			return fmt.Errorf("erro ao inscrever o agente %s na redistribuição: %v", agentID, err)
+		}
+	}
+
+	s.mu.Lock()
+	s.local[agentID] = role
+	s.mu.Unlock()
+	return nil
+}
+
+// Remove retira o agente local do protocolo
+func (s *Stealer) Remove(agentID string) {
+	s.mu.Lock()
+	delete(s.local, agentID)
+	delete(s.waiting, agentID)
+	s.mu.Unlock()
+
+	s.bus.Unsubscribe(RequestSubject(agentID))
+	s.bus.Unsubscribe(TransferSubject(agentID))
+	s.bus.Unsubscribe(ConfirmSubject(agentID))
+}
+
+// Run verifica os agentes ociosos a cada intervalo até o contexto ser cancelado
+func (s *Stealer) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.config.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.Balance(ctx)
+		}
+	}
+}
+
+// Balance pede tarefas, para cada agente local ocioso, à instância do mesmo papel com a
+// maior fila, e retorna quantos pedidos foram feitos
+func (s *Stealer) Balance(ctx context.Context) int {
+	statuses := s.registry.Agents()
+	now := time.Now()
+
+	s.mu.Lock()
+	idle := make(map[string]string)
+	for id, role := range s.local {
+		if since, ok := s.waiting[id]; ok && now.Sub(since) < s.config.Timeout {
+			continue
+		}
+		if stats, ok := s.inbox.AgentStats(id); ok && stats.Pending == 0 && stats.InFlight == 0 {
+			idle[id] = role
+		}
+	}
+	s.mu.Unlock()
+
+	requests := 0
+	claimed := make(map[string]bool) // Cada instância carregada atende um agente ocioso por rodada
+	for id, role := range idle {
+		victim, ok := s.victim(statuses, role, claimed)
+		if !ok {
+			continue
+		}
+		claimed[victim.AgentID] = true
+		max := victim.Load.Queued / 2
+		if max < 1 {
+			max = 1
+		}
+		if max > s.config.MaxBatch {
+			max = s.config.MaxBatch
+		}
+		data, err := json.Marshal(Request{Thief: id, Role: role, Max: max})
+		if err != nil {
+			continue
+		}
+		if err := s.bus.Publish(ctx, RequestSubject(victim.AgentID), data); err != nil {
+			log.Printf("⚠️ Erro ao pedir tarefas ao agente %s: %v", victim.AgentID, err)
+			continue
+		}
+		s.mu.Lock()
+		s.waiting[id] = now
+		s.mu.Unlock()
+		requests++
+	}
+	return requests
+}
+
+// victim escolhe a instância disponível do papel com a maior fila, desde que ela alcance
+// MinBacklog. Os agentes locais ficam de fora: entre eles a caixa de entrada já redistribui.
+func (s *Stealer) victim(statuses []presence.Status, role string, claimed map[string]bool) (presence.Status, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var best presence.Status
+	found := false
+	for _, status := range statuses {
+		if _, local := s.local[status.AgentID]; local || claimed[status.AgentID] {
+			continue
+		}
+		if !status.Available() || status.Role != role || status.Load.Queued < s.config.MinBacklog {
+			continue
+		}
+		if !found || status.Load.Queued > best.Load.Queued {
+			best, found = status, true
+		}
+	}
+	return best, found
+}
+
+// handleRequest empresta ao agente ocioso parte das tarefas do agente local
+func (s *Stealer) handleRequest(ctx context.Context, agentID string, data []byte) error {
+	var req Request
+	if err := json.Unmarshal(data, &req); err != nil {
+		return fmt.Errorf("pedido de tarefas inválido: %v", err)
+	}
+	transfer := Transfer{Victim: agentID, Items: []Item{}}
+	// As tarefas já recebidas pelo agente local, mas ainda não iniciadas, continuam com ele
+	stats, _ := s.inbox.AgentStats(agentID)
+	max := req.Max
+	if max > stats.Pending-1 {
+		max = stats.Pending - 1
+	}
+	if max > 0 {
+		for _, delivery := range s.inbox.Lease(agentID, req.Role, max) {
+			payload, err := json.Marshal(delivery.Payload)
+			if err != nil {
+				// Payloads sem JSON não atravessam o barramento; voltam a ficar disponíveis
+				s.inbox.Nack(delivery.Receipt, 0, nil)
+				continue
+			}
+			transfer.Items = append(transfer.Items, Item{
+				ID:         delivery.ID,
+				Type:       delivery.Type,
+				Payload:    payload,
+				Deliveries: delivery.Deliveries - 1, // O empréstimo não conta como entrega
+				EnqueuedAt: delivery.EnqueuedAt,
+				Receipt:    delivery.Receipt,
+			})
+		}
+	}
+	out, err := json.Marshal(transfer)
+	if err != nil {
+		return fmt.Errorf("erro ao serializar a transferência: %v", err)
+	}
+	if err := s.bus.Publish(ctx, TransferSubject(req.Thief), out); err != nil {
+		// O pedido falhou: as tarefas emprestadas voltam a ficar disponíveis
+		for _, item := range transfer.Items {
+			s.inbox.Nack(item.Receipt, 0, nil)
+		}
+		return fmt.Errorf("erro ao transferir tarefas ao agente %s: %v", req.Thief, err)
+	}
+	return nil
+}
+
+// handleTransfer coloca as tarefas recebidas na caixa do agente local e confirma o recebimento
+func (s *Stealer) handleTransfer(ctx context.Context, agentID string, data []byte) error {
+	var transfer Transfer
+	if err := json.Unmarshal(data, &transfer); err != nil {
+		return fmt.Errorf("transferência de tarefas inválida: %v", err)
+	}
+	s.mu.Lock()
+	delete(s.waiting, agentID)
+	s.mu.Unlock()
+	if len(transfer.Items) == 0 {
+		return nil
+	}
+
+	confirmation := Confirmation{Thief: agentID}
+	for _, item := range transfer.Items {
+		err := s.inbox.Put(agentID, inbox.Message{
+			ID:         item.ID,
+			Type:       item.Type,
+			Payload:    item.Payload,
+			Deliveries: item.Deliveries,
+			EnqueuedAt: item.EnqueuedAt,
+		})
+		if err != nil {
+			continue
+		}
+		confirmation.Receipts = append(confirmation.Receipts, item.Receipt)
+	}
+	transfersTotal.Add(float64(len(confirmation.Receipts)), "stolen")
+	out, err := json.Marshal(confirmation)
+	if err != nil {
+		return fmt.Errorf("erro ao serializar a confirmação: %v", err)
+	}
+	return s.bus.Publish(ctx, ConfirmSubject(transfer.Victim), out)
+}
+
+// handleConfirmation remove da caixa do agente local as tarefas recebidas pela outra instância
+func (s *Stealer) handleConfirmation(agentID string, data []byte) error {
+	var confirmation Confirmation
+	if err := json.Unmarshal(data, &confirmation); err != nil {
+		return fmt.Errorf("confirmação de transferência inválida: %v", err)
+	}
+	given := 0
+	for _, receipt := range confirmation.Receipts {
+		// Um recibo expirado já devolveu a tarefa à caixa: ela pode ser executada nas duas instâncias
+		if err := s.inbox.Ack(receipt); err == nil {
+			given++
+		}
+	}
+	transfersTotal.Add(float64(given), "given")
+	return nil
+}
@@ -0,0 +1,117 @@
+package stealing
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/suissa/HiveMind/agents/communication/commtest"
+	"github.com/suissa/HiveMind/agents/inbox"
+	"github.com/suissa/HiveMind/agents/presence"
+)
+
+// registry é um registro fixo de instâncias
+type registry []presence.Status
+
+func (r registry) Agents() []presence.Status { return r }
+
+// instance é um processo com uma caixa de entrada e um redistribuidor
+type instance struct {
+	inbox   *inbox.Inbox
+	stealer *Stealer
+}
+
+// cluster cria dois processos no mesmo barramento: "busy" com tarefas na fila e "idle" vazio
+func cluster(t *testing.T, bus *commtest.Bus, queued int) (busy, idle instance) {
+	t.Helper()
+	busy.inbox = inbox.New(inbox.Config{VisibilityTimeout: 50 * time.Millisecond})
+	idle.inbox = inbox.New(inbox.Config{})
+	busy.inbox.Register("writer-1", "writer")
+	idle.inbox.Register("writer-2", "writer")
+	for i := 0; i < queued; i++ {
+		busy.inbox.Send("writer-1", "writer", map[string]interface{}{"description": "post", "n": i})
+	}
+
+	statuses := registry{
+		{Heartbeat: presence.Heartbeat{AgentID: "writer-1", Role: "writer", Load: presence.Load{Queued: queued}}, State: presence.StateHealthy},
+		{Heartbeat: presence.Heartbeat{AgentID: "writer-2", Role: "writer"}, State: presence.StateHealthy},
+	}
+	busy.stealer = New(busy.inbox, bus, statuses, Config{})
+	idle.stealer = New(idle.inbox, bus, statuses, Config{})
+	if err := busy.stealer.Add("writer-1", "writer"); err != nil {
+		t.Fatal(err)
+	}
+	if err := idle.stealer.Add("writer-2", "writer"); err != nil {
+		t.Fatal(err)
+	}
+	return busy, idle
+}
+
+func TestBalanceStealsFromBacklog(t *testing.T) {
+	bus := commtest.NewBus()
+	busy, idle := cluster(t, bus, 6)
+
+	if n := busy.stealer.Balance(context.Background()); n != 0 {
+		t.Fatalf("a instância carregada não deveria pedir tarefas, fez %d pedidos", n)
+	}
+	if n := idle.stealer.Balance(context.Background()); n != 1 {
+		t.Fatalf("a instância ociosa deveria fazer um pedido, fez %d", n)
+	}
+	if got, _ := idle.inbox.AgentStats("writer-2"); got.Pending != 3 {
+		t.Fatalf("deveria receber metade da fila: %+v", got)
+	}
+	if got, _ := busy.inbox.AgentStats("writer-1"); got.Pending != 3 || got.InFlight != 0 {
+		t.Fatalf("as tarefas confirmadas deveriam sair da caixa original: %+v", got)
+	}
+
+	deliveries, err := idle.inbox.Receive(context.Background(), "writer-2", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	payload, ok := deliveries[0].Payload.(json.RawMessage)
+	if !ok || deliveries[0].Deliveries != 1 {
+		t.Fatalf("tarefa transferida inesperada: %+v", deliveries[0])
+	}
+	var params map[string]interface{}
+	if err := json.Unmarshal(payload, &params); err != nil || params["description"] != "post" {
+		t.Fatalf("payload transferido inesperado: %s", payload)
+	}
+}
+
+func TestBalanceSkipsSmallBacklogAndBusyThief(t *testing.T) {
+	bus := commtest.NewBus()
+	_, idle := cluster(t, bus, 1)
+	if n := idle.stealer.Balance(context.Background()); n != 0 {
+		t.Fatalf("fila abaixo de MinBacklog não deveria gerar pedido, fez %d", n)
+	}
+
+	bus = commtest.NewBus()
+	_, idle = cluster(t, bus, 6)
+	idle.inbox.Send("writer-2", "writer", "própria")
+	if n := idle.stealer.Balance(context.Background()); n != 0 {
+		t.Fatalf("instância com tarefas não deveria pedir mais, fez %d", n)
+	}
+}
+
+func TestUnconfirmedTransferReturns(t *testing.T) {
+	bus := commtest.NewBus()
+	busy, idle := cluster(t, bus, 4)
+	// A confirmação se perde: as tarefas emprestadas voltam à caixa original após o prazo
+	bus.Fail(ConfirmSubject("writer-1"), true)
+
+	idle.stealer.Balance(context.Background())
+	if got, _ := busy.inbox.AgentStats("writer-1"); got.InFlight != 2 {
+		t.Fatalf("as tarefas deveriam estar emprestadas: %+v", got)
+	}
+	time.Sleep(60 * time.Millisecond)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	deliveries, err := busy.inbox.Receive(ctx, "writer-1", 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(deliveries) != 4 {
+		t.Fatalf("todas as tarefas deveriam voltar à caixa original, vieram %d", len(deliveries))
+	}
+}
@@ -0,0 +1,188 @@
+// Package supervisor isola falhas das goroutines de agentes, consumidores e handlers:
+// um panic é recuperado, registrado e reportado, e o componente é reiniciado com backoff.
+package supervisor
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"runtime/debug"
+	"sync"
+	"time"
+)
+
+// Failure descreve um panic recuperado em um componente
+type Failure struct {
+	Component string      `json:"component"`
+	Panic     interface{} `json:"panic"`
+	Stack     string      `json:"stack"`
+	Restarts  int         `json:"restarts"` // Reinícios do componente até esta falha
+	Time      time.Time   `json:"time"`
+}
+
+// Error implementa error
+func (f Failure) Error() string {
+	return fmt.Sprintf("panic em %s: %v", f.Component, f.Panic)
+}
+
+// Reporter recebe as falhas recuperadas (ex.: para emitir eventos de erro)
+type Reporter func(Failure)
+
+// Backoff define o intervalo de espera entre reinícios
+type Backoff struct {
+	Initial    time.Duration
+	Max        time.Duration
+	Multiplier float64
+}
+
+// DefaultBackoff é o backoff usado quando nenhum é configurado
+var DefaultBackoff = Backoff{
+	Initial:    500 * time.Millisecond,
+	Max:        30 * time.Second,
+	Multiplier: 2,
+}
+
+// next calcula o próximo intervalo a partir do atual
+func (b Backoff) next(current time.Duration) time.Duration {
+	if current <= 0 {
+		return b.Initial
+	}
+	next := time.Duration(float64(current) * b.Multiplier)
+	if next > b.Max {
+		return b.Max
+	}
+	return next
+}
+
+// Supervisor executa componentes reiniciando-os após panics
+type Supervisor struct {
+	backoff   Backoff
+	reporters []Reporter
+	failures  map[string]int
+	mu        sync.RWMutex
+}
+
+// Default é o supervisor usado pelos componentes sem supervisor configurado
+var Default = New()
+
+// New cria um Supervisor com o backoff padrão
+func New(reporters ...Reporter) *Supervisor {
+	return &Supervisor{
+		backoff:   DefaultBackoff,
+		reporters: reporters,
+		failures:  make(map[string]int),
+	}
+}
+
+// SetBackoff define o backoff entre reinícios
+func (s *Supervisor) SetBackoff(backoff Backoff) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.backoff = backoff
+}
+
+// OnFailure registra um Reporter adicional
+func (s *Supervisor) OnFailure(reporter Reporter) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.reporters = append(s.reporters, reporter)
+}
+
+// Failures retorna quantos panics o componente já teve
+func (s *Supervisor) Failures(component string) int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.failures[component]
+}
+
+// Go executa o componente em uma goroutine supervisionada
+func (s *Supervisor) Go(ctx context.Context, component string, fn func(ctx context.Context) error) {
+	go func() {
+		if err := s.Run(ctx, component, fn); err != nil && ctx.Err() == nil {
+			log.Printf("❌ Componente %s encerrado com erro: %v", component, err)
+		}
+	}()
+}
+
+// Run executa o componente, reiniciando-o com backoff após cada panic. Retorna quando
+// o componente termina normalmente (com ou sem erro) ou quando o contexto é cancelado.
+func (s *Supervisor) Run(ctx context.Context, component string, fn func(ctx context.Context) error) error {
+	var wait time.Duration
+	restarts := 0
+
+	for {
+		started := time.Now()
+		failure, err := s.call(ctx, component, fn)
+		if failure == nil {
+			return err
+		}
+
+		s.mu.RLock()
+		backoff := s.backoff
+		s.mu.RUnlock()
+
+		// Um componente que ficou estável por mais que o backoff máximo recomeça do intervalo inicial
+		if time.Since(started) > backoff.Max {
+			wait = 0
+		}
+		wait = backoff.next(wait)
+		restarts++
+
+		log.Printf("🔁 Reiniciando %s em %v (reinício %d)", component, wait, restarts)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+}
+
+// call executa fn convertendo um panic em Failure
+func (s *Supervisor) call(ctx context.Context, component string, fn func(ctx context.Context) error) (failure *Failure, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			f := s.record(component, r)
+			failure = &f
+		}
+	}()
+	return nil, fn(ctx)
+}
+
+// Recover deve ser chamado com defer em goroutines que não são reiniciadas
+// (ex.: listeners de eventos): o panic é registrado e reportado, sem derrubar o processo
+func (s *Supervisor) Recover(component string) {
+	if r := recover(); r != nil {
+		s.record(component, r)
+	}
+}
+
+// Protect executa fn convertendo um panic em erro, isolando handlers de mensagens
+func (s *Supervisor) Protect(component string, fn func() error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = s.record(component, r)
+		}
+	}()
+	return fn()
+}
+
+// record contabiliza, registra e reporta a falha
+func (s *Supervisor) record(component string, r interface{}) Failure {
+	s.mu.Lock()
+	s.failures[component]++
+	failure := Failure{
+		Component: component,
+		Panic:     r,
+		Stack:     string(debug.Stack()),
+		Restarts:  s.failures[component] - 1,
+		Time:      time.Now(),
+	}
+	reporters := s.reporters
+	s.mu.Unlock()
+
+	log.Printf("💥 Panic recuperado em %s: %v", component, r)
+	for _, report := range reporters {
+		report(failure)
+	}
+	return failure
+}
@@ -0,0 +1,61 @@
+package supervisor
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRunRestartsAfterPanic(t *testing.T) {
+	reported := make(chan Failure, 10)
+	s := New(func(f Failure) { reported <- f })
+	s.SetBackoff(Backoff{Initial: time.Millisecond, Max: 10 * time.Millisecond, Multiplier: 2})
+
+	calls := 0
+	err := s.Run(context.Background(), "consumer", func(ctx context.Context) error {
+		calls++
+		if calls < 3 {
+			panic("falha simulada")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if calls != 3 {
+		t.Fatalf("esperadas 3 execuções, obtidas %d", calls)
+	}
+	if s.Failures("consumer") != 2 || len(reported) != 2 {
+		t.Fatalf("esperadas 2 falhas reportadas, obtidas %d/%d", s.Failures("consumer"), len(reported))
+	}
+	if f := <-reported; f.Component != "consumer" || f.Panic != "falha simulada" || f.Stack == "" {
+		t.Fatalf("falha inesperada: %+v", f)
+	}
+}
+
+func TestRunStopsOnCancel(t *testing.T) {
+	s := New()
+	s.SetBackoff(Backoff{Initial: time.Hour, Max: time.Hour, Multiplier: 1})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		cancel()
+	}()
+
+	err := s.Run(ctx, "loop", func(ctx context.Context) error { panic("sempre") })
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("esperado context.Canceled, obtido %v", err)
+	}
+}
+
+func TestProtect(t *testing.T) {
+	s := New()
+	err := s.Protect("handler", func() error { panic("boom") })
+
+	var failure Failure
+	if !errors.As(err, &failure) || failure.Component != "handler" {
+		t.Fatalf("esperado Failure, obtido %v", err)
+	}
+}
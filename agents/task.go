@@ -1,7 +1,10 @@
 package agents
 
 import (
+	"encoding/json"
 	"time"
+
+	"github.com/suissa/HiveMind/agents/overrides"
 )
 
 // TaskStatus representa o estado de uma tarefa
@@ -13,26 +16,34 @@ const (
 	TaskStatusComplete  TaskStatus = "complete"
 	TaskStatusFailed    TaskStatus = "failed"
 	TaskStatusCancelled TaskStatus = "cancelled"
+	TaskStatusTimeout   TaskStatus = "timeout" // Prazo excedido; Output["partial"] guarda o resultado parcial
 )
 
+// partialKey guarda em Task.Output o resultado parcial de uma tarefa interrompida
+const partialKey = "partial"
+
 // Task representa uma tarefa a ser executada por um agente
 type Task struct {
-	ID           string                 // Identificador único da tarefa
-	Type         string                 // Tipo da tarefa
-	Description  string                 // Descrição da tarefa
-	Input        map[string]interface{} // Dados de entrada
-	Output       map[string]interface{} // Dados de saída
-	Status       TaskStatus             // Estado atual da tarefa
-	Priority     int                    // Prioridade da tarefa (maior = mais prioritário)
-	CreatedAt    time.Time              // Data de criação
-	StartedAt    *time.Time             // Data de início
-	FinishedAt   *time.Time             // Data de conclusão
-	AssignedTo   string                 // ID do agente designado
-	Error        error                  // Erro ocorrido durante execução
-	Retries      int                    // Número de tentativas realizadas
-	MaxRetries   int                    // Número máximo de tentativas permitidas
-	Timeout      time.Duration          // Tempo máximo de execução
-	Dependencies []string               // IDs das tarefas que precisam ser concluídas antes
+	ID             string                 // Identificador único da tarefa
+	Type           string                 // Tipo da tarefa
+	Description    string                 // Descrição da tarefa
+	ExpectedOutput string                 // Descrição do resultado esperado
+	OutputSchema   json.RawMessage        // JSON Schema da saída (opcional), verificado com SetOutputValidation
+	Input          map[string]interface{} // Dados de entrada
+	Output         map[string]interface{} // Dados de saída
+	Status         TaskStatus             // Estado atual da tarefa
+	Priority       int                    // Prioridade da tarefa (maior = mais prioritário)
+	CreatedAt      time.Time              // Data de criação
+	StartedAt      *time.Time             // Data de início
+	FinishedAt     *time.Time             // Data de conclusão
+	AssignedTo     string                 // ID do agente designado
+	Error          error                  // Erro ocorrido durante execução
+	Retries        int                    // Número de tentativas realizadas
+	MaxRetries     int                    // Número máximo de tentativas permitidas
+	Timeout        time.Duration          // Tempo máximo de execução
+	Deadline       time.Time              // Prazo absoluto (opcional); com Timeout, vale o que vencer primeiro
+	Dependencies   []string               // IDs das tarefas que precisam ser concluídas antes
+	Overrides      *overrides.Overrides   // Parâmetros do modelo definidos para esta tarefa
 }
 
 // NewTask cria uma nova tarefa
@@ -144,6 +155,25 @@ func (t *Task) IsFailed() bool {
 	return t.Status == TaskStatusFailed
 }
 
+// IsTimedOut verifica se a tarefa foi interrompida pelo prazo
+func (t *Task) IsTimedOut() bool {
+	return t.Status == TaskStatusTimeout
+}
+
+// Partial retorna o resultado parcial de uma tarefa interrompida pelo prazo
+func (t *Task) Partial() (string, bool) {
+	partial, ok := t.Output[partialKey].(string)
+	return partial, ok && partial != ""
+}
+
+// setPartial guarda o último resultado intermediário, devolvido se a tarefa exceder o prazo
+func (t *Task) setPartial(output string) {
+	if t.Output == nil {
+		t.Output = make(map[string]interface{})
+	}
+	t.Output[partialKey] = output
+}
+
 // IsCancelled verifica se a tarefa foi cancelada
 func (t *Task) IsCancelled() bool {
 	return t.Status == TaskStatusCancelled
@@ -2,8 +2,11 @@ package agents
 
 import (
 	"fmt"
+	"sort"
 	"sync"
 	"time"
+
+	"github.com/suissa/HiveMind/agents/presence"
 )
 
 // TaskManager gerencia a execução de tarefas
@@ -142,6 +145,62 @@ func (tm *TaskManager) GetHealthSignals() <-chan *AgentHealth {
 	return tm.healthChan
 }
 
+// WatchPresence acompanha os heartbeats dos agentes: cada mudança de situação vira um sinal
+// de saúde e as tarefas dos agentes indisponíveis voltam para a fila
+func (tm *TaskManager) WatchPresence(monitor *presence.Monitor) {
+	monitor.OnChange(func(status presence.Status) {
+		tm.EmitHealthSignal(&AgentHealth{
+			AgentName:     status.AgentID,
+			LastHeartbeat: status.LastSeen,
+			IsProcessing:  status.Load.Running > 0,
+		})
+		if !status.Available() {
+			tm.RerouteTasks(status.AgentID)
+		}
+	})
+}
+
+// RerouteTasks devolve para a fila, sem agente designado, as tarefas pendentes e em execução
+// do agente, e as retorna. Uma tarefa em execução conta como tentativa e falha quando esgota
+// as tentativas.
+func (tm *TaskManager) RerouteTasks(agentID string) []*Task {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+
+	queued := make(map[string]bool, len(tm.taskQueue))
+	for _, task := range tm.taskQueue {
+		queued[task.ID] = true
+	}
+
+	var rerouted []*Task
+	for _, task := range tm.tasks {
+		if task.AssignedTo != agentID || (task.Status != TaskStatusPending && task.Status != TaskStatusRunning) {
+			continue
+		}
+		if task.Status == TaskStatusRunning {
+			task.Retries++
+			if task.Retries > task.MaxRetries {
+				task.Status = TaskStatusFailed
+				task.Error = fmt.Errorf("agente %s parou de responder e a tarefa esgotou as tentativas", agentID)
+				continue
+			}
+			task.StartedAt = nil
+		}
+		task.Status = TaskStatusPending
+		task.AssignedTo = ""
+		rerouted = append(rerouted, task)
+	}
+
+	// Devolve as tarefas na ordem de criação
+	sort.Slice(rerouted, func(i, j int) bool { return rerouted[i].CreatedAt.Before(rerouted[j].CreatedAt) })
+	for _, task := range rerouted {
+		if !queued[task.ID] {
+			tm.taskQueue = append(tm.taskQueue, task)
+		}
+	}
+	return rerouted
+}
+
 // GetActiveAgentsCount retorna o número de agentes ativos
 func (tm *TaskManager) GetActiveAgentsCount() int {
 	tm.mu.RLock()
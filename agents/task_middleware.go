@@ -0,0 +1,208 @@
+package agents
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/golang-lru/v2/simplelru"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+
+	"github.com/suissa/HiveMind/agents/errs"
+	"github.com/suissa/HiveMind/agents/fairshare"
+	"github.com/suissa/HiveMind/agents/policy"
+	"github.com/suissa/HiveMind/agents/tenant"
+)
+
+// TaskHandler executa uma tarefa de uma equipe e retorna a saída produzida
+type TaskHandler func(ctx context.Context, task TaskConfig) (string, error)
+
+// TaskMiddleware envolve a execução das tarefas de uma equipe, como um middleware HTTP
+type TaskMiddleware func(next TaskHandler) TaskHandler
+
+// ChainTaskMiddleware aplica os middlewares ao handler; o primeiro é o mais externo
+func ChainTaskMiddleware(handler TaskHandler, middlewares ...TaskMiddleware) TaskHandler {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		handler = middlewares[i](handler)
+	}
+	return handler
+}
+
+// TaskPolicyMiddleware autoriza cada tarefa no motor de políticas (consulta hivemind/tasks)
+func TaskPolicyMiddleware(engine policy.Engine) TaskMiddleware {
+	return func(next TaskHandler) TaskHandler {
+		return func(ctx context.Context, task TaskConfig) (string, error) {
+			err := policy.Check(ctx, engine, policy.QueryTask, policy.TaskInput{
+				ID:          task.ID,
+				Tenant:      tenant.FromContext(ctx),
+				Description: task.Description,
+				Parameters: map[string]interface{}{
+					"name":        task.Name,
+					"assigned_to": task.AssignedTo,
+				},
+			})
+			if err != nil {
+				return "", err
+			}
+			return next(ctx, task)
+		}
+	}
+}
+
+// RateLimitTaskMiddleware limita a quantidade de tarefas iniciadas por segundo. A espera pela
+// vez respeita o contexto: uma tarefa cancelada ou com prazo vencido desiste com o erro do
+// contexto e devolve a vez que reservou. Zero não limita.
+func RateLimitTaskMiddleware(perSecond int) TaskMiddleware {
+	if perSecond <= 0 {
+		return func(next TaskHandler) TaskHandler { return next }
+	}
+	interval := time.Second / time.Duration(perSecond)
+	var (
+		next time.Time // Próximo início permitido
+		mu   sync.Mutex
+	)
+	return func(handler TaskHandler) TaskHandler {
+		return func(ctx context.Context, task TaskConfig) (string, error) {
+			mu.Lock()
+			now := time.Now()
+			if next.Before(now) {
+				next = now
+			}
+			at := next
+			next = next.Add(interval)
+			mu.Unlock()
+
+			if wait := time.Until(at); wait > 0 {
+				timer := time.NewTimer(wait)
+				select {
+				case <-timer.C:
+				case <-ctx.Done():
+					timer.Stop()
+					mu.Lock()
+					// Só a última reserva pode ser devolvida sem adiantar as seguintes
+					if next.Equal(at.Add(interval)) {
+						next = at
+					}
+					mu.Unlock()
+					return "", errs.FromContext("crew.RateLimit", ctx.Err())
+				}
+			}
+			if err := ctx.Err(); err != nil {
+				return "", errs.FromContext("crew.RateLimit", err)
+			}
+			return handler(ctx, task)
+		}
+	}
+}
+
+// FairShareTaskMiddleware reparte o despacho das tarefas entre os tenants e os workflows
+// pelas cotas do escalonador. Compartilhe o mesmo escalonador entre as equipes para que um
+// workflow não esgote a vez dos demais.
+func FairShareTaskMiddleware(scheduler *fairshare.Scheduler) TaskMiddleware {
+	return func(next TaskHandler) TaskHandler {
+		return func(ctx context.Context, task TaskConfig) (string, error) {
+			release, err := scheduler.Acquire(ctx, FairShareFlow(ctx))
+			if err != nil {
+				return "", err
+			}
+			defer release()
+			return next(ctx, task)
+		}
+	}
+}
+
+// TracingTaskMiddleware cria um span OpenTelemetry para cada tarefa
+func TracingTaskMiddleware(crew string) TaskMiddleware {
+	tracer := otel.Tracer("github.com/suissa/HiveMind/agents")
+	return func(next TaskHandler) TaskHandler {
+		return func(ctx context.Context, task TaskConfig) (string, error) {
+			ctx, span := tracer.Start(ctx, crew+".task")
+			defer span.End()
+			span.SetAttributes(
+				attribute.String("task.id", task.ID),
+				attribute.String("task.name", task.Name),
+				attribute.String("task.assigned_to", task.AssignedTo),
+			)
+
+			start := time.Now()
+			output, err := next(ctx, task)
+			span.SetAttributes(attribute.Int64("task.duration_ms", time.Since(start).Milliseconds()))
+			if err != nil {
+				span.RecordError(err)
+				span.SetStatus(codes.Error, err.Error())
+			}
+			return output, err
+		}
+	}
+}
+
+// TaskCacheConfig limita o cache de saídas de CacheTaskMiddleware
+type TaskCacheConfig struct {
+	MaxEntries int           // Saídas guardadas; as menos usadas saem primeiro (padrão: 1000)
+	TTL        time.Duration // Validade de cada saída; zero não expira
+}
+
+// DefaultTaskCacheEntries é o tamanho padrão do cache de saídas das tarefas
+const DefaultTaskCacheEntries = 1000
+
+// CacheTaskMiddleware reutiliza a saída de tarefas com entradas idênticas (nome, descrição
+// e agente designado) já concluídas com sucesso pela equipe no mesmo tenant. O cache guarda
+// até config.MaxEntries saídas, cada uma por config.TTL.
+func CacheTaskMiddleware(config TaskCacheConfig) TaskMiddleware {
+	if config.MaxEntries <= 0 {
+		config.MaxEntries = DefaultTaskCacheEntries
+	}
+	// NewLRU só falha com tamanho não positivo
+	outputs, _ := simplelru.NewLRU[string, cachedOutput](config.MaxEntries, nil)
+	var mu sync.Mutex
+	return func(next TaskHandler) TaskHandler {
+		return func(ctx context.Context, task TaskConfig) (string, error) {
+			key := taskInputKey(tenant.FromContext(ctx), task)
+
+			mu.Lock()
+			cached, ok := outputs.Get(key)
+			if ok && cached.expired() {
+				outputs.Remove(key)
+				ok = false
+			}
+			mu.Unlock()
+			if ok {
+				return cached.output, nil
+			}
+
+			output, err := next(ctx, task)
+			if err != nil {
+				return "", err
+			}
+
+			cached = cachedOutput{output: output}
+			if config.TTL > 0 {
+				cached.expires = time.Now().Add(config.TTL)
+			}
+			mu.Lock()
+			outputs.Add(key, cached)
+			mu.Unlock()
+			return output, nil
+		}
+	}
+}
+
+// cachedOutput é uma saída guardada por CacheTaskMiddleware
+type cachedOutput struct {
+	output  string
+	expires time.Time // Zero não expira
+}
+
+func (c cachedOutput) expired() bool {
+	return !c.expires.IsZero() && time.Now().After(c.expires)
+}
+
+// taskInputKey calcula a chave das entradas de uma tarefa no tenant
+func taskInputKey(tenantID string, task TaskConfig) string {
+	sum := sha256.Sum256([]byte(tenantID + "\x00" + task.Name + "\x00" + task.Description + "\x00" + task.AssignedTo))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,154 @@
+package agents
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/suissa/HiveMind/agents/errs"
+	"github.com/suissa/HiveMind/agents/tenant"
+)
+
+// countingHandler conta as execuções e devolve a descrição da tarefa como saída
+func countingHandler(calls *int) TaskHandler {
+	return func(ctx context.Context, task TaskConfig) (string, error) {
+		*calls++
+		if task.Description == "falha" {
+			return "", errors.New("falha na tarefa")
+		}
+		return task.Description, nil
+	}
+}
+
+func TestCacheTaskMiddlewareIsolatesTenants(t *testing.T) {
+	var calls int
+	run := ChainTaskMiddleware(countingHandler(&calls), CacheTaskMiddleware(TaskCacheConfig{}))
+	task := TaskConfig{Name: "pesquisa", Description: "mercado de café", AssignedTo: "analyst"}
+
+	acme := tenant.WithTenant(context.Background(), "acme")
+	for i := 0; i < 2; i++ {
+		if output, err := run(acme, task); err != nil || output != task.Description {
+			t.Fatalf("saída inesperada: %q %v", output, err)
+		}
+	}
+	if calls != 1 {
+		t.Fatalf("a segunda execução no mesmo tenant deveria vir do cache: %d execuções", calls)
+	}
+
+	// Outro tenant não reaproveita a saída, mesmo com as mesmas entradas
+	if _, err := run(tenant.WithTenant(context.Background(), "globex"), task); err != nil {
+		t.Fatal(err)
+	}
+	if calls != 2 {
+		t.Fatalf("esperava executar a tarefa no outro tenant: %d execuções", calls)
+	}
+
+	// Falhas não são guardadas
+	failing := TaskConfig{Name: "pesquisa", Description: "falha"}
+	run(acme, failing)
+	run(acme, failing)
+	if calls != 4 {
+		t.Fatalf("uma falha não deveria ficar no cache: %d execuções", calls)
+	}
+}
+
+func TestCacheTaskMiddlewareBounds(t *testing.T) {
+	var calls int
+	run := ChainTaskMiddleware(countingHandler(&calls), CacheTaskMiddleware(TaskCacheConfig{MaxEntries: 1}))
+	ctx := context.Background()
+	first := TaskConfig{Name: "a", Description: "primeira"}
+	second := TaskConfig{Name: "b", Description: "segunda"}
+
+	run(ctx, first)
+	run(ctx, second)
+	run(ctx, first)
+	if calls != 3 {
+		t.Fatalf("a primeira saída deveria ter saído do cache cheio: %d execuções", calls)
+	}
+
+	calls = 0
+	run = ChainTaskMiddleware(countingHandler(&calls), CacheTaskMiddleware(TaskCacheConfig{TTL: 10 * time.Millisecond}))
+	run(ctx, first)
+	run(ctx, first)
+	time.Sleep(30 * time.Millisecond)
+	run(ctx, first)
+	if calls != 2 {
+		t.Fatalf("a saída deveria expirar após o TTL: %d execuções", calls)
+	}
+}
+
+func TestRateLimitTaskMiddlewareRespectsContext(t *testing.T) {
+	var calls int
+	run := ChainTaskMiddleware(countingHandler(&calls), RateLimitTaskMiddleware(2))
+	task := TaskConfig{Name: "a"}
+
+	start := time.Now()
+	if _, err := run(context.Background(), task); err != nil {
+		t.Fatal(err)
+	}
+
+	// Quem desiste de esperar a vez volta com o erro do contexto, sem executar a tarefa
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := run(ctx, task); !errors.Is(err, errs.ErrTimeout) {
+		t.Fatalf("esperava o timeout da espera: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 250*time.Millisecond {
+		t.Fatalf("a espera deveria terminar com o contexto, e não na vez reservada: %v", elapsed)
+	}
+
+	// A vez devolvida é usada pela próxima tarefa, que não espera um intervalo a mais
+	if _, err := run(context.Background(), task); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed > 900*time.Millisecond {
+		t.Fatalf("a vez da tarefa cancelada deveria ter sido devolvida: %v", elapsed)
+	}
+	if calls != 2 {
+		t.Fatalf("esperava duas execuções, obtidas %d", calls)
+	}
+}
+
+func TestTrainingCrewUse(t *testing.T) {
+	crew := NewTrainingCrew(nil)
+	for _, role := range []string{"training", "chapter", "feedback", "account"} {
+		crew.AddAgent(NewCognitiveAgent(role+"-1", role, role, 1, "gpt-4", role, "treinar", nil))
+	}
+
+	var stages []string
+	crew.Use(func(next TaskHandler) TaskHandler {
+		return func(ctx context.Context, task TaskConfig) (string, error) {
+			stages = append(stages, task.ID+"@"+task.AssignedTo)
+			return next(ctx, task)
+		}
+	})
+	project := &TrainingProject{Name: "Go", Description: "Curso de Go"}
+	results, err := crew.ExecuteWorkflow(project)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"training@training-1", "chapters@chapter-1", "feedback@feedback-1"}
+	if len(stages) != len(want) {
+		t.Fatalf("etapas inesperadas: %v", stages)
+	}
+	for i := range want {
+		if stages[i] != want[i] {
+			t.Fatalf("etapas inesperadas: %v", stages)
+		}
+	}
+	if results.Training == "" || results.Chapters == "" || results.Feedback == "" {
+		t.Fatalf("resultados incompletos: %+v", results)
+	}
+
+	// Um middleware que recusa a etapa interrompe o workflow
+	denied := errs.New(errs.ErrValidation, "test", "etapa recusada")
+	crew.Use(func(next TaskHandler) TaskHandler {
+		return func(ctx context.Context, task TaskConfig) (string, error) {
+			return "", denied
+		}
+	})
+	if _, err := crew.ExecuteWorkflow(project); !errors.Is(err, errs.ErrValidation) {
+		t.Fatalf("esperava a recusa do middleware: %v", err)
+	}
+}
@@ -14,7 +14,8 @@ import (
 )
 
 var (
-	meter metric.Meter
+	meter    metric.Meter
+	provider *sdkmetric.MeterProvider
 
 	// Métricas do agente
 	taskProcessingDuration metric.Float64Histogram
@@ -34,7 +35,7 @@ func InitTelemetry(serviceName string) error {
 	}
 
 	// Criar provedor de métricas
-	provider := sdkmetric.NewMeterProvider(
+	provider = sdkmetric.NewMeterProvider(
 		sdkmetric.WithReader(
 			sdkmetric.NewPeriodicReader(exporter,
 				sdkmetric.WithInterval(3*time.Second),
@@ -145,3 +146,14 @@ func RecordResourceUsage(ctx context.Context, agentName string, memoryBytes floa
 func GetMetrics() metric.Meter {
 	return meter
 }
+
+// Shutdown exporta as métricas pendentes e encerra o provedor
+func Shutdown(ctx context.Context) error {
+	if provider == nil {
+		return nil
+	}
+	if err := provider.Shutdown(ctx); err != nil {
+		return fmt.Errorf("erro ao encerrar telemetria: %v", err)
+	}
+	return nil
+}
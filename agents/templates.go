@@ -0,0 +1,115 @@
+package agents
+
+import (
+	"fmt"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/suissa/HiveMind/agents/errs"
+	"github.com/suissa/HiveMind/agents/llm"
+	"github.com/suissa/HiveMind/agents/memory"
+	"github.com/suissa/HiveMind/agents/templates"
+)
+
+// Workflow é um template da galeria instanciado: o projeto, com as tarefas, e a
+// configuração dos agentes que o executam
+type Workflow struct {
+	Template string
+	Project  *MarketingProject
+	Agents   *AgentsConfig
+}
+
+// InstantiateTemplate instancia um template embutido (templates.List) com as variáveis
+// informadas; as variáveis omitidas recebem os valores padrão do template
+func InstantiateTemplate(name string, vars map[string]string) (*Workflow, error) {
+	t, ok := templates.Get(name)
+	if !ok {
+		return nil, errs.New(errs.ErrNotFound, "agents.InstantiateTemplate", "template %s não encontrado", name)
+	}
+	return NewWorkflow(t, vars)
+}
+
+// NewWorkflow instancia o template (embutido ou lido com templates.Load)
+func NewWorkflow(t templates.Template, vars map[string]string) (*Workflow, error) {
+	data, err := t.Render(vars)
+	if err != nil {
+		return nil, err
+	}
+	var doc struct {
+		Project struct {
+			Name           string   `yaml:"name"`
+			Objective      string   `yaml:"objective"`
+			TargetAudience []string `yaml:"target_audience"`
+			Channels       []string `yaml:"channels"`
+			Constraints    []string `yaml:"constraints"`
+		} `yaml:"project"`
+		AgentsConfig `yaml:",inline"`
+		Tasks        []TaskConfig `yaml:"tasks"`
+	}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, errs.Wrap(errs.ErrValidation, "agents.NewWorkflow", err, "erro ao decodificar o template %s", t.Name)
+	}
+
+	agentIDs := make(map[string]bool, len(doc.Agents))
+	for _, agent := range doc.Agents {
+		agentIDs[agent.ID] = true
+	}
+	for _, task := range doc.Tasks {
+		if !agentIDs[task.AssignedTo] {
+			return nil, errs.New(errs.ErrValidation, "agents.NewWorkflow", "template %s: tarefa %s atribuída ao agente inexistente %s", t.Name, task.ID, task.AssignedTo)
+		}
+	}
+
+	project := &MarketingProject{
+		Name:           doc.Project.Name,
+		Objective:      doc.Project.Objective,
+		TargetAudience: doc.Project.TargetAudience,
+		Channels:       doc.Project.Channels,
+		Constraints:    doc.Project.Constraints,
+	}
+	for _, task := range doc.Tasks {
+		project.AddTask(task)
+	}
+	agentsConfig := doc.AgentsConfig
+	return &Workflow{Template: t.Name, Project: project, Agents: &agentsConfig}, nil
+}
+
+// Tasks retorna a configuração das tarefas do workflow
+func (w *Workflow) Tasks() *TasksConfig {
+	return &TasksConfig{Tasks: w.Project.Tasks}
+}
+
+// NewAgents cria os agentes cognitivos da configuração, com as personas compostas. Com
+// provider nil os agentes ficam sem LLM (o Runtime.RegisterAgent atribui o padrão).
+func (w *Workflow) NewAgents(memManager memory.MemoryManager, provider llm.Provider) ([]*CognitiveAgent, error) {
+	list := make([]*CognitiveAgent, 0, len(w.Agents.Agents))
+	for _, config := range w.Agents.Agents {
+		agent := NewCognitiveAgent(config.ID, config.Name, config.Description, config.MaxRounds,
+			config.Model, config.Role, config.Goal, memManager)
+		agent.Tenant = config.Tenant
+		p, err := w.Agents.Persona(config)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao compor a persona do template %s: %w", w.Template, err)
+		}
+		agent.SetPersona(p)
+		if provider != nil {
+			agent.SetLLM(provider)
+		}
+		list = append(list, agent)
+	}
+	return list, nil
+}
+
+// Crew cria a equipe com os agentes do workflow, pronta para ExecuteWorkflowContext(ctx,
+// w.Project)
+func (w *Workflow) Crew(memManager memory.MemoryManager, provider llm.Provider) (*MarketingCrew, error) {
+	list, err := w.NewAgents(memManager, provider)
+	if err != nil {
+		return nil, err
+	}
+	crew := NewMarketingCrew(memManager)
+	for _, agent := range list {
+		crew.AddAgent(agent)
+	}
+	return crew, nil
+}
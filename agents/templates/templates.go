@@ -0,0 +1,206 @@
+// Package templates é a galeria de workflows prontos (pesquisa de mercado, pipeline de
+// conteúdo, revisão de código e análise de incidentes). Cada template define o projeto, os
+// agentes e as tarefas de uma equipe em YAML, com variáveis {{.nome}} preenchidas na
+// instanciação. A conversão para a configuração dos agentes fica em agents.InstantiateTemplate.
+package templates
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"text/template"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/suissa/HiveMind/agents/errs"
+)
+
+// Variable é uma variável do template
+type Variable struct {
+	Name        string `yaml:"name"`
+	Description string `yaml:"description"`
+	Required    bool   `yaml:"required"`
+	Default     string `yaml:"default"`
+}
+
+// Template é um workflow parametrizado
+type Template struct {
+	Name        string
+	Description string
+	Variables   []Variable
+
+	body yaml.MapSlice // Projeto, agentes e tarefas, com as variáveis ainda não preenchidas
+}
+
+//go:embed builtin/*.yaml
+var builtin embed.FS
+
+var gallery = make(map[string]Template)
+
+func init() {
+	entries, err := builtin.ReadDir("builtin")
+	if err != nil {
+		panic(fmt.Sprintf("templates: erro ao ler templates embutidos: %v", err))
+	}
+	for _, entry := range entries {
+		data, err := builtin.ReadFile(path.Join("builtin", entry.Name()))
+		if err != nil {
+			panic(fmt.Sprintf("templates: erro ao ler %s: %v", entry.Name(), err))
+		}
+		t, err := Parse(data)
+		if err != nil {
+			panic(fmt.Sprintf("templates: %s: %v", entry.Name(), err))
+		}
+		gallery[t.Name] = t
+	}
+}
+
+// List retorna os templates embutidos, em ordem alfabética
+func List() []Template {
+	list := make([]Template, 0, len(gallery))
+	for _, t := range gallery {
+		list = append(list, t)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
+}
+
+// Get retorna o template embutido com o nome informado
+func Get(name string) (Template, bool) {
+	t, ok := gallery[name]
+	return t, ok
+}
+
+// Load lê um template de um arquivo YAML, no mesmo formato dos embutidos
+func Load(filename string) (Template, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return Template{}, fmt.Errorf("erro ao ler o template: %v", err)
+	}
+	return Parse(data)
+}
+
+// Parse interpreta um template: name, description e variables descrevem o template, e as
+// demais seções (project, agents, tasks...) são o workflow instanciado
+func Parse(data []byte) (Template, error) {
+	var header struct {
+		Name        string     `yaml:"name"`
+		Description string     `yaml:"description"`
+		Variables   []Variable `yaml:"variables"`
+	}
+	if err := yaml.Unmarshal(data, &header); err != nil {
+		return Template{}, errs.Wrap(errs.ErrValidation, "templates.Parse", err, "erro ao decodificar o template")
+	}
+	if header.Name == "" {
+		return Template{}, errs.New(errs.ErrValidation, "templates.Parse", "template sem nome")
+	}
+	var document yaml.MapSlice
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return Template{}, errs.Wrap(errs.ErrValidation, "templates.Parse", err, "erro ao decodificar o template %s", header.Name)
+	}
+
+	t := Template{Name: header.Name, Description: strings.TrimSpace(header.Description), Variables: header.Variables}
+	for _, item := range document {
+		switch item.Key {
+		case "name", "description", "variables":
+		default:
+			t.body = append(t.body, item)
+		}
+	}
+	// Valida a sintaxe e as variáveis usadas já na leitura, para que erros do template não
+	// apareçam só na instanciação
+	declared := make(map[string]string, len(t.Variables))
+	for _, variable := range t.Variables {
+		declared[variable.Name] = ""
+	}
+	if _, err := render(t.body, declared, template.New(t.Name).Option("missingkey=error")); err != nil {
+		return Template{}, errs.Wrap(errs.ErrValidation, "templates.Parse", err, "template %s inválido", t.Name)
+	}
+	return t, nil
+}
+
+// Resolve completa as variáveis com os valores padrão e valida as obrigatórias e as desconhecidas
+func (t Template) Resolve(vars map[string]string) (map[string]string, error) {
+	values := make(map[string]string, len(t.Variables))
+	known := make(map[string]bool, len(t.Variables))
+	var missing []string
+	for _, variable := range t.Variables {
+		known[variable.Name] = true
+		value, ok := vars[variable.Name]
+		if !ok || value == "" {
+			value = variable.Default
+		}
+		if value == "" && variable.Required {
+			missing = append(missing, variable.Name)
+		}
+		values[variable.Name] = value
+	}
+	if len(missing) > 0 {
+		return nil, errs.New(errs.ErrValidation, "templates.Render", "template %s: variáveis obrigatórias ausentes: %s", t.Name, strings.Join(missing, ", "))
+	}
+	for name := range vars {
+		if !known[name] {
+			return nil, errs.New(errs.ErrValidation, "templates.Render", "template %s não tem a variável %s", t.Name, name)
+		}
+	}
+	return values, nil
+}
+
+// Render preenche as variáveis e retorna o workflow em YAML (project, agents, tasks...). Os
+// valores são inseridos nos textos já decodificados, então não precisam de escape.
+func (t Template) Render(vars map[string]string) ([]byte, error) {
+	values, err := t.Resolve(vars)
+	if err != nil {
+		return nil, err
+	}
+	body, err := render(t.body, values, template.New(t.Name).Option("missingkey=error"))
+	if err != nil {
+		return nil, errs.Wrap(errs.ErrValidation, "templates.Render", err, "erro ao instanciar o template %s", t.Name)
+	}
+	return yaml.Marshal(body)
+}
+
+// render preenche as variáveis nos textos do YAML, recursivamente
+func render(value interface{}, vars map[string]string, tmpl *template.Template) (interface{}, error) {
+	switch v := value.(type) {
+	case yaml.MapSlice:
+		out := make(yaml.MapSlice, len(v))
+		for i, item := range v {
+			rendered, err := render(item.Value, vars, tmpl)
+			if err != nil {
+				return nil, fmt.Errorf("%v: %w", item.Key, err)
+			}
+			out[i] = yaml.MapItem{Key: item.Key, Value: rendered}
+		}
+		return out, nil
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, item := range v {
+			rendered, err := render(item, vars, tmpl)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = rendered
+		}
+		return out, nil
+	case string:
+		if !strings.Contains(v, "{{") {
+			return v, nil
+		}
+		parsed, err := tmpl.New("").Parse(v)
+		if err != nil {
+			return nil, err
+		}
+		var b bytes.Buffer
+		if err := parsed.Execute(&b, vars); err != nil {
+			return nil, err
+		}
+		return b.String(), nil
+	default:
+		return v, nil
+	}
+}
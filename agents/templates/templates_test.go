@@ -0,0 +1,79 @@
+package templates
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/suissa/HiveMind/agents/errs"
+)
+
+func TestBuiltinGallery(t *testing.T) {
+	var names []string
+	for _, tmpl := range List() {
+		names = append(names, tmpl.Name)
+	}
+	if got := strings.Join(names, ","); got != "code_review,content_pipeline,incident_analysis,market_research" {
+		t.Fatalf("templates inesperados: %s", got)
+	}
+
+	// Todos os templates embutidos instanciam com as variáveis obrigatórias
+	for _, tmpl := range List() {
+		vars := make(map[string]string)
+		for _, variable := range tmpl.Variables {
+			if variable.Required {
+				vars[variable.Name] = "x: {y}"
+			}
+		}
+		data, err := tmpl.Render(vars)
+		if err != nil {
+			t.Fatalf("%s: %v", tmpl.Name, err)
+		}
+		var doc struct {
+			Project struct{ Name string }
+			Agents  []struct{ ID string }
+			Tasks   []struct {
+				AssignedTo string `yaml:"assigned_to"`
+			}
+		}
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			t.Fatalf("%s: %v", tmpl.Name, err)
+		}
+		if len(doc.Agents) == 0 || len(doc.Tasks) == 0 || strings.Contains(string(data), "{{") {
+			t.Fatalf("%s: workflow inesperado:\n%s", tmpl.Name, data)
+		}
+	}
+}
+
+func TestRenderFillsDefaultsAndValidates(t *testing.T) {
+	tmpl, ok := Get("market_research")
+	if !ok {
+		t.Fatal("market_research não encontrado")
+	}
+	if _, err := tmpl.Render(nil); !errors.Is(err, errs.ErrValidation) || !strings.Contains(err.Error(), "product") {
+		t.Fatalf("esperava o erro da variável obrigatória: %v", err)
+	}
+	if _, err := tmpl.Render(map[string]string{"product": "café", "budget": "10"}); !errors.Is(err, errs.ErrValidation) || !strings.Contains(err.Error(), "budget") {
+		t.Fatalf("esperava o erro da variável desconhecida: %v", err)
+	}
+
+	data, err := tmpl.Render(map[string]string{"product": "café especial"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "Entender o mercado de café especial em Brasil") {
+		t.Fatalf("variáveis não preenchidas:\n%s", data)
+	}
+}
+
+func TestParseRejectsUndeclaredVariables(t *testing.T) {
+	_, err := Parse([]byte("name: t\nvariables: [{name: a}]\ntasks:\n  - description: \"{{.a}} {{.b}}\"\n"))
+	if !errors.Is(err, errs.ErrValidation) || !strings.Contains(err.Error(), "b") {
+		t.Fatalf("esperava o erro da variável não declarada: %v", err)
+	}
+	if _, err := Parse([]byte("description: sem nome\n")); !errors.Is(err, errs.ErrValidation) {
+		t.Fatalf("esperava o erro do template sem nome: %v", err)
+	}
+}
@@ -0,0 +1,73 @@
+package tenant
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// DefaultTenant é o tenant usado quando nenhum namespace é informado
+const DefaultTenant = "default"
+
+// HeaderName é o header HTTP que identifica o tenant na API de gerenciamento
+const HeaderName = "X-HiveMind-Tenant"
+
+var validTenant = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,62}$`)
+
+type contextKey struct{}
+
+// WithTenant retorna um contexto associado ao tenant informado
+func WithTenant(ctx context.Context, tenantID string) context.Context {
+	if tenantID == "" {
+		tenantID = DefaultTenant
+	}
+	return context.WithValue(ctx, contextKey{}, tenantID)
+}
+
+// FromContext retorna o tenant associado ao contexto ou o tenant padrão
+func FromContext(ctx context.Context) string {
+	if ctx == nil {
+		return DefaultTenant
+	}
+	if tenantID, ok := ctx.Value(contextKey{}).(string); ok && tenantID != "" {
+		return tenantID
+	}
+	return DefaultTenant
+}
+
+// Lookup retorna o tenant associado ao contexto, indicando se ele foi definido
+func Lookup(ctx context.Context) (string, bool) {
+	if ctx == nil {
+		return "", false
+	}
+	tenantID, ok := ctx.Value(contextKey{}).(string)
+	return tenantID, ok && tenantID != ""
+}
+
+// Validate verifica se o identificador do tenant é válido
+func Validate(tenantID string) error {
+	if !validTenant.MatchString(tenantID) {
+		return fmt.Errorf("tenant inválido: %q (use letras minúsculas, números, '-' ou '_')", tenantID)
+	}
+	return nil
+}
+
+// Namespace prefixa um nome (fila, chave, tópico) com o tenant.
+// O tenant padrão mantém o nome original para compatibilidade.
+func Namespace(tenantID, name string) string {
+	if tenantID == "" || tenantID == DefaultTenant {
+		return name
+	}
+	return tenantID + "." + name
+}
+
+// ClassName gera o nome de uma classe do Weaviate isolada por tenant.
+// O Weaviate exige nomes iniciados por letra maiúscula e sem hífens.
+func ClassName(tenantID, class string) string {
+	if tenantID == "" || tenantID == DefaultTenant {
+		return class
+	}
+	suffix := strings.ReplaceAll(tenantID, "-", "_")
+	return class + "_" + strings.ToUpper(suffix[:1]) + suffix[1:]
+}
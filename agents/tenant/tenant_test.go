@@ -0,0 +1,99 @@
+package tenant
+
+import (
+	"context"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	cases := []struct {
+		tenantID string
+		valid    bool
+	}{
+		{"acme", true},
+		{"acme-corp", true},
+		{"acme_corp", true},
+		{"0day", true},
+		{DefaultTenant, true},
+		{"", false},
+		{"Acme", false},
+		{"-acme", false},
+		{"_acme", false},
+		{"acme.corp", false},
+		{"acme corp", false},
+		{"acme:corp", false},
+	}
+	for _, c := range cases {
+		if err := Validate(c.tenantID); (err == nil) != c.valid {
+			t.Errorf("Validate(%q) = %v, esperado válido=%v", c.tenantID, err, c.valid)
+		}
+	}
+
+	// O limite é de 63 caracteres
+	long := "a"
+	for len(long) < 63 {
+		long += "b"
+	}
+	if err := Validate(long); err != nil {
+		t.Errorf("63 caracteres deveriam ser aceitos: %v", err)
+	}
+	if err := Validate(long + "c"); err == nil {
+		t.Error("64 caracteres deveriam ser recusados")
+	}
+}
+
+func TestNamespace(t *testing.T) {
+	cases := []struct {
+		tenantID, name, want string
+	}{
+		{"", "tasks", "tasks"},
+		{DefaultTenant, "tasks", "tasks"},
+		{"acme", "tasks", "acme.tasks"},
+		{"acme", "hivemind.events", "acme.hivemind.events"},
+		{"acme-corp", "memory:agent-1", "acme-corp.memory:agent-1"},
+	}
+	for _, c := range cases {
+		if got := Namespace(c.tenantID, c.name); got != c.want {
+			t.Errorf("Namespace(%q, %q) = %q, esperado %q", c.tenantID, c.name, got, c.want)
+		}
+	}
+}
+
+func TestClassName(t *testing.T) {
+	cases := []struct {
+		tenantID, class, want string
+	}{
+		{"", "Memory", "Memory"},
+		{DefaultTenant, "Memory", "Memory"},
+		{"acme", "Memory", "Memory_Acme"},
+		{"acme-corp", "Memory", "Memory_Acme_corp"},
+		{"0day", "Memory", "Memory_0day"},
+	}
+	for _, c := range cases {
+		if got := ClassName(c.tenantID, c.class); got != c.want {
+			t.Errorf("ClassName(%q, %q) = %q, esperado %q", c.tenantID, c.class, got, c.want)
+		}
+	}
+}
+
+func TestContext(t *testing.T) {
+	if got := FromContext(context.Background()); got != DefaultTenant {
+		t.Errorf("sem tenant, esperado %q, obtido %q", DefaultTenant, got)
+	}
+	if _, ok := Lookup(context.Background()); ok {
+		t.Error("Lookup não deveria encontrar tenant em um contexto vazio")
+	}
+
+	ctx := WithTenant(context.Background(), "acme")
+	if got := FromContext(ctx); got != "acme" {
+		t.Errorf("esperado acme, obtido %q", got)
+	}
+	if got, ok := Lookup(ctx); !ok || got != "acme" {
+		t.Errorf("Lookup = %q %v, esperado acme", got, ok)
+	}
+
+	// Um tenant vazio vira o padrão
+	if got, ok := Lookup(WithTenant(context.Background(), "")); !ok || got != DefaultTenant {
+		t.Errorf("Lookup = %q %v, esperado %q", got, ok, DefaultTenant)
+	}
+}
@@ -0,0 +1,234 @@
+// Package timing mede onde cada agente gasta o tempo de um workflow: chamadas ao LLM, às
+// ferramentas, operações da memória e espera na fila antes de a tarefa começar. O Recorder
+// viaja no contexto do workflow e cada ponto instrumentado chama Record; o Breakdown resume
+// os tempos por agente e exporta as pilhas no formato folded dos flame graphs.
+package timing
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Categorias dos tempos medidos
+const (
+	Task      = "task"       // Execução completa da tarefa pelo agente
+	LLM       = "llm"        // Chamadas ao LLM
+	Tool      = "tool"       // Chamadas às ferramentas
+	Memory    = "memory"     // Operações da memória
+	QueueWait = "queue_wait" // Espera entre o despacho da tarefa e o início da execução
+)
+
+// Span é um intervalo medido de um agente
+type Span struct {
+	AgentID  string        `json:"agent_id"`
+	Category string        `json:"category"`
+	Name     string        `json:"name"` // Tarefa, modelo, ferramenta ou operação da memória
+	Start    time.Time     `json:"start"`
+	Duration time.Duration `json:"duration"`
+}
+
+// Recorder acumula os intervalos medidos durante um workflow
+type Recorder struct {
+	spans []Span
+	mu    sync.Mutex
+}
+
+// NewRecorder cria um Recorder vazio
+func NewRecorder() *Recorder {
+	return &Recorder{}
+}
+
+// Add registra um intervalo
+func (r *Recorder) Add(span Span) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.spans = append(r.spans, span)
+}
+
+// Spans retorna uma cópia dos intervalos registrados, na ordem de registro
+func (r *Recorder) Spans() []Span {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Span(nil), r.spans...)
+}
+
+type (
+	recorderKey struct{}
+	agentKey    struct{}
+	queuedKey   struct{}
+)
+
+// WithRecorder instala o Recorder que mede as chamadas feitas com o contexto
+func WithRecorder(ctx context.Context, r *Recorder) context.Context {
+	return context.WithValue(ctx, recorderKey{}, r)
+}
+
+// FromContext retorna o Recorder do contexto, se houver
+func FromContext(ctx context.Context) (*Recorder, bool) {
+	if ctx == nil {
+		return nil, false
+	}
+	r, ok := ctx.Value(recorderKey{}).(*Recorder)
+	return r, ok && r != nil
+}
+
+// WithAgent atribui ao agente as medições sem agente explícito feitas com o contexto, como
+// as operações da memória disparadas por ferramentas
+func WithAgent(ctx context.Context, agentID string) context.Context {
+	return context.WithValue(ctx, agentKey{}, agentID)
+}
+
+// WithQueued registra quando a tarefa foi despachada, para medir a espera até o início
+func WithQueued(ctx context.Context, at time.Time) context.Context {
+	return context.WithValue(ctx, queuedKey{}, at)
+}
+
+// QueuedAt retorna quando a tarefa do contexto foi despachada, se informado
+func QueuedAt(ctx context.Context) (time.Time, bool) {
+	at, ok := ctx.Value(queuedKey{}).(time.Time)
+	return at, ok && !at.IsZero()
+}
+
+// Record registra no Recorder do contexto o intervalo de start até agora. Sem agentID vale o
+// agente de WithAgent; sem Recorder no contexto não faz nada.
+func Record(ctx context.Context, agentID, category, name string, start time.Time) {
+	r, ok := FromContext(ctx)
+	if !ok {
+		return
+	}
+	if agentID == "" {
+		agentID, _ = ctx.Value(agentKey{}).(string)
+	}
+	r.Add(Span{AgentID: agentID, Category: category, Name: name, Start: start, Duration: time.Since(start)})
+}
+
+// AgentTiming é o tempo de um agente no workflow por categoria
+type AgentTiming struct {
+	AgentID   string         `json:"agent_id"`
+	Tasks     int            `json:"tasks"`
+	Total     time.Duration  `json:"total"` // Tempo executando tarefas
+	LLM       time.Duration  `json:"llm"`
+	Tool      time.Duration  `json:"tool"`
+	Memory    time.Duration  `json:"memory"`
+	QueueWait time.Duration  `json:"queue_wait"` // Fora do Total: a tarefa ainda não tinha começado
+	Other     time.Duration  `json:"other"`      // Tempo das tarefas fora das categorias medidas
+	Calls     map[string]int `json:"calls"`      // Intervalos medidos por categoria
+}
+
+// Breakdown resume os tempos de um workflow por agente
+type Breakdown struct {
+	Agents []AgentTiming `json:"agents"` // Do agente com mais tempo ao com menos
+
+	spans []Span
+}
+
+// Breakdown resume os intervalos registrados até agora
+func (r *Recorder) Breakdown() *Breakdown {
+	spans := r.Spans()
+	byAgent := make(map[string]*AgentTiming)
+	for _, span := range spans {
+		agent, ok := byAgent[span.AgentID]
+		if !ok {
+			agent = &AgentTiming{AgentID: span.AgentID, Calls: make(map[string]int)}
+			byAgent[span.AgentID] = agent
+		}
+		agent.Calls[span.Category]++
+		switch span.Category {
+		case Task:
+			agent.Tasks++
+			agent.Total += span.Duration
+		case LLM:
+			agent.LLM += span.Duration
+		case Tool:
+			agent.Tool += span.Duration
+		case Memory:
+			agent.Memory += span.Duration
+		case QueueWait:
+			agent.QueueWait += span.Duration
+		}
+	}
+
+	b := &Breakdown{spans: spans}
+	for _, agent := range byAgent {
+		if other := agent.Total - agent.LLM - agent.Tool - agent.Memory; other > 0 {
+			agent.Other = other
+		}
+		b.Agents = append(b.Agents, *agent)
+	}
+	sort.Slice(b.Agents, func(i, j int) bool {
+		ti, tj := b.Agents[i].Total+b.Agents[i].QueueWait, b.Agents[j].Total+b.Agents[j].QueueWait
+		if ti != tj {
+			return ti > tj
+		}
+		return b.Agents[i].AgentID < b.Agents[j].AgentID
+	})
+	return b
+}
+
+// Agent retorna os tempos do agente informado
+func (b *Breakdown) Agent(agentID string) (AgentTiming, bool) {
+	for _, agent := range b.Agents {
+		if agent.AgentID == agentID {
+			return agent, true
+		}
+	}
+	return AgentTiming{}, false
+}
+
+// Bottleneck retorna o agente e a categoria (llm, tool, memory, queue_wait ou other) com o
+// maior tempo acumulado no workflow
+func (b *Breakdown) Bottleneck() (agentID, category string, d time.Duration) {
+	for _, agent := range b.Agents {
+		for _, c := range []struct {
+			name string
+			d    time.Duration
+		}{{LLM, agent.LLM}, {Tool, agent.Tool}, {Memory, agent.Memory}, {QueueWait, agent.QueueWait}, {"other", agent.Other}} {
+			if c.d > d {
+				agentID, category, d = agent.AgentID, c.name, c.d
+			}
+		}
+	}
+	return agentID, category, d
+}
+
+// Folded exporta os intervalos como pilhas "agente;categoria;nome microssegundos", uma por
+// linha, o formato lido por flamegraph.pl e speedscope. O tempo das tarefas fora das
+// categorias medidas aparece como "agente;task;other".
+func (b *Breakdown) Folded() string {
+	totals := make(map[string]time.Duration)
+	for _, span := range b.spans {
+		if span.Category == Task {
+			continue
+		}
+		totals[fmt.Sprintf("%s;%s;%s", frame(span.AgentID), span.Category, frame(span.Name))] += span.Duration
+	}
+	for _, agent := range b.Agents {
+		if agent.Other > 0 {
+			totals[fmt.Sprintf("%s;%s;other", frame(agent.AgentID), Task)] += agent.Other
+		}
+	}
+
+	stacks := make([]string, 0, len(totals))
+	for stack := range totals {
+		stacks = append(stacks, stack)
+	}
+	sort.Strings(stacks)
+
+	var out strings.Builder
+	for _, stack := range stacks {
+		fmt.Fprintf(&out, "%s %d\n", stack, totals[stack].Microseconds())
+	}
+	return out.String()
+}
+
+// frame remove os separadores do formato folded dos nomes
+func frame(name string) string {
+	if name == "" {
+		return "unknown"
+	}
+	return strings.NewReplacer(";", "_", " ", "_", "\n", "_").Replace(name)
+}
@@ -0,0 +1,66 @@
+package timing
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBreakdown(t *testing.T) {
+	r := NewRecorder()
+	for _, span := range []Span{
+		{AgentID: "writer", Category: QueueWait, Name: "copy", Duration: 2 * time.Second},
+		{AgentID: "writer", Category: Task, Name: "copy", Duration: 10 * time.Second},
+		{AgentID: "writer", Category: LLM, Name: "gpt-4", Duration: 6 * time.Second},
+		{AgentID: "writer", Category: LLM, Name: "gpt-4", Duration: 1 * time.Second},
+		{AgentID: "writer", Category: Memory, Name: "search_similar", Duration: 500 * time.Millisecond},
+		{AgentID: "analyst", Category: Task, Name: "research", Duration: 4 * time.Second},
+		{AgentID: "analyst", Category: Tool, Name: "web search", Duration: 3 * time.Second},
+	} {
+		r.Add(span)
+	}
+
+	b := r.Breakdown()
+	if len(b.Agents) != 2 || b.Agents[0].AgentID != "writer" {
+		t.Fatalf("agentes = %+v; esperava writer primeiro", b.Agents)
+	}
+	writer, _ := b.Agent("writer")
+	if writer.Total != 10*time.Second || writer.LLM != 7*time.Second || writer.QueueWait != 2*time.Second ||
+		writer.Other != 2500*time.Millisecond || writer.Calls[LLM] != 2 || writer.Tasks != 1 {
+		t.Fatalf("tempos do writer = %+v", writer)
+	}
+
+	if agent, category, d := b.Bottleneck(); agent != "writer" || category != LLM || d != 7*time.Second {
+		t.Fatalf("gargalo = %s/%s %v; esperava writer/llm 7s", agent, category, d)
+	}
+
+	folded := b.Folded()
+	for _, line := range []string{"writer;llm;gpt-4 7000000", "analyst;tool;web_search 3000000", "writer;task;other 2500000", "writer;queue_wait;copy 2000000"} {
+		if !strings.Contains(folded, line+"\n") {
+			t.Errorf("Folded() sem a linha %q:\n%s", line, folded)
+		}
+	}
+}
+
+func TestRecordFromContext(t *testing.T) {
+	// Sem Recorder no contexto a medição é ignorada
+	Record(context.Background(), "a", LLM, "m", time.Now())
+
+	r := NewRecorder()
+	ctx := WithAgent(WithRecorder(context.Background(), r), "writer")
+	Record(ctx, "", Memory, "store", time.Now().Add(-time.Second))
+	Record(ctx, "analyst", Tool, "search", time.Now())
+
+	spans := r.Spans()
+	if len(spans) != 2 || spans[0].AgentID != "writer" || spans[0].Duration < time.Second || spans[1].AgentID != "analyst" {
+		t.Fatalf("intervalos = %+v", spans)
+	}
+	if _, ok := QueuedAt(ctx); ok {
+		t.Fatal("não esperava instante de despacho")
+	}
+	at := time.Now()
+	if got, ok := QueuedAt(WithQueued(ctx, at)); !ok || !got.Equal(at) {
+		t.Fatalf("QueuedAt = %v, %v", got, ok)
+	}
+}
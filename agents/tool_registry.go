@@ -0,0 +1,324 @@
+package agents
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/suissa/HiveMind/agents/blob"
+	"github.com/suissa/HiveMind/agents/memory"
+	"github.com/suissa/HiveMind/agents/overrides"
+	"github.com/suissa/HiveMind/agents/policy"
+	"github.com/suissa/HiveMind/agents/simulation"
+	"github.com/suissa/HiveMind/agents/tenant"
+	"github.com/suissa/HiveMind/agents/timing"
+)
+
+// ErrToolDenied indica que o agente não tem permissão para usar a ferramenta
+var ErrToolDenied = errors.New("ferramenta não permitida para o agente")
+
+// Tool define a interface comum das ferramentas executadas pelos agentes
+type Tool interface {
+	Name() string
+	Description() string
+	Execute(ctx context.Context, params map[string]interface{}) (interface{}, error)
+}
+
+// SchemaTool é implementada pelas ferramentas que descrevem os parâmetros em JSON Schema
+// (ferramentas MCP e skills)
+type SchemaTool interface {
+	Tool
+	InputSchema() json.RawMessage
+}
+
+// ToolCaller identifica quem está chamando a ferramenta (implementado por Agent e AgentStruct)
+type ToolCaller interface {
+	GetID() string
+	GetRole() string
+}
+
+// funcTool adapta uma função para a interface Tool
+type funcTool struct {
+	name        string
+	description string
+	fn          func(ctx context.Context, params map[string]interface{}) (interface{}, error)
+}
+
+func (t *funcTool) Name() string        { return t.name }
+func (t *funcTool) Description() string { return t.description }
+func (t *funcTool) Execute(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+	return t.fn(ctx, params)
+}
+
+// NewFuncTool cria uma Tool a partir de uma função
+func NewFuncTool(name, description string, fn func(ctx context.Context, params map[string]interface{}) (interface{}, error)) Tool {
+	return &funcTool{name: name, description: description, fn: fn}
+}
+
+// ToolPermissions é a matriz de permissões de ferramentas definida no agents.yaml.
+// Ferramentas são liberadas por papel (role) ou por ID de agente; "*" libera todas.
+type ToolPermissions struct {
+	Roles  map[string][]string `yaml:"roles"`
+	Agents map[string][]string `yaml:"agents"`
+}
+
+// Empty indica se nenhuma permissão foi configurada
+func (p *ToolPermissions) Empty() bool {
+	return p == nil || (len(p.Roles) == 0 && len(p.Agents) == 0)
+}
+
+// Allows verifica se o agente (por ID ou papel) pode usar a ferramenta.
+// Sem matriz configurada todas as ferramentas são permitidas.
+func (p *ToolPermissions) Allows(agentID, role, tool string) bool {
+	if p.Empty() {
+		return true
+	}
+	return containsTool(p.Agents[agentID], tool) || containsTool(p.Roles[role], tool)
+}
+
+// containsTool verifica se a ferramenta está na lista (ou se a lista contém "*")
+func containsTool(allowed []string, tool string) bool {
+	for _, t := range allowed {
+		if t == tool || t == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+// ToolRegistry registra as ferramentas disponíveis e aplica a matriz de permissões
+type ToolRegistry struct {
+	tools       map[string]Tool
+	permissions *ToolPermissions
+	events      *EventEmitter
+	policy      policy.Engine
+	blobs       *blob.Manager
+	mu          sync.RWMutex
+}
+
+// NewToolRegistry cria um registro de ferramentas. As chamadas negadas são
+// emitidas como EventToolDenied no events (opcional) para auditoria.
+func NewToolRegistry(permissions *ToolPermissions, events *EventEmitter) *ToolRegistry {
+	return &ToolRegistry{
+		tools:       make(map[string]Tool),
+		permissions: permissions,
+		events:      events,
+	}
+}
+
+// Register adiciona uma ferramenta ao registro
+func (r *ToolRegistry) Register(tool Tool) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.tools[tool.Name()]; exists {
+		return fmt.Errorf("ferramenta já registrada: %s", tool.Name())
+	}
+	r.tools[tool.Name()] = tool
+	return nil
+}
+
+// SetPermissions substitui a matriz de permissões (ex.: após recarregar o agents.yaml)
+func (r *ToolRegistry) SetPermissions(permissions *ToolPermissions) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.permissions = permissions
+}
+
+// hasPermissions indica se há uma matriz de permissões carregada
+func (r *ToolRegistry) hasPermissions() bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return !r.permissions.Empty()
+}
+
+// GrantAgent define as ferramentas liberadas para um agente pelo seu ID (ex.: ao restaurar
+// um snapshot). Sem matriz configurada todas as ferramentas já são permitidas e nada muda.
+func (r *ToolRegistry) GrantAgent(agentID string, tools []string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.permissions.Empty() {
+		return
+	}
+
+	// Copia a matriz para não alterar a configuração compartilhada
+	permissions := &ToolPermissions{Roles: r.permissions.Roles, Agents: make(map[string][]string)}
+	for id, allowed := range r.permissions.Agents {
+		permissions.Agents[id] = allowed
+	}
+	permissions.Agents[agentID] = append([]string(nil), tools...)
+	r.permissions = permissions
+}
+
+// GrantTool libera a ferramenta para os papéis e agentes informados (ex.: pelas permissões do
+// manifesto de uma skill). Sem matriz configurada todas as ferramentas já são permitidas e
+// nada muda.
+func (r *ToolRegistry) GrantTool(tool string, roles, agentIDs []string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.permissions.Empty() {
+		return
+	}
+
+	// Copia a matriz para não alterar a configuração compartilhada
+	permissions := &ToolPermissions{Roles: make(map[string][]string), Agents: make(map[string][]string)}
+	for role, allowed := range r.permissions.Roles {
+		permissions.Roles[role] = allowed
+	}
+	for id, allowed := range r.permissions.Agents {
+		permissions.Agents[id] = allowed
+	}
+	for _, role := range roles {
+		permissions.Roles[role] = append(append([]string(nil), permissions.Roles[role]...), tool)
+	}
+	for _, id := range agentIDs {
+		permissions.Agents[id] = append(append([]string(nil), permissions.Agents[id]...), tool)
+	}
+	r.permissions = permissions
+}
+
+// SetPolicy define o motor de políticas (OPA) consultado antes de cada chamada
+func (r *ToolRegistry) SetPolicy(engine policy.Engine) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.policy = engine
+}
+
+// SetBlobs guarda no armazenamento de objetos as saídas grandes das ferramentas: textos viram
+// a prévia com a referência [blob:...] e binários ([]byte) viram uma blob.Ref
+func (r *ToolRegistry) SetBlobs(blobs *blob.Manager) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.blobs = blobs
+}
+
+// Get retorna uma ferramenta pelo nome
+func (r *ToolRegistry) Get(name string) (Tool, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	tool, ok := r.tools[name]
+	return tool, ok
+}
+
+// Schema retorna o JSON Schema dos parâmetros da ferramenta, se ela o declarar
+func (r *ToolRegistry) Schema(name string) (json.RawMessage, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	tool, ok := r.tools[name].(SchemaTool)
+	if !ok {
+		return nil, false
+	}
+	return tool.InputSchema(), true
+}
+
+// List retorna os nomes das ferramentas registradas
+func (r *ToolRegistry) List() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	names := make([]string, 0, len(r.tools))
+	for name := range r.tools {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Allowed retorna as ferramentas que o agente pode usar
+func (r *ToolRegistry) Allowed(caller ToolCaller) []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	names := make([]string, 0)
+	for name := range r.tools {
+		if r.permissions.Allows(caller.GetID(), caller.GetRole(), name) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Execute executa a ferramenta em nome do agente, se ele tiver permissão
+func (r *ToolRegistry) Execute(ctx context.Context, caller ToolCaller, name string, params map[string]interface{}) (interface{}, error) {
+	r.mu.RLock()
+	tool, ok := r.tools[name]
+	// Os overrides da tarefa podem apenas restringir as ferramentas do agente
+	allowed := r.permissions.Allows(caller.GetID(), caller.GetRole(), name) && overrides.FromContext(ctx).AllowsTool(name)
+	engine := r.policy
+	blobs := r.blobs
+	r.mu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("ferramenta não registrada: %s", name)
+	}
+
+	if !allowed {
+		r.emit(EventToolDenied, caller, name, nil)
+		return nil, fmt.Errorf("%w: %s (agente %s, papel %s)", ErrToolDenied, name, caller.GetID(), caller.GetRole())
+	}
+
+	input := policy.ToolInput{
+		AgentID:    caller.GetID(),
+		Role:       caller.GetRole(),
+		Tenant:     tenant.FromContext(ctx),
+		Tool:       name,
+		Parameters: params,
+	}
+	if err := policy.Check(ctx, engine, policy.QueryTool, input); err != nil {
+		r.emit(EventToolDenied, caller, name, err)
+		return nil, err
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	// No modo dry-run a ferramenta não é executada: registra a chamada e devolve o resultado simulado
+	if session, ok := simulation.FromContext(ctx); ok {
+		session.Record(simulation.EffectToolCall, name, params)
+		memory.RecordToolCall(ctx, name)
+		r.emit(EventToolCall, caller, name, nil)
+		return session.ToolResult(name), nil
+	}
+
+	start := time.Now()
+	result, err := tool.Execute(timing.WithAgent(ctx, caller.GetID()), params)
+	timing.Record(ctx, caller.GetID(), timing.Tool, name, start)
+	if err == nil && blobs != nil {
+		result, err = blobs.OffloadValue(ctx, result)
+	}
+	if err == nil {
+		// Resultados de ferramentas entram na proveniência das memórias gravadas na tarefa
+		memory.RecordToolCall(ctx, name)
+	}
+	r.emit(EventToolCall, caller, name, err)
+	return result, err
+}
+
+// emit registra a chamada de ferramenta no emissor de eventos
+func (r *ToolRegistry) emit(eventType EventType, caller ToolCaller, tool string, err error) {
+	if r.events == nil {
+		return
+	}
+
+	data := map[string]interface{}{
+		"agent_id": caller.GetID(),
+		"role":     caller.GetRole(),
+		"tool":     tool,
+	}
+	if err != nil {
+		data["error"] = err.Error()
+	}
+
+	r.events.Emit(Event{
+		Type:      eventType,
+		Timestamp: time.Now(),
+		Source:    "tool_registry",
+		Data:      data,
+	})
+}
@@ -0,0 +1,215 @@
+package agents
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/suissa/HiveMind/agents/overrides"
+	"github.com/suissa/HiveMind/agents/policy"
+)
+
+// fakeCaller identifica o agente que chama a ferramenta nos testes
+type fakeCaller struct{ id, role string }
+
+func (c fakeCaller) GetID() string   { return c.id }
+func (c fakeCaller) GetRole() string { return c.role }
+
+// decisionEngine nega as ferramentas listadas em deny
+type decisionEngine struct{ deny map[string]bool }
+
+func (e decisionEngine) Evaluate(ctx context.Context, query string, input interface{}) (*policy.Decision, error) {
+	if e.deny[input.(policy.ToolInput).Tool] {
+		return &policy.Decision{Reasons: []string{"ferramenta bloqueada"}}, nil
+	}
+	return &policy.Decision{Allow: true}, nil
+}
+
+func TestToolPermissionsAllows(t *testing.T) {
+	matrix := &ToolPermissions{
+		Roles:  map[string][]string{"researcher": {"search", "scrape"}, "admin": {"*"}},
+		Agents: map[string][]string{"writer-1": {"publish"}},
+	}
+
+	tests := []struct {
+		name        string
+		permissions *ToolPermissions
+		agentID     string
+		role        string
+		tool        string
+		want        bool
+	}{
+		{name: "Matriz nula libera tudo", permissions: nil, agentID: "a", role: "r", tool: "search", want: true},
+		{name: "Matriz vazia libera tudo", permissions: &ToolPermissions{}, agentID: "a", role: "r", tool: "search", want: true},
+		{name: "Liberada pelo papel", permissions: matrix, agentID: "r-1", role: "researcher", tool: "scrape", want: true},
+		{name: "Fora do papel", permissions: matrix, agentID: "r-1", role: "researcher", tool: "publish", want: false},
+		{name: "Liberada pelo ID", permissions: matrix, agentID: "writer-1", role: "writer", tool: "publish", want: true},
+		{name: "ID soma ao papel", permissions: matrix, agentID: "writer-1", role: "researcher", tool: "search", want: true},
+		{name: "Curinga do papel", permissions: matrix, agentID: "x", role: "admin", tool: "qualquer", want: true},
+		{name: "Papel desconhecido", permissions: matrix, agentID: "x", role: "guest", tool: "search", want: false},
+		{name: "Papel vazio", permissions: matrix, agentID: "x", role: "", tool: "search", want: false},
+		{name: "Só agentes configurados", permissions: &ToolPermissions{Agents: map[string][]string{"a": {"search"}}}, agentID: "b", role: "researcher", tool: "search", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.permissions.Allows(tt.agentID, tt.role, tt.tool); got != tt.want {
+				t.Errorf("Allows(%q, %q, %q) = %v, esperado %v", tt.agentID, tt.role, tt.tool, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestToolRegistryExecuteDenials(t *testing.T) {
+	permissions := &ToolPermissions{Roles: map[string][]string{"researcher": {"search", "scrape"}}}
+
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		caller  fakeCaller
+		tool    string
+		engine  policy.Engine
+		wantErr error
+		denied  bool // Espera o evento EventToolDenied
+		called  bool // Espera a execução da ferramenta
+	}{
+		{name: "Permitida", caller: fakeCaller{"r-1", "researcher"}, tool: "search", called: true},
+		{name: "Fora da matriz", caller: fakeCaller{"w-1", "writer"}, tool: "search", wantErr: ErrToolDenied, denied: true},
+		{
+			name:    "Restringida pelos overrides da tarefa",
+			ctx:     overrides.WithContext(context.Background(), overrides.Overrides{Tools: []string{"scrape"}}),
+			caller:  fakeCaller{"r-1", "researcher"},
+			tool:    "search",
+			wantErr: ErrToolDenied,
+			denied:  true,
+		},
+		{
+			name:   "Overrides com a ferramenta",
+			ctx:    overrides.WithContext(context.Background(), overrides.Overrides{Tools: []string{"search"}}),
+			caller: fakeCaller{"r-1", "researcher"},
+			tool:   "search",
+			called: true,
+		},
+		{
+			name:    "Negada pela política",
+			caller:  fakeCaller{"r-1", "researcher"},
+			tool:    "search",
+			engine:  decisionEngine{deny: map[string]bool{"search": true}},
+			wantErr: policy.ErrDenied,
+			denied:  true,
+		},
+		{
+			name:   "Permitida pela política",
+			caller: fakeCaller{"r-1", "researcher"},
+			tool:   "scrape",
+			engine: decisionEngine{deny: map[string]bool{"search": true}},
+			called: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events := NewEventEmitter()
+			var mu sync.Mutex
+			var denials []Event
+			events.On(EventToolDenied, func(e Event) {
+				mu.Lock()
+				defer mu.Unlock()
+				denials = append(denials, e)
+			})
+
+			registry := NewToolRegistry(permissions, events)
+			registry.SetPolicy(tt.engine)
+			calls := 0
+			for _, name := range []string{"search", "scrape"} {
+				registry.Register(NewFuncTool(name, name, func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+					calls++
+					return "ok", nil
+				}))
+			}
+
+			ctx := tt.ctx
+			if ctx == nil {
+				ctx = context.Background()
+			}
+			_, err := registry.Execute(ctx, tt.caller, tt.tool, nil)
+			if tt.wantErr == nil && err != nil {
+				t.Fatal(err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Fatalf("esperava %v, obtido %v", tt.wantErr, err)
+			}
+			if (calls == 1) != tt.called {
+				t.Fatalf("execuções da ferramenta: %d", calls)
+			}
+
+			if err := events.Flush(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			if (len(denials) == 1) != tt.denied {
+				t.Fatalf("eventos de negação inesperados: %+v", denials)
+			}
+			if tt.denied {
+				data := denials[0].Data
+				if data["agent_id"] != tt.caller.id || data["role"] != tt.caller.role || data["tool"] != tt.tool {
+					t.Errorf("dados do evento inesperados: %+v", data)
+				}
+				if _, hasReason := data["error"]; hasReason != (tt.engine != nil) {
+					t.Errorf("só a negação da política leva o motivo: %+v", data)
+				}
+			}
+		})
+	}
+}
+
+func TestToolRegistryUnknownTool(t *testing.T) {
+	registry := NewToolRegistry(nil, nil)
+	if _, err := registry.Execute(context.Background(), fakeCaller{"a", "r"}, "ausente", nil); err == nil || errors.Is(err, ErrToolDenied) {
+		t.Fatalf("esperava o erro da ferramenta não registrada: %v", err)
+	}
+}
+
+func TestToolRegistryGrants(t *testing.T) {
+	// Sem matriz, as concessões não criam restrições
+	open := NewToolRegistry(nil, nil)
+	open.GrantTool("search", []string{"researcher"}, nil)
+	open.GrantAgent("w-1", []string{"publish"})
+	if open.hasPermissions() {
+		t.Fatal("as concessões não deveriam criar uma matriz")
+	}
+
+	shared := &ToolPermissions{Roles: map[string][]string{"researcher": {"search"}}}
+	registry := NewToolRegistry(shared, nil)
+	for _, name := range []string{"search", "scrape", "publish"} {
+		registry.Register(NewFuncTool(name, name, nil))
+	}
+	registry.GrantTool("scrape", []string{"researcher"}, nil)
+	registry.GrantAgent("w-1", []string{"publish"})
+
+	tests := []struct {
+		caller fakeCaller
+		want   []string
+	}{
+		{fakeCaller{"r-1", "researcher"}, []string{"scrape", "search"}},
+		{fakeCaller{"w-1", "writer"}, []string{"publish"}},
+		{fakeCaller{"x", "guest"}, []string{}},
+	}
+	for _, tt := range tests {
+		got := registry.Allowed(tt.caller)
+		if len(got) != len(tt.want) {
+			t.Fatalf("%+v: ferramentas %v, esperado %v", tt.caller, got, tt.want)
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Fatalf("%+v: ferramentas %v, esperado %v", tt.caller, got, tt.want)
+			}
+		}
+	}
+	// A matriz compartilhada da configuração não é alterada
+	if len(shared.Roles["researcher"]) != 1 || len(shared.Agents) != 0 {
+		t.Fatalf("a matriz original foi alterada: %+v", shared)
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"sync"
 	"time"
+
+	"github.com/suissa/HiveMind/agents/dataset"
 )
 
 // TrainingConfig contém as configurações para treinamento dos agentes
@@ -17,6 +19,12 @@ type TrainingConfig struct {
 	LearningRate    float64       // Taxa de aprendizado
 	UseHistorical   bool          // Usar dados históricos para treinamento
 	SaveCheckpoints bool          // Salvar checkpoints durante treinamento
+
+	// Dataset são os exemplos compartilhados por todos os agentes treinados (opcional;
+	// cada agente soma os seus próprios exemplos e o feedback armazenado)
+	Dataset *dataset.Dataset
+	// Seed é a semente da divisão entre treino e validação
+	Seed int64
 }
 
 // TrainingMetrics armazena métricas do treinamento
@@ -27,6 +35,7 @@ type TrainingMetrics struct {
 	Loss           float64
 	RoundsExecuted int
 	Errors         []error
+	DatasetVersion string // Versão do dataset usado no treinamento
 }
 
 // TrainableAgent define a interface para agentes que podem ser treinados
@@ -71,6 +80,13 @@ func (t *AgentTrainer) AddAgent(agent TrainableAgent) {
 	t.agents = append(t.agents, agent)
 }
 
+// SetDataset define o dataset usado no treinamento de todos os agentes
+func (t *AgentTrainer) SetDataset(d *dataset.Dataset) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.config.Dataset = d
+}
+
 // Train executa o treinamento de todos os agentes
 func (t *AgentTrainer) Train(ctx context.Context) error {
 	t.mu.Lock()
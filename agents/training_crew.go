@@ -1,11 +1,13 @@
 package agents
 
 import (
+	"context"
 	"fmt"
 	"sync"
 	"time"
 
-	"HiveMind/agents/memory"
+	"github.com/suissa/HiveMind/agents/i18n"
+	"github.com/suissa/HiveMind/agents/memory"
 )
 
 // TrainingProject contém os detalhes do projeto de treinamento
@@ -34,6 +36,7 @@ type TrainingCrew struct {
 	chapterAgent  *CognitiveAgent
 	feedbackAgent *CognitiveAgent
 	accountAgent  *CognitiveAgent
+	middleware    []TaskMiddleware
 	mu            sync.RWMutex
 }
 
@@ -64,14 +67,30 @@ func (c *TrainingCrew) AddAgent(agent *CognitiveAgent) {
 	}
 }
 
+// Use adiciona middlewares à execução das etapas do workflow (treinamento, capítulos e
+// feedback); o primeiro registrado é o mais externo
+func (c *TrainingCrew) Use(middleware ...TaskMiddleware) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.middleware = append(c.middleware, middleware...)
+}
+
 // ExecuteWorkflow executa o workflow de treinamento
 func (c *TrainingCrew) ExecuteWorkflow(project *TrainingProject) (*TrainingResults, error) {
+	return c.ExecuteWorkflowContext(context.Background(), project)
+}
+
+// ExecuteWorkflowContext executa o workflow de treinamento, abortando se o contexto for cancelado
+func (c *TrainingCrew) ExecuteWorkflowContext(ctx context.Context, project *TrainingProject) (*TrainingResults, error) {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 
 	if c.trainingAgent == nil || c.chapterAgent == nil || c.feedbackAgent == nil || c.accountAgent == nil {
 		return nil, fmt.Errorf("equipe incompleta: todos os agentes são necessários")
 	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
 
 	// Emite evento de início do workflow
 	c.EmitEvent(Event{
@@ -87,10 +106,24 @@ func (c *TrainingCrew) ExecuteWorkflow(project *TrainingProject) (*TrainingResul
 
 	startTime := time.Now()
 
-	// Simula a execução do workflow
-	training := "Estratégia de treinamento focada em aprendizado prático"
-	chapters := "Capítulos estruturados com exercícios progressivos"
-	feedback := "Sistema de feedback personalizado baseado em desempenho"
+	// Cada etapa passa pelos middlewares da equipe como uma tarefa do agente responsável
+	run := ChainTaskMiddleware(c.runStage, c.middleware...)
+	stages := []TaskConfig{
+		{ID: "training", AssignedTo: c.trainingAgent.GetID()},
+		{ID: "chapters", AssignedTo: c.chapterAgent.GetID()},
+		{ID: "feedback", AssignedTo: c.feedbackAgent.GetID()},
+	}
+	outputs := make([]string, len(stages))
+	for i, stage := range stages {
+		stage.Name = project.Name + "/" + stage.ID
+		stage.Description = project.Description
+		output, err := run(ctx, stage)
+		if err != nil {
+			return nil, fmt.Errorf("erro na etapa %s do treinamento: %w", stage.ID, err)
+		}
+		outputs[i] = output
+	}
+	training, chapters, feedback := outputs[0], outputs[1], outputs[2]
 
 	// Emite evento de conclusão do workflow
 	c.EmitEvent(Event{
@@ -116,6 +149,14 @@ func (c *TrainingCrew) ExecuteWorkflow(project *TrainingProject) (*TrainingResul
 	}, nil
 }
 
+// runStage simula a execução de uma etapa; o relatório segue o idioma do contexto
+func (c *TrainingCrew) runStage(ctx context.Context, stage TaskConfig) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	return i18n.T(ctx, "report.training."+stage.ID), nil
+}
+
 // GetProjectStatus retorna o status atual do projeto
 func (c *TrainingCrew) GetProjectStatus() *ProjectStatus {
 	c.mu.RLock()
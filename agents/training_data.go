@@ -0,0 +1,164 @@
+package agents
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/suissa/HiveMind/agents/dataset"
+	"github.com/suissa/HiveMind/agents/eval"
+	"github.com/suissa/HiveMind/agents/memory"
+)
+
+// feedbackTag identifica as memórias de feedback usadas como exemplos de treinamento
+const feedbackTag = "feedback"
+
+// fewShotTemplate é o template com os exemplos aprendidos no treinamento,
+// incluído no prompt das tarefas do agente
+const fewShotTemplate = "few_shot"
+
+// maxFewShot limita a quantidade de exemplos incluídos no template few-shot
+const maxFewShot = 3
+
+// TrainingExample é uma entrada rotulada com a resposta esperada
+type TrainingExample = dataset.Example
+
+// Grader avalia a resposta do agente a um exemplo, retornando uma nota entre 0 e 1
+type Grader func(ctx context.Context, example TrainingExample, output string) (float64, error)
+
+// DefaultGrader dá nota 1 quando a resposta contém a esperada (sem diferenciar maiúsculas)
+// e, caso contrário, a fração das palavras esperadas presentes na resposta
+func DefaultGrader(ctx context.Context, example TrainingExample, output string) (float64, error) {
+	expected := strings.ToLower(strings.TrimSpace(example.Expected))
+	got := strings.ToLower(output)
+	if expected == "" {
+		return 0, fmt.Errorf("exemplo %s sem resposta esperada", example.ID)
+	}
+	if strings.Contains(got, expected) {
+		return 1, nil
+	}
+
+	words := strings.Fields(expected)
+	found := 0
+	for _, word := range words {
+		if strings.Contains(got, word) {
+			found++
+		}
+	}
+	return float64(found) / float64(len(words)), nil
+}
+
+// JudgeGrader avalia as respostas de treinamento com um modelo juiz (nota geral da rubrica)
+func JudgeGrader(judge *eval.Judge) Grader {
+	return func(ctx context.Context, example TrainingExample, output string) (float64, error) {
+		score, err := judge.Score(ctx, eval.CaseFromExample(example), output)
+		if err != nil {
+			return 0, err
+		}
+		return score.Overall, nil
+	}
+}
+
+// SetTrainingData define os exemplos rotulados usados em Train, além do feedback armazenado
+func (a *CognitiveAgent) SetTrainingData(examples []TrainingExample) {
+	a.trainingData = examples
+}
+
+// SetTrainingDataset define os exemplos de treinamento a partir de um dataset carregado
+// com dataset.Load; para treinar vários agentes com o mesmo dataset use TrainingConfig.Dataset
+func (a *CognitiveAgent) SetTrainingDataset(d *dataset.Dataset) {
+	a.trainingData = d.Examples
+}
+
+// SetGrader define como as respostas de treinamento são avaliadas (padrão DefaultGrader)
+func (a *CognitiveAgent) SetGrader(grader Grader) {
+	a.grader = grader
+}
+
+// RecordFeedback armazena na memória de longo prazo a resposta esperada para uma entrada;
+// o feedback armazenado é usado como exemplo nos próximos treinamentos
+func (a *CognitiveAgent) RecordFeedback(ctx context.Context, input, expected string) error {
+	if a.memoryManager == nil {
+		return fmt.Errorf("agente %s sem gerenciador de memória", a.GetID())
+	}
+
+	example := TrainingExample{
+		ID:       fmt.Sprintf("feedback_%s_%d", a.GetID(), time.Now().UnixNano()),
+		Input:    input,
+		Expected: expected,
+	}
+	content, err := json.Marshal(example)
+	if err != nil {
+		return fmt.Errorf("erro ao converter feedback para JSON: %v", err)
+	}
+
+	return a.memoryManager.StoreMemory(a.scope(ctx), &memory.Memory{
+		ID:         example.ID,
+		AgentID:    a.GetID(),
+		Type:       memory.LongTerm,
+		Content:    string(content),
+		Importance: 1,
+		Timestamp:  time.Now(),
+		Tags:       []string{feedbackTag},
+	})
+}
+
+// trainingDataset reúne em um dataset os exemplos de config.Dataset, os configurados no
+// agente e o feedback armazenado na memória
+func (a *CognitiveAgent) trainingDataset(ctx context.Context, config TrainingConfig) (*dataset.Dataset, error) {
+	var examples []TrainingExample
+	if config.Dataset != nil {
+		examples = append(examples, config.Dataset.Examples...)
+	}
+	examples = append(examples, a.trainingData...)
+
+	if a.memoryManager != nil {
+		memories, err := a.memoryManager.SearchMemories(a.scope(ctx), a.GetID(), []string{feedbackTag})
+		if err != nil {
+			return nil, fmt.Errorf("erro ao carregar feedback armazenado: %w", err)
+		}
+		for _, m := range memories {
+			var example TrainingExample
+			if err := json.Unmarshal([]byte(m.Content), &example); err != nil || example.Expected == "" {
+				continue
+			}
+			if example.ID == "" {
+				example.ID = m.ID
+			}
+			examples = append(examples, example)
+		}
+	}
+
+	name := a.GetID()
+	if config.Dataset != nil {
+		name = config.Dataset.Name
+	}
+	return dataset.New(name, examples), nil
+}
+
+// updateFewShot monta o template few-shot com os exemplos em que o agente errou
+func (a *CognitiveAgent) updateFewShot(failed []TrainingExample) {
+	if len(failed) == 0 {
+		return
+	}
+	if len(failed) > maxFewShot {
+		failed = failed[:maxFewShot]
+	}
+
+	var b strings.Builder
+	b.WriteString("Exemplos:\n")
+	for _, example := range failed {
+		fmt.Fprintf(&b, "Entrada: %s\nResposta: %s\n\n", example.Input, example.Expected)
+	}
+	a.PromptTemplates[fewShotTemplate] = b.String()
+}
+
+// withFewShot inclui os exemplos aprendidos no treinamento antes da entrada
+func (a *CognitiveAgent) withFewShot(input string) string {
+	if examples, ok := a.PromptTemplates[fewShotTemplate]; ok && examples != "" {
+		return examples + input
+	}
+	return input
+}
@@ -1,6 +1,10 @@
 package agents
 
-import "time"
+import (
+	"time"
+
+	"github.com/suissa/HiveMind/agents/progress"
+)
 
 // ProjectStatus representa o status atual de um projeto
 type ProjectStatus struct {
@@ -9,4 +13,7 @@ type ProjectStatus struct {
 	TotalTasks     int
 	ElapsedTime    time.Duration
 	RemainingTime  time.Duration
+
+	// TaskProgress é o último andamento informado pelas tarefas em execução
+	TaskProgress map[string]progress.Update
 }
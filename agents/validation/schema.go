@@ -0,0 +1,168 @@
+package validation
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// check verifica o valor contra o subconjunto do JSON Schema usado nas saídas das tarefas
+// (type, enum, required, properties, additionalProperties, items, limites de tamanho e de
+// valor) e retorna o primeiro problema encontrado, ou "" se o valor for válido
+func check(schema map[string]interface{}, value interface{}, path string) string {
+	if types := schemaTypes(schema["type"]); len(types) > 0 {
+		matched := false
+		for _, t := range types {
+			if hasType(value, t) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return fmt.Sprintf("%s deveria ser do tipo %s, não %s", path, strings.Join(types, " ou "), typeOf(value))
+		}
+	}
+
+	if enum, ok := schema["enum"].([]interface{}); ok {
+		found := false
+		for _, option := range enum {
+			if equal(option, value) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Sprintf("%s deveria ser um dos valores %s", path, compact(enum))
+		}
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return checkObject(schema, v, path)
+	case []interface{}:
+		if min, ok := number(schema["minItems"]); ok && float64(len(v)) < min {
+			return fmt.Sprintf("%s deveria ter pelo menos %v itens, tem %d", path, min, len(v))
+		}
+		if max, ok := number(schema["maxItems"]); ok && float64(len(v)) > max {
+			return fmt.Sprintf("%s deveria ter no máximo %v itens, tem %d", path, max, len(v))
+		}
+		if items, ok := schema["items"].(map[string]interface{}); ok {
+			for i, item := range v {
+				if problem := check(items, item, fmt.Sprintf("%s[%d]", path, i)); problem != "" {
+					return problem
+				}
+			}
+		}
+	case string:
+		length := float64(len([]rune(v)))
+		if min, ok := number(schema["minLength"]); ok && length < min {
+			return fmt.Sprintf("%s deveria ter pelo menos %v caracteres", path, min)
+		}
+		if max, ok := number(schema["maxLength"]); ok && length > max {
+			return fmt.Sprintf("%s deveria ter no máximo %v caracteres", path, max)
+		}
+	case float64:
+		if min, ok := number(schema["minimum"]); ok && v < min {
+			return fmt.Sprintf("%s deveria ser no mínimo %v", path, min)
+		}
+		if max, ok := number(schema["maximum"]); ok && v > max {
+			return fmt.Sprintf("%s deveria ser no máximo %v", path, max)
+		}
+	}
+	return ""
+}
+
+func checkObject(schema map[string]interface{}, object map[string]interface{}, path string) string {
+	if required, ok := schema["required"].([]interface{}); ok {
+		for _, name := range required {
+			key, _ := name.(string)
+			if _, present := object[key]; !present {
+				return fmt.Sprintf("%s deveria ter o campo %q", path, key)
+			}
+		}
+	}
+
+	properties, _ := schema["properties"].(map[string]interface{})
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		property, declared := properties[key].(map[string]interface{})
+		if !declared {
+			if additional, ok := schema["additionalProperties"].(bool); ok && !additional {
+				return fmt.Sprintf("%s não deveria ter o campo %q", path, key)
+			}
+			continue
+		}
+		if problem := check(property, object[key], path+"."+key); problem != "" {
+			return problem
+		}
+	}
+	return ""
+}
+
+// schemaTypes normaliza "type", que pode ser um nome ou uma lista de nomes
+func schemaTypes(value interface{}) []string {
+	switch v := value.(type) {
+	case string:
+		return []string{v}
+	case []interface{}:
+		types := make([]string, 0, len(v))
+		for _, t := range v {
+			if name, ok := t.(string); ok {
+				types = append(types, name)
+			}
+		}
+		return types
+	}
+	return nil
+}
+
+func hasType(value interface{}, t string) bool {
+	switch t {
+	case "integer":
+		n, ok := value.(float64)
+		return ok && n == math.Trunc(n)
+	case "number":
+		_, ok := value.(float64)
+		return ok
+	}
+	return typeOf(value) == t
+}
+
+func typeOf(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+func number(value interface{}) (float64, bool) {
+	n, ok := value.(float64)
+	return n, ok
+}
+
+// equal compara valores JSON decodificados
+func equal(a, b interface{}) bool {
+	return compact(a) == compact(b)
+}
+
+func compact(value interface{}) string {
+	data, _ := json.Marshal(value)
+	return string(data)
+}
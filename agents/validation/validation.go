@@ -0,0 +1,152 @@
+// Package validation verifica se a saída de uma tarefa atende ao resultado esperado antes de
+// a tarefa ser concluída: por um JSON Schema declarado na tarefa ou por um modelo juiz que
+// compara a saída com a descrição do ExpectedOutput. Saídas rejeitadas voltam ao agente com
+// o motivo, até o limite de tentativas.
+package validation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/suissa/HiveMind/agents/llm"
+)
+
+// DefaultMaxRetries é o número padrão de novas tentativas após uma saída rejeitada
+const DefaultMaxRetries = 2
+
+// Expectation é o resultado esperado de uma tarefa
+type Expectation struct {
+	Description string          // ExpectedOutput da tarefa, em texto livre
+	Schema      json.RawMessage // JSON Schema da saída (opcional)
+}
+
+// Empty indica que a tarefa não declara expectativa
+func (e Expectation) Empty() bool {
+	return strings.TrimSpace(e.Description) == "" && len(e.Schema) == 0
+}
+
+// Result é o veredito da validação
+type Result struct {
+	Valid  bool   `json:"valid"`
+	Reason string `json:"reason,omitempty"` // Motivo da rejeição, devolvido ao agente na nova tentativa
+}
+
+// Validator verifica uma saída contra a expectativa
+type Validator interface {
+	Validate(ctx context.Context, expectation Expectation, output string) (Result, error)
+}
+
+// ValidatorFunc adapta uma função a Validator
+type ValidatorFunc func(ctx context.Context, expectation Expectation, output string) (Result, error)
+
+// Validate implementa Validator
+func (f ValidatorFunc) Validate(ctx context.Context, expectation Expectation, output string) (Result, error) {
+	return f(ctx, expectation, output)
+}
+
+// Config configura a validação das saídas de um agente
+type Config struct {
+	Validator  Validator // Schema() se nil
+	MaxRetries int       // Novas tentativas após uma rejeição (DefaultMaxRetries se zero; negativo desativa)
+}
+
+// Retries retorna o número efetivo de novas tentativas
+func (c Config) Retries() int {
+	switch {
+	case c.MaxRetries < 0:
+		return 0
+	case c.MaxRetries == 0:
+		return DefaultMaxRetries
+	}
+	return c.MaxRetries
+}
+
+// All aplica os validadores em ordem; a primeira rejeição decide
+func All(validators ...Validator) Validator {
+	return ValidatorFunc(func(ctx context.Context, expectation Expectation, output string) (Result, error) {
+		for _, validator := range validators {
+			result, err := validator.Validate(ctx, expectation, output)
+			if err != nil || !result.Valid {
+				return result, err
+			}
+		}
+		return Result{Valid: true}, nil
+	})
+}
+
+// Schema valida a saída contra o JSON Schema da expectativa. O JSON é extraído da saída,
+// tolerando texto ou blocos de código ao redor. Tarefas sem schema são aceitas.
+func Schema() Validator {
+	return ValidatorFunc(func(_ context.Context, expectation Expectation, output string) (Result, error) {
+		if len(expectation.Schema) == 0 {
+			return Result{Valid: true}, nil
+		}
+		var schema map[string]interface{}
+		if err := json.Unmarshal(expectation.Schema, &schema); err != nil {
+			return Result{}, fmt.Errorf("JSON Schema da saída inválido: %v", err)
+		}
+		value, ok := extractJSON(output)
+		if !ok {
+			return Result{Reason: "a saída deveria ser um JSON válido"}, nil
+		}
+		if problem := check(schema, value, "$"); problem != "" {
+			return Result{Reason: problem}, nil
+		}
+		return Result{Valid: true}, nil
+	})
+}
+
+// extractJSON decodifica o objeto ou a lista JSON contida na saída
+func extractJSON(output string) (interface{}, bool) {
+	var value interface{}
+	if json.Unmarshal([]byte(strings.TrimSpace(output)), &value) == nil {
+		return value, true
+	}
+	for _, delimiters := range [][2]string{{"{", "}"}, {"[", "]"}} {
+		start, end := strings.Index(output, delimiters[0]), strings.LastIndex(output, delimiters[1])
+		if start >= 0 && end > start && json.Unmarshal([]byte(output[start:end+1]), &value) == nil {
+			return value, true
+		}
+	}
+	return nil, false
+}
+
+// judgeSystem instrui o juiz a responder apenas com JSON
+const judgeSystem = `Você verifica se a saída de uma tarefa atende ao resultado esperado.
+Não avalie o estilo: rejeite apenas saídas incompletas, fora do formato pedido ou que não
+entregam o que foi descrito. Responda apenas com um objeto JSON no formato
+{"valid": true, "reason": "<o que falta, se inválida>"}.`
+
+// Judge pede a um modelo juiz que compare a saída com a descrição do resultado esperado.
+// Tarefas sem descrição são aceitas.
+func Judge(provider llm.Provider, model string) Validator {
+	return ValidatorFunc(func(ctx context.Context, expectation Expectation, output string) (Result, error) {
+		if strings.TrimSpace(expectation.Description) == "" {
+			return Result{Valid: true}, nil
+		}
+		resp, err := provider.Complete(ctx, llm.Request{
+			Model:       model,
+			System:      judgeSystem,
+			Prompt:      fmt.Sprintf("Resultado esperado:\n%s\n\nSaída da tarefa:\n%s\n", expectation.Description, output),
+			Temperature: 0,
+		})
+		if err != nil {
+			return Result{}, fmt.Errorf("erro na chamada ao juiz da saída: %w", err)
+		}
+
+		var verdict Result
+		start, end := strings.Index(resp.Text, "{"), strings.LastIndex(resp.Text, "}")
+		if start < 0 || end < start {
+			return Result{}, fmt.Errorf("resposta do juiz da saída sem JSON: %q", resp.Text)
+		}
+		if err := json.Unmarshal([]byte(resp.Text[start:end+1]), &verdict); err != nil {
+			return Result{}, fmt.Errorf("resposta do juiz da saída inválida: %v", err)
+		}
+		if !verdict.Valid && verdict.Reason == "" {
+			verdict.Reason = "a saída não atende ao resultado esperado"
+		}
+		return verdict, nil
+	})
+}
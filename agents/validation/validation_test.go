@@ -0,0 +1,83 @@
+package validation
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/suissa/HiveMind/agents/llm"
+)
+
+var reportSchema = json.RawMessage(`{
+	"type": "object",
+	"required": ["title", "items"],
+	"additionalProperties": false,
+	"properties": {
+		"title": {"type": "string", "minLength": 3},
+		"status": {"enum": ["draft", "final"]},
+		"items": {"type": "array", "minItems": 1, "items": {"type": "object", "required": ["score"], "properties": {"score": {"type": "integer", "minimum": 0, "maximum": 10}}}}
+	}
+}`)
+
+func TestSchema(t *testing.T) {
+	expectation := Expectation{Schema: reportSchema}
+	cases := []struct {
+		output string
+		reason string // Trecho do motivo esperado; vazio se a saída é válida
+	}{
+		{"Segue:\n```json\n{\"title\": \"Relatório\", \"items\": [{\"score\": 7}]}\n```", ""},
+		{"sem json", "JSON válido"},
+		{`{"title": "Relatório"}`, `campo "items"`},
+		{`{"title": "Re", "items": [{"score": 1}]}`, "$.title deveria ter pelo menos 3 caracteres"},
+		{`{"title": "Relatório", "items": [{"score": 7.5}]}`, "$.items[0].score deveria ser do tipo integer"},
+		{`{"title": "Relatório", "items": [{"score": 11}]}`, "no máximo 10"},
+		{`{"title": "Relatório", "items": [], "status": "done"}`, "$.items deveria ter pelo menos 1 itens"},
+		{`{"title": "Relatório", "items": [{"score": 1}], "status": "done"}`, `["draft","final"]`},
+		{`{"title": "Relatório", "items": [{"score": 1}], "extra": true}`, `não deveria ter o campo "extra"`},
+	}
+	for _, c := range cases {
+		result, err := Schema().Validate(context.Background(), expectation, c.output)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result.Valid != (c.reason == "") || !strings.Contains(result.Reason, c.reason) {
+			t.Errorf("Validate(%q) = %+v; esperava o motivo %q", c.output, result, c.reason)
+		}
+	}
+
+	// Sem schema, qualquer saída é aceita
+	if result, _ := Schema().Validate(context.Background(), Expectation{Description: "um texto"}, "x"); !result.Valid {
+		t.Fatal("saída sem schema deveria ser aceita")
+	}
+}
+
+func TestJudge(t *testing.T) {
+	provider := llm.NewFakeLLMProvider()
+	if err := provider.SetDefault(`Veredito: {"valid": false, "reason": "faltam itens"}`); err != nil {
+		t.Fatal(err)
+	}
+	judge := Judge(provider, "juiz")
+
+	result, err := judge.Validate(context.Background(), Expectation{Description: "Uma lista com três itens"}, "um item")
+	if err != nil || result.Valid || result.Reason != "faltam itens" {
+		t.Fatalf("veredito inesperado: %+v, %v", result, err)
+	}
+	if result, _ := judge.Validate(context.Background(), Expectation{}, "x"); !result.Valid {
+		t.Fatal("saída sem descrição deveria ser aceita sem chamar o juiz")
+	}
+
+	all := All(Schema(), judge)
+	result, err = all.Validate(context.Background(), Expectation{Description: "lista", Schema: reportSchema}, "{}")
+	if err != nil || result.Valid || !strings.Contains(result.Reason, "title") {
+		t.Fatalf("o schema deveria rejeitar antes do juiz: %+v, %v", result, err)
+	}
+}
+
+func TestConfigRetries(t *testing.T) {
+	for _, c := range []struct{ max, want int }{{0, DefaultMaxRetries}, {-1, 0}, {5, 5}} {
+		if got := (Config{MaxRetries: c.max}).Retries(); got != c.want {
+			t.Errorf("Retries(%d) = %d; esperava %d", c.max, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package agents
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/suissa/HiveMind/agents/consensus"
+)
+
+// Voter faz do agente um votante: o agente responde à pergunta com o seu LLM, sem ver os
+// votos dos demais, e ordena as opções da preferida para a menos preferida quando houver
+func Voter(agent *CognitiveAgent) consensus.Voter {
+	return consensus.Voter{
+		ID: agent.GetID(),
+		Answer: func(ctx context.Context, question consensus.Question) (consensus.Ballot, error) {
+			output, err := agent.Complete(ctx, votePrompt(question))
+			if err != nil {
+				return consensus.Ballot{}, err
+			}
+			ballot, err := parseBallot(question, output)
+			if err != nil {
+				return consensus.Ballot{}, fmt.Errorf("voto do agente %s: %w", agent.GetID(), err)
+			}
+			ballot.Confidence = agent.confidence()
+			return ballot, nil
+		},
+	}
+}
+
+// votePrompt pede a resposta (ou o ranking das opções) em JSON
+func votePrompt(question consensus.Question) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Pergunta: %s\n", question.Text)
+	if len(question.Options) == 0 {
+		b.WriteString(`Responda apenas com JSON no formato {"answer": "...", "rationale": "..."}, com uma resposta curta.`)
+		return b.String()
+	}
+	b.WriteString("Opções:\n")
+	for _, option := range question.Options {
+		fmt.Fprintf(&b, "- %s\n", option)
+	}
+	b.WriteString(`Ordene as opções da preferida para a menos preferida e responda apenas com JSON no formato {"ranking": ["..."], "rationale": "..."}.`)
+	return b.String()
+}
+
+// parseBallot lê o voto do JSON devolvido; sem JSON, o texto inteiro é a resposta. Com opções,
+// o ranking mantém apenas as opções válidas e a resposta é a preferida.
+func parseBallot(question consensus.Question, text string) (consensus.Ballot, error) {
+	var vote struct {
+		Answer    string   `json:"answer"`
+		Ranking   []string `json:"ranking"`
+		Rationale string   `json:"rationale"`
+	}
+	start, end := strings.Index(text, "{"), strings.LastIndex(text, "}")
+	if start < 0 || end < start || json.Unmarshal([]byte(text[start:end+1]), &vote) != nil {
+		vote.Answer = strings.TrimSpace(text)
+	}
+
+	ballot := consensus.Ballot{Answer: vote.Answer, Rationale: vote.Rationale}
+	if len(question.Options) == 0 {
+		if ballot.Answer == "" {
+			return ballot, fmt.Errorf("resposta vazia")
+		}
+		return ballot, nil
+	}
+
+	options := make(map[string]string, len(question.Options))
+	for _, option := range question.Options {
+		options[consensus.Normalize(option)] = option
+	}
+	if len(vote.Ranking) == 0 && vote.Answer != "" {
+		vote.Ranking = []string{vote.Answer}
+	}
+	for _, choice := range vote.Ranking {
+		if option, ok := options[consensus.Normalize(choice)]; ok {
+			ballot.Ranking = append(ballot.Ranking, option)
+		}
+	}
+	if len(ballot.Ranking) == 0 {
+		return ballot, fmt.Errorf("nenhuma opção válida em %q", text)
+	}
+	ballot.Answer = ballot.Ranking[0]
+	return ballot, nil
+}
@@ -0,0 +1,39 @@
+package agents
+
+import (
+	"context"
+	"log"
+
+	"github.com/suissa/HiveMind/agents/webhook"
+)
+
+// ActionApprovalRequested é a ação do evento emitido por ApprovalHooks quando uma tarefa
+// passa a aguardar aprovação
+const ActionApprovalRequested = "approval_requested"
+
+// WebhookListener entrega aos webhooks os eventos do ciclo de vida: início, conclusão e falha
+// dos workflows, violações de SLA e aprovações pendentes. O evento inteiro (tipo, origem e
+// dados) vai no campo data da entrega.
+func WebhookListener(dispatcher *webhook.Dispatcher) EventListener {
+	return func(event Event) {
+		action, _ := event.Data["action"].(string)
+		var name string
+		switch action {
+		case "workflow_start":
+			name = webhook.EventWorkflowStarted
+		case "workflow_complete":
+			name = webhook.EventWorkflowCompleted
+		case "workflow_failed":
+			name = webhook.EventWorkflowFailed
+		case "sla_breach":
+			name = webhook.EventSLABreached
+		case ActionApprovalRequested:
+			name = webhook.EventApprovalRequested
+		default:
+			return
+		}
+		if _, err := dispatcher.Publish(context.Background(), name, event); err != nil {
+			log.Printf("⚠️ Erro ao enfileirar webhook %s: %v", name, err)
+		}
+	}
+}
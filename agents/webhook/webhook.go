@@ -0,0 +1,434 @@
+// Package webhook notifica sistemas externos, por HTTP, sobre os eventos do ciclo de vida dos
+// workflows: início, conclusão e falha, aprovação pendente, orçamento excedido e violação de
+// SLA. O corpo de cada entrega é assinado com HMAC-SHA256 do segredo do endpoint, as entregas
+// que falham são repetidas com espera exponencial até MaxAttempts, e a situação de cada
+// entrega (pendente, entregue ou falha) fica disponível em Deliveries.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/suissa/HiveMind/agents/metrics"
+)
+
+// Eventos do ciclo de vida entregues aos endpoints
+const (
+	EventWorkflowStarted   = "workflow.started"
+	EventWorkflowCompleted = "workflow.completed"
+	EventWorkflowFailed    = "workflow.failed"
+	EventApprovalRequested = "approval.requested"
+	EventSLABreached       = "sla.breached"
+)
+
+// Headers das entregas
+const (
+	HeaderEvent     = "X-HiveMind-Event"
+	HeaderDelivery  = "X-HiveMind-Delivery"
+	HeaderTimestamp = "X-HiveMind-Timestamp" // Segundos Unix, incluídos na assinatura
+	HeaderSignature = "X-HiveMind-Signature" // "sha256=<hex>"
+)
+
+// Valores padrão da configuração
+const (
+	DefaultMaxAttempts = 5
+	DefaultInterval    = time.Second
+	DefaultMaxBackoff  = 5 * time.Minute
+	DefaultTimeout     = 10 * time.Second
+	DefaultHistory     = 1000
+)
+
+var deliveriesTotal = metrics.Default.Counter("hivemind_webhook_deliveries_total",
+	"Tentativas de entrega de webhooks por evento e resultado (delivered, retry ou failed)", "event", "result")
+
+// Status é a situação de uma entrega
+type Status string
+
+const (
+	StatusPending   Status = "pending"   // Aguardando a primeira tentativa ou uma nova tentativa
+	StatusDelivered Status = "delivered" // O endpoint respondeu 2xx
+	StatusFailed    Status = "failed"    // As tentativas se esgotaram
+)
+
+// Endpoint é um destino dos webhooks
+type Endpoint struct {
+	ID      string            `json:"id" yaml:"id"` // Padrão: a URL
+	URL     string            `json:"url" yaml:"url"`
+	Secret  string            `json:"-" yaml:"secret"`                          // Chave da assinatura HMAC; vazio envia sem assinatura
+	Events  []string          `json:"events,omitempty" yaml:"events,omitempty"` // Eventos assinados ("workflow.*" aceita o prefixo); vazio assina todos
+	Headers map[string]string `json:"-" yaml:"headers,omitempty"`               // Cabeçalhos extras, ex.: Authorization
+}
+
+// Subscribed informa se o endpoint recebe o evento
+func (e Endpoint) Subscribed(event string) bool {
+	if len(e.Events) == 0 {
+		return true
+	}
+	for _, pattern := range e.Events {
+		if pattern == "*" || pattern == event {
+			return true
+		}
+		if prefix, ok := strings.CutSuffix(pattern, "*"); ok && strings.HasPrefix(event, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// Config define as tentativas de entrega
+type Config struct {
+	MaxAttempts int           `json:"max_attempts,omitempty" yaml:"max_attempts,omitempty"` // Tentativas por entrega (padrão DefaultMaxAttempts)
+	Interval    time.Duration `json:"interval,omitempty" yaml:"interval,omitempty"`         // Varredura das entregas e primeira espera entre tentativas
+	MaxBackoff  time.Duration `json:"max_backoff,omitempty" yaml:"max_backoff,omitempty"`   // Espera máxima entre tentativas
+	Timeout     time.Duration `json:"timeout,omitempty" yaml:"timeout,omitempty"`           // Prazo de cada requisição
+	History     int           `json:"history,omitempty" yaml:"history,omitempty"`           // Entregas concluídas mantidas para consulta
+}
+
+// Envelope é o corpo JSON enviado aos endpoints
+type Envelope struct {
+	ID        string          `json:"id"` // ID da entrega, repetido nas novas tentativas
+	Event     string          `json:"event"`
+	Timestamp time.Time       `json:"timestamp"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// Delivery é uma entrega de um evento a um endpoint
+type Delivery struct {
+	ID             string          `json:"id"`
+	Endpoint       string          `json:"endpoint"`
+	Event          string          `json:"event"`
+	Data           json.RawMessage `json:"data"`
+	Status         Status          `json:"status"`
+	Attempts       int             `json:"attempts"`
+	ResponseStatus int             `json:"response_status,omitempty"` // Status HTTP da última tentativa
+	LastError      string          `json:"last_error,omitempty"`
+	CreatedAt      time.Time       `json:"created_at"`
+	NextAttempt    time.Time       `json:"next_attempt,omitempty"`
+	DeliveredAt    time.Time       `json:"delivered_at,omitempty"`
+}
+
+// Sign retorna a assinatura do corpo enviado no instante timestamp (segundos Unix):
+// "sha256=" seguido do HMAC-SHA256 hexadecimal de "<timestamp>.<corpo>"
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify confere a assinatura recebida por um endpoint, em tempo constante. Os receptores
+// também devem rejeitar timestamps antigos para evitar a repetição de entregas capturadas.
+func Verify(secret, signature string, timestamp int64, body []byte) bool {
+	return hmac.Equal([]byte(signature), []byte(Sign(secret, timestamp, body)))
+}
+
+// Dispatcher entrega os eventos aos endpoints assinantes
+type Dispatcher struct {
+	config     Config
+	client     *http.Client
+	endpoints  map[string]Endpoint
+	deliveries map[string]*Delivery
+	wake       chan struct{}
+	mu         sync.Mutex
+	flushMu    sync.Mutex // Serializa as varreduras
+}
+
+// New cria o despachante com a configuração informada, completando os padrões
+func New(config Config) *Dispatcher {
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = DefaultMaxAttempts
+	}
+	if config.Interval <= 0 {
+		config.Interval = DefaultInterval
+	}
+	if config.MaxBackoff <= 0 {
+		config.MaxBackoff = DefaultMaxBackoff
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = DefaultTimeout
+	}
+	if config.History <= 0 {
+		config.History = DefaultHistory
+	}
+	return &Dispatcher{
+		config:     config,
+		client:     &http.Client{Timeout: config.Timeout},
+		endpoints:  make(map[string]Endpoint),
+		deliveries: make(map[string]*Delivery),
+		wake:       make(chan struct{}, 1),
+	}
+}
+
+// Add registra (ou substitui) um endpoint
+func (d *Dispatcher) Add(endpoint Endpoint) error {
+	u, err := url.Parse(endpoint.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("URL de webhook inválida: %q", endpoint.URL)
+	}
+	if endpoint.ID == "" {
+		endpoint.ID = endpoint.URL
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.endpoints[endpoint.ID] = endpoint
+	return nil
+}
+
+// Remove retira o endpoint; as entregas pendentes para ele falham na próxima tentativa
+func (d *Dispatcher) Remove(id string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	delete(d.endpoints, id)
+}
+
+// Publish cria uma entrega do evento para cada endpoint assinante, com data serializado em
+// JSON, e retorna os IDs das entregas
+func (d *Dispatcher) Publish(ctx context.Context, event string, data interface{}) ([]string, error) {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao serializar o evento %s: %v", event, err)
+	}
+	now := time.Now()
+	var ids []string
+	d.mu.Lock()
+	for _, endpoint := range d.endpoints {
+		if !endpoint.Subscribed(event) {
+			continue
+		}
+		delivery := &Delivery{
+			ID:          uuid.NewString(),
+			Endpoint:    endpoint.ID,
+			Event:       event,
+			Data:        payload,
+			Status:      StatusPending,
+			CreatedAt:   now,
+			NextAttempt: now,
+		}
+		d.deliveries[delivery.ID] = delivery
+		ids = append(ids, delivery.ID)
+	}
+	d.mu.Unlock()
+
+	if len(ids) > 0 {
+		d.notify()
+	}
+	return ids, nil
+}
+
+// Retry agenda uma nova série de tentativas para uma entrega que falhou
+func (d *Dispatcher) Retry(id string) error {
+	d.mu.Lock()
+	delivery, ok := d.deliveries[id]
+	if !ok {
+		d.mu.Unlock()
+		return fmt.Errorf("entrega de webhook não encontrada: %s", id)
+	}
+	if delivery.Status != StatusFailed {
+		d.mu.Unlock()
+		return fmt.Errorf("a entrega %s não falhou (situação %s)", id, delivery.Status)
+	}
+	delivery.Status = StatusPending
+	delivery.Attempts = 0
+	delivery.NextAttempt = time.Now()
+	d.mu.Unlock()
+	d.notify()
+	return nil
+}
+
+// Delivery retorna a situação de uma entrega
+func (d *Dispatcher) Delivery(id string) (Delivery, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	delivery, ok := d.deliveries[id]
+	if !ok {
+		return Delivery{}, false
+	}
+	return *delivery, true
+}
+
+// Deliveries retorna as entregas pendentes e as concluídas mais recentes, da mais antiga para
+// a mais nova; status vazio retorna todas
+func (d *Dispatcher) Deliveries(status Status) []Delivery {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	deliveries := make([]Delivery, 0, len(d.deliveries))
+	for _, delivery := range d.deliveries {
+		if status == "" || delivery.Status == status {
+			deliveries = append(deliveries, *delivery)
+		}
+	}
+	sort.Slice(deliveries, func(i, j int) bool { return deliveries[i].CreatedAt.Before(deliveries[j].CreatedAt) })
+	return deliveries
+}
+
+// notify antecipa a próxima varredura
+func (d *Dispatcher) notify() {
+	select {
+	case d.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Run tenta as entregas a cada intervalo (ou a cada Publish) até o contexto ser cancelado
+func (d *Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.config.Interval)
+	defer ticker.Stop()
+	for {
+		d.Flush(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-d.wake:
+		}
+	}
+}
+
+// Flush tenta as entregas pendentes cuja próxima tentativa já chegou e retorna quantas foram
+// entregues; as que falham são reagendadas com espera exponencial
+func (d *Dispatcher) Flush(ctx context.Context) (int, error) {
+	d.flushMu.Lock()
+	defer d.flushMu.Unlock()
+
+	now := time.Now()
+	type attempt struct {
+		delivery Delivery
+		endpoint Endpoint
+		known    bool
+	}
+	var due []attempt
+	d.mu.Lock()
+	for _, delivery := range d.deliveries {
+		if delivery.Status == StatusPending && !delivery.NextAttempt.After(now) {
+			endpoint, known := d.endpoints[delivery.Endpoint]
+			due = append(due, attempt{*delivery, endpoint, known})
+		}
+	}
+	d.mu.Unlock()
+	sort.Slice(due, func(i, j int) bool { return due[i].delivery.CreatedAt.Before(due[j].delivery.CreatedAt) })
+
+	delivered := 0
+	for _, a := range due {
+		if ctx.Err() != nil {
+			return delivered, ctx.Err()
+		}
+		status, err := 0, fmt.Errorf("endpoint removido: %s", a.delivery.Endpoint)
+		if a.known {
+			status, err = d.send(ctx, a.endpoint, a.delivery)
+		}
+		if d.record(a.delivery.ID, status, err, a.known) {
+			delivered++
+		}
+	}
+	d.trim()
+	return delivered, nil
+}
+
+// send faz uma tentativa de entrega e retorna o status HTTP da resposta
+func (d *Dispatcher) send(ctx context.Context, endpoint Endpoint, delivery Delivery) (int, error) {
+	now := time.Now()
+	body, err := json.Marshal(Envelope{ID: delivery.ID, Event: delivery.Event, Timestamp: now, Data: delivery.Data})
+	if err != nil {
+		return 0, fmt.Errorf("erro ao serializar a entrega: %v", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("erro ao criar a requisição do webhook: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for name, value := range endpoint.Headers {
+		req.Header.Set(name, value)
+	}
+	req.Header.Set(HeaderEvent, delivery.Event)
+	req.Header.Set(HeaderDelivery, delivery.ID)
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(now.Unix(), 10))
+	if endpoint.Secret != "" {
+		req.Header.Set(HeaderSignature, Sign(endpoint.Secret, now.Unix(), body))
+	}
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("erro ao chamar o webhook: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("webhook retornou status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// record registra o resultado da tentativa e retorna se a entrega foi concluída com sucesso.
+// Sem endpoint não há nova tentativa.
+func (d *Dispatcher) record(id string, status int, err error, retry bool) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	delivery, ok := d.deliveries[id]
+	if !ok {
+		return false
+	}
+	now := time.Now()
+	delivery.Attempts++
+	delivery.ResponseStatus = status
+	if err == nil {
+		delivery.Status = StatusDelivered
+		delivery.LastError = ""
+		delivery.DeliveredAt = now
+		delivery.NextAttempt = time.Time{}
+		deliveriesTotal.Inc(delivery.Event, "delivered")
+		return true
+	}
+	delivery.LastError = err.Error()
+	if !retry || delivery.Attempts >= d.config.MaxAttempts {
+		delivery.Status = StatusFailed
+		delivery.NextAttempt = time.Time{}
+		deliveriesTotal.Inc(delivery.Event, "failed")
+		return false
+	}
+	delivery.NextAttempt = now.Add(d.backoff(delivery.Attempts))
+	deliveriesTotal.Inc(delivery.Event, "retry")
+	return false
+}
+
+// backoff retorna a espera antes da próxima tentativa: o intervalo dobrado a cada tentativa
+// já feita, até MaxBackoff
+func (d *Dispatcher) backoff(attempts int) time.Duration {
+	wait := d.config.Interval
+	for i := 1; i < attempts && wait < d.config.MaxBackoff; i++ {
+		wait *= 2
+	}
+	return min(wait, d.config.MaxBackoff)
+}
+
+// trim descarta as entregas concluídas mais antigas além de History
+func (d *Dispatcher) trim() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	var done []*Delivery
+	for _, delivery := range d.deliveries {
+		if delivery.Status != StatusPending {
+			done = append(done, delivery)
+		}
+	}
+	if len(done) <= d.config.History {
+		return
+	}
+	sort.Slice(done, func(i, j int) bool { return done[i].CreatedAt.Before(done[j].CreatedAt) })
+	for _, delivery := range done[:len(done)-d.config.History] {
+		delete(d.deliveries, delivery.ID)
+	}
+}
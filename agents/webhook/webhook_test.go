@@ -0,0 +1,126 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSubscribed(t *testing.T) {
+	endpoint := Endpoint{Events: []string{"workflow.*", EventSLABreached}}
+	for event, want := range map[string]bool{
+		EventWorkflowStarted:   true,
+		EventWorkflowFailed:    true,
+		EventSLABreached:       true,
+		EventApprovalRequested: false,
+	} {
+		if got := endpoint.Subscribed(event); got != want {
+			t.Errorf("Subscribed(%s) = %v, esperava %v", event, got, want)
+		}
+	}
+	if !(Endpoint{}).Subscribed(EventSLABreached) {
+		t.Error("endpoint sem eventos deveria assinar todos")
+	}
+}
+
+func TestDeliverySigned(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		envelope Envelope
+		header   http.Header
+		valid    bool
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		timestamp, _ := strconv.ParseInt(r.Header.Get(HeaderTimestamp), 10, 64)
+		mu.Lock()
+		defer mu.Unlock()
+		header = r.Header
+		valid = Verify("segredo", r.Header.Get(HeaderSignature), timestamp, body)
+		json.Unmarshal(body, &envelope)
+	}))
+	defer server.Close()
+
+	dispatcher := New(Config{})
+	if err := dispatcher.Add(Endpoint{ID: "crm", URL: server.URL, Secret: "segredo", Headers: map[string]string{"Authorization": "Bearer x"}}); err != nil {
+		t.Fatal(err)
+	}
+	ids, err := dispatcher.Publish(context.Background(), EventWorkflowCompleted, map[string]string{"project": "campanha"})
+	if err != nil || len(ids) != 1 {
+		t.Fatalf("esperava uma entrega: %v %v", ids, err)
+	}
+	if n, err := dispatcher.Flush(context.Background()); err != nil || n != 1 {
+		t.Fatalf("Flush = %d, %v", n, err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if !valid {
+		t.Fatal("a assinatura HMAC deveria ser válida")
+	}
+	if header.Get(HeaderEvent) != EventWorkflowCompleted || header.Get(HeaderDelivery) != ids[0] || header.Get("Authorization") != "Bearer x" {
+		t.Fatalf("cabeçalhos inesperados: %v", header)
+	}
+	if envelope.ID != ids[0] || string(envelope.Data) != `{"project":"campanha"}` {
+		t.Fatalf("corpo inesperado: %+v", envelope)
+	}
+	delivery, ok := dispatcher.Delivery(ids[0])
+	if !ok || delivery.Status != StatusDelivered || delivery.Attempts != 1 || delivery.ResponseStatus != http.StatusOK {
+		t.Fatalf("situação inesperada: %+v", delivery)
+	}
+}
+
+func TestRetriesUntilFailed(t *testing.T) {
+	var mu sync.Mutex
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		calls++
+		mu.Unlock()
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	dispatcher := New(Config{MaxAttempts: 2, Interval: time.Millisecond, MaxBackoff: time.Millisecond})
+	dispatcher.Add(Endpoint{URL: server.URL, Events: []string{EventSLABreached}})
+	if ids, _ := dispatcher.Publish(context.Background(), EventApprovalRequested, nil); len(ids) != 0 {
+		t.Fatal("evento não assinado não deveria gerar entrega")
+	}
+	ids, _ := dispatcher.Publish(context.Background(), EventSLABreached, map[string]string{"project": "campanha"})
+
+	dispatcher.Flush(context.Background())
+	delivery, _ := dispatcher.Delivery(ids[0])
+	if delivery.Status != StatusPending || delivery.Attempts != 1 || delivery.ResponseStatus != http.StatusServiceUnavailable {
+		t.Fatalf("a falha deveria reagendar a entrega: %+v", delivery)
+	}
+	time.Sleep(5 * time.Millisecond)
+	dispatcher.Flush(context.Background())
+	if delivery, _ = dispatcher.Delivery(ids[0]); delivery.Status != StatusFailed || delivery.LastError == "" {
+		t.Fatalf("as tentativas deveriam ter se esgotado: %+v", delivery)
+	}
+	if failed := dispatcher.Deliveries(StatusFailed); len(failed) != 1 {
+		t.Fatalf("esperava uma entrega com falha: %+v", failed)
+	}
+
+	if err := dispatcher.Retry(ids[0]); err != nil {
+		t.Fatal(err)
+	}
+	dispatcher.Flush(context.Background())
+	mu.Lock()
+	defer mu.Unlock()
+	if calls != 3 {
+		t.Fatalf("esperava 3 chamadas, houve %d", calls)
+	}
+}
+
+func TestAddRejectsInvalidURL(t *testing.T) {
+	if err := New(Config{}).Add(Endpoint{URL: "ftp://exemplo"}); err == nil {
+		t.Fatal("URL sem http deveria ser rejeitada")
+	}
+}
@@ -0,0 +1,326 @@
+package api
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/suissa/HiveMind/agents/i18n"
+	"github.com/suissa/HiveMind/agents/tenant"
+)
+
+// Role representa o papel de um usuário da API de gerenciamento
+type Role string
+
+const (
+	RoleViewer   Role = "viewer"
+	RoleOperator Role = "operator"
+	RoleAdmin    Role = "admin"
+)
+
+// Permission representa uma ação protegida da API
+type Permission string
+
+const (
+	PermReadMetrics     Permission = "metrics:read"
+	PermReadMemories    Permission = "memories:read"
+	PermSubmitTasks     Permission = "tasks:submit"
+	PermDecideApprovals Permission = "approvals:decide"
+	PermWriteScaling    Permission = "scaling:write"
+	PermEraseSubjects   Permission = "subjects:erase"
+)
+
+// rolePermissions define as permissões concedidas a cada papel
+var rolePermissions = map[Role][]Permission{
+	RoleViewer:   {PermReadMetrics, PermReadMemories},
+	RoleOperator: {PermReadMetrics, PermReadMemories, PermSubmitTasks, PermDecideApprovals, PermWriteScaling},
+	RoleAdmin:    {PermReadMetrics, PermReadMemories, PermSubmitTasks, PermDecideApprovals, PermWriteScaling, PermEraseSubjects},
+}
+
+// Can verifica se o papel concede a permissão informada
+func (r Role) Can(perm Permission) bool {
+	for _, p := range rolePermissions[r] {
+		if p == perm {
+			return true
+		}
+	}
+	return false
+}
+
+// Principal representa a identidade autenticada de uma requisição
+type Principal struct {
+	Name    string   `yaml:"name" json:"name"`
+	Role    Role     `yaml:"role" json:"role"`
+	Tenants []string `yaml:"tenants" json:"tenants,omitempty"` // Vazio = todos os tenants
+}
+
+// AllowsTenant verifica se o principal pode operar no tenant informado
+func (p *Principal) AllowsTenant(tenantID string) bool {
+	if len(p.Tenants) == 0 {
+		return true
+	}
+	for _, t := range p.Tenants {
+		if t == tenantID {
+			return true
+		}
+	}
+	return false
+}
+
+// Authenticator identifica o principal de uma requisição HTTP
+type Authenticator interface {
+	Authenticate(r *http.Request) (*Principal, error)
+}
+
+type principalKey struct{}
+
+// PrincipalFromContext retorna o principal autenticado da requisição
+func PrincipalFromContext(ctx context.Context) (*Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(*Principal)
+	return p, ok
+}
+
+// APIKeyAuthenticator autentica requisições pelo header X-API-Key ou Bearer token
+type APIKeyAuthenticator struct {
+	keys map[string]*Principal // sha256(chave) -> principal
+}
+
+// APIKeyConfig representa uma chave de API no arquivo de configuração
+type APIKeyConfig struct {
+	Principal `yaml:",inline"`
+	Key       string `yaml:"key"`
+}
+
+// APIKeysConfig representa o arquivo de configuração das chaves de API
+type APIKeysConfig struct {
+	Keys []APIKeyConfig `yaml:"api_keys"`
+}
+
+// NewAPIKeyAuthenticator cria um autenticador sem chaves cadastradas
+func NewAPIKeyAuthenticator() *APIKeyAuthenticator {
+	return &APIKeyAuthenticator{
+		keys: make(map[string]*Principal),
+	}
+}
+
+// LoadAPIKeys carrega as chaves de API de um arquivo YAML, expandindo variáveis de ambiente.
+// Entradas cuja chave ficou vazia (variável não definida) são ignoradas com um aviso.
+func LoadAPIKeys(filename string) (*APIKeyAuthenticator, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler arquivo de chaves de API: %v", err)
+	}
+
+	var config APIKeysConfig
+	if err := yaml.Unmarshal([]byte(os.ExpandEnv(string(data))), &config); err != nil {
+		return nil, fmt.Errorf("erro ao decodificar chaves de API: %v", err)
+	}
+
+	auth := NewAPIKeyAuthenticator()
+	for _, k := range config.Keys {
+		if k.Key == "" {
+			log.Printf("⚠️ Chave de API de %s vazia em %s, entrada ignorada", k.Name, filename)
+			continue
+		}
+		principal := k.Principal
+		if err := auth.AddKey(k.Key, &principal); err != nil {
+			return nil, err
+		}
+	}
+
+	return auth, nil
+}
+
+// AddKey registra uma chave de API para um principal
+func (a *APIKeyAuthenticator) AddKey(key string, principal *Principal) error {
+	if key == "" {
+		return fmt.Errorf("chave de API vazia para %s", principal.Name)
+	}
+	if _, ok := rolePermissions[principal.Role]; !ok {
+		return fmt.Errorf("papel inválido para %s: %s", principal.Name, principal.Role)
+	}
+	for _, t := range principal.Tenants {
+		if err := tenant.Validate(t); err != nil {
+			return err
+		}
+	}
+
+	a.keys[hashKey(key)] = principal
+	return nil
+}
+
+// Authenticate implementa Authenticator
+func (a *APIKeyAuthenticator) Authenticate(r *http.Request) (*Principal, error) {
+	key := r.Header.Get("X-API-Key")
+	if key == "" {
+		key = bearerToken(r)
+	}
+	if key == "" {
+		return nil, errors.New(i18n.T(r.Context(), "api.missing_credentials"))
+	}
+
+	hashed := hashKey(key)
+	for stored, principal := range a.keys {
+		if subtle.ConstantTimeCompare([]byte(stored), []byte(hashed)) == 1 {
+			return principal, nil
+		}
+	}
+
+	return nil, errors.New(i18n.T(r.Context(), "api.invalid_api_key"))
+}
+
+// Authenticators tenta os autenticadores em ordem; vale o primeiro que identificar o
+// principal, e sem nenhum retorna o erro do último
+func Authenticators(auths ...Authenticator) Authenticator {
+	return chain(auths)
+}
+
+type chain []Authenticator
+
+// Authenticate implementa Authenticator
+func (c chain) Authenticate(r *http.Request) (*Principal, error) {
+	err := errors.New(i18n.T(r.Context(), "api.missing_credentials"))
+	for _, auth := range c {
+		var principal *Principal
+		if principal, err = auth.Authenticate(r); err == nil {
+			return principal, nil
+		}
+	}
+	return nil, err
+}
+
+// OIDCAuthenticator valida tokens Bearer via introspecção (RFC 7662) no provedor OIDC
+type OIDCAuthenticator struct {
+	IntrospectionURL string
+	ClientID         string
+	ClientSecret     string
+	RoleClaim        string // Claim que contém o papel (padrão: "role")
+	TenantsClaim     string // Claim com a lista de tenants (padrão: "tenants")
+	client           *http.Client
+}
+
+// NewOIDCAuthenticator cria um autenticador baseado em introspecção de tokens
+func NewOIDCAuthenticator(introspectionURL, clientID, clientSecret string) *OIDCAuthenticator {
+	return &OIDCAuthenticator{
+		IntrospectionURL: introspectionURL,
+		ClientID:         clientID,
+		ClientSecret:     clientSecret,
+		RoleClaim:        "role",
+		TenantsClaim:     "tenants",
+		client:           &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Authenticate implementa Authenticator
+func (a *OIDCAuthenticator) Authenticate(r *http.Request) (*Principal, error) {
+	token := bearerToken(r)
+	if token == "" {
+		return nil, errors.New(i18n.T(r.Context(), "api.missing_bearer"))
+	}
+
+	form := url.Values{"token": {token}}
+	req, err := http.NewRequestWithContext(r.Context(), http.MethodPost, a.IntrospectionURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("erro ao criar requisição de introspecção: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth(a.ClientID, a.ClientSecret)
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("erro na introspecção do token: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("introspecção retornou status %d", resp.StatusCode)
+	}
+
+	var claims map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&claims); err != nil {
+		return nil, fmt.Errorf("erro ao decodificar introspecção: %v", err)
+	}
+
+	if active, _ := claims["active"].(bool); !active {
+		return nil, errors.New(i18n.T(r.Context(), "api.inactive_token"))
+	}
+
+	principal := &Principal{Role: RoleViewer}
+	if sub, ok := claims["sub"].(string); ok {
+		principal.Name = sub
+	}
+	if role, ok := claims[a.RoleClaim].(string); ok {
+		if _, known := rolePermissions[Role(role)]; known {
+			principal.Role = Role(role)
+		}
+	}
+	if tenants, ok := claims[a.TenantsClaim].([]interface{}); ok {
+		for _, t := range tenants {
+			if s, ok := t.(string); ok {
+				principal.Tenants = append(principal.Tenants, s)
+			}
+		}
+	}
+
+	return principal, nil
+}
+
+// authorize exige que o principal da requisição tenha a permissão informada
+func (s *Server) authorize(perm Permission, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Sem autenticador, a API só fica aberta no modo inseguro de desenvolvimento
+		if s.auth == nil {
+			if !s.insecure {
+				writeError(w, http.StatusUnauthorized, errors.New(i18n.T(r.Context(), "api.auth_not_configured")))
+				return
+			}
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		principal, err := s.auth.Authenticate(r)
+		if err != nil {
+			writeError(w, http.StatusUnauthorized, err)
+			return
+		}
+
+		if !principal.Role.Can(perm) {
+			writeError(w, http.StatusForbidden, errors.New(i18n.T(r.Context(), "api.permission_denied", principal.Role, perm)))
+			return
+		}
+
+		if !principal.AllowsTenant(tenant.FromContext(r.Context())) {
+			writeError(w, http.StatusForbidden, errors.New(i18n.T(r.Context(), "api.tenant_denied", tenant.FromContext(r.Context()))))
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), principalKey{}, principal)))
+	})
+}
+
+// bearerToken extrai o token do header Authorization
+func bearerToken(r *http.Request) string {
+	header := r.Header.Get("Authorization")
+	if strings.HasPrefix(header, "Bearer ") {
+		return strings.TrimSpace(strings.TrimPrefix(header, "Bearer "))
+	}
+	return ""
+}
+
+// hashKey calcula o hash de uma chave de API para armazenamento em memória
+func hashKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,278 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/suissa/HiveMind/agents/tenant"
+	"github.com/suissa/HiveMind/orchestrator"
+)
+
+// acceptingSubmitter aceita todas as tarefas
+type acceptingSubmitter struct{}
+
+func (acceptingSubmitter) SubmitTask(ctx context.Context, task orchestrator.TaskRequest) error {
+	return nil
+}
+
+// jsonBody serializa o valor como corpo JSON de uma requisição
+func jsonBody(t *testing.T, v interface{}) io.Reader {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return bytes.NewReader(data)
+}
+
+func TestRoleCan(t *testing.T) {
+	cases := []struct {
+		role  Role
+		perm  Permission
+		allow bool
+	}{
+		{RoleViewer, PermReadMetrics, true},
+		{RoleViewer, PermReadMemories, true},
+		{RoleViewer, PermSubmitTasks, false},
+		{RoleViewer, PermDecideApprovals, false},
+		{RoleViewer, PermWriteScaling, false},
+		{RoleViewer, PermEraseSubjects, false},
+		{RoleOperator, PermReadMetrics, true},
+		{RoleOperator, PermReadMemories, true},
+		{RoleOperator, PermSubmitTasks, true},
+		{RoleOperator, PermDecideApprovals, true},
+		{RoleOperator, PermWriteScaling, true},
+		{RoleOperator, PermEraseSubjects, false},
+		{RoleAdmin, PermReadMetrics, true},
+		{RoleAdmin, PermReadMemories, true},
+		{RoleAdmin, PermSubmitTasks, true},
+		{RoleAdmin, PermDecideApprovals, true},
+		{RoleAdmin, PermWriteScaling, true},
+		{RoleAdmin, PermEraseSubjects, true},
+		{Role("root"), PermReadMemories, false},
+		{Role(""), PermReadMemories, false},
+	}
+	for _, c := range cases {
+		if got := c.role.Can(c.perm); got != c.allow {
+			t.Errorf("%q.Can(%q) = %v, esperado %v", c.role, c.perm, got, c.allow)
+		}
+	}
+}
+
+func TestPrincipalAllowsTenant(t *testing.T) {
+	cases := []struct {
+		tenants  []string
+		tenantID string
+		allow    bool
+	}{
+		{nil, "acme", true},
+		{nil, tenant.DefaultTenant, true},
+		{[]string{"acme"}, "acme", true},
+		{[]string{"acme"}, "globex", false},
+		{[]string{"acme"}, tenant.DefaultTenant, false},
+		{[]string{"acme", "globex"}, "globex", true},
+	}
+	for _, c := range cases {
+		p := &Principal{Name: "ana", Role: RoleViewer, Tenants: c.tenants}
+		if got := p.AllowsTenant(c.tenantID); got != c.allow {
+			t.Errorf("tenants %v, AllowsTenant(%q) = %v, esperado %v", c.tenants, c.tenantID, got, c.allow)
+		}
+	}
+}
+
+func TestAddKeyValidation(t *testing.T) {
+	auth := NewAPIKeyAuthenticator()
+	if err := auth.AddKey("", &Principal{Name: "ana", Role: RoleViewer}); err == nil {
+		t.Error("uma chave vazia deveria ser recusada")
+	}
+	if err := auth.AddKey("k", &Principal{Name: "ana", Role: Role("root")}); err == nil {
+		t.Error("um papel desconhecido deveria ser recusado")
+	}
+	if err := auth.AddKey("k", &Principal{Name: "ana", Role: RoleViewer, Tenants: []string{"Acme"}}); err == nil {
+		t.Error("um tenant inválido deveria ser recusado")
+	}
+}
+
+func TestAuthorizeMatrix(t *testing.T) {
+	auth := NewAPIKeyAuthenticator()
+	keys := map[string]*Principal{
+		"viewer-key":   {Name: "viewer", Role: RoleViewer},
+		"operator-key": {Name: "operator", Role: RoleOperator},
+		"admin-key":    {Name: "admin", Role: RoleAdmin},
+		"acme-key":     {Name: "acme-admin", Role: RoleAdmin, Tenants: []string{"acme"}},
+	}
+	for key, principal := range keys {
+		if err := auth.AddKey(key, principal); err != nil {
+			t.Fatal(err)
+		}
+	}
+	server := NewServer(":0", nil, acceptingSubmitter{})
+	server.SetAuthenticator(auth)
+
+	type route struct{ method, path string }
+	memories := route{http.MethodGet, "/v1/memories?agent_id=a-1"}
+	tasks := route{http.MethodPost, "/v1/tasks"}
+	subjects := route{http.MethodDelete, "/v1/subjects?subject_id=s-1"}
+	metricsRoute := route{http.MethodGet, "/metrics"}
+	approvals := route{http.MethodGet, "/v1/approvals"}
+	decision := route{http.MethodPost, "/v1/approvals/a-1"}
+	policies := route{http.MethodPut, "/v1/scaling/policies"}
+
+	cases := []struct {
+		name   string
+		route  route
+		key    string
+		bearer bool
+		tenant string
+		status int
+	}{
+		{"sem credenciais", memories, "", false, "", http.StatusUnauthorized},
+		{"chave desconhecida", memories, "outra", false, "", http.StatusUnauthorized},
+		{"viewer lê memórias", memories, "viewer-key", false, "", http.StatusNotImplemented},
+		{"viewer via Bearer", memories, "viewer-key", true, "", http.StatusNotImplemented},
+		{"viewer não envia tarefas", tasks, "viewer-key", false, "", http.StatusForbidden},
+		{"viewer não apaga titulares", subjects, "viewer-key", false, "", http.StatusForbidden},
+		{"métricas sem credenciais", metricsRoute, "", false, "", http.StatusUnauthorized},
+		{"viewer lê métricas", metricsRoute, "viewer-key", false, "", http.StatusOK},
+		{"viewer não lista aprovações", approvals, "viewer-key", false, "", http.StatusForbidden},
+		{"viewer não decide aprovações", decision, "viewer-key", false, "", http.StatusForbidden},
+		{"viewer não altera escalonamento", policies, "viewer-key", false, "", http.StatusForbidden},
+		{"operator lista aprovações", approvals, "operator-key", false, "", http.StatusNotImplemented},
+		{"operator decide aprovações", decision, "operator-key", false, "", http.StatusNotImplemented},
+		{"operator altera escalonamento", policies, "operator-key", false, "", http.StatusNotImplemented},
+		{"admin altera escalonamento", policies, "admin-key", false, "", http.StatusNotImplemented},
+		{"operator envia tarefas", tasks, "operator-key", false, "", http.StatusAccepted},
+		{"operator não apaga titulares", subjects, "operator-key", false, "", http.StatusForbidden},
+		{"admin apaga titulares", subjects, "admin-key", false, "", http.StatusNotImplemented},
+		{"admin em outro tenant", tasks, "admin-key", false, "globex", http.StatusAccepted},
+		{"tenant permitido", tasks, "acme-key", false, "acme", http.StatusAccepted},
+		{"tenant negado", tasks, "acme-key", false, "globex", http.StatusForbidden},
+		{"tenant padrão negado", tasks, "acme-key", false, "", http.StatusForbidden},
+		{"tenant inválido", tasks, "admin-key", false, "Acme", http.StatusBadRequest},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			req := httptest.NewRequest(c.route.method, c.route.path, nil)
+			if c.route == tasks {
+				req = httptest.NewRequest(c.route.method, c.route.path, jsonBody(t, orchestrator.TaskRequest{Description: "pesquisa"}))
+			}
+			switch {
+			case c.key != "" && c.bearer:
+				req.Header.Set("Authorization", "Bearer "+c.key)
+			case c.key != "":
+				req.Header.Set("X-API-Key", c.key)
+			}
+			if c.tenant != "" {
+				req.Header.Set(tenant.HeaderName, c.tenant)
+			}
+
+			rec := httptest.NewRecorder()
+			server.Handler().ServeHTTP(rec, req)
+			if rec.Code != c.status {
+				t.Fatalf("status %d, esperado %d: %s", rec.Code, c.status, rec.Body)
+			}
+		})
+	}
+}
+
+func TestAuthorizeWithoutAuthenticator(t *testing.T) {
+	cases := []struct {
+		insecure bool
+		status   int
+	}{
+		{false, http.StatusUnauthorized},
+		{true, http.StatusNotImplemented},
+	}
+	for _, c := range cases {
+		server := NewServer(":0", nil, acceptingSubmitter{})
+		server.SetInsecure(c.insecure)
+
+		rec := httptest.NewRecorder()
+		server.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/memories?agent_id=a-1", nil))
+		if rec.Code != c.status {
+			t.Errorf("insecure=%v: status %d, esperado %d", c.insecure, rec.Code, c.status)
+		}
+	}
+}
+
+func TestAuthenticatorsChain(t *testing.T) {
+	first := NewAPIKeyAuthenticator()
+	first.AddKey("first-key", &Principal{Name: "first", Role: RoleViewer})
+	second := NewAPIKeyAuthenticator()
+	second.AddKey("second-key", &Principal{Name: "second", Role: RoleAdmin})
+	auth := Authenticators(first, second)
+
+	cases := []struct {
+		key, want string
+	}{
+		{"first-key", "first"},
+		{"second-key", "second"},
+		{"outra", ""},
+	}
+	for _, c := range cases {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("X-API-Key", c.key)
+		principal, err := auth.Authenticate(req)
+		if c.want == "" {
+			if err == nil {
+				t.Errorf("%q deveria ser recusada", c.key)
+			}
+			continue
+		}
+		if err != nil || principal.Name != c.want {
+			t.Errorf("%q: principal %+v %v, esperado %s", c.key, principal, err, c.want)
+		}
+	}
+}
+
+func TestOIDCAuthenticator(t *testing.T) {
+	introspection := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, pass, ok := r.BasicAuth(); !ok || user != "hivemind" || pass != "segredo" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		claims := map[string]interface{}{"active": false}
+		switch r.FormValue("token") {
+		case "admin-token":
+			claims = map[string]interface{}{"active": true, "sub": "ana", "role": "admin", "tenants": []string{"acme"}}
+		case "unknown-role":
+			claims = map[string]interface{}{"active": true, "sub": "bia", "role": "root"}
+		}
+		json.NewEncoder(w).Encode(claims)
+	}))
+	defer introspection.Close()
+	auth := NewOIDCAuthenticator(introspection.URL, "hivemind", "segredo")
+
+	cases := []struct {
+		token   string
+		role    Role
+		tenants int
+		fails   bool
+	}{
+		{"admin-token", RoleAdmin, 1, false},
+		{"unknown-role", RoleViewer, 0, false},
+		{"expired", "", 0, true},
+		{"", "", 0, true},
+	}
+	for _, c := range cases {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		if c.token != "" {
+			req.Header.Set("Authorization", "Bearer "+c.token)
+		}
+		principal, err := auth.Authenticate(req)
+		if c.fails {
+			if err == nil {
+				t.Errorf("%q deveria ser recusado", c.token)
+			}
+			continue
+		}
+		if err != nil || principal.Role != c.role || len(principal.Tenants) != c.tenants {
+			t.Errorf("%q: principal %+v %v", c.token, principal, err)
+		}
+	}
+}
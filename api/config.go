@@ -0,0 +1,81 @@
+package api
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/suissa/HiveMind/agents/memory"
+)
+
+// Config configura a API de gerenciamento iniciada pelo runtime (hivemind.WithManagementAPI)
+// ou pelos comandos. A API exige autenticação: sem chaves de API nem OIDC ela não é iniciada,
+// a menos que Insecure seja ativado explicitamente para desenvolvimento.
+type Config struct {
+	Addr                 string `yaml:"addr" json:"addr"`                                       // Endereço HTTP (ex.: ":8080")
+	APIKeysFile          string `yaml:"api_keys_file,omitempty" json:"api_keys_file,omitempty"` // Arquivo das chaves (LoadAPIKeys)
+	OIDCIntrospectionURL string `yaml:"oidc_introspection_url,omitempty" json:"oidc_introspection_url,omitempty"`
+	OIDCClientID         string `yaml:"oidc_client_id,omitempty" json:"oidc_client_id,omitempty"`
+	OIDCClientSecret     string `yaml:"oidc_client_secret,omitempty" json:"-"`
+	Insecure             bool   `yaml:"insecure,omitempty" json:"insecure,omitempty"` // Sem autenticação, só para desenvolvimento
+}
+
+// ConfigFromEnv lê a configuração das variáveis de ambiente HIVEMIND_API_ADDR,
+// HIVEMIND_API_KEYS_FILE, HIVEMIND_OIDC_INTROSPECTION_URL, HIVEMIND_OIDC_CLIENT_ID,
+// HIVEMIND_OIDC_CLIENT_SECRET e HIVEMIND_API_INSECURE; sem HIVEMIND_API_ADDR a API não é
+// iniciada
+func ConfigFromEnv() Config {
+	insecure, _ := strconv.ParseBool(os.Getenv("HIVEMIND_API_INSECURE"))
+	return Config{
+		Addr:                 os.Getenv("HIVEMIND_API_ADDR"),
+		APIKeysFile:          os.Getenv("HIVEMIND_API_KEYS_FILE"),
+		OIDCIntrospectionURL: os.Getenv("HIVEMIND_OIDC_INTROSPECTION_URL"),
+		OIDCClientID:         os.Getenv("HIVEMIND_OIDC_CLIENT_ID"),
+		OIDCClientSecret:     os.Getenv("HIVEMIND_OIDC_CLIENT_SECRET"),
+		Insecure:             insecure,
+	}
+}
+
+// Enabled indica que a API deve ser iniciada
+func (c Config) Enabled() bool {
+	return c.Addr != ""
+}
+
+// Authenticator monta o autenticador configurado: as chaves de API, o OIDC ou os dois, nessa
+// ordem. Sem nenhum dos dois retorna nil.
+func (c Config) Authenticator() (Authenticator, error) {
+	var auths []Authenticator
+	if c.APIKeysFile != "" {
+		keys, err := LoadAPIKeys(c.APIKeysFile)
+		if err != nil {
+			return nil, err
+		}
+		auths = append(auths, keys)
+	}
+	if c.OIDCIntrospectionURL != "" {
+		auths = append(auths, NewOIDCAuthenticator(c.OIDCIntrospectionURL, c.OIDCClientID, c.OIDCClientSecret))
+	}
+	switch len(auths) {
+	case 0:
+		return nil, nil
+	case 1:
+		return auths[0], nil
+	}
+	return Authenticators(auths...), nil
+}
+
+// NewServer cria a API com o autenticador configurado. Sem autenticador, a criação falha, a
+// menos que Insecure esteja ativado.
+func (c Config) NewServer(memManager memory.MemoryManager, submitter TaskSubmitter) (*Server, error) {
+	auth, err := c.Authenticator()
+	if err != nil {
+		return nil, err
+	}
+	if auth == nil && !c.Insecure {
+		return nil, fmt.Errorf("API de gerenciamento sem autenticação: configure HIVEMIND_API_KEYS_FILE ou HIVEMIND_OIDC_INTROSPECTION_URL (ou HIVEMIND_API_INSECURE=true para desenvolvimento)")
+	}
+	server := NewServer(c.Addr, memManager, submitter)
+	server.SetAuthenticator(auth)
+	server.SetInsecure(c.Insecure)
+	return server, nil
+}
@@ -0,0 +1,503 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/suissa/HiveMind/agents/approval"
+	"github.com/suissa/HiveMind/agents/errs"
+	"github.com/suissa/HiveMind/agents/i18n"
+	"github.com/suissa/HiveMind/agents/memory"
+	"github.com/suissa/HiveMind/agents/metrics"
+	"github.com/suissa/HiveMind/agents/policy"
+	"github.com/suissa/HiveMind/agents/scaling"
+	"github.com/suissa/HiveMind/agents/tenant"
+	"github.com/suissa/HiveMind/orchestrator"
+)
+
+// TaskSubmitter publica tarefas recebidas pela API no barramento
+type TaskSubmitter interface {
+	SubmitTask(ctx context.Context, task orchestrator.TaskRequest) error
+}
+
+// ApprovalGate guarda as aprovações pendentes das tarefas (ex.: *approval.Gate)
+type ApprovalGate interface {
+	Pending(tenant string) []approval.Request
+	Decide(tenant, id string, decision approval.Decision) error
+}
+
+// ScalingController lê e altera as políticas de escalonamento dos agentes (ex.:
+// agents.OrchestratorInfrastructureAgent)
+type ScalingController interface {
+	ScalingPolicies() *scaling.Policies
+	SetScalingPolicies(policies *scaling.Policies) error
+}
+
+// Server expõe a API HTTP de gerenciamento do HiveMind
+type Server struct {
+	addr      string
+	mux       *http.ServeMux
+	memory    memory.MemoryManager
+	submitter TaskSubmitter
+	approvals ApprovalGate
+	scaling   ScalingController
+	auth      Authenticator
+	insecure  bool // Sem autenticador, aceita as requisições (só para desenvolvimento)
+	http      *http.Server
+}
+
+// NewServer cria uma nova API de gerenciamento
+func NewServer(addr string, memManager memory.MemoryManager, submitter TaskSubmitter) *Server {
+	s := &Server{
+		addr:      addr,
+		mux:       http.NewServeMux(),
+		memory:    memManager,
+		submitter: submitter,
+	}
+
+	s.mux.HandleFunc("/healthz", s.handleHealth)
+	s.mux.Handle("/metrics", withLocale(withTenant(s.authorize(PermReadMetrics, metrics.Default.Handler()))))
+	s.mux.Handle("/v1/tasks", withLocale(withTenant(s.authorize(PermSubmitTasks, http.HandlerFunc(s.handleTasks)))))
+	s.mux.Handle("/v1/memories", withLocale(withTenant(s.authorize(PermReadMemories, http.HandlerFunc(s.handleMemories)))))
+	s.mux.Handle("/v1/memories/stats", withLocale(withTenant(s.authorize(PermReadMemories, http.HandlerFunc(s.handleMemoryStats)))))
+	s.mux.Handle("/v1/memories/timeline", withLocale(withTenant(s.authorize(PermReadMemories, http.HandlerFunc(s.handleMemoryTimeline)))))
+	s.mux.Handle("/v1/subjects", withLocale(withTenant(s.authorize(PermEraseSubjects, http.HandlerFunc(s.handleSubjects)))))
+	s.mux.Handle("/v1/approvals", withLocale(withTenant(s.authorize(PermDecideApprovals, http.HandlerFunc(s.handleApprovals)))))
+	s.mux.Handle("/v1/approvals/", withLocale(withTenant(s.authorize(PermDecideApprovals, http.HandlerFunc(s.handleApprovalDecision)))))
+	s.mux.Handle("/v1/scaling/policies", withLocale(withTenant(s.authorize(PermWriteScaling, http.HandlerFunc(s.handleScalingPolicies)))))
+
+	return s
+}
+
+// SetAuthenticator define o autenticador usado pelo controle de acesso (RBAC)
+func (s *Server) SetAuthenticator(auth Authenticator) {
+	s.auth = auth
+}
+
+// SetApprovals expõe as aprovações pendentes do portão em /v1/approvals
+func (s *Server) SetApprovals(gate ApprovalGate) {
+	s.approvals = gate
+}
+
+// SetScaling expõe as políticas de escalonamento em /v1/scaling/policies
+func (s *Server) SetScaling(controller ScalingController) {
+	s.scaling = controller
+}
+
+// SetInsecure permite atender as requisições sem autenticador configurado, só para
+// desenvolvimento; sem ele, a API sem autenticador recusa as rotas protegidas
+func (s *Server) SetInsecure(insecure bool) {
+	s.insecure = insecure
+}
+
+// Handler retorna o http.Handler da API
+func (s *Server) Handler() http.Handler {
+	return s.mux
+}
+
+// Start abre o endereço e atende as requisições em background; um endereço em uso é
+// retornado como erro
+func (s *Server) Start() error {
+	if s.auth == nil && !s.insecure {
+		return fmt.Errorf("API de gerenciamento sem autenticação configurada")
+	}
+	listener, err := net.Listen("tcp", s.addr)
+	if err != nil {
+		return fmt.Errorf("erro ao abrir a API de gerenciamento em %s: %v", s.addr, err)
+	}
+	s.http = &http.Server{
+		Addr:              s.addr,
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		if err := s.http.Serve(listener); err != nil && err != http.ErrServerClosed {
+			log.Printf("❌ Erro na API de gerenciamento: %v", err)
+		}
+	}()
+
+	if s.auth == nil {
+		log.Printf("⚠️ API de gerenciamento sem autenticação (modo inseguro de desenvolvimento)")
+	}
+	log.Printf("🌐 API de gerenciamento ouvindo em %s", listener.Addr())
+	return nil
+}
+
+// Shutdown encerra o servidor HTTP
+func (s *Server) Shutdown(ctx context.Context) error {
+	if s.http == nil {
+		return nil
+	}
+	if err := s.http.Shutdown(ctx); err != nil {
+		return fmt.Errorf("erro ao encerrar API de gerenciamento: %v", err)
+	}
+	return nil
+}
+
+// withLocale associa ao contexto o idioma do header Accept-Language, usado nas
+// mensagens de erro da resposta; sem idioma suportado vale o padrão do i18n
+func withLocale(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		locale, ok := i18n.FromAcceptLanguage(r.Header.Get("Accept-Language"))
+		if !ok {
+			locale = i18n.Default()
+		}
+		w.Header().Set("Content-Language", string(locale))
+		next.ServeHTTP(w, r.WithContext(i18n.WithLocale(r.Context(), locale)))
+	})
+}
+
+// withTenant extrai o tenant do header da requisição e o associa ao contexto
+func withTenant(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tenantID := strings.TrimSpace(r.Header.Get(tenant.HeaderName))
+		if tenantID == "" {
+			tenantID = tenant.DefaultTenant
+		}
+		if err := tenant.Validate(tenantID); err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(tenant.WithTenant(r.Context(), tenantID)))
+	})
+}
+
+// handleHealth responde ao health check
+func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// handleTasks recebe novas tarefas para o tenant da requisição
+func (s *Server) handleTasks(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, errors.New(i18n.T(r.Context(), "api.method_not_allowed", r.Method)))
+		return
+	}
+
+	var task orchestrator.TaskRequest
+	if err := json.NewDecoder(r.Body).Decode(&task); err != nil {
+		writeError(w, http.StatusBadRequest, errors.New(i18n.T(r.Context(), "api.invalid_task", err)))
+		return
+	}
+
+	// O tenant da tarefa é sempre o da requisição
+	task.Tenant = tenant.FromContext(r.Context())
+
+	if err := s.submitter.SubmitTask(r.Context(), task); err != nil {
+		if errors.Is(err, policy.ErrDenied) {
+			writeError(w, http.StatusForbidden, err)
+			return
+		}
+		// Overrides fora dos limites configurados
+		if errors.Is(err, errs.ErrValidation) {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		writeError(w, http.StatusBadGateway, err)
+		return
+	}
+
+	writeJSON(w, http.StatusAccepted, task)
+}
+
+// handleMemories lista as memórias de um agente do tenant da requisição. Com os parâmetros
+// limit ou cursor a resposta é paginada (MemoryPage), da memória mais recente para a mais antiga.
+func (s *Server) handleMemories(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, errors.New(i18n.T(r.Context(), "api.method_not_allowed", r.Method)))
+		return
+	}
+
+	agentID := r.URL.Query().Get("agent_id")
+	if agentID == "" {
+		writeError(w, http.StatusBadRequest, errors.New(i18n.T(r.Context(), "api.agent_id_required")))
+		return
+	}
+	if !s.requireMemory(w, r) {
+		return
+	}
+
+	var tags []string
+	if raw := r.URL.Query().Get("tags"); raw != "" {
+		tags = strings.Split(raw, ",")
+	}
+
+	if query := r.URL.Query(); query.Has("limit") || query.Has("cursor") {
+		s.handleMemoryPage(w, r, agentID, tags)
+		return
+	}
+
+	memories, err := s.memory.SearchMemories(r.Context(), agentID, tags)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, memories)
+}
+
+// handleMemoryPage responde com uma página das memórias do agente
+func (s *Server) handleMemoryPage(w http.ResponseWriter, r *http.Request, agentID string, tags []string) {
+	searcher, ok := s.memory.(memory.PagedSearcher)
+	if !ok {
+		writeError(w, http.StatusNotImplemented, errors.New(i18n.T(r.Context(), "api.pagination_unsupported")))
+		return
+	}
+
+	page := memory.Page{Cursor: r.URL.Query().Get("cursor")}
+	if raw := r.URL.Query().Get("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit <= 0 {
+			writeError(w, http.StatusBadRequest, errors.New(i18n.T(r.Context(), "api.invalid_limit", raw)))
+			return
+		}
+		page.Limit = limit
+	}
+
+	result, err := searcher.SearchMemoriesPage(r.Context(), agentID, tags, page)
+	if err != nil {
+		// Cursor inválido
+		if errors.Is(err, errs.ErrValidation) {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, result)
+}
+
+// handleMemoryStats responde com a quantidade e a distribuição de importância das memórias
+// por agente do tenant da requisição (GET /v1/memories/stats[?agent_id=...])
+func (s *Server) handleMemoryStats(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, errors.New(i18n.T(r.Context(), "api.method_not_allowed", r.Method)))
+		return
+	}
+
+	if !s.requireMemory(w, r) {
+		return
+	}
+	provider, ok := s.memory.(memory.StatsProvider)
+	if !ok {
+		writeError(w, http.StatusNotImplemented, errors.New(i18n.T(r.Context(), "api.stats_unsupported")))
+		return
+	}
+
+	stats, err := provider.MemoryStats(r.Context(), r.URL.Query().Get("agent_id"))
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, stats)
+}
+
+// handleMemoryTimeline responde com as memórias do agente em ordem cronológica, com a
+// proveniência de cada uma (GET /v1/memories/timeline?agent_id=...&from=...&to=..., datas
+// em RFC 3339; por padrão as últimas 24 horas)
+func (s *Server) handleMemoryTimeline(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, errors.New(i18n.T(r.Context(), "api.method_not_allowed", r.Method)))
+		return
+	}
+
+	agentID := r.URL.Query().Get("agent_id")
+	if agentID == "" {
+		writeError(w, http.StatusBadRequest, errors.New(i18n.T(r.Context(), "api.agent_id_required")))
+		return
+	}
+
+	if !s.requireMemory(w, r) {
+		return
+	}
+	timeline, ok := s.memory.(memory.TimelineProvider)
+	if !ok {
+		writeError(w, http.StatusNotImplemented, errors.New(i18n.T(r.Context(), "api.timeline_unsupported")))
+		return
+	}
+
+	to := time.Now()
+	from := to.Add(-24 * time.Hour)
+	for name, target := range map[string]*time.Time{"from": &from, "to": &to} {
+		raw := r.URL.Query().Get(name)
+		if raw == "" {
+			continue
+		}
+		value, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, errors.New(i18n.T(r.Context(), "api.invalid_time", name, raw)))
+			return
+		}
+		*target = value
+	}
+
+	memories, err := timeline.GetMemoryTimeline(r.Context(), agentID, from, to)
+	if err != nil {
+		if errors.Is(err, errs.ErrValidation) {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, memories)
+}
+
+// handleSubjects remove todas as memórias de um titular de dados no tenant da requisição
+// (DELETE /v1/subjects?subject_id=...) e responde com o relatório da exclusão
+func (s *Server) handleSubjects(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		writeError(w, http.StatusMethodNotAllowed, errors.New(i18n.T(r.Context(), "api.method_not_allowed", r.Method)))
+		return
+	}
+
+	subjectID := r.URL.Query().Get("subject_id")
+	if subjectID == "" {
+		writeError(w, http.StatusBadRequest, errors.New(i18n.T(r.Context(), "api.subject_id_required")))
+		return
+	}
+
+	if !s.requireMemory(w, r) {
+		return
+	}
+	eraser, ok := s.memory.(memory.SubjectEraser)
+	if !ok {
+		writeError(w, http.StatusNotImplemented, errors.New(i18n.T(r.Context(), "api.erasure_unsupported")))
+		return
+	}
+
+	report, err := eraser.DeleteMemoriesBySubject(r.Context(), subjectID)
+	if err != nil {
+		if report == nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+		// Exclusão parcial: o relatório indica os armazenamentos que falharam
+		writeJSON(w, http.StatusBadGateway, report)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, report)
+}
+
+// handleApprovals lista as aprovações pendentes do tenant da requisição (GET /v1/approvals)
+func (s *Server) handleApprovals(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, errors.New(i18n.T(r.Context(), "api.method_not_allowed", r.Method)))
+		return
+	}
+	if s.approvals == nil {
+		writeError(w, http.StatusNotImplemented, errors.New(i18n.T(r.Context(), "api.approvals_unavailable")))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, s.approvals.Pending(tenant.FromContext(r.Context())))
+}
+
+// handleApprovalDecision aprova ou rejeita uma tarefa pendente do tenant da requisição
+// (POST /v1/approvals/{id} com {"approved": true, "comment": "..."}); o revisor é o
+// principal autenticado
+func (s *Server) handleApprovalDecision(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, errors.New(i18n.T(r.Context(), "api.method_not_allowed", r.Method)))
+		return
+	}
+	if s.approvals == nil {
+		writeError(w, http.StatusNotImplemented, errors.New(i18n.T(r.Context(), "api.approvals_unavailable")))
+		return
+	}
+
+	var decision approval.Decision
+	if err := json.NewDecoder(r.Body).Decode(&decision); err != nil {
+		writeError(w, http.StatusBadRequest, errors.New(i18n.T(r.Context(), "api.invalid_decision", err)))
+		return
+	}
+	// O revisor é sempre o principal autenticado, nunca o informado no corpo
+	decision.Reviewer = ""
+	if principal, ok := PrincipalFromContext(r.Context()); ok {
+		decision.Reviewer = principal.Name
+	}
+	decision.DecidedAt = time.Now()
+
+	id := strings.TrimPrefix(r.URL.Path, "/v1/approvals/")
+	if err := s.approvals.Decide(tenant.FromContext(r.Context()), id, decision); err != nil {
+		if errors.Is(err, errs.ErrNotFound) {
+			writeError(w, http.StatusNotFound, err)
+			return
+		}
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, decision)
+}
+
+// handleScalingPolicies responde com as políticas de escalonamento (GET) ou as substitui
+// (PUT /v1/scaling/policies com default e agents, como no YAML de scaling.LoadPolicies)
+func (s *Server) handleScalingPolicies(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodPut {
+		writeError(w, http.StatusMethodNotAllowed, errors.New(i18n.T(r.Context(), "api.method_not_allowed", r.Method)))
+		return
+	}
+	if s.scaling == nil {
+		writeError(w, http.StatusNotImplemented, errors.New(i18n.T(r.Context(), "api.scaling_unavailable")))
+		return
+	}
+
+	if r.Method == http.MethodGet {
+		writeJSON(w, http.StatusOK, s.scaling.ScalingPolicies())
+		return
+	}
+
+	var policies scaling.Policies
+	if err := json.NewDecoder(r.Body).Decode(&policies); err != nil {
+		writeError(w, http.StatusBadRequest, errors.New(i18n.T(r.Context(), "api.invalid_policies", err)))
+		return
+	}
+	if err := s.scaling.SetScalingPolicies(&policies); err != nil {
+		if errors.Is(err, errs.ErrValidation) {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, &policies)
+}
+
+// requireMemory responde 501 quando a API foi iniciada sem gerenciador de memória (por
+// exemplo, pelo cmd/main, que só roteia tarefas)
+func (s *Server) requireMemory(w http.ResponseWriter, r *http.Request) bool {
+	if s.memory == nil {
+		writeError(w, http.StatusNotImplemented, errors.New(i18n.T(r.Context(), "api.memory_unavailable")))
+		return false
+	}
+	return true
+}
+
+// writeJSON serializa a resposta em JSON
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		log.Printf("❌ Erro ao serializar resposta: %v", err)
+	}
+}
+
+// writeError responde com uma mensagem de erro padronizada
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
@@ -0,0 +1,184 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/suissa/HiveMind/agents/approval"
+	"github.com/suissa/HiveMind/agents/scaling"
+	"github.com/suissa/HiveMind/agents/tenant"
+)
+
+func TestWithTenant(t *testing.T) {
+	cases := []struct {
+		name   string
+		header string
+		status int
+		want   string
+	}{
+		{"sem header", "", http.StatusOK, tenant.DefaultTenant},
+		{"só espaços", "   ", http.StatusOK, tenant.DefaultTenant},
+		{"tenant válido", "acme", http.StatusOK, "acme"},
+		{"espaços nas bordas", " acme-corp ", http.StatusOK, "acme-corp"},
+		{"maiúsculas", "Acme", http.StatusBadRequest, ""},
+		{"ponto", "acme.corp", http.StatusBadRequest, ""},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var got string
+			handler := withTenant(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = tenant.FromContext(r.Context())
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/v1/memories", nil)
+			if c.header != "" {
+				req.Header.Set(tenant.HeaderName, c.header)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != c.status {
+				t.Fatalf("status %d, esperado %d", rec.Code, c.status)
+			}
+			if got != c.want {
+				t.Errorf("tenant %q, esperado %q", got, c.want)
+			}
+		})
+	}
+}
+
+// operatorServer cria a API com a chave de um operador
+func operatorServer(t *testing.T) *Server {
+	t.Helper()
+	auth := NewAPIKeyAuthenticator()
+	if err := auth.AddKey("operator-key", &Principal{Name: "maria", Role: RoleOperator}); err != nil {
+		t.Fatal(err)
+	}
+	server := NewServer(":0", nil, acceptingSubmitter{})
+	server.SetAuthenticator(auth)
+	return server
+}
+
+// serve executa a requisição do operador no tenant informado
+func serve(server *Server, method, path, tenantID, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("X-API-Key", "operator-key")
+	if tenantID != "" {
+		req.Header.Set(tenant.HeaderName, tenantID)
+	}
+	rec := httptest.NewRecorder()
+	server.Handler().ServeHTTP(rec, req)
+	return rec
+}
+
+func TestApprovalRoutes(t *testing.T) {
+	gate := approval.NewGate()
+	server := operatorServer(t)
+	server.SetApprovals(gate)
+
+	decided := make(chan approval.Decision, 1)
+	requested := make(chan approval.Request, 1)
+	go func() {
+		decision, _ := gate.Await(context.Background(), approval.Request{Tenant: "acme", TaskID: "t-1"}, func(req approval.Request) {
+			requested <- req
+		})
+		decided <- decision
+	}()
+	id := (<-requested).ID
+
+	rec := serve(server, http.MethodGet, "/v1/approvals", "acme", "")
+	var pending []approval.Request
+	if err := json.NewDecoder(rec.Body).Decode(&pending); err != nil || rec.Code != http.StatusOK {
+		t.Fatalf("status %d, erro %v", rec.Code, err)
+	}
+	if len(pending) != 1 || pending[0].ID != id {
+		t.Fatalf("aprovações pendentes inesperadas: %+v", pending)
+	}
+	if rec := serve(server, http.MethodGet, "/v1/approvals", "globex", ""); strings.TrimSpace(rec.Body.String()) != "[]" {
+		t.Errorf("outro tenant não deveria ver a aprovação: %s", rec.Body)
+	}
+
+	cases := []struct {
+		name   string
+		method string
+		id     string
+		tenant string
+		body   string
+		status int
+	}{
+		{"método inválido", http.MethodGet, id, "acme", "", http.StatusMethodNotAllowed},
+		{"corpo inválido", http.MethodPost, id, "acme", "{", http.StatusBadRequest},
+		{"aprovação desconhecida", http.MethodPost, "outra", "acme", `{"approved":true}`, http.StatusNotFound},
+		{"outro tenant", http.MethodPost, id, "globex", `{"approved":true}`, http.StatusNotFound},
+		{"aprovada", http.MethodPost, id, "acme", `{"approved":true,"reviewer":"forjado","comment":"ok"}`, http.StatusOK},
+		{"já decidida", http.MethodPost, id, "acme", `{"approved":false}`, http.StatusNotFound},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			rec := serve(server, c.method, "/v1/approvals/"+c.id, c.tenant, c.body)
+			if rec.Code != c.status {
+				t.Fatalf("status %d, esperado %d: %s", rec.Code, c.status, rec.Body)
+			}
+		})
+	}
+
+	select {
+	case decision := <-decided:
+		if !decision.Approved || decision.Reviewer != "maria" || decision.Comment != "ok" {
+			t.Errorf("decisão inesperada: %+v", decision)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("a tarefa deveria ter recebido a decisão")
+	}
+}
+
+// fakeScaling guarda as políticas de escalonamento
+type fakeScaling struct {
+	policies *scaling.Policies
+}
+
+func (f *fakeScaling) ScalingPolicies() *scaling.Policies { return f.policies }
+
+func (f *fakeScaling) SetScalingPolicies(policies *scaling.Policies) error {
+	if err := policies.Validate(); err != nil {
+		return err
+	}
+	f.policies = policies
+	return nil
+}
+
+func TestScalingPolicies(t *testing.T) {
+	cases := []struct {
+		name   string
+		method string
+		body   string
+		status int
+		max    int // MaxInstances da política padrão depois da requisição
+	}{
+		{"leitura", http.MethodGet, "", http.StatusOK, 4},
+		{"alteração", http.MethodPut, `{"default":{"max_instances":8},"agents":{"writer":{"min_instances":2}}}`, http.StatusOK, 8},
+		{"política inválida", http.MethodPut, `{"default":{"min_instances":5,"max_instances":2}}`, http.StatusBadRequest, 4},
+		{"corpo inválido", http.MethodPut, "{", http.StatusBadRequest, 4},
+		{"método inválido", http.MethodPost, "", http.StatusMethodNotAllowed, 4},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			controller := &fakeScaling{policies: &scaling.Policies{Default: scaling.Policy{MaxInstances: 4}}}
+			server := operatorServer(t)
+			server.SetScaling(controller)
+
+			rec := serve(server, c.method, "/v1/scaling/policies", "", c.body)
+			if rec.Code != c.status {
+				t.Fatalf("status %d, esperado %d: %s", rec.Code, c.status, rec.Body)
+			}
+			if got := controller.policies.Default.MaxInstances; got != c.max {
+				t.Errorf("max_instances %d, esperado %d", got, c.max)
+			}
+		})
+	}
+}
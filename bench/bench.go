@@ -0,0 +1,203 @@
+// Package bench mede throughput e latência das camadas de memória, mensageria e
+// workflows, comparando os resultados com limites de regressão (baseline).
+package bench
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Stats contém o resultado de uma medição
+type Stats struct {
+	Name       string        `json:"name"`
+	Ops        int           `json:"ops"`
+	Errors     int           `json:"errors"`
+	Duration   time.Duration `json:"duration"`
+	Throughput float64       `json:"throughput"` // Operações por segundo
+	P50        time.Duration `json:"p50"`
+	P95        time.Duration `json:"p95"`
+	P99        time.Duration `json:"p99"`
+	Max        time.Duration `json:"max"`
+}
+
+// ErrorRate retorna a fração de operações com erro
+func (s Stats) ErrorRate() float64 {
+	if s.Ops == 0 {
+		return 0
+	}
+	return float64(s.Errors) / float64(s.Ops)
+}
+
+// String formata o resultado para o terminal
+func (s Stats) String() string {
+	return fmt.Sprintf("%-16s ops=%-7d erros=%-5d %.1f op/s p50=%v p95=%v p99=%v max=%v",
+		s.Name, s.Ops, s.Errors, s.Throughput, s.P50, s.P95, s.P99, s.Max)
+}
+
+// Recorder acumula latências de forma concorrente
+type Recorder struct {
+	name      string
+	latencies []time.Duration
+	errors    int
+	start     time.Time
+	mu        sync.Mutex
+}
+
+// NewRecorder cria um Recorder para o cenário informado
+func NewRecorder(name string) *Recorder {
+	return &Recorder{
+		name:      name,
+		latencies: make([]time.Duration, 0, 1024),
+		start:     time.Now(),
+	}
+}
+
+// Observe registra a latência de uma operação
+func (r *Recorder) Observe(latency time.Duration, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.latencies = append(r.latencies, latency)
+	if err != nil {
+		r.errors++
+	}
+}
+
+// Stats calcula as estatísticas acumuladas
+func (r *Recorder) Stats() Stats {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	elapsed := time.Since(r.start)
+	sorted := make([]time.Duration, len(r.latencies))
+	copy(sorted, r.latencies)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	stats := Stats{
+		Name:     r.name,
+		Ops:      len(sorted),
+		Errors:   r.errors,
+		Duration: elapsed,
+	}
+	if elapsed > 0 {
+		stats.Throughput = float64(len(sorted)) / elapsed.Seconds()
+	}
+	if len(sorted) > 0 {
+		stats.P50 = percentile(sorted, 0.50)
+		stats.P95 = percentile(sorted, 0.95)
+		stats.P99 = percentile(sorted, 0.99)
+		stats.Max = sorted[len(sorted)-1]
+	}
+	return stats
+}
+
+// percentile retorna o percentil de uma lista ordenada
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	index := int(float64(len(sorted)-1) * p)
+	return sorted[index]
+}
+
+// Run executa fn com a concorrência informada até o fim da duração (ou do contexto)
+func Run(ctx context.Context, name string, duration time.Duration, concurrency int, fn func(ctx context.Context) error) Stats {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, duration)
+	defer cancel()
+
+	recorder := NewRecorder(name)
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for ctx.Err() == nil {
+				start := time.Now()
+				err := fn(ctx)
+				// Operações interrompidas pelo fim da medição não entram no resultado
+				if err != nil && ctx.Err() != nil {
+					return
+				}
+				recorder.Observe(time.Since(start), err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	return recorder.Stats()
+}
+
+// Threshold define os limites aceitos para um cenário
+type Threshold struct {
+	MinThroughput float64 `yaml:"min_throughput"` // op/s
+	MaxP95        string  `yaml:"max_p95"`        // Ex.: "50ms"
+	MaxP99        string  `yaml:"max_p99"`
+	MaxErrorRate  float64 `yaml:"max_error_rate"` // Ex.: 0.01 = 1%
+}
+
+// Baseline contém os limites de regressão por cenário
+type Baseline struct {
+	Scenarios map[string]Threshold `yaml:"scenarios"`
+}
+
+// LoadBaseline carrega os limites de um arquivo YAML
+func LoadBaseline(filename string) (*Baseline, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler baseline: %v", err)
+	}
+
+	var baseline Baseline
+	if err := yaml.Unmarshal(data, &baseline); err != nil {
+		return nil, fmt.Errorf("erro ao decodificar baseline: %v", err)
+	}
+	return &baseline, nil
+}
+
+// Check compara o resultado com os limites do cenário e retorna as violações
+func (b *Baseline) Check(stats Stats) ([]string, error) {
+	threshold, ok := b.Scenarios[stats.Name]
+	if !ok {
+		return nil, nil
+	}
+
+	violations := make([]string, 0)
+	if threshold.MinThroughput > 0 && stats.Throughput < threshold.MinThroughput {
+		violations = append(violations, fmt.Sprintf("%s: throughput %.1f op/s abaixo do mínimo %.1f op/s",
+			stats.Name, stats.Throughput, threshold.MinThroughput))
+	}
+
+	for _, limit := range []struct {
+		label string
+		value time.Duration
+		max   string
+	}{
+		{"p95", stats.P95, threshold.MaxP95},
+		{"p99", stats.P99, threshold.MaxP99},
+	} {
+		if limit.max == "" {
+			continue
+		}
+		max, err := time.ParseDuration(limit.max)
+		if err != nil {
+			return nil, fmt.Errorf("limite %s inválido para %s: %v", limit.label, stats.Name, err)
+		}
+		if limit.value > max {
+			violations = append(violations, fmt.Sprintf("%s: latência %s %v acima do máximo %v",
+				stats.Name, limit.label, limit.value, max))
+		}
+	}
+
+	if threshold.MaxErrorRate > 0 && stats.ErrorRate() > threshold.MaxErrorRate {
+		violations = append(violations, fmt.Sprintf("%s: taxa de erro %.2f%% acima do máximo %.2f%%",
+			stats.Name, stats.ErrorRate()*100, threshold.MaxErrorRate*100))
+	}
+
+	return violations, nil
+}
@@ -0,0 +1,54 @@
+package bench
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRun(t *testing.T) {
+	calls := 0
+	stats := Run(context.Background(), "noop", 50*time.Millisecond, 1, func(ctx context.Context) error {
+		calls++
+		time.Sleep(time.Millisecond)
+		if calls%10 == 0 {
+			return errors.New("falha simulada")
+		}
+		return nil
+	})
+
+	if stats.Ops == 0 || stats.Throughput == 0 {
+		t.Fatalf("Nenhuma operação medida: %+v", stats)
+	}
+	if stats.Errors == 0 {
+		t.Error("Erros simulados não foram contados")
+	}
+	if stats.P50 > stats.P99 || stats.P99 > stats.Max {
+		t.Errorf("Percentis inconsistentes: %+v", stats)
+	}
+}
+
+func TestBaselineCheck(t *testing.T) {
+	baseline := &Baseline{Scenarios: map[string]Threshold{
+		"memory-store": {MinThroughput: 100, MaxP95: "10ms", MaxErrorRate: 0.01},
+	}}
+
+	ok := Stats{Name: "memory-store", Ops: 1000, Throughput: 500, P95: 5 * time.Millisecond}
+	if violations, err := baseline.Check(ok); err != nil || len(violations) != 0 {
+		t.Errorf("Resultado dentro dos limites não deveria violar: %v, %v", violations, err)
+	}
+
+	slow := Stats{Name: "memory-store", Ops: 1000, Errors: 50, Throughput: 50, P95: 20 * time.Millisecond}
+	violations, err := baseline.Check(slow)
+	if err != nil {
+		t.Fatalf("Erro ao verificar baseline: %v", err)
+	}
+	if len(violations) != 3 {
+		t.Errorf("Esperadas 3 violações, obtidas %d: %v", len(violations), violations)
+	}
+
+	if violations, _ := baseline.Check(Stats{Name: "desconhecido"}); len(violations) != 0 {
+		t.Error("Cenário sem baseline não deveria violar")
+	}
+}
@@ -9,6 +9,9 @@ go build -o bin/consumer cmd/consume/main.go
 echo "🔨 Compilando o criador de capítulo..."
 go build -o bin/create_chapter cmd/create_chapter/main.go
 
+echo "🔨 Compilando a CLI do HiveMind..."
+go build -o bin/hivemind ./cmd/hivemind
+
 echo "✅ Compilação concluída!"
 echo ""
 echo "Para executar:"
@@ -1,7 +1,25 @@
 package main
 
-import "groq-consumer/consumers"
+import (
+	"log"
+
+	"github.com/joho/godotenv"
+
+	"github.com/suissa/HiveMind/agents/llm"
+	"github.com/suissa/HiveMind/consumers"
+)
 
 func main() {
+	if err := godotenv.Load(); err != nil {
+		log.Printf("⚠️ Arquivo .env não encontrado, usando valores padrão")
+	}
+
+	// Sem LLM, os agentes não geram quizzes nem desafios
+	provider, err := llm.GroqFromEnv()
+	if err != nil {
+		log.Fatalf("❌ Erro ao configurar o LLM dos consumidores: %v", err)
+	}
+	consumers.SetLLM(provider)
+
 	consumers.StartConsumers()
 }
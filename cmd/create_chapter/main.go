@@ -3,17 +3,18 @@ package main
 import (
 	"log"
 
-	"groq-consumer/publishers"
+	"github.com/suissa/HiveMind/agents/messages"
+	"github.com/suissa/HiveMind/publishers"
 )
 
 func main() {
 	// Criar mensagem para um novo capítulo sobre IA
-	chapterMessage := map[string]interface{}{
-		"tema": "Inteligência Artificial: Fundamentos e Aplicações",
-		"user_info": map[string]interface{}{
-			"level":      "Iniciante",
-			"profession": "estudante",
-			"age":        25,
+	chapterMessage := messages.ChapterCreationMessage{
+		Tema: "Inteligência Artificial: Fundamentos e Aplicações",
+		UserInfo: messages.UserInfo{
+			Level:      "Iniciante",
+			Profession: "estudante",
+			Age:        25,
 		},
 	}
 
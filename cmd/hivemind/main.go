@@ -0,0 +1,532 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/joho/godotenv"
+	"gopkg.in/yaml.v2"
+
+	"github.com/suissa/HiveMind/agents"
+	"github.com/suissa/HiveMind/agents/interop"
+	"github.com/suissa/HiveMind/agents/llm"
+	"github.com/suissa/HiveMind/agents/memory"
+	"github.com/suissa/HiveMind/agents/progress"
+	"github.com/suissa/HiveMind/agents/templates"
+	"github.com/suissa/HiveMind/agents/tenant"
+)
+
+const usage = `Uso: hivemind <comando> [opções]
+
+Comandos:
+  backup   exporta Redis, MongoDB e Weaviate de um tenant para um arquivo
+  restore  restaura um arquivo de backup, total ou parcialmente
+  repair   remove dos índices de tags do Redis os IDs de memórias expiradas
+  reindex  recalcula os embeddings da memória semântica com outro modelo e troca o alias da classe
+  export   exporta os agentes do agents.yaml para LangChain ou LlamaIndex
+  import-crewai  converte os agents.yaml e tasks.yaml de um projeto CrewAI
+  template lista os templates de workflow ou instancia um com as variáveis informadas
+  models   verifica o servidor de inferência local (Ollama ou vLLM) e lista, baixa ou remove modelos
+
+Use "hivemind <comando> -h" para ver as opções de cada comando.
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	if err := godotenv.Load(); err != nil {
+		log.Printf("⚠️ Arquivo .env não encontrado, usando valores padrão")
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "backup":
+		err = runBackup(os.Args[2:])
+	case "restore":
+		err = runRestore(os.Args[2:])
+	case "repair":
+		err = runRepair(os.Args[2:])
+	case "reindex":
+		err = runReindex(os.Args[2:])
+	case "export":
+		err = runExport(os.Args[2:])
+	case "import-crewai":
+		err = runImportCrewAI(os.Args[2:])
+	case "template":
+		err = runTemplate(os.Args[2:])
+	case "models":
+		err = runModels(os.Args[2:])
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+}
+
+// runBackup executa "hivemind backup"
+func runBackup(args []string) error {
+	flags := flag.NewFlagSet("backup", flag.ExitOnError)
+	tenantID := flags.String("tenant", tenant.DefaultTenant, "tenant (namespace) exportado")
+	output := flags.String("o", "hivemind-backup.tar.gz", "arquivo de destino")
+	backends := flags.String("backends", "", "armazenamentos exportados, separados por vírgula (padrão: todos)")
+	flags.Parse(args)
+
+	ctx, err := tenantContext(*tenantID)
+	if err != nil {
+		return err
+	}
+	manager, err := newMemoryManager(ctx)
+	if err != nil {
+		return err
+	}
+	defer manager.Close(ctx)
+
+	// O arquivo é gravado com outro nome e renomeado apenas após o manifesto, para que
+	// um backup interrompido nunca substitua um completo
+	tmp := *output + ".partial"
+	file, err := os.Create(tmp)
+	if err != nil {
+		return fmt.Errorf("erro ao criar %s: %v", tmp, err)
+	}
+	manifest, err := manager.Backup(ctx, file, memory.BackupOptions{Backends: splitList(*backends)})
+	if closeErr := file.Close(); err == nil && closeErr != nil {
+		err = fmt.Errorf("erro ao gravar %s: %v", tmp, closeErr)
+	}
+	if err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	if err := os.Rename(tmp, *output); err != nil {
+		return fmt.Errorf("erro ao gravar %s: %v", *output, err)
+	}
+
+	for _, section := range manifest.Sections {
+		log.Printf("📦 %s: %d registros", section.Name, section.Records)
+	}
+	log.Printf("✅ Backup do tenant %s gravado em %s", manifest.Namespace, *output)
+	return nil
+}
+
+// runRestore executa "hivemind restore"
+func runRestore(args []string) error {
+	flags := flag.NewFlagSet("restore", flag.ExitOnError)
+	input := flags.String("i", "hivemind-backup.tar.gz", "arquivo de backup")
+	tenantID := flags.String("tenant", "", "tenant de destino (padrão: o de origem do backup)")
+	backends := flags.String("backends", "", "armazenamentos restaurados, separados por vírgula (padrão: todos)")
+	agentIDs := flags.String("agents", "", "restaura apenas as memórias destes agentes, separados por vírgula")
+	flags.Parse(args)
+
+	ctx := context.Background()
+	if *tenantID != "" {
+		var err error
+		if ctx, err = tenantContext(*tenantID); err != nil {
+			return err
+		}
+	}
+
+	file, err := os.Open(*input)
+	if err != nil {
+		return fmt.Errorf("erro ao abrir %s: %v", *input, err)
+	}
+	defer file.Close()
+
+	manager, err := newMemoryManager(ctx)
+	if err != nil {
+		return err
+	}
+	defer manager.Close(ctx)
+
+	report, err := manager.Restore(ctx, file, memory.RestoreOptions{
+		Backends: splitList(*backends),
+		AgentIDs: splitList(*agentIDs),
+	})
+	if report != nil {
+		data, _ := json.MarshalIndent(report, "", "  ")
+		fmt.Println(string(data))
+	}
+	if err != nil {
+		return err
+	}
+	log.Printf("✅ Backup restaurado no tenant %s", report.Namespace)
+	return nil
+}
+
+// runRepair executa "hivemind repair"
+func runRepair(args []string) error {
+	flags := flag.NewFlagSet("repair", flag.ExitOnError)
+	tenantID := flags.String("tenant", tenant.DefaultTenant, "tenant (namespace) reparado")
+	flags.Parse(args)
+
+	ctx, err := tenantContext(*tenantID)
+	if err != nil {
+		return err
+	}
+	manager, err := newMemoryManager(ctx)
+	if err != nil {
+		return err
+	}
+	defer manager.Close(ctx)
+
+	report, err := manager.RepairIndexes(ctx)
+	if err != nil {
+		return err
+	}
+	log.Printf("✅ %d índices verificados no tenant %s, %d IDs pendentes removidos", report.IndexesSeen, report.Namespace, report.Removed)
+	return nil
+}
+
+// runReindex executa "hivemind reindex"
+func runReindex(args []string) error {
+	flags := flag.NewFlagSet("reindex", flag.ExitOnError)
+	tenantID := flags.String("tenant", tenant.DefaultTenant, "tenant (namespace) reindexado")
+	backend := flags.String("backend", "ollama", "servidor dos embeddings: ollama ou vllm")
+	baseURL := flags.String("url", "", "endereço do servidor (padrão: OLLAMA_HOST ou VLLM_BASE_URL)")
+	model := flags.String("model", "", "modelo de embeddings (padrão: OLLAMA_EMBEDDING_MODEL ou VLLM_EMBEDDING_MODEL)")
+	batchSize := flags.Int("batch", 0, "memórias por lote (padrão: weaviate_batch_size)")
+	drop := flags.Bool("drop", false, "remove a classe anterior depois da troca do alias")
+	flags.Parse(args)
+
+	var embedder memory.Embedder
+	switch *backend {
+	case "ollama":
+		ollama := llm.OllamaFromEnv()
+		if *baseURL != "" {
+			ollama.BaseURL = strings.TrimRight(*baseURL, "/")
+		}
+		if *model != "" {
+			ollama.EmbeddingModel = *model
+		}
+		*model, embedder = ollama.EmbeddingModel, ollama
+	case "vllm":
+		vllm := llm.VLLMFromEnv()
+		if *baseURL != "" {
+			vllm.BaseURL = strings.TrimRight(*baseURL, "/")
+		}
+		if *model != "" {
+			vllm.EmbeddingModel = *model
+		}
+		*model, embedder = vllm.EmbeddingModel, vllm
+	default:
+		return fmt.Errorf("backend desconhecido %q: use ollama ou vllm", *backend)
+	}
+	if *model == "" {
+		return fmt.Errorf("informe o modelo de embeddings com -model")
+	}
+
+	ctx, err := tenantContext(*tenantID)
+	if err != nil {
+		return err
+	}
+	manager, err := newMemoryManager(ctx)
+	if err != nil {
+		return err
+	}
+	defer manager.Close(ctx)
+
+	ctx = progress.WithReporter(ctx, progress.ReporterFunc(func(percent float64, message string) {
+		log.Printf("🔄 %3.0f%% %s", percent, message)
+	}))
+	report, err := manager.Reindex(ctx, embedder, *model, memory.ReindexOptions{BatchSize: *batchSize, DropPrevious: *drop})
+	if report != nil {
+		data, _ := json.MarshalIndent(report, "", "  ")
+		fmt.Println(string(data))
+	}
+	if err != nil {
+		return err
+	}
+	log.Printf("✅ %d memórias do tenant %s reindexadas com %s; alias %s aponta para %s", report.Reindexed, report.Namespace, *model, report.Alias, report.Class)
+	return nil
+}
+
+// runExport executa "hivemind export"
+func runExport(args []string) error {
+	flags := flag.NewFlagSet("export", flag.ExitOnError)
+	format := flags.String("format", interop.FormatLangChain, "formato: langchain ou llamaindex")
+	configFile := flags.String("config", "", "agents.yaml com os agentes (ex.: examples/marketing/config/agents.yaml)")
+	toolsFile := flags.String("tools", "", "tools.yaml com as descrições das ferramentas (opcional)")
+	output := flags.String("o", "", "arquivo de destino (padrão: saída padrão)")
+	flags.Parse(args)
+
+	if *configFile == "" {
+		return fmt.Errorf("informe o agents.yaml com -config")
+	}
+	config, err := agents.LoadAgentsConfig(*configFile)
+	if err != nil {
+		return err
+	}
+	var registry *agents.ToolRegistry
+	if *toolsFile != "" {
+		if registry, err = describedTools(*toolsFile); err != nil {
+			return err
+		}
+	}
+	data, err := config.Export(*format, registry)
+	if err != nil {
+		return err
+	}
+	if *output == "" {
+		_, err = os.Stdout.Write(append(data, '\n'))
+		return err
+	}
+	if err := os.WriteFile(*output, data, 0644); err != nil {
+		return fmt.Errorf("erro ao gravar %s: %v", *output, err)
+	}
+	log.Printf("✅ %d agentes exportados para %s (%s)", len(config.Agents), *output, *format)
+	return nil
+}
+
+// runImportCrewAI executa "hivemind import-crewai"
+func runImportCrewAI(args []string) error {
+	flags := flag.NewFlagSet("import-crewai", flag.ExitOnError)
+	agentsFile := flags.String("agents", "config/agents.yaml", "agents.yaml do CrewAI")
+	tasksFile := flags.String("tasks", "config/tasks.yaml", "tasks.yaml do CrewAI")
+	inputs := flags.String("inputs", "", "valores dos {placeholders}, no formato chave=valor separados por vírgula (padrão: mantém os placeholders)")
+	output := flags.String("o", ".", "diretório onde gravar os agents.yaml e tasks.yaml convertidos")
+	flags.Parse(args)
+
+	var values map[string]string
+	if *inputs != "" {
+		values = make(map[string]string)
+		for _, pair := range splitList(*inputs) {
+			key, value, ok := strings.Cut(pair, "=")
+			if !ok {
+				return fmt.Errorf("entrada inválida %q: use chave=valor", pair)
+			}
+			values[strings.TrimSpace(key)] = strings.TrimSpace(value)
+		}
+	}
+
+	agentsConfig, tasksConfig, issues, err := agents.ImportCrewAI(*agentsFile, *tasksFile, values)
+	if err != nil {
+		return err
+	}
+	for _, issue := range issues {
+		log.Printf("⚠️ %s", issue)
+	}
+
+	if err := os.MkdirAll(*output, 0755); err != nil {
+		return fmt.Errorf("erro ao criar %s: %v", *output, err)
+	}
+	for name, config := range map[string]interface{}{"agents.yaml": agentsConfig, "tasks.yaml": tasksConfig} {
+		data, err := yaml.Marshal(config)
+		if err != nil {
+			return fmt.Errorf("erro ao serializar %s: %v", name, err)
+		}
+		if err := os.WriteFile(filepath.Join(*output, name), data, 0644); err != nil {
+			return fmt.Errorf("erro ao gravar %s: %v", name, err)
+		}
+	}
+	log.Printf("✅ %d agentes e %d tarefas importados para %s (%d campos ignorados)", len(agentsConfig.Agents), len(tasksConfig.Tasks), *output, len(issues))
+	return nil
+}
+
+// runTemplate executa "hivemind template"
+func runTemplate(args []string) error {
+	flags := flag.NewFlagSet("template", flag.ExitOnError)
+	name := flags.String("name", "", "template instanciado (sem -name, lista os templates)")
+	vars := make(variables)
+	flags.Var(vars, "var", "variável do template no formato nome=valor (repita para várias)")
+	output := flags.String("o", ".", "diretório onde gravar os agents.yaml e tasks.yaml do workflow")
+	flags.Parse(args)
+
+	if *name == "" {
+		for _, t := range templates.List() {
+			fmt.Printf("%s — %s\n", t.Name, t.Description)
+			for _, variable := range t.Variables {
+				detail := "obrigatória"
+				if !variable.Required {
+					detail = fmt.Sprintf("padrão: %s", variable.Default)
+				}
+				fmt.Printf("  -var %s=...  %s (%s)\n", variable.Name, variable.Description, detail)
+			}
+		}
+		return nil
+	}
+
+	workflow, err := agents.InstantiateTemplate(*name, vars)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(*output, 0755); err != nil {
+		return fmt.Errorf("erro ao criar %s: %v", *output, err)
+	}
+	for file, config := range map[string]interface{}{"agents.yaml": workflow.Agents, "tasks.yaml": workflow.Tasks()} {
+		data, err := yaml.Marshal(config)
+		if err != nil {
+			return fmt.Errorf("erro ao serializar %s: %v", file, err)
+		}
+		if err := os.WriteFile(filepath.Join(*output, file), data, 0644); err != nil {
+			return fmt.Errorf("erro ao gravar %s: %v", file, err)
+		}
+	}
+	log.Printf("🎯 %s: %s", workflow.Project.Name, workflow.Project.Objective)
+	log.Printf("✅ %d agentes e %d tarefas do template %s gravados em %s", len(workflow.Agents.Agents), len(workflow.Project.Tasks), *name, *output)
+	return nil
+}
+
+// runModels executa "hivemind models"
+func runModels(args []string) error {
+	flags := flag.NewFlagSet("models", flag.ExitOnError)
+	backend := flags.String("backend", "ollama", "servidor de inferência local: ollama ou vllm")
+	baseURL := flags.String("url", "", "endereço do servidor (padrão: OLLAMA_HOST ou VLLM_BASE_URL)")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Uso: hivemind models [opções] list | health | pull <modelo> | rm <modelo>")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	var provider llm.LocalProvider
+	var ollama *llm.Ollama
+	switch *backend {
+	case "ollama":
+		ollama = llm.OllamaFromEnv()
+		if *baseURL != "" {
+			ollama.BaseURL = strings.TrimRight(*baseURL, "/")
+		}
+		provider = ollama
+	case "vllm":
+		vllm := llm.VLLMFromEnv()
+		if *baseURL != "" {
+			vllm.BaseURL = strings.TrimRight(*baseURL, "/")
+		}
+		provider = vllm
+	default:
+		return fmt.Errorf("backend desconhecido %q: use ollama ou vllm", *backend)
+	}
+
+	ctx := context.Background()
+	action, model := flags.Arg(0), flags.Arg(1)
+	switch action {
+	case "", "list":
+		models, err := provider.Models(ctx)
+		if err != nil {
+			return err
+		}
+		for _, name := range models {
+			fmt.Println(name)
+		}
+		return nil
+	case "health":
+		if err := provider.Health(ctx); err != nil {
+			return err
+		}
+		log.Printf("✅ %s respondendo", *backend)
+		return nil
+	case "pull", "rm":
+		if model == "" {
+			return fmt.Errorf("informe o modelo: hivemind models %s <modelo>", action)
+		}
+		if ollama == nil {
+			return fmt.Errorf("o vLLM carrega os modelos na inicialização (vllm serve <modelo>); %s não se aplica", action)
+		}
+		if action == "rm" {
+			if err := ollama.Delete(ctx, model); err != nil {
+				return err
+			}
+			log.Printf("🗑️ Modelo %s removido", model)
+			return nil
+		}
+		ctx = progress.WithReporter(ctx, progress.ReporterFunc(func(percent float64, message string) {
+			log.Printf("⬇️ %3.0f%% %s", percent, message)
+		}))
+		if err := ollama.Pull(ctx, model); err != nil {
+			return err
+		}
+		log.Printf("✅ Modelo %s disponível", model)
+		return nil
+	default:
+		flags.Usage()
+		return fmt.Errorf("ação desconhecida %q", action)
+	}
+}
+
+// variables acumula as variáveis -var nome=valor
+type variables map[string]string
+
+func (v variables) String() string {
+	pairs := make([]string, 0, len(v))
+	for key, value := range v {
+		pairs = append(pairs, key+"="+value)
+	}
+	return strings.Join(pairs, ",")
+}
+
+func (v variables) Set(pair string) error {
+	key, value, ok := strings.Cut(pair, "=")
+	if !ok {
+		return fmt.Errorf("variável inválida %q: use nome=valor", pair)
+	}
+	v[strings.TrimSpace(key)] = strings.TrimSpace(value)
+	return nil
+}
+
+// describedTools registra as ferramentas do tools.yaml, só com nome e descrição, para que a
+// exportação as descreva
+func describedTools(filename string) (*agents.ToolRegistry, error) {
+	config, err := agents.LoadToolsConfig(filename)
+	if err != nil {
+		return nil, err
+	}
+	registry := agents.NewToolRegistry(nil, nil)
+	for _, category := range config.Tools {
+		for _, tool := range category {
+			if err := registry.Register(agents.NewFuncTool(tool.ID, tool.Description, nil)); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return registry, nil
+}
+
+// tenantContext valida o tenant e o associa ao contexto
+func tenantContext(id string) (context.Context, error) {
+	if err := tenant.Validate(id); err != nil {
+		return nil, err
+	}
+	return tenant.WithTenant(context.Background(), id), nil
+}
+
+// newMemoryManager conecta à memória híbrida configurada pelas variáveis de ambiente
+func newMemoryManager(ctx context.Context) (*memory.HybridMemoryManager, error) {
+	config := memory.DefaultMemoryConfig()
+	config.RedisURL = getEnv("REDIS_URL", "redis://localhost:6379")
+	config.MongoURL = getEnv("MONGO_URL", config.MongoURL)
+	config.MongoDB = getEnv("MONGO_DB", config.MongoDB)
+	config.WeaviateURL = getEnv("WEAVIATE_URL", "localhost:8080")
+
+	manager, err := memory.NewHybridMemoryManager(ctx, config)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao conectar à memória: %v", err)
+	}
+	return manager, nil
+}
+
+// splitList separa uma lista separada por vírgulas, ignorando itens vazios
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// getEnv retorna o valor da variável de ambiente ou o valor padrão
+func getEnv(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
@@ -0,0 +1,239 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/joho/godotenv"
+	"github.com/streadway/amqp"
+
+	"github.com/suissa/HiveMind/agents"
+	"github.com/suissa/HiveMind/agents/communication"
+	"github.com/suissa/HiveMind/agents/llm"
+	"github.com/suissa/HiveMind/agents/memory"
+	"github.com/suissa/HiveMind/agents/simulation"
+	"github.com/suissa/HiveMind/bench"
+)
+
+// Cenários disponíveis
+var scenarios = []string{"memory-store", "memory-search", "broker", "workflow"}
+
+func main() {
+	scenarioFlag := flag.String("scenarios", strings.Join(scenarios, ","), "cenários a executar, separados por vírgula")
+	duration := flag.Duration("duration", 30*time.Second, "duração de cada cenário")
+	concurrency := flag.Int("concurrency", 8, "número de workers concorrentes")
+	baselineFile := flag.String("baseline", "", "arquivo YAML com os limites de regressão (ex.: config/benchmarks.yaml)")
+	output := flag.String("output", "", "arquivo JSON para gravar os resultados")
+	flag.Parse()
+
+	if err := godotenv.Load(); err != nil {
+		log.Printf("⚠️ Arquivo .env não encontrado, usando valores padrão")
+	}
+
+	ctx := context.Background()
+	results := make([]bench.Stats, 0)
+
+	for _, scenario := range strings.Split(*scenarioFlag, ",") {
+		scenario = strings.TrimSpace(scenario)
+		log.Printf("🏋️ Executando cenário %s por %v com %d workers", scenario, *duration, *concurrency)
+
+		stats, err := runScenario(ctx, scenario, *duration, *concurrency)
+		if err != nil {
+			log.Fatalf("❌ Erro no cenário %s: %v", scenario, err)
+		}
+		log.Printf("📊 %s", stats)
+		results = append(results, stats)
+	}
+
+	if *output != "" {
+		data, err := json.MarshalIndent(results, "", "  ")
+		if err != nil {
+			log.Fatalf("❌ Erro ao serializar resultados: %v", err)
+		}
+		if err := os.WriteFile(*output, data, 0644); err != nil {
+			log.Fatalf("❌ Erro ao gravar resultados: %v", err)
+		}
+	}
+
+	if *baselineFile != "" {
+		baseline, err := bench.LoadBaseline(*baselineFile)
+		if err != nil {
+			log.Fatalf("❌ %v", err)
+		}
+
+		failed := false
+		for _, stats := range results {
+			violations, err := baseline.Check(stats)
+			if err != nil {
+				log.Fatalf("❌ %v", err)
+			}
+			for _, violation := range violations {
+				log.Printf("🚨 Regressão: %s", violation)
+				failed = true
+			}
+		}
+		if failed {
+			os.Exit(1)
+		}
+		log.Printf("✅ Todos os cenários dentro da baseline")
+	}
+}
+
+// runScenario executa um cenário de carga
+func runScenario(ctx context.Context, scenario string, duration time.Duration, concurrency int) (bench.Stats, error) {
+	switch scenario {
+	case "memory-store", "memory-search":
+		return runMemory(ctx, scenario, duration, concurrency)
+	case "broker":
+		return runBroker(ctx, duration, concurrency)
+	case "workflow":
+		return runWorkflow(ctx, duration, concurrency)
+	default:
+		return bench.Stats{}, fmt.Errorf("cenário desconhecido: %s (disponíveis: %s)", scenario, strings.Join(scenarios, ", "))
+	}
+}
+
+// runMemory mede o throughput de StoreMemory ou SearchMemories no HybridMemoryManager
+func runMemory(ctx context.Context, scenario string, duration time.Duration, concurrency int) (bench.Stats, error) {
+	config := memory.DefaultMemoryConfig()
+	config.RedisURL = getEnv("REDIS_URL", "redis://localhost:6379")
+	config.MongoURL = getEnv("MONGO_URL", config.MongoURL)
+	config.MongoDB = getEnv("MONGO_DB", "hivemind_loadgen")
+	config.WeaviateURL = getEnv("WEAVIATE_URL", "localhost:8080")
+
+	memManager, err := memory.NewHybridMemoryManager(ctx, config)
+	if err != nil {
+		return bench.Stats{}, err
+	}
+	defer memManager.Close(ctx)
+
+	agentID := "loadgen-" + uuid.New().String()[:8]
+	store := func(ctx context.Context) error {
+		return memManager.StoreMemory(ctx, &memory.Memory{
+			ID:         uuid.New().String(),
+			AgentID:    agentID,
+			Content:    "Memória gerada pelo loadgen",
+			Type:       memory.ShortTerm,
+			Importance: 0.5,
+			Timestamp:  time.Now(),
+			Tags:       []string{"loadgen"},
+		})
+	}
+
+	if scenario == "memory-store" {
+		return bench.Run(ctx, scenario, duration, concurrency, store), nil
+	}
+
+	// Popula memórias antes de medir as buscas
+	for i := 0; i < 100; i++ {
+		if err := store(ctx); err != nil {
+			return bench.Stats{}, fmt.Errorf("erro ao popular memórias: %v", err)
+		}
+	}
+
+	return bench.Run(ctx, scenario, duration, concurrency, func(ctx context.Context) error {
+		_, err := memManager.SearchMemories(ctx, agentID, []string{"loadgen"})
+		return err
+	}), nil
+}
+
+// brokerWorker mantém um canal e uma fila exclusiva para medir o ida-e-volta no RabbitMQ
+type brokerWorker struct {
+	channel  *amqp.Channel
+	queue    string
+	messages <-chan amqp.Delivery
+}
+
+// runBroker mede a latência de publicação + consumo no RabbitMQ
+func runBroker(ctx context.Context, duration time.Duration, concurrency int) (bench.Stats, error) {
+	conn, err := communication.DialRabbitMQ(communication.RabbitMQConfigFromEnv())
+	if err != nil {
+		return bench.Stats{}, err
+	}
+	defer conn.Close()
+
+	pool := make(chan *brokerWorker, concurrency)
+	for i := 0; i < concurrency; i++ {
+		channel, err := conn.Channel()
+		if err != nil {
+			return bench.Stats{}, fmt.Errorf("erro ao criar canal: %v", err)
+		}
+		defer channel.Close()
+
+		queue, err := channel.QueueDeclare("", false, true, true, false, nil)
+		if err != nil {
+			return bench.Stats{}, fmt.Errorf("erro ao declarar fila: %v", err)
+		}
+		messages, err := channel.Consume(queue.Name, "", true, true, false, false, nil)
+		if err != nil {
+			return bench.Stats{}, fmt.Errorf("erro ao consumir fila: %v", err)
+		}
+		pool <- &brokerWorker{channel: channel, queue: queue.Name, messages: messages}
+	}
+
+	body := []byte(`{"id":"loadgen","description":"mensagem de teste de carga"}`)
+	return bench.Run(ctx, "broker", duration, concurrency, func(ctx context.Context) error {
+		worker := <-pool
+		defer func() { pool <- worker }()
+
+		if err := worker.channel.Publish("", worker.queue, false, false, amqp.Publishing{
+			ContentType: "application/json",
+			Body:        body,
+		}); err != nil {
+			return err
+		}
+
+		select {
+		case <-worker.messages:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}), nil
+}
+
+// runWorkflow mede o tempo de conclusão de um workflow em modo dry-run com o LLM simulado
+func runWorkflow(ctx context.Context, duration time.Duration, concurrency int) (bench.Stats, error) {
+	provider := llm.NewFakeLLMProvider()
+	if err := provider.SetDefault("Resposta simulada para: {{.Prompt}}"); err != nil {
+		return bench.Stats{}, err
+	}
+
+	return bench.Run(ctx, "workflow", duration, concurrency, func(ctx context.Context) error {
+		crew := agents.NewMarketingCrew(nil)
+		for _, role := range []string{"analyst", "strategist", "creator"} {
+			agent := agents.NewCognitiveAgent(role, role, role, 1, "gpt-4", role, "loadgen", nil)
+			agent.SetLLM(provider)
+			crew.AddAgent(agent)
+		}
+
+		project := &agents.MarketingProject{Name: "Loadgen", Objective: "Medir o tempo do workflow"}
+		for i, role := range []string{"analyst", "strategist", "creator"} {
+			project.AddTask(agents.TaskConfig{
+				ID:         fmt.Sprintf("task-%d", i+1),
+				Name:       "Tarefa " + role,
+				AssignedTo: role,
+				Status:     "pending",
+			})
+		}
+
+		session := simulation.NewSession(provider)
+		_, err := crew.ExecuteWorkflowContext(simulation.WithSession(ctx, session), project)
+		return err
+	}), nil
+}
+
+// getEnv retorna o valor da variável de ambiente ou o valor padrão
+func getEnv(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
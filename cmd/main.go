@@ -2,19 +2,19 @@ package main
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"log"
 	"os"
-	"os/signal"
-	"sync"
-	"syscall"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/joho/godotenv"
-	"github.com/streadway/amqp"
 
 	"github.com/suissa/HiveMind/agents"
+	"github.com/suissa/HiveMind/agents/communication"
+	"github.com/suissa/HiveMind/agents/i18n"
+	"github.com/suissa/HiveMind/agents/shutdown"
+	"github.com/suissa/HiveMind/api"
 	"github.com/suissa/HiveMind/config"
 	"github.com/suissa/HiveMind/orchestrator"
 )
@@ -52,31 +52,60 @@ func main() {
 		log.Printf("⚠️ Arquivo .env não encontrado, usando valores padrão")
 	}
 
+	// Idioma das mensagens exibidas ao usuário (eventos, API e relatórios)
+	if err := i18n.LoadFromEnv(); err != nil {
+		log.Printf("⚠️ %v, usando %s", err, i18n.Default())
+	}
+
 	// Configuração do RabbitMQ
 	rabbitConfig := config.NewRabbitMQConfig()
 	conn, err := config.ConnectRabbitMQ(rabbitConfig)
 	if err != nil {
 		log.Fatalf("❌ Erro ao conectar ao RabbitMQ: %v", err)
 	}
-	defer conn.Close()
 
 	// Criando o contexto principal
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
+	// Coordenador de encerramento: os clientes são fechados na ordem inversa ao registro
+	stopper := shutdown.New(getDuration("SHUTDOWN_TIMEOUT", shutdown.DefaultTimeout))
+	stopper.SetAbort(cancel)
+	stopper.OnClose("rabbitmq", func(ctx context.Context) error {
+		return conn.Close()
+	})
+
 	// Criando e iniciando o LLMRouter
 	router, err := orchestrator.NewLLMRouter(conn)
 	if err != nil {
 		log.Fatalf("❌ Erro ao criar LLMRouter: %v", err)
 	}
-	defer router.Close()
+	router.SetShutdown(stopper)
+	signer, verifier := signingFromEnv()
+	router.SetSigning(signer, verifier)
+
+	// API de gerenciamento (HIVEMIND_API_ADDR): recebe tarefas e expõe /metrics; sem gerenciador
+	// de memória, as rotas de memória respondem 501
+	if apiConfig := api.ConfigFromEnv(); apiConfig.Enabled() {
+		server, err := apiConfig.NewServer(nil, router)
+		if err != nil {
+			log.Fatalf("❌ %v", err)
+		}
+		if err := server.Start(); err != nil {
+			log.Fatalf("❌ %v", err)
+		}
+		stopper.OnStopIntake("management_api", server.Shutdown)
+	}
+	stopper.OnStopIntake("llm_router", router.StopIntake)
+	stopper.OnClose("llm_router", func(ctx context.Context) error {
+		return router.Close()
+	})
 
 	if err := router.Start(ctx); err != nil {
 		log.Fatalf("❌ Erro ao iniciar LLMRouter: %v", err)
 	}
 
 	// Criando e iniciando os agents
-	var wg sync.WaitGroup
 	for i, agentType := range agentTypes {
 		// Criando múltiplas instâncias de cada tipo de agent
 		for j := 1; j <= 2; j++ { // 2 agents de cada tipo = 10 agents no total
@@ -86,16 +115,17 @@ func main() {
 				log.Printf("❌ Erro ao criar agent %s: %v", agentID, err)
 				continue
 			}
-			defer agent.Close()
-
-			wg.Add(1)
-			go func(a *agents.LLMAgent, typ string, desc string) {
-				defer wg.Done()
-				log.Printf("🤖 Iniciando %s (Tipo: %s - %s)", a.ID, typ, desc)
-				if err := a.Start(ctx); err != nil {
-					log.Printf("❌ Erro ao iniciar agent %s: %v", a.ID, err)
-				}
-			}(agent, agentType.Type, agentType.Description)
+			agent.SetShutdown(stopper)
+			agent.SetSigning(signer, verifier)
+			stopper.OnStopIntake(agentID, agent.StopIntake)
+			stopper.OnClose(agentID, func(ctx context.Context) error {
+				return agent.Close()
+			})
+
+			log.Printf("🤖 Iniciando %s (Tipo: %s - %s)", agent.ID, agentType.Type, agentType.Description)
+			if err := agent.Start(ctx); err != nil {
+				log.Printf("❌ Erro ao iniciar agent %s: %v", agent.ID, err)
+			}
 		}
 	}
 
@@ -111,21 +141,7 @@ func main() {
 	}
 
 	// Publicando a tarefa
-	taskBytes, err := json.Marshal(task)
-	if err != nil {
-		log.Fatalf("❌ Erro ao serializar tarefa: %v", err)
-	}
-
-	err = router.channel.Publish(
-		"",                // exchange
-		router.inputQueue, // routing key
-		false,             // mandatory
-		false,             // immediate
-		amqp.Publishing{
-			ContentType: "application/json",
-			Body:        taskBytes,
-		})
-	if err != nil {
+	if err := router.SubmitTask(ctx, task); err != nil {
 		log.Fatalf("❌ Erro ao publicar tarefa: %v", err)
 	}
 
@@ -133,13 +149,33 @@ func main() {
 		len(agentTypes), len(agentTypes)*2)
 	log.Printf("📤 Tarefa enviada: %s", task.Description)
 
-	// Aguardando sinais de interrupção
-	sigChan := make(chan os.Signal, 1)
-	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
-	<-sigChan
+	// Aguardando sinais de interrupção e encerrando graciosamente
+	if err := stopper.WaitForSignal(context.Background()); err != nil {
+		log.Printf("⚠️ %v", err)
+	}
+}
 
-	// Encerrando graciosamente
-	log.Println("👋 Encerrando o sistema...")
-	cancel()
-	wg.Wait()
+// getDuration lê uma duração de uma variável de ambiente (ex.: "45s")
+// signingFromEnv lê o segredo HMAC compartilhado pelo router e pelos agents
+// (HIVEMIND_SIGNING_SECRET); vazio desativa a assinatura das filas
+func signingFromEnv() (communication.Signer, communication.Verifier) {
+	secret := os.Getenv("HIVEMIND_SIGNING_SECRET")
+	if secret == "" {
+		return nil, nil
+	}
+	keyID := os.Getenv("HIVEMIND_SIGNING_KEY_ID")
+	if keyID == "" {
+		keyID = "hivemind"
+	}
+	keys := communication.NewKeyRing()
+	keys.AddHMACKey(keyID, []byte(secret))
+	return communication.NewHMACSigner(keyID, []byte(secret)), keys
+}
+
+func getDuration(key string, fallback time.Duration) time.Duration {
+	value, err := time.ParseDuration(os.Getenv(key))
+	if err != nil || value <= 0 {
+		return fallback
+	}
+	return value
 }
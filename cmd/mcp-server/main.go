@@ -0,0 +1,111 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+
+	"github.com/joho/godotenv"
+
+	"github.com/suissa/HiveMind/agents"
+	"github.com/suissa/HiveMind/agents/mcp"
+	"github.com/suissa/HiveMind/tools"
+)
+
+// Servidor MCP com as ferramentas do HiveMind (predição de tendências, detecção de fraudes,
+// preenchimento de formulários e busca). Sem -http, atende por stdio; os logs vão para stderr.
+func main() {
+	configFile := flag.String("config", "", "agents.yaml com a matriz tool_permissions (sem ela nenhuma ferramenta é liberada)")
+	httpAddr := flag.String("http", "", "endereço HTTP (ex.: :8090); vazio atende por stdio")
+	callerID := flag.String("id", "mcp-client", "identidade do cliente na matriz de permissões")
+	callerRole := flag.String("role", "assistant", "papel do cliente na matriz de permissões")
+	token := flag.String("token", os.Getenv("MCP_SERVER_TOKEN"), "token Bearer exigido no HTTP")
+	flag.Parse()
+
+	if *httpAddr != "" && *token == "" {
+		log.Fatalf("❌ O modo HTTP exige um token: use -token ou MCP_SERVER_TOKEN")
+	}
+
+	if err := godotenv.Load(); err != nil {
+		log.Printf("⚠️ Arquivo .env não encontrado, usando valores padrão")
+	}
+
+	permissions := &agents.ToolPermissions{}
+	if *configFile == "" {
+		log.Printf("⚠️ Sem -config, nenhuma ferramenta é liberada aos clientes MCP")
+	} else {
+		config, err := agents.LoadAgentsConfig(*configFile)
+		if err != nil {
+			log.Fatalf("❌ Erro ao carregar %s: %v", *configFile, err)
+		}
+		permissions = &config.ToolPermissions
+	}
+
+	registry := agents.NewToolRegistry(permissions, agents.NewEventEmitter())
+	registerTools(registry)
+
+	caller := mcp.Caller{ID: *callerID, Role: *callerRole}
+	config := mcp.ServerConfig{Name: "hivemind", Caller: caller}
+	if *token != "" {
+		config.Tokens = map[string]mcp.Caller{*token: caller}
+	}
+	server := agents.NewMCPServer(registry, config)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	if *httpAddr == "" {
+		log.Printf("🔌 Servidor MCP atendendo por stdio com %d ferramentas", len(registry.List()))
+		if err := server.ServeStdio(ctx, os.Stdin, os.Stdout); err != nil {
+			log.Fatalf("❌ Erro no servidor MCP: %v", err)
+		}
+		return
+	}
+
+	httpServer := &http.Server{Addr: *httpAddr, Handler: server}
+	go func() {
+		<-ctx.Done()
+		httpServer.Shutdown(context.Background())
+	}()
+	log.Printf("🔌 Servidor MCP atendendo em %s com %d ferramentas", *httpAddr, len(registry.List()))
+	if err := httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		log.Fatalf("❌ Erro no servidor MCP: %v", err)
+	}
+}
+
+// registerTools registra as ferramentas disponíveis; as que não puderem ser criadas (ex.: sem
+// chave de API) ficam de fora
+func registerTools(registry *agents.ToolRegistry) {
+	register := func(tool *tools.AgentTool) {
+		if err := registry.Register(tool); err != nil {
+			log.Fatalf("❌ Erro ao registrar %s: %v", tool.Name(), err)
+		}
+	}
+
+	if predictor, err := tools.NewTrendPredictor(); err != nil {
+		log.Printf("⚠️ Predição de tendências indisponível: %v", err)
+	} else {
+		register(tools.NewTrendTool(predictor))
+	}
+	if detector, err := tools.NewFraudDetector(); err != nil {
+		log.Printf("⚠️ Detecção de fraudes indisponível: %v", err)
+	} else {
+		register(tools.NewFraudTool(detector))
+	}
+	if filler, err := tools.NewFormFiller(); err != nil {
+		log.Printf("⚠️ Preenchimento de formulários indisponível: %v", err)
+	} else {
+		register(tools.NewFormTool(filler))
+	}
+
+	if tavily, err := tools.NewTavilyTool(); err == nil {
+		register(tools.NewSearchTool(tavily))
+	} else if exa, err := tools.NewExaTool(); err == nil {
+		register(tools.NewSearchTool(exa))
+	} else {
+		log.Printf("⚠️ Busca indisponível: configure TAVILY_API_TOKEN ou EXA_API_TOKEN")
+	}
+}
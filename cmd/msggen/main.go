@@ -0,0 +1,29 @@
+// Command msggen gera os tipos Go das mensagens a partir dos JSON Schemas. É chamado pelo
+// go generate do pacote agents/messages:
+//
+//	go generate ./agents/messages
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+
+	"github.com/suissa/HiveMind/agents/messages/codegen"
+)
+
+func main() {
+	schemas := flag.String("schemas", "schemas", "diretório dos arquivos *.schema.json")
+	out := flag.String("out", "messages_gen.go", "arquivo Go gerado")
+	pkg := flag.String("package", "messages", "pacote do arquivo gerado")
+	flag.Parse()
+
+	source, err := codegen.Generate(os.DirFS(*schemas), *pkg)
+	if err != nil {
+		log.Fatalf("❌ Erro ao gerar as mensagens: %v", err)
+	}
+	if err := os.WriteFile(*out, source, 0o644); err != nil {
+		log.Fatalf("❌ Erro ao gravar %s: %v", *out, err)
+	}
+	log.Printf("✅ %s gerado", *out)
+}
@@ -1,6 +1,6 @@
 package main
 
-import "groq-consumer/publisher"
+import "github.com/suissa/HiveMind/publisher"
 
 func main() {
 	publisher.PublishChapterRequest()
@@ -3,7 +3,8 @@ package main
 import (
 	"log"
 
-	"groq-consumer/publishers"
+	"github.com/suissa/HiveMind/agents/messages"
+	"github.com/suissa/HiveMind/publishers"
 )
 
 func main() {
@@ -18,12 +19,12 @@ func main() {
 	}
 
 	// Exemplo de envio de mensagem para criar um capítulo
-	chapterMessage := map[string]interface{}{
-		"tema": "História da Computação",
-		"user_info": map[string]interface{}{
-			"level":      "Intermediário",
-			"profession": "teacher",
-			"age":        30,
+	chapterMessage := messages.ChapterCreationMessage{
+		Tema: "História da Computação",
+		UserInfo: messages.UserInfo{
+			Level:      "Intermediário",
+			Profession: "teacher",
+			Age:        30,
 		},
 	}
 
@@ -0,0 +1,42 @@
+# Políticas de execução do HiveMind avaliadas pelo Open Policy Agent.
+# Carregue com: opa run --server config/policies
+package hivemind
+
+import rego.v1
+
+# ---------------------------------------------------------------------------
+# Tarefas (consulta hivemind/tasks)
+# ---------------------------------------------------------------------------
+
+# Limite de gasto por tarefa (parameters.budget)
+max_task_budget := 1000
+
+# Classificações de dados que não podem ser processadas
+restricted_classifications := {"restricted", "secret"}
+
+tasks.deny contains msg if {
+	input.parameters.budget > max_task_budget
+	msg := sprintf("orçamento %v acima do limite de %v", [input.parameters.budget, max_task_budget])
+}
+
+tasks.deny contains msg if {
+	input.parameters.classification in restricted_classifications
+	msg := sprintf("classificação de dados não permitida: %v", [input.parameters.classification])
+}
+
+tasks.allow if count(tasks.deny) == 0
+
+# ---------------------------------------------------------------------------
+# Ferramentas (consulta hivemind/tools)
+# ---------------------------------------------------------------------------
+
+# Alvos permitidos para ferramentas de varredura de rede
+allowed_scan_targets := {"127.0.0.1", "localhost", "scanme.nmap.org"}
+
+tools.deny contains msg if {
+	input.tool == "nmap"
+	not input.parameters.target in allowed_scan_targets
+	msg := sprintf("alvo de varredura não permitido: %v", [input.parameters.target])
+}
+
+tools.allow if count(tools.deny) == 0
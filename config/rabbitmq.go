@@ -0,0 +1,18 @@
+package config
+
+import (
+	"github.com/streadway/amqp"
+
+	"github.com/suissa/HiveMind/agents/communication"
+)
+
+// NewRabbitMQConfig carrega a configuração do RabbitMQ (host, credenciais, TLS e SASL)
+// das variáveis de ambiente RABBITMQ_*
+func NewRabbitMQConfig() *communication.ConnectionConfig {
+	return communication.RabbitMQConfigFromEnv()
+}
+
+// ConnectRabbitMQ abre uma conexão com o RabbitMQ usando a configuração informada
+func ConnectRabbitMQ(config *communication.ConnectionConfig) (*amqp.Connection, error) {
+	return communication.DialRabbitMQ(config)
+}
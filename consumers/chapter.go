@@ -0,0 +1,101 @@
+package consumers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/suissa/HiveMind/agents"
+	"github.com/suissa/HiveMind/agents/llm"
+)
+
+// ChapterTargetScore é a pontuação que conclui a criação de um capítulo
+const ChapterTargetScore = 1000
+
+// chapterOrchestrator delega a geração de quizzes e desafios aos agentes cognitivos
+// e acumula a pontuação do conteúdo aprovado até a meta do capítulo
+type chapterOrchestrator struct {
+	quizAgent      *agents.CognitiveAgent
+	challengeAgent *agents.CognitiveAgent
+	TotalScore     int
+	mu             sync.Mutex
+}
+
+// newChapterOrchestrator cria o orquestrador do fluxo de capítulos
+func newChapterOrchestrator() *chapterOrchestrator {
+	return &chapterOrchestrator{}
+}
+
+// newQuizAgent cria o agente responsável pelos quizzes
+func newQuizAgent() *agents.CognitiveAgent {
+	agent := agents.NewCognitiveAgent("quiz", "Quiz Agent", "Gera quizzes sobre o tema do capítulo",
+		1, "llama-3.3-70b-versatile", "quiz", "Criar perguntas e respostas relacionadas ao tema", nil)
+	agent.SetBackstory("Sou um educador especializado em criar quizzes claros e objetivos.")
+	return agent
+}
+
+// newChallengeAgent cria o agente responsável pelos desafios práticos
+func newChallengeAgent() *agents.CognitiveAgent {
+	agent := agents.NewCognitiveAgent("challenge", "Challenge Agent", "Gera desafios práticos sobre o tema do capítulo",
+		1, "llama-3.3-70b-versatile", "challenge", "Criar desafios interativos que testem o conhecimento", nil)
+	agent.SetBackstory("Sou um instrutor especializado em desafios práticos e progressivos.")
+	return agent
+}
+
+// AssignCognitiveAgents define os agentes de quiz e desafio
+func (o *chapterOrchestrator) AssignCognitiveAgents(quizAgent, challengeAgent *agents.CognitiveAgent) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.quizAgent = quizAgent
+	o.challengeAgent = challengeAgent
+}
+
+// SetLLM define o provedor de LLM dos agentes
+func (o *chapterOrchestrator) SetLLM(provider llm.Provider) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	for _, agent := range []*agents.CognitiveAgent{o.quizAgent, o.challengeAgent} {
+		if agent != nil {
+			agent.SetLLM(provider)
+		}
+	}
+}
+
+// DelegateTask executa a tarefa com o agente informado
+func (o *chapterOrchestrator) DelegateTask(agent *agents.CognitiveAgent, task *agents.Task) (string, error) {
+	if agent == nil {
+		return "", fmt.Errorf("nenhum agente atribuído à tarefa")
+	}
+	return agent.Run(context.Background(), task)
+}
+
+// EvaluateContent aprova o conteúdo gerado e soma a pontuação ao capítulo
+func (o *chapterOrchestrator) EvaluateContent(content string, score int) (bool, error) {
+	if strings.TrimSpace(content) == "" {
+		return false, fmt.Errorf("conteúdo vazio")
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.TotalScore += score
+	return true, nil
+}
+
+// IsWorkflowComplete indica se o capítulo atingiu a pontuação alvo
+func (o *chapterOrchestrator) IsWorkflowComplete() bool {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.TotalScore >= ChapterTargetScore
+}
+
+// GetProgress retorna o progresso do capítulo em porcentagem
+func (o *chapterOrchestrator) GetProgress() float64 {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	progress := float64(o.TotalScore) / ChapterTargetScore * 100
+	if progress > 100 {
+		progress = 100
+	}
+	return progress
+}
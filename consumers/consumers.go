@@ -1,16 +1,19 @@
 package consumers
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
 	"log"
 	"math/rand"
 	"sync"
 	"time"
 
-	"groq-consumer/agents"
-
 	"github.com/streadway/amqp"
+
+	"github.com/suissa/HiveMind/agents"
+	"github.com/suissa/HiveMind/agents/communication"
+	"github.com/suissa/HiveMind/agents/llm"
+	"github.com/suissa/HiveMind/agents/messages"
 )
 
 const (
@@ -19,9 +22,9 @@ const (
 )
 
 var (
-	orchestrator   = agents.NewOrchestratorAgent()
-	quizAgent      = agents.NewQuizAgent()
-	challengeAgent = agents.NewChallengeAgent()
+	orchestrator   = newChapterOrchestrator()
+	quizAgent      = newQuizAgent()
+	challengeAgent = newChallengeAgent()
 )
 
 func init() {
@@ -29,33 +32,32 @@ func init() {
 	orchestrator.AssignCognitiveAgents(quizAgent, challengeAgent)
 }
 
-type UserInfo struct {
-	Level      string `json:"level"`
-	Profession string `json:"profession"`
-	Age        int    `json:"age"`
-}
-
-type ChapterCreationMessage struct {
-	Tema     string   `json:"tema"`
-	UserInfo UserInfo `json:"user_info"`
+// SetLLM define o provedor de LLM usado na geração de quizzes e desafios
+func SetLLM(provider llm.Provider) {
+	orchestrator.SetLLM(provider)
 }
 
-type TaskMessage struct {
-	Task string `json:"task"`
-	Tema string `json:"tema"`
-}
+// Assinatura das mensagens do fluxo de capítulos (SetSigning)
+var (
+	signer   communication.Signer
+	verifier communication.Verifier
+)
 
-type ApprovalMessage struct {
-	Type    string `json:"type"`
-	Prompt  string `json:"prompt"`
-	Content string `json:"content"`
-	Score   int    `json:"score"`
+// SetSigning assina as mensagens publicadas (tarefas e aprovações) e descarta as consumidas
+// sem assinatura válida. Deve ser chamado antes de StartConsumers.
+func SetSigning(s communication.Signer, v communication.Verifier) {
+	signer, verifier = s, v
 }
 
-type FinishedMessage struct {
-	Status     string `json:"status"`
-	TotalScore int    `json:"total_score"`
-}
+// Contratos das mensagens do fluxo de capítulos, gerados dos JSON Schemas em
+// agents/messages/schemas
+type (
+	UserInfo               = messages.UserInfo
+	ChapterCreationMessage = messages.ChapterCreationMessage
+	TaskMessage            = messages.ChapterTaskMessage
+	ApprovalMessage        = messages.ApprovalMessage
+	FinishedMessage        = messages.FinishedMessage
+)
 
 func handleError(err error, msg string) {
 	if err != nil {
@@ -63,28 +65,30 @@ func handleError(err error, msg string) {
 	}
 }
 
-func publishEvent(queueName string, message interface{}) {
-	conn, err := amqp.Dial(fmt.Sprintf("amqp://guest:guest@%s:%d/", RABBITMQ_HOST, RABBITMQ_PORT))
-	handleError(err, "Falha ao conectar ao RabbitMQ")
-	defer conn.Close()
+// rabbitPool é a conexão compartilhada pelas publicações do pacote
+var rabbitPool = communication.SharedRabbitMQPool(&communication.ConnectionConfig{
+	Host:     RABBITMQ_HOST,
+	Port:     RABBITMQ_PORT,
+	Username: "guest",
+	Password: "guest",
+})
 
-	ch, err := conn.Channel()
-	handleError(err, "Falha ao abrir o canal")
-	defer ch.Close()
+func publishEvent(queueName string, message messages.Message) {
+	body, err := messages.Encode(message)
+	if err != nil {
+		log.Printf("❌ Mensagem para %s fora do contrato: %v", queueName, err)
+		return
+	}
 
-	body, err := json.Marshal(message)
-	handleError(err, "Falha ao converter mensagem para JSON")
-
-	err = ch.Publish(
-		"",        // exchange
-		queueName, // routing key
-		false,     // mandatory
-		false,     // immediate
-		amqp.Publishing{
-			ContentType:  "application/json",
-			Body:         body,
-			DeliveryMode: 2, // mensagem persistente
-		})
+	msg := amqp.Publishing{
+		ContentType:  "application/json",
+		Body:         body,
+		DeliveryMode: 2, // mensagem persistente
+	}
+	if signer != nil {
+		handleError(communication.SignAMQP(signer, queueName, &msg), "Falha ao assinar mensagem")
+	}
+	err = rabbitPool.Publish(context.Background(), "", queueName, msg)
 	handleError(err, "Falha ao publicar mensagem")
 	log.Printf("📨 Mensagem enviada para %s: %s", queueName, string(body))
 }
@@ -168,8 +172,10 @@ func generateChallenge(tema string) {
 
 func processChapterCreation(body []byte) {
 	var message ChapterCreationMessage
-	err := json.Unmarshal(body, &message)
-	handleError(err, "Erro ao decodificar mensagem")
+	if err := messages.Decode(body, &message); err != nil {
+		log.Printf("❌ Mensagem inválida: %v", err)
+		return
+	}
 
 	log.Printf("\n🔔 Criando um novo Capítulo...")
 	startChapter(message.Tema, message.UserInfo)
@@ -177,8 +183,10 @@ func processChapterCreation(body []byte) {
 
 func processGenerateQuiz(body []byte) {
 	var message TaskMessage
-	err := json.Unmarshal(body, &message)
-	handleError(err, "Erro ao decodificar mensagem")
+	if err := messages.Decode(body, &message); err != nil {
+		log.Printf("❌ Mensagem inválida: %v", err)
+		return
+	}
 
 	log.Printf("\n🔔 Criando um novo Quiz sobre '%s'...", message.Tema)
 	generateQuiz(message.Tema)
@@ -186,8 +194,10 @@ func processGenerateQuiz(body []byte) {
 
 func processGenerateChallenge(body []byte) {
 	var message TaskMessage
-	err := json.Unmarshal(body, &message)
-	handleError(err, "Erro ao decodificar mensagem")
+	if err := messages.Decode(body, &message); err != nil {
+		log.Printf("❌ Mensagem inválida: %v", err)
+		return
+	}
 
 	log.Printf("\n🔔 Criando um novo Desafio sobre '%s'...", message.Tema)
 	generateChallenge(message.Tema)
@@ -195,8 +205,10 @@ func processGenerateChallenge(body []byte) {
 
 func processChapterApproval(body []byte) {
 	var message ApprovalMessage
-	err := json.Unmarshal(body, &message)
-	handleError(err, "Erro ao decodificar mensagem")
+	if err := messages.Decode(body, &message); err != nil {
+		log.Printf("❌ Mensagem inválida: %v", err)
+		return
+	}
 
 	log.Printf("\n🔔 Aprovando %s...", message.Type)
 
@@ -247,6 +259,13 @@ func startConsumer(queueName string, handler func([]byte)) {
 	log.Printf("📡 Aguardando mensagens na fila `%s`...", queueName)
 
 	for d := range msgs {
+		// Com auto-ack, a mensagem sem assinatura válida é descartada
+		if verifier != nil {
+			if err := communication.VerifyAMQP(verifier, &d, communication.DefaultSignatureMaxAge); err != nil {
+				log.Printf("🚫 Mensagem rejeitada em %s: %v", queueName, err)
+				continue
+			}
+		}
 		handler(d.Body)
 	}
 }
@@ -8,8 +8,8 @@ import (
 	"syscall"
 	"time"
 
-	"HiveMind/agents"
-	"HiveMind/agents/memory"
+	"github.com/suissa/HiveMind/agents"
+	"github.com/suissa/HiveMind/agents/memory"
 )
 
 func main() {
@@ -99,8 +99,14 @@ func main() {
 		log.Printf("Memorizado: %v", tarefa.conteudo["tipo"])
 	}
 
-	// Simula treinamento
+	// Treina o agente com exemplos rotulados (e com o feedback já armazenado)
+	agent.SetTrainingData([]agents.TrainingExample{
+		{ID: "pico", Input: "Em que horário ocorre o pico de consumo de energia?", Expected: "entre 18h e 21h"},
+		{ID: "temperatura", Input: "Como a temperatura ambiente afeta o consumo de energia?", Expected: "correlação positiva"},
+	})
 	config := agents.TrainingConfig{
+		MaxRounds:     3,
+		MinAccuracy:   0.8,
 		UseHistorical: true,
 		BatchSize:     5,
 		LearningRate:  0.001,
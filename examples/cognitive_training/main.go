@@ -2,11 +2,14 @@ package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
 	"log"
 	"time"
 
-	"HiveMind/agents"
+	"github.com/suissa/HiveMind/agents"
+	"github.com/suissa/HiveMind/agents/dataset"
+	"github.com/suissa/HiveMind/agents/memory"
 )
 
 func main() {
@@ -21,9 +24,23 @@ func main() {
 		UseHistorical:   true,
 	}
 
+	// Carrega o dataset de exemplos; a divisão treino/validação segue ValidationRatio
+	datasetPath := flag.String("dataset", "examples/cognitive_training/dataset.jsonl", "arquivo .jsonl ou .csv com os exemplos")
+	flag.Parse()
+	data, err := dataset.Load(*datasetPath)
+	if err != nil {
+		log.Fatalf("Erro ao carregar dataset: %v", err)
+	}
+	fmt.Printf("Dataset %s com %d exemplos\n", data, len(data.Examples))
+	config.Dataset = data
+
 	// Cria o gerenciador de treinamento
 	trainer := agents.NewAgentTrainer(config)
 
+	// Sem gerenciador de memória, o treinamento usa só o dataset e as métricas não são
+	// persistidas; passe um memory.HybridMemoryManager para usar o feedback armazenado
+	var memManager memory.MemoryManager
+
 	// Cria alguns agentes cognitivos para teste
 	agent1 := agents.NewCognitiveAgent(
 		"agent1",
@@ -33,6 +50,7 @@ func main() {
 		"gpt-4",
 		"researcher",
 		"Realizar pesquisas aprofundadas e análises de dados",
+		memManager,
 	)
 	agent1.SetBackstory("Sou um assistente de pesquisa com experiência em análise de dados e machine learning")
 
@@ -44,6 +62,7 @@ func main() {
 		"gpt-4",
 		"engineer",
 		"Desenvolver e otimizar código",
+		memManager,
 	)
 	agent2.SetBackstory("Sou um engenheiro de software com foco em arquitetura e boas práticas")
 
@@ -55,6 +74,7 @@ func main() {
 		"gpt-4",
 		"manager",
 		"Coordenar equipes e garantir entrega de projetos",
+		memManager,
 	)
 	agent3.SetBackstory("Sou um gerente de projetos com experiência em metodologias ágeis")
 
@@ -95,11 +115,12 @@ func main() {
 
 		if cogAgent != nil {
 			fmt.Printf("\nAgente: %s (%s)\n", cogAgent.Name, cogAgent.GetRole())
-			fmt.Printf("Objetivo: %s\n", cogAgent.GetGoal())
-			fmt.Printf("Rounds executados: %d/%d\n", metric.RoundsExecuted, cogAgent.GetMaxRounds())
+			fmt.Printf("Objetivo: %s\n", cogAgent.Goal)
+			fmt.Printf("Rounds executados: %d/%d\n", metric.RoundsExecuted, cogAgent.MaxRounds)
 			fmt.Printf("Tempo de treinamento: %v\n", metric.EndTime.Sub(metric.StartTime))
 			fmt.Printf("Acurácia: %.2f\n", metric.Accuracy)
 			fmt.Printf("Loss: %.2f\n", metric.Loss)
+			fmt.Printf("Dataset: %s\n", metric.DatasetVersion)
 
 			// Exibe estatísticas de performance
 			stats := cogAgent.GetPerformanceStats()
@@ -9,7 +9,7 @@ import (
 	"syscall"
 	"time"
 
-	"HiveMind/agents/communication"
+	"github.com/suissa/HiveMind/agents/communication"
 )
 
 func main() {
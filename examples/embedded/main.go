@@ -0,0 +1,33 @@
+package main
+
+import (
+	"context"
+	"log"
+
+	hivemind "github.com/suissa/HiveMind"
+	"github.com/suissa/HiveMind/agents/llm"
+)
+
+func main() {
+	ctx := context.Background()
+
+	rt := hivemind.New(
+		hivemind.WithLLM("fake", llm.NewFakeLLMProvider()),
+		hivemind.WithBus(hivemind.BusConfigFromEnv()),
+	)
+	if err := rt.Start(ctx); err != nil {
+		log.Fatalf("❌ Erro ao iniciar o runtime: %v", err)
+	}
+	defer rt.Close(ctx)
+
+	rt.Events().OnAny(func(event hivemind.Event) { log.Printf("📢 %s", event.Message) })
+
+	analyst := hivemind.NewCognitiveAgent("analyst", "Analyst", "Analista de mercado", 1, "llama-3.3-70b-versatile", "analyst", "Analisar o mercado", rt.Memory())
+	if err := rt.RegisterAgent(analyst); err != nil {
+		log.Fatalf("❌ Erro ao registrar agente: %v", err)
+	}
+
+	if err := rt.SubmitTask(ctx, hivemind.TaskRequest{ID: "task-1", Description: "Analisar o mercado de IA"}); err != nil {
+		log.Fatalf("❌ Erro ao publicar tarefa: %v", err)
+	}
+}
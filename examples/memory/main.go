@@ -8,8 +8,8 @@ import (
 	"syscall"
 	"time"
 
-	"HiveMind/agents"
-	"HiveMind/agents/memory"
+	"github.com/suissa/HiveMind/agents"
+	"github.com/suissa/HiveMind/agents/memory"
 )
 
 func main() {
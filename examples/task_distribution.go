@@ -5,10 +5,10 @@ import (
 	"log"
 	"time"
 
-	"HiveMind/agents"
-	"HiveMind/agents/telemetry"
-
 	"github.com/google/uuid"
+
+	"github.com/suissa/HiveMind/agents"
+	"github.com/suissa/HiveMind/agents/telemetry"
 )
 
 func main() {
@@ -0,0 +1,943 @@
+// Package hivemind é a API pública e estável do HiveMind. Aplicações devem importar
+// apenas este pacote (github.com/suissa/HiveMind); os pacotes internos em agents/...
+// podem mudar entre versões, enquanto os nomes reexportados aqui são mantidos.
+package hivemind
+
+import (
+	"context"
+	"time"
+
+	"github.com/suissa/HiveMind/agents"
+	"github.com/suissa/HiveMind/agents/approval"
+	"github.com/suissa/HiveMind/agents/batch"
+	"github.com/suissa/HiveMind/agents/blackboard"
+	"github.com/suissa/HiveMind/agents/blob"
+	"github.com/suissa/HiveMind/agents/brandvoice"
+	"github.com/suissa/HiveMind/agents/calendar"
+	"github.com/suissa/HiveMind/agents/chaos"
+	"github.com/suissa/HiveMind/agents/competitor"
+	"github.com/suissa/HiveMind/agents/consensus"
+	"github.com/suissa/HiveMind/agents/contractnet"
+	"github.com/suissa/HiveMind/agents/control"
+	"github.com/suissa/HiveMind/agents/cron"
+	"github.com/suissa/HiveMind/agents/dataset"
+	"github.com/suissa/HiveMind/agents/debate"
+	"github.com/suissa/HiveMind/agents/discovery"
+	"github.com/suissa/HiveMind/agents/docgen"
+	"github.com/suissa/HiveMind/agents/errs"
+	"github.com/suissa/HiveMind/agents/fairshare"
+	"github.com/suissa/HiveMind/agents/groupchat"
+	"github.com/suissa/HiveMind/agents/importance"
+	"github.com/suissa/HiveMind/agents/inbox"
+	"github.com/suissa/HiveMind/agents/ingest"
+	"github.com/suissa/HiveMind/agents/interop"
+	"github.com/suissa/HiveMind/agents/knowledge"
+	"github.com/suissa/HiveMind/agents/llm"
+	"github.com/suissa/HiveMind/agents/maintenance"
+	"github.com/suissa/HiveMind/agents/mcp"
+	"github.com/suissa/HiveMind/agents/memory"
+	"github.com/suissa/HiveMind/agents/moderation"
+	"github.com/suissa/HiveMind/agents/outbox"
+	"github.com/suissa/HiveMind/agents/persona"
+	"github.com/suissa/HiveMind/agents/pii"
+	"github.com/suissa/HiveMind/agents/presence"
+	"github.com/suissa/HiveMind/agents/progress"
+	"github.com/suissa/HiveMind/agents/recap"
+	"github.com/suissa/HiveMind/agents/retrieval"
+	"github.com/suissa/HiveMind/agents/rollout"
+	"github.com/suissa/HiveMind/agents/sheetbatch"
+	"github.com/suissa/HiveMind/agents/sinks"
+	"github.com/suissa/HiveMind/agents/skills"
+	"github.com/suissa/HiveMind/agents/sla"
+	"github.com/suissa/HiveMind/agents/stealing"
+	"github.com/suissa/HiveMind/agents/templates"
+	"github.com/suissa/HiveMind/agents/timing"
+	"github.com/suissa/HiveMind/agents/validation"
+	"github.com/suissa/HiveMind/agents/webhook"
+)
+
+// Version é a versão da API pública
+const Version = "0.1.0"
+
+// Agentes, tarefas e equipes
+type (
+	Agent            = agents.Agent
+	CognitiveAgent   = agents.CognitiveAgent
+	Task             = agents.Task
+	TaskConfig       = agents.TaskConfig
+	Tool             = agents.Tool
+	Hooks            = agents.Hooks
+	HookFuncs        = agents.HookFuncs
+	NopHooks         = agents.NopHooks
+	TrainingConfig   = agents.TrainingConfig
+	TrainingMetrics  = agents.TrainingMetrics
+	TrainingExample  = agents.TrainingExample
+	Dataset          = dataset.Dataset
+	FineTuneConfig   = agents.FineTuneConfig
+	Outcome          = agents.Outcome
+	Contribution     = agents.Contribution
+	AgentSnapshot    = agents.Snapshot
+	Divergence       = agents.Divergence
+	Rollout          = agents.Rollout
+	RolloutConfig    = rollout.Config
+	Grader           = agents.Grader
+	TaskHandler      = agents.TaskHandler
+	TaskMiddleware   = agents.TaskMiddleware
+	MarketingCrew    = agents.MarketingCrew
+	MarketingProject = agents.MarketingProject
+	WorkflowResults  = agents.WorkflowResults
+	TrainingCrew     = agents.TrainingCrew
+	TrainingProject  = agents.TrainingProject
+	TrainingResults  = agents.TrainingResults
+)
+
+// Eventos
+type (
+	Event         = agents.Event
+	EventType     = agents.EventType
+	EventListener = agents.EventListener
+	EventEmitter  = agents.EventEmitter
+)
+
+// Moderação de conteúdo
+type (
+	Moderator          = moderation.Moderator
+	ModerationConfig   = moderation.Config
+	ModerationPipeline = moderation.Pipeline
+)
+
+// Aprovação humana das tarefas
+type (
+	ApprovalGate     = approval.Gate
+	ApprovalRequest  = approval.Request
+	ApprovalDecision = approval.Decision
+)
+
+// Dados pessoais
+type (
+	Anonymizer  = pii.Anonymizer
+	PIIConfig   = pii.Config
+	PIIDetector = pii.Detector
+)
+
+// Importância das memórias
+type (
+	ImportanceScorer = importance.Scorer
+	ImportanceScore  = importance.Score
+)
+
+// Memória
+type (
+	Memory         = memory.Memory
+	MemoryType     = memory.MemoryType
+	MemoryConfig   = memory.MemoryConfig
+	MemoryManager  = memory.MemoryManager
+	Scratchpad     = memory.Scratchpad
+	Embedder       = memory.Embedder
+	EmbedderFunc   = memory.EmbedderFunc
+	EmbeddingCache = memory.EmbeddingCache
+	ClassAliases   = memory.ClassAliases
+	ReindexOptions = memory.ReindexOptions
+	ReindexReport  = memory.ReindexReport
+
+	SimilarityOptions  = memory.SimilarityOptions
+	SimilaritySearcher = memory.SimilaritySearcher
+)
+
+// Resumo dos workflows concluídos
+type (
+	RecapSummarizer = recap.Summarizer
+	RecapSummary    = recap.Summary
+	RecapTranscript = recap.Transcript
+	RecapWriter     = recap.Writer
+)
+
+// Ranking das memórias recuperadas
+type (
+	RetrievalWeights = retrieval.Weights
+	RankedMemory     = retrieval.Scored
+)
+
+// Manutenção da memória
+type (
+	MaintenanceConfig    = maintenance.Config
+	MaintenanceWindow    = maintenance.Window
+	MaintenanceScheduler = maintenance.Scheduler
+)
+
+// Outbox dos eventos gravados junto com a memória
+type (
+	OutboxEvent     = outbox.Event
+	OutboxConfig    = outbox.Config
+	OutboxRelay     = outbox.Relay
+	OutboxPublisher = outbox.Publisher
+)
+
+// SLA dos workflows e das tarefas
+type (
+	SLAConfig   = sla.Config
+	SLAAlert    = sla.Alert
+	SLANotifier = sla.Notifier
+	SLAMonitor  = sla.Monitor
+
+	SLAWebhookNotifier = sla.WebhookNotifier
+)
+
+// Execuções periódicas das equipes
+type (
+	CronConfig    = cron.Config
+	CronJob       = cron.Job
+	CronEntry     = cron.Entry
+	CronOverlap   = cron.Overlap
+	CronScheduler = cron.Scheduler
+)
+
+// Ingestão de tópicos do Kafka e assuntos do NATS como tarefas
+type (
+	IngestSource   = ingest.Source
+	IngestConfig   = ingest.Config
+	IngestTemplate = ingest.Template
+	IngestStats    = ingest.Stats
+	IngestBridge   = ingest.Bridge
+)
+
+// Sinks dos resultados finais dos workflows
+type (
+	SinkConfig    = sinks.Config
+	SinkRouter    = sinks.Router
+	ResultSink    = sinks.Sink
+	SinkResult    = sinks.Result
+	SinkExecer    = sinks.Execer
+	SinkTokenFunc = sinks.TokenFunc
+)
+
+// Execução de workflows por linha de planilha
+type (
+	SheetRow          = sheetbatch.Row
+	SpreadsheetSheet  = sheetbatch.Sheet
+	SheetBatchConfig  = sheetbatch.Config
+	SheetRowResult    = sheetbatch.RowResult
+	SheetBatchSummary = sheetbatch.Summary
+)
+
+// Documentos gerados a partir de modelos Markdown e Word
+type (
+	DocumentLibrary = docgen.Library
+	Document        = docgen.Document
+	DocumentFormat  = docgen.Format
+)
+
+// Grafo de conhecimento das entidades das campanhas
+type (
+	KnowledgeGraph    = knowledge.Graph
+	KnowledgeEntity   = knowledge.Entity
+	KnowledgeRelation = knowledge.Relation
+	KnowledgeQuery    = knowledge.Query
+)
+
+// Monitoramento das páginas e feeds dos concorrentes
+type (
+	CompetitorSource  = competitor.Source
+	CompetitorConfig  = competitor.Config
+	CompetitorChange  = competitor.Change
+	CompetitorFetcher = competitor.Fetcher
+	CompetitorWatcher = competitor.Watcher
+)
+
+// Calendário de conteúdo das campanhas, exportável em CSV e ICS
+type (
+	ContentCalendar = calendar.Calendar
+	CalendarEntry   = calendar.Entry
+	CalendarPlan    = calendar.Plan
+	CalendarFormat  = calendar.Format
+)
+
+// Voz da marca verificada nos textos gerados (CognitiveAgent.SetBrandVoice)
+type (
+	BrandVoice          = brandvoice.Voice
+	BrandVoiceReviewer  = brandvoice.Reviewer
+	BrandVoiceReview    = brandvoice.Review
+	BrandVoiceReport    = brandvoice.Report
+	BrandVoiceViolation = brandvoice.Violation
+	BrandVoiceRequest   = brandvoice.Request
+)
+
+// Webhooks dos eventos do ciclo de vida
+type (
+	WebhookConfig     = webhook.Config
+	WebhookEndpoint   = webhook.Endpoint
+	WebhookDelivery   = webhook.Delivery
+	WebhookDispatcher = webhook.Dispatcher
+	WebhookStatus     = webhook.Status
+)
+
+// Presença dos agentes no barramento
+type (
+	PresenceBus     = presence.Bus
+	PresenceConfig  = presence.Config
+	PresenceMonitor = presence.Monitor
+	PresenceStatus  = presence.Status
+)
+
+// Plano de controle: comandos administrativos às instâncias dos agentes
+type (
+	ControlConfig   = control.Config
+	ControlCommand  = control.Command
+	ControlAck      = control.Ack
+	ControlAction   = control.Action
+	AgentController = control.Controller
+	AgentControl    = presence.Control
+	AgentIntake     = presence.Intake
+)
+
+// Quadro compartilhado (blackboard) para problemas exploratórios
+type (
+	Blackboard           = blackboard.Board
+	BlackboardEntry      = blackboard.Entry
+	BlackboardChange     = blackboard.Change
+	BlackboardController = blackboard.Controller
+	KnowledgeSource      = blackboard.KnowledgeSource
+)
+
+// Decisões por votação entre agentes
+type (
+	VoteQuestion   = consensus.Question
+	VoteBallot     = consensus.Ballot
+	VoteAggregator = consensus.Aggregator
+	VoteResult     = consensus.Result
+	VoteDissent    = consensus.Dissent
+)
+
+// Debates estruturados entre agentes
+type (
+	DebateConfig     = debate.Config
+	DebateTranscript = debate.Transcript
+	DebateTurn       = debate.Turn
+	DebateVerdict    = debate.Verdict
+	DebateStore      = debate.Store
+)
+
+// Validação das saídas das tarefas contra o resultado esperado
+type (
+	OutputValidationConfig = validation.Config
+	OutputValidator        = validation.Validator
+	OutputExpectation      = validation.Expectation
+	OutputValidationResult = validation.Result
+)
+
+// Galeria de workflows parametrizados
+type (
+	Workflow         = agents.Workflow
+	WorkflowTemplate = templates.Template
+	TemplateVariable = templates.Variable
+)
+
+// Conversas em grupo entre agentes
+type (
+	GroupChatConfig      = groupchat.Config
+	GroupChatThread      = groupchat.Thread
+	GroupChatMessage     = groupchat.Message
+	GroupChatParticipant = groupchat.Participant
+	GroupChatSelector    = groupchat.Selector
+	GroupChatRule        = groupchat.Rule
+	GroupChatTermination = groupchat.Termination
+)
+
+// Andamento informado por tarefas e ferramentas demoradas
+type (
+	ProgressReporter = progress.Reporter
+	ProgressUpdate   = progress.Update
+	ProgressCounter  = progress.Counter
+)
+
+// Tempo de cada agente no workflow (WorkflowResults.Timing)
+type (
+	TimingBreakdown = timing.Breakdown
+	AgentTiming     = timing.AgentTiming
+	TimingSpan      = timing.Span
+)
+
+// Injeção de falhas para testes de resiliência
+type (
+	ChaosConfig   = chaos.Config
+	ChaosInjector = chaos.Injector
+	ChaosFault    = chaos.Fault
+)
+
+// Inferência local sem dependência de APIs externas
+type (
+	LocalLLMProvider = llm.LocalProvider
+	Ollama           = llm.Ollama
+	VLLM             = llm.VLLM
+)
+
+// Agrupamento de requisições ao LLM
+type (
+	BatchConfig      = batch.Config
+	BatchResult      = batch.Result
+	LLMBatcher       = batch.Batcher
+	LLMBatchProvider = llm.BatchProvider
+)
+
+// Escalonamento justo entre tenants e workflows
+type (
+	FairShareConfig    = fairshare.Config
+	FairShareScheduler = fairshare.Scheduler
+	FairShareFlow      = fairshare.Flow
+)
+
+// Caixas de entrada dos agentes com consumo por demanda
+type (
+	Inbox         = inbox.Inbox
+	InboxConfig   = inbox.Config
+	InboxMessage  = inbox.Message
+	InboxDelivery = inbox.Delivery
+	InboxStats    = inbox.Stats
+	InboxHandler  = inbox.Handler
+)
+
+// Redistribuição de tarefas entre instâncias do mesmo papel
+type (
+	WorkStealingConfig = stealing.Config
+)
+
+// Armazenamento de objetos para conteúdos grandes
+type (
+	BlobStore   = blob.Store
+	BlobRef     = blob.Ref
+	BlobConfig  = blob.Config
+	BlobManager = blob.Manager
+	S3Config    = blob.S3Config
+	// GridFSBlobStore mantém a conexão com o MongoDB, fechada por Close
+	GridFSBlobStore = blob.GridFSStore
+)
+
+// Skills carregadas de módulos WASM ou plugins Go
+type (
+	Skill         = skills.Skill
+	SkillManifest = skills.Manifest
+)
+
+// Cliente do Model Context Protocol para ferramentas e recursos de servidores externos
+type (
+	MCPConfig   = mcp.Config
+	MCPClient   = mcp.Client
+	MCPResource = mcp.Resource
+	SchemaTool  = agents.SchemaTool
+)
+
+// Servidor MCP que expõe as ferramentas do HiveMind a assistentes externos
+type (
+	MCPServer       = mcp.Server
+	MCPServerConfig = mcp.ServerConfig
+	MCPCaller       = mcp.Caller
+)
+
+// Biblioteca de personas dos agentes
+type (
+	Persona        = persona.Persona
+	PersonaLibrary = persona.Library
+)
+
+// Alocação de tarefas por licitação (contract net)
+type (
+	ContractNetBus     = contractnet.Bus
+	ContractNetConfig  = contractnet.Config
+	ContractNetManager = contractnet.Manager
+	TaskAnnouncement   = contractnet.Announcement
+	TaskBid            = contractnet.Bid
+	TaskAward          = contractnet.Award
+)
+
+// Descoberta dos agentes por gossip, sem registro central
+type (
+	DiscoveryConfig = discovery.Config
+	DiscoveryNode   = discovery.Node
+	DiscoveryEvent  = discovery.Event
+	AgentCapability = discovery.Capability
+)
+
+// Provedores de LLM
+type (
+	LLMProvider = llm.Provider
+	LLMRequest  = llm.Request
+	LLMResponse = llm.Response
+)
+
+// Tipos de memória e de eventos
+const (
+	ShortTerm = memory.ShortTerm
+	LongTerm  = memory.LongTerm
+	Scratch   = memory.Scratch
+
+	EventAgentAction       = agents.EventAgentAction
+	EventTaskUpdate        = agents.EventTaskUpdate
+	EventWorkflowUpdate    = agents.EventWorkflowUpdate
+	EventProjectUpdate     = agents.EventProjectUpdate
+	EventMemoryOperation   = agents.EventMemoryOperation
+	EventMemoryMaintenance = agents.EventMemoryMaintenance
+	EventToolCall          = agents.EventToolCall
+	EventToolDenied        = agents.EventToolDenied
+	EventModeration        = agents.EventModeration
+	EventError             = agents.EventError
+)
+
+// AutoImportance pede a CognitiveAgent.Memorize que estime a importância da memória
+const AutoImportance = agents.AutoImportance
+
+// Formatos de exportação dos agentes (Runtime.Export)
+const (
+	ExportLangChain  = interop.FormatLangChain
+	ExportLlamaIndex = interop.FormatLlamaIndex
+)
+
+// Ações do plano de controle (AgentController.Send) e situações da entrada de tarefas
+const (
+	ControlPause    = control.ActionPause
+	ControlResume   = control.ActionResume
+	ControlDrain    = control.ActionDrain
+	ControlReload   = control.ActionReload
+	ControlLogLevel = control.ActionLogLevel
+
+	IntakeOpen     = presence.IntakeOpen
+	IntakePaused   = presence.IntakePaused
+	IntakeDraining = presence.IntakeDraining
+	IntakeDrained  = presence.IntakeDrained
+)
+
+// Políticas de sobreposição das execuções periódicas (CronJob.Overlap)
+const (
+	CronSkip  = cron.OverlapSkip
+	CronQueue = cron.OverlapQueue
+)
+
+// Formatos dos modelos de documento
+const (
+	DocumentMarkdown = docgen.FormatMarkdown
+	DocumentDocx     = docgen.FormatDocx
+)
+
+// Formatos de exportação do calendário de conteúdo
+const (
+	CalendarCSV = calendar.FormatCSV
+	CalendarICS = calendar.FormatICS
+)
+
+// Status do workflow (WorkflowResults.Status); com WorkflowTimeout os resultados são parciais
+const (
+	WorkflowCompleted = agents.WorkflowCompleted
+	WorkflowTimeout   = agents.WorkflowTimeout
+)
+
+// Erros da taxonomia, para uso com errors.Is
+var (
+	ErrNotFound    = errs.ErrNotFound
+	ErrTimeout     = errs.ErrTimeout
+	ErrRateLimited = errs.ErrRateLimited
+	ErrValidation  = errs.ErrValidation
+
+	// ErrChaosInjected identifica as falhas injetadas por WithChaos
+	ErrChaosInjected = chaos.ErrInjected
+)
+
+// NewCognitiveAgent cria um agente cognitivo
+func NewCognitiveAgent(id, name, description string, maxRounds int, model, role, goal string, memoryManager MemoryManager) *CognitiveAgent {
+	return agents.NewCognitiveAgent(id, name, description, maxRounds, model, role, goal, memoryManager)
+}
+
+// NewTask cria uma tarefa
+func NewTask(id, taskType, description string, input map[string]interface{}) *Task {
+	return agents.NewTask(id, taskType, description, input)
+}
+
+// NewFuncTool adapta uma função para a interface Tool
+func NewFuncTool(name, description string, fn func(ctx context.Context, params map[string]interface{}) (interface{}, error)) Tool {
+	return agents.NewFuncTool(name, description, fn)
+}
+
+// NewMarketingCrew cria uma equipe de marketing
+func NewMarketingCrew(memoryManager MemoryManager) *MarketingCrew {
+	return agents.NewMarketingCrew(memoryManager)
+}
+
+// NewTrainingCrew cria uma equipe de treinamento
+func NewTrainingCrew(memoryManager MemoryManager) *TrainingCrew {
+	return agents.NewTrainingCrew(memoryManager)
+}
+
+// LoadDataset carrega um dataset de exemplos de um arquivo .jsonl ou .csv
+func LoadDataset(path string) (*Dataset, error) {
+	return dataset.Load(path)
+}
+
+// Accepted é o resultado de um workflow aprovado, usado em MarketingCrew.Reinforce
+func Accepted(source string) Outcome {
+	return agents.Accepted(source)
+}
+
+// Rejected é o resultado de um workflow rejeitado
+func Rejected(source, reason string) Outcome {
+	return agents.Rejected(source, reason)
+}
+
+// MetricOutcome converte uma métrica downstream em recompensa relativa à meta
+func MetricOutcome(source string, value, target float64) Outcome {
+	return agents.MetricOutcome(source, value, target)
+}
+
+// NewModeration cria um pipeline de moderação com os moderadores informados, usado em WithModeration
+func NewModeration(config ModerationConfig, moderators ...Moderator) *ModerationPipeline {
+	return moderation.New(config, moderators...)
+}
+
+// NewApprovalGate cria o portão de aprovação usado em WithApprovals
+func NewApprovalGate() *ApprovalGate {
+	return approval.NewGate()
+}
+
+// NewKeywordModerator cria um moderador local a partir de termos por categoria
+func NewKeywordModerator(categories map[string][]string) (Moderator, error) {
+	return moderation.NewKeywords(categories)
+}
+
+// NewOpenAIModerator cria um moderador que usa a API de moderação da OpenAI
+func NewOpenAIModerator(apiKey string) Moderator {
+	return moderation.NewOpenAIModerator(apiKey)
+}
+
+// NewAnonymizer cria um pseudonimizador de dados pessoais, usado em WithAnonymizer
+func NewAnonymizer(config PIIConfig, detectors ...PIIDetector) *Anonymizer {
+	return pii.New(config, detectors...)
+}
+
+// NewPIIPatterns cria um detector de dados pessoais por expressões regulares (e-mail, CPF,
+// CNPJ, cartão, telefone e IP)
+func NewPIIPatterns() PIIDetector {
+	return pii.DefaultPatterns()
+}
+
+// NewPIINER cria um detector de nomes, locais e organizações com um LLM local ou confiável
+func NewPIINER(provider LLMProvider, model string) PIIDetector {
+	return pii.NewNER(provider, model)
+}
+
+// NewImportanceScorer cria um avaliador de importância das memórias com um LLM, usado em
+// WithImportanceScorer; um modelo pequeno é suficiente
+func NewImportanceScorer(provider LLMProvider, model string) ImportanceScorer {
+	return importance.NewLLMScorer(provider, model)
+}
+
+// NewEventEmitter cria um emissor de eventos
+func NewEventEmitter() *EventEmitter {
+	return agents.NewEventEmitter()
+}
+
+// NewOutboxEvent cria um evento do outbox com o valor serializado em JSON
+func NewOutboxEvent(subject string, v interface{}) (*OutboxEvent, error) {
+	return outbox.NewJSONEvent(subject, v)
+}
+
+// NewBlackboardController coordena as fontes de conhecimento sobre o quadro até solved
+// retornar verdadeiro
+func NewBlackboardController(board *Blackboard, solved func(*Blackboard) bool, sources ...KnowledgeSource) *BlackboardController {
+	return blackboard.NewController(board, solved, sources...)
+}
+
+// BlackboardSource transforma o agente em uma fonte de conhecimento que lê os tópicos de
+// triggers e publica a sua contribuição em topic
+func BlackboardSource(agent *CognitiveAgent, topic string, triggers ...string) KnowledgeSource {
+	return agents.BlackboardSource(agent, topic, triggers...)
+}
+
+// NewPersonaLibrary cria uma biblioteca com as personas informadas
+func NewPersonaLibrary(personas ...Persona) (*PersonaLibrary, error) {
+	return persona.NewLibrary(personas...)
+}
+
+// LoadPersonas carrega uma biblioteca de personas de um arquivo YAML ("personas: {nome: {...}}")
+func LoadPersonas(filename string) (*PersonaLibrary, error) {
+	return persona.LoadLibrary(filename)
+}
+
+// MajorityVote escolhe a resposta mais votada, desempatando pela confiança dos votantes
+func MajorityVote() VoteAggregator {
+	return consensus.Majority()
+}
+
+// RankedChoiceVote decide por voto ranqueado (instant runoff) sobre as opções da pergunta
+func RankedChoiceVote() VoteAggregator {
+	return consensus.RankedChoice()
+}
+
+// JudgeVote usa um modelo juiz para escolher entre as respostas dos votantes
+func JudgeVote(provider LLMProvider, model string) VoteAggregator {
+	return consensus.Judge(provider, model)
+}
+
+// SchemaValidator valida as saídas das tarefas contra o OutputSchema
+func SchemaValidator() OutputValidator {
+	return validation.Schema()
+}
+
+// JudgeValidator pede a um modelo juiz que compare as saídas com o ExpectedOutput das tarefas
+func JudgeValidator(provider LLMProvider, model string) OutputValidator {
+	return validation.Judge(provider, model)
+}
+
+// AllValidators aplica os validadores em ordem; a primeira rejeição decide
+func AllValidators(validators ...OutputValidator) OutputValidator {
+	return validation.All(validators...)
+}
+
+// WorkflowTemplates retorna os templates de workflow embutidos (pesquisa de mercado, pipeline
+// de conteúdo, revisão de código e análise de incidentes)
+func WorkflowTemplates() []WorkflowTemplate {
+	return templates.List()
+}
+
+// InstantiateTemplate instancia um template de workflow embutido com as variáveis informadas
+func InstantiateTemplate(name string, vars map[string]string) (*Workflow, error) {
+	return agents.InstantiateTemplate(name, vars)
+}
+
+// RoundRobinSpeaker passa a palavra aos participantes da conversa em grupo em rodízio
+func RoundRobinSpeaker() GroupChatSelector {
+	return groupchat.RoundRobin()
+}
+
+// RuleBasedSpeaker escolhe o próximo participante pela primeira regra aplicável, ou pelo
+// fallback (rodízio se nil)
+func RuleBasedSpeaker(fallback GroupChatSelector, rules ...GroupChatRule) GroupChatSelector {
+	return groupchat.Rules(fallback, rules...)
+}
+
+// LLMSpeaker faz do agente o gerente que escolhe o próximo participante da conversa
+func LLMSpeaker(manager *CognitiveAgent) GroupChatSelector {
+	return agents.GroupChatSelector(manager)
+}
+
+// TerminateOn encerra a conversa em grupo quando a última mensagem contém a palavra
+func TerminateOn(keyword string) GroupChatTermination {
+	return groupchat.Keyword(keyword)
+}
+
+// ReportProgress informa o andamento da tarefa em execução ("30%, varrendo portas"), que vira
+// um evento task_progress e aparece em GetProjectStatus; sem acompanhamento não faz nada
+func ReportProgress(ctx context.Context, percent float64, message string) {
+	progress.Report(ctx, percent, message)
+}
+
+// NewProgressCounter informa o andamento de um lote de total itens a cada Add
+func NewProgressCounter(ctx context.Context, total int) *ProgressCounter {
+	return progress.NewCounter(ctx, total)
+}
+
+// ProgressStep faz os informes feitos com o contexto retornado ocuparem a faixa [from, to]
+// do andamento da tarefa
+func ProgressStep(ctx context.Context, from, to float64) context.Context {
+	return progress.Scale(ctx, from, to)
+}
+
+// NewChaos cria o injetor de falhas usado em WithChaos; as taxas vão de 0 a 1 por operação
+func NewChaos(config ChaosConfig) (*ChaosInjector, error) {
+	return chaos.New(config)
+}
+
+// NewOllama cria o provedor local do Ollama; baseURL vazio usa http://localhost:11434. Com
+// WithLLM, Start verifica o servidor e baixa o modelo padrão se ele não estiver instalado.
+func NewOllama(baseURL, model string) *Ollama {
+	return llm.NewOllama(baseURL, model)
+}
+
+// NewVLLM cria o provedor local do vLLM (API compatível com a OpenAI); baseURL vazio usa
+// http://localhost:8000/v1
+func NewVLLM(baseURL, model string) *VLLM {
+	return llm.NewVLLM(baseURL, model)
+}
+
+// NewLLMBatcher agrupa as requisições compatíveis ao provedor; CompleteAll envia um conjunto
+// de prompts (ex.: avaliar muitos itens) e aguarda todas as respostas
+func NewLLMBatcher(provider LLMProvider, config BatchConfig) *LLMBatcher {
+	return batch.New(provider, config)
+}
+
+// NewFairShare cria o escalonador justo usado em WithFairShare e WithFairShareLLM
+func NewFairShare(config FairShareConfig) (*FairShareScheduler, error) {
+	return fairshare.New(config)
+}
+
+// NewRecapSummarizer cria o resumidor de workflows por LLM, usado em WithRecap; um modelo
+// pequeno é suficiente
+func NewRecapSummarizer(provider LLMProvider, model string) RecapSummarizer {
+	return recap.NewLLMSummarizer(provider, model)
+}
+
+// DefaultRetrievalWeights retorna os pesos padrão do ranking de memórias, usados em
+// WithMemoryRanking ou CognitiveAgent.SetMemoryRanking
+func DefaultRetrievalWeights() RetrievalWeights {
+	return retrieval.DefaultWeights()
+}
+
+// RankMemories ordena memórias por similaridade, recência e importância
+func RankMemories(memories []*Memory, weights RetrievalWeights) []RankedMemory {
+	return retrieval.Rank(memories, weights, time.Now())
+}
+
+// NewInbox cria caixas de entrada avulsas; o runtime cria as suas com WithInbox
+func NewInbox(config InboxConfig) *Inbox {
+	return inbox.New(config)
+}
+
+// NewS3BlobStore cria o armazenamento de objetos num bucket S3 ou MinIO
+func NewS3BlobStore(config S3Config) (BlobStore, error) {
+	return blob.NewS3Store(config)
+}
+
+// S3ConfigFromEnv lê a configuração do bucket S3 ou MinIO das variáveis de ambiente
+func S3ConfigFromEnv() S3Config {
+	return blob.S3ConfigFromEnv()
+}
+
+// NewGridFSBlobStore conecta ao MongoDB e cria o armazenamento de objetos no bucket GridFS do banco
+func NewGridFSBlobStore(ctx context.Context, mongoURL, database, bucket string) (*GridFSBlobStore, error) {
+	return blob.NewGridFSStore(ctx, mongoURL, database, bucket)
+}
+
+// NewMemoryBlobStore cria um armazenamento de objetos em memória, para testes e uso local
+func NewMemoryBlobStore() BlobStore {
+	return blob.NewMemoryStore()
+}
+
+// NewBlobManager cria um gerenciador de blobs avulso; o runtime cria o seu com WithBlobStore
+func NewBlobManager(store BlobStore, config BlobConfig) *BlobManager {
+	return blob.NewManager(store, config)
+}
+
+// NewSLABusNotifier publica os alertas de SLA no barramento, no tópico informado ("sla.alert"
+// se vazio)
+func NewSLABusNotifier(bus PresenceBus, subject string) SLANotifier {
+	return sla.NewBusNotifier(bus, subject)
+}
+
+// NewSLAWebhookNotifier envia os alertas de SLA por POST à URL
+func NewSLAWebhookNotifier(url string) *SLAWebhookNotifier {
+	return sla.NewWebhookNotifier(url)
+}
+
+// VerifyWebhook confere, no receptor, a assinatura X-HiveMind-Signature do corpo de uma
+// entrega enviada no instante X-HiveMind-Timestamp
+func VerifyWebhook(secret, signature string, timestamp int64, body []byte) bool {
+	return webhook.Verify(secret, signature, timestamp, body)
+}
+
+// NewObjectSink grava cada resultado como JSON no armazenamento de objetos (S3, MinIO ou
+// GridFS), sob o prefixo informado
+func NewObjectSink(store BlobStore, prefix string) ResultSink {
+	return sinks.NewObjectSink(store, prefix)
+}
+
+// NewPostgresSink insere cada resultado na tabela informada, pela conexão aberta pela
+// aplicação (por exemplo, um *sql.DB com o driver do Postgres)
+func NewPostgresSink(db SinkExecer, table string) (ResultSink, error) {
+	return sinks.NewSQLSink(db, table)
+}
+
+// NewSheetsSink acrescenta uma linha por tarefa de cada resultado à aba da planilha do
+// Google Sheets, com o token OAuth fornecido por token
+func NewSheetsSink(spreadsheetID, sheet string, token SinkTokenFunc) ResultSink {
+	return sinks.NewSheetsSink(spreadsheetID, sheet, token)
+}
+
+// NewWebhookSink envia cada resultado por POST à URL, assinado com o segredo como os
+// webhooks do ciclo de vida
+func NewWebhookSink(url, secret string) ResultSink {
+	return sinks.NewWebhookSink(url, secret)
+}
+
+// NewSpreadsheetSheet monta as linhas de dados da planilha a partir dos cabeçalhos e das
+// células, como as lidas pelo SpreadsheetProcessor
+func NewSpreadsheetSheet(headers []string, cells [][]string) SpreadsheetSheet {
+	return sheetbatch.NewSheet(headers, cells)
+}
+
+// TemplateProject monta o projeto de cada linha da planilha a partir do template embutido,
+// com as colunas como variáveis
+func TemplateProject(name string) func(SheetRow) (*MarketingProject, error) {
+	return agents.TemplateProject(name)
+}
+
+// WriteSpreadsheetResults grava os resultados por linha numa nova aba da planilha XLSX e o
+// resumo da execução numa aba ao lado
+func WriteSpreadsheetResults(path, sheet string, summary *SheetBatchSummary) error {
+	return sheetbatch.WriteResults(path, sheet, summary)
+}
+
+// NewDocumentLibrary cria uma biblioteca vazia de modelos de documento
+func NewDocumentLibrary() *DocumentLibrary {
+	return docgen.New()
+}
+
+// LoadDocumentTemplates cria a biblioteca com os modelos Markdown (.md, .markdown, .mdown) e
+// Word (.docx) do diretório, cada um com o nome do arquivo sem a extensão
+func LoadDocumentTemplates(dir string) (*DocumentLibrary, error) {
+	library := docgen.New()
+	if err := library.LoadDir(dir); err != nil {
+		return nil, err
+	}
+	return library, nil
+}
+
+// WorkflowDocumentData reúne os dados do projeto e dos resultados de um workflow para os
+// modelos de documento
+func WorkflowDocumentData(project *MarketingProject, results *WorkflowResults) map[string]interface{} {
+	return agents.WorkflowDocumentData(project, results)
+}
+
+// NewKnowledgeGraph cria um grafo de conhecimento vazio; a ferramenta query_knowledge
+// (NewKnowledgeTool) o expõe aos agentes
+func NewKnowledgeGraph() *KnowledgeGraph {
+	return knowledge.New()
+}
+
+// NewKnowledgeTool cria a ferramenta com que os agentes consultam o grafo de conhecimento
+func NewKnowledgeTool(graph *KnowledgeGraph) Tool {
+	return agents.NewKnowledgeTool(graph)
+}
+
+// NewCompetitorWatcher cria o monitor das fontes dos concorrentes, baixadas por HTTP; para
+// usar o scraper das ferramentas, crie-o com competitor.New e tools.ScraperFetcher
+func NewCompetitorWatcher(config CompetitorConfig, sources ...CompetitorSource) (*CompetitorWatcher, error) {
+	return competitor.New(competitor.NewHTTPFetcher(), config, sources...)
+}
+
+// GenerateContentCalendar distribui as variantes do texto da campanha pelos dias e canais do
+// plano; a variante principal muda a cada publicação do canal
+func GenerateContentCalendar(name, campaign string, variants []string, plan CalendarPlan) (*ContentCalendar, error) {
+	return calendar.Generate(name, campaign, variants, plan)
+}
+
+// LoadBrandVoice carrega as diretrizes da voz da marca de um arquivo YAML no formato
+// "brand_voice: {...}"
+func LoadBrandVoice(filename string) (*BrandVoice, error) {
+	return brandvoice.Load(filename)
+}
+
+// NewBrandVoiceReviewer cria o revisor que verifica textos contra a voz da marca e pede
+// revisões enquanto houver violações
+func NewBrandVoiceReviewer(voice *BrandVoice) *BrandVoiceReviewer {
+	return brandvoice.NewReviewer(voice)
+}
+
+// DefaultMemoryConfig retorna a configuração de memória padrão
+func DefaultMemoryConfig() *MemoryConfig {
+	return memory.DefaultMemoryConfig()
+}
+
+// NewMemoryManager cria o gerenciador de memória híbrido (Redis, MongoDB e Weaviate)
+func NewMemoryManager(ctx context.Context, config *MemoryConfig) (MemoryManager, error) {
+	manager, err := memory.NewHybridMemoryManager(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+	return manager, nil
+}
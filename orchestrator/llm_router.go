@@ -3,51 +3,81 @@ package orchestrator
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
+	"sync"
 	"time"
 
 	"github.com/streadway/amqp"
+
+	"github.com/suissa/HiveMind/agents/communication"
+	"github.com/suissa/HiveMind/agents/contractnet"
+	"github.com/suissa/HiveMind/agents/errs"
+	"github.com/suissa/HiveMind/agents/inbox"
+	"github.com/suissa/HiveMind/agents/llm"
+	"github.com/suissa/HiveMind/agents/messages"
+	"github.com/suissa/HiveMind/agents/overrides"
+	"github.com/suissa/HiveMind/agents/policy"
+	"github.com/suissa/HiveMind/agents/shutdown"
+	"github.com/suissa/HiveMind/agents/simulation"
+	"github.com/suissa/HiveMind/agents/supervisor"
+	"github.com/suissa/HiveMind/agents/tenant"
 )
 
 // LLMRouter é responsável por integrar com o RouteLLM
 type LLMRouter struct {
+	tenant      string
 	conn        *amqp.Connection
-	channel     *amqp.Channel
+	channel     *amqp.Channel               // Consumo da fila de entrada
+	publisher   *communication.RabbitMQPool // Publicações, com confirmação do broker
 	inputQueue  string
 	taskQueue   string
 	resultQueue string
+	signer      communication.Signer
+	verifier    communication.Verifier
+	policy      policy.Engine
+	llm         llm.Provider
+	limits      overrides.Limits
+	shutdown    *shutdown.Manager
+	supervisor  *supervisor.Supervisor
+	contracts   *contractnet.Manager
+	inbox       *inbox.Inbox
 }
 
-// TaskRequest representa uma solicitação de tarefa
-type TaskRequest struct {
-	ID          string                 `json:"id"`
-	Description string                 `json:"description"`
-	Parameters  map[string]interface{} `json:"parameters"`
-}
+// routerConsumer identifica o consumidor da fila de entrada, permitindo cancelá-lo no encerramento
+const routerConsumer = "llm_router"
 
-// SubTask representa uma subtarefa gerada pela LLM
-type SubTask struct {
-	ID          string                 `json:"id"`
-	ParentID    string                 `json:"parent_id"`
-	Name        string                 `json:"name"`
-	Description string                 `json:"description"`
-	Type        string                 `json:"type"`
-	Parameters  map[string]interface{} `json:"parameters"`
-	Status      string                 `json:"status"`
-}
+// breakdownPrompt instrui o LLM a devolver as subtarefas em JSON
+const breakdownPrompt = `Quebre a tarefa do usuário em subtarefas executáveis.
+Responda apenas com um array JSON de objetos com os campos name, description, type e parameters.`
+
+// Contratos das mensagens das filas, gerados dos JSON Schemas em agents/messages/schemas
+type (
+	TaskRequest = messages.TaskRequest
+	SubTask     = messages.SubTask
+)
 
-// NewLLMRouter cria uma nova instância do LLMRouter
+// NewLLMRouter cria uma nova instância do LLMRouter para o tenant padrão
 func NewLLMRouter(conn *amqp.Connection) (*LLMRouter, error) {
+	return NewTenantLLMRouter(conn, tenant.DefaultTenant)
+}
+
+// NewTenantLLMRouter cria um LLMRouter cujas filas são isoladas pelo tenant
+func NewTenantLLMRouter(conn *amqp.Connection, tenantID string) (*LLMRouter, error) {
+	if err := tenant.Validate(tenantID); err != nil {
+		return nil, err
+	}
+
 	channel, err := conn.Channel()
 	if err != nil {
 		return nil, fmt.Errorf("erro ao criar canal: %v", err)
 	}
 
 	// Declarando as filas
-	inputQueue := "llm_input"
-	taskQueue := "llm_tasks"
-	resultQueue := "llm_results"
+	inputQueue := tenant.Namespace(tenantID, "llm_input")
+	taskQueue := tenant.Namespace(tenantID, "llm_tasks")
+	resultQueue := tenant.Namespace(tenantID, "llm_results")
 
 	// Fila de entrada
 	_, err = channel.QueueDeclare(
@@ -89,11 +119,30 @@ func NewLLMRouter(conn *amqp.Connection) (*LLMRouter, error) {
 	}
 
 	return &LLMRouter{
+		tenant:      tenantID,
 		conn:        conn,
 		channel:     channel,
+		publisher:   communication.NewConnectionPool(conn, communication.PoolOptions{}),
 		inputQueue:  inputQueue,
 		taskQueue:   taskQueue,
 		resultQueue: resultQueue,
+		supervisor:  supervisor.Default,
+	}, nil
+}
+
+// NewDryRunLLMRouter cria um LLMRouter sem conexão com o broker, para executar
+// ProcessTask e SubmitTask em modo dry-run (ex.: testes no CI)
+func NewDryRunLLMRouter(tenantID string) (*LLMRouter, error) {
+	if err := tenant.Validate(tenantID); err != nil {
+		return nil, err
+	}
+
+	return &LLMRouter{
+		tenant:      tenantID,
+		inputQueue:  tenant.Namespace(tenantID, "llm_input"),
+		taskQueue:   tenant.Namespace(tenantID, "llm_tasks"),
+		resultQueue: tenant.Namespace(tenantID, "llm_results"),
+		supervisor:  supervisor.Default,
 	}, nil
 }
 
@@ -167,16 +216,55 @@ func (r *LLMRouter) mockLLMBreakdown(task TaskRequest) []SubTask {
 	return subtasks
 }
 
+// SetShutdown registra as tarefas do router no coordenador de encerramento
+func (r *LLMRouter) SetShutdown(m *shutdown.Manager) {
+	r.shutdown = m
+}
+
+// SetOverrideLimits define os limites aplicados aos overrides das tarefas recebidas
+func (r *LLMRouter) SetOverrideLimits(limits overrides.Limits) {
+	r.limits = limits
+}
+
+// applyLimits valida os overrides da tarefa, reduzindo temperatura e tokens aos limites
+func (r *LLMRouter) applyLimits(task *TaskRequest) error {
+	if task.Overrides == nil {
+		return nil
+	}
+	o, err := r.limits.Apply(*task.Overrides)
+	if err != nil {
+		return fmt.Errorf("tarefa %s: %w", task.ID, err)
+	}
+	task.Overrides = &o
+	return nil
+}
+
+// SetSupervisor define o supervisor que reinicia o consumo após um panic
+func (r *LLMRouter) SetSupervisor(s *supervisor.Supervisor) {
+	r.supervisor = s
+}
+
+// StopIntake cancela o consumo da fila de entrada sem interromper as tarefas em andamento
+func (r *LLMRouter) StopIntake(ctx context.Context) error {
+	if r.channel == nil {
+		return nil
+	}
+	if err := r.channel.Cancel(routerConsumer, false); err != nil {
+		return fmt.Errorf("erro ao cancelar consumo do LLMRouter: %v", err)
+	}
+	return nil
+}
+
 // Start inicia o processamento de tarefas
 func (r *LLMRouter) Start(ctx context.Context) error {
 	msgs, err := r.channel.Consume(
-		r.inputQueue, // queue
-		"",           // consumer
-		false,        // auto-ack
-		false,        // exclusive
-		false,        // no-local
-		false,        // no-wait
-		nil,          // args
+		r.inputQueue,   // queue
+		routerConsumer, // consumer
+		false,          // auto-ack
+		false,          // exclusive
+		false,          // no-local
+		false,          // no-wait
+		nil,            // args
 	)
 	if err != nil {
 		return fmt.Errorf("erro ao consumir fila: %v", err)
@@ -184,61 +272,356 @@ func (r *LLMRouter) Start(ctx context.Context) error {
 
 	log.Printf("🚀 LLMRouter iniciado e aguardando tarefas na fila %s", r.inputQueue)
 
-	go func() {
+	// O loop é reiniciado com backoff se um panic escapar do processamento de uma tarefa
+	r.supervisor.Go(ctx, "llm_router:"+r.inputQueue, func(ctx context.Context) error {
 		for {
 			select {
 			case <-ctx.Done():
-				return
-			case msg := <-msgs:
-				var task TaskRequest
-				if err := json.Unmarshal(msg.Body, &task); err != nil {
-					log.Printf("❌ Erro ao deserializar tarefa: %v", err)
-					msg.Nack(false, true)
-					continue
+				// Para de receber entregas; as não confirmadas voltam para a fila
+				r.channel.Cancel(routerConsumer, false)
+				return nil
+			case msg, ok := <-msgs:
+				if !ok {
+					return nil
 				}
+				r.handleDelivery(ctx, msg)
+			}
+		}
+	})
 
-				log.Printf("📥 Recebida nova tarefa: %s", task.Description)
-
-				// Quebra a tarefa em subtarefas usando a LLM
-				subtasks := r.mockLLMBreakdown(task)
-				log.Printf("🔄 Tarefa quebrada em %d subtarefas", len(subtasks))
-
-				// Publica cada subtarefa na fila de tarefas
-				for _, subtask := range subtasks {
-					taskBytes, err := json.Marshal(subtask)
-					if err != nil {
-						log.Printf("❌ Erro ao serializar subtarefa: %v", err)
-						continue
-					}
-
-					err = r.channel.Publish(
-						"",          // exchange
-						r.taskQueue, // routing key
-						false,       // mandatory
-						false,       // immediate
-						amqp.Publishing{
-							ContentType: "application/json",
-							Body:        taskBytes,
-						})
-					if err != nil {
-						log.Printf("❌ Erro ao publicar subtarefa: %v", err)
-						continue
-					}
-
-					log.Printf("📤 Subtarefa publicada: %s", subtask.Name)
-				}
+	return nil
+}
 
-				msg.Ack(false)
-				log.Printf("✅ Tarefa processada com sucesso")
-			}
+// handleDelivery verifica, decodifica e processa uma tarefa recebida da fila de entrada
+func (r *LLMRouter) handleDelivery(ctx context.Context, msg amqp.Delivery) {
+	// Uma tarefa que causa panic é descartada para não derrubar o router em loop
+	defer func() {
+		if p := recover(); p != nil {
+			msg.Nack(false, false)
+			panic(p)
 		}
 	}()
 
+	if r.verifier != nil {
+		if err := communication.VerifyAMQP(r.verifier, &msg, communication.DefaultSignatureMaxAge); err != nil {
+			log.Printf("🚫 Tarefa rejeitada: %v", err)
+			msg.Nack(false, false)
+			return
+		}
+	}
+
+	// Tarefas fora do contrato não são reenfileiradas: falhariam de novo em outro consumidor.
+	// As de uma versão mais nova do contrato voltam à fila para um router atualizado.
+	var task TaskRequest
+	if err := messages.Decode(msg.Body, &task); err != nil {
+		log.Printf("❌ Tarefa inválida: %v", err)
+		msg.Nack(false, errors.Is(err, messages.ErrUnsupportedVersion))
+		return
+	}
+
+	if err := r.handleTask(ctx, task); err != nil {
+		// Interrompida pelo cancelamento ou encerramento: devolve a tarefa para outro consumidor
+		if ctx.Err() != nil || errors.Is(err, shutdown.ErrShuttingDown) {
+			msg.Nack(false, true)
+			return
+		}
+		log.Printf("🚫 Tarefa %s rejeitada: %v", task.ID, err)
+		msg.Nack(false, false)
+		return
+	}
+
+	msg.Ack(false)
+	log.Printf("✅ Tarefa processada com sucesso")
+}
+
+// handleTask processa a tarefa registrando-a no coordenador de encerramento, se configurado
+func (r *LLMRouter) handleTask(ctx context.Context, task TaskRequest) error {
+	if r.shutdown != nil {
+		done, err := r.shutdown.Track()
+		if err != nil {
+			return err
+		}
+		defer done()
+	}
+
+	_, err := r.ProcessTask(ctx, task)
+	return err
+}
+
+// ProcessTask avalia as políticas, quebra a tarefa em subtarefas e as publica na fila de tarefas.
+// Com uma sessão de simulation.WithSession no contexto nada é publicado (modo dry-run).
+func (r *LLMRouter) ProcessTask(ctx context.Context, task TaskRequest) ([]SubTask, error) {
+	if err := r.applyLimits(&task); err != nil {
+		return nil, err
+	}
+	if err := r.checkPolicy(ctx, task); err != nil {
+		return nil, err
+	}
+
+	log.Printf("📥 Recebida nova tarefa: %s", task.Description)
+
+	// Quebra a tarefa em subtarefas usando a LLM
+	subtasks, err := r.breakdown(ctx, task)
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("🔄 Tarefa quebrada em %d subtarefas", len(subtasks))
+
+	// As subtarefas herdam os overrides da tarefa e, sem NoAffinity, preferem a mesma
+	// instância, que já tem o contexto da tarefa na memória e nos caches
+	for i := range subtasks {
+		subtasks[i].Overrides = task.Overrides
+		if !task.NoAffinity {
+			subtasks[i].Affinity = task.ID
+		}
+	}
+
+	// Com contract net as subtarefas são licitadas entre os agentes; as que não recebem
+	// lances seguem para a fila de tarefas
+	awarded := r.award(ctx, subtasks)
+
+	// Publica cada subtarefa na fila de tarefas, salvo as entregues às caixas de entrada
+	for i, subtask := range subtasks {
+		if awarded[i] || r.deliver(ctx, subtask) {
+			continue
+		}
+		taskBytes, err := messages.Encode(subtask)
+		if err != nil {
+			log.Printf("❌ Subtarefa %s fora do contrato: %v", subtask.ID, err)
+			continue
+		}
+
+		err = r.publish(ctx, r.taskQueue, amqp.Publishing{
+			ContentType: "application/json",
+			Body:        taskBytes,
+		})
+		if err != nil {
+			log.Printf("❌ Erro ao publicar subtarefa: %v", err)
+			continue
+		}
+
+		log.Printf("📤 Subtarefa publicada: %s", subtask.Name)
+	}
+
+	return subtasks, nil
+}
+
+// award licita as subtarefas em paralelo quando há um gerente de contract net configurado e
+// retorna quais foram adjudicadas. No modo dry-run nada é licitado.
+func (r *LLMRouter) award(ctx context.Context, subtasks []SubTask) []bool {
+	awarded := make([]bool, len(subtasks))
+	if r.contracts == nil || simulation.IsDryRun(ctx) {
+		return awarded
+	}
+
+	var wg sync.WaitGroup
+	for i := range subtasks {
+		wg.Add(1)
+		go func(subtask *SubTask) {
+			defer wg.Done()
+			role, _ := subtask.Parameters["role"].(string)
+			award, err := r.contracts.Announce(ctx, contractnet.Announcement{
+				TaskID:      subtask.ID,
+				Type:        subtask.Type,
+				Role:        role,
+				Description: subtask.Description,
+				Parameters:  subtask.Parameters,
+				Overrides:   subtask.Overrides,
+				Affinity:    subtask.Affinity,
+			})
+			if err != nil {
+				log.Printf("⚠️ Subtarefa %s sem adjudicação, publicada na fila de tarefas: %v", subtask.Name, err)
+				return
+			}
+			subtask.AssignedTo = award.Bid.AgentID
+			subtask.Status = "awarded"
+			awarded[i] = true
+			log.Printf("🤝 Subtarefa %s adjudicada ao agente %s (%d lances)", subtask.Name, award.Bid.AgentID, award.Bids)
+		}(&subtasks[i])
+	}
+	wg.Wait()
+	return awarded
+}
+
+// deliver coloca a subtarefa na caixa de entrada de um agente do papel (parâmetro "role") ou
+// do tipo, com a afinidade da tarefa, e informa se conseguiu. Sem agente que a atenda, a
+// subtarefa segue para a fila de tarefas. No modo dry-run nada é entregue.
+func (r *LLMRouter) deliver(ctx context.Context, subtask SubTask) bool {
+	if r.inbox == nil || simulation.IsDryRun(ctx) {
+		return false
+	}
+	msgType, _ := subtask.Parameters["role"].(string)
+	if msgType == "" {
+		msgType = subtask.Type
+	}
+
+	// A caixa entrega a tarefa ao agente com os parâmetros e a descrição (agents.InboxTask)
+	payload := make(map[string]interface{}, len(subtask.Parameters)+3)
+	for k, v := range subtask.Parameters {
+		payload[k] = v
+	}
+	payload["description"] = subtask.Description
+	payload["subtask_id"] = subtask.ID
+	payload["parent_id"] = subtask.ParentID
+
+	id, err := r.inbox.DispatchAffinity(msgType, subtask.Affinity, payload)
+	if err != nil {
+		if !errors.Is(err, errs.ErrNotFound) {
+			log.Printf("⚠️ Subtarefa %s não entregue à caixa de entrada, publicada na fila de tarefas: %v", subtask.Name, err)
+		}
+		return false
+	}
+	log.Printf("📬 Subtarefa %s entregue à caixa de entrada (%s)", subtask.Name, id)
+	return true
+}
+
+// breakdown quebra a tarefa em subtarefas com o LLM configurado (ou o da sessão de simulação).
+// Sem LLM usa a quebra simulada.
+func (r *LLMRouter) breakdown(ctx context.Context, task TaskRequest) ([]SubTask, error) {
+	provider := simulation.Provider(ctx, r.llm)
+	if provider == nil {
+		return r.mockLLMBreakdown(task), nil
+	}
+
+	req := llm.Request{
+		System: breakdownPrompt,
+		Prompt: task.Description,
+	}
+	if task.Overrides != nil {
+		req = task.Overrides.Request(req)
+	}
+	resp, err := provider.Complete(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao quebrar tarefa com o LLM: %v", err)
+	}
+
+	var subtasks []SubTask
+	if err := json.Unmarshal([]byte(resp.Text), &subtasks); err != nil {
+		return nil, fmt.Errorf("resposta do LLM não é uma lista de subtarefas: %v", err)
+	}
+
+	for i := range subtasks {
+		// Versão e campos extras inventados pelo LLM não fazem parte do contrato
+		subtasks[i].Version, subtasks[i].MinVersion, subtasks[i].Extra = 0, 0, nil
+		if subtasks[i].ID == "" {
+			subtasks[i].ID = fmt.Sprintf("%s-%d", task.ID, i+1)
+		}
+		subtasks[i].ParentID = task.ID
+		if subtasks[i].Status == "" {
+			subtasks[i].Status = "pending"
+		}
+	}
+
+	return subtasks, nil
+}
+
+// SubmitTask publica uma tarefa na fila de entrada do tenant do contexto
+func (r *LLMRouter) SubmitTask(ctx context.Context, task TaskRequest) error {
+	if task.Tenant == "" {
+		task.Tenant = tenant.FromContext(ctx)
+	}
+	if err := tenant.Validate(task.Tenant); err != nil {
+		return err
+	}
+	if err := r.applyLimits(&task); err != nil {
+		return err
+	}
+
+	if err := r.checkPolicy(ctx, task); err != nil {
+		return err
+	}
+
+	queue := tenant.Namespace(task.Tenant, "llm_input")
+	// No modo dry-run a fila não é declarada no broker
+	if !simulation.IsDryRun(ctx) {
+		if r.publisher == nil {
+			return fmt.Errorf("LLMRouter sem conexão com o broker")
+		}
+		if err := r.publisher.DeclareQueue(ctx, queue); err != nil {
+			return fmt.Errorf("erro ao declarar fila de entrada: %v", err)
+		}
+	}
+
+	body, err := messages.Encode(task)
+	if err != nil {
+		return fmt.Errorf("erro ao serializar tarefa: %w", err)
+	}
+
+	err = r.publish(ctx, queue, amqp.Publishing{
+		ContentType: "application/json",
+		Body:        body,
+	})
+	if err != nil {
+		return fmt.Errorf("erro ao publicar tarefa: %v", err)
+	}
+
 	return nil
 }
 
+// SetSigning configura a assinatura das tarefas publicadas e a verificação das consumidas.
+// Com verifier definido, tarefas sem assinatura válida são descartadas.
+func (r *LLMRouter) SetSigning(signer communication.Signer, verifier communication.Verifier) {
+	r.signer = signer
+	r.verifier = verifier
+}
+
+// SetPolicy define o motor de políticas (OPA) que avalia as tarefas antes da execução
+func (r *LLMRouter) SetPolicy(engine policy.Engine) {
+	r.policy = engine
+}
+
+// checkPolicy avalia a tarefa contra as políticas configuradas
+func (r *LLMRouter) checkPolicy(ctx context.Context, task TaskRequest) error {
+	return policy.Check(ctx, r.policy, policy.QueryTask, policy.TaskInput{
+		ID:          task.ID,
+		Tenant:      task.Tenant,
+		Description: task.Description,
+		Parameters:  task.Parameters,
+	})
+}
+
+// SetContractNet passa a licitar as subtarefas entre os agentes com o gerente informado, em
+// vez de publicá-las na fila de tarefas para o roteamento pelo tipo
+func (r *LLMRouter) SetContractNet(manager *contractnet.Manager) {
+	r.contracts = manager
+}
+
+// SetInbox passa a entregar as subtarefas não adjudicadas às caixas de entrada dos agentes que
+// atendem o papel ou o tipo delas; as demais seguem para a fila de tarefas
+func (r *LLMRouter) SetInbox(box *inbox.Inbox) {
+	r.inbox = box
+}
+
+// SetLLM define o provedor de LLM usado na quebra das tarefas
+func (r *LLMRouter) SetLLM(provider llm.Provider) {
+	r.llm = provider
+}
+
+// publish publica uma mensagem na fila, assinando-a quando há um signer configurado, e aguarda
+// a confirmação do broker. No modo dry-run a publicação é apenas registrada na sessão de simulação.
+func (r *LLMRouter) publish(ctx context.Context, queue string, msg amqp.Publishing) error {
+	if session, ok := simulation.FromContext(ctx); ok {
+		session.Record(simulation.EffectPublish, queue, string(msg.Body))
+		return nil
+	}
+	if r.publisher == nil {
+		return fmt.Errorf("LLMRouter sem conexão com o broker")
+	}
+
+	if r.signer != nil {
+		if err := communication.SignAMQP(r.signer, queue, &msg); err != nil {
+			return err
+		}
+	}
+
+	return r.publisher.Publish(ctx, "", queue, msg)
+}
+
 // Close fecha a conexão
 func (r *LLMRouter) Close() error {
+	if r.channel == nil {
+		return nil
+	}
 	if err := r.channel.Close(); err != nil {
 		return fmt.Errorf("erro ao fechar canal: %v", err)
 	}
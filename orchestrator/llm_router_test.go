@@ -0,0 +1,56 @@
+package orchestrator
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/suissa/HiveMind/agents/inbox"
+	"github.com/suissa/HiveMind/agents/llm"
+	"github.com/suissa/HiveMind/agents/simulation"
+)
+
+func TestProcessTaskDeliversToInbox(t *testing.T) {
+	router, err := NewDryRunLLMRouter("acme")
+	if err != nil {
+		t.Fatal(err)
+	}
+	box := inbox.New(inbox.Config{})
+	box.Register("analyst-1", "analysis")
+	box.Register("researcher-1", "research")
+	router.SetInbox(box)
+
+	// Sem conexão, as subtarefas sem agente na caixa de entrada falham ao publicar
+	subtasks, err := router.ProcessTask(context.Background(), TaskRequest{ID: "t-1", Description: "Lançar produto"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(subtasks) < 3 {
+		t.Fatalf("subtarefas inesperadas: %+v", subtasks)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	deliveries, err := box.Receive(ctx, "analyst-1", 10)
+	if err != nil || len(deliveries) != 1 {
+		t.Fatalf("esperava a subtarefa de análise na caixa do analista: %+v %v", deliveries, err)
+	}
+	payload := deliveries[0].Payload.(map[string]interface{})
+	if payload["subtask_id"] != "t-1-1" || payload["parent_id"] != "t-1" || payload["description"] != subtasks[0].Description || payload["priority"] != "high" {
+		t.Fatalf("payload inesperado: %+v", payload)
+	}
+	if stats := box.Stats(); stats["researcher-1"].Pending != 1 {
+		t.Fatalf("esperava a subtarefa de pesquisa na caixa do pesquisador: %+v", stats)
+	}
+
+	// No modo dry-run nada é entregue
+	session := simulation.NewSession(llm.ProviderFunc(func(ctx context.Context, req llm.Request) (*llm.Response, error) {
+		return &llm.Response{Text: `[{"name": "Pesquisa", "description": "Pesquisar", "type": "research"}]`}, nil
+	}))
+	if _, err := router.ProcessTask(simulation.WithSession(context.Background(), session), TaskRequest{ID: "t-2", Description: "Simular"}); err != nil {
+		t.Fatal(err)
+	}
+	if stats := box.Stats(); stats["researcher-1"].Pending != 1 {
+		t.Fatalf("o dry-run não deveria entregar subtarefas: %+v", stats)
+	}
+}
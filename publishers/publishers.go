@@ -1,11 +1,15 @@
 package publishers
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"log"
 
 	"github.com/streadway/amqp"
+
+	"github.com/suissa/HiveMind/agents/communication"
+	"github.com/suissa/HiveMind/agents/messages"
 )
 
 const (
@@ -13,32 +17,27 @@ const (
 	RABBITMQ_PORT = 1234
 )
 
-// PublishEvent publica uma mensagem em uma fila específica do RabbitMQ
+// rabbitPool é a conexão compartilhada pelas publicações do pacote
+var rabbitPool = communication.SharedRabbitMQPool(&communication.ConnectionConfig{
+	Host:     RABBITMQ_HOST,
+	Port:     RABBITMQ_PORT,
+	Username: "guest",
+	Password: "guest",
+})
+
+// PublishEvent publica uma mensagem em uma fila específica do RabbitMQ e aguarda a confirmação
+// do broker. Os tipos de agents/messages são validados contra o schema antes da publicação.
 func PublishEvent(queueName string, message interface{}) error {
-	// Conectar ao RabbitMQ
-	conn, err := amqp.Dial(fmt.Sprintf("amqp://guest:guest@%s:%d/", RABBITMQ_HOST, RABBITMQ_PORT))
-	if err != nil {
-		return fmt.Errorf("falha ao conectar ao RabbitMQ: %v", err)
-	}
-	defer conn.Close()
+	ctx := context.Background()
 
-	// Criar canal
-	ch, err := conn.Channel()
-	if err != nil {
-		return fmt.Errorf("falha ao abrir o canal: %v", err)
+	if msg, ok := message.(messages.Message); ok {
+		if err := msg.Validate(); err != nil {
+			return fmt.Errorf("mensagem fora do contrato: %w", err)
+		}
 	}
-	defer ch.Close()
 
-	// Declarar a fila
-	_, err = ch.QueueDeclare(
-		queueName, // nome
-		true,      // durable
-		false,     // delete when unused
-		false,     // exclusive
-		false,     // no-wait
-		nil,       // arguments
-	)
-	if err != nil {
+	// Declarar a fila (uma vez por conexão)
+	if err := rabbitPool.DeclareQueue(ctx, queueName); err != nil {
 		return fmt.Errorf("falha ao declarar a fila: %v", err)
 	}
 
@@ -49,16 +48,11 @@ func PublishEvent(queueName string, message interface{}) error {
 	}
 
 	// Publicar a mensagem
-	err = ch.Publish(
-		"",        // exchange
-		queueName, // routing key
-		false,     // mandatory
-		false,     // immediate
-		amqp.Publishing{
-			ContentType:  "application/json",
-			Body:         body,
-			DeliveryMode: 2, // mensagem persistente
-		})
+	err = rabbitPool.Publish(ctx, "", queueName, amqp.Publishing{
+		ContentType:  "application/json",
+		Body:         body,
+		DeliveryMode: 2, // mensagem persistente
+	})
 	if err != nil {
 		return fmt.Errorf("falha ao publicar mensagem: %v", err)
 	}
@@ -0,0 +1,2086 @@
+package hivemind
+
+import (
+	"context"
+	"crypto/ed25519"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/streadway/amqp"
+
+	"github.com/suissa/HiveMind/agents"
+	"github.com/suissa/HiveMind/agents/batch"
+	"github.com/suissa/HiveMind/agents/blackboard"
+	"github.com/suissa/HiveMind/agents/blob"
+	"github.com/suissa/HiveMind/agents/cache"
+	"github.com/suissa/HiveMind/agents/communication"
+	"github.com/suissa/HiveMind/agents/consensus"
+	"github.com/suissa/HiveMind/agents/contractnet"
+	"github.com/suissa/HiveMind/agents/control"
+	"github.com/suissa/HiveMind/agents/cron"
+	"github.com/suissa/HiveMind/agents/deadline"
+	"github.com/suissa/HiveMind/agents/debate"
+	"github.com/suissa/HiveMind/agents/discovery"
+	"github.com/suissa/HiveMind/agents/errs"
+	"github.com/suissa/HiveMind/agents/i18n"
+	"github.com/suissa/HiveMind/agents/inbox"
+	"github.com/suissa/HiveMind/agents/ingest"
+	"github.com/suissa/HiveMind/agents/llm"
+	"github.com/suissa/HiveMind/agents/maintenance"
+	"github.com/suissa/HiveMind/agents/mcp"
+	"github.com/suissa/HiveMind/agents/memory"
+	"github.com/suissa/HiveMind/agents/outbox"
+	"github.com/suissa/HiveMind/agents/overrides"
+	"github.com/suissa/HiveMind/agents/persona"
+	"github.com/suissa/HiveMind/agents/presence"
+	"github.com/suissa/HiveMind/agents/progress"
+	"github.com/suissa/HiveMind/agents/sheetbatch"
+	"github.com/suissa/HiveMind/agents/shutdown"
+	"github.com/suissa/HiveMind/agents/sinks"
+	"github.com/suissa/HiveMind/agents/skills"
+	"github.com/suissa/HiveMind/agents/sla"
+	"github.com/suissa/HiveMind/agents/stealing"
+	"github.com/suissa/HiveMind/agents/tenant"
+	"github.com/suissa/HiveMind/agents/validation"
+	"github.com/suissa/HiveMind/agents/webhook"
+	"github.com/suissa/HiveMind/api"
+	"github.com/suissa/HiveMind/orchestrator"
+)
+
+// Tipos usados na configuração e na submissão de tarefas do runtime
+type (
+	BusConfig       = communication.ConnectionConfig
+	MessageSigner   = communication.Signer
+	MessageVerifier = communication.Verifier
+	KeyRing         = communication.KeyRing
+	LLMAgent        = agents.LLMAgent
+	TaskRequest     = orchestrator.TaskRequest
+	ToolRegistry    = agents.ToolRegistry
+	ToolPermissions = agents.ToolPermissions
+	ProjectStatus   = agents.ProjectStatus
+	Overrides       = overrides.Overrides
+	OverrideLimits  = overrides.Limits
+	DeletionReport  = memory.DeletionReport
+)
+
+// ManagementAPIConfig configura a API de gerenciamento (WithManagementAPI)
+type ManagementAPIConfig = api.Config
+
+// Crew é uma equipe de agentes registrada no runtime
+type Crew interface {
+	GetProjectStatus() *ProjectStatus
+}
+
+// Option configura o Runtime
+type Option func(*Runtime)
+
+// WithMemoryConfig cria o gerenciador de memória híbrido com a configuração informada em Start
+func WithMemoryConfig(config *MemoryConfig) Option {
+	return func(r *Runtime) {
+		r.memoryConfig = config
+	}
+}
+
+// WithMemoryManager usa um gerenciador de memória já criado (não é fechado pelo runtime)
+func WithMemoryManager(manager MemoryManager) Option {
+	return func(r *Runtime) {
+		r.memory = manager
+	}
+}
+
+// WithBus conecta o runtime ao RabbitMQ em Start. Sem barramento, SubmitTask só
+// funciona em modo dry-run (simulation.WithSession).
+func WithBus(config *BusConfig) Option {
+	return func(r *Runtime) {
+		r.busConfig = config
+	}
+}
+
+// WithManagementAPI inicia em Start a API HTTP de gerenciamento (tarefas, memórias, titulares,
+// aprovações, escalonamento e /metrics) sobre a memória e o roteador do runtime; o servidor para de aceitar requisições
+// no início do encerramento. Start falha se a configuração não tiver autenticação nem
+// Insecure.
+func WithManagementAPI(config ManagementAPIConfig) Option {
+	return func(r *Runtime) {
+		r.apiConfig = &config
+	}
+}
+
+// WithSigning assina as mensagens publicadas nas filas de tarefas e resultados e descarta as
+// consumidas sem assinatura válida: o router assina as subtarefas e verifica a fila de entrada, e
+// os agentes criados com Runtime.NewLLMAgent verificam as subtarefas e assinam os resultados.
+// Os barramentos de WithPresence e WithContractNet podem ser assinados com NewSignedClient.
+func WithSigning(signer MessageSigner, verifier MessageVerifier) Option {
+	return func(r *Runtime) {
+		r.signer = signer
+		r.verifier = verifier
+	}
+}
+
+// WithOutbox publica com o publicador informado (por exemplo, um cliente de
+// agents/communication) os eventos gravados no outbox da memória por
+// CognitiveAgent.MemorizeAndPublish. O relay roda de Start até o encerramento, quando
+// publica o que ainda estiver pendente.
+func WithOutbox(publisher OutboxPublisher, config OutboxConfig) Option {
+	return func(r *Runtime) {
+		r.outboxPublisher = publisher
+		r.outboxConfig = config
+	}
+}
+
+// WithPresence ativa o protocolo de presença no barramento informado (por exemplo, um cliente
+// de agents/communication): de Start até o encerramento, cada agente registrado publica
+// heartbeats com a sua carga, e as equipes registradas marcam como indisponíveis os agentes
+// cujos heartbeats param de chegar, redirecionando as suas tarefas
+func WithPresence(bus PresenceBus, config PresenceConfig) Option {
+	return func(r *Runtime) {
+		r.presenceBus = bus
+		r.presenceConfig = config
+		r.presence = presence.NewMonitor(bus, config)
+	}
+}
+
+// WithContractNet passa a alocar as subtarefas por licitação no barramento informado: o
+// router anuncia cada subtarefa, os agentes registrados dão lances com a sua carga como custo
+// e a taxa de sucesso como confiança (CognitiveAgent.Bid), e o melhor lance executa a tarefa.
+// Subtarefas sem lances seguem para a fila de tarefas.
+func WithContractNet(bus ContractNetBus, config ContractNetConfig) Option {
+	return func(r *Runtime) {
+		r.contracts = contractnet.NewManager(bus, config)
+		r.contractor = contractnet.NewContractor(bus)
+	}
+}
+
+// WithDiscovery ativa a descoberta por gossip para as implantações sem registro central: em
+// Start o runtime entra no cluster pelos seeds da configuração e anuncia as capacidades dos
+// agentes registrados (papel e ferramentas permitidas), e Discovery().FormCrew escolhe
+// agentes de todo o cluster pelos papéis
+func WithDiscovery(config DiscoveryConfig) Option {
+	return func(r *Runtime) {
+		r.discoveryConfig = &config
+	}
+}
+
+// WithDebateArtifacts grava as transcrições dos debates de Runtime.Debate como arquivos JSON
+// no diretório informado
+func WithDebateArtifacts(dir string) Option {
+	return func(r *Runtime) {
+		r.debateStore = debate.DirStore(dir)
+	}
+}
+
+// WithPersonas define a biblioteca de personas usada por Runtime.SwitchPersona
+func WithPersonas(library *PersonaLibrary) Option {
+	return func(r *Runtime) {
+		r.personas = library
+	}
+}
+
+// WithLLM registra um provedor de LLM pelo nome. O primeiro registrado é o padrão.
+func WithLLM(name string, provider LLMProvider) Option {
+	return func(r *Runtime) {
+		if r.defaultLLM == "" {
+			r.defaultLLM = name
+		}
+		r.providers[name] = provider
+	}
+}
+
+// WithDefaultLLM define qual dos provedores registrados é o padrão
+func WithDefaultLLM(name string) Option {
+	return func(r *Runtime) {
+		r.defaultLLM = name
+	}
+}
+
+// WithTools registra ferramentas no registro do runtime
+func WithTools(tools ...Tool) Option {
+	return func(r *Runtime) {
+		r.pendingTools = append(r.pendingTools, tools...)
+	}
+}
+
+// WithSkills carrega em Start as skills (ferramentas WASM ou plugins Go) dos subdiretórios de
+// dir; veja Runtime.LoadSkills
+func WithSkills(dir string) Option {
+	return func(r *Runtime) {
+		r.skillsDir = dir
+	}
+}
+
+// WithMCP conecta o runtime, em Start, aos servidores MCP informados e registra as ferramentas
+// de cada um (com o JSON Schema dos parâmetros) no registro de ferramentas, com o prefixo
+// "<nome do servidor>." por padrão
+func WithMCP(servers ...MCPConfig) Option {
+	return func(r *Runtime) {
+		r.mcpConfigs = append(r.mcpConfigs, servers...)
+	}
+}
+
+// WithHooks anexa hooks de ciclo de vida a todos os agentes registrados no runtime
+func WithHooks(hooks ...Hooks) Option {
+	return func(r *Runtime) {
+		r.hooks = append(r.hooks, hooks...)
+	}
+}
+
+// WithModeration modera a entrada das tarefas e a saída de todos os agentes registrados no
+// runtime; as decisões sobre conteúdo sinalizado são emitidas como EventModeration
+func WithModeration(pipeline *ModerationPipeline) Option {
+	return func(r *Runtime) {
+		r.moderation = pipeline
+	}
+}
+
+// WithApprovals só inicia as tarefas dos tipos informados (todas, sem tipos) dos agentes
+// registrados depois que um revisor as aprova no gate; a API de gerenciamento lista e decide
+// as aprovações pendentes com a permissão approvals:decide, e cada uma é notificada aos
+// webhooks como approval.requested
+func WithApprovals(gate *ApprovalGate, taskTypes ...string) Option {
+	return func(r *Runtime) {
+		r.approvals = gate
+		r.approvalTypes = taskTypes
+	}
+}
+
+// WithScalingController expõe na API de gerenciamento as políticas de escalonamento do
+// controlador (ex.: o OrchestratorInfrastructureAgent), alteradas com a permissão scaling:write
+func WithScalingController(controller api.ScalingController) Option {
+	return func(r *Runtime) {
+		r.scaling = controller
+	}
+}
+
+// WithAnonymizer pseudonimiza os dados pessoais dos prompts dos agentes registrados que não
+// têm um próprio e do conteúdo gravado na memória híbrida do runtime
+func WithAnonymizer(anonymizer *Anonymizer) Option {
+	return func(r *Runtime) {
+		r.anonymizer = anonymizer
+	}
+}
+
+// WithEmbedder vetoriza as memórias da memória híbrida do runtime com o embedder do modelo
+// informado, em vez de deixar o Weaviate vetorizar o conteúdo. Os embeddings ficam em cache no
+// Redis pelo hash do conteúdo.
+func WithEmbedder(embedder Embedder, model string) Option {
+	return func(r *Runtime) {
+		r.embedder = embedder
+		r.embeddingModel = model
+	}
+}
+
+// WithMemoryRanking ordena as memórias incluídas no prompt dos agentes registrados que não
+// têm pesos próprios por similaridade, recência e importância
+func WithMemoryRanking(weights RetrievalWeights) Option {
+	return func(r *Runtime) {
+		r.ranking = &weights
+	}
+}
+
+// WithRecap resume cada workflow ou conversa em grupo concluído das equipes registradas numa
+// memória de longo prazo, recuperada pelos próximos workflows sem repassar a transcrição
+func WithRecap(summarizer RecapSummarizer) Option {
+	return func(r *Runtime) {
+		r.recap = summarizer
+	}
+}
+
+// WithInbox dá a cada agente registrado uma caixa de entrada consumida por demanda, com o papel
+// do agente como tipo das mensagens. As mensagens enviadas por Inbox().Dispatch vão para o
+// agente menos carregado do papel, e os agentes ociosos tomam as dos ocupados. As subtarefas
+// das tarefas recebidas pela fila de entrada (SubmitTask) também vão para as caixas, quando
+// algum agente atende o papel ou o tipo delas.
+func WithInbox(config InboxConfig) Option {
+	return func(r *Runtime) {
+		r.inbox = inbox.New(config)
+	}
+}
+
+// WithWorkStealing redistribui as tarefas das caixas de entrada (WithInbox) entre as
+// instâncias do mesmo papel em processos diferentes: um agente ocioso pede parte da fila da
+// instância mais carregada, conhecida pelos heartbeats de presença (WithPresence), pelo
+// barramento de presença
+func WithWorkStealing(config WorkStealingConfig) Option {
+	return func(r *Runtime) {
+		r.stealingConfig = &config
+	}
+}
+
+// WithBlobStore guarda no armazenamento de objetos (S3, MinIO ou GridFS) as saídas de
+// ferramentas e os conteúdos de memórias que passam de config.Threshold, deixando nas
+// mensagens e nas memórias a prévia com a referência [blob:...]. Os agentes leem o restante
+// com a ferramenta read_blob.
+func WithBlobStore(store BlobStore, config BlobConfig) Option {
+	return func(r *Runtime) {
+		r.blobs = blob.NewManager(store, config)
+	}
+}
+
+// WithControl inscreve os agentes registrados no plano de controle, pelo barramento de
+// presença (WithPresence): o orquestrador (Controller) pode pausar, retomar e drenar a entrada
+// de tarefas de cada instância e mudar o nível dos logs em tempo de execução. A recarga da
+// configuração chama reload, se informado; sem ele, o comando reload é recusado.
+func WithControl(config ControlConfig, reload func(ctx context.Context, agentID string) error) Option {
+	return func(r *Runtime) {
+		r.controlConfig = &config
+		r.reload = reload
+	}
+}
+
+// WithSLA acompanha a duração dos workflows e a latência das tarefas das equipes registradas
+// e do contract net: cada violação dos limites de config vira um evento sla_breach e um alerta
+// para os notificadores (barramento, webhook), enquanto o workflow ou a tarefa ainda executa
+func WithSLA(config SLAConfig, notifiers ...SLANotifier) Option {
+	return func(r *Runtime) {
+		r.slaConfig = &config
+		r.slaNotifiers = append(r.slaNotifiers, notifiers...)
+	}
+}
+
+// WithWebhooks notifica os endpoints sobre os eventos do ciclo de vida (início, conclusão e
+// falha dos workflows, violações de SLA, aprovações pendentes e orçamentos excedidos), com
+// assinatura HMAC e novas tentativas; as entregas pendentes são tentadas mais uma vez na drenagem
+func WithWebhooks(config WebhookConfig, endpoints ...WebhookEndpoint) Option {
+	return func(r *Runtime) {
+		r.webhookConfig = &config
+		r.webhookTargets = append(r.webhookTargets, endpoints...)
+	}
+}
+
+// WithCron agenda execuções periódicas; as expressões usam o fuso de config. Os jobs também
+// podem ser agendados depois, com Schedule e ScheduleCrew.
+func WithCron(config CronConfig, jobs ...CronJob) Option {
+	return func(r *Runtime) {
+		r.cronConfig = config
+		r.cronJobs = append(r.cronJobs, jobs...)
+	}
+}
+
+// WithIngest transforma os registros dos tópicos do Kafka (ou assuntos do NATS) de source em
+// tarefas, pelos templates de configs, e as submete ao roteamento como SubmitTask. O consumo
+// começa ao fim de Start e para junto com a entrada de tarefas.
+func WithIngest(source IngestSource, configs ...IngestConfig) Option {
+	return func(r *Runtime) {
+		r.ingestSource = source
+		r.ingestConfigs = append(r.ingestConfigs, configs...)
+	}
+}
+
+// WithSinks grava os resultados finais dos workflows concluídos nos sinks de cada workflow
+// (pelo nome do projeto; sinks.AllWorkflows vale para todos), como um bucket S3, uma tabela
+// do Postgres, uma planilha do Google Sheets ou um webhook. As escritas pendentes terminam na
+// drenagem, junto com os eventos.
+func WithSinks(config SinkConfig, routes map[string][]ResultSink) Option {
+	return func(r *Runtime) {
+		if r.sinks == nil {
+			r.sinks = sinks.New(config)
+		}
+		for workflow, list := range routes {
+			r.sinks.Add(workflow, list...)
+		}
+	}
+}
+
+// WithDocuments registra a ferramenta generate_document sobre a biblioteca de modelos de
+// documento; com WithBlobStore, os documentos gerados são exportados para o armazenamento de
+// objetos e os agentes recebem a referência
+func WithDocuments(library *DocumentLibrary) Option {
+	return func(r *Runtime) {
+		r.documents = library
+	}
+}
+
+// WithImportanceScorer estima a importância das memórias gravadas com AutoImportance pelos
+// agentes registrados que não têm um avaliador próprio
+func WithImportanceScorer(scorer ImportanceScorer) Option {
+	return func(r *Runtime) {
+		r.scorer = scorer
+	}
+}
+
+// WithResultCache ativa o cache de resultados nos agentes registrados que não têm um próprio
+func WithResultCache(store cache.Store) Option {
+	return func(r *Runtime) {
+		r.resultCache = store
+	}
+}
+
+// WithSemanticCache ativa o cache semântico nos agentes registrados que não têm um próprio.
+// Para desativá-lo em um agente, chame agent.SetSemanticCache(nil) após o registro.
+func WithSemanticCache(store cache.SemanticStore) Option {
+	return func(r *Runtime) {
+		r.semanticCache = store
+	}
+}
+
+// WithOutputValidation valida as saídas das tarefas com ExpectedOutput ou OutputSchema nos
+// agentes registrados que não têm uma validação própria
+func WithOutputValidation(config OutputValidationConfig) Option {
+	return func(r *Runtime) {
+		r.validation = &config
+	}
+}
+
+// WithToolPermissions restringe as ferramentas permitidas por agente ou papel
+func WithToolPermissions(permissions *ToolPermissions) Option {
+	return func(r *Runtime) {
+		r.permissions = permissions
+	}
+}
+
+// WithOverrideLimits define os limites dos overrides por tarefa (modelos permitidos,
+// temperatura e tokens máximos), aplicados na submissão e pelos agentes registrados
+func WithOverrideLimits(limits OverrideLimits) Option {
+	return func(r *Runtime) {
+		r.limits = limits
+	}
+}
+
+// WithTenant define o tenant das filas e das tarefas submetidas
+func WithTenant(id string) Option {
+	return func(r *Runtime) {
+		r.tenant = id
+	}
+}
+
+// WithLocale define o idioma das mensagens dos eventos
+func WithLocale(locale i18n.Locale) Option {
+	return func(r *Runtime) {
+		r.locale = locale
+	}
+}
+
+// WithShutdownTimeout define o prazo para drenar as tarefas em Close
+func WithShutdownTimeout(timeout time.Duration) Option {
+	return func(r *Runtime) {
+		r.shutdownTimeout = timeout
+	}
+}
+
+// WithChaos injeta falhas nos provedores de LLM e na memória do runtime, com as taxas do
+// injetor, para validar retries e fallbacks em testes de resiliência
+func WithChaos(injector *ChaosInjector) Option {
+	return func(r *Runtime) {
+		r.chaos = injector
+	}
+}
+
+// WithLLMBatching agrupa as requisições compatíveis feitas aos provedores de LLM dentro da
+// janela da configuração, usando a API de lote do provedor ou chamadas paralelas limitadas
+func WithLLMBatching(config BatchConfig) Option {
+	return func(r *Runtime) {
+		r.batching = &config
+	}
+}
+
+// WithFairShare reparte o despacho das tarefas das equipes registradas (MarketingCrew) e das
+// adjudicadas por licitação entre os tenants e os workflows, pelas cotas do escalonador
+func WithFairShare(scheduler *FairShareScheduler) Option {
+	return func(r *Runtime) {
+		r.fairShare = scheduler
+	}
+}
+
+// WithFairShareLLM reparte as chamadas aos provedores de LLM entre os tenants e os workflows.
+// Use um escalonador diferente do de WithFairShare.
+func WithFairShareLLM(scheduler *FairShareScheduler) Option {
+	return func(r *Runtime) {
+		r.fairShareLLM = scheduler
+	}
+}
+
+// Runtime reúne os componentes compartilhados de uma aplicação que embute o HiveMind:
+// memória, barramento, provedores de LLM, ferramentas, agentes, equipes e eventos.
+//
+//	rt := hivemind.New(hivemind.WithLLM("groq", provider), hivemind.WithBus(hivemind.BusConfigFromEnv()))
+//	if err := rt.Start(ctx); err != nil { ... }
+//	defer rt.Close(ctx)
+//	rt.RegisterAgent(hivemind.NewCognitiveAgent(..., rt.Memory()))
+//	rt.SubmitTask(ctx, hivemind.TaskRequest{ID: "t-1", Description: "..."})
+type Runtime struct {
+	memoryConfig    *MemoryConfig
+	memory          MemoryManager
+	ownsMemory      bool
+	busConfig       *BusConfig
+	conn            *amqp.Connection
+	router          *orchestrator.LLMRouter
+	signer          MessageSigner
+	verifier        MessageVerifier
+	apiConfig       *ManagementAPIConfig
+	providers       map[string]LLMProvider
+	defaultLLM      string
+	tools           *agents.ToolRegistry
+	pendingTools    []Tool
+	blobs           *BlobManager
+	skillsDir       string
+	mcpConfigs      []MCPConfig
+	mcpClients      map[string]*MCPClient
+	permissions     *ToolPermissions
+	limits          OverrideLimits
+	events          *agents.EventEmitter
+	agents          map[string]*CognitiveAgent
+	hooks           []Hooks
+	moderation      *ModerationPipeline
+	approvals       *ApprovalGate
+	approvalTypes   []string
+	scaling         api.ScalingController
+	anonymizer      *Anonymizer
+	embedder        Embedder
+	embeddingModel  string
+	scorer          ImportanceScorer
+	ranking         *RetrievalWeights
+	recap           RecapSummarizer
+	maintenance     *MaintenanceScheduler
+	outboxPublisher OutboxPublisher
+	outboxConfig    OutboxConfig
+	outbox          *OutboxRelay
+	presenceBus     PresenceBus
+	presenceConfig  PresenceConfig
+	presence        *PresenceMonitor
+	beaconCtx       context.Context // Contexto dos beacons dos agentes, cancelado no encerramento
+	beacons         sync.WaitGroup
+	discoveryConfig *DiscoveryConfig
+	contracts       *ContractNetManager
+	contractor      *contractnet.Contractor
+	inbox           *Inbox
+	inboxCtx        context.Context // Contexto do consumo das caixas de entrada, cancelado no encerramento
+	runCtx          context.Context // Contexto das tarefas das caixas de entrada, cancelado só se a drenagem estourar o prazo
+	stealingConfig  *WorkStealingConfig
+	stealer         *stealing.Stealer
+	serving         map[string]context.CancelFunc // Consumo da caixa de entrada de cada agente
+	controlConfig   *ControlConfig
+	reload          func(ctx context.Context, agentID string) error
+	control         *control.Node
+	controller      *AgentController
+	slaConfig       *SLAConfig
+	slaNotifiers    []SLANotifier
+	sla             *SLAMonitor
+	webhookConfig   *WebhookConfig
+	webhookTargets  []WebhookEndpoint
+	webhooks        *WebhookDispatcher
+	cronConfig      CronConfig
+	cronJobs        []CronJob
+	cron            *CronScheduler
+	ingestSource    IngestSource
+	ingestConfigs   []IngestConfig
+	ingest          *IngestBridge
+	sinks           *SinkRouter
+	documents       *DocumentLibrary
+	discovery       *DiscoveryNode
+	resultCache     cache.Store
+	semanticCache   cache.SemanticStore
+	validation      *validation.Config
+	crews           map[string]Crew
+	boards          map[string]*Blackboard
+	debateStore     debate.Store
+	personas        *PersonaLibrary
+	tenant          string
+	locale          i18n.Locale
+	shutdownTimeout time.Duration
+	chaos           *ChaosInjector
+	batching        *BatchConfig
+	fairShare       *FairShareScheduler
+	fairShareLLM    *FairShareScheduler
+	stopper         *shutdown.Manager
+	started         bool
+	mu              sync.RWMutex
+}
+
+// New cria um Runtime com as opções informadas. Os clientes externos só são
+// conectados em Start.
+func New(opts ...Option) *Runtime {
+	r := &Runtime{
+		providers: make(map[string]LLMProvider),
+		events:    agents.NewEventEmitter(),
+		agents:    make(map[string]*CognitiveAgent),
+		crews:     make(map[string]Crew),
+		boards:    make(map[string]*Blackboard),
+		serving:   make(map[string]context.CancelFunc),
+		tenant:    tenant.DefaultTenant,
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	r.cron = cron.New(r.cronConfig)
+
+	if r.locale != "" {
+		r.events.SetLocale(r.locale)
+	}
+	r.tools = agents.NewToolRegistry(r.permissions, r.events)
+	if r.blobs != nil {
+		r.tools.SetBlobs(r.blobs)
+		r.pendingTools = append(r.pendingTools, agents.NewBlobReadTool(r.blobs))
+	}
+	if r.documents != nil {
+		r.pendingTools = append(r.pendingTools, agents.NewDocumentTool(r.documents, r.blobs))
+	}
+	return r
+}
+
+// BusConfigFromEnv lê a configuração do RabbitMQ das variáveis de ambiente
+func BusConfigFromEnv() *BusConfig {
+	return communication.RabbitMQConfigFromEnv()
+}
+
+// NewHMACSigner cria um MessageSigner HMAC-SHA256 com um segredo compartilhado
+func NewHMACSigner(keyID string, secret []byte) MessageSigner {
+	return communication.NewHMACSigner(keyID, secret)
+}
+
+// NewEd25519Signer cria um MessageSigner com uma chave privada Ed25519
+func NewEd25519Signer(keyID string, key ed25519.PrivateKey) (MessageSigner, error) {
+	signer, err := communication.NewEd25519Signer(keyID, key)
+	if err != nil {
+		return nil, err
+	}
+	return signer, nil
+}
+
+// NewKeyRing cria um KeyRing vazio; as chaves confiáveis são adicionadas com AddHMACKey e
+// AddEd25519Key
+func NewKeyRing() *KeyRing {
+	return communication.NewKeyRing()
+}
+
+// NewSignedClient decora um cliente de agents/communication assinando as publicações e
+// verificando as mensagens consumidas. O signer ou o verifier podem ser nil.
+func NewSignedClient(client communication.CommunicationClient, signer MessageSigner, verifier MessageVerifier) communication.CommunicationClient {
+	return communication.NewSignedClient(client, signer, verifier)
+}
+
+// Start conecta a memória e o barramento e inicia o roteamento de tarefas.
+// Os recursos já abertos são liberados se alguma etapa falhar.
+func (r *Runtime) Start(ctx context.Context) (err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.started {
+		return fmt.Errorf("runtime já iniciado")
+	}
+
+	for _, tool := range r.pendingTools {
+		if err := r.tools.Register(tool); err != nil {
+			return fmt.Errorf("erro ao registrar ferramenta: %v", err)
+		}
+	}
+	r.pendingTools = nil
+
+	if r.defaultLLM != "" {
+		if _, ok := r.providers[r.defaultLLM]; !ok {
+			return fmt.Errorf("provedor de LLM padrão não registrado: %s", r.defaultLLM)
+		}
+	}
+
+	// Provedores locais (Ollama, vLLM): o servidor deve responder e o modelo padrão estar
+	// disponível, baixando-o quando o provedor permite
+	for name, provider := range r.providers {
+		local, ok := provider.(llm.LocalProvider)
+		if !ok {
+			continue
+		}
+		if err := local.Health(ctx); err != nil {
+			return fmt.Errorf("provedor de LLM %s indisponível: %w", name, err)
+		}
+		if model := local.DefaultModel(); model != "" {
+			if err := local.EnsureModel(ctx, model); err != nil {
+				return fmt.Errorf("provedor de LLM %s sem o modelo %s: %w", name, model, err)
+			}
+		}
+	}
+	for name, provider := range r.providers {
+		if r.chaos != nil {
+			provider = r.chaos.LLM(provider)
+		}
+		if r.batching != nil {
+			provider = batch.New(provider, *r.batching)
+		}
+		if r.fairShareLLM != nil {
+			provider = agents.FairShareLLM(r.fairShareLLM, provider)
+		}
+		r.providers[name] = provider
+	}
+
+	runCtx, cancel := context.WithCancel(context.Background())
+	r.stopper = shutdown.New(r.shutdownTimeout)
+	r.stopper.SetAbort(cancel)
+	r.stopper.OnFlush("events", r.events.Flush)
+	defer func() {
+		if err != nil {
+			r.stopper.Shutdown(context.Background())
+		}
+	}()
+
+	if r.skillsDir != "" {
+		if _, err := r.loadSkills(ctx, r.skillsDir); err != nil {
+			return err
+		}
+	}
+	for _, config := range r.mcpConfigs {
+		if err := r.connectMCP(ctx, config); err != nil {
+			return err
+		}
+	}
+
+	// Memória
+	if r.memory == nil && r.memoryConfig != nil {
+		manager, err := NewMemoryManager(ctx, r.memoryConfig)
+		if err != nil {
+			return fmt.Errorf("erro ao criar gerenciador de memória: %v", err)
+		}
+		r.memory = manager
+		r.ownsMemory = true
+	}
+	if manager, ok := r.memory.(interface{ SetAnonymizer(*Anonymizer) }); ok && r.anonymizer != nil {
+		manager.SetAnonymizer(r.anonymizer)
+	}
+	if manager, ok := r.memory.(interface{ SetEmbedder(Embedder, string) }); ok && r.embedder != nil {
+		manager.SetEmbedder(r.embedder, r.embeddingModel)
+	}
+	if manager, ok := r.memory.(interface{ SetBlobs(*BlobManager) }); ok && r.blobs != nil {
+		manager.SetBlobs(r.blobs)
+	}
+	if manager, ok := r.memory.(memory.Observable); ok {
+		manager.SetObserver(r.emitMemoryOperation)
+	}
+	// Manutenção automática configurada em MemoryConfig.Maintenance: os agentes registrados
+	// são agendados com o intervalo padrão ou o definido para cada um
+	if manager, ok := r.memory.(memory.Maintainer); ok {
+		scheduler, err := manager.StartMaintenance(runCtx, r.emitMaintenance)
+		if err != nil {
+			return fmt.Errorf("erro ao iniciar a manutenção da memória: %v", err)
+		}
+		if scheduler != nil {
+			for id := range r.agents {
+				scheduler.Schedule(id, 0)
+			}
+			r.maintenance = scheduler
+			r.stopper.OnStopIntake("memory_maintenance", func(ctx context.Context) error {
+				manager.StopMaintenance()
+				return nil
+			})
+		}
+	}
+	// Relay do outbox: a última varredura acontece no encerramento, antes de a memória fechar
+	if r.outboxPublisher != nil {
+		store, ok := r.memory.(memory.EventStore)
+		if !ok {
+			return fmt.Errorf("o gerenciador de memória não tem outbox")
+		}
+		relay := outbox.NewRelay(store.Outbox(), r.outboxPublisher, r.outboxConfig)
+		relayCtx, stopRelay := context.WithCancel(runCtx)
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			relay.Run(relayCtx)
+		}()
+		r.outbox = relay
+		r.stopper.OnFlush("outbox", func(ctx context.Context) error {
+			stopRelay()
+			<-done
+			_, err := relay.Flush(ctx)
+			return err
+		})
+	}
+	// Plano de controle: criado antes dos beacons, que informam a situação administrativa
+	// dos agentes nos heartbeats
+	if r.controlConfig != nil {
+		if r.presence == nil {
+			return fmt.Errorf("o plano de controle requer WithPresence")
+		}
+		r.control = r.newControlNode()
+	}
+	// Presença: os agentes publicam os heartbeats e o monitor acompanha os de todos. No
+	// encerramento os beacons publicam o heartbeat de saída antes de a intake parar.
+	if r.presence != nil {
+		if err := r.presence.Start(runCtx); err != nil {
+			return err
+		}
+		beaconCtx, stopBeacons := context.WithCancel(runCtx)
+		r.beaconCtx = beaconCtx
+		for _, agent := range r.agents {
+			r.startBeacon(agent)
+		}
+		r.stopper.OnStopIntake("presence", func(ctx context.Context) error {
+			stopBeacons()
+			r.beacons.Wait()
+			return nil
+		})
+	}
+	// Descoberta: o nó sai do cluster junto com a intake, para que os outros nós deixem de
+	// escolher os agentes deste processo
+	if r.discoveryConfig != nil {
+		node, err := discovery.New(*r.discoveryConfig)
+		if err != nil {
+			return err
+		}
+		r.discovery = node
+		r.advertise()
+		r.stopper.OnStopIntake("discovery", func(ctx context.Context) error {
+			timeout := discovery.DefaultLeaveTimeout
+			if deadline, ok := ctx.Deadline(); ok {
+				timeout = time.Until(deadline)
+			}
+			return node.Leave(timeout)
+		})
+	}
+	// Contract net: os agentes deste processo param de dar lances junto com a intake, e as
+	// tarefas adjudicadas são drenadas como as demais
+	if r.contractor != nil {
+		for _, agent := range r.agents {
+			r.contractor.Register(agent.GetID(), agent.Bid, r.executeAward(agent))
+		}
+		if err := r.contractor.Start(runCtx); err != nil {
+			return err
+		}
+		r.stopper.OnStopIntake("contract_net", func(ctx context.Context) error {
+			r.contractor.Stop()
+			return nil
+		})
+	}
+	// Caixas de entrada: os agentes param de consumir junto com a intake, e as tarefas em
+	// andamento são drenadas como as demais
+	if r.inbox != nil {
+		inboxCtx, stopInbox := context.WithCancel(runCtx)
+		r.inboxCtx, r.runCtx = inboxCtx, runCtx
+		for _, agent := range r.agents {
+			r.serveInbox(agent)
+		}
+		r.stopper.OnStopIntake("inbox", func(ctx context.Context) error {
+			stopInbox()
+			return nil
+		})
+	}
+	// Redistribuição entre instâncias: os agentes ociosos pedem tarefas às instâncias do mesmo
+	// papel em outros processos, pela fila informada nos heartbeats
+	if r.stealingConfig != nil {
+		if r.inbox == nil || r.presence == nil {
+			return fmt.Errorf("a redistribuição entre instâncias requer WithInbox e WithPresence")
+		}
+		stealer := stealing.New(r.inbox, r.presenceBus, r.presence, *r.stealingConfig)
+		for id, agent := range r.agents {
+			if err := stealer.Add(id, agent.GetRole()); err != nil {
+				return err
+			}
+		}
+		stealCtx, stopStealing := context.WithCancel(runCtx)
+		go stealer.Run(stealCtx)
+		r.stealer = stealer
+		r.stopper.OnStopIntake("work_stealing", func(ctx context.Context) error {
+			stopStealing()
+			return nil
+		})
+	}
+	// Plano de controle: os agentes param de atender comandos junto com a intake
+	if r.control != nil {
+		for id := range r.agents {
+			if err := r.control.Add(id); err != nil {
+				return err
+			}
+		}
+		r.controller = control.NewController(r.presenceBus, r.presence, *r.controlConfig)
+		if err := r.controller.Start(runCtx); err != nil {
+			return err
+		}
+		node := r.control
+		r.stopper.OnStopIntake("control", func(ctx context.Context) error {
+			node.Stop()
+			return nil
+		})
+	}
+	// SLA: os eventos das equipes e do contract net alimentam o monitor, e os alertas também
+	// viram eventos, até as tarefas em andamento serem drenadas
+	if r.slaConfig != nil {
+		monitor := sla.New(*r.slaConfig, r.slaNotifiers...)
+		events := r.events
+		monitor.AddNotifier(sla.NotifierFunc(func(ctx context.Context, alert sla.Alert) error {
+			events.Emit(agents.SLAEvent(alert))
+			return nil
+		}))
+		r.events.OnAny(agents.SLAListener(monitor))
+		slaCtx, stopSLA := context.WithCancel(runCtx)
+		go monitor.Run(slaCtx)
+		r.sla = monitor
+		r.stopper.OnFlush("sla", func(ctx context.Context) error {
+			stopSLA()
+			return nil
+		})
+	}
+	if r.sinks != nil {
+		r.events.OnAny(agents.SinkListener(r.sinks))
+	}
+	// Webhooks: os eventos do ciclo de vida viram entregas, tentadas até o fim da drenagem
+	if r.webhookConfig != nil {
+		dispatcher := webhook.New(*r.webhookConfig)
+		for _, endpoint := range r.webhookTargets {
+			if err := dispatcher.Add(endpoint); err != nil {
+				return err
+			}
+		}
+		r.events.OnAny(agents.WebhookListener(dispatcher))
+		webhookCtx, stopWebhooks := context.WithCancel(runCtx)
+		go dispatcher.Run(webhookCtx)
+		r.webhooks = dispatcher
+		r.stopper.OnFlush("webhooks", func(ctx context.Context) error {
+			stopWebhooks()
+			_, err := dispatcher.Flush(ctx)
+			return err
+		})
+	}
+	// Execuções periódicas: param de ser disparadas junto com a entrada de tarefas, e as que
+	// estão em andamento são aguardadas na drenagem
+	for _, job := range r.cronJobs {
+		if err := r.schedule(job); err != nil {
+			return err
+		}
+	}
+	r.cronJobs = nil
+	r.cron.OnRun(r.emitCron)
+	go r.cron.Run(runCtx)
+	scheduler := r.cron
+	r.stopper.OnStopIntake("cron", func(ctx context.Context) error {
+		scheduler.Stop()
+		return nil
+	})
+	// As falhas afetam só as operações dos agentes: a manutenção e o outbox usam o gerenciador original
+	if r.chaos != nil && r.memory != nil {
+		r.memory = r.chaos.Memory(r.memory)
+	}
+	if closer, ok := r.memory.(interface{ Close(context.Context) error }); ok && r.ownsMemory {
+		r.stopper.OnClose("memory", closer.Close)
+	}
+
+	// Barramento e roteamento de tarefas
+	if r.busConfig != nil {
+		conn, dialErr := communication.DialRabbitMQ(r.busConfig)
+		if dialErr != nil {
+			return dialErr
+		}
+		r.conn = conn
+		r.stopper.OnClose("rabbitmq", func(ctx context.Context) error {
+			return conn.Close()
+		})
+	}
+
+	var router *orchestrator.LLMRouter
+	if r.conn != nil {
+		router, err = orchestrator.NewTenantLLMRouter(r.conn, r.tenant)
+	} else {
+		router, err = orchestrator.NewDryRunLLMRouter(r.tenant)
+	}
+	if err != nil {
+		return fmt.Errorf("erro ao criar LLMRouter: %v", err)
+	}
+	r.router = router
+	router.SetShutdown(r.stopper)
+	router.SetSigning(r.signer, r.verifier)
+	router.SetOverrideLimits(r.limits)
+	router.SetLLM(r.providers[r.defaultLLM])
+	if r.contracts != nil {
+		router.SetContractNet(r.contracts)
+	}
+	if r.inbox != nil {
+		router.SetInbox(r.inbox)
+	}
+	// Ingestão: para antes do roteador, para que nenhum registro chegue com a fila fechada
+	if r.ingestSource != nil {
+		defaultTenant := r.tenant
+		bridge, err := ingest.New(r.ingestSource, func(ctx context.Context, task TaskRequest) error {
+			if _, ok := tenant.Lookup(ctx); task.Tenant == "" && !ok {
+				task.Tenant = defaultTenant
+			}
+			return router.SubmitTask(ctx, task)
+		}, r.ingestConfigs...)
+		if err != nil {
+			return err
+		}
+		r.ingest = bridge
+		r.stopper.OnStopIntake("ingest", bridge.Stop)
+	}
+	// API de gerenciamento: para de aceitar requisições antes do roteador
+	if r.apiConfig != nil {
+		server, err := r.apiConfig.NewServer(r.memory, router)
+		if err != nil {
+			return err
+		}
+		if r.approvals != nil {
+			server.SetApprovals(r.approvals)
+		}
+		if r.scaling != nil {
+			server.SetScaling(r.scaling)
+		}
+		if err := server.Start(); err != nil {
+			return err
+		}
+		r.stopper.OnStopIntake("management_api", server.Shutdown)
+	}
+	r.stopper.OnStopIntake("llm_router", router.StopIntake)
+	r.stopper.OnClose("llm_router", func(ctx context.Context) error {
+		return router.Close()
+	})
+
+	if r.conn != nil {
+		if err := router.Start(runCtx); err != nil {
+			return err
+		}
+	}
+	if r.ingest != nil {
+		if err := r.ingest.Start(runCtx); err != nil {
+			return err
+		}
+	}
+
+	r.started = true
+	return nil
+}
+
+// Close encerra o runtime: para de receber tarefas, aguarda as em andamento,
+// descarrega os eventos e fecha os clientes abertos em Start
+func (r *Runtime) Close(ctx context.Context) error {
+	r.mu.Lock()
+	stopper := r.stopper
+	r.started = false
+	r.mu.Unlock()
+
+	if stopper == nil {
+		return nil
+	}
+	return stopper.Shutdown(ctx)
+}
+
+// RegisterAgent registra um agente cognitivo. Agentes sem provedor de LLM recebem o padrão
+// do runtime, e os hooks do runtime são anexados aos do agente.
+func (r *Runtime) RegisterAgent(agent *CognitiveAgent) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	id := agent.GetID()
+	if id == "" {
+		return fmt.Errorf("agente sem ID")
+	}
+	if _, exists := r.agents[id]; exists {
+		return fmt.Errorf("agente %s já registrado", id)
+	}
+
+	if agent.LLM() == nil {
+		if provider, ok := r.providers[r.defaultLLM]; ok {
+			agent.SetLLM(provider)
+		}
+	}
+	if agent.ResultCache() == nil && r.resultCache != nil {
+		agent.SetResultCache(r.resultCache)
+	}
+	if agent.SemanticCache() == nil && r.semanticCache != nil {
+		agent.SetSemanticCache(r.semanticCache)
+	}
+	if agent.OutputValidation() == nil && r.validation != nil {
+		agent.SetOutputValidation(*r.validation)
+	}
+	if agent.Anonymizer() == nil && r.anonymizer != nil {
+		agent.SetAnonymizer(r.anonymizer)
+	}
+	if agent.ImportanceScorer() == nil && r.scorer != nil {
+		agent.SetImportanceScorer(r.scorer)
+	}
+	if agent.MemoryRanking == nil && r.ranking != nil {
+		if err := agent.SetMemoryRanking(r.ranking); err != nil {
+			return fmt.Errorf("pesos de ranking de memórias inválidos: %w", err)
+		}
+	}
+	agent.SetToolRegistry(r.tools)
+	agent.SetOverrideLimits(r.limits)
+	agent.AddHooks(r.hooks...)
+	if r.moderation != nil {
+		agent.AddHooks(agents.ModerationHooks(r.moderation, r.events))
+	}
+	if r.approvals != nil {
+		agent.AddHooks(agents.ApprovalHooks(r.approvals, r.events, r.approvalTypes...))
+	}
+	if r.maintenance != nil {
+		r.maintenance.Schedule(id, 0)
+	}
+	if r.beaconCtx != nil {
+		r.startBeacon(agent)
+	}
+	if r.contractor != nil {
+		r.contractor.Register(id, agent.Bid, r.executeAward(agent))
+	}
+	if r.stealer != nil {
+		if err := r.stealer.Add(id, agent.GetRole()); err != nil {
+			return err
+		}
+	}
+	if r.inbox != nil {
+		r.inbox.Register(id, agent.GetRole())
+		if r.inboxCtx != nil {
+			r.serveInbox(agent)
+		}
+	}
+	if r.control != nil && r.controller != nil {
+		if err := r.control.Add(id); err != nil {
+			return err
+		}
+	}
+	r.agents[id] = agent
+	if r.discovery != nil {
+		r.advertise()
+	}
+	return nil
+}
+
+// executeAward executa com o agente as tarefas adjudicadas a ele, registrando-as no
+// coordenador de encerramento
+func (r *Runtime) executeAward(agent *CognitiveAgent) contractnet.Executor {
+	return func(ctx context.Context, award contractnet.Award) error {
+		done, err := r.stopper.Track()
+		if err != nil {
+			return err
+		}
+		defer done()
+
+		announcement := award.Announcement
+		task := agents.NewTask(announcement.TaskID, announcement.Type, announcement.Description, announcement.Parameters)
+		task.Overrides = announcement.Overrides
+		// O prazo dos parâmetros do anúncio é aplicado pelo agente em Run
+		if task.Deadline, err = deadline.FromParameters(announcement.Parameters, time.Now()); err != nil {
+			return err
+		}
+		r.emitAward(task, agent, "task_awarded", award.Bids)
+		ctx = progress.WithReporter(ctx, r.progressReporter(task, agent))
+		if r.fairShare != nil {
+			release, err := r.fairShare.Acquire(ctx, agents.FairShareFlow(ctx))
+			if err != nil {
+				return err
+			}
+			defer release()
+		}
+		if _, err := agent.Run(ctx, task); err != nil {
+			return err
+		}
+		r.emitAward(task, agent, "task_complete", award.Bids)
+		return nil
+	}
+}
+
+// emitAward emite o evento de uma tarefa adjudicada por licitação
+func (r *Runtime) emitAward(task *agents.Task, agent *CognitiveAgent, action string, bids int) {
+	r.events.Emit(agents.Event{
+		Type:      agents.EventTaskUpdate,
+		Timestamp: time.Now(),
+		Source:    "contract_net",
+		Data: map[string]interface{}{
+			"action":      action,
+			"task_id":     task.ID,
+			"task_name":   task.Description,
+			"assigned_to": agent.GetID(),
+			"bids":        bids,
+		},
+	})
+}
+
+// progressReporter emite o andamento informado por uma tarefa adjudicada (progress.Report)
+func (r *Runtime) progressReporter(task *agents.Task, agent *CognitiveAgent) progress.Reporter {
+	return progress.ReporterFunc(func(percent float64, message string) {
+		r.events.Emit(agents.Event{
+			Type:      agents.EventTaskUpdate,
+			Timestamp: time.Now(),
+			Source:    "contract_net",
+			Data: map[string]interface{}{
+				"action":      "task_progress",
+				"task_id":     task.ID,
+				"task_name":   task.Description,
+				"assigned_to": agent.GetID(),
+				"percent":     percent,
+				"message":     message,
+			},
+		})
+	})
+}
+
+// serveInbox consome a caixa de entrada do agente até o encerramento, registrando as tarefas
+// no coordenador de encerramento. Deve ser chamado com r.mu travado.
+func (r *Runtime) serveInbox(agent *CognitiveAgent) {
+	handler := agents.InboxHandler(agent)
+	runCtx, stopper := r.runCtx, r.stopper
+	// Cada agente tem o próprio contexto de consumo, para que o plano de controle o pause
+	inboxCtx, stop := context.WithCancel(r.inboxCtx)
+	r.serving[agent.GetID()] = stop
+	go r.inbox.Serve(inboxCtx, agent.GetID(), func(ctx context.Context, delivery InboxDelivery) error {
+		done, err := stopper.Track()
+		if err != nil {
+			return err
+		}
+		defer done()
+		// A tarefa usa o contexto da entrega, mas não é interrompida quando o consumo para
+		// (encerramento ou pausa): ela é drenada, e só o fim do runtime a cancela
+		taskCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+		defer cancel()
+		defer context.AfterFunc(runCtx, cancel)()
+		return handler(taskCtx, delivery)
+	})
+}
+
+// advertise anuncia no cluster as capacidades dos agentes registrados. Deve ser chamado com r.mu travado.
+func (r *Runtime) advertise() {
+	capabilities := make([]discovery.Capability, 0, len(r.agents))
+	for _, agent := range r.agents {
+		capabilities = append(capabilities, discovery.Capability{
+			AgentID: agent.GetID(),
+			Name:    agent.GetName(),
+			Role:    agent.GetRole(),
+			Tools:   r.tools.Allowed(agent.AgentStruct),
+		})
+	}
+	r.discovery.Advertise(capabilities...)
+}
+
+// startBeacon publica os heartbeats do agente até o encerramento. Deve ser chamado com r.mu travado.
+func (r *Runtime) startBeacon(agent *CognitiveAgent) {
+	load := agent.Load
+	if r.inbox != nil {
+		// As tarefas na caixa de entrada orientam a redistribuição entre instâncias
+		box, id := r.inbox, agent.GetID()
+		load = func() presence.Load {
+			current := agent.Load()
+			if stats, ok := box.AgentStats(id); ok {
+				current.Queued = stats.Pending
+			}
+			return current
+		}
+	}
+	beacon := presence.NewBeacon(r.presenceBus, agent.Heartbeat(), load, r.presenceConfig)
+	if node, id := r.control, agent.GetID(); node != nil {
+		beacon.SetControl(func() presence.Control { return node.Control(id) })
+	}
+	r.beacons.Add(1)
+	go func() {
+		defer r.beacons.Done()
+		beacon.Run(r.beaconCtx)
+	}()
+}
+
+// newControlNode cria o nó do plano de controle com as ações aplicadas aos agentes locais
+func (r *Runtime) newControlNode() *control.Node {
+	node := control.NewNode(r.presenceBus)
+	node.Handle(control.ActionPause, func(ctx context.Context, agentID string, _ ControlCommand) error {
+		return r.pauseAgent(agentID)
+	})
+	node.Handle(control.ActionResume, func(ctx context.Context, agentID string, _ ControlCommand) error {
+		return r.resumeAgent(agentID)
+	})
+	node.Handle(control.ActionDrain, func(ctx context.Context, agentID string, _ ControlCommand) error {
+		if err := r.pauseAgent(agentID); err != nil {
+			return err
+		}
+		return r.waitIdle(ctx, agentID)
+	})
+	if r.reload != nil {
+		node.Handle(control.ActionReload, func(ctx context.Context, agentID string, _ ControlCommand) error {
+			return r.reload(ctx, agentID)
+		})
+	}
+	return node
+}
+
+// pauseAgent para a entrada de tarefas do agente (caixa de entrada, licitações e
+// redistribuição entre instâncias) sem interromper as tarefas em andamento. As mensagens já
+// na caixa do agente podem ser tomadas pelos outros agentes do papel.
+func (r *Runtime) pauseAgent(agentID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.agents[agentID]; !ok {
+		return fmt.Errorf("agente não registrado: %s", agentID)
+	}
+	if stop, ok := r.serving[agentID]; ok {
+		stop()
+		delete(r.serving, agentID)
+	}
+	if r.contractor != nil {
+		r.contractor.Unregister(agentID)
+	}
+	if r.stealer != nil {
+		r.stealer.Remove(agentID)
+	}
+	return nil
+}
+
+// resumeAgent reabre a entrada de tarefas do agente pausado ou drenado
+func (r *Runtime) resumeAgent(agentID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	agent, ok := r.agents[agentID]
+	if !ok {
+		return fmt.Errorf("agente não registrado: %s", agentID)
+	}
+	if r.stopper.Draining() {
+		return shutdown.ErrShuttingDown
+	}
+	if _, serving := r.serving[agentID]; !serving && r.inbox != nil && r.inboxCtx != nil {
+		r.serveInbox(agent)
+	}
+	if r.contractor != nil {
+		r.contractor.Register(agentID, agent.Bid, r.executeAward(agent))
+	}
+	if r.stealer != nil {
+		if err := r.stealer.Add(agentID, agent.GetRole()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// waitIdle aguarda o agente concluir as tarefas em andamento, inclusive as recebidas da
+// caixa de entrada e ainda não confirmadas
+func (r *Runtime) waitIdle(ctx context.Context, agentID string) error {
+	agent, ok := r.Agent(agentID)
+	if !ok {
+		return fmt.Errorf("agente não registrado: %s", agentID)
+	}
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		idle := agent.Load().Running == 0
+		if r.inbox != nil {
+			if stats, ok := r.inbox.AgentStats(agentID); ok && stats.InFlight > 0 {
+				idle = false
+			}
+		}
+		if idle {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return errs.FromContext("runtime.Drain", ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// Agent retorna um agente registrado
+func (r *Runtime) Agent(id string) (*CognitiveAgent, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	agent, ok := r.agents[id]
+	return agent, ok
+}
+
+// Export exporta os agentes registrados, em ordem de ID, com as ferramentas do runtime no
+// formato informado ("langchain" ou "llamaindex")
+func (r *Runtime) Export(format string) ([]byte, error) {
+	r.mu.RLock()
+	ids := make([]string, 0, len(r.agents))
+	for id := range r.agents {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	list := make([]*CognitiveAgent, 0, len(ids))
+	for _, id := range ids {
+		list = append(list, r.agents[id])
+	}
+	r.mu.RUnlock()
+	return agents.ExportAgents(format, r.tools, list...)
+}
+
+// RegisterCrew registra uma equipe pelo nome. Os eventos das equipes conhecidas
+// (marketing e treinamento) são repassados ao emissor do runtime e, com WithPresence,
+// elas acompanham os heartbeats dos seus agentes.
+func (r *Runtime) RegisterCrew(name string, crew Crew) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.crews[name]; exists {
+		return fmt.Errorf("equipe %s já registrada", name)
+	}
+
+	switch c := crew.(type) {
+	case *MarketingCrew:
+		c.OnAnyEvent(r.events.Emit)
+		if r.presence != nil {
+			c.WatchPresence(r.presence)
+		}
+		if r.fairShare != nil {
+			c.Use(agents.FairShareTaskMiddleware(r.fairShare))
+		}
+		if r.recap != nil && c.Recap() == nil {
+			c.SetRecap(r.recap)
+		}
+	case *TrainingCrew:
+		c.OnAnyEvent(r.events.Emit)
+		if r.presence != nil {
+			c.WatchPresence(r.presence)
+		}
+		if r.fairShare != nil {
+			c.Use(agents.FairShareTaskMiddleware(r.fairShare))
+		}
+	}
+	r.crews[name] = crew
+	return nil
+}
+
+// Crew retorna uma equipe registrada
+func (r *Runtime) Crew(name string) (Crew, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	crew, ok := r.crews[name]
+	return crew, ok
+}
+
+// Blackboard retorna o quadro compartilhado com o nome informado, criando-o no primeiro uso.
+// Os agentes do processo coordenam-se pelo quadro com BlackboardSource e
+// NewBlackboardController, em vez de delegarem tarefas pelas filas.
+func (r *Runtime) Blackboard(name string) *Blackboard {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	board, ok := r.boards[name]
+	if !ok {
+		board = blackboard.New(name)
+		r.boards[name] = board
+	}
+	return board
+}
+
+// LoadSkills carrega as skills dos subdiretórios de dir que têm um skill.yaml e as registra
+// como ferramentas, com o runtime em execução: os operadores adicionam capacidades sem
+// recompilar. Os papéis e agentes das permissões de cada manifesto são liberados na matriz de
+// ferramentas. Retorna os nomes das skills carregadas.
+func (r *Runtime) LoadSkills(ctx context.Context, dir string) ([]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.started {
+		return nil, fmt.Errorf("runtime não iniciado")
+	}
+	return r.loadSkills(ctx, dir)
+}
+
+// loadSkills carrega e registra as skills; chamado com r.mu travado
+func (r *Runtime) loadSkills(ctx context.Context, dir string) ([]string, error) {
+	loaded, err := skills.LoadDir(ctx, dir)
+	if err != nil {
+		return nil, err
+	}
+	closeAll := func(ctx context.Context) error {
+		for _, skill := range loaded {
+			skill.Close(ctx)
+		}
+		return nil
+	}
+
+	// Verifica os conflitos antes de registrar para não deixar skills fechadas no registro
+	for _, skill := range loaded {
+		if _, exists := r.tools.Get(skill.Name()); exists {
+			closeAll(ctx)
+			return nil, fmt.Errorf("erro ao registrar skill: ferramenta já registrada: %s", skill.Name())
+		}
+	}
+	names := make([]string, 0, len(loaded))
+	for _, skill := range loaded {
+		if err := r.tools.Register(skill); err != nil {
+			closeAll(ctx)
+			return nil, fmt.Errorf("erro ao registrar skill: %v", err)
+		}
+		permissions := skill.Manifest().Permissions
+		r.tools.GrantTool(skill.Name(), permissions.Roles, permissions.Agents)
+		names = append(names, skill.Name())
+	}
+	r.stopper.OnClose("skills", closeAll)
+	return names, nil
+}
+
+// connectMCP conecta-se ao servidor MCP e registra as suas ferramentas; chamado com r.mu travado
+func (r *Runtime) connectMCP(ctx context.Context, config MCPConfig) error {
+	client, err := mcp.Connect(ctx, config)
+	if err != nil {
+		return err
+	}
+	r.stopper.OnClose("mcp_"+config.Name, func(ctx context.Context) error {
+		return client.Close()
+	})
+
+	tools, err := client.Tools(ctx)
+	if err != nil {
+		return fmt.Errorf("erro ao listar as ferramentas do servidor MCP %s: %w", config.Name, err)
+	}
+	for _, tool := range tools {
+		if err := r.tools.Register(tool); err != nil {
+			return fmt.Errorf("erro ao registrar ferramenta MCP: %v", err)
+		}
+	}
+	if r.mcpClients == nil {
+		r.mcpClients = make(map[string]*MCPClient)
+	}
+	r.mcpClients[config.Name] = client
+	return nil
+}
+
+// MCP retorna o cliente do servidor MCP conectado com o nome informado, para listar e ler
+// recursos diretamente
+func (r *Runtime) MCP(name string) (*MCPClient, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	client, ok := r.mcpClients[name]
+	return client, ok
+}
+
+// MCPServer cria um servidor MCP que expõe as ferramentas do runtime a assistentes externos,
+// com as permissões da identidade configurada (stdio) ou do token de cada cliente (HTTP)
+func (r *Runtime) MCPServer(config MCPServerConfig) *MCPServer {
+	return agents.NewMCPServer(r.tools, config)
+}
+
+// Personas retorna a biblioteca de personas do runtime, criando uma vazia no primeiro uso
+func (r *Runtime) Personas() *PersonaLibrary {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.personas == nil {
+		r.personas, _ = persona.NewLibrary()
+	}
+	return r.personas
+}
+
+// SwitchPersona troca a persona de um agente registrado pela composição das personas da
+// biblioteca, com o agente em execução, e emite um EventAgentAction "persona_switched"
+func (r *Runtime) SwitchPersona(agentID string, names ...string) error {
+	agent, ok := r.Agent(agentID)
+	if !ok {
+		return fmt.Errorf("agente %s não registrado", agentID)
+	}
+	p, err := r.Personas().Compose(names...)
+	if err != nil {
+		return err
+	}
+	previous := agent.Persona()
+	agent.SetPersona(p)
+
+	r.events.Emit(agents.Event{
+		Type:      agents.EventAgentAction,
+		Timestamp: time.Now(),
+		Source:    agentID,
+		Data: map[string]interface{}{
+			"action":     "persona_switched",
+			"agent_name": agent.GetName(),
+			"persona":    p.Name,
+			"previous":   previous,
+		},
+	})
+	return nil
+}
+
+// Vote submete a pergunta aos agentes registrados informados, que respondem de forma
+// independente, e agrega os votos. O resultado registra a divergência de cada agente e é
+// emitido no evento "vote_decided".
+func (r *Runtime) Vote(ctx context.Context, question VoteQuestion, aggregator VoteAggregator, agentIDs ...string) (*VoteResult, error) {
+	voters := make([]consensus.Voter, 0, len(agentIDs))
+	for _, id := range agentIDs {
+		agent, ok := r.Agent(id)
+		if !ok {
+			return nil, fmt.Errorf("agente %s não registrado", id)
+		}
+		voters = append(voters, agents.Voter(agent))
+	}
+
+	result, err := consensus.Vote(ctx, question, voters, aggregator)
+	if err != nil {
+		return nil, err
+	}
+	dissenters := make([]string, len(result.Dissent))
+	for i, dissent := range result.Dissent {
+		dissenters[i] = dissent.VoterID
+	}
+	r.events.Emit(agents.Event{
+		Type:      agents.EventAgentAction,
+		Timestamp: time.Now(),
+		Source:    "consensus",
+		Data: map[string]interface{}{
+			"action":     "vote_decided",
+			"question":   question.Text,
+			"answer":     result.Decision.Answer,
+			"agreement":  result.Agreement * 100,
+			"voters":     len(voters),
+			"dissenters": strings.Join(dissenters, ", "),
+		},
+	})
+	return &result, nil
+}
+
+// Debate conduz um debate entre os agentes registrados — proponente, crítico e juiz — e emite
+// um EventAgentAction com a resposta final. Sem Store na configuração, a transcrição é gravada
+// no diretório de WithDebateArtifacts.
+func (r *Runtime) Debate(ctx context.Context, question string, config DebateConfig, proposerID, criticID, judgeID string) (*DebateTranscript, error) {
+	participants := make([]*CognitiveAgent, 0, 3)
+	for _, id := range []string{proposerID, criticID, judgeID} {
+		agent, ok := r.Agent(id)
+		if !ok {
+			return nil, fmt.Errorf("agente %s não registrado", id)
+		}
+		participants = append(participants, agent)
+	}
+	if config.Store == nil {
+		config.Store = r.debateStore
+	}
+
+	transcript, err := agents.Debate(ctx, question, participants[0], participants[1], participants[2], config)
+	if err != nil {
+		return transcript, err
+	}
+	r.events.Emit(agents.Event{
+		Type:      agents.EventAgentAction,
+		Timestamp: time.Now(),
+		Source:    "debate",
+		Data: map[string]interface{}{
+			"action":   "debate_decided",
+			"question": question,
+			"answer":   transcript.Verdict.Answer,
+			"rounds":   transcript.Rounds,
+			"judge":    judgeID,
+			"artifact": transcript.Artifact,
+		},
+	})
+	return transcript, nil
+}
+
+// GroupChat conduz uma conversa em grupo entre os agentes registrados e emite um
+// EventAgentAction com o motivo do término. Sem seletor na configuração, os agentes falam
+// em rodízio na ordem dos IDs.
+func (r *Runtime) GroupChat(ctx context.Context, topic string, config GroupChatConfig, agentIDs ...string) (*GroupChatThread, error) {
+	participants := make([]*CognitiveAgent, 0, len(agentIDs))
+	for _, id := range agentIDs {
+		agent, ok := r.Agent(id)
+		if !ok {
+			return nil, fmt.Errorf("agente %s não registrado", id)
+		}
+		participants = append(participants, agent)
+	}
+
+	thread, err := agents.GroupChat(ctx, topic, config, participants...)
+	if err != nil {
+		return thread, err
+	}
+	r.events.Emit(agents.Event{
+		Type:      agents.EventAgentAction,
+		Timestamp: time.Now(),
+		Source:    "group_chat",
+		Data: map[string]interface{}{
+			"action":       "group_chat_finished",
+			"topic":        topic,
+			"messages":     len(thread.Messages),
+			"participants": strings.Join(agentIDs, ", "),
+			"stopped_by":   thread.StoppedBy,
+		},
+	})
+	return thread, nil
+}
+
+// InstantiateTemplate instancia um template da galeria com as variáveis informadas, registra
+// os seus agentes e a equipe (com o nome do template) e retorna a equipe e o workflow, para
+// execução com crew.ExecuteWorkflowContext(ctx, workflow.Project)
+func (r *Runtime) InstantiateTemplate(name string, vars map[string]string) (*MarketingCrew, *Workflow, error) {
+	workflow, err := agents.InstantiateTemplate(name, vars)
+	if err != nil {
+		return nil, nil, err
+	}
+	list, err := workflow.NewAgents(r.Memory(), nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, agent := range list {
+		if _, exists := r.Agent(agent.GetID()); exists {
+			return nil, nil, fmt.Errorf("agente %s já registrado", agent.GetID())
+		}
+	}
+
+	crew := agents.NewMarketingCrew(r.Memory())
+	for _, agent := range list {
+		if err := r.RegisterAgent(agent); err != nil {
+			return nil, nil, err
+		}
+		crew.AddAgent(agent)
+	}
+	if err := r.RegisterCrew(name, crew); err != nil {
+		return nil, nil, err
+	}
+	return crew, workflow, nil
+}
+
+// NewLLMAgent cria um LLMAgent que consome a fila de tarefas do tenant do runtime, com a
+// assinatura de WithSigning e registrado no encerramento gracioso. Requer WithBus; o consumo
+// começa com LLMAgent.Start.
+func (r *Runtime) NewLLMAgent(id, agentType string) (*LLMAgent, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if !r.started {
+		return nil, fmt.Errorf("runtime não iniciado")
+	}
+	if r.conn == nil {
+		return nil, fmt.Errorf("o LLMAgent %s requer o barramento (WithBus)", id)
+	}
+
+	agent, err := agents.NewTenantLLMAgent(id, agentType, r.tenant, r.conn)
+	if err != nil {
+		return nil, err
+	}
+	agent.SetShutdown(r.stopper)
+	agent.SetSigning(r.signer, r.verifier)
+	r.stopper.OnStopIntake(id, agent.StopIntake)
+	r.stopper.OnClose(id, func(ctx context.Context) error {
+		return agent.Close()
+	})
+	return agent, nil
+}
+
+// SubmitTask publica a tarefa na fila de entrada do tenant do runtime.
+// Tarefas sem tenant usam o do contexto ou, na falta dele, o do runtime.
+func (r *Runtime) SubmitTask(ctx context.Context, task TaskRequest) error {
+	r.mu.RLock()
+	router := r.router
+	started := r.started
+	r.mu.RUnlock()
+
+	if !started {
+		return fmt.Errorf("runtime não iniciado")
+	}
+	if _, ok := tenant.Lookup(ctx); task.Tenant == "" && !ok {
+		task.Tenant = r.tenant
+	}
+	return router.SubmitTask(ctx, task)
+}
+
+// Schedule agenda uma execução periódica. As execuções contam como tarefas em andamento no
+// encerramento gracioso e só são disparadas depois de Start.
+func (r *Runtime) Schedule(job CronJob) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.schedule(job)
+}
+
+func (r *Runtime) schedule(job CronJob) error {
+	run := job.Run
+	if run == nil {
+		return fmt.Errorf("job periódico %s sem função de execução", job.Name)
+	}
+	job.Run = func(ctx context.Context) error {
+		r.mu.RLock()
+		stopper := r.stopper
+		r.mu.RUnlock()
+		if stopper == nil {
+			return fmt.Errorf("runtime não iniciado")
+		}
+		done, err := stopper.Track()
+		if err != nil {
+			return err
+		}
+		defer done()
+		return run(ctx)
+	}
+	return r.cron.Add(job)
+}
+
+// ScheduleCrew agenda o workflow da equipe de marketing registrada com o nome crew, como
+// a análise semanal de tendências. project é chamado a cada execução para criar o projeto;
+// job.Run é ignorado e, sem nome, o job recebe o nome da equipe.
+func (r *Runtime) ScheduleCrew(job CronJob, crew string, project func() *MarketingProject) error {
+	if job.Name == "" {
+		job.Name = crew
+	}
+	job.Run = func(ctx context.Context) error {
+		registered, ok := r.Crew(crew)
+		if !ok {
+			return fmt.Errorf("equipe %s não registrada", crew)
+		}
+		marketing, ok := registered.(*MarketingCrew)
+		if !ok {
+			return fmt.Errorf("a equipe %s não executa workflows agendados", crew)
+		}
+		_, err := marketing.ExecuteWorkflowContext(ctx, project())
+		return err
+	}
+	return r.Schedule(job)
+}
+
+// emitCron repassa as etapas das execuções periódicas ao emissor de eventos
+func (r *Runtime) emitCron(ctx context.Context, run cron.Run) {
+	data := map[string]interface{}{
+		"action": "scheduled_" + run.Stage,
+		"job":    run.Job,
+	}
+	if run.Stage == cron.StageComplete || run.Stage == cron.StageFailed {
+		data["duration_ms"] = float64(run.Duration.Microseconds()) / 1000
+	}
+	if run.Err != nil {
+		data["error"] = run.Err.Error()
+	}
+	r.events.Emit(agents.Event{
+		Type:      agents.EventWorkflowUpdate,
+		Timestamp: time.Now(),
+		Source:    "cron",
+		Data:      data,
+	})
+}
+
+// RunSpreadsheet executa o workflow da equipe de marketing registrada com o nome crew uma vez
+// por linha da planilha — por exemplo, uma campanha por produto —, com o projeto montado por
+// project a partir das colunas da linha (ex.: TemplateProject). O andamento é emitido como
+// EventWorkflowUpdate a cada linha e o resumo pode ser gravado com WriteSpreadsheetResults.
+func (r *Runtime) RunSpreadsheet(ctx context.Context, crew string, sheet SpreadsheetSheet, project func(SheetRow) (*MarketingProject, error), config SheetBatchConfig) (*SheetBatchSummary, error) {
+	registered, ok := r.Crew(crew)
+	if !ok {
+		return nil, fmt.Errorf("equipe %s não registrada", crew)
+	}
+	marketing, ok := registered.(*MarketingCrew)
+	if !ok {
+		return nil, fmt.Errorf("a equipe %s não executa workflows por planilha", crew)
+	}
+	// Com o runtime iniciado, o encerramento aguarda a planilha em andamento
+	r.mu.RLock()
+	stopper := r.stopper
+	r.mu.RUnlock()
+	if stopper != nil {
+		done, err := stopper.Track()
+		if err != nil {
+			return nil, err
+		}
+		defer done()
+	}
+	return sheetbatch.Run(ctx, sheet, agents.SpreadsheetRunner(marketing, project), config, r.emitSheetProgress)
+}
+
+// emitSheetProgress repassa o andamento das execuções por planilha ao emissor de eventos
+func (r *Runtime) emitSheetProgress(progress sheetbatch.Progress) {
+	data := map[string]interface{}{
+		"action": "batch_progress",
+		"done":   progress.Done,
+		"total":  progress.Total,
+		"row":    progress.Result.Row.Index,
+		"status": progress.Result.Status,
+	}
+	if progress.Result.Key != "" {
+		data["key"] = progress.Result.Key
+	}
+	if progress.Result.Error != "" {
+		data["error"] = progress.Result.Error
+	}
+	r.events.Emit(agents.Event{
+		Type:      agents.EventWorkflowUpdate,
+		Timestamp: time.Now(),
+		Source:    "sheetbatch",
+		Data:      data,
+	})
+}
+
+// DeleteMemoriesBySubject remove todas as memórias que referenciam o titular de dados nos
+// armazenamentos da memória do runtime e emite um EventMemoryOperation com o total removido
+func (r *Runtime) DeleteMemoriesBySubject(ctx context.Context, subjectID string) (*DeletionReport, error) {
+	eraser, ok := r.Memory().(memory.SubjectEraser)
+	if !ok {
+		return nil, fmt.Errorf("gerenciador de memória não suporta exclusão por titular")
+	}
+	if _, ok := tenant.Lookup(ctx); !ok {
+		ctx = tenant.WithTenant(ctx, r.tenant)
+	}
+
+	report, err := eraser.DeleteMemoriesBySubject(ctx, subjectID)
+	if report != nil {
+		r.events.Emit(agents.Event{
+			Type:      agents.EventMemoryOperation,
+			Timestamp: time.Now(),
+			Source:    "runtime",
+			Data: map[string]interface{}{
+				"action":   "delete_subject",
+				"tenant":   report.TenantID,
+				"total":    report.Total(),
+				"complete": report.Complete(),
+			},
+		})
+	}
+	return report, err
+}
+
+// emitMemoryOperation emite cada operação da memória como EventMemoryOperation, com a
+// duração total e a latência de cada armazenamento em milissegundos
+func (r *Runtime) emitMemoryOperation(ctx context.Context, op memory.Operation) {
+	backends := make(map[string]interface{}, len(op.Backends))
+	for backend, elapsed := range op.Backends {
+		backends[backend] = float64(elapsed.Microseconds()) / 1000
+	}
+	data := map[string]interface{}{
+		"action":      op.Action,
+		"tenant":      tenant.FromContext(ctx),
+		"agent_id":    op.AgentID,
+		"count":       op.Count,
+		"duration_ms": float64(op.Duration.Microseconds()) / 1000,
+		"backends":    backends,
+	}
+	if op.Err != nil {
+		data["error"] = op.Err.Error()
+	}
+	r.events.Emit(agents.Event{
+		Type:      agents.EventMemoryOperation,
+		Timestamp: time.Now(),
+		Source:    "memory",
+		Data:      data,
+	})
+}
+
+// emitMaintenance emite o progresso da manutenção da memória como EventMemoryMaintenance
+func (r *Runtime) emitMaintenance(ctx context.Context, progress maintenance.Progress) {
+	data := map[string]interface{}{
+		"action":       progress.Stage,
+		"tenant":       tenant.FromContext(ctx),
+		"agent_id":     progress.AgentID,
+		"batches":      progress.Batches,
+		"consolidated": progress.Consolidated,
+		"duration_ms":  float64(progress.Duration.Microseconds()) / 1000,
+	}
+	if progress.Err != nil {
+		data["error"] = progress.Err.Error()
+	}
+	r.events.Emit(agents.Event{
+		Type:      agents.EventMemoryMaintenance,
+		Timestamp: time.Now(),
+		Source:    "memory",
+		Data:      data,
+	})
+}
+
+// Maintenance retorna o agendador da manutenção da memória (nil sem manutenção configurada
+// ou antes de Start), onde é possível ajustar o intervalo de um agente ou executá-la na hora
+func (r *Runtime) Maintenance() *MaintenanceScheduler {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.maintenance
+}
+
+// Outbox retorna o relay do outbox (nil sem WithOutbox ou antes de Start)
+func (r *Runtime) Outbox() *OutboxRelay {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.outbox
+}
+
+// Presence retorna o monitor de presença dos agentes (nil sem WithPresence)
+func (r *Runtime) Presence() *PresenceMonitor {
+	return r.presence
+}
+
+// ContractNet retorna o gerente das licitações de tarefas (nil sem WithContractNet), que
+// também pode anunciar tarefas diretamente com Announce
+func (r *Runtime) ContractNet() *ContractNetManager {
+	return r.contracts
+}
+
+// Inbox retorna as caixas de entrada dos agentes (nil sem WithInbox), onde as tarefas são
+// enviadas por Dispatch ou Send
+func (r *Runtime) Inbox() *Inbox {
+	return r.inbox
+}
+
+// Blobs retorna o gerenciador do armazenamento de objetos (nil sem WithBlobStore), com que os
+// conteúdos das referências [blob:...] são buscados por Fetch ou Resolve
+func (r *Runtime) Blobs() *BlobManager {
+	return r.blobs
+}
+
+// Controller retorna o controlador do plano de controle (WithControl), para enviar comandos
+// às instâncias dos agentes, ou nil
+func (r *Runtime) Controller() *AgentController {
+	return r.controller
+}
+
+// SLA retorna o monitor de SLA (WithSLA), ou nil
+func (r *Runtime) SLA() *SLAMonitor {
+	return r.sla
+}
+
+// Webhooks retorna o despachante dos webhooks (WithWebhooks), para acompanhar as entregas ou
+// publicar eventos próprios, ou nil
+func (r *Runtime) Webhooks() *WebhookDispatcher {
+	return r.webhooks
+}
+
+// Cron retorna o agendador das execuções periódicas, para consultar os jobs, dispará-los
+// fora do horário ou desagendá-los
+func (r *Runtime) Cron() *CronScheduler {
+	return r.cron
+}
+
+// Ingest retorna a ponte de ingestão (WithIngest), com as contagens de cada tópico, ou nil
+func (r *Runtime) Ingest() *IngestBridge {
+	return r.ingest
+}
+
+// Sinks retorna o roteador dos sinks dos resultados (WithSinks), onde é possível acrescentar
+// sinks a um workflow, ou nil
+func (r *Runtime) Sinks() *SinkRouter {
+	return r.sinks
+}
+
+// Documents retorna a biblioteca de modelos de documento (WithDocuments), ou nil
+func (r *Runtime) Documents() *DocumentLibrary {
+	return r.documents
+}
+
+// ExportDocument preenche o modelo de documento com os dados (por exemplo,
+// WorkflowDocumentData) e o guarda no armazenamento de objetos de WithBlobStore
+func (r *Runtime) ExportDocument(ctx context.Context, name string, data interface{}) (*Document, BlobRef, error) {
+	if r.documents == nil || r.blobs == nil {
+		return nil, BlobRef{}, fmt.Errorf("exportar documentos exige WithDocuments e WithBlobStore")
+	}
+	doc, err := r.documents.Render(name, data)
+	if err != nil {
+		return nil, BlobRef{}, err
+	}
+	ref, err := r.blobs.Put(ctx, doc.Data, doc.ContentType)
+	if err != nil {
+		return nil, BlobRef{}, err
+	}
+	return doc, ref, nil
+}
+
+// ExportCalendar grava o calendário de conteúdo em CSV ou ICS no armazenamento de objetos de
+// WithBlobStore
+func (r *Runtime) ExportCalendar(ctx context.Context, contentCalendar *ContentCalendar, format CalendarFormat) (BlobRef, error) {
+	if r.blobs == nil {
+		return BlobRef{}, fmt.Errorf("exportar calendários exige WithBlobStore")
+	}
+	data, err := contentCalendar.Export(format)
+	if err != nil {
+		return BlobRef{}, err
+	}
+	return r.blobs.Put(ctx, data, format.ContentType())
+}
+
+// Discovery retorna o nó de descoberta (nil sem WithDiscovery ou antes de Start)
+func (r *Runtime) Discovery() *DiscoveryNode {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.discovery
+}
+
+// Events retorna o emissor de eventos do runtime
+func (r *Runtime) Events() *EventEmitter {
+	return r.events
+}
+
+// Tools retorna o registro de ferramentas do runtime
+func (r *Runtime) Tools() *ToolRegistry {
+	return r.tools
+}
+
+// Memory retorna o gerenciador de memória (nil antes de Start quando criado a partir da configuração)
+func (r *Runtime) Memory() MemoryManager {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.memory
+}
+
+// LLM retorna o provedor registrado com o nome informado; nome vazio retorna o padrão
+func (r *Runtime) LLM(name string) (LLMProvider, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if name == "" {
+		name = r.defaultLLM
+	}
+	provider, ok := r.providers[name]
+	return provider, ok
+}
@@ -0,0 +1,136 @@
+//go:build integration
+
+package testenv
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/streadway/amqp"
+
+	"github.com/suissa/HiveMind/agents"
+	"github.com/suissa/HiveMind/agents/llm"
+	"github.com/suissa/HiveMind/agents/memory"
+	"github.com/suissa/HiveMind/agents/simulation"
+)
+
+func benchMemory(id string) *memory.Memory {
+	return &memory.Memory{
+		ID:         id,
+		AgentID:    "bench-agent",
+		Content:    "Memória de benchmark",
+		Type:       memory.ShortTerm,
+		Importance: 0.5,
+		Timestamp:  time.Now(),
+		Tags:       []string{"bench"},
+	}
+}
+
+func BenchmarkStoreMemory(b *testing.B) {
+	ctx := context.Background()
+	env := New(b, MemoryServices())
+
+	memManager, err := env.NewMemoryManager(ctx)
+	if err != nil {
+		b.Fatalf("erro ao criar gerenciador de memória: %v", err)
+	}
+	defer memManager.Close(ctx)
+
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			if err := memManager.StoreMemory(ctx, benchMemory(uuid.New().String())); err != nil {
+				b.Errorf("erro ao armazenar memória: %v", err)
+			}
+		}
+	})
+}
+
+func BenchmarkSearchMemories(b *testing.B) {
+	ctx := context.Background()
+	env := New(b, MemoryServices())
+
+	memManager, err := env.NewMemoryManager(ctx)
+	if err != nil {
+		b.Fatalf("erro ao criar gerenciador de memória: %v", err)
+	}
+	defer memManager.Close(ctx)
+
+	for i := 0; i < 100; i++ {
+		if err := memManager.StoreMemory(ctx, benchMemory(fmt.Sprintf("bench-%d", i))); err != nil {
+			b.Fatalf("erro ao popular memórias: %v", err)
+		}
+	}
+
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			if _, err := memManager.SearchMemories(ctx, "bench-agent", []string{"bench"}); err != nil {
+				b.Errorf("erro ao buscar memórias: %v", err)
+			}
+		}
+	})
+}
+
+func BenchmarkRabbitMQRoundTrip(b *testing.B) {
+	env := New(b, Options{RabbitMQ: true})
+
+	conn, err := env.DialRabbitMQ()
+	if err != nil {
+		b.Fatalf("erro ao conectar ao RabbitMQ: %v", err)
+	}
+	defer conn.Close()
+
+	channel, err := conn.Channel()
+	if err != nil {
+		b.Fatalf("erro ao criar canal: %v", err)
+	}
+	defer channel.Close()
+
+	queue, err := channel.QueueDeclare("", false, true, true, false, nil)
+	if err != nil {
+		b.Fatalf("erro ao declarar fila: %v", err)
+	}
+	messages, err := channel.Consume(queue.Name, "", true, true, false, false, nil)
+	if err != nil {
+		b.Fatalf("erro ao consumir fila: %v", err)
+	}
+
+	body := []byte(`{"id":"bench"}`)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := channel.Publish("", queue.Name, false, false, amqp.Publishing{Body: body}); err != nil {
+			b.Fatalf("erro ao publicar: %v", err)
+		}
+		select {
+		case <-messages:
+		case <-time.After(5 * time.Second):
+			b.Fatal("timeout aguardando mensagem")
+		}
+	}
+}
+
+func BenchmarkWorkflowDryRun(b *testing.B) {
+	provider := llm.NewFakeLLMProvider()
+	if err := provider.SetDefault("Resposta simulada para: {{.Prompt}}"); err != nil {
+		b.Fatal(err)
+	}
+
+	for i := 0; i < b.N; i++ {
+		crew := agents.NewMarketingCrew(nil)
+		agent := agents.NewCognitiveAgent("analyst", "Analista", "Analista de mercado", 1, "gpt-4", "analyst", "bench", nil)
+		agent.SetLLM(provider)
+		crew.AddAgent(agent)
+
+		project := &agents.MarketingProject{Name: "Bench", Objective: "Medir o workflow"}
+		project.AddTask(agents.TaskConfig{ID: "task-1", Name: "Análise", AssignedTo: "analyst", Status: "pending"})
+
+		ctx := simulation.WithSession(context.Background(), simulation.NewSession(provider))
+		if _, err := crew.ExecuteWorkflowContext(ctx, project); err != nil {
+			b.Fatalf("erro no workflow: %v", err)
+		}
+	}
+}
@@ -0,0 +1,135 @@
+//go:build integration
+
+package testenv
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/suissa/HiveMind/agents/memory"
+	"github.com/suissa/HiveMind/agents/tenant"
+)
+
+// newMongoMemory cria o gerenciador de memória de longo prazo em uma coleção própria do teste
+func newMongoMemory(t *testing.T, env *Environment) *memory.MongoMemoryManager {
+	t.Helper()
+	ctx := context.Background()
+	manager, err := memory.NewMongoMemoryManager(ctx, env.MongoURL, "hivemind_test", t.Name())
+	if err != nil {
+		t.Fatalf("Erro ao criar gerenciador MongoDB: %v", err)
+	}
+	t.Cleanup(func() { manager.Close(ctx) })
+	return manager
+}
+
+func TestMongoSearchMemoriesPage(t *testing.T) {
+	env := New(t, Options{Mongo: true})
+	manager := newMongoMemory(t, env)
+	ctx := context.Background()
+
+	// m-2 e m-3 têm a mesma data: o _id desempata a ordem entre as páginas
+	base := time.Now().Truncate(time.Millisecond)
+	memories := []*memory.Memory{
+		{ID: "m-1", Timestamp: base},
+		{ID: "m-2", Timestamp: base.Add(time.Minute)},
+		{ID: "m-3", Timestamp: base.Add(time.Minute)},
+		{ID: "m-4", Timestamp: base.Add(2 * time.Minute)},
+		{ID: "m-5", Timestamp: base.Add(3 * time.Minute), Tags: []string{"copy"}},
+	}
+	for _, m := range memories {
+		m.AgentID, m.Content = "writer-1", m.ID
+		if err := manager.StoreMemory(ctx, m); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Memória do mesmo agente em outro tenant
+	other := &memory.Memory{ID: "m-9", AgentID: "writer-1", Content: "acme", Timestamp: base.Add(time.Hour)}
+	if err := manager.StoreMemory(tenant.WithTenant(ctx, "acme"), other); err != nil {
+		t.Fatal(err)
+	}
+
+	var pages [][]string
+	page := memory.Page{Limit: 2}
+	for {
+		result, err := manager.SearchMemoriesPage(ctx, "writer-1", nil, page)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var ids []string
+		for _, m := range result.Memories {
+			ids = append(ids, m.ID)
+		}
+		pages = append(pages, ids)
+		if result.NextCursor == "" {
+			break
+		}
+		if len(pages) > len(memories) {
+			t.Fatalf("a paginação não terminou: %v", pages)
+		}
+		page.Cursor = result.NextCursor
+	}
+
+	want := [][]string{{"m-5", "m-4"}, {"m-3", "m-2"}, {"m-1"}}
+	if len(pages) != len(want) {
+		t.Fatalf("páginas %v, esperava %v", pages, want)
+	}
+	for i := range want {
+		if len(pages[i]) != len(want[i]) {
+			t.Fatalf("páginas %v, esperava %v", pages, want)
+		}
+		for j := range want[i] {
+			if pages[i][j] != want[i][j] {
+				t.Fatalf("páginas %v, esperava %v", pages, want)
+			}
+		}
+	}
+
+	// O filtro de tags se combina com a paginação
+	tagged, err := manager.SearchMemoriesPage(ctx, "writer-1", []string{"copy"}, memory.Page{Limit: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tagged.Memories) != 1 || tagged.Memories[0].ID != "m-5" || tagged.NextCursor != "" {
+		t.Fatalf("busca por tag inesperada: %+v", tagged)
+	}
+}
+
+func TestMongoMemoryStats(t *testing.T) {
+	env := New(t, Options{Mongo: true})
+	manager := newMongoMemory(t, env)
+	ctx := context.Background()
+
+	memories := []*memory.Memory{
+		{ID: "w-1", AgentID: "writer-1", Importance: 0.2},
+		{ID: "w-2", AgentID: "writer-1", Importance: 0.25},
+		{ID: "w-3", AgentID: "writer-1", Importance: 1},
+		{ID: "r-1", AgentID: "reviewer-1", Importance: 0.5},
+	}
+	for _, m := range memories {
+		m.Content = m.ID
+		if err := manager.StoreMemory(ctx, m); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	stats, err := manager.MemoryStats(ctx, "writer-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stats) != 1 || stats[0].AgentID != "writer-1" || stats[0].Count != 3 {
+		t.Fatalf("estatísticas inesperadas: %+v", stats)
+	}
+	// Importância 1 entra na última faixa
+	if stats[0].Importance[2].Count != 2 || stats[0].Importance[9].Count != 1 {
+		t.Errorf("faixas inesperadas: %+v", stats[0].Importance)
+	}
+
+	all, err := manager.MemoryStats(ctx, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 2 || all[0].AgentID != "reviewer-1" || all[1].AgentID != "writer-1" {
+		t.Fatalf("esperava os dois agentes em ordem: %+v", all)
+	}
+}
@@ -0,0 +1,167 @@
+//go:build integration
+
+package testenv
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+
+	"github.com/suissa/HiveMind/agents/memory"
+	"github.com/suissa/HiveMind/agents/tenant"
+)
+
+// newRedisMemory cria o gerenciador de memória de curto prazo e um cliente para inspecionar
+// as chaves gravadas por ele
+func newRedisMemory(t *testing.T, env *Environment) (*memory.RedisMemoryManager, *redis.Client) {
+	t.Helper()
+	ctx := context.Background()
+	manager, err := memory.NewRedisMemoryManager(ctx, env.RedisURL)
+	if err != nil {
+		t.Fatalf("Erro ao criar gerenciador Redis: %v", err)
+	}
+	t.Cleanup(func() { manager.Close(ctx) })
+
+	opt, err := redis.ParseURL(env.RedisURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := redis.NewClient(opt)
+	t.Cleanup(func() { client.Close() })
+	return manager, client
+}
+
+func TestRedisIndexTTL(t *testing.T) {
+	env := New(t, Options{Redis: true})
+	manager, client := newRedisMemory(t, env)
+	ctx := context.Background()
+
+	store := func(id string, ttl time.Duration) {
+		t.Helper()
+		err := manager.StoreMemory(ctx, &memory.Memory{ID: id, AgentID: "writer-1", Content: id, Tags: []string{"copy"}, TTL: ttl})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	indexTTL := func(key string) time.Duration {
+		t.Helper()
+		ttl, err := client.PTTL(ctx, key).Result()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return ttl
+	}
+
+	// O índice nasce com o TTL da primeira memória
+	store("m-1", time.Minute)
+	for _, key := range []string{"agent:writer-1:memories", "tag:writer-1:copy"} {
+		if ttl := indexTTL(key); ttl <= 0 || ttl > time.Minute {
+			t.Fatalf("%s: TTL %v, esperava até 1 minuto", key, ttl)
+		}
+	}
+
+	// Uma memória mais duradoura estende o índice; uma mais curta não o encurta
+	store("m-2", time.Hour)
+	store("m-3", time.Second)
+	cases := []struct {
+		key      string
+		min, max time.Duration
+	}{
+		{"agent:writer-1:memories", 59 * time.Minute, time.Hour},
+		{"tag:writer-1:copy", 59 * time.Minute, time.Hour},
+	}
+	for _, c := range cases {
+		if ttl := indexTTL(c.key); ttl < c.min || ttl > c.max {
+			t.Errorf("%s: TTL %v, esperava entre %v e %v", c.key, ttl, c.min, c.max)
+		}
+	}
+
+	// Um índice sem TTL, de antes da manutenção, continua sem TTL
+	if err := client.SAdd(ctx, "agent:legacy:memories", "m-0").Err(); err != nil {
+		t.Fatal(err)
+	}
+	if err := manager.StoreMemory(ctx, &memory.Memory{ID: "m-4", AgentID: "legacy", Content: "nova", TTL: time.Minute}); err != nil {
+		t.Fatal(err)
+	}
+	if ttl := indexTTL("agent:legacy:memories"); ttl != -1 {
+		t.Errorf("o índice sem TTL deveria continuar sem TTL: %v", ttl)
+	}
+}
+
+func TestRedisRepairIndexes(t *testing.T) {
+	env := New(t, Options{Redis: true})
+	manager, client := newRedisMemory(t, env)
+
+	for _, tenantID := range []string{tenant.DefaultTenant, "acme"} {
+		t.Run(tenantID, func(t *testing.T) {
+			ctx := tenant.WithTenant(context.Background(), tenantID)
+			key := func(name string) string { return tenant.Namespace(tenantID, name) }
+
+			// Agentes e tags com ':' não podem ser confundidos entre si
+			memories := []*memory.Memory{
+				{ID: "live-1", AgentID: "crew:writer", Content: "a", Tags: []string{"copy"}},
+				{ID: "live-2", AgentID: "crew", Content: "b", Tags: []string{"writer:copy"}},
+				{ID: "gone-1", AgentID: "crew:writer", Content: "c", Tags: []string{"copy"}},
+				{ID: "gone-2", AgentID: "crew", Content: "d", Tags: []string{"writer:copy"}},
+			}
+			for _, m := range memories {
+				if err := manager.StoreMemory(ctx, m); err != nil {
+					t.Fatal(err)
+				}
+			}
+			// As memórias gone-* expiram e deixam os IDs pendentes nos índices
+			for _, m := range memories[2:] {
+				if err := client.Del(ctx, key("memory:"+m.AgentID+":"+m.ID)).Err(); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			report, err := manager.RepairIndexes(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if report.Namespace != tenantID || report.IndexesSeen != 3 || report.Removed != 4 {
+				t.Fatalf("relatório inesperado: %+v", report)
+			}
+
+			// A tag "writer:copy" do agente "crew" e a tag "copy" do agente "crew:writer" têm a
+			// mesma chave; cada uma mantém a memória viva
+			cases := []struct {
+				index string
+				want  []string
+			}{
+				{"agent:crew:writer:memories", []string{"live-1"}},
+				{"agent:crew:memories", []string{"live-2"}},
+				{"tag:crew:writer:copy", []string{"live-1", "live-2"}},
+			}
+			for _, c := range cases {
+				members, err := client.SMembers(ctx, key(c.index)).Result()
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !sameMembers(members, c.want) {
+					t.Errorf("%s: %v, esperava %v", c.index, members, c.want)
+				}
+			}
+		})
+	}
+}
+
+// sameMembers compara dois conjuntos sem considerar a ordem
+func sameMembers(got, want []string) bool {
+	if len(got) != len(want) {
+		return false
+	}
+	seen := make(map[string]bool, len(got))
+	for _, v := range got {
+		seen[v] = true
+	}
+	for _, v := range want {
+		if !seen[v] {
+			return false
+		}
+	}
+	return true
+}
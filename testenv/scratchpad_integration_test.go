@@ -0,0 +1,112 @@
+//go:build integration
+
+package testenv
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+
+	"github.com/suissa/HiveMind/agents/errs"
+	"github.com/suissa/HiveMind/agents/memory"
+	"github.com/suissa/HiveMind/agents/tenant"
+)
+
+func TestScratchpad(t *testing.T) {
+	env := New(t, MemoryServices())
+	ctx := tenant.WithTenant(context.Background(), "acme")
+
+	memManager, err := env.NewMemoryManager(ctx)
+	if err != nil {
+		t.Fatalf("Erro ao criar gerenciador de memória: %v", err)
+	}
+	defer memManager.Close(ctx)
+	opt, err := redis.ParseURL(env.RedisURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := redis.NewClient(opt)
+	defer client.Close()
+
+	pad := memManager.Scratchpad("wf-1")
+	pad.TTL = time.Minute
+	other := memManager.Scratchpad("wf-2")
+	base := time.Now()
+	for i, id := range []string{"n-3", "n-1", "n-2"} {
+		note := &memory.Memory{ID: id, AgentID: "writer-1", Content: "Rascunho " + id, Timestamp: base.Add(time.Duration(3-i) * time.Second)}
+		if err := pad.Write(ctx, note); err != nil {
+			t.Fatalf("Erro ao gravar anotação: %v", err)
+		}
+	}
+	if err := other.Write(ctx, &memory.Memory{ID: "n-9", AgentID: "writer-1", Content: "Outro workflow"}); err != nil {
+		t.Fatalf("Erro ao gravar anotação: %v", err)
+	}
+
+	// As anotações ficam no hash do workflow, no namespace do tenant e com expiração
+	if ttl := client.PTTL(ctx, tenant.Namespace("acme", "scratch:wf-1")).Val(); ttl <= 0 || ttl > time.Minute {
+		t.Errorf("TTL do scratchpad %v, esperava até 1 minuto", ttl)
+	}
+	notes, err := pad.List(ctx)
+	if err != nil {
+		t.Fatalf("Erro ao listar anotações: %v", err)
+	}
+	if len(notes) != 3 || notes[0].ID != "n-2" || notes[1].ID != "n-1" || notes[2].ID != "n-3" {
+		t.Fatalf("esperava as anotações em ordem cronológica: %+v", notes)
+	}
+	if notes[0].Type != memory.Scratch {
+		t.Errorf("tipo da anotação %q, esperava %q", notes[0].Type, memory.Scratch)
+	}
+
+	// A importância decide entre curto e longo prazo, e a anotação sai do scratchpad
+	cases := []struct {
+		id         string
+		importance float64
+		want       memory.MemoryType
+	}{
+		{"n-1", 0.9, memory.LongTerm},
+		{"n-2", 0.1, memory.ShortTerm},
+	}
+	for _, c := range cases {
+		promoted, err := pad.Promote(ctx, c.id, c.importance)
+		if err != nil {
+			t.Fatalf("Erro ao promover %s: %v", c.id, err)
+		}
+		if promoted.Type != c.want {
+			t.Errorf("%s promovida como %q, esperava %q", c.id, promoted.Type, c.want)
+		}
+		stored, err := memManager.GetMemory(ctx, "writer-1", c.id)
+		if err != nil || stored.Type != c.want || stored.Importance != c.importance {
+			t.Errorf("%s não foi gravada na memória do agente: %+v, %v", c.id, stored, err)
+		}
+		if _, err := pad.Get(ctx, c.id); !errors.Is(err, errs.ErrNotFound) {
+			t.Errorf("%s deveria sair do scratchpad: %v", c.id, err)
+		}
+	}
+	if _, err := pad.Promote(ctx, "ausente", 0.9); !errors.Is(err, errs.ErrNotFound) {
+		t.Errorf("esperava ErrNotFound ao promover anotação ausente: %v", err)
+	}
+
+	// Purge descarta só as anotações restantes do workflow
+	purged, err := pad.Purge(ctx)
+	if err != nil {
+		t.Fatalf("Erro ao descartar scratchpad: %v", err)
+	}
+	if purged != 1 {
+		t.Errorf("descartadas %d anotações, esperava 1", purged)
+	}
+	if notes, err := pad.List(ctx); err != nil || len(notes) != 0 {
+		t.Errorf("o scratchpad deveria estar vazio: %+v, %v", notes, err)
+	}
+	if _, err := memManager.GetMemory(ctx, "writer-1", "n-1"); err != nil {
+		t.Errorf("a purga não deveria remover memórias promovidas: %v", err)
+	}
+	if notes, err := other.List(ctx); err != nil || len(notes) != 1 {
+		t.Errorf("a purga não deveria alcançar outro workflow: %+v, %v", notes, err)
+	}
+	if purged, err := pad.Purge(ctx); err != nil || purged != 0 {
+		t.Errorf("purgar de novo deveria remover 0 anotações: %d, %v", purged, err)
+	}
+}
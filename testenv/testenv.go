@@ -0,0 +1,276 @@
+// Package testenv sobe Redis, MongoDB, Weaviate, RabbitMQ e Kafka em containers
+// (testcontainers) e monta o HybridMemoryManager e as crews para testes de ponta a ponta.
+// Pode ser reutilizado pelos testes de integração de quem usa o HiveMind.
+package testenv
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/docker/go-connections/nat"
+	"github.com/streadway/amqp"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+
+	"github.com/suissa/HiveMind/agents"
+	"github.com/suissa/HiveMind/agents/communication"
+	"github.com/suissa/HiveMind/agents/memory"
+)
+
+// Imagens usadas por padrão
+const (
+	RedisImage    = "redis:7-alpine"
+	MongoImage    = "mongo:7"
+	WeaviateImage = "semitechnologies/weaviate:1.27.0"
+	RabbitMQImage = "rabbitmq:3.13-alpine"
+	KafkaImage    = "bitnami/kafka:3.7"
+)
+
+// startupTimeout é o tempo máximo de espera pela inicialização de cada container
+const startupTimeout = 2 * time.Minute
+
+// Options define quais serviços são iniciados
+type Options struct {
+	Redis    bool
+	Mongo    bool
+	Weaviate bool
+	RabbitMQ bool
+	Kafka    bool
+}
+
+// MemoryServices inicia apenas os serviços do HybridMemoryManager
+func MemoryServices() Options {
+	return Options{Redis: true, Mongo: true, Weaviate: true}
+}
+
+// AllServices inicia todos os serviços
+func AllServices() Options {
+	return Options{Redis: true, Mongo: true, Weaviate: true, RabbitMQ: true, Kafka: true}
+}
+
+// Environment representa os serviços em execução
+type Environment struct {
+	RedisURL     string // redis://host:porta
+	MongoURL     string // mongodb://host:porta
+	WeaviateURL  string // host:porta
+	RabbitMQ     *communication.ConnectionConfig
+	KafkaBrokers []string
+
+	containers []testcontainers.Container
+}
+
+// Start inicia os containers selecionados. Em caso de erro os containers já iniciados são encerrados.
+func Start(ctx context.Context, opts Options) (*Environment, error) {
+	env := &Environment{}
+
+	if err := env.start(ctx, opts); err != nil {
+		env.Terminate(context.Background())
+		return nil, err
+	}
+
+	return env, nil
+}
+
+// start inicia cada serviço selecionado
+func (e *Environment) start(ctx context.Context, opts Options) error {
+	if opts.Redis {
+		endpoint, err := e.run(ctx, testcontainers.ContainerRequest{
+			Image:        RedisImage,
+			ExposedPorts: []string{"6379/tcp"},
+			WaitingFor:   wait.ForLog("Ready to accept connections").WithStartupTimeout(startupTimeout),
+		}, "6379/tcp")
+		if err != nil {
+			return fmt.Errorf("erro ao iniciar Redis: %v", err)
+		}
+		e.RedisURL = "redis://" + endpoint
+	}
+
+	if opts.Mongo {
+		endpoint, err := e.run(ctx, testcontainers.ContainerRequest{
+			Image:        MongoImage,
+			ExposedPorts: []string{"27017/tcp"},
+			WaitingFor:   wait.ForLog("Waiting for connections").WithStartupTimeout(startupTimeout),
+		}, "27017/tcp")
+		if err != nil {
+			return fmt.Errorf("erro ao iniciar MongoDB: %v", err)
+		}
+		e.MongoURL = "mongodb://" + endpoint
+	}
+
+	if opts.Weaviate {
+		endpoint, err := e.run(ctx, testcontainers.ContainerRequest{
+			Image:        WeaviateImage,
+			ExposedPorts: []string{"8080/tcp"},
+			Env: map[string]string{
+				"AUTHENTICATION_ANONYMOUS_ACCESS_ENABLED": "true",
+				"PERSISTENCE_DATA_PATH":                   "/var/lib/weaviate",
+				"DEFAULT_VECTORIZER_MODULE":               "none",
+				"CLUSTER_HOSTNAME":                        "node1",
+			},
+			WaitingFor: wait.ForHTTP("/v1/.well-known/ready").WithPort("8080/tcp").WithStartupTimeout(startupTimeout),
+		}, "8080/tcp")
+		if err != nil {
+			return fmt.Errorf("erro ao iniciar Weaviate: %v", err)
+		}
+		e.WeaviateURL = endpoint
+	}
+
+	if opts.RabbitMQ {
+		endpoint, err := e.run(ctx, testcontainers.ContainerRequest{
+			Image:        RabbitMQImage,
+			ExposedPorts: []string{"5672/tcp"},
+			WaitingFor:   wait.ForLog("Server startup complete").WithStartupTimeout(startupTimeout),
+		}, "5672/tcp")
+		if err != nil {
+			return fmt.Errorf("erro ao iniciar RabbitMQ: %v", err)
+		}
+		host, port, err := splitEndpoint(endpoint)
+		if err != nil {
+			return err
+		}
+		e.RabbitMQ = &communication.ConnectionConfig{
+			Host:     host,
+			Port:     port,
+			Username: "guest",
+			Password: "guest",
+		}
+	}
+
+	if opts.Kafka {
+		// O Kafka anuncia o próprio endereço aos clientes, então a porta do host é fixada antes da inicialização
+		port, err := freePort()
+		if err != nil {
+			return err
+		}
+		endpoint, err := e.run(ctx, testcontainers.ContainerRequest{
+			Image:        KafkaImage,
+			ExposedPorts: []string{fmt.Sprintf("%d:9092/tcp", port)},
+			Env: map[string]string{
+				"KAFKA_CFG_NODE_ID":                        "0",
+				"KAFKA_CFG_PROCESS_ROLES":                  "controller,broker",
+				"KAFKA_CFG_LISTENERS":                      "PLAINTEXT://:9092,CONTROLLER://:9093",
+				"KAFKA_CFG_ADVERTISED_LISTENERS":           fmt.Sprintf("PLAINTEXT://localhost:%d", port),
+				"KAFKA_CFG_LISTENER_SECURITY_PROTOCOL_MAP": "CONTROLLER:PLAINTEXT,PLAINTEXT:PLAINTEXT",
+				"KAFKA_CFG_CONTROLLER_QUORUM_VOTERS":       "0@localhost:9093",
+				"KAFKA_CFG_CONTROLLER_LISTENER_NAMES":      "CONTROLLER",
+			},
+			WaitingFor: wait.ForLog("Kafka Server started").WithStartupTimeout(startupTimeout),
+		}, "9092/tcp")
+		if err != nil {
+			return fmt.Errorf("erro ao iniciar Kafka: %v", err)
+		}
+		e.KafkaBrokers = []string{endpoint}
+	}
+
+	return nil
+}
+
+// run inicia um container e retorna o endereço host:porta da porta informada
+func (e *Environment) run(ctx context.Context, req testcontainers.ContainerRequest, port nat.Port) (string, error) {
+	container, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+		ContainerRequest: req,
+		Started:          true,
+	})
+	if container != nil {
+		e.containers = append(e.containers, container)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	host, err := container.Host(ctx)
+	if err != nil {
+		return "", fmt.Errorf("erro ao obter host do container: %v", err)
+	}
+	mapped, err := container.MappedPort(ctx, port)
+	if err != nil {
+		return "", fmt.Errorf("erro ao obter porta do container: %v", err)
+	}
+
+	return net.JoinHostPort(host, mapped.Port()), nil
+}
+
+// MemoryConfig retorna a configuração de memória apontando para os containers
+func (e *Environment) MemoryConfig() *memory.MemoryConfig {
+	config := memory.DefaultMemoryConfig()
+	config.RedisURL = e.RedisURL
+	config.MongoURL = e.MongoURL
+	config.MongoDB = "hivemind_test"
+	config.WeaviateURL = e.WeaviateURL
+	return config
+}
+
+// NewMemoryManager cria um HybridMemoryManager conectado aos containers
+func (e *Environment) NewMemoryManager(ctx context.Context) (*memory.HybridMemoryManager, error) {
+	if e.RedisURL == "" || e.MongoURL == "" || e.WeaviateURL == "" {
+		return nil, fmt.Errorf("o HybridMemoryManager requer Redis, MongoDB e Weaviate")
+	}
+	return memory.NewHybridMemoryManager(ctx, e.MemoryConfig())
+}
+
+// NewMarketingCrew cria uma MarketingCrew com um HybridMemoryManager conectado aos containers
+func (e *Environment) NewMarketingCrew(ctx context.Context) (*agents.MarketingCrew, *memory.HybridMemoryManager, error) {
+	memManager, err := e.NewMemoryManager(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	return agents.NewMarketingCrew(memManager), memManager, nil
+}
+
+// DialRabbitMQ conecta ao RabbitMQ do ambiente
+func (e *Environment) DialRabbitMQ() (*amqp.Connection, error) {
+	if e.RabbitMQ == nil {
+		return nil, fmt.Errorf("RabbitMQ não foi iniciado")
+	}
+	return communication.DialRabbitMQ(e.RabbitMQ)
+}
+
+// KafkaClient cria um cliente Kafka conectado ao broker do ambiente
+func (e *Environment) KafkaClient() (*communication.KafkaClient, error) {
+	if len(e.KafkaBrokers) == 0 {
+		return nil, fmt.Errorf("Kafka não foi iniciado")
+	}
+	host, port, err := splitEndpoint(e.KafkaBrokers[0])
+	if err != nil {
+		return nil, err
+	}
+	return communication.NewKafkaClient(&communication.ConnectionConfig{Host: host, Port: port}, "hivemind-test"), nil
+}
+
+// Terminate encerra todos os containers
+func (e *Environment) Terminate(ctx context.Context) error {
+	var firstErr error
+	for i := len(e.containers) - 1; i >= 0; i-- {
+		if err := e.containers[i].Terminate(ctx); err != nil && firstErr == nil {
+			firstErr = fmt.Errorf("erro ao encerrar container: %v", err)
+		}
+	}
+	e.containers = nil
+	return firstErr
+}
+
+// splitEndpoint separa host e porta de um endereço host:porta
+func splitEndpoint(endpoint string) (string, int, error) {
+	host, portStr, err := net.SplitHostPort(endpoint)
+	if err != nil {
+		return "", 0, fmt.Errorf("endereço inválido %s: %v", endpoint, err)
+	}
+	port, err := strconv.Atoi(portStr)
+	if err != nil {
+		return "", 0, fmt.Errorf("porta inválida %s: %v", endpoint, err)
+	}
+	return host, port, nil
+}
+
+// freePort reserva uma porta TCP livre no host
+func freePort() (int, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return 0, fmt.Errorf("erro ao reservar porta livre: %v", err)
+	}
+	defer listener.Close()
+	return listener.Addr().(*net.TCPAddr).Port, nil
+}
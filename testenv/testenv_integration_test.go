@@ -0,0 +1,175 @@
+//go:build integration
+
+package testenv
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/suissa/HiveMind/agents"
+	"github.com/suissa/HiveMind/agents/memory"
+)
+
+func TestHybridMemoryManager(t *testing.T) {
+	env := New(t, MemoryServices())
+	ctx := context.Background()
+
+	memManager, err := env.NewMemoryManager(ctx)
+	if err != nil {
+		t.Fatalf("Erro ao criar gerenciador de memória: %v", err)
+	}
+	defer memManager.Close(ctx)
+
+	mem := &memory.Memory{
+		ID:         "mem-1",
+		AgentID:    "agent-1",
+		Content:    "O público prefere vídeos curtos",
+		Type:       memory.ShortTerm,
+		Importance: 0.9,
+		Timestamp:  time.Now(),
+		Tags:       []string{"research"},
+	}
+	if err := memManager.StoreMemory(ctx, mem); err != nil {
+		t.Fatalf("Erro ao armazenar memória: %v", err)
+	}
+
+	memories, err := memManager.SearchMemories(ctx, "agent-1", []string{"research"})
+	if err != nil {
+		t.Fatalf("Erro ao buscar memórias: %v", err)
+	}
+	if len(memories) == 0 {
+		t.Error("Memória armazenada não encontrada")
+	}
+}
+
+func TestDeleteMemoriesBySubject(t *testing.T) {
+	env := New(t, MemoryServices())
+	ctx := context.Background()
+
+	memManager, err := env.NewMemoryManager(ctx)
+	if err != nil {
+		t.Fatalf("Erro ao criar gerenciador de memória: %v", err)
+	}
+	defer memManager.Close(ctx)
+
+	memories := []*memory.Memory{
+		{ID: "mem-subject", AgentID: "agent-1", Content: "Preferências de contato registradas", Subjects: []string{"ana"}},
+		{ID: "mem-content", AgentID: "agent-1", Content: "Reunião com ana sobre o contrato"},
+		{ID: "mem-other", AgentID: "agent-1", Content: "Receita de bolo de banana para o evento"},
+	}
+	for _, mem := range memories {
+		mem.Type = memory.LongTerm
+		mem.Importance = 0.9
+		mem.Timestamp = time.Now()
+		if err := memManager.StoreMemory(ctx, mem); err != nil {
+			t.Fatalf("Erro ao armazenar memória: %v", err)
+		}
+	}
+
+	report, err := memManager.DeleteMemoriesBySubject(ctx, "ana")
+	if err != nil {
+		t.Fatalf("Erro ao excluir memórias do titular: %v", err)
+	}
+	for _, backend := range report.Backends {
+		for _, id := range backend.MemoryIDs {
+			if id == "mem-other" {
+				t.Errorf("%s removeu uma memória que não cita o titular", backend.Backend)
+			}
+		}
+	}
+	if report.Total() == 0 {
+		t.Error("Nenhuma memória do titular removida")
+	}
+}
+
+func TestMarketingCrewWorkflow(t *testing.T) {
+	env := New(t, MemoryServices())
+	ctx := context.Background()
+
+	crew, memManager, err := env.NewMarketingCrew(ctx)
+	if err != nil {
+		t.Fatalf("Erro ao criar crew: %v", err)
+	}
+	defer memManager.Close(ctx)
+
+	project := &agents.MarketingProject{Name: "Teste", Objective: "Validar o ambiente"}
+	if _, err := crew.ExecuteWorkflowContext(ctx, project); err != nil {
+		t.Fatalf("Erro no workflow: %v", err)
+	}
+}
+
+func TestRabbitMQ(t *testing.T) {
+	env := New(t, Options{RabbitMQ: true})
+
+	conn, err := env.DialRabbitMQ()
+	if err != nil {
+		t.Fatalf("Erro ao conectar ao RabbitMQ: %v", err)
+	}
+	defer conn.Close()
+}
+
+func TestKafka(t *testing.T) {
+	env := New(t, Options{Kafka: true})
+	ctx := context.Background()
+
+	client, err := env.KafkaClient()
+	if err != nil {
+		t.Fatalf("Erro ao criar cliente Kafka: %v", err)
+	}
+	if err := client.Connect(ctx); err != nil {
+		t.Fatalf("Erro ao conectar ao Kafka: %v", err)
+	}
+	defer client.Disconnect()
+
+	if err := client.Publish(ctx, "hivemind.test", []byte("ping")); err != nil {
+		t.Fatalf("Erro ao publicar no Kafka: %v", err)
+	}
+}
+
+func TestMemoryTimeline(t *testing.T) {
+	env := New(t, MemoryServices())
+	ctx := context.Background()
+
+	memManager, err := env.NewMemoryManager(ctx)
+	if err != nil {
+		t.Fatalf("Erro ao criar gerenciador de memória: %v", err)
+	}
+	defer memManager.Close(ctx)
+
+	// Memórias de curto prazo (Redis) e de longo prazo (MongoDB) intercaladas no tempo
+	from := time.Now().Add(-time.Hour).Truncate(time.Millisecond)
+	to := from.Add(time.Hour)
+	taskCtx := memory.WithProvenance(ctx, memory.Provenance{WorkflowID: "wf-1", TaskID: "task-1"})
+	memories := []*memory.Memory{
+		{ID: "before", Importance: 0.9, Timestamp: from.Add(-time.Minute)},
+		{ID: "long-1", Importance: 0.9, Timestamp: from},
+		{ID: "short-1", Importance: 0.1, Timestamp: from.Add(10 * time.Minute)},
+		{ID: "long-2", Importance: 0.9, Timestamp: from.Add(20 * time.Minute)},
+		{ID: "short-2", Importance: 0.1, Timestamp: from.Add(30 * time.Minute)},
+		{ID: "after", Importance: 0.1, Timestamp: to},
+	}
+	for _, mem := range memories {
+		mem.AgentID, mem.Content = "agent-1", "Evento "+mem.ID
+		if err := memManager.StoreMemory(taskCtx, mem); err != nil {
+			t.Fatalf("Erro ao armazenar memória: %v", err)
+		}
+	}
+
+	timeline, err := memManager.GetMemoryTimeline(ctx, "agent-1", from, to)
+	if err != nil {
+		t.Fatalf("Erro ao consultar a linha do tempo: %v", err)
+	}
+	want := []string{"long-1", "short-1", "long-2", "short-2"}
+	if len(timeline) != len(want) {
+		t.Fatalf("linha do tempo com %d memórias, esperava %v", len(timeline), want)
+	}
+	for i, mem := range timeline {
+		if mem.ID != want[i] {
+			t.Errorf("posição %d: %s, esperava %s", i, mem.ID, want[i])
+		}
+		if mem.Provenance == nil || mem.Provenance.WorkflowID != "wf-1" || mem.Provenance.TaskID != "task-1" {
+			t.Errorf("%s: proveniência inesperada: %+v", mem.ID, mem.Provenance)
+		}
+	}
+}
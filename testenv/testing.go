@@ -0,0 +1,29 @@
+package testenv
+
+import (
+	"context"
+	"testing"
+)
+
+// New inicia o ambiente para um teste e registra o encerramento dos containers ao final.
+// O teste é ignorado (Skip) em modo -short ou quando o Docker não está disponível.
+func New(t testing.TB, opts Options) *Environment {
+	t.Helper()
+
+	if testing.Short() {
+		t.Skip("testes de integração ignorados em modo -short")
+	}
+
+	env, err := Start(context.Background(), opts)
+	if err != nil {
+		t.Skipf("Docker/testcontainers não disponível: %v", err)
+	}
+
+	t.Cleanup(func() {
+		if err := env.Terminate(context.Background()); err != nil {
+			t.Logf("Erro ao encerrar containers: %v", err)
+		}
+	})
+
+	return env
+}
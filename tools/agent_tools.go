@@ -0,0 +1,200 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/suissa/HiveMind/agents/errs"
+)
+
+// AgentTool adapta uma ferramenta deste pacote à interface Tool dos agentes, com o JSON Schema
+// dos parâmetros; pode ser registrada no ToolRegistry e exposta pelo servidor MCP
+type AgentTool struct {
+	name        string
+	description string
+	schema      json.RawMessage
+	fn          func(ctx context.Context, params map[string]interface{}) (interface{}, error)
+}
+
+// Name implementa Tool
+func (t *AgentTool) Name() string { return t.name }
+
+// Description implementa Tool
+func (t *AgentTool) Description() string { return t.description }
+
+// InputSchema retorna o JSON Schema dos parâmetros
+func (t *AgentTool) InputSchema() json.RawMessage { return t.schema }
+
+// Execute implementa Tool
+func (t *AgentTool) Execute(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, errs.FromContext(t.name, err)
+	}
+	return t.fn(ctx, params)
+}
+
+// decodeParams converte os parâmetros da chamada na struct de entrada da ferramenta
+func decodeParams(tool string, params map[string]interface{}, out interface{}) error {
+	data, err := json.Marshal(params)
+	if err != nil {
+		return errs.Wrap(errs.ErrValidation, tool, err, "parâmetros inválidos")
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return errs.Wrap(errs.ErrValidation, tool, err, "parâmetros inválidos")
+	}
+	return nil
+}
+
+// NewTrendTool expõe a predição de tendências como a ferramenta "predict_trend". Os pontos
+// enviados substituem os da série (que é criada se não existir).
+func NewTrendTool(predictor TrendPredictor) *AgentTool {
+	return &AgentTool{
+		name:        "predict_trend",
+		description: "Prevê a tendência de uma série temporal",
+		schema: json.RawMessage(`{"type":"object","properties":{` +
+			`"series_id":{"type":"string","description":"ID da série temporal"},` +
+			`"points":{"type":"array","description":"Pontos da série (opcional se ela já existir)","items":{"type":"object","properties":{"timestamp":{"type":"string","format":"date-time"},"value":{"type":"number"}},"required":["timestamp","value"]}},` +
+			`"method":{"type":"string","description":"Método de predição: arima, prophet, lstm ou simple (padrão)"},` +
+			`"horizon_hours":{"type":"number","description":"Horizonte da previsão em horas"},` +
+			`"interval_hours":{"type":"number","description":"Intervalo entre as previsões em horas"}` +
+			`},"required":["series_id"]}`),
+		fn: func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+			var input struct {
+				SeriesID      string      `json:"series_id"`
+				Points        []DataPoint `json:"points"`
+				Method        string      `json:"method"`
+				HorizonHours  float64     `json:"horizon_hours"`
+				IntervalHours float64     `json:"interval_hours"`
+			}
+			if err := decodeParams("predict_trend", params, &input); err != nil {
+				return nil, err
+			}
+			if input.SeriesID == "" {
+				return nil, errs.New(errs.ErrValidation, "predict_trend", "parâmetro series_id obrigatório")
+			}
+
+			if len(input.Points) > 0 {
+				series := TimeSeries{ID: input.SeriesID, Name: input.SeriesID, DataPoints: input.Points}
+				_, err := predictor.GetTimeSeries(input.SeriesID)
+				if err != nil {
+					err = predictor.AddTimeSeries(series)
+				} else {
+					err = predictor.UpdateTimeSeries(input.SeriesID, series)
+				}
+				if err != nil {
+					return nil, fmt.Errorf("erro ao gravar a série %s: %w", input.SeriesID, err)
+				}
+			}
+
+			options := PredictionOptions{
+				Method:          input.Method,
+				Horizon:         hours(input.HorizonHours, 24),
+				Interval:        hours(input.IntervalHours, 1),
+				ConfidenceLevel: 0.95,
+				MinDataPoints:   2,
+			}
+			if options.Method == "" {
+				options.Method = "simple"
+			}
+			return predictor.PredictTrend(input.SeriesID, options)
+		},
+	}
+}
+
+// hours converte horas em duração, usando o padrão se o valor não for positivo
+func hours(value, fallback float64) time.Duration {
+	if value <= 0 {
+		value = fallback
+	}
+	return time.Duration(value * float64(time.Hour))
+}
+
+// NewFraudTool expõe a detecção de fraudes como a ferramenta "analyze_transaction"
+func NewFraudTool(detector FraudDetector) *AgentTool {
+	return &AgentTool{
+		name:        "analyze_transaction",
+		description: "Analisa uma transação em busca de fraude e retorna o score de risco",
+		schema: json.RawMessage(`{"type":"object","properties":{` +
+			`"transaction":{"type":"object","description":"Transação (id, user_id, amount, currency, timestamp, type, device, location, payment_method)","required":["id","amount"]},` +
+			`"threshold":{"type":"number","description":"Score (0-100) a partir do qual a transação é recusada (padrão 75)"}` +
+			`},"required":["transaction"]}`),
+		fn: func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+			var input struct {
+				Transaction *Transaction `json:"transaction"`
+				Threshold   float64      `json:"threshold"`
+			}
+			if err := decodeParams("analyze_transaction", params, &input); err != nil {
+				return nil, err
+			}
+			if input.Transaction == nil || input.Transaction.ID == "" {
+				return nil, errs.New(errs.ErrValidation, "analyze_transaction", "parâmetro transaction com id obrigatório")
+			}
+			if input.Transaction.Timestamp.IsZero() {
+				input.Transaction.Timestamp = time.Now()
+			}
+			if input.Threshold <= 0 {
+				input.Threshold = 75
+			}
+			return detector.AnalyzeTransaction(*input.Transaction, FraudDetectionOptions{
+				EnableRules: true,
+				Threshold:   input.Threshold,
+			})
+		},
+	}
+}
+
+// NewFormTool expõe o preenchimento de formulários como a ferramenta "fill_form"
+func NewFormTool(filler FormFiller) *AgentTool {
+	return &AgentTool{
+		name:        "fill_form",
+		description: "Preenche um formulário cadastrado com os dados informados",
+		schema: json.RawMessage(`{"type":"object","properties":{` +
+			`"form_id":{"type":"string","description":"ID do formulário"},` +
+			`"data":{"type":"object","description":"Valores dos campos, pelo nome"},` +
+			`"validate":{"type":"boolean","description":"Valida os campos ao preencher"}` +
+			`},"required":["form_id","data"]}`),
+		fn: func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+			var input struct {
+				FormID   string                 `json:"form_id"`
+				Data     map[string]interface{} `json:"data"`
+				Validate bool                   `json:"validate"`
+			}
+			if err := decodeParams("fill_form", params, &input); err != nil {
+				return nil, err
+			}
+			if input.FormID == "" {
+				return nil, errs.New(errs.ErrValidation, "fill_form", "parâmetro form_id obrigatório")
+			}
+			return filler.FillForm(input.FormID, input.Data, FillOptions{ValidateOnFill: input.Validate})
+		},
+	}
+}
+
+// NewSearchTool expõe uma ferramenta de busca na web (Tavily, Exa) como a ferramenta "search"
+func NewSearchTool(search SearchTool) *AgentTool {
+	return &AgentTool{
+		name:        "search",
+		description: "Busca na web e retorna os resultados mais relevantes",
+		schema: json.RawMessage(`{"type":"object","properties":{` +
+			`"query":{"type":"string","description":"Consulta"},` +
+			`"max_results":{"type":"integer","description":"Número máximo de resultados (padrão 5)"},` +
+			`"include_domains":{"type":"array","items":{"type":"string"}},` +
+			`"exclude_domains":{"type":"array","items":{"type":"string"}}` +
+			`},"required":["query"]}`),
+		fn: func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+			var input SearchOptions
+			if err := decodeParams("search", params, &input); err != nil {
+				return nil, err
+			}
+			if input.Query == "" {
+				return nil, errs.New(errs.ErrValidation, "search", "parâmetro query obrigatório")
+			}
+			if input.MaxResults <= 0 {
+				input.MaxResults = 5
+			}
+			return search.Search(input)
+		},
+	}
+}
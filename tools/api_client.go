@@ -2,6 +2,7 @@ package tools
 
 import (
 	"bytes"
+	"context"
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
@@ -10,6 +11,8 @@ import (
 	"net/url"
 	"strings"
 	"time"
+
+	"github.com/suissa/HiveMind/agents/errs"
 )
 
 // APIClient implementa a interface APITool
@@ -25,7 +28,7 @@ func NewAPIClient() *APIClient {
 }
 
 // Request implementa a interface APITool
-func (c *APIClient) Request(options APIOptions) (*APIResponse, error) {
+func (c *APIClient) Request(ctx context.Context, options APIOptions) (*APIResponse, error) {
 	startTime := time.Now()
 
 	// Validar método HTTP
@@ -37,7 +40,7 @@ func (c *APIClient) Request(options APIOptions) (*APIResponse, error) {
 	// Construir URL com query params
 	reqURL, err := c.buildURL(options.URL, options.QueryParams)
 	if err != nil {
-		return nil, fmt.Errorf("erro ao construir URL: %v", err)
+		return nil, errs.Wrap(errs.ErrValidation, "api.Request", err, "erro ao construir URL")
 	}
 
 	// Preparar corpo da requisição
@@ -51,7 +54,7 @@ func (c *APIClient) Request(options APIOptions) (*APIResponse, error) {
 	}
 
 	// Criar requisição
-	req, err := http.NewRequest(method, reqURL, bodyReader)
+	req, err := http.NewRequestWithContext(ctx, method, reqURL, bodyReader)
 	if err != nil {
 		return nil, fmt.Errorf("erro ao criar requisição: %v", err)
 	}
@@ -73,7 +76,7 @@ func (c *APIClient) Request(options APIOptions) (*APIResponse, error) {
 	if options.Auth != nil {
 		err = c.setAuthentication(req, options.Auth)
 		if err != nil {
-			return nil, fmt.Errorf("erro ao configurar autenticação: %v", err)
+			return nil, fmt.Errorf("erro ao configurar autenticação: %w", err)
 		}
 	}
 
@@ -88,7 +91,11 @@ func (c *APIClient) Request(options APIOptions) (*APIResponse, error) {
 
 	for attempt := 0; attempt <= options.RetryCount; attempt++ {
 		if attempt > 0 && options.RetryDelay > 0 {
-			time.Sleep(options.RetryDelay)
+			select {
+			case <-ctx.Done():
+				return nil, errs.FromContext("api.Request", ctx.Err())
+			case <-time.After(options.RetryDelay):
+			}
 		}
 
 		resp, err = c.client.Do(req)
@@ -165,31 +172,31 @@ func (c *APIClient) setAuthentication(req *http.Request, auth *APIAuth) error {
 	switch strings.ToLower(auth.Type) {
 	case "basic":
 		if auth.Username == "" || auth.Password == "" {
-			return fmt.Errorf("usuário e senha são necessários para autenticação basic")
+			return errs.New(errs.ErrValidation, "api.Auth", "usuário e senha são necessários para autenticação basic")
 		}
 		authStr := base64.StdEncoding.EncodeToString([]byte(auth.Username + ":" + auth.Password))
 		req.Header.Set("Authorization", "Basic "+authStr)
 
 	case "bearer":
 		if auth.Token == "" {
-			return fmt.Errorf("token é necessário para autenticação bearer")
+			return errs.New(errs.ErrValidation, "api.Auth", "token é necessário para autenticação bearer")
 		}
 		req.Header.Set("Authorization", "Bearer "+auth.Token)
 
 	case "api_key":
 		if auth.KeyName == "" || auth.KeyValue == "" {
-			return fmt.Errorf("nome e valor da chave são necessários para autenticação api_key")
+			return errs.New(errs.ErrValidation, "api.Auth", "nome e valor da chave são necessários para autenticação api_key")
 		}
 		req.Header.Set(auth.KeyName, auth.KeyValue)
 
 	case "custom":
 		if auth.HeaderName == "" || auth.HeaderValue == "" {
-			return fmt.Errorf("nome e valor do header são necessários para autenticação custom")
+			return errs.New(errs.ErrValidation, "api.Auth", "nome e valor do header são necessários para autenticação custom")
 		}
 		req.Header.Set(auth.HeaderName, auth.HeaderValue)
 
 	default:
-		return fmt.Errorf("tipo de autenticação não suportado: %s", auth.Type)
+		return errs.New(errs.ErrValidation, "api.Auth", "tipo de autenticação não suportado: %s", auth.Type)
 	}
 
 	return nil
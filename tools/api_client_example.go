@@ -1,6 +1,7 @@
 package tools
 
 import (
+	"context"
 	"fmt"
 	"time"
 )
@@ -32,7 +33,7 @@ func ExampleAPIClient() {
 	finalClient := NewRateLimitDecorator(circuitClient, 10)
 
 	// Exemplo de uso com timeout específico para esta requisição
-	resp, err := finalClient.Request(APIOptions{
+	resp, err := finalClient.Request(context.Background(), APIOptions{
 		Method: "GET",
 		URL:    "https://api.exemplo.com/data",
 		Auth: &APIAuth{
@@ -70,7 +71,7 @@ func ExampleIndividualDecorators() {
 
 	// Exemplo com apenas timeout
 	timeoutClient := NewTimeoutDecorator(baseClient, 10*time.Second)
-	timeoutClient.Request(APIOptions{
+	timeoutClient.Request(context.Background(), APIOptions{
 		Method: "GET",
 		URL:    "https://api.exemplo.com/slow-endpoint",
 	})
@@ -78,7 +79,7 @@ func ExampleIndividualDecorators() {
 	// Exemplo com timeout e cache
 	cachedClient, _ := NewCacheDecorator(baseClient, 100)
 	timeoutCachedClient := NewTimeoutDecorator(cachedClient, 5*time.Second)
-	timeoutCachedClient.Request(APIOptions{
+	timeoutCachedClient.Request(context.Background(), APIOptions{
 		Method: "GET",
 		URL:    "https://api.exemplo.com/cached-data",
 		Timeout: 2*time.Second, // Timeout específico para esta requisição
@@ -87,7 +88,7 @@ func ExampleIndividualDecorators() {
 	// Exemplo com timeout e rate limit
 	rateLimitedClient := NewRateLimitDecorator(baseClient, 5)
 	timeoutRateLimitedClient := NewTimeoutDecorator(rateLimitedClient, 15*time.Second)
-	timeoutRateLimitedClient.Request(APIOptions{
+	timeoutRateLimitedClient.Request(context.Background(), APIOptions{
 		Method: "POST",
 		URL:    "https://api.exemplo.com/limited-endpoint",
 		Body: map[string]interface{}{
@@ -12,6 +12,8 @@ import (
 
 	"github.com/hashicorp/golang-lru/v2"
 	"go.uber.org/ratelimit"
+
+	"github.com/suissa/HiveMind/agents/errs"
 )
 
 // BaseAPIDecorator é a interface base para todos os decorators
@@ -43,10 +45,10 @@ func (d *CacheDecorator) GetWrapped() APITool {
 	return d.wrapped
 }
 
-func (d *CacheDecorator) Request(options APIOptions) (*APIResponse, error) {
+func (d *CacheDecorator) Request(ctx context.Context, options APIOptions) (*APIResponse, error) {
 	// Só cacheia requisições GET
 	if options.Method != "GET" {
-		return d.wrapped.Request(options)
+		return d.wrapped.Request(ctx, options)
 	}
 
 	// Gerar chave do cache
@@ -58,7 +60,7 @@ func (d *CacheDecorator) Request(options APIOptions) (*APIResponse, error) {
 	}
 
 	// Fazer requisição
-	resp, err := d.wrapped.Request(options)
+	resp, err := d.wrapped.Request(ctx, options)
 	if err != nil {
 		return nil, err
 	}
@@ -86,7 +88,7 @@ func (d *CompressionDecorator) GetWrapped() APITool {
 	return d.wrapped
 }
 
-func (d *CompressionDecorator) Request(options APIOptions) (*APIResponse, error) {
+func (d *CompressionDecorator) Request(ctx context.Context, options APIOptions) (*APIResponse, error) {
 	// Comprimir corpo da requisição se existir
 	if options.Body != nil {
 		var buf bytes.Buffer
@@ -110,7 +112,7 @@ func (d *CompressionDecorator) Request(options APIOptions) (*APIResponse, error)
 		options.Headers["Content-Encoding"] = "gzip"
 	}
 
-	return d.wrapped.Request(options)
+	return d.wrapped.Request(ctx, options)
 }
 
 // MetricsDecorator implementa métricas e logging
@@ -132,10 +134,10 @@ func (d *MetricsDecorator) GetWrapped() APITool {
 	return d.wrapped
 }
 
-func (d *MetricsDecorator) Request(options APIOptions) (*APIResponse, error) {
+func (d *MetricsDecorator) Request(ctx context.Context, options APIOptions) (*APIResponse, error) {
 	startTime := time.Now()
 
-	resp, err := d.wrapped.Request(options)
+	resp, err := d.wrapped.Request(ctx, options)
 
 	d.mutex.Lock()
 	d.totalCalls++
@@ -195,7 +197,7 @@ func (d *CircuitBreakerDecorator) GetWrapped() APITool {
 	return d.wrapped
 }
 
-func (d *CircuitBreakerDecorator) Request(options APIOptions) (*APIResponse, error) {
+func (d *CircuitBreakerDecorator) Request(ctx context.Context, options APIOptions) (*APIResponse, error) {
 	d.mutex.Lock()
 	state := d.state
 	if state == "open" {
@@ -210,7 +212,7 @@ func (d *CircuitBreakerDecorator) Request(options APIOptions) (*APIResponse, err
 		return nil, fmt.Errorf("circuit breaker está aberto")
 	}
 
-	resp, err := d.wrapped.Request(options)
+	resp, err := d.wrapped.Request(ctx, options)
 
 	d.mutex.Lock()
 	defer d.mutex.Unlock()
@@ -247,9 +249,12 @@ func (d *RateLimitDecorator) GetWrapped() APITool {
 	return d.wrapped
 }
 
-func (d *RateLimitDecorator) Request(options APIOptions) (*APIResponse, error) {
+func (d *RateLimitDecorator) Request(ctx context.Context, options APIOptions) (*APIResponse, error) {
 	d.limiter.Take()
-	return d.wrapped.Request(options)
+	if err := ctx.Err(); err != nil {
+		return nil, errs.FromContext("api.RateLimit", err)
+	}
+	return d.wrapped.Request(ctx, options)
 }
 
 // TimeoutDecorator implementa timeout personalizado para requisições
@@ -269,15 +274,15 @@ func (d *TimeoutDecorator) GetWrapped() APITool {
 	return d.wrapped
 }
 
-func (d *TimeoutDecorator) Request(options APIOptions) (*APIResponse, error) {
+func (d *TimeoutDecorator) Request(ctx context.Context, options APIOptions) (*APIResponse, error) {
 	// Usar timeout das opções se fornecido, senão usar o padrão
 	timeout := d.defaultTimeout
 	if options.Timeout > 0 {
 		timeout = options.Timeout
 	}
 
-	// Criar contexto com timeout
-	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	// Criar contexto com timeout derivado do contexto da chamada
+	ctx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
 	// Criar canal para resultado
@@ -289,17 +294,21 @@ func (d *TimeoutDecorator) Request(options APIOptions) (*APIResponse, error) {
 
 	// Executar requisição em goroutine
 	go func() {
-		resp, err := d.wrapped.Request(options)
+		resp, err := d.wrapped.Request(ctx, options)
 		done <- result{resp, err}
 	}()
 
 	// Aguardar resultado ou timeout
 	select {
 	case <-ctx.Done():
+		// Cancelamento do chamador não é timeout
+		if ctx.Err() == context.Canceled {
+			return nil, ctx.Err()
+		}
 		return &APIResponse{
 			Error:        fmt.Sprintf("timeout após %v", timeout),
 			ResponseTime: timeout,
-		}, fmt.Errorf("timeout após %v", timeout)
+		}, errs.Wrap(errs.ErrTimeout, "api.Timeout", ctx.Err(), "timeout após %v", timeout)
 	case r := <-done:
 		return r.response, r.err
 	}
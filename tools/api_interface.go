@@ -1,6 +1,11 @@
 package tools
 
-import "time"
+import (
+	"context"
+	"time"
+
+	"github.com/suissa/HiveMind/agents/errs"
+)
 
 // APIResponse representa a resposta de uma requisição à API
 type APIResponse struct {
@@ -12,6 +17,16 @@ type APIResponse struct {
 	Error        string           `json:"error,omitempty"`
 }
 
+// Err classifica o status HTTP da resposta na taxonomia de erros (errs.ErrNotFound,
+// errs.ErrRateLimited, ...). Retorna nil para status sem categoria associada.
+func (r *APIResponse) Err() error {
+	kind := errs.KindForStatus(r.StatusCode)
+	if kind == nil {
+		return nil
+	}
+	return errs.New(kind, "api.Request", "status HTTP %d", r.StatusCode)
+}
+
 // APIAuth representa as opções de autenticação
 type APIAuth struct {
 	Type        string            `json:"type"`        // basic, bearer, api_key, custom
@@ -39,5 +54,5 @@ type APIOptions struct {
 
 // APITool é a interface que todas as ferramentas de requisição à API devem implementar
 type APITool interface {
-	Request(options APIOptions) (*APIResponse, error)
+	Request(ctx context.Context, options APIOptions) (*APIResponse, error)
 } 
\ No newline at end of file
@@ -0,0 +1,37 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/suissa/HiveMind/agents/competitor"
+)
+
+// ScraperFetcher usa uma ferramenta de scraping (CollyScraper, SeleniumScraper) para baixar
+// as fontes do monitoramento de concorrentes (competitor.Watcher); as páginas que dependem de
+// JavaScript devem usar o SeleniumScraper
+type ScraperFetcher struct {
+	Scraper ScraperTool
+}
+
+// Fetch implementa competitor.Fetcher
+func (f ScraperFetcher) Fetch(ctx context.Context, source competitor.Source) ([]string, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	result, err := f.Scraper.Scrape(ScraperOptions{URL: source.URL, Selectors: source.Selectors})
+	if err != nil {
+		return nil, err
+	}
+	if result.Error != "" {
+		return nil, fmt.Errorf("erro no scraping de %s: %s", source.URL, result.Error)
+	}
+	var lines []string
+	for _, line := range strings.Split(result.Content, "\n") {
+		if line = strings.Join(strings.Fields(line), " "); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines, nil
+}
@@ -8,6 +8,8 @@ import (
 	"regexp"
 	"sync"
 	"time"
+
+	"github.com/suissa/HiveMind/agents/errs"
 )
 
 // FormFillerImpl implementa a interface FormFiller
@@ -63,7 +65,7 @@ func (f *FormFillerImpl) UpdateForm(formID string, form Form) error {
 	defer f.mu.Unlock()
 
 	if _, exists := f.forms[formID]; !exists {
-		return fmt.Errorf("formulário não encontrado: %s", formID)
+		return errs.New(errs.ErrNotFound, "form", "formulário não encontrado: %s", formID)
 	}
 
 	form.UpdatedAt = time.Now()
@@ -77,7 +79,7 @@ func (f *FormFillerImpl) DeleteForm(formID string) error {
 	defer f.mu.Unlock()
 
 	if _, exists := f.forms[formID]; !exists {
-		return fmt.Errorf("formulário não encontrado: %s", formID)
+		return errs.New(errs.ErrNotFound, "form", "formulário não encontrado: %s", formID)
 	}
 
 	delete(f.forms, formID)
@@ -91,7 +93,7 @@ func (f *FormFillerImpl) GetForm(formID string) (*Form, error) {
 
 	form, exists := f.forms[formID]
 	if !exists {
-		return nil, fmt.Errorf("formulário não encontrado: %s", formID)
+		return nil, errs.New(errs.ErrNotFound, "form", "formulário não encontrado: %s", formID)
 	}
 
 	return &form, nil
@@ -199,7 +201,7 @@ func (f *FormFillerImpl) GetFormData(formID string) (*FormData, error) {
 
 	data, exists := f.formData[formID]
 	if !exists {
-		return nil, fmt.Errorf("dados não encontrados para o formulário: %s", formID)
+		return nil, errs.New(errs.ErrNotFound, "form", "dados não encontrados para o formulário: %s", formID)
 	}
 
 	return &data, nil
@@ -8,6 +8,8 @@ import (
 	"path/filepath"
 	"sync"
 	"time"
+
+	"github.com/suissa/HiveMind/agents/errs"
 )
 
 // FraudDetectorImpl implementa a interface FraudDetector
@@ -131,7 +133,7 @@ func (d *FraudDetectorImpl) UpdateRule(ruleID string, rule FraudDetectionRule) e
 		}
 	}
 
-	return fmt.Errorf("regra não encontrada: %s", ruleID)
+	return errs.New(errs.ErrNotFound, "fraud", "regra não encontrada: %s", ruleID)
 }
 
 // DeleteRule remove uma regra
@@ -146,7 +148,7 @@ func (d *FraudDetectorImpl) DeleteRule(ruleID string) error {
 		}
 	}
 
-	return fmt.Errorf("regra não encontrada: %s", ruleID)
+	return errs.New(errs.ErrNotFound, "fraud", "regra não encontrada: %s", ruleID)
 }
 
 // GetRules retorna todas as regras configuradas
@@ -252,7 +254,7 @@ func (d *FraudDetectorImpl) saveRules() error {
 
 func (d *FraudDetectorImpl) validateRule(rule FraudDetectionRule) error {
 	if rule.ID == "" {
-		return fmt.Errorf("ID da regra é obrigatório")
+		return errs.New(errs.ErrValidation, "fraud", "ID da regra é obrigatório")
 	}
 	if rule.Score < 0 || rule.Score > 100 {
 		return fmt.Errorf("score deve estar entre 0 e 100")
@@ -392,12 +394,15 @@ func (d *FraudDetectorImpl) updateStatistics(result *FraudAnalysisResult) {
 	d.mu.Lock()
 	defer d.mu.Unlock()
 
-	d.statistics["total_transactions"] = d.statistics["total_transactions"].(int) + 1
+	total, _ := d.statistics["total_transactions"].(int)
+	d.statistics["total_transactions"] = total + 1
 	if !result.IsAccepted {
-		d.statistics["blocked_transactions"] = d.statistics["blocked_transactions"].(int) + 1
+		blocked, _ := d.statistics["blocked_transactions"].(int)
+		d.statistics["blocked_transactions"] = blocked + 1
 	}
 	if result.ReviewNeeded {
-		d.statistics["reviewed_transactions"] = d.statistics["reviewed_transactions"].(int) + 1
+		reviewed, _ := d.statistics["reviewed_transactions"].(int)
+		d.statistics["reviewed_transactions"] = reviewed + 1
 	}
 }
 
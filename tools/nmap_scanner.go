@@ -1,13 +1,19 @@
 package tools
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
+	"regexp"
 	"strconv"
 	"strings"
 	"time"
+
+	"github.com/suissa/HiveMind/agents/progress"
 )
 
 // NmapScanner implementa a interface SecurityScanner usando Nmap
@@ -42,6 +48,11 @@ func NewNmapScanner() (*NmapScanner, error) {
 
 // Scan realiza uma varredura de segurança
 func (s *NmapScanner) Scan(options ScanOptions) (*ScanResult, error) {
+	return s.ScanContext(context.Background(), options)
+}
+
+// ScanContext realiza uma varredura de segurança, encerrando o processo do Nmap se o contexto for cancelado
+func (s *NmapScanner) ScanContext(ctx context.Context, options ScanOptions) (*ScanResult, error) {
 	startTime := time.Now()
 
 	// Usar localhost se nenhum alvo for especificado
@@ -82,9 +93,16 @@ func (s *NmapScanner) Scan(options ScanOptions) (*ScanResult, error) {
 	// Adicionar alvo
 	args = append(args, target)
 
-	// Executar Nmap
-	cmd := exec.Command(s.nmapPath, args...)
-	output, err := cmd.CombinedOutput()
+	// Executar Nmap; as estatísticas periódicas (--stats-every) são informadas como andamento
+	cmd := exec.CommandContext(ctx, s.nmapPath, args...)
+	var output bytes.Buffer
+	cmd.Stdout = io.MultiWriter(&output, &nmapStats{ctx: ctx, target: target})
+	cmd.Stderr = &output
+	progress.Report(ctx, 0, fmt.Sprintf("iniciando varredura de %s", target))
+	err := cmd.Run()
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return nil, fmt.Errorf("varredura interrompida: %w", ctxErr)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("erro ao executar nmap: %v", err)
 	}
@@ -98,7 +116,7 @@ func (s *NmapScanner) Scan(options ScanOptions) (*ScanResult, error) {
 	}
 
 	// Parsear saída do Nmap
-	if err := s.parseNmapOutput(string(output), result); err != nil {
+	if err := s.parseNmapOutput(output.String(), result); err != nil {
 		return nil, err
 	}
 
@@ -110,6 +128,34 @@ func (s *NmapScanner) Scan(options ScanOptions) (*ScanResult, error) {
 	return result, nil
 }
 
+// nmapTiming reconhece as estatísticas do Nmap, como
+// "SYN Stealth Scan Timing: About 45.50% done; ETC: 12:34 (0:00:12 remaining)"
+var nmapTiming = regexp.MustCompile(`^(.+?) Timing: About ([0-9.]+)% done`)
+
+// nmapStats lê a saída do Nmap linha a linha e informa o andamento de cada fase da varredura
+type nmapStats struct {
+	ctx     context.Context
+	target  string
+	pending []byte
+}
+
+// Write implementa io.Writer
+func (w *nmapStats) Write(p []byte) (int, error) {
+	w.pending = append(w.pending, p...)
+	for {
+		i := bytes.IndexByte(w.pending, '\n')
+		if i < 0 {
+			return len(p), nil
+		}
+		line := strings.TrimSpace(string(w.pending[:i]))
+		w.pending = w.pending[i+1:]
+		if match := nmapTiming.FindStringSubmatch(line); match != nil {
+			percent, _ := strconv.ParseFloat(match[2], 64)
+			progress.Report(w.ctx, percent, fmt.Sprintf("%s em %s", strings.ToLower(match[1]), w.target))
+		}
+	}
+}
+
 // parseNmapOutput processa a saída do Nmap
 func (s *NmapScanner) parseNmapOutput(output string, result *ScanResult) error {
 	lines := strings.Split(output, "\n")
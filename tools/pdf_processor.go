@@ -12,6 +12,8 @@ import (
 	"github.com/otiai10/gosseract/v2"
 	"github.com/pdfcpu/pdfcpu/pkg/api"
 	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu"
+
+	"github.com/suissa/HiveMind/agents/errs"
 )
 
 // PDFProcessor implementa a interface PDFTool
@@ -47,7 +49,7 @@ func (p *PDFProcessor) Process(options PDFOptions) (*PDFResult, error) {
 
 	// Verificar se o arquivo existe
 	if _, err := os.Stat(options.FilePath); os.IsNotExist(err) {
-		return nil, fmt.Errorf("arquivo não encontrado: %s", options.FilePath)
+		return nil, errs.New(errs.ErrNotFound, "pdf", "arquivo não encontrado: %s", options.FilePath)
 	}
 
 	// Extrair metadados do PDF
@@ -3,9 +3,9 @@ package tools
 import (
 	"bufio"
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
-	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -13,6 +13,8 @@ import (
 	"strings"
 	"sync"
 	"time"
+
+	"github.com/suissa/HiveMind/agents/errs"
 )
 
 // PythonExecutorImpl implementa a interface PythonExecutor
@@ -25,6 +27,12 @@ type PythonExecutorImpl struct {
 
 // NewPythonExecutor cria uma nova instância do PythonExecutor
 func NewPythonExecutor() (*PythonExecutorImpl, error) {
+	return NewPythonExecutorContext(context.Background())
+}
+
+// NewPythonExecutorContext cria uma nova instância do PythonExecutor, interrompendo a criação
+// do ambiente virtual quando o contexto é cancelado
+func NewPythonExecutorContext(ctx context.Context) (*PythonExecutorImpl, error) {
 	// Encontrar Python no sistema
 	pythonPath, err := findPython()
 	if err != nil {
@@ -36,7 +44,7 @@ func NewPythonExecutor() (*PythonExecutorImpl, error) {
 	}
 
 	// Criar ambiente virtual se necessário
-	if err := executor.setupVirtualEnv(); err != nil {
+	if err := executor.setupVirtualEnv(ctx); err != nil {
 		return nil, err
 	}
 
@@ -45,6 +53,11 @@ func NewPythonExecutor() (*PythonExecutorImpl, error) {
 
 // Execute executa um script Python
 func (e *PythonExecutorImpl) Execute(options PythonExecutionOptions) (*PythonResult, error) {
+	return e.ExecuteContext(context.Background(), options)
+}
+
+// ExecuteContext executa um script Python, encerrando o processo quando o contexto é cancelado
+func (e *PythonExecutorImpl) ExecuteContext(ctx context.Context, options PythonExecutionOptions) (*PythonResult, error) {
 	e.mu.Lock()
 	defer e.mu.Unlock()
 
@@ -64,21 +77,22 @@ func (e *PythonExecutorImpl) Execute(options PythonExecutionOptions) (*PythonRes
 	}
 	tmpFile.Close()
 
+	// Aplicar timeout se especificado
+	if options.Context != nil && options.Context.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(options.Context.Timeout)*time.Second)
+		defer cancel()
+	}
+
 	// Preparar comando
-	cmd := e.createPythonCommand(tmpFile.Name(), options)
+	cmd := e.createPythonCommand(ctx, tmpFile.Name(), options)
 
 	// Capturar saída
 	var stdout, stderr bytes.Buffer
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
 
-	// Executar com timeout se especificado
-	var err error
-	if options.Context != nil && options.Context.Timeout > 0 {
-		err = e.executeWithTimeout(cmd, options.Context.Timeout)
-	} else {
-		err = cmd.Run()
-	}
+	err = cmd.Run()
 
 	// Processar resultado
 	result := &PythonResult{
@@ -87,9 +101,12 @@ func (e *PythonExecutorImpl) Execute(options PythonExecutionOptions) (*PythonRes
 	}
 
 	if err != nil {
-		// Verificar se é erro de timeout
-		if err.Error() == "timeout" {
-			return nil, fmt.Errorf("timeout após %d segundos", options.Context.Timeout)
+		// Verificar se é erro de timeout ou cancelamento
+		if ctx.Err() == context.DeadlineExceeded {
+			return nil, errs.Wrap(errs.ErrTimeout, "python", ctx.Err(), "timeout após %d segundos", options.Context.Timeout)
+		}
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("execução interrompida: %w", ctx.Err())
 		}
 
 		// Capturar erro Python
@@ -154,6 +171,11 @@ func (e *PythonExecutorImpl) CreateContext(variables map[string]interface{}) (*P
 
 // InstallPackage instala um pacote Python
 func (e *PythonExecutorImpl) InstallPackage(name string, version string) error {
+	return e.InstallPackageContext(context.Background(), name, version)
+}
+
+// InstallPackageContext instala um pacote Python, encerrando o pip quando o contexto é cancelado
+func (e *PythonExecutorImpl) InstallPackageContext(ctx context.Context, name string, version string) error {
 	e.mu.Lock()
 	defer e.mu.Unlock()
 
@@ -167,9 +189,12 @@ func (e *PythonExecutorImpl) InstallPackage(name string, version string) error {
 		pkg = fmt.Sprintf("%s==%s", name, version)
 	}
 
-	cmd := exec.Command(pip, "install", pkg)
+	cmd := exec.CommandContext(ctx, pip, "install", pkg)
 	output, err := cmd.CombinedOutput()
 	if err != nil {
+		if ctx.Err() != nil {
+			return interrupted(ctx, "python.InstallPackage")
+		}
 		return fmt.Errorf("erro ao instalar pacote: %s\n%s", err, output)
 	}
 
@@ -178,14 +203,23 @@ func (e *PythonExecutorImpl) InstallPackage(name string, version string) error {
 
 // GetInstalledPackages retorna os pacotes instalados
 func (e *PythonExecutorImpl) GetInstalledPackages() ([]string, error) {
+	return e.GetInstalledPackagesContext(context.Background())
+}
+
+// GetInstalledPackagesContext retorna os pacotes instalados, encerrando o pip quando o
+// contexto é cancelado
+func (e *PythonExecutorImpl) GetInstalledPackagesContext(ctx context.Context) ([]string, error) {
 	pip := e.pipPath
 	if pip == "" {
 		pip = "pip"
 	}
 
-	cmd := exec.Command(pip, "list", "--format=json")
+	cmd := exec.CommandContext(ctx, pip, "list", "--format=json")
 	output, err := cmd.Output()
 	if err != nil {
+		if ctx.Err() != nil {
+			return nil, interrupted(ctx, "python.GetInstalledPackages")
+		}
 		return nil, fmt.Errorf("erro ao listar pacotes: %v", err)
 	}
 
@@ -207,9 +241,18 @@ func (e *PythonExecutorImpl) GetInstalledPackages() ([]string, error) {
 
 // GetPythonVersion retorna a versão do Python
 func (e *PythonExecutorImpl) GetPythonVersion() (string, error) {
-	cmd := exec.Command(e.pythonPath, "--version")
+	return e.GetPythonVersionContext(context.Background())
+}
+
+// GetPythonVersionContext retorna a versão do Python, encerrando o processo quando o contexto
+// é cancelado
+func (e *PythonExecutorImpl) GetPythonVersionContext(ctx context.Context) (string, error) {
+	cmd := exec.CommandContext(ctx, e.pythonPath, "--version")
 	output, err := cmd.Output()
 	if err != nil {
+		if ctx.Err() != nil {
+			return "", interrupted(ctx, "python.GetPythonVersion")
+		}
 		return "", fmt.Errorf("erro ao obter versão do Python: %v", err)
 	}
 
@@ -218,6 +261,12 @@ func (e *PythonExecutorImpl) GetPythonVersion() (string, error) {
 
 // Funções auxiliares
 
+// interrupted retorna o erro de um comando encerrado pelo contexto: ErrTimeout quando o prazo
+// venceu, o erro do cancelamento nos demais casos
+func interrupted(ctx context.Context, op string) error {
+	return errs.FromContext(op, fmt.Errorf("comando interrompido: %w", ctx.Err()))
+}
+
 func findPython() (string, error) {
 	// Tentar Python 3 primeiro
 	if path, err := exec.LookPath("python3"); err == nil {
@@ -232,7 +281,7 @@ func findPython() (string, error) {
 	return "", fmt.Errorf("Python 3 não encontrado no sistema")
 }
 
-func (e *PythonExecutorImpl) setupVirtualEnv() error {
+func (e *PythonExecutorImpl) setupVirtualEnv(ctx context.Context) error {
 	// Criar diretório para ambiente virtual
 	venvPath := filepath.Join(".", "venv")
 	e.venvPath = venvPath
@@ -245,8 +294,11 @@ func (e *PythonExecutorImpl) setupVirtualEnv() error {
 	}
 
 	// Criar novo ambiente virtual
-	cmd := exec.Command(e.pythonPath, "-m", "venv", venvPath)
+	cmd := exec.CommandContext(ctx, e.pythonPath, "-m", "venv", venvPath)
 	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			return interrupted(ctx, "python.setupVirtualEnv")
+		}
 		return fmt.Errorf("erro ao criar ambiente virtual: %v", err)
 	}
 
@@ -288,8 +340,8 @@ if __name__ == '__main__':
 `, indentScript(script))
 }
 
-func (e *PythonExecutorImpl) createPythonCommand(scriptPath string, options PythonExecutionOptions) *exec.Cmd {
-	cmd := exec.Command(e.pythonPath, scriptPath)
+func (e *PythonExecutorImpl) createPythonCommand(ctx context.Context, scriptPath string, options PythonExecutionOptions) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, e.pythonPath, scriptPath)
 
 	// Configurar ambiente
 	env := os.Environ()
@@ -308,27 +360,6 @@ func (e *PythonExecutorImpl) createPythonCommand(scriptPath string, options Pyth
 	return cmd
 }
 
-func (e *PythonExecutorImpl) executeWithTimeout(cmd *exec.Cmd, timeout int) error {
-	if err := cmd.Start(); err != nil {
-		return err
-	}
-
-	done := make(chan error, 1)
-	go func() {
-		done <- cmd.Wait()
-	}()
-
-	select {
-	case err := <-done:
-		return err
-	case <-time.After(time.Duration(timeout) * time.Second):
-		if err := cmd.Process.Kill(); err != nil {
-			return fmt.Errorf("erro ao matar processo: %v", err)
-		}
-		return fmt.Errorf("timeout")
-	}
-}
-
 func (e *PythonExecutorImpl) parseError(errOutput string) *PythonError {
 	lines := strings.Split(errOutput, "\n")
 	if len(lines) == 0 {
@@ -378,4 +409,4 @@ func indentScript(script string) string {
 		result.WriteString("    " + scanner.Text() + "\n")
 	}
 	return result.String()
-} 
\ No newline at end of file
+} 
\ No newline at end of file
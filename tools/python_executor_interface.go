@@ -1,5 +1,7 @@
 package tools
 
+import "context"
+
 // PythonValue representa um valor retornado pela execução do Python
 type PythonValue struct {
 	Type  string      `json:"type"`
@@ -48,6 +50,9 @@ type PythonExecutor interface {
 	// Execute executa um script Python
 	Execute(options PythonExecutionOptions) (*PythonResult, error)
 
+	// ExecuteContext executa um script Python, encerrando o processo quando o contexto é cancelado
+	ExecuteContext(ctx context.Context, options PythonExecutionOptions) (*PythonResult, error)
+
 	// EvaluateExpression avalia uma expressão Python
 	EvaluateExpression(expression string) (*PythonValue, error)
 
@@ -57,9 +62,20 @@ type PythonExecutor interface {
 	// InstallPackage instala um pacote Python
 	InstallPackage(name string, version string) error
 
+	// InstallPackageContext instala um pacote Python, encerrando o pip quando o contexto é cancelado
+	InstallPackageContext(ctx context.Context, name string, version string) error
+
 	// GetInstalledPackages retorna os pacotes instalados
 	GetInstalledPackages() ([]string, error)
 
+	// GetInstalledPackagesContext retorna os pacotes instalados, encerrando o pip quando o
+	// contexto é cancelado
+	GetInstalledPackagesContext(ctx context.Context) ([]string, error)
+
 	// GetPythonVersion retorna a versão do Python
 	GetPythonVersion() (string, error)
-} 
\ No newline at end of file
+
+	// GetPythonVersionContext retorna a versão do Python, encerrando o processo quando o
+	// contexto é cancelado
+	GetPythonVersionContext(ctx context.Context) (string, error)
+} 
\ No newline at end of file
@@ -1,5 +1,7 @@
 package tools
 
+import "context"
+
 // Vulnerability representa uma vulnerabilidade encontrada
 type Vulnerability struct {
 	ID          string  `json:"id"`
@@ -64,6 +66,9 @@ type ScanOptions struct {
 type SecurityScanner interface {
 	// Scan realiza uma varredura de segurança
 	Scan(options ScanOptions) (*ScanResult, error)
+
+	// ScanContext realiza uma varredura de segurança que é interrompida quando o contexto é cancelado
+	ScanContext(ctx context.Context, options ScanOptions) (*ScanResult, error)
 	
 	// GetVulnerabilityDatabase retorna informações sobre a base de vulnerabilidades
 	GetVulnerabilityDatabase() (map[string]interface{}, error)
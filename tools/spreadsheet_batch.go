@@ -0,0 +1,22 @@
+package tools
+
+import (
+	"fmt"
+
+	"github.com/suissa/HiveMind/agents/sheetbatch"
+)
+
+// BatchSheet converte os dados lidos pelo SpreadsheetProcessor nas linhas da execução por
+// planilha (sheetbatch.Run), com as células como texto
+func (s SheetData) BatchSheet() sheetbatch.Sheet {
+	cells := make([][]string, len(s.Rows))
+	for i, row := range s.Rows {
+		cells[i] = make([]string, len(row))
+		for j, cell := range row {
+			if cell.Value != nil {
+				cells[i][j] = fmt.Sprint(cell.Value)
+			}
+		}
+	}
+	return sheetbatch.NewSheet(s.Headers, cells)
+}
@@ -10,6 +10,8 @@ import (
 	"time"
 
 	"github.com/xuri/excelize/v2"
+
+	"github.com/suissa/HiveMind/agents/errs"
 )
 
 // SpreadsheetProcessor implementa a interface SpreadsheetTool
@@ -28,7 +30,7 @@ func (p *SpreadsheetProcessor) Process(options SpreadsheetOptions) (*Spreadsheet
 
 	// Verificar se o arquivo existe
 	if _, err := os.Stat(options.FilePath); os.IsNotExist(err) {
-		return nil, fmt.Errorf("arquivo não encontrado: %s", options.FilePath)
+		return nil, errs.New(errs.ErrNotFound, "spreadsheet", "arquivo não encontrado: %s", options.FilePath)
 	}
 
 	// Determinar o tipo de arquivo pela extensão
@@ -8,6 +8,8 @@ import (
 	"path/filepath"
 	"sync"
 	"time"
+
+	"github.com/suissa/HiveMind/agents/errs"
 )
 
 // TrendPredictorImpl implementa a interface TrendPredictor
@@ -58,7 +60,7 @@ func (p *TrendPredictorImpl) UpdateTimeSeries(seriesID string, series TimeSeries
 	defer p.mu.Unlock()
 
 	if _, exists := p.series[seriesID]; !exists {
-		return fmt.Errorf("série não encontrada: %s", seriesID)
+		return errs.New(errs.ErrNotFound, "trend", "série não encontrada: %s", seriesID)
 	}
 
 	p.series[seriesID] = series
@@ -71,7 +73,7 @@ func (p *TrendPredictorImpl) DeleteTimeSeries(seriesID string) error {
 	defer p.mu.Unlock()
 
 	if _, exists := p.series[seriesID]; !exists {
-		return fmt.Errorf("série não encontrada: %s", seriesID)
+		return errs.New(errs.ErrNotFound, "trend", "série não encontrada: %s", seriesID)
 	}
 
 	delete(p.series, seriesID)
@@ -85,7 +87,7 @@ func (p *TrendPredictorImpl) GetTimeSeries(seriesID string) (*TimeSeries, error)
 
 	series, exists := p.series[seriesID]
 	if !exists {
-		return nil, fmt.Errorf("série não encontrada: %s", seriesID)
+		return nil, errs.New(errs.ErrNotFound, "trend", "série não encontrada: %s", seriesID)
 	}
 
 	return &series, nil
@@ -402,7 +404,8 @@ func (p *TrendPredictorImpl) updateStatistics(result *TrendPredictionResult) {
 	p.mu.Lock()
 	defer p.mu.Unlock()
 
-	p.statistics["total_predictions"] = p.statistics["total_predictions"].(int) + 1
+	total, _ := p.statistics["total_predictions"].(int)
+	p.statistics["total_predictions"] = total + 1
 	p.statistics["last_prediction"] = time.Now()
 	
 	// Atualizar estatísticas de precisão se tivermos dados reais para comparar
@@ -9,6 +9,8 @@ import (
 	"time"
 
 	v8 "rogchap.com/v8go"
+
+	"github.com/suissa/HiveMind/agents/errs"
 )
 
 // V8Executor implementa a interface JSExecutor usando V8
@@ -79,7 +81,7 @@ func (e *V8Executor) Execute(options JSExecutionOptions) (*JSResult, error) {
 		case <-done:
 			// Script completou normalmente
 		case <-time.After(time.Duration(options.Context.Timeout) * time.Second):
-			return nil, fmt.Errorf("timeout após %d segundos", options.Context.Timeout)
+			return nil, errs.New(errs.ErrTimeout, "v8", "timeout após %d segundos", options.Context.Timeout)
 		}
 	} else {
 		result, err = e.executeScript(ctx, options)